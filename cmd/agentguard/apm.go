@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/apm"
+	"github.com/agentguard/agentguard/internal/config"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// newMetricExporters builds the enabled Datadog and New Relic exporters. Each
+// is returned as a periodic metric reader for the meter provider and as a
+// signal exporter for the signal publisher.
+func newMetricExporters(cfg config.OTELConfig) ([]sdkmetric.Reader, []apm.SignalExporter, error) {
+	type exporter interface {
+		sdkmetric.Exporter
+		apm.SignalExporter
+	}
+	var exporters []exporter
+	if cfg.Datadog.Enabled {
+		dd, err := apm.NewDatadog(apm.DatadogConfig{
+			Mode:       cfg.Datadog.Mode,
+			APIKey:     cfg.Datadog.APIKey,
+			Site:       cfg.Datadog.Site,
+			StatsdAddr: cfg.Datadog.StatsdAddr,
+			Prefix:     cfg.Datadog.Prefix,
+			Tags:       cfg.Datadog.Tags,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("configuring datadog: %w", err)
+		}
+		exporters = append(exporters, dd)
+	}
+	if cfg.NewRelic.Enabled {
+		nr, err := apm.NewNewRelic(apm.NewRelicConfig{
+			LicenseKey: cfg.NewRelic.LicenseKey,
+			AccountID:  cfg.NewRelic.AccountID,
+			Region:     cfg.NewRelic.Region,
+			Prefix:     cfg.NewRelic.Prefix,
+			Attributes: cfg.NewRelic.Attributes,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("configuring newrelic: %w", err)
+		}
+		exporters = append(exporters, nr)
+	}
+
+	interval := time.Duration(cfg.ExportIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	readers := make([]sdkmetric.Reader, 0, len(exporters))
+	signals := make([]apm.SignalExporter, 0, len(exporters))
+	for _, e := range exporters {
+		readers = append(readers, sdkmetric.NewPeriodicReader(e, sdkmetric.WithInterval(interval)))
+		signals = append(signals, e)
+	}
+	return readers, signals, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/response"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// newAuditAnchorer builds the object store anchorer for the audit chain head.
+func newAuditAnchorer(cfg config.AuditAnchorConfig) (*audit.StorageAnchorer, error) {
+	var provider storage.Provider
+	switch cfg.Provider {
+	case "", "local":
+		p, err := storage.NewLocalProvider(storage.LocalConfig{Root: cfg.LocalRoot})
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	default:
+		return nil, fmt.Errorf("audit anchor provider %q is not supported", cfg.Provider)
+	}
+	return audit.NewStorageAnchorer(provider, cfg.Prefix), nil
+}
+
+// auditResponseActions returns a response engine hook that appends every
+// action record to the audit log.
+func auditResponseActions(l *audit.Log) func(response.ActionRecord) {
+	return func(rec response.ActionRecord) {
+		actor := rec.RequestedBy
+		if rec.RolledBackBy != "" {
+			actor = rec.RolledBackBy
+		}
+		if actor == "" {
+			actor = "rule:" + rec.RuleID
+		}
+		if _, err := l.Append(audit.KindResponseAction, rec.OrgID, actor, rec); err != nil {
+			log.Error().Err(err).Str("action_id", rec.ID).Msg("failed to append response action to audit log")
+		}
+	}
+}
+
+func newAuditCmd() *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Tamper-evident audit log tools",
+	}
+	verifyCmd := &cobra.Command{
+		Use:   "verify [log-file]",
+		Short: "Verify the audit log hash chain and anchors",
+		Long: `Verify recomputes every entry hash and chain link in the audit log and
+compares the log against the anchored chain heads. Modified, reordered,
+missing, or truncated entries are reported and the command exits non-zero.
+
+Examples:
+  # Verify the log and anchors named in the configuration
+  agentguard audit verify --config config.yaml
+
+  # Verify a copied log against an anchor directory
+  agentguard audit verify audit.jsonl --anchors /mnt/worm/audit`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runAuditVerify,
+	}
+	verifyCmd.Flags().StringP("config", "c", "", "Path to configuration file")
+	verifyCmd.Flags().String("anchors", "", "Local anchor storage root; overrides the configured root")
+	verifyCmd.Flags().Bool("no-anchors", false, "Verify the hash chain only")
+	verifyCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	auditCmd.AddCommand(verifyCmd)
+	return auditCmd
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+
+	configPath, _ := cmd.Flags().GetString("config")
+	anchorRoot, _ := cmd.Flags().GetString("anchors")
+	noAnchors, _ := cmd.Flags().GetBool("no-anchors")
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	path := cfg.Audit.Path
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	var anchors []audit.Anchor
+	if !noAnchors && (cfg.Audit.Anchor.Enabled || anchorRoot != "") {
+		anchorCfg := cfg.Audit.Anchor
+		if anchorRoot != "" {
+			anchorCfg.Provider, anchorCfg.LocalRoot = "local", anchorRoot
+		}
+		anchorer, err := newAuditAnchorer(anchorCfg)
+		if err != nil {
+			return fmt.Errorf("configuring audit anchors: %w", err)
+		}
+		if anchors, err = anchorer.Anchors(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+	report, err := audit.Verify(f, anchors)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printAuditReport(os.Stdout, path, report)
+	}
+	if !report.OK() {
+		return fmt.Errorf("audit log failed verification with %d problem(s)", len(report.Problems))
+	}
+	return nil
+}
+
+func printAuditReport(w io.Writer, path string, r *audit.Report) {
+	fmt.Fprintf(w, "Audit log: %s\n", path)
+	fmt.Fprintf(w, "Entries:   %d\n", r.Entries)
+	fmt.Fprintf(w, "Head:      seq %d %s\n", r.Head.Seq, r.Head.Hash)
+	fmt.Fprintf(w, "Anchors:   %d checked\n", r.AnchorsChecked)
+	if r.OK() {
+		fmt.Fprintln(w, "\nOK: hash chain and anchors verified")
+		return
+	}
+	fmt.Fprintf(w, "\nFAILED: %d problem(s)\n", len(r.Problems))
+	for _, p := range r.Problems {
+		loc := fmt.Sprintf("seq %d", p.Seq)
+		if p.Line > 0 {
+			loc = fmt.Sprintf("line %d", p.Line)
+		}
+		fmt.Fprintf(w, "  [%s] %s: %s\n", p.Kind, loc, p.Detail)
+	}
+	if r.Truncated {
+		fmt.Fprintln(w, "  ... further problems omitted")
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/detect"
+)
+
+// newDetectionEngine loads the detection rules. It returns nil without an
+// error when the rules directory does not exist, so the default
+// configuration runs without rules.
+func newDetectionEngine(cfg config.DetectionConfig) (*detect.Engine, error) {
+	if _, err := os.Stat(cfg.RulesDir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return detect.NewEngine(cfg.RulesDir)
+}
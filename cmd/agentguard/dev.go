@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// devDemoFramework is the framework the seeded gap analysis runs against.
+const devDemoFramework = "nist-ai-rmf"
+
+// devHost is the interface the development server listens on unless
+// --host is given.
+const devHost = "127.0.0.1"
+
+// applyDevMode overrides configuration for the all-in-one development
+// server: no authentication, any CORS origin and no external stores. It
+// listens on the loopback interface only, since anyone who can reach it
+// has full access.
+func applyDevMode(cfg *config.Config) {
+	cfg.Server.Dev = true
+	cfg.Server.Host = devHost
+	cfg.Auth.Provider = "none"
+	cfg.Server.CORSOrigins = []string{"*"}
+	cfg.Database.Host = ""
+	cfg.Observability.ClickHouse.Enabled = false
+}
+
+// logDevBanner warns loudly that the server is unauthenticated and
+// unpersisted, and louder still when it listens beyond loopback.
+func logDevBanner(host, port string) {
+	for _, line := range []string{
+		"******************************************************************",
+		"*  DEVELOPMENT MODE                                              *",
+		"*  Authentication is DISABLED and CORS allows ANY origin.        *",
+		"*  Data is held in memory and lost on exit.                      *",
+		"*  Never expose this server beyond localhost.                    *",
+		"******************************************************************",
+	} {
+		log.Warn().Msg(line)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		log.Warn().Str("host", host).Msg("Development server is listening beyond localhost: anyone who can reach it has full access")
+	}
+	log.Warn().Msgf("Development console: http://localhost:%s/ui/", port)
+}
+
+// newDevDeps returns router dependencies backed by in-memory repositories.
+func newDevDeps() *api.RouterDeps {
+	traces := memory.NewTraceStore(0)
+	return &api.RouterDeps{
+		ControlRepo:     memory.NewControlRepository(),
+		GapAnalyses:     memory.NewGapAnalysisRepository(),
+		Implementations: memory.NewControlImplementationRepository(),
+		Attestations:    memory.NewAttestationRepository(),
+		GapDispositions: memory.NewGapDispositionRepository(),
+		Agents:          memory.NewAgentRepository(),
+		Policies:        memory.NewPolicyRepository(),
+		ThreatModels:    memory.NewThreatModelRepository(),
+		TraceWriter:     traces,
+		TraceList:       traces,
+		Traces:          traces,
+		SignalWriter:    traces,
+		SignalList:      traces,
+		AgentActivity:   traces,
+	}
+}
+
+// seedDevData loads the embedded control catalogs into the development
+// repositories and adds demo control implementations, a gap analysis and
+// traces for org.
+func seedDevData(ctx context.Context, deps *api.RouterDeps, org string) error {
+	now := time.Now().UTC()
+	if ga := deps.GapAnalyzer; ga != nil {
+		frameworks := ga.Frameworks()
+		for _, fw := range frameworks {
+			catalog := &repository.CatalogImport{Framework: *fw}
+			catalog.Controls, _ = ga.Controls(fw.ID)
+			for _, target := range frameworks {
+				if target.ID == fw.ID {
+					continue
+				}
+				crosswalks, err := ga.Crosswalks(fw.ID, target.ID, false)
+				if err != nil {
+					continue
+				}
+				for _, cw := range crosswalks {
+					if !cw.Derived {
+						catalog.Crosswalks = append(catalog.Crosswalks, cw)
+					}
+				}
+			}
+			if err := deps.ControlRepo.ImportCatalog(ctx, catalog); err != nil {
+				return fmt.Errorf("seeding framework %s: %w", fw.ID, err)
+			}
+		}
+
+		if err := seedDevGapAnalysis(ctx, deps, org, now); err != nil {
+			return err
+		}
+		if err := seedDevAttestations(ctx, deps, org, now); err != nil {
+			return err
+		}
+	}
+
+	if err := seedDevAgent(ctx, deps); err != nil {
+		return err
+	}
+	for _, t := range demoTraces(now) {
+		if err := deps.TraceWriter.InsertTrace(ctx, org, &t); err != nil {
+			return fmt.Errorf("seeding trace %s: %w", t.TraceID, err)
+		}
+	}
+	failOpen := models.SecuritySignal{
+		ID:          uuid.NewString(),
+		Type:        models.SignalFailOpen,
+		Severity:    "medium",
+		Title:       "Call allowed without a policy decision",
+		Description: "Demo signal: the policy engine timed out and the call failed open.",
+		Timestamp:   now.Add(-10 * time.Minute),
+	}
+	if err := deps.SignalWriter.InsertSignals(ctx, org, demoAgentID.String(), []models.SecuritySignal{failOpen}); err != nil {
+		return fmt.Errorf("seeding signals: %w", err)
+	}
+	return nil
+}
+
+// seedDevGapAnalysis tracks implementations of the demo framework's
+// controls, a third of them implemented and others under way, then runs and
+// stores a gap analysis from them.
+func seedDevGapAnalysis(ctx context.Context, deps *api.RouterDeps, org string, now time.Time) error {
+	ctrls, ok := deps.GapAnalyzer.Controls(devDemoFramework)
+	if !ok {
+		return nil
+	}
+	var impls []models.ControlImplementation
+	for i, c := range ctrls {
+		ci := models.ControlImplementation{
+			OrganizationID: org,
+			FrameworkID:    devDemoFramework,
+			ControlID:      c.ControlID,
+			Owner:          "demo-owner@example.com",
+		}
+		switch i % 3 {
+		case 0:
+			ci.Status = models.ImplementationImplemented
+		case 1:
+			due := now.AddDate(0, 1+i/3, 0).Truncate(24 * time.Hour)
+			ci.Status, ci.DueDate = models.ImplementationInProgress, &due
+		default:
+			ci.Status = models.ImplementationPlanned
+		}
+		if err := deps.Implementations.Create(ctx, &ci); err != nil {
+			return fmt.Errorf("seeding control implementation %s: %w", c.ControlID, err)
+		}
+		impls = append(impls, ci)
+	}
+	input := &controls.AnalysisInput{
+		TargetFramework:     devDemoFramework,
+		ImplementedControls: controls.TrackedImplementedControls(impls),
+	}
+	output, err := deps.GapAnalyzer.RunAnalysis(ctx, input)
+	if err != nil {
+		return fmt.Errorf("seeding gap analysis: %w", err)
+	}
+	if deps.Coverage != nil {
+		deps.Coverage.Record(org, output.Framework, now, output.CoveragePercentage)
+	}
+	if err := deps.GapAnalyses.Create(ctx, controls.NewGapAnalysis(org, input, output, now)); err != nil {
+		return fmt.Errorf("seeding gap analysis: %w", err)
+	}
+	return nil
+}
+
+// seedDevAttestations opens an attestation campaign over the demo
+// framework's implemented controls and answers two of them.
+func seedDevAttestations(ctx context.Context, deps *api.RouterDeps, org string, now time.Time) error {
+	impls, err := deps.Implementations.List(ctx, org, devDemoFramework)
+	if err != nil {
+		return fmt.Errorf("seeding attestation campaign: %w", err)
+	}
+	campaign := &models.AttestationCampaign{
+		ID:                   uuid.NewString(),
+		OrganizationID:       org,
+		Name:                 "Quarterly control attestation",
+		FrameworkID:          devDemoFramework,
+		Status:               models.CampaignOpen,
+		DueDate:              now.AddDate(0, 0, 14).Truncate(24 * time.Hour),
+		ReminderIntervalDays: 7,
+		CreatedBy:            "demo-grc@example.com",
+	}
+	attestations, _ := controls.NewAttestations(campaign, impls, "")
+	if len(attestations) > 1 {
+		attested, declined := &attestations[0], &attestations[1]
+		attested.Status, attested.RespondedBy, attested.RespondedAt = models.AttestationAttested, attested.Owner, &now
+		declined.Status, declined.RespondedBy, declined.RespondedAt = models.AttestationDeclined, declined.Owner, &now
+		declined.Comment = "Demo response: evidence of the last review is missing."
+	}
+	if err := deps.Attestations.CreateCampaign(ctx, campaign, attestations); err != nil {
+		return fmt.Errorf("seeding attestation campaign: %w", err)
+	}
+	return nil
+}
+
+// seedDevAgent registers the demo agent with one threat model and adds a
+// policy it could bind, leaving it unbound so the agent's onboarding
+// checklist has work left.
+func seedDevAgent(ctx context.Context, deps *api.RouterDeps) error {
+	agent := &models.Agent{
+		ID:          demoAgentID,
+		Name:        "demo-research-assistant",
+		Description: "Demo agent that plans, searches internal docs and sometimes reaches for a shell.",
+		Framework:   "langchain",
+		Version:     "0.1.0",
+		Owner:       "demo-owner@example.com",
+		Team:        "demo",
+		Environment: "dev",
+		RiskLevel:   "medium",
+		Tools: []models.ToolBinding{
+			{ToolID: "search_docs", Name: "search_docs", Category: "retrieval"},
+			{ToolID: "shell", Name: "shell", Category: "code_execution"},
+		},
+		Status: models.AgentStatusActive,
+	}
+	if err := deps.Agents.Create(ctx, agent); err != nil {
+		return fmt.Errorf("seeding agent: %w", err)
+	}
+	tm := &models.ThreatModel{
+		Name:          "Demo research assistant",
+		Description:   "Demo threat model: prompt injection through retrieved documents leading to shell execution.",
+		TargetAgentID: &agent.ID,
+		Scope:         "agent",
+	}
+	if err := deps.ThreatModels.Create(ctx, tm); err != nil {
+		return fmt.Errorf("seeding threat model: %w", err)
+	}
+	policy := &models.Policy{
+		ID:          "demo-shell-approval",
+		Name:        "Approve shell execution",
+		Description: "Demo policy: shell commands need human approval outside dev.",
+		Type:        models.PolicyTypeHITL,
+		Version:     "1",
+		Scope:       models.PolicyScope{Agents: []string{"*"}, Environments: []string{"staging", "prod"}},
+		Rules: []models.PolicyRule{{
+			ID:         "shell-approval",
+			Conditions: map[string]any{"tool": "shell"},
+			Actions:    []models.PolicyAction{{Type: "require_approval"}},
+		}},
+		Enabled:  true,
+		Priority: 100,
+	}
+	if err := deps.Policies.Create(ctx, policy); err != nil {
+		return fmt.Errorf("seeding policy: %w", err)
+	}
+	return nil
+}
+
+// demoAgentID identifies the agent behind the seeded traces.
+var demoAgentID = uuid.MustParse("00000000-0000-4000-8000-00000000d3e0")
+
+// demoTraces returns a completed trace and one that tripped a tool abuse
+// signal, ending shortly before now.
+func demoTraces(now time.Time) []models.AgentTrace {
+	trace := func(id string, start time.Time, status models.TraceStatus, spans []models.Span, signals []models.SecuritySignal) models.AgentTrace {
+		end := start.Add(1800 * time.Millisecond)
+		t := models.AgentTrace{
+			TraceID:         id,
+			AgentID:         demoAgentID,
+			SessionID:       "demo-session",
+			UserID:          "demo-user",
+			StartTime:       start,
+			EndTime:         &end,
+			DurationMs:      end.Sub(start).Milliseconds(),
+			Status:          status,
+			Spans:           spans,
+			SecuritySignals: signals,
+		}
+		t.Metrics.TotalSpans = len(spans)
+		t.Metrics.SecuritySignals = len(signals)
+		for _, s := range spans {
+			switch s.Type {
+			case models.SpanTypeLLM:
+				t.Metrics.LLMCalls++
+			case models.SpanTypeTool:
+				t.Metrics.ToolInvocations++
+			}
+		}
+		return t
+	}
+	span := func(id, name string, typ models.SpanType, start time.Time, ms int64) models.Span {
+		end := start.Add(time.Duration(ms) * time.Millisecond)
+		return models.Span{SpanID: id, Name: name, Type: typ, StartTime: start, EndTime: &end, DurationMs: ms, Status: "ok"}
+	}
+
+	start1 := now.Add(-time.Hour)
+	start2 := now.Add(-15 * time.Minute)
+	abuse := span("span-3", "tool:shell", models.SpanTypeTool, start2.Add(400*time.Millisecond), 900)
+	abuse.Status = "error"
+	return []models.AgentTrace{
+		trace("demo-trace-1", start1, models.TraceStatusCompleted, []models.Span{
+			span("span-1", "llm:plan", models.SpanTypeLLM, start1, 700),
+			span("span-2", "tool:search_docs", models.SpanTypeTool, start1.Add(800*time.Millisecond), 500),
+		}, nil),
+		trace("demo-trace-2", start2, models.TraceStatusFailed, []models.Span{
+			span("span-1", "llm:plan", models.SpanTypeLLM, start2, 350),
+			abuse,
+		}, []models.SecuritySignal{{
+			ID:          uuid.NewString(),
+			TraceID:     "demo-trace-2",
+			SpanID:      "span-3",
+			Type:        models.SignalToolAbuse,
+			Severity:    "high",
+			Title:       "Shell tool invoked outside its allowlist",
+			Description: "Demo signal: the agent ran a shell command it is not permitted to use.",
+			Timestamp:   start2.Add(400 * time.Millisecond),
+		}}),
+	}
+}
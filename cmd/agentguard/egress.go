@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/urlcat"
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+// newURLCategoryUpdater builds the URL category updater, which publishes
+// categories and the default blocked categories to the policy engine.
+func newURLCategoryUpdater(cfg config.URLCategoriesConfig, engine *opa.Engine) *urlcat.Updater {
+	blocked := cfg.Blocked
+	if blocked == nil {
+		blocked = urlcat.HighExfiltrationRisk
+	}
+	return urlcat.NewUpdater(cfg.Sources, func(domains map[string]string) error {
+		return engine.UpdateData(context.Background(), opa.URLCategoriesPath, opa.URLCategoryData(domains, blocked))
+	})
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/evidence"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/repository/clickhouse"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export compliance artifacts",
+	}
+	evidenceCmd := &cobra.Command{
+		Use:   "evidence",
+		Short: "Export a signed compliance evidence bundle",
+		Long: `Export a signed, immutable archive of compliance evidence for one framework
+and reporting period: control status, policies in force, decision log samples
+with audit chain verification, signal statistics, and the gap analysis report.
+
+The archive's manifest lists the SHA-256 digest of every file and is signed
+with an Ed25519 key. Create one with:
+  openssl genpkey -algorithm ed25519 -out evidence-signing.pem
+  openssl pkey -in evidence-signing.pem -pubout -out evidence-signing.pub
+
+Examples:
+  # Export Q4 2024 evidence for ISO 42001 under a 7-year compliance lock
+  agentguard export evidence --framework iso-42001 --period 2024Q4 \
+    --signing-key evidence-signing.pem --retention-days 2555
+
+  # Credit implemented controls and write to a local file as well
+  agentguard export evidence --framework eu-ai-act --period 2025-03 \
+    --source iso-42001 --implemented "ISO42001-6.1,ISO42001-A.5.2" --out evidence.tar.gz`,
+		RunE: runExportEvidence,
+	}
+	evidenceCmd.Flags().StringP("config", "c", "", "Path to configuration file")
+	evidenceCmd.Flags().StringP("framework", "f", "", "Framework the evidence is for")
+	evidenceCmd.Flags().String("period", "", "Reporting period: quarter (2024Q4), half (2024H2), month (2024-11) or year (2024)")
+	evidenceCmd.Flags().StringP("implemented", "i", "", "Comma-separated list of implemented control IDs")
+	evidenceCmd.Flags().StringP("source", "s", "", "Source framework whose implemented controls are credited via crosswalks")
+	evidenceCmd.Flags().String("org", "", "Organization to collect decisions and signals for; defaults to the default organization")
+	evidenceCmd.Flags().String("signing-key", "", "PKCS #8 PEM Ed25519 signing key; overrides the configured key")
+	evidenceCmd.Flags().Int("retention-days", -1, "Retention lock in days; overrides the configured value, 0 disables")
+	evidenceCmd.Flags().String("retention-mode", "", "Retention lock mode: governance or compliance")
+	evidenceCmd.Flags().String("out", "", "Also write the archive to this local file")
+	_ = evidenceCmd.MarkFlagRequired("framework")
+	_ = evidenceCmd.MarkFlagRequired("period")
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify [archive]",
+		Short: "Verify an evidence bundle's signature and file digests",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runExportVerify,
+	}
+	verifyCmd.Flags().String("public-key", "", "PKIX PEM Ed25519 public key the bundle must be signed with")
+
+	exportCmd.AddCommand(evidenceCmd, verifyCmd, newExportSSPCmd())
+	return exportCmd
+}
+
+// newEvidenceStore builds the object store evidence bundles are written to.
+func newEvidenceStore(cfg config.EvidenceConfig) (storage.Provider, error) {
+	switch cfg.Provider {
+	case "", "local":
+		return storage.NewLocalProvider(storage.LocalConfig{Root: cfg.LocalRoot})
+	default:
+		return nil, fmt.Errorf("evidence storage provider %q is not supported", cfg.Provider)
+	}
+}
+
+func runExportEvidence(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+	flags := cmd.Flags()
+	configPath, _ := flags.GetString("config")
+	framework, _ := flags.GetString("framework")
+	periodFlag, _ := flags.GetString("period")
+	implemented, _ := flags.GetString("implemented")
+	source, _ := flags.GetString("source")
+	orgID, _ := flags.GetString("org")
+	outPath, _ := flags.GetString("out")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	ecfg := cfg.Evidence
+	if v, _ := flags.GetString("signing-key"); v != "" {
+		ecfg.SigningKey = v
+	}
+	if v, _ := flags.GetInt("retention-days"); v >= 0 {
+		ecfg.RetentionDays = v
+	}
+	if v, _ := flags.GetString("retention-mode"); v != "" {
+		ecfg.RetentionMode = v
+	}
+	if orgID == "" {
+		orgID = cfg.Quotas.DefaultOrg
+	}
+
+	period, err := evidence.ParsePeriod(periodFlag)
+	if err != nil {
+		return err
+	}
+	if ecfg.SigningKey == "" {
+		return fmt.Errorf("a signing key is required: set evidence.signing_key or --signing-key")
+	}
+	key, err := evidence.LoadSigningKey(ecfg.SigningKey)
+	if err != nil {
+		return err
+	}
+	var retention *storage.Retention
+	if ecfg.RetentionDays > 0 {
+		retention = &storage.Retention{
+			Mode:  storage.RetentionMode(ecfg.RetentionMode),
+			Until: time.Now().UTC().AddDate(0, 0, ecfg.RetentionDays),
+		}
+		if err := retention.Validate(); err != nil {
+			return err
+		}
+	}
+	store, err := newEvidenceStore(ecfg)
+	if err != nil {
+		return err
+	}
+	if retention != nil {
+		if _, ok := store.(storage.RetentionLocker); !ok {
+			return fmt.Errorf("evidence storage provider %s does not support retention locks", store.Name())
+		}
+	}
+
+	analyzer, err := controls.NewGapAnalyzer(cfg.Controls.DataDir)
+	if err != nil {
+		return fmt.Errorf("initializing analyzer: %w", err)
+	}
+	ctx := context.Background()
+	src := evidence.Sources{
+		Analyzer:        analyzer,
+		Analysis:        controls.AnalysisInput{ImplementedControls: splitList(implemented), SourceFramework: source},
+		PolicyDir:       ecfg.PolicyDir,
+		DecisionSamples: ecfg.DecisionSamples,
+	}
+	if cfg.Audit.Enabled {
+		src.AuditLog = cfg.Audit.Path
+	}
+	if metrics, err := evidenceMetrics(ctx, cfg.Observability.ClickHouse); err != nil {
+		log.Warn().Err(err).Msg("ClickHouse unavailable, signal statistics not included")
+	} else {
+		src.Metrics = metrics
+	}
+
+	bundle, err := evidence.Collect(ctx, framework, orgID, period, "agentguard "+version, src)
+	if err != nil {
+		return err
+	}
+	var archive bytes.Buffer
+	manifest, err := bundle.Write(&archive, key)
+	if err != nil {
+		return fmt.Errorf("writing evidence bundle: %w", err)
+	}
+
+	name := fmt.Sprintf("evidence-%s-%s-%s.tar.gz", framework, period.Label, manifest.CreatedAt.Format("20060102T150405Z"))
+	objectKey := path.Join(ecfg.Prefix, framework, period.Label, name)
+	if err := store.Upload(ctx, objectKey, bytes.NewReader(archive.Bytes()), "application/gzip"); err != nil {
+		return fmt.Errorf("storing evidence bundle: %w", err)
+	}
+	if retention != nil {
+		if err := store.(storage.RetentionLocker).LockRetention(ctx, objectKey, *retention); err != nil {
+			return fmt.Errorf("locking evidence bundle: %w", err)
+		}
+	}
+	if outPath != "" {
+		if err := os.WriteFile(outPath, archive.Bytes(), 0o444); err != nil {
+			return fmt.Errorf("writing %s: %w", outPath, err)
+		}
+	}
+
+	fmt.Printf("Evidence bundle: %s/%s\n", store.Name(), objectKey)
+	fmt.Printf("Framework:       %s\n", framework)
+	fmt.Printf("Period:          %s (%s to %s)\n", period.Label, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"))
+	fmt.Printf("Files:           %d\n", len(manifest.Files))
+	if retention != nil {
+		fmt.Printf("Retention:       %s until %s\n", retention.Mode, retention.Until.Format(time.RFC3339))
+	}
+	for _, note := range manifest.Notes {
+		fmt.Printf("Note:            %s\n", note)
+	}
+	return nil
+}
+
+// evidenceMetrics connects to ClickHouse for signal statistics. It returns
+// nil when ClickHouse is not enabled.
+func evidenceMetrics(ctx context.Context, cfg config.ClickHouseConfig) (repository.MetricsRepository, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	ch, err := clickhouse.New(ctx, clickhouse.Config{
+		Host:     cfg.Host,
+		HTTPPort: cfg.HTTPPort,
+		Database: cfg.Database,
+		User:     cfg.User,
+		Password: cfg.Password,
+		Secure:   cfg.Secure,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clickhouse.NewMetricsRepository(ch), nil
+}
+
+func runExportVerify(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+	pubPath, _ := cmd.Flags().GetString("public-key")
+
+	var pub ed25519.PublicKey
+	if pubPath != "" {
+		var err error
+		if pub, err = evidence.LoadPublicKey(pubPath); err != nil {
+			return err
+		}
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	manifest, err := evidence.Verify(f, pub)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("OK: %s evidence for %s, %d files, created %s\n",
+		manifest.Framework, manifest.Period.Label, len(manifest.Files), manifest.CreatedAt.Format(time.RFC3339))
+	if pub == nil {
+		fmt.Println("Warning: verified with the key embedded in the bundle; pass --public-key to verify the signer")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/fingerprint"
+)
+
+// newFingerprintTracker builds the session fingerprint tracker from
+// configuration. Prompts are compared with the local hash embedder so the
+// pre-invoke hook never waits on an embedding service.
+func newFingerprintTracker(cfg config.FingerprintsConfig) *fingerprint.Tracker {
+	levels := make(map[string]fingerprint.Sensitivity, len(cfg.RiskLevels))
+	for level, s := range cfg.RiskLevels {
+		levels[strings.ToLower(level)] = fingerprintSensitivity(s)
+	}
+	return fingerprint.NewTracker(fingerprint.Config{
+		Baseline:    cfg.Baseline,
+		IdleTimeout: time.Duration(cfg.IdleMinutes) * time.Minute,
+		MaxSessions: cfg.MaxSessions,
+		Default:     fingerprintSensitivity(cfg.Default),
+		RiskLevels:  levels,
+	})
+}
+
+func fingerprintSensitivity(s config.FingerprintSensitivity) fingerprint.Sensitivity {
+	return fingerprint.Sensitivity{
+		MinStyleSimilarity: s.MinStyleSimilarity,
+		CadenceFactor:      s.CadenceFactor,
+		Block:              s.Block,
+	}
+}
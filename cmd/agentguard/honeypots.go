@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/honeypot"
+)
+
+// newHoneypotRegistry builds the decoy tool registry from configuration.
+func newHoneypotRegistry(cfg config.HoneypotsConfig) (*honeypot.Registry, error) {
+	decoys := make([]honeypot.Decoy, 0, len(cfg.Tools))
+	for _, t := range cfg.Tools {
+		decoys = append(decoys, honeypot.Decoy{
+			ID:          t.ID,
+			AgentID:     t.AgentID,
+			Tool:        t.Tool,
+			Description: t.Description,
+		})
+	}
+	return honeypot.NewRegistry(decoys)
+}
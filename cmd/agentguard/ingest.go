@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/canary"
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/detect"
+	"github.com/agentguard/agentguard/internal/hashing"
+	"github.com/agentguard/agentguard/internal/ingest"
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/severity"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/rs/zerolog/log"
+)
+
+// newIngestPipeline builds the trace ingest pipeline from configuration.
+// registry, canaries, rules, scorer, quarantine, payloads, identities and
+// hasher may be nil.
+func newIngestPipeline(cfg config.IngestConfig, pricing map[string]config.ModelPrice, registry *prompts.Registry, canaries *canary.Registry, rules *detect.Engine, scorer *severity.Scorer, quarantine ingest.QuarantineLookup, payloads *storage.ContentStore, payloadThreshold int, identities *privacy.Pseudonymizer, hasher *hashing.Hasher) *ingest.Pipeline {
+	var store ingest.PayloadStore
+	if payloads != nil {
+		store = payloads
+	}
+	var pseudonyms ingest.IdentityPseudonymizer
+	if identities != nil {
+		pseudonyms = identities
+	}
+	var hashes ingest.ContentHasher
+	if hasher != nil {
+		hashes = hasher
+	}
+	deny := cfg.AttributeDeny
+	if deny == nil {
+		deny = ingest.DefaultAttributeDeny
+	}
+	prices := make(map[string]ingest.ModelPrice, len(pricing))
+	for model, p := range pricing {
+		prices[model] = ingest.ModelPrice{PromptPer1K: p.PromptPer1K, CompletionPer1K: p.CompletionPer1K}
+	}
+	return ingest.NewPipeline(ingest.Config{
+		DedupeWindow:     time.Duration(cfg.DedupeWindow) * time.Second,
+		DedupeMaxEntries: cfg.DedupeMaxEntries,
+		MaxClockSkew:     time.Duration(cfg.MaxClockSkewSec) * time.Second,
+		MaxTraceAge:      time.Duration(cfg.MaxTraceAgeHours) * time.Hour,
+		Attributes: ingest.AttributePolicy{
+			Allow:         cfg.AttributeAllow,
+			Deny:          deny,
+			MaxValueBytes: cfg.MaxAttributeBytes,
+			MaxSpanBytes:  cfg.MaxSpanAttributeBytes,
+		},
+		Prompts:          registry,
+		Canaries:         canaries,
+		Rules:            rules,
+		Quarantine:       quarantine,
+		Payloads:         store,
+		PayloadThreshold: payloadThreshold,
+		Identities:       pseudonyms,
+		Hasher:           hashes,
+		Severity:         scorer,
+		Pricing:          prices,
+	})
+}
+
+// startTraceWriter starts a batching trace writer over stores, in write
+// order, that runs until ctx is cancelled.
+func startTraceWriter(ctx context.Context, cfg config.IngestConfig, stores ...repository.TraceWriter) *ingest.Writer {
+	w := ingest.NewWriter(ingest.WriterConfig{BatchSize: cfg.BatchSize, QueueSize: cfg.QueueSize}, stores...)
+	go w.Run(ctx)
+	return w
+}
+
+// newHasher builds the server-side prompt and payload hasher.
+func newHasher(cfg config.HashingConfig) (*hashing.Hasher, error) {
+	return hashing.New(hashing.Config{
+		Algorithm: hashing.Algorithm(cfg.Algorithm),
+		Secret:    []byte(cfg.SaltSecret),
+		OrgSalts:  cfg.OrgSalts,
+	})
+}
+
+// newPayloadStore builds the content-addressable span payload store.
+func newPayloadStore(cfg config.PayloadsConfig) (*storage.ContentStore, error) {
+	var provider storage.Provider
+	switch cfg.Provider {
+	case "", "local":
+		p, err := storage.NewLocalProvider(storage.LocalConfig{Root: cfg.LocalRoot})
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	default:
+		return nil, fmt.Errorf("payload storage provider %q is not supported", cfg.Provider)
+	}
+	return storage.NewContentStore(provider, cfg.Prefix, time.Duration(cfg.TTLHours)*time.Hour), nil
+}
+
+// newPromptRegistry builds the prompt hash registry, seeds its blocklist, and
+// publishes blocklist changes to the policy engine when one is configured.
+func newPromptRegistry(cfg config.PromptsConfig, engine *opa.Engine) *prompts.Registry {
+	reg := prompts.NewRegistry(prompts.Config{
+		Window:         time.Duration(cfg.WindowHours) * time.Hour,
+		MaxHashes:      cfg.MaxHashes,
+		DistinctUsers:  cfg.DistinctUsers,
+		DistinctAgents: cfg.DistinctAgents,
+		Count:          cfg.Count,
+	})
+	for _, b := range cfg.Blocked {
+		reg.Block(b.Hash, b.Reason, "config")
+	}
+	if engine != nil {
+		reg.OnBlocklistChange(func(blocks map[string]prompts.Block) {
+			data := make(map[string]any, len(blocks))
+			for hash, b := range blocks {
+				data[hash] = map[string]any{"reason": b.Reason, "blocked_at": b.BlockedAt}
+			}
+			if err := engine.UpdateData(context.Background(), opa.BlockedPromptHashesPath, data); err != nil {
+				log.Error().Err(err).Msg("failed to publish prompt blocklist to policy engine")
+			}
+		})
+	}
+	return reg
+}
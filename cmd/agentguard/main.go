@@ -5,18 +5,46 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apm"
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/canary"
 	"github.com/agentguard/agentguard/internal/config"
 	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/detect"
+	"github.com/agentguard/agentguard/internal/hashing"
+	"github.com/agentguard/agentguard/internal/ingest"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/llm"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/repository/clickhouse"
+	"github.com/agentguard/agentguard/internal/repository/memory"
 	"github.com/agentguard/agentguard/internal/repository/postgres"
+	"github.com/agentguard/agentguard/internal/response"
+	"github.com/agentguard/agentguard/internal/severity"
+	"github.com/agentguard/agentguard/internal/signing"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/agentguard/agentguard/internal/telemetry"
+	"github.com/agentguard/agentguard/internal/workload"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -51,7 +79,9 @@ Features:
 	}
 	serveCmd.Flags().StringP("config", "c", "", "Path to configuration file")
 	serveCmd.Flags().StringP("port", "p", "8080", "Port to listen on")
+	serveCmd.Flags().String("host", "", "Interface to listen on (default server.host, or 127.0.0.1 with --dev)")
 	serveCmd.Flags().Bool("debug", false, "Enable debug logging")
+	serveCmd.Flags().Bool("dev", false, "Run the all-in-one development server: in-memory storage with demo data, no authentication, open CORS and the web console")
 
 	// Validate command
 	validateCmd := &cobra.Command{
@@ -66,17 +96,63 @@ Features:
 		Use:   "controls",
 		Short: "Manage control framework mappings",
 	}
+	controlCmd.PersistentFlags().String("data-dir", "", "Directory of framework definitions, OSCAL catalogs (catalogs/<framework-id>.json) and licensed catalog text (licensed/<framework-id>.yaml)")
 	controlCmd.AddCommand(&cobra.Command{
-		Use:   "list",
-		Short: "List available control frameworks",
+		Use:   "list [framework]",
+		Short: "List available control frameworks, or a framework's controls",
+		Args:  cobra.MaximumNArgs(1),
 		RunE:  runControlList,
 	})
-	controlCmd.AddCommand(&cobra.Command{
+	crosswalkCmd := &cobra.Command{
 		Use:   "crosswalk [source] [target]",
 		Short: "Generate crosswalk between frameworks",
 		Args:  cobra.ExactArgs(2),
 		RunE:  runControlCrosswalk,
-	})
+	}
+	crosswalkCmd.Flags().Bool("derived", false, "Include transitive mappings derived through other frameworks")
+	crosswalkCmd.Flags().StringP("output", "o", "text", "Output format: text, json (with --suggest), csv, xlsx or oscal (mapping collection)")
+	crosswalkCmd.Flags().Bool("suggest", false, "Suggest unmapped control pairs by embedding similarity, for review")
+	crosswalkCmd.Flags().Int("top", controls.DefaultSuggestTopK, "Suggestions per source control (with --suggest)")
+	crosswalkCmd.Flags().Float64("min-confidence", controls.DefaultSuggestMinConfidence, "Minimum suggestion confidence (with --suggest)")
+	crosswalkCmd.Flags().String("embedder", "hash", "Embedder for --suggest: hash (local) or openai (reads OPENAI_API_KEY)")
+	controlCmd.AddCommand(crosswalkCmd)
+	importCmd := &cobra.Command{
+		Use:   "import [oscal-file]",
+		Short: "Import an OSCAL catalog or profile as a framework",
+		Long: `Import an OSCAL catalog or profile into the data directory as
+catalogs/<id>.json. Profiles are resolved against the local catalogs they
+import, so the data directory holds the selected controls only. An ID that
+matches a built-in framework replaces its controls; with --version the
+catalog is kept as that version alongside the current one, as
+catalogs/<id>@<version>.json.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runControlImport,
+	}
+	importCmd.Flags().String("id", "", "Framework ID to import as, e.g. nist-800-53")
+	_ = importCmd.MarkFlagRequired("id")
+	importCmd.Flags().String("version", "", "Keep the catalog as this version of the framework instead of replacing the current one")
+	controlCmd.AddCommand(importCmd)
+	diffCmd := &cobra.Command{
+		Use:   "diff [framework@version] [framework@version]",
+		Short: "Compare two versions of a framework",
+		Long: `Report the controls added, removed and changed between two versions of a
+framework. Without a version, or without the second argument, the current
+version is used. Older versions are loaded from the data directory's
+catalogs/<id>@<version>.json and frameworks/<id>@<version>.yaml files.
+
+Examples:
+  # What changed from NIST AI RMF 1.0 to the current version
+  agentguard controls diff nist-ai-rmf@1.0 --data-dir data
+
+  # Flag implemented controls that need re-assessing after an update
+  agentguard controls diff nist-ai-rmf@1.0 nist-ai-rmf@1.1 --input analysis.json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runControlDiff,
+	}
+	diffCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	diffCmd.Flags().StringP("implemented", "i", "", "Comma-separated list of implemented control IDs to check for re-assessment")
+	diffCmd.Flags().String("input", "", "Path to a JSON analysis input file whose implemented controls to check")
+	controlCmd.AddCommand(diffCmd)
 	gapsCmd := &cobra.Command{
 		Use:   "gaps [framework]",
 		Short: "Analyze control gaps",
@@ -89,18 +165,119 @@ Examples:
   # Analyze with some controls already implemented
   agentguard controls gaps iso-42001 --implemented "ISO42001-4.1,ISO42001-5.1,ISO42001-6.1"
 
+  # Import hundreds of implemented controls from a GRC export, counting
+  # only rows whose Status column says they are implemented
+  agentguard controls gaps nist-800-53 --implemented-file controls.csv --id-column "Control ID" --status-column Status
+
   # Generate crosswalk from NIST AI RMF
   agentguard controls gaps iso-42001 --source nist-ai-rmf
 
   # Output as JSON
-  agentguard controls gaps iso-42001 --output json`,
-		Args: cobra.ExactArgs(1),
+  agentguard controls gaps iso-42001 --output json
+
+  # Shareable report for auditors
+  agentguard controls gaps iso-42001 --implemented "ISO42001-4.1" --output pdf > gaps.pdf
+
+  # Spreadsheet for GRC tracking (summary, gaps and inherited controls)
+  agentguard controls gaps iso-42001 --implemented "ISO42001-4.1" --output xlsx > gaps.xlsx
+
+  # Assess EU AI Act obligations, crediting ISO 42001 controls
+  agentguard controls gaps eu-ai-act --source iso-42001
+
+  # Start an AppSec assessment from the OWASP LLM Top 10
+  agentguard controls gaps owasp-llm-top10 --source nist-ai-rmf
+
+  # Find MITRE ATLAS techniques left unmitigated by OWASP LLM controls
+  agentguard controls gaps mitre-atlas --source owasp-llm-top10 --implemented "LLM01,LLM02"
+
+  # Assess SOC 2 readiness, crediting implemented NIST 800-53 controls
+  agentguard controls gaps soc2 --source nist-800-53 --implemented "AC-2,SC-7"
+
+  # Prepare a cloud AI vendor assessment keyed off the CSA AI Controls Matrix
+  agentguard controls gaps csa-aicm --source iso-42001 --implemented "ISO42001-8.6"
+
+  # Use an organization-specific effort/priority model
+  agentguard controls gaps iso-42001 --scoring scoring.json
+
+  # Inherit common controls from a platform provider
+  agentguard controls gaps nist-800-53 --providers providers.json --inherit cloud-platform
+
+  # Reproduce a run from a version-controlled input file (or - for stdin);
+  # flags and the framework argument override values from the file
+  agentguard controls gaps --input analysis.json --output json
+
+  # Store the run in the database to track coverage over time
+  agentguard controls gaps iso-42001 --implemented "ISO42001-4.1" --save --config config.yaml`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: runControlGaps,
 	}
-	gapsCmd.Flags().StringP("implemented", "i", "", "Comma-separated list of implemented control IDs")
-	gapsCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
-	gapsCmd.Flags().StringP("source", "s", "", "Source framework for crosswalk comparison")
+	addGapInputFlags(gapsCmd)
+	gapsCmd.Flags().StringP("output", "o", "text", "Output format: text, json, html, pdf, csv, xlsx or oscal (component definition)")
+	gapsCmd.Flags().Bool("save", false, "Store the analysis in the configured database for coverage history")
+	gapsCmd.Flags().StringP("config", "c", "", "Path to configuration file (with --save)")
+	gapsCmd.Flags().String("org", "", "Organization to store the analysis under (with --save; defaults to quotas.default_org)")
 	controlCmd.AddCommand(gapsCmd)
+	roadmapCmd := &cobra.Command{
+		Use:   "roadmap [framework]",
+		Short: "Plan gap remediation by quarter",
+		Long: `Sequence the gaps from a gap analysis into a quarterly remediation roadmap.
+
+Gaps are ordered so prerequisites come first: a control's parent, and the
+governance controls of its family, are remediated before it. Quick wins
+(small, independent, at least medium priority) lead; then gaps go by the
+most urgent priority they unblock, how much they unblock, and effort. Each
+quarter takes gaps up to --capacity effort points (small 1, medium 2,
+large 3, with larger custom sizes weighted 5, 8, 13...).
+
+Takes the same input flags as "controls gaps".
+
+Examples:
+  # Roadmap for ISO 42001 starting this quarter
+  agentguard controls roadmap iso-42001 --implemented "ISO42001-4.1"
+
+  # A bigger team, starting next year, as a spreadsheet
+  agentguard controls roadmap nist-800-53 --capacity 20 --start 2027-Q1 --output xlsx > roadmap.xlsx
+
+  # Add organization-specific prerequisites ({"AC-2": ["IA-2"]})
+  agentguard controls roadmap nist-800-53 --dependencies deps.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runControlRoadmap,
+	}
+	addGapInputFlags(roadmapCmd)
+	roadmapCmd.Flags().StringP("output", "o", "text", "Output format: text, json, csv or xlsx")
+	roadmapCmd.Flags().Int("capacity", controls.DefaultRoadmapCapacity, "Effort points to schedule per quarter")
+	roadmapCmd.Flags().String("start", "", "First quarter, as 2027-Q1 or a date (default: this quarter)")
+	roadmapCmd.Flags().String("dependencies", "", "Path to a JSON object mapping control IDs to the control IDs they depend on")
+	controlCmd.AddCommand(roadmapCmd)
+
+	poamCmd := &cobra.Command{
+		Use:   "poam [framework]",
+		Short: "Generate a Plan of Action and Milestones for gaps",
+		Long: `Generate a Plan of Action and Milestones (POA&M) from a gap analysis:
+every gap as an open item with a scheduled remediation window and dated
+milestones, one per remediation option plus a final validation milestone.
+
+Items are sequenced as by "controls roadmap" and, within a quarter, take
+days in proportion to their estimated effort. Use --analysis to build the
+POA&M from an analysis stored with "controls gaps --save"; otherwise the
+same input flags as "controls gaps" run a new one.
+
+Examples:
+  # POA&M for a stored analysis as an OSCAL document
+  agentguard controls poam --analysis 3f2a... --config config.yaml --output oscal > poam.json
+
+  # POA&M for a fresh NIST 800-53 analysis as CSV
+  agentguard controls poam nist-800-53 --implemented "AC-2,SC-7" --output csv > poam.csv`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runControlPOAM,
+	}
+	addGapInputFlags(poamCmd)
+	poamCmd.Flags().StringP("output", "o", "text", "Output format: text, json, csv, xlsx or oscal (plan of action and milestones)")
+	poamCmd.Flags().String("analysis", "", "ID of a stored gap analysis to build the POA&M from")
+	poamCmd.Flags().StringP("config", "c", "", "Path to configuration file (with --analysis)")
+	poamCmd.Flags().Int("capacity", controls.DefaultRoadmapCapacity, "Effort points to schedule per quarter")
+	poamCmd.Flags().String("start", "", "First quarter, as 2027-Q1 or a date (default: the quarter of the analysis)")
+	controlCmd.AddCommand(poamCmd)
 
 	// Threat modeling commands
 	threatCmd := &cobra.Command{
@@ -113,6 +290,7 @@ Examples:
 		Args:  cobra.ExactArgs(1),
 		RunE:  runThreatAnalyze,
 	})
+	threatCmd.AddCommand(newThreatCalibrateCmd(), newThreatPathsCmd(), newThreatGroupCmd())
 
 	// Maturity assessment commands
 	maturityCmd := &cobra.Command{
@@ -131,7 +309,7 @@ Examples:
 		RunE:  runMaturityReport,
 	})
 
-	rootCmd.AddCommand(serveCmd, validateCmd, controlCmd, threatCmd, maturityCmd)
+	rootCmd.AddCommand(serveCmd, validateCmd, controlCmd, threatCmd, maturityCmd, newAuditCmd(), newExportCmd(), newSeedCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -155,28 +333,88 @@ func runServer(cmd *cobra.Command, args []string) error {
 	if port != "" {
 		cfg.Server.Port = port
 	}
+	if dev, _ := cmd.Flags().GetBool("dev"); dev || cfg.Server.Dev {
+		applyDevMode(cfg)
+	}
+	if host, _ := cmd.Flags().GetString("host"); host != "" {
+		cfg.Server.Host = host
+	}
 
 	log.Info().
 		Str("version", version).
+		Str("host", cfg.Server.Host).
 		Str("port", cfg.Server.Port).
 		Msg("Starting AgentGuard server")
+	if cfg.Server.Dev {
+		logDevBanner(cfg.Server.Host, cfg.Server.Port)
+	}
 
 	// Initialize database connection
 	var deps *api.RouterDeps
 	ctx := context.Background()
 
-	if cfg.Database.Host != "" && cfg.Database.User != "" {
-		dbCfg := postgres.Config{
-			Host:     cfg.Database.Host,
-			Port:     cfg.Database.Port,
-			User:     cfg.Database.User,
-			Password: cfg.Database.Password,
-			Database: cfg.Database.Database,
-			SSLMode:  cfg.Database.SSLMode,
-			MaxConns: int32(cfg.Database.MaxConns),
+	// Initialize OpenTelemetry before the database so pool metrics and query
+	// spans are exported through the configured providers.
+	// Datadog and New Relic exporters push the same metrics on an interval.
+	metricReaders, signalExporters, err := newMetricExporters(cfg.OTEL)
+	if err != nil {
+		return err
+	}
+	var metricsHandler http.Handler
+	if cfg.OTEL.Enabled && cfg.OTEL.Endpoint != "" {
+		tp, err := telemetry.NewProvider(telemetry.Config{
+			ServiceName:    cfg.OTEL.ServiceName,
+			ServiceVersion: version,
+			OTLPEndpoint:   cfg.OTEL.Endpoint,
+			MetricReaders:  metricReaders,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Telemetry initialization failed")
+		} else {
+			metricsHandler = promhttp.Handler()
+			metricReaders = nil
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := tp.Shutdown(shutdownCtx); err != nil {
+					log.Warn().Err(err).Msg("Telemetry shutdown error")
+				}
+			}()
+			log.Info().Str("endpoint", cfg.OTEL.Endpoint).Msg("Telemetry initialized")
 		}
+	}
+	// Without the OTel provider, metrics are still pushed to the platforms.
+	if len(metricReaders) > 0 {
+		mp, err := telemetry.NewMeterProvider(telemetry.Config{
+			ServiceName:    cfg.OTEL.ServiceName,
+			ServiceVersion: version,
+			MetricReaders:  metricReaders,
+		})
+		if err != nil {
+			return fmt.Errorf("configuring metric export: %w", err)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := mp.Shutdown(shutdownCtx); err != nil {
+				log.Warn().Err(err).Msg("Metric export shutdown error")
+			}
+		}()
+	}
+	if len(signalExporters) > 0 {
+		log.Info().Int("exporters", len(signalExporters)).Msg("Metric and signal export enabled")
+	}
+
+	// Stores holding personal data, in the order erasure runs against them
+	var erasureStores []privacy.Store
+	// Trace-level records of ingested traces, kept in Postgres when connected
+	var traceMetadata *postgres.TraceMetadataRepository
 
-		db, err := postgres.New(ctx, dbCfg)
+	if cfg.Server.Dev {
+		deps = newDevDeps()
+		log.Info().Msg("Using in-memory repositories")
+	} else if cfg.Database.Host != "" && cfg.Database.User != "" {
+		db, err := postgres.New(ctx, postgresConfig(cfg.Database))
 		if err != nil {
 			log.Warn().Err(err).Msg("Database connection failed, using stub handlers")
 		} else {
@@ -193,9 +431,21 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 			// Create repositories
 			controlRepo := postgres.NewControlRepository(db)
+			traceMetadata = postgres.NewTraceMetadataRepository(db)
+			erasureStores = append(erasureStores, postgres.NewErasureStore(db), traceMetadata)
 
 			deps = &api.RouterDeps{
-				ControlRepo: controlRepo,
+				ControlRepo:     controlRepo,
+				GapAnalyses:     postgres.NewGapAnalysisRepository(db),
+				Implementations: postgres.NewControlImplementationRepository(db),
+				Attestations:    postgres.NewAttestationRepository(db),
+				GapDispositions: postgres.NewGapDispositionRepository(db),
+				Agents:          postgres.NewAgentRepository(db),
+				Policies:        postgres.NewPolicyRepository(db),
+				TraceList:       traceMetadata,
+			}
+			if cfg.Auth.RequestSigning.Enabled {
+				deps.SigningKeys = signing.New(postgres.NewSigningKeyRepository(db), signingOptions(cfg.Auth.RequestSigning))
 			}
 
 			// Ensure DB is closed on shutdown
@@ -205,29 +455,523 @@ func runServer(cmd *cobra.Command, args []string) error {
 		log.Info().Msg("No database configured, using stub handlers")
 	}
 
+	// Initialize prompt hash reuse tracking
+	var promptRegistry *prompts.Registry
+	if cfg.Observability.Prompts.Enabled {
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		promptRegistry = newPromptRegistry(cfg.Observability.Prompts, deps.PolicyEngine)
+		deps.Prompts = promptRegistry
+	}
+
+	// Initialize the tamper-evident audit log
+	var auditLog *audit.Log
+	var onAction func(response.ActionRecord)
+	if cfg.Audit.Enabled {
+		auditLog, err = audit.Open(cfg.Audit.Path, cfg.Audit.FSync)
+		if err != nil {
+			return fmt.Errorf("opening audit log: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Audit = auditLog
+		onAction = auditResponseActions(auditLog)
+
+		anchoring := make(chan struct{})
+		anchorCtx, stopAnchoring := context.WithCancel(ctx)
+		if aCfg := cfg.Audit.Anchor; aCfg.Enabled {
+			anchorer, err := newAuditAnchorer(aCfg)
+			if err != nil {
+				stopAnchoring()
+				auditLog.Close()
+				return fmt.Errorf("configuring audit anchors: %w", err)
+			}
+			go func() {
+				defer close(anchoring)
+				auditLog.RunAnchoring(anchorCtx, anchorer, time.Duration(aCfg.IntervalSec)*time.Second)
+			}()
+		} else {
+			close(anchoring)
+		}
+		// Anchor the final head before the log is closed.
+		defer func() {
+			stopAnchoring()
+			<-anchoring
+			auditLog.Close()
+		}()
+		log.Info().Str("path", auditLog.Path()).Uint64("seq", auditLog.Head().Seq).
+			Bool("anchoring", cfg.Audit.Anchor.Enabled).Msg("Audit log enabled")
+	}
+
+	// Initialize automated response actions
+	if cfg.Response.Enabled {
+		engine, err := newResponseEngine(cfg.Response, promptRegistry, onAction)
+		if err != nil {
+			return fmt.Errorf("configuring response actions: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Response = engine
+		log.Info().Bool("dry_run", cfg.Response.DryRun).Int("rules", len(engine.Rules())).Msg("Response actions enabled")
+	}
+
+	// Initialize asset-based severity re-scoring
+	var scorer *severity.Scorer
+	if cfg.Severity.Enabled {
+		scorer, err = newSeverityScorer(cfg.Severity)
+		if err != nil {
+			return fmt.Errorf("configuring severity rules: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Severity = scorer
+		log.Info().Int("rules", len(scorer.Rules())).Int("assets", len(cfg.Severity.Assets)).Msg("Severity re-scoring enabled")
+	}
+
+	// Initialize per-environment guardrail profiles
+	if cfg.Profiles.Enabled {
+		reg, err := newProfileRegistry(cfg.Profiles)
+		if err != nil {
+			return fmt.Errorf("configuring environment profiles: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Profiles = reg
+		log.Info().Int("profiles", len(reg.List())).Str("fallback", reg.Fallback()).Msg("Environment profiles enabled")
+	}
+
+	// Initialize the sub-agent spawn policy from declared agent groups
+	if len(cfg.Groups.Files) > 0 {
+		spawn, err := newSpawnPolicy(cfg.Groups)
+		if err != nil {
+			return fmt.Errorf("configuring agent groups: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Spawn = spawn
+		log.Info().Int("groups", len(cfg.Groups.Files)).Msg("Sub-agent spawn policy enabled")
+	}
+
+	// Initialize structured output validation for the post-invoke hook
+	if cfg.Outputs.Enabled {
+		reg, err := newOutputSchemaRegistry(ctx, cfg.Outputs)
+		if err != nil {
+			return fmt.Errorf("configuring output schemas: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.OutputSchemas = reg
+		log.Info().Int("schemas", len(reg.List())).Msg("Output schema validation enabled")
+	}
+
+	// Initialize decoy tool bindings for the pre-invoke hook
+	if cfg.Honeypots.Enabled {
+		reg, err := newHoneypotRegistry(cfg.Honeypots)
+		if err != nil {
+			return fmt.Errorf("configuring honeypots: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Honeypots = reg
+		log.Info().Int("honeypots", len(reg.List(""))).Msg("Honeypot tools enabled")
+	}
+
+	// Initialize session behavioral fingerprints
+	if cfg.Observability.Fingerprints.Enabled {
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Fingerprints = newFingerprintTracker(cfg.Observability.Fingerprints)
+		log.Info().Int("baseline", cfg.Observability.Fingerprints.Baseline).Msg("Session fingerprinting enabled")
+	}
+
+	// Forward security signals and policy denials to a SIEM over syslog
+	if cfg.SIEM.Syslog.Enabled {
+		sl, err := newSyslogExporter(cfg.SIEM.Syslog)
+		if err != nil {
+			return fmt.Errorf("configuring syslog: %w", err)
+		}
+		defer sl.Close()
+		signalExporters = append(signalExporters, sl)
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Syslog = sl
+		log.Info().Str("address", cfg.SIEM.Syslog.Address).Str("network", cfg.SIEM.Syslog.Network).Msg("Syslog CEF export enabled")
+	}
+
+	// Publish security signals to Datadog, New Relic and syslog
+	if len(signalExporters) > 0 {
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.SignalExports = apm.NewPublisher(signalExporters...)
+	}
+
+	// Initialize fail-open/fail-closed policy
+	failure, err := newFailurePolicy(cfg.Failure)
+	if err != nil {
+		return fmt.Errorf("configuring failure policy: %w", err)
+	}
+	if deps == nil {
+		deps = &api.RouterDeps{}
+	}
+	deps.Failure = failure
+	deps.DecisionBudget = time.Duration(cfg.OPA.DecisionBudgetMs) * time.Millisecond
+	if cfg.OPA.DecisionCacheTTL > 0 {
+		deps.DecisionCache = opa.NewDecisionCache(time.Duration(cfg.OPA.DecisionCacheTTL)*time.Second, cfg.OPA.DecisionCacheSize)
+	}
+
+	// Publish URL categories for the web-browsing egress policy
+	if uCfg := cfg.OPA.URLCategories; uCfg.Enabled && deps.PolicyEngine != nil {
+		updater := newURLCategoryUpdater(uCfg, deps.PolicyEngine)
+		if err := updater.Refresh(ctx); err != nil {
+			log.Error().Err(err).Msg("URL category feeds failed to load; publishing the categories that did")
+		}
+		updateCtx, stopUpdates := context.WithCancel(ctx)
+		defer stopUpdates()
+		go updater.Run(updateCtx, time.Duration(uCfg.RefreshSec)*time.Second)
+		log.Info().Int("domains", len(updater.Domains())).Int("sources", len(uCfg.Sources)).Msg("URL categories published to policy engine")
+	}
+
+	// Initialize API keys
+	if !cfg.Server.Dev {
+		keys, err := newAPIKeyring(cfg.Auth)
+		if err != nil {
+			return fmt.Errorf("configuring API keys: %w", err)
+		}
+		deps.APIKeys = keys
+		log.Info().Int("keys", keys.Len()).Msg("API keys loaded")
+	}
+
+	// Initialize SDK workload identity
+	if cfg.Auth.Workload.Enabled {
+		verifier, err := newWorkloadVerifier(cfg.Auth.Workload)
+		if err != nil {
+			return fmt.Errorf("configuring workload identity: %w", err)
+		}
+		deps.Workload = verifier
+		log.Info().Int("issuers", len(cfg.Auth.Workload.Issuers)).Int("bindings", len(cfg.Auth.Workload.Bindings)).Msg("Workload identity enabled for SDK hooks")
+	}
+	if rCfg := cfg.Auth.RequestSigning; rCfg.Enabled {
+		if deps.SigningKeys == nil {
+			log.Warn().Msg("No database configured; request signing keys are kept in memory and lost on restart")
+			deps.SigningKeys = signing.New(memory.NewSigningKeyRepository(), signingOptions(rCfg))
+		}
+		log.Info().Bool("required", rCfg.Required).Int("tolerance_seconds", rCfg.ToleranceSeconds).Msg("Request signing enabled for SDK hooks")
+	}
+	var svids *workload.SVIDSource
+	if cfg.Auth.SPIFFE.Enabled {
+		verifier, source, err := newSPIFFE(cfg.Auth.SPIFFE)
+		if err != nil {
+			return fmt.Errorf("configuring spiffe identity: %w", err)
+		}
+		deps.SVIDs, svids = verifier, source
+		log.Info().Str("trust_domain", cfg.Auth.SPIFFE.TrustDomain).Int("bindings", len(cfg.Auth.SPIFFE.Bindings)).Msg("SPIFFE mTLS enabled for SDK hooks")
+	}
+
+	// Initialize ingest pseudonymization of user and session IDs
+	var pseudonymizer *privacy.Pseudonymizer
+	if pCfg := cfg.Privacy.Pseudonymization; pCfg.Enabled {
+		pseudonymizer, err = newPseudonymizer(pCfg)
+		if err != nil {
+			return fmt.Errorf("configuring pseudonymization: %w", err)
+		}
+		vaultCtx, stopVault := context.WithCancel(ctx)
+		defer stopVault()
+		go pseudonymizer.Run(vaultCtx, time.Duration(pCfg.FlushIntervalSec)*time.Second)
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Pseudonyms = pseudonymizer
+		log.Info().Str("vault_provider", pCfg.VaultProvider).Msg("Ingest pseudonymization enabled")
+	}
+
+	// Initialize knowledge base canaries
+	var canaries *canary.Registry
+	if cfg.Canaries.Enabled {
+		canaries, err = canary.NewRegistry(cfg.Canaries.Path)
+		if err != nil {
+			return fmt.Errorf("configuring canaries: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Canaries = canaries
+		log.Info().Str("path", cfg.Canaries.Path).Msg("Knowledge base canaries enabled")
+	}
+
+	// Initialize hot-reloaded detection rules
+	var detectionRules *detect.Engine
+	if dCfg := cfg.Detection; dCfg.Enabled {
+		detectionRules, err = newDetectionEngine(dCfg)
+		if err != nil {
+			return fmt.Errorf("loading detection rules: %w", err)
+		}
+		if detectionRules == nil {
+			log.Warn().Str("rules_dir", dCfg.RulesDir).Msg("Detection rules directory not found, rule-based detection disabled")
+		} else {
+			rulesCtx, stopRules := context.WithCancel(ctx)
+			defer stopRules()
+			go detectionRules.Run(rulesCtx, time.Duration(dCfg.ReloadIntervalSec)*time.Second)
+			if deps == nil {
+				deps = &api.RouterDeps{}
+			}
+			deps.DetectionRules = detectionRules
+			st := detectionRules.Status()
+			log.Info().Str("rules_dir", dCfg.RulesDir).Int("rules", st.Current.Rules).Int("files", len(st.Current.Files)).Msg("Detection rules loaded")
+		}
+	}
+
+	// Initialize server-side prompt and payload hashing
+	var hasher *hashing.Hasher
+	if hCfg := cfg.Observability.Ingest.Hashing; hCfg.Enabled {
+		hasher, err = newHasher(hCfg)
+		if err != nil {
+			return fmt.Errorf("configuring content hashing: %w", err)
+		}
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Hasher = hasher
+		log.Info().Str("algorithm", string(hasher.Algorithm())).Msg("Server-side salted content hashing enabled")
+	}
+
+	// Initialize ClickHouse trace storage and metric rollups
+	if chCfg := cfg.Observability.ClickHouse; chCfg.Enabled {
+		ch, err := clickhouse.New(ctx, clickhouse.Config{
+			Host:     chCfg.Host,
+			HTTPPort: chCfg.HTTPPort,
+			Database: chCfg.Database,
+			User:     chCfg.User,
+			Password: chCfg.Password,
+			Secure:   chCfg.Secure,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("ClickHouse connection failed, trace ingest and metrics disabled")
+		} else if err := ch.EnsureSchema(ctx); err != nil {
+			return fmt.Errorf("creating clickhouse schema: %w", err)
+		} else {
+			if deps == nil {
+				deps = &api.RouterDeps{}
+			}
+			metricsRepo := clickhouse.NewMetricsRepository(ch)
+			deps.Metrics = metricsRepo
+			// Spans and signals go to ClickHouse first, then the trace's
+			// record to Postgres when it is connected.
+			traceStores := []repository.TraceWriter{metricsRepo}
+			if traceMetadata != nil {
+				traceStores = append(traceStores, traceMetadata)
+			}
+			deps.TraceWriter = startTraceWriter(ctx, cfg.Observability.Ingest, traceStores...)
+			deps.Traces = metricsRepo
+			deps.ToolUsage = metricsRepo
+			deps.AgentActivity = metricsRepo
+			deps.Violations = metricsRepo
+			deps.SignalWriter = metricsRepo
+			deps.SignalList = metricsRepo
+			erasureStores = append(erasureStores, clickhouse.NewErasureStore(ch))
+			var quarantine ingest.QuarantineLookup
+			if deps.Response != nil {
+				quarantine = deps.Response.Containment()
+			}
+			var payloads *storage.ContentStore
+			if pCfg := cfg.Observability.Payloads; pCfg.Enabled {
+				payloads, err = newPayloadStore(pCfg)
+				if err != nil {
+					return fmt.Errorf("configuring payload storage: %w", err)
+				}
+				sweepCtx, stopSweep := context.WithCancel(ctx)
+				defer stopSweep()
+				go payloads.Run(sweepCtx, time.Duration(pCfg.SweepIntervalMin)*time.Minute)
+				deps.Payloads = payloads
+				log.Info().Str("provider", pCfg.Provider).Int("threshold_bytes", pCfg.ThresholdBytes).Msg("Span payload storage enabled")
+			}
+			deps.Ingest = newIngestPipeline(cfg.Observability.Ingest, cfg.Observability.Pricing, promptRegistry, canaries, detectionRules, scorer, quarantine, payloads, cfg.Observability.Payloads.ThresholdBytes, pseudonymizer, hasher)
+			log.Info().Int("batch_size", cfg.Observability.Ingest.BatchSize).Int("queue_size", cfg.Observability.Ingest.QueueSize).Bool("trace_metadata", traceMetadata != nil).Msg("Trace ingest enabled")
+		}
+	} else if cfg.Server.Dev {
+		// Ingest into the in-memory trace store
+		var quarantine ingest.QuarantineLookup
+		if deps.Response != nil {
+			quarantine = deps.Response.Containment()
+		}
+		deps.Ingest = newIngestPipeline(cfg.Observability.Ingest, cfg.Observability.Pricing, promptRegistry, canaries, detectionRules, scorer, quarantine, nil, 0, pseudonymizer, hasher)
+		deps.TraceWriter = startTraceWriter(ctx, cfg.Observability.Ingest, deps.TraceWriter)
+	}
+
+	// Initialize data subject erasure
+	if cfg.Privacy.Enabled {
+		if promptRegistry != nil {
+			erasureStores = append(erasureStores, privacy.NewPromptStore(promptRegistry))
+		}
+		if deps != nil && deps.Payloads != nil {
+			erasureStores = append(erasureStores, privacy.NewPayloadStore(deps.Payloads))
+		}
+		if pseudonymizer != nil {
+			erasureStores = append(erasureStores, privacy.NewIdentityStore(pseudonymizer))
+		}
+		eraser, err := newEraser(cfg.Privacy, auditLog, erasureStores...)
+		if err != nil {
+			return fmt.Errorf("configuring data subject erasure: %w", err)
+		}
+		eraser.Identities = pseudonymizer
+		if deps == nil {
+			deps = &api.RouterDeps{}
+		}
+		deps.Privacy = eraser
+		log.Info().Strs("stores", eraser.Stores()).Msg("Data subject erasure enabled")
+	}
+
 	// Initialize gap analyzer (can work without DB using embedded data)
-	gapAnalyzer, err := controls.NewGapAnalyzer("")
+	gapAnalyzer, err := controls.NewGapAnalyzer(cfg.Controls.DataDir)
 	if err != nil {
 		log.Warn().Err(err).Msg("Gap analyzer initialization failed")
 	} else {
 		log.Info().Msg("Gap analyzer initialized with embedded frameworks")
+		if cfg.Controls.ScoringModelPath != "" {
+			model, err := controls.LoadScoringModel(cfg.Controls.ScoringModelPath)
+			if err != nil {
+				return fmt.Errorf("loading scoring model: %w", err)
+			}
+			if err := gapAnalyzer.SetScoringModel(model); err != nil {
+				return err
+			}
+			log.Info().Str("path", cfg.Controls.ScoringModelPath).Msg("Loaded gap scoring model")
+		}
+		if cfg.Controls.ProvidersPath != "" {
+			if err := gapAnalyzer.LoadProviders(cfg.Controls.ProvidersPath); err != nil {
+				return fmt.Errorf("loading control providers: %w", err)
+			}
+			log.Info().Str("path", cfg.Controls.ProvidersPath).Int("count", len(gapAnalyzer.ListProviders())).Msg("Loaded common control providers")
+		}
 		if deps == nil {
 			deps = &api.RouterDeps{}
 		}
 		deps.GapAnalyzer = gapAnalyzer
+		deps.Coverage = controls.NewCoverageHistory(0)
+
+		embedder, err := newEmbedder(cfg.Controls.Suggest)
+		if err != nil {
+			return fmt.Errorf("configuring crosswalk suggestions: %w", err)
+		}
+		deps.Suggester = controls.NewCrosswalkSuggester(embedder, nil)
+
+		if cfg.Controls.Monitoring.Enabled {
+			monitor, err := newControlMonitor(cfg, deps.PolicyEngine)
+			if err != nil {
+				return fmt.Errorf("configuring control monitoring: %w", err)
+			}
+			gapAnalyzer.SetMonitor(monitor)
+			monitor.Start(ctx)
+			defer monitor.Stop()
+			log.Info().Int("checks", len(monitor.Checks())).Msg("Continuous control monitoring started")
+		}
+	}
+
+	// Seed the in-memory repositories with the catalogs and demo data
+	if cfg.Server.Dev {
+		if err := seedDevData(ctx, deps, cfg.Quotas.DefaultOrg); err != nil {
+			return fmt.Errorf("seeding development data: %w", err)
+		}
+		log.Info().Str("org_id", cfg.Quotas.DefaultOrg).Msg("Seeded development demo data")
 	}
 
+	// Remind control owners of pending attestations
+	if deps != nil && deps.Attestations != nil {
+		aCfg := cfg.Controls.Attestations
+		hook, err := newWebhook("attestation reminder", aCfg.ReminderWebhookURL, aCfg.ReminderWebhookSecret)
+		if err != nil {
+			return err
+		}
+		reminders := controls.NewAttestationReminders(deps.Attestations, hook)
+		remindCtx, stopReminders := context.WithCancel(ctx)
+		defer stopReminders()
+		go reminders.Run(remindCtx, time.Duration(aCfg.ReminderCheckIntervalMin)*time.Minute)
+		log.Info().Bool("webhook", aCfg.ReminderWebhookURL != "").Int("interval_min", aCfg.ReminderCheckIntervalMin).Msg("Attestation reminders scheduled")
+	}
+
+	// Remind approvers to re-review accepted and transferred gap risks
+	if deps != nil && deps.GapDispositions != nil {
+		dCfg := cfg.Controls.Dispositions
+		hook, err := newWebhook("gap disposition reminder", dCfg.ReminderWebhookURL, dCfg.ReminderWebhookSecret)
+		if err != nil {
+			return err
+		}
+		reminders := controls.NewDispositionReminders(deps.GapDispositions, hook)
+		remindCtx, stopReminders := context.WithCancel(ctx)
+		defer stopReminders()
+		go reminders.Run(remindCtx, time.Duration(dCfg.ReminderCheckIntervalMin)*time.Minute)
+		log.Info().Bool("webhook", dCfg.ReminderWebhookURL != "").Int("interval_min", dCfg.ReminderCheckIntervalMin).Msg("Gap disposition reminders scheduled")
+	}
+
+	// Re-run gap analysis on a schedule
+	if sCfg := cfg.Controls.Schedule; sCfg.Enabled && deps != nil && deps.GapAnalyzer != nil &&
+		deps.GapAnalyses != nil && deps.Implementations != nil {
+		schedule, err := controls.ParseSchedule(sCfg.Cron)
+		if err != nil {
+			return fmt.Errorf("configuring scheduled gap analysis: %w", err)
+		}
+		for _, fw := range sCfg.Frameworks {
+			if _, ok := deps.GapAnalyzer.Framework(fw); !ok {
+				return fmt.Errorf("configuring scheduled gap analysis: unknown framework %s", fw)
+			}
+		}
+		orgs := sCfg.Organizations
+		if len(orgs) == 0 {
+			orgs = []string{cfg.Quotas.DefaultOrg}
+		}
+		hook, err := newWebhook("scheduled gap analysis", sCfg.WebhookURL, sCfg.WebhookSecret)
+		if err != nil {
+			return err
+		}
+		tags, _ := deps.ControlRepo.(repository.ControlTagRepository)
+		scheduled := controls.NewScheduledAnalyses(deps.GapAnalyzer, deps.GapAnalyses, deps.Implementations, deps.GapDispositions, tags, deps.Coverage, hook)
+		scheduleCtx, stopSchedule := context.WithCancel(ctx)
+		defer stopSchedule()
+		go scheduled.Run(scheduleCtx, schedule, orgs, sCfg.Frameworks)
+		log.Info().Str("cron", sCfg.Cron).Strs("frameworks", sCfg.Frameworks).Bool("webhook", sCfg.WebhookURL != "").Msg("Gap analysis scheduled")
+	}
+
+	// Initialize async job workers for long-running operations
+	jobManager := jobs.NewManager(jobs.Config{
+		Workers:   cfg.Jobs.Workers,
+		QueueSize: cfg.Jobs.QueueSize,
+		Timeout:   time.Duration(cfg.Jobs.Timeout) * time.Second,
+		Retention: time.Duration(cfg.Jobs.Retention) * time.Second,
+	})
+	defer jobManager.Stop()
+	if deps == nil {
+		deps = &api.RouterDeps{}
+	}
+	deps.Jobs = jobManager
+	deps.MetricsHandler = metricsHandler
+
 	// Initialize router with dependencies
 	router := api.NewRouter(cfg, deps)
+	if deps.StopLoadShedder != nil {
+		defer deps.StopLoadShedder()
+	}
 
 	// Create HTTP server
 	srv := &http.Server{
-		Addr:         ":" + cfg.Server.Port,
+		Addr:         net.JoinHostPort(cfg.Server.Host, cfg.Server.Port),
 		Handler:      router,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	if svids != nil {
+		srv.TLSConfig = svids.TLSConfig()
+	}
 
 	// Graceful shutdown
 	go func() {
@@ -245,7 +989,12 @@ func runServer(cmd *cobra.Command, args []string) error {
 	}()
 
 	// Start server
-	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+	serve := srv.ListenAndServe
+	if srv.TLSConfig != nil {
+		// Certificates come from the SVID source.
+		serve = func() error { return srv.ListenAndServeTLS("", "") }
+	}
+	if err := serve(); err != http.ErrServerClosed {
 		return fmt.Errorf("server error: %w", err)
 	}
 
@@ -267,11 +1016,15 @@ func runValidate(cmd *cobra.Command, args []string) error {
 func runControlList(cmd *cobra.Command, args []string) error {
 	configureLogging(false)
 
-	analyzer, err := controls.NewGapAnalyzer("")
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	analyzer, err := controls.NewGapAnalyzer(dataDir)
 	if err != nil {
 		return fmt.Errorf("initializing analyzer: %w", err)
 	}
 
+	if len(args) == 1 {
+		return analyzer.ListControls(os.Stdout, args[0])
+	}
 	analyzer.ListFrameworks(os.Stdout)
 	return nil
 }
@@ -281,56 +1034,519 @@ func runControlCrosswalk(cmd *cobra.Command, args []string) error {
 
 	source, target := args[0], args[1]
 
-	analyzer, err := controls.NewGapAnalyzer("")
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	analyzer, err := controls.NewGapAnalyzer(dataDir)
 	if err != nil {
 		return fmt.Errorf("initializing analyzer: %w", err)
 	}
 
-	return analyzer.GenerateCrosswalkReport(os.Stdout, source, target)
+	derived, _ := cmd.Flags().GetBool("derived")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if suggest, _ := cmd.Flags().GetBool("suggest"); suggest {
+		return runCrosswalkSuggest(cmd, analyzer, source, target, outputFormat)
+	}
+	switch outputFormat {
+	case "oscal":
+		doc, err := analyzer.OSCALMappingCollection(source, target, derived)
+		if err != nil {
+			return err
+		}
+		return oscal.WriteJSON(os.Stdout, doc)
+	case controls.ReportCSV, controls.ReportXLSX:
+		crosswalks, err := analyzer.Crosswalks(source, target, derived)
+		if err != nil {
+			return err
+		}
+		sourceControls, _ := analyzer.Controls(source)
+		targetControls, _ := analyzer.Controls(target)
+		return controls.WriteCrosswalks(os.Stdout, crosswalks, sourceControls, targetControls, outputFormat)
+	}
+	return analyzer.GenerateCrosswalkReport(os.Stdout, source, target, derived)
 }
 
-func runControlGaps(cmd *cobra.Command, args []string) error {
+// runCrosswalkSuggest prints suggested mappings between two frameworks.
+func runCrosswalkSuggest(cmd *cobra.Command, analyzer *controls.GapAnalyzer, source, target, outputFormat string) error {
+	name, _ := cmd.Flags().GetString("embedder")
+	embedder, err := newEmbedder(config.SuggestConfig{Embedder: name, APIKey: os.Getenv("OPENAI_API_KEY")})
+	if err != nil {
+		return err
+	}
+	var opts controls.SuggestOptions
+	opts.TopK, _ = cmd.Flags().GetInt("top")
+	opts.MinConfidence, _ = cmd.Flags().GetFloat64("min-confidence")
+
+	suggester := controls.NewCrosswalkSuggester(embedder, nil)
+	suggestions, err := analyzer.SuggestCrosswalks(cmd.Context(), suggester, source, target, opts)
+	if err != nil {
+		return err
+	}
+	switch outputFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"source":      source,
+			"target":      target,
+			"embedder":    suggester.Embedder(),
+			"suggestions": suggestions,
+		})
+	case "text", "":
+	default:
+		return fmt.Errorf("unsupported output format for --suggest: %s", outputFormat)
+	}
+
+	fmt.Printf("\nSuggested crosswalk: %s → %s (%s)\n", source, target, suggester.Embedder())
+	fmt.Printf("══════════════════════════════\n\n")
+	fmt.Printf("Suggestions are unreviewed; confirm each before adding it to a catalog.\n\n")
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SOURCE\tTARGET\tCONFIDENCE\n")
+	for _, xw := range suggestions {
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\n", xw.SourceControlID, xw.TargetControlID, xw.Confidence)
+	}
+	tw.Flush()
+	fmt.Printf("\n%d suggestions\n\n", len(suggestions))
+	return nil
+}
+
+// newEmbedder builds the embedder behind crosswalk suggestions.
+func newEmbedder(cfg config.SuggestConfig) (llm.Embedder, error) {
+	switch cfg.Embedder {
+	case "", "hash":
+		return llm.NewHashEmbedder(0), nil
+	case "openai":
+		return llm.NewOpenAIProvider(llm.OpenAIConfig{APIKey: cfg.APIKey, EmbeddingModel: cfg.Model, BaseURL: cfg.BaseURL})
+	default:
+		return nil, fmt.Errorf("unknown embedder %q", cfg.Embedder)
+	}
+}
+
+func runControlImport(cmd *cobra.Command, args []string) error {
 	configureLogging(false)
 
-	framework := args[0]
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	if dataDir == "" {
+		return fmt.Errorf("--data-dir is required")
+	}
+	id, _ := cmd.Flags().GetString("id")
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid framework ID %q", id)
+	}
 
-	// Parse implemented controls from flags
-	implementedStr, _ := cmd.Flags().GetString("implemented")
-	outputFormat, _ := cmd.Flags().GetString("output")
-	sourceFramework, _ := cmd.Flags().GetString("source")
+	cat, err := oscal.Load(args[0])
+	if err != nil {
+		return err
+	}
+	n := len(oscal.ToControls(cat, id))
+	if n == 0 {
+		return fmt.Errorf("%s selects no controls", args[0])
+	}
+
+	dir := filepath.Join(dataDir, "catalogs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := id
+	if version, _ := cmd.Flags().GetString("version"); version != "" {
+		if strings.ContainsAny(version, `/\@`) {
+			return fmt.Errorf("invalid version %q", version)
+		}
+		name += "@" + version
+	}
+	path := filepath.Join(dir, name+".json")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := oscal.WriteJSON(f, cat); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// Load it back as the analyzer will, so a bad import fails here.
+	if _, err := controls.NewGapAnalyzer(dataDir); err != nil {
+		return fmt.Errorf("loading imported catalog: %w", err)
+	}
+	fmt.Printf("Imported %d controls as %s to %s\n", n, id, path)
+	return nil
+}
+
+func runControlDiff(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
 
-	implemented := []string{}
-	if implementedStr != "" {
-		implemented = strings.Split(implementedStr, ",")
-		for i := range implemented {
-			implemented[i] = strings.TrimSpace(implemented[i])
+	framework, from := controls.ParseVersionRef(args[0])
+	to := ""
+	if len(args) == 2 {
+		var other string
+		other, to = controls.ParseVersionRef(args[1])
+		if other != framework {
+			return fmt.Errorf("cannot compare %s with %s: versions must be of the same framework", framework, other)
 		}
 	}
 
-	analyzer, err := controls.NewGapAnalyzer("")
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	analyzer, err := controls.NewGapAnalyzer(dataDir)
 	if err != nil {
 		return fmt.Errorf("initializing analyzer: %w", err)
 	}
+	diff, err := analyzer.Diff(framework, from, to)
+	if err != nil {
+		return err
+	}
+
+	var implemented []string
+	if inputPath, _ := cmd.Flags().GetString("input"); inputPath != "" {
+		input, err := controls.LoadInputFromFile(inputPath)
+		if err != nil {
+			return fmt.Errorf("loading analysis input: %w", err)
+		}
+		implemented = input.ImplementedControls
+	}
+	if cmd.Flags().Changed("implemented") {
+		implementedStr, _ := cmd.Flags().GetString("implemented")
+		implemented = splitList(implementedStr)
+	}
+	if implemented != nil {
+		diff.MarkAffected(implemented)
+	}
 
-	input := &controls.AnalysisInput{
-		TargetFramework:     framework,
-		ImplementedControls: implemented,
-		SourceFramework:     sourceFramework,
+	if outputFormat, _ := cmd.Flags().GetString("output"); outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
 	}
+	analyzer.PrintDiff(os.Stdout, diff)
+	return nil
+}
 
-	output, err := analyzer.RunAnalysis(context.Background(), input)
+// addGapInputFlags registers the flags that build a gap analysis input.
+func addGapInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("implemented", "i", "", "Comma-separated list of implemented control IDs")
+	cmd.Flags().String("implemented-file", "", "Path to a CSV or JSON file of implemented control IDs")
+	cmd.Flags().String("id-column", "", "Column (or JSON field) of --implemented-file holding control IDs (default: control_id, control or id)")
+	cmd.Flags().String("status-column", "", "Column (or JSON field) of --implemented-file whose value must be implemented, yes or done for a control to count")
+	cmd.Flags().StringP("source", "s", "", "Source framework for crosswalk comparison")
+	cmd.Flags().String("scoring", "", "Path to a JSON or YAML scoring model, or a Rego priority policy (.rego), for priority and effort estimation")
+	cmd.Flags().String("providers", "", "Path to a JSON file of common control providers")
+	cmd.Flags().String("inherit", "", "Comma-separated list of provider IDs whose controls are inherited")
+	cmd.Flags().String("input", "", "Path to a JSON analysis input file, or - to read stdin")
+}
+
+func runControlGaps(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	analyzer, input, output, err := runGapAnalysis(cmd, args)
 	if err != nil {
 		return err
 	}
 
-	if outputFormat == "json" {
-		return analyzer.PrintJSON(os.Stdout, output)
+	if save, _ := cmd.Flags().GetBool("save"); save {
+		if err := saveGapAnalysis(cmd, input, output); err != nil {
+			return err
+		}
+	}
+
+	switch outputFormat {
+	case controls.ReportJSON, controls.ReportHTML, controls.ReportPDF, controls.ReportCSV, controls.ReportXLSX:
+		return analyzer.WriteReport(os.Stdout, output, outputFormat)
+	case "oscal":
+		doc, err := analyzer.OSCALComponentDefinition(output)
+		if err != nil {
+			return err
+		}
+		return oscal.WriteJSON(os.Stdout, doc)
 	}
 
 	analyzer.PrintReport(os.Stdout, output)
 	return nil
 }
 
+func runControlRoadmap(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	opts := controls.RoadmapOptions{}
+	opts.Capacity, _ = cmd.Flags().GetInt("capacity")
+	if start, _ := cmd.Flags().GetString("start"); start != "" {
+		t, err := controls.ParseQuarter(start)
+		if err != nil {
+			return err
+		}
+		opts.Start = t
+	}
+	if path, _ := cmd.Flags().GetString("dependencies"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading dependencies: %w", err)
+		}
+		if err := json.Unmarshal(data, &opts.Dependencies); err != nil {
+			return fmt.Errorf("parsing dependencies: %w", err)
+		}
+	}
+
+	analyzer, _, output, err := runGapAnalysis(cmd, args)
+	if err != nil {
+		return err
+	}
+	roadmap, err := analyzer.Roadmap(output, opts)
+	if err != nil {
+		return err
+	}
+	return controls.WriteRoadmap(os.Stdout, roadmap, outputFormat)
+}
+
+func runControlPOAM(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	opts := controls.RoadmapOptions{}
+	opts.Capacity, _ = cmd.Flags().GetInt("capacity")
+	if start, _ := cmd.Flags().GetString("start"); start != "" {
+		t, err := controls.ParseQuarter(start)
+		if err != nil {
+			return err
+		}
+		opts.Start = t
+	}
+
+	var (
+		analyzer *controls.GapAnalyzer
+		ga       *models.GapAnalysis
+	)
+	if id, _ := cmd.Flags().GetString("analysis"); id != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--analysis and a framework argument are mutually exclusive")
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		_, db, err := connectDatabase(ctx, cmd, "--analysis")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		ga, err = postgres.NewGapAnalysisRepository(db).Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ga == nil {
+			return fmt.Errorf("gap analysis %s not found", id)
+		}
+		dataDir, _ := cmd.Flags().GetString("data-dir")
+		if analyzer, err = controls.NewGapAnalyzer(dataDir); err != nil {
+			return fmt.Errorf("initializing analyzer: %w", err)
+		}
+	} else {
+		var (
+			input  *controls.AnalysisInput
+			output *controls.AnalysisOutput
+			err    error
+		)
+		analyzer, input, output, err = runGapAnalysis(cmd, args)
+		if err != nil {
+			return err
+		}
+		// The analysis is not stored, so the POA&M references no ID.
+		ga = controls.NewGapAnalysis("", input, output, time.Now())
+		ga.ID = ""
+	}
+
+	poam, err := analyzer.POAM(ga, opts)
+	if err != nil {
+		return err
+	}
+	return analyzer.WritePOAM(os.Stdout, poam, outputFormat)
+}
+
+// runGapAnalysis runs a gap analysis from the input flags registered by
+// addGapInputFlags and the framework argument.
+func runGapAnalysis(cmd *cobra.Command, args []string) (*controls.GapAnalyzer, *controls.AnalysisInput, *controls.AnalysisOutput, error) {
+	scoringPath, _ := cmd.Flags().GetString("scoring")
+	providersPath, _ := cmd.Flags().GetString("providers")
+	inputPath, _ := cmd.Flags().GetString("input")
+
+	input := &controls.AnalysisInput{ImplementedControls: []string{}}
+	if inputPath != "" {
+		var err error
+		if inputPath == "-" {
+			input, err = controls.LoadInput(cmd.InOrStdin())
+		} else {
+			input, err = controls.LoadInputFromFile(inputPath)
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("loading analysis input: %w", err)
+		}
+	}
+
+	// Explicit flags and the framework argument override the input file.
+	if len(args) == 1 {
+		input.TargetFramework = args[0]
+	}
+	if input.TargetFramework == "" {
+		return nil, nil, nil, fmt.Errorf("a target framework is required, as an argument or target_framework in --input")
+	}
+	if cmd.Flags().Changed("implemented") {
+		implementedStr, _ := cmd.Flags().GetString("implemented")
+		input.ImplementedControls = splitList(implementedStr)
+	}
+	if cmd.Flags().Changed("source") {
+		input.SourceFramework, _ = cmd.Flags().GetString("source")
+	}
+	if cmd.Flags().Changed("inherit") {
+		inheritStr, _ := cmd.Flags().GetString("inherit")
+		input.Providers = splitList(inheritStr)
+	}
+	if input.ImplementedControls == nil {
+		input.ImplementedControls = []string{}
+	}
+
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	analyzer, err := controls.NewGapAnalyzer(dataDir)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("initializing analyzer: %w", err)
+	}
+
+	if providersPath != "" {
+		if err := analyzer.LoadProviders(providersPath); err != nil {
+			return nil, nil, nil, fmt.Errorf("loading control providers: %w", err)
+		}
+	}
+
+	if scoringPath != "" {
+		model, err := controls.LoadScoringModel(scoringPath)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("loading scoring model: %w", err)
+		}
+		input.Scoring = model
+	}
+
+	// Controls imported from a file add to those listed on the command line
+	// or in the input file.
+	var imported *controls.ImplementedImport
+	if implementedFile, _ := cmd.Flags().GetString("implemented-file"); implementedFile != "" {
+		idColumn, _ := cmd.Flags().GetString("id-column")
+		statusColumn, _ := cmd.Flags().GetString("status-column")
+		imported, err = analyzer.ImportImplementedFile(implementedFile, controls.ImportOptions{IDColumn: idColumn, StatusColumn: statusColumn})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("importing implemented controls: %w", err)
+		}
+		input.ImplementedControls = append(input.ImplementedControls, imported.Controls...)
+		printImportSummary(cmd.ErrOrStderr(), imported)
+	}
+
+	output, err := analyzer.RunAnalysis(context.Background(), input)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	output.Input = input
+	output.Import = imported
+	return analyzer, input, output, nil
+}
+
+// importSummaryLimit caps the ignored entries listed after an import.
+const importSummaryLimit = 20
+
+// printImportSummary reports how many controls a file contributed and which
+// entries were ignored.
+func printImportSummary(w io.Writer, imp *controls.ImplementedImport) {
+	fmt.Fprintf(w, "Implemented controls from %s: %d imported, %d ignored\n", imp.Source, imp.Imported, len(imp.Ignored))
+	for i, e := range imp.Ignored {
+		if i == importSummaryLimit {
+			fmt.Fprintf(w, "  ... and %d more\n", len(imp.Ignored)-i)
+			break
+		}
+		fmt.Fprintf(w, "  entry %d: %s (%s", e.Entry, e.Value, strings.ReplaceAll(e.Reason, "_", " "))
+		if e.Details != "" {
+			fmt.Fprintf(w, ", %s", e.Details)
+		}
+		fmt.Fprintln(w, ")")
+	}
+}
+
+// saveGapAnalysis stores an analysis run in the configured database.
+func saveGapAnalysis(cmd *cobra.Command, input *controls.AnalysisInput, output *controls.AnalysisOutput) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg, db, err := connectDatabase(ctx, cmd, "--save")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	orgID, _ := cmd.Flags().GetString("org")
+	if orgID == "" {
+		orgID = cfg.Quotas.DefaultOrg
+	}
+
+	ga := controls.NewGapAnalysis(orgID, input, output, time.Now())
+	if err := postgres.NewGapAnalysisRepository(db).Create(ctx, ga); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Saved gap analysis %s\n", ga.ID)
+	return nil
+}
+
+// connectDatabase connects to the database in the file named by the config
+// flag and runs migrations. flag names the option that needs the database,
+// for the error when none is configured.
+func connectDatabase(ctx context.Context, cmd *cobra.Command, flag string) (*config.Config, *postgres.DB, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Database.Host == "" || cfg.Database.User == "" {
+		return nil, nil, fmt.Errorf("%s requires a configured database", flag)
+	}
+	db, err := postgres.New(ctx, postgresConfig(cfg.Database))
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	if err := db.RunMigrations(ctx); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("running migrations: %w", err)
+	}
+	return cfg, db, nil
+}
+
+// postgresConfig builds the connection settings for the configured
+// database.
+func postgresConfig(cfg config.DatabaseConfig) postgres.Config {
+	dbCfg := postgres.Config{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		User:     cfg.User,
+		Password: cfg.Password,
+		Database: cfg.Database,
+		SSLMode:  cfg.SSLMode,
+		MaxConns: int32(cfg.MaxConns),
+
+		SlowQueryThreshold:   time.Duration(cfg.SlowQueryMs) * time.Millisecond,
+		ReplicaCheckInterval: time.Duration(cfg.ReplicaCheckInterval) * time.Second,
+	}
+	for _, r := range cfg.Replicas {
+		dbCfg.Replicas = append(dbCfg.Replicas, postgres.ReplicaConfig{Host: r.Host, Port: r.Port})
+	}
+	return dbCfg
+}
+
+// splitList splits a comma-separated flag value, trimming whitespace and
+// dropping empty items.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func runThreatAnalyze(cmd *cobra.Command, args []string) error {
 	manifest := args[0]
 	fmt.Printf("Analyzing threats for: %s\n", manifest)
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+// newControlMonitor builds the continuous control monitor with the built-in
+// platform checks and any configured cloud API checks.
+func newControlMonitor(cfg *config.Config, engine *opa.Engine) (*controls.Monitor, error) {
+	monitor := controls.NewMonitor(time.Duration(cfg.Controls.Monitoring.Interval) * time.Second)
+
+	checks := []controls.Check{
+		{
+			ID:          "policy-engine-ready",
+			Description: "Policy engine is loaded and enforcing access decisions",
+			Controls:    []string{"AC-3"},
+			Run: func(ctx context.Context) error {
+				if engine == nil {
+					return fmt.Errorf("policy engine not configured")
+				}
+				if !engine.Ready() {
+					return fmt.Errorf("policy engine not ready")
+				}
+				return nil
+			},
+		},
+		{
+			ID:          "audit-logging-enabled",
+			Description: "Telemetry export is enabled so agent events are recorded",
+			Controls:    []string{"AU-2", "AU-3"},
+			Run: func(ctx context.Context) error {
+				if !cfg.OTEL.Enabled {
+					return fmt.Errorf("telemetry export is disabled")
+				}
+				if cfg.OTEL.Endpoint == "" {
+					return fmt.Errorf("telemetry endpoint not configured")
+				}
+				return nil
+			},
+		},
+		{
+			ID:          "retention-configured",
+			Description: "A retention period is configured for observability data",
+			Controls:    []string{"SI-12"},
+			Run: func(ctx context.Context) error {
+				if cfg.Observability.RetentionDays <= 0 {
+					return fmt.Errorf("observability.retention_days not configured")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, hc := range cfg.Controls.Monitoring.HTTPChecks {
+		checks = append(checks, controls.HTTPCheck(hc.ID, hc.Description, hc.URL, hc.ExpectStatus, hc.Controls))
+	}
+
+	for _, c := range checks {
+		if err := monitor.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return monitor, nil
+}
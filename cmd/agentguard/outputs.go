@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/outputs"
+)
+
+// newOutputSchemaRegistry builds the output schema registry from
+// configuration, reading schema documents from files where given.
+func newOutputSchemaRegistry(ctx context.Context, cfg config.OutputsConfig) (*outputs.Registry, error) {
+	schemas := make([]outputs.Schema, 0, len(cfg.Schemas))
+	for _, sc := range cfg.Schemas {
+		doc := []byte(sc.Schema)
+		switch {
+		case sc.Schema != "" && sc.File != "":
+			return nil, fmt.Errorf("output schema %s: set schema or file, not both", sc.ID)
+		case sc.File != "":
+			data, err := os.ReadFile(sc.File)
+			if err != nil {
+				return nil, fmt.Errorf("output schema %s: %w", sc.ID, err)
+			}
+			doc = data
+		case sc.Schema == "":
+			return nil, fmt.Errorf("output schema %s: schema or file is required", sc.ID)
+		}
+		mode := outputs.Mode(sc.Mode)
+		if mode == "" {
+			mode = outputs.Flag
+		}
+		schemas = append(schemas, outputs.Schema{
+			ID:      sc.ID,
+			AgentID: sc.AgentID,
+			Tool:    sc.Tool,
+			Schema:  json.RawMessage(doc),
+			Mode:    mode,
+			Strict:  sc.Strict,
+		})
+	}
+	return outputs.NewRegistry(ctx, schemas)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// newEraser builds the data subject eraser. When the audit log is enabled,
+// every completion report is appended to it as proof of erasure; reports
+// name the subject only by pseudonym.
+func newEraser(cfg config.PrivacyConfig, auditLog *audit.Log, stores ...privacy.Store) (*privacy.Eraser, error) {
+	pcfg := privacy.Config{PseudonymKey: []byte(cfg.PseudonymKey)}
+	for _, f := range cfg.PersonalData {
+		pcfg.Fields = append(pcfg.Fields, privacy.Field{Name: f.Field, Action: privacy.FieldAction(f.Action)})
+	}
+	eraser, err := privacy.NewEraser(pcfg, stores...)
+	if err != nil {
+		return nil, err
+	}
+	if auditLog != nil {
+		eraser.OnReport = func(rep privacy.Report) {
+			if _, err := auditLog.Append(audit.KindErasure, rep.OrgID, rep.RequestedBy, rep); err != nil {
+				log.Error().Err(err).Str("report_id", rep.ID).Msg("failed to append erasure report to audit log")
+			}
+		}
+	}
+	return eraser, nil
+}
+
+// newPseudonymizer builds the ingest pseudonymizer. Its key is read from a
+// file kept apart from the main configuration and the identity vault.
+func newPseudonymizer(cfg config.PseudonymizationConfig) (*privacy.Pseudonymizer, error) {
+	if cfg.KeyFile == "" {
+		return nil, fmt.Errorf("privacy.pseudonymization.key_file is required")
+	}
+	info, err := os.Stat(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading pseudonymization key: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		log.Warn().Str("key_file", cfg.KeyFile).Msg("pseudonymization key file is readable by group or others")
+	}
+	key, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading pseudonymization key: %w", err)
+	}
+
+	var vault storage.Provider
+	switch cfg.VaultProvider {
+	case "", "local":
+		p, err := storage.NewLocalProvider(storage.LocalConfig{Root: cfg.VaultRoot})
+		if err != nil {
+			return nil, err
+		}
+		vault = p
+	default:
+		return nil, fmt.Errorf("identity vault provider %q is not supported", cfg.VaultProvider)
+	}
+	return privacy.NewPseudonymizer(bytes.TrimSpace(key), vault, cfg.VaultPrefix)
+}
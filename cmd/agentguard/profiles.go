@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/profiles"
+)
+
+// newProfileRegistry builds the environment profile registry from
+// configuration, falling back to the built-in profiles.
+func newProfileRegistry(cfg config.ProfilesConfig) (*profiles.Registry, error) {
+	defs := profiles.Defaults
+	if len(cfg.Definitions) > 0 {
+		defs = make([]profiles.Profile, 0, len(cfg.Definitions))
+		for _, pc := range cfg.Definitions {
+			defs = append(defs, profiles.Profile{
+				Name:               pc.Name,
+				Environments:       pc.Environments,
+				FailMode:           profiles.FailMode(pc.FailMode),
+				Enforcement:        profiles.Enforcement(pc.Enforcement),
+				TraceSampleRate:    pc.TraceSampleRate,
+				ApprovalCategories: pc.ApprovalCategories,
+				RequireSandbox:     pc.RequireSandbox,
+			})
+		}
+	}
+	return profiles.NewRegistry(defs, cfg.Fallback)
+}
+
+// newFailurePolicy builds the fail-open/fail-closed policy from configuration.
+func newFailurePolicy(cfg config.FailureConfig) (*profiles.FailurePolicy, error) {
+	fp := &profiles.FailurePolicy{
+		Default:    profiles.FailMode(cfg.Default),
+		Routes:     make(map[string]profiles.FailMode, len(cfg.Routes)),
+		RiskLevels: make(map[string]profiles.FailMode, len(cfg.RiskLevels)),
+	}
+	for route, m := range cfg.Routes {
+		fp.Routes[route] = profiles.FailMode(m)
+	}
+	for level, m := range cfg.RiskLevels {
+		fp.RiskLevels[strings.ToLower(level)] = profiles.FailMode(m)
+	}
+	if err := fp.Validate(); err != nil {
+		return nil, err
+	}
+	return fp, nil
+}
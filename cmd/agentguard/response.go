@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/response"
+)
+
+// defaultResponseRules apply when no rules are configured: critical signals
+// suspend the agent and notify, blocked prompts are re-blocked in OPA, and
+// high-severity tool abuse or exfiltration suggests quarantine for review.
+var defaultResponseRules = []response.Rule{
+	{
+		ID:          "contain-critical",
+		Name:        "Suspend agents raising critical signals",
+		MinSeverity: "critical",
+		Actions:     []response.ActionType{response.ActionSuspendAgent, response.ActionNotify},
+	},
+	{
+		ID:          "block-prompt-campaigns",
+		Name:        "Block prompts reused across many principals",
+		SignalTypes: []models.SignalType{models.SignalAnomalousBehavior},
+		MinSeverity: "high",
+		Actions:     []response.ActionType{response.ActionBlockPrompt, response.ActionNotify},
+	},
+	{
+		ID:   "suggest-quarantine",
+		Name: "Suggest quarantine for agents abusing tools or exfiltrating data",
+		SignalTypes: []models.SignalType{
+			models.SignalToolAbuse,
+			models.SignalDataExfiltration,
+			models.SignalPrivilegeEscalation,
+		},
+		MinSeverity: "high",
+		Actions:     []response.ActionType{response.ActionSuggestQuarantine},
+	},
+}
+
+var validActions = map[response.ActionType]bool{
+	response.ActionSuspendAgent:      true,
+	response.ActionRevokeTools:       true,
+	response.ActionZeroRateLimit:     true,
+	response.ActionBlockPrompt:       true,
+	response.ActionNotify:            true,
+	response.ActionQuarantine:        true,
+	response.ActionSuggestQuarantine: true,
+}
+
+var validSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// newResponseEngine builds the response engine from configuration. onRecord
+// may be nil.
+func newResponseEngine(cfg config.ResponseConfig, registry *prompts.Registry, onRecord func(response.ActionRecord)) (*response.Engine, error) {
+	rules := defaultResponseRules
+	if len(cfg.Rules) > 0 {
+		rules = make([]response.Rule, 0, len(cfg.Rules))
+		for i, rc := range cfg.Rules {
+			if rc.ID == "" {
+				return nil, fmt.Errorf("response rule %d: id is required", i)
+			}
+			if !validSeverities[rc.MinSeverity] {
+				return nil, fmt.Errorf("response rule %s: invalid min_severity %q", rc.ID, rc.MinSeverity)
+			}
+			rule := response.Rule{ID: rc.ID, Name: rc.Name, MinSeverity: rc.MinSeverity}
+			for _, t := range rc.SignalTypes {
+				rule.SignalTypes = append(rule.SignalTypes, models.SignalType(t))
+			}
+			for _, a := range rc.Actions {
+				action := response.ActionType(a)
+				if !validActions[action] {
+					return nil, fmt.Errorf("response rule %s: unknown action %q", rc.ID, a)
+				}
+				rule.Actions = append(rule.Actions, action)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	notify, err := newWebhook("response notification", cfg.NotifyWebhookURL, cfg.NotifyWebhookSecret)
+	if err != nil {
+		return nil, err
+	}
+	return response.NewEngine(response.Config{
+		DryRun:          cfg.DryRun,
+		Cooldown:        time.Duration(cfg.CooldownSec) * time.Second,
+		NotifyWebhook:   notify,
+		QuarantineTools: cfg.QuarantineTools,
+		Rules:           rules,
+		OnRecord:        onRecord,
+	}, response.NewContainment(), registry), nil
+}
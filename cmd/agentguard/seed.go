@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository/postgres"
+	"github.com/spf13/cobra"
+)
+
+func newSeedCmd() *cobra.Command {
+	seedCmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load the embedded control catalogs into the database",
+		Long: `Seed upserts the embedded frameworks, their controls and the built-in
+crosswalks into the configured database, where the API's catalog handlers
+read them. Running it again only applies what changed. A framework stored
+at another version, or a curated crosswalk that maps a control pair
+differently, is reported as a conflict and left alone.
+
+Examples:
+  # Preview what a seed would change
+  agentguard seed --config config.yaml --dry-run
+
+  # Seed two frameworks, including catalogs from a data directory
+  agentguard seed --config config.yaml --framework nist-ai-rmf,iso-42001 --data-dir data`,
+		Args: cobra.NoArgs,
+		RunE: runSeed,
+	}
+	seedCmd.Flags().StringP("config", "c", "", "Path to configuration file")
+	seedCmd.Flags().String("data-dir", "", "Directory of framework definitions and catalogs; overrides the configured directory")
+	seedCmd.Flags().StringSlice("framework", nil, "Seed only these framework IDs")
+	seedCmd.Flags().Bool("dry-run", false, "Report what would change without writing")
+	seedCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	return seedCmd
+}
+
+func runSeed(cmd *cobra.Command, _ []string) error {
+	configureLogging(false)
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format %q: use text or json", output)
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg, db, err := connectDatabase(ctx, cmd, "seed")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	if dataDir == "" {
+		dataDir = cfg.Controls.DataDir
+	}
+	analyzer, err := controls.NewGapAnalyzer(dataDir)
+	if err != nil {
+		return fmt.Errorf("initializing analyzer: %w", err)
+	}
+	opts := controls.SeedOptions{}
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Frameworks, _ = cmd.Flags().GetStringSlice("framework")
+	report, err := analyzer.Seed(ctx, postgres.NewControlRepository(db), opts)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printSeedReport(cmd.OutOrStdout(), report)
+	return nil
+}
+
+// printSeedReport writes a seed report as text.
+func printSeedReport(w io.Writer, r *controls.SeedReport) {
+	if r.DryRun {
+		fmt.Fprintln(w, "Dry run: nothing was written.")
+	}
+	for _, row := range []struct {
+		name   string
+		counts controls.SeedCounts
+	}{
+		{"Frameworks", r.Frameworks},
+		{"Controls", r.Controls},
+		{"Crosswalks", r.Crosswalks},
+	} {
+		fmt.Fprintf(w, "%-11s %d created, %d updated, %d unchanged\n",
+			row.name+":", row.counts.Created, row.counts.Updated, row.counts.Unchanged)
+	}
+	if len(r.Conflicts) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d conflicts (left unchanged):\n", len(r.Conflicts))
+	for _, c := range r.Conflicts {
+		fmt.Fprintf(w, "  %s %s: %s\n", c.Kind, c.ID, c.Reason)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/severity"
+)
+
+// defaultSeverityRules apply when no rules are configured: signals on
+// production agents with a high risk level and signals involving regulated
+// data go up a level each, and signals from development sandboxes go down
+// one.
+var defaultSeverityRules = []severity.Rule{
+	{
+		ID:           "prod-high-risk",
+		Environments: []string{"prod", "production"},
+		RiskLevels:   []string{"high", "critical"},
+		Adjust:       1,
+	},
+	{
+		ID:                  "regulated-data",
+		DataClassifications: []string{"pii", "phi", "pci", "restricted"},
+		Adjust:              1,
+	},
+	{
+		ID:           "dev-sandbox",
+		Environments: []string{"dev", "development", "sandbox", "local"},
+		Adjust:       -1,
+	},
+}
+
+// newSeverityScorer builds the severity scorer from configuration.
+func newSeverityScorer(cfg config.SeverityConfig) (*severity.Scorer, error) {
+	rules := defaultSeverityRules
+	if len(cfg.Rules) > 0 {
+		rules = make([]severity.Rule, 0, len(cfg.Rules))
+		seen := make(map[string]bool, len(cfg.Rules))
+		for i, rc := range cfg.Rules {
+			if rc.ID == "" {
+				return nil, fmt.Errorf("severity rule %d: id is required", i)
+			}
+			if seen[rc.ID] {
+				return nil, fmt.Errorf("severity rule %s: duplicate id", rc.ID)
+			}
+			seen[rc.ID] = true
+			if rc.Floor != "" && !validSeverities[rc.Floor] {
+				return nil, fmt.Errorf("severity rule %s: invalid floor %q", rc.ID, rc.Floor)
+			}
+			if rc.Ceiling != "" && !validSeverities[rc.Ceiling] {
+				return nil, fmt.Errorf("severity rule %s: invalid ceiling %q", rc.ID, rc.Ceiling)
+			}
+			if rc.Adjust == 0 && rc.Floor == "" && rc.Ceiling == "" {
+				return nil, fmt.Errorf("severity rule %s: adjust, floor or ceiling is required", rc.ID)
+			}
+			rules = append(rules, severity.Rule{
+				ID:                  rc.ID,
+				Environments:        rc.Environments,
+				RiskLevels:          rc.RiskLevels,
+				DataClassifications: rc.DataClassifications,
+				Adjust:              rc.Adjust,
+				Floor:               rc.Floor,
+				Ceiling:             rc.Ceiling,
+			})
+		}
+	}
+
+	assets := make(map[string]severity.Asset, len(cfg.Assets))
+	for id, ac := range cfg.Assets {
+		assets[id] = severity.Asset{
+			Environment:         ac.Environment,
+			RiskLevel:           ac.RiskLevel,
+			DataClassifications: ac.DataClassifications,
+		}
+	}
+	return severity.New(severity.Config{Rules: rules, Assets: assets}), nil
+}
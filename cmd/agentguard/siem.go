@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/siem"
+)
+
+// newSyslogExporter builds the syslog CEF exporter from configuration.
+func newSyslogExporter(cfg config.SyslogConfig) (*siem.Syslog, error) {
+	sc := siem.SyslogConfig{
+		Network:   cfg.Network,
+		Addr:      cfg.Address,
+		Facility:  cfg.Facility,
+		Format:    cfg.Format,
+		Hostname:  cfg.Hostname,
+		Version:   version,
+		Fields:    cfg.Fields,
+		QueueSize: cfg.QueueSize,
+	}
+	if cfg.Network == siem.NetworkTLS {
+		tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: cfg.ServerName}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading syslog CA: %w", err)
+			}
+			tlsCfg.RootCAs = x509.NewCertPool()
+			if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates in %s", cfg.CAFile)
+			}
+		}
+		if cfg.CertFile != "" || cfg.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("loading syslog client certificate: %w", err)
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+		sc.TLS = tlsCfg
+	}
+	return siem.NewSyslog(sc)
+}
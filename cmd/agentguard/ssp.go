@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newExportSSPCmd() *cobra.Command {
+	sspCmd := &cobra.Command{
+		Use:   "ssp",
+		Short: "Generate a System Security Plan from tracked implementations",
+		Long: `Generate a System Security Plan (SSP) for one framework from the
+organization's tracked control implementations: each implemented control
+with its implementation notes as the narrative, its owners as responsible
+roles and its attestations and passing monitoring checks as evidence,
+followed by the residual gaps with their owners, due dates and risk
+dispositions.
+
+Examples:
+  # Markdown SSP for ISO 42001
+  agentguard export ssp --config config.yaml --framework iso-42001 > ssp.md
+
+  # Word document for one agent under NIST 800-53, inheriting from the
+  # landing zone
+  agentguard export ssp --config config.yaml --framework nist-800-53 \
+    --agent 6f1c... --providers aws-landing-zone --output docx --out ssp.docx`,
+		Args: cobra.NoArgs,
+		RunE: runExportSSP,
+	}
+	sspCmd.Flags().StringP("config", "c", "", "Path to configuration file")
+	sspCmd.Flags().StringP("framework", "f", "", "Framework the plan is for")
+	sspCmd.Flags().String("org", "", "Organization to document; defaults to the default organization")
+	sspCmd.Flags().String("agent", "", "Registered agent ID to scope the plan to")
+	sspCmd.Flags().StringSlice("providers", nil, "Common control providers the system inherits from")
+	sspCmd.Flags().StringP("output", "o", controls.ReportMarkdown, "Output format: markdown, docx or json")
+	sspCmd.Flags().String("out", "", "Write to this file instead of standard output")
+	_ = sspCmd.MarkFlagRequired("framework")
+	return sspCmd
+}
+
+func runExportSSP(cmd *cobra.Command, _ []string) error {
+	configureLogging(false)
+	flags := cmd.Flags()
+	framework, _ := flags.GetString("framework")
+	orgID, _ := flags.GetString("org")
+	agentID, _ := flags.GetString("agent")
+	providers, _ := flags.GetStringSlice("providers")
+	output, _ := flags.GetString("output")
+	outPath, _ := flags.GetString("out")
+	if output != controls.ReportMarkdown && output != controls.ReportDOCX && output != controls.ReportJSON {
+		return fmt.Errorf("unsupported output format %q: use markdown, docx or json", output)
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg, db, err := connectDatabase(ctx, cmd, "ssp")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if orgID == "" {
+		orgID = cfg.Quotas.DefaultOrg
+	}
+
+	analyzer, err := controls.NewGapAnalyzer(cfg.Controls.DataDir)
+	if err != nil {
+		return fmt.Errorf("initializing analyzer: %w", err)
+	}
+	if cfg.Controls.ProvidersPath != "" {
+		if err := analyzer.LoadProviders(cfg.Controls.ProvidersPath); err != nil {
+			return fmt.Errorf("loading control providers: %w", err)
+		}
+	}
+	in := &controls.SSPInput{OrganizationID: orgID, Framework: framework, Providers: providers}
+	if agentID != "" {
+		id, err := uuid.Parse(agentID)
+		if err != nil {
+			return fmt.Errorf("invalid agent ID %q: %w", agentID, err)
+		}
+		if in.Agent, err = postgres.NewAgentRepository(db).Get(ctx, id); err != nil {
+			return fmt.Errorf("loading agent: %w", err)
+		}
+		if in.Agent == nil {
+			return fmt.Errorf("agent %s is not registered", agentID)
+		}
+	}
+	if in.Implementations, err = postgres.NewControlImplementationRepository(db).List(ctx, orgID, framework); err != nil {
+		return fmt.Errorf("loading control implementations: %w", err)
+	}
+	attestations := postgres.NewAttestationRepository(db)
+	campaigns, err := attestations.ListCampaigns(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("loading attestation campaigns: %w", err)
+	}
+	for _, c := range campaigns {
+		if c.FrameworkID != "" && c.FrameworkID != framework {
+			continue
+		}
+		list, err := attestations.ListAttestations(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("loading attestations: %w", err)
+		}
+		in.Attestations = append(in.Attestations, list...)
+	}
+	if in.Dispositions, err = postgres.NewGapDispositionRepository(db).List(ctx, orgID, framework); err != nil {
+		return fmt.Errorf("loading gap dispositions: %w", err)
+	}
+
+	ssp, err := analyzer.SSP(ctx, in, time.Now())
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if output == controls.ReportJSON {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(ssp)
+	} else {
+		err = controls.WriteSSP(&buf, ssp, output)
+	}
+	if err != nil {
+		return fmt.Errorf("writing ssp: %w", err)
+	}
+	if outPath != "" {
+		return os.WriteFile(outPath, buf.Bytes(), 0o644)
+	}
+	_, err = cmd.OutOrStdout().Write(buf.Bytes())
+	return err
+}
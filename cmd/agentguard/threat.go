@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/multiagent"
+	"github.com/agentguard/agentguard/internal/threat"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newThreatCalibrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "calibrate [model-file]",
+		Short: "Raise threat likelihoods from observed security signals",
+		Long: `Count the security signals observed for a threat model's target agent
+and raise the likelihood of the threats they are evidence of, recording the
+signals as provenance and rescoring risk. Likelihoods are recomputed from the
+design-time values each run, so calibration can be repeated.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runThreatCalibrate,
+	}
+	cmd.Flags().StringP("config", "c", "", "Path to configuration file")
+	cmd.Flags().String("org", "", "Organization whose signals are counted; defaults to the default organization")
+	cmd.Flags().Duration("window", 30*24*time.Hour, "How far back signals are counted")
+	cmd.Flags().String("out", "", "Write the calibrated model to this file instead of stdout; may be the input file")
+	return cmd
+}
+
+func runThreatCalibrate(cmd *cobra.Command, args []string) error {
+	configureLogging(false)
+	flags := cmd.Flags()
+	configPath, _ := flags.GetString("config")
+	orgID, _ := flags.GetString("org")
+	window, _ := flags.GetDuration("window")
+	outPath, _ := flags.GetString("out")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if orgID == "" {
+		orgID = cfg.Quotas.DefaultOrg
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var tm models.ThreatModel
+	if err := json.Unmarshal(data, &tm); err != nil {
+		return fmt.Errorf("decoding threat model: %w", err)
+	}
+
+	ctx := context.Background()
+	metrics, err := evidenceMetrics(ctx, cfg.Observability.ClickHouse)
+	if err != nil {
+		return fmt.Errorf("connecting to ClickHouse: %w", err)
+	}
+	if metrics == nil {
+		return fmt.Errorf("calibration needs ClickHouse signal rollups: enable observability.clickhouse")
+	}
+	adjustments, err := threat.Calibrate(ctx, metrics, orgID, &tm, threat.Calibration{Window: window}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	for _, a := range adjustments {
+		fmt.Fprintf(os.Stderr, "%s: likelihood %s -> %s (signal score %.1f)\n", a.ThreatID, a.From, a.To, a.Score)
+	}
+	if len(adjustments) == 0 {
+		fmt.Fprintln(os.Stderr, "No likelihood changes")
+	}
+
+	out, err := json.MarshalIndent(tm, "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+	if outPath == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return os.WriteFile(outPath, out, 0o644)
+}
+
+func newThreatPathsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "paths [agent-file]",
+		Short: "Enumerate attack paths through an agent's tools",
+		Long: `Build a capability graph of an agent's tools from its JSON definition and
+list the paths from untrusted input sources (web, inbound email) to egress
+or action tools, with the tools to guard to break them, and a recommended
+sandbox profile for each code execution tool.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runThreatPaths,
+	}
+	cmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	cmd.Flags().Int("max-steps", 4, "Maximum tools in a path")
+	return cmd
+}
+
+func runThreatPaths(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	maxSteps, _ := cmd.Flags().GetInt("max-steps")
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var agent models.Agent
+	if err := json.Unmarshal(data, &agent); err != nil {
+		return fmt.Errorf("decoding agent: %w", err)
+	}
+	agentID := agent.Name
+	if agent.ID != uuid.Nil {
+		agentID = agent.ID.String()
+	}
+	analysis := threat.AnalyzePaths(agentID, agent.Tools, threat.PathOptions{MaxSteps: maxSteps})
+
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(analysis)
+	}
+
+	fmt.Printf("\nAttack paths for %s: %d\n", agentID, len(analysis.Paths))
+	fmt.Printf("══════════════════════════════\n\n")
+	if len(analysis.Paths) == 0 {
+		fmt.Printf("No path from an untrusted source to an egress or action tool.\n")
+	} else {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "RISK\tKIND\tPATH\n")
+		for _, p := range analysis.Paths {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Risk, p.Kind, strings.Join(p.Tools, " → "))
+		}
+		tw.Flush()
+		fmt.Printf("\nChokepoints:\n")
+		for _, c := range analysis.Chokepoints {
+			fmt.Printf("  %s (%d paths): %s\n", c.Tool, c.Paths, c.Suggestion)
+		}
+	}
+	if len(analysis.Sandboxes) > 0 {
+		fmt.Printf("\nSandboxes for code execution tools:\n")
+		for _, s := range analysis.Sandboxes {
+			network := s.Profile.Network.Mode
+			if len(s.Profile.Network.Allow) > 0 {
+				network += " " + strings.Join(s.Profile.Network.Allow, ",")
+			}
+			fmt.Printf("  %s: %s (network %s, writable %s)\n", s.Tool, s.Profile.Name, network, strings.Join(s.Profile.Filesystem.Writable, ","))
+			for _, r := range s.Rationale {
+				fmt.Printf("    - %s\n", r)
+			}
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func newThreatGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group [group-file]",
+		Short: "Analyze delegation between the agents of a multi-agent group",
+		Long: `Read an agent group (a crew or orchestration) from its JSON definition and
+report the policies each member inherits from the agents that delegate to
+it, the threats at cross-agent trust boundaries, and delegation loops.
+With -o mermaid, print a diagram of the group's trust zones instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runThreatGroup,
+	}
+	cmd.Flags().StringP("output", "o", "text", "Output format: text, json or mermaid")
+	return cmd
+}
+
+func runThreatGroup(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	g, err := loadAgentGroup(args[0])
+	if err != nil {
+		return err
+	}
+
+	tm := threat.AnalyzeGroup(g)
+	scopes := multiagent.Propagate(g)
+	switch outputFormat {
+	case "mermaid":
+		fmt.Print(threat.GroupDiagram(g))
+		return nil
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"threat_model": tm,
+			"scopes":       scopes,
+			"cycles":       multiagent.Cycles(g),
+		})
+	}
+
+	fmt.Printf("\nAgent group %s: %d agents, %d delegations\n", g.Name, len(g.Members), len(g.Delegations))
+	fmt.Printf("══════════════════════════════\n\n")
+	zones := make(map[string]string, len(g.Members))
+	for _, m := range g.Members {
+		zones[m.AgentID] = multiagent.Zone(m)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "AGENT\tZONE\tCALLERS\tPOLICIES\n")
+	for _, s := range scopes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.AgentID, zones[s.AgentID], orDash(s.Callers), orDash(s.Policies))
+	}
+	tw.Flush()
+
+	fmt.Printf("\nThreats: %d\n", len(tm.Threats))
+	if len(tm.Threats) > 0 {
+		tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "RISK\tCATEGORY\tTHREAT\n")
+		for _, t := range tm.Threats {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", t.RiskLevel, t.Category, t.Title)
+		}
+		tw.Flush()
+	}
+	fmt.Println()
+	return nil
+}
+
+func orDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
+
+// loadAgentGroup reads and validates an agent group definition.
+func loadAgentGroup(path string) (*models.AgentGroup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var g models.AgentGroup
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("decoding agent group %s: %w", path, err)
+	}
+	if err := multiagent.Validate(&g); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &g, nil
+}
+
+// newSpawnPolicy builds the sub-agent spawn policy from the configured
+// agent group definitions.
+func newSpawnPolicy(cfg config.GroupsConfig) (*multiagent.SpawnPolicy, error) {
+	groups := make([]models.AgentGroup, 0, len(cfg.Files))
+	for _, path := range cfg.Files {
+		g, err := loadAgentGroup(path)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *g)
+	}
+	return multiagent.NewSpawnPolicy(groups)
+}
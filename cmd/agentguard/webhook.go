@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/rs/zerolog/log"
+)
+
+// newWebhook returns the sender for a configured webhook destination, or
+// nil when url is empty. name identifies the destination in errors and
+// warnings.
+func newWebhook(name, url, secret string) (*webhook.Sender, error) {
+	if url == "" {
+		return nil, nil
+	}
+	hook, err := webhook.New(url, secret)
+	if err != nil {
+		return nil, fmt.Errorf("configuring %s webhook: %w", name, err)
+	}
+	if !hook.Signed() {
+		log.Warn().Str("webhook", name).Msg("Webhook has no secret; receivers cannot verify its requests")
+	}
+	return hook, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/signing"
+	"github.com/agentguard/agentguard/internal/workload"
+	"github.com/rs/zerolog/log"
+)
+
+// IDs of the API keys configured by auth.bearer_token and
+// auth.previous_bearer_token.
+const (
+	bearerKeyID         = "bearer"
+	previousBearerKeyID = "bearer-previous"
+)
+
+// newAPIKeyring builds the API keys bearer tokens are checked against:
+// auth.bearer_token, auth.previous_bearer_token and auth.api_keys. Weak and
+// already expired keys are logged.
+func newAPIKeyring(cfg config.AuthConfig) (*apikey.Keyring, error) {
+	keys := append([]config.APIKeyConfig(nil), cfg.APIKeys...)
+	if cfg.PreviousBearerToken != "" {
+		keys = append([]config.APIKeyConfig{{
+			ID: previousBearerKeyID, Token: cfg.PreviousBearerToken, ExpiresAt: cfg.PreviousBearerTokenExpiresAt,
+		}}, keys...)
+	}
+	if cfg.BearerToken != "" {
+		keys = append([]config.APIKeyConfig{{ID: bearerKeyID, Token: cfg.BearerToken}}, keys...)
+	}
+
+	parsed := make([]apikey.Key, 0, len(keys))
+	now := time.Now()
+	for _, k := range keys {
+		key := apikey.Key{ID: k.ID, Token: k.Token, Org: k.Org, Scopes: k.Scopes}
+		for _, ts := range []struct {
+			name, value string
+			dst         *time.Time
+		}{{"not_before", k.NotBefore, &key.NotBefore}, {"expires_at", k.ExpiresAt, &key.ExpiresAt}} {
+			if ts.value == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, ts.value)
+			if err != nil {
+				return nil, fmt.Errorf("API key %s: invalid %s: %w", k.ID, ts.name, err)
+			}
+			*ts.dst = t
+		}
+		if len(k.Token) < apikey.MinTokenLength {
+			log.Warn().Str("api_key_id", k.ID).Int("token_len", len(k.Token)).
+				Msg("API key token is shorter than 32 chars — consider using a stronger token")
+		}
+		if !key.ExpiresAt.IsZero() && !now.Before(key.ExpiresAt) {
+			log.Warn().Str("api_key_id", k.ID).Time("expires_at", key.ExpiresAt).Msg("API key has expired and will be refused")
+		}
+		parsed = append(parsed, key)
+	}
+	return apikey.New(parsed...)
+}
+
+// newWorkloadVerifier builds the SDK workload identity verifier from
+// configuration.
+func newWorkloadVerifier(cfg config.WorkloadConfig) (*workload.Verifier, error) {
+	wc := workload.Config{
+		Issuers:  make([]workload.Issuer, 0, len(cfg.Issuers)),
+		Bindings: make([]workload.Binding, 0, len(cfg.Bindings)),
+		Leeway:   time.Duration(cfg.LeewaySeconds) * time.Second,
+	}
+	for _, is := range cfg.Issuers {
+		wc.Issuers = append(wc.Issuers, workload.Issuer{
+			URL:       is.URL,
+			JWKSURL:   is.JWKSURL,
+			JWKSFile:  is.JWKSFile,
+			Audiences: is.Audiences,
+		})
+	}
+	for _, b := range cfg.Bindings {
+		wc.Bindings = append(wc.Bindings, workload.Binding{
+			Issuer:  b.Issuer,
+			Subject: b.Subject,
+			AgentID: b.AgentID,
+			OrgID:   b.OrgID,
+		})
+	}
+	return workload.NewVerifier(wc)
+}
+
+// newSPIFFE builds the SDK SVID verifier and the server's own SVID source
+// from configuration.
+func newSPIFFE(cfg config.SPIFFEConfig) (*workload.SVIDVerifier, *workload.SVIDSource, error) {
+	bindings := make([]workload.Binding, 0, len(cfg.Bindings))
+	for _, b := range cfg.Bindings {
+		bindings = append(bindings, workload.Binding{
+			Subject: b.Subject,
+			AgentID: b.AgentID,
+			OrgID:   b.OrgID,
+		})
+	}
+	verifier, err := workload.NewSVIDVerifier(workload.SPIFFEConfig{
+		TrustDomain: cfg.TrustDomain,
+		Bindings:    bindings,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := workload.NewSVIDSource(cfg.CertFile, cfg.KeyFile, cfg.BundleFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return verifier, source, nil
+}
+
+// signingOptions converts request signing configuration.
+func signingOptions(cfg config.RequestSigningConfig) signing.Options {
+	return signing.Options{
+		Tolerance: time.Duration(cfg.ToleranceSeconds) * time.Second,
+		Grace:     time.Duration(cfg.RotationGraceSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Agent list page sizes.
+const (
+	defaultAgentLimit = 50
+	maxAgentLimit     = 200
+)
+
+var agentListSpec = listSpec{defaultLimit: defaultAgentLimit, maxLimit: maxAgentLimit, sorts: repository.AgentSortFields}
+
+var (
+	agentEnvironments = []string{"dev", "staging", "prod"}
+	agentRiskLevels   = []string{"low", "medium", "high", "critical"}
+	agentStatuses     = []models.AgentStatus{
+		models.AgentStatusActive, models.AgentStatusInactive, models.AgentStatusSuspended, models.AgentStatusDeprecated,
+	}
+)
+
+// AgentRequest registers or updates an agent. Policies are bound through
+// PUT /agents/:id/policies.
+type AgentRequest struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Framework    string               `json:"framework"`
+	Version      string               `json:"version"`
+	Owner        string               `json:"owner"`
+	Team         string               `json:"team"`
+	Environment  string               `json:"environment"`
+	Capabilities []models.Capability  `json:"capabilities"`
+	Tools        []models.ToolBinding `json:"tools"`
+	RiskLevel    string               `json:"risk_level"`
+	// Status defaults to active.
+	Status models.AgentStatus `json:"status"`
+}
+
+// apply validates the request and copies it to a. It returns a message
+// describing the first invalid field.
+func (req *AgentRequest) apply(a *models.Agent) string {
+	name := strings.TrimSpace(req.Name)
+	switch {
+	case name == "" || len(name) > 255:
+		return "name is required and at most 255 characters"
+	case strings.TrimSpace(req.Framework) == "":
+		return "framework is required, e.g. langchain, crewai or autogen"
+	case !slices.Contains(agentEnvironments, req.Environment):
+		return "environment must be dev, staging or prod"
+	case req.RiskLevel != "" && !slices.Contains(agentRiskLevels, req.RiskLevel):
+		return "risk_level must be low, medium, high or critical"
+	}
+	status := req.Status
+	if status == "" {
+		status = models.AgentStatusActive
+	}
+	if !slices.Contains(agentStatuses, status) {
+		return "status must be active, inactive, suspended or deprecated"
+	}
+	for _, cp := range req.Capabilities {
+		if strings.TrimSpace(cp.Name) == "" {
+			return "every capability needs a name"
+		}
+		if cp.RiskLevel != "" && !slices.Contains(agentRiskLevels, cp.RiskLevel) {
+			return "capability risk_level must be low, medium, high or critical"
+		}
+	}
+	for _, t := range req.Tools {
+		if strings.TrimSpace(t.Name) == "" {
+			return "every tool needs a name"
+		}
+	}
+
+	a.Name, a.Description = name, req.Description
+	a.Framework, a.Version = strings.TrimSpace(req.Framework), strings.TrimSpace(req.Version)
+	a.Owner, a.Team = strings.TrimSpace(req.Owner), strings.TrimSpace(req.Team)
+	a.Environment, a.RiskLevel, a.Status = req.Environment, req.RiskLevel, status
+	a.Capabilities = append([]models.Capability{}, req.Capabilities...)
+	a.Tools = append([]models.ToolBinding{}, req.Tools...)
+	return ""
+}
+
+// AgentPoliciesRequest replaces the policies bound to an agent.
+type AgentPoliciesRequest struct {
+	PolicyIDs []string `json:"policy_ids"`
+}
+
+// ListAgents returns registered agents ordered by name, filtered by the
+// status, environment, team, framework and policy query parameters, and
+// paged and sorted like the other lists.
+func (h *Handlers) ListAgents(c *gin.Context) {
+	if h.Agents == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent registry not configured"})
+		return
+	}
+	page, ok := parseListPage(c, agentListSpec)
+	if !ok {
+		return
+	}
+	filters := &repository.AgentFilters{Sort: page.sort}
+	if v := c.Query("status"); v != "" {
+		status := models.AgentStatus(v)
+		if !slices.Contains(agentStatuses, status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status", "details": "use active, inactive, suspended or deprecated"})
+			return
+		}
+		filters.Status = &status
+	}
+	if v := c.Query("environment"); v != "" {
+		if !slices.Contains(agentEnvironments, v) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid environment", "details": "use dev, staging or prod"})
+			return
+		}
+		filters.Environment = &v
+	}
+	if v := c.Query("team"); v != "" {
+		filters.Team = &v
+	}
+	if v := c.Query("framework"); v != "" {
+		filters.Framework = &v
+	}
+	if v := c.Query("policy"); v != "" {
+		filters.Policy = &v
+	}
+
+	ctx := c.Request.Context()
+	total, err := h.Agents.Count(ctx, filters)
+	if err != nil {
+		respondRepoError(c, err, "failed to count agents")
+		return
+	}
+	filters.Offset, filters.Limit = page.offset, page.limit
+	agents, err := h.Agents.List(ctx, filters)
+	if err != nil {
+		respondRepoError(c, err, "failed to list agents")
+		return
+	}
+	respondList(c, page, total, "agents", agents, nil)
+}
+
+// GetAgent returns a registered agent.
+func (h *Handlers) GetAgent(c *gin.Context) {
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// RegisterAgent adds an agent to the registry.
+func (h *Handlers) RegisterAgent(c *gin.Context) {
+	if h.Agents == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent registry not configured"})
+		return
+	}
+	var req AgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	a := &models.Agent{ID: uuid.New(), Policies: []string{}}
+	if msg := req.apply(a); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent", "details": msg})
+		return
+	}
+	if err := h.Agents.Create(c.Request.Context(), a); err != nil {
+		respondRepoError(c, err, "failed to register agent")
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+// UpdateAgent replaces an agent's registration, keeping its bound policies
+// and last activity.
+func (h *Handlers) UpdateAgent(c *gin.Context) {
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	var req AgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if msg := req.apply(a); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent", "details": msg})
+		return
+	}
+	if err := h.Agents.Update(c.Request.Context(), a); err != nil {
+		respondRepoError(c, err, "failed to update agent")
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// DeleteAgent removes an agent from the registry.
+func (h *Handlers) DeleteAgent(c *gin.Context) {
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	if err := h.Agents.Delete(c.Request.Context(), a.ID); err != nil {
+		respondRepoError(c, err, "failed to delete agent")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetAgentPolicies returns the policies bound to an agent. When a policy
+// store is configured the full definitions are returned; otherwise only
+// the IDs are set.
+func (h *Handlers) GetAgentPolicies(c *gin.Context) {
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	policies, err := h.Agents.GetPolicies(ctx, a.ID)
+	if err != nil {
+		respondRepoError(c, err, "failed to get agent policies")
+		return
+	}
+	if h.Policies != nil {
+		for i := range policies {
+			p, err := h.Policies.Get(ctx, policies[i].ID)
+			if err != nil {
+				respondRepoError(c, err, "failed to get agent policies")
+				return
+			}
+			if p != nil {
+				policies[i] = *p
+			}
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"agent_id": a.ID, "policies": policies})
+}
+
+// BindAgentPolicies replaces the policies bound to an agent. An empty
+// policy_ids unbinds them all. When a policy store is configured, every
+// policy must exist.
+func (h *Handlers) BindAgentPolicies(c *gin.Context) {
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	var req AgentPoliciesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	ids := make([]string, 0, len(req.PolicyIDs))
+	for _, id := range req.PolicyIDs {
+		if !validateID(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid policy ID format", "details": id})
+			return
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	ctx := c.Request.Context()
+	if h.Policies != nil {
+		var missing []string
+		for _, id := range ids {
+			p, err := h.Policies.Get(ctx, id)
+			if err != nil {
+				respondRepoError(c, err, "failed to bind agent policies")
+				return
+			}
+			if p == nil {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "unknown policies", "policy_ids": missing})
+			return
+		}
+	}
+	if err := h.Agents.BindPolicies(ctx, a.ID, ids); err != nil {
+		respondRepoError(c, err, "failed to bind agent policies")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"agent_id": a.ID, "policy_ids": ids})
+}
+
+// loadAgent fetches the agent named by the id path parameter, responding
+// 404 when it is missing.
+func (h *Handlers) loadAgent(c *gin.Context) (*models.Agent, bool) {
+	if h.Agents == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent registry not configured"})
+		return nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent ID format"})
+		return nil, false
+	}
+	a, err := h.Agents.Get(c.Request.Context(), id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get agent")
+		return nil, false
+	}
+	if a == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "agent not found"})
+		return nil, false
+	}
+	return a, true
+}
@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+func TestAgentRegistry(t *testing.T) {
+	policies := memory.NewPolicyRepository()
+	if err := policies.Create(context.Background(), &models.Policy{ID: "pii-filter", Name: "PII filter", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		Agents:      memory.NewAgentRepository(),
+		Policies:    policies,
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"},
+			apikey.Key{ID: "viewer", Token: "viewer-token", Org: "acme", Scopes: []string{"read:controls", "read:policies"}},
+		),
+	})
+	valid := api.AgentRequest{Name: "support-bot", Framework: "langchain", Environment: "prod", Team: "support"}
+
+	for name, req := range map[string]api.AgentRequest{
+		"no name":         {Framework: "langchain", Environment: "prod"},
+		"no framework":    {Name: "support-bot", Environment: "prod"},
+		"bad environment": {Name: "support-bot", Framework: "langchain", Environment: "qa"},
+		"bad risk level":  {Name: "support-bot", Framework: "langchain", Environment: "prod", RiskLevel: "severe"},
+		"bad status":      {Name: "support-bot", Framework: "langchain", Environment: "prod", Status: "retired"},
+		"unnamed tool":    {Name: "support-bot", Framework: "langchain", Environment: "prod", Tools: []models.ToolBinding{{}}},
+	} {
+		if w := do(srv, http.MethodPost, "/api/v1/agents", "ci-token", req); w.Code != http.StatusBadRequest {
+			t.Errorf("register with %s = %d, want 400", name, w.Code)
+		}
+	}
+	if w := do(srv, http.MethodPost, "/api/v1/agents", "viewer-token", valid); w.Code != http.StatusForbidden {
+		t.Errorf("register without write:agents = %d, want 403", w.Code)
+	}
+	w := do(srv, http.MethodPost, "/api/v1/agents", "ci-token", valid)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("register = %d %s, want 201", w.Code, w.Body)
+	}
+	agent := decode[models.Agent](t, w)
+	if agent.Status != models.AgentStatusActive {
+		t.Errorf("status = %q, want active by default", agent.Status)
+	}
+	path := "/api/v1/agents/" + agent.ID.String()
+	if w := do(srv, http.MethodPost, "/api/v1/agents", "ci-token", api.AgentRequest{Name: "batch-bot", Framework: "crewai", Environment: "dev"}); w.Code != http.StatusCreated {
+		t.Fatalf("register = %d %s, want 201", w.Code, w.Body)
+	}
+
+	for query, want := range map[string]int{
+		"":                   2,
+		"?environment=prod":  1,
+		"?team=support":      1,
+		"?framework=crewai":  1,
+		"?status=suspended":  0,
+		"?policy=pii-filter": 0,
+	} {
+		w := do(srv, http.MethodGet, "/api/v1/agents"+query, "viewer-token", nil)
+		if got := decode[struct{ Total int }](t, w); w.Code != http.StatusOK || got.Total != want {
+			t.Errorf("list%s = %d %s, want %d agents", query, w.Code, w.Body, want)
+		}
+	}
+	for _, query := range []string{"?status=retired", "?environment=qa", "?limit=0"} {
+		if w := do(srv, http.MethodGet, "/api/v1/agents"+query, "ci-token", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("list%s = %d, want 400", query, w.Code)
+		}
+	}
+
+	for _, p := range []string{"/api/v1/agents/not-a-uuid", "/api/v1/agents/not-a-uuid/policies"} {
+		if w := do(srv, http.MethodGet, p, "ci-token", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", p, w.Code)
+		}
+	}
+	unknown := "/api/v1/agents/" + uuid.NewString()
+	if w := do(srv, http.MethodGet, unknown, "ci-token", nil); w.Code != http.StatusNotFound {
+		t.Errorf("get an unknown agent = %d, want 404", w.Code)
+	}
+	if w := do(srv, http.MethodPut, unknown, "ci-token", valid); w.Code != http.StatusNotFound {
+		t.Errorf("update an unknown agent = %d, want 404", w.Code)
+	}
+
+	if w := do(srv, http.MethodPut, path, "ci-token", api.AgentRequest{Name: "support-bot", Framework: "langchain"}); w.Code != http.StatusBadRequest {
+		t.Errorf("update without an environment = %d, want 400", w.Code)
+	}
+	update := valid
+	update.Status = models.AgentStatusSuspended
+	if w := do(srv, http.MethodPut, path, "ci-token", update); w.Code != http.StatusOK {
+		t.Errorf("update = %d %s, want 200", w.Code, w.Body)
+	}
+	if got := decode[models.Agent](t, do(srv, http.MethodGet, path, "viewer-token", nil)); got.Status != models.AgentStatusSuspended {
+		t.Errorf("status after update = %q, want suspended", got.Status)
+	}
+
+	bind := func(token string, ids ...string) int {
+		return do(srv, http.MethodPut, path+"/policies", token, api.AgentPoliciesRequest{PolicyIDs: ids}).Code
+	}
+	if code := bind("viewer-token", "pii-filter"); code != http.StatusForbidden {
+		t.Errorf("bind without write:agents = %d, want 403", code)
+	}
+	if code := bind("ci-token", "pii-filter", "no-such-policy"); code != http.StatusUnprocessableEntity {
+		t.Errorf("bind an unknown policy = %d, want 422", code)
+	}
+	if code := bind("ci-token", "bad id!"); code != http.StatusBadRequest {
+		t.Errorf("bind a malformed policy ID = %d, want 400", code)
+	}
+	if code := bind("ci-token", "pii-filter", "pii-filter"); code != http.StatusOK {
+		t.Errorf("bind = %d, want 200", code)
+	}
+	w = do(srv, http.MethodGet, path+"/policies", "viewer-token", nil)
+	got := decode[struct{ Policies []models.Policy }](t, w)
+	if len(got.Policies) != 1 || got.Policies[0].Name != "PII filter" {
+		t.Errorf("bound policies = %s, want the pii-filter definition", w.Body)
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/agents?policy=pii-filter", "viewer-token", nil); decode[struct{ Total int }](t, w).Total != 1 {
+		t.Errorf("list by policy = %s, want the bound agent", w.Body)
+	}
+
+	if w := do(srv, http.MethodDelete, path, "viewer-token", nil); w.Code != http.StatusForbidden {
+		t.Errorf("delete without write:agents = %d, want 403", w.Code)
+	}
+	if w := do(srv, http.MethodDelete, path, "ci-token", nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete = %d, want 204", w.Code)
+	}
+	if w := do(srv, http.MethodGet, path, "ci-token", nil); w.Code != http.StatusNotFound {
+		t.Errorf("get a deleted agent = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ApplicabilityRequest asks which of a framework's controls apply to a
+// system described by its traits.
+type ApplicabilityRequest struct {
+	Framework string          `json:"framework" binding:"required"`
+	Traits    map[string]bool `json:"traits"`
+	// Rules replace the default applicability rules. Optional.
+	Rules []controls.ApplicabilityRule `json:"rules,omitempty"`
+}
+
+// makeApplicabilityHandler serves POST /controls/applicability: a
+// framework's controls tailored to a system's traits. Traits not given are
+// false.
+func makeApplicabilityHandler(ga *controls.GapAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ApplicabilityRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		traits := make(map[string]bool, len(controls.KnownTraits))
+		for _, t := range controls.KnownTraits {
+			traits[t] = false
+		}
+		for t, v := range req.Traits {
+			if _, ok := traits[t]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown trait", "details": t})
+				return
+			}
+			traits[t] = v
+		}
+		for i := range req.Rules {
+			if err := req.Rules[i].Validate(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid applicability rule", "details": err.Error()})
+				return
+			}
+		}
+		respondBaseline(c, ga, req.Framework, traits, req.Rules)
+	}
+}
+
+// makeAgentBaselineHandler serves GET /agents/:id/baseline: the framework
+// query parameter's controls tailored to the traits derived from the
+// agent's registration. The traits query parameter, a comma-separated
+// list, sets traits the registration does not show, such as hosts_models.
+// The as_of query parameter uses the registration as it stood at a past
+// date or time.
+func makeAgentBaselineHandler(deps *RouterDeps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.Agents == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent registry not configured"})
+			return
+		}
+		framework := c.Query("framework")
+		if framework == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "framework is required"})
+			return
+		}
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent ID format"})
+			return
+		}
+		asOf, ok := asOfParam(c)
+		if !ok {
+			return
+		}
+		var agent *models.Agent
+		if asOf.IsZero() {
+			agent, err = deps.Agents.Get(c.Request.Context(), id)
+		} else if history, ok := deps.Agents.(repository.AgentHistory); ok {
+			agent, err = history.GetAsOf(c.Request.Context(), id, asOf)
+		} else {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "agent history not supported by this repository"})
+			return
+		}
+		if err != nil {
+			respondRepoError(c, err, "failed to get agent")
+			return
+		}
+		if agent == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "agent not found"})
+			return
+		}
+
+		traits := controls.AgentTraits(agent)
+		if extra := c.Query("traits"); extra != "" {
+			for _, t := range strings.Split(extra, ",") {
+				t = strings.TrimSpace(t)
+				if _, ok := traits[t]; !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "unknown trait", "details": t})
+					return
+				}
+				traits[t] = true
+			}
+		}
+		respondBaseline(c, deps.GapAnalyzer, framework, traits, nil)
+	}
+}
+
+func respondBaseline(c *gin.Context, ga *controls.GapAnalyzer, framework string, traits map[string]bool, rules []controls.ApplicabilityRule) {
+	if _, ok := ga.Framework(framework); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown framework", "details": framework})
+		return
+	}
+	baseline, err := ga.Baseline(framework, traits, rules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid applicability rules", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, baseline)
+}
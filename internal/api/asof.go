@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// asOfParam reads the as_of query parameter: an RFC 3339 time, or a date
+// meaning the end of that day in UTC, so as_of=2026-03-31 answers "what was
+// it on March 31"; today means now. The zero time means now. ok is false
+// after responding 400.
+func asOfParam(c *gin.Context) (asOf time.Time, ok bool) {
+	v := c.Query("as_of")
+	if v == "" {
+		return time.Time{}, true
+	}
+	now := time.Now()
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		asOf = t
+	} else if d, err := time.Parse(time.DateOnly, v); err == nil {
+		asOf = d
+		if end := d.AddDate(0, 0, 1).Add(-time.Nanosecond); !d.After(now) {
+			asOf = now
+			if end.Before(now) {
+				asOf = end
+			}
+		}
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of", "details": "must be a date (2006-01-02) or an RFC 3339 time"})
+		return time.Time{}, false
+	}
+	if asOf.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of", "details": "must not be in the future"})
+		return time.Time{}, false
+	}
+	return asOf, true
+}
+
+// implementationHistory returns the implementation repository's history
+// support, or responds 501 when it has none.
+func (h *Handlers) implementationHistory(c *gin.Context) (repository.ControlImplementationHistory, bool) {
+	repo, ok := h.Implementations.(repository.ControlImplementationHistory)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "implementation history not supported by this repository"})
+	}
+	return repo, ok
+}
+
+// listImplementations lists an organization's implementations, as they
+// stood at asOf unless it is zero. The caller has checked that history is
+// supported when asOf is set.
+func (h *Handlers) listImplementations(ctx context.Context, org, framework string, asOf time.Time) ([]models.ControlImplementation, error) {
+	if asOf.IsZero() {
+		return h.Implementations.List(ctx, org, framework)
+	}
+	return h.Implementations.(repository.ControlImplementationHistory).ListAsOf(ctx, org, framework, asOf)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/atlas"
+	"github.com/gin-gonic/gin"
+)
+
+// atlasTechnique is a technique with the IDs of the mitigations for it.
+type atlasTechnique struct {
+	atlas.Technique
+	URL         string   `json:"url"`
+	Mitigations []string `json:"mitigations"`
+}
+
+func newATLASTechnique(t atlas.Technique) atlasTechnique {
+	out := atlasTechnique{Technique: t, URL: t.URL(), Mitigations: []string{}}
+	for _, m := range atlas.MitigationsFor(t.ID) {
+		out.Mitigations = append(out.Mitigations, m.ID)
+	}
+	return out
+}
+
+// getATLASCatalog serves GET /threats/atlas: the MITRE ATLAS tactics,
+// techniques and mitigations. ?tactic= limits techniques to one tactic.
+func getATLASCatalog(c *gin.Context) {
+	list := atlas.Techniques()
+	if tactic := c.Query("tactic"); tactic != "" {
+		if _, ok := atlas.GetTactic(tactic); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown ATLAS tactic", "tactic": tactic})
+			return
+		}
+		list = atlas.TechniquesFor(tactic)
+	}
+	techniques := make([]atlasTechnique, 0, len(list))
+	for _, t := range list {
+		techniques = append(techniques, newATLASTechnique(t))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"source":      atlas.URL,
+		"tactics":     atlas.Tactics(),
+		"techniques":  techniques,
+		"mitigations": atlas.Mitigations(),
+		"total":       len(techniques),
+	})
+}
+
+// getATLASTechnique serves GET /threats/atlas/techniques/:id with the
+// technique's tactics, sub-techniques and mitigations.
+func getATLASTechnique(c *gin.Context) {
+	t, ok := atlas.GetTechnique(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ATLAS technique not found"})
+		return
+	}
+	tactics := make([]atlas.Tactic, 0, len(t.Tactics))
+	for _, id := range t.Tactics {
+		if tactic, ok := atlas.GetTactic(id); ok {
+			tactics = append(tactics, tactic)
+		}
+	}
+	mitigations := atlas.MitigationsFor(t.ID)
+	if mitigations == nil {
+		mitigations = []atlas.Mitigation{}
+	}
+	subs := []atlasTechnique{}
+	for _, s := range atlas.SubTechniques(t.ID) {
+		subs = append(subs, newATLASTechnique(s))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"technique":      newATLASTechnique(t),
+		"tactics":        tactics,
+		"sub_techniques": subs,
+		"mitigations":    mitigations,
+	})
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultReminderIntervalDays is how often owners are reminded when a
+// campaign does not say.
+const defaultReminderIntervalDays = 7
+
+// AttestationCampaignRequest starts an attestation campaign.
+type AttestationCampaignRequest struct {
+	Name string `json:"name" binding:"required"`
+	// FrameworkID limits the campaign to one framework. Empty covers every
+	// framework with tracked implementations.
+	FrameworkID string `json:"framework_id"`
+	// DueDate is a calendar date, YYYY-MM-DD.
+	DueDate string `json:"due_date" binding:"required"`
+	// ReminderIntervalDays defaults to 7; zero disables reminders.
+	ReminderIntervalDays *int `json:"reminder_interval_days"`
+	// DefaultOwner is assigned controls whose implementation has no owner.
+	// Without it those controls are left out and listed as unassigned.
+	DefaultOwner string `json:"default_owner"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// AttestationResponseRequest records an owner's response. Responder must be
+// the attestation's owner.
+type AttestationResponseRequest struct {
+	Responder string `json:"responder" binding:"required"`
+	Comment   string `json:"comment"`
+}
+
+// CreateAttestationCampaign assigns the caller's implemented and verified
+// controls to their owners for attestation.
+func (h *Handlers) CreateAttestationCampaign(c *gin.Context) {
+	if h.Attestations == nil || h.Implementations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "attestation campaigns not configured"})
+		return
+	}
+	var req AttestationCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and due_date are required"})
+		return
+	}
+	if req.FrameworkID != "" && !validFrameworkID.MatchString(req.FrameworkID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	due, err := time.Parse(time.DateOnly, req.DueDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "due_date must be a date as YYYY-MM-DD"})
+		return
+	}
+	interval := defaultReminderIntervalDays
+	if req.ReminderIntervalDays != nil {
+		interval = *req.ReminderIntervalDays
+	}
+	if interval < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reminder_interval_days must not be negative"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	org := c.GetString(orgKey)
+	impls, err := h.Implementations.List(ctx, org, req.FrameworkID)
+	if err != nil {
+		respondRepoError(c, err, "failed to load tracked control implementations")
+		return
+	}
+	campaign := &models.AttestationCampaign{
+		ID:                   uuid.NewString(),
+		OrganizationID:       org,
+		Name:                 strings.TrimSpace(req.Name),
+		FrameworkID:          req.FrameworkID,
+		Status:               models.CampaignOpen,
+		DueDate:              due,
+		ReminderIntervalDays: interval,
+		CreatedBy:            strings.TrimSpace(req.CreatedBy),
+	}
+	attestations, unassigned := controls.NewAttestations(campaign, impls, strings.TrimSpace(req.DefaultOwner))
+	if len(attestations) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "no controls to attest",
+			"details": "the campaign needs implemented or verified control implementations with an owner or a default_owner",
+		})
+		return
+	}
+	if err := h.Attestations.CreateCampaign(ctx, campaign, attestations); err != nil {
+		respondRepoError(c, err, "failed to create attestation campaign")
+		return
+	}
+	if unassigned == nil {
+		unassigned = []models.ControlImplementation{}
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"campaign":     campaign,
+		"attestations": attestations,
+		"unassigned":   unassigned,
+	})
+}
+
+// ListAttestationCampaigns returns the caller's campaigns, newest first,
+// optionally filtered by the status query parameter.
+func (h *Handlers) ListAttestationCampaigns(c *gin.Context) {
+	if h.Attestations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "attestation campaigns not configured"})
+		return
+	}
+	campaigns, err := h.Attestations.ListCampaigns(c.Request.Context(), c.GetString(orgKey))
+	if err != nil {
+		respondRepoError(c, err, "failed to list attestation campaigns")
+		return
+	}
+	if status := models.CampaignStatus(c.Query("status")); status != "" {
+		filtered := campaigns[:0]
+		for _, ac := range campaigns {
+			if ac.Status == status {
+				filtered = append(filtered, ac)
+			}
+		}
+		campaigns = filtered
+	}
+	if campaigns == nil {
+		campaigns = []models.AttestationCampaign{}
+	}
+	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns, "total": len(campaigns)})
+}
+
+// GetAttestationCampaign returns a campaign with its attestations.
+func (h *Handlers) GetAttestationCampaign(c *gin.Context) {
+	campaign, ok := h.loadCampaign(c)
+	if !ok {
+		return
+	}
+	attestations, err := h.Attestations.ListAttestations(c.Request.Context(), campaign.ID)
+	if err != nil {
+		respondRepoError(c, err, "failed to list attestations")
+		return
+	}
+	if attestations == nil {
+		attestations = []models.Attestation{}
+	}
+	c.JSON(http.StatusOK, gin.H{"campaign": campaign, "attestations": attestations})
+}
+
+// GetAttestationReport returns a campaign's completion report, as JSON or,
+// with format=text, as plain text.
+func (h *Handlers) GetAttestationReport(c *gin.Context) {
+	campaign, ok := h.loadCampaign(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format", "details": "format must be json or text"})
+		return
+	}
+	attestations, err := h.Attestations.ListAttestations(c.Request.Context(), campaign.ID)
+	if err != nil {
+		respondRepoError(c, err, "failed to list attestations")
+		return
+	}
+	report := controls.NewAttestationReport(campaign, attestations, time.Now().UTC())
+	if format == "text" {
+		c.String(http.StatusOK, report.Text())
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// CloseAttestationCampaign ends a campaign. Pending attestations stay
+// pending in its report and owners are no longer reminded.
+func (h *Handlers) CloseAttestationCampaign(c *gin.Context) {
+	campaign, ok := h.loadCampaign(c)
+	if !ok {
+		return
+	}
+	if campaign.Status == models.CampaignClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "attestation campaign already closed"})
+		return
+	}
+	now := time.Now().UTC()
+	campaign.Status, campaign.ClosedAt = models.CampaignClosed, &now
+	if err := h.Attestations.UpdateCampaign(c.Request.Context(), campaign); err != nil {
+		respondRepoError(c, err, "failed to close attestation campaign")
+		return
+	}
+	c.JSON(http.StatusOK, campaign)
+}
+
+// ListAttestations returns the attestations in the caller's open campaigns,
+// optionally filtered by the owner and status query parameters, so an
+// owner can find what awaits their response.
+func (h *Handlers) ListAttestations(c *gin.Context) {
+	if h.Attestations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "attestation campaigns not configured"})
+		return
+	}
+	ctx := c.Request.Context()
+	campaigns, err := h.Attestations.ListCampaigns(ctx, c.GetString(orgKey))
+	if err != nil {
+		respondRepoError(c, err, "failed to list attestation campaigns")
+		return
+	}
+	owner, status := c.Query("owner"), models.AttestationStatus(c.Query("status"))
+	list := []models.Attestation{}
+	for _, campaign := range campaigns {
+		if campaign.Status != models.CampaignOpen {
+			continue
+		}
+		attestations, err := h.Attestations.ListAttestations(ctx, campaign.ID)
+		if err != nil {
+			respondRepoError(c, err, "failed to list attestations")
+			return
+		}
+		for _, a := range attestations {
+			if (owner == "" || strings.EqualFold(a.Owner, owner)) && (status == "" || a.Status == status) {
+				list = append(list, a)
+			}
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"attestations": list, "total": len(list)})
+}
+
+// AttestControl records that the owner confirms the control is in place.
+func (h *Handlers) AttestControl(c *gin.Context) {
+	h.respondToAttestation(c, models.AttestationAttested)
+}
+
+// DeclineAttestation records that the owner cannot confirm the control is
+// in place. A comment explaining why is required.
+func (h *Handlers) DeclineAttestation(c *gin.Context) {
+	h.respondToAttestation(c, models.AttestationDeclined)
+}
+
+func (h *Handlers) respondToAttestation(c *gin.Context, status models.AttestationStatus) {
+	if h.Attestations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "attestation campaigns not configured"})
+		return
+	}
+	id := c.Param("id")
+	if !validateID(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attestation ID format"})
+		return
+	}
+	var req AttestationResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "responder is required"})
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if status == models.AttestationDeclined && comment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a comment is required to decline"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	a, err := h.Attestations.GetAttestation(ctx, id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get attestation")
+		return
+	}
+	if a == nil || a.OrganizationID != c.GetString(orgKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attestation not found"})
+		return
+	}
+	if !strings.EqualFold(strings.TrimSpace(req.Responder), a.Owner) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the control owner can respond", "details": "owner is " + a.Owner})
+		return
+	}
+	campaign, err := h.Attestations.GetCampaign(ctx, a.CampaignID)
+	if err != nil {
+		respondRepoError(c, err, "failed to get attestation campaign")
+		return
+	}
+	if campaign == nil || campaign.Status != models.CampaignOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "attestation campaign is closed"})
+		return
+	}
+
+	now := time.Now().UTC()
+	a.Status, a.Comment, a.RespondedBy, a.RespondedAt = status, comment, strings.TrimSpace(req.Responder), &now
+	if err := h.Attestations.UpdateAttestation(ctx, a); err != nil {
+		respondRepoError(c, err, "failed to record attestation")
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// loadCampaign fetches the campaign named by the id path parameter,
+// responding 404 when it is missing or belongs to another organization.
+func (h *Handlers) loadCampaign(c *gin.Context) (*models.AttestationCampaign, bool) {
+	if h.Attestations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "attestation campaigns not configured"})
+		return nil, false
+	}
+	id := c.Param("id")
+	if !validateID(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID format"})
+		return nil, false
+	}
+	campaign, err := h.Attestations.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get attestation campaign")
+		return nil, false
+	}
+	if campaign == nil || campaign.OrganizationID != c.GetString(orgKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attestation campaign not found"})
+		return nil, false
+	}
+	return campaign, true
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/siem"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// decisionRecord is the audit log payload for a pre-invoke decision.
+type decisionRecord struct {
+	Tool        string            `json:"tool,omitempty"`
+	Allow       bool              `json:"allow"`
+	Reasons     []string          `json:"reasons,omitempty"`
+	Violations  []opa.Violation   `json:"violations,omitempty"`
+	Degraded    bool              `json:"degraded,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Metadata    map[string]any    `json:"metadata,omitempty"`
+	APIKey      string            `json:"api_key,omitempty"` // ID of the key the request was made with
+}
+
+// auditDecision appends a pre-invoke decision to the audit log. It runs on
+// the request path so entries are chained in the order decisions are served;
+// a failed append is logged and does not change the decision. Denials are
+// also forwarded to syslog.
+func auditDecision(c *gin.Context, deps *RouterDeps, input *opa.EvaluationInput, d *opa.Decision) {
+	if deps == nil {
+		return
+	}
+	if !d.Allow && deps.Syslog != nil {
+		deps.Syslog.Deny(c.GetString(orgKey), syslogDenial(input, d))
+	}
+	if deps.Audit == nil {
+		return
+	}
+	rec := decisionRecord{
+		Allow:       d.Allow,
+		Reasons:     d.Reasons,
+		Violations:  d.Violations,
+		Degraded:    d.Degraded,
+		Environment: input.Environment,
+		Metadata:    d.Metadata,
+		APIKey:      c.GetString(apiKeyKey),
+	}
+	if input.Tool != nil {
+		rec.Tool = input.Tool.Name
+	}
+	if _, err := deps.Audit.Append(audit.KindDecision, c.GetString(orgKey), input.Agent.ID, rec); err != nil {
+		log.Error().Err(err).Str("agent_id", input.Agent.ID).Msg("failed to append decision to audit log")
+	}
+}
+
+// violationSeverity ranks rule severities for picking a denial's severity.
+var violationSeverity = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// syslogDenial describes a denied decision for the SIEM: the violated
+// policies and the most severe violation's severity.
+func syslogDenial(input *opa.EvaluationInput, d *opa.Decision) siem.Denial {
+	denial := siem.Denial{
+		AgentID:     input.Agent.ID,
+		Environment: input.Agent.Environment,
+		Reasons:     d.Reasons,
+	}
+	if input.Tool != nil {
+		denial.Tool = input.Tool.Name
+	}
+	seen := make(map[string]bool)
+	for _, v := range d.Violations {
+		if v.Policy != "" && !seen[v.Policy] {
+			seen[v.Policy] = true
+			denial.Policies = append(denial.Policies, v.Policy)
+		}
+		if violationSeverity[v.Severity] > violationSeverity[denial.Severity] {
+			denial.Severity = v.Severity
+		}
+	}
+	return denial
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/gin-gonic/gin"
+)
+
+// coverageBadgeColor picks a shields.io color for a coverage percentage.
+func coverageBadgeColor(pct float64) string {
+	switch {
+	case pct >= 90:
+		return "brightgreen"
+	case pct >= 75:
+		return "green"
+	case pct >= 50:
+		return "yellow"
+	case pct >= 25:
+		return "orange"
+	default:
+		return "red"
+	}
+}
+
+// badgeHex maps shields.io color names to the hex values drawn in SVG badges.
+var badgeHex = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"lightgrey":   "#9f9f9f",
+}
+
+// makeCoverageBadgeHandler serves GET /controls/coverage/badge, a shield
+// showing the organization's latest recorded coverage for a framework.
+// format=json returns the shields.io endpoint schema; the default is SVG.
+// Frameworks without a recorded analysis show "unknown".
+func makeCoverageBadgeHandler(history *controls.CoverageHistory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		framework := c.Query("framework")
+		if !validFrameworkID.MatchString(framework) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework", "details": "framework query parameter required"})
+			return
+		}
+		format := c.DefaultQuery("format", "svg")
+		if format != "svg" && format != "json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format", "details": "format must be svg or json"})
+			return
+		}
+
+		label := c.DefaultQuery("label", framework)
+		if len(label) > 64 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid label", "details": "label exceeds 64 characters"})
+			return
+		}
+		message, color := "unknown", "lightgrey"
+		if p, ok := history.Latest(c.GetString(orgKey), framework); ok {
+			message = fmt.Sprintf("%.0f%%", p.Coverage)
+			color = coverageBadgeColor(p.Coverage)
+			c.Header("Last-Modified", p.Time.UTC().Format(http.TimeFormat))
+		}
+		// Badges are embedded in pages that may be cached; keep them fresh.
+		c.Header("Cache-Control", "no-cache, max-age=0")
+
+		if format == "json" {
+			c.JSON(http.StatusOK, gin.H{
+				"schemaVersion": 1,
+				"label":         label,
+				"message":       message,
+				"color":         color,
+			})
+			return
+		}
+		c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", []byte(renderBadge(label, message, badgeHex[color])))
+	}
+}
+
+// renderBadge draws a flat two-part badge. Text widths are estimated from
+// character count, which is close enough for short labels.
+func renderBadge(label, message, color string) string {
+	lw := 10 + 7*len([]rune(label))
+	mw := 10 + 7*len([]rune(message))
+	w := lw + mw
+	label, message = html.EscapeString(label), html.EscapeString(message)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, w, label, message)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, label, message)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" rx="3" fill="#555"/>`, w)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" rx="3" fill="%s"/>`, lw, mw, color)
+	fmt.Fprintf(&b, `<rect x="%d" width="4" height="20" fill="%s"/>`, lw, color)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw+mw/2, message)
+	b.WriteString(`</g></svg>`)
+	return b.String()
+}
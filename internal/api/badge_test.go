@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func TestCoverageBadge(t *testing.T) {
+	history := controls.NewCoverageHistory(0)
+	history.Record("acme", "nist-ai-rmf", time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC), 82.4)
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		Coverage: history,
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"},
+			apikey.Key{ID: "globex", Token: "globex-token", Org: "globex"},
+		),
+	})
+	type badge struct{ Label, Message, Color string }
+
+	w := do(srv, http.MethodGet, "/api/v1/controls/coverage/badge?framework=nist-ai-rmf&format=json", "ci-token", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("json badge = %d %s, want 200", w.Code, w.Body)
+	}
+	if got := decode[badge](t, w); got != (badge{"nist-ai-rmf", "82%", "green"}) {
+		t.Errorf("badge = %+v, want nist-ai-rmf 82%% green", got)
+	}
+	if w.Header().Get("Last-Modified") != "Fri, 01 May 2026 09:00:00 GMT" {
+		t.Errorf("Last-Modified = %q, want the analysis time", w.Header().Get("Last-Modified"))
+	}
+	if got := decode[badge](t, do(srv, http.MethodGet, "/api/v1/controls/coverage/badge?framework=nist-ai-rmf&format=json", "globex-token", nil)); got.Message != "unknown" || got.Color != "lightgrey" {
+		t.Errorf("another organization's badge = %+v, want unknown", got)
+	}
+
+	w = do(srv, http.MethodGet, "/api/v1/controls/coverage/badge?framework=nist-ai-rmf&label=%3Cb%3EAI%3C/b%3E", "ci-token", nil)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "image/svg+xml") {
+		t.Fatalf("svg badge = %d %s, want 200 image/svg+xml", w.Code, w.Header().Get("Content-Type"))
+	}
+	if svg := w.Body.String(); !strings.Contains(svg, "82%") || !strings.Contains(svg, "&lt;b&gt;AI") || strings.Contains(svg, "<b>") {
+		t.Errorf("svg = %s, want 82%% and an escaped label", svg)
+	}
+
+	for name, query := range map[string]string{
+		"without a framework":  "",
+		"with a bad framework": "framework=NIST%20AI",
+		"with a bad format":    "framework=nist-ai-rmf&format=png",
+		"with a long label":    "framework=nist-ai-rmf&label=" + strings.Repeat("x", 65),
+	} {
+		if w := do(srv, http.MethodGet, "/api/v1/controls/coverage/badge?"+query, "ci-token", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("badge %s = %d, want 400", name, w.Code)
+		}
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/controls/coverage/badge?framework=nist-ai-rmf", "", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("badge without a token = %d, want 401", w.Code)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// budgetHeader lets an SDK request a tighter decision deadline than the
+// server's configured budget, in milliseconds. It cannot extend the budget.
+const budgetHeader = "X-AgentGuard-Budget-Ms"
+
+const instrumentationName = "github.com/agentguard/agentguard/internal/api"
+
+// errBudgetExceeded is returned when evaluation does not finish in time.
+var errBudgetExceeded = errors.New("decision latency budget exceeded")
+
+// decisionMetrics records pre-invoke evaluation latency and budget misses.
+type decisionMetrics struct {
+	duration metric.Float64Histogram
+	exceeded metric.Int64Counter
+}
+
+func newDecisionMetrics() *decisionMetrics {
+	meter := otel.Meter(instrumentationName)
+	m := &decisionMetrics{}
+	var err error
+	m.duration, err = meter.Float64Histogram(
+		"agentguard_decision_duration_seconds",
+		metric.WithDescription("Pre-invoke policy evaluation latency"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create decision duration metric")
+	}
+	m.exceeded, err = meter.Int64Counter(
+		"agentguard_decision_budget_exceeded_total",
+		metric.WithDescription("Pre-invoke decisions that exceeded the latency budget, by fallback outcome"),
+	)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create decision budget metric")
+	}
+	return m
+}
+
+func (m *decisionMetrics) observe(ctx context.Context, elapsed time.Duration, profile string) {
+	if m.duration != nil {
+		m.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attribute.String("profile", profile)))
+	}
+}
+
+// budgetExceeded counts a budget miss; outcome is cached, fail_open, or
+// fail_closed.
+func (m *decisionMetrics) budgetExceeded(ctx context.Context, outcome, profile string) {
+	if m.exceeded != nil {
+		m.exceeded.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("outcome", outcome),
+			attribute.String("profile", profile),
+		))
+	}
+}
+
+// decisionBudget returns the deadline for this request: the configured
+// budget, lowered by the SDK's budget header when that is smaller. Zero
+// means no budget. shortened reports that the header lowered it; a miss of
+// a budget the caller chose must not fail open, or any fail-open agent
+// could skip policy evaluation by asking for a 1ms deadline.
+func decisionBudget(c *gin.Context, configured time.Duration) (budget time.Duration, shortened bool) {
+	ms, err := strconv.Atoi(c.GetHeader(budgetHeader))
+	if err != nil || ms <= 0 {
+		return configured, false
+	}
+	requested := time.Duration(ms) * time.Millisecond
+	if configured == 0 || requested < configured {
+		return requested, true
+	}
+	return configured, false
+}
+
+// evaluateWithinBudget evaluates input, giving up with errBudgetExceeded
+// once budget elapses. The evaluation's context is cancelled at the
+// deadline so abandoned evaluations stop promptly.
+func evaluateWithinBudget(ctx context.Context, engine *opa.Engine, input *opa.EvaluationInput, budget time.Duration) (*opa.Decision, error) {
+	if budget <= 0 {
+		return engine.Evaluate(ctx, "default", input)
+	}
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	type result struct {
+		d   *opa.Decision
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		d, err := engine.Evaluate(ctx, "default", input)
+		done <- result{d, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errBudgetExceeded
+		}
+		return r.d, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errBudgetExceeded
+		}
+		return nil, ctx.Err()
+	}
+}
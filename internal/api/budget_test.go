@@ -0,0 +1,82 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/profiles"
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+// slowPolicy allows support-bot at once and takes seconds to decide for
+// any other agent.
+const slowPolicy = `package agentguard
+
+allow { input.agent.id == "support-bot" }
+allow { input.agent.id != "support-bot"; count([1 | numbers.range(1, 3000)[_]; numbers.range(1, 3000)[_]]) < 0 }
+`
+
+func TestDecisionBudgetHeaderNeverFailsOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.rego")
+	if err := os.WriteFile(path, []byte(slowPolicy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := opa.NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.LoadPolicies(context.Background(), []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		PolicyEngine:   engine,
+		DecisionBudget: 50 * time.Millisecond,
+		Failure:        &profiles.FailurePolicy{Default: profiles.FailOpen},
+		APIKeys:        mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+	type decision struct {
+		Allow    bool
+		Degraded bool
+		Metadata map[string]any
+	}
+	preInvoke := func(input opa.EvaluationInput, budgetMs string) (int, decision) {
+		t.Helper()
+		var header []string
+		if budgetMs != "" {
+			header = []string{"X-AgentGuard-Budget-Ms", budgetMs}
+		}
+		w := do(srv, http.MethodPost, "/api/v1/sdk/pre-invoke", "ci-token", input, header...)
+		return w.Code, decode[decision](t, w)
+	}
+	slow := opa.EvaluationInput{Agent: opa.AgentContext{ID: "batch-bot"}, Tool: &opa.ToolContext{Name: "web_search"}}
+
+	// The server's own budget running out fails open for a fail-open agent.
+	code, d := preInvoke(slow, "")
+	if code != http.StatusOK || !d.Allow || d.Metadata["degraded_source"] != "fail_open" {
+		t.Errorf("server budget miss = %d %+v, want a fail-open allow", code, d)
+	}
+	// A budget the SDK shortened itself denies instead.
+	code, d = preInvoke(slow, "1")
+	if code != http.StatusForbidden || d.Allow || d.Metadata["degraded_source"] != "fail_closed" {
+		t.Errorf("shortened budget miss = %d %+v, want a fail-closed deny", code, d)
+	}
+
+	// The same holds for the delegating callers' evaluations.
+	delegated := opa.EvaluationInput{
+		Agent:      opa.AgentContext{ID: "support-bot"},
+		Tool:       &opa.ToolContext{Name: "web_search"},
+		Delegation: &opa.DelegationContext{Callers: []opa.AgentContext{{ID: "planner"}}},
+	}
+	if code, d := preInvoke(delegated, ""); code != http.StatusOK || !d.Allow {
+		t.Errorf("caller missing the server budget = %d %+v, want the call allowed", code, d)
+	}
+	if code, d := preInvoke(delegated, "40"); code != http.StatusOK || d.Allow {
+		t.Errorf("caller missing a shortened budget = %d %+v, want the call denied", code, d)
+	}
+}
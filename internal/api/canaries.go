@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/canary"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func makeListCanariesHandler(reg *canary.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list := reg.List(c.GetString(orgKey))
+		c.JSON(http.StatusOK, gin.H{"canaries": list, "total": len(list)})
+	}
+}
+
+func makeGetCanaryHandler(reg *canary.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cn, err := reg.Get(c.Param("id"))
+		if errors.Is(err, canary.ErrNotFound) || err == nil && cn.OrgID != c.GetString(orgKey) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "canary not found"})
+			return
+		}
+		c.JSON(http.StatusOK, cn)
+	}
+}
+
+// makeIssueCanaryHandler issues a canary for a knowledge base document.
+// When the request carries the document's content, the response includes
+// it marked with the token, ready to be written back to the vector store.
+func makeIssueCanaryHandler(reg *canary.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			VectorStore string `json:"vector_store" binding:"required"`
+			DocumentID  string `json:"document_id" binding:"required"`
+			Content     string `json:"content"`
+			Note        string `json:"note"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		cn, err := reg.Issue(c.GetString(orgKey), req.VectorStore, req.DocumentID, req.Note)
+		if err != nil {
+			log.Error().Err(err).Str("vector_store", req.VectorStore).Msg("failed to issue canary")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue canary"})
+			return
+		}
+		resp := gin.H{"canary": cn}
+		if req.Content != "" {
+			resp["marked_content"] = canary.Mark(req.Content, cn.Token)
+		}
+		c.JSON(http.StatusCreated, resp)
+	}
+}
+
+func makeDeleteCanaryHandler(reg *canary.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cn, err := reg.Get(c.Param("id"))
+		if errors.Is(err, canary.ErrNotFound) || err == nil && cn.OrgID != c.GetString(orgKey) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "canary not found"})
+			return
+		}
+		if err := reg.Delete(cn.ID); err != nil {
+			log.Error().Err(err).Str("canary_id", cn.ID).Msg("failed to delete canary")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete canary"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// recordCanaryLeaks raises a critical exfiltration signal for each canary
+// token in a reported tool output and returns the signal IDs.
+func recordCanaryLeaks(c *gin.Context, deps *RouterDeps, req *postInvokeRequest) []string {
+	orgID := c.GetString(orgKey)
+	leaked := deps.Canaries.Find(orgID, string(req.Output))
+	ids := make([]string, 0, len(leaked))
+	for _, cn := range leaked {
+		sig := models.SecuritySignal{
+			ID:          uuid.NewString(),
+			Type:        models.SignalDataExfiltration,
+			Severity:    "critical",
+			Title:       "Knowledge base canary leaked",
+			Description: "canary from document '" + cn.DocumentID + "' in '" + cn.VectorStore + "' appeared in the output of " + req.Tool.Name,
+			Evidence: map[string]any{
+				"canary_id":    cn.ID,
+				"vector_store": cn.VectorStore,
+				"document_id":  cn.DocumentID,
+				"field":        "output",
+				"tool_name":    req.Tool.Name,
+				"session_id":   req.SessionID,
+			},
+			Timestamp: time.Now().UTC(),
+		}
+		log.Warn().Str("org_id", orgID).Str("agent_id", req.AgentID).Str("canary_id", cn.ID).
+			Str("vector_store", cn.VectorStore).Msg("knowledge base canary leaked")
+		dispatchSignal(c, deps, orgID, req.AgentID, sig)
+		ids = append(ids, sig.ID)
+	}
+	return ids
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/oscal"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportOSCALCatalog serves POST /controls/import/oscal: an OSCAL catalog,
+// or a profile such as an 800-53B baseline, becomes the framework named by
+// the id query parameter. Profiles are resolved against the catalogs in the
+// data directory's catalogs/. With ?async=true and a job manager, the
+// catalog is stored by a job.
+func (h *Handlers) ImportOSCALCatalog(c *gin.Context) {
+	id := c.Query("id")
+	if !validFrameworkID.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	cat, err := oscal.Parse(c.Request.Body, h.CatalogDir)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid oscal document", "details": err.Error()})
+		return
+	}
+	catalog := repository.CatalogImport{Framework: *oscal.ToFramework(cat, id), Controls: oscal.ToControls(cat, id)}
+	if len(catalog.Controls) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid catalog", "details": "the document selects no controls"})
+		return
+	}
+	if err := prepareCatalogImport(&catalog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid catalog", "details": err.Error()})
+		return
+	}
+
+	store := func(ctx context.Context) (any, error) {
+		if err := h.ControlRepo.ImportCatalog(ctx, &catalog); err != nil {
+			return nil, err
+		}
+		return gin.H{
+			"framework":  catalog.Framework,
+			"controls":   len(catalog.Controls),
+			"crosswalks": 0,
+		}, nil
+	}
+
+	if h.Jobs != nil && wantsAsync(c) {
+		job, err := submitJob(c, h.Jobs, "catalog_import", "write:controls", store)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue catalog import", "details": err.Error()})
+			return
+		}
+		acceptJob(c, job)
+		return
+	}
+
+	result, err := store(c.Request.Context())
+	if err != nil {
+		respondRepoError(c, err, "failed to import catalog")
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
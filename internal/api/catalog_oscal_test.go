@@ -0,0 +1,83 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestImportOSCALProfile(t *testing.T) {
+	catalog, err := os.ReadFile("../oscal/testdata/catalog.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := os.ReadFile("../oscal/testdata/profile.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testConfig()
+	cfg.Controls.DataDir = t.TempDir()
+	if err := os.Mkdir(filepath.Join(cfg.Controls.DataDir, "catalogs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Controls.DataDir, "catalogs", "catalog.json"), catalog, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := jobs.NewManager(jobs.Config{Workers: 1})
+	t.Cleanup(m.Stop)
+	srv := newServer(t, cfg, &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		Jobs:        m,
+		APIKeys:     mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+	post := func(path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer ci-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("/api/v1/controls/import/oscal?id=example", catalog); w.Code != http.StatusCreated {
+		t.Errorf("catalog import = %d %s, want 201", w.Code, w.Body)
+	}
+
+	w := post("/api/v1/controls/import/oscal?id=baseline&async=true", profile)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("async profile import = %d %s, want 202", w.Code, w.Body)
+	}
+	job := decode[jobs.Job](t, w)
+	if job.Type != "catalog_import" || job.Scope != "write:controls" {
+		t.Errorf("job = %+v, want a catalog_import needing write:controls", job)
+	}
+	if w := awaitJob(t, srv, "ci-token", job.ID); w.Code != http.StatusOK {
+		t.Fatalf("result = %d %s, want 200", w.Code, w.Body)
+	}
+	controls := decode[struct{ Total int }](t, do(srv, http.MethodGet, "/api/v1/controls/frameworks/baseline/controls", "ci-token", nil))
+	if controls.Total != 3 {
+		t.Errorf("baseline controls = %d, want the profile's 3", controls.Total)
+	}
+
+	escape := bytes.Replace(profile, []byte(`"catalog.json"`), []byte(`"../catalog.json"`), 1)
+	for _, tc := range []struct {
+		name, query string
+		body        []byte
+	}{
+		{"without an id", "", profile},
+		{"with a bad id", "id=Bad%20ID", profile},
+		{"of a non-OSCAL document", "id=other", []byte(`{"framework": {}}`)},
+		{"importing outside the catalogs", "id=other", escape},
+	} {
+		if w := post("/api/v1/controls/import/oscal?async=true&"+tc.query, tc.body); w.Code != http.StatusBadRequest {
+			t.Errorf("import %s = %d, want 400", tc.name, w.Code)
+		}
+	}
+}
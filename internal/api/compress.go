@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// incompressibleTypes lists content type prefixes that are already compressed.
+var incompressibleTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/octet-stream",
+	"application/pdf",
+	"application/vnd.openxmlformats-officedocument",
+}
+
+// compressionMiddleware compresses responses with gzip or deflate when the
+// client accepts it and the body reaches minSize bytes. Responses that are
+// already encoded or carry an incompressible content type pass through.
+func compressionMiddleware(cfg config.CompressionConfig) gin.HandlerFunc {
+	level := cfg.Level
+	if level < gzip.BestSpeed || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", appendVary(c.Writer.Header().Get("Vary"), "Accept-Encoding"))
+
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			level:          level,
+			minSize:        cfg.MinSize,
+			status:         http.StatusOK,
+		}
+		c.Writer = cw
+		defer cw.finish()
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// honoring q-values and preferring gzip on ties.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		if name == "*" {
+			name = "gzip"
+		}
+		if name != "gzip" && name != "deflate" {
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+func appendVary(existing, value string) string {
+	if existing == "" {
+		return value
+	}
+	for _, v := range strings.Split(existing, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return existing
+		}
+	}
+	return existing + ", " + value
+}
+
+// compressWriter buffers the response until it can decide whether compression
+// is worthwhile, then either streams through a compressor or writes as-is.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	level    int
+	minSize  int
+
+	status     int
+	buf        bytes.Buffer
+	compressor io.WriteCloser
+	decided    bool
+	passthru   bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if !w.decided {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Status() int {
+	if !w.decided {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *compressWriter) Written() bool {
+	return w.decided || w.buf.Len() > 0
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		if w.passthru {
+			return w.ResponseWriter.Write(p)
+		}
+		return w.compressor.Write(p)
+	}
+
+	if !w.compressible() {
+		w.decide(false)
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf.Write(p)
+	if w.buf.Len() >= w.minSize {
+		if err := w.flushBuffer(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush forces a decision so streaming handlers are not held in the buffer.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.flushBuffer(w.compressible() && w.buf.Len() >= w.minSize)
+	}
+	if f, ok := w.compressor.(interface{ Flush() error }); ok && !w.passthru {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible reports whether the response status and headers allow compression.
+func (w *compressWriter) compressible() bool {
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	ct := strings.ToLower(h.Get("Content-Type"))
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(ct, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// decide commits the response headers for compressed or plain output.
+func (w *compressWriter) decide(compress bool) {
+	w.decided = true
+	w.passthru = !compress
+	if compress {
+		h := w.Header()
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		weakenETag(h)
+		if w.encoding == "gzip" {
+			w.compressor, _ = gzip.NewWriterLevel(w.ResponseWriter, w.level)
+		} else {
+			// HTTP's deflate coding is the zlib format (RFC 9110
+			// §8.4.1.2), not a raw DEFLATE stream.
+			w.compressor, _ = zlib.NewWriterLevel(w.ResponseWriter, w.level)
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *compressWriter) flushBuffer(compress bool) error {
+	w.decide(compress)
+	data := w.buf.Bytes()
+	w.buf.Reset()
+	if len(data) == 0 {
+		return nil
+	}
+	if compress {
+		_, err := w.compressor.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// weakenETag marks a strong ETag weak. The ETag conditionalGetMiddleware
+// computes is of the identity body; an encoded body differs byte for byte,
+// so it may only claim to be semantically equivalent (RFC 9110 §8.8.1).
+// If-None-Match uses weak comparison, so either form still revalidates.
+func weakenETag(h http.Header) {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+}
+
+// finish writes any buffered body and closes the compressor.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if w.buf.Len() == 0 {
+			// No body written; let gin emit the status as usual. A 304
+			// stands in for a body that would have been encoded, so it
+			// carries the same validator.
+			if w.status == http.StatusNotModified {
+				weakenETag(w.Header())
+			}
+			w.decided, w.passthru = true, true
+			w.ResponseWriter.WriteHeader(w.status)
+			return
+		}
+		_ = w.flushBuffer(false)
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+	}
+}
@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestCompression(t *testing.T) {
+	cfg := testConfig()
+	cfg.Server.Compression.Enabled = true
+	cfg.Server.Compression.MinSize = 1
+	srv := newServer(t, cfg, &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		APIKeys:     mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+	const path = "/api/v1/controls/frameworks"
+
+	plain := do(srv, http.MethodGet, path, "ci-token", nil)
+	if plain.Code != http.StatusOK || plain.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("GET without Accept-Encoding = %d, %q", plain.Code, plain.Header().Get("Content-Encoding"))
+	}
+
+	etag := plain.Header().Get("ETag")
+	if etag == "" || etag[0] != '"' {
+		t.Fatalf("identity ETag = %q, want a strong ETag", etag)
+	}
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip":    func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"deflate": func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	}
+	for encoding, newReader := range decoders {
+		t.Run(encoding, func(t *testing.T) {
+			w := do(srv, http.MethodGet, path, "ci-token", nil, "Accept-Encoding", encoding)
+			if got := w.Header().Get("Content-Encoding"); got != encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, encoding)
+			}
+			// The encoded body is not byte-identical to the one the ETag
+			// was computed over, so the ETag is weak.
+			if got := w.Header().Get("ETag"); got != "W/"+etag {
+				t.Errorf("ETag = %q, want W/%s", got, etag)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			nm := do(srv, http.MethodGet, path, "ci-token", nil, "Accept-Encoding", encoding, "If-None-Match", w.Header().Get("ETag"))
+			if nm.Code != http.StatusNotModified || nm.Header().Get("ETag") != "W/"+etag {
+				t.Errorf("revalidation = %d with ETag %q, want 304 with W/%s", nm.Code, nm.Header().Get("ETag"), etag)
+			}
+
+			r, err := newReader(w.Body)
+			if err != nil {
+				t.Fatalf("reading %s body: %v", encoding, err)
+			}
+			body, err := io.ReadAll(r)
+			if err != nil || string(body) != plain.Body.String() {
+				t.Errorf("decoded body = %s, %v; want %s", body, err, plain.Body)
+			}
+		})
+	}
+}
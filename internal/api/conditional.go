@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// conditionalGetMiddleware adds an ETag header to successful GET responses
+// and answers If-None-Match with 304. The ETag is derived from the response
+// body, so it changes exactly when the representation does, whichever
+// replica or process changed the catalog. There is no Last-Modified: no
+// single clock sees every catalog write.
+func conditionalGetMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		ew := &etagWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = ew
+		c.Next()
+		c.Writer = ew.ResponseWriter
+
+		if ew.status != http.StatusOK {
+			ew.ResponseWriter.WriteHeader(ew.status)
+			_, _ = ew.ResponseWriter.Write(ew.buf.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(ew.buf.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		h := c.Writer.Header()
+		h.Set("ETag", etag)
+		h.Set("Cache-Control", "no-cache")
+
+		if notModified(c.Request, etag) {
+			h.Del("Content-Type")
+			h.Del("Content-Length")
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+
+		c.Writer.WriteHeader(http.StatusOK)
+		_, _ = c.Writer.Write(ew.buf.Bytes())
+	}
+}
+
+// notModified reports whether If-None-Match names etag, using weak
+// comparison (RFC 9110 §13.1.2).
+func notModified(r *http.Request, etag string) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// etagWriter buffers a response so its ETag can be computed before headers are sent.
+type etagWriter struct {
+	gin.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *etagWriter) WriteHeader(code int) { w.status = code }
+
+func (w *etagWriter) WriteHeaderNow() {}
+
+func (w *etagWriter) Status() int { return w.status }
+
+func (w *etagWriter) Written() bool { return w.buf.Len() > 0 }
+
+func (w *etagWriter) Size() int { return w.buf.Len() }
+
+func (w *etagWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *etagWriter) WriteString(s string) (int, error) { return w.buf.WriteString(s) }
@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestConditionalGet(t *testing.T) {
+	// Two replicas share a catalog; a write through one must invalidate
+	// what clients cached from the other.
+	repo := memory.NewControlRepository()
+	keys := mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"})
+	first := newServer(t, testConfig(), &api.RouterDeps{ControlRepo: repo, APIKeys: keys})
+	second := newServer(t, testConfig(), &api.RouterDeps{ControlRepo: repo, APIKeys: keys})
+	const path = "/api/v1/controls/frameworks"
+
+	w := do(first, http.MethodGet, path, "ci-token", nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GET = %d with ETag %q, want 200 with an ETag", w.Code, etag)
+	}
+	if lm := w.Header().Get("Last-Modified"); lm != "" {
+		t.Errorf("Last-Modified = %q, want none", lm)
+	}
+	if w := do(first, http.MethodGet, path, "ci-token", nil, "If-None-Match", etag); w.Code != http.StatusNotModified {
+		t.Errorf("GET with a matching If-None-Match = %d, want 304", w.Code)
+	}
+
+	if w := do(second, http.MethodPost, path, "ci-token", map[string]any{"id": "acme-ai", "name": "Acme AI", "version": "1.0"}); w.Code != http.StatusCreated {
+		t.Fatalf("creating a framework = %d %s", w.Code, w.Body)
+	}
+	w = do(first, http.MethodGet, path, "ci-token", nil, "If-None-Match", etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("GET after another replica's write = %d with ETag %q, want 200 with a new ETag", w.Code, w.Header().Get("ETag"))
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if w := do(first, http.MethodGet, path, "ci-token", nil, "If-Modified-Since", future); w.Code != http.StatusOK {
+		t.Errorf("GET with If-Modified-Since = %d, want 200", w.Code)
+	}
+}
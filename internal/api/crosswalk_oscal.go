@@ -0,0 +1,226 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportCrosswalks serves GET /controls/crosswalks/export: every mapping in
+// effect, built-in and curated, as one OSCAL mapping collection with a
+// mapping per framework pair. The source and target query parameters
+// limit the export to the pairs with that source or target framework.
+func (h *Handlers) ExportCrosswalks(c *gin.Context) {
+	ctx := c.Request.Context()
+	source, target := c.Query("source"), c.Query("target")
+	frameworks, err := h.mappingFrameworks(ctx)
+	if err != nil {
+		respondRepoError(c, err, "failed to list frameworks")
+		return
+	}
+	for _, id := range []string{source, target} {
+		if _, ok := frameworks[id]; id != "" && !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown framework", "details": id})
+			return
+		}
+	}
+
+	builtin := make(map[[2]string][]models.Crosswalk)
+	if h.GapAnalyzer != nil {
+		direct, err := h.GapAnalyzer.DirectCrosswalks()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list built-in crosswalks"})
+			return
+		}
+		for _, xw := range direct {
+			pair := [2]string{xw.SourceFrameworkID, xw.TargetFrameworkID}
+			builtin[pair] = append(builtin[pair], xw)
+		}
+	}
+	var all []models.Crosswalk
+	for s := range frameworks {
+		for t := range frameworks {
+			if s == t || (source != "" && s != source) || (target != "" && t != target) {
+				continue
+			}
+			stored, err := h.ControlRepo.GetCrosswalk(ctx, s, t)
+			if err != nil {
+				respondRepoError(c, err, "failed to get crosswalk")
+				return
+			}
+			all = append(all, controls.OverrideCrosswalks(builtin[[2]string{s, t}], stored)...)
+		}
+	}
+
+	doc, err := oscal.NewCrosswalkCollection("AgentGuard crosswalks", frameworks, all)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export crosswalks", "details": err.Error()})
+		return
+	}
+	writeReport(c, "crosswalks", controls.ReportOSCAL, func(buf *bytes.Buffer) error {
+		return oscal.WriteJSON(buf, doc)
+	})
+}
+
+// ImportCrosswalks serves POST /controls/crosswalks/import: the mappings of
+// an OSCAL mapping collection, such as one published by CSA or CIS, become
+// curated crosswalks. Query parameters:
+//   - source, target: the frameworks of mapping resources that are not
+//     AgentGuard frameworks.
+//   - conflicts: skip (default) keeps curated mappings of the same control
+//     pair, replace overwrites them, fail rejects the import with 409.
+//   - approved_by: approves the imported mappings in that reviewer's name;
+//     otherwise they are proposed for review.
+//   - dry_run=true: returns the plan without storing anything.
+//   - async=true: stores the mappings as a job when a job manager is
+//     configured; the job's result is the response.
+func (h *Handlers) ImportCrosswalks(c *gin.Context) {
+	ctx := c.Request.Context()
+	mc, err := oscal.ParseMappingCollection(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping collection", "details": err.Error()})
+		return
+	}
+	frameworks, err := h.mappingFrameworks(ctx)
+	if err != nil {
+		respondRepoError(c, err, "failed to list frameworks")
+		return
+	}
+	catalogs := make([]controls.MappingCatalog, 0, len(frameworks))
+	for _, fw := range frameworks {
+		catalogs = append(catalogs, controls.MappingCatalog{Framework: fw, Controls: h.frameworkControls(ctx, fw.ID)})
+	}
+	incoming, issues, err := controls.CrosswalksFromOSCAL(mc, catalogs, c.Query("source"), c.Query("target"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping collection", "details": err.Error()})
+		return
+	}
+
+	var existing []models.Crosswalk
+	seen := make(map[[2]string]bool)
+	for _, xw := range incoming {
+		pair := [2]string{xw.SourceFrameworkID, xw.TargetFrameworkID}
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		stored, err := h.ControlRepo.GetCrosswalk(ctx, pair[0], pair[1])
+		if err != nil {
+			respondRepoError(c, err, "failed to get crosswalk")
+			return
+		}
+		existing = append(existing, stored...)
+	}
+	plan, err := controls.PlanCrosswalkImport(existing, incoming, c.Query("conflicts"))
+	if errors.Is(err, controls.ErrCrosswalkConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "crosswalk conflict", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conflicts option", "details": err.Error()})
+		return
+	}
+	if issues == nil {
+		issues = []controls.MappingIssue{}
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	var repo repository.CrosswalkReviewRepository
+	if !dryRun && len(plan.Replace) > 0 {
+		var ok bool
+		if repo, ok = h.crosswalkReview(c); !ok {
+			return
+		}
+	}
+	review := reviewFields(c.Query("approved_by"))
+	store := func(ctx context.Context) (any, error) {
+		if !dryRun {
+			for i := range plan.Create {
+				xw := &plan.Create[i]
+				xw.ID = uuid.NewString()
+				review(xw)
+				if err := h.ControlRepo.CreateCrosswalk(ctx, xw); err != nil {
+					return nil, err
+				}
+			}
+			for i := range plan.Replace {
+				xw := &plan.Replace[i]
+				review(xw)
+				if err := repo.UpdateCrosswalk(ctx, xw); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return gin.H{
+			"dry_run":   dryRun,
+			"created":   plan.Create,
+			"replaced":  plan.Replace,
+			"skipped":   plan.Skipped,
+			"unchanged": plan.Unchanged,
+			"issues":    issues,
+		}, nil
+	}
+
+	if !dryRun && h.Jobs != nil && wantsAsync(c) {
+		job, err := submitJob(c, h.Jobs, "crosswalk_import", "write:controls", store)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue crosswalk import", "details": err.Error()})
+			return
+		}
+		acceptJob(c, job)
+		return
+	}
+
+	result, err := store(ctx)
+	if err != nil {
+		respondRepoError(c, err, "failed to import crosswalks")
+		return
+	}
+	status := http.StatusOK
+	if !dryRun && len(plan.Create) > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, result)
+}
+
+// reviewFields returns a function setting an imported crosswalk's review
+// state: approved by approver, or proposed when approver is empty.
+func reviewFields(approver string) func(*models.Crosswalk) {
+	now := time.Now().UTC()
+	return func(xw *models.Crosswalk) {
+		xw.ReviewState = models.ReviewProposed
+		xw.ReviewedBy, xw.ReviewedAt, xw.ApprovedBy, xw.ApprovedAt = "", nil, "", nil
+		if approver != "" {
+			xw.ReviewState = models.ReviewApproved
+			xw.ReviewedBy, xw.ReviewedAt, xw.ApprovedBy, xw.ApprovedAt = approver, &now, approver, &now
+		}
+	}
+}
+
+// mappingFrameworks returns the frameworks crosswalks may reference by ID:
+// the stored ones and the analyzer's.
+func (h *Handlers) mappingFrameworks(ctx context.Context) (map[string]*models.Framework, error) {
+	stored, err := h.ControlRepo.ListFrameworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	frameworks := make(map[string]*models.Framework, len(stored))
+	if h.GapAnalyzer != nil {
+		for _, fw := range h.GapAnalyzer.Frameworks() {
+			frameworks[fw.ID] = fw
+		}
+	}
+	for i := range stored {
+		frameworks[stored[i].ID] = &stored[i]
+	}
+	return frameworks, nil
+}
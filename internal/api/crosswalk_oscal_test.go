@@ -0,0 +1,73 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestImportCrosswalksAsync(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := jobs.NewManager(jobs.Config{Workers: 1})
+	t.Cleanup(m.Stop)
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		GapAnalyzer: analyzer,
+		Jobs:        m,
+		APIKeys:     mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+	export := do(srv, http.MethodGet, "/api/v1/controls/crosswalks/export?source=nist-ai-rmf&target=iso-42001", "ci-token", nil)
+	if export.Code != http.StatusOK {
+		t.Fatalf("export = %d %s", export.Code, export.Body)
+	}
+	post := func(path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer ci-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+	type plan struct {
+		DryRun  bool             `json:"dry_run"`
+		Created []map[string]any `json:"created"`
+	}
+
+	dry := post("/api/v1/controls/crosswalks/import?dry_run=true&async=true", export.Body.Bytes())
+	if dry.Code != http.StatusOK {
+		t.Fatalf("dry run = %d %s, want 200 without queueing a job", dry.Code, dry.Body)
+	}
+	want := len(decode[plan](t, dry).Created)
+	if want == 0 {
+		t.Fatal("dry run plans no mappings")
+	}
+
+	w := post("/api/v1/controls/crosswalks/import?async=true", export.Body.Bytes())
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("async import = %d %s, want 202", w.Code, w.Body)
+	}
+	job := decode[jobs.Job](t, w)
+	if job.Type != "crosswalk_import" || job.Scope != "write:controls" {
+		t.Errorf("job = %+v, want a crosswalk_import needing write:controls", job)
+	}
+	result := awaitJob(t, srv, "ci-token", job.ID)
+	if result.Code != http.StatusOK {
+		t.Fatalf("result = %d %s, want 200", result.Code, result.Body)
+	}
+	if got := decode[plan](t, result); got.DryRun || len(got.Created) != want {
+		t.Errorf("imported %d mappings (dry run %v), want %d", len(got.Created), got.DryRun, want)
+	}
+
+	if w := post("/api/v1/controls/crosswalks/import?async=true", []byte(`{}`)); w.Code != http.StatusBadRequest {
+		t.Errorf("import of an empty document = %d, want 400", w.Code)
+	}
+}
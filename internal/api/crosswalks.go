@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// validReviewState reports whether s is a crosswalk review state.
+func validReviewState(s models.ReviewState) bool {
+	return s == models.ReviewProposed || s == models.ReviewReviewed || s == models.ReviewApproved
+}
+
+// validCrosswalk checks the mapping fields of a curated crosswalk.
+func validCrosswalk(xw *models.Crosswalk) string {
+	switch {
+	case !validFrameworkID.MatchString(xw.SourceFrameworkID) || !validFrameworkID.MatchString(xw.TargetFrameworkID):
+		return "invalid framework ID format"
+	case xw.SourceControlID == "" || xw.TargetControlID == "":
+		return "source_control_id and target_control_id are required"
+	case xw.MappingType != models.MappingExact && xw.MappingType != models.MappingPartial &&
+		xw.MappingType != models.MappingSuperset && xw.MappingType != models.MappingSubset &&
+		xw.MappingType != models.MappingRelated:
+		return "mapping_type must be exact, partial, superset, subset or related"
+	case xw.Confidence < 0 || xw.Confidence > 1:
+		return "confidence must be between 0 and 1"
+	}
+	return ""
+}
+
+// crosswalkReview returns the repository's crosswalk review support, or
+// responds 501 when it has none.
+func (h *Handlers) crosswalkReview(c *gin.Context) (repository.CrosswalkReviewRepository, bool) {
+	repo, ok := h.ControlRepo.(repository.CrosswalkReviewRepository)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "crosswalk review not supported by this repository"})
+	}
+	return repo, ok
+}
+
+// CreateCrosswalk adds an analyst-curated mapping. It starts proposed and
+// only takes effect once approved.
+func (h *Handlers) CreateCrosswalk(c *gin.Context) {
+	var xw models.Crosswalk
+	if err := c.ShouldBindJSON(&xw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if msg := validCrosswalk(&xw); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	xw.ID = uuid.NewString()
+	xw.Derived, xw.Via = false, nil
+	xw.ReviewState = models.ReviewProposed
+	xw.ReviewedBy, xw.ReviewedAt, xw.ApprovedBy, xw.ApprovedAt = "", nil, "", nil
+
+	if err := h.ControlRepo.CreateCrosswalk(c.Request.Context(), &xw); err != nil {
+		respondRepoError(c, err, "failed to create crosswalk")
+		return
+	}
+	c.JSON(http.StatusCreated, xw)
+}
+
+// UpdateCrosswalk replaces a curated mapping. A changed mapping needs
+// review again, so it returns to proposed.
+func (h *Handlers) UpdateCrosswalk(c *gin.Context) {
+	repo, ok := h.crosswalkReview(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if !validID.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crosswalk ID format"})
+		return
+	}
+
+	var update models.Crosswalk
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if msg := validCrosswalk(&update); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	xw, err := repo.GetCrosswalkByID(ctx, id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get crosswalk")
+		return
+	}
+	if xw == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crosswalk not found"})
+		return
+	}
+	xw.SourceFrameworkID, xw.SourceControlID = update.SourceFrameworkID, update.SourceControlID
+	xw.TargetFrameworkID, xw.TargetControlID = update.TargetFrameworkID, update.TargetControlID
+	xw.MappingType, xw.Confidence, xw.Rationale = update.MappingType, update.Confidence, update.Rationale
+	xw.Gaps, xw.Supplements, xw.EvidenceMapping = update.Gaps, update.Supplements, update.EvidenceMapping
+	xw.ReviewState = models.ReviewProposed
+	xw.ReviewedBy, xw.ReviewedAt, xw.ApprovedBy, xw.ApprovedAt = "", nil, "", nil
+
+	if err := repo.UpdateCrosswalk(ctx, xw); err != nil {
+		respondRepoError(c, err, "failed to update crosswalk")
+		return
+	}
+	c.JSON(http.StatusOK, xw)
+}
+
+// DeleteCrosswalk removes a mapping.
+func (h *Handlers) DeleteCrosswalk(c *gin.Context) {
+	id := c.Param("id")
+	if !validID.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crosswalk ID format"})
+		return
+	}
+	if err := h.ControlRepo.DeleteCrosswalk(c.Request.Context(), id); err != nil {
+		respondRepoError(c, err, "failed to delete crosswalk")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// crosswalkReviewRequest moves a crosswalk to a review state.
+type crosswalkReviewRequest struct {
+	State    models.ReviewState `json:"state" binding:"required"`
+	Reviewer string             `json:"reviewer" binding:"required"`
+}
+
+// ReviewCrosswalk records a review step on a curated mapping: proposed →
+// reviewed → approved, or back to proposed.
+func (h *Handlers) ReviewCrosswalk(c *gin.Context) {
+	repo, ok := h.crosswalkReview(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if !validID.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crosswalk ID format"})
+		return
+	}
+
+	var req crosswalkReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state and reviewer are required"})
+		return
+	}
+	if !validReviewState(req.State) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state must be proposed, reviewed or approved"})
+		return
+	}
+
+	xw, err := repo.GetCrosswalkByID(ctx, id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get crosswalk")
+		return
+	}
+	if xw == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crosswalk not found"})
+		return
+	}
+	if err := controls.ReviewCrosswalk(xw, req.State, req.Reviewer, time.Now().UTC()); err != nil {
+		if errors.Is(err, controls.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "invalid review transition", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := repo.UpdateCrosswalk(ctx, xw); err != nil {
+		respondRepoError(c, err, "failed to review crosswalk")
+		return
+	}
+	c.JSON(http.StatusOK, xw)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/delegation"
+	"github.com/agentguard/agentguard/internal/multiagent"
+	"github.com/agentguard/agentguard/internal/profiles"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// enforceDelegation applies the restrictions of the agents that delegated
+// a call: each caller must be uncontained and allowed by policy to make the
+// call itself, otherwise the delegate is denied too. Callers whose
+// evaluation fails count as denied unless the route fails open.
+func enforceDelegation(c *gin.Context, deps *RouterDeps, input *opa.EvaluationInput, decision *opa.Decision, failMode profiles.FailMode) {
+	if deps == nil {
+		return
+	}
+	tool := ""
+	if input.Tool != nil {
+		tool = input.Tool.Name
+	}
+	ctx := c.Request.Context()
+	for _, caller := range input.Delegation.Callers {
+		if deps.Response != nil {
+			if ok, reason := deps.Response.Containment().Check(caller.ID, tool); !ok {
+				decision.MergeDelegated(caller.ID, &opa.Decision{Reasons: []string{reason}})
+				continue
+			}
+		}
+		if deps.PolicyEngine == nil {
+			continue
+		}
+		budget, shortened := decisionBudget(c, deps.DecisionBudget)
+		cd, err := evaluateWithinBudget(ctx, deps.PolicyEngine, input.AsCaller(caller), budget)
+		if err != nil {
+			log.Warn().Err(err).Str("agent_id", input.Agent.ID).Str("caller", caller.ID).Msg("delegating caller evaluation failed")
+			if failMode != profiles.FailOpen || (shortened && errors.Is(err, errBudgetExceeded)) {
+				decision.MergeDelegated(caller.ID, &opa.Decision{Reasons: []string{"policy evaluation failed — denying by default"}})
+			}
+			continue
+		}
+		decision.MergeDelegated(caller.ID, cd)
+	}
+}
+
+// enforceSpawn checks a spawn_agent call against the declared agent
+// groups: the child must be registered, delegated to by the calling agent,
+// and hold no tool the caller lacks. Allowed spawns return the child's
+// tools and policies in the decision metadata for the SDK to start it with.
+func enforceSpawn(policy *multiagent.SpawnPolicy, input *opa.EvaluationInput, decision *opa.Decision) {
+	req := multiagent.SpawnRequest{Parent: input.Agent.ID}
+	req.Child, _ = input.Tool.Parameters["agent_id"].(string)
+	if tools, ok := input.Tool.Parameters["tools"].([]any); ok {
+		for _, t := range tools {
+			if name, ok := t.(string); ok {
+				req.Tools = append(req.Tools, name)
+			}
+		}
+	}
+	if decision.Metadata == nil {
+		decision.Metadata = make(map[string]any)
+	}
+	if req.Child == "" {
+		decision.Allow = false
+		decision.Reasons = append(decision.Reasons, "spawn_agent call names no child agent")
+		return
+	}
+
+	grant, violations := policy.Check(req)
+	if grant != nil {
+		decision.Metadata["spawn"] = grant
+		return
+	}
+	decision.Allow = false
+	for _, v := range violations {
+		decision.Reasons = append(decision.Reasons, v.Description)
+		decision.Violations = append(decision.Violations, opa.Violation{
+			Policy:      multiagent.SpawnTool,
+			Rule:        v.Rule,
+			Description: v.Description,
+			Severity:    "high",
+		})
+	}
+	log.Warn().Str("agent_id", req.Parent).Str("child", req.Child).Int("violations", len(violations)).Msg("sub-agent spawn denied")
+}
+
+// makeTraceDelegationHandler serves GET /observe/traces/:id/delegation: the
+// delegation forest around a trace, the chain of agents that led to it and
+// the hops where a delegate exercised privileges its callers did not.
+func makeTraceDelegationHandler(repo repository.DelegationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		f, err := delegation.Reconstruct(c.Request.Context(), repo, c.GetString(orgKey), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "trace not found"})
+				return
+			}
+			log.Error().Err(err).Str("trace_id", id).Msg("failed to reconstruct delegation chain")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load delegation chain"})
+			return
+		}
+		c.JSON(http.StatusOK, f)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/detect"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// makeListDetectionRulesHandler returns the live detection rules with the
+// ruleset revision they belong to.
+func makeListDetectionRulesHandler(e *detect.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rules := e.Rules()
+		c.JSON(http.StatusOK, gin.H{"ruleset": e.Status(), "rules": rules, "total": len(rules)})
+	}
+}
+
+func makeGetDetectionRuleHandler(e *detect.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := e.Rule(c.Param("id"))
+		if errors.Is(err, detect.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "detection rule not found"})
+			return
+		}
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// makeSetDetectionRuleHandler enables or disables a rule for every
+// organization. The change lasts until the rule file changes the rule's
+// version.
+func makeSetDetectionRuleHandler(e *detect.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Enabled *bool `json:"enabled" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		r, err := e.SetEnabled(c.Param("id"), *req.Enabled)
+		if errors.Is(err, detect.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "detection rule not found"})
+			return
+		}
+		log.Info().Str("rule_id", r.ID).Int("rule_version", r.Version).Bool("active", r.Active).Str("organization_id", c.GetString(orgKey)).Msg("detection rule state changed")
+		c.JSON(http.StatusOK, r)
+	}
+}
+
+// makeReloadDetectionRulesHandler reloads the rule files now rather than at
+// the next poll. Invalid files are rejected with every problem found and
+// the previous ruleset stays live.
+func makeReloadDetectionRulesHandler(e *detect.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := e.Reload(); err != nil {
+			var verr *detect.ValidationError
+			if errors.As(err, &verr) {
+				details := make([]string, len(verr.Errors))
+				for i, fe := range verr.Errors {
+					details[i] = fe.Error()
+				}
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid detection rules", "details": details})
+				return
+			}
+			log.Error().Err(err).Str("dir", e.Dir()).Msg("detection rule reload failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reload detection rules"})
+			return
+		}
+		c.JSON(http.StatusOK, e.Status())
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/gin-gonic/gin"
+)
+
+// devWarning is sent in the Warning header of every response in
+// development mode.
+const devWarning = "development mode: authentication is disabled and data is not persisted"
+
+//go:embed ui
+var uiFiles embed.FS
+
+// devModeMiddleware marks every response as coming from a development
+// server so it is not mistaken for a real deployment.
+func devModeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("X-AgentGuard-Mode", "development")
+		c.Header("Warning", `199 agentguard "`+devWarning+`"`)
+		c.Next()
+	}
+}
+
+// devAuthMiddleware admits every request with all scopes. It replaces the
+// bearer token check in development mode only.
+func devAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(scopeKey, apikey.Scopes)
+		c.Next()
+	}
+}
+
+// mountConsole serves the embedded development console under /ui and
+// redirects the root to it.
+func mountConsole(r *gin.Engine) {
+	sub, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err) // the directory is embedded at build time
+	}
+	r.StaticFS("/ui", http.FS(sub))
+	r.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, "/ui/")
+	})
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GapDispositionRequest records or changes a decision on a control gap.
+// FrameworkID and ControlID are fixed once created.
+type GapDispositionRequest struct {
+	FrameworkID   string             `json:"framework_id"`
+	ControlID     string             `json:"control_id"`
+	Disposition   models.Disposition `json:"disposition"`
+	Approver      string             `json:"approver"`
+	Justification string             `json:"justification"`
+	TransferredTo string             `json:"transferred_to"`
+	// ExpiresAt is an RFC 3339 time, or a date meaning the end of that day
+	// in UTC. Required to accept or transfer a risk.
+	ExpiresAt          string `json:"expires_at"`
+	ReviewIntervalDays int    `json:"review_interval_days"`
+	CreatedBy          string `json:"created_by"`
+}
+
+// apply validates the decision fields and copies them to d. It returns a
+// message describing the first invalid field.
+func (req *GapDispositionRequest) apply(d *models.GapDisposition, now time.Time) string {
+	var expires *time.Time
+	if req.ExpiresAt != "" {
+		t, ok := parseExpiry(req.ExpiresAt)
+		if !ok {
+			return "expires_at must be a date (2006-01-02) or an RFC 3339 time"
+		}
+		expires = &t
+	}
+	d.Disposition = req.Disposition
+	d.Approver = strings.TrimSpace(req.Approver)
+	d.Justification = strings.TrimSpace(req.Justification)
+	d.TransferredTo = strings.TrimSpace(req.TransferredTo)
+	d.ExpiresAt = expires
+	d.ReviewIntervalDays = req.ReviewIntervalDays
+	if err := controls.ValidateDisposition(d, now); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// parseExpiry reads an RFC 3339 time, or a date meaning the end of that
+// day in UTC.
+func parseExpiry(v string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), true
+	}
+	if d, err := time.Parse(time.DateOnly, v); err == nil {
+		return d.AddDate(0, 0, 1), true
+	}
+	return time.Time{}, false
+}
+
+// GapDispositionReviewRequest records a re-review of an accepted or
+// transferred risk, optionally extending its expiry.
+type GapDispositionReviewRequest struct {
+	Reviewer  string `json:"reviewer" binding:"required"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// analysisDispositions returns the organization's decisions on a
+// framework's gaps for a gap analysis.
+func (h *Handlers) analysisDispositions(ctx context.Context, org, framework string) []models.GapDisposition {
+	return controls.AnalysisDispositions(ctx, h.GapDispositions, org, framework)
+}
+
+// ListGapDispositions returns the caller's gap dispositions, optionally
+// filtered by the framework and disposition query parameters. Each is
+// marked active while it is in effect.
+func (h *Handlers) ListGapDispositions(c *gin.Context) {
+	if h.GapDispositions == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap dispositions not configured"})
+		return
+	}
+	framework := c.Query("framework")
+	if framework != "" && !validFrameworkID.MatchString(framework) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	org := c.GetString(orgKey)
+	list, err := h.GapDispositions.List(c.Request.Context(), org, framework)
+	if err != nil {
+		respondRepoError(c, err, "failed to list gap dispositions")
+		return
+	}
+	type item struct {
+		models.GapDisposition
+		Active bool `json:"active"`
+	}
+	now := time.Now()
+	filter := models.Disposition(c.Query("disposition"))
+	items := []item{}
+	for _, d := range list {
+		if filter == "" || d.Disposition == filter {
+			items = append(items, item{GapDisposition: d, Active: controls.DispositionActive(&d, now)})
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"organization_id": org,
+		"dispositions":    items,
+		"total":           len(items),
+	})
+}
+
+// GetGapDisposition returns one of the caller's gap dispositions.
+func (h *Handlers) GetGapDisposition(c *gin.Context) {
+	d, ok := h.loadGapDisposition(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// CreateGapDisposition records the caller's decision on a framework
+// control's gap. Accepting or transferring the risk needs an approver, a
+// justification and an expiry.
+func (h *Handlers) CreateGapDisposition(c *gin.Context) {
+	if h.GapDispositions == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap dispositions not configured"})
+		return
+	}
+	var req GapDispositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if !validFrameworkID.MatchString(req.FrameworkID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	ctx := c.Request.Context()
+	controlID, ok := h.resolveControlID(ctx, req.FrameworkID, req.ControlID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown control", "details": req.FrameworkID + ":" + req.ControlID})
+		return
+	}
+
+	d := &models.GapDisposition{
+		ID:             uuid.NewString(),
+		OrganizationID: c.GetString(orgKey),
+		FrameworkID:    req.FrameworkID,
+		ControlID:      controlID,
+		CreatedBy:      strings.TrimSpace(req.CreatedBy),
+	}
+	if msg := req.apply(d, time.Now()); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gap disposition", "details": msg})
+		return
+	}
+	if err := h.GapDispositions.Create(ctx, d); err != nil {
+		respondRepoError(c, err, "failed to create gap disposition")
+		return
+	}
+	c.JSON(http.StatusCreated, d)
+}
+
+// UpdateGapDisposition replaces a gap disposition's decision, approval and
+// expiry. Changing the decision restarts its review schedule.
+func (h *Handlers) UpdateGapDisposition(c *gin.Context) {
+	d, ok := h.loadGapDisposition(c)
+	if !ok {
+		return
+	}
+	var req GapDispositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	now := time.Now().UTC()
+	if msg := req.apply(d, now); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gap disposition", "details": msg})
+		return
+	}
+	d.ReviewedAt, d.ReviewedBy, d.LastRemindedAt = &now, d.Approver, nil
+	if err := h.GapDispositions.Update(c.Request.Context(), d); err != nil {
+		respondRepoError(c, err, "failed to update gap disposition")
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// ReviewGapDisposition records that an accepted or transferred risk was
+// re-reviewed and still stands, restarting its review interval. An
+// expires_at extends or shortens the decision.
+func (h *Handlers) ReviewGapDisposition(c *gin.Context) {
+	d, ok := h.loadGapDisposition(c)
+	if !ok {
+		return
+	}
+	var req GapDispositionReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reviewer is required"})
+		return
+	}
+	if d.Disposition == models.DispositionRemediate {
+		c.JSON(http.StatusConflict, gin.H{"error": "only accepted or transferred risks are reviewed"})
+		return
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != "" {
+		t, ok := parseExpiry(req.ExpiresAt)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be a date (2006-01-02) or an RFC 3339 time"})
+			return
+		}
+		d.ExpiresAt = &t
+	}
+	if !controls.DispositionActive(d, now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gap disposition", "details": "the decision has expired; set a future expires_at"})
+		return
+	}
+	d.ReviewedAt, d.ReviewedBy, d.LastRemindedAt = &now, strings.TrimSpace(req.Reviewer), nil
+	if err := h.GapDispositions.Update(c.Request.Context(), d); err != nil {
+		respondRepoError(c, err, "failed to review gap disposition")
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// DeleteGapDisposition removes a decision, leaving the gap open.
+func (h *Handlers) DeleteGapDisposition(c *gin.Context) {
+	d, ok := h.loadGapDisposition(c)
+	if !ok {
+		return
+	}
+	if err := h.GapDispositions.Delete(c.Request.Context(), d.ID); err != nil {
+		respondRepoError(c, err, "failed to delete gap disposition")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadGapDisposition fetches the disposition named by the id path
+// parameter, responding 404 when it is missing or belongs to another
+// organization.
+func (h *Handlers) loadGapDisposition(c *gin.Context) (*models.GapDisposition, bool) {
+	if h.GapDispositions == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap dispositions not configured"})
+		return nil, false
+	}
+	id := c.Param("id")
+	if !validateID(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid gap disposition ID format"})
+		return nil, false
+	}
+	d, err := h.GapDispositions.Get(c.Request.Context(), id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get gap disposition")
+		return nil, false
+	}
+	if d == nil || d.OrganizationID != c.GetString(orgKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "gap disposition not found"})
+		return nil, false
+	}
+	return d, true
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/traceexport"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// makeExportTraceHandler serves GET /observe/traces/:id/export. The format
+// query parameter selects otlp (default) or jaeger; the response is a JSON
+// attachment ready to import into Tempo, Jaeger, or Grafana.
+func makeExportTraceHandler(r repository.TraceReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", traceexport.FormatOTLP)
+		if format != traceexport.FormatOTLP && format != traceexport.FormatJaeger {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format", "details": "format must be otlp or jaeger"})
+			return
+		}
+
+		id := c.Param("id")
+		trace, err := r.GetTrace(c.Request.Context(), c.GetString(orgKey), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "trace not found"})
+				return
+			}
+			log.Error().Err(err).Str("trace_id", id).Msg("failed to load trace for export")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load trace"})
+			return
+		}
+
+		body, err := traceexport.Export(format, trace)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format", "details": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="trace-%s-%s.json"`, trace.TraceID, format))
+		c.JSON(http.StatusOK, body)
+	}
+}
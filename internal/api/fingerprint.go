@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/fingerprint"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// fingerprintReason is what a caller is told when a session is blocked for
+// an abrupt behavior change.
+const fingerprintReason = "session behavior changed abruptly"
+
+// observeSession adds a pre-invoke call to its session fingerprint and
+// raises an anomalous_behavior signal for each abrupt change. prompt is the
+// raw prompt captured before hashing. When the agent's risk level blocks on
+// a change, the call is denied and observeSession reports that it has been
+// answered.
+func observeSession(c *gin.Context, deps *RouterDeps, input *opa.EvaluationInput, prompt any) bool {
+	if deps == nil || deps.Fingerprints == nil || input.Request == nil {
+		return false
+	}
+	orgID := c.GetString(orgKey)
+	obs := fingerprint.Observation{
+		OrgID:     orgID,
+		AgentID:   input.Agent.ID,
+		SessionID: input.Request.SessionID,
+		UserID:    input.Request.UserID,
+		RiskLevel: input.Agent.RiskLevel,
+		Prompt:    promptText(prompt),
+		At:        time.Now(),
+	}
+	if input.Tool != nil {
+		obs.Tool = input.Tool.Name
+	}
+	findings, err := deps.Fingerprints.Observe(c.Request.Context(), obs)
+	if err != nil {
+		// A fingerprint is advisory; never fail the call over it.
+		log.Warn().Err(err).Str("agent_id", obs.AgentID).Str("session_id", obs.SessionID).Msg("session fingerprint unavailable")
+		return false
+	}
+
+	blocked := false
+	signalIDs := make([]string, 0, len(findings))
+	for _, f := range findings {
+		severity := "high"
+		if f.Block {
+			severity = "critical"
+			blocked = true
+		}
+		sig := models.SecuritySignal{
+			ID:          uuid.NewString(),
+			Type:        models.SignalAnomalousBehavior,
+			Severity:    severity,
+			Title:       "Abrupt change in session behavior",
+			Description: f.Reason,
+			Evidence: map[string]any{
+				"detector":    "session_fingerprint",
+				"kind":        f.Kind,
+				"score":       f.Score,
+				"threshold":   f.Threshold,
+				"session_id":  obs.SessionID,
+				"user_id":     obs.UserID,
+				"tool_name":   obs.Tool,
+				"prompt_hash": input.Request.PromptHash,
+			},
+			Timestamp: time.Now().UTC(),
+		}
+		log.Warn().Str("org_id", orgID).Str("agent_id", obs.AgentID).Str("session_id", obs.SessionID).
+			Str("kind", string(f.Kind)).Str("signal_id", sig.ID).Msg(f.Reason)
+		dispatchSignal(c, deps, orgID, obs.AgentID, sig)
+		signalIDs = append(signalIDs, sig.ID)
+	}
+	if !blocked {
+		return false
+	}
+
+	auditDecision(c, deps, input, &opa.Decision{
+		Reasons:  []string{fingerprintReason},
+		Metadata: map[string]any{"findings": findings, "signal_ids": signalIDs},
+	})
+	c.JSON(http.StatusForbidden, gin.H{
+		"allow":   false,
+		"reasons": []string{fingerprintReason},
+	})
+	return true
+}
+
+// promptText returns the text to fingerprint from a raw prompt: a string as
+// sent, the latest user message of a chat transcript, or otherwise its JSON.
+func promptText(prompt any) string {
+	switch p := prompt.(type) {
+	case nil:
+		return ""
+	case string:
+		return p
+	case []any:
+		for i := len(p) - 1; i >= 0; i-- {
+			msg, ok := p[i].(map[string]any)
+			if !ok || msg["role"] != "user" {
+				continue
+			}
+			if content, ok := msg["content"].(string); ok {
+				return content
+			}
+		}
+	}
+	b, err := json.Marshal(prompt)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
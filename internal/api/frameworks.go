@@ -0,0 +1,370 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// Without a database, the framework catalog endpoints serve the frameworks
+// loaded into the gap analyzer: the built-in ones and any custom
+// definitions in the data directory.
+
+func makeLoadedFrameworksHandler(ga *controls.GapAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"frameworks": ga.Frameworks()})
+	}
+}
+
+func makeLoadedFrameworkHandler(ga *controls.GapAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if !validFrameworkID.MatchString(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+			return
+		}
+		fw, ok := ga.Framework(id)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "framework not found"})
+			return
+		}
+		c.JSON(http.StatusOK, fw)
+	}
+}
+
+func makeLoadedControlsHandler(ga *controls.GapAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if !validFrameworkID.MatchString(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+			return
+		}
+		list, ok := ga.Controls(id)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "framework not found"})
+			return
+		}
+		q, ok := controlListFilters(c, id)
+		if !ok {
+			return
+		}
+		if q != nil {
+			matches, _, _ := ga.SearchControls(c.Request.Context(), q)
+			list = matchedControls(matches)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"framework_id": id,
+			"controls":     list,
+			"count":        len(list),
+		})
+	}
+}
+
+// maxVersionLen bounds version query parameters; versions are labels such
+// as "1.0" or "2017 (rev. 2022)".
+const maxVersionLen = 64
+
+// versionLister returns a framework's versions, or nil if it is unknown.
+type versionLister func(ctx context.Context, frameworkID string) ([]models.FrameworkVersion, error)
+
+// versionResolver returns a framework version with its controls, or nil if
+// it is unknown. An empty version is the current one.
+type versionResolver func(ctx context.Context, frameworkID, version string) (*models.FrameworkVersion, error)
+
+// makeFrameworkVersionsHandler serves GET /controls/frameworks/:id/versions,
+// oldest first.
+func makeFrameworkVersionsHandler(list versionLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if !validFrameworkID.MatchString(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+			return
+		}
+		versions, err := list(c.Request.Context(), id)
+		if err != nil {
+			respondRepoError(c, err, "failed to list framework versions")
+			return
+		}
+		if versions == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "framework not found"})
+			return
+		}
+		controls.SortVersions(versions)
+		c.JSON(http.StatusOK, gin.H{"framework_id": id, "versions": versions})
+	}
+}
+
+// makeFrameworkDiffHandler serves GET /controls/frameworks/:id/diff: the
+// controls added, removed and changed between ?from= and ?to=, which
+// defaults to the current version. ?implemented= lists an assessment's
+// implemented controls, comma-separated, to flag those to re-assess.
+func makeFrameworkDiffHandler(resolve versionResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if !validFrameworkID.MatchString(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+			return
+		}
+		from, to := c.Query("from"), c.Query("to")
+		if from == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from query parameter required"})
+			return
+		}
+		if len(from) > maxVersionLen || len(to) > maxVersionLen {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		versions := make([]*models.FrameworkVersion, 2)
+		for i, v := range []string{from, to} {
+			fv, err := resolve(ctx, id, v)
+			if err != nil {
+				respondRepoError(c, err, "failed to get framework version")
+				return
+			}
+			if fv == nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "framework version not found", "version": v})
+				return
+			}
+			versions[i] = fv
+		}
+
+		diff := controls.DiffVersions(versions[0], versions[1])
+		if v := c.Query("implemented"); v != "" {
+			var implemented []string
+			for _, id := range strings.Split(v, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					implemented = append(implemented, id)
+				}
+			}
+			diff.MarkAffected(implemented)
+		}
+		c.JSON(http.StatusOK, diff)
+	}
+}
+
+// loadedVersions lists the versions loaded into the gap analyzer.
+func loadedVersions(ga *controls.GapAnalyzer) versionLister {
+	return func(_ context.Context, id string) ([]models.FrameworkVersion, error) {
+		versions, err := ga.Versions(id)
+		if err != nil {
+			return nil, nil
+		}
+		return versions, nil
+	}
+}
+
+// loadedVersion resolves versions loaded into the gap analyzer.
+func loadedVersion(ga *controls.GapAnalyzer) versionResolver {
+	return func(_ context.Context, id, version string) (*models.FrameworkVersion, error) {
+		v, err := ga.Version(id, version)
+		if err != nil {
+			return nil, nil
+		}
+		return v, nil
+	}
+}
+
+// Bounds on control search parameters.
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+	maxSearchTextLen   = 256
+	maxSearchFilters   = 20
+	maxSearchFilterLen = 64
+)
+
+// searchListSpec pages search results, which are ranked by relevance.
+var searchListSpec = listSpec{defaultLimit: defaultSearchLimit, maxLimit: maxSearchLimit}
+
+// controlSearcher returns a page of the controls matching a query and the
+// number of matches in all pages.
+type controlSearcher func(ctx context.Context, q *repository.ControlQuery) ([]repository.ControlMatch, int, error)
+
+// makeControlSearchHandler serves GET /controls/search: controls whose ID,
+// title or description match ?q=, filtered by ?framework=, ?layer= and
+// ?evidence=. Each filter may be repeated or comma-separated and matches
+// controls with any of its values. Results are paged like the other lists,
+// most relevant first.
+func makeControlSearchHandler(search controlSearcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := &repository.ControlQuery{
+			Text:           strings.TrimSpace(c.Query("q")),
+			FrameworkIDs:   queryList(c, "framework"),
+			Layers:         queryList(c, "layer"),
+			EvidenceTypes:  queryList(c, "evidence"),
+			Tags:           queryList(c, "tag"),
+			OrganizationID: c.GetString(orgKey),
+		}
+		if len(q.Text) > maxSearchTextLen {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query text too long"})
+			return
+		}
+		for _, id := range q.FrameworkIDs {
+			if !validFrameworkID.MatchString(id) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+				return
+			}
+		}
+		if !validSearchFilters(c, q.FrameworkIDs, q.Layers, q.EvidenceTypes, q.Tags) {
+			return
+		}
+		page, ok := parseListPage(c, searchListSpec)
+		if !ok {
+			return
+		}
+		q.Offset, q.Limit = page.offset, page.limit
+
+		matches, total, err := search(c.Request.Context(), q)
+		if err != nil {
+			respondRepoError(c, err, "failed to search controls")
+			return
+		}
+		respondList(c, page, total, "controls", matches, gin.H{"query": q.Text})
+	}
+}
+
+// validSearchFilters bounds the number and length of filter values,
+// responding 400 when a filter exceeds them.
+func validSearchFilters(c *gin.Context, filters ...[]string) bool {
+	for _, filter := range filters {
+		if len(filter) > maxSearchFilters || slices.ContainsFunc(filter, func(v string) bool { return len(v) > maxSearchFilterLen }) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "too many or too long filter values"})
+			return false
+		}
+	}
+	return true
+}
+
+// controlListFilters reads the filters of a framework's controls list:
+// ?q= text as in search, ?layer= and ?evidence_type=, which matches whole
+// evidence types. It returns nil when no filter is set, and false after
+// responding 400 to invalid ones.
+func controlListFilters(c *gin.Context, frameworkID string) (*repository.ControlQuery, bool) {
+	q := &repository.ControlQuery{
+		Text:           strings.TrimSpace(c.Query("q")),
+		FrameworkIDs:   []string{frameworkID},
+		Layers:         queryList(c, "layer"),
+		EvidenceTypes:  queryList(c, "evidence_type"),
+		ExactEvidence:  true,
+		OrganizationID: c.GetString(orgKey),
+	}
+	if len(q.Text) > maxSearchTextLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query text too long"})
+		return nil, false
+	}
+	if !validSearchFilters(c, q.Layers, q.EvidenceTypes) {
+		return nil, false
+	}
+	if q.Text == "" && len(q.Layers) == 0 && len(q.EvidenceTypes) == 0 {
+		return nil, true
+	}
+	return q, true
+}
+
+// matchedControls returns the controls of search matches.
+func matchedControls(matches []repository.ControlMatch) []models.Control {
+	list := make([]models.Control, len(matches))
+	for i, m := range matches {
+		list[i] = m.Control
+	}
+	return list
+}
+
+// queryList collects a query parameter's values, repeated or
+// comma-separated.
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, v := range c.QueryArray(key) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+// maxSuggestTopK bounds the suggestions requested per source control.
+const maxSuggestTopK = 10
+
+// suggestRequest is the body of POST /controls/crosswalk/suggest.
+type suggestRequest struct {
+	Source string `json:"source" binding:"required"`
+	Target string `json:"target" binding:"required"`
+	controls.SuggestOptions
+}
+
+// makeCrosswalkSuggestHandler serves POST /controls/crosswalk/suggest:
+// candidate mappings between two loaded frameworks for control pairs the
+// catalog does not map, ranked by embedding similarity. Suggestions are
+// returned for review and not stored. With ?async=true and a job manager,
+// the suggestions are computed as a job.
+func makeCrosswalkSuggestHandler(ga *controls.GapAnalyzer, s *controls.CrosswalkSuggester, m *jobs.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req suggestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source and target are required"})
+			return
+		}
+		if !validFrameworkID.MatchString(req.Source) || !validFrameworkID.MatchString(req.Target) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+			return
+		}
+		if req.TopK < 0 || req.TopK > maxSuggestTopK || req.MinConfidence < 0 || req.MinConfidence > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "top_k must be 0-10 and min_confidence 0-1"})
+			return
+		}
+		for _, id := range []string{req.Source, req.Target} {
+			if _, ok := ga.Framework(id); !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "framework not found", "framework": id})
+				return
+			}
+		}
+
+		suggest := func(ctx context.Context) (any, error) {
+			suggestions, err := ga.SuggestCrosswalks(ctx, s, req.Source, req.Target, req.SuggestOptions)
+			if err != nil {
+				return nil, err
+			}
+			if suggestions == nil {
+				suggestions = []models.Crosswalk{}
+			}
+			return gin.H{
+				"source":      req.Source,
+				"target":      req.Target,
+				"embedder":    s.Embedder(),
+				"status":      "proposed",
+				"suggestions": suggestions,
+				"count":       len(suggestions),
+			}, nil
+		}
+
+		if m != nil && wantsAsync(c) {
+			job, err := submitJob(c, m, "crosswalk_suggestion", "write:controls", suggest)
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue crosswalk suggestion", "details": err.Error()})
+				return
+			}
+			acceptJob(c, job)
+			return
+		}
+
+		result, err := suggest(c.Request.Context())
+		if err != nil {
+			log.Error().Err(err).Str("source", req.Source).Str("target", req.Target).Msg("failed to suggest crosswalk")
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to suggest crosswalk"})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
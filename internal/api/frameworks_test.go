@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/llm"
+)
+
+func TestCrosswalkSuggestAsync(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := jobs.NewManager(jobs.Config{Workers: 1})
+	t.Cleanup(m.Stop)
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		GapAnalyzer: analyzer,
+		Suggester:   controls.NewCrosswalkSuggester(llm.NewHashEmbedder(0), nil),
+		Jobs:        m,
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"},
+			apikey.Key{ID: "reader", Token: "reader-token", Org: "acme", Scopes: []string{"read:controls"}},
+		),
+	})
+	body := map[string]any{"source": "csa-aicm", "target": "nist-800-53", "top_k": 2}
+
+	sync := do(srv, http.MethodPost, "/api/v1/controls/crosswalk/suggest", "ci-token", body)
+	if sync.Code != http.StatusOK {
+		t.Fatalf("suggest = %d %s, want 200", sync.Code, sync.Body)
+	}
+	want := decode[struct{ Count int }](t, sync).Count
+
+	w := do(srv, http.MethodPost, "/api/v1/controls/crosswalk/suggest?async=true", "ci-token", body)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("async suggest = %d %s, want 202", w.Code, w.Body)
+	}
+	job := decode[jobs.Job](t, w)
+	if job.Type != "crosswalk_suggestion" || job.Scope != "write:controls" {
+		t.Errorf("job = %+v, want a crosswalk_suggestion needing write:controls", job)
+	}
+	result := awaitJob(t, srv, "ci-token", job.ID)
+	if result.Code != http.StatusOK {
+		t.Fatalf("result = %d %s, want 200", result.Code, result.Body)
+	}
+	if got := decode[struct{ Count int }](t, result).Count; got != want || got == 0 {
+		t.Errorf("async suggestions = %d, want %d", got, want)
+	}
+
+	if w := do(srv, http.MethodPost, "/api/v1/controls/crosswalk/suggest?async=true", "reader-token", body); w.Code != http.StatusForbidden {
+		t.Errorf("suggest without write:controls = %d, want 403", w.Code)
+	}
+	body["target"] = "no-such-framework"
+	if w := do(srv, http.MethodPost, "/api/v1/controls/crosswalk/suggest?async=true", "ci-token", body); w.Code != http.StatusNotFound {
+		t.Errorf("suggest for an unknown framework = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/gin-gonic/gin"
+)
+
+// bindGapAnalysisUpload reads a multipart gap analysis request: the
+// implemented controls come from a CSV or JSON file in the file part, added
+// to any listed in the implemented_controls field. The other fields mirror
+// GapAnalysisRequest, with lists comma-separated and scoring as JSON;
+// id_column and status_column map the file's columns. It responds 400 and
+// reports false for a malformed request.
+func (h *Handlers) bindGapAnalysisUpload(c *gin.Context, req *GapAnalysisRequest) (*controls.ImplementedImport, bool) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "implemented controls file is required", "details": err.Error()})
+		return nil, false
+	}
+
+	req.TargetFramework = strings.TrimSpace(c.PostForm("target_framework"))
+	if req.TargetFramework == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": "target_framework is required"})
+		return nil, false
+	}
+	req.SourceFramework = strings.TrimSpace(c.PostForm("source_framework"))
+	req.ImplementedControls = formList(c, "implemented_controls")
+	req.Providers = formList(c, "providers")
+	if scoring := c.PostForm("scoring"); scoring != "" {
+		if err := json.Unmarshal([]byte(scoring), &req.Scoring); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scoring model", "details": err.Error()})
+			return nil, false
+		}
+	}
+
+	format := controls.ImportFormat(fh.Filename)
+	if strings.HasPrefix(fh.Header.Get("Content-Type"), "application/json") {
+		format = controls.ImportJSON
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unreadable implemented controls file", "details": err.Error()})
+		return nil, false
+	}
+	defer f.Close()
+	imported, err := h.GapAnalyzer.ImportImplemented(f, format, controls.ImportOptions{
+		IDColumn:     c.PostForm("id_column"),
+		StatusColumn: c.PostForm("status_column"),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid implemented controls file", "details": err.Error()})
+		return nil, false
+	}
+	imported.Source = fh.Filename
+	req.ImplementedControls = append(req.ImplementedControls, imported.Controls...)
+	return imported, true
+}
+
+// formList returns a form field's comma-separated values.
+func formList(c *gin.Context, key string) []string {
+	var values []string
+	for _, v := range c.PostFormArray(key) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
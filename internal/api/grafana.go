@@ -0,0 +1,351 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Grafana series served by the JSON datasource endpoints. Rollup-backed
+// series need ClickHouse; coverage needs gap analyses to have run.
+const (
+	grafanaSignals  = "signals"
+	grafanaDenials  = "denials"
+	grafanaTokens   = "tokens"
+	grafanaLLMCalls = "llm_calls"
+	grafanaCost     = "cost_usd"
+	grafanaCoverage = "coverage"
+)
+
+// grafanaMetric describes a series and the payload options Grafana offers.
+type grafanaMetric struct {
+	id      string
+	label   string
+	rollup  string   // repository metric; empty for non-rollup series
+	groupBy []string // group_by options
+	hourly  bool
+}
+
+var grafanaMetrics = []grafanaMetric{
+	{id: grafanaSignals, label: "Security signals", rollup: repository.MetricSignals, groupBy: []string{"severity", "type", "agent"}},
+	{id: grafanaDenials, label: "Policy denials", rollup: repository.MetricDenials, groupBy: []string{"policy", "tool", "agent"}},
+	{id: grafanaTokens, label: "LLM tokens", rollup: repository.MetricTokens, groupBy: []string{"model", "provider", "agent"}, hourly: true},
+	{id: grafanaLLMCalls, label: "LLM calls", rollup: repository.MetricLLMCalls, groupBy: []string{"model", "provider", "agent"}, hourly: true},
+	{id: grafanaCost, label: "Estimated cost (USD)", groupBy: []string{"model", "agent"}, hourly: true},
+	{id: grafanaCoverage, label: "Framework coverage (%)"},
+}
+
+func lookupGrafanaMetric(id string) (grafanaMetric, bool) {
+	for _, m := range grafanaMetrics {
+		if m.id == id {
+			return m, true
+		}
+	}
+	return grafanaMetric{}, false
+}
+
+// grafanaSource backs the Grafana JSON datasource endpoints. metrics and
+// coverage may be nil; their series then return no data.
+type grafanaSource struct {
+	metrics  repository.MetricsRepository
+	coverage *controls.CoverageHistory
+	pricing  map[string]config.ModelPrice
+}
+
+func newGrafanaSource(metrics repository.MetricsRepository, coverage *controls.CoverageHistory, pricing map[string]config.ModelPrice) *grafanaSource {
+	prices := make(map[string]config.ModelPrice, len(pricing))
+	for model, p := range pricing {
+		prices[strings.ToLower(model)] = p
+	}
+	return &grafanaSource{metrics: metrics, coverage: coverage, pricing: prices}
+}
+
+// grafanaPayload is the per-target payload set in the query editor.
+type grafanaPayload struct {
+	GroupBy   string `json:"group_by"`
+	AgentID   string `json:"agent_id"`
+	Framework string `json:"framework"`
+}
+
+type grafanaQueryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	IntervalMs int64 `json:"intervalMs"`
+	Targets    []struct {
+		Target  string         `json:"target"`
+		RefID   string         `json:"refId"`
+		Hide    bool           `json:"hide"`
+		Payload grafanaPayload `json:"payload"`
+	} `json:"targets"`
+}
+
+// grafanaSeries is a time series; each datapoint is [value, unix ms].
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// handleTest serves GET /grafana, which Grafana calls to test the datasource.
+func (g *grafanaSource) handleTest(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// handleMetrics serves POST /grafana/metrics, listing the available series
+// and their payload fields.
+func (g *grafanaSource) handleMetrics(c *gin.Context) {
+	out := make([]gin.H, 0, len(grafanaMetrics))
+	for _, m := range grafanaMetrics {
+		var payloads []gin.H
+		if len(m.groupBy) > 0 {
+			payloads = append(payloads,
+				gin.H{"name": "group_by", "label": "Group by", "type": "select"},
+				gin.H{"name": "agent_id", "label": "Agent ID", "type": "input"})
+		}
+		if m.id == grafanaCoverage {
+			payloads = append(payloads, gin.H{"name": "framework", "label": "Framework", "type": "select"})
+		}
+		out = append(out, gin.H{"label": m.label, "value": m.id, "payloads": payloads})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// handlePayloadOptions serves POST /grafana/metric-payload-options, listing
+// values for a select payload field.
+func (g *grafanaSource) handlePayloadOptions(c *gin.Context) {
+	var req struct {
+		Metric string `json:"metric"`
+		Name   string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+	m, ok := lookupGrafanaMetric(req.Metric)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown metric", "details": req.Metric})
+		return
+	}
+	options := []grafanaOption{}
+	switch req.Name {
+	case "group_by":
+		for _, dim := range m.groupBy {
+			options = append(options, grafanaOption{Label: dim, Value: dim})
+		}
+	case "framework":
+		// Only frameworks the organization has analyzed have coverage.
+		for _, fw := range g.coverage.Frameworks(c.GetString(orgKey)) {
+			options = append(options, grafanaOption{Label: fw, Value: fw})
+		}
+	}
+	c.JSON(http.StatusOK, options)
+}
+
+// handleQuery serves POST /grafana/query, returning one or more series per
+// target. Rollup intervals follow Grafana's intervalMs: hourly buckets for
+// short ranges where the rollup allows it, weekly for intervals of a week or
+// more, daily otherwise.
+func (g *grafanaSource) handleQuery(c *gin.Context) {
+	var req grafanaQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+	from, to := req.Range.From, req.Range.To
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time range", "details": "from must be before to"})
+		return
+	}
+	if to.Sub(from) > maxMetricsRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time range", "details": "range exceeds one year"})
+		return
+	}
+
+	org := c.GetString(orgKey)
+	out := []grafanaSeries{}
+	for _, t := range req.Targets {
+		if t.Hide || t.Target == "" {
+			continue
+		}
+		m, ok := lookupGrafanaMetric(t.Target)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown metric", "details": t.Target})
+			return
+		}
+		if t.Payload.AgentID != "" && !validateID(t.Payload.AgentID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent_id"})
+			return
+		}
+
+		if t.Target == grafanaCoverage {
+			out = append(out, g.coverageSeries(org, t.Payload.Framework, from, to))
+			continue
+		}
+		if g.metrics == nil {
+			out = append(out, grafanaSeries{Target: t.Target, Datapoints: [][2]float64{}})
+			continue
+		}
+
+		q := repository.MetricsQuery{
+			OrgID:    org,
+			Metric:   m.rollup,
+			From:     from,
+			To:       to,
+			Interval: grafanaInterval(req.IntervalMs, m.hourly),
+		}
+		if t.Payload.GroupBy != "" {
+			q.GroupBy = []string{t.Payload.GroupBy}
+		}
+		if t.Payload.AgentID != "" {
+			agent := t.Payload.AgentID
+			q.AgentID = &agent
+		}
+
+		var points []repository.MetricPoint
+		var err error
+		if t.Target == grafanaCost {
+			points, err = g.cost(c, q)
+		} else {
+			points, err = g.metrics.Rollup(c.Request.Context(), &q)
+		}
+		if err != nil {
+			respondRepoError(c, err, "failed to query metrics")
+			return
+		}
+		out = append(out, toGrafanaSeries(t.Target, q.GroupBy, points)...)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func grafanaInterval(intervalMs int64, hourly bool) string {
+	interval := time.Duration(intervalMs) * time.Millisecond
+	switch {
+	case interval >= 7*24*time.Hour:
+		return "week"
+	case hourly && interval < 24*time.Hour:
+		return "hour"
+	default:
+		return "day"
+	}
+}
+
+// cost prices prompt and completion token rollups by model. Tokens for
+// models without a configured price are not counted.
+func (g *grafanaSource) cost(c *gin.Context, q repository.MetricsQuery) ([]repository.MetricPoint, error) {
+	byModel := true
+	for _, dim := range q.GroupBy {
+		if dim == "model" {
+			byModel = false
+		}
+	}
+	if byModel {
+		q.GroupBy = append(q.GroupBy, "model")
+	}
+
+	type key struct {
+		bucket time.Time
+		group  string
+	}
+	totals := map[key]*repository.MetricPoint{}
+	for _, part := range []struct {
+		metric string
+		price  func(config.ModelPrice) float64
+	}{
+		{repository.MetricPromptTokens, func(p config.ModelPrice) float64 { return p.PromptPer1K }},
+		{repository.MetricCompletionTokens, func(p config.ModelPrice) float64 { return p.CompletionPer1K }},
+	} {
+		pq := q
+		pq.Metric = part.metric
+		points, err := g.metrics.Rollup(c.Request.Context(), &pq)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range points {
+			price, ok := g.pricing[strings.ToLower(p.Group["model"])]
+			if !ok {
+				continue
+			}
+			if byModel {
+				delete(p.Group, "model")
+				if len(p.Group) == 0 {
+					p.Group = nil
+				}
+			}
+			k := key{p.Bucket, groupLabel(p.Group)}
+			if totals[k] == nil {
+				totals[k] = &repository.MetricPoint{Bucket: p.Bucket, Group: p.Group}
+			}
+			totals[k].Value += p.Value / 1000 * part.price(price)
+		}
+	}
+
+	out := make([]repository.MetricPoint, 0, len(totals))
+	for _, p := range totals {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Bucket.Before(out[j].Bucket) })
+	return out, nil
+}
+
+func (g *grafanaSource) coverageSeries(org, framework string, from, to time.Time) grafanaSeries {
+	s := grafanaSeries{Target: grafanaCoverage, Datapoints: [][2]float64{}}
+	if framework == "" {
+		return s
+	}
+	s.Target = fmt.Sprintf("%s{framework=%s}", grafanaCoverage, framework)
+	for _, p := range g.coverage.Range(org, framework, from, to) {
+		s.Datapoints = append(s.Datapoints, [2]float64{p.Coverage, float64(p.Time.UnixMilli())})
+	}
+	return s
+}
+
+// toGrafanaSeries splits points into one series per group, named like
+// signals{severity=high}.
+func toGrafanaSeries(target string, groupBy []string, points []repository.MetricPoint) []grafanaSeries {
+	if len(groupBy) == 0 {
+		s := grafanaSeries{Target: target, Datapoints: make([][2]float64, 0, len(points))}
+		for _, p := range points {
+			s.Datapoints = append(s.Datapoints, [2]float64{p.Value, float64(p.Bucket.UnixMilli())})
+		}
+		return []grafanaSeries{s}
+	}
+	index := map[string]int{}
+	var out []grafanaSeries
+	for _, p := range points {
+		name := target + "{" + groupLabel(p.Group) + "}"
+		i, ok := index[name]
+		if !ok {
+			i = len(out)
+			index[name] = i
+			out = append(out, grafanaSeries{Target: name})
+		}
+		out[i].Datapoints = append(out[i].Datapoints, [2]float64{p.Value, float64(p.Bucket.UnixMilli())})
+	}
+	return out
+}
+
+func groupLabel(group map[string]string) string {
+	keys := make([]string, 0, len(group))
+	for k := range group {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + group[k]
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,12 +1,21 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/jobs"
 	"github.com/agentguard/agentguard/internal/models"
 	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/signing"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -25,12 +34,70 @@ func validateID(id string) bool {
 	return validID.MatchString(id)
 }
 
+// repoErrorStatus maps repository sentinel errors to HTTP status codes.
+func repoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrForeignKey):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrInvalidQuery):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondRepoError writes an error response for a failed repository call.
+// Client-caused failures get a specific message; others are logged as errors.
+func respondRepoError(c *gin.Context, err error, msg string) {
+	status := repoErrorStatus(err)
+	switch status {
+	case http.StatusNotFound:
+		c.JSON(status, gin.H{"error": msg, "details": "referenced entity not found"})
+	case http.StatusConflict:
+		c.JSON(status, gin.H{"error": msg, "details": "entity already exists"})
+	case http.StatusUnprocessableEntity:
+		c.JSON(status, gin.H{"error": msg, "details": "referenced entity does not exist or is still in use"})
+	case http.StatusBadRequest:
+		c.JSON(status, gin.H{"error": msg, "details": err.Error()})
+	default:
+		log.Error().Err(err).Msg(msg)
+		c.JSON(status, gin.H{"error": msg})
+	}
+}
+
 // Handlers holds all API handlers with their dependencies.
 type Handlers struct {
 	ControlRepo repository.ControlRepository
 	GapAnalyzer *controls.GapAnalyzer
-	// AgentRepo   repository.AgentRepository  // TODO: implement
-	// PolicyRepo  repository.PolicyRepository // TODO: implement
+	Jobs        *jobs.Manager
+	// Coverage records each analysis's coverage for charting. Optional.
+	Coverage *controls.CoverageHistory
+	// GapAnalyses stores each analysis run for history. Optional.
+	GapAnalyses repository.GapAnalysisRepository
+	// Implementations tracks control implementations; a gap analysis that
+	// does not list its implemented controls uses them. Optional.
+	Implementations repository.ControlImplementationRepository
+	// Attestations stores attestation campaigns. Optional.
+	Attestations repository.AttestationRepository
+	// GapDispositions stores decisions to remediate, accept or transfer
+	// gaps, which gap analyses apply. Optional.
+	GapDispositions repository.GapDispositionRepository
+	// Agents is the agent registry, which also scopes System Security
+	// Plans to one agent. Optional.
+	Agents repository.AgentRepository
+	// Policies stores policy definitions; agents may only bind policies
+	// it holds. Optional.
+	Policies repository.PolicyRepository
+	// SigningKeys issues the keys agents sign SDK hook requests with.
+	// Optional.
+	SigningKeys *signing.Keys
+	// CatalogDir holds the OSCAL catalogs that imported profiles may
+	// select from. Optional.
+	CatalogDir string
 }
 
 // NewHandlers creates a new Handlers instance.
@@ -45,9 +112,37 @@ func NewHandlers(controlRepo repository.ControlRepository, gapAnalyzer *controls
 // Control Framework Handlers
 // -----------------------------------------------------------------------------
 
-// ListFrameworks returns all compliance frameworks.
+// Framework and control list page sizes. Catalogs are small, so the
+// defaults usually return a whole list in one page.
+const (
+	defaultFrameworkLimit = 100
+	maxFrameworkLimit     = 500
+	defaultControlLimit   = 500
+	maxControlLimit       = 2000
+)
+
+var (
+	frameworkListSpec = listSpec{defaultLimit: defaultFrameworkLimit, maxLimit: maxFrameworkLimit, sorts: []string{"id", "name"}}
+	controlListSpec   = listSpec{defaultLimit: defaultControlLimit, maxLimit: maxControlLimit, sorts: []string{"control_id", "title"}}
+
+	frameworkSortFields = map[string]func(a, b *models.Framework) int{
+		"id":   func(a, b *models.Framework) int { return strings.Compare(a.ID, b.ID) },
+		"name": func(a, b *models.Framework) int { return strings.Compare(a.Name, b.Name) },
+	}
+	controlSortFields = map[string]func(a, b *models.Control) int{
+		"control_id": func(a, b *models.Control) int { return strings.Compare(a.ControlID, b.ControlID) },
+		"title":      func(a, b *models.Control) int { return strings.Compare(a.Title, b.Title) },
+	}
+)
+
+// ListFrameworks returns the compliance frameworks, paged and sorted like
+// the other lists.
 func (h *Handlers) ListFrameworks(c *gin.Context) {
 	ctx := c.Request.Context()
+	page, ok := parseListPage(c, frameworkListSpec)
+	if !ok {
+		return
+	}
 
 	frameworks, err := h.ControlRepo.ListFrameworks(ctx)
 	if err != nil {
@@ -56,7 +151,23 @@ func (h *Handlers) ListFrameworks(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"frameworks": frameworks})
+	// Custom frameworks from the data directory are listed alongside the
+	// stored ones.
+	if h.GapAnalyzer != nil {
+		stored := make(map[string]bool, len(frameworks))
+		for i := range frameworks {
+			stored[frameworks[i].ID] = true
+			h.withLicensing(&frameworks[i])
+		}
+		for _, fw := range h.GapAnalyzer.Frameworks() {
+			if !stored[fw.ID] {
+				frameworks = append(frameworks, *fw)
+			}
+		}
+	}
+
+	sortItems(frameworks, page, frameworkSortFields)
+	respondList(c, page, len(frameworks), "frameworks", pageOf(frameworks, page), nil)
 }
 
 // GetFramework returns a single framework by ID.
@@ -76,15 +187,22 @@ func (h *Handlers) GetFramework(c *gin.Context) {
 		return
 	}
 
+	if framework == nil && h.GapAnalyzer != nil {
+		framework, _ = h.GapAnalyzer.Framework(id)
+	}
 	if framework == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "framework not found"})
 		return
 	}
+	h.withLicensing(framework)
 
 	c.JSON(http.StatusOK, framework)
 }
 
-// ListControls returns all controls for a framework.
+// ListControls returns a framework's controls. ?q=, ?layer=,
+// ?evidence_type= and ?tag= narrow the list; each filter may be repeated or
+// comma-separated and matches controls with any of its values. The list is
+// paged and sorted like the other lists.
 func (h *Handlers) ListControls(c *gin.Context) {
 	ctx := c.Request.Context()
 	frameworkID := c.Param("id")
@@ -94,18 +212,111 @@ func (h *Handlers) ListControls(c *gin.Context) {
 		return
 	}
 
-	controls, err := h.ControlRepo.ListControls(ctx, frameworkID)
-	if err != nil {
-		log.Error().Err(err).Str("framework_id", frameworkID).Msg("failed to list controls")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list controls"})
+	page, ok := parseListPage(c, controlListSpec)
+	if !ok {
+		return
+	}
+	filter, ok := controlListFilters(c, frameworkID)
+	if !ok {
+		return
+	}
+	tagFilter := queryList(c, "tag")
+	if !validSearchFilters(c, tagFilter) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"framework_id": frameworkID,
-		"controls":     controls,
-		"count":        len(controls),
-	})
+	var controls []models.Control
+	if filter != nil {
+		// Filters are applied by the repository's search, which indexes
+		// layers, evidence types and text.
+		filter.Tags = tagFilter
+		matches, _, err := h.searchControls(ctx, filter)
+		if err != nil {
+			respondRepoError(c, err, "failed to list controls")
+			return
+		}
+		controls = matchedControls(matches)
+	} else {
+		var err error
+		controls, err = h.ControlRepo.ListControls(ctx, frameworkID)
+		if err != nil {
+			log.Error().Err(err).Str("framework_id", frameworkID).Msg("failed to list controls")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list controls"})
+			return
+		}
+		if len(controls) == 0 && h.GapAnalyzer != nil {
+			controls, _ = h.GapAnalyzer.Controls(frameworkID)
+		}
+		tags, err := h.tagIndex(ctx, c.GetString(orgKey), frameworkID)
+		if err != nil {
+			respondRepoError(c, err, "failed to list control tags")
+			return
+		}
+		controls = tags.Apply(controls, tagFilter)
+	}
+	if !h.requireFullText(c, frameworkID, controls) {
+		return
+	}
+
+	sortItems(controls, page, controlSortFields)
+	respondList(c, page, len(controls), "controls", pageOf(controls, page), gin.H{"framework_id": frameworkID})
+}
+
+// frameworkVersions lists a framework's stored versions, falling back to
+// those loaded into the gap analyzer.
+func (h *Handlers) frameworkVersions(ctx context.Context, id string) ([]models.FrameworkVersion, error) {
+	if vr, ok := h.ControlRepo.(repository.FrameworkVersionRepository); ok {
+		versions, err := vr.ListFrameworkVersions(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) > 0 {
+			return versions, nil
+		}
+	}
+	if h.GapAnalyzer != nil {
+		return loadedVersions(h.GapAnalyzer)(ctx, id)
+	}
+	return nil, nil
+}
+
+// frameworkVersion resolves a stored framework version, falling back to
+// those loaded into the gap analyzer.
+func (h *Handlers) frameworkVersion(ctx context.Context, id, version string) (*models.FrameworkVersion, error) {
+	if vr, ok := h.ControlRepo.(repository.FrameworkVersionRepository); ok {
+		v, err := vr.GetFrameworkVersion(ctx, id, version)
+		if err != nil || v != nil {
+			return v, err
+		}
+	}
+	if h.GapAnalyzer != nil {
+		return loadedVersion(h.GapAnalyzer)(ctx, id, version)
+	}
+	return nil, nil
+}
+
+// searchControls searches the stored controls, falling back to those
+// loaded into the gap analyzer when the database has no matches.
+func (h *Handlers) searchControls(ctx context.Context, q *repository.ControlQuery) ([]repository.ControlMatch, int, error) {
+	tags, err := h.tagIndex(ctx, q.OrganizationID, "")
+	if err != nil {
+		return nil, 0, err
+	}
+	if sr, ok := h.ControlRepo.(repository.ControlSearchRepository); ok {
+		matches, total, err := sr.SearchControls(ctx, q)
+		if err != nil || total > 0 {
+			for i := range matches {
+				matches[i].Tags = tags.Of(matches[i].FrameworkID, matches[i].ControlID)
+			}
+			return matches, total, err
+		}
+	}
+	if h.GapAnalyzer != nil {
+		return controls.SearchTagged(func(q *repository.ControlQuery) ([]repository.ControlMatch, int, error) {
+			return h.GapAnalyzer.SearchControls(ctx, q)
+		}, q, tags)
+	}
+	return nil, 0, nil
 }
 
 // GetControl returns a single control by ID.
@@ -129,11 +340,15 @@ func (h *Handlers) GetControl(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "control not found"})
 		return
 	}
+	if !h.requireFullText(c, control.FrameworkID, []models.Control{*control}) {
+		return
+	}
 
 	c.JSON(http.StatusOK, control)
 }
 
-// GetCrosswalk returns crosswalks between two frameworks.
+// GetCrosswalk returns crosswalks between two frameworks. The format query
+// parameter, or else the Accept header, selects json (default), csv or xlsx.
 func (h *Handlers) GetCrosswalk(c *gin.Context) {
 	ctx := c.Request.Context()
 	source := c.Query("source")
@@ -148,8 +363,12 @@ func (h *Handlers) GetCrosswalk(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
 		return
 	}
+	format, ok := negotiateReportFormat(c, controls.ReportJSON, controls.ReportCSV, controls.ReportXLSX)
+	if !ok {
+		return
+	}
 
-	crosswalks, err := h.ControlRepo.GetCrosswalk(ctx, source, target)
+	stored, err := h.ControlRepo.GetCrosswalk(ctx, source, target)
 	if err != nil {
 		log.Error().Err(err).
 			Str("source", source).
@@ -159,6 +378,71 @@ func (h *Handlers) GetCrosswalk(c *gin.Context) {
 		return
 	}
 
+	// review_state lists the curated mappings at one review stage, for
+	// reviewers, instead of the mappings in effect.
+	if state := models.ReviewState(c.Query("review_state")); state != "" {
+		if !validReviewState(state) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "review_state must be proposed, reviewed or approved"})
+			return
+		}
+		queue := []models.Crosswalk{}
+		for _, xw := range stored {
+			if xw.ReviewState == state {
+				queue = append(queue, xw)
+			}
+		}
+		if format != controls.ReportJSON {
+			h.writeCrosswalks(c, source, target, queue, format)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"source":       source,
+			"target":       target,
+			"review_state": state,
+			"mappings":     queue,
+			"count":        len(queue),
+		})
+		return
+	}
+
+	// Approved curated mappings override the built-in ones for the same
+	// control pair.
+	var builtin []models.Crosswalk
+	if h.GapAnalyzer != nil {
+		if direct, err := h.GapAnalyzer.Crosswalks(source, target, false); err == nil {
+			for _, xw := range direct {
+				if !xw.Derived {
+					builtin = append(builtin, xw)
+				}
+			}
+		}
+	}
+	crosswalks := controls.OverrideCrosswalks(builtin, stored)
+
+	// Pairs without direct mappings get mappings inferred through pivot
+	// frameworks; include_derived adds them for the control pairs the
+	// catalog does not map directly even when others are mapped.
+	if len(crosswalks) == 0 || c.Query("include_derived") == "true" {
+		derived, err := h.deriveCrosswalks(ctx, source, target)
+		if err != nil {
+			log.Warn().Err(err).Str("source", source).Str("target", target).Msg("failed to derive crosswalks")
+		} else {
+			mapped := make(map[[2]string]bool, len(crosswalks))
+			for _, xw := range crosswalks {
+				mapped[[2]string{strings.ToLower(xw.SourceControlID), strings.ToLower(xw.TargetControlID)}] = true
+			}
+			for _, xw := range derived {
+				if !mapped[[2]string{strings.ToLower(xw.SourceControlID), strings.ToLower(xw.TargetControlID)}] {
+					crosswalks = append(crosswalks, xw)
+				}
+			}
+		}
+	}
+
+	if format != controls.ReportJSON {
+		h.writeCrosswalks(c, source, target, crosswalks, format)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"source":   source,
 		"target":   target,
@@ -167,6 +451,58 @@ func (h *Handlers) GetCrosswalk(c *gin.Context) {
 	})
 }
 
+// writeCrosswalks sends crosswalk mappings as a csv or xlsx download. The
+// workbook also lists the controls of either framework left unmapped.
+func (h *Handlers) writeCrosswalks(c *gin.Context, source, target string, crosswalks []models.Crosswalk, format string) {
+	var sourceControls, targetControls []models.Control
+	if format == controls.ReportXLSX {
+		sourceControls = h.frameworkControls(c.Request.Context(), source)
+		targetControls = h.frameworkControls(c.Request.Context(), target)
+	}
+	writeReport(c, "crosswalk-"+source+"-"+target, format, func(buf *bytes.Buffer) error {
+		return controls.WriteCrosswalks(buf, crosswalks, sourceControls, targetControls, format)
+	})
+}
+
+// frameworkControls returns a framework's stored controls, falling back to
+// those loaded into the gap analyzer. Errors are logged and yield none.
+func (h *Handlers) frameworkControls(ctx context.Context, framework string) []models.Control {
+	list, err := h.ControlRepo.ListControls(ctx, framework)
+	if err != nil {
+		log.Warn().Err(err).Str("framework_id", framework).Msg("failed to list framework controls")
+	}
+	if len(list) == 0 && h.GapAnalyzer != nil {
+		list, _ = h.GapAnalyzer.Controls(framework)
+	}
+	return list
+}
+
+// deriveCrosswalks infers source→target mappings through the stored
+// frameworks, falling back to the built-in catalog when the stored
+// crosswalks yield none.
+func (h *Handlers) deriveCrosswalks(ctx context.Context, source, target string) ([]models.Crosswalk, error) {
+	frameworks, err := h.ControlRepo.ListFrameworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pivots := make([]controls.FrameworkID, len(frameworks))
+	for i, f := range frameworks {
+		pivots[i] = controls.FrameworkID(f.ID)
+	}
+	derived, err := controls.InferCrosswalks(controls.FrameworkID(source), controls.FrameworkID(target), pivots,
+		func(from, to controls.FrameworkID) ([]models.Crosswalk, error) {
+			stored, err := h.ControlRepo.GetCrosswalk(ctx, string(from), string(to))
+			return controls.OverrideCrosswalks(nil, stored), err
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(derived) == 0 && h.GapAnalyzer != nil {
+		return h.GapAnalyzer.DeriveCrosswalks(source, target)
+	}
+	return derived, nil
+}
+
 // CreateFramework creates a new framework.
 func (h *Handlers) CreateFramework(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -187,8 +523,7 @@ func (h *Handlers) CreateFramework(c *gin.Context) {
 	}
 
 	if err := h.ControlRepo.CreateFramework(ctx, &framework); err != nil {
-		log.Error().Err(err).Msg("failed to create framework")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create framework"})
+		respondRepoError(c, err, "failed to create framework")
 		return
 	}
 
@@ -212,38 +547,203 @@ func (h *Handlers) CreateControl(c *gin.Context) {
 	}
 
 	if err := h.ControlRepo.CreateControl(ctx, &control); err != nil {
-		log.Error().Err(err).Msg("failed to create control")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create control"})
+		respondRepoError(c, err, "failed to create control")
 		return
 	}
 
 	c.JSON(http.StatusCreated, control)
 }
 
+// ImportCatalog creates a framework with its controls and crosswalks in one
+// transaction, so a failed import never leaves a partial catalog behind.
+func (h *Handlers) ImportCatalog(c *gin.Context) {
+	var catalog repository.CatalogImport
+	if err := c.ShouldBindJSON(&catalog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := prepareCatalogImport(&catalog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid catalog", "details": err.Error()})
+		return
+	}
+
+	if err := h.ControlRepo.ImportCatalog(c.Request.Context(), &catalog); err != nil {
+		respondRepoError(c, err, "failed to import catalog")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"framework":  catalog.Framework,
+		"controls":   len(catalog.Controls),
+		"crosswalks": len(catalog.Crosswalks),
+	})
+}
+
+// prepareCatalogImport validates an import and assigns missing IDs.
+func prepareCatalogImport(catalog *repository.CatalogImport) error {
+	fw := &catalog.Framework
+	if !validFrameworkID.MatchString(fw.ID) {
+		return fmt.Errorf("framework id must be 2-64 lowercase alphanumeric chars, hyphens, or underscores")
+	}
+	if fw.Name == "" || fw.Version == "" {
+		return fmt.Errorf("framework name and version are required")
+	}
+
+	seen := make(map[string]bool, len(catalog.Controls))
+	for i := range catalog.Controls {
+		ctrl := &catalog.Controls[i]
+		if ctrl.FrameworkID == "" {
+			ctrl.FrameworkID = fw.ID
+		}
+		if ctrl.FrameworkID != fw.ID {
+			return fmt.Errorf("control %s belongs to framework %s, not %s", ctrl.ControlID, ctrl.FrameworkID, fw.ID)
+		}
+		if ctrl.ControlID == "" || ctrl.Title == "" {
+			return fmt.Errorf("control %d: control_id and title are required", i)
+		}
+		if seen[ctrl.ControlID] {
+			return fmt.Errorf("duplicate control_id %s", ctrl.ControlID)
+		}
+		seen[ctrl.ControlID] = true
+		if ctrl.ID == "" {
+			ctrl.ID = uuid.New().String()
+		}
+	}
+
+	for i := range catalog.Crosswalks {
+		cw := &catalog.Crosswalks[i]
+		if cw.SourceFrameworkID != fw.ID && cw.TargetFrameworkID != fw.ID {
+			return fmt.Errorf("crosswalk %d does not reference framework %s", i, fw.ID)
+		}
+		if cw.SourceControlID == "" || cw.TargetControlID == "" {
+			return fmt.Errorf("crosswalk %d: source and target control IDs are required", i)
+		}
+		if cw.ID == "" {
+			cw.ID = uuid.New().String()
+		}
+	}
+
+	return nil
+}
+
 // GapAnalysisRequest represents a gap analysis request.
 type GapAnalysisRequest struct {
-	TargetFramework     string   `json:"target_framework" binding:"required"`
-	ImplementedControls []string `json:"implemented_controls"`
-	SourceFramework     string   `json:"source_framework,omitempty"`
+	TargetFramework string `json:"target_framework" binding:"required"`
+	// ImplementedControls lists the implemented control IDs. When omitted,
+	// the organization's implemented and verified tracked implementations
+	// in the target and source frameworks are used.
+	ImplementedControls []string               `json:"implemented_controls"`
+	SourceFramework     string                 `json:"source_framework,omitempty"`
+	Providers           []string               `json:"providers,omitempty"`
+	Scoring             *controls.ScoringModel `json:"scoring,omitempty"`
 }
 
-// AnalyzeGaps analyzes gaps between frameworks.
+// AnalyzeGaps analyzes gaps between frameworks. The format query parameter,
+// or else the Accept header, selects a json (default), html, pdf, csv or
+// xlsx report.
+// A multipart/form-data request reads the implemented controls from an
+// uploaded CSV or JSON file; the JSON report summarizes ignored entries.
+// The as_of query parameter analyzes the tracked implementations as they
+// stood at a past date or time; such analyses are not stored.
 func (h *Handlers) AnalyzeGaps(c *gin.Context) {
 	if h.GapAnalyzer == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
 		return
 	}
 
-	var req GapAnalysisRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	format, ok := negotiateReportFormat(c, controls.ReportJSON, controls.ReportHTML, controls.ReportPDF, controls.ReportCSV, controls.ReportXLSX)
+	if !ok {
+		return
+	}
+
+	var (
+		req      GapAnalysisRequest
+		imported *controls.ImplementedImport
+	)
+	if c.ContentType() == "multipart/form-data" {
+		if imported, ok = h.bindGapAnalysisUpload(c, &req); !ok {
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
 
+	// Without a list or file, the organization's tracked implementations
+	// are its implemented controls; as_of takes them as they stood then.
+	asOf, ok := asOfParam(c)
+	if !ok {
+		return
+	}
+	if !asOf.IsZero() {
+		if req.ImplementedControls != nil || imported != nil || h.Implementations == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of", "details": "as_of applies only to tracked implementations"})
+			return
+		}
+		if _, ok := h.implementationHistory(c); !ok {
+			return
+		}
+	}
+	org := c.GetString(orgKey)
+	if req.ImplementedControls == nil && imported == nil && h.Implementations != nil {
+		tracked, err := h.trackedControls(c.Request.Context(), org, asOf, req.TargetFramework, req.SourceFramework)
+		if err != nil {
+			respondRepoError(c, err, "failed to load tracked control implementations")
+			return
+		}
+		req.ImplementedControls = tracked
+	}
+
+	if req.Scoring != nil {
+		if err := req.Scoring.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scoring model", "details": err.Error()})
+			return
+		}
+	}
+
 	input := &controls.AnalysisInput{
 		TargetFramework:     req.TargetFramework,
 		ImplementedControls: req.ImplementedControls,
 		SourceFramework:     req.SourceFramework,
+		Providers:           req.Providers,
+		Scoring:             req.Scoring,
+		Tags:                h.analysisTags(c.Request.Context(), org, req.TargetFramework),
+		Dispositions:        h.analysisDispositions(c.Request.Context(), org, req.TargetFramework),
+	}
+
+	for _, id := range req.Providers {
+		if _, ok := h.GapAnalyzer.GetProvider(id); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown control provider", "details": id})
+			return
+		}
+	}
+	if req.SourceFramework != "" {
+		if _, ok := h.GapAnalyzer.Framework(req.SourceFramework); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown source framework", "details": req.SourceFramework})
+			return
+		}
+	}
+
+	if h.Jobs != nil && wantsAsync(c) {
+		if format != controls.ReportJSON {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported report format", "details": "asynchronous analyses return json"})
+			return
+		}
+		job, err := submitJob(c, h.Jobs, "gap_analysis", "write:controls", func(ctx context.Context) (any, error) {
+			output, err := h.GapAnalyzer.RunAnalysis(ctx, input)
+			if err == nil {
+				output.Import = imported
+				h.recordGapAnalysis(ctx, org, asOf, input, output)
+			}
+			return output, err
+		})
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue analysis", "details": err.Error()})
+			return
+		}
+		acceptJob(c, job)
+		return
 	}
 
 	output, err := h.GapAnalyzer.RunAnalysis(c.Request.Context(), input)
@@ -252,10 +752,173 @@ func (h *Handlers) AnalyzeGaps(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "analysis failed"})
 		return
 	}
+	output.Import = imported
+	h.recordGapAnalysis(c.Request.Context(), org, asOf, input, output)
 
+	if format != controls.ReportJSON {
+		writeReport(c, "gap-analysis-"+output.Framework, format, func(buf *bytes.Buffer) error {
+			return h.GapAnalyzer.WriteReport(buf, output, format)
+		})
+		return
+	}
 	c.JSON(http.StatusOK, output)
 }
 
+// recordGapAnalysis records a current analysis in the coverage history and
+// stores it. An analysis as of a past time is only marked as such: storing
+// it would put a past posture in today's trend.
+func (h *Handlers) recordGapAnalysis(ctx context.Context, org string, asOf time.Time, input *controls.AnalysisInput, output *controls.AnalysisOutput) {
+	if !asOf.IsZero() {
+		output.AsOf = &asOf
+		return
+	}
+	h.Coverage.Record(org, output.Framework, time.Now(), output.CoveragePercentage)
+	h.saveGapAnalysis(ctx, org, input, output)
+}
+
+// saveGapAnalysis stores an analysis run when a repository is configured.
+// A failure is logged; the analysis result is still returned.
+func (h *Handlers) saveGapAnalysis(ctx context.Context, org string, input *controls.AnalysisInput, output *controls.AnalysisOutput) {
+	if h.GapAnalyses == nil {
+		return
+	}
+	ga := controls.NewGapAnalysis(org, input, output, time.Now())
+	if err := h.GapAnalyses.Create(ctx, ga); err != nil {
+		log.Error().Err(err).Str("org_id", org).Str("framework", output.Framework).Msg("failed to save gap analysis")
+	}
+}
+
+// ListGapAnalyses returns the caller organization's stored gap analyses,
+// newest first. An org query parameter naming another organization is
+// refused. The as_of query parameter leaves out analyses run after a past
+// date or time.
+func (h *Handlers) ListGapAnalyses(c *gin.Context) {
+	if h.GapAnalyses == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analysis history not configured"})
+		return
+	}
+	org := c.GetString(orgKey)
+	if q := c.Query("org"); q != "" && q != org {
+		c.JSON(http.StatusForbidden, gin.H{"error": "organization mismatch", "details": "gap analyses can only be listed for the caller's organization"})
+		return
+	}
+
+	analyses, err := h.GapAnalyses.List(c.Request.Context(), org)
+	if err != nil {
+		log.Error().Err(err).Str("org_id", org).Msg("failed to list gap analyses")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list gap analyses"})
+		return
+	}
+	asOf, ok := asOfParam(c)
+	if !ok {
+		return
+	}
+	if framework := c.Query("framework"); framework != "" || !asOf.IsZero() {
+		filtered := analyses[:0]
+		for _, ga := range analyses {
+			if (framework == "" || ga.TargetFrameworkID == framework) && (asOf.IsZero() || !ga.AnalysisDate.After(asOf)) {
+				filtered = append(filtered, ga)
+			}
+		}
+		analyses = filtered
+	}
+	if analyses == nil {
+		analyses = []models.GapAnalysis{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organization_id": org,
+		"analyses":        analyses,
+		"total":           len(analyses),
+	})
+}
+
+// GetGapAnalysis returns a stored gap analysis.
+func (h *Handlers) GetGapAnalysis(c *gin.Context) {
+	if h.GapAnalyses == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analysis history not configured"})
+		return
+	}
+	ga, ok := h.loadGapAnalysis(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, ga)
+}
+
+// loadGapAnalysis fetches the stored gap analysis named by the id path
+// parameter, responding 404 when it is missing or belongs to another
+// organization.
+func (h *Handlers) loadGapAnalysis(c *gin.Context) (*models.GapAnalysis, bool) {
+	id := c.Param("id")
+	ga, err := h.GapAnalyses.Get(c.Request.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("failed to get gap analysis")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get gap analysis"})
+		return nil, false
+	}
+	if ga == nil || ga.OrganizationID != c.GetString(orgKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "gap analysis not found"})
+		return nil, false
+	}
+	return ga, true
+}
+
+// GetGapAnalysisPOAM returns a plan of action and milestones for a stored
+// gap analysis. The capacity and start query parameters sequence it as for
+// the roadmap; start defaults to the quarter of the analysis. The format
+// query parameter, or else the Accept header, selects json (default), csv,
+// xlsx or oscal.
+func (h *Handlers) GetGapAnalysisPOAM(c *gin.Context) {
+	if h.GapAnalyses == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analysis history not configured"})
+		return
+	}
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+	format, ok := negotiateReportFormat(c, controls.ReportJSON, controls.ReportCSV, controls.ReportXLSX, controls.ReportOSCAL)
+	if !ok {
+		return
+	}
+	var opts controls.RoadmapOptions
+	if v := c.Query("capacity"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxRoadmapCapacity {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "capacity must be between 1 and 1000"})
+			return
+		}
+		opts.Capacity = n
+	}
+	if v := c.Query("start"); v != "" {
+		start, err := controls.ParseQuarter(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start", "details": err.Error()})
+			return
+		}
+		opts.Start = start
+	}
+	ga, ok := h.loadGapAnalysis(c)
+	if !ok {
+		return
+	}
+
+	poam, err := h.GapAnalyzer.POAM(ga, opts)
+	if err != nil {
+		// The stored framework may since have been removed from the catalog.
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "cannot build poam", "details": err.Error()})
+		return
+	}
+	if format != controls.ReportJSON {
+		writeReport(c, "poam-"+ga.TargetFrameworkID+"-"+ga.AnalysisDate.Format("20060102"), format, func(buf *bytes.Buffer) error {
+			return h.GapAnalyzer.WritePOAM(buf, poam, format)
+		})
+		return
+	}
+	c.JSON(http.StatusOK, poam)
+}
+
 // GetGapAnalysisSummary returns a summary of gaps for a framework.
 func (h *Handlers) GetGapAnalysisSummary(c *gin.Context) {
 	if h.GapAnalyzer == nil {
@@ -288,3 +951,139 @@ func (h *Handlers) GetGapAnalysisSummary(c *gin.Context) {
 		"summary":        output.Summary,
 	})
 }
+
+// GetScoringModel returns the organization-wide gap scoring model.
+func (h *Handlers) GetScoringModel(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.GapAnalyzer.ScoringModel())
+}
+
+// UpdateScoringModel replaces the organization-wide gap scoring model.
+func (h *Handlers) UpdateScoringModel(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+
+	var model controls.ScoringModel
+	if err := c.ShouldBindJSON(&model); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.GapAnalyzer.SetScoringModel(&model); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scoring model", "details": err.Error()})
+		return
+	}
+
+	log.Info().Msg("gap scoring model updated")
+	c.JSON(http.StatusOK, &model)
+}
+
+// ListControlProviders returns the registered common control providers.
+func (h *Handlers) ListControlProviders(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+
+	providers := h.GapAnalyzer.ListProviders()
+	c.JSON(http.StatusOK, gin.H{
+		"providers": providers,
+		"total":     len(providers),
+	})
+}
+
+// GetControlProvider returns a common control provider by ID.
+func (h *Handlers) GetControlProvider(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+
+	provider, ok := h.GapAnalyzer.GetProvider(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "control provider not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, provider)
+}
+
+// RegisterControlProvider creates or replaces a common control provider.
+func (h *Handlers) RegisterControlProvider(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+
+	var provider models.ControlProvider
+	if err := c.ShouldBindJSON(&provider); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.GapAnalyzer.RegisterProvider(&provider); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid control provider", "details": err.Error()})
+		return
+	}
+
+	log.Info().Str("provider_id", provider.ID).Int("controls", len(provider.Controls)).Msg("control provider registered")
+	c.JSON(http.StatusCreated, &provider)
+}
+
+// DeleteControlProvider removes a common control provider.
+func (h *Handlers) DeleteControlProvider(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+
+	if !h.GapAnalyzer.RemoveProvider(c.Param("id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "control provider not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMonitoringStatus returns the latest continuous monitoring check results
+// and the control status derived from them.
+func (h *Handlers) GetMonitoringStatus(c *gin.Context) {
+	monitor := h.monitor()
+	if monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control monitoring not enabled"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"checks":   monitor.Results(),
+		"controls": monitor.ControlStatuses(),
+	})
+}
+
+// RunMonitoringChecks runs all continuous monitoring checks immediately.
+func (h *Handlers) RunMonitoringChecks(c *gin.Context) {
+	monitor := h.monitor()
+	if monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control monitoring not enabled"})
+		return
+	}
+
+	results := monitor.RunAll(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{
+		"checks": results,
+		"total":  len(results),
+	})
+}
+
+func (h *Handlers) monitor() *controls.Monitor {
+	if h.GapAnalyzer == nil {
+		return nil
+	}
+	return h.GapAnalyzer.Monitor()
+}
@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestGapAnalysesScopedToOrganization(t *testing.T) {
+	analyses := memory.NewGapAnalysisRepository()
+	ctx := context.Background()
+	for _, ga := range []models.GapAnalysis{
+		{ID: "acme-run", OrganizationID: "acme", TargetFrameworkID: "iso-42001", AnalysisDate: time.Now()},
+		{ID: "globex-run", OrganizationID: "globex", TargetFrameworkID: "iso-42001", AnalysisDate: time.Now()},
+	} {
+		if err := analyses.Create(ctx, &ga); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		GapAnalyses: analyses,
+		APIKeys:     mustKeys(t, apikey.Key{ID: "acme", Token: "acme-token", Org: "acme"}),
+	})
+
+	w := do(srv, http.MethodGet, "/api/v1/controls/gaps", "acme-token", nil)
+	list := decode[struct {
+		Analyses []models.GapAnalysis `json:"analyses"`
+	}](t, w)
+	if w.Code != http.StatusOK || len(list.Analyses) != 1 || list.Analyses[0].ID != "acme-run" {
+		t.Errorf("list = %d %s, want only acme's analysis", w.Code, w.Body)
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/controls/gaps?org=globex", "acme-token", nil); w.Code != http.StatusForbidden {
+		t.Errorf("listing another organization = %d, want 403", w.Code)
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/controls/gaps/acme-run", "acme-token", nil); w.Code != http.StatusOK {
+		t.Errorf("own analysis = %d, want 200", w.Code)
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/controls/gaps/globex-run", "acme-token", nil); w.Code != http.StatusNotFound {
+		t.Errorf("another organization's analysis = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// recordViolations persists the rules behind a denied decision, including
+// denials a monitor-only profile let through, off the request path.
+func recordViolations(c *gin.Context, w repository.ViolationWriter, input *opa.EvaluationInput, d *opa.Decision) {
+	monitored, _ := d.Metadata["would_deny"].(bool)
+	if d.Allow && !monitored || len(d.Violations) == 0 {
+		return
+	}
+	tool := ""
+	if input.Tool != nil {
+		tool = input.Tool.Name
+	}
+	now := time.Now().UTC()
+	violations := make([]repository.PolicyViolation, 0, len(d.Violations))
+	for _, v := range d.Violations {
+		violations = append(violations, repository.PolicyViolation{
+			Timestamp: now,
+			AgentID:   input.Agent.ID,
+			Tool:      tool,
+			Policy:    v.Policy,
+			Rule:      v.Rule,
+			Severity:  v.Severity,
+			Enforced:  !monitored,
+		})
+	}
+	org := c.GetString(orgKey)
+	ctx := context.WithoutCancel(c.Request.Context())
+	go func() {
+		if err := w.InsertViolations(ctx, org, violations); err != nil {
+			log.Error().Err(err).Str("agent_id", input.Agent.ID).Msg("failed to record policy violations")
+		}
+	}()
+}
+
+// heatmapCell is the denial count for one rule, agent, and day.
+type heatmapCell struct {
+	Policy  string    `json:"policy"`
+	Rule    string    `json:"rule"`
+	AgentID string    `json:"agent_id"`
+	Day     time.Time `json:"day"`
+	Denials float64   `json:"denials"`
+}
+
+// heatmapRule totals a rule's denials. Rules denying many distinct agents
+// are candidates for being too broad.
+type heatmapRule struct {
+	Policy  string  `json:"policy"`
+	Rule    string  `json:"rule"`
+	Denials float64 `json:"denials"`
+	Agents  int     `json:"agents"`
+	Days    int     `json:"days"`
+}
+
+// makeDenialHeatmapHandler serves GET /observe/denials/heatmap: denials per
+// Rego rule, agent, and day, with per-rule totals sorted by how many agents
+// each rule denied.
+//
+// Query parameters:
+//   - from, to: RFC 3339 timestamps (default the last 30 days)
+//   - agent_id: restrict to one agent
+func makeDenialHeatmapHandler(repo repository.MetricsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := repository.MetricsQuery{
+			OrgID:    c.GetString(orgKey),
+			Metric:   repository.MetricRuleDenials,
+			Interval: "day",
+			GroupBy:  []string{"policy", "rule", "agent"},
+		}
+		var ok bool
+		if q.From, q.To, ok = parseTimeRange(c, 30*24*time.Hour); !ok {
+			return
+		}
+		if v := c.Query("agent_id"); v != "" {
+			if !validateID(v) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent_id"})
+				return
+			}
+			q.AgentID = &v
+		}
+
+		points, err := repo.Rollup(c.Request.Context(), &q)
+		if err != nil {
+			respondRepoError(c, err, "failed to query denials")
+			return
+		}
+
+		type ruleKey struct{ policy, rule string }
+		type ruleAgg struct {
+			denials float64
+			agents  map[string]bool
+			days    map[time.Time]bool
+		}
+		rules := map[ruleKey]*ruleAgg{}
+		cells := make([]heatmapCell, 0, len(points))
+		for _, p := range points {
+			cell := heatmapCell{
+				Policy:  p.Group["policy"],
+				Rule:    p.Group["rule"],
+				AgentID: p.Group["agent"],
+				Day:     p.Bucket,
+				Denials: p.Value,
+			}
+			cells = append(cells, cell)
+
+			k := ruleKey{cell.Policy, cell.Rule}
+			agg := rules[k]
+			if agg == nil {
+				agg = &ruleAgg{agents: map[string]bool{}, days: map[time.Time]bool{}}
+				rules[k] = agg
+			}
+			agg.denials += cell.Denials
+			agg.agents[cell.AgentID] = true
+			agg.days[cell.Day] = true
+		}
+
+		totals := make([]heatmapRule, 0, len(rules))
+		for k, agg := range rules {
+			totals = append(totals, heatmapRule{
+				Policy:  k.policy,
+				Rule:    k.rule,
+				Denials: agg.denials,
+				Agents:  len(agg.agents),
+				Days:    len(agg.days),
+			})
+		}
+		sort.Slice(totals, func(i, j int) bool {
+			if totals[i].Agents != totals[j].Agents {
+				return totals[i].Agents > totals[j].Agents
+			}
+			if totals[i].Denials != totals[j].Denials {
+				return totals[i].Denials > totals[j].Denials
+			}
+			return totals[i].Policy+totals[i].Rule < totals[j].Policy+totals[j].Rule
+		})
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":  q.From,
+			"to":    q.To,
+			"rules": totals,
+			"cells": cells,
+		})
+	}
+}
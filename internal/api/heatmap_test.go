@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// metricsRepo returns fixed points and records the last query.
+type metricsRepo struct {
+	points []repository.MetricPoint
+	err    error
+	query  *repository.MetricsQuery
+}
+
+func (r *metricsRepo) Rollup(_ context.Context, q *repository.MetricsQuery) ([]repository.MetricPoint, error) {
+	r.query = q
+	return r.points, r.err
+}
+
+func TestDenialHeatmap(t *testing.T) {
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	point := func(rule, agent string, days int, denials float64) repository.MetricPoint {
+		return repository.MetricPoint{
+			Bucket: day.AddDate(0, 0, days),
+			Group:  map[string]string{"policy": "tools", "rule": rule, "agent": agent},
+			Value:  denials,
+		}
+	}
+	repo := &metricsRepo{points: []repository.MetricPoint{
+		point("deny_shell", "support-bot", 0, 9),
+		point("deny_shell", "support-bot", 1, 3),
+		point("deny_egress", "support-bot", 0, 1),
+		point("deny_egress", "billing-bot", 0, 1),
+		point("deny_egress", "triage-bot", 1, 2),
+	}}
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		Metrics: repo,
+		APIKeys: mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+
+	w := do(srv, http.MethodGet, "/api/v1/observe/denials/heatmap", "ci-token", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("heatmap = %d %s, want 200", w.Code, w.Body)
+	}
+	q := repo.query
+	if q.OrgID != "acme" || q.Metric != repository.MetricRuleDenials || q.Interval != "day" ||
+		q.AgentID != nil || q.To.Sub(q.From) != 30*24*time.Hour {
+		t.Errorf("query = %+v, want acme's daily rule denials over 30 days", q)
+	}
+	type rule struct {
+		Rule    string
+		Denials float64
+		Agents  int
+		Days    int
+	}
+	got := decode[struct {
+		Rules []rule
+		Cells []struct{ Rule string }
+	}](t, w)
+	want := []rule{{"deny_egress", 4, 3, 2}, {"deny_shell", 12, 1, 2}}
+	if len(got.Cells) != 5 || len(got.Rules) != len(want) {
+		t.Fatalf("heatmap = %+v, want 5 cells and rules %+v", got, want)
+	}
+	for i := range want {
+		if got.Rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, got.Rules[i], want[i])
+		}
+	}
+
+	if w := do(srv, http.MethodGet, "/api/v1/observe/denials/heatmap?agent_id=support-bot", "ci-token", nil); w.Code != http.StatusOK ||
+		repo.query.AgentID == nil || *repo.query.AgentID != "support-bot" {
+		t.Errorf("heatmap for support-bot = %d, query %+v, want it filtered to the agent", w.Code, repo.query)
+	}
+
+	for name, query := range map[string]string{
+		"a malformed agent_id": "agent_id=bad%20agent",
+		"a malformed from":     "from=yesterday",
+		"an inverted range":    "from=2026-05-02T00:00:00Z&to=2026-05-01T00:00:00Z",
+	} {
+		if w := do(srv, http.MethodGet, "/api/v1/observe/denials/heatmap?"+query, "ci-token", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("heatmap with %s = %d, want 400", name, w.Code)
+		}
+	}
+
+	repo.err = errors.New("clickhouse unavailable")
+	if w := do(srv, http.MethodGet, "/api/v1/observe/denials/heatmap", "ci-token", nil); w.Code != http.StatusInternalServerError {
+		t.Errorf("heatmap on a store error = %d, want 500", w.Code)
+	}
+}
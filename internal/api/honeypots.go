@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/honeypot"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// honeypotReason is what a caller of a decoy is told. It reads like any
+// unavailable tool so a hijacked agent is not warned that it was caught.
+const honeypotReason = "tool is not available to this agent"
+
+func makeListHoneypotsHandler(reg *honeypot.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list := reg.List(c.Query("agent_id"))
+		c.JSON(http.StatusOK, gin.H{"honeypots": list, "total": len(list)})
+	}
+}
+
+func makeGetHoneypotHandler(reg *honeypot.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d, err := reg.Get(c.Param("id"))
+		if errors.Is(err, honeypot.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "honeypot not found"})
+			return
+		}
+		c.JSON(http.StatusOK, d)
+	}
+}
+
+func makePutHoneypotHandler(reg *honeypot.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var d honeypot.Decoy
+		if err := c.ShouldBindJSON(&d); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		d.ID = c.Param("id")
+		if err := reg.Put(d); err != nil {
+			respondHoneypotError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, d)
+	}
+}
+
+func makeDeleteHoneypotHandler(reg *honeypot.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := reg.Delete(c.Param("id")); err != nil {
+			respondHoneypotError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func respondHoneypotError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, honeypot.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "honeypot not found"})
+	case errors.Is(err, honeypot.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "honeypot conflict", "details": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid honeypot", "details": err.Error()})
+	}
+}
+
+// springHoneypot denies a pre-invoke call to a decoy tool and raises a
+// critical signal, which the response engine acts on. It reports whether
+// the call hit a decoy and has been answered.
+func springHoneypot(c *gin.Context, deps *RouterDeps, input *opa.EvaluationInput) bool {
+	if deps == nil || deps.Honeypots == nil || input.Tool == nil {
+		return false
+	}
+	d, ok := deps.Honeypots.Match(input.Agent.ID, input.Tool.Name)
+	if !ok {
+		return false
+	}
+
+	evidence := map[string]any{
+		"honeypot_id": d.ID,
+		"tool_name":   input.Tool.Name,
+		"parameters":  input.Tool.Parameters,
+		"environment": input.Agent.Environment,
+		"risk_level":  input.Agent.RiskLevel,
+	}
+	if input.Request != nil {
+		evidence["session_id"] = input.Request.SessionID
+		evidence["user_id"] = input.Request.UserID
+		evidence["prompt_hash"] = input.Request.PromptHash
+	}
+	if input.Data != nil && input.Data.Classification != "" {
+		evidence["data_classification"] = input.Data.Classification
+	}
+	sig := models.SecuritySignal{
+		ID:          uuid.NewString(),
+		Type:        models.SignalHoneypotTriggered,
+		Severity:    "critical",
+		Title:       "Agent attempted to call a honeypot tool",
+		Description: "agent " + input.Agent.ID + " attempted to call decoy tool " + input.Tool.Name,
+		Evidence:    evidence,
+		Timestamp:   time.Now().UTC(),
+	}
+	orgID := c.GetString(orgKey)
+	log.Warn().Str("org_id", orgID).Str("agent_id", input.Agent.ID).Str("tool", input.Tool.Name).
+		Str("honeypot_id", d.ID).Str("signal_id", sig.ID).Msg("honeypot tool invoked")
+	dispatchSignal(c, deps, orgID, input.Agent.ID, sig)
+
+	auditDecision(c, deps, input, &opa.Decision{
+		Reasons:  []string{"honeypot tool invoked"},
+		Metadata: map[string]any{"honeypot_id": d.ID, "signal_id": sig.ID},
+	})
+	c.JSON(http.StatusForbidden, gin.H{
+		"allow":   false,
+		"reasons": []string{honeypotReason},
+	})
+	return true
+}
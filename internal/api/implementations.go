@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ControlImplementationRequest creates or updates a tracked control
+// implementation. FrameworkID and ControlID are fixed once created.
+type ControlImplementationRequest struct {
+	FrameworkID string                      `json:"framework_id"`
+	ControlID   string                      `json:"control_id"`
+	Status      models.ImplementationStatus `json:"status"`
+	Owner       string                      `json:"owner"`
+	// DueDate is a calendar date, YYYY-MM-DD. Empty clears it.
+	DueDate string `json:"due_date"`
+	Notes   string `json:"notes"`
+}
+
+// apply validates the mutable fields and copies them to ci. It returns a
+// message describing the first invalid field.
+func (req *ControlImplementationRequest) apply(ci *models.ControlImplementation) string {
+	status := req.Status
+	if status == "" {
+		status = models.ImplementationPlanned
+	}
+	if !controls.ValidImplementationStatus(status) {
+		return "status must be planned, in_progress, implemented or verified"
+	}
+	var due *time.Time
+	if req.DueDate != "" {
+		d, err := time.Parse(time.DateOnly, req.DueDate)
+		if err != nil {
+			return "due_date must be a date as YYYY-MM-DD"
+		}
+		due = &d
+	}
+	ci.Status, ci.Owner, ci.DueDate, ci.Notes = status, strings.TrimSpace(req.Owner), due, req.Notes
+	return ""
+}
+
+// ListImplementations returns the caller's tracked control
+// implementations, optionally filtered by the framework and status query
+// parameters. The as_of query parameter returns them as they stood at a
+// past date or time.
+func (h *Handlers) ListImplementations(c *gin.Context) {
+	if h.Implementations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control implementation tracking not configured"})
+		return
+	}
+	asOf, ok := asOfParam(c)
+	if !ok {
+		return
+	}
+	if !asOf.IsZero() {
+		if _, ok := h.implementationHistory(c); !ok {
+			return
+		}
+	}
+	org := c.GetString(orgKey)
+	impls, err := h.listImplementations(c.Request.Context(), org, c.Query("framework"), asOf)
+	if err != nil {
+		respondRepoError(c, err, "failed to list control implementations")
+		return
+	}
+	if status := models.ImplementationStatus(c.Query("status")); status != "" {
+		filtered := impls[:0]
+		for _, ci := range impls {
+			if ci.Status == status {
+				filtered = append(filtered, ci)
+			}
+		}
+		impls = filtered
+	}
+	if impls == nil {
+		impls = []models.ControlImplementation{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"organization_id": org,
+		"implementations": impls,
+		"total":           len(impls),
+	})
+}
+
+// GetImplementation returns one of the caller's tracked control
+// implementations.
+func (h *Handlers) GetImplementation(c *gin.Context) {
+	ci, ok := h.loadImplementation(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, ci)
+}
+
+// CreateImplementation starts tracking a framework control for the
+// caller's organization. The status defaults to planned.
+func (h *Handlers) CreateImplementation(c *gin.Context) {
+	if h.Implementations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control implementation tracking not configured"})
+		return
+	}
+	var req ControlImplementationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if !validFrameworkID.MatchString(req.FrameworkID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	ctx := c.Request.Context()
+	controlID, ok := h.resolveControlID(ctx, req.FrameworkID, req.ControlID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown control", "details": req.FrameworkID + ":" + req.ControlID})
+		return
+	}
+
+	ci := &models.ControlImplementation{
+		ID:             uuid.NewString(),
+		OrganizationID: c.GetString(orgKey),
+		FrameworkID:    req.FrameworkID,
+		ControlID:      controlID,
+	}
+	if msg := req.apply(ci); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if err := h.Implementations.Create(ctx, ci); err != nil {
+		respondRepoError(c, err, "failed to create control implementation")
+		return
+	}
+	c.JSON(http.StatusCreated, ci)
+}
+
+// UpdateImplementation replaces the status, owner, due date and notes of a
+// tracked control implementation.
+func (h *Handlers) UpdateImplementation(c *gin.Context) {
+	ci, ok := h.loadImplementation(c)
+	if !ok {
+		return
+	}
+	var req ControlImplementationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if msg := req.apply(ci); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if err := h.Implementations.Update(c.Request.Context(), ci); err != nil {
+		respondRepoError(c, err, "failed to update control implementation")
+		return
+	}
+	c.JSON(http.StatusOK, ci)
+}
+
+// DeleteImplementation stops tracking a control implementation.
+func (h *Handlers) DeleteImplementation(c *gin.Context) {
+	ci, ok := h.loadImplementation(c)
+	if !ok {
+		return
+	}
+	if err := h.Implementations.Delete(c.Request.Context(), ci.ID); err != nil {
+		respondRepoError(c, err, "failed to delete control implementation")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadImplementation fetches the implementation named by the id path
+// parameter, responding 404 when it is missing or belongs to another
+// organization.
+func (h *Handlers) loadImplementation(c *gin.Context) (*models.ControlImplementation, bool) {
+	if h.Implementations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control implementation tracking not configured"})
+		return nil, false
+	}
+	id := c.Param("id")
+	if !validateID(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid implementation ID format"})
+		return nil, false
+	}
+	ci, err := h.Implementations.Get(c.Request.Context(), id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get control implementation")
+		return nil, false
+	}
+	if ci == nil || ci.OrganizationID != c.GetString(orgKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "control implementation not found"})
+		return nil, false
+	}
+	return ci, true
+}
+
+// resolveControlID returns the catalog spelling of a framework control ID,
+// matched case-insensitively.
+func (h *Handlers) resolveControlID(ctx context.Context, framework, controlID string) (string, bool) {
+	controlID = strings.TrimSpace(controlID)
+	if controlID == "" {
+		return "", false
+	}
+	for _, ctrl := range h.frameworkControls(ctx, framework) {
+		if strings.EqualFold(ctrl.ControlID, controlID) {
+			return ctrl.ControlID, true
+		}
+	}
+	return "", false
+}
+
+// trackedControls returns the organization's implemented and verified
+// controls in the given frameworks, as they stood at asOf unless it is
+// zero, for gap analyses that do not list their implemented controls.
+func (h *Handlers) trackedControls(ctx context.Context, org string, asOf time.Time, frameworks ...string) ([]string, error) {
+	impls, err := h.listImplementations(ctx, org, "", asOf)
+	if err != nil {
+		return nil, err
+	}
+	in := impls[:0]
+	for _, ci := range impls {
+		for _, fw := range frameworks {
+			if fw != "" && ci.FrameworkID == fw {
+				in = append(in, ci)
+				break
+			}
+		}
+	}
+	return controls.TrackedImplementedControls(in), nil
+}
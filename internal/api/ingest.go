@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/apm"
+	"github.com/agentguard/agentguard/internal/ingest"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// makeIngestTraceHandler serves POST /observe/traces. The trace runs through
+// the ingest pipeline before it is written; validation failures return 400
+// with every problem found. The response is sent once the trace is stored;
+// when the write queue is full it is 503 with Retry-After and the trace
+// should be resubmitted.
+// Response actions for the trace's signals run after the write, off the
+// request path, and the signals are published to monitoring platforms;
+// resp and exports may be nil.
+func makeIngestTraceHandler(p *ingest.Pipeline, w repository.TraceWriter, resp *response.Engine, exports *apm.Publisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var trace models.AgentTrace
+		if err := c.ShouldBindJSON(&trace); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+
+		org := c.GetString(orgKey)
+		report, err := p.Process(org, &trace)
+		if err != nil {
+			var verr *ingest.ValidationError
+			if errors.As(err, &verr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid trace", "details": verr.Problems})
+				return
+			}
+			log.Error().Err(err).Str("trace_id", trace.TraceID).Msg("trace ingest failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process trace"})
+			return
+		}
+
+		// Fully duplicate submissions are acknowledged without a write.
+		if len(trace.Spans) == 0 && len(trace.SecuritySignals) == 0 && report.DuplicateSpans+report.DuplicateSignals > 0 {
+			c.JSON(http.StatusOK, report)
+			return
+		}
+
+		p.StorePayloads(c.Request.Context(), org, &trace, report)
+		if err := w.InsertTrace(c.Request.Context(), org, &trace); err != nil {
+			if errors.Is(err, ingest.ErrBackpressure) || errors.Is(err, ingest.ErrWriterStopped) {
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "trace ingest overloaded", "details": err.Error()})
+				return
+			}
+			log.Error().Err(err).Str("trace_id", trace.TraceID).Msg("failed to persist trace")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to persist trace"})
+			return
+		}
+		p.Commit(org, &trace)
+		if exports != nil && len(trace.SecuritySignals) > 0 {
+			agentID := ""
+			if trace.AgentID != uuid.Nil {
+				agentID = trace.AgentID.String()
+			}
+			exports.Publish(c.Request.Context(), org, agentID, trace.SecuritySignals...)
+		}
+
+		if resp != nil && len(trace.SecuritySignals) > 0 {
+			ctx := context.WithoutCancel(c.Request.Context())
+			go resp.HandleTrace(ctx, org, &trace)
+		}
+
+		c.JSON(http.StatusAccepted, report)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/gin-gonic/gin"
+)
+
+// acceptJob responds 202 with the queued job and a Location header for polling.
+func acceptJob(c *gin.Context, job *jobs.Job) {
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// wantsAsync reports whether the client asked for asynchronous execution.
+func wantsAsync(c *gin.Context) bool {
+	switch c.Query("async") {
+	case "true", "1":
+		return true
+	}
+	return c.GetHeader("Prefer") == "respond-async"
+}
+
+// jobOwner identifies the caller as the owner of the jobs it submits.
+func jobOwner(c *gin.Context) jobs.Owner {
+	return jobs.Owner{OrgID: c.GetString(orgKey), Actor: requestActor(c)}
+}
+
+// submitJob queues fn as a job owned by the caller. scope is the scope of
+// the submitting route, which is needed again to read or cancel the job.
+func submitJob(c *gin.Context, m *jobs.Manager, jobType, scope string, fn jobs.Func) (*jobs.Job, error) {
+	return m.Submit(jobOwner(c), jobType, scope, fn)
+}
+
+// ownJob fetches the caller's job named by the id path parameter,
+// responding 404 when there is none and 403 when the caller lacks the
+// job's scope.
+func ownJob(c *gin.Context, m *jobs.Manager, provider string) (*jobs.Job, bool) {
+	job, err := m.Get(jobOwner(c), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return nil, false
+	}
+	if !hasScope(c, provider, job.Scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient scope", "required": job.Scope})
+		return nil, false
+	}
+	return job, true
+}
+
+// makeListJobsHandler lists the caller's jobs that it has the scope to read.
+func makeListJobsHandler(m *jobs.Manager, provider string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list := make([]*jobs.Job, 0)
+		for _, job := range m.List(jobOwner(c), c.Query("type")) {
+			if hasScope(c, provider, job.Scope) {
+				list = append(list, job)
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"jobs":  list,
+			"total": len(list),
+		})
+	}
+}
+
+func makeGetJobHandler(m *jobs.Manager, provider string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if job, ok := ownJob(c, m, provider); ok {
+			c.JSON(http.StatusOK, job)
+		}
+	}
+}
+
+func makeGetJobResultHandler(m *jobs.Manager, provider string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := ownJob(c, m, provider); !ok {
+			return
+		}
+		result, job, err := m.Result(jobOwner(c), c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
+		switch job.Status {
+		case jobs.StatusSucceeded:
+			c.JSON(http.StatusOK, result)
+		case jobs.StatusFailed, jobs.StatusCancelled:
+			c.JSON(http.StatusConflict, gin.H{"error": "job did not succeed", "status": job.Status, "details": job.Error})
+		default:
+			c.Header("Retry-After", "2")
+			c.JSON(http.StatusAccepted, job)
+		}
+	}
+}
+
+func makeCancelJobHandler(m *jobs.Manager, provider string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := ownJob(c, m, provider); !ok {
+			return
+		}
+		job, err := m.Cancel(jobOwner(c), c.Param("id"))
+		if errors.Is(err, jobs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	}
+}
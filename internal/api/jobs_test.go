@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestJobsVisibleToSubmitter(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := jobs.NewManager(jobs.Config{Workers: 1})
+	t.Cleanup(m.Stop)
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		GapAnalyzer: analyzer,
+		Jobs:        m,
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"},
+			apikey.Key{ID: "colleague", Token: "colleague-token", Org: "acme"},
+			apikey.Key{ID: "globex", Token: "globex-token", Org: "globex"},
+		),
+	})
+
+	w := do(srv, http.MethodPost, "/api/v1/controls/gaps/analyze?async=true", "ci-token",
+		map[string]any{"target_framework": "nist-ai-rmf", "implemented_controls": []string{}})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("submit = %d %s, want 202", w.Code, w.Body)
+	}
+	job := decode[jobs.Job](t, w)
+	if job.OrgID != "acme" || job.SubmittedBy != "api_key:ci" || job.Scope != "write:controls" {
+		t.Errorf("job = %+v, want acme's, by api_key:ci, needing write:controls", job)
+	}
+
+	if w := do(srv, http.MethodGet, "/api/v1/jobs/"+job.ID, "ci-token", nil); w.Code != http.StatusOK {
+		t.Errorf("submitter reading the job = %d, want 200", w.Code)
+	}
+	for _, token := range []string{"colleague-token", "globex-token"} {
+		for _, req := range []struct{ method, path string }{
+			{http.MethodGet, "/api/v1/jobs/" + job.ID},
+			{http.MethodGet, "/api/v1/jobs/" + job.ID + "/result"},
+			{http.MethodDelete, "/api/v1/jobs/" + job.ID},
+		} {
+			if w := do(srv, req.method, req.path, token, nil); w.Code != http.StatusNotFound {
+				t.Errorf("%s %s with %s = %d, want 404", req.method, req.path, token, w.Code)
+			}
+		}
+		list := decode[struct{ Total int }](t, do(srv, http.MethodGet, "/api/v1/jobs", token, nil))
+		if list.Total != 0 {
+			t.Errorf("jobs listed for %s = %d, want none", token, list.Total)
+		}
+	}
+}
+
+// awaitJob polls the result of job id until it is no longer pending and
+// returns the final response.
+func awaitJob(t *testing.T, h http.Handler, token, id string) *httptest.ResponseRecorder {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		w := do(h, http.MethodGet, "/api/v1/jobs/"+id+"/result", token, nil)
+		if w.Code != http.StatusAccepted {
+			return w
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s still pending", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// withLicensing copies a built-in framework's licensing onto a stored copy
+// of it, which does not persist it.
+func (h *Handlers) withLicensing(fw *models.Framework) {
+	if h.GapAnalyzer == nil || fw.License != "" {
+		return
+	}
+	if loaded, ok := h.GapAnalyzer.Framework(fw.ID); ok {
+		fw.License, fw.TextUnavailable = loaded.License, loaded.TextUnavailable
+	}
+}
+
+// requireFullText handles text=full on control reads: a restricted
+// catalog listed without its text responds 451 naming where its licensed
+// copy goes. Controls that carry text, such as a licensed copy imported
+// into the repository, pass. ok is false after responding.
+func (h *Handlers) requireFullText(c *gin.Context, framework string, list []models.Control) bool {
+	if c.Query("text") != "full" || h.GapAnalyzer == nil {
+		return true
+	}
+	err := h.GapAnalyzer.RequireFullText(framework)
+	if err == nil || slices.ContainsFunc(list, func(ctrl models.Control) bool { return ctrl.Description != "" }) {
+		return true
+	}
+	c.JSON(http.StatusUnavailableForLegalReasons, gin.H{"error": "licensed catalog text not available", "details": err.Error()})
+	return false
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// maxMetricsRange bounds a single metrics query.
+const maxMetricsRange = 366 * 24 * time.Hour
+
+// makeMetricsHandler serves GET /observe/metrics from pre-aggregated rollups.
+//
+// Query parameters:
+//   - metric: tokens, prompt_tokens, completion_tokens, llm_calls, denials, rule_denials, signals (default tokens)
+//   - from, to: RFC 3339 timestamps (default the last 24 hours)
+//   - interval: hour, day, week (default hour for token metrics, day otherwise)
+//   - group_by: comma-separated dimensions, e.g. model,agent or severity
+//   - agent_id: restrict to one agent
+func makeMetricsHandler(repo repository.MetricsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := repository.MetricsQuery{
+			OrgID:    c.GetString(orgKey),
+			Metric:   c.DefaultQuery("metric", repository.MetricTokens),
+			Interval: c.Query("interval"),
+		}
+
+		var ok bool
+		if q.From, q.To, ok = parseTimeRange(c, 24*time.Hour); !ok {
+			return
+		}
+
+		if q.Interval == "" {
+			q.Interval = "day"
+			switch q.Metric {
+			case repository.MetricTokens, repository.MetricPromptTokens, repository.MetricCompletionTokens, repository.MetricLLMCalls:
+				q.Interval = "hour"
+			}
+		}
+		if v := c.Query("group_by"); v != "" {
+			for _, dim := range strings.Split(v, ",") {
+				if dim = strings.TrimSpace(dim); dim != "" {
+					q.GroupBy = append(q.GroupBy, dim)
+				}
+			}
+		}
+		if v := c.Query("agent_id"); v != "" {
+			if !validateID(v) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent_id"})
+				return
+			}
+			q.AgentID = &v
+		}
+
+		points, err := repo.Rollup(c.Request.Context(), &q)
+		if err != nil {
+			respondRepoError(c, err, "failed to query metrics")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"metric":   q.Metric,
+			"interval": q.Interval,
+			"from":     q.From,
+			"to":       q.To,
+			"group_by": q.GroupBy,
+			"points":   points,
+		})
+	}
+}
+
+// parseTimeRange reads the from and to query parameters as RFC 3339
+// timestamps. to defaults to now and from to def before to. On invalid input
+// it writes a 400 response and returns false.
+func parseTimeRange(c *gin.Context, def time.Duration) (from, to time.Time, ok bool) {
+	to = time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to", "details": "expected RFC 3339 timestamp"})
+			return from, to, false
+		}
+		to = t
+	}
+	from = to.Add(-def)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from", "details": "expected RFC 3339 timestamp"})
+			return from, to, false
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time range", "details": "from must be before to"})
+		return from, to, false
+	}
+	if to.Sub(from) > maxMetricsRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time range", "details": "range exceeds one year"})
+		return from, to, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/onboarding"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// makeAgentOnboardingHandler returns the agent's onboarding checklist with
+// its percent complete.
+func makeAgentOnboardingHandler(deps *RouterDeps) gin.HandlerFunc {
+	checker := &onboarding.Checker{ThreatModels: deps.ThreatModels, Activity: deps.AgentActivity}
+	if deps.PolicyEngine != nil {
+		checker.Policy = deps.PolicyEngine
+	}
+	return func(c *gin.Context) {
+		if deps.Agents == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent registry not configured"})
+			return
+		}
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent ID format"})
+			return
+		}
+		ctx := c.Request.Context()
+		agent, err := deps.Agents.Get(ctx, id)
+		if err != nil {
+			respondRepoError(c, err, "failed to get agent")
+			return
+		}
+		if agent == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "agent not found"})
+			return
+		}
+		checklist, err := checker.Check(ctx, c.GetString(orgKey), agent)
+		if err != nil {
+			respondRepoError(c, err, "failed to compute onboarding checklist")
+			return
+		}
+		c.JSON(http.StatusOK, checklist)
+	}
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/outputs"
+	"github.com/agentguard/agentguard/internal/severity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func makeListOutputSchemasHandler(reg *outputs.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list := reg.List()
+		c.JSON(http.StatusOK, gin.H{"schemas": list, "total": len(list)})
+	}
+}
+
+func makeGetOutputSchemaHandler(reg *outputs.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, err := reg.Get(c.Param("id"))
+		if errors.Is(err, outputs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "output schema not found"})
+			return
+		}
+		c.JSON(http.StatusOK, s)
+	}
+}
+
+func makePutOutputSchemaHandler(reg *outputs.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var s outputs.Schema
+		if err := c.ShouldBindJSON(&s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		s.ID = c.Param("id")
+		if err := reg.Put(c.Request.Context(), s); err != nil {
+			respondOutputSchemaError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, s)
+	}
+}
+
+func makeDeleteOutputSchemaHandler(reg *outputs.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := reg.Delete(c.Param("id")); err != nil {
+			respondOutputSchemaError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func respondOutputSchemaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, outputs.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "output schema not found"})
+	case errors.Is(err, outputs.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "output schema conflict", "details": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid output schema", "details": err.Error()})
+	}
+}
+
+// postInvokeRequest is the SDK report of a completed tool call.
+type postInvokeRequest struct {
+	AgentID string `json:"agent_id"`
+	Tool    struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+	} `json:"tool"`
+	// Output is the structured output to check, such as the function call
+	// arguments produced by the model. Optional.
+	Output     json.RawMessage `json:"output,omitempty"`
+	ResultHash string          `json:"result_hash,omitempty"`
+	DurationMs int64           `json:"duration_ms,omitempty"`
+	SessionID  string          `json:"session_id,omitempty"`
+}
+
+// makePostInvokeHook acknowledges completed tool calls and checks the
+// reported output: canary tokens in it raise exfiltration signals, and when
+// output schemas are registered it is validated. Outputs failing a blocking
+// schema are answered with 403 so the SDK discards them.
+func makePostInvokeHook(deps *RouterDeps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps == nil || (deps.OutputSchemas == nil && deps.Canaries == nil) {
+			c.JSON(http.StatusAccepted, gin.H{"status": "acknowledged"})
+			return
+		}
+
+		var req postInvokeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		if !bindWorkloadAgent(c, deps, &req.AgentID) {
+			return
+		}
+		body := gin.H{"status": "acknowledged"}
+		if req.Output == nil {
+			c.JSON(http.StatusAccepted, body)
+			return
+		}
+
+		if deps.Canaries != nil {
+			if ids := recordCanaryLeaks(c, deps, &req); len(ids) > 0 {
+				body["status"] = "flagged"
+				body["canary_signal_ids"] = ids
+			}
+		}
+		if deps.OutputSchemas == nil {
+			c.JSON(http.StatusAccepted, body)
+			return
+		}
+
+		res, err := deps.OutputSchemas.Validate(c.Request.Context(), req.AgentID, req.Tool.Name, req.Output)
+		if err != nil {
+			log.Error().Err(err).Str("agent_id", req.AgentID).Str("tool", req.Tool.Name).Msg("output validation failed")
+			c.JSON(http.StatusAccepted, body)
+			return
+		}
+		if res == nil {
+			c.JSON(http.StatusAccepted, body)
+			return
+		}
+		body["validation"] = res
+		if res.Valid {
+			c.JSON(http.StatusAccepted, body)
+			return
+		}
+
+		body["status"] = "flagged"
+		body["signal_id"] = recordInvalidOutput(c, deps, &req, res)
+		if res.Blocked() {
+			delete(body, "status")
+			body["allow"] = false
+			body["reasons"] = []string{"output does not match schema " + res.SchemaID}
+			c.JSON(http.StatusForbidden, body)
+			return
+		}
+		c.JSON(http.StatusAccepted, body)
+	}
+}
+
+// recordInvalidOutput raises a signal for an output that failed its schema
+// and returns the signal ID.
+func recordInvalidOutput(c *gin.Context, deps *RouterDeps, req *postInvokeRequest, res *outputs.Result) string {
+	severity := "medium"
+	if res.Blocked() {
+		severity = "high"
+	}
+	sig := models.SecuritySignal{
+		ID:          uuid.NewString(),
+		Type:        models.SignalInvalidOutput,
+		Severity:    severity,
+		Title:       "Agent output failed schema validation",
+		Description: "output of " + req.Tool.Name + " does not match schema " + res.SchemaID,
+		Evidence: map[string]any{
+			"schema_id":  res.SchemaID,
+			"mode":       res.Mode,
+			"tool":       req.Tool.Name,
+			"session_id": req.SessionID,
+			"violations": res.Violations,
+		},
+		Timestamp: time.Now().UTC(),
+	}
+	orgID := c.GetString(orgKey)
+	log.Warn().Str("org_id", orgID).Str("agent_id", req.AgentID).Str("tool", req.Tool.Name).
+		Str("schema_id", res.SchemaID).Str("mode", string(res.Mode)).Msg("agent output failed schema validation")
+
+	dispatchSignal(c, deps, orgID, req.AgentID, sig)
+	return sig.ID
+}
+
+// dispatchSignal re-scores a signal for the agent's asset criticality, then
+// persists it, publishes it to monitoring platforms, and hands it to the
+// response engine, all off the request path.
+func dispatchSignal(c *gin.Context, deps *RouterDeps, orgID, agentID string, sig models.SecuritySignal) {
+	deps.Severity.Rescore(agentID, severity.Asset{}, &sig)
+	deps.SignalExports.Publish(c.Request.Context(), orgID, agentID, sig)
+	if deps.SignalWriter == nil && deps.Response == nil {
+		return
+	}
+	ctx := context.WithoutCancel(c.Request.Context())
+	go func() {
+		if deps.SignalWriter != nil {
+			if err := deps.SignalWriter.InsertSignals(ctx, orgID, agentID, []models.SecuritySignal{sig}); err != nil {
+				log.Error().Err(err).Str("signal_id", sig.ID).Str("type", string(sig.Type)).Msg("failed to persist signal")
+			}
+		}
+		if deps.Response != nil {
+			deps.Response.HandleSignal(ctx, orgID, agentID, &sig)
+		}
+	}()
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// List endpoints share these query parameters:
+//
+//	limit   page size, up to the endpoint's maximum
+//	offset  number of items to skip
+//	cursor  next_cursor of the previous page, in place of offset
+//	sort    field to order by, prefixed with - for descending order
+//
+// Responses carry the items with count, total, limit and offset, plus
+// next_offset and next_cursor while more remain. X-Total-Count repeats the
+// total and Link (RFC 8288) points at the first, prev, next and last pages.
+
+// listSpec describes how a list endpoint pages and sorts.
+type listSpec struct {
+	defaultLimit int
+	maxLimit     int
+	// sorts are the fields ?sort= accepts; none for lists in a fixed order.
+	sorts []string
+}
+
+// listPage is the page of a list a request asks for.
+type listPage struct {
+	limit  int
+	offset int
+	sort   repository.Sort
+}
+
+// listCursor is the decoded form of a cursor token. It carries the sort it
+// was issued for so a cursor is not applied to a differently ordered list.
+type listCursor struct {
+	Offset int    `json:"o"`
+	Sort   string `json:"s,omitempty"`
+}
+
+// parseListPage reads a list request's limit, offset or cursor, and sort,
+// responding 400 and returning false when one is invalid.
+func parseListPage(c *gin.Context, spec listSpec) (listPage, bool) {
+	p := listPage{limit: spec.defaultLimit}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > spec.maxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit", "details": fmt.Sprintf("use 1 to %d", spec.maxLimit)})
+			return p, false
+		}
+		p.limit = n
+	}
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return p, false
+		}
+		p.offset = n
+	}
+
+	sort := c.Query("sort")
+	if v := c.Query("cursor"); v != "" {
+		if c.Query("offset") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor", "details": "use offset or cursor, not both"})
+			return p, false
+		}
+		cur, ok := decodeCursor(v)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return p, false
+		}
+		if sort != "" && sort != cur.Sort {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor", "details": "cursor was issued for another sort"})
+			return p, false
+		}
+		p.offset, sort = cur.Offset, cur.Sort
+	}
+	if sort != "" {
+		field, desc := strings.CutPrefix(sort, "-")
+		if !slices.Contains(spec.sorts, field) {
+			details := "this list cannot be sorted"
+			if len(spec.sorts) > 0 {
+				details = "use " + strings.Join(spec.sorts, ", ") + ", prefixed with - to reverse"
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort", "details": details})
+			return p, false
+		}
+		p.sort = repository.Sort{Field: field, Desc: desc}
+	}
+	return p, true
+}
+
+// sortParam returns the ?sort= value selecting p's sort.
+func (p listPage) sortParam() string {
+	if p.sort.Desc {
+		return "-" + p.sort.Field
+	}
+	return p.sort.Field
+}
+
+// encodeCursor returns the cursor token for the page at offset.
+func encodeCursor(offset int, sort string) string {
+	b, _ := json.Marshal(listCursor{Offset: offset, Sort: sort})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses a cursor token made by encodeCursor.
+func decodeCursor(token string) (listCursor, bool) {
+	var cur listCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(b, &cur) != nil || cur.Offset < 0 {
+		return cur, false
+	}
+	return cur, true
+}
+
+// respondList writes a page of a list holding total items in all: items
+// under key, the paging fields and headers described above, and extra.
+func respondList[T any](c *gin.Context, p listPage, total int, key string, items []T, extra gin.H) {
+	if items == nil {
+		items = []T{}
+	}
+	resp := gin.H{
+		key:      items,
+		"count":  len(items),
+		"total":  total,
+		"limit":  p.limit,
+		"offset": p.offset,
+	}
+	for k, v := range extra {
+		resp[k] = v
+	}
+
+	links := []string{listLink(c, p, 0, "first")}
+	if p.offset > 0 {
+		links = append(links, listLink(c, p, max(p.offset-p.limit, 0), "prev"))
+	}
+	if next := p.offset + p.limit; next < total {
+		resp["next_offset"] = next
+		resp["next_cursor"] = encodeCursor(next, p.sortParam())
+		links = append(links, listLink(c, p, next, "next"))
+	}
+	if total > 0 {
+		links = append(links, listLink(c, p, (total-1)/p.limit*p.limit, "last"))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(http.StatusOK, resp)
+}
+
+// listLink returns a Link header entry for the request's list at offset,
+// keeping its filters, limit and sort.
+func listLink(c *gin.Context, p listPage, offset int, rel string) string {
+	q := c.Request.URL.Query()
+	q.Del("cursor")
+	q.Set("limit", strconv.Itoa(p.limit))
+	q.Set("offset", strconv.Itoa(offset))
+	if s := p.sortParam(); s != "" {
+		q.Set("sort", s)
+	}
+	return fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, q.Encode(), rel)
+}
+
+// pageOf returns the page p selects from items held in memory.
+func pageOf[T any](items []T, p listPage) []T {
+	if p.offset >= len(items) {
+		return nil
+	}
+	items = items[p.offset:]
+	if len(items) > p.limit {
+		items = items[:p.limit]
+	}
+	return items
+}
+
+// sortItems orders items held in memory by p's sort field, using fields to
+// compare them. Ties, and lists without a sort, keep their order.
+func sortItems[T any](items []T, p listPage, fields map[string]func(a, b *T) int) {
+	cmp, ok := fields[p.sort.Field]
+	if !ok {
+		return
+	}
+	slices.SortStableFunc(items, func(a, b T) int {
+		if p.sort.Desc {
+			return cmp(&b, &a)
+		}
+		return cmp(&a, &b)
+	})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// makeGetPayloadHandler serves GET /observe/payloads/:hash, streaming a span
+// payload stored by content hash. Payloads are scoped to the caller's
+// organization; hashes from other organizations return 404.
+func makeGetPayloadHandler(store *storage.ContentStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hash := c.Param("hash")
+		body, err := store.Get(c.Request.Context(), c.GetString(orgKey), hash)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrInvalidHash):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload hash", "details": "expected sha256:<64 hex digits>"})
+			case errors.Is(err, storage.ErrNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "payload not found"})
+			default:
+				log.Error().Err(err).Str("hash", hash).Msg("failed to read payload")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read payload"})
+			}
+			return
+		}
+		defer body.Close()
+		c.Header("Cache-Control", "private, max-age=3600, immutable")
+		c.DataFromReader(http.StatusOK, -1, "application/json", body, nil)
+	}
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Policy list page sizes.
+const (
+	defaultPolicyLimit = 50
+	maxPolicyLimit     = 200
+)
+
+var policyListSpec = listSpec{defaultLimit: defaultPolicyLimit, maxLimit: maxPolicyLimit, sorts: repository.PolicySortFields}
+
+var (
+	policyTypes = []models.PolicyType{
+		models.PolicyTypeToolAccess, models.PolicyTypeDataFlow, models.PolicyTypeHITL,
+		models.PolicyTypeRateLimit, models.PolicyTypeCapability,
+	}
+	policyActionTypes = []string{"allow", "deny", "warn", "audit", "require_approval"}
+)
+
+// PolicyRequest creates or updates a policy.
+type PolicyRequest struct {
+	// ID is optional on create; one is generated when empty. It is ignored
+	// on update.
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Type        models.PolicyType   `json:"type"`
+	Version     string              `json:"version"`
+	Scope       models.PolicyScope  `json:"scope"`
+	Rules       []models.PolicyRule `json:"rules"`
+	// Enabled defaults to true.
+	Enabled  *bool          `json:"enabled"`
+	Priority int            `json:"priority"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// apply validates the request and copies it to p. It returns a message
+// describing the first invalid field.
+func (req *PolicyRequest) apply(p *models.Policy) string {
+	name := strings.TrimSpace(req.Name)
+	switch {
+	case name == "" || len(name) > 255:
+		return "name is required and at most 255 characters"
+	case !slices.Contains(policyTypes, req.Type):
+		return "type must be tool_access, data_flow, human_in_loop, rate_limit or capability"
+	case req.Priority < -1000 || req.Priority > 1000:
+		return "priority must be between -1000 and 1000"
+	}
+	for _, env := range req.Scope.Environments {
+		if !slices.Contains(agentEnvironments, env) {
+			return "scope environments must be dev, staging or prod"
+		}
+	}
+	ruleIDs := make(map[string]bool, len(req.Rules))
+	for _, rule := range req.Rules {
+		id := strings.TrimSpace(rule.ID)
+		if id == "" || ruleIDs[id] {
+			return "every rule needs a unique id"
+		}
+		ruleIDs[id] = true
+		if len(rule.Actions) == 0 {
+			return "rule " + id + " needs at least one action"
+		}
+		for _, action := range rule.Actions {
+			if !slices.Contains(policyActionTypes, action.Type) {
+				return "rule " + id + " action type must be allow, deny, warn, audit or require_approval"
+			}
+		}
+	}
+
+	p.Name, p.Description, p.Type = name, req.Description, req.Type
+	p.Version = strings.TrimSpace(req.Version)
+	p.Scope = req.Scope
+	p.Rules = append([]models.PolicyRule{}, req.Rules...)
+	p.Enabled = req.Enabled == nil || *req.Enabled
+	p.Priority, p.Metadata = req.Priority, req.Metadata
+	return ""
+}
+
+// ListPolicies returns policies, highest priority first, filtered by the
+// type and enabled query parameters, and paged and sorted like the other
+// lists.
+func (h *Handlers) ListPolicies(c *gin.Context) {
+	if h.Policies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "policy store not configured"})
+		return
+	}
+	page, ok := parseListPage(c, policyListSpec)
+	if !ok {
+		return
+	}
+	filters := &repository.PolicyFilters{Sort: page.sort}
+	if v := c.Query("type"); v != "" {
+		t := models.PolicyType(v)
+		if !slices.Contains(policyTypes, t) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type", "details": "use tool_access, data_flow, human_in_loop, rate_limit or capability"})
+			return
+		}
+		filters.Type = &t
+	}
+	if v := c.Query("enabled"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid enabled", "details": "use true or false"})
+			return
+		}
+		filters.Enabled = &enabled
+	}
+
+	ctx := c.Request.Context()
+	total, err := h.Policies.Count(ctx, filters)
+	if err != nil {
+		respondRepoError(c, err, "failed to count policies")
+		return
+	}
+	filters.Offset, filters.Limit = page.offset, page.limit
+	policies, err := h.Policies.List(ctx, filters)
+	if err != nil {
+		respondRepoError(c, err, "failed to list policies")
+		return
+	}
+	respondList(c, page, total, "policies", policies, nil)
+}
+
+// ListPoliciesByType returns every policy of the type path parameter,
+// enabled or not, highest priority first.
+func (h *Handlers) ListPoliciesByType(c *gin.Context) {
+	if h.Policies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "policy store not configured"})
+		return
+	}
+	t := models.PolicyType(c.Param("type"))
+	if !slices.Contains(policyTypes, t) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type", "details": "use tool_access, data_flow, human_in_loop, rate_limit or capability"})
+		return
+	}
+	policies, err := h.Policies.GetByType(c.Request.Context(), t)
+	if err != nil {
+		respondRepoError(c, err, "failed to list policies")
+		return
+	}
+	if policies == nil {
+		policies = []models.Policy{}
+	}
+	c.JSON(http.StatusOK, gin.H{"type": t, "policies": policies, "count": len(policies)})
+}
+
+// GetPolicy returns a policy.
+func (h *Handlers) GetPolicy(c *gin.Context) {
+	p, ok := h.loadPolicy(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// CreatePolicy adds a policy.
+func (h *Handlers) CreatePolicy(c *gin.Context) {
+	if h.Policies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "policy store not configured"})
+		return
+	}
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if req.ID != "" && !validateID(req.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid policy ID format"})
+		return
+	}
+	p := &models.Policy{ID: req.ID}
+	if msg := req.apply(p); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid policy", "details": msg})
+		return
+	}
+	if err := h.Policies.Create(c.Request.Context(), p); err != nil {
+		respondRepoError(c, err, "failed to create policy")
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// UpdatePolicy replaces a policy. Agents bound to it stay bound.
+func (h *Handlers) UpdatePolicy(c *gin.Context) {
+	p, ok := h.loadPolicy(c)
+	if !ok {
+		return
+	}
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if msg := req.apply(p); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid policy", "details": msg})
+		return
+	}
+	if err := h.Policies.Update(c.Request.Context(), p); err != nil {
+		respondRepoError(c, err, "failed to update policy")
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// DeletePolicy removes a policy. A policy still bound to agents is not
+// deleted; the response lists them so they can be unbound first.
+func (h *Handlers) DeletePolicy(c *gin.Context) {
+	p, ok := h.loadPolicy(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if h.Agents != nil {
+		bound, err := h.Agents.List(ctx, &repository.AgentFilters{Policy: &p.ID, Limit: maxAgentLimit})
+		if err != nil {
+			respondRepoError(c, err, "failed to check policy bindings")
+			return
+		}
+		if len(bound) > 0 {
+			ids := make([]string, 0, len(bound))
+			for _, a := range bound {
+				ids = append(ids, a.ID.String())
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": "policy is bound to agents", "agent_ids": ids})
+			return
+		}
+	}
+	if err := h.Policies.Delete(ctx, p.ID); err != nil {
+		respondRepoError(c, err, "failed to delete policy")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadPolicy fetches the policy named by the id path parameter, responding
+// 404 when it is missing.
+func (h *Handlers) loadPolicy(c *gin.Context) (*models.Policy, bool) {
+	if h.Policies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "policy store not configured"})
+		return nil, false
+	}
+	id := c.Param("id")
+	if !validateID(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid policy ID format"})
+		return nil, false
+	}
+	p, err := h.Policies.Get(c.Request.Context(), id)
+	if err != nil {
+		respondRepoError(c, err, "failed to get policy")
+		return nil, false
+	}
+	if p == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "policy not found"})
+		return nil, false
+	}
+	return p, true
+}
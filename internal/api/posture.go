@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Posture query bounds, in days.
+const (
+	defaultPostureDays = 90
+	maxPostureDays     = 730
+)
+
+// GetPosture serves GET /controls/posture: the caller's compliance posture
+// for an executive dashboard. Coverage trends come from stored gap
+// analyses over the last days query parameter (default 90); framework
+// limits the posture to one framework. Evidence freshness is included when
+// implementations are tracked, with evidence older than
+// evidence_max_age_days (default 90) counting as stale. The as_of query
+// parameter returns the posture as it stood at a past date or time, from
+// the analyses, implementations and attestations of then; monitoring
+// checks, which have no history, are left out.
+func (h *Handlers) GetPosture(c *gin.Context) {
+	if h.GapAnalyses == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analysis history not configured"})
+		return
+	}
+	days, ok := postureDays(c, "days")
+	if !ok {
+		return
+	}
+	maxAge, ok := postureDays(c, "evidence_max_age_days")
+	if !ok {
+		return
+	}
+	asOf, ok := asOfParam(c)
+	if !ok {
+		return
+	}
+	if !asOf.IsZero() && h.Implementations != nil {
+		if _, ok := h.implementationHistory(c); !ok {
+			return
+		}
+	}
+	ctx := c.Request.Context()
+	org := c.GetString(orgKey)
+	framework := c.Query("framework")
+	now := time.Now()
+	if !asOf.IsZero() {
+		now = asOf
+	}
+
+	analyses, err := h.GapAnalyses.List(ctx, org)
+	if err != nil {
+		respondRepoError(c, err, "failed to list gap analyses")
+		return
+	}
+	in := controls.PostureInput{
+		OrganizationID: org,
+		FrameworkNames: make(map[string]string),
+		Since:          now.AddDate(0, 0, -days),
+		MaxEvidenceAge: time.Duration(maxAge) * 24 * time.Hour,
+		Now:            now,
+		AsOf:           asOf,
+	}
+	for _, ga := range analyses {
+		if framework == "" || ga.TargetFrameworkID == framework {
+			in.Analyses = append(in.Analyses, ga)
+		}
+	}
+	if h.GapAnalyzer != nil {
+		for _, ga := range in.Analyses {
+			if fw, ok := h.GapAnalyzer.Framework(ga.TargetFrameworkID); ok {
+				in.FrameworkNames[fw.ID] = fw.Name
+			}
+		}
+		// Check results are only known as they are now.
+		if monitor := h.monitor(); monitor != nil && asOf.IsZero() {
+			in.Checks = monitor.ControlStatuses()
+		}
+	}
+
+	if h.Implementations != nil {
+		in.Implementations, err = h.listImplementations(ctx, org, framework, asOf)
+		if err != nil {
+			respondRepoError(c, err, "failed to list implementations")
+			return
+		}
+		if in.Implementations == nil {
+			in.Implementations = []models.ControlImplementation{}
+		}
+	}
+	if h.Attestations != nil && len(in.Implementations) > 0 {
+		campaigns, err := h.Attestations.ListCampaigns(ctx, org)
+		if err != nil {
+			respondRepoError(c, err, "failed to list attestation campaigns")
+			return
+		}
+		for _, campaign := range campaigns {
+			attestations, err := h.Attestations.ListAttestations(ctx, campaign.ID)
+			if err != nil {
+				respondRepoError(c, err, "failed to list attestations")
+				return
+			}
+			in.Attestations = append(in.Attestations, attestations...)
+		}
+	}
+
+	c.JSON(http.StatusOK, controls.BuildPosture(in))
+}
+
+// postureDays reads a day count query parameter, responding 400 when it
+// is out of range.
+func postureDays(c *gin.Context, name string) (int, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return defaultPostureDays, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxPostureDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name, "details": "must be between 1 and 730"})
+		return 0, false
+	}
+	return n, true
+}
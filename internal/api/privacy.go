@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type erasureRequest struct {
+	UserID string       `json:"user_id" binding:"required"`
+	Mode   privacy.Mode `json:"mode"` // delete (default) or pseudonymize
+}
+
+// makeEraseSubjectHandler serves POST /privacy/erasure. Erasure runs
+// synchronously and returns the completion report, or as a job when the
+// client asks for asynchronous execution.
+func makeEraseSubjectHandler(e *privacy.Eraser, m *jobs.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body erasureRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		req := privacy.Request{
+			OrgID:       c.GetString(orgKey),
+			UserID:      body.UserID,
+			Mode:        body.Mode,
+			RequestedBy: requestActor(c),
+		}
+
+		if m != nil && wantsAsync(c) {
+			job, err := submitJob(c, m, "privacy_erasure", "admin:privacy", func(ctx context.Context) (any, error) {
+				return e.Erase(ctx, req)
+			})
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue erasure", "details": err.Error()})
+				return
+			}
+			acceptJob(c, job)
+			return
+		}
+
+		rep, err := e.Erase(c.Request.Context(), req)
+		if err != nil {
+			if errors.Is(err, privacy.ErrInvalidRequest) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			log.Error().Err(err).Msg("data subject erasure failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "erasure failed"})
+			return
+		}
+		status := http.StatusOK
+		if rep.Status != privacy.StatusCompleted {
+			status = http.StatusMultiStatus
+		}
+		c.JSON(status, rep)
+	}
+}
+
+func makeListErasureReportsHandler(e *privacy.Eraser) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reports := e.Reports(c.GetString(orgKey))
+		c.JSON(http.StatusOK, gin.H{
+			"reports": reports,
+			"total":   len(reports),
+		})
+	}
+}
+
+func makeGetErasureReportHandler(e *privacy.Eraser) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rep, ok := e.Report(c.GetString(orgKey), c.Param("id"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "erasure report not found"})
+			return
+		}
+		c.JSON(http.StatusOK, rep)
+	}
+}
+
+type reidentifyRequest struct {
+	Pseudonym string `json:"pseudonym" binding:"required"`
+	// Reason records why the identity is needed, e.g. an incident number.
+	Reason string `json:"reason" binding:"required"`
+}
+
+// reidentification is the audit record of a re-identification. It names
+// the pseudonym but never the identity behind it.
+type reidentification struct {
+	Pseudonym string `json:"pseudonym"`
+	Reason    string `json:"reason"`
+	Found     bool   `json:"found"`
+}
+
+// makeReidentifyHandler serves POST /privacy/reidentify. Every lookup is
+// appended to the audit log before the identity is returned; when the
+// audit log is configured but the append fails, the identity is withheld.
+func makeReidentifyHandler(p *privacy.Pseudonymizer, auditLog *audit.Log) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body reidentifyRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		orgID := c.GetString(orgKey)
+
+		identity, err := p.Reidentify(c.Request.Context(), orgID, body.Pseudonym)
+		if err != nil && !errors.Is(err, privacy.ErrUnknownPseudonym) {
+			log.Error().Err(err).Str("pseudonym", body.Pseudonym).Msg("re-identification failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "re-identification failed"})
+			return
+		}
+
+		rec := reidentification{Pseudonym: body.Pseudonym, Reason: body.Reason, Found: identity != nil}
+		if auditLog != nil {
+			if _, err := auditLog.Append(audit.KindReidentify, orgID, requestActor(c), rec); err != nil {
+				log.Error().Err(err).Str("pseudonym", body.Pseudonym).Msg("failed to audit re-identification")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "re-identification could not be audited"})
+				return
+			}
+		} else {
+			log.Warn().Str("org_id", orgID).Str("pseudonym", body.Pseudonym).Str("reason", body.Reason).
+				Bool("found", rec.Found).Msg("re-identification without audit log")
+		}
+
+		if identity == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown pseudonym"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"pseudonym": body.Pseudonym,
+			"kind":      identity.Kind,
+			"value":     identity.Value,
+		})
+	}
+}
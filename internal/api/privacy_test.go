@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/privacy"
+)
+
+func TestErasureJobsNeedPrivacyScope(t *testing.T) {
+	eraser, err := privacy.NewEraser(privacy.Config{PseudonymKey: []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := jobs.NewManager(jobs.Config{Workers: 1})
+	t.Cleanup(m.Stop)
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		Privacy: eraser,
+		Jobs:    m,
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "dpo", Token: "dpo-token", Org: "acme", Scopes: []string{"admin:privacy"}},
+			apikey.Key{ID: "ops", Token: "ops-token", Org: "acme"},
+			apikey.Key{ID: "globex-dpo", Token: "globex-token", Org: "globex", Scopes: []string{"admin:privacy"}},
+		),
+	})
+
+	erasure := map[string]any{"user_id": "alice@example.com"}
+	if w := do(srv, http.MethodPost, "/api/v1/privacy/erasure?async=true", "ops-token", erasure); w.Code != http.StatusForbidden {
+		t.Errorf("erasure without admin:privacy = %d, want 403", w.Code)
+	}
+	w := do(srv, http.MethodPost, "/api/v1/privacy/erasure?async=true", "dpo-token", erasure)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("erasure = %d %s, want 202", w.Code, w.Body)
+	}
+	job := decode[jobs.Job](t, w)
+	if job.Scope != "admin:privacy" {
+		t.Errorf("job scope = %q, want admin:privacy", job.Scope)
+	}
+
+	result := "/api/v1/jobs/" + job.ID + "/result"
+	deadline := time.Now().Add(2 * time.Second)
+	for w = do(srv, http.MethodGet, result, "dpo-token", nil); w.Code == http.StatusAccepted && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+		w = do(srv, http.MethodGet, result, "dpo-token", nil)
+	}
+	if rep := decode[privacy.Report](t, w); w.Code != http.StatusOK || rep.OrgID != "acme" {
+		t.Fatalf("report = %d %s, want acme's erasure report", w.Code, w.Body)
+	}
+
+	for _, token := range []string{"ops-token", "globex-token"} {
+		if w := do(srv, http.MethodGet, result, token, nil); w.Code != http.StatusNotFound {
+			t.Errorf("report read with %s = %d, want 404", token, w.Code)
+		}
+		if w := do(srv, http.MethodDelete, "/api/v1/jobs/"+job.ID, token, nil); w.Code != http.StatusNotFound {
+			t.Errorf("cancel with %s = %d, want 404", token, w.Code)
+		}
+	}
+	list := decode[struct{ Total int }](t, do(srv, http.MethodGet, "/api/v1/privacy/erasure", "globex-token", nil))
+	if list.Total != 0 {
+		t.Errorf("globex sees %d erasure reports, want none", list.Total)
+	}
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/profiles"
+	"github.com/agentguard/agentguard/internal/threat"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func makeListProfilesHandler(reg *profiles.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list := reg.List()
+		c.JSON(http.StatusOK, gin.H{
+			"profiles": list,
+			"fallback": reg.Fallback(),
+			"total":    len(list),
+		})
+	}
+}
+
+func makeGetProfileHandler(reg *profiles.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := reg.Get(c.Param("name"))
+		if errors.Is(err, profiles.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "profile not found"})
+			return
+		}
+		c.JSON(http.StatusOK, p)
+	}
+}
+
+// makeResolveProfileHandler reports which profile applies to an agent
+// environment.
+func makeResolveProfileHandler(reg *profiles.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, reg.Resolve(c.Query("environment")))
+	}
+}
+
+func makePutProfileHandler(reg *profiles.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var p profiles.Profile
+		if err := c.ShouldBindJSON(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		p.Name = c.Param("name")
+		if err := p.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid profile", "details": err.Error()})
+			return
+		}
+		if err := reg.Put(p); err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, p)
+	}
+}
+
+func makeDeleteProfileHandler(reg *profiles.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := reg.Delete(c.Param("name")); err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, profiles.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "profile not found"})
+	case errors.Is(err, profiles.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "profile conflict", "details": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid profile", "details": err.Error()})
+	}
+}
+
+// strictProfile applies when no profiles are configured: enforce every deny
+// and record every trace. Failure handling falls to the failure policy.
+var strictProfile = profiles.Profile{
+	Name:            "default",
+	Enforcement:     profiles.Enforce,
+	TraceSampleRate: 1,
+}
+
+// routePreInvoke names the SDK pre-invoke hook in failure policy routes.
+const routePreInvoke = "pre_invoke"
+
+// failOpen returns an allow decision for a call made without a policy
+// decision and records a fail_open signal for it.
+func failOpen(c *gin.Context, deps *RouterDeps, input *opa.EvaluationInput, cause, source string) *opa.Decision {
+	tool := ""
+	if input.Tool != nil {
+		tool = input.Tool.Name
+	}
+	sig := models.SecuritySignal{
+		ID:          uuid.NewString(),
+		Type:        models.SignalFailOpen,
+		Severity:    "medium",
+		Title:       "Tool call allowed without a policy decision",
+		Description: cause + "; allowed by " + source,
+		Evidence: map[string]any{
+			"cause":       cause,
+			"fail_source": source,
+			"tool":        tool,
+			"environment": input.Agent.Environment,
+			"risk_level":  input.Agent.RiskLevel,
+		},
+		Timestamp: time.Now().UTC(),
+	}
+	if input.Data != nil && input.Data.Classification != "" {
+		sig.Evidence["data_classification"] = input.Data.Classification
+	}
+	orgID := c.GetString(orgKey)
+	log.Warn().Str("org_id", orgID).Str("agent_id", input.Agent.ID).Str("tool", tool).
+		Str("cause", cause).Str("fail_source", source).Msg("pre-invoke failed open")
+
+	if deps != nil {
+		dispatchSignal(c, deps, orgID, input.Agent.ID, sig)
+	}
+
+	return &opa.Decision{
+		Allow:    true,
+		Reasons:  []string{cause + " — allowed by fail-open " + source},
+		Metadata: map[string]any{"fail_open": true, "signal_id": sig.ID},
+	}
+}
+
+// applyProfile adjusts a policy decision for the agent's environment profile
+// and tells the SDK how to sample traces.
+func applyProfile(d *opa.Decision, input *opa.EvaluationInput, p profiles.Profile) {
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]any)
+	}
+	d.Metadata["profile"] = p.Name
+	d.Metadata["trace_sample_rate"] = p.TraceSampleRate
+
+	if d.Allow && p.RequireSandbox {
+		d.RequireSandbox(input.Tool, threat.SandboxProfileNames())
+	}
+	if !d.Allow && p.Enforcement == profiles.Monitor {
+		d.Allow = true
+		d.Metadata["monitor_only"] = true
+		d.Metadata["would_deny"] = true
+	}
+	if d.Allow && input.Tool != nil && p.RequiresApproval(input.Tool.Category) {
+		d.Metadata["requires_approval"] = true
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/gin-gonic/gin"
+)
+
+// validPromptHash matches hex or prefixed digests such as sha256:abc…
+var validPromptHash = regexp.MustCompile(`^[A-Za-z0-9:_-]{8,136}$`)
+
+func makeListPromptsHandler(reg *prompts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		flagged := c.Query("flagged") == "true"
+		entries := reg.List(c.GetString(orgKey), flagged)
+		c.JSON(http.StatusOK, gin.H{
+			"prompts": entries,
+			"total":   len(entries),
+		})
+	}
+}
+
+func makeGetPromptHandler(reg *prompts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hash := c.Param("hash")
+		if !validPromptHash.MatchString(hash) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prompt hash"})
+			return
+		}
+		entry, err := reg.Get(c.GetString(orgKey), hash)
+		if errors.Is(err, prompts.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "prompt hash not found"})
+			return
+		}
+		c.JSON(http.StatusOK, entry)
+	}
+}
+
+func makeListPromptOffendersHandler(reg *prompts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		minHits := int64(2)
+		if v := c.Query("min_hits"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_hits"})
+				return
+			}
+			minHits = n
+		}
+		offenders := reg.Offenders(c.GetString(orgKey), minHits)
+		c.JSON(http.StatusOK, gin.H{
+			"offenders": offenders,
+			"total":     len(offenders),
+		})
+	}
+}
+
+// BlockPromptRequest is the request body for blocking a prompt hash.
+type BlockPromptRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func makeBlockPromptHandler(reg *prompts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hash := c.Param("hash")
+		if !validPromptHash.MatchString(hash) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prompt hash"})
+			return
+		}
+		var req BlockPromptRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+			return
+		}
+		block := reg.Block(hash, req.Reason, c.GetString(orgKey))
+		c.JSON(http.StatusOK, gin.H{"hash": hash, "blocked": block})
+	}
+}
+
+func makeUnblockPromptHandler(reg *prompts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hash := c.Param("hash")
+		if err := reg.Unblock(hash); errors.Is(err, prompts.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "prompt hash is not blocked"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func makeListPromptBlocklistHandler(reg *prompts.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		blocks := reg.Blocklist()
+		c.JSON(http.StatusOK, gin.H{
+			"blocked": blocks,
+			"total":   len(blocks),
+		})
+	}
+}
+
+// hashPrompt replaces a raw prompt in a pre-invoke request with its salted
+// hash so it is checked against the same hashes ingest records, and never
+// reaches policy input or the audit log. Without a hasher the raw prompt
+// is dropped and any client hash is used as is. It reports false after
+// aborting the request.
+func hashPrompt(c *gin.Context, deps *RouterDeps, req *opa.RequestContext) bool {
+	if req == nil || req.Prompt == nil {
+		return true
+	}
+	prompt := req.Prompt
+	req.Prompt = nil
+	if deps == nil || deps.Hasher == nil {
+		return true
+	}
+	hash, err := deps.Hasher.Sum(c.GetString(orgKey), prompt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"allow":   false,
+			"reasons": []string{"invalid prompt"},
+		})
+		return false
+	}
+	req.PromptHash = hash
+	return true
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// orgKey is the gin context key for the caller's organization ID.
+const orgKey = "org_id"
+
+// bytesPerGB converts configured ingest quotas to bytes.
+const bytesPerGB = 1 << 30
+
+// quotaTracker accounts daily per-organization usage against configured quotas.
+// Windows are UTC calendar days; usage is only kept for the current one.
+type quotaTracker struct {
+	cfg config.QuotaConfig
+	now func() time.Time
+
+	mu    sync.Mutex
+	day   string
+	usage map[string]*orgUsage
+}
+
+// orgUsage is one organization's usage within the current window.
+type orgUsage struct {
+	day         string
+	requests    int64
+	ingestBytes int64
+}
+
+// UsageReport describes an organization's usage and limits for the current window.
+type UsageReport struct {
+	Organization      string    `json:"organization"`
+	WindowStart       time.Time `json:"window_start"`
+	WindowEnd         time.Time `json:"window_end"`
+	Requests          int64     `json:"requests"`
+	RequestsLimit     int64     `json:"requests_limit"` // 0 means unlimited
+	IngestBytes       int64     `json:"ingest_bytes"`
+	IngestBytesLimit  int64     `json:"ingest_bytes_limit"` // 0 means unlimited
+	RequestsRemaining *int64    `json:"requests_remaining,omitempty"`
+}
+
+func newQuotaTracker(cfg config.QuotaConfig) *quotaTracker {
+	return &quotaTracker{
+		cfg:   cfg,
+		now:   time.Now,
+		usage: make(map[string]*orgUsage),
+	}
+}
+
+// limits returns the effective limits for an organization.
+func (q *quotaTracker) limits(org string) (requests, ingestBytes int64) {
+	l := q.cfg.Default
+	if override, ok := q.cfg.Organizations[org]; ok {
+		l = override
+	}
+	return l.RequestsPerDay, int64(l.IngestGBPerDay * bytesPerGB)
+}
+
+// current returns the usage record for org, dropping every organization's
+// usage when the day rolls over. Callers must hold q.mu.
+func (q *quotaTracker) current(org string) *orgUsage {
+	day := q.now().UTC().Format("2006-01-02")
+	if day != q.day {
+		q.day = day
+		clear(q.usage)
+	}
+	u, ok := q.usage[org]
+	if !ok {
+		u = &orgUsage{day: day}
+		q.usage[org] = u
+	}
+	return u
+}
+
+// allowRequest counts a request and reports whether it is within quota.
+func (q *quotaTracker) allowRequest(org string) (bool, int64, int64) {
+	limit, _ := q.limits(org)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.current(org)
+	if limit > 0 && u.requests >= limit {
+		return false, limit, 0
+	}
+	u.requests++
+	return true, limit, limit - u.requests
+}
+
+// ingestAvailable reports whether n more ingest bytes fit in the org's quota.
+func (q *quotaTracker) ingestAvailable(org string, n int64) bool {
+	_, limit := q.limits(org)
+	if limit <= 0 {
+		return true
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.current(org).ingestBytes+n <= limit
+}
+
+// addIngest records ingested bytes for an organization.
+func (q *quotaTracker) addIngest(org string, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.current(org).ingestBytes += n
+}
+
+// report returns the current usage for an organization.
+func (q *quotaTracker) report(org string) UsageReport {
+	reqLimit, ingestLimit := q.limits(org)
+	q.mu.Lock()
+	u := *q.current(org)
+	q.mu.Unlock()
+
+	start, _ := time.Parse("2006-01-02", u.day)
+	r := UsageReport{
+		Organization:     org,
+		WindowStart:      start,
+		WindowEnd:        start.Add(24 * time.Hour),
+		Requests:         u.requests,
+		RequestsLimit:    reqLimit,
+		IngestBytes:      u.ingestBytes,
+		IngestBytesLimit: ingestLimit,
+	}
+	if reqLimit > 0 {
+		remaining := reqLimit - u.requests
+		if remaining < 0 {
+			remaining = 0
+		}
+		r.RequestsRemaining = &remaining
+	}
+	return r
+}
+
+// maxOrgIDLength bounds the organization IDs accepted in the org header.
+const maxOrgIDLength = 64
+
+// validOrgID reports whether id is a well-formed organization ID: up to
+// maxOrgIDLength letters, digits, dots, dashes and underscores, starting
+// with a letter or digit.
+func validOrgID(id string) bool {
+	if id == "" || len(id) > maxOrgIDLength {
+		return false
+	}
+	for i, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case i > 0 && (r == '.' || r == '-' || r == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// orgMiddleware resolves the caller's organization from its credential: the
+// organization of its API key or workload identity binding, else the
+// default organization. A request whose org header names another
+// organization is refused, so callers can neither read another
+// organization's data nor spread their usage across made-up organizations.
+// With trustHeader, used in dev mode where requests carry no credential,
+// the header selects the organization instead.
+func orgMiddleware(cfg config.QuotaConfig, trustHeader bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(cfg.OrgHeader)
+		if header != "" && !validOrgID(header) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid organization",
+				"details": fmt.Sprintf("%s must be up to %d letters, digits, dots, dashes or underscores", cfg.OrgHeader, maxOrgIDLength),
+			})
+			return
+		}
+		org := c.GetString(orgKey)
+		if org == "" {
+			org = cfg.DefaultOrg
+			if trustHeader && header != "" {
+				org = header
+			}
+		}
+		if header != "" && header != org {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "organization mismatch",
+				"details": cfg.OrgHeader + " does not match the organization of the credential",
+			})
+			return
+		}
+		c.Set(orgKey, org)
+		c.Next()
+	}
+}
+
+// quotaMiddleware enforces per-organization daily request quotas.
+func quotaMiddleware(q *quotaTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		org := c.GetString(orgKey)
+		ok, limit, remaining := q.allowRequest(org)
+		if limit > 0 {
+			c.Header("X-Quota-Limit", strconv.FormatInt(limit, 10))
+			c.Header("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":        "organization request quota exceeded",
+				"organization": org,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ingestQuotaMiddleware enforces per-organization daily ingest volume on
+// routes that accept trace data. Bytes are counted as the body is read.
+func ingestQuotaMiddleware(q *quotaTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		org := c.GetString(orgKey)
+		size := c.Request.ContentLength
+		if size < 0 {
+			size = 0
+		}
+		if !q.ingestAvailable(org, size) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":        "organization ingest quota exceeded",
+				"organization": org,
+			})
+			return
+		}
+
+		counter := &countingReader{r: c.Request.Body}
+		c.Request.Body = counter
+		c.Next()
+		q.addIngest(org, counter.n)
+	}
+}
+
+// countingReader counts bytes read from the wrapped body.
+type countingReader struct {
+	r io.ReadCloser
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) Close() error { return cr.r.Close() }
+
+// makeUsageHandler returns the caller organization's usage for the current window.
+func makeUsageHandler(q *quotaTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, q.report(c.GetString(orgKey)))
+	}
+}
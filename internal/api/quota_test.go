@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+)
+
+func TestOrganizationFromCredential(t *testing.T) {
+	cfg := testConfig()
+	srv := newServer(t, cfg, &api.RouterDeps{APIKeys: mustKeys(t,
+		apikey.Key{ID: "acme", Token: "acme-token", Org: "acme"},
+		apikey.Key{ID: "shared", Token: "shared-token"},
+	)})
+
+	for _, tc := range []struct {
+		name, token, header string
+		status              int
+		org                 string
+	}{
+		{"key org", "acme-token", "", http.StatusOK, "acme"},
+		{"matching header", "acme-token", "acme", http.StatusOK, "acme"},
+		{"other org", "acme-token", "globex", http.StatusForbidden, ""},
+		{"key without org", "shared-token", "", http.StatusOK, "default"},
+		{"key without org claiming one", "shared-token", "acme", http.StatusForbidden, ""},
+		{"malformed header", "acme-token", "acme/../globex", http.StatusBadRequest, ""},
+		{"overlong header", "acme-token", strings.Repeat("a", 65), http.StatusBadRequest, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var header []string
+			if tc.header != "" {
+				header = []string{"X-AgentGuard-Org", tc.header}
+			}
+			w := do(srv, http.MethodGet, "/api/v1/usage", tc.token, nil, header...)
+			if w.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.status, w.Body)
+			}
+			if tc.org != "" {
+				if got := decode[api.UsageReport](t, w).Organization; got != tc.org {
+					t.Errorf("organization = %q, want %q", got, tc.org)
+				}
+			}
+		})
+	}
+
+	// Without credentials, in dev mode, the header selects the organization.
+	dev := testConfig()
+	dev.Server.Dev = true
+	dev.Auth.Provider = "none"
+	w := do(newServer(t, dev, nil), http.MethodGet, "/api/v1/usage", "", nil, "X-AgentGuard-Org", "globex")
+	if w.Code != http.StatusOK || decode[api.UsageReport](t, w).Organization != "globex" {
+		t.Errorf("dev mode: %d %s, want globex's usage", w.Code, w.Body)
+	}
+}
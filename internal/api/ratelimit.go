@@ -0,0 +1,144 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitBucket is the state of one rate limiter key.
+type rateLimitBucket struct {
+	Key       string    `json:"key"`
+	Count     int       `json:"count"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Blocked   bool      `json:"blocked"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// rateLimitStore is the operator view of a rate limiter backend. The
+// in-memory rateLimiter implements it; a shared backend must implement it to
+// be managed through the admin API.
+type rateLimitStore interface {
+	// buckets returns the keys with requests inside the current window.
+	buckets() []rateLimitBucket
+	// reset clears a key and reports whether it was tracked.
+	reset(key string) bool
+	// resetAll clears every key and returns how many were cleared.
+	resetAll() int
+}
+
+// bucketLocked builds the state of key as of now. The caller must hold rl.mu.
+func (rl *rateLimiter) bucketLocked(key string, now time.Time) (rateLimitBucket, bool) {
+	cutoff := now.Add(-rl.window)
+	var count int
+	var oldest time.Time
+	for _, ts := range rl.visitors[key] {
+		if !ts.After(cutoff) {
+			continue
+		}
+		if count == 0 || ts.Before(oldest) {
+			oldest = ts
+		}
+		count++
+	}
+	if count == 0 {
+		return rateLimitBucket{}, false
+	}
+	return rateLimitBucket{
+		Key:       key,
+		Count:     count,
+		Limit:     rl.limit,
+		Remaining: max(rl.limit-count, 0),
+		Blocked:   count >= rl.limit,
+		ResetAt:   oldest.Add(rl.window).UTC(),
+	}, true
+}
+
+func (rl *rateLimiter) buckets() []rateLimitBucket {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	out := make([]rateLimitBucket, 0, len(rl.visitors))
+	for key := range rl.visitors {
+		if b, ok := rl.bucketLocked(key, now); ok {
+			out = append(out, b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+func (rl *rateLimiter) reset(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	_, ok := rl.visitors[key]
+	delete(rl.visitors, key)
+	return ok
+}
+
+func (rl *rateLimiter) resetAll() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	n := len(rl.visitors)
+	rl.visitors = make(map[string][]time.Time)
+	return n
+}
+
+// makeListRateLimitsHandler lists the active rate limiter keys. ?prefix=
+// narrows the keys and ?blocked=true returns only keys at their limit.
+func makeListRateLimitsHandler(store rateLimitStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prefix := c.Query("prefix")
+		blockedOnly := c.Query("blocked") == "true"
+
+		buckets := make([]rateLimitBucket, 0)
+		for _, b := range store.buckets() {
+			if !strings.HasPrefix(b.Key, prefix) || (blockedOnly && !b.Blocked) {
+				continue
+			}
+			buckets = append(buckets, b)
+		}
+		c.JSON(http.StatusOK, gin.H{"buckets": buckets, "total": len(buckets)})
+	}
+}
+
+// makeGetRateLimitHandler returns the state of a single key.
+func makeGetRateLimitHandler(store rateLimitStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Param("key")
+		for _, b := range store.buckets() {
+			if b.Key == key {
+				c.JSON(http.StatusOK, b)
+				return
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "rate limit key not found"})
+	}
+}
+
+// makeResetRateLimitHandler clears a single key so its next request starts a
+// fresh window.
+func makeResetRateLimitHandler(store rateLimitStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Param("key")
+		if !store.reset(key) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "rate limit key not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"reset": key})
+	}
+}
+
+// makeResetAllRateLimitsHandler clears every key.
+func makeResetAllRateLimitsHandler(store rateLimitStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"reset": store.resetAll()})
+	}
+}
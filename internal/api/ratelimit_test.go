@@ -0,0 +1,76 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+)
+
+func TestRateLimitAdmin(t *testing.T) {
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"},
+			apikey.Key{ID: "ops", Token: "ops-token", Org: "acme", Scopes: []string{"admin:ratelimits"}},
+		),
+	})
+	type bucket struct {
+		Key       string
+		Count     int
+		Limit     int
+		Remaining int
+		Blocked   bool
+	}
+	type list struct {
+		Buckets []bucket
+		Total   int
+	}
+
+	for _, path := range []string{"/api/v1/admin/ratelimits", "/api/v1/admin/ratelimits/api_key:ci"} {
+		if w := do(srv, http.MethodGet, path, "ci-token", nil); w.Code != http.StatusForbidden {
+			t.Errorf("GET %s without admin:ratelimits = %d, want 403", path, w.Code)
+		}
+	}
+
+	// The router allows 100 requests a minute per key; the two refused
+	// admin requests count toward ci's.
+	for range 98 {
+		do(srv, http.MethodGet, "/api/v1/usage", "ci-token", nil)
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/usage", "ci-token", nil); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the limit = %d, want 429", w.Code)
+	}
+
+	blocked := decode[list](t, do(srv, http.MethodGet, "/api/v1/admin/ratelimits?blocked=true", "ops-token", nil))
+	if blocked.Total != 1 || blocked.Buckets[0] != (bucket{"api_key:ci", 100, 100, 0, true}) {
+		t.Errorf("blocked keys = %+v, want api_key:ci at its limit", blocked)
+	}
+	if got := decode[list](t, do(srv, http.MethodGet, "/api/v1/admin/ratelimits?prefix=api_key:ops", "ops-token", nil)); got.Total != 1 || got.Buckets[0] != (bucket{"api_key:ops", 2, 100, 98, false}) {
+		t.Errorf("ops keys = %+v, want only the admin's own requests", got)
+	}
+	if got := decode[bucket](t, do(srv, http.MethodGet, "/api/v1/admin/ratelimits/api_key:ci", "ops-token", nil)); got.Count != 100 || !got.Blocked {
+		t.Errorf("api_key:ci = %+v, want 100 requests, blocked", got)
+	}
+
+	w := do(srv, http.MethodDelete, "/api/v1/admin/ratelimits/api_key:ci", "ops-token", nil)
+	if got := decode[struct{ Reset string }](t, w); w.Code != http.StatusOK || got.Reset != "api_key:ci" {
+		t.Errorf("reset = %d %s, want api_key:ci reset", w.Code, w.Body)
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/usage", "ci-token", nil); w.Code != http.StatusOK {
+		t.Errorf("request after a reset = %d, want 200", w.Code)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if w := do(srv, method, "/api/v1/admin/ratelimits/api_key:nobody", "ops-token", nil); w.Code != http.StatusNotFound {
+			t.Errorf("%s of an unknown key = %d, want 404", method, w.Code)
+		}
+	}
+
+	w = do(srv, http.MethodDelete, "/api/v1/admin/ratelimits", "ops-token", nil)
+	if got := decode[struct{ Reset int }](t, w); w.Code != http.StatusOK || got.Reset != 2 {
+		t.Errorf("reset all = %d %s, want both keys cleared", w.Code, w.Body)
+	}
+	if got := decode[list](t, do(srv, http.MethodGet, "/api/v1/admin/ratelimits", "ops-token", nil)); got.Total != 1 {
+		t.Errorf("keys after reset all = %+v, want only this request's", got)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// reportContentTypes maps report formats to their media types, for Accept
+// negotiation and responses.
+var reportContentTypes = map[string]string{
+	controls.ReportJSON: "application/json",
+	controls.ReportHTML: "text/html",
+	controls.ReportPDF:  "application/pdf",
+	controls.ReportCSV:  "text/csv",
+	controls.ReportXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	// OSCAL documents are JSON; the distinct type lets Accept select them.
+	controls.ReportOSCAL:    "application/oscal+json",
+	controls.ReportMarkdown: "text/markdown",
+	controls.ReportDOCX:     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+// negotiateReportFormat picks one of the offered formats, the first being
+// the default. An explicit format query parameter wins; otherwise the Accept
+// header is matched against the formats' media types, and an Accept that
+// matches none gets the default. It responds 400 and reports false for an
+// unsupported format parameter.
+func negotiateReportFormat(c *gin.Context, offered ...string) (string, bool) {
+	if format := c.Query("format"); format != "" {
+		for _, f := range offered {
+			if f == format {
+				return format, true
+			}
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported report format", "details": format})
+		return "", false
+	}
+
+	c.Header("Vary", appendVary(c.Writer.Header().Get("Vary"), "Accept"))
+	types := make([]string, len(offered))
+	for i, f := range offered {
+		types[i] = reportContentTypes[f]
+	}
+	if t := c.NegotiateFormat(types...); t != "" {
+		for i, ct := range types {
+			if ct == t {
+				return offered[i], true
+			}
+		}
+	}
+	return offered[0], true
+}
+
+// writeReport renders a report into memory and sends it, so a rendering
+// error can still be answered with a JSON error. HTML is shown inline; other
+// formats download as name.format.
+func writeReport(c *gin.Context, name, format string, render func(*bytes.Buffer) error) {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		log.Error().Err(err).Str("report", name).Str("format", format).Msg("report rendering failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "report rendering failed"})
+		return
+	}
+	contentType := reportContentTypes[format]
+	disposition := "attachment"
+	ext := format
+	switch format {
+	case controls.ReportHTML:
+		disposition = "inline"
+		contentType += "; charset=utf-8"
+	case controls.ReportCSV:
+		contentType += "; charset=utf-8"
+	case controls.ReportOSCAL:
+		ext = "json"
+	case controls.ReportMarkdown:
+		contentType += "; charset=utf-8"
+		ext = "md"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=\"%s.%s\"", disposition, name, ext))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
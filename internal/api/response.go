@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+func makeListResponseRulesHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"rules":   e.Rules(),
+			"dry_run": e.DryRun(),
+		})
+	}
+}
+
+func makeListResponseActionsHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actions := e.Actions(c.Query("agent_id"))
+		c.JSON(http.StatusOK, gin.H{
+			"actions": actions,
+			"total":   len(actions),
+		})
+	}
+}
+
+func makeRollbackResponseActionHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rec, err := e.Rollback(c.Param("id"), c.GetString(orgKey))
+		switch {
+		case errors.Is(err, response.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "action not found"})
+		case err != nil && (errors.Is(err, response.ErrNotReversible) || errors.Is(err, response.ErrAlreadyUndone) || errors.Is(err, response.ErrNothingApplied)):
+			c.JSON(http.StatusConflict, gin.H{"error": "action cannot be rolled back", "details": err.Error(), "action": rec})
+		case err != nil:
+			respondRepoError(c, err, "failed to roll back action")
+		default:
+			c.JSON(http.StatusOK, rec)
+		}
+	}
+}
+
+func makeListContainmentHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		agents := e.Containment().List()
+		c.JSON(http.StatusOK, gin.H{
+			"agents": agents,
+			"total":  len(agents),
+		})
+	}
+}
+
+type quarantineRequest struct {
+	Reason string `json:"reason"`
+	// AllowTools replaces the default quarantine allowlist when set. An
+	// empty list denies every tool call.
+	AllowTools *[]string `json:"allow_tools"`
+}
+
+func makeQuarantineAgentHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req quarantineRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+				return
+			}
+		}
+		var tools []string
+		if req.AllowTools != nil {
+			tools = append([]string{}, *req.AllowTools...)
+		}
+		rec := e.Quarantine(c.GetString(orgKey), c.Param("agent_id"), req.Reason, c.GetString(orgKey), tools)
+		state, _ := e.Containment().Get(c.Param("agent_id"))
+		c.JSON(http.StatusOK, gin.H{"action": rec, "state": state})
+	}
+}
+
+func makeReleaseAgentHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !e.Release(c.Param("agent_id"), c.GetString(orgKey)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "agent is not quarantined"})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func makeListResponseSuggestionsHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suggestions := e.Suggestions()
+		c.JSON(http.StatusOK, gin.H{
+			"suggestions": suggestions,
+			"total":       len(suggestions),
+		})
+	}
+}
+
+func makeAcceptSuggestionHandler(e *response.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req quarantineRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+				return
+			}
+		}
+		var tools []string
+		if req.AllowTools != nil {
+			tools = append([]string{}, *req.AllowTools...)
+		}
+		rec, err := e.AcceptSuggestion(c.Param("id"), c.GetString(orgKey), tools)
+		switch {
+		case errors.Is(err, response.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "suggestion not found"})
+		case errors.Is(err, response.ErrNotSuggestion):
+			c.JSON(http.StatusConflict, gin.H{"error": "action is not a pending suggestion", "action": rec})
+		case err != nil:
+			respondRepoError(c, err, "failed to accept suggestion")
+		default:
+			c.JSON(http.StatusOK, rec)
+		}
+	}
+}
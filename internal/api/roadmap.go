@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// maxRoadmapCapacity bounds the effort points per quarter a request may ask
+// for.
+const maxRoadmapCapacity = 1000
+
+// RoadmapRequest is a gap analysis request with roadmap sequencing options.
+type RoadmapRequest struct {
+	GapAnalysisRequest
+	// Capacity is the effort points per quarter.
+	Capacity int `json:"capacity,omitempty"`
+	// Start is the first quarter, as 2027-Q1 or a date. Defaults to the
+	// current quarter.
+	Start string `json:"start,omitempty"`
+	// Dependencies maps control IDs to the control IDs they depend on, in
+	// addition to those inferred from the catalog.
+	Dependencies map[string][]string `json:"dependencies,omitempty"`
+}
+
+// makeRoadmapHandler serves POST /controls/roadmap: a gap analysis
+// sequenced into a quarterly remediation roadmap. The format query
+// parameter, or else the Accept header, selects json (default), csv or
+// xlsx. The analysis is not stored.
+func makeRoadmapHandler(ga *controls.GapAnalyzer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, ok := negotiateReportFormat(c, controls.ReportJSON, controls.ReportCSV, controls.ReportXLSX)
+		if !ok {
+			return
+		}
+
+		var req RoadmapRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if req.Capacity < 0 || req.Capacity > maxRoadmapCapacity {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "capacity must be between 1 and 1000"})
+			return
+		}
+		opts := controls.RoadmapOptions{Capacity: req.Capacity, Dependencies: req.Dependencies}
+		if req.Start != "" {
+			start, err := controls.ParseQuarter(req.Start)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start", "details": err.Error()})
+				return
+			}
+			opts.Start = start
+		}
+		if req.Scoring != nil {
+			if err := req.Scoring.Validate(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scoring model", "details": err.Error()})
+				return
+			}
+		}
+		if _, ok := ga.Framework(req.TargetFramework); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown framework", "details": req.TargetFramework})
+			return
+		}
+		if req.SourceFramework != "" {
+			if _, ok := ga.Framework(req.SourceFramework); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown source framework", "details": req.SourceFramework})
+				return
+			}
+		}
+		for _, id := range req.Providers {
+			if _, ok := ga.GetProvider(id); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown control provider", "details": id})
+				return
+			}
+		}
+
+		input := &controls.AnalysisInput{
+			TargetFramework:     req.TargetFramework,
+			ImplementedControls: req.ImplementedControls,
+			SourceFramework:     req.SourceFramework,
+			Providers:           req.Providers,
+			Scoring:             req.Scoring,
+		}
+		output, err := ga.RunAnalysis(c.Request.Context(), input)
+		if err != nil {
+			log.Error().Err(err).Str("framework", req.TargetFramework).Msg("gap analysis failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "analysis failed"})
+			return
+		}
+		output.Input = input
+
+		roadmap, err := ga.Roadmap(output, opts)
+		if err != nil {
+			// Only caller-supplied dependencies can form a cycle.
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dependencies", "details": err.Error()})
+			return
+		}
+
+		if format != controls.ReportJSON {
+			writeReport(c, "roadmap-"+output.Framework, format, func(buf *bytes.Buffer) error {
+				return controls.WriteRoadmap(buf, roadmap, format)
+			})
+			return
+		}
+		c.JSON(http.StatusOK, roadmap)
+	}
+}
+
+// maxPlanControls bounds the gaps a remediation plan may select.
+const maxPlanControls = 500
+
+// PlanRequest selects the gaps of a stored analysis to plan and how to
+// sequence and group them.
+type PlanRequest struct {
+	// Controls lists the control IDs of the gaps to plan. Empty plans
+	// every open gap.
+	Controls []string `json:"controls,omitempty"`
+	// GroupBy groups tasks into phases by quarter (default), priority or
+	// effort.
+	GroupBy string `json:"group_by,omitempty"`
+	// Capacity is the effort points per quarter.
+	Capacity int `json:"capacity,omitempty"`
+	// Start is the first quarter, as 2027-Q1 or a date. Defaults to the
+	// quarter of the analysis.
+	Start string `json:"start,omitempty"`
+	// Dependencies maps control IDs to the control IDs they depend on, in
+	// addition to those inferred from the catalog.
+	Dependencies map[string][]string `json:"dependencies,omitempty"`
+}
+
+// CreateRemediationPlan serves POST /controls/gaps/:id/plan: the selected
+// gaps of a stored analysis as a phased, dated remediation plan whose tasks
+// depend on each other and on the catalog activities the gaps share. The
+// format query parameter, or else the Accept header, selects json
+// (default), csv or xlsx. The plan is not stored.
+func (h *Handlers) CreateRemediationPlan(c *gin.Context) {
+	if h.GapAnalyses == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analysis history not configured"})
+		return
+	}
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+	format, ok := negotiateReportFormat(c, controls.ReportJSON, controls.ReportCSV, controls.ReportXLSX)
+	if !ok {
+		return
+	}
+	var req PlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if req.Capacity < 0 || req.Capacity > maxRoadmapCapacity {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "capacity must be between 1 and 1000"})
+		return
+	}
+	if len(req.Controls) > maxPlanControls {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many controls", "details": "select at most 500 gaps"})
+		return
+	}
+	switch req.GroupBy {
+	case "", controls.PlanByQuarter, controls.PlanByPriority, controls.PlanByEffort:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group_by", "details": "use quarter, priority or effort"})
+		return
+	}
+	opts := controls.PlanOptions{
+		RoadmapOptions: controls.RoadmapOptions{Capacity: req.Capacity, Dependencies: req.Dependencies},
+		Controls:       req.Controls,
+		GroupBy:        req.GroupBy,
+	}
+	if req.Start != "" {
+		start, err := controls.ParseQuarter(req.Start)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start", "details": err.Error()})
+			return
+		}
+		opts.Start = start
+	}
+	ga, ok := h.loadGapAnalysis(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.GapAnalyzer.RemediationPlan(ga, opts)
+	if err != nil {
+		// Unknown selections, dependency cycles, or a framework since
+		// removed from the catalog.
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "cannot build remediation plan", "details": err.Error()})
+		return
+	}
+	if format != controls.ReportJSON {
+		writeReport(c, "plan-"+ga.TargetFrameworkID+"-"+ga.AnalysisDate.Format("20060102"), format, func(buf *bytes.Buffer) error {
+			return controls.WritePlan(buf, plan, format)
+		})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
@@ -2,31 +2,187 @@
 package api
 
 import (
-	"crypto/subtle"
+	"errors"
 	"net/http"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/apm"
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/canary"
 	"github.com/agentguard/agentguard/internal/config"
 	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/detect"
+	"github.com/agentguard/agentguard/internal/fingerprint"
+	"github.com/agentguard/agentguard/internal/hashing"
+	"github.com/agentguard/agentguard/internal/honeypot"
+	"github.com/agentguard/agentguard/internal/ingest"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/multiagent"
+	"github.com/agentguard/agentguard/internal/outputs"
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/agentguard/agentguard/internal/profiles"
+	"github.com/agentguard/agentguard/internal/prompts"
 	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/response"
+	"github.com/agentguard/agentguard/internal/severity"
+	"github.com/agentguard/agentguard/internal/siem"
+	"github.com/agentguard/agentguard/internal/signing"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/agentguard/agentguard/internal/workload"
 	"github.com/agentguard/agentguard/pkg/opa"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // scopeKey is the gin context key for storing JWT scopes.
 const scopeKey = "auth_scopes"
 
+// apiKeyKey is the gin context key for the ID of the API key that
+// authenticated the request.
+const apiKeyKey = "api_key_id"
+
 // RouterDeps holds dependencies for router initialization.
 type RouterDeps struct {
 	ControlRepo  repository.ControlRepository
 	GapAnalyzer  *controls.GapAnalyzer
 	PolicyEngine *opa.Engine
+	// Jobs executes long-running operations asynchronously. Optional.
+	Jobs *jobs.Manager
+	// Ingest and TraceWriter back POST /observe/traces. Both are required to
+	// enable ingestion.
+	Ingest      *ingest.Pipeline
+	TraceWriter repository.TraceWriter
+	// TraceList serves GET /observe/traces from stored trace metadata.
+	// Optional.
+	TraceList repository.TraceLister
+	// SignalList serves GET /observe/signals, signals on ingested traces
+	// included. Optional.
+	SignalList repository.SignalLister
+	// Response runs automated containment for ingested signals. Optional.
+	Response *response.Engine
+	// Profiles selects guardrail strictness by agent environment. When nil,
+	// every decision is enforced.
+	Profiles *profiles.Registry
+	// Agents is the agent registry. Optional.
+	Agents repository.AgentRepository
+	// Policies stores policy definitions. Optional.
+	Policies repository.PolicyRepository
+	// ThreatModels stores threat models. Optional.
+	ThreatModels repository.ThreatModelRepository
+	// AgentActivity reports when agents last sent traces, for onboarding
+	// checklists. Optional.
+	AgentActivity repository.AgentActivityReader
+	// Canaries issues knowledge base canary tokens and finds them in
+	// post-invoke outputs. Optional.
+	Canaries *canary.Registry
+	// DetectionRules holds the hot-reloaded detection rules run at ingest.
+	// Optional.
+	DetectionRules *detect.Engine
+	// Honeypots binds decoy tools to agents. Pre-invoke calls to one are
+	// denied and raise a critical signal. Optional.
+	Honeypots *honeypot.Registry
+	// Fingerprints tracks per-session behavior and flags abrupt changes
+	// consistent with prompt hijacking or account takeover. Optional.
+	Fingerprints *fingerprint.Tracker
+	// OutputSchemas validates tool outputs reported to the post-invoke hook.
+	// Optional.
+	OutputSchemas *outputs.Registry
+	// Failure decides whether calls fail open when no policy decision can be
+	// made. When nil, they fail closed unless the profile opens them.
+	Failure *profiles.FailurePolicy
+	// SignalWriter persists fail-open and invalid output signals. Optional;
+	// they are always logged.
+	SignalWriter repository.SignalWriter
+	// SignalExports publishes security signals, including those on ingested
+	// traces, to monitoring platforms and SIEMs. Optional.
+	SignalExports *apm.Publisher
+	// Severity adjusts signal severities by the criticality of the agent
+	// and data involved before signals are stored, exported or handed to
+	// the response engine. Optional; ingested traces are re-scored by the
+	// pipeline.
+	Severity *severity.Scorer
+	// Syslog forwards policy denials to a SIEM as CEF events. Optional;
+	// signals reach it through SignalExports.
+	Syslog *siem.Syslog
+	// DecisionBudget bounds pre-invoke evaluation time. Zero disables it.
+	DecisionBudget time.Duration
+	// DecisionCache serves recent decisions when the budget is exceeded.
+	// Optional.
+	DecisionCache *opa.DecisionCache
+	// Prompts tracks prompt hash reuse and the prompt blocklist. Optional.
+	Prompts *prompts.Registry
+	// Traces backs trace export. Optional.
+	Traces repository.TraceReader
+	// Payloads serves span payloads stored by content hash. Optional.
+	Payloads *storage.ContentStore
+	// Violations records the rules behind denied pre-invoke decisions.
+	// Optional.
+	Violations repository.ViolationWriter
+	// ToolUsage backs per-agent tool analytics. Optional.
+	ToolUsage repository.ToolUsageRepository
+	// Suggester proposes crosswalk mappings for review. Optional; requires
+	// GapAnalyzer.
+	Suggester *controls.CrosswalkSuggester
+	// Coverage records gap analysis coverage over time. Optional.
+	Coverage *controls.CoverageHistory
+	// GapAnalyses stores gap analysis runs and backs their history.
+	// Optional; requires ControlRepo.
+	GapAnalyses repository.GapAnalysisRepository
+	// Implementations tracks control implementations and supplies the
+	// implemented controls of gap analyses that omit them. Optional;
+	// requires ControlRepo.
+	Implementations repository.ControlImplementationRepository
+	// Attestations stores attestation campaigns, which assign tracked
+	// implementations to their owners. Optional; requires Implementations.
+	Attestations repository.AttestationRepository
+	// GapDispositions stores decisions to remediate, accept or transfer
+	// control gaps. Optional; requires ControlRepo.
+	GapDispositions repository.GapDispositionRepository
+	// Workload authenticates agents on /sdk routes by workload identity
+	// token instead of the static bearer token. Optional.
+	Workload *workload.Verifier
+	// SVIDs authenticates agents on /sdk routes by SPIFFE client
+	// certificate. Optional; requires the server to terminate mTLS.
+	SVIDs *workload.SVIDVerifier
+	// SigningKeys verifies HMAC-signed requests on /sdk routes, in addition
+	// to authentication. Optional.
+	SigningKeys *signing.Keys
+	// APIKeys are the bearer tokens accepted outside dev mode. When nil,
+	// every request needing one is rejected.
+	APIKeys *apikey.Keyring
+	// Spawn checks spawn_agent calls against the delegations declared in
+	// agent groups. Optional.
+	Spawn *multiagent.SpawnPolicy
+	// Audit records every pre-invoke decision in a hash-chained log.
+	// Optional.
+	Audit *audit.Log
+	// Privacy runs data subject erasure across observability stores.
+	// Optional.
+	Privacy *privacy.Eraser
+	// Hasher computes salted prompt hashes for pre-invoke requests that
+	// send the raw prompt. Optional.
+	Hasher *hashing.Hasher
+	// Pseudonyms re-identifies pseudonymized user and session IDs for
+	// investigations. Optional.
+	Pseudonyms *privacy.Pseudonymizer
+	// Metrics serves /observe/metrics from rollups. Optional.
+	Metrics repository.MetricsRepository
+	// MetricsHandler serves Prometheus metrics at /metrics when set.
+	MetricsHandler http.Handler
 	// StopRateLimiter is set by NewRouter. Call it during graceful shutdown to stop
 	// the rate limiter's background cleanup goroutine.
 	StopRateLimiter func()
+	// StopLoadShedder is set by NewRouter when load shedding is enabled. Call
+	// it during graceful shutdown to stop its CPU pressure sampler.
+	StopLoadShedder func()
 }
 
 // NewRouter creates and configures the HTTP router.
@@ -39,21 +195,56 @@ func NewRouter(cfg *config.Config, deps *RouterDeps) *gin.Engine {
 	r.SetTrustedProxies(nil)
 	r.Use(gin.Recovery())
 	r.Use(securityHeadersMiddleware())
+	if cfg.Server.LoadShedding.Enabled {
+		var signals []func() float64
+		if deps != nil && deps.Jobs != nil {
+			signals = append(signals, deps.Jobs.QueueLoad)
+		}
+		shedder := newLoadShedder(cfg.Server.LoadShedding, signals...)
+		if deps != nil {
+			deps.StopLoadShedder = shedder.Stop
+		}
+		r.Use(shedder.Middleware())
+	}
 	r.Use(func(c *gin.Context) {
 		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 1<<20) // 1MB
 		c.Next()
 	})
 	r.Use(corsMiddleware(cfg.Server.CORSOrigins))
+	if cfg.Server.Dev {
+		r.Use(devModeMiddleware())
+	}
+	if cfg.Server.Compression.Enabled {
+		r.Use(compressionMiddleware(cfg.Server.Compression))
+	}
 
 	// Create handlers with dependencies
 	var h *Handlers
 	if deps != nil && deps.ControlRepo != nil {
 		h = NewHandlers(deps.ControlRepo, deps.GapAnalyzer)
+		h.Jobs = deps.Jobs
+		h.Coverage = deps.Coverage
+		h.GapAnalyses = deps.GapAnalyses
+		h.Implementations = deps.Implementations
+		h.Attestations = deps.Attestations
+		h.GapDispositions = deps.GapDispositions
+		h.Agents = deps.Agents
+		h.Policies = deps.Policies
+		h.SigningKeys = deps.SigningKeys
+		if cfg.Controls.DataDir != "" {
+			h.CatalogDir = filepath.Join(cfg.Controls.DataDir, "catalogs")
+		}
 	}
 
 	// Health check
 	r.GET("/health", healthCheck)
 	r.GET("/ready", makeReadinessCheck(deps))
+	if deps != nil && deps.MetricsHandler != nil {
+		r.GET("/metrics", gin.WrapH(deps.MetricsHandler))
+	}
+	if cfg.Server.Dev {
+		mountConsole(r)
+	}
 
 	// API v1
 	rl := newRateLimiter(100, time.Minute)
@@ -65,66 +256,259 @@ func NewRouter(cfg *config.Config, deps *RouterDeps) *gin.Engine {
 	// Middleware order: Auth → Rate Limiting so that:
 	// 1. Unauthenticated requests are rejected before consuming rate limit budget.
 	// 2. Rate limits key on bearer identity rather than IP (set after auth validates token).
-	v1.Use(bearerTokenMiddleware(cfg.Auth.BearerToken))
+	var tokens *workload.Verifier
+	var svids *workload.SVIDVerifier
+	if deps != nil {
+		tokens, svids = deps.Workload, deps.SVIDs
+	}
+	auth := devAuthMiddleware()
+	if !cfg.Server.Dev {
+		var keys *apikey.Keyring
+		if deps != nil {
+			keys = deps.APIKeys
+		}
+		auth = bearerTokenMiddleware(keys)
+	}
+	v1.Use(workloadAuthMiddleware(tokens, svids, auth))
 	v1.Use(rateLimitMiddleware(rl))
+	// Per-organization quotas apply after per-identity rate limiting.
+	quotas := newQuotaTracker(cfg.Quotas)
+	v1.Use(orgMiddleware(cfg.Quotas, cfg.Server.Dev))
+	v1.Use(quotaMiddleware(quotas))
 	{
+		// Usage accounting for the caller's organization
+		v1.GET("/usage", makeUsageHandler(quotas))
+
+		// Rate limiter administration
+		limits := v1.Group("/admin/ratelimits", requireScope(cfg.Auth.Provider, "admin:ratelimits"))
+		{
+			limits.GET("", makeListRateLimitsHandler(rl))
+			limits.GET("/:key", makeGetRateLimitHandler(rl))
+			limits.DELETE("", makeResetAllRateLimitsHandler(rl))
+			limits.DELETE("/:key", makeResetRateLimitHandler(rl))
+		}
+
+		// Async job endpoints. Callers only see their own jobs, and need the
+		// scope of the route that submitted a job to read or cancel it.
+		if deps != nil && deps.Jobs != nil {
+			jobsGroup := v1.Group("/jobs")
+			jobsGroup.GET("", makeListJobsHandler(deps.Jobs, cfg.Auth.Provider))
+			jobsGroup.GET("/:id", makeGetJobHandler(deps.Jobs, cfg.Auth.Provider))
+			jobsGroup.GET("/:id/result", makeGetJobResultHandler(deps.Jobs, cfg.Auth.Provider))
+			jobsGroup.DELETE("/:id", makeCancelJobHandler(deps.Jobs, cfg.Auth.Provider))
+		}
+
 		// Control Framework endpoints
-		controls := v1.Group("/controls")
+		controls := v1.Group("/controls", requireReadScope(cfg.Auth.Provider, "read:controls"))
 		{
+			// Catalog GETs support ETags so SDKs can cache them.
+			cacheable := conditionalGetMiddleware()
+
 			if h != nil {
 				// Use repository-backed handlers
-				controls.GET("/frameworks", h.ListFrameworks)
-				controls.GET("/frameworks/:id", h.GetFramework)
-				controls.GET("/frameworks/:id/controls", h.ListControls)
-				controls.GET("/controls/:id", h.GetControl)
-				controls.GET("/crosswalk", h.GetCrosswalk)
+				controls.GET("/frameworks", cacheable, h.ListFrameworks)
+				controls.GET("/frameworks/:id", cacheable, h.GetFramework)
+				controls.GET("/frameworks/:id/controls", cacheable, h.ListControls)
+				controls.GET("/tags", h.ListControlTags)
+				controls.GET("/frameworks/:id/versions", cacheable, makeFrameworkVersionsHandler(h.frameworkVersions))
+				controls.GET("/frameworks/:id/diff", cacheable, makeFrameworkDiffHandler(h.frameworkVersion))
+				controls.GET("/search", cacheable, makeControlSearchHandler(h.searchControls))
+				controls.GET("/controls/:id", cacheable, h.GetControl)
+				controls.GET("/crosswalk", cacheable, h.GetCrosswalk)
 				writeScope := requireScope(cfg.Auth.Provider, "write:controls")
 				controls.POST("/frameworks", writeScope, h.CreateFramework)
 				controls.POST("/controls", writeScope, h.CreateControl)
+				controls.PUT("/frameworks/:id/controls/:control/tags", writeScope, h.SetControlTags)
+				controls.POST("/import", writeScope, h.ImportCatalog)
+				controls.POST("/import/oscal", writeScope, h.ImportOSCALCatalog)
+				controls.POST("/seed", requireScope(cfg.Auth.Provider, "admin:catalog"), h.SeedCatalogs)
+				controls.POST("/crosswalk", writeScope, h.CreateCrosswalk)
+				controls.PUT("/crosswalk/:id", writeScope, h.UpdateCrosswalk)
+				controls.DELETE("/crosswalk/:id", writeScope, h.DeleteCrosswalk)
+				controls.POST("/crosswalk/:id/review", writeScope, h.ReviewCrosswalk)
+				controls.GET("/crosswalks/export", h.ExportCrosswalks)
+				controls.POST("/crosswalks/import", writeScope, h.ImportCrosswalks)
+				controls.GET("/gaps", h.ListGapAnalyses)
+				controls.GET("/gaps/:id", h.GetGapAnalysis)
+				controls.GET("/gaps/:id/poam", h.GetGapAnalysisPOAM)
+				controls.POST("/gaps/:id/plan", h.CreateRemediationPlan)
+				controls.GET("/ssp", h.GetSSP)
+				controls.GET("/posture", h.GetPosture)
 				controls.POST("/gaps/analyze", writeScope, h.AnalyzeGaps)
+				controls.GET("/gap-dispositions", h.ListGapDispositions)
+				controls.GET("/gap-dispositions/:id", h.GetGapDisposition)
+				controls.POST("/gap-dispositions", writeScope, h.CreateGapDisposition)
+				controls.PUT("/gap-dispositions/:id", writeScope, h.UpdateGapDisposition)
+				controls.POST("/gap-dispositions/:id/review", writeScope, h.ReviewGapDisposition)
+				controls.DELETE("/gap-dispositions/:id", writeScope, h.DeleteGapDisposition)
+				controls.GET("/implementations", h.ListImplementations)
+				controls.GET("/implementations/:id", h.GetImplementation)
+				controls.POST("/implementations", writeScope, h.CreateImplementation)
+				controls.PUT("/implementations/:id", writeScope, h.UpdateImplementation)
+				controls.DELETE("/implementations/:id", writeScope, h.DeleteImplementation)
+				controls.GET("/attestation-campaigns", h.ListAttestationCampaigns)
+				controls.GET("/attestation-campaigns/:id", h.GetAttestationCampaign)
+				controls.GET("/attestation-campaigns/:id/report", h.GetAttestationReport)
+				controls.POST("/attestation-campaigns", writeScope, h.CreateAttestationCampaign)
+				controls.POST("/attestation-campaigns/:id/close", writeScope, h.CloseAttestationCampaign)
+				controls.GET("/attestations", h.ListAttestations)
+				controls.POST("/attestations/:id/attest", writeScope, h.AttestControl)
+				controls.POST("/attestations/:id/decline", writeScope, h.DeclineAttestation)
+				controls.GET("/scoring", h.GetScoringModel)
+				controls.PUT("/scoring", writeScope, h.UpdateScoringModel)
+				controls.GET("/providers", h.ListControlProviders)
+				controls.GET("/providers/:id", h.GetControlProvider)
+				controls.POST("/providers", writeScope, h.RegisterControlProvider)
+				controls.DELETE("/providers/:id", writeScope, h.DeleteControlProvider)
+				controls.GET("/monitoring", h.GetMonitoringStatus)
+				controls.POST("/monitoring/run", writeScope, h.RunMonitoringChecks)
 			} else {
 				// Fallback to stub handlers (for testing without DB)
-				controls.GET("/frameworks", listFrameworks)
-				controls.GET("/frameworks/:id", getFramework)
-				controls.GET("/frameworks/:id/controls", listControls)
-				controls.GET("/controls/:id", getControl)
-				controls.GET("/crosswalk", getCrosswalk)
+				if deps != nil && deps.GapAnalyzer != nil {
+					controls.GET("/frameworks", cacheable, makeLoadedFrameworksHandler(deps.GapAnalyzer))
+					controls.GET("/frameworks/:id", cacheable, makeLoadedFrameworkHandler(deps.GapAnalyzer))
+					controls.GET("/frameworks/:id/controls", cacheable, makeLoadedControlsHandler(deps.GapAnalyzer))
+					controls.GET("/frameworks/:id/versions", cacheable, makeFrameworkVersionsHandler(loadedVersions(deps.GapAnalyzer)))
+					controls.GET("/frameworks/:id/diff", cacheable, makeFrameworkDiffHandler(loadedVersion(deps.GapAnalyzer)))
+					controls.GET("/search", cacheable, makeControlSearchHandler(deps.GapAnalyzer.SearchControls))
+				} else {
+					controls.GET("/frameworks", cacheable, listFrameworks)
+					controls.GET("/frameworks/:id", cacheable, getFramework)
+					controls.GET("/frameworks/:id/controls", cacheable, listControls)
+				}
+				controls.GET("/controls/:id", cacheable, getControl)
+				controls.GET("/crosswalk", cacheable, getCrosswalk)
 				controls.POST("/gaps/analyze", requireScope(cfg.Auth.Provider, "write:controls"), analyzeGaps)
 			}
+			if deps != nil && deps.Coverage != nil {
+				controls.GET("/coverage/badge", makeCoverageBadgeHandler(deps.Coverage))
+			}
+			if deps != nil && deps.GapAnalyzer != nil {
+				controls.POST("/roadmap", makeRoadmapHandler(deps.GapAnalyzer))
+				controls.POST("/applicability", makeApplicabilityHandler(deps.GapAnalyzer))
+			}
+			if deps != nil && deps.GapAnalyzer != nil && deps.Suggester != nil {
+				controls.POST("/crosswalk/suggest", requireScope(cfg.Auth.Provider, "write:controls"), makeCrosswalkSuggestHandler(deps.GapAnalyzer, deps.Suggester, deps.Jobs))
+			}
 		}
 
 		// Agent Registry endpoints
 		agents := v1.Group("/agents")
 		{
-			agents.GET("", listAgents)
-			agents.POST("", registerAgent)
-			agents.GET("/:id", getAgent)
-			agents.PUT("/:id", updateAgent)
-			agents.DELETE("/:id", deleteAgent)
-			agents.GET("/:id/policies", getAgentPolicies)
-			agents.PUT("/:id/policies", bindAgentPolicies)
+			if h != nil {
+				agentWrite := requireScope(cfg.Auth.Provider, "write:agents")
+				agents.GET("", h.ListAgents)
+				agents.POST("", agentWrite, h.RegisterAgent)
+				agents.GET("/:id", h.GetAgent)
+				agents.PUT("/:id", agentWrite, h.UpdateAgent)
+				agents.DELETE("/:id", agentWrite, h.DeleteAgent)
+				agents.GET("/:id/policies", h.GetAgentPolicies)
+				agents.PUT("/:id/policies", agentWrite, h.BindAgentPolicies)
+				agents.GET("/:id/signing-keys", h.ListSigningKeys)
+				agents.POST("/:id/signing-keys", agentWrite, h.RotateSigningKey)
+				agents.DELETE("/:id/signing-keys/:key_id", agentWrite, h.RevokeSigningKey)
+			} else {
+				agents.GET("", listAgents)
+				agents.POST("", registerAgent)
+				agents.GET("/:id", getAgent)
+				agents.PUT("/:id", updateAgent)
+				agents.DELETE("/:id", deleteAgent)
+				agents.GET("/:id/policies", getAgentPolicies)
+				agents.PUT("/:id/policies", bindAgentPolicies)
+			}
+			if deps != nil {
+				agents.GET("/:id/onboarding", makeAgentOnboardingHandler(deps))
+			}
+			if deps != nil && deps.GapAnalyzer != nil {
+				agents.GET("/:id/baseline", makeAgentBaselineHandler(deps))
+			}
+			if deps != nil && deps.ToolUsage != nil {
+				agents.GET("/:id/tool-usage", makeToolUsageHandler(deps.ToolUsage))
+				agents.GET("/:id/attack-paths", makeAgentAttackPathsHandler(deps.ToolUsage))
+			}
 		}
 
 		// Observability endpoints
 		observe := v1.Group("/observe")
 		{
-			observe.POST("/traces", ingestTrace)
-			observe.GET("/traces", queryTraces)
+			if deps != nil && deps.Ingest != nil && deps.TraceWriter != nil {
+				observe.POST("/traces", ingestQuotaMiddleware(quotas), makeIngestTraceHandler(deps.Ingest, deps.TraceWriter, deps.Response, deps.SignalExports))
+			} else {
+				observe.POST("/traces", ingestQuotaMiddleware(quotas), ingestTrace)
+			}
+			if deps != nil && deps.TraceList != nil {
+				observe.GET("/traces", makeListTracesHandler(deps.TraceList))
+			} else {
+				observe.GET("/traces", queryTraces)
+			}
 			observe.GET("/traces/:id", getTrace)
 			observe.GET("/traces/:id/spans", getTraceSpans)
-			observe.GET("/signals", querySecuritySignals)
+			if deps != nil && deps.Traces != nil {
+				observe.GET("/traces/:id/export", makeExportTraceHandler(deps.Traces))
+				if links, ok := deps.Traces.(repository.DelegationRepository); ok {
+					observe.GET("/traces/:id/delegation", makeTraceDelegationHandler(links))
+				}
+			}
+			if deps != nil && deps.SignalList != nil {
+				observe.GET("/signals", makeListSignalsHandler(deps.SignalList))
+			} else {
+				observe.GET("/signals", querySecuritySignals)
+			}
 			observe.GET("/anomalies", getAnomalies)
-			observe.GET("/metrics", getMetrics)
+			if deps != nil && deps.Prompts != nil {
+				policyWrite := requireScope(cfg.Auth.Provider, "write:policies")
+				observe.GET("/prompts", makeListPromptsHandler(deps.Prompts))
+				observe.GET("/prompts/offenders", makeListPromptOffendersHandler(deps.Prompts))
+				observe.GET("/prompts/blocklist", makeListPromptBlocklistHandler(deps.Prompts))
+				observe.GET("/prompts/hash/:hash", makeGetPromptHandler(deps.Prompts))
+				observe.PUT("/prompts/hash/:hash/block", policyWrite, makeBlockPromptHandler(deps.Prompts))
+				observe.DELETE("/prompts/hash/:hash/block", policyWrite, makeUnblockPromptHandler(deps.Prompts))
+			}
+			if deps != nil && deps.Payloads != nil {
+				// Payloads may hold raw tool inputs and outputs, so reading
+				// them needs a scope of its own.
+				observe.GET("/payloads/:hash", requireScope(cfg.Auth.Provider, "read:payloads"), makeGetPayloadHandler(deps.Payloads))
+			}
+			if deps != nil && deps.Metrics != nil {
+				observe.GET("/denials/heatmap", makeDenialHeatmapHandler(deps.Metrics))
+				observe.GET("/metrics", makeMetricsHandler(deps.Metrics))
+			} else {
+				observe.GET("/metrics", getMetrics)
+			}
+		}
+
+		// Grafana JSON datasource endpoints
+		if deps != nil && (deps.Metrics != nil || deps.Coverage != nil) {
+			src := newGrafanaSource(deps.Metrics, deps.Coverage, cfg.Observability.Pricing)
+			grafana := v1.Group("/grafana")
+			grafana.GET("", src.handleTest)
+			grafana.POST("/metrics", src.handleMetrics)
+			grafana.POST("/metric-payload-options", src.handlePayloadOptions)
+			grafana.POST("/query", src.handleQuery)
 		}
 
+		// Reading policies, profiles, detection and response configuration
+		// needs read:policies; see apikey.Scopes.
+		policyRead := requireReadScope(cfg.Auth.Provider, "read:policies")
+
 		// Policy endpoints
-		policies := v1.Group("/policies")
+		policies := v1.Group("/policies", policyRead)
 		{
-			policies.GET("", listPolicies)
-			policies.POST("", createPolicy)
-			policies.GET("/:id", getPolicy)
-			policies.PUT("/:id", updatePolicy)
-			policies.DELETE("/:id", deletePolicy)
+			if h != nil {
+				policyWrite := requireScope(cfg.Auth.Provider, "write:policies")
+				policies.GET("", h.ListPolicies)
+				policies.POST("", policyWrite, h.CreatePolicy)
+				policies.GET("/types/:type", h.ListPoliciesByType)
+				policies.GET("/:id", h.GetPolicy)
+				policies.PUT("/:id", policyWrite, h.UpdatePolicy)
+				policies.DELETE("/:id", policyWrite, h.DeletePolicy)
+			} else {
+				policies.GET("", listPolicies)
+				policies.POST("", createPolicy)
+				policies.GET("/:id", getPolicy)
+				policies.PUT("/:id", updatePolicy)
+				policies.DELETE("/:id", deletePolicy)
+			}
 			policies.POST("/validate", validatePolicy)
 			policies.POST("/evaluate", evaluatePolicy)
 		}
@@ -136,8 +520,14 @@ func NewRouter(cfg *config.Config, deps *RouterDeps) *gin.Engine {
 			threats.POST("/models", createThreatModel)
 			threats.GET("/models/:id", getThreatModel)
 			threats.PUT("/models/:id", updateThreatModel)
+			if deps != nil && deps.Metrics != nil {
+				threats.POST("/models/calibrate", makeCalibrateThreatModelHandler(deps.Metrics))
+			}
 			threats.POST("/analyze", analyzeThreat)
-			threats.GET("/atlas", getATLASMapping)
+			threats.POST("/attack-paths", analyzeAttackPaths)
+			threats.POST("/groups/analyze", analyzeAgentGroup)
+			threats.GET("/atlas", getATLASCatalog)
+			threats.GET("/atlas/techniques/:id", getATLASTechnique)
 		}
 
 		// Maturity Assessment endpoints
@@ -151,11 +541,87 @@ func NewRouter(cfg *config.Config, deps *RouterDeps) *gin.Engine {
 			maturity.GET("/benchmarks", getBenchmarks)
 		}
 
+		// Automated response actions and agent containment
+		if deps != nil && deps.Response != nil {
+			resp := v1.Group("/response", policyRead)
+			resp.GET("/rules", makeListResponseRulesHandler(deps.Response))
+			resp.GET("/actions", makeListResponseActionsHandler(deps.Response))
+			resp.POST("/actions/:id/rollback", requireScope(cfg.Auth.Provider, "write:policies"), makeRollbackResponseActionHandler(deps.Response))
+			resp.GET("/containment", makeListContainmentHandler(deps.Response))
+			resp.PUT("/quarantine/:agent_id", requireScope(cfg.Auth.Provider, "write:policies"), makeQuarantineAgentHandler(deps.Response))
+			resp.DELETE("/quarantine/:agent_id", requireScope(cfg.Auth.Provider, "write:policies"), makeReleaseAgentHandler(deps.Response))
+			resp.GET("/suggestions", makeListResponseSuggestionsHandler(deps.Response))
+			resp.POST("/suggestions/:id/accept", requireScope(cfg.Auth.Provider, "write:policies"), makeAcceptSuggestionHandler(deps.Response))
+		}
+
+		// Per-environment guardrail profiles
+		if deps != nil && deps.Profiles != nil {
+			prof := v1.Group("/profiles", policyRead)
+			prof.GET("", makeListProfilesHandler(deps.Profiles))
+			prof.GET("/resolve", makeResolveProfileHandler(deps.Profiles))
+			prof.GET("/:name", makeGetProfileHandler(deps.Profiles))
+			prof.PUT("/:name", requireScope(cfg.Auth.Provider, "write:policies"), makePutProfileHandler(deps.Profiles))
+			prof.DELETE("/:name", requireScope(cfg.Auth.Provider, "write:policies"), makeDeleteProfileHandler(deps.Profiles))
+		}
+
+		// Structured output schemas checked by the post-invoke hook
+		if deps != nil && deps.OutputSchemas != nil {
+			outs := v1.Group("/outputs/schemas", policyRead)
+			outs.GET("", makeListOutputSchemasHandler(deps.OutputSchemas))
+			outs.GET("/:id", makeGetOutputSchemaHandler(deps.OutputSchemas))
+			outs.PUT("/:id", requireScope(cfg.Auth.Provider, "write:policies"), makePutOutputSchemaHandler(deps.OutputSchemas))
+			outs.DELETE("/:id", requireScope(cfg.Auth.Provider, "write:policies"), makeDeleteOutputSchemaHandler(deps.OutputSchemas))
+		}
+
+		// Knowledge base canaries for exfiltration detection
+		if deps != nil && deps.Canaries != nil {
+			can := v1.Group("/canaries", policyRead)
+			can.GET("", makeListCanariesHandler(deps.Canaries))
+			can.GET("/:id", makeGetCanaryHandler(deps.Canaries))
+			can.POST("", requireScope(cfg.Auth.Provider, "write:policies"), makeIssueCanaryHandler(deps.Canaries))
+			can.DELETE("/:id", requireScope(cfg.Auth.Provider, "write:policies"), makeDeleteCanaryHandler(deps.Canaries))
+		}
+
+		// Detection rules loaded from the rules directory
+		if deps != nil && deps.DetectionRules != nil {
+			dr := v1.Group("/detection/rules", policyRead)
+			dr.GET("", makeListDetectionRulesHandler(deps.DetectionRules))
+			dr.GET("/:id", makeGetDetectionRuleHandler(deps.DetectionRules))
+			dr.PATCH("/:id", requireScope(cfg.Auth.Provider, "write:policies"), makeSetDetectionRuleHandler(deps.DetectionRules))
+			dr.POST("/reload", requireScope(cfg.Auth.Provider, "write:policies"), makeReloadDetectionRulesHandler(deps.DetectionRules))
+		}
+
+		// Decoy tools that no legitimate workflow calls
+		if deps != nil && deps.Honeypots != nil {
+			hp := v1.Group("/honeypots", policyRead)
+			hp.GET("", makeListHoneypotsHandler(deps.Honeypots))
+			hp.GET("/:id", makeGetHoneypotHandler(deps.Honeypots))
+			hp.PUT("/:id", requireScope(cfg.Auth.Provider, "write:policies"), makePutHoneypotHandler(deps.Honeypots))
+			hp.DELETE("/:id", requireScope(cfg.Auth.Provider, "write:policies"), makeDeleteHoneypotHandler(deps.Honeypots))
+		}
+
+		// Data subject erasure and re-identification
+		priv := v1.Group("/privacy")
+		if deps != nil && deps.Privacy != nil {
+			erasure := priv.Group("/erasure", requireScope(cfg.Auth.Provider, "admin:privacy"))
+			erasure.POST("", makeEraseSubjectHandler(deps.Privacy, deps.Jobs))
+			erasure.GET("", makeListErasureReportsHandler(deps.Privacy))
+			erasure.GET("/:id", makeGetErasureReportHandler(deps.Privacy))
+		}
+		if deps != nil && deps.Pseudonyms != nil {
+			// Re-identification has its own scope so erasure operators
+			// cannot unmask pseudonyms.
+			priv.POST("/reidentify", requireScope(cfg.Auth.Provider, "admin:reidentify"), makeReidentifyHandler(deps.Pseudonyms, deps.Audit))
+		}
+
 		// SDK webhook endpoints (for agent middleware callbacks)
 		sdk := v1.Group("/sdk")
+		if deps != nil && deps.SigningKeys != nil {
+			sdk.Use(sdkSigningMiddleware(deps.SigningKeys, cfg.Auth.RequestSigning.Required))
+		}
 		{
 			sdk.POST("/pre-invoke", makePreInvokeHook(deps))
-			sdk.POST("/post-invoke", postInvokeHook)
+			sdk.POST("/post-invoke", makePostInvokeHook(deps))
 			sdk.POST("/error", errorHook)
 		}
 	}
@@ -258,7 +724,11 @@ func rateLimitMiddleware(rl *rateLimiter) gin.HandlerFunc {
 		// Key on bearer token identity when present — more accurate for authenticated APIs
 		// and allows per-identity rate limits rather than per-IP (which breaks behind NAT).
 		key := c.ClientIP()
-		if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		if id := workloadIdentity(c); id != nil {
+			key = "workload:" + id.AgentID
+		} else if id := c.GetString(apiKeyKey); id != "" {
+			key = "api_key:" + id
+		} else if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
 			token := strings.TrimPrefix(auth, "Bearer ")
 			if len(token) >= 8 {
 				// Use last 8 chars as key suffix to avoid storing full tokens in memory.
@@ -305,7 +775,8 @@ func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
 				c.Header("Vary", "Origin")
 			}
 			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, If-None-Match, If-Modified-Since")
+			c.Header("Access-Control-Expose-Headers", "ETag, Last-Modified")
 			c.Header("Access-Control-Max-Age", "86400")
 		}
 
@@ -318,16 +789,30 @@ func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	}
 }
 
-func bearerTokenMiddleware(token string) gin.HandlerFunc {
-	if token == "" {
-		log.Warn().Msg("AUTH_BEARER_TOKEN is not configured — all API requests will be rejected")
+// bearerTokenMiddleware authenticates requests by bearer token against the
+// configured API keys and records which key was used, granting its scopes
+// and organization. Any active key is accepted, so a token can be rotated
+// while its predecessor is still valid.
+func bearerTokenMiddleware(keys *apikey.Keyring) gin.HandlerFunc {
+	if keys == nil || keys.Len() == 0 {
+		log.Warn().Msg("No API keys configured (AUTH_BEARER_TOKEN or auth.api_keys) — all API requests will be rejected")
 		return func(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		}
 	}
-	if len(token) < 32 {
-		log.Warn().Int("token_len", len(token)).
-			Msg("AUTH_BEARER_TOKEN is shorter than 32 chars — consider using a stronger token")
+	// Requests by key show when a rotated-out key is no longer in use.
+	requests, err := otel.Meter(instrumentationName).Int64Counter(
+		"agentguard_api_key_requests_total",
+		metric.WithDescription("Requests authenticated or refused by API key, by key ID and outcome"),
+	)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create API key metric")
+	}
+	count := func(c *gin.Context, id, outcome string) {
+		if requests != nil {
+			requests.Add(c.Request.Context(), 1, metric.WithAttributes(
+				attribute.String("api_key_id", id), attribute.String("outcome", outcome)))
+		}
 	}
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -335,17 +820,52 @@ func bearerTokenMiddleware(token string) gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
-		provided := strings.TrimPrefix(authHeader, "Bearer ")
-		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+		key, err := keys.Authenticate(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			if key.ID != "" {
+				outcome := "expired"
+				if errors.Is(err, apikey.ErrNotYetValid) {
+					outcome = "not_yet_valid"
+				}
+				count(c, key.ID, outcome)
+				log.Warn().Err(err).Str("api_key_id", key.ID).Str("path", c.Request.URL.Path).Msg("API key refused")
+			}
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
-		// Bearer token grants full read+write access — store synthetic scope set.
-		c.Set(scopeKey, []string{"read:controls", "write:controls"})
+		count(c, key.ID, "accepted")
+		c.Set(apiKeyKey, key.ID)
+		if key.Org != "" {
+			c.Set(orgKey, key.Org)
+		}
+		c.Set(scopeKey, key.Scopes)
 		c.Next()
 	}
 }
 
+// requestActor names who made a request in audit records: the API key that
+// authenticated it, else its workload identity, else its organization.
+func requestActor(c *gin.Context) string {
+	if id := c.GetString(apiKeyKey); id != "" {
+		return "api_key:" + id
+	}
+	if id := workloadIdentity(c); id != nil {
+		return id.Subject
+	}
+	return c.GetString(orgKey)
+}
+
+// hasScope reports whether the request was granted scope, which is always
+// the case in dev mode (auth.provider == "none") and for an empty scope.
+func hasScope(c *gin.Context, provider, scope string) bool {
+	if scope == "" || strings.EqualFold(provider, "none") {
+		return true
+	}
+	raw, _ := c.Get(scopeKey)
+	scopes, _ := raw.([]string)
+	return slices.Contains(scopes, scope)
+}
+
 // requireScope returns middleware that enforces the presence of a required scope
 // in the request context. In dev mode (auth.provider == "none"), scope checks
 // are bypassed. Scopes are populated by the auth middleware upstream.
@@ -383,6 +903,19 @@ func requireScope(provider, scope string) gin.HandlerFunc {
 	}
 }
 
+// requireReadScope returns middleware for a route group that enforces scope
+// on reads (GET and HEAD). Writes in the group check their own write scope.
+func requireReadScope(provider, scope string) gin.HandlerFunc {
+	check := requireScope(provider, scope)
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		check(c)
+	}
+}
+
 // Health endpoints
 
 func healthCheck(c *gin.Context) {
@@ -491,7 +1024,7 @@ func bindAgentPolicies(c *gin.Context) {
 // Observability handlers
 
 func ingestTrace(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"status": "not_implemented"})
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": "trace storage not configured", "details": "enable observability.clickhouse"})
 }
 
 func queryTraces(c *gin.Context) {
@@ -570,10 +1103,6 @@ func analyzeThreat(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"status": "not_implemented"})
 }
 
-func getATLASMapping(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"techniques": []any{}, "status": "not_implemented"})
-}
-
 // Maturity Assessment handlers
 
 func listAssessments(c *gin.Context) {
@@ -603,18 +1132,14 @@ func getBenchmarks(c *gin.Context) {
 // SDK webhook handlers
 
 // makePreInvokeHook returns a handler that evaluates the request against OPA policies.
-// Fail-closed: if no policy engine is configured, all requests are denied.
+// When no decision can be made the call fails closed unless the failure
+// policy opens it for the agent's risk level, environment, or this route;
+// every fail-open is recorded as a security signal. Evaluation that overruns
+// the latency budget is answered from the decision cache or the failure
+// mode's default and flagged as degraded.
 func makePreInvokeHook(deps *RouterDeps) gin.HandlerFunc {
+	metrics := newDecisionMetrics()
 	return func(c *gin.Context) {
-		// Fail-closed if policy engine not available
-		if deps == nil || deps.PolicyEngine == nil {
-			c.JSON(http.StatusForbidden, gin.H{
-				"allow":   false,
-				"reasons": []string{"policy engine not configured — denying by default"},
-			})
-			return
-		}
-
 		// Limit request body to 1MB to prevent memory exhaustion via large payloads
 		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 1<<20)
 
@@ -628,25 +1153,151 @@ func makePreInvokeHook(deps *RouterDeps) gin.HandlerFunc {
 			return
 		}
 
-		// Evaluate against OPA policies
-		decision, err := deps.PolicyEngine.Evaluate(c.Request.Context(), "default", &input)
-		if err != nil {
-			log.Error().Err(err).Msg("policy evaluation failed")
-			c.JSON(http.StatusForbidden, gin.H{
-				"allow":   false,
-				"reasons": []string{"policy evaluation failed — denying by default"},
-			})
+		if !bindWorkloadAgent(c, deps, &input.Agent.ID) {
+			return
+		}
+		var prompt any
+		if input.Request != nil {
+			prompt = input.Request.Prompt
+		}
+		if !hashPrompt(c, deps, input.Request) {
+			return
+		}
+		if springHoneypot(c, deps, &input) {
 			return
 		}
+		if observeSession(c, deps, &input, prompt) {
+			return
+		}
+
+		profile := strictProfile
+		if deps != nil && deps.Profiles != nil {
+			profile = deps.Profiles.Resolve(input.Agent.Environment)
+			if input.Environment == nil {
+				input.Environment = make(map[string]string)
+			}
+			input.Environment["profile"] = profile.Name
+		}
+
+		// Contained agents are denied before policy evaluation. Quarantined
+		// agents may still call tools on their allowlist.
+		quarantined := false
+		if deps != nil && deps.Response != nil {
+			tool := ""
+			if input.Tool != nil {
+				tool = input.Tool.Name
+			}
+			if ok, reason := deps.Response.Containment().Check(input.Agent.ID, tool); !ok {
+				auditDecision(c, deps, &input, &opa.Decision{Reasons: []string{reason}})
+				c.JSON(http.StatusForbidden, gin.H{
+					"allow":   false,
+					"reasons": []string{reason},
+				})
+				return
+			}
+			_, quarantined = deps.Response.Containment().Quarantined(input.Agent.ID)
+		}
+
+		var failure *profiles.FailurePolicy
+		if deps != nil {
+			failure = deps.Failure
+		}
+		failMode, failSource := failure.Resolve(routePreInvoke, profile, input.Agent.RiskLevel)
+
+		// Evaluate against OPA policies
+		var decision *opa.Decision
+		if deps == nil || deps.PolicyEngine == nil {
+			if failMode != profiles.FailOpen {
+				reason := "policy engine not configured — denying by default"
+				auditDecision(c, deps, &input, &opa.Decision{Reasons: []string{reason}})
+				c.JSON(http.StatusForbidden, gin.H{
+					"allow":   false,
+					"reasons": []string{reason},
+				})
+				return
+			}
+			decision = failOpen(c, deps, &input, "policy engine not configured", failSource)
+		} else {
+			ctx := c.Request.Context()
+			budget, shortened := decisionBudget(c, deps.DecisionBudget)
+			key := ""
+			if deps.DecisionCache != nil {
+				key = opa.DecisionKey(&input)
+			}
+			start := time.Now()
+			var err error
+			decision, err = evaluateWithinBudget(ctx, deps.PolicyEngine, &input, budget)
+			metrics.observe(ctx, time.Since(start), profile.Name)
+			switch {
+			case err == nil:
+				deps.DecisionCache.Put(key, decision)
+			case errors.Is(err, errBudgetExceeded):
+				// Serve the last decision for the same call, or the
+				// failure mode's default, flagged as degraded. A budget
+				// the SDK shortened itself never fails open.
+				if cached, age, ok := deps.DecisionCache.Get(key); ok {
+					metrics.budgetExceeded(ctx, "cached", profile.Name)
+					decision = cached
+					decision.Metadata["degraded_source"] = "cache"
+					decision.Metadata["cache_age_ms"] = age.Milliseconds()
+				} else if failMode == profiles.FailOpen && !shortened {
+					metrics.budgetExceeded(ctx, "fail_open", profile.Name)
+					decision = failOpen(c, deps, &input, errBudgetExceeded.Error(), failSource)
+					decision.Metadata["degraded_source"] = "fail_open"
+				} else {
+					metrics.budgetExceeded(ctx, "fail_closed", profile.Name)
+					denied := &opa.Decision{
+						Allow:    false,
+						Reasons:  []string{"decision latency budget exceeded — denying by default"},
+						Metadata: map[string]any{"degraded_source": "fail_closed", "budget_ms": budget.Milliseconds()},
+						Degraded: true,
+					}
+					auditDecision(c, deps, &input, denied)
+					c.JSON(http.StatusForbidden, denied)
+					return
+				}
+				decision.Degraded = true
+				decision.Metadata["budget_ms"] = budget.Milliseconds()
+				log.Warn().Str("agent_id", input.Agent.ID).Dur("budget", budget).
+					Interface("source", decision.Metadata["degraded_source"]).Msg("pre-invoke decision degraded")
+			default:
+				log.Error().Err(err).Str("profile", profile.Name).Msg("policy evaluation failed")
+				if failMode != profiles.FailOpen {
+					reason := "policy evaluation failed — denying by default"
+					auditDecision(c, deps, &input, &opa.Decision{Reasons: []string{reason}})
+					c.JSON(http.StatusForbidden, gin.H{
+						"allow":   false,
+						"reasons": []string{reason},
+					})
+					return
+				}
+				decision = failOpen(c, deps, &input, "policy evaluation failed", failSource)
+			}
+		}
 
+		if decision.Allow && !decision.Degraded && input.Delegation != nil {
+			enforceDelegation(c, deps, &input, decision, failMode)
+		}
+		if decision.Allow && deps != nil && deps.Spawn != nil && input.Tool != nil && input.Tool.Name == multiagent.SpawnTool {
+			enforceSpawn(deps.Spawn, &input, decision)
+		}
+
+		applyProfile(decision, &input, profile)
+		if deps != nil && deps.Violations != nil && !decision.Degraded {
+			recordViolations(c, deps.Violations, &input, decision)
+		}
+
+		// Tell the SDK to record every span for quarantined agents.
+		if quarantined {
+			decision.Metadata["quarantined"] = true
+			decision.Metadata["trace_sample_rate"] = 1.0
+		}
+
+		auditDecision(c, deps, &input, decision)
 		c.JSON(http.StatusOK, decision)
 	}
 }
 
-func postInvokeHook(c *gin.Context) {
-	c.JSON(http.StatusAccepted, gin.H{"status": "acknowledged"})
-}
-
 func errorHook(c *gin.Context) {
 	c.JSON(http.StatusAccepted, gin.H{"status": "acknowledged"})
 }
@@ -0,0 +1,117 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+// testConfig returns the configuration of an authenticated server with
+// the default organization header.
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Auth.Provider = "static"
+	cfg.Quotas.OrgHeader = "X-AgentGuard-Org"
+	cfg.Quotas.DefaultOrg = "default"
+	return cfg
+}
+
+// newServer starts a router for cfg and deps, stopping its background
+// goroutines when the test ends.
+func newServer(t *testing.T, cfg *config.Config, deps *api.RouterDeps) http.Handler {
+	t.Helper()
+	if deps == nil {
+		deps = &api.RouterDeps{}
+	}
+	r := api.NewRouter(cfg, deps)
+	t.Cleanup(func() {
+		deps.StopRateLimiter()
+		if deps.StopLoadShedder != nil {
+			deps.StopLoadShedder()
+		}
+	})
+	return r
+}
+
+// mustKeys returns a keyring holding keys.
+func mustKeys(t *testing.T, keys ...apikey.Key) *apikey.Keyring {
+	t.Helper()
+	k, err := apikey.New(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// do sends a request with an optional JSON body, bearer token and extra
+// header pairs, and returns the response.
+func do(h http.Handler, method, path, token string, body any, header ...string) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// decode unmarshals a JSON response body.
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body, err)
+	}
+	return v
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		Agents:      memory.NewAgentRepository(),
+		Policies:    memory.NewPolicyRepository(),
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "default", Token: "default-token"},
+			apikey.Key{ID: "reader", Token: "reader-token", Scopes: []string{"read:controls"}},
+			apikey.Key{ID: "admin", Token: "admin-token", Scopes: []string{"admin:ratelimits"}},
+		),
+	})
+	agent := map[string]any{"name": "support-bot", "framework": "langchain", "environment": "prod"}
+
+	for _, tc := range []struct {
+		name, token, method, path string
+		body                      any
+		status                    int
+	}{
+		{"default key registers agents", "default-token", http.MethodPost, "/api/v1/agents", agent, http.StatusCreated},
+		{"default key lacks admin scopes", "default-token", http.MethodGet, "/api/v1/admin/ratelimits", nil, http.StatusForbidden},
+		{"key without write:agents", "reader-token", http.MethodPost, "/api/v1/agents", agent, http.StatusForbidden},
+		{"admin key", "admin-token", http.MethodGet, "/api/v1/admin/ratelimits", nil, http.StatusOK},
+		{"key with read:controls", "reader-token", http.MethodGet, "/api/v1/controls/frameworks", nil, http.StatusOK},
+		{"key without read:controls", "admin-token", http.MethodGet, "/api/v1/controls/frameworks", nil, http.StatusForbidden},
+		{"default key reads policies", "default-token", http.MethodGet, "/api/v1/policies", nil, http.StatusOK},
+		{"key without read:policies", "reader-token", http.MethodGet, "/api/v1/policies", nil, http.StatusForbidden},
+		{"unknown token", "guess", http.MethodGet, "/api/v1/agents", nil, http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := do(srv, tc.method, tc.path, tc.token, tc.body); w.Code != tc.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tc.status, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestScoringModelEndpoints(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		GapAnalyzer: analyzer,
+		APIKeys: mustKeys(t,
+			apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"},
+			apikey.Key{ID: "reader", Token: "reader-token", Org: "acme", Scopes: []string{"read:controls"}},
+		),
+	})
+
+	w := do(srv, http.MethodGet, "/api/v1/controls/scoring", "reader-token", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get = %d %s, want 200", w.Code, w.Body)
+	}
+	if got := decode[controls.ScoringModel](t, w); got.Effort.ActivityWeight != 1 || len(got.Effort.Sizes) != 3 {
+		t.Errorf("model = %+v, want the default", got.Effort)
+	}
+
+	model := controls.DefaultScoringModel()
+	zero := 0.0
+	model.Frameworks = map[string]controls.ScoringOverride{
+		"nist-800-53": {Effort: controls.EffortOverride{ActivityWeight: &zero}},
+	}
+	if w := do(srv, http.MethodPut, "/api/v1/controls/scoring", "reader-token", model); w.Code != http.StatusForbidden {
+		t.Errorf("put without write:controls = %d, want 403", w.Code)
+	}
+	if w := do(srv, http.MethodPut, "/api/v1/controls/scoring", "ci-token", model); w.Code != http.StatusOK {
+		t.Fatalf("put = %d %s, want 200", w.Code, w.Body)
+	}
+	got := decode[controls.ScoringModel](t, do(srv, http.MethodGet, "/api/v1/controls/scoring", "reader-token", nil))
+	if override := got.Frameworks["nist-800-53"].Effort.ActivityWeight; override == nil || *override != 0 {
+		t.Errorf("nist-800-53 activity weight = %v, want an override to 0", override)
+	}
+	if weight := got.ForFramework("nist-800-53").Effort.ActivityWeight; weight != 0 {
+		t.Errorf("effective nist-800-53 activity weight = %v, want 0", weight)
+	}
+
+	model.Effort.EvidenceWeight = -1
+	for name, body := range map[string]any{"negative weight": model, "malformed body": "model"} {
+		if w := do(srv, http.MethodPut, "/api/v1/controls/scoring", "ci-token", body); w.Code != http.StatusBadRequest {
+			t.Errorf("put with a %s = %d, want 400", name, w.Code)
+		}
+	}
+	if got := decode[controls.ScoringModel](t, do(srv, http.MethodGet, "/api/v1/controls/scoring", "reader-token", nil)); got.Effort.EvidenceWeight != 1 {
+		t.Errorf("evidence weight = %v after rejected updates, want 1", got.Effort.EvidenceWeight)
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/gin-gonic/gin"
+)
+
+// SeedCatalogs serves POST /controls/seed: the embedded frameworks, their
+// controls and the built-in crosswalks are upserted into the control
+// repository, as by the seed command. Query parameters:
+//   - framework: seeds only these frameworks; repeatable or comma-separated.
+//   - dry_run=true: reports what would change without writing.
+//
+// Stored records the seed does not overwrite, such as a framework at
+// another version, are listed as conflicts.
+func (h *Handlers) SeedCatalogs(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+	frameworks := queryList(c, "framework")
+	for _, id := range frameworks {
+		if _, ok := h.GapAnalyzer.Framework(id); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown framework", "details": id})
+			return
+		}
+	}
+	report, err := h.GapAnalyzer.Seed(c.Request.Context(), h.ControlRepo, controls.SeedOptions{
+		DryRun:     c.Query("dry_run") == "true",
+		Frameworks: frameworks,
+	})
+	if err != nil {
+		respondRepoError(c, err, "failed to seed catalogs")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"runtime/metrics"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Priority orders traffic for load shedding. Lower priorities are shed first.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	// PriorityCritical is never shed: SDK decisions and health probes.
+	PriorityCritical
+)
+
+var priorityNames = map[string]Priority{
+	"low":      PriorityLow,
+	"normal":   PriorityNormal,
+	"high":     PriorityHigh,
+	"critical": PriorityCritical,
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// shedThresholds is the load at which each priority starts being shed.
+var shedThresholds = map[Priority]float64{
+	PriorityLow:    0.6,
+	PriorityNormal: 0.85,
+	PriorityHigh:   1.0,
+}
+
+// defaultRoutePriorities classify route groups by path prefix. The longest
+// matching prefix wins; unmatched routes are normal priority.
+var defaultRoutePriorities = map[string]Priority{
+	"/health":                      PriorityCritical,
+	"/ready":                       PriorityCritical,
+	"/api/v1/sdk":                  PriorityCritical,
+	"/api/v1/response":             PriorityHigh,
+	"/api/v1/profiles":             PriorityHigh,
+	"/api/v1/policies":             PriorityHigh,
+	"/api/v1/observe":              PriorityLow,
+	"/api/v1/jobs":                 PriorityLow,
+	"/api/v1/controls/gaps":        PriorityLow,
+	"/api/v1/maturity/assessments": PriorityLow,
+	"/metrics":                     PriorityLow,
+}
+
+type routePriority struct {
+	prefix   string
+	priority Priority
+}
+
+// loadShedder rejects requests by priority when the server is overloaded.
+// Load is the largest of: in-flight requests against their limit, recent
+// p99 goroutine scheduling latency against its target (a proxy for CPU
+// saturation), and any extra signals such as job queue depth.
+type loadShedder struct {
+	routes      []routePriority // longest prefix first
+	maxInflight int64
+	schedTarget time.Duration
+	signals     []func() float64
+
+	inflight  atomic.Int64
+	schedLoad atomic.Uint64 // float64 bits
+	shed      metric.Int64Counter
+	stop      chan struct{}
+	stopped   atomic.Bool
+}
+
+func newLoadShedder(cfg config.LoadSheddingConfig, signals ...func() float64) *loadShedder {
+	routes := make(map[string]Priority, len(defaultRoutePriorities)+len(cfg.Priorities))
+	for prefix, p := range defaultRoutePriorities {
+		routes[prefix] = p
+	}
+	for prefix, name := range cfg.Priorities {
+		p, ok := priorityNames[strings.ToLower(name)]
+		if !ok {
+			log.Warn().Str("route", prefix).Str("priority", name).Msg("unknown load shedding priority, ignoring")
+			continue
+		}
+		routes[prefix] = p
+	}
+
+	s := &loadShedder{
+		maxInflight: int64(cfg.MaxInflight),
+		schedTarget: time.Duration(cfg.SchedLatencyMs) * time.Millisecond,
+		signals:     signals,
+		stop:        make(chan struct{}),
+	}
+	for prefix, p := range routes {
+		s.routes = append(s.routes, routePriority{prefix: prefix, priority: p})
+	}
+	sort.Slice(s.routes, func(i, j int) bool { return len(s.routes[i].prefix) > len(s.routes[j].prefix) })
+
+	var err error
+	s.shed, err = otel.Meter(instrumentationName).Int64Counter(
+		"agentguard_requests_shed_total",
+		metric.WithDescription("Requests rejected by load shedding, by priority"),
+	)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create load shedding metric")
+	}
+
+	if s.schedTarget > 0 {
+		interval := time.Duration(cfg.SampleIntervalMs) * time.Millisecond
+		if interval <= 0 {
+			interval = time.Second
+		}
+		go s.sampleScheduler(interval)
+	}
+	return s
+}
+
+// Stop halts the scheduler latency sampler.
+func (s *loadShedder) Stop() {
+	if s.stopped.CompareAndSwap(false, true) {
+		close(s.stop)
+	}
+}
+
+func (s *loadShedder) priority(path string) Priority {
+	for _, r := range s.routes {
+		if strings.HasPrefix(path, r.prefix) {
+			return r.priority
+		}
+	}
+	return PriorityNormal
+}
+
+// load returns current load as a fraction of capacity.
+func (s *loadShedder) load() float64 {
+	load := math.Float64frombits(s.schedLoad.Load())
+	if s.maxInflight > 0 {
+		load = math.Max(load, float64(s.inflight.Load())/float64(s.maxInflight))
+	}
+	for _, signal := range s.signals {
+		load = math.Max(load, signal())
+	}
+	return load
+}
+
+// Middleware sheds requests whose priority threshold the current load
+// exceeds, answering 503 with Retry-After.
+func (s *loadShedder) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := s.priority(c.Request.URL.Path)
+		if p != PriorityCritical {
+			if load := s.load(); load >= shedThresholds[p] {
+				if s.shed != nil {
+					s.shed.Add(c.Request.Context(), 1, metric.WithAttributes(attribute.String("priority", p.String())))
+				}
+				c.Header("Retry-After", "1")
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+					"error":   "server overloaded",
+					"details": "request shed at " + p.String() + " priority; retry later",
+				})
+				return
+			}
+		}
+
+		s.inflight.Add(1)
+		defer s.inflight.Add(-1)
+		c.Next()
+	}
+}
+
+// sampleScheduler converts the p99 scheduling latency observed in each
+// interval into a load fraction.
+func (s *loadShedder) sampleScheduler(interval time.Duration) {
+	sample := []metrics.Sample{{Name: "/sched/latencies:seconds"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindFloat64Histogram {
+		log.Warn().Msg("scheduler latency metric unavailable, load shedding uses in-flight requests only")
+		return
+	}
+	prev := copyCounts(sample[0].Value.Float64Histogram().Counts)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			metrics.Read(sample)
+			h := sample[0].Value.Float64Histogram()
+			p99 := histogramQuantile(h.Buckets, h.Counts, prev, 0.99)
+			prev = copyCounts(h.Counts)
+			s.schedLoad.Store(math.Float64bits(p99 / s.schedTarget.Seconds()))
+		}
+	}
+}
+
+func copyCounts(counts []uint64) []uint64 {
+	return append([]uint64(nil), counts...)
+}
+
+// histogramQuantile returns the q quantile of the observations added since
+// prev, using each bucket's upper bound.
+func histogramQuantile(buckets []float64, counts, prev []uint64, q float64) float64 {
+	var total uint64
+	for i := range counts {
+		total += counts[i] - prev[i]
+	}
+	if total == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(float64(total) * q))
+	var seen uint64
+	for i := range counts {
+		seen += counts[i] - prev[i]
+		if seen >= target {
+			upper := buckets[i+1]
+			if math.IsInf(upper, 1) {
+				upper = buckets[i]
+			}
+			return upper
+		}
+	}
+	return buckets[len(buckets)-1]
+}
@@ -0,0 +1,88 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/jobs"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestLoadShedding(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A job queue of four, filled by jobs that wait on release, sets the
+	// load to the fraction of the queue in use.
+	m := jobs.NewManager(jobs.Config{Workers: 1, QueueSize: 4})
+	t.Cleanup(m.Stop)
+	release, started := make(chan struct{}), make(chan struct{}, 1)
+	t.Cleanup(func() { close(release) })
+	queue := func() {
+		t.Helper()
+		_, err := m.Submit(jobs.Owner{OrgID: "acme"}, "test", "", func(ctx context.Context) (any, error) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	queue()
+	<-started
+
+	cfg := testConfig()
+	cfg.Server.LoadShedding.Enabled = true
+	cfg.Server.LoadShedding.Priorities = map[string]string{
+		"/api/v1/controls/providers": "low",
+		"/api/v1/controls/crosswalk": "urgent",
+	}
+	srv := newServer(t, cfg, &api.RouterDeps{
+		ControlRepo: memory.NewControlRepository(),
+		GapAnalyzer: analyzer,
+		Jobs:        m,
+		APIKeys:     mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+	get := func(path string) int { return do(srv, http.MethodGet, path, "ci-token", nil).Code }
+
+	for _, path := range []string{"/health", "/api/v1/jobs", "/api/v1/controls/scoring", "/api/v1/controls/providers"} {
+		if code := get(path); code != http.StatusOK {
+			t.Errorf("GET %s with an idle queue = %d, want 200", path, code)
+		}
+	}
+
+	// 3/4 of the queue sheds low priority routes, configured ones included.
+	for range 3 {
+		queue()
+	}
+	w := do(srv, http.MethodGet, "/api/v1/jobs", "ci-token", nil)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("GET /api/v1/jobs at 0.75 load = %d, Retry-After %q, want a 503 to retry", w.Code, w.Header().Get("Retry-After"))
+	}
+	if code := get("/api/v1/controls/providers"); code != http.StatusServiceUnavailable {
+		t.Errorf("GET of a route configured low at 0.75 load = %d, want 503", code)
+	}
+	for _, path := range []string{"/api/v1/controls/scoring", "/api/v1/controls/crosswalk?source=nist-ai-rmf&target=iso-42001"} {
+		if code := get(path); code == http.StatusServiceUnavailable {
+			t.Errorf("GET %s at 0.75 load shed, want normal priority kept", path)
+		}
+	}
+
+	// A full queue sheds everything but critical routes.
+	queue()
+	if code := get("/api/v1/controls/scoring"); code != http.StatusServiceUnavailable {
+		t.Errorf("GET of a normal route at full load = %d, want 503", code)
+	}
+	if code := get("/health"); code != http.StatusOK {
+		t.Errorf("GET /health at full load = %d, want 200", code)
+	}
+}
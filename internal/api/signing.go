@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/signing"
+	"github.com/agentguard/agentguard/internal/workload"
+	"github.com/agentguard/agentguard/pkg/client"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// signingIssuer marks workload identities established by a request
+// signature rather than a token or certificate.
+const signingIssuer = "agentguard:request-signing"
+
+// maxSignedBody bounds the SDK request body read for signature checks; the
+// hooks apply the same limit.
+const maxSignedBody = 1 << 20
+
+// sdkSigningMiddleware verifies HMAC-signed SDK hook requests. A verified
+// request is bound to the signing agent like a workload identity, so a body
+// claiming another agent is denied. Unsigned requests are rejected when
+// required is set or when the agent they claim has an active key.
+func sdkSigningMiddleware(keys *signing.Keys, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBody))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		ctx := c.Request.Context()
+
+		if c.GetHeader(client.SignatureHeader) == "" {
+			if required {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "request signature required"})
+				return
+			}
+			// An unsigned request cannot pass as an agent that signs.
+			claimed := claimedAgent(body)
+			if claimed == "" {
+				c.Next()
+				return
+			}
+			signs, err := keys.HasActive(ctx, claimed)
+			if err != nil {
+				log.Error().Err(err).Str("agent_id", claimed).Msg("failed to check signing keys")
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check request signature"})
+				return
+			}
+			if signs {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "request signature required", "details": "agent " + claimed + " signs its requests"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		agentID := c.GetHeader(client.AgentHeader)
+		if agentID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid request signature", "details": client.AgentHeader + " header required"})
+			return
+		}
+		key, err := keys.Verify(ctx, agentID, c.Request.Header, body)
+		if err != nil {
+			if !rejectedSignature(err) {
+				log.Error().Err(err).Str("agent_id", agentID).Msg("failed to verify request signature")
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check request signature"})
+				return
+			}
+			log.Warn().Err(err).Str("agent_id", agentID).Str("path", c.Request.URL.Path).Msg("Request signature rejected")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid request signature", "details": err.Error()})
+			return
+		}
+
+		if id := workloadIdentity(c); id != nil {
+			if id.AgentID != agentID {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "signing agent does not match workload identity"})
+				return
+			}
+		} else {
+			c.Set(workloadKey, &workload.Identity{Issuer: signingIssuer, Subject: "signing-key:" + key.ID, AgentID: agentID})
+		}
+		c.Next()
+	}
+}
+
+// rejectedSignature reports whether a verification error is the caller's
+// fault rather than a failure to look up keys.
+func rejectedSignature(err error) bool {
+	for _, target := range []error{
+		signing.ErrNoKey, client.ErrMissingSignature, client.ErrInvalidSignature,
+		client.ErrStaleTimestamp, client.ErrReplayedNonce,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// claimedAgent returns the agent an SDK hook body names: agent.id for
+// pre-invoke requests, agent_id for the others.
+func claimedAgent(body []byte) string {
+	var claim struct {
+		Agent struct {
+			ID string `json:"id"`
+		} `json:"agent"`
+		AgentID string `json:"agent_id"`
+	}
+	if err := json.Unmarshal(body, &claim); err != nil {
+		return ""
+	}
+	if claim.Agent.ID != "" {
+		return claim.Agent.ID
+	}
+	return claim.AgentID
+}
+
+// signingKeyView is a signing key as listed, without its secret.
+type signingKeyView struct {
+	models.SigningKey
+	Active bool `json:"active"`
+}
+
+// ListSigningKeys returns the keys an agent signs SDK hook requests with,
+// without their secrets.
+func (h *Handlers) ListSigningKeys(c *gin.Context) {
+	if h.SigningKeys == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "request signing not configured"})
+		return
+	}
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	keys, err := h.SigningKeys.List(c.Request.Context(), a.ID.String())
+	if err != nil {
+		respondRepoError(c, err, "failed to list signing keys")
+		return
+	}
+	views := make([]signingKeyView, 0, len(keys))
+	now := time.Now()
+	for _, k := range keys {
+		views = append(views, signingKeyView{SigningKey: k, Active: signing.Active(&k, now)})
+	}
+	c.JSON(http.StatusOK, gin.H{"agent_id": a.ID, "keys": views})
+}
+
+// RotateSigningKey issues a new signing key for an agent and returns its
+// secret, which is not shown again. The agent's other keys stay valid for
+// the rotation grace period.
+func (h *Handlers) RotateSigningKey(c *gin.Context) {
+	if h.SigningKeys == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "request signing not configured"})
+		return
+	}
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	key, err := h.SigningKeys.Rotate(c.Request.Context(), a.ID.String())
+	if err != nil {
+		respondRepoError(c, err, "failed to rotate signing key")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         key.ID,
+		"agent_id":   key.AgentID,
+		"secret":     key.Secret,
+		"created_at": key.CreatedAt,
+	})
+}
+
+// RevokeSigningKey deletes one of an agent's signing keys immediately.
+func (h *Handlers) RevokeSigningKey(c *gin.Context) {
+	if h.SigningKeys == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "request signing not configured"})
+		return
+	}
+	a, ok := h.loadAgent(c)
+	if !ok {
+		return
+	}
+	if err := h.SigningKeys.Revoke(c.Request.Context(), a.ID.String(), c.Param("key_id")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "signing key not found"})
+			return
+		}
+		respondRepoError(c, err, "failed to revoke signing key")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetSSP generates a System Security Plan for the framework query
+// parameter from the caller's tracked implementations, attestations and
+// gap dispositions. The agent query parameter scopes it to one registered
+// agent, listing the controls its traits make not applicable apart; the
+// providers query parameter names common control providers the system
+// inherits from. The format query parameter, or else the Accept header,
+// selects json (default), markdown or docx.
+func (h *Handlers) GetSSP(c *gin.Context) {
+	if h.GapAnalyzer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gap analyzer not initialized"})
+		return
+	}
+	if h.Implementations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control implementation tracking not configured"})
+		return
+	}
+	format, ok := negotiateReportFormat(c, controls.ReportJSON, controls.ReportMarkdown, controls.ReportDOCX)
+	if !ok {
+		return
+	}
+	framework := c.Query("framework")
+	if !validFrameworkID.MatchString(framework) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "framework is required", "details": "pass a framework ID as the framework query parameter"})
+		return
+	}
+	if _, ok := h.GapAnalyzer.Framework(framework); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown framework", "details": framework})
+		return
+	}
+	providers := queryList(c, "providers")
+	for _, id := range providers {
+		if _, ok := h.GapAnalyzer.GetProvider(id); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown control provider", "details": id})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	org := c.GetString(orgKey)
+	in := &controls.SSPInput{OrganizationID: org, Framework: framework, Providers: providers}
+	if v := c.Query("agent"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent ID format"})
+			return
+		}
+		if h.Agents == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "agent registry not configured"})
+			return
+		}
+		agent, err := h.Agents.Get(ctx, id)
+		if err != nil {
+			respondRepoError(c, err, "failed to get agent")
+			return
+		}
+		if agent == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "agent not found"})
+			return
+		}
+		in.Agent = agent
+	}
+	impls, err := h.Implementations.List(ctx, org, framework)
+	if err != nil {
+		respondRepoError(c, err, "failed to load tracked control implementations")
+		return
+	}
+	in.Implementations = impls
+	if in.Attestations, err = h.sspAttestations(ctx, org, framework); err != nil {
+		respondRepoError(c, err, "failed to load attestations")
+		return
+	}
+	in.Dispositions = h.analysisDispositions(ctx, org, framework)
+
+	ssp, err := h.GapAnalyzer.SSP(ctx, in, time.Now())
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "cannot build ssp", "details": err.Error()})
+		return
+	}
+	if format != controls.ReportJSON {
+		writeReport(c, "ssp-"+framework, format, func(buf *bytes.Buffer) error {
+			return controls.WriteSSP(buf, ssp, format)
+		})
+		return
+	}
+	c.JSON(http.StatusOK, ssp)
+}
+
+// sspAttestations returns the organization's attestations of a framework's
+// controls across its campaigns, open and closed. Without attestation
+// campaigns there are none.
+func (h *Handlers) sspAttestations(ctx context.Context, org, framework string) ([]models.Attestation, error) {
+	if h.Attestations == nil {
+		return nil, nil
+	}
+	campaigns, err := h.Attestations.ListCampaigns(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	var list []models.Attestation
+	for _, campaign := range campaigns {
+		if campaign.FrameworkID != "" && campaign.FrameworkID != framework {
+			continue
+		}
+		attestations, err := h.Attestations.ListAttestations(ctx, campaign.ID)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, attestations...)
+	}
+	return list, nil
+}
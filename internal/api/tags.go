@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// controlTagRepo returns the control repository's tag support, or responds
+// 501 when it has none.
+func (h *Handlers) controlTagRepo(c *gin.Context) (repository.ControlTagRepository, bool) {
+	repo, ok := h.ControlRepo.(repository.ControlTagRepository)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "control tags not supported by this repository"})
+	}
+	return repo, ok
+}
+
+// tagIndex returns an organization's control tags, of one framework unless
+// framework is empty. Repositories without tag support have none.
+func (h *Handlers) tagIndex(ctx context.Context, org, framework string) (controls.TagIndex, error) {
+	repo, ok := h.ControlRepo.(repository.ControlTagRepository)
+	if !ok {
+		return controls.TagIndex{}, nil
+	}
+	list, err := repo.ListControlTags(ctx, org, framework)
+	if err != nil {
+		return nil, err
+	}
+	return controls.IndexTags(list), nil
+}
+
+// analysisTags returns the tags of a framework's controls for a gap
+// analysis. Repositories without tag support have none.
+func (h *Handlers) analysisTags(ctx context.Context, org, framework string) map[string][]string {
+	repo, _ := h.ControlRepo.(repository.ControlTagRepository)
+	return controls.AnalysisTags(ctx, repo, org, framework)
+}
+
+// ListControlTags returns the organization's tagged controls and how many
+// controls carry each tag. The framework query parameter limits both to
+// one framework.
+func (h *Handlers) ListControlTags(c *gin.Context) {
+	repo, ok := h.controlTagRepo(c)
+	if !ok {
+		return
+	}
+	framework := c.Query("framework")
+	if framework != "" && !validFrameworkID.MatchString(framework) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	list, err := repo.ListControlTags(c.Request.Context(), c.GetString(orgKey), framework)
+	if err != nil {
+		respondRepoError(c, err, "failed to list control tags")
+		return
+	}
+	if list == nil {
+		list = []models.ControlTags{}
+	}
+	counts := make(map[string]int)
+	for _, t := range list {
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"controls": list, "tags": counts, "count": len(list)})
+}
+
+// SetControlTagsRequest replaces a control's tags.
+type SetControlTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// SetControlTags replaces the organization's tags on a framework control.
+// An empty list removes them.
+func (h *Handlers) SetControlTags(c *gin.Context) {
+	repo, ok := h.controlTagRepo(c)
+	if !ok {
+		return
+	}
+	framework, controlID := c.Param("id"), c.Param("control")
+	if !validFrameworkID.MatchString(framework) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid framework ID format"})
+		return
+	}
+	var req SetControlTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+	tags, err := controls.NormalizeTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tags", "details": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	list := h.frameworkControls(ctx, framework)
+	i := slices.IndexFunc(list, func(ctrl models.Control) bool {
+		return strings.EqualFold(ctrl.ControlID, controlID)
+	})
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "control not found", "details": framework + ":" + controlID})
+		return
+	}
+	t := &models.ControlTags{
+		OrganizationID: c.GetString(orgKey),
+		FrameworkID:    framework,
+		ControlID:      list[i].ControlID,
+		Tags:           tags,
+	}
+	if err := repo.SetControlTags(ctx, t); err != nil {
+		respondRepoError(c, err, "failed to set control tags")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
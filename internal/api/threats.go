@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/multiagent"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/threat"
+	"github.com/gin-gonic/gin"
+)
+
+// makeCalibrateThreatModelHandler serves POST /threats/models/calibrate:
+// it raises the likelihood of the posted model's threats from the signals
+// observed for its target agent and returns the rescored model with the
+// adjustments made. ?window= sets how far back signals count, e.g. 168h.
+func makeCalibrateThreatModelHandler(metrics repository.MetricsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cal threat.Calibration
+		if v := c.Query("window"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid window"})
+				return
+			}
+			cal.Window = d
+		}
+		var tm models.ThreatModel
+		if err := c.ShouldBindJSON(&tm); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threat model: " + err.Error()})
+			return
+		}
+
+		adjustments, err := threat.Calibrate(c.Request.Context(), metrics, c.GetString(orgKey), &tm, cal, time.Now().UTC())
+		if err != nil {
+			respondRepoError(c, err, "failed to count signals")
+			return
+		}
+		if adjustments == nil {
+			adjustments = []threat.Adjustment{}
+		}
+		c.JSON(http.StatusOK, gin.H{"model": tm, "adjustments": adjustments})
+	}
+}
+
+// attackPathRequest is the body of POST /threats/attack-paths.
+type attackPathRequest struct {
+	AgentID     string                      `json:"agent_id"`
+	Tools       []models.ToolBinding        `json:"tools" binding:"required"`
+	Overrides   map[string]threat.ToolRoles `json:"overrides"`
+	Transitions []threat.Transition         `json:"transitions"`
+	MaxSteps    int                         `json:"max_steps"`
+}
+
+// maxAttackPathSteps bounds requested path length; enumeration grows
+// exponentially with it.
+const maxAttackPathSteps = 6
+
+// analyzeAttackPaths serves POST /threats/attack-paths: the attack paths
+// through a posted agent's tools and the chokepoints that break them.
+func analyzeAttackPaths(c *gin.Context) {
+	var req attackPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+	if req.MaxSteps < 0 || req.MaxSteps > maxAttackPathSteps {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_steps must be between 1 and 6"})
+		return
+	}
+	c.JSON(http.StatusOK, threat.AnalyzePaths(req.AgentID, req.Tools, threat.PathOptions{
+		Overrides:   req.Overrides,
+		Transitions: req.Transitions,
+		MaxSteps:    req.MaxSteps,
+	}))
+}
+
+// makeAgentAttackPathsHandler serves GET /agents/:id/attack-paths: attack
+// paths through the tools an agent has actually called in the time range.
+func makeAgentAttackPathsHandler(repo repository.ToolUsageRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		agentID := c.Param("id")
+		if !validateID(agentID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent id"})
+			return
+		}
+		q := repository.ToolUsageQuery{OrgID: c.GetString(orgKey), AgentID: agentID}
+		var ok bool
+		if q.From, q.To, ok = parseTimeRange(c, 30*24*time.Hour); !ok {
+			return
+		}
+		usage, err := repo.ToolUsage(c.Request.Context(), &q)
+		if err != nil {
+			respondRepoError(c, err, "failed to query tool usage")
+			return
+		}
+		tools := make([]models.ToolBinding, 0, len(usage))
+		for _, u := range usage {
+			tools = append(tools, models.ToolBinding{Name: u.Tool, Category: u.Category})
+		}
+		c.JSON(http.StatusOK, threat.AnalyzePaths(agentID, tools, threat.PathOptions{}))
+	}
+}
+
+// groupAnalysis is the response of POST /threats/groups/analyze.
+type groupAnalysis struct {
+	ThreatModel *models.ThreatModel `json:"threat_model"`
+	Scopes      []multiagent.Scope  `json:"scopes"`
+	Cycles      [][]string          `json:"cycles"`
+	Diagram     string              `json:"diagram"`
+}
+
+// analyzeAgentGroup serves POST /threats/groups/analyze: the trust
+// boundaries and delegation threats of a posted agent group, the policies
+// and tool limits each member inherits from its callers, and a Mermaid
+// diagram of the group.
+func analyzeAgentGroup(c *gin.Context) {
+	var g models.AgentGroup
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+	if err := multiagent.Validate(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cycles := multiagent.Cycles(&g)
+	if cycles == nil {
+		cycles = [][]string{}
+	}
+	c.JSON(http.StatusOK, groupAnalysis{
+		ThreatModel: threat.AnalyzeGroup(&g),
+		Scopes:      multiagent.Propagate(&g),
+		Cycles:      cycles,
+		Diagram:     threat.GroupDiagram(&g),
+	})
+}
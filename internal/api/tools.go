@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// makeToolUsageHandler serves GET /agents/:id/tool-usage: per-tool call
+// counts, denial rates, latency percentiles, and external-call ratios.
+//
+// Query parameters:
+//   - from, to: RFC 3339 timestamps (default the last 7 days)
+//   - tool: restrict to one tool
+func makeToolUsageHandler(repo repository.ToolUsageRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		agentID := c.Param("id")
+		if !validateID(agentID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent id"})
+			return
+		}
+		q := repository.ToolUsageQuery{OrgID: c.GetString(orgKey), AgentID: agentID}
+		var ok bool
+		if q.From, q.To, ok = parseTimeRange(c, 7*24*time.Hour); !ok {
+			return
+		}
+		if v := c.Query("tool"); v != "" {
+			q.Tool = &v
+		}
+
+		usage, err := repo.ToolUsage(c.Request.Context(), &q)
+		if err != nil {
+			respondRepoError(c, err, "failed to query tool usage")
+			return
+		}
+
+		var calls, denials, external int64
+		for _, u := range usage {
+			calls += u.Calls
+			denials += u.Denials
+			external += u.ExternalCalls
+		}
+		totals := gin.H{"calls": calls, "denials": denials, "external_calls": external, "denial_rate": 0.0, "external_ratio": 0.0}
+		if calls > 0 {
+			totals["denial_rate"] = float64(denials) / float64(calls)
+			totals["external_ratio"] = float64(external) / float64(calls)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"agent_id": agentID,
+			"from":     q.From,
+			"to":       q.To,
+			"tools":    usage,
+			"totals":   totals,
+		})
+	}
+}
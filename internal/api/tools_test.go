@@ -0,0 +1,82 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/api"
+	"github.com/agentguard/agentguard/internal/apikey"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// toolUsageRepo returns fixed usage and records the last query.
+type toolUsageRepo struct {
+	usage []repository.ToolUsage
+	err   error
+	query *repository.ToolUsageQuery
+}
+
+func (r *toolUsageRepo) ToolUsage(_ context.Context, q *repository.ToolUsageQuery) ([]repository.ToolUsage, error) {
+	r.query = q
+	return r.usage, r.err
+}
+
+func TestToolUsage(t *testing.T) {
+	repo := &toolUsageRepo{usage: []repository.ToolUsage{
+		{Tool: "web_search", Calls: 6, Denials: 1, ExternalCalls: 6},
+		{Tool: "sql_query", Calls: 2, Denials: 1},
+	}}
+	srv := newServer(t, testConfig(), &api.RouterDeps{
+		ToolUsage: repo,
+		APIKeys:   mustKeys(t, apikey.Key{ID: "ci", Token: "ci-token", Org: "acme"}),
+	})
+
+	w := do(srv, http.MethodGet, "/api/v1/agents/support-bot/tool-usage?tool=web_search&from=2026-05-01T00:00:00Z&to=2026-05-02T00:00:00Z", "ci-token", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("tool usage = %d %s, want 200", w.Code, w.Body)
+	}
+	q := repo.query
+	if q.OrgID != "acme" || q.AgentID != "support-bot" || q.Tool == nil || *q.Tool != "web_search" ||
+		!q.From.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) || !q.To.Equal(time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("query = %+v, want acme's support-bot web_search calls on May 1", q)
+	}
+	got := decode[struct {
+		Tools  []repository.ToolUsage
+		Totals struct {
+			Calls         int64
+			Denials       int64
+			DenialRate    float64 `json:"denial_rate"`
+			ExternalRatio float64 `json:"external_ratio"`
+		}
+	}](t, w)
+	if len(got.Tools) != 2 || got.Totals.Calls != 8 || got.Totals.Denials != 2 || got.Totals.DenialRate != 0.25 || got.Totals.ExternalRatio != 0.75 {
+		t.Errorf("usage = %+v, want 8 calls, a quarter denied and three quarters external", got)
+	}
+
+	w = do(srv, http.MethodGet, "/api/v1/agents/support-bot/tool-usage", "ci-token", nil)
+	if w.Code != http.StatusOK || repo.query.Tool != nil || repo.query.To.Sub(repo.query.From) != 7*24*time.Hour {
+		t.Errorf("default tool usage = %d, query %+v, want the last week of every tool", w.Code, repo.query)
+	}
+
+	for name, query := range map[string]string{
+		"a malformed from":    "from=yesterday",
+		"a malformed to":      "to=2026-05-01",
+		"an inverted range":   "from=2026-05-02T00:00:00Z&to=2026-05-01T00:00:00Z",
+		"a range over a year": "from=2024-01-01T00:00:00Z&to=2026-01-01T00:00:00Z",
+	} {
+		if w := do(srv, http.MethodGet, "/api/v1/agents/support-bot/tool-usage?"+query, "ci-token", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("tool usage with %s = %d, want 400", name, w.Code)
+		}
+	}
+	if w := do(srv, http.MethodGet, "/api/v1/agents/bad%20agent/tool-usage", "ci-token", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("tool usage of a malformed agent ID = %d, want 400", w.Code)
+	}
+
+	repo.err = errors.New("clickhouse unavailable")
+	if w := do(srv, http.MethodGet, "/api/v1/agents/support-bot/tool-usage", "ci-token", nil); w.Code != http.StatusInternalServerError {
+		t.Errorf("tool usage on a store error = %d, want 500", w.Code)
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Trace list page sizes.
+const (
+	defaultTraceLimit = 50
+	maxTraceLimit     = 500
+)
+
+var traceListSpec = listSpec{defaultLimit: defaultTraceLimit, maxLimit: maxTraceLimit, sorts: repository.TraceSortFields}
+
+// Signal list page sizes.
+const (
+	defaultSignalLimit = 100
+	maxSignalLimit     = 1000
+)
+
+var signalListSpec = listSpec{defaultLimit: defaultSignalLimit, maxLimit: maxSignalLimit, sorts: repository.SignalSortFields}
+
+var (
+	traceStatuses = []models.TraceStatus{
+		models.TraceStatusRunning, models.TraceStatusCompleted, models.TraceStatusFailed, models.TraceStatusBlocked,
+	}
+	signalSeverities = []string{"low", "medium", "high", "critical"}
+)
+
+// makeListTracesHandler serves GET /observe/traces: the organization's
+// traces, newest first, with their metrics and metadata but not their spans.
+// agent_id, session_id, status and from/to (RFC 3339, on start time) filter
+// them; they are paged and sorted like the other lists.
+func makeListTracesHandler(l repository.TraceLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, ok := parseListPage(c, traceListSpec)
+		if !ok {
+			return
+		}
+		filters := &repository.TraceFilters{Sort: page.sort}
+		if v := c.Query("agent_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent_id"})
+				return
+			}
+			filters.AgentID = &id
+		}
+		if v := c.Query("session_id"); v != "" {
+			filters.SessionID = &v
+		}
+		if v := c.Query("status"); v != "" {
+			s := models.TraceStatus(v)
+			if !slices.Contains(traceStatuses, s) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status", "details": "use running, completed, failed or blocked"})
+				return
+			}
+			filters.Status = &s
+		}
+		for _, p := range []struct {
+			name string
+			dst  **int64
+		}{{"from", &filters.StartFrom}, {"to", &filters.StartTo}} {
+			if v := c.Query(p.name); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.name, "details": "expected RFC 3339 timestamp"})
+					return
+				}
+				unix := t.Unix()
+				*p.dst = &unix
+			}
+		}
+
+		ctx, orgID := c.Request.Context(), c.GetString(orgKey)
+		total, err := l.CountTraces(ctx, orgID, filters)
+		if err != nil {
+			respondRepoError(c, err, "failed to count traces")
+			return
+		}
+		filters.Offset, filters.Limit = page.offset, page.limit
+		traces, err := l.ListTraces(ctx, orgID, filters)
+		if err != nil {
+			respondRepoError(c, err, "failed to list traces")
+			return
+		}
+		summaries := make([]traceSummary, 0, len(traces))
+		for _, t := range traces {
+			summaries = append(summaries, traceSummary{
+				TraceID: t.TraceID, AgentID: t.AgentID, SessionID: t.SessionID, UserID: t.UserID,
+				StartTime: t.StartTime, EndTime: t.EndTime, DurationMs: t.DurationMs, Status: t.Status,
+				Metrics: t.Metrics, Metadata: t.Metadata, Parent: t.Parent,
+			})
+		}
+		respondList(c, page, total, "traces", summaries, nil)
+	}
+}
+
+// traceSummary is a listed trace: an AgentTrace without spans and signals.
+type traceSummary struct {
+	TraceID    string              `json:"trace_id"`
+	AgentID    uuid.UUID           `json:"agent_id"`
+	SessionID  string              `json:"session_id"`
+	UserID     string              `json:"user_id"`
+	StartTime  time.Time           `json:"start_time"`
+	EndTime    *time.Time          `json:"end_time,omitempty"`
+	DurationMs int64               `json:"duration_ms"`
+	Status     models.TraceStatus  `json:"status"`
+	Metrics    models.TraceMetrics `json:"metrics"`
+	Metadata   map[string]any      `json:"metadata,omitempty"`
+	Parent     *models.TraceLink   `json:"parent,omitempty"`
+}
+
+// makeListSignalsHandler serves GET /observe/signals: the organization's
+// security signals, those on ingested traces included, newest first.
+// trace_id, agent_id, type and severity filter them; they are paged and
+// sorted like the other lists.
+func makeListSignalsHandler(l repository.SignalLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, ok := parseListPage(c, signalListSpec)
+		if !ok {
+			return
+		}
+		filters := &repository.SignalFilters{Sort: page.sort}
+		if v := c.Query("trace_id"); v != "" {
+			filters.TraceID = &v
+		}
+		if v := c.Query("agent_id"); v != "" {
+			if _, err := uuid.Parse(v); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent_id"})
+				return
+			}
+			filters.AgentID = &v
+		}
+		if v := c.Query("type"); v != "" {
+			t := models.SignalType(v)
+			filters.Type = &t
+		}
+		if v := c.Query("severity"); v != "" {
+			if !slices.Contains(signalSeverities, v) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid severity", "details": "use low, medium, high or critical"})
+				return
+			}
+			filters.Severity = &v
+		}
+
+		ctx, orgID := c.Request.Context(), c.GetString(orgKey)
+		total, err := l.CountSignals(ctx, orgID, filters)
+		if err != nil {
+			respondRepoError(c, err, "failed to count security signals")
+			return
+		}
+		filters.Offset, filters.Limit = page.offset, page.limit
+		signals, err := l.ListSignals(ctx, orgID, filters)
+		if err != nil {
+			respondRepoError(c, err, "failed to list security signals")
+			return
+		}
+		respondList(c, page, total, "signals", signals, nil)
+	}
+}
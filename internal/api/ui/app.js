@@ -0,0 +1,112 @@
+// Development console for agentguard serve --dev. It only calls the public
+// API; authentication is disabled in development mode.
+"use strict";
+
+const api = "/api/v1";
+
+async function getJSON(path, options) {
+  const res = await fetch(path, options);
+  const body = await res.json();
+  if (!res.ok) {
+    throw new Error(body.error + (body.details ? ": " + body.details : ""));
+  }
+  return body;
+}
+
+function row(cells) {
+  const tr = document.createElement("tr");
+  for (const cell of cells) {
+    const td = document.createElement("td");
+    td.textContent = cell;
+    tr.appendChild(td);
+  }
+  return tr;
+}
+
+function show(el, value) {
+  el.hidden = false;
+  el.textContent = typeof value === "string" ? value : JSON.stringify(value, null, 2);
+}
+
+async function loadHealth() {
+  const el = document.getElementById("health");
+  try {
+    const body = await getJSON("/health");
+    el.textContent = body.status;
+    el.className = "status ok";
+  } catch (err) {
+    el.textContent = "unreachable";
+    el.className = "status down";
+  }
+}
+
+async function loadFrameworks() {
+  const body = await getJSON(api + "/controls/frameworks");
+  const tbody = document.querySelector("#frameworks tbody");
+  const select = document.getElementById("framework");
+  tbody.replaceChildren();
+  select.replaceChildren();
+  for (const f of body.frameworks) {
+    tbody.appendChild(row([f.id, f.name, f.version]));
+    const opt = document.createElement("option");
+    opt.value = f.id;
+    opt.textContent = f.name;
+    select.appendChild(opt);
+  }
+}
+
+async function loadHistory() {
+  const body = await getJSON(api + "/controls/gaps");
+  const tbody = document.querySelector("#history tbody");
+  tbody.replaceChildren();
+  for (const ga of body.analyses) {
+    tbody.appendChild(row([
+      new Date(ga.analysis_date).toLocaleString(),
+      ga.target_framework_id,
+      String((ga.gaps || []).length),
+      ga.summary.coverage_percentage.toFixed(1) + "%",
+    ]));
+  }
+}
+
+document.getElementById("analyze").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const out = document.getElementById("result");
+  const implemented = document.getElementById("implemented").value
+    .split(/[\s,]+/)
+    .filter((id) => id !== "");
+  try {
+    const body = await getJSON(api + "/controls/gaps/analyze", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        target_framework: document.getElementById("framework").value,
+        implemented_controls: implemented,
+      }),
+    });
+    show(out, {
+      framework: body.framework,
+      coverage_percentage: body.coverage_percentage,
+      gap_count: body.gap_count,
+      gaps: (body.gaps || []).map((g) => g.control_id + " (" + g.priority + ")"),
+    });
+    await loadHistory();
+  } catch (err) {
+    show(out, err.message);
+  }
+});
+
+document.getElementById("trace").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const out = document.getElementById("trace-result");
+  const id = encodeURIComponent(document.getElementById("trace-id").value.trim());
+  try {
+    show(out, await getJSON(api + "/observe/traces/" + id + "/export"));
+  } catch (err) {
+    show(out, err.message);
+  }
+});
+
+loadHealth();
+loadFrameworks().catch((err) => console.error(err));
+loadHistory().catch((err) => console.error(err));
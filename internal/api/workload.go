@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/workload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// workloadKey is the gin context key for the authenticated *workload.Identity.
+const workloadKey = "workload_identity"
+
+// sdkPathPrefix is where workload identities are accepted.
+const sdkPathPrefix = "/api/v1/sdk/"
+
+// workloadAuthMiddleware accepts workload identities on SDK routes and defers
+// everything else to the static bearer token check. A verified SPIFFE client
+// certificate takes precedence over a workload identity token. Workload
+// identities carry no scopes and pin the caller's organization when their
+// binding names one.
+func workloadAuthMiddleware(tokens *workload.Verifier, svids *workload.SVIDVerifier, bearer gin.HandlerFunc) gin.HandlerFunc {
+	if tokens == nil && svids == nil {
+		return bearer
+	}
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.Request.URL.Path, sdkPathPrefix) {
+			bearer(c)
+			return
+		}
+
+		var id *workload.Identity
+		var err error
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		switch {
+		case svids != nil && c.Request.TLS != nil && len(c.Request.TLS.VerifiedChains) > 0:
+			id, err = svids.Identify(c.Request.TLS.VerifiedChains[0][0])
+		case tokens != nil && workload.IsJWT(token):
+			id, err = tokens.Verify(c.Request.Context(), token)
+		default:
+			bearer(c)
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("path", c.Request.URL.Path).Msg("Workload identity rejected")
+			if errors.Is(err, workload.ErrUnbound) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "workload identity is not bound to an agent"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.Set(workloadKey, id)
+		c.Set(scopeKey, []string{})
+		if id.OrgID != "" {
+			c.Set(orgKey, id.OrgID)
+		}
+		c.Next()
+	}
+}
+
+// workloadIdentity returns the caller's workload identity, if any.
+func workloadIdentity(c *gin.Context) *workload.Identity {
+	id, _ := c.Get(workloadKey)
+	wi, _ := id.(*workload.Identity)
+	return wi
+}
+
+// bindWorkloadAgent fills agentID from the caller's workload identity. A
+// request claiming a different agent is denied and raises an
+// identity_mismatch signal against the authenticated agent. It reports
+// whether the request may proceed; on false the response has been written.
+func bindWorkloadAgent(c *gin.Context, deps *RouterDeps, agentID *string) bool {
+	id := workloadIdentity(c)
+	if id == nil {
+		return true
+	}
+	if *agentID == "" || *agentID == id.AgentID {
+		*agentID = id.AgentID
+		return true
+	}
+
+	sig := models.SecuritySignal{
+		ID:          uuid.NewString(),
+		Type:        models.SignalIdentityMismatch,
+		Severity:    "high",
+		Title:       "Agent identity mismatch",
+		Description: "workload " + id.Subject + " is bound to agent " + id.AgentID + " but claimed agent " + *agentID,
+		Evidence: map[string]any{
+			"claimed_agent_id": *agentID,
+			"bound_agent_id":   id.AgentID,
+			"subject":          id.Subject,
+			"issuer":           id.Issuer,
+			"path":             c.Request.URL.Path,
+		},
+		Timestamp: time.Now().UTC(),
+	}
+	orgID := c.GetString(orgKey)
+	log.Warn().Str("org_id", orgID).Str("agent_id", id.AgentID).Str("claimed_agent_id", *agentID).
+		Str("subject", id.Subject).Msg("workload identity mismatch")
+
+	if deps != nil {
+		dispatchSignal(c, deps, orgID, id.AgentID, sig)
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"allow":     false,
+		"reasons":   []string{"agent id does not match workload identity"},
+		"signal_id": sig.ID,
+	})
+	return false
+}
@@ -0,0 +1,144 @@
+// Package apikey authenticates API requests by bearer token. Several keys
+// may be active at once, each with an ID that is recorded with the requests
+// it authenticates and an optional validity window, so a token can be
+// rotated by adding its replacement and letting the old one expire once
+// clients have moved over.
+package apikey
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MinTokenLength is the shortest token not reported as weak.
+const MinTokenLength = 32
+
+// Scopes lists the scopes a key can grant:
+//
+//	read:controls, write:controls     control catalogs, gap analysis, tracking
+//	read:policies, write:policies     policies, profiles, detection and response
+//	write:agents                      agent registry, bindings and signing keys
+//	read:payloads                     raw span payloads
+//	admin:catalog                     seeding embedded catalogs
+//	admin:ratelimits                  rate limiter inspection and resets
+//	admin:privacy                     data subject erasure
+//	admin:reidentify                  unmasking pseudonymized identifiers
+var Scopes = []string{
+	"read:controls", "write:controls", "read:policies", "write:policies", "write:agents",
+	"read:payloads", "admin:catalog", "admin:ratelimits", "admin:privacy", "admin:reidentify",
+}
+
+// DefaultScopes are granted to keys that do not list their own: everything
+// but payload access and administration.
+var DefaultScopes = []string{"read:controls", "write:controls", "read:policies", "write:policies", "write:agents"}
+
+var (
+	// ErrUnknown is returned for a token that matches no key.
+	ErrUnknown = errors.New("unknown API key")
+	// ErrExpired is returned for a key used after its expiry.
+	ErrExpired = errors.New("API key expired")
+	// ErrNotYetValid is returned for a key used before its validity starts.
+	ErrNotYetValid = errors.New("API key not yet valid")
+)
+
+// Key is an API key. A zero NotBefore or ExpiresAt leaves that end of its
+// validity open.
+type Key struct {
+	ID    string
+	Token string
+	// Org is the organization the key acts for; empty for the server's
+	// default organization.
+	Org string
+	// Scopes are the scopes the key grants, DefaultScopes when empty.
+	Scopes    []string
+	NotBefore time.Time
+	ExpiresAt time.Time
+}
+
+// ValidAt reports whether the key is accepted at t.
+func (k *Key) ValidAt(t time.Time) bool {
+	return (k.NotBefore.IsZero() || !t.Before(k.NotBefore)) && (k.ExpiresAt.IsZero() || t.Before(k.ExpiresAt))
+}
+
+// Keyring holds the API keys a server accepts. It is safe for concurrent
+// use.
+type Keyring struct {
+	keys    []Key // without tokens
+	digests [][sha256.Size]byte
+}
+
+// New creates a Keyring. Every key needs an ID and a token, both unique, and
+// may only list scopes from Scopes.
+func New(keys ...Key) (*Keyring, error) {
+	k := &Keyring{}
+	ids := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		switch {
+		case key.ID == "":
+			return nil, errors.New("API key without an ID")
+		case key.Token == "":
+			return nil, fmt.Errorf("API key %s has no token", key.ID)
+		case ids[key.ID]:
+			return nil, fmt.Errorf("duplicate API key ID %s", key.ID)
+		case !key.ExpiresAt.IsZero() && !key.ExpiresAt.After(key.NotBefore):
+			return nil, fmt.Errorf("API key %s expires before it becomes valid", key.ID)
+		}
+		for _, scope := range key.Scopes {
+			if !slices.Contains(Scopes, scope) {
+				return nil, fmt.Errorf("API key %s has unknown scope %q", key.ID, scope)
+			}
+		}
+		if len(key.Scopes) == 0 {
+			key.Scopes = DefaultScopes
+		}
+		key.Scopes = slices.Clone(key.Scopes)
+		ids[key.ID] = true
+		digest := sha256.Sum256([]byte(key.Token))
+		for i, d := range k.digests {
+			if d == digest {
+				return nil, fmt.Errorf("API keys %s and %s share a token", k.keys[i].ID, key.ID)
+			}
+		}
+		key.Token = ""
+		k.keys = append(k.keys, key)
+		k.digests = append(k.digests, digest)
+	}
+	return k, nil
+}
+
+// Authenticate returns the key whose token is token, without the token.
+// A key outside its validity window is returned with ErrExpired or
+// ErrNotYetValid so callers can report which key was refused. Tokens are
+// compared by digest in constant time, and every key is checked.
+func (k *Keyring) Authenticate(token string) (Key, error) {
+	digest := sha256.Sum256([]byte(token))
+	match := -1
+	for i, d := range k.digests {
+		if subtle.ConstantTimeCompare(digest[:], d[:]) == 1 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return Key{}, ErrUnknown
+	}
+	key := k.keys[match]
+	if now := time.Now(); !key.ValidAt(now) {
+		if now.Before(key.NotBefore) {
+			return key, ErrNotYetValid
+		}
+		return key, ErrExpired
+	}
+	return key, nil
+}
+
+// Keys returns the keys, without their tokens, in the order given to New.
+func (k *Keyring) Keys() []Key {
+	return append([]Key(nil), k.keys...)
+}
+
+// Len returns the number of keys.
+func (k *Keyring) Len() int { return len(k.keys) }
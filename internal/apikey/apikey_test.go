@@ -0,0 +1,62 @@
+package apikey_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/apikey"
+)
+
+func TestKeyring(t *testing.T) {
+	now := time.Now()
+	keys, err := apikey.New(
+		apikey.Key{ID: "current", Token: "new-token", Org: "acme"},
+		apikey.Key{ID: "previous", Token: "old-token", Scopes: []string{"admin:privacy"}, ExpiresAt: now.Add(time.Hour)},
+		apikey.Key{ID: "retired", Token: "older-token", ExpiresAt: now.Add(-time.Minute)},
+		apikey.Key{ID: "next", Token: "next-token", NotBefore: now.Add(time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		token, id string
+		err       error
+	}{
+		{"new-token", "current", nil},
+		{"old-token", "previous", nil},
+		{"older-token", "retired", apikey.ErrExpired},
+		{"next-token", "next", apikey.ErrNotYetValid},
+		{"guess", "", apikey.ErrUnknown},
+	} {
+		key, err := keys.Authenticate(tc.token)
+		if key.ID != tc.id || !errors.Is(err, tc.err) {
+			t.Errorf("Authenticate(%q) = %q, %v; want %q, %v", tc.token, key.ID, err, tc.id, tc.err)
+		}
+		if key.Token != "" {
+			t.Errorf("Authenticate(%q) returned the token", tc.token)
+		}
+	}
+
+	if key, _ := keys.Authenticate("new-token"); key.Org != "acme" || !slices.Equal(key.Scopes, apikey.DefaultScopes) {
+		t.Errorf("Authenticate() = org %q, scopes %v; want acme with the default scopes", key.Org, key.Scopes)
+	}
+	if key, _ := keys.Authenticate("old-token"); !slices.Equal(key.Scopes, []string{"admin:privacy"}) {
+		t.Errorf("Authenticate() scopes = %v, want the key's own", key.Scopes)
+	}
+
+	for name, bad := range map[string][]apikey.Key{
+		"missing ID":    {{Token: "t"}},
+		"missing token": {{ID: "a"}},
+		"duplicate ID":  {{ID: "a", Token: "t1"}, {ID: "a", Token: "t2"}},
+		"shared token":  {{ID: "a", Token: "t"}, {ID: "b", Token: "t"}},
+		"empty window":  {{ID: "a", Token: "t", NotBefore: now, ExpiresAt: now}},
+		"unknown scope": {{ID: "a", Token: "t", Scopes: []string{"admin:everything"}}},
+	} {
+		if _, err := apikey.New(bad...); err == nil {
+			t.Errorf("New() with %s succeeded", name)
+		}
+	}
+}
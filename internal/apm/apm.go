@@ -0,0 +1,233 @@
+// Package apm pushes AgentGuard metrics and security signals to hosted
+// monitoring platforms, Datadog and New Relic, for deployments that do not
+// scrape Prometheus. Metric exporters plug into the OpenTelemetry meter
+// provider as periodic readers, next to the Prometheus endpoint; signals are
+// sent as platform events.
+package apm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// DefaultPrefix namespaces exported metric names.
+const DefaultPrefix = "agentguard."
+
+// SignalExporter sends security signals to a monitoring platform.
+type SignalExporter interface {
+	Name() string
+	ExportSignals(ctx context.Context, orgID, agentID string, signals []models.SecuritySignal) error
+}
+
+// Publisher fans security signals out to exporters and counts them on the
+// agentguard_security_signals_total metric. A nil Publisher does nothing.
+type Publisher struct {
+	exporters []SignalExporter
+	timeout   time.Duration
+	counter   metric.Int64Counter
+}
+
+// NewPublisher creates a publisher for exporters.
+func NewPublisher(exporters ...SignalExporter) *Publisher {
+	p := &Publisher{exporters: exporters, timeout: 10 * time.Second}
+	var err error
+	p.counter, err = otel.Meter("github.com/agentguard/agentguard/internal/apm").Int64Counter(
+		"agentguard_security_signals_total",
+		metric.WithDescription("Security signals raised, by type and severity"),
+	)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create security signal metric")
+	}
+	return p
+}
+
+// Publish sends signals to every exporter in the background. Failures are
+// logged; a monitoring outage never holds up a request.
+func (p *Publisher) Publish(ctx context.Context, orgID, agentID string, signals ...models.SecuritySignal) {
+	if p == nil || len(signals) == 0 {
+		return
+	}
+	if p.counter != nil {
+		for _, sig := range signals {
+			p.counter.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("type", string(sig.Type)),
+				attribute.String("severity", sig.Severity),
+			))
+		}
+	}
+	if len(p.exporters) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+		for _, e := range p.exporters {
+			if err := e.ExportSignals(ctx, orgID, agentID, signals); err != nil {
+				log.Error().Err(err).Str("exporter", e.Name()).Int("signals", len(signals)).Msg("failed to export security signals")
+			}
+		}
+	}()
+}
+
+// temporality asks for deltas from counters and histograms, which both
+// platforms ingest as counts per interval, and cumulative values from
+// up-down counters and gauges, which are reported as gauges.
+func temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch k {
+	case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindObservableCounter, sdkmetric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	}
+	return metricdata.CumulativeTemporality
+}
+
+type pointKind int
+
+const (
+	pointCount pointKind = iota
+	pointGauge
+	pointSummary
+)
+
+// point is one exported value in a form both platforms map from.
+type point struct {
+	name  string
+	kind  pointKind
+	value float64 // count or gauge value
+	// Summary fields, from histograms.
+	count    uint64
+	sum      float64
+	min, max *float64
+	start    time.Time
+	time     time.Time
+	tags     map[string]string
+}
+
+// points flattens collected metrics. Monotonic deltas become counts, other
+// sums and gauges become gauges, and histograms become summaries. Empty
+// deltas are skipped.
+func points(rm *metricdata.ResourceMetrics, prefix string) []point {
+	var out []point
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			name := metricName(prefix, m.Name)
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				out = appendSum(out, name, data.DataPoints, data.IsMonotonic && data.Temporality == metricdata.DeltaTemporality)
+			case metricdata.Sum[float64]:
+				out = appendSum(out, name, data.DataPoints, data.IsMonotonic && data.Temporality == metricdata.DeltaTemporality)
+			case metricdata.Gauge[int64]:
+				out = appendSum(out, name, data.DataPoints, false)
+			case metricdata.Gauge[float64]:
+				out = appendSum(out, name, data.DataPoints, false)
+			case metricdata.Histogram[int64]:
+				out = appendHistogram(out, name, data.DataPoints)
+			case metricdata.Histogram[float64]:
+				out = appendHistogram(out, name, data.DataPoints)
+			}
+		}
+	}
+	return out
+}
+
+func appendSum[N int64 | float64](out []point, name string, dps []metricdata.DataPoint[N], count bool) []point {
+	for _, dp := range dps {
+		p := point{name: name, kind: pointGauge, value: float64(dp.Value), start: dp.StartTime, time: dp.Time, tags: tags(dp.Attributes)}
+		if count {
+			if dp.Value == 0 {
+				continue
+			}
+			p.kind = pointCount
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func appendHistogram[N int64 | float64](out []point, name string, dps []metricdata.HistogramDataPoint[N]) []point {
+	for _, dp := range dps {
+		if dp.Count == 0 {
+			continue
+		}
+		p := point{name: name, kind: pointSummary, count: dp.Count, sum: float64(dp.Sum), start: dp.StartTime, time: dp.Time, tags: tags(dp.Attributes)}
+		if v, ok := dp.Min.Value(); ok {
+			f := float64(v)
+			p.min = &f
+		}
+		if v, ok := dp.Max.Value(); ok {
+			f := float64(v)
+			p.max = &f
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// metricName prefixes a metric name, dropping an agentguard_ prefix the
+// namespace already carries.
+func metricName(prefix, name string) string {
+	if strings.HasPrefix(prefix, "agentguard") {
+		name = strings.TrimPrefix(name, "agentguard_")
+	}
+	return prefix + name
+}
+
+func tags(set attribute.Set) map[string]string {
+	if set.Len() == 0 {
+		return nil
+	}
+	out := make(map[string]string, set.Len())
+	for _, kv := range set.ToSlice() {
+		out[string(kv.Key)] = kv.Value.Emit()
+	}
+	return out
+}
+
+// tagList renders tags as sorted key:value pairs.
+func tagList(tags map[string]string) []string {
+	out := make([]string, 0, len(tags))
+	for k, v := range tags {
+		out = append(out, k+":"+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// signalTags describes a signal for tagging and filtering.
+func signalTags(orgID, agentID string, sig models.SecuritySignal) map[string]string {
+	t := map[string]string{
+		"org_id":      orgID,
+		"signal_type": string(sig.Type),
+		"severity":    sig.Severity,
+	}
+	if agentID != "" {
+		t["agent_id"] = agentID
+	}
+	if sig.TraceID != "" {
+		t["trace_id"] = sig.TraceID
+	}
+	return t
+}
+
+// signalText is a signal's event body.
+func signalText(sig models.SecuritySignal) string {
+	text := sig.Description
+	if text == "" {
+		text = sig.Title
+	}
+	if sig.ID != "" {
+		text = fmt.Sprintf("%s\n\nsignal_id: %s", text, sig.ID)
+	}
+	return text
+}
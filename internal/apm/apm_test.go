@@ -0,0 +1,343 @@
+package apm_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/apm"
+	"github.com/agentguard/agentguard/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect records a counter, an up-down counter and a histogram and
+// collects them with the exporter's temporality.
+func collect(t *testing.T, exp sdkmetric.Exporter) *metricdata.ResourceMetrics {
+	t.Helper()
+	reader := sdkmetric.NewManualReader(sdkmetric.WithTemporalitySelector(exp.Temporality))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter := mp.Meter("test")
+	ctx := context.Background()
+
+	decisions, _ := meter.Int64Counter("agentguard_decisions_total")
+	decisions.Add(ctx, 3, metric.WithAttributes(attribute.String("decision", "deny")))
+	inflight, _ := meter.Int64UpDownCounter("agentguard_inflight")
+	inflight.Add(ctx, 2)
+	latency, _ := meter.Float64Histogram("agentguard_latency_ms")
+	latency.Record(ctx, 10)
+	latency.Record(ctx, 30)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	return &rm
+}
+
+type request struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// recorder is an HTTP endpoint that records requests.
+type recorder struct {
+	mu   sync.Mutex
+	reqs []request
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.reqs = append(r.reqs, request{req.URL.Path, req.Header, body})
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *recorder) requests() []request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]request(nil), r.reqs...)
+}
+
+func testSignal() models.SecuritySignal {
+	return models.SecuritySignal{
+		ID:        "sig-1",
+		TraceID:   "trace-1",
+		Type:      models.SignalToolAbuse,
+		Severity:  "critical",
+		Title:     "honeypot invoked",
+		Timestamp: time.Unix(1700000000, 0),
+	}
+}
+
+func TestDatadogAPI(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	dd, err := apm.NewDatadog(apm.DatadogConfig{APIKey: "k", APIURL: srv.URL, Tags: []string{"env:test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dd.Export(context.Background(), collect(t, dd)); err != nil {
+		t.Fatal(err)
+	}
+	if err := dd.ExportSignals(context.Background(), "org-1", "agent-1", []models.SecuritySignal{testSignal()}); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := rec.requests()
+	if len(reqs) != 2 || reqs[0].path != "/api/v2/series" || reqs[1].path != "/api/v1/events" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if got := reqs[0].header.Get("DD-API-KEY"); got != "k" {
+		t.Errorf("DD-API-KEY = %q", got)
+	}
+
+	var payload struct {
+		Series []struct {
+			Metric string
+			Type   int
+			Points []struct{ Value float64 }
+			Tags   []string
+		}
+	}
+	if err := json.Unmarshal(reqs[0].body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	series := map[string]int{}
+	values := map[string]float64{}
+	for _, s := range payload.Series {
+		series[s.Metric] = s.Type
+		values[s.Metric] = s.Points[0].Value
+		if s.Tags[0] != "env:test" {
+			t.Errorf("%s tags = %v", s.Metric, s.Tags)
+		}
+	}
+	want := map[string]int{
+		"agentguard.decisions_total":  1,
+		"agentguard.inflight":         3,
+		"agentguard.latency_ms.count": 1,
+		"agentguard.latency_ms.sum":   1,
+		"agentguard.latency_ms.avg":   3,
+		"agentguard.latency_ms.min":   3,
+		"agentguard.latency_ms.max":   3,
+	}
+	for name, typ := range want {
+		if series[name] != typ {
+			t.Errorf("%s type = %d, want %d", name, series[name], typ)
+		}
+	}
+	if values["agentguard.decisions_total"] != 3 || values["agentguard.latency_ms.avg"] != 20 {
+		t.Errorf("values = %v", values)
+	}
+
+	var event map[string]any
+	if err := json.Unmarshal(reqs[1].body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event["alert_type"] != "error" || event["aggregation_key"] != "tool_abuse" || event["title"] != "AgentGuard: honeypot invoked" {
+		t.Errorf("event = %v", event)
+	}
+	tags, _ := json.Marshal(event["tags"])
+	for _, tag := range []string{"env:test", "org_id:org-1", "agent_id:agent-1", "severity:critical", "trace_id:trace-1"} {
+		if !strings.Contains(string(tags), `"`+tag+`"`) {
+			t.Errorf("event tags %s missing %s", tags, tag)
+		}
+	}
+}
+
+func TestDatadogAPIErrors(t *testing.T) {
+	if _, err := apm.NewDatadog(apm.DatadogConfig{}); err == nil {
+		t.Error("expected error without an API key")
+	}
+	if _, err := apm.NewDatadog(apm.DatadogConfig{Mode: "carrier-pigeon"}); err == nil {
+		t.Error("expected error for an unknown mode")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	dd, err := apm.NewDatadog(apm.DatadogConfig{APIKey: "bad", APIURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dd.ExportSignals(context.Background(), "org-1", "", []models.SecuritySignal{testSignal()}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("err = %v, want 403", err)
+	}
+}
+
+func TestDogStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("udp unavailable:", err)
+	}
+	defer conn.Close()
+
+	dd, err := apm.NewDatadog(apm.DatadogConfig{Mode: apm.DatadogModeDogStatsD, StatsdAddr: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dd.Shutdown(context.Background())
+	if err := dd.Export(context.Background(), collect(t, dd)); err != nil {
+		t.Fatal(err)
+	}
+	if err := dd.ExportSignals(context.Background(), "org-1", "", []models.SecuritySignal{testSignal()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	buf := make([]byte, 65536)
+	for len(got) < 2 {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("reading datagrams: %v (got %q)", err, got)
+		}
+		got = append(got, string(buf[:n]))
+	}
+	metrics, event := got[0], got[1]
+	for _, line := range []string{
+		"agentguard.decisions_total:3|c|#decision:deny",
+		"agentguard.inflight:2|g",
+		"agentguard.latency_ms.count:2|c",
+		"agentguard.latency_ms.avg:20|g",
+	} {
+		if !strings.Contains(metrics, line) {
+			t.Errorf("metrics datagram missing %q:\n%s", line, metrics)
+		}
+	}
+	if !strings.HasPrefix(event, "_e{28,") || !strings.Contains(event, "|t:error|s:agentguard|k:tool_abuse|d:1700000000|#") {
+		t.Errorf("event datagram = %q", event)
+	}
+}
+
+func TestNewRelic(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	nr, err := apm.NewNewRelic(apm.NewRelicConfig{
+		LicenseKey: "lk",
+		MetricsURL: srv.URL + "/metric/v1",
+		EventsURL:  srv.URL + "/v1/accounts/1/events",
+		Attributes: map[string]string{"environment": "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nr.Export(context.Background(), collect(t, nr)); err != nil {
+		t.Fatal(err)
+	}
+	if err := nr.ExportSignals(context.Background(), "org-1", "agent-1", []models.SecuritySignal{testSignal()}); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := rec.requests()
+	if len(reqs) != 2 || reqs[0].path != "/metric/v1" || reqs[1].path != "/v1/accounts/1/events" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if got := reqs[0].header.Get("Api-Key"); got != "lk" {
+		t.Errorf("Api-Key = %q", got)
+	}
+
+	var payload []struct {
+		Common  struct{ Attributes map[string]string }
+		Metrics []struct {
+			Name  string
+			Type  string
+			Value any
+		}
+	}
+	if err := json.Unmarshal(reqs[0].body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload[0].Common.Attributes["environment"] != "test" {
+		t.Errorf("common attributes = %v", payload[0].Common.Attributes)
+	}
+	types := map[string]string{}
+	for _, m := range payload[0].Metrics {
+		types[m.Name] = m.Type
+		if m.Name == "agentguard.latency_ms" {
+			summary, _ := m.Value.(map[string]any)
+			if summary["count"] != 2.0 || summary["sum"] != 40.0 || summary["min"] != 10.0 || summary["max"] != 30.0 {
+				t.Errorf("summary = %v", summary)
+			}
+		}
+	}
+	if types["agentguard.decisions_total"] != "count" || types["agentguard.inflight"] != "gauge" || types["agentguard.latency_ms"] != "summary" {
+		t.Errorf("metric types = %v", types)
+	}
+
+	var events []map[string]any
+	if err := json.Unmarshal(reqs[1].body, &events); err != nil {
+		t.Fatal(err)
+	}
+	e := events[0]
+	if e["eventType"] != apm.NewRelicEventType || e["signal_type"] != "tool_abuse" || e["agent_id"] != "agent-1" || e["environment"] != "test" {
+		t.Errorf("event = %v", e)
+	}
+}
+
+func TestNewRelicSkipsEventsWithoutAccount(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	nr, err := apm.NewNewRelic(apm.NewRelicConfig{LicenseKey: "lk", MetricsURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nr.ExportSignals(context.Background(), "org-1", "", []models.SecuritySignal{testSignal()}); err != nil {
+		t.Fatal(err)
+	}
+	if reqs := rec.requests(); len(reqs) != 0 {
+		t.Errorf("sent %d requests without an account ID", len(reqs))
+	}
+	if _, err := apm.NewNewRelic(apm.NewRelicConfig{LicenseKey: "lk", Region: "mars"}); err == nil {
+		t.Error("expected error for an unknown region")
+	}
+}
+
+// fakeExporter records exported signals.
+type fakeExporter struct {
+	done chan []models.SecuritySignal
+}
+
+func (f *fakeExporter) Name() string { return "fake" }
+
+func (f *fakeExporter) ExportSignals(_ context.Context, _, _ string, signals []models.SecuritySignal) error {
+	f.done <- signals
+	return nil
+}
+
+func TestPublisher(t *testing.T) {
+	var nilPub *apm.Publisher
+	nilPub.Publish(context.Background(), "org-1", "", testSignal())
+
+	f := &fakeExporter{done: make(chan []models.SecuritySignal, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+	apm.NewPublisher(f).Publish(ctx, "org-1", "agent-1", testSignal())
+	// A request finishing must not cancel the export.
+	cancel()
+	select {
+	case got := <-f.done:
+		if len(got) != 1 || got[0].ID != "sig-1" {
+			t.Errorf("exported %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("signals were not exported")
+	}
+}
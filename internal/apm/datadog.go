@@ -0,0 +1,346 @@
+package apm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Datadog transport modes.
+const (
+	DatadogModeAPI       = "api"
+	DatadogModeDogStatsD = "dogstatsd"
+)
+
+// DatadogConfig configures a Datadog exporter.
+type DatadogConfig struct {
+	// Mode is api (default), which posts to the Datadog HTTP API, or
+	// dogstatsd, which sends to a local Datadog Agent over UDP.
+	Mode string
+	// APIKey authenticates to the API. Required in api mode.
+	APIKey string
+	// Site is the Datadog site, e.g. datadoghq.eu. Defaults to
+	// datadoghq.com.
+	Site string
+	// APIURL overrides the API base URL derived from Site.
+	APIURL string
+	// StatsdAddr is the agent's DogStatsD address. Defaults to
+	// 127.0.0.1:8125.
+	StatsdAddr string
+	// Prefix namespaces metric names. Defaults to DefaultPrefix.
+	Prefix string
+	// Tags are added to every metric and event, e.g. env:prod.
+	Tags []string
+	// Client sends API requests. Defaults to a client with a 10 second
+	// timeout.
+	Client *http.Client
+}
+
+// Datadog exports metrics and signals to Datadog. It implements
+// sdkmetric.Exporter and SignalExporter.
+type Datadog struct {
+	cfg DatadogConfig
+
+	mu   sync.Mutex
+	conn net.Conn // dogstatsd
+}
+
+// NewDatadog creates a Datadog exporter.
+func NewDatadog(cfg DatadogConfig) (*Datadog, error) {
+	if cfg.Mode == "" {
+		cfg.Mode = DatadogModeAPI
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	d := &Datadog{cfg: cfg}
+	switch cfg.Mode {
+	case DatadogModeAPI:
+		if cfg.APIKey == "" {
+			return nil, errors.New("datadog: api_key is required in api mode")
+		}
+		if d.cfg.APIURL == "" {
+			site := cfg.Site
+			if site == "" {
+				site = "datadoghq.com"
+			}
+			d.cfg.APIURL = "https://api." + site
+		}
+		d.cfg.APIURL = strings.TrimRight(d.cfg.APIURL, "/")
+		if d.cfg.Client == nil {
+			d.cfg.Client = &http.Client{Timeout: 10 * time.Second}
+		}
+	case DatadogModeDogStatsD:
+		if d.cfg.StatsdAddr == "" {
+			d.cfg.StatsdAddr = "127.0.0.1:8125"
+		}
+		conn, err := net.Dial("udp", d.cfg.StatsdAddr)
+		if err != nil {
+			return nil, fmt.Errorf("datadog: dialing dogstatsd: %w", err)
+		}
+		d.conn = conn
+	default:
+		return nil, fmt.Errorf("datadog: unknown mode %q", cfg.Mode)
+	}
+	return d, nil
+}
+
+// Name identifies the exporter in logs.
+func (d *Datadog) Name() string { return "datadog" }
+
+// Temporality implements sdkmetric.Exporter.
+func (d *Datadog) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return temporality(k)
+}
+
+// Aggregation implements sdkmetric.Exporter.
+func (d *Datadog) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+// Export implements sdkmetric.Exporter. Histograms are sent as .count and
+// .sum counts plus .avg, .min and .max gauges.
+func (d *Datadog) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	pts := points(rm, d.cfg.Prefix)
+	if len(pts) == 0 {
+		return nil
+	}
+	if d.cfg.Mode == DatadogModeDogStatsD {
+		return d.statsd(statsdMetrics(pts, d.cfg.Tags))
+	}
+
+	var series []ddSeries
+	for _, p := range pts {
+		series = append(series, d.series(p)...)
+	}
+	return postJSON(ctx, d.cfg.Client, d.cfg.APIURL+"/api/v2/series", d.headers(), map[string]any{"series": series})
+}
+
+// ForceFlush implements sdkmetric.Exporter; nothing is buffered.
+func (d *Datadog) ForceFlush(context.Context) error { return nil }
+
+// Shutdown implements sdkmetric.Exporter.
+func (d *Datadog) Shutdown(context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.conn == nil {
+		return nil
+	}
+	err := d.conn.Close()
+	d.conn = nil
+	return err
+}
+
+// ExportSignals sends each signal as a Datadog event, aggregated by signal
+// type. High and critical signals are error events.
+func (d *Datadog) ExportSignals(ctx context.Context, orgID, agentID string, signals []models.SecuritySignal) error {
+	var errs []error
+	for _, sig := range signals {
+		tags := append(append([]string{}, d.cfg.Tags...), tagList(signalTags(orgID, agentID, sig))...)
+		title := "AgentGuard: " + sig.Title
+		if d.cfg.Mode == DatadogModeDogStatsD {
+			errs = append(errs, d.statsd([]string{statsdEvent(title, signalText(sig), sig, tags)}))
+			continue
+		}
+		event := map[string]any{
+			"title":            title,
+			"text":             signalText(sig),
+			"alert_type":       ddAlertType(sig.Severity),
+			"priority":         "normal",
+			"source_type_name": "agentguard",
+			"aggregation_key":  string(sig.Type),
+			"tags":             tags,
+		}
+		if !sig.Timestamp.IsZero() {
+			event["date_happened"] = sig.Timestamp.Unix()
+		}
+		errs = append(errs, postJSON(ctx, d.cfg.Client, d.cfg.APIURL+"/api/v1/events", d.headers(), event))
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Datadog) headers() map[string]string {
+	return map[string]string{"DD-API-KEY": d.cfg.APIKey}
+}
+
+// Datadog series metric types.
+const (
+	ddCount = 1
+	ddGauge = 3
+)
+
+type ddSeries struct {
+	Metric   string    `json:"metric"`
+	Type     int       `json:"type"`
+	Points   []ddPoint `json:"points"`
+	Interval int64     `json:"interval,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+}
+
+type ddPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+func (d *Datadog) series(p point) []ddSeries {
+	tags := append(append([]string{}, d.cfg.Tags...), tagList(p.tags)...)
+	ts := p.time.Unix()
+	var interval int64
+	if !p.start.IsZero() {
+		interval = int64(p.time.Sub(p.start).Seconds())
+	}
+	one := func(name string, typ int, v float64) ddSeries {
+		s := ddSeries{Metric: name, Type: typ, Points: []ddPoint{{ts, v}}, Tags: tags}
+		if typ == ddCount {
+			s.Interval = interval
+		}
+		return s
+	}
+	switch p.kind {
+	case pointCount:
+		return []ddSeries{one(p.name, ddCount, p.value)}
+	case pointGauge:
+		return []ddSeries{one(p.name, ddGauge, p.value)}
+	}
+	out := []ddSeries{
+		one(p.name+".count", ddCount, float64(p.count)),
+		one(p.name+".sum", ddCount, p.sum),
+		one(p.name+".avg", ddGauge, p.sum/float64(p.count)),
+	}
+	if p.min != nil {
+		out = append(out, one(p.name+".min", ddGauge, *p.min))
+	}
+	if p.max != nil {
+		out = append(out, one(p.name+".max", ddGauge, *p.max))
+	}
+	return out
+}
+
+func ddAlertType(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	}
+	return "info"
+}
+
+// statsdMetrics renders points as DogStatsD metric lines.
+func statsdMetrics(pts []point, globalTags []string) []string {
+	var lines []string
+	line := func(name string, v float64, typ string, tags []string) {
+		l := name + ":" + strconv.FormatFloat(v, 'f', -1, 64) + "|" + typ
+		if len(tags) > 0 {
+			l += "|#" + strings.Join(tags, ",")
+		}
+		lines = append(lines, l)
+	}
+	for _, p := range pts {
+		tags := append(append([]string{}, globalTags...), tagList(p.tags)...)
+		switch p.kind {
+		case pointCount:
+			line(p.name, p.value, "c", tags)
+		case pointGauge:
+			line(p.name, p.value, "g", tags)
+		case pointSummary:
+			line(p.name+".count", float64(p.count), "c", tags)
+			line(p.name+".sum", p.sum, "c", tags)
+			line(p.name+".avg", p.sum/float64(p.count), "g", tags)
+			if p.min != nil {
+				line(p.name+".min", *p.min, "g", tags)
+			}
+			if p.max != nil {
+				line(p.name+".max", *p.max, "g", tags)
+			}
+		}
+	}
+	return lines
+}
+
+// statsdEvent renders a signal as a DogStatsD event datagram.
+func statsdEvent(title, text string, sig models.SecuritySignal, tags []string) string {
+	text = strings.ReplaceAll(text, "\n", `\n`)
+	e := fmt.Sprintf("_e{%d,%d}:%s|%s|p:normal|t:%s|s:agentguard|k:%s", len(title), len(text), title, text, ddAlertType(sig.Severity), sig.Type)
+	if !sig.Timestamp.IsZero() {
+		e += "|d:" + strconv.FormatInt(sig.Timestamp.Unix(), 10)
+	}
+	if len(tags) > 0 {
+		e += "|#" + strings.Join(tags, ",")
+	}
+	return e
+}
+
+// statsdMaxPacket keeps datagrams under a typical network MTU.
+const statsdMaxPacket = 1432
+
+// statsd sends lines to the agent, packing as many into each datagram as
+// fit.
+func (d *Datadog) statsd(lines []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.conn == nil {
+		return errors.New("datadog: exporter is shut down")
+	}
+	var buf bytes.Buffer
+	flush := func() error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		_, err := d.conn.Write(buf.Bytes())
+		buf.Reset()
+		return err
+	}
+	for _, l := range lines {
+		if buf.Len() > 0 && buf.Len()+1+len(l) > statsdMaxPacket {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(l)
+	}
+	return flush()
+}
+
+// postJSON posts a JSON body and fails on a non-2xx response.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,170 @@
+package apm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// NewRelicEventType is the event type signals are recorded as, queryable
+// with NRQL as FROM AgentGuardSecuritySignal.
+const NewRelicEventType = "AgentGuardSecuritySignal"
+
+// NewRelicConfig configures a New Relic exporter.
+type NewRelicConfig struct {
+	// LicenseKey is the ingest license key. Required.
+	LicenseKey string
+	// AccountID receives signal events. Without it, only metrics are sent.
+	AccountID string
+	// Region is us (default) or eu.
+	Region string
+	// MetricsURL and EventsURL override the endpoints derived from Region.
+	MetricsURL string
+	EventsURL  string
+	// Prefix namespaces metric names. Defaults to DefaultPrefix.
+	Prefix string
+	// Attributes are added to every metric and event, e.g. environment.
+	Attributes map[string]string
+	// Client sends requests. Defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+// NewRelic exports metrics to the New Relic Metric API and signals to the
+// Event API. It implements sdkmetric.Exporter and SignalExporter.
+type NewRelic struct {
+	cfg NewRelicConfig
+}
+
+// NewNewRelic creates a New Relic exporter.
+func NewNewRelic(cfg NewRelicConfig) (*NewRelic, error) {
+	if cfg.LicenseKey == "" {
+		return nil, errors.New("newrelic: license_key is required")
+	}
+	metricHost, eventHost := "metric-api.newrelic.com", "insights-collector.newrelic.com"
+	switch strings.ToLower(cfg.Region) {
+	case "", "us":
+	case "eu":
+		metricHost, eventHost = "metric-api.eu.newrelic.com", "insights-collector.eu01.nr-data.net"
+	default:
+		return nil, fmt.Errorf("newrelic: unknown region %q", cfg.Region)
+	}
+	if cfg.MetricsURL == "" {
+		cfg.MetricsURL = "https://" + metricHost + "/metric/v1"
+	}
+	if cfg.EventsURL == "" && cfg.AccountID != "" {
+		cfg.EventsURL = "https://" + eventHost + "/v1/accounts/" + cfg.AccountID + "/events"
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &NewRelic{cfg: cfg}, nil
+}
+
+// Name identifies the exporter in logs.
+func (n *NewRelic) Name() string { return "newrelic" }
+
+// Temporality implements sdkmetric.Exporter.
+func (n *NewRelic) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return temporality(k)
+}
+
+// Aggregation implements sdkmetric.Exporter.
+func (n *NewRelic) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+// Export implements sdkmetric.Exporter. Histograms are sent as summary
+// metrics.
+func (n *NewRelic) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	pts := points(rm, n.cfg.Prefix)
+	if len(pts) == 0 {
+		return nil
+	}
+	metrics := make([]map[string]any, 0, len(pts))
+	for _, p := range pts {
+		m := map[string]any{
+			"name":      p.name,
+			"timestamp": p.time.UnixMilli(),
+		}
+		if len(p.tags) > 0 {
+			m["attributes"] = p.tags
+		}
+		if !p.start.IsZero() {
+			m["interval.ms"] = p.time.Sub(p.start).Milliseconds()
+		}
+		switch p.kind {
+		case pointCount:
+			m["type"], m["value"] = "count", p.value
+		case pointGauge:
+			m["type"], m["value"] = "gauge", p.value
+			delete(m, "interval.ms")
+		case pointSummary:
+			// The Metric API requires min and max; without extrema the
+			// mean stands in for both.
+			mean := p.sum / float64(p.count)
+			lo, hi := mean, mean
+			if p.min != nil {
+				lo = *p.min
+			}
+			if p.max != nil {
+				hi = *p.max
+			}
+			m["type"] = "summary"
+			m["value"] = map[string]any{"count": p.count, "sum": p.sum, "min": lo, "max": hi}
+		}
+		metrics = append(metrics, m)
+	}
+	body := []map[string]any{{"metrics": metrics}}
+	if len(n.cfg.Attributes) > 0 {
+		body[0]["common"] = map[string]any{"attributes": n.cfg.Attributes}
+	}
+	return postJSON(ctx, n.cfg.Client, n.cfg.MetricsURL, n.headers(), body)
+}
+
+// ForceFlush implements sdkmetric.Exporter; nothing is buffered.
+func (n *NewRelic) ForceFlush(context.Context) error { return nil }
+
+// Shutdown implements sdkmetric.Exporter.
+func (n *NewRelic) Shutdown(context.Context) error { return nil }
+
+// ExportSignals records signals as AgentGuardSecuritySignal events. It does
+// nothing without an account ID.
+func (n *NewRelic) ExportSignals(ctx context.Context, orgID, agentID string, signals []models.SecuritySignal) error {
+	if n.cfg.EventsURL == "" {
+		return nil
+	}
+	events := make([]map[string]any, 0, len(signals))
+	for _, sig := range signals {
+		e := map[string]any{
+			"eventType":   NewRelicEventType,
+			"signalId":    sig.ID,
+			"title":       sig.Title,
+			"description": sig.Description,
+		}
+		for k, v := range n.cfg.Attributes {
+			e[k] = v
+		}
+		for k, v := range signalTags(orgID, agentID, sig) {
+			e[k] = v
+		}
+		if !sig.Timestamp.IsZero() {
+			e["timestamp"] = sig.Timestamp.Unix()
+		}
+		events = append(events, e)
+	}
+	return postJSON(ctx, n.cfg.Client, n.cfg.EventsURL, n.headers(), events)
+}
+
+func (n *NewRelic) headers() map[string]string {
+	return map[string]string{"Api-Key": n.cfg.LicenseKey}
+}
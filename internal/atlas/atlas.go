@@ -0,0 +1,123 @@
+// Package atlas embeds the MITRE ATLAS (Adversarial Threat Landscape for
+// Artificial-Intelligence Systems) catalog of adversary tactics, techniques
+// and mitigations. The catalog covers the tactics and techniques relevant to
+// LLM applications and agents; see https://atlas.mitre.org for the full
+// matrix.
+package atlas
+
+import "sort"
+
+// URL is the canonical location of the ATLAS matrix.
+const URL = "https://atlas.mitre.org"
+
+// Tactic is an adversary goal, e.g. initial access or exfiltration.
+type Tactic struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Technique is how an adversary achieves one or more tactics. Sub-techniques
+// carry their parent's ID.
+type Technique struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tactics     []string `json:"tactics"`
+	ParentID    string   `json:"parent_id,omitempty"`
+}
+
+// URL returns the technique's page on the ATLAS site.
+func (t Technique) URL() string {
+	return URL + "/techniques/" + t.ID
+}
+
+// Mitigation is a security concept or class of technology that prevents a
+// technique from succeeding.
+type Mitigation struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Techniques  []string `json:"techniques"`
+}
+
+// Tactics returns the tactics in matrix order.
+func Tactics() []Tactic {
+	return append([]Tactic(nil), tactics...)
+}
+
+// GetTactic returns a tactic by ID.
+func GetTactic(id string) (Tactic, bool) {
+	for _, t := range tactics {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return Tactic{}, false
+}
+
+// Techniques returns every technique and sub-technique sorted by ID.
+func Techniques() []Technique {
+	out := append([]Technique(nil), techniques...)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// GetTechnique returns a technique or sub-technique by ID.
+func GetTechnique(id string) (Technique, bool) {
+	for _, t := range techniques {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return Technique{}, false
+}
+
+// TechniquesFor returns the techniques that achieve a tactic, sorted by ID.
+func TechniquesFor(tacticID string) []Technique {
+	var out []Technique
+	for _, t := range Techniques() {
+		for _, ta := range t.Tactics {
+			if ta == tacticID {
+				out = append(out, t)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// SubTechniques returns a technique's sub-techniques, sorted by ID.
+func SubTechniques(id string) []Technique {
+	var out []Technique
+	for _, t := range Techniques() {
+		if t.ParentID == id {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Mitigations returns every mitigation sorted by ID.
+func Mitigations() []Mitigation {
+	return append([]Mitigation(nil), mitigations...)
+}
+
+// MitigationsFor returns the mitigations for a technique. A sub-technique
+// is also mitigated by its parent's mitigations.
+func MitigationsFor(techniqueID string) []Mitigation {
+	ids := map[string]bool{techniqueID: true}
+	if t, ok := GetTechnique(techniqueID); ok && t.ParentID != "" {
+		ids[t.ParentID] = true
+	}
+	var out []Mitigation
+	for _, m := range mitigations {
+		for _, id := range m.Techniques {
+			if ids[id] {
+				out = append(out, m)
+				break
+			}
+		}
+	}
+	return out
+}
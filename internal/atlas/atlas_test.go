@@ -0,0 +1,61 @@
+package atlas_test
+
+import (
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/atlas"
+)
+
+func TestCatalogConsistency(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, tech := range atlas.Techniques() {
+		if seen[tech.ID] {
+			t.Errorf("duplicate technique %s", tech.ID)
+		}
+		seen[tech.ID] = true
+		if len(tech.Tactics) == 0 {
+			t.Errorf("%s has no tactics", tech.ID)
+		}
+		for _, id := range tech.Tactics {
+			if _, ok := atlas.GetTactic(id); !ok {
+				t.Errorf("%s references unknown tactic %s", tech.ID, id)
+			}
+		}
+		if tech.ParentID != "" {
+			if _, ok := atlas.GetTechnique(tech.ParentID); !ok {
+				t.Errorf("%s references unknown parent %s", tech.ID, tech.ParentID)
+			}
+		}
+	}
+	for _, m := range atlas.Mitigations() {
+		for _, id := range m.Techniques {
+			if !seen[id] {
+				t.Errorf("%s references unknown technique %s", m.ID, id)
+			}
+		}
+	}
+	for _, tactic := range atlas.Tactics() {
+		if len(atlas.TechniquesFor(tactic.ID)) == 0 {
+			t.Errorf("tactic %s has no techniques", tactic.ID)
+		}
+	}
+}
+
+func TestMitigationsFor(t *testing.T) {
+	// Indirect prompt injection inherits the prompt injection mitigations.
+	var guardrails bool
+	for _, m := range atlas.MitigationsFor("AML.T0051.001") {
+		if m.ID == "AML.M0020" {
+			guardrails = true
+		}
+	}
+	if !guardrails {
+		t.Error("AML.T0051.001 not mitigated by generative AI guardrails")
+	}
+	if subs := atlas.SubTechniques("AML.T0051"); len(subs) != 2 {
+		t.Errorf("AML.T0051 has %d sub-techniques, want 2", len(subs))
+	}
+	if got := (atlas.Technique{ID: "AML.T0051.001"}).URL(); got != atlas.URL+"/techniques/AML.T0051.001" {
+		t.Errorf("URL() = %s", got)
+	}
+}
@@ -0,0 +1,241 @@
+package atlas
+
+// Tactic IDs.
+const (
+	TacticReconnaissance      = "AML.TA0002"
+	TacticResourceDevelopment = "AML.TA0003"
+	TacticInitialAccess       = "AML.TA0004"
+	TacticModelAccess         = "AML.TA0000"
+	TacticExecution           = "AML.TA0005"
+	TacticPersistence         = "AML.TA0006"
+	TacticPrivilegeEscalation = "AML.TA0012"
+	TacticDefenseEvasion      = "AML.TA0007"
+	TacticCredentialAccess    = "AML.TA0013"
+	TacticDiscovery           = "AML.TA0008"
+	TacticCollection          = "AML.TA0009"
+	TacticAttackStaging       = "AML.TA0001"
+	TacticExfiltration        = "AML.TA0010"
+	TacticImpact              = "AML.TA0011"
+)
+
+var tactics = []Tactic{
+	{ID: TacticReconnaissance, Name: "Reconnaissance", Description: "The adversary is trying to gather information about the AI system they can use to plan future operations."},
+	{ID: TacticResourceDevelopment, Name: "Resource Development", Description: "The adversary is trying to establish resources they can use to support operations, such as datasets, models or infrastructure."},
+	{ID: TacticInitialAccess, Name: "Initial Access", Description: "The adversary is trying to gain access to the AI system."},
+	{ID: TacticModelAccess, Name: "AI Model Access", Description: "The adversary is attempting to gain some level of access to an AI model, through an inference API, a product built on it, or the model itself."},
+	{ID: TacticExecution, Name: "Execution", Description: "The adversary is trying to run malicious code embedded in AI artifacts or software, or to make an agent act on their behalf."},
+	{ID: TacticPersistence, Name: "Persistence", Description: "The adversary is trying to maintain their foothold via AI artifacts or software."},
+	{ID: TacticPrivilegeEscalation, Name: "Privilege Escalation", Description: "The adversary is trying to gain higher-level permissions, often by abusing the permissions granted to an LLM or agent."},
+	{ID: TacticDefenseEvasion, Name: "Defense Evasion", Description: "The adversary is trying to avoid being detected by AI-enabled security software and guardrails."},
+	{ID: TacticCredentialAccess, Name: "Credential Access", Description: "The adversary is trying to steal account names, passwords and API keys."},
+	{ID: TacticDiscovery, Name: "Discovery", Description: "The adversary is trying to figure out the AI environment, its models and its configuration."},
+	{ID: TacticCollection, Name: "Collection", Description: "The adversary is trying to gather AI artifacts and other information relevant to their goal."},
+	{ID: TacticAttackStaging, Name: "AI Attack Staging", Description: "The adversary is using their knowledge of and access to the target system to tailor the attack."},
+	{ID: TacticExfiltration, Name: "Exfiltration", Description: "The adversary is trying to steal AI artifacts or other information about the AI system."},
+	{ID: TacticImpact, Name: "Impact", Description: "The adversary is trying to manipulate, interrupt, erode confidence in, or destroy the AI system and its data."},
+}
+
+var techniques = []Technique{
+	// Reconnaissance
+	{ID: "AML.T0000", Name: "Search for Victim's Publicly Available Research Materials", Tactics: []string{TacticReconnaissance},
+		Description: "Adversaries search publicly available research, papers and technical blogs to learn how and where the victim uses AI."},
+	{ID: "AML.T0003", Name: "Search Victim-Owned Websites", Tactics: []string{TacticReconnaissance},
+		Description: "Adversaries search the victim's websites for information about AI products, models and the teams behind them."},
+	{ID: "AML.T0006", Name: "Active Scanning", Tactics: []string{TacticReconnaissance},
+		Description: "Adversaries probe or scan the victim's systems to gather information about exposed AI services."},
+
+	// Resource Development
+	{ID: "AML.T0002", Name: "Acquire Public AI Artifacts", Tactics: []string{TacticResourceDevelopment},
+		Description: "Adversaries acquire public datasets and models similar to those the victim uses, to develop and test attacks."},
+	{ID: "AML.T0016", Name: "Obtain Capabilities", Tactics: []string{TacticResourceDevelopment},
+		Description: "Adversaries obtain software tools, such as adversarial attack libraries, to support their operations."},
+	{ID: "AML.T0017", Name: "Develop Capabilities", Tactics: []string{TacticResourceDevelopment},
+		Description: "Adversaries develop their own attacks, for example adversarial example generators or jailbreak prompts."},
+	{ID: "AML.T0008", Name: "Acquire Infrastructure", Tactics: []string{TacticResourceDevelopment},
+		Description: "Adversaries buy, lease or rent compute and hosting to train proxy models and stage attacks."},
+	{ID: "AML.T0019", Name: "Publish Poisoned Datasets", Tactics: []string{TacticResourceDevelopment},
+		Description: "Adversaries poison datasets and publish them to public locations the victim may pull training data from."},
+	{ID: "AML.T0020", Name: "Poison Training Data", Tactics: []string{TacticResourceDevelopment, TacticPersistence},
+		Description: "Adversaries modify training or fine-tuning data to embed vulnerabilities or backdoors in models trained on it."},
+
+	// Initial Access
+	{ID: "AML.T0010", Name: "AI Supply Chain Compromise", Tactics: []string{TacticInitialAccess},
+		Description: "Adversaries compromise parts of the AI supply chain: GPU hardware, data, software dependencies or pretrained models."},
+	{ID: "AML.T0010.001", ParentID: "AML.T0010", Name: "AI Software", Tactics: []string{TacticInitialAccess},
+		Description: "Adversaries compromise AI frameworks, libraries or tooling the victim depends on."},
+	{ID: "AML.T0010.002", ParentID: "AML.T0010", Name: "Data", Tactics: []string{TacticInitialAccess},
+		Description: "Adversaries compromise third-party datasets or labeling services."},
+	{ID: "AML.T0010.003", ParentID: "AML.T0010", Name: "Model", Tactics: []string{TacticInitialAccess},
+		Description: "Adversaries compromise pretrained models published to model hubs and later fine-tuned or deployed by the victim."},
+	{ID: "AML.T0012", Name: "Valid Accounts", Tactics: []string{TacticInitialAccess, TacticPrivilegeEscalation},
+		Description: "Adversaries obtain and abuse credentials of existing accounts, including API keys for AI services."},
+	{ID: "AML.T0015", Name: "Evade AI Model", Tactics: []string{TacticInitialAccess, TacticDefenseEvasion, TacticImpact},
+		Description: "Adversaries craft inputs that prevent an AI model from correctly classifying or detecting them."},
+	{ID: "AML.T0049", Name: "Exploit Public-Facing Application", Tactics: []string{TacticInitialAccess},
+		Description: "Adversaries exploit weaknesses in internet-facing applications that host or front AI models."},
+	{ID: "AML.T0051", Name: "LLM Prompt Injection", Tactics: []string{TacticInitialAccess, TacticExecution, TacticPersistence, TacticPrivilegeEscalation, TacticDefenseEvasion},
+		Description: "Adversaries craft malicious prompts that cause an LLM to ignore its instructions and act in unintended ways."},
+	{ID: "AML.T0051.000", ParentID: "AML.T0051", Name: "Direct", Tactics: []string{TacticInitialAccess, TacticExecution, TacticPersistence, TacticPrivilegeEscalation, TacticDefenseEvasion},
+		Description: "The adversary submits the injected prompt to the LLM themselves."},
+	{ID: "AML.T0051.001", ParentID: "AML.T0051", Name: "Indirect", Tactics: []string{TacticInitialAccess, TacticExecution, TacticPersistence, TacticPrivilegeEscalation, TacticDefenseEvasion},
+		Description: "The injected prompt reaches the LLM through data it ingests, such as a web page, document, email or tool result."},
+	{ID: "AML.T0052", Name: "Phishing", Tactics: []string{TacticInitialAccess},
+		Description: "Adversaries send phishing messages, increasingly generated or personalized with LLMs, to gain access to victim systems."},
+
+	// AI Model Access
+	{ID: "AML.T0040", Name: "AI Model Inference API Access", Tactics: []string{TacticModelAccess},
+		Description: "Adversaries gain access to a model through its inference API, to stage attacks or exfiltrate information."},
+	{ID: "AML.T0047", Name: "AI-Enabled Product or Service", Tactics: []string{TacticModelAccess},
+		Description: "Adversaries use a product or service built on an AI model to gain indirect access to it."},
+	{ID: "AML.T0044", Name: "Full AI Model Access", Tactics: []string{TacticModelAccess},
+		Description: "Adversaries gain full white-box access to a model: its architecture, parameters and logic."},
+
+	// Execution
+	{ID: "AML.T0011", Name: "User Execution", Tactics: []string{TacticExecution},
+		Description: "Adversaries rely on a user taking an action, such as loading an untrusted model file, to execute malicious code."},
+	{ID: "AML.T0011.001", ParentID: "AML.T0011", Name: "Malicious Package", Tactics: []string{TacticExecution},
+		Description: "Adversaries publish malicious packages, including hallucinated package names suggested by LLMs, for users or agents to install."},
+	{ID: "AML.T0050", Name: "Command and Scripting Interpreter", Tactics: []string{TacticExecution},
+		Description: "Adversaries abuse interpreters, including code execution tools available to agents, to run commands."},
+	{ID: "AML.T0053", Name: "LLM Plugin Compromise", Tactics: []string{TacticExecution, TacticPrivilegeEscalation},
+		Description: "Adversaries use the plugins and tools an LLM or agent can invoke to act in systems the adversary cannot reach directly."},
+
+	// Persistence
+	{ID: "AML.T0018", Name: "Manipulate AI Model", Tactics: []string{TacticPersistence, TacticAttackStaging},
+		Description: "Adversaries modify a model's weights or code to embed a backdoor that a trigger activates."},
+	{ID: "AML.T0061", Name: "LLM Prompt Self-Replication", Tactics: []string{TacticPersistence},
+		Description: "Adversaries craft prompts that cause an LLM to reproduce the prompt in its output, spreading it to other LLMs and agents."},
+	{ID: "AML.T0070", Name: "RAG Poisoning", Tactics: []string{TacticPersistence},
+		Description: "Adversaries inject malicious content into data indexed by a retrieval-augmented generation system, so it is retrieved into prompts."},
+
+	// Privilege Escalation and Defense Evasion
+	{ID: "AML.T0054", Name: "LLM Jailbreak", Tactics: []string{TacticPrivilegeEscalation, TacticDefenseEvasion},
+		Description: "Adversaries use prompts that bypass an LLM's safety controls and guardrails."},
+
+	// Credential Access
+	{ID: "AML.T0055", Name: "Unsecured Credentials", Tactics: []string{TacticCredentialAccess},
+		Description: "Adversaries search for insecurely stored credentials, including keys left in prompts, agent configuration or tool output."},
+
+	// Discovery
+	{ID: "AML.T0007", Name: "Discover AI Artifacts", Tactics: []string{TacticDiscovery},
+		Description: "Adversaries search private sources to find models, datasets and other AI artifacts in the victim's environment."},
+	{ID: "AML.T0013", Name: "Discover AI Model Ontology", Tactics: []string{TacticDiscovery},
+		Description: "Adversaries discover a model's output space, for example the classes it predicts."},
+	{ID: "AML.T0014", Name: "Discover AI Model Family", Tactics: []string{TacticDiscovery},
+		Description: "Adversaries discover the general family or architecture of a model to tailor attacks."},
+	{ID: "AML.T0062", Name: "Discover LLM Hallucinations", Tactics: []string{TacticDiscovery},
+		Description: "Adversaries prompt LLMs to find hallucinated entities, such as package names or URLs, they can then register and weaponize."},
+
+	// Collection
+	{ID: "AML.T0035", Name: "AI Artifact Collection", Tactics: []string{TacticCollection},
+		Description: "Adversaries collect models, datasets and logs to exfiltrate or use in staging attacks."},
+	{ID: "AML.T0036", Name: "Data from Information Repositories", Tactics: []string{TacticCollection},
+		Description: "Adversaries collect sensitive data from repositories such as wikis, document stores and vector databases."},
+	{ID: "AML.T0037", Name: "Data from Local System", Tactics: []string{TacticCollection},
+		Description: "Adversaries search local file systems, for example through an agent's file tools, for data of interest."},
+
+	// AI Attack Staging
+	{ID: "AML.T0005", Name: "Create Proxy AI Model", Tactics: []string{TacticAttackStaging},
+		Description: "Adversaries build a proxy of the target model to develop and test attacks offline."},
+	{ID: "AML.T0042", Name: "Verify Attack", Tactics: []string{TacticAttackStaging},
+		Description: "Adversaries verify an attack works against the target model before deploying it."},
+	{ID: "AML.T0043", Name: "Craft Adversarial Data", Tactics: []string{TacticAttackStaging},
+		Description: "Adversaries craft inputs that cause a model to produce the adversary's desired output."},
+
+	// Exfiltration
+	{ID: "AML.T0024", Name: "Exfiltration via AI Inference API", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries use a model's inference API to exfiltrate private information about its training data or the model itself."},
+	{ID: "AML.T0024.000", ParentID: "AML.T0024", Name: "Infer Training Data Membership", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries infer whether a record was in the model's training data, exposing private information."},
+	{ID: "AML.T0024.001", ParentID: "AML.T0024", Name: "Invert AI Model", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries reconstruct training data from model outputs."},
+	{ID: "AML.T0024.002", ParentID: "AML.T0024", Name: "Extract AI Model", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries query the model to build a functional copy of it."},
+	{ID: "AML.T0025", Name: "Exfiltration via Cyber Means", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries exfiltrate AI artifacts and data over traditional channels."},
+	{ID: "AML.T0056", Name: "Extract LLM System Prompt", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries extract an LLM's system prompt, which may hold proprietary instructions or secrets."},
+	{ID: "AML.T0057", Name: "LLM Data Leakage", Tactics: []string{TacticExfiltration},
+		Description: "Adversaries craft prompts that cause an LLM to leak sensitive data from its training data or context."},
+
+	// Impact
+	{ID: "AML.T0029", Name: "Denial of AI Service", Tactics: []string{TacticImpact},
+		Description: "Adversaries send resource-intensive requests to degrade or disable an AI service."},
+	{ID: "AML.T0031", Name: "Erode AI Model Integrity", Tactics: []string{TacticImpact},
+		Description: "Adversaries degrade a model's performance over time with adversarial inputs, eroding confidence in it."},
+	{ID: "AML.T0034", Name: "Cost Harvesting", Tactics: []string{TacticImpact},
+		Description: "Adversaries send useless queries or trigger runaway agent loops to increase the cost of running the AI service."},
+	{ID: "AML.T0046", Name: "Spamming AI System with Chaff Data", Tactics: []string{TacticImpact},
+		Description: "Adversaries flood the system with inputs that raise false detections, wasting analysts' time."},
+	{ID: "AML.T0048", Name: "External Harms", Tactics: []string{TacticImpact},
+		Description: "Adversaries abuse the AI system to cause financial, reputational, societal or user harm."},
+}
+
+var mitigations = []Mitigation{
+	{ID: "AML.M0000", Name: "Limit Public Release of Information",
+		Description: "Limit the public release of technical information about the AI stack used in the organization's products and services.",
+		Techniques:  []string{"AML.T0000", "AML.T0003", "AML.T0014"}},
+	{ID: "AML.M0001", Name: "Limit Model Artifact Release",
+		Description: "Limit the release of public models, datasets and other artifacts that help adversaries build proxy models.",
+		Techniques:  []string{"AML.T0002", "AML.T0005"}},
+	{ID: "AML.M0002", Name: "Passive AI Output Obfuscation",
+		Description: "Decrease the fidelity of model outputs, for example by returning labels rather than confidence scores.",
+		Techniques:  []string{"AML.T0013", "AML.T0024", "AML.T0042"}},
+	{ID: "AML.M0003", Name: "Model Hardening",
+		Description: "Use techniques such as adversarial training to make models robust to adversarial inputs.",
+		Techniques:  []string{"AML.T0015", "AML.T0031", "AML.T0043"}},
+	{ID: "AML.M0004", Name: "Restrict Number of AI Model Queries",
+		Description: "Limit the total number and rate of queries a user can perform.",
+		Techniques:  []string{"AML.T0024", "AML.T0029", "AML.T0034", "AML.T0040", "AML.T0046"}},
+	{ID: "AML.M0005", Name: "Control Access to AI Models and Data at Rest",
+		Description: "Establish access controls on internal model registries and limit access to production models and data.",
+		Techniques:  []string{"AML.T0007", "AML.T0018", "AML.T0020", "AML.T0025", "AML.T0035", "AML.T0044"}},
+	{ID: "AML.M0007", Name: "Sanitize Training Data",
+		Description: "Detect and remove or remediate poisoned training data before it is used to train or fine-tune a model.",
+		Techniques:  []string{"AML.T0010.002", "AML.T0019", "AML.T0020"}},
+	{ID: "AML.M0008", Name: "Validate AI Model",
+		Description: "Validate that models perform as intended, including checks for backdoors and degraded performance.",
+		Techniques:  []string{"AML.T0010.003", "AML.T0018", "AML.T0031"}},
+	{ID: "AML.M0011", Name: "Restrict Library Loading",
+		Description: "Prevent loading untrusted code, such as libraries embedded in serialized model files.",
+		Techniques:  []string{"AML.T0011"}},
+	{ID: "AML.M0012", Name: "Encrypt Sensitive Information",
+		Description: "Encrypt models and sensitive data at rest and in transit.",
+		Techniques:  []string{"AML.T0025", "AML.T0035"}},
+	{ID: "AML.M0013", Name: "Code Signing",
+		Description: "Enforce signing of software, models and other artifacts before they are loaded.",
+		Techniques:  []string{"AML.T0010", "AML.T0011"}},
+	{ID: "AML.M0014", Name: "Verify AI Artifacts",
+		Description: "Verify the cryptographic checksums of all AI artifacts against trusted values.",
+		Techniques:  []string{"AML.T0010", "AML.T0011", "AML.T0019"}},
+	{ID: "AML.M0015", Name: "Adversarial Input Detection",
+		Description: "Detect and block adversarial inputs or atypical queries before they reach the model.",
+		Techniques:  []string{"AML.T0015", "AML.T0024", "AML.T0029", "AML.T0043", "AML.T0046"}},
+	{ID: "AML.M0016", Name: "Vulnerability Scanning",
+		Description: "Scan for vulnerabilities in AI software, model files and dependencies.",
+		Techniques:  []string{"AML.T0010.001", "AML.T0011"}},
+	{ID: "AML.M0018", Name: "User Training",
+		Description: "Educate users about adversary tradecraft, such as phishing and malicious model files.",
+		Techniques:  []string{"AML.T0011", "AML.T0052"}},
+	{ID: "AML.M0019", Name: "Control Access to AI Models and Data in Production",
+		Description: "Require authentication and authorization for inference APIs and production data.",
+		Techniques:  []string{"AML.T0012", "AML.T0036", "AML.T0040", "AML.T0047"}},
+	{ID: "AML.M0020", Name: "Generative AI Guardrails",
+		Description: "Filter LLM inputs and outputs and constrain the actions an LLM or agent may take.",
+		Techniques:  []string{"AML.T0048", "AML.T0050", "AML.T0051", "AML.T0053", "AML.T0054", "AML.T0056", "AML.T0057", "AML.T0061", "AML.T0070"}},
+	{ID: "AML.M0021", Name: "Generative AI Guidelines",
+		Description: "Give the model guidelines, for example in its system prompt, on how to treat untrusted input and what it must not do.",
+		Techniques:  []string{"AML.T0051", "AML.T0054", "AML.T0056", "AML.T0057"}},
+	{ID: "AML.M0022", Name: "Generative AI Model Alignment",
+		Description: "Align models with safety requirements through fine-tuning and reinforcement learning from feedback.",
+		Techniques:  []string{"AML.T0048", "AML.T0051", "AML.T0054"}},
+	{ID: "AML.M0023", Name: "AI Bill of Materials",
+		Description: "Maintain an inventory of the models, datasets and software that make up each AI system.",
+		Techniques:  []string{"AML.T0010", "AML.T0011.001"}},
+	{ID: "AML.M0024", Name: "AI Telemetry Logging",
+		Description: "Log model inputs, outputs and agent actions so attacks can be detected and investigated.",
+		Techniques:  []string{"AML.T0024", "AML.T0034", "AML.T0040", "AML.T0051", "AML.T0053", "AML.T0055"}},
+	{ID: "AML.M0025", Name: "Maintain AI Dataset Provenance",
+		Description: "Track the sources and transformations of datasets used to train and augment models.",
+		Techniques:  []string{"AML.T0010.002", "AML.T0019", "AML.T0020", "AML.T0070"}},
+}
@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// Anchor is a chain head recorded outside the log.
+type Anchor struct {
+	Seq        uint64    `json:"seq"`
+	Hash       string    `json:"hash"`
+	AnchoredAt time.Time `json:"anchored_at"`
+}
+
+// Anchorer records chain heads somewhere the log's writer cannot rewrite,
+// such as write-once object storage or an RFC 3161 timestamping service.
+type Anchorer interface {
+	Anchor(ctx context.Context, a Anchor) error
+}
+
+// StorageAnchorer writes each anchor as an object under a prefix. Pair it
+// with a bucket that has object lock or versioning enabled.
+type StorageAnchorer struct {
+	provider storage.Provider
+	prefix   string
+}
+
+// NewStorageAnchorer creates an anchorer writing to provider under prefix.
+func NewStorageAnchorer(provider storage.Provider, prefix string) *StorageAnchorer {
+	return &StorageAnchorer{provider: provider, prefix: strings.Trim(prefix, "/")}
+}
+
+func (s *StorageAnchorer) key(seq uint64) string {
+	return path.Join(s.prefix, fmt.Sprintf("%020d.json", seq))
+}
+
+// Anchor implements Anchorer.
+func (s *StorageAnchorer) Anchor(ctx context.Context, a Anchor) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.provider.Upload(ctx, s.key(a.Seq), bytes.NewReader(data), "application/json")
+}
+
+// Anchors returns every stored anchor in sequence order.
+func (s *StorageAnchorer) Anchors(ctx context.Context) ([]Anchor, error) {
+	prefix := s.prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	objects, err := s.provider.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing anchors: %w", err)
+	}
+	anchors := make([]Anchor, 0, len(objects))
+	for _, obj := range objects {
+		if !strings.HasSuffix(obj.Key, ".json") {
+			continue
+		}
+		rc, err := s.provider.Download(ctx, obj.Key)
+		if err != nil {
+			return nil, fmt.Errorf("reading anchor %s: %w", obj.Key, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, 64<<10))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading anchor %s: %w", obj.Key, err)
+		}
+		var a Anchor
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, fmt.Errorf("decoding anchor %s: %w", obj.Key, err)
+		}
+		anchors = append(anchors, a)
+	}
+	sort.Slice(anchors, func(i, j int) bool { return anchors[i].Seq < anchors[j].Seq })
+	return anchors, nil
+}
+
+// RunAnchoring anchors the chain head every interval while it advances,
+// until ctx is done. A final anchor is attempted on shutdown.
+func (l *Log) RunAnchoring(ctx context.Context, a Anchorer, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last Head
+	anchor := func(ctx context.Context) {
+		head := l.Head()
+		if head.Seq == 0 || head == last {
+			return
+		}
+		if err := a.Anchor(ctx, Anchor{Seq: head.Seq, Hash: head.Hash, AnchoredAt: time.Now().UTC()}); err != nil {
+			log.Error().Err(err).Uint64("seq", head.Seq).Msg("failed to anchor audit log head")
+			return
+		}
+		last = head
+		log.Debug().Uint64("seq", head.Seq).Str("hash", head.Hash).Msg("anchored audit log head")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			shutdown, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			anchor(shutdown)
+			cancel()
+			return
+		case <-ticker.C:
+			anchor(ctx)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/storage"
+)
+
+func writeLog(t *testing.T, n int) (string, *audit.Log) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	l, err := audit.Open(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if _, err := l.Append(audit.KindDecision, "acme", "agent-1", map[string]any{"tool": "search", "allow": i%2 == 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, l
+}
+
+func verify(t *testing.T, data []byte, anchors []audit.Anchor) *audit.Report {
+	t.Helper()
+	rep, err := audit.Verify(bytes.NewReader(data), anchors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rep
+}
+
+func kinds(rep *audit.Report) []string {
+	var out []string
+	for _, p := range rep.Problems {
+		out = append(out, p.Kind)
+	}
+	return out
+}
+
+func TestLogResumesChain(t *testing.T) {
+	path, l := writeLog(t, 3)
+	head := l.Head()
+	l.Close()
+
+	l, err := audit.Open(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Head() != head {
+		t.Fatalf("reopened head = %+v, want %+v", l.Head(), head)
+	}
+	e, err := l.Append(audit.KindResponseAction, "acme", "oncall", map[string]string{"action": "suspend_agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if e.Seq != 4 || e.PrevHash != head.Hash {
+		t.Errorf("appended seq %d prev %s, want 4 after %s", e.Seq, e.PrevHash, head.Hash)
+	}
+
+	data, _ := os.ReadFile(path)
+	if rep := verify(t, data, []audit.Anchor{{Seq: 3, Hash: head.Hash}}); !rep.OK() || rep.Entries != 4 {
+		t.Errorf("clean log: entries %d, problems %+v", rep.Entries, rep.Problems)
+	}
+
+	// A torn final write must be repaired before appending.
+	if err := os.WriteFile(path, append(data, `{"seq":5`...), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := audit.Open(path, false); err == nil {
+		t.Error("Open accepted a log ending in a partial entry")
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	path, l := writeLog(t, 5)
+	l.Close()
+	data, _ := os.ReadFile(path)
+	lines := strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	t.Run("modified", func(t *testing.T) {
+		edited := append([]string(nil), lines...)
+		edited[2] = strings.Replace(edited[2], `"allow":true`, `"allow":false`, 1)
+		rep := verify(t, []byte(strings.Join(edited, "")), nil)
+		if got := kinds(rep); len(got) != 1 || got[0] != audit.ProblemModified {
+			t.Errorf("problems = %v, want one %s", got, audit.ProblemModified)
+		}
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		removed := append(append([]string(nil), lines[:1]...), lines[2:]...)
+		rep := verify(t, []byte(strings.Join(removed, "")), nil)
+		got := kinds(rep)
+		if len(got) != 2 || got[0] != audit.ProblemGap || got[1] != audit.ProblemChainBreak {
+			t.Errorf("problems = %v, want gap and chain_break", got)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		rep := verify(t, []byte(strings.Join(lines[:3], "")), nil)
+		if !rep.OK() {
+			t.Fatalf("prefix of a valid log should verify without anchors: %+v", rep.Problems)
+		}
+		full := verify(t, data, nil)
+		rep = verify(t, []byte(strings.Join(lines[:3], "")), []audit.Anchor{{Seq: full.Head.Seq, Hash: full.Head.Hash}})
+		if got := kinds(rep); len(got) != 1 || got[0] != audit.ProblemTruncated {
+			t.Errorf("problems = %v, want one %s", got, audit.ProblemTruncated)
+		}
+	})
+}
+
+func TestComputeHashSeparatesFields(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := audit.Entry{Seq: 1, Time: at, Kind: audit.KindDecision, OrgID: "a\nb", PrevHash: audit.GenesisHash}
+	b := audit.Entry{Seq: 1, Time: at, Kind: audit.KindDecision, OrgID: "a", Actor: "b\n", PrevHash: audit.GenesisHash}
+	if a.ComputeHash() == b.ComputeHash() {
+		t.Error("entries differing only in where a newline splits org and actor hash alike")
+	}
+	c := audit.Entry{Seq: 1, Time: at, Kind: audit.KindDecision, Actor: "x", Data: []byte(`{}`), PrevHash: audit.GenesisHash}
+	d := audit.Entry{Seq: 1, Time: at, Kind: audit.KindDecision, Actor: "x{}", PrevHash: audit.GenesisHash}
+	if c.ComputeHash() == d.ComputeHash() {
+		t.Error("data shifted into the actor hashes alike")
+	}
+}
+
+func TestRunAnchoring(t *testing.T) {
+	p, err := storage.NewLocalProvider(storage.LocalConfig{Root: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchorer := audit.NewStorageAnchorer(p, "anchors")
+	_, l := writeLog(t, 2)
+	defer l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		l.RunAnchoring(ctx, anchorer, time.Hour)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	anchors, err := anchorer.Anchors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anchors) != 1 || anchors[0].Seq != 2 || anchors[0].Hash != l.Head().Hash {
+		t.Errorf("anchors = %+v, want final head %+v", anchors, l.Head())
+	}
+}
@@ -0,0 +1,204 @@
+// Package audit keeps a tamper-evident log of policy decisions and response
+// actions. Entries are appended to a JSON Lines file and hash-chained: each
+// entry's hash covers its content and the previous entry's hash, so editing,
+// removing or reordering any entry breaks every later link. The chain head is
+// periodically anchored outside the log (object storage or a timestamping
+// service) so truncating the tail is detectable too.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first entry.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Entry kinds.
+const (
+	KindDecision       = "decision"
+	KindResponseAction = "response_action"
+	KindErasure        = "erasure"
+	KindReidentify     = "reidentification"
+)
+
+// Entry is one audit log record.
+type Entry struct {
+	Seq      uint64          `json:"seq"`
+	Time     time.Time       `json:"time"`
+	Kind     string          `json:"kind"`
+	OrgID    string          `json:"org_id,omitempty"`
+	Actor    string          `json:"actor,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+}
+
+// ComputeHash returns the hash the entry should carry. Data is compacted so
+// the hash does not depend on whitespace. Every field is length-prefixed,
+// so no content of one field, such as a newline in a client-supplied
+// organization ID, can pass for a field boundary.
+func (e *Entry) ComputeHash() string {
+	var data bytes.Buffer
+	if len(e.Data) > 0 {
+		if err := json.Compact(&data, e.Data); err != nil {
+			data.Reset()
+			data.Write(e.Data)
+		}
+	}
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte(e.PrevHash),
+		[]byte(strconv.FormatUint(e.Seq, 10)),
+		[]byte(e.Time.UTC().Format(time.RFC3339Nano)),
+		[]byte(e.Kind),
+		[]byte(e.OrgID),
+		[]byte(e.Actor),
+		data.Bytes(),
+	} {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(field)))
+		h.Write(n[:])
+		h.Write(field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Head identifies the latest entry of a chain.
+type Head struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// Log is an append-only, hash-chained audit log file. It is safe for
+// concurrent use.
+type Log struct {
+	path  string
+	fsync bool
+
+	mu   sync.Mutex
+	f    *os.File
+	head Head
+}
+
+// Open opens or creates the log at path and resumes the chain from its last
+// entry. With fsync set, every append is flushed to stable storage.
+func Open(path string, fsync bool) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+	head, err := lastHead(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	return &Log{path: path, fsync: fsync, f: f, head: head}, nil
+}
+
+// lastHead reads the head of an existing log. A final line without a
+// newline means a torn write; the log must be inspected before appending.
+func lastHead(path string) (Head, error) {
+	head := Head{Hash: GenesisHash}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return head, nil
+	}
+	if err != nil {
+		return head, fmt.Errorf("reading audit log: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var last []byte
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				return head, fmt.Errorf("audit log %s ends with a partial entry; verify and repair it before appending", path)
+			}
+			break
+		}
+		if err != nil {
+			return head, fmt.Errorf("reading audit log: %w", err)
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			last = line
+		}
+	}
+	if last == nil {
+		return head, nil
+	}
+	var e Entry
+	if err := json.Unmarshal(last, &e); err != nil {
+		return head, fmt.Errorf("decoding last audit entry: %w", err)
+	}
+	return Head{Seq: e.Seq, Hash: e.Hash}, nil
+}
+
+// Append adds an entry with data encoded as JSON and returns it.
+func (l *Log) Append(kind, orgID, actor string, data any) (*Entry, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit data: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := &Entry{
+		Seq:      l.head.Seq + 1,
+		Time:     time.Now().UTC(),
+		Kind:     kind,
+		OrgID:    orgID,
+		Actor:    actor,
+		Data:     raw,
+		PrevHash: l.head.Hash,
+	}
+	e.Hash = e.ComputeHash()
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit entry: %w", err)
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("writing audit entry: %w", err)
+	}
+	if l.fsync {
+		if err := l.f.Sync(); err != nil {
+			return nil, fmt.Errorf("syncing audit log: %w", err)
+		}
+	}
+	l.head = Head{Seq: e.Seq, Hash: e.Hash}
+	return e, nil
+}
+
+// Head returns the latest entry's sequence number and hash.
+func (l *Log) Head() Head {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.head
+}
+
+// Path returns the log file path.
+func (l *Log) Path() string { return l.path }
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
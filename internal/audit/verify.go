@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Problem kinds reported by Verify.
+const (
+	ProblemMalformed  = "malformed"   // line is not a valid entry
+	ProblemModified   = "modified"    // entry content does not match its hash
+	ProblemChainBreak = "chain_break" // prev_hash does not match the previous entry
+	ProblemGap        = "gap"         // sequence numbers skip or repeat
+	ProblemAnchor     = "anchor"      // anchored hash differs from the log
+	ProblemTruncated  = "truncated"   // log ends before an anchored entry
+)
+
+// maxProblems bounds the problems listed in a report.
+const maxProblems = 1000
+
+// Problem is one integrity failure.
+type Problem struct {
+	Line   int    `json:"line,omitempty"`
+	Seq    uint64 `json:"seq,omitempty"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// Report is the result of verifying a log.
+type Report struct {
+	Entries        int       `json:"entries"`
+	Head           Head      `json:"head"`
+	AnchorsChecked int       `json:"anchors_checked"`
+	Problems       []Problem `json:"problems"`
+	// Truncated reports that more problems were found than are listed.
+	Truncated bool `json:"problems_truncated,omitempty"`
+}
+
+// OK reports whether the log verified cleanly.
+func (r *Report) OK() bool { return len(r.Problems) == 0 }
+
+func (r *Report) add(p Problem) {
+	if len(r.Problems) >= maxProblems {
+		r.Truncated = true
+		return
+	}
+	r.Problems = append(r.Problems, p)
+}
+
+// Verify walks a log, recomputing every hash and link, and checks it against
+// externally recorded anchors. An error is returned only when r cannot be
+// read; integrity failures are listed in the report.
+func Verify(r io.Reader, anchors []Anchor) (*Report, error) {
+	anchored := make(map[uint64]string, len(anchors))
+	for _, a := range anchors {
+		anchored[a.Seq] = a.Hash
+	}
+	found := make(map[uint64]string, len(anchors))
+
+	rep := &Report{Problems: []Problem{}, Head: Head{Hash: GenesisHash}}
+	prev := Head{Hash: GenesisHash}
+	br := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("reading audit log: %w", err)
+		}
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			prev = rep.check(lineNo, trimmed, prev, anchored, found)
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	rep.Head = prev
+
+	for _, a := range anchors {
+		hash, ok := found[a.Seq]
+		switch {
+		case !ok && a.Seq > prev.Seq:
+			rep.add(Problem{Seq: a.Seq, Kind: ProblemTruncated,
+				Detail: fmt.Sprintf("anchor at seq %d is beyond the last entry %d", a.Seq, prev.Seq)})
+		case !ok:
+			rep.add(Problem{Seq: a.Seq, Kind: ProblemAnchor,
+				Detail: fmt.Sprintf("anchored entry %d is missing from the log", a.Seq)})
+		case hash != a.Hash:
+			rep.add(Problem{Seq: a.Seq, Kind: ProblemAnchor,
+				Detail: fmt.Sprintf("entry %d hash %s differs from anchored %s", a.Seq, hash, a.Hash)})
+		}
+		rep.AnchorsChecked++
+	}
+	return rep, nil
+}
+
+// check verifies one line against the previous head and returns the new
+// head. After a failure the chain continues from the entry as written, so
+// one edit is reported once rather than for every later entry.
+func (r *Report) check(lineNo int, line []byte, prev Head, anchored, found map[uint64]string) Head {
+	var e Entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		r.add(Problem{Line: lineNo, Kind: ProblemMalformed, Detail: err.Error()})
+		return prev
+	}
+	r.Entries++
+
+	if e.Seq != prev.Seq+1 {
+		r.add(Problem{Line: lineNo, Seq: e.Seq, Kind: ProblemGap,
+			Detail: fmt.Sprintf("expected seq %d, found %d", prev.Seq+1, e.Seq)})
+	}
+	if e.PrevHash != prev.Hash {
+		r.add(Problem{Line: lineNo, Seq: e.Seq, Kind: ProblemChainBreak,
+			Detail: fmt.Sprintf("prev_hash %s does not match previous entry hash %s", e.PrevHash, prev.Hash)})
+	}
+	if want := e.ComputeHash(); e.Hash != want {
+		r.add(Problem{Line: lineNo, Seq: e.Seq, Kind: ProblemModified,
+			Detail: fmt.Sprintf("hash %s does not match content hash %s", e.Hash, want)})
+	}
+	if _, ok := anchored[e.Seq]; ok {
+		found[e.Seq] = e.Hash
+	}
+	return Head{Seq: e.Seq, Hash: e.Hash}
+}
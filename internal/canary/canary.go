@@ -0,0 +1,295 @@
+// Package canary plants unique marker strings in knowledge base documents
+// and finds them again in agent traffic. A canary token only reaches a tool
+// call or an agent output by being retrieved from the document it was
+// planted in, so its appearance there is evidence of exfiltration from that
+// knowledge base.
+package canary
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/vectordb"
+	"github.com/google/uuid"
+)
+
+// tokenPrefix starts every canary token so they can be found in free text
+// without scanning for each one.
+const tokenPrefix = "agc-"
+
+// tokenPattern matches canary tokens: the prefix and 24 hex characters.
+var tokenPattern = regexp.MustCompile(tokenPrefix + `[0-9a-f]{24}`)
+
+// MetadataKey is the vector store document metadata key carrying the ID of
+// the canary planted in the document.
+const MetadataKey = "agentguard_canary"
+
+var ErrNotFound = errors.New("canary not found")
+
+// Canary is a marker planted in one knowledge base document.
+type Canary struct {
+	ID    string `json:"id"`
+	OrgID string `json:"org_id"`
+	// Token is the marker string written into the document.
+	Token       string    `json:"token"`
+	VectorStore string    `json:"vector_store"`
+	DocumentID  string    `json:"document_id"`
+	Note        string    `json:"note,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewToken returns a fresh canary token.
+func NewToken() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating canary token: %w", err)
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
+
+// Mark returns content with the token appended as a reference line, which
+// survives chunking better than an inline marker.
+func Mark(content, token string) string {
+	return strings.TrimRight(content, "\n") + "\n\nRef: " + token + "\n"
+}
+
+// Registry holds issued canaries and finds their tokens in text. When given
+// a path it persists canaries there, so tokens planted before a restart are
+// still recognized. It is safe for concurrent use.
+type Registry struct {
+	path string
+
+	mu       sync.RWMutex
+	canaries map[string]Canary // by ID
+	tokens   map[string]string // token to ID
+}
+
+// NewRegistry creates a registry, loading canaries saved at path. An empty
+// path keeps canaries in memory only.
+func NewRegistry(path string) (*Registry, error) {
+	r := &Registry{path: path, canaries: make(map[string]Canary), tokens: make(map[string]string)}
+	if path == "" {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading canaries: %w", err)
+	}
+	var saved []Canary
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parsing canaries %s: %w", path, err)
+	}
+	for _, c := range saved {
+		r.canaries[c.ID] = c
+		r.tokens[c.Token] = c.ID
+	}
+	return r, nil
+}
+
+// Issue registers a new canary for a document and returns it with its
+// token. The caller writes the token into the document, e.g. with Mark.
+func (r *Registry) Issue(orgID, vectorStore, documentID, note string) (Canary, error) {
+	if vectorStore == "" || documentID == "" {
+		return Canary{}, errors.New("vector_store and document_id are required")
+	}
+	token, err := NewToken()
+	if err != nil {
+		return Canary{}, err
+	}
+	c := Canary{
+		ID:          uuid.NewString(),
+		OrgID:       orgID,
+		Token:       token,
+		VectorStore: vectorStore,
+		DocumentID:  documentID,
+		Note:        note,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.canaries[c.ID] = c
+	r.tokens[c.Token] = c.ID
+	if err := r.save(); err != nil {
+		delete(r.canaries, c.ID)
+		delete(r.tokens, c.Token)
+		return Canary{}, err
+	}
+	return c, nil
+}
+
+// Plant issues a canary for each document, marks the document's content
+// with it, and upserts the documents into the store. Documents must carry
+// whatever the provider needs to store them, such as embeddings. Canaries
+// are withdrawn if the upsert fails.
+func (r *Registry) Plant(ctx context.Context, store vectordb.Provider, orgID string, docs []vectordb.Document) ([]Canary, error) {
+	planted := make([]Canary, 0, len(docs))
+	marked := make([]vectordb.Document, len(docs))
+	for i, d := range docs {
+		c, err := r.Issue(orgID, store.Name(), d.ID, "")
+		if err != nil {
+			r.withdraw(planted)
+			return nil, err
+		}
+		planted = append(planted, c)
+		d.Content = Mark(d.Content, c.Token)
+		meta := make(map[string]string, len(d.Metadata)+1)
+		for k, v := range d.Metadata {
+			meta[k] = v
+		}
+		meta[MetadataKey] = c.ID
+		d.Metadata = meta
+		marked[i] = d
+	}
+	if err := store.Upsert(ctx, marked); err != nil {
+		r.withdraw(planted)
+		return nil, fmt.Errorf("planting canaries in %s: %w", store.Name(), err)
+	}
+	return planted, nil
+}
+
+func (r *Registry) withdraw(canaries []Canary) {
+	for _, c := range canaries {
+		_ = r.Delete(c.ID)
+	}
+}
+
+// Get returns a canary by ID.
+func (r *Registry) Get(id string) (Canary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.canaries[id]
+	if !ok {
+		return Canary{}, ErrNotFound
+	}
+	return c, nil
+}
+
+// List returns an organization's canaries, newest first.
+func (r *Registry) List(orgID string) []Canary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Canary
+	for _, c := range r.canaries {
+		if c.OrgID == orgID {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Delete retires a canary. Its token is no longer reported; remove it from
+// the document separately.
+func (r *Registry) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.canaries[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(r.canaries, id)
+	delete(r.tokens, c.Token)
+	if err := r.save(); err != nil {
+		r.canaries[id] = c
+		r.tokens[c.Token] = id
+		return err
+	}
+	return nil
+}
+
+// Find returns the organization's canaries whose tokens appear in text,
+// each once.
+func (r *Registry) Find(orgID, text string) []Canary {
+	if !strings.Contains(text, tokenPrefix) {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Canary
+	seen := make(map[string]bool)
+	for _, token := range tokenPattern.FindAllString(text, -1) {
+		id, ok := r.tokens[token]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if c := r.canaries[id]; c.OrgID == orgID {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// FindIn is Find over any JSON-encodable value, such as a tool payload.
+func (r *Registry) FindIn(orgID string, v any) []Canary {
+	if v == nil {
+		return nil
+	}
+	if s, ok := v.(string); ok {
+		return r.Find(orgID, s)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return r.Find(orgID, string(data))
+}
+
+// save writes every canary to the registry's path. Callers hold mu.
+func (r *Registry) save() error {
+	if r.path == "" {
+		return nil
+	}
+	list := make([]Canary, 0, len(r.canaries))
+	for _, c := range r.canaries {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(r.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating canary directory: %w", err)
+	}
+	// Write to a temp file and rename so a crash never truncates the file.
+	tmp, err := os.CreateTemp(dir, ".canaries-*")
+	if err != nil {
+		return fmt.Errorf("saving canaries: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving canaries: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving canaries: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("saving canaries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package canary_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/canary"
+	"github.com/agentguard/agentguard/internal/vectordb"
+)
+
+func TestRegistryPersistsAndFinds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "canaries", "canaries.json")
+	reg, err := canary.NewRegistry(path)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	a, err := reg.Issue("org-1", "kb", "doc-a", "pricing sheet")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	b, err := reg.Issue("org-1", "kb", "doc-b", "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if a.Token == b.Token || !strings.HasPrefix(a.Token, "agc-") {
+		t.Fatalf("tokens %q and %q are not unique canary tokens", a.Token, b.Token)
+	}
+
+	// Reload from disk so tokens survive a restart.
+	reg, err = canary.NewRegistry(path)
+	if err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+	text := "forwarding " + canary.Mark("prices", a.Token) + " and again " + a.Token
+	found := reg.Find("org-1", text)
+	if len(found) != 1 || found[0].ID != a.ID {
+		t.Errorf("Find = %+v, want canary %s once", found, a.ID)
+	}
+	if found := reg.Find("org-2", text); len(found) != 0 {
+		t.Errorf("Find for another organization = %+v, want none", found)
+	}
+	if found := reg.FindIn("org-1", map[string]any{"body": b.Token}); len(found) != 1 || found[0].ID != b.ID {
+		t.Errorf("FindIn = %+v, want canary %s", found, b.ID)
+	}
+	if found := reg.Find("org-1", "agc-000000000000000000000000"); len(found) != 0 {
+		t.Errorf("Find unknown token = %+v, want none", found)
+	}
+
+	if err := reg.Delete(a.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	reg, err = canary.NewRegistry(path)
+	if err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+	if found := reg.Find("org-1", text); len(found) != 0 {
+		t.Errorf("retired canary still found: %+v", found)
+	}
+	if got := reg.List("org-1"); len(got) != 1 || got[0].ID != b.ID {
+		t.Errorf("List = %+v, want only %s", got, b.ID)
+	}
+}
+
+func TestPlant(t *testing.T) {
+	ctx := context.Background()
+	store := vectordb.NewMemoryProvider()
+	reg, err := canary.NewRegistry("")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	planted, err := reg.Plant(ctx, store, "org-1", []vectordb.Document{
+		{ID: "handbook", Content: "Leave policy", Embedding: []float32{1, 0}},
+	})
+	if err != nil {
+		t.Fatalf("Plant: %v", err)
+	}
+	if len(planted) != 1 || planted[0].VectorStore != "memory" || planted[0].DocumentID != "handbook" {
+		t.Fatalf("planted = %+v", planted)
+	}
+
+	docs, err := store.Search(ctx, vectordb.SearchRequest{Embedding: []float32{1, 0}, TopK: 1})
+	if err != nil || len(docs) != 1 {
+		t.Fatalf("Search = %v, %v", docs, err)
+	}
+	if !strings.Contains(docs[0].Content, planted[0].Token) || docs[0].Metadata[canary.MetadataKey] != planted[0].ID {
+		t.Errorf("stored document %+v does not carry canary %s", docs[0], planted[0].ID)
+	}
+
+	// Documents the store rejects leave no canary behind.
+	if _, err := reg.Plant(ctx, store, "org-1", []vectordb.Document{{ID: "no-embedding"}}); err == nil {
+		t.Fatal("Plant without embedding succeeded")
+	}
+	if got := reg.List("org-1"); len(got) != 1 {
+		t.Errorf("List after failed plant = %+v, want only the first canary", got)
+	}
+}
@@ -18,6 +18,22 @@ type Config struct {
 	OTEL          OTELConfig          `mapstructure:"otel"`
 	Auth          AuthConfig          `mapstructure:"auth"`
 	Observability ObservabilityConfig `mapstructure:"observability"`
+	Controls      ControlsConfig      `mapstructure:"controls"`
+	Quotas        QuotaConfig         `mapstructure:"quotas"`
+	Jobs          JobsConfig          `mapstructure:"jobs"`
+	Response      ResponseConfig      `mapstructure:"response"`
+	Severity      SeverityConfig      `mapstructure:"severity"`
+	Profiles      ProfilesConfig      `mapstructure:"profiles"`
+	Groups        GroupsConfig        `mapstructure:"groups"`
+	Outputs       OutputsConfig       `mapstructure:"outputs"`
+	Canaries      CanariesConfig      `mapstructure:"canaries"`
+	Detection     DetectionConfig     `mapstructure:"detection"`
+	Honeypots     HoneypotsConfig     `mapstructure:"honeypots"`
+	Failure       FailureConfig       `mapstructure:"failure"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	Evidence      EvidenceConfig      `mapstructure:"evidence"`
+	Privacy       PrivacyConfig       `mapstructure:"privacy"`
+	SIEM          SIEMConfig          `mapstructure:"siem"`
 }
 
 // ServerConfig holds HTTP server configuration.
@@ -28,6 +44,33 @@ type ServerConfig struct {
 	WriteTimeout    int      `mapstructure:"write_timeout"`
 	ShutdownTimeout int      `mapstructure:"shutdown_timeout"`
 	CORSOrigins     []string `mapstructure:"cors_origins"`
+	// Dev runs the all-in-one development mode: in-memory storage seeded
+	// with demo data, no authentication, any CORS origin and the embedded
+	// console. Never enable it on a reachable server.
+	Dev bool `mapstructure:"dev"`
+
+	Compression  CompressionConfig  `mapstructure:"compression"`
+	LoadShedding LoadSheddingConfig `mapstructure:"load_shedding"`
+}
+
+// CompressionConfig holds HTTP response compression configuration.
+type CompressionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	MinSize int  `mapstructure:"min_size"` // bytes; smaller responses are sent uncompressed
+	Level   int  `mapstructure:"level"`    // 1 (fastest) to 9 (best); 0 uses the default level
+}
+
+// LoadSheddingConfig holds overload protection configuration. Requests are
+// shed lowest priority first as load approaches capacity; SDK decisions and
+// health probes are never shed.
+type LoadSheddingConfig struct {
+	Enabled          bool `mapstructure:"enabled"`
+	MaxInflight      int  `mapstructure:"max_inflight"`       // concurrent requests at full load; 0 ignores concurrency
+	SchedLatencyMs   int  `mapstructure:"sched_latency_ms"`   // p99 scheduling latency at full load; 0 ignores CPU pressure
+	SampleIntervalMs int  `mapstructure:"sample_interval_ms"` // scheduling latency sample interval
+	// Priorities overrides route priorities by path prefix, e.g.
+	// "/api/v1/observe": low. Classes: low, normal, high, critical.
+	Priorities map[string]string `mapstructure:"priorities"`
 }
 
 // DatabaseConfig holds PostgreSQL configuration.
@@ -39,6 +82,19 @@ type DatabaseConfig struct {
 	Database string `mapstructure:"database"`
 	SSLMode  string `mapstructure:"sslmode"`
 	MaxConns int    `mapstructure:"max_conns"`
+	// SlowQueryMs logs queries taking at least this many milliseconds. Zero disables.
+	SlowQueryMs int `mapstructure:"slow_query_ms"`
+	// Replicas are read-only hosts for catalog and trace reads. They share the
+	// primary's credentials; reads may lag the primary by replication delay.
+	Replicas []ReplicaConfig `mapstructure:"replicas"`
+	// ReplicaCheckInterval is the replica health probe interval in seconds.
+	ReplicaCheckInterval int `mapstructure:"replica_check_interval"`
+}
+
+// ReplicaConfig identifies a PostgreSQL read replica.
+type ReplicaConfig struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"` // defaults to the primary's port
 }
 
 // RedisConfig holds Redis configuration.
@@ -55,6 +111,27 @@ type OPAConfig struct {
 	BundleURL     string `mapstructure:"bundle_url"`
 	DecisionPath  string `mapstructure:"decision_path"`
 	EnableMetrics bool   `mapstructure:"enable_metrics"`
+	// DecisionBudgetMs bounds pre-invoke evaluation; slower decisions are
+	// answered from the decision cache or the fail mode default. Zero
+	// disables the budget.
+	DecisionBudgetMs  int `mapstructure:"decision_budget_ms"`
+	DecisionCacheTTL  int `mapstructure:"decision_cache_ttl"` // seconds
+	DecisionCacheSize int `mapstructure:"decision_cache_size"`
+
+	URLCategories URLCategoriesConfig `mapstructure:"url_categories"`
+}
+
+// URLCategoriesConfig publishes URL categories to the policy engine for the
+// egress policy on web-browsing tools.
+type URLCategoriesConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Sources are category feeds, http(s) URLs or file paths, each a JSON
+	// object mapping domains to categories. They extend the built-in list.
+	Sources    []string `mapstructure:"sources"`
+	RefreshSec int      `mapstructure:"refresh_sec"`
+	// Blocked lists the categories blocked for agents without their own
+	// data.policies.blocked_url_categories entry.
+	Blocked []string `mapstructure:"blocked"`
 }
 
 // OTELConfig holds OpenTelemetry configuration.
@@ -64,6 +141,35 @@ type OTELConfig struct {
 	ServiceName    string  `mapstructure:"service_name"`
 	ServiceVersion string  `mapstructure:"service_version"`
 	SamplingRate   float64 `mapstructure:"sampling_rate"`
+
+	// Datadog and NewRelic push metrics and security signals to hosted
+	// platforms, alongside or instead of the Prometheus endpoint.
+	Datadog           DatadogConfig  `mapstructure:"datadog"`
+	NewRelic          NewRelicConfig `mapstructure:"newrelic"`
+	ExportIntervalSec int            `mapstructure:"export_interval_sec"`
+}
+
+// DatadogConfig configures the Datadog exporter.
+type DatadogConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Mode is api (HTTP API, needs api_key) or dogstatsd (local agent).
+	Mode       string   `mapstructure:"mode"`
+	APIKey     string   `mapstructure:"api_key"` // or DD_API_KEY
+	Site       string   `mapstructure:"site"`
+	StatsdAddr string   `mapstructure:"statsd_addr"`
+	Prefix     string   `mapstructure:"prefix"`
+	Tags       []string `mapstructure:"tags"`
+}
+
+// NewRelicConfig configures the New Relic exporter. Signals are sent as
+// events only when account_id is set.
+type NewRelicConfig struct {
+	Enabled    bool              `mapstructure:"enabled"`
+	LicenseKey string            `mapstructure:"license_key"` // or NEW_RELIC_LICENSE_KEY
+	AccountID  string            `mapstructure:"account_id"`
+	Region     string            `mapstructure:"region"`
+	Prefix     string            `mapstructure:"prefix"`
+	Attributes map[string]string `mapstructure:"attributes"`
 }
 
 // AuthConfig holds authentication configuration.
@@ -75,12 +181,209 @@ type AuthConfig struct {
 	ClientSecret string   `mapstructure:"client_secret"`
 	AllowedRoles []string `mapstructure:"allowed_roles"`
 	BearerToken  string   `mapstructure:"bearer_token"`
+	// PreviousBearerToken is still accepted until
+	// PreviousBearerTokenExpiresAt (RFC 3339; never when empty), so
+	// AUTH_BEARER_TOKEN can be rotated without cutting off clients that
+	// have not picked up the new token.
+	PreviousBearerToken          string `mapstructure:"previous_bearer_token"`
+	PreviousBearerTokenExpiresAt string `mapstructure:"previous_bearer_token_expires_at"`
+	// APIKeys are further bearer tokens, each with an ID recorded with the
+	// requests it authenticates and an optional validity window.
+	APIKeys []APIKeyConfig `mapstructure:"api_keys"`
+	// Workload accepts platform workload identity tokens on SDK routes.
+	Workload WorkloadConfig `mapstructure:"workload"`
+	// SPIFFE accepts X.509-SVID client certificates on SDK routes.
+	SPIFFE SPIFFEConfig `mapstructure:"spiffe"`
+	// RequestSigning verifies HMAC-signed SDK hook requests.
+	RequestSigning RequestSigningConfig `mapstructure:"request_signing"`
+}
+
+// APIKeyConfig is a bearer token accepted by the API. Org is the
+// organization its requests act for, quotas.default_org when empty. Scopes
+// are the scopes it grants; when empty it gets every read and write scope
+// but no payload access or administration. NotBefore and ExpiresAt are
+// RFC 3339 timestamps bounding when it is accepted; either may be empty.
+type APIKeyConfig struct {
+	ID        string   `mapstructure:"id"`
+	Token     string   `mapstructure:"token"`
+	Org       string   `mapstructure:"org"`
+	Scopes    []string `mapstructure:"scopes"`
+	NotBefore string   `mapstructure:"not_before"`
+	ExpiresAt string   `mapstructure:"expires_at"`
+}
+
+// RequestSigningConfig verifies SDK hook requests signed with per-agent
+// keys, in addition to bearer or workload authentication. Keys are issued
+// and rotated through /api/v1/agents/:id/signing-keys. An agent with an
+// active key must sign every request; Required demands signatures from all
+// agents.
+type RequestSigningConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	Required bool `mapstructure:"required"`
+	// ToleranceSeconds is how far a request's timestamp may be from the
+	// server clock, in either direction.
+	ToleranceSeconds int `mapstructure:"tolerance_seconds"`
+	// RotationGraceSeconds is how long replaced keys stay valid after a
+	// rotation.
+	RotationGraceSeconds int `mapstructure:"rotation_grace_seconds"`
+}
+
+// SPIFFEConfig enables mTLS with SPIFFE identities. The server presents its
+// own SVID and requests, but does not require, client SVIDs so non-SDK
+// clients can keep using bearer tokens.
+type SPIFFEConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	TrustDomain string `mapstructure:"trust_domain"`
+	CertFile    string `mapstructure:"cert_file"`   // server SVID
+	KeyFile     string `mapstructure:"key_file"`    // server SVID key
+	BundleFile  string `mapstructure:"bundle_file"` // trust bundle PEM
+	// Bindings map SPIFFE ID patterns to agents; issuer is ignored and an
+	// empty agent_id uses the last path segment of the ID.
+	Bindings []WorkloadBindingConfig `mapstructure:"bindings"`
+}
+
+// WorkloadConfig configures OIDC workload identity (e.g. Kubernetes projected
+// service account tokens) for agents calling the SDK hooks.
+type WorkloadConfig struct {
+	Enabled  bool                    `mapstructure:"enabled"`
+	Issuers  []WorkloadIssuerConfig  `mapstructure:"issuers"`
+	Bindings []WorkloadBindingConfig `mapstructure:"bindings"`
+	// LeewaySeconds tolerates clock skew on token expiry.
+	LeewaySeconds int `mapstructure:"leeway_seconds"`
+}
+
+// WorkloadIssuerConfig is a trusted token issuer.
+type WorkloadIssuerConfig struct {
+	URL       string   `mapstructure:"url"`       // must equal the iss claim
+	JWKSURL   string   `mapstructure:"jwks_url"`  // discovered from the issuer when empty
+	JWKSFile  string   `mapstructure:"jwks_file"` // read keys from disk instead
+	Audiences []string `mapstructure:"audiences"`
+}
+
+// WorkloadBindingConfig maps token subjects to an agent.
+type WorkloadBindingConfig struct {
+	Issuer  string `mapstructure:"issuer"`   // empty matches any issuer
+	Subject string `mapstructure:"subject"`  // glob, e.g. system:serviceaccount:agents:*
+	AgentID string `mapstructure:"agent_id"` // empty uses the service account name
+	OrgID   string `mapstructure:"org_id"`
 }
 
 // ObservabilityConfig holds observability backend configuration.
 type ObservabilityConfig struct {
-	Langfuse   LangfuseConfig   `mapstructure:"langfuse"`
-	ClickHouse ClickHouseConfig `mapstructure:"clickhouse"`
+	Langfuse      LangfuseConfig     `mapstructure:"langfuse"`
+	ClickHouse    ClickHouseConfig   `mapstructure:"clickhouse"`
+	RetentionDays int                `mapstructure:"retention_days"` // trace and audit data retention
+	Ingest        IngestConfig       `mapstructure:"ingest"`
+	Prompts       PromptsConfig      `mapstructure:"prompts"`
+	Payloads      PayloadsConfig     `mapstructure:"payloads"`
+	Fingerprints  FingerprintsConfig `mapstructure:"fingerprints"`
+	// Pricing maps model names (case-insensitive) to token prices for cost
+	// estimates. Models without a price contribute no cost.
+	Pricing map[string]ModelPrice `mapstructure:"pricing"`
+}
+
+// ModelPrice is a model's price in USD per 1,000 tokens.
+type ModelPrice struct {
+	PromptPer1K     float64 `mapstructure:"prompt_per_1k"`
+	CompletionPer1K float64 `mapstructure:"completion_per_1k"`
+}
+
+// PayloadsConfig configures content-addressable storage of span payloads.
+type PayloadsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Provider is the blob store; only "local" is currently implemented.
+	Provider  string `mapstructure:"provider"`
+	LocalRoot string `mapstructure:"local_root"`
+	Prefix    string `mapstructure:"prefix"`
+	// ThresholdBytes is the encoded size at which tool inputs and outputs are
+	// stored; smaller payloads are kept only as a hash.
+	ThresholdBytes   int `mapstructure:"threshold_bytes"`
+	TTLHours         int `mapstructure:"ttl_hours"`
+	SweepIntervalMin int `mapstructure:"sweep_interval_min"`
+}
+
+// FingerprintsConfig configures per-session behavioral fingerprints, which
+// flag abrupt mid-session changes in prompt style, tool call cadence, or user.
+type FingerprintsConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	IdleMinutes int  `mapstructure:"idle_minutes"`
+	MaxSessions int  `mapstructure:"max_sessions"`
+	// Baseline is how many prompts and tool call intervals establish a
+	// session's fingerprint.
+	Baseline int                    `mapstructure:"baseline"`
+	Default  FingerprintSensitivity `mapstructure:"default"`
+	// RiskLevels overrides Default by agent risk level.
+	RiskLevels map[string]FingerprintSensitivity `mapstructure:"risk_levels"`
+}
+
+// FingerprintSensitivity sets how large a session change is flagged. Zero
+// disables a check.
+type FingerprintSensitivity struct {
+	MinStyleSimilarity float64 `mapstructure:"min_style_similarity"`
+	CadenceFactor      float64 `mapstructure:"cadence_factor"`
+	// Block denies the call that changed the session instead of only
+	// raising a signal.
+	Block bool `mapstructure:"block"`
+}
+
+// PromptsConfig configures prompt hash reuse tracking.
+type PromptsConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	WindowHours int  `mapstructure:"window_hours"`
+	MaxHashes   int  `mapstructure:"max_hashes"`
+	// A hash is flagged once any non-zero threshold is reached within the window.
+	DistinctUsers  int   `mapstructure:"distinct_users"`
+	DistinctAgents int   `mapstructure:"distinct_agents"`
+	Count          int64 `mapstructure:"count"`
+	// Blocked seeds the prompt blocklist at startup.
+	Blocked []BlockedPrompt `mapstructure:"blocked"`
+}
+
+// BlockedPrompt is a prompt hash blocked by configuration.
+type BlockedPrompt struct {
+	Hash   string `mapstructure:"hash"`
+	Reason string `mapstructure:"reason"`
+}
+
+// IngestConfig holds trace ingest pipeline configuration.
+type IngestConfig struct {
+	// DedupeWindow is how long, in seconds, ingested span IDs are remembered
+	// so resubmitted traces are not stored twice. Zero disables.
+	DedupeWindow     int `mapstructure:"dedupe_window"`
+	DedupeMaxEntries int `mapstructure:"dedupe_max_entries"`
+	// MaxClockSkewSec is how far ahead of server time, in seconds, trace
+	// timestamps may be before the trace is shifted back.
+	MaxClockSkewSec int `mapstructure:"max_clock_skew_sec"`
+	// MaxTraceAgeHours rejects traces that started longer ago. Zero disables.
+	MaxTraceAgeHours int `mapstructure:"max_trace_age_hours"`
+	// AttributeAllow and AttributeDeny are key patterns (e.g. "http.*") for
+	// span attributes. An empty allowlist allows all keys not denied; a nil
+	// denylist uses the built-in credential and prompt-content patterns.
+	AttributeAllow []string `mapstructure:"attribute_allow"`
+	AttributeDeny  []string `mapstructure:"attribute_deny"`
+	// MaxAttributeBytes and MaxSpanAttributeBytes cap attribute sizes.
+	MaxAttributeBytes     int `mapstructure:"max_attribute_bytes"`
+	MaxSpanAttributeBytes int `mapstructure:"max_span_attribute_bytes"`
+	// Hashing computes prompt and tool payload hashes server-side.
+	Hashing HashingConfig `mapstructure:"hashing"`
+	// BatchSize caps how many traces are written to storage together.
+	// QueueSize is how many traces may wait to be written; beyond it
+	// ingest answers 503 until the writer catches up.
+	BatchSize int `mapstructure:"batch_size"`
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// HashingConfig configures server-side salted hashing of prompts and tool
+// payloads.
+type HashingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Algorithm is sha256 (HMAC-SHA-256) or blake3 (keyed BLAKE3).
+	Algorithm string `mapstructure:"algorithm"`
+	// SaltSecret derives each organization's salt; set it from
+	// AGENTGUARD_OBSERVABILITY_INGEST_HASHING_SALT_SECRET. At least 16 bytes.
+	SaltSecret string `mapstructure:"salt_secret"`
+	// OrgSalts overrides the derived salt for specific organization IDs.
+	OrgSalts map[string]string `mapstructure:"org_salts"`
 }
 
 // LangfuseConfig holds Langfuse integration configuration.
@@ -93,13 +396,388 @@ type LangfuseConfig struct {
 
 // ClickHouseConfig holds ClickHouse configuration for time-series data.
 type ClickHouseConfig struct {
+	// Enabled stores ingested traces in ClickHouse and serves
+	// /observe/metrics from its rollups.
+	Enabled  bool   `mapstructure:"enabled"`
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
+	HTTPPort int    `mapstructure:"http_port"` // HTTP interface used by AgentGuard
+	Secure   bool   `mapstructure:"secure"`
 	Database string `mapstructure:"database"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 }
 
+// ControlsConfig holds control framework and gap analysis configuration.
+type ControlsConfig struct {
+	// DataDir holds framework definitions (frameworks/*.json) and full
+	// OSCAL catalogs (catalogs/<framework-id>.json) that override the
+	// embedded frameworks, and licensed copies of restricted catalogs
+	// (licensed/<framework-id>.yaml) whose text is not embedded.
+	DataDir string `mapstructure:"data_dir"`
+	// ScoringModelPath points to a JSON or YAML scoring model, or a Rego
+	// priority policy (.rego), overriding the default gap priority and
+	// effort scoring.
+	ScoringModelPath string `mapstructure:"scoring_model_path"`
+	// ProvidersPath points to a JSON file of common control providers whose
+	// controls systems can inherit.
+	ProvidersPath string `mapstructure:"providers_path"`
+	// Monitoring configures automated checks that verify control implementation.
+	Monitoring MonitoringConfig `mapstructure:"monitoring"`
+	// Suggest configures embedding-based crosswalk suggestions.
+	Suggest SuggestConfig `mapstructure:"suggest"`
+	// Attestations configures attestation campaign reminders.
+	Attestations AttestationsConfig `mapstructure:"attestations"`
+	// Dispositions configures re-review reminders for accepted and
+	// transferred gap risks.
+	Dispositions DispositionsConfig `mapstructure:"dispositions"`
+	// Schedule configures recurring gap analyses.
+	Schedule ScheduleConfig `mapstructure:"schedule"`
+}
+
+// ScheduleConfig re-runs gap analysis for Frameworks against each of
+// Organizations' tracked implementations at every time Cron (a five-field
+// cron expression, in UTC) matches. Organizations defaults to the quota
+// default organization. Gaps opened or closed since a framework's previous
+// analysis are POSTed as JSON to WebhookURL, signed with WebhookSecret, or
+// logged when it is empty.
+type ScheduleConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	Cron          string   `mapstructure:"cron"`
+	Frameworks    []string `mapstructure:"frameworks"`
+	Organizations []string `mapstructure:"organizations"`
+	WebhookURL    string   `mapstructure:"webhook_url"`
+	// WebhookSecret keys the webhook signature; set it from
+	// AGENTGUARD_CONTROLS_SCHEDULE_WEBHOOK_SECRET. At least 16 bytes.
+	WebhookSecret string `mapstructure:"webhook_secret"`
+}
+
+// AttestationsConfig configures reminders to control owners with pending
+// attestations. Each reminder is POSTed as JSON to ReminderWebhookURL,
+// signed with ReminderWebhookSecret, or logged when it is empty. Campaigns
+// are checked for due reminders every ReminderCheckIntervalMin minutes.
+type AttestationsConfig struct {
+	ReminderWebhookURL string `mapstructure:"reminder_webhook_url"`
+	// ReminderWebhookSecret keys the webhook signature; set it from
+	// AGENTGUARD_CONTROLS_ATTESTATIONS_REMINDER_WEBHOOK_SECRET. At least
+	// 16 bytes.
+	ReminderWebhookSecret    string `mapstructure:"reminder_webhook_secret"`
+	ReminderCheckIntervalMin int    `mapstructure:"reminder_check_interval_min"`
+}
+
+// DispositionsConfig configures reminders to approvers of accepted and
+// transferred gap risks that are due for re-review, about to expire or
+// expired. Each reminder is POSTed as JSON to ReminderWebhookURL, signed
+// with ReminderWebhookSecret, or logged when it is empty. Dispositions are
+// checked every ReminderCheckIntervalMin minutes.
+type DispositionsConfig struct {
+	ReminderWebhookURL string `mapstructure:"reminder_webhook_url"`
+	// ReminderWebhookSecret keys the webhook signature; set it from
+	// AGENTGUARD_CONTROLS_DISPOSITIONS_REMINDER_WEBHOOK_SECRET. At least
+	// 16 bytes.
+	ReminderWebhookSecret    string `mapstructure:"reminder_webhook_secret"`
+	ReminderCheckIntervalMin int    `mapstructure:"reminder_check_interval_min"`
+}
+
+// SuggestConfig selects the embedder behind crosswalk suggestions: "hash"
+// embeds locally by shared vocabulary, "openai" calls the OpenAI (or a
+// compatible) embeddings API.
+type SuggestConfig struct {
+	Embedder string `mapstructure:"embedder"`
+	Model    string `mapstructure:"model"`
+	APIKey   string `mapstructure:"api_key"`
+	BaseURL  string `mapstructure:"base_url"`
+}
+
+// MonitoringConfig holds continuous control monitoring configuration.
+type MonitoringConfig struct {
+	Enabled    bool              `mapstructure:"enabled"`
+	Interval   int               `mapstructure:"interval"` // seconds between check runs
+	HTTPChecks []HTTPCheckConfig `mapstructure:"http_checks"`
+}
+
+// HTTPCheckConfig defines a cloud or platform API check. The check passes when
+// a GET to URL returns ExpectStatus.
+type HTTPCheckConfig struct {
+	ID           string   `mapstructure:"id"`
+	Description  string   `mapstructure:"description"`
+	URL          string   `mapstructure:"url"`
+	ExpectStatus int      `mapstructure:"expect_status"`
+	Controls     []string `mapstructure:"controls"`
+}
+
+// QuotaConfig holds per-organization usage quotas for shared deployments.
+type QuotaConfig struct {
+	// OrgHeader names the request header carrying the caller's organization
+	// ID. The organization comes from the caller's credential, so the
+	// header is only checked against it, except in dev mode.
+	OrgHeader string `mapstructure:"org_header"`
+	// DefaultOrg is the organization of credentials that do not name one.
+	DefaultOrg    string                 `mapstructure:"default_org"`
+	Default       QuotaLimits            `mapstructure:"default"`
+	Organizations map[string]QuotaLimits `mapstructure:"organizations"`
+}
+
+// QuotaLimits defines daily limits for an organization. Zero means unlimited.
+type QuotaLimits struct {
+	RequestsPerDay int64   `mapstructure:"requests_per_day"`
+	IngestGBPerDay float64 `mapstructure:"ingest_gb_per_day"`
+}
+
+// JobsConfig holds async job worker pool configuration.
+type JobsConfig struct {
+	Workers   int `mapstructure:"workers"`
+	QueueSize int `mapstructure:"queue_size"`
+	Timeout   int `mapstructure:"timeout"`   // seconds per job
+	Retention int `mapstructure:"retention"` // seconds finished jobs are kept
+}
+
+// ResponseConfig holds automated response action configuration.
+type ResponseConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// DryRun records actions without applying them. Defaults to true so
+	// rules can be tuned before they contain real agents.
+	DryRun           bool   `mapstructure:"dry_run"`
+	CooldownSec      int    `mapstructure:"cooldown_sec"`
+	NotifyWebhookURL string `mapstructure:"notify_webhook_url"`
+	// NotifyWebhookSecret keys the notification webhook signature; set it
+	// from AGENTGUARD_RESPONSE_NOTIFY_WEBHOOK_SECRET. At least 16 bytes.
+	NotifyWebhookSecret string `mapstructure:"notify_webhook_secret"`
+	// QuarantineTools are the tools a quarantined agent may still call.
+	QuarantineTools []string       `mapstructure:"quarantine_tools"`
+	Rules           []ResponseRule `mapstructure:"rules"`
+}
+
+// ResponseRule maps signals to response actions.
+type ResponseRule struct {
+	ID          string   `mapstructure:"id"`
+	Name        string   `mapstructure:"name"`
+	SignalTypes []string `mapstructure:"signal_types"` // empty matches any type
+	MinSeverity string   `mapstructure:"min_severity"` // low, medium, high, critical
+	// Actions: suspend_agent, revoke_tools, zero_rate_limit, quarantine,
+	// suggest_quarantine, block_prompt, notify
+	Actions []string `mapstructure:"actions"`
+}
+
+// SeverityConfig re-scores signal severities by asset criticality. When
+// Rules is empty the built-in rules apply.
+type SeverityConfig struct {
+	Enabled bool                 `mapstructure:"enabled"`
+	Rules   []SeverityRuleConfig `mapstructure:"rules"`
+	// Assets describes agents by ID. It takes precedence over the
+	// environment and risk level agents report.
+	Assets map[string]AssetConfig `mapstructure:"assets"`
+}
+
+// SeverityRuleConfig adjusts the severity of signals on matching assets.
+// Empty lists match any value.
+type SeverityRuleConfig struct {
+	ID                  string   `mapstructure:"id"`
+	Environments        []string `mapstructure:"environments"`
+	RiskLevels          []string `mapstructure:"risk_levels"`
+	DataClassifications []string `mapstructure:"data_classifications"`
+	Adjust              int      `mapstructure:"adjust"`  // levels up (positive) or down (negative)
+	Floor               string   `mapstructure:"floor"`   // optional minimum severity
+	Ceiling             string   `mapstructure:"ceiling"` // optional maximum severity
+}
+
+// AssetConfig is the criticality context of one agent.
+type AssetConfig struct {
+	Environment         string   `mapstructure:"environment"`
+	RiskLevel           string   `mapstructure:"risk_level"`
+	DataClassifications []string `mapstructure:"data_classifications"`
+}
+
+// ProfilesConfig holds per-environment guardrail profiles. When Definitions
+// is empty the built-in development, staging, and production profiles apply.
+type ProfilesConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Fallback names the profile used for agents whose environment matches
+	// no profile.
+	Fallback    string          `mapstructure:"fallback"`
+	Definitions []ProfileConfig `mapstructure:"definitions"`
+}
+
+// ProfileConfig bundles guardrail strictness for one environment.
+type ProfileConfig struct {
+	Name               string   `mapstructure:"name"`
+	Environments       []string `mapstructure:"environments"`
+	FailMode           string   `mapstructure:"fail_mode"`   // open or closed; empty inherits failure settings
+	Enforcement        string   `mapstructure:"enforcement"` // enforce or monitor
+	TraceSampleRate    float64  `mapstructure:"trace_sample_rate"`
+	ApprovalCategories []string `mapstructure:"approval_categories"` // tool categories needing human approval
+	RequireSandbox     bool     `mapstructure:"require_sandbox"`     // code execution tools must declare a sandbox profile
+}
+
+// FailureConfig decides whether calls are allowed when no policy decision can
+// be made. Agent risk level overrides the environment profile, which
+// overrides the route, which overrides the default.
+type FailureConfig struct {
+	Default    string            `mapstructure:"default"`     // open or closed
+	Routes     map[string]string `mapstructure:"routes"`      // e.g. pre_invoke: closed
+	RiskLevels map[string]string `mapstructure:"risk_levels"` // e.g. low: open
+}
+
+// GroupsConfig lists agent group definitions, in the JSON format read by
+// `agentguard threat group`. When set, spawn_agent calls are allowed only
+// along the groups' delegations.
+type GroupsConfig struct {
+	Files []string `mapstructure:"files"`
+}
+
+// OutputsConfig holds the JSON Schemas that structured agent outputs
+// reported to the post-invoke hook are validated against.
+type OutputsConfig struct {
+	Enabled bool                 `mapstructure:"enabled"`
+	Schemas []OutputSchemaConfig `mapstructure:"schemas"`
+}
+
+// OutputSchemaConfig registers a JSON Schema for an agent's outputs from a
+// tool. Exactly one of Schema and File is set.
+type OutputSchemaConfig struct {
+	ID      string `mapstructure:"id"`
+	AgentID string `mapstructure:"agent_id"` // empty matches every agent
+	Tool    string `mapstructure:"tool"`     // empty matches every tool
+	Schema  string `mapstructure:"schema"`   // inline JSON Schema document
+	File    string `mapstructure:"file"`     // path to a JSON Schema document
+	Mode    string `mapstructure:"mode"`     // flag or block; defaults to flag
+	Strict  bool   `mapstructure:"strict"`   // reject undeclared object properties
+}
+
+// CanariesConfig configures knowledge base canary tokens. Issued canaries
+// are saved to Path so they are still recognized after a restart.
+type CanariesConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+}
+
+// DetectionConfig configures the detection rules run at ingest. Rules are
+// YAML files in RulesDir, polled for changes every ReloadIntervalSec.
+type DetectionConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	RulesDir          string `mapstructure:"rules_dir"`
+	ReloadIntervalSec int    `mapstructure:"reload_interval_sec"`
+}
+
+// HoneypotsConfig binds decoy tools to agents. Any attempt to call one
+// raises a critical honeypot_triggered signal for the response engine.
+type HoneypotsConfig struct {
+	Enabled bool                 `mapstructure:"enabled"`
+	Tools   []HoneypotToolConfig `mapstructure:"tools"`
+}
+
+// HoneypotToolConfig binds one decoy tool.
+type HoneypotToolConfig struct {
+	ID          string `mapstructure:"id"`
+	AgentID     string `mapstructure:"agent_id"` // empty binds every agent
+	Tool        string `mapstructure:"tool"`
+	Description string `mapstructure:"description"`
+}
+
+// AuditConfig configures the tamper-evident audit log of policy decisions
+// and response actions.
+type AuditConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	// FSync flushes every entry to stable storage before the call returns.
+	FSync  bool              `mapstructure:"fsync"`
+	Anchor AuditAnchorConfig `mapstructure:"anchor"`
+}
+
+// AuditAnchorConfig configures periodic anchoring of the audit chain head
+// outside the log. Point it at write-once storage.
+type AuditAnchorConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Provider is the object store; only "local" is currently implemented.
+	Provider    string `mapstructure:"provider"`
+	LocalRoot   string `mapstructure:"local_root"`
+	Prefix      string `mapstructure:"prefix"`
+	IntervalSec int    `mapstructure:"interval_sec"`
+}
+
+// EvidenceConfig configures compliance evidence bundle export.
+type EvidenceConfig struct {
+	// Provider is the object store; only "local" is currently implemented.
+	Provider  string `mapstructure:"provider"`
+	LocalRoot string `mapstructure:"local_root"`
+	Prefix    string `mapstructure:"prefix"`
+	// SigningKey is a PKCS #8 PEM Ed25519 private key.
+	SigningKey string `mapstructure:"signing_key"`
+	// PolicyDir is archived as the policies in force.
+	PolicyDir       string `mapstructure:"policy_dir"`
+	DecisionSamples int    `mapstructure:"decision_samples"`
+	// RetentionDays locks bundles against overwrite and deletion. Zero
+	// disables the lock.
+	RetentionDays int    `mapstructure:"retention_days"`
+	RetentionMode string `mapstructure:"retention_mode"` // governance or compliance
+}
+
+// PrivacyConfig configures personal data handling and data subject erasure.
+type PrivacyConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// PseudonymKey keys the HMAC that derives pseudonyms; set it from
+	// AGENTGUARD_PRIVACY_PSEUDONYM_KEY. At least 16 bytes.
+	PseudonymKey string `mapstructure:"pseudonym_key"`
+	// PersonalData lists the fields that hold personal data, e.g.
+	// user_id, session_id, payloads or attributes.<key>. Empty uses the
+	// defaults: user_id and session_id pseudonymized, payloads redacted.
+	PersonalData []PersonalDataField `mapstructure:"personal_data"`
+	// Pseudonymization replaces user and session IDs with pseudonyms at
+	// ingest.
+	Pseudonymization PseudonymizationConfig `mapstructure:"pseudonymization"`
+}
+
+// PseudonymizationConfig configures ingest pseudonymization and the identity
+// vault that maps pseudonyms back to identities for investigations.
+type PseudonymizationConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// KeyFile holds the pseudonymization key, at least 16 bytes. It is kept
+	// out of the main config so the key can be stored and rotated apart
+	// from it.
+	KeyFile string `mapstructure:"key_file"`
+	// VaultProvider and VaultRoot locate the identity vault; only "local"
+	// is supported.
+	VaultProvider string `mapstructure:"vault_provider"`
+	VaultRoot     string `mapstructure:"vault_root"`
+	VaultPrefix   string `mapstructure:"vault_prefix"`
+	// FlushIntervalSec is how often new mappings are written to the vault.
+	FlushIntervalSec int `mapstructure:"flush_interval_sec"`
+}
+
+// PersonalDataField marks one field as personal data.
+type PersonalDataField struct {
+	Field  string `mapstructure:"field"`
+	Action string `mapstructure:"action"` // pseudonymize or redact
+}
+
+// SIEMConfig configures forwarding of security events to a SIEM.
+type SIEMConfig struct {
+	Syslog SyslogConfig `mapstructure:"syslog"`
+}
+
+// SyslogConfig sends security signals and policy denials to a syslog
+// receiver as CEF events.
+type SyslogConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Network string `mapstructure:"network"` // tcp, tls or udp
+	Address string `mapstructure:"address"` // host:port
+	// Facility is a syslog facility name such as local4, auth or user.
+	Facility string `mapstructure:"facility"`
+	Format   string `mapstructure:"format"` // rfc5424 or rfc3164
+	Hostname string `mapstructure:"hostname"`
+	// CAFile verifies the receiver over tls instead of the system roots;
+	// CertFile and KeyFile present a client certificate.
+	CAFile     string `mapstructure:"ca_file"`
+	CertFile   string `mapstructure:"cert_file"`
+	KeyFile    string `mapstructure:"key_file"`
+	ServerName string `mapstructure:"server_name"`
+	// Fields maps event fields (time, signal_id, type, description,
+	// org_id, agent_id, trace_id, span_id, tool, policies, reasons,
+	// action, environment) to CEF extension keys; "" drops a field.
+	Fields    map[string]string `mapstructure:"fields"`
+	QueueSize int               `mapstructure:"queue_size"`
+}
+
 // Load reads configuration from file and environment.
 func Load(path string) (*Config, error) {
 	v := viper.New()
@@ -154,6 +832,12 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.write_timeout", 15)
 	v.SetDefault("server.shutdown_timeout", 30)
 	v.SetDefault("server.cors_origins", []string{"http://localhost:3000"})
+	v.SetDefault("server.compression.enabled", true)
+	v.SetDefault("server.compression.min_size", 1024)
+	v.SetDefault("server.load_shedding.enabled", true)
+	v.SetDefault("server.load_shedding.max_inflight", 1000)
+	v.SetDefault("server.load_shedding.sched_latency_ms", 50)
+	v.SetDefault("server.load_shedding.sample_interval_ms", 1000)
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -161,6 +845,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.database", "agentguard")
 	v.SetDefault("database.sslmode", "require")
 	v.SetDefault("database.max_conns", 25)
+	v.SetDefault("database.slow_query_ms", 500)
+	v.SetDefault("database.replica_check_interval", 15)
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -171,20 +857,161 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("opa.bundle_path", "./policies/bundle.tar.gz")
 	v.SetDefault("opa.decision_path", "agentguard/allow")
 	v.SetDefault("opa.enable_metrics", true)
+	v.SetDefault("opa.decision_budget_ms", 50)
+	v.SetDefault("opa.decision_cache_ttl", 300)
+	v.SetDefault("opa.decision_cache_size", 10000)
+	v.SetDefault("opa.url_categories.enabled", true)
+	v.SetDefault("opa.url_categories.refresh_sec", 3600)
+	v.SetDefault("opa.url_categories.blocked", []string{"webmail", "paste_sites", "file_sharing"})
 
 	// OTEL defaults
 	v.SetDefault("otel.enabled", true)
 	v.SetDefault("otel.service_name", "agentguard")
 	v.SetDefault("otel.sampling_rate", 1.0)
+	v.SetDefault("otel.export_interval_sec", 60)
+	v.SetDefault("otel.datadog.enabled", false)
+	v.SetDefault("otel.datadog.mode", "api")
+	v.SetDefault("otel.datadog.site", "datadoghq.com")
+	v.SetDefault("otel.datadog.statsd_addr", "127.0.0.1:8125")
+	v.SetDefault("otel.newrelic.enabled", false)
+	v.SetDefault("otel.newrelic.region", "us")
 
 	// Auth defaults
 	v.SetDefault("auth.provider", "none")
+	v.SetDefault("auth.workload.enabled", false)
+	v.SetDefault("auth.workload.leeway_seconds", 60)
+	v.SetDefault("auth.spiffe.enabled", false)
+	v.SetDefault("auth.request_signing.enabled", false)
+	v.SetDefault("auth.request_signing.required", false)
+	v.SetDefault("auth.request_signing.tolerance_seconds", 300)
+	v.SetDefault("auth.request_signing.rotation_grace_seconds", 86400)
 
 	// Observability defaults
 	v.SetDefault("observability.langfuse.enabled", false)
 	v.SetDefault("observability.clickhouse.host", "localhost")
 	v.SetDefault("observability.clickhouse.port", 9000)
+	v.SetDefault("observability.clickhouse.http_port", 8123)
+	v.SetDefault("observability.ingest.dedupe_window", 3600)
+	v.SetDefault("observability.ingest.dedupe_max_entries", 100000)
+	v.SetDefault("observability.ingest.max_clock_skew_sec", 60)
+	v.SetDefault("observability.ingest.max_trace_age_hours", 168)
+	v.SetDefault("observability.ingest.max_attribute_bytes", 4096)
+	v.SetDefault("observability.prompts.enabled", true)
+	v.SetDefault("observability.prompts.window_hours", 24)
+	v.SetDefault("observability.prompts.max_hashes", 100000)
+	v.SetDefault("observability.prompts.distinct_users", 5)
+	v.SetDefault("observability.prompts.distinct_agents", 5)
+	v.SetDefault("observability.ingest.max_span_attribute_bytes", 65536)
+	v.SetDefault("observability.ingest.batch_size", 200)
+	v.SetDefault("observability.ingest.queue_size", 2000)
+	v.SetDefault("observability.ingest.hashing.enabled", false)
+	v.SetDefault("observability.ingest.hashing.algorithm", "sha256")
+	v.SetDefault("observability.ingest.hashing.salt_secret", "")
+	v.SetDefault("observability.payloads.enabled", false)
+	v.SetDefault("observability.payloads.provider", "local")
+	v.SetDefault("observability.payloads.local_root", "data/payloads")
+	v.SetDefault("observability.payloads.prefix", "payloads")
+	v.SetDefault("observability.payloads.threshold_bytes", 16384)
+	v.SetDefault("observability.payloads.ttl_hours", 720)
+	v.SetDefault("observability.payloads.sweep_interval_min", 60)
 	v.SetDefault("observability.clickhouse.database", "agentguard")
+	v.SetDefault("observability.fingerprints.enabled", true)
+	v.SetDefault("observability.fingerprints.idle_minutes", 30)
+	v.SetDefault("observability.fingerprints.max_sessions", 10000)
+	v.SetDefault("observability.fingerprints.baseline", 3)
+	v.SetDefault("observability.fingerprints.default.min_style_similarity", 0.1)
+	v.SetDefault("observability.fingerprints.default.cadence_factor", 5.0)
+	v.SetDefault("observability.fingerprints.risk_levels", map[string]any{
+		"high":     map[string]any{"min_style_similarity": 0.15, "cadence_factor": 4.0},
+		"critical": map[string]any{"min_style_similarity": 0.2, "cadence_factor": 3.0},
+	})
+
+	// Quota defaults
+	v.SetDefault("quotas.org_header", "X-AgentGuard-Org")
+	v.SetDefault("quotas.default_org", "default")
+
+	// Jobs defaults
+	v.SetDefault("jobs.workers", 4)
+	v.SetDefault("jobs.queue_size", 100)
+	v.SetDefault("jobs.timeout", 1800)
+	v.SetDefault("jobs.retention", 86400)
+
+	// Response defaults
+	v.SetDefault("response.enabled", true)
+	v.SetDefault("response.dry_run", true)
+	v.SetDefault("response.cooldown_sec", 600)
+	v.SetDefault("response.notify_webhook_secret", "")
+	v.SetDefault("severity.enabled", true)
+
+	// Profile defaults
+	v.SetDefault("profiles.enabled", true)
+	v.SetDefault("profiles.fallback", "production")
+
+	// Output schema defaults
+	v.SetDefault("outputs.enabled", true)
+
+	// Canary defaults
+	v.SetDefault("canaries.enabled", true)
+	v.SetDefault("canaries.path", "data/canaries/canaries.json")
+	v.SetDefault("detection.enabled", true)
+	v.SetDefault("detection.rules_dir", "data/rules")
+	v.SetDefault("detection.reload_interval_sec", 10)
+
+	// Honeypot defaults
+	v.SetDefault("honeypots.enabled", true)
+
+	// Failure defaults: high-risk agents never fail open
+	v.SetDefault("failure.default", "closed")
+	v.SetDefault("failure.risk_levels", map[string]string{"high": "closed", "critical": "closed"})
+
+	// Audit log defaults
+	v.SetDefault("audit.enabled", false)
+	v.SetDefault("audit.path", "data/audit/audit.jsonl")
+	v.SetDefault("audit.fsync", true)
+	v.SetDefault("audit.anchor.enabled", false)
+	v.SetDefault("audit.anchor.provider", "local")
+	v.SetDefault("audit.anchor.local_root", "data/audit")
+	v.SetDefault("audit.anchor.prefix", "anchors")
+	v.SetDefault("audit.anchor.interval_sec", 300)
+
+	// Evidence export defaults
+	v.SetDefault("evidence.provider", "local")
+	v.SetDefault("evidence.local_root", "data/evidence")
+	v.SetDefault("evidence.prefix", "evidence")
+	v.SetDefault("evidence.policy_dir", "./policies")
+	v.SetDefault("evidence.decision_samples", 500)
+	v.SetDefault("evidence.retention_days", 0)
+	v.SetDefault("evidence.retention_mode", "compliance")
+
+	// Privacy defaults
+	v.SetDefault("privacy.enabled", false)
+	v.SetDefault("privacy.pseudonym_key", "")
+	v.SetDefault("privacy.pseudonymization.enabled", false)
+	v.SetDefault("privacy.pseudonymization.key_file", "")
+	v.SetDefault("privacy.pseudonymization.vault_provider", "local")
+	v.SetDefault("privacy.pseudonymization.vault_root", "data/identity-vault")
+	v.SetDefault("privacy.pseudonymization.vault_prefix", "identities")
+	v.SetDefault("privacy.pseudonymization.flush_interval_sec", 5)
+
+	// SIEM defaults
+	v.SetDefault("siem.syslog.enabled", false)
+	v.SetDefault("siem.syslog.network", "tcp")
+	v.SetDefault("siem.syslog.facility", "local4")
+	v.SetDefault("siem.syslog.format", "rfc5424")
+	v.SetDefault("siem.syslog.queue_size", 1024)
+
+	// Controls defaults
+	v.SetDefault("controls.data_dir", "")
+	v.SetDefault("controls.monitoring.enabled", true)
+	v.SetDefault("controls.monitoring.interval", 300)
+	v.SetDefault("controls.suggest.embedder", "hash")
+	v.SetDefault("controls.attestations.reminder_check_interval_min", 60)
+	v.SetDefault("controls.attestations.reminder_webhook_secret", "")
+	v.SetDefault("controls.dispositions.reminder_check_interval_min", 60)
+	v.SetDefault("controls.dispositions.reminder_webhook_secret", "")
+	v.SetDefault("controls.schedule.enabled", false)
+	v.SetDefault("controls.schedule.cron", "0 6 * * *")
+	v.SetDefault("controls.schedule.webhook_secret", "")
 }
 
 func bindEnvVars(v *viper.Viper) {
@@ -218,6 +1045,25 @@ func bindEnvVars(v *viper.Viper) {
 	if val := os.Getenv("AUTH_BEARER_TOKEN"); val != "" {
 		v.Set("auth.bearer_token", val)
 	}
+	if val := os.Getenv("AUTH_BEARER_TOKEN_PREVIOUS"); val != "" {
+		v.Set("auth.previous_bearer_token", val)
+	}
+	if val := os.Getenv("AUTH_BEARER_TOKEN_PREVIOUS_EXPIRES_AT"); val != "" {
+		v.Set("auth.previous_bearer_token_expires_at", val)
+	}
+
+	// Monitoring platform keys from env
+	if val := os.Getenv("DD_API_KEY"); val != "" {
+		v.Set("otel.datadog.api_key", val)
+	}
+	if val := os.Getenv("NEW_RELIC_LICENSE_KEY"); val != "" {
+		v.Set("otel.newrelic.license_key", val)
+	}
+
+	// Embeddings from env
+	if val := os.Getenv("OPENAI_API_KEY"); val != "" {
+		v.Set("controls.suggest.api_key", val)
+	}
 }
 
 // DSN returns the PostgreSQL connection string with the password redacted.
@@ -0,0 +1,260 @@
+package controls
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Traits describing an agent or system, from which applicability rules
+// decide which controls apply.
+const (
+	TraitTrainsModels          = "trains_models"
+	TraitHostsModels           = "hosts_models"
+	TraitUsesRetrieval         = "uses_retrieval"
+	TraitProcessesPersonalData = "processes_personal_data"
+	TraitUsesTools             = "uses_tools"
+	TraitExternalFacing        = "external_facing"
+)
+
+// KnownTraits lists the traits rules may refer to.
+var KnownTraits = []string{
+	TraitTrainsModels, TraitHostsModels, TraitUsesRetrieval,
+	TraitProcessesPersonalData, TraitUsesTools, TraitExternalFacing,
+}
+
+// ApplicabilityRule marks controls as not applicable to systems without
+// any of its Unless traits. A control matches when it has one of Layers
+// and its title mentions one of Keywords; an empty list matches every
+// control. Titles are matched rather than descriptions, which often list
+// many concerns in passing.
+type ApplicabilityRule struct {
+	ID       string   `json:"id"`
+	Reason   string   `json:"reason"`
+	Unless   []string `json:"unless"`
+	Layers   []string `json:"layers,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// Validate checks that the rule names known traits and is scoped by
+// layers or keywords.
+func (r *ApplicabilityRule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("applicability rule: id is required")
+	}
+	if len(r.Unless) == 0 {
+		return fmt.Errorf("applicability rule %s: unless is required", r.ID)
+	}
+	for _, t := range r.Unless {
+		if !isKnownTrait(t) {
+			return fmt.Errorf("applicability rule %s: unknown trait %q", r.ID, t)
+		}
+	}
+	if len(r.Layers) == 0 && len(r.Keywords) == 0 {
+		return fmt.Errorf("applicability rule %s: layers or keywords are required", r.ID)
+	}
+	return nil
+}
+
+func (r *ApplicabilityRule) matches(c models.Control) bool {
+	if len(r.Layers) > 0 && !anyOf(c.ApplicableLayers, r.Layers, strings.EqualFold) {
+		return false
+	}
+	if len(r.Keywords) > 0 && !mentions(c.Title, r.Keywords...) {
+		return false
+	}
+	return true
+}
+
+// DefaultApplicabilityRules tailor AI framework baselines to what an agent
+// actually does: training data and model development controls are skipped
+// for agents that only call hosted models, privacy controls for agents
+// without personal data, tool and plugin controls for agents without
+// tools, and societal impact controls for internal agents.
+var DefaultApplicabilityRules = []ApplicabilityRule{
+	{
+		ID:       "no-training-data",
+		Reason:   "the agent does not train or fine-tune models, so it has no training data",
+		Unless:   []string{TraitTrainsModels},
+		Layers:   []string{"data"},
+		Keywords: []string{"training", "lineage", "provenance", "dataset", "labeling", "annotation", "data quality", "data governance"},
+	},
+	{
+		ID:       "no-model-development",
+		Reason:   "the agent neither trains nor hosts its models",
+		Unless:   []string{TraitTrainsModels, TraitHostsModels},
+		Layers:   []string{"model"},
+		Keywords: []string{"training", "develop", "fine-tun", "retrain"},
+	},
+	{
+		ID:       "no-personal-data",
+		Reason:   "the agent does not process personal data",
+		Unless:   []string{TraitProcessesPersonalData},
+		Keywords: []string{"personal data", "personally identifiable", "pii", "privacy protection", "privacy impact", "data subject"},
+	},
+	{
+		ID:       "no-tools",
+		Reason:   "the agent has no tools",
+		Unless:   []string{TraitUsesTools},
+		Keywords: []string{"plugin", "tool", "agency"},
+	},
+	{
+		ID:       "internal-only",
+		Reason:   "the agent is not exposed to the public",
+		Unless:   []string{TraitExternalFacing},
+		Layers:   []string{"society"},
+		Keywords: []string{"communities", "society", "societal", "fundamental rights"},
+	},
+}
+
+// AgentTraits derives an agent's traits from its registration: capability
+// names and descriptions (training, fine-tuning, self-hosted models,
+// retrieval, customer-facing use), the data its capabilities access and
+// the tools it declares. Traits that cannot be derived are false.
+func AgentTraits(a *models.Agent) map[string]bool {
+	traits := make(map[string]bool, len(KnownTraits))
+	for _, t := range KnownTraits {
+		traits[t] = false
+	}
+	for _, capability := range a.Capabilities {
+		text := capability.Name + " " + capability.Description
+		if mentions(text, "train", "fine-tun", "fine tun") {
+			traits[TraitTrainsModels] = true
+		}
+		if mentions(text, "self-hosted", "self hosted", "model hosting", "model serving") {
+			traits[TraitHostsModels] = true
+		}
+		if mentions(text, "rag", "retriev", "vector", "knowledge base") {
+			traits[TraitUsesRetrieval] = true
+		}
+		if mentions(text, "customer-facing", "customer facing", "public", "external") {
+			traits[TraitExternalFacing] = true
+		}
+		if mentions(strings.Join(capability.DataAccess, " "), "pii", "personal", "phi", "customer", "user data") {
+			traits[TraitProcessesPersonalData] = true
+		}
+	}
+	for _, t := range a.Tools {
+		traits[TraitUsesTools] = true
+		if mentions(t.Category, "retrieval", "vector", "search") {
+			traits[TraitUsesRetrieval] = true
+		}
+	}
+	return traits
+}
+
+// Baseline is the controls of one framework tailored to a system.
+type Baseline struct {
+	Framework     string                 `json:"framework"`
+	FrameworkName string                 `json:"framework_name"`
+	Traits        map[string]bool        `json:"traits"`
+	Applicable    int                    `json:"applicable"`
+	NotApplicable int                    `json:"not_applicable"`
+	Controls      []ControlApplicability `json:"controls"`
+}
+
+// ControlApplicability is whether one control applies, and if not, why.
+type ControlApplicability struct {
+	ControlID  string   `json:"control_id"`
+	Title      string   `json:"title"`
+	Layers     []string `json:"layers,omitempty"`
+	Applicable bool     `json:"applicable"`
+	// Rules are the IDs of the rules that excluded the control.
+	Rules   []string `json:"rules,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ApplicableControls returns the IDs of the controls that apply.
+func (b *Baseline) ApplicableControls() []string {
+	ids := make([]string, 0, b.Applicable)
+	for _, c := range b.Controls {
+		if c.Applicable {
+			ids = append(ids, c.ControlID)
+		}
+	}
+	return ids
+}
+
+// Baseline tailors a framework's controls to a system's traits using
+// rules, or DefaultApplicabilityRules when rules is nil. A control is not
+// applicable when any rule matching it has none of its Unless traits set.
+func (g *GapAnalyzer) Baseline(framework string, traits map[string]bool, rules []ApplicabilityRule) (*Baseline, error) {
+	fw, ok := g.Framework(framework)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework: %s", framework)
+	}
+	list, ok := g.Controls(framework)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework: %s", framework)
+	}
+	if rules == nil {
+		rules = DefaultApplicabilityRules
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	b := &Baseline{
+		Framework:     fw.ID,
+		FrameworkName: fw.Name,
+		Traits:        traits,
+		Controls:      make([]ControlApplicability, 0, len(list)),
+	}
+	for _, c := range list {
+		ca := ControlApplicability{ControlID: c.ControlID, Title: c.Title, Layers: c.ApplicableLayers, Applicable: true}
+		for i := range rules {
+			r := &rules[i]
+			if !r.matches(c) || anyTrait(traits, r.Unless) {
+				continue
+			}
+			ca.Applicable = false
+			ca.Rules = append(ca.Rules, r.ID)
+			ca.Reasons = append(ca.Reasons, r.Reason)
+		}
+		if ca.Applicable {
+			b.Applicable++
+		} else {
+			b.NotApplicable++
+		}
+		b.Controls = append(b.Controls, ca)
+	}
+	sort.SliceStable(b.Controls, func(i, j int) bool { return b.Controls[i].ControlID < b.Controls[j].ControlID })
+	return b, nil
+}
+
+func anyTrait(traits map[string]bool, names []string) bool {
+	for _, n := range names {
+		if traits[n] {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownTrait(t string) bool {
+	for _, k := range KnownTraits {
+		if k == t {
+			return true
+		}
+	}
+	return false
+}
+
+// mentions reports whether text contains a word starting with one of
+// words, ignoring case; multi-word phrases must start at a word boundary.
+func mentions(text string, words ...string) bool {
+	text = " " + strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}), " ")
+	for _, w := range words {
+		if strings.Contains(text, " "+strings.ToLower(w)) {
+			return true
+		}
+	}
+	return false
+}
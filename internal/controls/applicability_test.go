@@ -0,0 +1,104 @@
+package controls_test
+
+import (
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestAgentTraits(t *testing.T) {
+	traits := controls.AgentTraits(&models.Agent{
+		Capabilities: []models.Capability{
+			{Name: "fine_tuning", Description: "Fine-tunes the support model nightly"},
+			{Name: "answer_questions", Description: "Answers customer-facing questions", DataAccess: []string{"customer_pii"}},
+		},
+		Tools: []models.ToolBinding{{Name: "kb", Category: "retrieval"}},
+	})
+	for trait, want := range map[string]bool{
+		controls.TraitTrainsModels:          true,
+		controls.TraitHostsModels:           false,
+		controls.TraitUsesRetrieval:         true,
+		controls.TraitProcessesPersonalData: true,
+		controls.TraitUsesTools:             true,
+		controls.TraitExternalFacing:        true,
+	} {
+		if traits[trait] != want {
+			t.Errorf("%s = %v, want %v", trait, traits[trait], want)
+		}
+	}
+	// "storage" does not mention RAG.
+	if controls.AgentTraits(&models.Agent{Capabilities: []models.Capability{{Name: "storage"}}})[controls.TraitUsesRetrieval] {
+		t.Error("storage capability counted as retrieval")
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applicable := func(b *controls.Baseline, id string) controls.ControlApplicability {
+		t.Helper()
+		for _, c := range b.Controls {
+			if c.ControlID == id {
+				return c
+			}
+		}
+		t.Fatalf("%s not in baseline", id)
+		return controls.ControlApplicability{}
+	}
+
+	// An internal agent without tools that calls a hosted model.
+	b, err := analyzer.Baseline("owasp-llm-top10", map[string]bool{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := applicable(b, "LLM06"); c.Applicable || len(c.Rules) != 1 || c.Rules[0] != "no-tools" || c.Reasons[0] == "" {
+		t.Errorf("LLM06 = %+v, want excluded by no-tools", c)
+	}
+	if c := applicable(b, "LLM01"); !c.Applicable {
+		t.Errorf("LLM01 = %+v, want applicable", c)
+	}
+	if b.Applicable+b.NotApplicable != len(b.Controls) || len(b.ApplicableControls()) != b.Applicable {
+		t.Errorf("counts = %d + %d for %d controls", b.Applicable, b.NotApplicable, len(b.Controls))
+	}
+
+	b, err = analyzer.Baseline("eu-ai-act", map[string]bool{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := applicable(b, "EUAIA-10"); c.Applicable {
+		t.Errorf("EUAIA-10 = %+v, want excluded without training data", c)
+	}
+	b, err = analyzer.Baseline("eu-ai-act", map[string]bool{controls.TraitTrainsModels: true, controls.TraitExternalFacing: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.NotApplicable != 0 {
+		t.Errorf("%d controls excluded for a public agent that trains models", b.NotApplicable)
+	}
+
+	// Custom rules replace the defaults.
+	rules := []controls.ApplicabilityRule{{ID: "no-retrieval", Unless: []string{controls.TraitUsesRetrieval}, Keywords: []string{"prompt injection"}}}
+	b, err = analyzer.Baseline("owasp-llm-top10", map[string]bool{}, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := applicable(b, "LLM06"); !c.Applicable || b.NotApplicable != 1 {
+		t.Errorf("custom rules: LLM06 = %+v, %d excluded", c, b.NotApplicable)
+	}
+
+	for _, bad := range []controls.ApplicabilityRule{
+		{ID: "x", Unless: []string{"flies"}, Layers: []string{"data"}},
+		{ID: "x", Unless: []string{controls.TraitUsesTools}},
+		{ID: "x", Layers: []string{"data"}},
+	} {
+		if _, err := analyzer.Baseline("owasp-llm-top10", nil, []controls.ApplicabilityRule{bad}); err == nil {
+			t.Errorf("rule %+v accepted", bad)
+		}
+	}
+	if _, err := analyzer.Baseline("no-such-framework", nil, nil); err == nil {
+		t.Error("expected an unknown framework error")
+	}
+}
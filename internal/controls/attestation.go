@@ -0,0 +1,315 @@
+package controls
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// NewAttestations assigns a campaign's controls to their owners: one
+// pending attestation per implemented or verified control implementation
+// in the campaign's framework, or in every framework when it names none.
+// Implementations without an owner go to defaultOwner; when that is empty
+// they are returned as unassigned instead.
+func NewAttestations(c *models.AttestationCampaign, impls []models.ControlImplementation, defaultOwner string) (attestations []models.Attestation, unassigned []models.ControlImplementation) {
+	for _, ci := range impls {
+		if ci.Status != models.ImplementationImplemented && ci.Status != models.ImplementationVerified {
+			continue
+		}
+		if c.FrameworkID != "" && ci.FrameworkID != c.FrameworkID {
+			continue
+		}
+		owner := ci.Owner
+		if owner == "" {
+			owner = defaultOwner
+		}
+		if owner == "" {
+			unassigned = append(unassigned, ci)
+			continue
+		}
+		attestations = append(attestations, models.Attestation{
+			ID:               uuid.NewString(),
+			CampaignID:       c.ID,
+			OrganizationID:   c.OrganizationID,
+			ImplementationID: ci.ID,
+			FrameworkID:      ci.FrameworkID,
+			ControlID:        ci.ControlID,
+			Owner:            owner,
+			Status:           models.AttestationPending,
+		})
+	}
+	return attestations, unassigned
+}
+
+// Reminder asks one owner to respond to their pending attestations in a
+// campaign.
+type Reminder struct {
+	OrganizationID string    `json:"organization_id"`
+	CampaignID     string    `json:"campaign_id"`
+	CampaignName   string    `json:"campaign_name"`
+	Owner          string    `json:"owner"`
+	DueDate        time.Time `json:"due_date"`
+	Overdue        bool      `json:"overdue"`
+	// Controls are framework:control IDs awaiting the owner's response.
+	Controls       []string `json:"controls"`
+	AttestationIDs []string `json:"attestation_ids"`
+}
+
+// DueReminders groups by owner the pending attestations of an open campaign
+// that were last reminded, or assigned, at least the campaign's reminder
+// interval before now. Reminders continue past the due date until the
+// campaign is closed.
+func DueReminders(c *models.AttestationCampaign, attestations []models.Attestation, now time.Time) []Reminder {
+	if c.Status != models.CampaignOpen || c.ReminderIntervalDays <= 0 {
+		return nil
+	}
+	interval := time.Duration(c.ReminderIntervalDays) * 24 * time.Hour
+	byOwner := make(map[string]*Reminder)
+	for _, a := range attestations {
+		if a.Status != models.AttestationPending {
+			continue
+		}
+		last := a.CreatedAt
+		if a.LastRemindedAt != nil {
+			last = *a.LastRemindedAt
+		}
+		if now.Sub(last) < interval {
+			continue
+		}
+		r, ok := byOwner[a.Owner]
+		if !ok {
+			r = &Reminder{
+				OrganizationID: c.OrganizationID,
+				CampaignID:     c.ID,
+				CampaignName:   c.Name,
+				Owner:          a.Owner,
+				DueDate:        c.DueDate,
+				Overdue:        campaignOverdue(c, now),
+			}
+			byOwner[a.Owner] = r
+		}
+		r.Controls = append(r.Controls, a.FrameworkID+":"+a.ControlID)
+		r.AttestationIDs = append(r.AttestationIDs, a.ID)
+	}
+	reminders := make([]Reminder, 0, len(byOwner))
+	for _, r := range byOwner {
+		reminders = append(reminders, *r)
+	}
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i].Owner < reminders[j].Owner })
+	return reminders
+}
+
+// campaignOverdue reports whether now is after the campaign's due date.
+// The due date is a calendar day and lasts until its end.
+func campaignOverdue(c *models.AttestationCampaign, now time.Time) bool {
+	return now.After(c.DueDate.AddDate(0, 0, 1))
+}
+
+// AttestationReport summarizes a campaign's progress for owners and
+// auditors.
+type AttestationReport struct {
+	Campaign models.AttestationCampaign `json:"campaign"`
+	Total    int                        `json:"total"`
+	Attested int                        `json:"attested"`
+	Declined int                        `json:"declined"`
+	Pending  int                        `json:"pending"`
+	// CompletionPercentage is the share of attestations with a response.
+	CompletionPercentage float64 `json:"completion_percentage"`
+	Overdue              bool    `json:"overdue"`
+	// Owners summarizes each owner's responses, most pending first.
+	Owners []OwnerAttestationSummary `json:"owners"`
+	// Declined lists the controls owners could not attest to, with their
+	// comments, for remediation.
+	DeclinedControls []models.Attestation `json:"declined_controls"`
+	// PendingControls lists the controls still awaiting a response.
+	PendingControls []models.Attestation `json:"pending_controls"`
+	GeneratedAt     time.Time            `json:"generated_at"`
+}
+
+// OwnerAttestationSummary counts one owner's responses in a campaign.
+type OwnerAttestationSummary struct {
+	Owner    string `json:"owner"`
+	Total    int    `json:"total"`
+	Attested int    `json:"attested"`
+	Declined int    `json:"declined"`
+	Pending  int    `json:"pending"`
+}
+
+// NewAttestationReport summarizes a campaign's attestations as of now.
+func NewAttestationReport(c *models.AttestationCampaign, attestations []models.Attestation, now time.Time) *AttestationReport {
+	r := &AttestationReport{
+		Campaign:         *c,
+		Total:            len(attestations),
+		Overdue:          c.Status == models.CampaignOpen && campaignOverdue(c, now),
+		DeclinedControls: []models.Attestation{},
+		PendingControls:  []models.Attestation{},
+		GeneratedAt:      now,
+	}
+	owners := make(map[string]*OwnerAttestationSummary)
+	for _, a := range attestations {
+		o, ok := owners[a.Owner]
+		if !ok {
+			o = &OwnerAttestationSummary{Owner: a.Owner}
+			owners[a.Owner] = o
+		}
+		o.Total++
+		switch a.Status {
+		case models.AttestationAttested:
+			r.Attested++
+			o.Attested++
+		case models.AttestationDeclined:
+			r.Declined++
+			o.Declined++
+			r.DeclinedControls = append(r.DeclinedControls, a)
+		default:
+			r.Pending++
+			o.Pending++
+			r.PendingControls = append(r.PendingControls, a)
+		}
+	}
+	if r.Total > 0 {
+		r.CompletionPercentage = float64(r.Attested+r.Declined) / float64(r.Total) * 100
+	}
+	r.Owners = make([]OwnerAttestationSummary, 0, len(owners))
+	for _, o := range owners {
+		r.Owners = append(r.Owners, *o)
+	}
+	sort.Slice(r.Owners, func(i, j int) bool {
+		if r.Owners[i].Pending != r.Owners[j].Pending {
+			return r.Owners[i].Pending > r.Owners[j].Pending
+		}
+		return r.Owners[i].Owner < r.Owners[j].Owner
+	})
+	return r
+}
+
+// Text renders the report for a terminal or an email.
+func (r *AttestationReport) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Attestation campaign: %s (%s)\n", r.Campaign.Name, r.Campaign.Status)
+	fmt.Fprintf(&b, "Due: %s", r.Campaign.DueDate.Format(time.DateOnly))
+	if r.Overdue {
+		b.WriteString(" (overdue)")
+	}
+	fmt.Fprintf(&b, "\nCompletion: %.1f%% (%d attested, %d declined, %d pending of %d)\n",
+		r.CompletionPercentage, r.Attested, r.Declined, r.Pending, r.Total)
+	if len(r.Owners) > 0 {
+		b.WriteString("\nOwners:\n")
+		for _, o := range r.Owners {
+			fmt.Fprintf(&b, "  %-32s %d attested, %d declined, %d pending\n", o.Owner, o.Attested, o.Declined, o.Pending)
+		}
+	}
+	if len(r.DeclinedControls) > 0 {
+		b.WriteString("\nDeclined:\n")
+		for _, a := range r.DeclinedControls {
+			fmt.Fprintf(&b, "  %s:%s (%s)", a.FrameworkID, a.ControlID, a.Owner)
+			if a.Comment != "" {
+				fmt.Fprintf(&b, ": %s", a.Comment)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// AttestationReminders sends due reminders for every open campaign on a
+// schedule and records them on the attestations.
+type AttestationReminders struct {
+	repo repository.AttestationRepository
+	// webhook receives a JSON POST per reminder. When nil, reminders are
+	// logged.
+	webhook *webhook.Sender
+	now     func() time.Time
+}
+
+// NewAttestationReminders creates a reminder scheduler. hook may be nil.
+func NewAttestationReminders(repo repository.AttestationRepository, hook *webhook.Sender) *AttestationReminders {
+	return &AttestationReminders{
+		repo:    repo,
+		webhook: hook,
+		now:     time.Now,
+	}
+}
+
+// Run sends due reminders every interval until ctx is cancelled.
+func (s *AttestationReminders) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SendDue(ctx); err != nil {
+				log.Error().Err(err).Msg("attestation reminders failed")
+			}
+		}
+	}
+}
+
+// SendDue sends the reminders due now and returns how many were sent. A
+// reminder that fails to send is retried on the next run.
+func (s *AttestationReminders) SendDue(ctx context.Context) (int, error) {
+	campaigns, err := s.repo.ListOpenCampaigns(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing open campaigns: %w", err)
+	}
+	now := s.now().UTC()
+	sent := 0
+	for i := range campaigns {
+		c := &campaigns[i]
+		attestations, err := s.repo.ListAttestations(ctx, c.ID)
+		if err != nil {
+			return sent, fmt.Errorf("listing attestations for campaign %s: %w", c.ID, err)
+		}
+		byID := make(map[string]*models.Attestation, len(attestations))
+		for j := range attestations {
+			byID[attestations[j].ID] = &attestations[j]
+		}
+		for _, r := range DueReminders(c, attestations, now) {
+			if err := s.send(ctx, &r); err != nil {
+				log.Warn().Err(err).Str("campaign_id", c.ID).Str("owner", r.Owner).Msg("attestation reminder not sent")
+				continue
+			}
+			sent++
+			for _, id := range r.AttestationIDs {
+				a := byID[id]
+				a.Reminders++
+				a.LastRemindedAt = &now
+				if err := s.repo.UpdateAttestation(ctx, a); err != nil {
+					return sent, fmt.Errorf("recording reminder for attestation %s: %w", id, err)
+				}
+			}
+		}
+	}
+	return sent, nil
+}
+
+// send posts a reminder to the webhook, or logs it.
+func (s *AttestationReminders) send(ctx context.Context, r *Reminder) error {
+	if s.webhook == nil {
+		log.Info().Str("campaign_id", r.CampaignID).Str("owner", r.Owner).Int("controls", len(r.Controls)).
+			Bool("overdue", r.Overdue).Msg("attestation reminder")
+		return nil
+	}
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding reminder: %w", err)
+	}
+	if err := s.webhook.Send(ctx, body); err != nil {
+		return fmt.Errorf("sending reminder: %w", err)
+	}
+	return nil
+}
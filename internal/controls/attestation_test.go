@@ -0,0 +1,65 @@
+package controls_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestAttestationCampaign(t *testing.T) {
+	due := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	campaign := &models.AttestationCampaign{ID: "c1", OrganizationID: "org-1", Name: "Q1", FrameworkID: "soc2",
+		Status: models.CampaignOpen, DueDate: due, ReminderIntervalDays: 7}
+	impls := []models.ControlImplementation{
+		{ID: "i1", FrameworkID: "soc2", ControlID: "CC1.1", Status: models.ImplementationImplemented, Owner: "alice"},
+		{ID: "i2", FrameworkID: "soc2", ControlID: "CC1.2", Status: models.ImplementationVerified},
+		{ID: "i3", FrameworkID: "soc2", ControlID: "CC1.3", Status: models.ImplementationPlanned, Owner: "alice"},
+		{ID: "i4", FrameworkID: "iso-42001", ControlID: "A.2", Status: models.ImplementationImplemented, Owner: "bob"},
+	}
+
+	atts, unassigned := controls.NewAttestations(campaign, impls, "")
+	if len(atts) != 1 || atts[0].ControlID != "CC1.1" || len(unassigned) != 1 || unassigned[0].ID != "i2" {
+		t.Fatalf("attestations = %+v, unassigned = %+v", atts, unassigned)
+	}
+	atts, unassigned = controls.NewAttestations(campaign, impls, "grc-team")
+	if len(atts) != 2 || atts[1].Owner != "grc-team" || atts[1].Status != models.AttestationPending || unassigned != nil {
+		t.Fatalf("with default owner: attestations = %+v, unassigned = %+v", atts, unassigned)
+	}
+
+	created := due.AddDate(0, 0, -20)
+	for i := range atts {
+		atts[i].CreatedAt = created
+	}
+	if got := controls.DueReminders(campaign, atts, created.AddDate(0, 0, 6)); len(got) != 0 {
+		t.Errorf("reminders before the interval = %+v", got)
+	}
+	reminded := created.AddDate(0, 0, 8)
+	atts[1].LastRemindedAt = &reminded
+	got := controls.DueReminders(campaign, atts, created.AddDate(0, 0, 10))
+	if len(got) != 1 || got[0].Owner != "alice" || got[0].Controls[0] != "soc2:CC1.1" || got[0].Overdue {
+		t.Errorf("reminders = %+v, want alice only", got)
+	}
+	if got := controls.DueReminders(campaign, atts, due.AddDate(0, 0, 2)); len(got) != 2 || !got[0].Overdue {
+		t.Errorf("reminders after the due date = %+v, want both owners, overdue", got)
+	}
+
+	atts[0].Status, atts[0].Comment = models.AttestationDeclined, "MFA not enforced for contractors"
+	report := controls.NewAttestationReport(campaign, atts, due)
+	if report.Total != 2 || report.Declined != 1 || report.Pending != 1 || report.CompletionPercentage != 50 || report.Overdue {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Owners[0].Owner != "grc-team" || len(report.DeclinedControls) != 1 {
+		t.Errorf("owners = %+v, declined = %+v", report.Owners, report.DeclinedControls)
+	}
+	if text := report.Text(); !strings.Contains(text, "soc2:CC1.1 (alice): MFA not enforced") {
+		t.Errorf("text report missing the declined control:\n%s", text)
+	}
+
+	campaign.Status = models.CampaignClosed
+	if got := controls.DueReminders(campaign, atts, due.AddDate(0, 0, 2)); got != nil {
+		t.Errorf("closed campaign reminders = %+v", got)
+	}
+}
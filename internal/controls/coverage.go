@@ -0,0 +1,162 @@
+package controls
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// crosswalkFullCoverage is the combined credit at or above which a target
+// control is treated as fully covered by implemented controls elsewhere.
+const crosswalkFullCoverage = 0.85
+
+// mappingCoverageWeight is the share of a target control an implemented source
+// control covers, by mapping type (before confidence is applied).
+var mappingCoverageWeight = map[models.MappingType]float64{
+	models.MappingExact:    1.0,
+	models.MappingSuperset: 1.0,
+	models.MappingPartial:  0.5,
+	models.MappingSubset:   0.5,
+	models.MappingRelated:  0.25,
+}
+
+// crosswalkCredit accumulates coverage contributions for one target control.
+type crosswalkCredit struct {
+	score         float64
+	contributions []models.CoverageContribution
+	// direct is set once a direct mapping contributes; derived mappings
+	// alone never fully cover a control.
+	direct bool
+}
+
+// covers reports whether the credit fully covers the control.
+func (c *crosswalkCredit) covers() bool {
+	return c != nil && c.direct && c.score >= crosswalkFullCoverage
+}
+
+// crosswalkCoverage computes, for each control in the target framework, how much
+// coverage implemented controls in other frameworks provide through crosswalks.
+// Mappings are used in both directions; reverse mappings invert subset/superset.
+// Only direct mappings earn credit, except from the analysis's source
+// framework: when it has no direct mappings to the target, mappings derived
+// through intermediate frameworks earn credit toward partial coverage.
+// The result is keyed by lower-cased target control ID.
+func (s *Service) crosswalkCoverage(target, source FrameworkID, implemented map[string]bool) map[string]*crosswalkCredit {
+	credits := make(map[string]*crosswalkCredit)
+	if len(implemented) == 0 {
+		return credits
+	}
+
+	add := func(targetControl string, c models.CoverageContribution) {
+		weight, ok := mappingCoverageWeight[c.MappingType]
+		if !ok {
+			return
+		}
+		c.Credit = weight * c.Confidence
+		if c.Credit <= 0 {
+			return
+		}
+		key := strings.ToLower(targetControl)
+		credit, ok := credits[key]
+		if !ok {
+			credit = &crosswalkCredit{}
+			credits[key] = credit
+		}
+		credit.score = combineCoverage(credit.score, c.Credit)
+		credit.contributions = append(credit.contributions, c)
+		credit.direct = credit.direct || !c.Derived
+	}
+
+	for _, fw := range s.frameworkIDs() {
+		if fw == target {
+			continue
+		}
+		mapped := false
+
+		if forward, err := s.directCrosswalks(fw, target); err == nil {
+			mapped = len(forward) > 0
+			for _, xw := range forward {
+				if !implemented[strings.ToLower(xw.SourceControlID)] {
+					continue
+				}
+				add(xw.TargetControlID, models.CoverageContribution{
+					FrameworkID: xw.SourceFrameworkID,
+					ControlID:   xw.SourceControlID,
+					MappingType: xw.MappingType,
+					Confidence:  xw.Confidence,
+				})
+			}
+		}
+
+		if reverse, err := s.directCrosswalks(target, fw); err == nil {
+			mapped = mapped || len(reverse) > 0
+			for _, xw := range reverse {
+				if !implemented[strings.ToLower(xw.TargetControlID)] {
+					continue
+				}
+				add(xw.SourceControlID, models.CoverageContribution{
+					FrameworkID: xw.TargetFrameworkID,
+					ControlID:   xw.TargetControlID,
+					MappingType: invertMappingType(xw.MappingType),
+					Confidence:  xw.Confidence,
+				})
+			}
+		}
+
+		if fw == source && !mapped {
+			derived, err := s.DeriveCrosswalks(fw, target)
+			if err != nil {
+				continue
+			}
+			for _, xw := range derived {
+				if !implemented[strings.ToLower(xw.SourceControlID)] {
+					continue
+				}
+				add(xw.TargetControlID, models.CoverageContribution{
+					FrameworkID: xw.SourceFrameworkID,
+					ControlID:   xw.SourceControlID,
+					MappingType: xw.MappingType,
+					Confidence:  xw.Confidence,
+					Derived:     true,
+				})
+			}
+		}
+	}
+
+	for _, credit := range credits {
+		sort.SliceStable(credit.contributions, func(i, j int) bool {
+			return credit.contributions[i].Credit > credit.contributions[j].Credit
+		})
+	}
+
+	return credits
+}
+
+// frameworkIDs returns the registered framework IDs in a stable order.
+func (s *Service) frameworkIDs() []FrameworkID {
+	ids := make([]FrameworkID, 0, len(s.frameworks))
+	for id := range s.frameworks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// combineCoverage merges independent coverage credits so that overlapping
+// mappings never exceed full coverage: 1 - (1-a)(1-b).
+func combineCoverage(a, b float64) float64 {
+	return 1 - (1-a)*(1-b)
+}
+
+// invertMappingType returns the mapping type as seen from the target side.
+func invertMappingType(t models.MappingType) models.MappingType {
+	switch t {
+	case models.MappingSuperset:
+		return models.MappingSubset
+	case models.MappingSubset:
+		return models.MappingSuperset
+	default:
+		return t
+	}
+}
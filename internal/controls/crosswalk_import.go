@@ -0,0 +1,249 @@
+package controls
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+)
+
+// How an imported crosswalk that maps the same control pair as a stored
+// curated one is resolved.
+const (
+	// ConflictSkip keeps the stored mapping.
+	ConflictSkip = "skip"
+	// ConflictReplace overwrites the stored mapping with the imported one.
+	ConflictReplace = "replace"
+	// ConflictFail rejects the whole import.
+	ConflictFail = "fail"
+)
+
+// ErrCrosswalkConflict is returned when an import under ConflictFail maps
+// a control pair that already has a curated mapping.
+var ErrCrosswalkConflict = errors.New("crosswalk conflicts with a curated mapping")
+
+// DefaultImportConfidence is the confidence of imported mappings that
+// carry no confidence score.
+const DefaultImportConfidence = 0.5
+
+// MappingCatalog is a framework, with its controls, that an imported
+// mapping collection may reference.
+type MappingCatalog struct {
+	Framework *models.Framework
+	Controls  []models.Control
+}
+
+// MappingIssue is a map of an imported mapping collection that was not
+// imported.
+type MappingIssue struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+// MappingCatalogs returns the analyzer's frameworks with their controls.
+func (g *GapAnalyzer) MappingCatalogs() []MappingCatalog {
+	var catalogs []MappingCatalog
+	for _, fw := range g.Frameworks() {
+		list, _ := g.Controls(fw.ID)
+		catalogs = append(catalogs, MappingCatalog{Framework: fw, Controls: list})
+	}
+	return catalogs
+}
+
+// DirectCrosswalks returns every direct mapping between the analyzer's
+// frameworks: the built-in tables and those loaded from the data
+// directory. Derived mappings are left out.
+func (g *GapAnalyzer) DirectCrosswalks() ([]models.Crosswalk, error) {
+	var all []models.Crosswalk
+	frameworks := g.Frameworks()
+	for _, source := range frameworks {
+		for _, target := range frameworks {
+			if source.ID == target.ID {
+				continue
+			}
+			direct, err := g.service.directCrosswalks(FrameworkID(source.ID), FrameworkID(target.ID))
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, direct...)
+		}
+	}
+	return all, nil
+}
+
+// CrosswalksFromOSCAL reads the crosswalks of a mapping collection. Each
+// mapping's resources are matched to catalogs by AgentGuard href, then by
+// framework ID or name; source and target name the frameworks of
+// resources that match none, as in third-party files. Control references
+// are matched by control ID or its OSCAL form ("ac-2.1" for "AC-2(1)"), and
+// maps with several sources or targets become one crosswalk per pair.
+// Maps naming controls the frameworks do not have are returned as issues.
+func CrosswalksFromOSCAL(mc *oscal.MappingCollection, catalogs []MappingCatalog, source, target string) ([]models.Crosswalk, []MappingIssue, error) {
+	byID := make(map[string]*MappingCatalog, len(catalogs))
+	for i := range catalogs {
+		byID[catalogs[i].Framework.ID] = &catalogs[i]
+	}
+	resolve := func(r oscal.MappingResource, fallback, side string) (*MappingCatalog, error) {
+		for _, name := range []string{oscal.FrameworkRef(r), r.Title, r.Href} {
+			if name == "" {
+				continue
+			}
+			for i := range catalogs {
+				fw := catalogs[i].Framework
+				if strings.EqualFold(name, fw.ID) || strings.EqualFold(name, fw.Name) {
+					return &catalogs[i], nil
+				}
+			}
+		}
+		if fallback != "" {
+			if cat, ok := byID[fallback]; ok {
+				return cat, nil
+			}
+			return nil, fmt.Errorf("unknown %s framework: %s", side, fallback)
+		}
+		return nil, fmt.Errorf("%s resource %q is not a known framework; name it with the %s option", side, firstNonEmpty(r.Title, r.Href), side)
+	}
+
+	var (
+		crosswalks []models.Crosswalk
+		issues     []MappingIssue
+	)
+	for _, m := range mc.Mappings {
+		src, err := resolve(m.SourceResource, source, "source")
+		if err != nil {
+			return nil, nil, err
+		}
+		tgt, err := resolve(m.TargetResource, target, "target")
+		if err != nil {
+			return nil, nil, err
+		}
+		srcIDs, tgtIDs := controlRefs(src.Controls), controlRefs(tgt.Controls)
+		for _, mp := range m.Maps {
+			confidence, ok := mp.Confidence()
+			if !ok {
+				confidence = DefaultImportConfidence
+			}
+			rationale := mp.Remarks
+			if rationale == "" {
+				rationale = "Imported from " + firstNonEmpty(mc.Metadata.Title, "an OSCAL mapping collection")
+			}
+			for _, s := range mp.Sources {
+				for _, t := range mp.Targets {
+					sourceID, sok := srcIDs[strings.ToLower(s.IDRef)]
+					targetID, tok := tgtIDs[strings.ToLower(t.IDRef)]
+					switch {
+					case !sok:
+						issues = append(issues, MappingIssue{Source: src.Framework.ID + ":" + s.IDRef, Target: tgt.Framework.ID + ":" + t.IDRef, Reason: "unknown source control"})
+					case !tok:
+						issues = append(issues, MappingIssue{Source: src.Framework.ID + ":" + s.IDRef, Target: tgt.Framework.ID + ":" + t.IDRef, Reason: "unknown target control"})
+					default:
+						crosswalks = append(crosswalks, models.Crosswalk{
+							SourceFrameworkID: src.Framework.ID,
+							SourceControlID:   sourceID,
+							TargetFrameworkID: tgt.Framework.ID,
+							TargetControlID:   targetID,
+							MappingType:       mp.MappingType(),
+							Confidence:        confidence,
+							Rationale:         rationale,
+						})
+					}
+				}
+			}
+		}
+	}
+	return crosswalks, issues, nil
+}
+
+// controlRefs indexes control IDs by their lower-cased ID and OSCAL form.
+func controlRefs(list []models.Control) map[string]string {
+	refs := make(map[string]string, 2*len(list))
+	for _, c := range list {
+		refs[strings.ToLower(c.ControlID)] = c.ControlID
+		refs[oscal.ControlID(c.ControlID)] = c.ControlID
+	}
+	return refs
+}
+
+// CrosswalkImportPlan is what importing crosswalks does to the stored
+// curated mappings.
+type CrosswalkImportPlan struct {
+	// Create are the imported mappings of control pairs without a curated
+	// mapping.
+	Create []models.Crosswalk `json:"create"`
+	// Replace are imported mappings carrying the ID of the curated mapping
+	// they overwrite.
+	Replace []models.Crosswalk `json:"replace"`
+	// Skipped are the curated mappings kept over conflicting imports.
+	Skipped []models.Crosswalk `json:"skipped"`
+	// Unchanged counts imported mappings identical to the curated ones.
+	Unchanged int `json:"unchanged"`
+}
+
+// PlanCrosswalkImport matches imported crosswalks against the stored
+// curated ones by framework and control pair. An import that maps a pair
+// the same way as the stored mapping is unchanged; otherwise it conflicts
+// and is resolved by resolution. Under ConflictFail any conflict returns
+// ErrCrosswalkConflict naming the first. Later duplicates in incoming
+// replace earlier ones.
+func PlanCrosswalkImport(existing, incoming []models.Crosswalk, resolution string) (*CrosswalkImportPlan, error) {
+	switch resolution {
+	case "":
+		resolution = ConflictSkip
+	case ConflictSkip, ConflictReplace, ConflictFail:
+	default:
+		return nil, fmt.Errorf("unknown conflict resolution: %s", resolution)
+	}
+	stored := make(map[[4]string]models.Crosswalk, len(existing))
+	for _, xw := range existing {
+		stored[importKey(xw)] = xw
+	}
+	var order [][4]string
+	unique := make(map[[4]string]models.Crosswalk, len(incoming))
+	for _, xw := range incoming {
+		key := importKey(xw)
+		if _, ok := unique[key]; !ok {
+			order = append(order, key)
+		}
+		unique[key] = xw
+	}
+
+	plan := &CrosswalkImportPlan{Create: []models.Crosswalk{}, Replace: []models.Crosswalk{}, Skipped: []models.Crosswalk{}}
+	for _, key := range order {
+		xw := unique[key]
+		cur, ok := stored[key]
+		switch {
+		case !ok:
+			plan.Create = append(plan.Create, xw)
+		case cur.MappingType == xw.MappingType && cur.Confidence == xw.Confidence && cur.Rationale == xw.Rationale:
+			plan.Unchanged++
+		case resolution == ConflictFail:
+			return nil, fmt.Errorf("%w: %s:%s to %s:%s", ErrCrosswalkConflict,
+				xw.SourceFrameworkID, xw.SourceControlID, xw.TargetFrameworkID, xw.TargetControlID)
+		case resolution == ConflictReplace:
+			xw.ID = cur.ID
+			plan.Replace = append(plan.Replace, xw)
+		default:
+			plan.Skipped = append(plan.Skipped, cur)
+		}
+	}
+	return plan, nil
+}
+
+func importKey(xw models.Crosswalk) [4]string {
+	return [4]string{
+		xw.SourceFrameworkID, strings.ToLower(xw.SourceControlID),
+		xw.TargetFrameworkID, strings.ToLower(xw.TargetControlID),
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
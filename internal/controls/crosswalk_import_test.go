@@ -0,0 +1,82 @@
+package controls_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+)
+
+func TestCrosswalksFromOSCAL(t *testing.T) {
+	catalogs := []controls.MappingCatalog{
+		{Framework: &models.Framework{ID: "soc2", Name: "SOC 2"}, Controls: []models.Control{{ControlID: "CC6.1"}, {ControlID: "CC7.2"}}},
+		{Framework: &models.Framework{ID: "nist-800-53", Name: "NIST SP 800-53"}, Controls: []models.Control{{ControlID: "AC-2(1)"}, {ControlID: "SI-4"}}},
+	}
+	mc := &oscal.MappingCollection{
+		Metadata: oscal.Metadata{Title: "Published mapping"},
+		Mappings: []oscal.Mapping{{
+			SourceResource: oscal.MappingResource{Href: "https://example.org/soc2.json", Title: "Trust Services Criteria"},
+			TargetResource: oscal.MappingResource{Title: "NIST SP 800-53"},
+			Maps: []oscal.Map{
+				{Relationship: "intersects-with", Sources: []oscal.MapItem{{IDRef: "cc6.1"}, {IDRef: "CC7.2"}}, Targets: []oscal.MapItem{{IDRef: "ac-2.1"}}, ConfidenceScore: &oscal.ConfidenceScore{Percentage: "90%"}},
+				{Relationship: "equivalent-to", Sources: []oscal.MapItem{{IDRef: "CC9.9"}}, Targets: []oscal.MapItem{{IDRef: "si-4"}}},
+			},
+		}},
+	}
+
+	if _, _, err := controls.CrosswalksFromOSCAL(mc, catalogs, "", ""); err == nil {
+		t.Fatal("unknown source resource imported without the source option")
+	}
+	got, issues, err := controls.CrosswalksFromOSCAL(mc, catalogs, "soc2", "")
+	if err != nil {
+		t.Fatalf("CrosswalksFromOSCAL: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d crosswalks, want one per source: %+v", len(got), got)
+	}
+	xw := got[0]
+	if xw.SourceControlID != "CC6.1" || xw.TargetFrameworkID != "nist-800-53" || xw.TargetControlID != "AC-2(1)" {
+		t.Errorf("crosswalk = %+v, want control IDs resolved", xw)
+	}
+	if xw.MappingType != models.MappingPartial || xw.Confidence != 0.9 || xw.Rationale != "Imported from Published mapping" {
+		t.Errorf("crosswalk = %+v", xw)
+	}
+	if len(issues) != 1 || issues[0].Reason != "unknown source control" {
+		t.Errorf("issues = %+v, want the unknown source control", issues)
+	}
+}
+
+func TestPlanCrosswalkImport(t *testing.T) {
+	existing := []models.Crosswalk{
+		{ID: "a", SourceFrameworkID: "soc2", SourceControlID: "CC6.1", TargetFrameworkID: "nist-800-53", TargetControlID: "AC-2", MappingType: models.MappingPartial, Confidence: 0.7},
+		{ID: "b", SourceFrameworkID: "soc2", SourceControlID: "CC7.2", TargetFrameworkID: "nist-800-53", TargetControlID: "SI-4", MappingType: models.MappingExact, Confidence: 0.9},
+	}
+	incoming := []models.Crosswalk{
+		{SourceFrameworkID: "soc2", SourceControlID: "cc6.1", TargetFrameworkID: "nist-800-53", TargetControlID: "ac-2", MappingType: models.MappingExact, Confidence: 0.9},
+		{SourceFrameworkID: "soc2", SourceControlID: "CC7.2", TargetFrameworkID: "nist-800-53", TargetControlID: "SI-4", MappingType: models.MappingExact, Confidence: 0.9},
+		{SourceFrameworkID: "soc2", SourceControlID: "CC8.1", TargetFrameworkID: "nist-800-53", TargetControlID: "CM-3", MappingType: models.MappingRelated, Confidence: 0.5},
+	}
+
+	plan, err := controls.PlanCrosswalkImport(existing, incoming, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Create) != 1 || len(plan.Replace) != 0 || len(plan.Skipped) != 1 || plan.Unchanged != 1 {
+		t.Errorf("skip plan = %+v", plan)
+	}
+	plan, err = controls.PlanCrosswalkImport(existing, incoming, controls.ConflictReplace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Replace) != 1 || plan.Replace[0].ID != "a" || plan.Replace[0].MappingType != models.MappingExact {
+		t.Errorf("replace plan = %+v, want the imported mapping under the curated ID", plan.Replace)
+	}
+	if _, err := controls.PlanCrosswalkImport(existing, incoming, controls.ConflictFail); !errors.Is(err, controls.ErrCrosswalkConflict) {
+		t.Errorf("fail plan error = %v, want ErrCrosswalkConflict", err)
+	}
+	if _, err := controls.PlanCrosswalkImport(existing, incoming, "merge"); err == nil {
+		t.Error("unknown conflict resolution accepted")
+	}
+}
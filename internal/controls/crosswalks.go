@@ -302,6 +302,931 @@ func getCrosswalkMappings(source, target FrameworkID) map[string]CrosswalkMappin
 				Rationale:  "Privacy protection maps to information management",
 			},
 		},
+
+		// EU AI Act -> NIST AI RMF
+		string(FrameworkEUAIAct) + "->" + string(FrameworkNISTAIRMF): {
+			"EUAIA-4": {
+				TargetIDs:  []string{"GOVERN-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "AI literacy maps to training within accountability structures",
+			},
+			"EUAIA-5": {
+				TargetIDs:  []string{"GOVERN-1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Prohibited practices are a legal requirement to be understood and managed",
+			},
+			"EUAIA-6": {
+				TargetIDs:  []string{"MAP-1", "MAP-2"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "High-risk classification maps to context establishment and AI categorization",
+			},
+			"EUAIA-9": {
+				TargetIDs:  []string{"GOVERN-4", "MAP-4", "MANAGE-1"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Lifecycle risk management maps to risk tolerance, mapping and prioritization",
+			},
+			"EUAIA-10": {
+				TargetIDs:  []string{"MEASURE-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Data quality and bias examination relate to trustworthiness evaluation",
+			},
+			"EUAIA-11": {
+				TargetIDs:  []string{"MAP-3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Technical documentation covers capabilities and limitations",
+			},
+			"EUAIA-12": {
+				TargetIDs:  []string{"MEASURE-3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Automatic event logging maps to risk tracking mechanisms",
+			},
+			"EUAIA-13": {
+				TargetIDs:  []string{"MAP-3"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Transparency to deployers maps to documented capabilities and limitations",
+			},
+			"EUAIA-14": {
+				TargetIDs:  []string{"GOVERN-2", "MANAGE-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Human oversight relates to accountability and managed deployment",
+			},
+			"EUAIA-15": {
+				TargetIDs:  []string{"MEASURE-1", "MEASURE-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Accuracy, robustness and security map to measurement and evaluation",
+			},
+			"EUAIA-17": {
+				TargetIDs:  []string{"GOVERN-1", "GOVERN-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Quality management maps to governance policies and accountability",
+			},
+			"EUAIA-26": {
+				TargetIDs:  []string{"MANAGE-2", "MEASURE-3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Deployer monitoring maps to managed deployment and tracking",
+			},
+			"EUAIA-27": {
+				TargetIDs:  []string{"MAP-5"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Fundamental rights impact maps to impacts on individuals and communities",
+			},
+			"EUAIA-50": {
+				TargetIDs:  []string{"GOVERN-5"},
+				Type:       models.MappingPartial,
+				Confidence: 0.5,
+				Rationale:  "Disclosure to affected persons relates to stakeholder engagement",
+			},
+			"EUAIA-53": {
+				TargetIDs:  []string{"GOVERN-6"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "General-purpose model documentation supports third-party risk policies",
+			},
+			"EUAIA-72": {
+				TargetIDs:  []string{"MEASURE-3", "MEASURE-4"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Post-market monitoring maps to tracking and feedback incorporation",
+			},
+			"EUAIA-73": {
+				TargetIDs:  []string{"MANAGE-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Serious incident reporting maps to risk treatment and incident communication",
+			},
+		},
+
+		// EU AI Act -> ISO 42001
+		string(FrameworkEUAIAct) + "->" + string(FrameworkISO42001): {
+			"EUAIA-4": {
+				TargetIDs:  []string{"ISO42001-7.2", "ISO42001-7.3"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "AI literacy maps to competence and awareness",
+			},
+			"EUAIA-6": {
+				TargetIDs:  []string{"ISO42001-4.3", "ISO42001-8.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Classification relates to AIMS scope and impact assessment",
+			},
+			"EUAIA-9": {
+				TargetIDs:  []string{"ISO42001-6.1", "ISO42001-8.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Risk management system maps to risk planning and impact assessment",
+			},
+			"EUAIA-10": {
+				TargetIDs:  []string{"ISO42001-A.3.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Data governance and bias examination map to bias assessment",
+			},
+			"EUAIA-11": {
+				TargetIDs:  []string{"ISO42001-7.5", "ISO42001-8.4"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Technical documentation maps to documented information and system documentation",
+			},
+			"EUAIA-12": {
+				TargetIDs:  []string{"ISO42001-9.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Record-keeping supports monitoring and measurement",
+			},
+			"EUAIA-13": {
+				TargetIDs:  []string{"ISO42001-A.2.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Transparency to deployers maps directly to transparency controls",
+			},
+			"EUAIA-14": {
+				TargetIDs:  []string{"ISO42001-A.5.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Human oversight maps directly to human oversight controls",
+			},
+			"EUAIA-15": {
+				TargetIDs:  []string{"ISO42001-A.4.4", "ISO42001-A.6.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Robustness and cybersecurity map to AI security and reliability",
+			},
+			"EUAIA-17": {
+				TargetIDs:  []string{"ISO42001-4.4", "ISO42001-5.2", "ISO42001-8.1"},
+				Type:       models.MappingSuperset,
+				Confidence: 0.8,
+				Rationale:  "An AI management system covers the quality management system obligations",
+			},
+			"EUAIA-26": {
+				TargetIDs:  []string{"ISO42001-8.1", "ISO42001-9.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Deployer obligations relate to operational control and monitoring",
+			},
+			"EUAIA-27": {
+				TargetIDs:  []string{"ISO42001-8.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Fundamental rights impact assessment is a form of AI impact assessment",
+			},
+			"EUAIA-43": {
+				TargetIDs:  []string{"ISO42001-9.2"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Conformity assessment relates to internal audit of the AIMS",
+			},
+			"EUAIA-50": {
+				TargetIDs:  []string{"ISO42001-A.2.2", "ISO42001-7.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Disclosure obligations map to transparency and communication",
+			},
+			"EUAIA-53": {
+				TargetIDs:  []string{"ISO42001-8.6"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Model provider information supports third-party considerations",
+			},
+			"EUAIA-72": {
+				TargetIDs:  []string{"ISO42001-9.1", "ISO42001-10.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Post-market monitoring maps to monitoring and continual improvement",
+			},
+			"EUAIA-73": {
+				TargetIDs:  []string{"ISO42001-10.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Incident reporting maps to nonconformity and corrective action",
+			},
+		},
+
+		// OWASP LLM Top 10 -> NIST AI RMF
+		string(FrameworkOWASPLLM) + "->" + string(FrameworkNISTAIRMF): {
+			"LLM01": {
+				TargetIDs:  []string{"MEASURE-2", "MANAGE-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Prompt injection resistance is evaluated and mitigations are documented as risk treatments",
+			},
+			"LLM02": {
+				TargetIDs:  []string{"MEASURE-2", "MAP-5"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Disclosure risk is evaluated and its impact on individuals is mapped",
+			},
+			"LLM03": {
+				TargetIDs:  []string{"GOVERN-6", "MAP-4"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Supply chain risk maps to third-party policies and component risk mapping",
+			},
+			"LLM04": {
+				TargetIDs:  []string{"MAP-4", "MEASURE-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Poisoning risk arises from data components and is detected through evaluation",
+			},
+			"LLM05": {
+				TargetIDs:  []string{"MEASURE-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Output handling weaknesses are identified through security evaluation",
+			},
+			"LLM06": {
+				TargetIDs:  []string{"MAP-3", "MANAGE-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Agent capabilities are scoped and their constraints documented as risk treatments",
+			},
+			"LLM07": {
+				TargetIDs:  []string{"MEASURE-2"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "System prompt exposure is one of the security properties evaluated",
+			},
+			"LLM08": {
+				TargetIDs:  []string{"MAP-4", "MEASURE-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Retrieval stores are mapped as components and evaluated for security and privacy",
+			},
+			"LLM09": {
+				TargetIDs:  []string{"MEASURE-2", "MAP-3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Output validity is evaluated and model limitations are documented",
+			},
+			"LLM10": {
+				TargetIDs:  []string{"MEASURE-1", "MANAGE-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Resource consumption is monitored and bounded through documented treatments",
+			},
+		},
+
+		// OWASP LLM Top 10 -> ISO 42001
+		string(FrameworkOWASPLLM) + "->" + string(FrameworkISO42001): {
+			"LLM01": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Prompt injection is addressed by AI system security controls",
+			},
+			"LLM02": {
+				TargetIDs:  []string{"ISO42001-A.7.3", "ISO42001-A.4.4"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Sensitive data disclosure maps to privacy protection and system security",
+			},
+			"LLM03": {
+				TargetIDs:  []string{"ISO42001-8.6", "ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Supply chain risk maps to third-party considerations and system security",
+			},
+			"LLM04": {
+				TargetIDs:  []string{"ISO42001-A.3.2", "ISO42001-A.6.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Poisoned data undermines bias mitigation and reliability",
+			},
+			"LLM05": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Output handling is part of AI system security",
+			},
+			"LLM06": {
+				TargetIDs:  []string{"ISO42001-A.4.4", "ISO42001-A.5.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Excessive agency is constrained by security controls and human oversight",
+			},
+			"LLM07": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "System prompt protection is part of AI system security",
+			},
+			"LLM08": {
+				TargetIDs:  []string{"ISO42001-A.4.4", "ISO42001-A.7.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Vector store weaknesses map to system security and privacy protection",
+			},
+			"LLM09": {
+				TargetIDs:  []string{"ISO42001-A.2.2", "ISO42001-A.5.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Misinformation is mitigated by transparency about limitations and oversight of outputs",
+			},
+			"LLM10": {
+				TargetIDs:  []string{"ISO42001-A.6.2"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Availability under abusive load is part of AI system reliability",
+			},
+		},
+
+		// OWASP LLM Top 10 -> MITRE ATLAS: the techniques each risk's
+		// mitigations defend against
+		string(FrameworkOWASPLLM) + "->" + string(FrameworkMITREATLAS): {
+			"LLM01": {
+				TargetIDs:  []string{"AML.T0051", "AML.T0051.000", "AML.T0051.001", "AML.T0054"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Prompt injection and jailbreak mitigations counter the ATLAS prompt injection techniques directly",
+			},
+			"LLM02": {
+				TargetIDs:  []string{"AML.T0057", "AML.T0024.000", "AML.T0024.001"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Sensitive data disclosure controls limit LLM data leakage and training data inference",
+			},
+			"LLM03": {
+				TargetIDs:  []string{"AML.T0010", "AML.T0010.001", "AML.T0010.003", "AML.T0011.001"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Supply chain controls counter compromised software, models and malicious packages",
+			},
+			"LLM04": {
+				TargetIDs:  []string{"AML.T0020", "AML.T0019", "AML.T0018", "AML.T0010.002"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Data and model poisoning mitigations counter training data poisoning and backdoored models",
+			},
+			"LLM05": {
+				TargetIDs:  []string{"AML.T0050"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Treating model output as untrusted prevents it reaching interpreters unchecked",
+			},
+			"LLM06": {
+				TargetIDs:  []string{"AML.T0053", "AML.T0050", "AML.T0048"},
+				Type:       models.MappingPartial,
+				Confidence: 0.75,
+				Rationale:  "Limiting agent tools and permissions bounds what plugin compromise and injected commands can do",
+			},
+			"LLM07": {
+				TargetIDs:  []string{"AML.T0056", "AML.T0055"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Keeping secrets out of system prompts counters system prompt extraction and credential theft",
+			},
+			"LLM08": {
+				TargetIDs:  []string{"AML.T0070", "AML.T0036"},
+				Type:       models.MappingPartial,
+				Confidence: 0.75,
+				Rationale:  "Vector store access control and validation counter RAG poisoning and repository data collection",
+			},
+			"LLM09": {
+				TargetIDs:  []string{"AML.T0062"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Verifying model output limits the hallucinations adversaries discover and weaponize",
+			},
+			"LLM10": {
+				TargetIDs:  []string{"AML.T0029", "AML.T0034", "AML.T0024.002"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Rate limits and quotas counter denial of service, cost harvesting and model extraction",
+			},
+		},
+
+		// SOC 2 -> NIST 800-53
+		string(FrameworkSOC2) + "->" + string(FrameworkNIST80053): {
+			"CC1.3": {
+				TargetIDs:  []string{"PL-2"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Defined responsibilities for security are documented in system security plans",
+			},
+			"CC2.1": {
+				TargetIDs:  []string{"AU-2", "AU-3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Quality information for internal control depends on logging the right events and content",
+			},
+			"CC3.2": {
+				TargetIDs:  []string{"RA-3"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Risk identification and analysis is the risk assessment control",
+			},
+			"CC3.4": {
+				TargetIDs:  []string{"CM-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Assessing changes that affect internal control maps to impact analyses",
+			},
+			"CC4.1": {
+				TargetIDs:  []string{"SI-4", "AU-6"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Ongoing evaluations rely on system monitoring and audit review",
+			},
+			"CC5.2": {
+				TargetIDs:  []string{"CM-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Technology general controls include secure baseline configurations",
+			},
+			"CC5.3": {
+				TargetIDs:  []string{"AC-1", "AU-1", "CM-1", "IA-1", "SC-1", "SI-1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Policies and procedures map to the policy controls of each family",
+			},
+			"CC6.1": {
+				TargetIDs:  []string{"AC-3", "SC-8"},
+				Type:       models.MappingPartial,
+				Confidence: 0.8,
+				Rationale:  "Logical access security maps to access enforcement and protection of information in transit",
+			},
+			"CC6.2": {
+				TargetIDs:  []string{"AC-2", "IA-2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "User registration and authorization maps to account management and identification",
+			},
+			"CC6.3": {
+				TargetIDs:  []string{"AC-2", "AC-6"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Role-based least privilege access maps to account management and least privilege",
+			},
+			"CC6.5": {
+				TargetIDs:  []string{"SI-12"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Disposal after data is unrecoverable maps to information retention and disposal",
+			},
+			"CC6.6": {
+				TargetIDs:  []string{"SC-7"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Protection against threats outside system boundaries is boundary protection",
+			},
+			"CC6.7": {
+				TargetIDs:  []string{"SC-8"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Protecting data during transmission maps to transmission confidentiality and integrity",
+			},
+			"CC7.1": {
+				TargetIDs:  []string{"RA-5", "CM-2", "SI-5"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Configuration and vulnerability monitoring maps to scanning, baselines and advisories",
+			},
+			"CC7.2": {
+				TargetIDs:  []string{"SI-4", "AU-6"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Anomaly monitoring maps to system monitoring and audit record review",
+			},
+			"CC7.3": {
+				TargetIDs:  []string{"AU-6"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Evaluating security events relies on audit record analysis",
+			},
+			"CC8.1": {
+				TargetIDs:  []string{"CM-3", "CM-4"},
+				Type:       models.MappingExact,
+				Confidence: 0.9,
+				Rationale:  "Change management maps to configuration change control and impact analysis",
+			},
+			"CC9.1": {
+				TargetIDs:  []string{"CP-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Business disruption mitigation maps to contingency planning",
+			},
+			"A1.2": {
+				TargetIDs:  []string{"CP-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Backup and recovery infrastructure is specified in the contingency plan",
+			},
+			"A1.3": {
+				TargetIDs:  []string{"CP-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Recovery plan testing exercises the contingency plan",
+			},
+			"C1.1": {
+				TargetIDs:  []string{"SC-8", "AC-3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Protecting confidential information relies on access enforcement and transmission protection",
+			},
+			"C1.2": {
+				TargetIDs:  []string{"SI-12"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Disposal of confidential information maps to information management and retention",
+			},
+		},
+
+		// SOC 2 -> ISO 42001
+		string(FrameworkSOC2) + "->" + string(FrameworkISO42001): {
+			"CC1.1": {
+				TargetIDs:  []string{"ISO42001-5.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Commitment to integrity is demonstrated through leadership commitment",
+			},
+			"CC1.2": {
+				TargetIDs:  []string{"ISO42001-9.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Board oversight of internal control corresponds to management review",
+			},
+			"CC1.3": {
+				TargetIDs:  []string{"ISO42001-5.3"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Structures and authorities map to organizational roles and responsibilities",
+			},
+			"CC1.4": {
+				TargetIDs:  []string{"ISO42001-7.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Commitment to competence maps to competence requirements",
+			},
+			"CC2.1": {
+				TargetIDs:  []string{"ISO42001-7.5", "ISO42001-8.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Quality information maps to documented information and AI system documentation",
+			},
+			"CC2.2": {
+				TargetIDs:  []string{"ISO42001-7.3", "ISO42001-7.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Internal communication maps to awareness and communication",
+			},
+			"CC2.3": {
+				TargetIDs:  []string{"ISO42001-7.4", "ISO42001-A.2.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "External communication maps to communication and AI system transparency",
+			},
+			"CC3.1": {
+				TargetIDs:  []string{"ISO42001-6.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Objective specification maps to AI objectives and planning",
+			},
+			"CC3.2": {
+				TargetIDs:  []string{"ISO42001-6.1", "ISO42001-8.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Risk identification and analysis maps to risk actions and AI system impact assessment",
+			},
+			"CC3.4": {
+				TargetIDs:  []string{"ISO42001-6.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Assessing changes maps to planning of changes",
+			},
+			"CC4.1": {
+				TargetIDs:  []string{"ISO42001-9.1", "ISO42001-9.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Ongoing and separate evaluations map to monitoring and internal audit",
+			},
+			"CC4.2": {
+				TargetIDs:  []string{"ISO42001-10.1"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Communicating deficiencies for correction maps to nonconformity and corrective action",
+			},
+			"CC5.1": {
+				TargetIDs:  []string{"ISO42001-8.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Selecting control activities maps to operational planning and control",
+			},
+			"CC5.3": {
+				TargetIDs:  []string{"ISO42001-5.2", "ISO42001-7.5"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Policies and procedures map to the AI policy and documented information",
+			},
+			"CC6.1": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Logical access security is part of AI system security",
+			},
+			"CC6.8": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Preventing malicious software contributes to AI system security",
+			},
+			"CC7.2": {
+				TargetIDs:  []string{"ISO42001-9.1", "ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Anomaly monitoring maps to monitoring and AI system security",
+			},
+			"CC7.4": {
+				TargetIDs:  []string{"ISO42001-10.1"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Incident response feeds nonconformity and corrective action",
+			},
+			"CC8.1": {
+				TargetIDs:  []string{"ISO42001-6.3", "ISO42001-8.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Change management maps to planning of changes and the AI system lifecycle",
+			},
+			"CC9.2": {
+				TargetIDs:  []string{"ISO42001-8.6"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Vendor risk management maps to third-party considerations",
+			},
+			"A1.1": {
+				TargetIDs:  []string{"ISO42001-7.1", "ISO42001-A.6.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.5,
+				Rationale:  "Capacity management maps to resources and AI system reliability",
+			},
+			"C1.1": {
+				TargetIDs:  []string{"ISO42001-8.5", "ISO42001-A.7.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Identifying and protecting confidential information maps to data for AI systems and privacy protection",
+			},
+		},
+
+		// CSA AICM -> NIST 800-53
+		string(FrameworkCSAAICM) + "->" + string(FrameworkNIST80053): {
+			"A&A-02": {
+				TargetIDs:  []string{"RA-3"},
+				Type:       models.MappingRelated,
+				Confidence: 0.4,
+				Rationale:  "Independent assessments examine the risk assessment and the controls it selects",
+			},
+			"AIS-04": {
+				TargetIDs:  []string{"PL-2", "CM-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Secure design is documented in system security plans and checked by impact analysis",
+			},
+			"AIS-05": {
+				TargetIDs:  []string{"RA-5"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Automated application security testing is a form of vulnerability scanning",
+			},
+			"BCR-08": {
+				TargetIDs:  []string{"CP-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Backups are specified and exercised through the contingency plan",
+			},
+			"CCC-03": {
+				TargetIDs:  []string{"CM-3", "CM-4"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Managed, approved changes map to configuration change control and impact analysis",
+			},
+			"CCC-07": {
+				TargetIDs:  []string{"CM-2", "SI-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Detecting deviation needs a baseline configuration and monitoring against it",
+			},
+			"CEK-03": {
+				TargetIDs:  []string{"SC-8"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Encryption in transit maps to transmission confidentiality and integrity",
+			},
+			"DSP-05": {
+				TargetIDs:  []string{"PL-2"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Data flows are documented as part of the system security plan",
+			},
+			"DSP-17": {
+				TargetIDs:  []string{"AC-3", "SC-8"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Protecting sensitive data relies on access enforcement and transmission protection",
+			},
+			"GRC-01": {
+				TargetIDs:  []string{"PL-1", "AC-1", "AU-1", "CM-1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Governance policies and procedures map to the policy controls of each family",
+			},
+			"GRC-02": {
+				TargetIDs:  []string{"RA-1", "RA-3"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "The risk management program maps to risk assessment policy and risk assessment",
+			},
+			"IAM-05": {
+				TargetIDs:  []string{"AC-6"},
+				Type:       models.MappingExact,
+				Confidence: 0.95,
+				Rationale:  "Least privilege is the same control",
+			},
+			"IAM-08": {
+				TargetIDs:  []string{"AC-2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.75,
+				Rationale:  "Access reviews are part of account management",
+			},
+			"IAM-14": {
+				TargetIDs:  []string{"IA-2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Strong authentication maps to identification and authentication",
+			},
+			"LOG-03": {
+				TargetIDs:  []string{"SI-4", "AU-6"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Security monitoring and alerting maps to system monitoring and audit review",
+			},
+			"LOG-08": {
+				TargetIDs:  []string{"AU-2", "AU-3"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Log records map to event logging and the content of audit records",
+			},
+			"MDS-02": {
+				TargetIDs:  []string{"CM-3"},
+				Type:       models.MappingRelated,
+				Confidence: 0.4,
+				Rationale:  "Protecting models from unauthorized modification relies on change control",
+			},
+			"MDS-04": {
+				TargetIDs:  []string{"SI-4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Model monitoring extends system monitoring to model behavior",
+			},
+			"SEF-03": {
+				TargetIDs:  []string{"CP-2"},
+				Type:       models.MappingRelated,
+				Confidence: 0.4,
+				Rationale:  "Incident response plans complement contingency planning",
+			},
+			"TVM-07": {
+				TargetIDs:  []string{"RA-5", "SI-5"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Vulnerability identification maps to vulnerability scanning and security advisories",
+			},
+		},
+
+		// CSA AICM -> ISO 42001
+		string(FrameworkCSAAICM) + "->" + string(FrameworkISO42001): {
+			"A&A-02": {
+				TargetIDs:  []string{"ISO42001-9.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.8,
+				Rationale:  "Independent assessments map to internal audit of the AI management system",
+			},
+			"A&A-05": {
+				TargetIDs:  []string{"ISO42001-10.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Managing audit findings maps to nonconformity and corrective action",
+			},
+			"AIS-04": {
+				TargetIDs:  []string{"ISO42001-8.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Secure design and development is part of the AI system lifecycle",
+			},
+			"CCC-03": {
+				TargetIDs:  []string{"ISO42001-6.3", "ISO42001-8.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Change management maps to planning of changes and the AI system lifecycle",
+			},
+			"DSP-05": {
+				TargetIDs:  []string{"ISO42001-8.5"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Data flow documentation maps to data for AI systems",
+			},
+			"DSP-07": {
+				TargetIDs:  []string{"ISO42001-A.7.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Data protection by design maps to privacy protection for AI data",
+			},
+			"DSP-17": {
+				TargetIDs:  []string{"ISO42001-A.7.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Sensitive data protection maps to privacy protection for AI data",
+			},
+			"GRC-01": {
+				TargetIDs:  []string{"ISO42001-5.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "The governance policy maps to the AI policy",
+			},
+			"GRC-02": {
+				TargetIDs:  []string{"ISO42001-6.1", "ISO42001-8.2"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "The risk management program maps to risk actions and AI system impact assessment",
+			},
+			"GRC-06": {
+				TargetIDs:  []string{"ISO42001-5.3"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Governance responsibilities map to organizational roles and responsibilities",
+			},
+			"HRS-11": {
+				TargetIDs:  []string{"ISO42001-7.2", "ISO42001-7.3"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Security awareness training maps to competence and awareness",
+			},
+			"IAM-05": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Least privilege is part of AI system security",
+			},
+			"LOG-03": {
+				TargetIDs:  []string{"ISO42001-9.1"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Security monitoring contributes to monitoring and measurement",
+			},
+			"MDS-01": {
+				TargetIDs:  []string{"ISO42001-8.4"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Model documentation maps to AI system documentation",
+			},
+			"MDS-02": {
+				TargetIDs:  []string{"ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Model integrity is part of AI system security",
+			},
+			"MDS-03": {
+				TargetIDs:  []string{"ISO42001-A.6.2", "ISO42001-A.4.4"},
+				Type:       models.MappingPartial,
+				Confidence: 0.6,
+				Rationale:  "Adversarial robustness maps to AI system reliability and security",
+			},
+			"MDS-04": {
+				TargetIDs:  []string{"ISO42001-9.1", "ISO42001-A.6.2"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Model monitoring maps to monitoring and AI system reliability",
+			},
+			"SEF-03": {
+				TargetIDs:  []string{"ISO42001-10.1"},
+				Type:       models.MappingRelated,
+				Confidence: 0.5,
+				Rationale:  "Incident response feeds nonconformity and corrective action",
+			},
+			"STA-01": {
+				TargetIDs:  []string{"ISO42001-8.6"},
+				Type:       models.MappingPartial,
+				Confidence: 0.7,
+				Rationale:  "Shared responsibility with providers is part of third-party considerations",
+			},
+			"STA-08": {
+				TargetIDs:  []string{"ISO42001-8.6"},
+				Type:       models.MappingExact,
+				Confidence: 0.85,
+				Rationale:  "Supply chain risk management maps to third-party considerations",
+			},
+		},
 	}
 
 	if m, ok := mappings[key]; ok {
@@ -0,0 +1,322 @@
+package controls
+
+import "github.com/agentguard/agentguard/internal/models"
+
+// getCSAAICMControls returns the control objectives of the Cloud Security
+// Alliance AI Controls Matrix (AICM) v1.0 that bear on AI agents and the
+// platforms that host them: governance and risk, identity and access, data
+// security, logging, incident response, supply chain, vulnerability
+// management and the AI-specific Model Security domain. Control statements
+// are paraphrased; datacenter, endpoint and interoperability domains are not
+// included. Load the full matrix as a catalog to assess against all of it.
+func getCSAAICMControls() []models.Control {
+	return []models.Control{
+		// A&A: Audit and Assurance
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "A&A-02",
+			Title:            "Independent Assessments",
+			Description:      "Conduct independent audit and assurance assessments of AI systems and services against relevant standards at least annually.",
+			Objectives:       []string{"Obtain independent assurance over AI controls", "Assess on a defined schedule"},
+			Activities:       []string{"Schedule independent assessments", "Include AI model and agent controls in audit scope"},
+			EvidenceTypes:    []string{"Audit reports", "Assessment schedule"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "A&A-05",
+			Title:            "Audit Management Process",
+			Description:      "Define and implement a process to manage audit findings, including remediation plans and tracking to closure.",
+			Objectives:       []string{"Track audit findings to closure"},
+			Activities:       []string{"Record findings with owners and due dates", "Review remediation progress"},
+			EvidenceTypes:    []string{"Findings register", "Remediation plans"},
+			ApplicableLayers: []string{"governance"},
+		},
+
+		// AIS: Application and Interface Security
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "AIS-04",
+			Title:            "Secure Application Design and Development",
+			Description:      "Design and develop AI applications, agents and their interfaces following a secure development lifecycle that addresses AI-specific risks.",
+			Objectives:       []string{"Build security into AI application design", "Address prompt injection and insecure output handling"},
+			Activities:       []string{"Threat model agents and their tools", "Apply secure coding standards to model integrations", "Review designs before release"},
+			EvidenceTypes:    []string{"Threat models", "Design reviews", "Secure development standard"},
+			ApplicableLayers: []string{"application"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "AIS-05",
+			Title:            "Automated Application Security Testing",
+			Description:      "Test AI applications and interfaces for security weaknesses, including adversarial and prompt injection testing, before release and on change.",
+			Objectives:       []string{"Detect weaknesses before release"},
+			Activities:       []string{"Run automated security tests in CI", "Run adversarial prompt and jailbreak tests"},
+			EvidenceTypes:    []string{"Test results", "CI pipeline configuration", "Red team reports"},
+			ApplicableLayers: []string{"application"},
+		},
+
+		// BCR: Business Continuity Management and Operational Resilience
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "BCR-08",
+			Title:            "Backup",
+			Description:      "Back up AI system data, models and configuration, and verify backups can be restored.",
+			Objectives:       []string{"Recover models and configuration after loss"},
+			Activities:       []string{"Back up model artifacts and agent configuration", "Test restores periodically"},
+			EvidenceTypes:    []string{"Backup configuration", "Restore test records"},
+			ApplicableLayers: []string{"infrastructure", "data"},
+		},
+
+		// CCC: Change Control and Configuration Management
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "CCC-03",
+			Title:            "Change Management Technology",
+			Description:      "Manage changes to AI models, prompts, policies and agent configurations through a controlled, approved and tracked process.",
+			Objectives:       []string{"Authorize and track changes", "Assess the impact of model changes"},
+			Activities:       []string{"Version models, prompts and policies", "Require approval before deploying changes"},
+			EvidenceTypes:    []string{"Change records", "Approval history", "Version control history"},
+			ApplicableLayers: []string{"system", "application"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "CCC-07",
+			Title:            "Detection of Baseline Deviation",
+			Description:      "Detect and act on deviations of AI systems and agents from their approved configuration baselines.",
+			Objectives:       []string{"Detect unauthorized configuration drift"},
+			Activities:       []string{"Define baseline agent configurations", "Alert on deviations from the baseline"},
+			EvidenceTypes:    []string{"Baseline definitions", "Drift alerts"},
+			ApplicableLayers: []string{"system"},
+		},
+
+		// CEK: Cryptography, Encryption and Key Management
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "CEK-03",
+			Title:            "Data Encryption",
+			Description:      "Protect AI data, prompts, model artifacts and telemetry at rest and in transit with cryptography.",
+			Objectives:       []string{"Encrypt sensitive AI data at rest and in transit"},
+			Activities:       []string{"Enforce TLS for model and tool traffic", "Encrypt stored prompts, payloads and models"},
+			EvidenceTypes:    []string{"Encryption configuration", "TLS settings"},
+			ApplicableLayers: []string{"data", "infrastructure"},
+		},
+
+		// DSP: Data Security and Privacy Lifecycle Management
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "DSP-05",
+			Title:            "Data Flow Documentation",
+			Description:      "Document the data flows of AI systems, including training data sources, prompts, retrieved context and tool outputs.",
+			Objectives:       []string{"Know where AI data comes from and goes"},
+			Activities:       []string{"Maintain data flow diagrams for agents and their tools", "Review flows on change"},
+			EvidenceTypes:    []string{"Data flow diagrams", "Data inventory"},
+			ApplicableLayers: []string{"data"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "DSP-07",
+			Title:            "Data Protection by Design and Default",
+			Description:      "Build data protection into AI systems by design, minimizing personal and sensitive data in prompts, context and outputs by default.",
+			Objectives:       []string{"Minimize sensitive data exposure to models"},
+			Activities:       []string{"Redact or pseudonymize sensitive fields before model calls", "Restrict the data agents can retrieve"},
+			EvidenceTypes:    []string{"Redaction configuration", "Data access policies"},
+			ApplicableLayers: []string{"data", "application"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "DSP-17",
+			Title:            "Sensitive Data Protection",
+			Description:      "Protect sensitive data processed by AI systems from unauthorized disclosure, including through model outputs and agent tool calls.",
+			Objectives:       []string{"Prevent sensitive data leakage through outputs and tools"},
+			Activities:       []string{"Filter outputs for sensitive data", "Block sensitive data from leaving through egress tools"},
+			EvidenceTypes:    []string{"Output filtering configuration", "Data loss prevention policies"},
+			ApplicableLayers: []string{"data", "application"},
+		},
+
+		// GRC: Governance, Risk and Compliance
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "GRC-01",
+			Title:            "Governance Program Policy and Procedures",
+			Description:      "Establish, approve and review an AI governance program with policies and procedures covering AI systems across their lifecycle.",
+			Objectives:       []string{"Govern AI systems under approved policy"},
+			Activities:       []string{"Publish an AI governance policy", "Review the policy at least annually"},
+			EvidenceTypes:    []string{"AI governance policy", "Policy review records"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "GRC-02",
+			Title:            "Risk Management Program",
+			Description:      "Establish a risk management program that identifies, assesses and treats the risks of AI systems.",
+			Objectives:       []string{"Identify and treat AI risks"},
+			Activities:       []string{"Maintain an AI risk register", "Assess risks of new agents and models", "Track risk treatment"},
+			EvidenceTypes:    []string{"Risk register", "Risk assessments", "Treatment plans"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "GRC-06",
+			Title:            "Governance Responsibility Model",
+			Description:      "Define and document roles and responsibilities for AI governance, including owners for each AI system.",
+			Objectives:       []string{"Assign accountability for AI systems"},
+			Activities:       []string{"Name an owner for each agent and model", "Document governance roles"},
+			EvidenceTypes:    []string{"RACI matrix", "AI system inventory with owners"},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+
+		// HRS: Human Resources
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "HRS-11",
+			Title:            "Security Awareness Training",
+			Description:      "Train personnel on the security and responsible use of AI systems, including AI-specific threats.",
+			Objectives:       []string{"Make personnel aware of AI risks"},
+			Activities:       []string{"Run AI security awareness training", "Track completion"},
+			EvidenceTypes:    []string{"Training materials", "Completion records"},
+			ApplicableLayers: []string{"organization"},
+		},
+
+		// IAM: Identity and Access Management
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "IAM-05",
+			Title:            "Least Privilege",
+			Description:      "Grant users, services and AI agents only the access and tool permissions they need.",
+			Objectives:       []string{"Limit agent permissions to what their tasks require"},
+			Activities:       []string{"Scope agent tool permissions", "Enforce tool allowlists with policy guardrails"},
+			EvidenceTypes:    []string{"Access policies", "Agent tool bindings"},
+			ApplicableLayers: []string{"application", "system"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "IAM-08",
+			Title:            "User Access Review",
+			Description:      "Review the access of users, services and AI agents periodically and remove what is no longer needed.",
+			Objectives:       []string{"Remove unneeded access"},
+			Activities:       []string{"Review agent permissions and tool bindings periodically", "Revoke unused access"},
+			EvidenceTypes:    []string{"Access review records"},
+			ApplicableLayers: []string{"system"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "IAM-14",
+			Title:            "Strong Authentication",
+			Description:      "Authenticate users, services and AI agents with strong mechanisms, including workload identity for agents.",
+			Objectives:       []string{"Verify the identity of every caller"},
+			Activities:       []string{"Use workload identity for agents", "Require multi-factor authentication for administrators"},
+			EvidenceTypes:    []string{"Authentication configuration", "Identity provider settings"},
+			ApplicableLayers: []string{"system"},
+		},
+
+		// LOG: Logging and Monitoring
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "LOG-03",
+			Title:            "Security Monitoring and Alerting",
+			Description:      "Monitor AI systems and agents for security events and anomalous behavior, and alert responsible personnel.",
+			Objectives:       []string{"Detect anomalous agent behavior"},
+			Activities:       []string{"Monitor agent tool calls and policy violations", "Alert on anomalies"},
+			EvidenceTypes:    []string{"Alert rules", "Monitoring dashboards"},
+			ApplicableLayers: []string{"system"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "LOG-08",
+			Title:            "Log Records",
+			Description:      "Record AI system activity, including prompts, model responses, tool calls and policy decisions, in logs protected from tampering.",
+			Objectives:       []string{"Keep a trustworthy record of AI activity"},
+			Activities:       []string{"Log agent invocations and decisions", "Protect logs from modification"},
+			EvidenceTypes:    []string{"Logging configuration", "Audit log samples"},
+			ApplicableLayers: []string{"system", "data"},
+		},
+
+		// MDS: Model Security
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "MDS-01",
+			Title:            "Model Documentation",
+			Description:      "Document AI models, including their purpose, provenance, training data, limitations and intended use.",
+			Objectives:       []string{"Know what each model is and where it came from"},
+			Activities:       []string{"Maintain model cards", "Record model provenance"},
+			EvidenceTypes:    []string{"Model cards", "Model inventory"},
+			ApplicableLayers: []string{"model"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "MDS-02",
+			Title:            "Model Integrity",
+			Description:      "Protect models and their artifacts from unauthorized modification and verify their integrity before use.",
+			Objectives:       []string{"Use only untampered models"},
+			Activities:       []string{"Sign and verify model artifacts", "Restrict write access to model registries"},
+			EvidenceTypes:    []string{"Artifact signatures", "Registry access policies"},
+			ApplicableLayers: []string{"model"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "MDS-03",
+			Title:            "Adversarial Robustness",
+			Description:      "Assess and improve the robustness of models and agents against adversarial inputs such as prompt injection, jailbreaks and evasion.",
+			Objectives:       []string{"Resist adversarial manipulation"},
+			Activities:       []string{"Red team models and agents", "Deploy input and output guardrails"},
+			EvidenceTypes:    []string{"Red team reports", "Guardrail configuration"},
+			ApplicableLayers: []string{"model", "application"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "MDS-04",
+			Title:            "Model Monitoring",
+			Description:      "Monitor models in production for drift, degraded performance and misuse.",
+			Objectives:       []string{"Detect drift and misuse in production"},
+			Activities:       []string{"Track model quality metrics", "Alert on drift and abuse patterns"},
+			EvidenceTypes:    []string{"Monitoring dashboards", "Drift reports"},
+			ApplicableLayers: []string{"model", "system"},
+		},
+
+		// SEF: Security Incident Management, E-Discovery and Cloud Forensics
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "SEF-03",
+			Title:            "Incident Response Plans",
+			Description:      "Maintain and test incident response plans that cover AI-specific incidents such as model compromise and agent misuse.",
+			Objectives:       []string{"Respond effectively to AI incidents"},
+			Activities:       []string{"Write playbooks for agent containment", "Exercise the plan periodically"},
+			EvidenceTypes:    []string{"Incident response plan", "Exercise records"},
+			ApplicableLayers: []string{"organization", "system"},
+		},
+
+		// STA: Supply Chain Management, Transparency and Accountability
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "STA-01",
+			Title:            "Shared Security Responsibility Model",
+			Description:      "Define the security responsibilities of model providers, platform providers and customers for AI services.",
+			Objectives:       []string{"Make provider and customer responsibilities explicit"},
+			Activities:       []string{"Document the shared responsibility model for each AI service", "Map inherited controls"},
+			EvidenceTypes:    []string{"Shared responsibility matrix", "Provider attestations"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "STA-08",
+			Title:            "Supply Chain Risk Management",
+			Description:      "Assess and manage the risks of third-party models, datasets, tools and AI service providers.",
+			Objectives:       []string{"Manage third-party AI risk"},
+			Activities:       []string{"Assess model and tool providers before adoption", "Reassess providers periodically"},
+			EvidenceTypes:    []string{"Vendor assessments", "Third-party inventory"},
+			ApplicableLayers: []string{"governance", "model"},
+		},
+
+		// TVM: Threat and Vulnerability Management
+		{
+			FrameworkID:      string(FrameworkCSAAICM),
+			ControlID:        "TVM-07",
+			Title:            "Vulnerability Identification",
+			Description:      "Identify vulnerabilities in AI systems, their dependencies and models, including through threat intelligence on AI attacks.",
+			Objectives:       []string{"Find vulnerabilities before attackers do"},
+			Activities:       []string{"Scan dependencies and model artifacts", "Track AI threat intelligence such as MITRE ATLAS"},
+			EvidenceTypes:    []string{"Scan results", "Threat intelligence reports"},
+			ApplicableLayers: []string{"system", "model"},
+		},
+	}
+}
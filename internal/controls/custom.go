@@ -0,0 +1,506 @@
+package controls
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// CustomFramework is a framework definition file in the data directory's
+// frameworks/ folder, in YAML or JSON. See docs/custom-frameworks.md for
+// the schema.
+type CustomFramework struct {
+	ID          string          `yaml:"id"`
+	Name        string          `yaml:"name"`
+	Version     string          `yaml:"version"`
+	Publisher   string          `yaml:"publisher"`
+	Description string          `yaml:"description"`
+	URL         string          `yaml:"url"`
+	Controls    []CustomControl `yaml:"controls"`
+	Crosswalks  []CrosswalkHint `yaml:"crosswalks"`
+}
+
+// CustomControl is a control in a custom framework. Parent makes it a
+// sub-control or enhancement of another control in the same file.
+type CustomControl struct {
+	ID               string   `yaml:"id"`
+	Title            string   `yaml:"title"`
+	Description      string   `yaml:"description"`
+	Family           string   `yaml:"family"`
+	Parent           string   `yaml:"parent"`
+	Objectives       []string `yaml:"objectives"`
+	Activities       []string `yaml:"activities"`
+	EvidenceTypes    []string `yaml:"evidence_types"`
+	ApplicableLayers []string `yaml:"applicable_layers"`
+}
+
+// CrosswalkHint maps a custom control to controls in another framework.
+type CrosswalkHint struct {
+	Control    string   `yaml:"control"`
+	Framework  string   `yaml:"framework"`
+	Targets    []string `yaml:"targets"`
+	Type       string   `yaml:"type"`
+	Confidence float64  `yaml:"confidence"`
+	Rationale  string   `yaml:"rationale"`
+}
+
+// FieldError is a problem at a position in a framework definition file.
+type FieldError struct {
+	File   string
+	Line   int
+	Column int
+	Path   string // e.g. controls[2].parent
+	Msg    string
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Path, e.Msg)
+}
+
+// ValidationError lists every problem found in a framework definition.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid framework definition:\n  " + strings.Join(msgs, "\n  ")
+}
+
+// frameworkIDPattern matches framework IDs, as the API accepts them.
+var frameworkIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}[a-z0-9]$`)
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindStrings
+	kindNumber
+	kindObjects
+	kindIgnored
+)
+
+type fieldSpec struct {
+	kind     fieldKind
+	required bool
+	items    map[string]fieldSpec
+}
+
+// customFrameworkSchema is the definition file schema. created_at and
+// updated_at are accepted, and ignored, for metadata files written before
+// definitions could carry controls.
+var customFrameworkSchema = map[string]fieldSpec{
+	"id":          {kind: kindString, required: true},
+	"name":        {kind: kindString, required: true},
+	"version":     {kind: kindString},
+	"publisher":   {kind: kindString},
+	"description": {kind: kindString},
+	"url":         {kind: kindString},
+	"created_at":  {kind: kindIgnored},
+	"updated_at":  {kind: kindIgnored},
+	"controls": {kind: kindObjects, items: map[string]fieldSpec{
+		"id":                {kind: kindString, required: true},
+		"title":             {kind: kindString, required: true},
+		"description":       {kind: kindString},
+		"family":            {kind: kindString},
+		"parent":            {kind: kindString},
+		"objectives":        {kind: kindStrings},
+		"activities":        {kind: kindStrings},
+		"evidence_types":    {kind: kindStrings},
+		"applicable_layers": {kind: kindStrings},
+	}},
+	"crosswalks": {kind: kindObjects, items: map[string]fieldSpec{
+		"control":    {kind: kindString, required: true},
+		"framework":  {kind: kindString, required: true},
+		"targets":    {kind: kindStrings, required: true},
+		"type":       {kind: kindString, required: true},
+		"confidence": {kind: kindNumber},
+		"rationale":  {kind: kindString},
+	}},
+}
+
+// customFile is a parsed definition with the positions later checks
+// report against.
+type customFile struct {
+	path string
+	def  CustomFramework
+	root *yaml.Node
+}
+
+// pos returns the position of a node under the root mapping, following
+// keys and sequence indexes, or of the deepest node found.
+func (f *customFile) pos(path string, steps ...any) FieldError {
+	n := f.root
+	for _, s := range steps {
+		var next *yaml.Node
+		switch s := s.(type) {
+		case string:
+			if n.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(n.Content); i += 2 {
+					if n.Content[i].Value == s {
+						next = n.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			if n.Kind == yaml.SequenceNode && s < len(n.Content) {
+				next = n.Content[s]
+			}
+		}
+		if next == nil {
+			break
+		}
+		n = next
+	}
+	return FieldError{File: f.path, Line: n.Line, Column: n.Column, Path: path}
+}
+
+// ParseCustomFramework parses and validates a framework definition. YAML
+// and JSON are both accepted. Every problem is reported with its line and
+// column in a *ValidationError.
+func ParseCustomFramework(path string, data []byte) (*CustomFramework, error) {
+	f, err := parseCustomFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return &f.def, nil
+}
+
+func parseCustomFile(path string, data []byte) (*customFile, error) {
+	var doc yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, &ValidationError{Errors: []FieldError{{File: path, Line: 1, Column: 1, Msg: "empty document"}}}
+	}
+	f := &customFile{path: path, root: doc.Content[0]}
+
+	var errs []FieldError
+	validateNode(f.path, f.root, customFrameworkSchema, "", &errs)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	if err := f.root.Decode(&f.def); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if errs := f.check(); len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	return f, nil
+}
+
+// validateNode checks a mapping against a schema: unknown keys, missing
+// required keys and value types.
+func validateNode(file string, n *yaml.Node, schema map[string]fieldSpec, path string, errs *[]FieldError) {
+	at := func(n *yaml.Node, p, msg string) {
+		*errs = append(*errs, FieldError{File: file, Line: n.Line, Column: n.Column, Path: p, Msg: msg})
+	}
+	if n.Kind != yaml.MappingNode {
+		at(n, path, "expected an object")
+		return
+	}
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		p := key.Value
+		if path != "" {
+			p = path + "." + key.Value
+		}
+		spec, ok := schema[key.Value]
+		if !ok {
+			at(key, p, "unknown field")
+			continue
+		}
+		if seen[key.Value] {
+			at(key, p, "duplicate field")
+		}
+		seen[key.Value] = true
+
+		switch spec.kind {
+		case kindString:
+			if val.Kind != yaml.ScalarNode || val.Tag == "!!null" {
+				at(val, p, "expected a string")
+			} else if spec.required && strings.TrimSpace(val.Value) == "" {
+				at(val, p, "must not be empty")
+			}
+		case kindNumber:
+			if val.Kind != yaml.ScalarNode || (val.Tag != "!!int" && val.Tag != "!!float") {
+				at(val, p, "expected a number")
+			}
+		case kindStrings:
+			if val.Kind != yaml.SequenceNode {
+				at(val, p, "expected a list of strings")
+				continue
+			}
+			if spec.required && len(val.Content) == 0 {
+				at(val, p, "must not be empty")
+			}
+			for j, item := range val.Content {
+				if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+					at(item, fmt.Sprintf("%s[%d]", p, j), "expected a string")
+				}
+			}
+		case kindObjects:
+			if val.Kind != yaml.SequenceNode {
+				at(val, p, "expected a list")
+				continue
+			}
+			for j, item := range val.Content {
+				validateNode(file, item, spec.items, fmt.Sprintf("%s[%d]", p, j), errs)
+			}
+		}
+	}
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if schema[k].required && !seen[k] {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			at(n, p, "required field missing")
+		}
+	}
+}
+
+// check validates a decoded definition: ID formats, unique controls, the
+// control hierarchy and crosswalk hints. Hint targets are checked once
+// every framework has loaded.
+func (f *customFile) check() []FieldError {
+	var errs []FieldError
+	fail := func(fe FieldError, msg string) {
+		fe.Msg = msg
+		errs = append(errs, fe)
+	}
+	def := &f.def
+	if !frameworkIDPattern.MatchString(def.ID) {
+		fail(f.pos("id", "id"), "must be 2-64 lowercase letters, digits, hyphens or underscores")
+	}
+
+	ids := make(map[string]int, len(def.Controls))
+	for i, c := range def.Controls {
+		p := fmt.Sprintf("controls[%d]", i)
+		if strings.ContainsAny(c.ID, " \t\n") {
+			fail(f.pos(p+".id", "controls", i, "id"), "must not contain whitespace")
+		}
+		key := strings.ToLower(c.ID)
+		if prev, dup := ids[key]; dup {
+			fail(f.pos(p+".id", "controls", i, "id"), fmt.Sprintf("duplicate control %s, first defined at controls[%d]", c.ID, prev))
+			continue
+		}
+		ids[key] = i
+	}
+	for i, c := range def.Controls {
+		if c.Parent == "" {
+			continue
+		}
+		p := fmt.Sprintf("controls[%d].parent", i)
+		if _, ok := ids[strings.ToLower(c.Parent)]; !ok {
+			fail(f.pos(p, "controls", i, "parent"), fmt.Sprintf("unknown control %s", c.Parent))
+			continue
+		}
+		// Walk up the hierarchy; revisiting a control means a cycle.
+		visited := map[string]bool{strings.ToLower(c.ID): true}
+		for parent := c.Parent; parent != ""; {
+			key := strings.ToLower(parent)
+			if visited[key] {
+				fail(f.pos(p, "controls", i, "parent"), fmt.Sprintf("parent cycle through %s", parent))
+				break
+			}
+			visited[key] = true
+			j, ok := ids[key]
+			if !ok {
+				break
+			}
+			parent = def.Controls[j].Parent
+		}
+	}
+
+	for i, h := range def.Crosswalks {
+		p := fmt.Sprintf("crosswalks[%d]", i)
+		if _, ok := ids[strings.ToLower(h.Control)]; !ok {
+			fail(f.pos(p+".control", "crosswalks", i, "control"), fmt.Sprintf("unknown control %s", h.Control))
+		}
+		switch models.MappingType(h.Type) {
+		case models.MappingExact, models.MappingPartial, models.MappingSuperset, models.MappingSubset, models.MappingRelated:
+		default:
+			fail(f.pos(p+".type", "crosswalks", i, "type"), "must be exact, partial, superset, subset or related")
+		}
+		if h.Confidence < 0 || h.Confidence > 1 {
+			fail(f.pos(p+".confidence", "crosswalks", i, "confidence"), "must be between 0 and 1")
+		}
+		if h.Framework == def.ID {
+			fail(f.pos(p+".framework", "crosswalks", i, "framework"), "must be another framework")
+		}
+	}
+	return errs
+}
+
+// controls converts the definition's controls.
+func (d *CustomFramework) controls() []models.Control {
+	out := make([]models.Control, 0, len(d.Controls))
+	for _, c := range d.Controls {
+		ctrl := models.Control{
+			FrameworkID:      d.ID,
+			ControlID:        c.ID,
+			Title:            c.Title,
+			Description:      c.Description,
+			Family:           c.Family,
+			Objectives:       orEmpty(c.Objectives),
+			Activities:       orEmpty(c.Activities),
+			EvidenceTypes:    orEmpty(c.EvidenceTypes),
+			ApplicableLayers: orEmpty(c.ApplicableLayers),
+		}
+		if c.Parent != "" {
+			parent := c.Parent
+			ctrl.ParentControlID = &parent
+		}
+		out = append(out, ctrl)
+	}
+	return out
+}
+
+func orEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// loadCustomFile loads a framework definition. A file without controls
+// for a framework that is already loaded only replaces its metadata. A
+// file named <id>@<version> adds that version without replacing the
+// current one.
+func (s *Service) loadCustomFile(path string) (*customFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseCustomFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+	def := &f.def
+	id := FrameworkID(def.ID)
+	_, known := s.frameworks[id]
+	fileID, version := splitVersionedName(path)
+	if len(def.Controls) == 0 && (!known || version != "") {
+		fe := f.pos("controls")
+		fe.Path, fe.Msg = "controls", "required for a framework that is not built in"
+		if version != "" {
+			fe.Msg = "required for a framework version"
+		}
+		return nil, &ValidationError{Errors: []FieldError{fe}}
+	}
+	if version != "" && fileID != id {
+		fe := f.pos("id", "id")
+		fe.Msg = fmt.Sprintf("does not match the file name's framework %s", fileID)
+		return nil, &ValidationError{Errors: []FieldError{fe}}
+	}
+
+	fw := &models.Framework{
+		ID:          def.ID,
+		Name:        def.Name,
+		Version:     def.Version,
+		Publisher:   def.Publisher,
+		Description: def.Description,
+		URL:         def.URL,
+	}
+	if version != "" {
+		fw.Version = version
+		s.addVersion(fw, def.controls())
+		// Hints describe the current version only.
+		f.def.Crosswalks = nil
+		return f, nil
+	}
+	if len(def.Controls) > 0 {
+		s.archive(id)
+		s.controls[id] = def.controls()
+	}
+	s.frameworks[id] = fw
+	return f, nil
+}
+
+// resolveHints checks crosswalk hints against the loaded frameworks and
+// adds them as crosswalks, in both directions.
+func (s *Service) resolveHints(files []*customFile) error {
+	var errs []FieldError
+	for _, f := range files {
+		var added []models.Crosswalk
+		for i, h := range f.def.Crosswalks {
+			p := fmt.Sprintf("crosswalks[%d]", i)
+			target := FrameworkID(h.Framework)
+			targetControls, ok := s.controls[target]
+			if !ok {
+				fe := f.pos(p+".framework", "crosswalks", i, "framework")
+				fe.Msg = fmt.Sprintf("unknown framework %s", h.Framework)
+				errs = append(errs, fe)
+				continue
+			}
+			known := make(map[string]string, len(targetControls))
+			for _, c := range targetControls {
+				known[strings.ToLower(c.ControlID)] = c.ControlID
+			}
+			for j, t := range h.Targets {
+				id, ok := known[strings.ToLower(t)]
+				if !ok {
+					fe := f.pos(fmt.Sprintf("%s.targets[%d]", p, j), "crosswalks", i, "targets", j)
+					fe.Msg = fmt.Sprintf("unknown %s control %s", h.Framework, t)
+					errs = append(errs, fe)
+					continue
+				}
+				confidence := h.Confidence
+				if confidence == 0 {
+					confidence = 0.5
+				}
+				xw := models.Crosswalk{
+					SourceFrameworkID: f.def.ID,
+					SourceControlID:   h.Control,
+					TargetFrameworkID: h.Framework,
+					TargetControlID:   id,
+					MappingType:       models.MappingType(h.Type),
+					Confidence:        confidence,
+					Rationale:         h.Rationale,
+					Gaps:              []string{},
+					Supplements:       []string{},
+					EvidenceMapping:   []string{},
+				}
+				// The reverse mapping, so gap analysis works in both
+				// directions.
+				rev := xw
+				rev.SourceFrameworkID, rev.TargetFrameworkID = xw.TargetFrameworkID, xw.SourceFrameworkID
+				rev.SourceControlID, rev.TargetControlID = xw.TargetControlID, xw.SourceControlID
+				switch xw.MappingType {
+				case models.MappingSuperset:
+					rev.MappingType = models.MappingSubset
+				case models.MappingSubset:
+					rev.MappingType = models.MappingSuperset
+				}
+				added = append(added, xw, rev)
+			}
+		}
+		s.crosswalks = append(s.crosswalks, added...)
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
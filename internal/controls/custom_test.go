@@ -0,0 +1,135 @@
+package controls_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestCustomFramework(t *testing.T) {
+	ga, err := controls.NewGapAnalyzer("testdata")
+	if err != nil {
+		t.Fatalf("loading custom framework: %v", err)
+	}
+	fw, ok := ga.Framework("acme-ai-policy")
+	if !ok {
+		t.Fatal("acme-ai-policy not loaded")
+	}
+	if fw.Publisher != "ACME Corp" || fw.Version != "2026.1" {
+		t.Errorf("framework = %+v", fw)
+	}
+
+	list, ok := ga.Controls("acme-ai-policy")
+	if !ok || len(list) != 4 {
+		t.Fatalf("controls = %d, want 4", len(list))
+	}
+	if p := list[2].ParentControlID; p == nil || *p != "ACME-1.1" {
+		t.Errorf("ACME-1.1.a parent = %v, want ACME-1.1", p)
+	}
+
+	var out strings.Builder
+	if err := ga.ListControls(&out, "acme-ai-policy"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "\n      ACME-1.1.a") {
+		t.Errorf("nested control not indented by depth:\n%s", out.String())
+	}
+
+	xws, err := ga.Crosswalks("acme-ai-policy", "nist-ai-rmf", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(xws) != 1 || xws[0].TargetControlID != "GOVERN-1" || xws[0].MappingType != models.MappingSuperset {
+		t.Errorf("crosswalks = %+v", xws)
+	}
+	rev, err := ga.Crosswalks("nist-ai-rmf", "acme-ai-policy", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rev) != 1 || rev[0].SourceControlID != "GOVERN-1" || rev[0].MappingType != models.MappingSubset {
+		t.Errorf("reverse crosswalks = %+v", rev)
+	}
+}
+
+func TestCustomFrameworkValidation(t *testing.T) {
+	_, err := controls.NewService("testdata/invalid")
+	var verr *controls.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want a ValidationError", err)
+	}
+
+	want := []string{
+		"broken.yaml:3:1: owner: unknown field",
+		"broken.yaml:8:13: controls[1].family: expected a string",
+		"broken.yaml:7:5: controls[1].title: required field missing",
+		"broken.yaml:12:14: crosswalks[0].targets: expected a list of strings",
+	}
+	got := err.Error()
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("error missing %q:\n%s", w, got)
+		}
+	}
+	if len(verr.Errors) != len(want) {
+		t.Errorf("got %d errors, want %d:\n%s", len(verr.Errors), len(want), got)
+	}
+}
+
+func TestParseCustomFramework(t *testing.T) {
+	data := []byte(`id: Broken Framework
+name: Broken
+controls:
+  - id: B-1
+    title: First
+  - id: B-1
+    title: Duplicate
+  - id: B-2
+    title: Orphan
+    parent: B-9
+  - id: B-3
+    title: Loop
+    parent: B-4
+  - id: B-4
+    title: Loop
+    parent: B-3
+crosswalks:
+  - control: B-1
+    framework: nist-ai-rmf
+    targets: [GOVERN-1]
+    type: equivalent
+    confidence: 2
+`)
+	_, err := controls.ParseCustomFramework("broken.yaml", data)
+	var verr *controls.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want a ValidationError", err)
+	}
+
+	want := []string{
+		"broken.yaml:1:5: id: must be 2-64 lowercase letters",
+		"broken.yaml:6:9: controls[1].id: duplicate control B-1, first defined at controls[0]",
+		"broken.yaml:10:13: controls[2].parent: unknown control B-9",
+		"broken.yaml:13:13: controls[3].parent: parent cycle through",
+		"broken.yaml:16:13: controls[4].parent: parent cycle through",
+		"broken.yaml:21:11: crosswalks[0].type: must be exact, partial",
+		"broken.yaml:22:17: crosswalks[0].confidence: must be between 0 and 1",
+	}
+	got := err.Error()
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("error missing %q:\n%s", w, got)
+		}
+	}
+	if len(verr.Errors) != len(want) {
+		t.Errorf("got %d errors, want %d:\n%s", len(verr.Errors), len(want), got)
+	}
+
+	// JSON is accepted too, with the same positions.
+	_, err = controls.ParseCustomFramework("fw.json", []byte("{\n  \"id\": \"json-fw\",\n  \"name\": [\"x\"]\n}"))
+	if err == nil || !strings.Contains(err.Error(), "fw.json:3:11: name: expected a string") {
+		t.Errorf("json err = %v", err)
+	}
+}
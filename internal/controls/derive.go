@@ -0,0 +1,150 @@
+package controls
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// minDerivedConfidence drops derived mappings too weak to be worth
+// reviewing.
+const minDerivedConfidence = 0.3
+
+// CrosswalkLookup returns the direct mappings from one framework to
+// another.
+type CrosswalkLookup func(source, target FrameworkID) ([]models.Crosswalk, error)
+
+// DeriveCrosswalks infers source→target mappings by chaining direct mappings
+// through each other loaded framework: if A maps to M and M maps to B, A
+// maps to B. See InferCrosswalks.
+func (s *Service) DeriveCrosswalks(source, target FrameworkID) ([]models.Crosswalk, error) {
+	return InferCrosswalks(source, target, s.frameworkIDs(), s.directCrosswalks)
+}
+
+// InferCrosswalks infers source→target mappings through each pivot
+// framework, such as NIST 800-53 between SOC 2 and ISO 42001. A hop may
+// follow a mapping in either direction; mappings only recorded the other
+// way are inverted. Mapping types are composed along the chain and the
+// confidence is the product of both hops, halved when composition leaves
+// only a related mapping between two containment links. Pairs of controls
+// with a direct mapping are skipped; the rest are returned strongest first,
+// each flagged Derived.
+func InferCrosswalks(source, target FrameworkID, pivots []FrameworkID, lookup CrosswalkLookup) ([]models.Crosswalk, error) {
+	direct, err := lookup(source, target)
+	if err != nil {
+		return nil, err
+	}
+	mapped := make(map[[2]string]bool, len(direct))
+	for _, xw := range direct {
+		mapped[[2]string{strings.ToLower(xw.SourceControlID), strings.ToLower(xw.TargetControlID)}] = true
+	}
+
+	best := make(map[[2]string]models.Crosswalk)
+	for _, via := range pivots {
+		if via == source || via == target {
+			continue
+		}
+		first := linkedCrosswalks(lookup, source, via)
+		if len(first) == 0 {
+			continue
+		}
+		second := linkedCrosswalks(lookup, via, target)
+		if len(second) == 0 {
+			continue
+		}
+		next := make(map[string][]models.Crosswalk)
+		for _, xw := range second {
+			key := strings.ToLower(xw.SourceControlID)
+			next[key] = append(next[key], xw)
+		}
+
+		for _, a := range first {
+			for _, b := range next[strings.ToLower(a.TargetControlID)] {
+				key := [2]string{strings.ToLower(a.SourceControlID), strings.ToLower(b.TargetControlID)}
+				if mapped[key] {
+					continue
+				}
+				mappingType := composeMappingType(a.MappingType, b.MappingType)
+				confidence := a.Confidence * b.Confidence
+				if mappingType == models.MappingRelated && a.MappingType != models.MappingRelated && b.MappingType != models.MappingRelated {
+					confidence /= 2
+				}
+				if confidence < minDerivedConfidence {
+					continue
+				}
+				if prev, ok := best[key]; ok && prev.Confidence >= confidence {
+					continue
+				}
+				best[key] = models.Crosswalk{
+					SourceFrameworkID: string(source),
+					SourceControlID:   a.SourceControlID,
+					TargetFrameworkID: string(target),
+					TargetControlID:   b.TargetControlID,
+					MappingType:       mappingType,
+					Confidence:        confidence,
+					Rationale:         "Derived via " + string(via) + " " + a.TargetControlID,
+					Derived:           true,
+					Via:               []string{string(via) + ":" + a.TargetControlID},
+				}
+			}
+		}
+	}
+
+	out := make([]models.Crosswalk, 0, len(best))
+	for _, xw := range best {
+		out = append(out, xw)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Confidence != out[j].Confidence {
+			return out[i].Confidence > out[j].Confidence
+		}
+		if out[i].SourceControlID != out[j].SourceControlID {
+			return out[i].SourceControlID < out[j].SourceControlID
+		}
+		return out[i].TargetControlID < out[j].TargetControlID
+	})
+	return out, nil
+}
+
+// linkedCrosswalks returns the source→target mappings, or the inverted
+// target→source mappings when only those are recorded. Lookup failures
+// count as no mappings.
+func linkedCrosswalks(lookup CrosswalkLookup, source, target FrameworkID) []models.Crosswalk {
+	if forward, err := lookup(source, target); err == nil && len(forward) > 0 {
+		return forward
+	}
+	reverse, err := lookup(target, source)
+	if err != nil {
+		return nil
+	}
+	out := make([]models.Crosswalk, len(reverse))
+	for i, xw := range reverse {
+		out[i] = xw
+		out[i].SourceFrameworkID, out[i].TargetFrameworkID = xw.TargetFrameworkID, xw.SourceFrameworkID
+		out[i].SourceControlID, out[i].TargetControlID = xw.TargetControlID, xw.SourceControlID
+		out[i].MappingType = invertMappingType(xw.MappingType)
+	}
+	return out
+}
+
+// composeMappingType is the relationship implied by chaining two mappings.
+// Exact links preserve the other side; containment survives only when both
+// links point the same way; anything involving a related link is related.
+func composeMappingType(a, b models.MappingType) models.MappingType {
+	switch {
+	case a == models.MappingRelated || b == models.MappingRelated:
+		return models.MappingRelated
+	case a == models.MappingExact:
+		return b
+	case b == models.MappingExact:
+		return a
+	case a == b && (a == models.MappingSuperset || a == models.MappingSubset):
+		return a
+	case a == models.MappingPartial || b == models.MappingPartial:
+		return models.MappingPartial
+	default:
+		// superset then subset, or subset then superset: overlap is unknown.
+		return models.MappingRelated
+	}
+}
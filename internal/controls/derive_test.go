@@ -0,0 +1,109 @@
+package controls_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestDeriveCrosswalks(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+
+	derived, err := analyzer.DeriveCrosswalks("nist-ai-rmf", "nist-800-53")
+	if err != nil {
+		t.Fatalf("DeriveCrosswalks: %v", err)
+	}
+	if len(derived) == 0 {
+		t.Fatal("no mappings derived through iso-42001")
+	}
+	for _, xw := range derived {
+		if !xw.Derived || len(xw.Via) != 1 || !strings.HasPrefix(xw.Via[0], "iso-42001:") {
+			t.Errorf("mapping %s -> %s = %+v, want derived via iso-42001", xw.SourceControlID, xw.TargetControlID, xw)
+		}
+		if xw.Confidence <= 0 || xw.Confidence >= 1 {
+			t.Errorf("confidence %v not a product of two link confidences", xw.Confidence)
+		}
+	}
+	// MAP-2 maps to ISO42001-8.2 (exact, 0.9), which maps to CM-4 (exact,
+	// 0.9); MAP-2 has no direct CM-4 mapping.
+	var found bool
+	for _, xw := range derived {
+		if xw.SourceControlID == "MAP-2" && xw.TargetControlID == "CM-4" {
+			found = true
+			if xw.MappingType != models.MappingExact || xw.Confidence < 0.80 || xw.Confidence > 0.82 {
+				t.Errorf("MAP-2 -> CM-4 = %+v, want exact at 0.81", xw)
+			}
+		}
+	}
+	if !found {
+		t.Error("MAP-2 -> CM-4 not derived")
+	}
+
+	if _, err := analyzer.DeriveCrosswalks("nist-ai-rmf", "unknown"); err == nil {
+		t.Error("unknown framework accepted")
+	}
+}
+
+func TestInferCrosswalks(t *testing.T) {
+	// SOC 2 and CSA AICM both map into NIST 800-53, so the second hop
+	// follows the CSA mappings backwards.
+	stored := map[string][]models.Crosswalk{
+		"soc2->nist-800-53": {
+			{SourceControlID: "CC6.1", TargetControlID: "AC-2", MappingType: models.MappingExact, Confidence: 0.9},
+			{SourceControlID: "CC7.2", TargetControlID: "SI-4", MappingType: models.MappingSuperset, Confidence: 0.8},
+		},
+		"csa-aicm->nist-800-53": {
+			{SourceControlID: "IAM-01", TargetControlID: "AC-2", MappingType: models.MappingSubset, Confidence: 0.8},
+			{SourceControlID: "LOG-05", TargetControlID: "SI-4", MappingType: models.MappingSuperset, Confidence: 0.9},
+		},
+	}
+	lookup := func(source, target controls.FrameworkID) ([]models.Crosswalk, error) {
+		return stored[string(source)+"->"+string(target)], nil
+	}
+
+	derived, err := controls.InferCrosswalks("soc2", "csa-aicm", []controls.FrameworkID{"nist-800-53"}, lookup)
+	if err != nil {
+		t.Fatalf("InferCrosswalks: %v", err)
+	}
+	want := map[string]struct {
+		mappingType models.MappingType
+		confidence  float64
+	}{
+		// exact, then subset inverted to superset
+		"CC6.1->IAM-01": {models.MappingSuperset, 0.72},
+		// superset, then superset inverted to subset: overlap unknown, so
+		// the confidence is halved
+		"CC7.2->LOG-05": {models.MappingRelated, 0.36},
+	}
+	if len(derived) != len(want) {
+		t.Fatalf("derived %d mappings, want %d: %+v", len(derived), len(want), derived)
+	}
+	for _, xw := range derived {
+		key := xw.SourceControlID + "->" + xw.TargetControlID
+		w, ok := want[key]
+		if !ok {
+			t.Errorf("unexpected mapping %s", key)
+			continue
+		}
+		if xw.MappingType != w.mappingType || xw.Confidence < w.confidence-0.001 || xw.Confidence > w.confidence+0.001 {
+			t.Errorf("%s = %s at %.2f, want %s at %.2f", key, xw.MappingType, xw.Confidence, w.mappingType, w.confidence)
+		}
+		if !xw.Derived || len(xw.Via) != 1 || !strings.HasPrefix(xw.Via[0], "nist-800-53:") {
+			t.Errorf("%s not derived via nist-800-53: %+v", key, xw)
+		}
+	}
+
+	// The built-in catalog derives SOC 2 to CSA AICM the same way.
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	if derived, err := analyzer.DeriveCrosswalks("soc2", "csa-aicm"); err != nil || len(derived) == 0 {
+		t.Errorf("DeriveCrosswalks(soc2, csa-aicm) = %d mappings, %v; want some", len(derived), err)
+	}
+}
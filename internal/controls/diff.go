@@ -0,0 +1,187 @@
+package controls
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// CatalogDiff is the difference between two versions of a framework's
+// catalog, matched by control ID.
+type CatalogDiff struct {
+	Framework   string           `json:"framework"`
+	FromVersion string           `json:"from_version"`
+	ToVersion   string           `json:"to_version"`
+	Added       []models.Control `json:"added"`
+	Removed     []models.Control `json:"removed"`
+	Changed     []ControlChange  `json:"changed"`
+	Unchanged   int              `json:"unchanged"`
+	// Affected lists implemented controls that were removed or changed,
+	// and so need re-assessing. Set by MarkAffected.
+	Affected []string `json:"affected,omitempty"`
+}
+
+// ControlChange is a control present in both versions whose definition
+// changed.
+type ControlChange struct {
+	ControlID string         `json:"control_id"`
+	Fields    []string       `json:"fields"`
+	From      models.Control `json:"from"`
+	To        models.Control `json:"to"`
+}
+
+// DiffVersions compares two versions of a framework.
+func DiffVersions(from, to *models.FrameworkVersion) *CatalogDiff {
+	diff := &CatalogDiff{
+		Framework:   to.FrameworkID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Added:       []models.Control{},
+		Removed:     []models.Control{},
+		Changed:     []ControlChange{},
+	}
+
+	old := make(map[string]models.Control, len(from.Controls))
+	for _, c := range from.Controls {
+		old[strings.ToLower(c.ControlID)] = c
+	}
+	seen := make(map[string]bool, len(to.Controls))
+	for _, c := range to.Controls {
+		key := strings.ToLower(c.ControlID)
+		seen[key] = true
+		prev, ok := old[key]
+		if !ok {
+			diff.Added = append(diff.Added, c)
+			continue
+		}
+		if fields := changedFields(prev, c); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ControlChange{ControlID: c.ControlID, Fields: fields, From: prev, To: c})
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for _, c := range from.Controls {
+		if !seen[strings.ToLower(c.ControlID)] {
+			diff.Removed = append(diff.Removed, c)
+		}
+	}
+	return diff
+}
+
+// changedFields lists the fields that differ between two definitions of a
+// control. Storage IDs and timestamps are ignored.
+func changedFields(a, b models.Control) []string {
+	var fields []string
+	if a.Title != b.Title {
+		fields = append(fields, "title")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "description")
+	}
+	if a.Family != b.Family {
+		fields = append(fields, "family")
+	}
+	if deref(a.ParentControlID) != deref(b.ParentControlID) {
+		fields = append(fields, "parent_control_id")
+	}
+	if !slices.Equal(a.Objectives, b.Objectives) {
+		fields = append(fields, "objectives")
+	}
+	if !slices.Equal(a.Activities, b.Activities) {
+		fields = append(fields, "activities")
+	}
+	if !slices.Equal(a.EvidenceTypes, b.EvidenceTypes) {
+		fields = append(fields, "evidence_types")
+	}
+	if !slices.Equal(a.ApplicableLayers, b.ApplicableLayers) {
+		fields = append(fields, "applicable_layers")
+	}
+	return fields
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// MarkAffected records which of an assessment's implemented controls were
+// removed or changed, so its evidence can be re-baselined.
+func (d *CatalogDiff) MarkAffected(implemented []string) {
+	touched := make(map[string]bool, len(d.Removed)+len(d.Changed))
+	for _, c := range d.Removed {
+		touched[strings.ToLower(c.ControlID)] = true
+	}
+	for _, c := range d.Changed {
+		touched[strings.ToLower(c.ControlID)] = true
+	}
+	d.Affected = []string{}
+	for _, id := range implemented {
+		if touched[strings.ToLower(id)] {
+			d.Affected = append(d.Affected, id)
+		}
+	}
+}
+
+// Diff compares two versions of a loaded framework; an empty version is
+// the current one.
+func (g *GapAnalyzer) Diff(framework, from, to string) (*CatalogDiff, error) {
+	a, err := g.service.GetVersion(FrameworkID(framework), from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := g.service.GetVersion(FrameworkID(framework), to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffVersions(a, b), nil
+}
+
+// Versions returns the loaded versions of a framework, oldest first.
+func (g *GapAnalyzer) Versions(framework string) ([]models.FrameworkVersion, error) {
+	return g.service.ListVersions(FrameworkID(framework))
+}
+
+// Version returns a loaded version of a framework with its controls; an
+// empty version is the current one.
+func (g *GapAnalyzer) Version(framework, version string) (*models.FrameworkVersion, error) {
+	return g.service.GetVersion(FrameworkID(framework), version)
+}
+
+// PrintDiff writes a catalog diff as text.
+func (g *GapAnalyzer) PrintDiff(w io.Writer, d *CatalogDiff) {
+	fmt.Fprintf(w, "\n%s %s → %s\n", d.Framework, d.FromVersion, d.ToVersion)
+	fmt.Fprintf(w, "══════════════════════════════\n")
+	fmt.Fprintf(w, "%d added, %d removed, %d changed, %d unchanged\n",
+		len(d.Added), len(d.Removed), len(d.Changed), d.Unchanged)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(d.Added) > 0 {
+		fmt.Fprintf(tw, "\nAdded\n")
+		for _, c := range d.Added {
+			fmt.Fprintf(tw, "  + %s\t%s\n", c.ControlID, c.Title)
+		}
+	}
+	if len(d.Removed) > 0 {
+		fmt.Fprintf(tw, "\nRemoved\n")
+		for _, c := range d.Removed {
+			fmt.Fprintf(tw, "  - %s\t%s\n", c.ControlID, c.Title)
+		}
+	}
+	if len(d.Changed) > 0 {
+		fmt.Fprintf(tw, "\nChanged\n")
+		for _, c := range d.Changed {
+			fmt.Fprintf(tw, "  ~ %s\t%s\t(%s)\n", c.ControlID, c.To.Title, strings.Join(c.Fields, ", "))
+		}
+	}
+	tw.Flush()
+	if d.Affected != nil {
+		fmt.Fprintf(w, "\nImplemented controls to re-assess: %s\n", listOrNone(d.Affected))
+	}
+	fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,261 @@
+package controls
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/rs/zerolog/log"
+)
+
+// DispositionExpiryNotice is how long before a risk acceptance expires its
+// approver is reminded to renew or close it.
+const DispositionExpiryNotice = 14 * 24 * time.Hour
+
+// ValidDisposition reports whether d is a known disposition.
+func ValidDisposition(d models.Disposition) bool {
+	switch d {
+	case models.DispositionRemediate, models.DispositionAccept, models.DispositionTransfer:
+		return true
+	}
+	return false
+}
+
+// ValidateDisposition checks a disposition before it is stored. Accepted
+// and transferred risks need an approver, a justification and an expiry
+// after now; a transfer also names who carries the risk.
+func ValidateDisposition(d *models.GapDisposition, now time.Time) error {
+	if !ValidDisposition(d.Disposition) {
+		return fmt.Errorf("unknown disposition %q: use remediate, accept or transfer", d.Disposition)
+	}
+	if d.ReviewIntervalDays < 0 {
+		return fmt.Errorf("review_interval_days must not be negative")
+	}
+	if d.Disposition == models.DispositionRemediate {
+		return nil
+	}
+	switch {
+	case strings.TrimSpace(d.Approver) == "":
+		return fmt.Errorf("%s needs an approver", d.Disposition)
+	case strings.TrimSpace(d.Justification) == "":
+		return fmt.Errorf("%s needs a justification", d.Disposition)
+	case d.ExpiresAt == nil:
+		return fmt.Errorf("%s needs an expires_at", d.Disposition)
+	case !d.ExpiresAt.After(now):
+		return fmt.Errorf("expires_at must be in the future")
+	case d.Disposition == models.DispositionTransfer && strings.TrimSpace(d.TransferredTo) == "":
+		return fmt.Errorf("transfer needs transferred_to")
+	}
+	return nil
+}
+
+// DispositionActive reports whether a disposition is in effect at now.
+// Remediation never expires; an accepted or transferred risk is in effect
+// until its expiry.
+func DispositionActive(d *models.GapDisposition, now time.Time) bool {
+	if d.Disposition == models.DispositionRemediate {
+		return true
+	}
+	return d.ExpiresAt != nil && now.Before(*d.ExpiresAt)
+}
+
+// RiskAccepted reports whether the gap's risk is accepted or transferred
+// by a disposition in effect, rather than open for remediation.
+func (d *GapDetail) RiskAccepted() bool {
+	return d.Disposition != nil && d.Disposition.Disposition != models.DispositionRemediate && !d.DispositionExpired
+}
+
+// AnalysisDispositions returns an organization's decisions on a
+// framework's gaps for a gap analysis. repo may be nil. A failure to load
+// them is logged, and the analysis goes ahead treating every gap as open.
+func AnalysisDispositions(ctx context.Context, repo repository.GapDispositionRepository, org, framework string) []models.GapDisposition {
+	if repo == nil {
+		return nil
+	}
+	list, err := repo.List(ctx, org, framework)
+	if err != nil {
+		log.Warn().Err(err).Str("org_id", org).Str("framework_id", framework).Msg("failed to load gap dispositions")
+		return nil
+	}
+	return list
+}
+
+// applyDispositions attaches to each gap the framework's disposition of
+// its control, as of now.
+func applyDispositions(gaps []GapDetail, framework string, dispositions []models.GapDisposition, now time.Time) {
+	byControl := make(map[string]models.GapDisposition, len(dispositions))
+	for _, d := range dispositions {
+		if d.FrameworkID == framework {
+			byControl[strings.ToLower(d.ControlID)] = d
+		}
+	}
+	for i := range gaps {
+		d, ok := byControl[strings.ToLower(gaps[i].ControlID)]
+		if !ok {
+			continue
+		}
+		gaps[i].Disposition = &d
+		gaps[i].DispositionExpired = !DispositionActive(&d, now)
+	}
+}
+
+// Reasons a disposition's approver is reminded.
+const (
+	ReviewDue      = "review_due"
+	ReviewExpiring = "expiring"
+	ReviewExpired  = "expired"
+)
+
+// DispositionReminder asks a risk acceptance's approver to re-review it.
+type DispositionReminder struct {
+	OrganizationID string             `json:"organization_id"`
+	DispositionID  string             `json:"disposition_id"`
+	FrameworkID    string             `json:"framework_id"`
+	ControlID      string             `json:"control_id"`
+	Disposition    models.Disposition `json:"disposition"`
+	Approver       string             `json:"approver"`
+	ExpiresAt      *time.Time         `json:"expires_at,omitempty"`
+	// Reason is review_due, expiring or expired.
+	Reason string `json:"reason"`
+}
+
+// DueDispositionReviews returns a reminder for each accepted or transferred
+// risk that reached a review point since it was last reminded: its review
+// interval elapsing since it was last reviewed or created, its expiry
+// coming within DispositionExpiryNotice, or its expiry passing. Only the
+// latest point reached is reminded of.
+func DueDispositionReviews(dispositions []models.GapDisposition, now time.Time) []DispositionReminder {
+	var reminders []DispositionReminder
+	for _, d := range dispositions {
+		if d.Disposition == models.DispositionRemediate {
+			continue
+		}
+		var due time.Time
+		reason := ""
+		reached := func(at time.Time, why string) {
+			if !at.After(now) && !at.Before(due) {
+				due, reason = at, why
+			}
+		}
+		if d.ReviewIntervalDays > 0 {
+			last := d.CreatedAt
+			if d.ReviewedAt != nil {
+				last = *d.ReviewedAt
+			}
+			reached(last.AddDate(0, 0, d.ReviewIntervalDays), ReviewDue)
+		}
+		if d.ExpiresAt != nil {
+			reached(d.ExpiresAt.Add(-DispositionExpiryNotice), ReviewExpiring)
+			reached(*d.ExpiresAt, ReviewExpired)
+		}
+		if reason == "" || (d.LastRemindedAt != nil && !d.LastRemindedAt.Before(due)) {
+			continue
+		}
+		reminders = append(reminders, DispositionReminder{
+			OrganizationID: d.OrganizationID,
+			DispositionID:  d.ID,
+			FrameworkID:    d.FrameworkID,
+			ControlID:      d.ControlID,
+			Disposition:    d.Disposition,
+			Approver:       d.Approver,
+			ExpiresAt:      d.ExpiresAt,
+			Reason:         reason,
+		})
+	}
+	sort.Slice(reminders, func(i, j int) bool {
+		if reminders[i].OrganizationID != reminders[j].OrganizationID {
+			return reminders[i].OrganizationID < reminders[j].OrganizationID
+		}
+		return reminders[i].FrameworkID+":"+reminders[i].ControlID < reminders[j].FrameworkID+":"+reminders[j].ControlID
+	})
+	return reminders
+}
+
+// DispositionReminders sends due re-review reminders for accepted and
+// transferred risks on a schedule and records them on the dispositions.
+type DispositionReminders struct {
+	repo repository.GapDispositionRepository
+	// webhook receives a JSON POST per reminder. When nil, reminders are
+	// logged.
+	webhook *webhook.Sender
+	now     func() time.Time
+}
+
+// NewDispositionReminders creates a reminder scheduler. hook may be nil.
+func NewDispositionReminders(repo repository.GapDispositionRepository, hook *webhook.Sender) *DispositionReminders {
+	return &DispositionReminders{
+		repo:    repo,
+		webhook: hook,
+		now:     time.Now,
+	}
+}
+
+// Run sends due reminders every interval until ctx is cancelled.
+func (s *DispositionReminders) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SendDue(ctx); err != nil {
+				log.Error().Err(err).Msg("gap disposition reminders failed")
+			}
+		}
+	}
+}
+
+// SendDue sends the reminders due now and returns how many were sent. A
+// reminder that fails to send is retried on the next run.
+func (s *DispositionReminders) SendDue(ctx context.Context) (int, error) {
+	list, err := s.repo.ListRiskAcceptances(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing risk acceptances: %w", err)
+	}
+	byID := make(map[string]*models.GapDisposition, len(list))
+	for i := range list {
+		byID[list[i].ID] = &list[i]
+	}
+	now := s.now().UTC()
+	sent := 0
+	for _, r := range DueDispositionReviews(list, now) {
+		if err := s.send(ctx, &r); err != nil {
+			log.Warn().Err(err).Str("disposition_id", r.DispositionID).Msg("gap disposition reminder not sent")
+			continue
+		}
+		sent++
+		d := byID[r.DispositionID]
+		d.LastRemindedAt = &now
+		if err := s.repo.Update(ctx, d); err != nil {
+			return sent, fmt.Errorf("recording reminder for gap disposition %s: %w", d.ID, err)
+		}
+	}
+	return sent, nil
+}
+
+// send posts a reminder to the webhook, or logs it.
+func (s *DispositionReminders) send(ctx context.Context, r *DispositionReminder) error {
+	if s.webhook == nil {
+		log.Info().Str("org_id", r.OrganizationID).Str("framework_id", r.FrameworkID).Str("control_id", r.ControlID).
+			Str("approver", r.Approver).Str("reason", r.Reason).Msg("gap disposition review reminder")
+		return nil
+	}
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding reminder: %w", err)
+	}
+	if err := s.webhook.Send(ctx, body); err != nil {
+		return fmt.Errorf("sending reminder: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package controls_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestGapDispositions(t *testing.T) {
+	now := time.Now().UTC()
+	future, past := now.AddDate(0, 6, 0), now.AddDate(0, 0, -1)
+	accept := &models.GapDisposition{Disposition: models.DispositionAccept, Approver: "ciso",
+		Justification: "compensating controls", ExpiresAt: &future}
+	if err := controls.ValidateDisposition(accept, now); err != nil {
+		t.Errorf("valid accept: %v", err)
+	}
+	for name, d := range map[string]models.GapDisposition{
+		"unknown":       {Disposition: "ignore"},
+		"no approver":   {Disposition: models.DispositionAccept, Justification: "j", ExpiresAt: &future},
+		"no expiry":     {Disposition: models.DispositionAccept, Approver: "ciso", Justification: "j"},
+		"expired":       {Disposition: models.DispositionAccept, Approver: "ciso", Justification: "j", ExpiresAt: &past},
+		"no transferee": {Disposition: models.DispositionTransfer, Approver: "ciso", Justification: "j", ExpiresAt: &future},
+	} {
+		if err := controls.ValidateDisposition(&d, now); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := &controls.AnalysisInput{TargetFramework: "nist-800-53", ImplementedControls: []string{"AC-1"}}
+	base, err := analyzer.RunAnalysis(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.OpenGapCount != base.GapCount || base.RiskAdjustedCoverage != base.CoveragePercentage {
+		t.Errorf("without dispositions: open = %d of %d, risk-adjusted = %.1f, coverage = %.1f",
+			base.OpenGapCount, base.GapCount, base.RiskAdjustedCoverage, base.CoveragePercentage)
+	}
+	accepted, transferred, expired := base.Gaps[0], base.Gaps[1], base.Gaps[2]
+	input.Dispositions = []models.GapDisposition{
+		{FrameworkID: "nist-800-53", ControlID: strings.ToLower(accepted.ControlID), Disposition: models.DispositionAccept, ExpiresAt: &future},
+		{FrameworkID: "nist-800-53", ControlID: transferred.ControlID, Disposition: models.DispositionTransfer, ExpiresAt: &future},
+		{FrameworkID: "nist-800-53", ControlID: expired.ControlID, Disposition: models.DispositionAccept, ExpiresAt: &past},
+		{FrameworkID: "iso-42001", ControlID: base.Gaps[3].ControlID, Disposition: models.DispositionAccept, ExpiresAt: &future},
+	}
+	out, err := analyzer.RunAnalysis(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.GapCount != base.GapCount || out.AcceptedRiskCount != 1 || out.TransferredRiskCount != 1 || out.OpenGapCount != base.GapCount-2 {
+		t.Errorf("gaps = %d, accepted = %d, transferred = %d, open = %d", out.GapCount, out.AcceptedRiskCount, out.TransferredRiskCount, out.OpenGapCount)
+	}
+	want := float64(out.ImplementedCount+2) / float64(out.TotalControls) * 100
+	if out.RiskAdjustedCoverage != want || out.CoveragePercentage != base.CoveragePercentage {
+		t.Errorf("risk-adjusted coverage = %.2f, want %.2f; coverage = %.2f", out.RiskAdjustedCoverage, want, out.CoveragePercentage)
+	}
+	open := out.Summary.Critical + out.Summary.High + out.Summary.Medium + out.Summary.Low
+	if open != out.OpenGapCount {
+		t.Errorf("summary counts %d gaps, want the %d open ones", open, out.OpenGapCount)
+	}
+	if !out.Gaps[0].RiskAccepted() || out.Gaps[2].RiskAccepted() || !out.Gaps[2].DispositionExpired || out.Gaps[3].Disposition != nil {
+		t.Errorf("gaps = %+v", out.Gaps[:4])
+	}
+
+	ga := controls.NewGapAnalysis("acme", input, out, now)
+	if ga.Gaps[0].Disposition != models.DispositionAccept || ga.Gaps[2].Disposition != "" ||
+		ga.Summary.AcceptedRisk != 1 || ga.Summary.TransferredRisk != 1 {
+		t.Errorf("recorded analysis = %+v, summary = %+v", ga.Gaps[:3], ga.Summary)
+	}
+	r, err := analyzer.Roadmap(out, controls.RoadmapOptions{Start: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range r.Phases {
+		for _, item := range p.Items {
+			if item.ControlID == accepted.ControlID || item.ControlID == transferred.ControlID {
+				t.Errorf("roadmap schedules %s, whose risk is accepted", item.ControlID)
+			}
+		}
+	}
+}
+
+func TestDueDispositionReviews(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := created.AddDate(0, 6, 0)
+	list := []models.GapDisposition{
+		{ID: "d1", OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC6.1", Disposition: models.DispositionAccept,
+			Approver: "ciso", ExpiresAt: &expires, ReviewIntervalDays: 90, CreatedAt: created},
+		{ID: "d2", OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC6.2", Disposition: models.DispositionRemediate, CreatedAt: created},
+	}
+	if got := controls.DueDispositionReviews(list, created.AddDate(0, 0, 89)); len(got) != 0 {
+		t.Errorf("reminders before the review interval = %+v", got)
+	}
+	got := controls.DueDispositionReviews(list, created.AddDate(0, 0, 91))
+	if len(got) != 1 || got[0].DispositionID != "d1" || got[0].Reason != controls.ReviewDue || got[0].Approver != "ciso" {
+		t.Fatalf("reminders = %+v, want a review of d1", got)
+	}
+	reminded := created.AddDate(0, 0, 91)
+	list[0].LastRemindedAt = &reminded
+	if got := controls.DueDispositionReviews(list, created.AddDate(0, 0, 120)); len(got) != 0 {
+		t.Errorf("reminders after reminding = %+v", got)
+	}
+	if got := controls.DueDispositionReviews(list, expires.AddDate(0, 0, -7)); len(got) != 1 || got[0].Reason != controls.ReviewExpiring {
+		t.Errorf("reminders a week before expiry = %+v", got)
+	}
+	if got := controls.DueDispositionReviews(list, expires.Add(time.Hour)); len(got) != 1 || got[0].Reason != controls.ReviewExpired {
+		t.Errorf("reminders after expiry = %+v", got)
+	}
+}
@@ -0,0 +1,184 @@
+package controls
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// textDocument is a document of headings, paragraphs, bullets and tables,
+// rendered the same way to each output format.
+type textDocument interface {
+	Heading(level int, text string)
+	Paragraph(text string)
+	Bullet(text string)
+	Table(header []string, rows [][]string)
+	WriteTo(w io.Writer) (int64, error)
+}
+
+// markdownDocument renders a textDocument as CommonMark with GitHub tables.
+type markdownDocument struct {
+	b strings.Builder
+	// inList is set after a bullet, so the next block closes the list.
+	inList bool
+}
+
+func (d *markdownDocument) block() {
+	if d.b.Len() > 0 {
+		d.b.WriteString("\n")
+	}
+	d.inList = false
+}
+
+func (d *markdownDocument) Heading(level int, text string) {
+	d.block()
+	fmt.Fprintf(&d.b, "%s %s\n", strings.Repeat("#", level), markdownInline(text))
+}
+
+func (d *markdownDocument) Paragraph(text string) {
+	d.block()
+	d.b.WriteString(markdownInline(text) + "\n")
+}
+
+func (d *markdownDocument) Bullet(text string) {
+	if !d.inList {
+		d.block()
+	}
+	d.inList = true
+	d.b.WriteString("- " + markdownInline(text) + "\n")
+}
+
+func (d *markdownDocument) Table(header []string, rows [][]string) {
+	d.block()
+	row := func(cells []string) {
+		for _, c := range cells {
+			d.b.WriteString("| " + strings.ReplaceAll(markdownInline(c), "|", `\|`) + " ")
+		}
+		d.b.WriteString("|\n")
+	}
+	row(header)
+	d.b.WriteString(strings.Repeat("| --- ", len(header)) + "|\n")
+	for _, r := range rows {
+		row(r)
+	}
+}
+
+func (d *markdownDocument) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, d.b.String())
+	return int64(n), err
+}
+
+// markdownInline flattens text onto one line, so narratives with line
+// breaks cannot start headings or end table rows.
+func markdownInline(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// docxDocument renders a textDocument as an Office Open XML word
+// processing document. It emits only the parts a word processor needs:
+// the body, and styles for headings, list paragraphs and bordered tables.
+type docxDocument struct {
+	body bytes.Buffer
+}
+
+func (d *docxDocument) paragraph(style, text string) {
+	d.body.WriteString("<w:p>")
+	if style != "" {
+		fmt.Fprintf(&d.body, `<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+	}
+	fmt.Fprintf(&d.body, `<w:r><w:t xml:space="preserve">%s</w:t></w:r></w:p>`, xmlEscape(text))
+}
+
+func (d *docxDocument) Heading(level int, text string) {
+	d.paragraph(fmt.Sprintf("Heading%d", min(max(level, 1), 3)), text)
+}
+
+func (d *docxDocument) Paragraph(text string) { d.paragraph("", text) }
+
+func (d *docxDocument) Bullet(text string) { d.paragraph("ListBullet", "• "+text) }
+
+func (d *docxDocument) Table(header []string, rows [][]string) {
+	d.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr>`)
+	row := func(cells []string, bold bool) {
+		d.body.WriteString("<w:tr>")
+		for _, c := range cells {
+			props := ""
+			if bold {
+				props = "<w:rPr><w:b/></w:rPr>"
+			}
+			fmt.Fprintf(&d.body, `<w:tc><w:p><w:r>%s<w:t xml:space="preserve">%s</w:t></w:r></w:p></w:tc>`, props, xmlEscape(c))
+		}
+		d.body.WriteString("</w:tr>")
+	}
+	row(header, true)
+	for _, r := range rows {
+		row(r, false)
+	}
+	// Word requires a paragraph between adjacent tables.
+	d.body.WriteString("</w:tbl><w:p/>")
+}
+
+func (d *docxDocument) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	zw := zip.NewWriter(cw)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+			`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`</Relationships>`},
+		{"word/_rels/document.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`},
+		{"word/document.xml", xml.Header + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			d.body.String() + `</w:body></w:document>`},
+		{"word/styles.xml", xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri"/><w:sz w:val="22"/></w:rPr></w:rPrDefault>` +
+			`<w:pPrDefault><w:pPr><w:spacing w:after="120"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+			`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+			docxHeadingStyle(1, 32) + docxHeadingStyle(2, 28) + docxHeadingStyle(3, 24) +
+			`<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/>` +
+			`<w:pPr><w:spacing w:after="40"/><w:ind w:left="360" w:hanging="220"/></w:pPr></w:style>` +
+			`<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblBorders>` +
+			`<w:top w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:left w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+			`<w:bottom w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:right w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+			`<w:insideH w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+			`</w:tblBorders></w:tblPr></w:style></w:styles>`},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return cw.n, err
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return cw.n, err
+		}
+	}
+	err := zw.Close()
+	return cw.n, err
+}
+
+func docxHeadingStyle(level, halfPoints int) string {
+	return fmt.Sprintf(`<w:style w:type="paragraph" w:styleId="Heading%d"><w:name w:val="heading %d"/><w:basedOn w:val="Normal"/>`+
+		`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="%d"/></w:pPr>`+
+		`<w:rPr><w:b/><w:sz w:val="%d"/></w:rPr></w:style>`, level, level, level-1, halfPoints)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,421 @@
+package controls
+
+import "github.com/agentguard/agentguard/internal/models"
+
+// getEUAIActControls returns EU AI Act (Regulation (EU) 2024/1689) obligations.
+// The catalog covers the high-risk system requirements of Articles 9-15, the
+// provider and deployer obligations that operationalize them, transparency
+// duties for systems interacting with people, and post-market monitoring.
+func getEUAIActControls() []models.Control {
+	return []models.Control{
+		// Chapter I-II: General provisions and prohibited practices
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-4",
+			Title:       "AI Literacy",
+			Description: "Providers and deployers take measures to ensure a sufficient level of AI literacy of staff and other persons dealing with the operation and use of AI systems on their behalf.",
+			Objectives: []string{
+				"Ensure staff understand AI capabilities and limitations",
+				"Tailor training to technical knowledge and context of use",
+				"Consider the persons affected by AI systems",
+			},
+			Activities: []string{
+				"Deliver role-based AI literacy training",
+				"Track training completion for AI operators",
+				"Refresh training as systems change",
+			},
+			EvidenceTypes: []string{
+				"Training curriculum",
+				"Training completion records",
+				"Competence assessments",
+			},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-5",
+			Title:       "Prohibited AI Practices",
+			Description: "AI systems are not placed on the market, put into service or used for practices prohibited under Article 5, such as manipulative techniques, exploitation of vulnerabilities, social scoring or untargeted scraping of facial images.",
+			Objectives: []string{
+				"Identify prohibited use cases before deployment",
+				"Prevent repurposing of systems into prohibited uses",
+				"Document screening decisions",
+			},
+			Activities: []string{
+				"Screen AI use cases against Article 5 prohibitions",
+				"Enforce use restrictions through policy guardrails",
+				"Review agent tool grants for prohibited capabilities",
+			},
+			EvidenceTypes: []string{
+				"Use case screening records",
+				"Legal review sign-off",
+				"Guardrail policy definitions",
+			},
+			ApplicableLayers: []string{"governance", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-6",
+			Title:       "High-Risk Classification",
+			Description: "AI systems are classified against Article 6 and Annex III to determine whether high-risk requirements apply, and classification decisions for Annex III systems deemed not high-risk are documented.",
+			Objectives: []string{
+				"Maintain an inventory of AI systems and intended purposes",
+				"Classify systems against Annex I and Annex III",
+				"Document non-high-risk determinations",
+			},
+			Activities: []string{
+				"Maintain AI system inventory",
+				"Assess intended purpose against Annex III areas",
+				"Reassess classification on substantial modification",
+			},
+			EvidenceTypes: []string{
+				"AI system inventory",
+				"Classification assessments",
+				"Non-high-risk determination records",
+			},
+			ApplicableLayers: []string{"governance", "risk_management"},
+		},
+
+		// Chapter III Section 2: Requirements for high-risk AI systems
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-9",
+			Title:       "Risk Management System",
+			Description: "A risk management system is established, implemented, documented and maintained as a continuous iterative process across the entire lifecycle of the high-risk AI system.",
+			Objectives: []string{
+				"Identify and analyse known and foreseeable risks",
+				"Evaluate risks from intended use and reasonably foreseeable misuse",
+				"Adopt targeted risk management measures",
+				"Test to identify the most appropriate measures",
+			},
+			Activities: []string{
+				"Conduct lifecycle risk assessments",
+				"Evaluate residual risk against acceptability criteria",
+				"Test systems against defined metrics and thresholds",
+				"Consider impacts on minors and vulnerable groups",
+			},
+			EvidenceTypes: []string{
+				"Risk management plan",
+				"Risk register",
+				"Residual risk acceptance records",
+				"Test reports",
+			},
+			ApplicableLayers: []string{"risk_management", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-10",
+			Title:       "Data and Data Governance",
+			Description: "Training, validation and testing data sets are subject to data governance and management practices and are relevant, sufficiently representative and, to the best extent possible, free of errors and complete.",
+			Objectives: []string{
+				"Govern data collection and preparation",
+				"Examine data sets for possible biases",
+				"Identify data gaps and shortcomings",
+			},
+			Activities: []string{
+				"Document data provenance and preparation steps",
+				"Perform bias examination and mitigation",
+				"Assess representativeness for the intended purpose",
+			},
+			EvidenceTypes: []string{
+				"Data governance procedures",
+				"Data sheets",
+				"Bias examination reports",
+			},
+			ApplicableLayers: []string{"data", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-11",
+			Title:       "Technical Documentation",
+			Description: "Technical documentation demonstrating compliance with the high-risk requirements is drawn up before the system is placed on the market and kept up to date, covering at least the elements of Annex IV.",
+			Objectives: []string{
+				"Describe the system, its purpose and design",
+				"Document development, validation and testing",
+				"Keep documentation current",
+			},
+			Activities: []string{
+				"Prepare Annex IV technical documentation",
+				"Version documentation with system releases",
+				"Retain documentation for ten years",
+			},
+			EvidenceTypes: []string{
+				"Annex IV technical file",
+				"System architecture documentation",
+				"Documentation change history",
+			},
+			ApplicableLayers: []string{"governance", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-12",
+			Title:       "Record-Keeping",
+			Description: "High-risk AI systems technically allow for the automatic recording of events (logs) over their lifetime, enabling traceability, identification of risk situations and post-market monitoring.",
+			Objectives: []string{
+				"Automatically log events relevant to risk identification",
+				"Support post-market monitoring",
+				"Enable monitoring of system operation",
+			},
+			Activities: []string{
+				"Capture agent decisions, tool calls and inputs",
+				"Protect logs against tampering",
+				"Retain logs for the required period",
+			},
+			EvidenceTypes: []string{
+				"Logging configuration",
+				"Sample event logs",
+				"Log retention policy",
+			},
+			ApplicableLayers: []string{"operations", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-13",
+			Title:       "Transparency and Information to Deployers",
+			Description: "High-risk AI systems are designed so their operation is sufficiently transparent for deployers to interpret output and use it appropriately, and are accompanied by instructions for use.",
+			Objectives: []string{
+				"Enable deployers to interpret system output",
+				"Disclose capabilities, limitations and accuracy",
+				"Provide complete instructions for use",
+			},
+			Activities: []string{
+				"Publish instructions for use",
+				"Document performance characteristics and known limitations",
+				"Describe human oversight measures and log interpretation",
+			},
+			EvidenceTypes: []string{
+				"Instructions for use",
+				"Model cards",
+				"Performance disclosures",
+			},
+			ApplicableLayers: []string{"governance", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-14",
+			Title:       "Human Oversight",
+			Description: "High-risk AI systems are designed so they can be effectively overseen by natural persons during use, including the ability to disregard, override or interrupt the system.",
+			Objectives: []string{
+				"Enable overseers to understand and monitor operation",
+				"Guard against automation bias",
+				"Allow intervention and safe interruption",
+			},
+			Activities: []string{
+				"Require human approval for high-impact actions",
+				"Provide stop and override mechanisms",
+				"Train overseers on system limitations",
+			},
+			EvidenceTypes: []string{
+				"Human oversight procedures",
+				"Approval workflow configuration",
+				"Override and stop records",
+			},
+			ApplicableLayers: []string{"operations", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-15",
+			Title:       "Accuracy, Robustness and Cybersecurity",
+			Description: "High-risk AI systems achieve an appropriate level of accuracy, robustness and cybersecurity, and perform consistently throughout their lifecycle, including resilience against attempts to alter their use or outputs.",
+			Objectives: []string{
+				"Declare and meet accuracy levels",
+				"Remain resilient to errors, faults and inconsistencies",
+				"Protect against data poisoning, adversarial inputs and model flaws",
+			},
+			Activities: []string{
+				"Measure accuracy against declared metrics",
+				"Test resilience to prompt injection and adversarial input",
+				"Mitigate feedback loops in continuously learning systems",
+			},
+			EvidenceTypes: []string{
+				"Accuracy metrics",
+				"Robustness test results",
+				"Security assessment reports",
+			},
+			ApplicableLayers: []string{"security", "system"},
+		},
+
+		// Chapter III Section 3: Obligations of providers and deployers
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-17",
+			Title:       "Quality Management System",
+			Description: "Providers of high-risk AI systems put in place a documented quality management system ensuring compliance, covering design, development, testing, data management, risk management and post-market monitoring.",
+			Objectives: []string{
+				"Document policies, procedures and instructions",
+				"Assign accountability for compliance",
+				"Integrate risk management and monitoring",
+			},
+			Activities: []string{
+				"Establish quality management procedures",
+				"Define design and verification techniques",
+				"Maintain an accountability framework",
+			},
+			EvidenceTypes: []string{
+				"Quality manual",
+				"Procedure documents",
+				"Management review records",
+			},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-26",
+			Title:       "Deployer Obligations",
+			Description: "Deployers use high-risk AI systems in accordance with the instructions for use, assign competent human oversight, monitor operation, keep automatically generated logs for at least six months and inform affected workers.",
+			Objectives: []string{
+				"Use systems in line with instructions for use",
+				"Assign competent oversight personnel",
+				"Monitor operation and report risks",
+			},
+			Activities: []string{
+				"Assign and train human overseers",
+				"Monitor system operation against instructions",
+				"Retain logs under the deployer's control",
+			},
+			EvidenceTypes: []string{
+				"Oversight assignments",
+				"Operational monitoring records",
+				"Log retention evidence",
+			},
+			ApplicableLayers: []string{"operations", "organization"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-27",
+			Title:       "Fundamental Rights Impact Assessment",
+			Description: "Deployers that are public bodies or provide public services, and deployers of certain Annex III systems, assess the impact on fundamental rights before first use.",
+			Objectives: []string{
+				"Identify affected persons and groups",
+				"Assess specific risks of harm",
+				"Define oversight and mitigation measures",
+			},
+			Activities: []string{
+				"Conduct fundamental rights impact assessments",
+				"Notify the market surveillance authority of results",
+				"Update the assessment when circumstances change",
+			},
+			EvidenceTypes: []string{
+				"Fundamental rights impact assessment",
+				"Authority notification records",
+			},
+			ApplicableLayers: []string{"governance", "society"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-43",
+			Title:       "Conformity Assessment and Registration",
+			Description: "High-risk AI systems undergo the applicable conformity assessment procedure, carry an EU declaration of conformity and CE marking, and are registered in the EU database before being placed on the market.",
+			Objectives: []string{
+				"Complete the applicable conformity assessment",
+				"Issue the EU declaration of conformity",
+				"Register the system in the EU database",
+			},
+			Activities: []string{
+				"Perform internal control or notified body assessment",
+				"Reassess after substantial modifications",
+				"Maintain registration details",
+			},
+			EvidenceTypes: []string{
+				"Conformity assessment report",
+				"EU declaration of conformity",
+				"EU database registration",
+			},
+			ApplicableLayers: []string{"governance", "system"},
+		},
+
+		// Chapter IV: Transparency obligations
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-50",
+			Title:       "Transparency for AI Interacting with People",
+			Description: "Persons are informed when they interact with an AI system, synthetic audio, image, video or text output is marked as artificially generated, and deep fakes are disclosed.",
+			Objectives: []string{
+				"Disclose AI interaction to natural persons",
+				"Mark synthetic content in a machine-readable format",
+				"Disclose emotion recognition and biometric categorisation",
+			},
+			Activities: []string{
+				"Add AI disclosure to agent conversations",
+				"Watermark or label generated content",
+				"Review disclosures for accessibility",
+			},
+			EvidenceTypes: []string{
+				"Disclosure notices",
+				"Content marking configuration",
+				"User interface reviews",
+			},
+			ApplicableLayers: []string{"application", "society"},
+		},
+
+		// Chapter V: General-purpose AI models
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-53",
+			Title:       "General-Purpose AI Model Obligations",
+			Description: "Providers of general-purpose AI models maintain technical documentation, provide information to downstream providers, put in place a copyright policy and publish a summary of training content.",
+			Objectives: []string{
+				"Document the model for authorities and downstream providers",
+				"Respect Union copyright law",
+				"Publish a training content summary",
+			},
+			Activities: []string{
+				"Maintain model technical documentation",
+				"Share integration information with downstream providers",
+				"Track upstream model provider documentation for integrated models",
+			},
+			EvidenceTypes: []string{
+				"Model documentation",
+				"Copyright policy",
+				"Training content summary",
+			},
+			ApplicableLayers: []string{"supply_chain", "system"},
+		},
+
+		// Chapter IX: Post-market monitoring and incident reporting
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-72",
+			Title:       "Post-Market Monitoring",
+			Description: "Providers establish and document a post-market monitoring system proportionate to the risks, actively collecting and analysing performance data throughout the system's lifetime.",
+			Objectives: []string{
+				"Collect performance data from deployed systems",
+				"Evaluate continuous compliance",
+				"Analyse interactions with other AI systems",
+			},
+			Activities: []string{
+				"Define a post-market monitoring plan",
+				"Monitor agent behaviour and security signals in production",
+				"Feed findings back into risk management",
+			},
+			EvidenceTypes: []string{
+				"Post-market monitoring plan",
+				"Monitoring dashboards",
+				"Periodic monitoring reports",
+			},
+			ApplicableLayers: []string{"operations", "risk_management"},
+		},
+		{
+			FrameworkID: string(FrameworkEUAIAct),
+			ControlID:   "EUAIA-73",
+			Title:       "Serious Incident Reporting",
+			Description: "Providers report serious incidents to the market surveillance authorities without undue delay and within the prescribed deadlines, investigate them and take corrective action.",
+			Objectives: []string{
+				"Detect and classify serious incidents",
+				"Report within regulatory deadlines",
+				"Investigate and correct root causes",
+			},
+			Activities: []string{
+				"Define serious incident criteria",
+				"Establish reporting workflow and contacts",
+				"Perform root cause analysis",
+			},
+			EvidenceTypes: []string{
+				"Incident response procedure",
+				"Incident reports",
+				"Corrective action records",
+			},
+			ApplicableLayers: []string{"operations", "governance"},
+		},
+	}
+}
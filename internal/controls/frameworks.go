@@ -3,13 +3,13 @@ package controls
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/agentguard/agentguard/internal/atlas"
 	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
 )
 
 // FrameworkID identifies a control framework.
@@ -20,6 +20,12 @@ const (
 	FrameworkNIST80053 FrameworkID = "nist-800-53"
 	FrameworkISO42001  FrameworkID = "iso-42001"
 	FrameworkSOC2      FrameworkID = "soc2"
+	FrameworkEUAIAct   FrameworkID = "eu-ai-act"
+	FrameworkOWASPLLM  FrameworkID = "owasp-llm-top10"
+	FrameworkCSAAICM   FrameworkID = "csa-aicm"
+	// FrameworkMITREATLAS catalogs adversary techniques rather than
+	// controls; crosswalks map other frameworks' mitigations onto them.
+	FrameworkMITREATLAS FrameworkID = "mitre-atlas"
 )
 
 // Service provides control framework operations.
@@ -28,6 +34,10 @@ type Service struct {
 	frameworks map[FrameworkID]*models.Framework
 	controls   map[FrameworkID][]models.Control
 	crosswalks []models.Crosswalk
+	versions   map[FrameworkID]map[string]*models.FrameworkVersion
+	scoring    *ScoringModel
+	// licensed holds the restricted catalogs whose full text was loaded.
+	licensed map[FrameworkID]bool
 }
 
 // NewService creates a new control framework service.
@@ -36,6 +46,9 @@ func NewService(dataDir string) (*Service, error) {
 		dataDir:    dataDir,
 		frameworks: make(map[FrameworkID]*models.Framework),
 		controls:   make(map[FrameworkID][]models.Control),
+		versions:   make(map[FrameworkID]map[string]*models.FrameworkVersion),
+		scoring:    DefaultScoringModel(),
+		licensed:   make(map[FrameworkID]bool),
 	}
 
 	if err := s.loadFrameworks(); err != nil {
@@ -52,32 +65,135 @@ func (s *Service) loadFrameworks() error {
 
 	// Override with files from data directory if present
 	if s.dataDir != "" {
-		files, err := filepath.Glob(filepath.Join(s.dataDir, "frameworks", "*.json"))
+		// Framework definitions, with or without controls, in YAML or JSON
+		var files []string
+		for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(s.dataDir, "frameworks", pattern))
+			if err != nil {
+				return err
+			}
+			files = append(files, matches...)
+		}
+		var custom []*customFile
+		for _, file := range files {
+			f, err := s.loadCustomFile(file)
+			if err != nil {
+				return fmt.Errorf("loading %s: %w", file, err)
+			}
+			custom = append(custom, f)
+		}
+
+		// Full OSCAL catalogs, named after the framework they replace,
+		// e.g. catalogs/nist-800-53.json
+		catalogs, err := filepath.Glob(filepath.Join(s.dataDir, "catalogs", "*.json"))
 		if err != nil {
 			return err
 		}
-		for _, file := range files {
-			if err := s.loadFrameworkFile(file); err != nil {
+		for _, file := range catalogs {
+			if err := s.loadCatalogFile(file); err != nil {
 				return fmt.Errorf("loading %s: %w", file, err)
 			}
 		}
+
+		// Licensed copies of restricted catalogs, e.g.
+		// licensed/iso-42001.yaml
+		if err := s.loadLicensedText(); err != nil {
+			return err
+		}
+
+		// Crosswalk hints may target any framework, so they are resolved
+		// once everything has loaded.
+		if err := s.resolveHints(custom); err != nil {
+			return err
+		}
 	}
+	s.markLicensing()
 
+	for id := range s.frameworks {
+		s.archive(id)
+	}
 	return nil
 }
 
-func (s *Service) loadFrameworkFile(path string) error {
-	data, err := os.ReadFile(path)
+// loadCatalogFile replaces a framework's controls with an OSCAL catalog, or
+// the catalog an OSCAL profile resolves to.
+// The embedded controls' AI-specific activities, evidence and layers are
+// kept where the catalog has none, and embedded controls missing from the
+// catalog are kept so crosswalks to them still resolve.
+func (s *Service) loadCatalogFile(path string) error {
+	id, version := splitVersionedName(path)
+	cat, err := oscal.Load(path)
 	if err != nil {
 		return err
 	}
+	loaded := oscal.ToControls(cat, string(id))
+	if len(loaded) == 0 {
+		return fmt.Errorf("catalog has no controls")
+	}
+	if version != "" {
+		fw := oscal.ToFramework(cat, string(id))
+		if cur, ok := s.frameworks[id]; ok {
+			updated := *cur
+			fw = &updated
+		}
+		fw.Version = version
+		s.addVersion(fw, loaded)
+		return nil
+	}
+	s.archive(id)
+	if _, restricted := restrictedCatalogs[id]; restricted {
+		s.licensed[id] = true
+	}
 
-	var fw models.Framework
-	if err := json.Unmarshal(data, &fw); err != nil {
-		return err
+	curated := make(map[string]models.Control, len(s.controls[id]))
+	for _, c := range s.controls[id] {
+		curated[strings.ToLower(c.ControlID)] = c
+	}
+	for i := range loaded {
+		c := &loaded[i]
+		prev, ok := curated[strings.ToLower(c.ControlID)]
+		if !ok {
+			continue
+		}
+		delete(curated, strings.ToLower(c.ControlID))
+		if len(c.Objectives) == 0 {
+			c.Objectives = prev.Objectives
+		}
+		if len(c.Activities) == 0 {
+			c.Activities = prev.Activities
+		}
+		if len(c.EvidenceTypes) == 0 {
+			c.EvidenceTypes = prev.EvidenceTypes
+		}
+		c.ApplicableLayers = prev.ApplicableLayers
+	}
+	// Families are keyed by control ID prefix, e.g. "AC", so kept controls
+	// are grouped with their catalog family.
+	families := make(map[string]string)
+	for _, c := range loaded {
+		prefix, _, _ := strings.Cut(c.ControlID, "-")
+		families[prefix] = c.Family
 	}
+	for _, c := range s.controls[id] {
+		if _, missing := curated[strings.ToLower(c.ControlID)]; missing {
+			if c.Family == "" {
+				prefix, _, _ := strings.Cut(c.ControlID, "-")
+				c.Family = families[prefix]
+			}
+			loaded = append(loaded, c)
+		}
+	}
+	s.controls[id] = loaded
 
-	s.frameworks[FrameworkID(fw.ID)] = &fw
+	if fw, ok := s.frameworks[id]; ok {
+		updated := *fw
+		if cat.Metadata.Version != "" {
+			updated.Version = cat.Metadata.Version
+		}
+		s.frameworks[id] = &updated
+	} else {
+		s.frameworks[id] = oscal.ToFramework(cat, string(id))
+	}
 	return nil
 }
 
@@ -115,6 +231,61 @@ func (s *Service) loadEmbeddedFrameworks() {
 		URL:         "https://www.iso.org/standard/81230.html",
 	}
 	s.controls[FrameworkISO42001] = getISO42001Controls()
+
+	// SOC 2
+	s.frameworks[FrameworkSOC2] = &models.Framework{
+		ID:          string(FrameworkSOC2),
+		Name:        "SOC 2 Trust Services Criteria",
+		Version:     "2017 (rev. 2022)",
+		Publisher:   "AICPA",
+		Description: "Trust Services Criteria for security, availability and confidentiality used in SOC 2 examinations",
+		URL:         "https://www.aicpa-cima.com/resources/download/2017-trust-services-criteria-with-revised-points-of-focus-2022",
+	}
+	s.controls[FrameworkSOC2] = getSOC2Controls()
+
+	// EU AI Act
+	s.frameworks[FrameworkEUAIAct] = &models.Framework{
+		ID:          string(FrameworkEUAIAct),
+		Name:        "EU Artificial Intelligence Act",
+		Version:     "2024/1689",
+		Publisher:   "European Union",
+		Description: "Harmonised rules on artificial intelligence, including requirements for high-risk AI systems",
+		URL:         "https://eur-lex.europa.eu/eli/reg/2024/1689/oj",
+	}
+	s.controls[FrameworkEUAIAct] = getEUAIActControls()
+
+	// OWASP Top 10 for LLM Applications
+	s.frameworks[FrameworkOWASPLLM] = &models.Framework{
+		ID:          string(FrameworkOWASPLLM),
+		Name:        "OWASP Top 10 for LLM Applications",
+		Version:     "2025",
+		Publisher:   "OWASP",
+		Description: "The most critical security risks for applications and agents built on large language models",
+		URL:         "https://genai.owasp.org/llm-top-10/",
+	}
+	s.controls[FrameworkOWASPLLM] = getOWASPLLMControls()
+
+	// CSA AI Controls Matrix
+	s.frameworks[FrameworkCSAAICM] = &models.Framework{
+		ID:          string(FrameworkCSAAICM),
+		Name:        "CSA AI Controls Matrix",
+		Version:     "1.0",
+		Publisher:   "Cloud Security Alliance",
+		Description: "Vendor-agnostic control objectives for securing AI systems and services across the cloud supply chain",
+		URL:         "https://cloudsecurityalliance.org/artifacts/ai-controls-matrix",
+	}
+	s.controls[FrameworkCSAAICM] = getCSAAICMControls()
+
+	// MITRE ATLAS
+	s.frameworks[FrameworkMITREATLAS] = &models.Framework{
+		ID:          string(FrameworkMITREATLAS),
+		Name:        "MITRE ATLAS",
+		Version:     "2025",
+		Publisher:   "MITRE",
+		Description: "Adversarial Threat Landscape for Artificial-Intelligence Systems: adversary tactics and techniques against AI systems",
+		URL:         atlas.URL,
+	}
+	s.controls[FrameworkMITREATLAS] = getATLASControls()
 }
 
 // GetFramework returns a framework by ID.
@@ -144,8 +315,23 @@ func (s *Service) GetControls(id FrameworkID) ([]models.Control, error) {
 	return controls, nil
 }
 
-// GetCrosswalks returns mappings between two frameworks.
+// GetCrosswalks returns mappings between two frameworks. When the pair has
+// no direct mappings, transitive mappings derived through other frameworks
+// are returned instead, flagged Derived.
 func (s *Service) GetCrosswalks(source, target FrameworkID) ([]models.Crosswalk, error) {
+	result, err := s.directCrosswalks(source, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return s.DeriveCrosswalks(source, target)
+	}
+	return result, nil
+}
+
+// directCrosswalks returns the loaded or predefined mappings between two
+// frameworks.
+func (s *Service) directCrosswalks(source, target FrameworkID) ([]models.Crosswalk, error) {
 	result := []models.Crosswalk{}
 
 	for _, xw := range s.crosswalks {
@@ -208,55 +394,83 @@ func (s *Service) generateCrosswalks(source, target FrameworkID) ([]models.Cross
 
 // AnalyzeGaps performs gap analysis between current state and target framework.
 func (s *Service) AnalyzeGaps(ctx context.Context, targetFramework FrameworkID, implementedControls []string) (*models.GapAnalysis, error) {
+	return s.analyzeGaps(ctx, targetFramework, "", localInventory(implementedControls), s.scoring, nil)
+}
+
+// analyzeGaps performs gap analysis over an implementation inventory using the
+// given scoring model for priority and effort. Implemented controls of the
+// source framework, if any, also earn credit through derived crosswalks.
+// tags holds the organization's tags of target controls by lower-cased
+// control ID, for the scoring model's tag priorities.
+func (s *Service) analyzeGaps(ctx context.Context, targetFramework, sourceFramework FrameworkID, inventory []models.ImplementedControl, scoring *ScoringModel, tags map[string][]string) (*models.GapAnalysis, error) {
 	controls, err := s.GetControls(targetFramework)
 	if err != nil {
 		return nil, err
 	}
 
+	model := scoring.ForFramework(string(targetFramework))
+
 	implemented := make(map[string]bool)
-	for _, c := range implementedControls {
-		implemented[strings.ToLower(c)] = true
+	inherited := make(map[string]bool)
+	for _, c := range inventory {
+		id := strings.ToLower(c.ControlID)
+		implemented[id] = true
+		if c.Source == models.ImplementationInherited {
+			inherited[id] = true
+		}
 	}
 
+	// Implemented controls in other frameworks imply coverage through crosswalks.
+	implied := s.crosswalkCoverage(targetFramework, sourceFramework, implemented)
+
 	gaps := []models.ControlGap{}
 	fullyCovered := 0
 	partiallyCovered := 0
+	crosswalkCovered := 0
+	inheritedCovered := 0
 
 	for _, ctrl := range controls {
 		ctrlID := strings.ToLower(ctrl.ControlID)
+		ctrl.Tags = tags[ctrlID]
 		if implemented[ctrlID] {
 			fullyCovered++
+			if inherited[ctrlID] {
+				inheritedCovered++
+			}
 			continue
 		}
 
-		// Check for partial coverage (parent or child implemented)
-		partial := false
+		credit := implied[ctrlID]
+		if credit.covers() {
+			fullyCovered++
+			crosswalkCovered++
+			continue
+		}
+
+		// Check for partial coverage (parent implemented or crosswalk credit)
+		partial := credit != nil
 		if ctrl.ParentControlID != nil && implemented[strings.ToLower(*ctrl.ParentControlID)] {
 			partial = true
-			partiallyCovered++
 		}
 
+		gap := models.ControlGap{
+			ControlID:          ctrl.ControlID,
+			GapType:            "not_implemented",
+			Description:        fmt.Sprintf("Control '%s' (%s) is not implemented", ctrl.ControlID, ctrl.Title),
+			Priority:           model.DeterminePriority(ctx, ctrl),
+			RemediationOptions: generateRemediationOptions(ctrl),
+			EstimatedEffort:    model.EstimateEffort(ctx, ctrl),
+		}
 		if partial {
-			gap := models.ControlGap{
-				ControlID:          ctrl.ControlID,
-				GapType:            "partial",
-				Description:        fmt.Sprintf("Control '%s' (%s) is only partially covered", ctrl.ControlID, ctrl.Title),
-				Priority:           determineGapPriority(ctrl),
-				RemediationOptions: generateRemediationOptions(ctrl),
-				EstimatedEffort:    estimateEffort(ctrl),
-			}
-			gaps = append(gaps, gap)
-		} else {
-			gap := models.ControlGap{
-				ControlID:          ctrl.ControlID,
-				GapType:            "not_implemented",
-				Description:        fmt.Sprintf("Control '%s' (%s) is not implemented", ctrl.ControlID, ctrl.Title),
-				Priority:           determineGapPriority(ctrl),
-				RemediationOptions: generateRemediationOptions(ctrl),
-				EstimatedEffort:    estimateEffort(ctrl),
-			}
-			gaps = append(gaps, gap)
+			partiallyCovered++
+			gap.GapType = "partial"
+			gap.Description = fmt.Sprintf("Control '%s' (%s) is only partially covered", ctrl.ControlID, ctrl.Title)
+		}
+		if credit != nil {
+			gap.CoverageScore = credit.score
+			gap.CoveredBy = credit.contributions
 		}
+		gaps = append(gaps, gap)
 	}
 
 	totalControls := len(controls)
@@ -279,28 +493,14 @@ func (s *Service) AnalyzeGaps(ctx context.Context, targetFramework FrameworkID,
 			FullyCovered:       fullyCovered,
 			PartiallyCovered:   partiallyCovered,
 			NotCovered:         notCovered,
+			CrosswalkCovered:   crosswalkCovered,
+			InheritedCovered:   inheritedCovered,
 			CoveragePercentage: coverage,
 			GapsByPriority:     gapsByPriority,
 		},
 	}, nil
 }
 
-func determineGapPriority(ctrl models.Control) string {
-	// Priority based on control characteristics
-	for _, layer := range ctrl.ApplicableLayers {
-		if layer == "governance" || layer == "risk_management" {
-			return "high"
-		}
-	}
-	if len(ctrl.EvidenceTypes) > 3 {
-		return "high"
-	}
-	if len(ctrl.Activities) > 2 {
-		return "medium"
-	}
-	return "low"
-}
-
 func generateRemediationOptions(ctrl models.Control) []string {
 	options := []string{}
 	for _, activity := range ctrl.Activities {
@@ -311,17 +511,3 @@ func generateRemediationOptions(ctrl models.Control) []string {
 	}
 	return options
 }
-
-func estimateEffort(ctrl models.Control) string {
-	activityCount := len(ctrl.Activities)
-	evidenceCount := len(ctrl.EvidenceTypes)
-
-	total := activityCount + evidenceCount
-	if total > 6 {
-		return "large"
-	}
-	if total > 3 {
-		return "medium"
-	}
-	return "small"
-}
@@ -0,0 +1,182 @@
+package controls_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestEmbeddedCatalogs(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+
+	tests := []struct {
+		framework   string
+		source      string
+		implemented []string
+		// controls is the catalog's exact size when non-zero.
+		controls   int
+		covered    []string
+		gap        string
+		crosswalks [][2]string
+	}{
+		{
+			// Risk management (Art. 9) and human oversight (Art. 14) map
+			// exactly to the implemented ISO 42001 controls.
+			framework:   "eu-ai-act",
+			source:      "iso-42001",
+			implemented: []string{"ISO42001-6.1", "ISO42001-A.5.2"},
+			covered:     []string{"EUAIA-9", "EUAIA-14"},
+			gap:         "EUAIA-73",
+			crosswalks:  [][2]string{{"eu-ai-act", "nist-ai-rmf"}, {"eu-ai-act", "iso-42001"}},
+		},
+		{
+			// Sensitive information disclosure maps exactly onto privacy
+			// and security controls; poisoning needs bias and reliability
+			// controls.
+			framework:   "owasp-llm-top10",
+			source:      "iso-42001",
+			implemented: []string{"ISO42001-A.4.4", "ISO42001-A.7.3"},
+			controls:    10,
+			covered:     []string{"LLM02"},
+			gap:         "LLM04",
+			crosswalks:  [][2]string{{"owasp-llm-top10", "nist-ai-rmf"}, {"owasp-llm-top10", "iso-42001"}},
+		},
+		{
+			// Prompt injection and unbounded consumption mitigations
+			// counter these techniques exactly; nothing implemented
+			// counters poisoning.
+			framework:   "mitre-atlas",
+			source:      "owasp-llm-top10",
+			implemented: []string{"LLM01", "LLM10"},
+			covered:     []string{"AML.T0051", "AML.T0051.001", "AML.T0034"},
+			gap:         "AML.T0020",
+			crosswalks:  [][2]string{{"owasp-llm-top10", "mitre-atlas"}},
+		},
+		{
+			// Boundary protection and change control map exactly to the
+			// implemented 800-53 controls; nothing implemented covers
+			// competence.
+			framework:   "soc2",
+			source:      "nist-800-53",
+			implemented: []string{"SC-7", "CM-3", "CM-4"},
+			covered:     []string{"CC6.6", "CC8.1"},
+			gap:         "CC1.4",
+			crosswalks:  [][2]string{{"soc2", "nist-800-53"}, {"soc2", "iso-42001"}},
+		},
+		{
+			// Model documentation and supply chain risk map exactly to the
+			// implemented ISO 42001 clauses; nothing implemented covers
+			// least privilege.
+			framework:   "csa-aicm",
+			source:      "iso-42001",
+			implemented: []string{"ISO42001-8.4", "ISO42001-8.6"},
+			covered:     []string{"MDS-01", "STA-08"},
+			gap:         "IAM-05",
+			crosswalks:  [][2]string{{"csa-aicm", "nist-800-53"}, {"csa-aicm", "iso-42001"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.framework, func(t *testing.T) {
+			out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+				TargetFramework:     tt.framework,
+				SourceFramework:     tt.source,
+				ImplementedControls: tt.implemented,
+			})
+			if err != nil {
+				t.Fatalf("RunAnalysis: %v", err)
+			}
+			if out.TotalControls == 0 || tt.controls != 0 && out.TotalControls != tt.controls {
+				t.Fatalf("%s has %d controls, want %d", tt.framework, out.TotalControls, tt.controls)
+			}
+			for _, id := range tt.covered {
+				if findGap(out, id) != nil {
+					t.Errorf("%s reported as a gap, want covered via %s", id, tt.source)
+				}
+			}
+			if findGap(out, tt.gap) == nil {
+				t.Errorf("%s not reported as a gap", tt.gap)
+			}
+
+			for _, pair := range tt.crosswalks {
+				var buf strings.Builder
+				if err := analyzer.GenerateCrosswalkReport(&buf, pair[0], pair[1], false); err != nil {
+					t.Errorf("crosswalk %s to %s: %v", pair[0], pair[1], err)
+				}
+			}
+		})
+	}
+}
+
+func TestOSCALCatalog(t *testing.T) {
+	svc, err := controls.NewService("testdata")
+	if err != nil {
+		t.Fatalf("loading catalog: %v", err)
+	}
+	fw, err := svc.GetFramework("nist-800-53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fw.Version != "5.1.1" {
+		t.Errorf("version = %q, want the catalog's 5.1.1", fw.Version)
+	}
+
+	list, err := svc.GetControls("nist-800-53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]models.Control, len(list))
+	for _, c := range list {
+		byID[c.ControlID] = c
+	}
+
+	ac2, ok := byID["AC-2"]
+	if !ok {
+		t.Fatal("AC-2 not loaded")
+	}
+	if ac2.Family != "Access Control" {
+		t.Errorf("AC-2 family = %q", ac2.Family)
+	}
+	if !strings.Contains(ac2.Description, "b. Require [Assignment: prerequisites and criteria]") {
+		t.Errorf("AC-2 parameters not resolved: %q", ac2.Description)
+	}
+	if !strings.Contains(ac2.Description, "[Selection (one or more): daily; weekly]") {
+		t.Errorf("AC-2 selection not resolved: %q", ac2.Description)
+	}
+	if len(ac2.Objectives) != 2 || len(ac2.EvidenceTypes) != 2 || len(ac2.Activities) != 2 {
+		t.Errorf("AC-2 assessment = %d objectives, %d evidence, %d activities; want 2, 2, 2",
+			len(ac2.Objectives), len(ac2.EvidenceTypes), len(ac2.Activities))
+	}
+	// Applicable layers are AgentGuard's own and come from the curated control.
+	if len(ac2.ApplicableLayers) == 0 {
+		t.Error("AC-2 lost its curated applicable layers")
+	}
+
+	enh, ok := byID["AC-2(1)"]
+	if !ok {
+		t.Fatal("AC-2(1) not loaded")
+	}
+	if enh.ParentControlID == nil || *enh.ParentControlID != "AC-2" {
+		t.Errorf("AC-2(1) parent = %v, want AC-2", enh.ParentControlID)
+	}
+	if enh.Family != "Access Control" {
+		t.Errorf("AC-2(1) family = %q", enh.Family)
+	}
+	if _, ok := byID["AC-2(10)"]; ok {
+		t.Error("withdrawn AC-2(10) was loaded")
+	}
+	if c := byID["SC-45"]; c.Family != "System and Communications Protection" {
+		t.Errorf("SC-45 family = %q", c.Family)
+	}
+	// Curated controls absent from the catalog excerpt are kept so
+	// crosswalks to them still resolve.
+	if _, ok := byID["AU-2"]; !ok {
+		t.Error("curated AU-2 dropped")
+	}
+}
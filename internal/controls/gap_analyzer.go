@@ -6,15 +6,24 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"text/tabwriter"
+	"time"
 
 	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
 )
 
 // GapAnalyzer provides gap analysis functionality.
 type GapAnalyzer struct {
 	service *Service
+
+	mu        sync.RWMutex
+	scoring   *ScoringModel
+	providers map[string]*models.ControlProvider
+	monitor   *Monitor
 }
 
 // NewGapAnalyzer creates a new gap analyzer.
@@ -23,7 +32,48 @@ func NewGapAnalyzer(dataDir string) (*GapAnalyzer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &GapAnalyzer{service: svc}, nil
+	return &GapAnalyzer{
+		service:   svc,
+		scoring:   DefaultScoringModel(),
+		providers: make(map[string]*models.ControlProvider),
+	}, nil
+}
+
+// ScoringModel returns the organization-wide scoring model used when an
+// analysis input does not supply its own.
+func (g *GapAnalyzer) ScoringModel() *ScoringModel {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.scoring
+}
+
+// SetScoringModel validates and replaces the organization-wide scoring model.
+func (g *GapAnalyzer) SetScoringModel(m *ScoringModel) error {
+	if m == nil {
+		return fmt.Errorf("scoring model is required")
+	}
+	if err := m.Validate(); err != nil {
+		return fmt.Errorf("invalid scoring model: %w", err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.scoring = m
+	return nil
+}
+
+// Monitor returns the continuous monitoring checks feeding the analyzer, if any.
+func (g *GapAnalyzer) Monitor() *Monitor {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.monitor
+}
+
+// SetMonitor attaches continuous monitoring checks. Their latest results are
+// reconciled with the implementation inventory on every analysis.
+func (g *GapAnalyzer) SetMonitor(m *Monitor) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.monitor = m
 }
 
 // AnalysisInput represents input for gap analysis.
@@ -31,32 +81,75 @@ type AnalysisInput struct {
 	TargetFramework     string   `json:"target_framework"`
 	ImplementedControls []string `json:"implemented_controls"`
 	SourceFramework     string   `json:"source_framework,omitempty"`
+	// Providers lists the common control providers the system inherits from.
+	Providers []string `json:"providers,omitempty"`
+	// Scoring overrides the analyzer's scoring model for this run only.
+	Scoring *ScoringModel `json:"scoring,omitempty"`
+	// Tags are the organization's tags of target framework controls, keyed
+	// by control ID, that the scoring model's tag priorities apply to.
+	Tags map[string][]string `json:"tags,omitempty"`
+	// Dispositions are the organization's decisions on gaps in target
+	// framework controls. Accepted and transferred risks in effect count
+	// toward risk-adjusted coverage instead of the open gaps.
+	Dispositions []models.GapDisposition `json:"dispositions,omitempty"`
 }
 
 // AnalysisOutput represents the output of gap analysis.
 type AnalysisOutput struct {
-	Framework          string             `json:"framework"`
-	FrameworkName      string             `json:"framework_name"`
-	TotalControls      int                `json:"total_controls"`
-	ImplementedCount   int                `json:"implemented_count"`
-	GapCount           int                `json:"gap_count"`
-	CoveragePercentage float64            `json:"coverage_percentage"`
-	Gaps               []GapDetail        `json:"gaps"`
-	Summary            GapSummaryOutput   `json:"summary"`
-	Crosswalks         []CrosswalkSummary `json:"crosswalks,omitempty"`
+	Framework          string                      `json:"framework"`
+	FrameworkName      string                      `json:"framework_name"`
+	TotalControls      int                         `json:"total_controls"`
+	ImplementedCount   int                         `json:"implemented_count"`
+	PartialCount       int                         `json:"partial_count"`
+	CrosswalkCovered   int                         `json:"crosswalk_covered"`
+	InheritedCount     int                         `json:"inherited_count"`
+	GapCount           int                         `json:"gap_count"`
+	CoveragePercentage float64                     `json:"coverage_percentage"`
+	Gaps               []GapDetail                 `json:"gaps"`
+	Summary            GapSummaryOutput            `json:"summary"`
+	Crosswalks         []CrosswalkSummary          `json:"crosswalks,omitempty"`
+	Inventory          []models.ImplementedControl `json:"inventory"`
+	FailingChecks      []ControlStatus             `json:"failing_checks,omitempty"`
+	// AcceptedRiskCount and TransferredRiskCount count the gaps whose risk
+	// is accepted or transferred by a disposition in effect; OpenGapCount
+	// counts the rest.
+	AcceptedRiskCount    int `json:"accepted_risk_count"`
+	TransferredRiskCount int `json:"transferred_risk_count"`
+	OpenGapCount         int `json:"open_gap_count"`
+	// RiskAdjustedCoverage is the share of controls implemented or with an
+	// accepted or transferred risk.
+	RiskAdjustedCoverage float64 `json:"risk_adjusted_coverage"`
+	// Input is the resolved input the analysis ran with, so a result can be
+	// traced to and reproduced from it. Set by callers that want it recorded.
+	Input *AnalysisInput `json:"input,omitempty"`
+	// Import summarizes the implemented-controls file the input was read
+	// from, including the entries that were ignored. Set by callers.
+	Import *ImplementedImport `json:"import,omitempty"`
+	// AsOf is the past time whose tracked implementations the analysis
+	// ran against. Set by callers.
+	AsOf *time.Time `json:"as_of,omitempty"`
 }
 
 // GapDetail provides details about a specific gap.
 type GapDetail struct {
-	ControlID          string   `json:"control_id"`
-	Title              string   `json:"title"`
-	Description        string   `json:"description"`
-	Priority           string   `json:"priority"`
-	EstimatedEffort    string   `json:"estimated_effort"`
-	RemediationOptions []string `json:"remediation_options"`
+	ControlID          string                        `json:"control_id"`
+	Title              string                        `json:"title"`
+	Description        string                        `json:"description"`
+	GapType            string                        `json:"gap_type"`
+	Priority           string                        `json:"priority"`
+	EstimatedEffort    string                        `json:"estimated_effort"`
+	RemediationOptions []string                      `json:"remediation_options"`
+	CoverageScore      float64                       `json:"coverage_score,omitempty"`
+	CoveredBy          []models.CoverageContribution `json:"covered_by,omitempty"`
+	Tags               []string                      `json:"tags,omitempty"`
+	// Disposition is the organization's decision on the gap, if any.
+	Disposition *models.GapDisposition `json:"disposition,omitempty"`
+	// DispositionExpired is set when Disposition accepted or transferred
+	// the risk until a time now past, leaving the gap open again.
+	DispositionExpired bool `json:"disposition_expired,omitempty"`
 }
 
-// GapSummaryOutput provides aggregate statistics.
+// GapSummaryOutput counts the open gaps by priority.
 type GapSummaryOutput struct {
 	Critical int `json:"critical"`
 	High     int `json:"high"`
@@ -81,7 +174,34 @@ func (g *GapAnalyzer) RunAnalysis(ctx context.Context, input *AnalysisInput) (*A
 		return nil, fmt.Errorf("unknown framework: %s", input.TargetFramework)
 	}
 
-	analysis, err := g.service.AnalyzeGaps(ctx, targetFW, input.ImplementedControls)
+	if input.SourceFramework != "" {
+		if _, err := g.service.GetFramework(FrameworkID(input.SourceFramework)); err != nil {
+			return nil, fmt.Errorf("unknown source framework: %s", input.SourceFramework)
+		}
+	}
+
+	scoring := input.Scoring
+	if scoring == nil {
+		scoring = g.ScoringModel()
+	} else if err := scoring.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scoring model: %w", err)
+	}
+
+	inventory, err := g.buildInventory(input.ImplementedControls, input.Providers)
+	if err != nil {
+		return nil, err
+	}
+
+	var failing []ControlStatus
+	if monitor := g.Monitor(); monitor != nil {
+		inventory, failing = applyMonitoring(inventory, monitor.ControlStatuses())
+	}
+
+	tags := make(map[string][]string, len(input.Tags))
+	for id, t := range input.Tags {
+		tags[strings.ToLower(id)] = t
+	}
+	analysis, err := g.service.analyzeGaps(ctx, targetFW, FrameworkID(input.SourceFramework), inventory, scoring, tags)
 	if err != nil {
 		return nil, err
 	}
@@ -104,12 +224,28 @@ func (g *GapAnalyzer) RunAnalysis(ctx context.Context, input *AnalysisInput) (*A
 			ControlID:          gap.ControlID,
 			Title:              ctrl.Title,
 			Description:        ctrl.Description,
+			GapType:            gap.GapType,
 			Priority:           gap.Priority,
 			EstimatedEffort:    gap.EstimatedEffort,
 			RemediationOptions: gap.RemediationOptions,
+			CoverageScore:      gap.CoverageScore,
+			CoveredBy:          gap.CoveredBy,
+			Tags:               tags[strings.ToLower(gap.ControlID)],
 		}
 		gaps = append(gaps, detail)
-
+	}
+	applyDispositions(gaps, input.TargetFramework, input.Dispositions, time.Now())
+
+	accepted, transferred := 0, 0
+	for _, gap := range gaps {
+		if gap.RiskAccepted() {
+			if gap.Disposition.Disposition == models.DispositionTransfer {
+				transferred++
+			} else {
+				accepted++
+			}
+			continue
+		}
 		switch gap.Priority {
 		case "critical":
 			summary.Critical++
@@ -127,10 +263,22 @@ func (g *GapAnalyzer) RunAnalysis(ctx context.Context, input *AnalysisInput) (*A
 		FrameworkName:      fw.Name,
 		TotalControls:      analysis.Summary.TotalControls,
 		ImplementedCount:   analysis.Summary.FullyCovered,
+		PartialCount:       analysis.Summary.PartiallyCovered,
+		CrosswalkCovered:   analysis.Summary.CrosswalkCovered,
+		InheritedCount:     analysis.Summary.InheritedCovered,
 		GapCount:           len(gaps),
 		CoveragePercentage: analysis.Summary.CoveragePercentage,
 		Gaps:               gaps,
 		Summary:            summary,
+		Inventory:          inventory,
+		FailingChecks:      failing,
+
+		AcceptedRiskCount:    accepted,
+		TransferredRiskCount: transferred,
+		OpenGapCount:         len(gaps) - accepted - transferred,
+	}
+	if output.TotalControls > 0 {
+		output.RiskAdjustedCoverage = float64(output.ImplementedCount+accepted+transferred) / float64(output.TotalControls) * 100
 	}
 
 	// Add crosswalk information if source framework specified
@@ -156,6 +304,52 @@ func (g *GapAnalyzer) RunAnalysis(ctx context.Context, input *AnalysisInput) (*A
 	return output, nil
 }
 
+// NewGapAnalysis records an analysis run for an organization so coverage
+// can be tracked across runs.
+func NewGapAnalysis(orgID string, input *AnalysisInput, output *AnalysisOutput, at time.Time) *models.GapAnalysis {
+	gaps := make([]models.ControlGap, len(output.Gaps))
+	for i, g := range output.Gaps {
+		gaps[i] = models.ControlGap{
+			ControlID:          g.ControlID,
+			GapType:            g.GapType,
+			Description:        g.Description,
+			RemediationOptions: g.RemediationOptions,
+			Priority:           g.Priority,
+			EstimatedEffort:    g.EstimatedEffort,
+			CoverageScore:      g.CoverageScore,
+			CoveredBy:          g.CoveredBy,
+		}
+		if g.Disposition != nil && !g.DispositionExpired {
+			gaps[i].Disposition = g.Disposition.Disposition
+		}
+	}
+	return &models.GapAnalysis{
+		ID:                uuid.NewString(),
+		OrganizationID:    orgID,
+		SourceFrameworkID: input.SourceFramework,
+		TargetFrameworkID: output.Framework,
+		AnalysisDate:      at.UTC(),
+		Gaps:              gaps,
+		Summary: models.GapSummary{
+			TotalControls:      output.TotalControls,
+			FullyCovered:       output.ImplementedCount,
+			PartiallyCovered:   output.PartialCount,
+			NotCovered:         output.TotalControls - output.ImplementedCount - output.PartialCount,
+			CrosswalkCovered:   output.CrosswalkCovered,
+			InheritedCovered:   output.InheritedCount,
+			CoveragePercentage: output.CoveragePercentage,
+			AcceptedRisk:       output.AcceptedRiskCount,
+			TransferredRisk:    output.TransferredRiskCount,
+			GapsByPriority: map[string]int{
+				"critical": output.Summary.Critical,
+				"high":     output.Summary.High,
+				"medium":   output.Summary.Medium,
+				"low":      output.Summary.Low,
+			},
+		},
+	}
+}
+
 // PrintReport prints a formatted gap analysis report.
 func (g *GapAnalyzer) PrintReport(w io.Writer, output *AnalysisOutput) {
 	fmt.Fprintf(w, "\n╔══════════════════════════════════════════════════════════════════════════════╗\n")
@@ -169,8 +363,32 @@ func (g *GapAnalyzer) PrintReport(w io.Writer, output *AnalysisOutput) {
 	fmt.Fprintf(w, "────────────────\n")
 	fmt.Fprintf(w, "  Total Controls:      %d\n", output.TotalControls)
 	fmt.Fprintf(w, "  Implemented:         %d\n", output.ImplementedCount)
+	fmt.Fprintf(w, "  Inherited:           %d\n", output.InheritedCount)
+	fmt.Fprintf(w, "  Via Crosswalks:      %d\n", output.CrosswalkCovered)
+	fmt.Fprintf(w, "  Partially Covered:   %d\n", output.PartialCount)
 	fmt.Fprintf(w, "  Gaps Identified:     %d\n", output.GapCount)
-	fmt.Fprintf(w, "  Coverage:            %.1f%%\n\n", output.CoveragePercentage)
+	if output.AcceptedRiskCount+output.TransferredRiskCount > 0 {
+		fmt.Fprintf(w, "  Accepted Risk:       %d\n", output.AcceptedRiskCount)
+		fmt.Fprintf(w, "  Transferred Risk:    %d\n", output.TransferredRiskCount)
+		fmt.Fprintf(w, "  Coverage:            %.1f%%\n", output.CoveragePercentage)
+		fmt.Fprintf(w, "  Risk-Adjusted:       %.1f%%\n\n", output.RiskAdjustedCoverage)
+	} else {
+		fmt.Fprintf(w, "  Coverage:            %.1f%%\n\n", output.CoveragePercentage)
+	}
+
+	if in := output.Input; in != nil {
+		fmt.Fprintf(w, "ANALYSIS INPUT\n")
+		fmt.Fprintf(w, "──────────────\n")
+		fmt.Fprintf(w, "  Implemented:         %s\n", listOrNone(in.ImplementedControls))
+		if in.SourceFramework != "" {
+			fmt.Fprintf(w, "  Source Framework:    %s\n", in.SourceFramework)
+		}
+		fmt.Fprintf(w, "  Providers:           %s\n", listOrNone(in.Providers))
+		if in.Scoring != nil {
+			fmt.Fprintf(w, "  Scoring Model:       custom\n")
+		}
+		fmt.Fprintf(w, "\n")
+	}
 
 	fmt.Fprintf(w, "GAPS BY PRIORITY\n")
 	fmt.Fprintf(w, "────────────────\n")
@@ -184,16 +402,58 @@ func (g *GapAnalyzer) PrintReport(w io.Writer, output *AnalysisOutput) {
 		fmt.Fprintf(w, "═════════════\n\n")
 
 		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-		fmt.Fprintf(tw, "CONTROL ID\tTITLE\tPRIORITY\tEFFORT\n")
-		fmt.Fprintf(tw, "──────────\t─────\t────────\t──────\n")
+		fmt.Fprintf(tw, "CONTROL ID\tTITLE\tPRIORITY\tEFFORT\tCOVERAGE\n")
+		fmt.Fprintf(tw, "──────────\t─────\t────────\t──────\t────────\n")
 
 		for _, gap := range output.Gaps {
 			title := gap.Title
 			if len(title) > 40 {
 				title = title[:37] + "..."
 			}
-			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
-				gap.ControlID, title, gap.Priority, gap.EstimatedEffort)
+			coverage := "-"
+			if gap.CoverageScore > 0 {
+				coverage = fmt.Sprintf("%.0f%%", gap.CoverageScore*100)
+			}
+			priority := gap.Priority
+			if gap.RiskAccepted() {
+				priority += " (" + string(gap.Disposition.Disposition) + ")"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+				gap.ControlID, title, priority, gap.EstimatedEffort, coverage)
+		}
+		tw.Flush()
+	}
+
+	inherited := []models.ImplementedControl{}
+	for _, c := range output.Inventory {
+		if c.Source != models.ImplementationLocal {
+			inherited = append(inherited, c)
+		}
+	}
+	if len(inherited) > 0 {
+		fmt.Fprintf(w, "\n\nINHERITED CONTROLS\n")
+		fmt.Fprintf(w, "══════════════════\n\n")
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "CONTROL ID\tSOURCE\tPROVIDERS\n")
+		fmt.Fprintf(tw, "──────────\t──────\t─────────\n")
+
+		for _, c := range inherited {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", c.ControlID, c.Source, strings.Join(c.ProviderIDs, ", "))
+		}
+		tw.Flush()
+	}
+
+	if len(output.FailingChecks) > 0 {
+		fmt.Fprintf(w, "\n\nFAILING MONITORING CHECKS\n")
+		fmt.Fprintf(w, "═════════════════════════\n\n")
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "CONTROL ID\tCHECKS\tLAST RUN\n")
+		fmt.Fprintf(tw, "──────────\t──────\t────────\n")
+
+		for _, s := range output.FailingChecks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.ControlID, strings.Join(s.Checks, ", "), s.VerifiedAt.Format(time.RFC3339))
 		}
 		tw.Flush()
 	}
@@ -216,6 +476,13 @@ func (g *GapAnalyzer) PrintReport(w io.Writer, output *AnalysisOutput) {
 	fmt.Fprintf(w, "\n")
 }
 
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, ", ")
+}
+
 // PrintJSON prints the analysis as JSON.
 func (g *GapAnalyzer) PrintJSON(w io.Writer, output *AnalysisOutput) error {
 	encoder := json.NewEncoder(w)
@@ -228,20 +495,92 @@ func (g *GapAnalyzer) ListFrameworks(w io.Writer) {
 	fmt.Fprintf(w, "\nAvailable Control Frameworks:\n")
 	fmt.Fprintf(w, "══════════════════════════════\n\n")
 
-	frameworks := g.service.ListFrameworks()
+	frameworks := g.Frameworks()
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(tw, "ID\tNAME\tVERSION\n")
-	fmt.Fprintf(tw, "──\t────\t───────\n")
+	fmt.Fprintf(tw, "ID\tNAME\tVERSION\tCONTROLS\n")
+	fmt.Fprintf(tw, "──\t────\t───────\t────────\n")
 
 	for _, fw := range frameworks {
-		fmt.Fprintf(tw, "%s\t%s\t%s\n", fw.ID, fw.Name, fw.Version)
+		controls, _ := g.Controls(fw.ID)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", fw.ID, fw.Name, fw.Version, len(controls))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n")
+}
+
+// ListControls writes a framework's controls grouped by family.
+func (g *GapAnalyzer) ListControls(w io.Writer, framework string) error {
+	fw, err := g.service.GetFramework(FrameworkID(framework))
+	if err != nil {
+		return fmt.Errorf("unknown framework: %s", framework)
+	}
+	controls, err := g.service.GetControls(FrameworkID(framework))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%s %s: %d controls\n", fw.Name, fw.Version, len(controls))
+	fmt.Fprintf(w, "══════════════════════════════\n")
+	if err := g.RequireFullText(framework); err != nil {
+		fmt.Fprintf(w, "Note: %v\n", err)
+	}
+	var families []string
+	byFamily := make(map[string][]models.Control)
+	for _, c := range controls {
+		if _, ok := byFamily[c.Family]; !ok {
+			families = append(families, c.Family)
+		}
+		byFamily[c.Family] = append(byFamily[c.Family], c)
+	}
+
+	parents := make(map[string]string, len(controls))
+	for _, c := range controls {
+		if c.ParentControlID != nil {
+			parents[c.ControlID] = *c.ParentControlID
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, family := range families {
+		fmt.Fprintf(tw, "\n%s\n", family)
+		for _, c := range byFamily[family] {
+			// Indent by depth; custom frameworks may nest sub-controls.
+			indent := ""
+			for id, n := c.ControlID, 0; parents[id] != "" && n < len(controls); id, n = parents[id], n+1 {
+				indent += "  "
+			}
+			fmt.Fprintf(tw, "  %s%s\t%s\n", indent, c.ControlID, c.Title)
+		}
 	}
 	tw.Flush()
 	fmt.Fprintf(w, "\n")
+	return nil
+}
+
+// Frameworks returns the loaded frameworks, built in and from the data
+// directory, sorted by ID.
+func (g *GapAnalyzer) Frameworks() []*models.Framework {
+	frameworks := g.service.ListFrameworks()
+	sort.Slice(frameworks, func(i, j int) bool { return frameworks[i].ID < frameworks[j].ID })
+	return frameworks
+}
+
+// Framework returns a loaded framework.
+func (g *GapAnalyzer) Framework(id string) (*models.Framework, bool) {
+	fw, err := g.service.GetFramework(FrameworkID(id))
+	return fw, err == nil
+}
+
+// Controls returns a loaded framework's controls.
+func (g *GapAnalyzer) Controls(framework string) ([]models.Control, bool) {
+	controls, err := g.service.GetControls(FrameworkID(framework))
+	return controls, err == nil
 }
 
 // GenerateCrosswalkReport generates a crosswalk report between two frameworks.
-func (g *GapAnalyzer) GenerateCrosswalkReport(w io.Writer, source, target string) error {
+// When includeDerived is set, transitive mappings are listed after the
+// direct ones even if direct mappings exist.
+func (g *GapAnalyzer) GenerateCrosswalkReport(w io.Writer, source, target string, includeDerived bool) error {
 	sourceFW := FrameworkID(source)
 	targetFW := FrameworkID(target)
 
@@ -255,7 +594,7 @@ func (g *GapAnalyzer) GenerateCrosswalkReport(w io.Writer, source, target string
 		return fmt.Errorf("unknown target framework: %s", target)
 	}
 
-	crosswalks, err := g.service.GetCrosswalks(sourceFW, targetFW)
+	crosswalks, err := g.Crosswalks(source, target, includeDerived)
 	if err != nil {
 		return err
 	}
@@ -276,12 +615,16 @@ func (g *GapAnalyzer) GenerateCrosswalkReport(w io.Writer, source, target string
 	fmt.Fprintf(w, "Found %d control mappings:\n\n", len(crosswalks))
 
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(tw, "SOURCE CONTROL\tTARGET CONTROL\tMAPPING TYPE\tCONFIDENCE\n")
-	fmt.Fprintf(tw, "──────────────\t──────────────\t────────────\t──────────\n")
+	fmt.Fprintf(tw, "SOURCE CONTROL\tTARGET CONTROL\tMAPPING TYPE\tCONFIDENCE\tDERIVED VIA\n")
+	fmt.Fprintf(tw, "──────────────\t──────────────\t────────────\t──────────\t───────────\n")
 
 	for _, xw := range crosswalks {
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%.0f%%\n",
-			xw.SourceControlID, xw.TargetControlID, xw.MappingType, xw.Confidence*100)
+		via := "-"
+		if xw.Derived {
+			via = strings.Join(xw.Via, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.0f%%\t%s\n",
+			xw.SourceControlID, xw.TargetControlID, xw.MappingType, xw.Confidence*100, via)
 	}
 	tw.Flush()
 
@@ -290,22 +633,67 @@ func (g *GapAnalyzer) GenerateCrosswalkReport(w io.Writer, source, target string
 	fmt.Fprintf(w, "  partial  - Some overlap exists\n")
 	fmt.Fprintf(w, "  superset - Source includes target\n")
 	fmt.Fprintf(w, "  subset   - Target includes source\n")
-	fmt.Fprintf(w, "  related  - Controls address similar topics\n\n")
+	fmt.Fprintf(w, "  related  - Controls address similar topics\n")
+	fmt.Fprintf(w, "\nDerived mappings chain two direct mappings and multiply their\n")
+	fmt.Fprintf(w, "confidence; review them before relying on them.\n\n")
 
 	return nil
 }
 
+// Crosswalks returns the source→target mappings, optionally with derived
+// mappings for control pairs without a direct one.
+func (g *GapAnalyzer) Crosswalks(source, target string, includeDerived bool) ([]models.Crosswalk, error) {
+	sourceFW, targetFW := FrameworkID(source), FrameworkID(target)
+	crosswalks, err := g.service.GetCrosswalks(sourceFW, targetFW)
+	if err != nil {
+		return nil, err
+	}
+	if includeDerived && (len(crosswalks) == 0 || !crosswalks[0].Derived) {
+		derived, err := g.service.DeriveCrosswalks(sourceFW, targetFW)
+		if err != nil {
+			return nil, err
+		}
+		crosswalks = append(crosswalks, derived...)
+	}
+	return crosswalks, nil
+}
+
+// DeriveCrosswalks returns transitive source→target mappings for control
+// pairs without a direct mapping.
+func (g *GapAnalyzer) DeriveCrosswalks(source, target string) ([]models.Crosswalk, error) {
+	if _, err := g.service.GetFramework(FrameworkID(source)); err != nil {
+		return nil, err
+	}
+	if _, err := g.service.GetFramework(FrameworkID(target)); err != nil {
+		return nil, err
+	}
+	return g.service.DeriveCrosswalks(FrameworkID(source), FrameworkID(target))
+}
+
 // LoadInputFromFile loads analysis input from a JSON file.
 func LoadInputFromFile(path string) (*AnalysisInput, error) {
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
+	return LoadInput(f)
+}
+
+// LoadInput reads analysis input as JSON. Unknown fields are rejected so
+// typos in version-controlled input files do not silently change results.
+func LoadInput(r io.Reader) (*AnalysisInput, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
 
 	var input AnalysisInput
-	if err := json.Unmarshal(data, &input); err != nil {
-		return nil, err
+	if err := dec.Decode(&input); err != nil {
+		return nil, fmt.Errorf("parsing analysis input: %w", err)
+	}
+	if input.Scoring != nil {
+		if err := input.Scoring.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid scoring model: %w", err)
+		}
 	}
-
 	return &input, nil
 }
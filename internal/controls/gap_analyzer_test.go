@@ -0,0 +1,196 @@
+package controls_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func findGap(out *controls.AnalysisOutput, controlID string) *controls.GapDetail {
+	for i := range out.Gaps {
+		if out.Gaps[i].ControlID == controlID {
+			return &out.Gaps[i]
+		}
+	}
+	return nil
+}
+
+func TestCrosswalkCoverage(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		implemented     []string
+		wantCovered     int
+		wantPartialGap  string
+		wantMissingGaps []string
+	}{
+		{
+			name:        "exact high-confidence mappings fully cover target controls",
+			implemented: []string{"GOVERN-2", "MAP-2"},
+			wantCovered: 2,
+			wantMissingGaps: []string{
+				"ISO42001-5.3", // GOVERN-2 -> exact 0.9
+				"ISO42001-8.2", // MAP-2 -> exact 0.9
+			},
+		},
+		{
+			name:           "partial mappings leave a partial gap",
+			implemented:    []string{"GOVERN-5"},
+			wantCovered:    0,
+			wantPartialGap: "ISO42001-7.4",
+		},
+		{
+			name:           "reverse mappings credit the target side",
+			implemented:    []string{"AU-6"},
+			wantCovered:    0,
+			wantPartialGap: "ISO42001-9.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+				TargetFramework:     "iso-42001",
+				ImplementedControls: tt.implemented,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.CrosswalkCovered != tt.wantCovered {
+				t.Errorf("crosswalk covered = %d, want %d", out.CrosswalkCovered, tt.wantCovered)
+			}
+			for _, id := range tt.wantMissingGaps {
+				if findGap(out, id) != nil {
+					t.Errorf("expected %s to be covered, found gap", id)
+				}
+			}
+			if tt.wantPartialGap != "" {
+				gap := findGap(out, tt.wantPartialGap)
+				if gap == nil {
+					t.Fatalf("expected partial gap for %s", tt.wantPartialGap)
+				}
+				if gap.GapType != "partial" || gap.CoverageScore <= 0 || len(gap.CoveredBy) == 0 {
+					t.Errorf("gap %s = %+v, want partial with coverage", gap.ControlID, gap)
+				}
+			}
+		})
+	}
+}
+
+func TestSourceFrameworkDerivedCoverage(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	// NIST 800-53 has no direct mappings to the EU AI Act; credit flows
+	// only through derived crosswalks, and only when it is the source.
+	all, _ := analyzer.Controls("nist-800-53")
+	implemented := make([]string, 0, len(all))
+	for _, c := range all {
+		implemented = append(implemented, c.ControlID)
+	}
+	run := func(source string) *controls.AnalysisOutput {
+		out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+			TargetFramework:     "eu-ai-act",
+			SourceFramework:     source,
+			ImplementedControls: implemented,
+		})
+		if err != nil {
+			t.Fatalf("RunAnalysis(source %q): %v", source, err)
+		}
+		return out
+	}
+
+	if out := run(""); out.PartialCount != 0 || out.CrosswalkCovered != 0 {
+		t.Fatalf("without a source: partial %d, crosswalk covered %d; want no credit", out.PartialCount, out.CrosswalkCovered)
+	}
+	out := run("nist-800-53")
+	if out.PartialCount == 0 {
+		t.Fatal("source framework earned no credit through derived crosswalks")
+	}
+	if out.CrosswalkCovered != 0 {
+		t.Errorf("derived crosswalks fully covered %d controls", out.CrosswalkCovered)
+	}
+	for _, gap := range out.Gaps {
+		for _, cb := range gap.CoveredBy {
+			if !cb.Derived || cb.FrameworkID != "nist-800-53" || cb.Credit <= 0 {
+				t.Errorf("%s covered by %+v, want derived credit from nist-800-53", gap.ControlID, cb)
+			}
+		}
+	}
+
+	if _, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{TargetFramework: "eu-ai-act", SourceFramework: "nist-9000"}); err == nil {
+		t.Error("unknown source framework accepted")
+	}
+}
+
+func TestLoadInput(t *testing.T) {
+	in, err := controls.LoadInput(strings.NewReader(`{"target_framework":"iso-42001","implemented_controls":["ISO42001-4.1"],"providers":["cloud"]}`))
+	if err != nil {
+		t.Fatalf("LoadInput: %v", err)
+	}
+	if in.TargetFramework != "iso-42001" || len(in.ImplementedControls) != 1 || len(in.Providers) != 1 {
+		t.Errorf("input = %+v", in)
+	}
+
+	if _, err := controls.LoadInput(strings.NewReader(`{"target":"iso-42001"}`)); err == nil {
+		t.Error("unknown field accepted")
+	}
+	if _, err := controls.LoadInput(strings.NewReader(`{"target_framework":"iso-42001","scoring":{}}`)); err == nil {
+		t.Error("invalid scoring model accepted")
+	}
+}
+
+func TestNewGapAnalysis(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	input := &controls.AnalysisInput{
+		TargetFramework:     "iso-42001",
+		SourceFramework:     "nist-ai-rmf",
+		ImplementedControls: []string{"ISO42001-4.1", "GOVERN-5"},
+	}
+	out, err := analyzer.RunAnalysis(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	ga := controls.NewGapAnalysis("acme", input, out, at)
+	if ga.ID == "" {
+		t.Error("expected an ID")
+	}
+	if ga.OrganizationID != "acme" || ga.SourceFrameworkID != "nist-ai-rmf" || ga.TargetFrameworkID != "iso-42001" {
+		t.Errorf("analysis = %s %s -> %s, want acme nist-ai-rmf -> iso-42001", ga.OrganizationID, ga.SourceFrameworkID, ga.TargetFrameworkID)
+	}
+	if !ga.AnalysisDate.Equal(at) || ga.AnalysisDate.Location() != time.UTC {
+		t.Errorf("analysis date = %v, want %v in UTC", ga.AnalysisDate, at)
+	}
+	if len(ga.Gaps) != len(out.Gaps) {
+		t.Fatalf("gaps = %d, want %d", len(ga.Gaps), len(out.Gaps))
+	}
+	for i, g := range out.Gaps {
+		if ga.Gaps[i].ControlID != g.ControlID || ga.Gaps[i].Priority != g.Priority || ga.Gaps[i].CoverageScore != g.CoverageScore {
+			t.Errorf("gap %d = %+v, want %+v", i, ga.Gaps[i], g)
+		}
+	}
+
+	s := ga.Summary
+	if s.TotalControls != out.TotalControls || s.CoveragePercentage != out.CoveragePercentage {
+		t.Errorf("summary = %+v, want totals from %+v", s, out)
+	}
+	if s.FullyCovered+s.PartiallyCovered+s.NotCovered != s.TotalControls {
+		t.Errorf("summary counts %d+%d+%d do not add up to %d", s.FullyCovered, s.PartiallyCovered, s.NotCovered, s.TotalControls)
+	}
+	if s.GapsByPriority["critical"] != out.Summary.Critical || s.GapsByPriority["low"] != out.Summary.Low {
+		t.Errorf("gaps by priority = %v, want %+v", s.GapsByPriority, out.Summary)
+	}
+}
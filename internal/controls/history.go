@@ -0,0 +1,103 @@
+package controls
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CoveragePoint is a framework's coverage percentage at the time of a gap
+// analysis.
+type CoveragePoint struct {
+	Time     time.Time `json:"time"`
+	Coverage float64   `json:"coverage"`
+}
+
+// CoverageHistory records coverage results per organization and framework so
+// coverage can be charted over time. It is in-memory and keeps the most
+// recent points per series.
+type CoverageHistory struct {
+	mu        sync.RWMutex
+	maxPoints int
+	series    map[coverageKey][]CoveragePoint
+}
+
+type coverageKey struct {
+	org       string
+	framework string
+}
+
+// NewCoverageHistory creates a history keeping up to maxPoints points per
+// organization and framework. A non-positive maxPoints keeps 1000.
+func NewCoverageHistory(maxPoints int) *CoverageHistory {
+	if maxPoints <= 0 {
+		maxPoints = 1000
+	}
+	return &CoverageHistory{maxPoints: maxPoints, series: make(map[coverageKey][]CoveragePoint)}
+}
+
+// Record appends an analysis result. Points are kept in time order.
+func (h *CoverageHistory) Record(orgID, framework string, at time.Time, coverage float64) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := coverageKey{orgID, framework}
+	points := append(h.series[key], CoveragePoint{Time: at.UTC(), Coverage: coverage})
+	if n := len(points); n > 1 && points[n-1].Time.Before(points[n-2].Time) {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	}
+	if len(points) > h.maxPoints {
+		points = append([]CoveragePoint(nil), points[len(points)-h.maxPoints:]...)
+	}
+	h.series[key] = points
+}
+
+// Range returns the points recorded in [from, to).
+func (h *CoverageHistory) Range(orgID, framework string, from, to time.Time) []CoveragePoint {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var out []CoveragePoint
+	for _, p := range h.series[coverageKey{orgID, framework}] {
+		if !p.Time.Before(from) && p.Time.Before(to) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Latest returns the most recent point for a framework.
+func (h *CoverageHistory) Latest(orgID, framework string) (CoveragePoint, bool) {
+	if h == nil {
+		return CoveragePoint{}, false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	points := h.series[coverageKey{orgID, framework}]
+	if len(points) == 0 {
+		return CoveragePoint{}, false
+	}
+	return points[len(points)-1], true
+}
+
+// Frameworks returns the frameworks with recorded coverage for an
+// organization, sorted.
+func (h *CoverageHistory) Frameworks(orgID string) []string {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var out []string
+	for key := range h.series {
+		if key.org == orgID {
+			out = append(out, key.framework)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,385 @@
+package controls_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/agentguard/agentguard/pkg/client"
+)
+
+func TestCoverageHistory(t *testing.T) {
+	h := controls.NewCoverageHistory(2)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.Record("org", "iso-42001", base.Add(2*time.Hour), 30)
+	h.Record("org", "iso-42001", base, 10) // out of order
+	h.Record("org", "iso-42001", base.Add(time.Hour), 20)
+	h.Record("other", "nist-ai-rmf", base, 90)
+
+	got := h.Range("org", "iso-42001", base, base.Add(3*time.Hour))
+	if len(got) != 2 || got[0].Coverage != 20 || got[1].Coverage != 30 {
+		t.Errorf("Range = %+v, want the two newest points in order", got)
+	}
+	if p, ok := h.Latest("org", "iso-42001"); !ok || p.Coverage != 30 {
+		t.Errorf("Latest = %+v, %v", p, ok)
+	}
+	if fws := h.Frameworks("org"); len(fws) != 1 || fws[0] != "iso-42001" {
+		t.Errorf("Frameworks = %v, want only this org's framework", fws)
+	}
+
+	var nilHistory *controls.CoverageHistory
+	nilHistory.Record("org", "iso-42001", base, 1)
+	if _, ok := nilHistory.Latest("org", "iso-42001"); ok {
+		t.Error("nil history returned a point")
+	}
+}
+
+func TestBuildPosture(t *testing.T) {
+	now := time.Date(2027, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return now.AddDate(0, 0, -d) }
+	analysis := func(id, fw string, at time.Time, coverage float64, priorities ...string) models.GapAnalysis {
+		ga := models.GapAnalysis{ID: id, TargetFrameworkID: fw, AnalysisDate: at}
+		ga.Summary.CoveragePercentage = coverage
+		for _, p := range priorities {
+			ga.Gaps = append(ga.Gaps, models.ControlGap{Priority: p})
+		}
+		return ga
+	}
+	attestedAt, checkedAt := day(200), day(3)
+
+	p := controls.BuildPosture(controls.PostureInput{
+		OrganizationID: "acme",
+		Analyses: []models.GapAnalysis{
+			analysis("latest", "iso-42001", day(1), 60, "high", "low"),
+			analysis("old", "iso-42001", day(400), 10, "critical"),
+			analysis("morning", "iso-42001", day(10).Add(-time.Hour), 30),
+			analysis("evening", "iso-42001", day(10), 40),
+			analysis("only", "nist-ai-rmf", day(120), 80, "medium", "bogus"),
+		},
+		FrameworkNames: map[string]string{"iso-42001": "ISO/IEC 42001"},
+		Implementations: []models.ControlImplementation{
+			{ID: "i1", FrameworkID: "iso-42001", ControlID: "A-1", Status: models.ImplementationVerified},
+			{ID: "i2", FrameworkID: "iso-42001", ControlID: "A-2", Status: models.ImplementationImplemented},
+			{ID: "i3", FrameworkID: "iso-42001", ControlID: "A-3", Status: models.ImplementationImplemented},
+			{ID: "i4", FrameworkID: "iso-42001", ControlID: "A-4", Status: models.ImplementationPlanned},
+		},
+		Attestations: []models.Attestation{
+			{ImplementationID: "i1", Status: models.AttestationAttested, RespondedAt: &attestedAt},
+			{ImplementationID: "i2", Status: models.AttestationAttested, RespondedAt: &attestedAt},
+		},
+		Checks: map[string]controls.ControlStatus{
+			"a-1": {ControlID: "A-1", Status: controls.CheckPassed, VerifiedAt: checkedAt},
+			"a-3": {ControlID: "A-3", Status: controls.CheckFailed, VerifiedAt: checkedAt},
+		},
+		Since: day(90),
+		Now:   now,
+	})
+
+	if len(p.Frameworks) != 2 || p.Frameworks[0].Framework != "iso-42001" {
+		t.Fatalf("frameworks = %+v", p.Frameworks)
+	}
+	iso := p.Frameworks[0]
+	if iso.Coverage != 60 || iso.LatestAnalysis != "latest" || iso.FrameworkName != "ISO/IEC 42001" {
+		t.Errorf("iso = %+v", iso)
+	}
+	// One point per day within the window, the day's last analysis.
+	if len(iso.Trend) != 2 || iso.Trend[0].Coverage != 40 || iso.Change != 20 {
+		t.Errorf("trend = %+v, change %v", iso.Trend, iso.Change)
+	}
+	// The latest analysis counts even when it is older than the window.
+	nist := p.Frameworks[1]
+	if nist.Coverage != 80 || len(nist.Trend) != 0 || nist.OpenGaps["low"] != 1 {
+		t.Errorf("nist = %+v", nist)
+	}
+	if p.TotalOpenGaps != 4 || p.OpenGaps["high"] != 1 || p.OpenGaps["critical"] != 0 || p.OpenGaps["low"] != 2 {
+		t.Errorf("open gaps = %v (%d)", p.OpenGaps, p.TotalOpenGaps)
+	}
+
+	ev := p.Evidence
+	if ev == nil || ev.Total != 3 || ev.Fresh != 1 || ev.Stale != 1 || ev.Missing != 1 || ev.FreshPercentage != 33.3 {
+		t.Fatalf("evidence = %+v", ev)
+	}
+	if len(ev.Attention) != 2 || ev.Attention[0].ControlID != "A-3" || ev.Attention[1].ControlID != "A-2" ||
+		ev.Attention[1].Source != controls.EvidenceAttestation || ev.Attention[1].AgeDays != 200 {
+		t.Errorf("attention = %+v", ev.Attention)
+	}
+
+	// As of 50 days ago: the later analyses and the day(3) check are not
+	// known, and the 200-day-old attestations are 150 days old.
+	past := controls.BuildPosture(controls.PostureInput{
+		Analyses: []models.GapAnalysis{
+			analysis("latest", "iso-42001", day(1), 60),
+			analysis("evening", "iso-42001", day(10), 40),
+			analysis("only", "nist-ai-rmf", day(120), 80),
+		},
+		Implementations: []models.ControlImplementation{
+			{ID: "i1", FrameworkID: "iso-42001", ControlID: "A-1", Status: models.ImplementationVerified},
+		},
+		Attestations: []models.Attestation{
+			{ImplementationID: "i1", Status: models.AttestationAttested, RespondedAt: &attestedAt},
+		},
+		Since: day(140),
+		Now:   now,
+		AsOf:  day(50),
+	})
+	if past.AsOf == nil || !past.AsOf.Equal(day(50)) || len(past.Frameworks) != 1 || past.Frameworks[0].Framework != "nist-ai-rmf" {
+		t.Errorf("posture as of = %+v", past)
+	}
+	if ev := past.Evidence; ev == nil || ev.Stale != 1 || ev.Attention[0].AgeDays != 150 {
+		t.Errorf("evidence as of = %+v", ev)
+	}
+
+	if p := controls.BuildPosture(controls.PostureInput{Now: now}); p.Evidence != nil || len(p.Frameworks) != 0 {
+		t.Errorf("empty posture = %+v", p)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC) // a Saturday
+	for _, tc := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 6 * * *", time.Date(2026, 3, 15, 6, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 10 14 3 *", time.Date(2027, 3, 14, 10, 30, 0, 0, time.UTC)},
+		// Both day fields restricted: the 20th or any Sunday.
+		{"0 0 20 * 7", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		s, err := controls.ParseSchedule(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) error = %v", tc.expr, err)
+		}
+		if got := s.Next(from); !got.Equal(tc.want) {
+			t.Errorf("ParseSchedule(%q).Next = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := controls.ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) error = nil", expr)
+		}
+	}
+}
+
+func TestDiffGapAnalyses(t *testing.T) {
+	prev := &models.GapAnalysis{ID: "a1", TargetFrameworkID: "owasp-llm-top10", Gaps: []models.ControlGap{
+		{ControlID: "LLM01"}, {ControlID: "LLM02"},
+	}}
+	cur := &models.GapAnalysis{ID: "a2", TargetFrameworkID: "owasp-llm-top10", Gaps: []models.ControlGap{
+		{ControlID: "llm02"}, {ControlID: "LLM05"}, {ControlID: "LLM03"},
+	}}
+	d := controls.DiffGapAnalyses(prev, cur)
+	if d.AnalysisID != "a2" || d.PreviousAnalysisID != "a1" || !d.Changed() {
+		t.Fatalf("DiffGapAnalyses = %+v", d)
+	}
+	if len(d.Opened) != 2 || d.Opened[0].ControlID != "LLM03" || d.Opened[1].ControlID != "LLM05" {
+		t.Errorf("Opened = %+v, want LLM03 and LLM05", d.Opened)
+	}
+	if len(d.Closed) != 1 || d.Closed[0].ControlID != "LLM01" {
+		t.Errorf("Closed = %+v, want LLM01", d.Closed)
+	}
+	if controls.DiffGapAnalyses(cur, cur).Changed() {
+		t.Error("DiffGapAnalyses of an analysis with itself changed")
+	}
+}
+
+func TestScheduledAnalysesRunOnce(t *testing.T) {
+	ctx := context.Background()
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("NewGapAnalyzer() error = %v", err)
+	}
+	const secret = "scheduled-analysis-secret"
+	verifier := client.NewWebhookVerifier(secret, 0)
+	var received []controls.GapDiff
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := verifier.VerifyRequest(r)
+		if err != nil {
+			t.Errorf("verifying webhook: %v", err)
+		}
+		var d controls.GapDiff
+		if err := json.Unmarshal(body, &d); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+		received = append(received, d)
+	}))
+	defer srv.Close()
+
+	analyses := memory.NewGapAnalysisRepository()
+	impls := memory.NewControlImplementationRepository()
+	coverage := controls.NewCoverageHistory(0)
+	hook, err := webhook.New(srv.URL, secret)
+	if err != nil {
+		t.Fatalf("webhook.New() error = %v", err)
+	}
+	s := controls.NewScheduledAnalyses(analyzer, analyses, impls, nil, nil, coverage, hook)
+	frameworks := []string{"owasp-llm-top10"}
+
+	diffs, err := s.RunOnce(ctx, "org-1", frameworks)
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(diffs) != 0 || len(received) != 0 {
+		t.Fatalf("first run diffs = %d, webhooks = %d, want none", len(diffs), len(received))
+	}
+
+	// Unchanged implementations: a diff, but nothing to send.
+	if diffs, err = s.RunOnce(ctx, "org-1", frameworks); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Changed() || len(received) != 0 {
+		t.Fatalf("unchanged run diffs = %+v, webhooks = %d", diffs, len(received))
+	}
+
+	if err := impls.Create(ctx, &models.ControlImplementation{
+		OrganizationID: "org-1", FrameworkID: "owasp-llm-top10", ControlID: "LLM01", Status: models.ImplementationImplemented,
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if diffs, err = s.RunOnce(ctx, "org-1", frameworks); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(diffs) != 1 || len(diffs[0].Closed) != 1 || diffs[0].Closed[0].ControlID != "LLM01" || len(diffs[0].Opened) != 0 {
+		t.Fatalf("diffs = %+v, want LLM01 closed", diffs)
+	}
+	if len(received) != 1 || received[0].Closed[0].ControlID != "LLM01" || received[0].Coverage <= received[0].PreviousCoverage {
+		t.Errorf("webhook received %+v", received)
+	}
+
+	stored, _ := analyses.List(ctx, "org-1")
+	if len(stored) != 3 {
+		t.Errorf("stored analyses = %d, want 3", len(stored))
+	}
+	if _, ok := coverage.Latest("org-1", "owasp-llm-top10"); !ok {
+		t.Error("coverage not recorded")
+	}
+	if _, err := s.RunOnce(ctx, "org-1", []string{"nist-9000"}); err == nil {
+		t.Error("RunOnce(unknown framework) error = nil")
+	}
+}
+
+func TestScheduledAnalysesDispositions(t *testing.T) {
+	ctx := context.Background()
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("NewGapAnalyzer() error = %v", err)
+	}
+	analyses := memory.NewGapAnalysisRepository()
+	dispositions := memory.NewGapDispositionRepository()
+	s := controls.NewScheduledAnalyses(analyzer, analyses, memory.NewControlImplementationRepository(), dispositions, nil, nil, nil)
+	frameworks := []string{"owasp-llm-top10"}
+	if _, err := s.RunOnce(ctx, "org-1", frameworks); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+
+	expires := time.Now().Add(30 * 24 * time.Hour)
+	if err := dispositions.Create(ctx, &models.GapDisposition{
+		OrganizationID: "org-1", FrameworkID: "owasp-llm-top10", ControlID: "LLM01",
+		Disposition: models.DispositionAccept, Approver: "ciso", ExpiresAt: &expires,
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	diffs, err := s.RunOnce(ctx, "org-1", frameworks)
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(diffs) != 1 || len(diffs[0].Opened) != 0 || len(diffs[0].Closed) != 1 || diffs[0].Closed[0].ControlID != "LLM01" {
+		t.Fatalf("diffs = %+v, want LLM01 closed by the accepted risk", diffs)
+	}
+
+	stored, err := analyses.Get(ctx, diffs[0].AnalysisID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.Summary.AcceptedRisk != 1 {
+		t.Errorf("accepted risk = %d, want 1", stored.Summary.AcceptedRisk)
+	}
+	for _, g := range stored.Gaps {
+		if g.ControlID == "LLM01" && g.Disposition != models.DispositionAccept {
+			t.Errorf("LLM01 disposition = %q, want accept", g.Disposition)
+		}
+	}
+
+	// Unchanged dispositions: nothing opens or closes.
+	if diffs, err = s.RunOnce(ctx, "org-1", frameworks); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Changed() {
+		t.Errorf("unchanged run diffs = %+v", diffs)
+	}
+}
+
+func TestScheduledAnalysesTags(t *testing.T) {
+	ctx := context.Background()
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("NewGapAnalyzer() error = %v", err)
+	}
+	m := controls.DefaultScoringModel()
+	m.Priority.TagPriorities = map[string]string{"crown-jewel": "critical"}
+	if err := analyzer.SetScoringModel(m); err != nil {
+		t.Fatalf("SetScoringModel() error = %v", err)
+	}
+	analyses := memory.NewGapAnalysisRepository()
+	tags := memory.NewControlRepository()
+	s := controls.NewScheduledAnalyses(analyzer, analyses, memory.NewControlImplementationRepository(), nil, tags, nil, nil)
+	frameworks := []string{"owasp-llm-top10"}
+	// priorities runs the analysis and returns its gaps' priorities.
+	priorities := func() map[string]string {
+		t.Helper()
+		before, err := analyses.List(ctx, "org-1")
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if _, err := s.RunOnce(ctx, "org-1", frameworks); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+		stored, err := analyses.List(ctx, "org-1")
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		out := make(map[string]string)
+		for _, ga := range stored {
+			if slices.ContainsFunc(before, func(old models.GapAnalysis) bool { return old.ID == ga.ID }) {
+				continue
+			}
+			for _, g := range ga.Gaps {
+				out[g.ControlID] = g.Priority
+			}
+		}
+		return out
+	}
+
+	untagged := ""
+	for id, p := range priorities() {
+		if p != "critical" {
+			untagged = id
+			break
+		}
+	}
+	if untagged == "" {
+		t.Fatal("every gap is already critical")
+	}
+	if err := tags.SetControlTags(ctx, &models.ControlTags{
+		OrganizationID: "org-1", FrameworkID: "owasp-llm-top10", ControlID: untagged, Tags: []string{"crown-jewel"},
+	}); err != nil {
+		t.Fatalf("SetControlTags() error = %v", err)
+	}
+	if got := priorities()[untagged]; got != "critical" {
+		t.Errorf("%s priority = %q, want critical from its tag", untagged, got)
+	}
+}
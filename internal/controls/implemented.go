@@ -0,0 +1,345 @@
+package controls
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Implemented-controls file formats.
+const (
+	ImportCSV  = "csv"
+	ImportJSON = "json"
+)
+
+// Reasons an implemented-controls file entry is ignored.
+const (
+	IgnoredUnknown        = "unknown_control"
+	IgnoredDuplicate      = "duplicate"
+	IgnoredNotImplemented = "not_implemented"
+)
+
+// idColumns are the column names recognized as holding control IDs when no
+// column is mapped, in order of preference.
+var idColumns = []string{"control_id", "control id", "controlid", "control", "id"}
+
+// implementedStatuses are the status values that count a control as
+// implemented when a status column is mapped.
+var implementedStatuses = map[string]bool{
+	"implemented": true,
+	"in place":    true,
+	"complete":    true,
+	"completed":   true,
+	"done":        true,
+	"yes":         true,
+	"y":           true,
+	"true":        true,
+	"1":           true,
+}
+
+// ImportOptions maps the columns of an implemented-controls file. For JSON
+// files the columns are object fields.
+type ImportOptions struct {
+	// IDColumn holds control IDs. When empty, the first of control_id,
+	// control id, control or id found in the header is used; a CSV file
+	// without any of them is read as one ID per line from the first column.
+	IDColumn string
+	// StatusColumn, when set, counts only entries whose status is
+	// implemented, in place, complete, done, yes or true.
+	StatusColumn string
+}
+
+// IgnoredEntry is an implemented-controls file entry left out of the
+// analysis.
+type IgnoredEntry struct {
+	// Entry is the CSV line or the 1-based JSON array index.
+	Entry   int    `json:"entry"`
+	Value   string `json:"value"`
+	Reason  string `json:"reason"`
+	Details string `json:"details,omitempty"`
+}
+
+// ImplementedImport is the result of importing implemented controls from a
+// file.
+type ImplementedImport struct {
+	Source   string         `json:"source,omitempty"`
+	Imported int            `json:"imported"`
+	Ignored  []IgnoredEntry `json:"ignored,omitempty"`
+	// Controls are the imported control IDs, as the frameworks spell them.
+	Controls []string `json:"-"`
+}
+
+// ImportFormat infers a file's format from its name, defaulting to CSV.
+func ImportFormat(name string) string {
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return ImportJSON
+	}
+	return ImportCSV
+}
+
+// ImportImplementedFile imports implemented controls from a CSV or JSON
+// file, by extension.
+func (g *GapAnalyzer) ImportImplementedFile(path string, opts ImportOptions) (*ImplementedImport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	imp, err := g.ImportImplemented(f, ImportFormat(path), opts)
+	if err != nil {
+		return nil, err
+	}
+	imp.Source = filepath.Base(path)
+	return imp, nil
+}
+
+// ImportImplemented reads implemented control IDs from a CSV or JSON
+// document. IDs are matched case-insensitively against every loaded
+// framework, so crosswalk sources can be listed alongside target controls;
+// unknown IDs, duplicates and entries whose mapped status is not
+// implemented are reported as ignored rather than failing the import.
+//
+// JSON documents are an array of IDs, an array of objects, or an object
+// with an implemented_controls array of either.
+func (g *GapAnalyzer) ImportImplemented(r io.Reader, format string, opts ImportOptions) (*ImplementedImport, error) {
+	var (
+		entries []importEntry
+		err     error
+	)
+	switch format {
+	case ImportCSV:
+		entries, err = readImportCSV(r, opts)
+	case ImportJSON:
+		entries, err = readImportJSON(r, opts)
+	default:
+		return nil, fmt.Errorf("unsupported implemented controls format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := g.controlIndex()
+	imp := &ImplementedImport{Controls: []string{}}
+	seen := make(map[string]int)
+	for _, e := range entries {
+		if e.id == "" {
+			continue
+		}
+		ignore := func(reason, details string) {
+			imp.Ignored = append(imp.Ignored, IgnoredEntry{Entry: e.pos, Value: e.id, Reason: reason, Details: details})
+		}
+		if opts.StatusColumn != "" && !implementedStatuses[strings.ToLower(strings.TrimSpace(e.status))] {
+			ignore(IgnoredNotImplemented, fmt.Sprintf("status %q", e.status))
+			continue
+		}
+		key := strings.ToLower(e.id)
+		id, ok := known[key]
+		if !ok {
+			ignore(IgnoredUnknown, "")
+			continue
+		}
+		if first, ok := seen[key]; ok {
+			ignore(IgnoredDuplicate, fmt.Sprintf("first listed at entry %d", first))
+			continue
+		}
+		seen[key] = e.pos
+		imp.Controls = append(imp.Controls, id)
+	}
+	imp.Imported = len(imp.Controls)
+	return imp, nil
+}
+
+// controlIndex maps lowercased control IDs of every loaded framework to
+// their canonical spelling.
+func (g *GapAnalyzer) controlIndex() map[string]string {
+	index := make(map[string]string)
+	for _, fw := range g.service.ListFrameworks() {
+		controls, err := g.service.GetControls(FrameworkID(fw.ID))
+		if err != nil {
+			continue
+		}
+		for _, c := range controls {
+			index[strings.ToLower(c.ControlID)] = c.ControlID
+		}
+	}
+	return index
+}
+
+// importEntry is one row or element of an implemented-controls file.
+type importEntry struct {
+	pos    int
+	id     string
+	status string
+}
+
+func readImportCSV(r io.Reader, opts ImportOptions) ([]importEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	var (
+		records [][]string
+		lines   []int
+	)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing implemented controls csv: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		records = append(records, rec)
+		lines = append(lines, line)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	// A UTF-8 byte order mark from spreadsheet exports would hide the
+	// first column's name.
+	if len(records[0]) > 0 {
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	}
+
+	header := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := header[name]; !ok {
+			header[name] = i
+		}
+	}
+	idCol, hasHeader := -1, true
+	if opts.IDColumn != "" {
+		i, ok := header[strings.ToLower(opts.IDColumn)]
+		if !ok {
+			return nil, fmt.Errorf("implemented controls csv has no %q column", opts.IDColumn)
+		}
+		idCol = i
+	} else {
+		for _, name := range idColumns {
+			if i, ok := header[name]; ok {
+				idCol = i
+				break
+			}
+		}
+		if idCol < 0 {
+			idCol, hasHeader = 0, false
+		}
+	}
+	statusCol := -1
+	if opts.StatusColumn != "" {
+		if !hasHeader {
+			return nil, fmt.Errorf("implemented controls csv needs a header row to map the %q column", opts.StatusColumn)
+		}
+		i, ok := header[strings.ToLower(opts.StatusColumn)]
+		if !ok {
+			return nil, fmt.Errorf("implemented controls csv has no %q column", opts.StatusColumn)
+		}
+		statusCol = i
+	}
+
+	if hasHeader {
+		records, lines = records[1:], lines[1:]
+	}
+	entries := make([]importEntry, 0, len(records))
+	for i, rec := range records {
+		e := importEntry{pos: lines[i]}
+		if idCol < len(rec) {
+			e.id = strings.TrimSpace(rec[idCol])
+		}
+		if statusCol >= 0 && statusCol < len(rec) {
+			e.status = rec[statusCol]
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func readImportJSON(r io.Reader, opts ImportOptions) ([]importEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var wrapper struct {
+			ImplementedControls json.RawMessage `json:"implemented_controls"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("parsing implemented controls json: %w", err)
+		}
+		if wrapper.ImplementedControls == nil {
+			return nil, errors.New("implemented controls json object has no implemented_controls array")
+		}
+		data = wrapper.ImplementedControls
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("parsing implemented controls json: %w", err)
+	}
+
+	idField := strings.ToLower(opts.IDColumn)
+	statusField := strings.ToLower(opts.StatusColumn)
+	entries := make([]importEntry, 0, len(items))
+	for i, item := range items {
+		e := importEntry{pos: i + 1}
+		var id string
+		if err := json.Unmarshal(item, &id); err == nil {
+			if statusField != "" {
+				return nil, fmt.Errorf("implemented controls json entry %d: a status field needs objects, not plain IDs", e.pos)
+			}
+			e.id = strings.TrimSpace(id)
+			entries = append(entries, e)
+			continue
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(item, &obj); err != nil {
+			return nil, fmt.Errorf("implemented controls json entry %d: want a control ID or an object", e.pos)
+		}
+		fields := make(map[string]any, len(obj))
+		for k, v := range obj {
+			fields[strings.ToLower(k)] = v
+		}
+		var ok bool
+		if idField != "" {
+			_, ok = fields[idField]
+			e.id = jsonField(fields[idField])
+		} else {
+			for _, name := range idColumns {
+				if _, ok = fields[name]; ok {
+					e.id = jsonField(fields[name])
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("implemented controls json entry %d has no control ID field", e.pos)
+		}
+		if statusField != "" {
+			e.status = jsonField(fields[statusField])
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// jsonField renders a scalar JSON value as text.
+func jsonField(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package controls_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func TestImportImplemented(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("NewGapAnalyzer: %v", err)
+	}
+
+	t.Run("csv with mapped columns", func(t *testing.T) {
+		csvData := "\ufeffRef,Control ID,Status\n" +
+			"1,ac-2,Implemented\n" +
+			"# retired\n" +
+			"2,AC-2,implemented\n" +
+			"3,SC-7,Planned\n" +
+			"4,NOPE-1,Implemented\n" +
+			"5,ISO42001-4.1,yes\n"
+		imp, err := analyzer.ImportImplemented(strings.NewReader(csvData), controls.ImportCSV, controls.ImportOptions{IDColumn: "control id", StatusColumn: "Status"})
+		if err != nil {
+			t.Fatalf("ImportImplemented: %v", err)
+		}
+		// IDs take the catalog's spelling, and crosswalk sources from other
+		// frameworks are accepted.
+		if !slices.Equal(imp.Controls, []string{"AC-2", "ISO42001-4.1"}) || imp.Imported != 2 {
+			t.Errorf("controls = %v (imported %d)", imp.Controls, imp.Imported)
+		}
+		want := []controls.IgnoredEntry{
+			{Entry: 4, Value: "AC-2", Reason: controls.IgnoredDuplicate, Details: "first listed at entry 2"},
+			{Entry: 5, Value: "SC-7", Reason: controls.IgnoredNotImplemented, Details: `status "Planned"`},
+			{Entry: 6, Value: "NOPE-1", Reason: controls.IgnoredUnknown},
+		}
+		if !slices.Equal(imp.Ignored, want) {
+			t.Errorf("ignored = %+v", imp.Ignored)
+		}
+	})
+
+	t.Run("csv without header", func(t *testing.T) {
+		imp, err := analyzer.ImportImplemented(strings.NewReader("AC-2\n\nSC-7\n"), controls.ImportCSV, controls.ImportOptions{})
+		if err != nil {
+			t.Fatalf("ImportImplemented: %v", err)
+		}
+		if !slices.Equal(imp.Controls, []string{"AC-2", "SC-7"}) || len(imp.Ignored) != 0 {
+			t.Errorf("import = %+v", imp)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		for _, doc := range []string{
+			`["AC-2", "sc-7", "bogus"]`,
+			`[{"control_id": "AC-2"}, {"Control_ID": "SC-7"}, {"control_id": "bogus"}]`,
+			`{"implemented_controls": ["AC-2", "SC-7", "bogus"]}`,
+		} {
+			imp, err := analyzer.ImportImplemented(strings.NewReader(doc), controls.ImportJSON, controls.ImportOptions{})
+			if err != nil {
+				t.Fatalf("ImportImplemented(%s): %v", doc, err)
+			}
+			if !slices.Equal(imp.Controls, []string{"AC-2", "SC-7"}) || len(imp.Ignored) != 1 || imp.Ignored[0].Entry != 3 {
+				t.Errorf("import of %s = %+v", doc, imp)
+			}
+		}
+
+		imp, err := analyzer.ImportImplemented(strings.NewReader(`[{"ref": "AC-2", "done": true}, {"ref": "SC-7", "done": false}]`),
+			controls.ImportJSON, controls.ImportOptions{IDColumn: "ref", StatusColumn: "done"})
+		if err != nil {
+			t.Fatalf("ImportImplemented: %v", err)
+		}
+		if !slices.Equal(imp.Controls, []string{"AC-2"}) || len(imp.Ignored) != 1 || imp.Ignored[0].Reason != controls.IgnoredNotImplemented {
+			t.Errorf("import = %+v", imp)
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		data, format string
+		opts         controls.ImportOptions
+	}{
+		"unmapped csv column":     {"control_id\nAC-2\n", controls.ImportCSV, controls.ImportOptions{IDColumn: "ref"}},
+		"status without header":   {"AC-2,yes\n", controls.ImportCSV, controls.ImportOptions{StatusColumn: "status"}},
+		"json object without ids": {`{"controls": []}`, controls.ImportJSON, controls.ImportOptions{}},
+		"json entry without id":   {`[{"name": "x"}]`, controls.ImportJSON, controls.ImportOptions{}},
+		"unsupported format":      {"AC-2", "xml", controls.ImportOptions{}},
+	} {
+		if _, err := analyzer.ImportImplemented(strings.NewReader(tc.data), tc.format, tc.opts); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// RegisterProvider adds or replaces a common control provider.
+func (g *GapAnalyzer) RegisterProvider(p *models.ControlProvider) error {
+	if p == nil || strings.TrimSpace(p.ID) == "" {
+		return fmt.Errorf("provider id is required")
+	}
+	if len(p.Controls) == 0 {
+		return fmt.Errorf("provider %s must provide at least one control", p.ID)
+	}
+
+	now := time.Now().UTC()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if existing, ok := g.providers[p.ID]; ok {
+		p.CreatedAt = existing.CreatedAt
+	} else if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	p.UpdatedAt = now
+	g.providers[p.ID] = p
+	return nil
+}
+
+// GetProvider returns a registered common control provider.
+func (g *GapAnalyzer) GetProvider(id string) (*models.ControlProvider, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	p, ok := g.providers[id]
+	return p, ok
+}
+
+// ListProviders returns all registered common control providers ordered by ID.
+func (g *GapAnalyzer) ListProviders() []*models.ControlProvider {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	result := make([]*models.ControlProvider, 0, len(g.providers))
+	for _, p := range g.providers {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// RemoveProvider deletes a common control provider. It reports whether the
+// provider existed.
+func (g *GapAnalyzer) RemoveProvider(id string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.providers[id]
+	delete(g.providers, id)
+	return ok
+}
+
+// LoadProviders registers the common control providers defined in a JSON file
+// containing an array of providers.
+func (g *GapAnalyzer) LoadProviders(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var providers []*models.ControlProvider
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return fmt.Errorf("parsing control providers: %w", err)
+	}
+	for _, p := range providers {
+		if err := g.RegisterProvider(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildInventory merges locally implemented controls with the controls
+// inherited from the given providers. A control implemented locally and also
+// provided by a provider is recorded as hybrid.
+func (g *GapAnalyzer) buildInventory(local []string, providerIDs []string) ([]models.ImplementedControl, error) {
+	inventory := []models.ImplementedControl{}
+	index := make(map[string]int)
+
+	for _, id := range local {
+		key := strings.ToLower(strings.TrimSpace(id))
+		if key == "" {
+			continue
+		}
+		if _, ok := index[key]; ok {
+			continue
+		}
+		index[key] = len(inventory)
+		inventory = append(inventory, models.ImplementedControl{
+			ControlID:    strings.TrimSpace(id),
+			Source:       models.ImplementationLocal,
+			Verification: models.VerificationAttested,
+		})
+	}
+
+	for _, pid := range providerIDs {
+		provider, ok := g.GetProvider(pid)
+		if !ok {
+			return nil, fmt.Errorf("unknown control provider: %s", pid)
+		}
+		for _, id := range provider.Controls {
+			key := strings.ToLower(id)
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(inventory)
+				inventory = append(inventory, models.ImplementedControl{
+					ControlID:   id,
+					Source:      models.ImplementationInherited,
+					ProviderIDs: []string{provider.ID},
+				})
+				continue
+			}
+			entry := &inventory[i]
+			if entry.Source == models.ImplementationLocal {
+				entry.Source = models.ImplementationHybrid
+			}
+			entry.ProviderIDs = append(entry.ProviderIDs, provider.ID)
+		}
+	}
+
+	return inventory, nil
+}
+
+// localInventory wraps plain control IDs as locally implemented inventory entries.
+func localInventory(ids []string) []models.ImplementedControl {
+	inventory := make([]models.ImplementedControl, 0, len(ids))
+	for _, id := range ids {
+		inventory = append(inventory, models.ImplementedControl{
+			ControlID:    id,
+			Source:       models.ImplementationLocal,
+			Verification: models.VerificationAttested,
+		})
+	}
+	return inventory
+}
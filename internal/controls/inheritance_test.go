@@ -0,0 +1,56 @@
+package controls_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestInheritedControls(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	if err := analyzer.RegisterProvider(&models.ControlProvider{
+		ID:       "cloud-platform",
+		Controls: []string{"ISO42001-4.1", "ISO42001-5.1"},
+	}); err != nil {
+		t.Fatalf("registering provider: %v", err)
+	}
+
+	out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework:     "iso-42001",
+		ImplementedControls: []string{"ISO42001-5.1"},
+		Providers:           []string{"cloud-platform"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.InheritedCount != 1 {
+		t.Errorf("inherited count = %d, want 1", out.InheritedCount)
+	}
+	want := map[string]models.ImplementationSource{
+		"ISO42001-4.1": models.ImplementationInherited,
+		"ISO42001-5.1": models.ImplementationHybrid,
+	}
+	for _, c := range out.Inventory {
+		if src, ok := want[c.ControlID]; ok && c.Source != src {
+			t.Errorf("%s source = %s, want %s", c.ControlID, c.Source, src)
+		}
+	}
+	for id := range want {
+		if findGap(out, id) != nil {
+			t.Errorf("expected %s to be covered, found gap", id)
+		}
+	}
+
+	if _, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework: "iso-42001",
+		Providers:       []string{"missing"},
+	}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
@@ -5,6 +5,8 @@ import "github.com/agentguard/agentguard/internal/models"
 // getISO42001Controls returns ISO/IEC 42001:2023 AI Management System controls.
 // This standard specifies requirements for establishing, implementing, maintaining,
 // and continually improving an AI management system within organizations.
+// Its text is licensed, so only control IDs, short titles and AgentGuard's own
+// implementation guidance are embedded; see licensed.go.
 func getISO42001Controls() []models.Control {
 	return []models.Control{
 		// Clause 4: Context of the Organization
@@ -12,7 +14,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-4.1",
 			Title:       "Understanding the Organization and Its Context",
-			Description: "Determine external and internal issues relevant to AI management system purpose and strategic direction.",
 			Objectives: []string{
 				"Identify external issues affecting AI systems",
 				"Identify internal issues affecting AI systems",
@@ -34,7 +35,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-4.2",
 			Title:       "Understanding Stakeholder Needs",
-			Description: "Determine interested parties relevant to the AI management system and their requirements.",
 			Objectives: []string{
 				"Identify AI stakeholders",
 				"Understand stakeholder requirements",
@@ -56,7 +56,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-4.3",
 			Title:       "Scope of the AI Management System",
-			Description: "Determine boundaries and applicability of the AI management system.",
 			Objectives: []string{
 				"Define AIMS boundaries",
 				"Identify applicable AI systems",
@@ -77,7 +76,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-4.4",
 			Title:       "AI Management System",
-			Description: "Establish, implement, maintain, and continually improve an AI management system.",
 			Objectives: []string{
 				"Establish AIMS processes",
 				"Implement management system",
@@ -101,7 +99,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-5.1",
 			Title:       "Leadership and Commitment",
-			Description: "Top management demonstrates leadership and commitment to the AI management system.",
 			Objectives: []string{
 				"Demonstrate management commitment",
 				"Allocate resources",
@@ -124,7 +121,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-5.2",
 			Title:       "AI Policy",
-			Description: "Establish an AI policy appropriate to the organization's purpose.",
 			Objectives: []string{
 				"Define AI policy",
 				"Communicate policy",
@@ -147,7 +143,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-5.3",
 			Title:       "Organizational Roles and Responsibilities",
-			Description: "Assign responsibilities and authorities for relevant roles.",
 			Objectives: []string{
 				"Define AI-related roles",
 				"Assign responsibilities",
@@ -171,7 +166,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-6.1",
 			Title:       "Actions to Address Risks and Opportunities",
-			Description: "Plan actions to address AI-related risks and opportunities.",
 			Objectives: []string{
 				"Identify AI risks",
 				"Identify AI opportunities",
@@ -194,7 +188,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-6.2",
 			Title:       "AI Objectives and Planning",
-			Description: "Establish AI objectives at relevant functions and levels.",
 			Objectives: []string{
 				"Define AI objectives",
 				"Align with AI policy",
@@ -217,7 +210,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-6.3",
 			Title:       "Planning of Changes",
-			Description: "Plan changes to the AI management system in a systematic manner.",
 			Objectives: []string{
 				"Manage AIMS changes",
 				"Assess change impacts",
@@ -241,7 +233,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-7.1",
 			Title:       "Resources",
-			Description: "Determine and provide resources needed for the AI management system.",
 			Objectives: []string{
 				"Identify resource needs",
 				"Provide adequate resources",
@@ -264,7 +255,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-7.2",
 			Title:       "Competence",
-			Description: "Ensure persons doing work affecting AI system performance are competent.",
 			Objectives: []string{
 				"Define competency requirements",
 				"Ensure staff competence",
@@ -287,7 +277,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-7.3",
 			Title:       "Awareness",
-			Description: "Ensure persons are aware of AI policy and their contribution to AIMS.",
 			Objectives: []string{
 				"Promote AI policy awareness",
 				"Communicate individual roles",
@@ -309,7 +298,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-7.4",
 			Title:       "Communication",
-			Description: "Determine internal and external communications relevant to AIMS.",
 			Objectives: []string{
 				"Plan AI communications",
 				"Enable effective information flow",
@@ -331,7 +319,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-7.5",
 			Title:       "Documented Information",
-			Description: "Manage documented information required by the AI management system.",
 			Objectives: []string{
 				"Maintain required documentation",
 				"Control document creation and updates",
@@ -356,7 +343,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-8.1",
 			Title:       "Operational Planning and Control",
-			Description: "Plan, implement, and control processes for AI operations.",
 			Objectives: []string{
 				"Plan AI operations",
 				"Control AI processes",
@@ -379,7 +365,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-8.2",
 			Title:       "AI System Impact Assessment",
-			Description: "Conduct impact assessment before AI system deployment.",
 			Objectives: []string{
 				"Assess AI system impacts",
 				"Identify potential harms",
@@ -402,7 +387,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-8.3",
 			Title:       "AI System Lifecycle",
-			Description: "Manage AI systems throughout their lifecycle.",
 			Objectives: []string{
 				"Manage full AI lifecycle",
 				"Control development and deployment",
@@ -426,7 +410,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-8.4",
 			Title:       "AI System Documentation",
-			Description: "Maintain documentation for AI systems.",
 			Objectives: []string{
 				"Document AI systems",
 				"Maintain technical records",
@@ -450,7 +433,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-8.5",
 			Title:       "Data for AI Systems",
-			Description: "Manage data used in AI systems appropriately.",
 			Objectives: []string{
 				"Manage AI data quality",
 				"Ensure data appropriateness",
@@ -474,7 +456,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-8.6",
 			Title:       "Third-Party Considerations",
-			Description: "Manage third-party AI components and services.",
 			Objectives: []string{
 				"Assess third-party AI",
 				"Control vendor relationships",
@@ -499,7 +480,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-9.1",
 			Title:       "Monitoring, Measurement, Analysis and Evaluation",
-			Description: "Determine what needs to be monitored and measured.",
 			Objectives: []string{
 				"Define monitoring requirements",
 				"Measure AI performance",
@@ -522,7 +502,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-9.2",
 			Title:       "Internal Audit",
-			Description: "Conduct internal audits at planned intervals.",
 			Objectives: []string{
 				"Verify AIMS conformance",
 				"Assess effectiveness",
@@ -545,7 +524,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-9.3",
 			Title:       "Management Review",
-			Description: "Review the AI management system at planned intervals.",
 			Objectives: []string{
 				"Review AIMS performance",
 				"Ensure continuing suitability",
@@ -571,7 +549,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-10.1",
 			Title:       "Nonconformity and Corrective Action",
-			Description: "React to nonconformities and take corrective action.",
 			Objectives: []string{
 				"Address nonconformities",
 				"Prevent recurrence",
@@ -594,7 +571,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-10.2",
 			Title:       "Continual Improvement",
-			Description: "Continually improve the AI management system.",
 			Objectives: []string{
 				"Improve AIMS effectiveness",
 				"Enhance AI governance",
@@ -619,7 +595,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.2.2",
 			Title:       "AI System Transparency",
-			Description: "Provide appropriate transparency about AI system capabilities and limitations.",
 			Objectives: []string{
 				"Enable system understanding",
 				"Disclose limitations",
@@ -641,7 +616,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.2.3",
 			Title:       "AI System Explainability",
-			Description: "Provide explanations of AI system outputs where appropriate.",
 			Objectives: []string{
 				"Enable output understanding",
 				"Support accountability",
@@ -663,7 +637,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.3.2",
 			Title:       "Bias Assessment and Mitigation",
-			Description: "Assess and mitigate bias in AI systems.",
 			Objectives: []string{
 				"Identify bias sources",
 				"Assess bias impacts",
@@ -685,7 +658,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.4.4",
 			Title:       "AI System Security",
-			Description: "Implement security controls for AI systems.",
 			Objectives: []string{
 				"Protect AI systems",
 				"Secure AI data",
@@ -709,7 +681,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.5.2",
 			Title:       "Human Oversight",
-			Description: "Implement appropriate human oversight of AI systems.",
 			Objectives: []string{
 				"Enable human control",
 				"Support intervention",
@@ -731,7 +702,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.6.2",
 			Title:       "AI System Reliability",
-			Description: "Ensure AI systems perform reliably as intended.",
 			Objectives: []string{
 				"Ensure consistent performance",
 				"Manage failures gracefully",
@@ -753,7 +723,6 @@ func getISO42001Controls() []models.Control {
 			FrameworkID: string(FrameworkISO42001),
 			ControlID:   "ISO42001-A.7.3",
 			Title:       "Privacy Protection",
-			Description: "Protect privacy in AI system development and operation.",
 			Objectives: []string{
 				"Protect personal data",
 				"Implement privacy by design",
@@ -0,0 +1,168 @@
+package controls
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// restrictedCatalogs are the built-in frameworks whose text may not be
+// redistributed, with the terms shown to users. Only control IDs, short
+// titles and AgentGuard's own implementation guidance are embedded; the
+// full text comes from a licensed copy the customer provides.
+var restrictedCatalogs = map[FrameworkID]string{
+	FrameworkISO42001: "ISO/IEC copyright: the standard's text is licensed per user and is not redistributed with AgentGuard",
+}
+
+// ErrLicensedText is returned when a restricted catalog's full text is
+// needed but no licensed copy was provided.
+var ErrLicensedText = errors.New("licensed catalog text not available")
+
+// LicensedTextError names the restricted framework whose full text is
+// missing and where a licensed copy is looked for.
+type LicensedTextError struct {
+	Framework string
+	// Path is the file the licensed copy is loaded from, or empty when no
+	// data directory is configured.
+	Path string
+}
+
+func (e *LicensedTextError) Error() string {
+	where := "licensed/" + e.Framework + ".yaml in the data directory"
+	if e.Path != "" {
+		where = e.Path
+	}
+	return fmt.Sprintf("%s: %s is a licensed catalog and only its control IDs and titles are embedded; provide your licensed copy as %s, or an OSCAL catalog as catalogs/%s.json",
+		ErrLicensedText, e.Framework, where, e.Framework)
+}
+
+func (e *LicensedTextError) Unwrap() error { return ErrLicensedText }
+
+// LicensedCatalog is the full text of a restricted catalog, as provided by
+// a licensee in <data_dir>/licensed/<framework-id>.yaml or .json.
+type LicensedCatalog struct {
+	Controls []LicensedControl `yaml:"controls"`
+}
+
+// LicensedControl is the licensed text of one control.
+type LicensedControl struct {
+	ID string `yaml:"id"`
+	// Title replaces the embedded short title when set.
+	Title string `yaml:"title"`
+	Text  string `yaml:"text"`
+	// Objectives replace the embedded ones when set.
+	Objectives []string `yaml:"objectives"`
+}
+
+// licensedPath returns the file a framework's licensed copy is loaded
+// from: the first of licensed/<id>.yaml, .yml and .json that exists, or
+// the .yaml name when none does.
+func (s *Service) licensedPath(id FrameworkID) string {
+	if s.dataDir == "" {
+		return ""
+	}
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		path := filepath.Join(s.dataDir, "licensed", string(id)+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(s.dataDir, "licensed", string(id)+".yaml")
+}
+
+// loadLicensedText merges the licensed copies of restricted catalogs found
+// in the data directory into their embedded controls. Every control in a
+// copy must exist in the catalog, so a copy of the wrong edition fails
+// loudly rather than leaving controls without text.
+func (s *Service) loadLicensedText() error {
+	for id := range restrictedCatalogs {
+		path := s.licensedPath(id)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var cat LicensedCatalog
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cat); err != nil {
+			return fmt.Errorf("loading %s: %w", path, err)
+		}
+		if err := s.applyLicensedText(id, &cat); err != nil {
+			return fmt.Errorf("loading %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// applyLicensedText sets the text of a restricted catalog's controls.
+func (s *Service) applyLicensedText(id FrameworkID, cat *LicensedCatalog) error {
+	index := make(map[string]int, len(s.controls[id]))
+	for i, c := range s.controls[id] {
+		index[strings.ToLower(c.ControlID)] = i
+	}
+	var unknown []string
+	for _, lc := range cat.Controls {
+		i, ok := index[strings.ToLower(lc.ID)]
+		if !ok {
+			unknown = append(unknown, lc.ID)
+			continue
+		}
+		c := &s.controls[id][i]
+		if lc.Title != "" {
+			c.Title = lc.Title
+		}
+		c.Description = lc.Text
+		if len(lc.Objectives) > 0 {
+			c.Objectives = lc.Objectives
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("controls not in %s: %s", id, strings.Join(unknown, ", "))
+	}
+	if len(cat.Controls) == 0 {
+		return fmt.Errorf("no controls")
+	}
+	s.licensed[id] = true
+	return nil
+}
+
+// markLicensing records on each restricted framework its terms and
+// whether its full text is available.
+func (s *Service) markLicensing() {
+	for id, terms := range restrictedCatalogs {
+		fw, ok := s.frameworks[id]
+		if !ok {
+			continue
+		}
+		updated := *fw
+		updated.License = terms
+		updated.TextUnavailable = !s.licensed[id]
+		s.frameworks[id] = &updated
+	}
+}
+
+// RequireFullText returns a LicensedTextError when a framework is a
+// restricted catalog whose licensed copy was not provided.
+func (g *GapAnalyzer) RequireFullText(framework string) error {
+	return g.service.requireFullText(FrameworkID(framework))
+}
+
+func (s *Service) requireFullText(id FrameworkID) error {
+	if _, restricted := restrictedCatalogs[id]; !restricted || s.licensed[id] {
+		return nil
+	}
+	return &LicensedTextError{Framework: string(id), Path: s.licensedPath(id)}
+}
@@ -0,0 +1,74 @@
+package controls_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestLicensedCatalog(t *testing.T) {
+	ga, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw, _ := ga.Framework("iso-42001")
+	if fw.License == "" || !fw.TextUnavailable {
+		t.Errorf("embedded iso-42001 = %+v, want it restricted without text", fw)
+	}
+	list, _ := ga.Controls("iso-42001")
+	if slices.ContainsFunc(list, func(c models.Control) bool { return c.Description != "" }) {
+		t.Error("licensed catalog text embedded")
+	}
+	if err := ga.RequireFullText("iso-42001"); !errors.Is(err, controls.ErrLicensedText) || !strings.Contains(err.Error(), "licensed/iso-42001.yaml") {
+		t.Errorf("RequireFullText = %v, want ErrLicensedText naming the licensed copy", err)
+	}
+	if err := ga.RequireFullText("nist-800-53"); err != nil {
+		t.Errorf("open catalog RequireFullText = %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "licensed"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "licensed", "iso-42001.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`controls:
+  - id: iso42001-4.1
+    title: Understanding the organization and its context
+    text: The organization shall determine external and internal issues.
+`)
+	ga, err = controls.NewGapAnalyzer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ga.RequireFullText("iso-42001"); err != nil {
+		t.Errorf("RequireFullText with a licensed copy = %v", err)
+	}
+	if fw, _ := ga.Framework("iso-42001"); fw.TextUnavailable {
+		t.Error("framework still marked without text")
+	}
+	list, _ = ga.Controls("iso-42001")
+	i := slices.IndexFunc(list, func(c models.Control) bool { return c.ControlID == "ISO42001-4.1" })
+	if i < 0 || !strings.HasPrefix(list[i].Description, "The organization shall") || list[i].Title != "Understanding the organization and its context" {
+		t.Errorf("licensed control = %+v", list[max(i, 0)])
+	}
+	if len(list[i].Activities) == 0 {
+		t.Error("embedded guidance dropped")
+	}
+
+	write("controls:\n  - id: ISO42001-99.9\n    text: x\n")
+	if _, err := controls.NewGapAnalyzer(dir); err == nil || !strings.Contains(err.Error(), "ISO42001-99.9") {
+		t.Errorf("licensed copy with an unknown control = %v, want an error naming it", err)
+	}
+}
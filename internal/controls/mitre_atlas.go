@@ -0,0 +1,65 @@
+package controls
+
+import (
+	"github.com/agentguard/agentguard/internal/atlas"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// atlasLayers maps ATLAS tactics to the layers their techniques target.
+var atlasLayers = map[string][]string{
+	atlas.TacticReconnaissance:      {"organization"},
+	atlas.TacticResourceDevelopment: {"supply_chain"},
+	atlas.TacticInitialAccess:       {"application", "supply_chain"},
+	atlas.TacticModelAccess:         {"model"},
+	atlas.TacticExecution:           {"application", "system"},
+	atlas.TacticPersistence:         {"model", "data"},
+	atlas.TacticPrivilegeEscalation: {"system"},
+	atlas.TacticDefenseEvasion:      {"application"},
+	atlas.TacticCredentialAccess:    {"system"},
+	atlas.TacticDiscovery:           {"model"},
+	atlas.TacticCollection:          {"data"},
+	atlas.TacticAttackStaging:       {"model"},
+	atlas.TacticExfiltration:        {"model", "data"},
+	atlas.TacticImpact:              {"operations"},
+}
+
+// getATLASControls returns the MITRE ATLAS techniques as controls, so they
+// can be gap-analyzed and crosswalked like any other framework. A technique
+// is covered when the mitigations mapped to it are implemented; the
+// technique's ATLAS mitigations are listed as its activities.
+func getATLASControls() []models.Control {
+	var out []models.Control
+	for _, t := range atlas.Techniques() {
+		c := models.Control{
+			FrameworkID: string(FrameworkMITREATLAS),
+			ControlID:   t.ID,
+			Title:       t.Name,
+			Description: t.Description,
+			EvidenceTypes: []string{
+				"Adversarial test results exercising the technique",
+				"Detection rules and monitoring alerts",
+			},
+		}
+		if t.ParentID != "" {
+			parent := t.ParentID
+			c.ParentControlID = &parent
+		}
+		layers := make(map[string]bool)
+		for _, id := range t.Tactics {
+			if tactic, ok := atlas.GetTactic(id); ok {
+				c.Objectives = append(c.Objectives, "Counter adversary "+tactic.Name)
+			}
+			for _, l := range atlasLayers[id] {
+				if !layers[l] {
+					layers[l] = true
+					c.ApplicableLayers = append(c.ApplicableLayers, l)
+				}
+			}
+		}
+		for _, m := range atlas.MitigationsFor(t.ID) {
+			c.Activities = append(c.Activities, m.ID+" "+m.Name)
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,286 @@
+package controls
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// CheckStatus is the outcome of an automated control check.
+type CheckStatus string
+
+const (
+	CheckPassed CheckStatus = "passed"
+	CheckFailed CheckStatus = "failed"
+)
+
+// Check is an automated verification for one or more controls. Run returns nil
+// when the controls are verified to be in place.
+type Check struct {
+	ID          string                          `json:"id"`
+	Description string                          `json:"description"`
+	Controls    []string                        `json:"controls"`
+	Run         func(ctx context.Context) error `json:"-"`
+}
+
+// CheckResult records the latest outcome of a check.
+type CheckResult struct {
+	CheckID    string      `json:"check_id"`
+	Controls   []string    `json:"controls"`
+	Status     CheckStatus `json:"status"`
+	Message    string      `json:"message,omitempty"`
+	CheckedAt  time.Time   `json:"checked_at"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// ControlStatus summarizes automated verification for a single control.
+type ControlStatus struct {
+	ControlID  string      `json:"control_id"`
+	Status     CheckStatus `json:"status"`
+	Checks     []string    `json:"checks"`
+	VerifiedAt time.Time   `json:"verified_at"`
+}
+
+// checkTimeout bounds a single check run.
+const checkTimeout = 10 * time.Second
+
+// Monitor runs registered checks on a schedule and keeps the latest results so
+// control implementation status is derived from evidence rather than attestation.
+type Monitor struct {
+	interval time.Duration
+
+	mu      sync.RWMutex
+	checks  []Check
+	results map[string]CheckResult
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewMonitor creates a monitor that runs checks every interval.
+func NewMonitor(interval time.Duration) *Monitor {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &Monitor{
+		interval: interval,
+		results:  make(map[string]CheckResult),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register adds a check. Check IDs must be unique.
+func (m *Monitor) Register(c Check) error {
+	if c.ID == "" || c.Run == nil {
+		return fmt.Errorf("check id and run function are required")
+	}
+	if len(c.Controls) == 0 {
+		return fmt.Errorf("check %s must verify at least one control", c.ID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.checks {
+		if existing.ID == c.ID {
+			return fmt.Errorf("check %s already registered", c.ID)
+		}
+	}
+	m.checks = append(m.checks, c)
+	return nil
+}
+
+// Checks returns the registered checks.
+func (m *Monitor) Checks() []Check {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Check(nil), m.checks...)
+}
+
+// RunAll executes every registered check once and records the results.
+func (m *Monitor) RunAll(ctx context.Context) []CheckResult {
+	checks := m.Checks()
+	results := make([]CheckResult, 0, len(checks))
+	for _, c := range checks {
+		results = append(results, runCheck(ctx, c))
+	}
+
+	m.mu.Lock()
+	for _, r := range results {
+		m.results[r.CheckID] = r
+	}
+	m.mu.Unlock()
+
+	return results
+}
+
+func runCheck(ctx context.Context, c Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Run(ctx)
+	elapsed := time.Since(start)
+
+	result := CheckResult{
+		CheckID:    c.ID,
+		Controls:   c.Controls,
+		Status:     CheckPassed,
+		CheckedAt:  start.UTC(),
+		DurationMs: elapsed.Milliseconds(),
+	}
+	if err != nil {
+		result.Status = CheckFailed
+		result.Message = err.Error()
+		log.Warn().Str("check", c.ID).Err(err).Msg("control check failed")
+	}
+	return result
+}
+
+// Start runs all checks immediately and then on every interval until Stop is
+// called or ctx is cancelled.
+func (m *Monitor) Start(ctx context.Context) {
+	go func() {
+		m.RunAll(ctx)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.RunAll(ctx)
+			case <-m.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts scheduled check runs.
+func (m *Monitor) Stop() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+// Results returns the latest result of every check that has run, ordered by check ID.
+func (m *Monitor) Results() []CheckResult {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	results := make([]CheckResult, 0, len(m.results))
+	for _, r := range m.results {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].CheckID < results[j].CheckID })
+	return results
+}
+
+// ControlStatuses derives per-control status from the latest results. A control
+// passes only when every check covering it passed.
+func (m *Monitor) ControlStatuses() map[string]ControlStatus {
+	statuses := make(map[string]ControlStatus)
+	for _, r := range m.Results() {
+		for _, id := range r.Controls {
+			key := strings.ToLower(id)
+			s, ok := statuses[key]
+			if !ok {
+				s = ControlStatus{ControlID: id, Status: CheckPassed}
+			}
+			s.Checks = append(s.Checks, r.CheckID)
+			if r.Status != CheckPassed {
+				s.Status = CheckFailed
+			}
+			if r.CheckedAt.After(s.VerifiedAt) {
+				s.VerifiedAt = r.CheckedAt
+			}
+			statuses[key] = s
+		}
+	}
+	return statuses
+}
+
+// applyMonitoring reconciles an inventory with automated check results.
+// Controls with passing checks are added or marked verified; controls with a
+// failing check are removed and returned separately.
+func applyMonitoring(inventory []models.ImplementedControl, statuses map[string]ControlStatus) ([]models.ImplementedControl, []ControlStatus) {
+	if len(statuses) == 0 {
+		return inventory, nil
+	}
+
+	result := make([]models.ImplementedControl, 0, len(inventory)+len(statuses))
+	seen := make(map[string]bool)
+	failing := []ControlStatus{}
+
+	for _, c := range inventory {
+		key := strings.ToLower(c.ControlID)
+		seen[key] = true
+		if s, ok := statuses[key]; ok {
+			if s.Status != CheckPassed {
+				failing = append(failing, s)
+				continue
+			}
+			verifiedAt := s.VerifiedAt
+			c.Verification = models.VerificationAutomated
+			c.VerifiedAt = &verifiedAt
+		}
+		result = append(result, c)
+	}
+
+	keys := make([]string, 0, len(statuses))
+	for key := range statuses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := statuses[key]
+		if seen[key] {
+			continue
+		}
+		if s.Status != CheckPassed {
+			failing = append(failing, s)
+			continue
+		}
+		verifiedAt := s.VerifiedAt
+		result = append(result, models.ImplementedControl{
+			ControlID:    s.ControlID,
+			Source:       models.ImplementationLocal,
+			Verification: models.VerificationAutomated,
+			VerifiedAt:   &verifiedAt,
+		})
+	}
+
+	return result, failing
+}
+
+// HTTPCheck returns a check that passes when a GET to url returns expectStatus.
+// It is used for cloud and platform API verifications.
+func HTTPCheck(id, description, url string, expectStatus int, controls []string) Check {
+	if expectStatus == 0 {
+		expectStatus = http.StatusOK
+	}
+	client := &http.Client{Timeout: checkTimeout}
+	return Check{
+		ID:          id,
+		Description: description,
+		Controls:    controls,
+		Run: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode != expectStatus {
+				return fmt.Errorf("unexpected status %d (want %d)", resp.StatusCode, expectStatus)
+			}
+			return nil
+		},
+	}
+}
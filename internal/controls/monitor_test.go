@@ -0,0 +1,54 @@
+package controls_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestMonitoringFeedsInventory(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+
+	monitor := controls.NewMonitor(time.Minute)
+	checks := []controls.Check{
+		{ID: "passing", Controls: []string{"AC-3"}, Run: func(context.Context) error { return nil }},
+		{ID: "failing", Controls: []string{"AU-2"}, Run: func(context.Context) error { return errors.New("disabled") }},
+	}
+	for _, c := range checks {
+		if err := monitor.Register(c); err != nil {
+			t.Fatalf("registering check: %v", err)
+		}
+	}
+	monitor.RunAll(context.Background())
+	analyzer.SetMonitor(monitor)
+
+	out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework:     "nist-800-53",
+		ImplementedControls: []string{"AU-2"}, // attested, but the check disagrees
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if findGap(out, "AC-3") != nil {
+		t.Error("expected AC-3 to be covered by a passing check")
+	}
+	if findGap(out, "AU-2") == nil {
+		t.Error("expected AU-2 gap when its check fails")
+	}
+	if len(out.FailingChecks) != 1 || out.FailingChecks[0].ControlID != "AU-2" {
+		t.Errorf("failing checks = %+v, want AU-2", out.FailingChecks)
+	}
+	for _, c := range out.Inventory {
+		if c.ControlID == "AC-3" && c.Verification != models.VerificationAutomated {
+			t.Errorf("AC-3 verification = %q, want automated", c.Verification)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package controls
+
+import (
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+)
+
+// OSCALComponentDefinition exports an analysis as an OSCAL component
+// definition recording each target control's implementation status.
+func (g *GapAnalyzer) OSCALComponentDefinition(output *AnalysisOutput) (*oscal.ComponentDefinition, error) {
+	fw, err := g.service.GetFramework(FrameworkID(output.Framework))
+	if err != nil {
+		return nil, fmt.Errorf("unknown framework: %s", output.Framework)
+	}
+	controls, err := g.service.GetControls(FrameworkID(output.Framework))
+	if err != nil {
+		return nil, err
+	}
+	gaps := make([]models.ControlGap, 0, len(output.Gaps))
+	for _, gap := range output.Gaps {
+		gaps = append(gaps, models.ControlGap{
+			ControlID:          gap.ControlID,
+			GapType:            gap.GapType,
+			Description:        gap.Description,
+			RemediationOptions: gap.RemediationOptions,
+			Priority:           gap.Priority,
+			EstimatedEffort:    gap.EstimatedEffort,
+			CoverageScore:      gap.CoverageScore,
+			CoveredBy:          gap.CoveredBy,
+		})
+	}
+	return oscal.NewComponentDefinition(oscal.ComponentInput{
+		Framework: fw,
+		Controls:  controls,
+		Inventory: output.Inventory,
+		Gaps:      gaps,
+	}), nil
+}
+
+// OSCALMappingCollection exports the source→target crosswalks as an OSCAL
+// mapping collection.
+func (g *GapAnalyzer) OSCALMappingCollection(source, target string, includeDerived bool) (*oscal.MappingCollection, error) {
+	sourceFW, err := g.service.GetFramework(FrameworkID(source))
+	if err != nil {
+		return nil, fmt.Errorf("unknown source framework: %s", source)
+	}
+	targetFW, err := g.service.GetFramework(FrameworkID(target))
+	if err != nil {
+		return nil, fmt.Errorf("unknown target framework: %s", target)
+	}
+	crosswalks, err := g.Crosswalks(source, target, includeDerived)
+	if err != nil {
+		return nil, err
+	}
+	return oscal.NewMappingCollection(sourceFW, targetFW, crosswalks), nil
+}
@@ -0,0 +1,241 @@
+package controls
+
+import "github.com/agentguard/agentguard/internal/models"
+
+// getOWASPLLMControls returns the OWASP Top 10 for LLM Applications (2025).
+// Each entry is a risk; the control is the set of mitigations OWASP
+// recommends for it. Earlier editions' Insecure Output Handling and Training
+// Data Poisoning are LLM05 and LLM04 here.
+func getOWASPLLMControls() []models.Control {
+	return []models.Control{
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM01",
+			Title:       "Prompt Injection",
+			Description: "User prompts or external content processed by the model cannot alter its behavior in unintended ways, whether injected directly or indirectly through documents, web pages, tool results or other retrieved data.",
+			Objectives: []string{
+				"Constrain model behavior to the intended task",
+				"Treat retrieved and tool-returned content as untrusted",
+				"Limit the impact of a successful injection",
+			},
+			Activities: []string{
+				"Segregate and label untrusted content in prompts",
+				"Filter inputs and outputs for injection patterns",
+				"Enforce least privilege on agent tools with policy guardrails",
+				"Require human approval for high-risk actions",
+				"Run adversarial prompt injection tests",
+			},
+			EvidenceTypes: []string{
+				"Input and output filtering configuration",
+				"Guardrail policy definitions",
+				"Red team test results",
+			},
+			ApplicableLayers: []string{"application", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM02",
+			Title:       "Sensitive Information Disclosure",
+			Description: "Personal data, credentials, proprietary information and other sensitive data are not exposed through model outputs, training data or context passed to the model.",
+			Objectives: []string{
+				"Keep sensitive data out of training and prompt context",
+				"Detect and redact sensitive data in outputs",
+				"Inform users of data handling practices",
+			},
+			Activities: []string{
+				"Sanitize and classify data before training or retrieval",
+				"Apply access controls to retrieval sources",
+				"Scan outputs for secrets and personal data",
+				"Monitor for data exfiltration signals",
+			},
+			EvidenceTypes: []string{
+				"Data classification records",
+				"Output redaction configuration",
+				"Exfiltration monitoring alerts",
+			},
+			ApplicableLayers: []string{"data", "application"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM03",
+			Title:       "Supply Chain",
+			Description: "Third-party models, datasets, plugins, adapters and deployment platforms are vetted and tracked so that compromised or vulnerable components do not reach production.",
+			Objectives: []string{
+				"Maintain an inventory of model and data components",
+				"Verify the provenance and integrity of components",
+				"Assess third-party suppliers and their terms",
+			},
+			Activities: []string{
+				"Maintain an AI bill of materials",
+				"Verify model and package signatures and hashes",
+				"Scan components for known vulnerabilities",
+				"Review supplier security and licensing terms",
+			},
+			EvidenceTypes: []string{
+				"AI bill of materials",
+				"Component integrity verification records",
+				"Supplier assessments",
+			},
+			ApplicableLayers: []string{"supply_chain", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM04",
+			Title:       "Data and Model Poisoning",
+			Description: "Pre-training, fine-tuning and embedding data are protected from manipulation that introduces vulnerabilities, backdoors or biases into the model.",
+			Objectives: []string{
+				"Track the origin and transformation of training data",
+				"Detect tampered or anomalous training data",
+				"Validate model behavior before release",
+			},
+			Activities: []string{
+				"Record data lineage for training and fine-tuning sets",
+				"Restrict write access to training data stores",
+				"Evaluate models against held-out and adversarial tests",
+				"Monitor production behavior for drift",
+			},
+			EvidenceTypes: []string{
+				"Data lineage records",
+				"Model evaluation reports",
+				"Drift monitoring results",
+			},
+			ApplicableLayers: []string{"data", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM05",
+			Title:       "Improper Output Handling",
+			Description: "Model outputs are validated, sanitized and encoded before being passed to downstream components, browsers, shells, databases or tools (formerly Insecure Output Handling).",
+			Objectives: []string{
+				"Treat model output as untrusted user input",
+				"Prevent injection into downstream interpreters",
+				"Validate structured outputs against schemas",
+			},
+			Activities: []string{
+				"Encode output for its rendering or execution context",
+				"Use parameterized queries for model-generated data",
+				"Validate tool arguments against schemas before invocation",
+				"Apply content security policies to rendered output",
+			},
+			EvidenceTypes: []string{
+				"Output validation configuration",
+				"Secure coding review records",
+				"Application security test results",
+			},
+			ApplicableLayers: []string{"application"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM06",
+			Title:       "Excessive Agency",
+			Description: "Agents are granted only the tools, permissions and autonomy their task requires, so that unexpected or manipulated outputs cannot trigger damaging actions.",
+			Objectives: []string{
+				"Minimize agent tool functionality and permissions",
+				"Execute actions in the user's security context",
+				"Keep humans in the loop for high-impact actions",
+			},
+			Activities: []string{
+				"Review and restrict agent tool grants",
+				"Enforce tool allowlists with policy-as-code",
+				"Require approval for destructive or external actions",
+				"Log and rate-limit agent actions",
+			},
+			EvidenceTypes: []string{
+				"Agent tool inventories",
+				"Guardrail policy definitions",
+				"Approval and action logs",
+			},
+			ApplicableLayers: []string{"system", "operations"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM07",
+			Title:       "System Prompt Leakage",
+			Description: "System prompts do not contain secrets or security controls that would be compromised if the prompt were disclosed, and disclosure attempts are detected.",
+			Objectives: []string{
+				"Keep credentials and sensitive logic out of prompts",
+				"Enforce security controls outside the model",
+				"Detect attempts to extract system prompts",
+			},
+			Activities: []string{
+				"Review system prompts for embedded secrets",
+				"Move authorization decisions to deterministic guardrails",
+				"Monitor outputs for system prompt content",
+			},
+			EvidenceTypes: []string{
+				"System prompt reviews",
+				"Guardrail policy definitions",
+				"Leakage detection alerts",
+			},
+			ApplicableLayers: []string{"application", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM08",
+			Title:       "Vector and Embedding Weaknesses",
+			Description: "Retrieval-augmented generation pipelines protect vector stores and embeddings against unauthorized access, cross-tenant leakage, data poisoning and embedding inversion.",
+			Objectives: []string{
+				"Enforce access control on retrieved content",
+				"Isolate tenants and data classifications in vector stores",
+				"Validate content before it is embedded",
+			},
+			Activities: []string{
+				"Apply permission-aware retrieval",
+				"Partition vector stores by tenant and sensitivity",
+				"Validate and log documents added to knowledge bases",
+				"Monitor retrieval activity for anomalies",
+			},
+			EvidenceTypes: []string{
+				"Vector store access policies",
+				"Ingestion validation logs",
+				"Retrieval audit logs",
+			},
+			ApplicableLayers: []string{"data", "system"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM09",
+			Title:       "Misinformation",
+			Description: "The risk of false or misleading outputs being relied upon is reduced through grounding, verification and clear communication of limitations.",
+			Objectives: []string{
+				"Ground outputs in trusted sources",
+				"Verify critical outputs before use",
+				"Communicate model limitations to users",
+			},
+			Activities: []string{
+				"Use retrieval from vetted sources",
+				"Require human review for high-stakes outputs",
+				"Label AI-generated content and its limitations",
+				"Measure factual accuracy in evaluations",
+			},
+			EvidenceTypes: []string{
+				"Evaluation reports",
+				"Human review records",
+				"User-facing disclosures",
+			},
+			ApplicableLayers: []string{"application", "society"},
+		},
+		{
+			FrameworkID: string(FrameworkOWASPLLM),
+			ControlID:   "LLM10",
+			Title:       "Unbounded Consumption",
+			Description: "Inference requests are bounded so that excessive or adversarial use cannot cause denial of service, runaway cost or model extraction.",
+			Objectives: []string{
+				"Limit request rates and resource use per principal",
+				"Bound cost and token consumption",
+				"Detect model extraction attempts",
+			},
+			Activities: []string{
+				"Apply rate limits and quotas per agent and tenant",
+				"Cap input size, output tokens and execution time",
+				"Alert on cost and usage anomalies",
+			},
+			EvidenceTypes: []string{
+				"Rate limit and quota configuration",
+				"Usage and cost dashboards",
+				"Anomaly alerts",
+			},
+			ApplicableLayers: []string{"operations", "system"},
+		},
+	}
+}
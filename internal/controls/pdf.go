@@ -0,0 +1,211 @@
+package controls
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// US Letter page geometry, in points.
+const (
+	pdfPageWidth  = 612
+	pdfPageHeight = 792
+	pdfMargin     = 54
+	// pdfMonoColumns is how many Courier characters fit a line at
+	// pdfMonoSize.
+	pdfMonoColumns = 104
+	pdfMonoSize    = 8
+)
+
+// pdfFont names the standard Type 1 fonts every PDF reader provides, so
+// reports need no embedded fonts.
+type pdfFont string
+
+const (
+	pdfHelvetica     pdfFont = "F1"
+	pdfHelveticaBold pdfFont = "F2"
+	pdfCourier       pdfFont = "F3"
+	pdfCourierBold   pdfFont = "F4"
+)
+
+var pdfFontNames = []struct {
+	ref  pdfFont
+	name string
+}{
+	{pdfHelvetica, "Helvetica"},
+	{pdfHelveticaBold, "Helvetica-Bold"},
+	{pdfCourier, "Courier"},
+	{pdfCourierBold, "Courier-Bold"},
+}
+
+type pdfLine struct {
+	font pdfFont
+	size float64
+	text string
+	y    float64
+}
+
+// pdfDocument lays out lines of text top to bottom onto pages and writes
+// them as a minimal PDF 1.4 file.
+type pdfDocument struct {
+	pages [][]pdfLine
+	y     float64
+}
+
+// add places a line, starting a new page when it does not fit. gap is the
+// space above the line.
+func (d *pdfDocument) add(font pdfFont, size, gap float64, text string) {
+	leading := size * 1.35
+	if len(d.pages) == 0 || d.y-gap-leading < pdfMargin+20 {
+		d.pages = append(d.pages, nil)
+		d.y = pdfPageHeight - pdfMargin
+		gap = 0
+	}
+	d.y -= gap + leading
+	last := len(d.pages) - 1
+	d.pages[last] = append(d.pages[last], pdfLine{font: font, size: size, text: text, y: d.y})
+}
+
+func (d *pdfDocument) title(s string)   { d.add(pdfHelveticaBold, 18, 0, s) }
+func (d *pdfDocument) heading(s string) { d.add(pdfHelveticaBold, 12, 14, s) }
+func (d *pdfDocument) text(s string)    { d.add(pdfHelvetica, 10, 2, s) }
+func (d *pdfDocument) mono(s string)    { d.add(pdfCourier, pdfMonoSize, 0, truncate(s, pdfMonoColumns)) }
+func (d *pdfDocument) bold(s string) {
+	d.add(pdfCourierBold, pdfMonoSize, 0, truncate(s, pdfMonoColumns))
+}
+func (d *pdfDocument) space() { d.add(pdfCourier, pdfMonoSize, 0, "") }
+
+// wrapped adds s word-wrapped to the page width, with prefix on the first
+// line and continuation lines indented to match.
+func (d *pdfDocument) wrapped(prefix, s string) {
+	indent := strings.Repeat(" ", len([]rune(prefix)))
+	for i, line := range wrapWords(s, pdfMonoColumns-len(indent)) {
+		if i == 0 {
+			d.mono(prefix + line)
+		} else {
+			d.mono(indent + line)
+		}
+	}
+}
+
+// wrapWords splits s into lines of at most width characters, breaking
+// words longer than a line.
+func wrapWords(s string, width int) []string {
+	var lines []string
+	var cur []rune
+	for _, word := range strings.Fields(s) {
+		w := []rune(word)
+		for len(w) > width {
+			if len(cur) > 0 {
+				lines = append(lines, string(cur))
+				cur = nil
+			}
+			lines = append(lines, string(w[:width]))
+			w = w[width:]
+		}
+		switch {
+		case len(cur) == 0:
+			cur = w
+		case len(cur)+1+len(w) <= width:
+			cur = append(append(cur, ' '), w...)
+		default:
+			lines = append(lines, string(cur))
+			cur = w
+		}
+	}
+	if len(cur) > 0 || len(lines) == 0 {
+		lines = append(lines, string(cur))
+	}
+	return lines
+}
+
+// WriteTo writes the document as a PDF file with a page number footer.
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.pages = append(d.pages, nil)
+	}
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1 and 2 are the catalog and page tree, then the fonts, then
+	// a page object and its content stream for each page.
+	fontBase := 3
+	pageBase := fontBase + len(pdfFontNames)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageBase+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	var fonts []string
+	for i, f := range pdfFontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+		fonts = append(fonts, fmt.Sprintf("/%s %d 0 R", f.ref, fontBase+i))
+	}
+	resources := "<< /Font << " + strings.Join(fonts, " ") + " >> >>"
+
+	for i, lines := range d.pages {
+		var content bytes.Buffer
+		for _, l := range lines {
+			if l.text == "" {
+				continue
+			}
+			fmt.Fprintf(&content, "BT /%s %g Tf %d %.2f Td (%s) Tj ET\n", l.font, l.size, pdfMargin, l.y, pdfString(l.text))
+		}
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		fmt.Fprintf(&content, "BT /%s 8 Tf %d %d Td (%s) Tj ET\n", pdfHelvetica, pdfMargin, pdfMargin/2, pdfString(footer))
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources %s /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, resources, pageBase+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// winAnsi maps the punctuation common in control text that WinAnsiEncoding
+// places outside Latin-1.
+var winAnsi = map[rune]byte{
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '…': 0x85,
+}
+
+// pdfString encodes s as the body of a PDF literal string in
+// WinAnsiEncoding. Characters the encoding lacks become '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := winAnsi[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,358 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Remediation plan phase groupings.
+const (
+	PlanByQuarter  = "quarter"
+	PlanByPriority = "priority"
+	PlanByEffort   = "effort"
+)
+
+// sharedActivitiesPhase groups shared activities when a plan is grouped by
+// effort.
+const sharedActivitiesPhase = "shared activities"
+
+// Remediation plan task kinds.
+const (
+	PlanTaskGap      = "gap"
+	PlanTaskActivity = "activity"
+)
+
+// PlanOptions configures a remediation plan.
+type PlanOptions struct {
+	RoadmapOptions
+	// Controls selects the gaps to plan by control ID. Empty plans every
+	// open gap.
+	Controls []string
+	// GroupBy groups tasks into phases by quarter (default), priority or
+	// effort.
+	GroupBy string
+}
+
+// RemediationPlan is a Gantt-style plan for remediating selected gaps of a
+// stored analysis: dated tasks with their predecessors, grouped into
+// phases, for import into planning tools.
+type RemediationPlan struct {
+	AnalysisID       string      `json:"analysis_id"`
+	OrganizationID   string      `json:"organization_id"`
+	Framework        string      `json:"framework"`
+	FrameworkName    string      `json:"framework_name"`
+	GroupBy          string      `json:"group_by"`
+	Capacity         int         `json:"capacity"`
+	Start            time.Time   `json:"start"`
+	End              time.Time   `json:"end"`
+	TotalEffort      int         `json:"total_effort"`
+	SharedActivities int         `json:"shared_activities"`
+	Phases           []PlanPhase `json:"phases"`
+	Tasks            []PlanTask  `json:"tasks"`
+}
+
+// PlanPhase is a group of tasks, spanning from its first task's start to
+// its last task's end.
+type PlanPhase struct {
+	Name         string    `json:"name"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	EffortPoints int       `json:"effort_points"`
+	Tasks        []string  `json:"tasks"`
+}
+
+// PlanTask is a gap to remediate, or a catalog activity that several of the
+// selected gaps share and that is done once before any of them.
+type PlanTask struct {
+	// ID numbers the task in schedule order, e.g. TASK-001.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Kind is gap or activity.
+	Kind            string `json:"kind"`
+	ControlID       string `json:"control_id,omitempty"`
+	Phase           string `json:"phase"`
+	Quarter         string `json:"quarter"`
+	Priority        string `json:"priority"`
+	EstimatedEffort string `json:"estimated_effort,omitempty"`
+	EffortPoints    int    `json:"effort_points"`
+	// Start and End are the first and last days of the task.
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	DurationDays int       `json:"duration_days"`
+	// DependsOn lists the IDs of the tasks that must finish first.
+	DependsOn []string `json:"depends_on"`
+	// SharedBy lists the gaps, by control ID, that an activity is shared by.
+	SharedBy []string `json:"shared_by,omitempty"`
+}
+
+// RemediationPlan builds a remediation plan for the selected gaps of a
+// stored analysis. Gaps are sequenced into quarters as by Roadmap, so a
+// selected gap depends only on other selected gaps. A catalog activity that
+// two or more selected gaps share becomes a task of its own, worth the
+// smallest effort, scheduled just before the first gap that needs it; each
+// of those gaps depends on it. Within a quarter tasks run back to back,
+// each taking a share of the days in proportion to its effort points.
+func (g *GapAnalyzer) RemediationPlan(ga *models.GapAnalysis, opts PlanOptions) (*RemediationPlan, error) {
+	fw, ok := g.Framework(ga.TargetFrameworkID)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework: %s", ga.TargetFrameworkID)
+	}
+	switch opts.GroupBy {
+	case "":
+		opts.GroupBy = PlanByQuarter
+	case PlanByQuarter, PlanByPriority, PlanByEffort:
+	default:
+		return nil, fmt.Errorf("unsupported grouping %q: use quarter, priority or effort", opts.GroupBy)
+	}
+	if opts.Start.IsZero() {
+		opts.Start = ga.AnalysisDate
+	}
+	if opts.Capacity <= 0 {
+		opts.Capacity = DefaultRoadmapCapacity
+	}
+
+	output, gaps := g.storedOutput(ga, fw)
+	if len(opts.Controls) > 0 {
+		selected := make(map[string]bool, len(opts.Controls))
+		for _, id := range opts.Controls {
+			if _, ok := gaps[strings.ToLower(id)]; !ok {
+				return nil, fmt.Errorf("%s is not a gap of analysis %s", id, ga.ID)
+			}
+			selected[strings.ToLower(id)] = true
+		}
+		output.Gaps = slices.DeleteFunc(output.Gaps, func(gap GapDetail) bool {
+			return !selected[strings.ToLower(gap.ControlID)]
+		})
+	}
+	roadmap, err := g.Roadmap(output, opts.RoadmapOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	activities := g.sharedActivities(ga.TargetFrameworkID, roadmap)
+	p := &RemediationPlan{
+		AnalysisID:       ga.ID,
+		OrganizationID:   ga.OrganizationID,
+		Framework:        ga.TargetFrameworkID,
+		FrameworkName:    fw.Name,
+		GroupBy:          opts.GroupBy,
+		Capacity:         opts.Capacity,
+		TotalEffort:      roadmap.TotalEffort + len(activities)*effortPoints[0],
+		SharedActivities: len(activities),
+		Phases:           []PlanPhase{},
+		Tasks:            []PlanTask{},
+	}
+	newTask := func(t PlanTask) *PlanTask {
+		t.ID = fmt.Sprintf("TASK-%03d", len(p.Tasks)+1)
+		t.DependsOn = []string{}
+		p.Tasks = append(p.Tasks, t)
+		return &p.Tasks[len(p.Tasks)-1]
+	}
+
+	taskIDs := make(map[string]string)
+	for _, phase := range roadmap.Phases {
+		// Lay out the quarter's gaps after the activities they bring in.
+		type slot struct {
+			item     *RoadmapItem
+			activity *planActivity
+		}
+		var slots []slot
+		total := 0
+		for i := range phase.Items {
+			item := &phase.Items[i]
+			for _, a := range activities {
+				if !a.scheduled && a.sharedBy[0] == item.ControlID {
+					a.scheduled = true
+					slots = append(slots, slot{activity: a})
+					total += effortPoints[0]
+				}
+			}
+			slots = append(slots, slot{item: item})
+			total += item.EffortPoints
+		}
+		// A quarter holding more than its capacity, with an oversized gap or
+		// with activities, is spread over the same days.
+		capacity := max(opts.Capacity, total)
+		used := 0
+		for _, s := range slots {
+			if a := s.activity; a != nil {
+				first := gaps[strings.ToLower(a.sharedBy[0])]
+				start, end := phaseWindow(phase, used, effortPoints[0], capacity)
+				used += effortPoints[0]
+				t := newTask(PlanTask{
+					Name:         a.name,
+					Kind:         PlanTaskActivity,
+					Quarter:      phase.Quarter,
+					Priority:     first.Priority,
+					EffortPoints: effortPoints[0],
+					Start:        start,
+					End:          end,
+					SharedBy:     a.sharedBy,
+				})
+				a.taskID = t.ID
+				continue
+			}
+			item := s.item
+			start, end := phaseWindow(phase, used, item.EffortPoints, capacity)
+			used += item.EffortPoints
+			t := newTask(PlanTask{
+				Name:            item.ControlID + " " + item.Title,
+				Kind:            PlanTaskGap,
+				ControlID:       item.ControlID,
+				Quarter:         phase.Quarter,
+				Priority:        item.Priority,
+				EstimatedEffort: item.EstimatedEffort,
+				EffortPoints:    item.EffortPoints,
+				Start:           start,
+				End:             end,
+			})
+			for _, a := range activities {
+				if slices.Contains(a.sharedBy, item.ControlID) {
+					t.DependsOn = append(t.DependsOn, a.taskID)
+				}
+			}
+			for _, dep := range item.DependsOn {
+				t.DependsOn = append(t.DependsOn, taskIDs[strings.ToLower(dep)])
+			}
+			taskIDs[strings.ToLower(item.ControlID)] = t.ID
+		}
+	}
+
+	var order []string
+	phases := make(map[string]*PlanPhase)
+	for i := range p.Tasks {
+		t := &p.Tasks[i]
+		t.DurationDays = int(t.End.Sub(t.Start).Hours()/24) + 1
+		switch opts.GroupBy {
+		case PlanByPriority:
+			t.Phase = t.Priority
+		case PlanByEffort:
+			t.Phase = t.EstimatedEffort
+			if t.Kind == PlanTaskActivity {
+				t.Phase = sharedActivitiesPhase
+			}
+		default:
+			t.Phase = t.Quarter
+		}
+		ph, ok := phases[t.Phase]
+		if !ok {
+			ph = &PlanPhase{Name: t.Phase, Start: t.Start, End: t.End}
+			phases[t.Phase] = ph
+			order = append(order, t.Phase)
+		}
+		if t.Start.Before(ph.Start) {
+			ph.Start = t.Start
+		}
+		if t.End.After(ph.End) {
+			ph.End = t.End
+		}
+		ph.EffortPoints += t.EffortPoints
+		ph.Tasks = append(ph.Tasks, t.ID)
+		if p.Start.IsZero() || t.Start.Before(p.Start) {
+			p.Start = t.Start
+		}
+		if t.End.After(p.End) {
+			p.End = t.End
+		}
+	}
+	switch opts.GroupBy {
+	case PlanByPriority:
+		slices.SortStableFunc(order, func(a, b string) int { return rankOf(a) - rankOf(b) })
+	case PlanByEffort:
+		// Shared activities come first, then sizes from smallest.
+		sizes := g.ScoringModel().ForFramework(ga.TargetFrameworkID).Effort.Sizes
+		points := func(phase string) int {
+			if phase == sharedActivitiesPhase {
+				return 0
+			}
+			return sizePoints(phase, sizes)
+		}
+		slices.SortStableFunc(order, func(a, b string) int { return points(a) - points(b) })
+	}
+	for _, name := range order {
+		p.Phases = append(p.Phases, *phases[name])
+	}
+	return p, nil
+}
+
+// planActivity is a catalog activity shared by several gaps of a plan.
+type planActivity struct {
+	name string
+	// sharedBy lists the gaps needing the activity in schedule order.
+	sharedBy  []string
+	scheduled bool
+	taskID    string
+}
+
+// sharedActivities returns the catalog activities that two or more of a
+// roadmap's gaps share, compared without regard to case, in the order the
+// roadmap first needs them.
+func (g *GapAnalyzer) sharedActivities(framework string, r *Roadmap) []*planActivity {
+	catalog := make(map[string]models.Control)
+	if list, ok := g.Controls(framework); ok {
+		for _, c := range list {
+			catalog[strings.ToLower(c.ControlID)] = c
+		}
+	}
+	byKey := make(map[string]*planActivity)
+	var all []*planActivity
+	for _, phase := range r.Phases {
+		for _, item := range phase.Items {
+			for _, activity := range catalog[strings.ToLower(item.ControlID)].Activities {
+				key := strings.ToLower(strings.TrimSpace(activity))
+				if key == "" {
+					continue
+				}
+				a, ok := byKey[key]
+				if !ok {
+					a = &planActivity{name: strings.TrimSpace(activity)}
+					byKey[key] = a
+					all = append(all, a)
+				}
+				if !slices.Contains(a.sharedBy, item.ControlID) {
+					a.sharedBy = append(a.sharedBy, item.ControlID)
+				}
+			}
+		}
+	}
+	return slices.DeleteFunc(all, func(a *planActivity) bool { return len(a.sharedBy) < 2 })
+}
+
+// PlanTable returns a remediation plan as a spreadsheet table, one row per
+// task, with columns named for import into project planning tools.
+func PlanTable(p *RemediationPlan) Table {
+	t := Table{
+		Name: "Plan",
+		Header: []string{"ID", "Name", "Type", "Control ID", "Phase", "Quarter", "Priority", "Effort",
+			"Effort Points", "Start", "Finish", "Duration (days)", "Predecessors", "Shared By"},
+	}
+	for _, task := range p.Tasks {
+		t.Rows = append(t.Rows, []any{
+			task.ID, task.Name, task.Kind, task.ControlID, task.Phase, task.Quarter, task.Priority, task.EstimatedEffort,
+			task.EffortPoints, task.Start.Format(time.DateOnly), task.End.Format(time.DateOnly), task.DurationDays,
+			strings.Join(task.DependsOn, ", "), strings.Join(task.SharedBy, ", "),
+		})
+	}
+	return t
+}
+
+// WritePlan writes a remediation plan as json, csv or xlsx.
+func WritePlan(w io.Writer, p *RemediationPlan, format string) error {
+	switch format {
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(p)
+	case ReportCSV:
+		return WriteCSV(w, PlanTable(p))
+	case ReportXLSX:
+		return WriteXLSX(w, PlanTable(p))
+	}
+	return fmt.Errorf("unsupported plan format: %s", format)
+}
@@ -0,0 +1,109 @@
+package controls_test
+
+import (
+	"context"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func TestRemediationPlan(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := &controls.AnalysisInput{TargetFramework: "nist-800-53"}
+	out, err := analyzer.RunAnalysis(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ga := controls.NewGapAnalysis("org-1", input, out, time.Date(2027, 2, 10, 0, 0, 0, 0, time.UTC))
+
+	p, err := analyzer.RemediationPlan(ga, controls.PlanOptions{RoadmapOptions: controls.RoadmapOptions{Capacity: 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.GroupBy != controls.PlanByQuarter || p.SharedActivities == 0 || len(p.Tasks) != len(ga.Gaps)+p.SharedActivities {
+		t.Fatalf("plan groups by %q with %d tasks for %d gaps and %d shared activities",
+			p.GroupBy, len(p.Tasks), len(ga.Gaps), p.SharedActivities)
+	}
+	if !p.Start.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) || p.Phases[0].Name != "2027-Q1" {
+		t.Errorf("plan starts %v in %q", p.Start, p.Phases[0].Name)
+	}
+	tasks := make(map[string]controls.PlanTask)
+	for _, task := range p.Tasks {
+		tasks[task.ID] = task
+	}
+	inPhases := 0
+	for _, ph := range p.Phases {
+		inPhases += len(ph.Tasks)
+	}
+	if inPhases != len(p.Tasks) {
+		t.Errorf("phases hold %d of %d tasks", inPhases, len(p.Tasks))
+	}
+	for _, task := range p.Tasks {
+		if task.End.Before(task.Start) || task.DurationDays < 1 {
+			t.Errorf("%s runs %v to %v for %d days", task.ID, task.Start, task.End, task.DurationDays)
+		}
+		// Every predecessor is an earlier task that finishes first.
+		for _, dep := range task.DependsOn {
+			pre, ok := tasks[dep]
+			if !ok || pre.ID >= task.ID || pre.End.After(task.Start) {
+				t.Errorf("%s depends on %s (%+v)", task.ID, dep, pre)
+			}
+		}
+		if task.Kind == controls.PlanTaskActivity {
+			if len(task.SharedBy) < 2 {
+				t.Errorf("activity %s shared by %v", task.Name, task.SharedBy)
+			}
+			for _, id := range task.SharedBy {
+				var gap controls.PlanTask
+				for _, other := range p.Tasks {
+					if other.ControlID == id {
+						gap = other
+					}
+				}
+				if !slices.Contains(gap.DependsOn, task.ID) {
+					t.Errorf("%s does not depend on shared activity %s", id, task.Name)
+				}
+			}
+		}
+	}
+
+	// A selection plans only those gaps, grouped by priority.
+	p, err = analyzer.RemediationPlan(ga, controls.PlanOptions{Controls: []string{"ac-2", "AU-2"}, GroupBy: controls.PlanByPriority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var planned []string
+	for _, task := range p.Tasks {
+		if task.Kind == controls.PlanTaskGap {
+			planned = append(planned, task.ControlID)
+		}
+		if task.Phase != task.Priority {
+			t.Errorf("%s in phase %q, want its priority %q", task.ID, task.Phase, task.Priority)
+		}
+	}
+	slices.Sort(planned)
+	if !slices.Equal(planned, []string{"AC-2", "AU-2"}) {
+		t.Errorf("planned %v, want AC-2 and AU-2", planned)
+	}
+	for _, opts := range []controls.PlanOptions{{Controls: []string{"AC-1", "XX-9"}}, {GroupBy: "owner"}} {
+		if _, err := analyzer.RemediationPlan(ga, opts); err == nil {
+			t.Errorf("%+v: expected an error", opts)
+		}
+	}
+
+	var b strings.Builder
+	if err := controls.WritePlan(&b, p, controls.ReportCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil || len(records) != len(p.Tasks)+1 || records[0][9] != "Start" || records[1][0] != "TASK-001" {
+		t.Errorf("csv = %q, %v", records[:min(len(records), 2)], err)
+	}
+}
@@ -0,0 +1,294 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+)
+
+// ReportOSCAL selects an OSCAL JSON document for exports that have one.
+const ReportOSCAL = "oscal"
+
+// POAM is a Plan of Action and Milestones for the gaps of a stored
+// analysis: each gap as an open weakness with a scheduled remediation
+// window and dated milestones.
+type POAM struct {
+	AnalysisID     string     `json:"analysis_id"`
+	OrganizationID string     `json:"organization_id"`
+	Framework      string     `json:"framework"`
+	FrameworkName  string     `json:"framework_name"`
+	AnalysisDate   time.Time  `json:"analysis_date"`
+	Capacity       int        `json:"capacity"`
+	Items          []POAMItem `json:"items"`
+}
+
+// POAMItem is one gap to close.
+type POAMItem struct {
+	// ID numbers the item in schedule order, e.g. POAM-001.
+	ID                  string          `json:"id"`
+	ControlID           string          `json:"control_id"`
+	Title               string          `json:"title"`
+	Weakness            string          `json:"weakness"`
+	GapType             string          `json:"gap_type"`
+	Priority            string          `json:"priority"`
+	EstimatedEffort     string          `json:"estimated_effort"`
+	EffortPoints        int             `json:"effort_points"`
+	Quarter             string          `json:"quarter"`
+	ScheduledStart      time.Time       `json:"scheduled_start"`
+	ScheduledCompletion time.Time       `json:"scheduled_completion"`
+	Milestones          []POAMMilestone `json:"milestones"`
+	DependsOn           []string        `json:"depends_on,omitempty"`
+	Status              string          `json:"status"`
+}
+
+// POAMMilestone is a dated step toward closing a gap.
+type POAMMilestone struct {
+	Sequence    int       `json:"sequence"`
+	Description string    `json:"description"`
+	Due         time.Time `json:"due"`
+}
+
+// POAM builds a plan of action and milestones from a stored analysis. Gaps
+// are sequenced as by Roadmap, starting in the quarter of the analysis
+// unless opts.Start is set. Within its quarter each gap takes a share of the
+// days in proportion to its effort points, so a quarter's gaps run back to
+// back, and a gap larger than the capacity takes its whole quarter. Each
+// remediation option becomes a milestone, spaced evenly across the gap's
+// window, followed by a final milestone to validate and close it on the
+// scheduled completion date.
+func (g *GapAnalyzer) POAM(ga *models.GapAnalysis, opts RoadmapOptions) (*POAM, error) {
+	fw, ok := g.Framework(ga.TargetFrameworkID)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework: %s", ga.TargetFrameworkID)
+	}
+	if opts.Start.IsZero() {
+		opts.Start = ga.AnalysisDate
+	}
+	if opts.Capacity <= 0 {
+		opts.Capacity = DefaultRoadmapCapacity
+	}
+
+	output, gaps := g.storedOutput(ga, fw)
+	roadmap, err := g.Roadmap(output, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &POAM{
+		AnalysisID:     ga.ID,
+		OrganizationID: ga.OrganizationID,
+		Framework:      ga.TargetFrameworkID,
+		FrameworkName:  fw.Name,
+		AnalysisDate:   ga.AnalysisDate,
+		Capacity:       opts.Capacity,
+		Items:          []POAMItem{},
+	}
+	for _, phase := range roadmap.Phases {
+		used := 0
+		for _, item := range phase.Items {
+			gap := gaps[strings.ToLower(item.ControlID)]
+			start, end := phaseWindow(phase, used, item.EffortPoints, opts.Capacity)
+			used += item.EffortPoints
+			p.Items = append(p.Items, POAMItem{
+				ID:                  fmt.Sprintf("POAM-%03d", len(p.Items)+1),
+				ControlID:           item.ControlID,
+				Title:               item.Title,
+				Weakness:            gap.Description,
+				GapType:             gap.GapType,
+				Priority:            item.Priority,
+				EstimatedEffort:     item.EstimatedEffort,
+				EffortPoints:        item.EffortPoints,
+				Quarter:             phase.Quarter,
+				ScheduledStart:      start,
+				ScheduledCompletion: end,
+				Milestones:          poamMilestones(item.ControlID, gap.RemediationOptions, start, end),
+				DependsOn:           item.DependsOn,
+				Status:              "open",
+			})
+		}
+	}
+	return p, nil
+}
+
+// storedOutput rebuilds the gaps of a stored analysis for sequencing,
+// with their catalog titles, and indexes the stored gaps by control ID.
+func (g *GapAnalyzer) storedOutput(ga *models.GapAnalysis, fw *models.Framework) (*AnalysisOutput, map[string]models.ControlGap) {
+	titles := make(map[string]string)
+	if list, ok := g.Controls(ga.TargetFrameworkID); ok {
+		for _, c := range list {
+			titles[strings.ToLower(c.ControlID)] = c.Title
+		}
+	}
+	output := &AnalysisOutput{Framework: ga.TargetFrameworkID, FrameworkName: fw.Name}
+	gaps := make(map[string]models.ControlGap, len(ga.Gaps))
+	for _, gap := range ga.Gaps {
+		gaps[strings.ToLower(gap.ControlID)] = gap
+		detail := GapDetail{
+			ControlID:       gap.ControlID,
+			Title:           titles[strings.ToLower(gap.ControlID)],
+			Priority:        gap.Priority,
+			EstimatedEffort: gap.EstimatedEffort,
+		}
+		if gap.Disposition != "" {
+			detail.Disposition = &models.GapDisposition{Disposition: gap.Disposition}
+		}
+		output.Gaps = append(output.Gaps, detail)
+	}
+	return output, gaps
+}
+
+// phaseWindow returns the days of a quarter given to work of points effort
+// points that starts after used points of the quarter's capacity have been
+// scheduled. Work past the capacity is held to the quarter's last day.
+func phaseWindow(phase RoadmapPhase, used, points, capacity int) (start, end time.Time) {
+	days := int(phase.End.Sub(phase.Start).Hours()/24) + 1
+	start = phase.Start.AddDate(0, 0, used*days/capacity)
+	end = phase.Start.AddDate(0, 0, (used+points)*days/capacity-1)
+	if start.After(phase.End) {
+		start = phase.End
+	}
+	if end.After(phase.End) {
+		end = phase.End
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
+
+// poamMilestones spaces a milestone per remediation option evenly between
+// start and end, then adds the closing milestone due on end.
+func poamMilestones(controlID string, options []string, start, end time.Time) []POAMMilestone {
+	span := int(end.Sub(start).Hours() / 24)
+	milestones := make([]POAMMilestone, 0, len(options)+1)
+	for i, opt := range options {
+		milestones = append(milestones, POAMMilestone{
+			Sequence:    i + 1,
+			Description: opt,
+			Due:         start.AddDate(0, 0, (i+1)*span/(len(options)+1)),
+		})
+	}
+	return append(milestones, POAMMilestone{
+		Sequence:    len(options) + 1,
+		Description: "Validate the implementation of " + controlID + " and close the item",
+		Due:         end,
+	})
+}
+
+// POAMTable returns a POA&M as a spreadsheet table, one row per gap with
+// its milestones numbered in one cell.
+func POAMTable(p *POAM) Table {
+	t := Table{
+		Name: "POA&M",
+		Header: []string{"POA&M ID", "Control ID", "Title", "Weakness", "Gap Type", "Priority", "Estimated Effort",
+			"Scheduled Start", "Scheduled Completion", "Milestones", "Depends On", "Status"},
+	}
+	for _, item := range p.Items {
+		milestones := make([]string, len(item.Milestones))
+		for i, m := range item.Milestones {
+			milestones[i] = fmt.Sprintf("%d. %s (%s)", m.Sequence, m.Description, m.Due.Format(time.DateOnly))
+		}
+		t.Rows = append(t.Rows, []any{
+			item.ID, item.ControlID, item.Title, item.Weakness, item.GapType, item.Priority, item.EstimatedEffort,
+			item.ScheduledStart.Format(time.DateOnly), item.ScheduledCompletion.Format(time.DateOnly),
+			strings.Join(milestones, "; "), strings.Join(item.DependsOn, ", "), item.Status,
+		})
+	}
+	return t
+}
+
+// OSCALPOAM exports a POA&M as an OSCAL plan of action and milestones.
+func (g *GapAnalyzer) OSCALPOAM(p *POAM) (*oscal.PlanOfActionAndMilestones, error) {
+	fw, ok := g.Framework(p.Framework)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework: %s", p.Framework)
+	}
+	entries := make([]oscal.POAMEntry, 0, len(p.Items))
+	for _, item := range p.Items {
+		e := oscal.POAMEntry{
+			ID:              item.ID,
+			ControlID:       item.ControlID,
+			Title:           item.Title,
+			Weakness:        item.Weakness,
+			GapType:         item.GapType,
+			Priority:        item.Priority,
+			EstimatedEffort: item.EstimatedEffort,
+			Start:           item.ScheduledStart,
+			Completion:      item.ScheduledCompletion,
+		}
+		for _, m := range item.Milestones {
+			e.Milestones = append(e.Milestones, oscal.POAMMilestone{Title: m.Description, Due: m.Due})
+		}
+		entries = append(entries, e)
+	}
+	return oscal.NewPlanOfActionAndMilestones(oscal.POAMInput{
+		SystemID:  p.OrganizationID,
+		Framework: fw,
+		Assessed:  p.AnalysisDate,
+		Entries:   entries,
+	}), nil
+}
+
+// WritePOAM writes a POA&M as text, json, csv, xlsx or oscal.
+func (g *GapAnalyzer) WritePOAM(w io.Writer, p *POAM, format string) error {
+	switch format {
+	case "", "text":
+		PrintPOAM(w, p)
+		return nil
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(p)
+	case ReportCSV:
+		return WriteCSV(w, POAMTable(p))
+	case ReportXLSX:
+		return WriteXLSX(w, POAMTable(p))
+	case ReportOSCAL:
+		doc, err := g.OSCALPOAM(p)
+		if err != nil {
+			return err
+		}
+		return oscal.WriteJSON(w, doc)
+	}
+	return fmt.Errorf("unsupported poam format: %s", format)
+}
+
+// PrintPOAM prints a POA&M one item at a time with its milestones.
+func PrintPOAM(w io.Writer, p *POAM) {
+	fmt.Fprintf(w, "\n╔══════════════════════════════════════════════════════════════════════════════╗\n")
+	fmt.Fprintf(w, "║                      PLAN OF ACTION AND MILESTONES                           ║\n")
+	fmt.Fprintf(w, "╚══════════════════════════════════════════════════════════════════════════════╝\n\n")
+
+	fmt.Fprintf(w, "Framework: %s (%s)\n", p.FrameworkName, p.Framework)
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════════════════════════\n\n")
+	if p.AnalysisID != "" {
+		fmt.Fprintf(w, "  Analysis:            %s\n", p.AnalysisID)
+	}
+	fmt.Fprintf(w, "  Assessed:            %s\n", p.AnalysisDate.Format(time.DateOnly))
+	fmt.Fprintf(w, "  Open Items:          %d\n\n", len(p.Items))
+
+	if len(p.Items) == 0 {
+		fmt.Fprintf(w, "No gaps to remediate.\n\n")
+		return
+	}
+
+	for _, item := range p.Items {
+		heading := fmt.Sprintf("%s  %s  %s", item.ID, item.ControlID, item.Title)
+		fmt.Fprintf(w, "%s\n%s\n", heading, strings.Repeat("─", len([]rune(heading))))
+		fmt.Fprintf(w, "  Priority %s, effort %s, %s to %s\n", item.Priority, item.EstimatedEffort,
+			item.ScheduledStart.Format(time.DateOnly), item.ScheduledCompletion.Format(time.DateOnly))
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, m := range item.Milestones {
+			fmt.Fprintf(tw, "  %d.\t%s\t%s\n", m.Sequence, m.Due.Format(time.DateOnly), m.Description)
+		}
+		tw.Flush()
+		fmt.Fprintf(w, "\n")
+	}
+}
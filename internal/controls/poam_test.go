@@ -0,0 +1,93 @@
+package controls_test
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestPOAM(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := &controls.AnalysisInput{TargetFramework: "nist-800-53", ImplementedControls: []string{"AC-1"}}
+	out, err := analyzer.RunAnalysis(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assessed := time.Date(2027, 2, 10, 0, 0, 0, 0, time.UTC)
+	ga := controls.NewGapAnalysis("org-1", input, out, assessed)
+
+	p, err := analyzer.POAM(ga, controls.RoadmapOptions{Capacity: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Items) != len(ga.Gaps) || p.AnalysisID != ga.ID {
+		t.Fatalf("poam has %d items for %d gaps, analysis %q", len(p.Items), len(ga.Gaps), p.AnalysisID)
+	}
+	// Scheduling starts in the quarter of the analysis.
+	first := p.Items[0]
+	if first.ID != "POAM-001" || first.Quarter != "2027-Q1" || !first.ScheduledStart.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first item = %+v", first)
+	}
+	gaps := make(map[string]models.ControlGap)
+	for _, g := range ga.Gaps {
+		gaps[g.ControlID] = g
+	}
+	for i, item := range p.Items {
+		if item.ScheduledCompletion.Before(item.ScheduledStart) {
+			t.Errorf("%s completes %v before it starts %v", item.ID, item.ScheduledCompletion, item.ScheduledStart)
+		}
+		// Items in a quarter run back to back.
+		if i > 0 && p.Items[i-1].Quarter == item.Quarter && !item.ScheduledStart.After(p.Items[i-1].ScheduledCompletion) {
+			t.Errorf("%s starts %v before %s completes", item.ID, item.ScheduledStart, p.Items[i-1].ID)
+		}
+		// A milestone per remediation option, then the closing one.
+		options := gaps[item.ControlID].RemediationOptions
+		if len(item.Milestones) != len(options)+1 {
+			t.Fatalf("%s has %d milestones for %d options", item.ID, len(item.Milestones), len(options))
+		}
+		for j, m := range item.Milestones {
+			if j < len(options) && m.Description != options[j] {
+				t.Errorf("%s milestone %d = %q, want %q", item.ID, j+1, m.Description, options[j])
+			}
+			if m.Due.Before(item.ScheduledStart) || m.Due.After(item.ScheduledCompletion) {
+				t.Errorf("%s milestone %d due %v outside its window", item.ID, j+1, m.Due)
+			}
+			if j > 0 && m.Due.Before(item.Milestones[j-1].Due) {
+				t.Errorf("%s milestones out of order", item.ID)
+			}
+		}
+		if last := item.Milestones[len(item.Milestones)-1]; !last.Due.Equal(item.ScheduledCompletion) {
+			t.Errorf("%s closes %v, want %v", item.ID, last.Due, item.ScheduledCompletion)
+		}
+	}
+
+	var b strings.Builder
+	if err := analyzer.WritePOAM(&b, p, controls.ReportCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil || len(records) != len(p.Items)+1 || records[1][0] != "POAM-001" || !strings.HasPrefix(records[1][9], "1. ") {
+		t.Errorf("csv = %q, %v", records[:min(len(records), 2)], err)
+	}
+
+	b.Reset()
+	if err := analyzer.WritePOAM(&b, p, controls.ReportOSCAL); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"plan-of-action-and-milestones"`) {
+		t.Errorf("oscal document = %.200s", b.String())
+	}
+
+	ga.TargetFrameworkID = "no-such-framework"
+	if _, err := analyzer.POAM(ga, controls.RoadmapOptions{}); err == nil {
+		t.Error("expected an unknown framework error")
+	}
+}
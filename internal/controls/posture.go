@@ -0,0 +1,259 @@
+package controls
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// DefaultEvidenceMaxAge is how old the latest evidence for an implemented
+// control may be before it counts as stale.
+const DefaultEvidenceMaxAge = 90 * 24 * time.Hour
+
+// Evidence sources.
+const (
+	EvidenceCheck       = "check"
+	EvidenceAttestation = "attestation"
+)
+
+// Posture is an organization's compliance posture: coverage per framework
+// and its trend from stored gap analyses, open gaps by priority from each
+// framework's latest analysis, and how fresh the evidence behind
+// implemented controls is.
+type Posture struct {
+	OrganizationID string             `json:"organization_id"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+	AsOf           *time.Time         `json:"as_of,omitempty"`
+	Since          time.Time          `json:"since"`
+	Frameworks     []FrameworkPosture `json:"frameworks"`
+	OpenGaps       map[string]int     `json:"open_gaps"`
+	TotalOpenGaps  int                `json:"total_open_gaps"`
+	Evidence       *EvidenceFreshness `json:"evidence,omitempty"`
+}
+
+// FrameworkPosture is one framework's coverage.
+type FrameworkPosture struct {
+	Framework     string `json:"framework"`
+	FrameworkName string `json:"framework_name,omitempty"`
+	// Coverage is the latest analysis's coverage percentage.
+	Coverage float64 `json:"coverage"`
+	// Change is the coverage gained since the first point of the trend.
+	Change         float64         `json:"change"`
+	LatestAnalysis string          `json:"latest_analysis_id"`
+	AnalyzedAt     time.Time       `json:"analyzed_at"`
+	OpenGaps       map[string]int  `json:"open_gaps"`
+	TotalControls  int             `json:"total_controls"`
+	Trend          []CoveragePoint `json:"trend"`
+}
+
+// EvidenceFreshness classifies implemented controls by the age of their
+// latest evidence: a passing automated check or an owner's attestation.
+type EvidenceFreshness struct {
+	MaxAgeDays int `json:"max_age_days"`
+	Total      int `json:"total"`
+	Fresh      int `json:"fresh"`
+	Stale      int `json:"stale"`
+	Missing    int `json:"missing"`
+	// FreshPercentage is the share of implemented controls with fresh
+	// evidence, to one decimal place.
+	FreshPercentage float64 `json:"fresh_percentage"`
+	// Attention lists the stale and missing controls, oldest evidence
+	// first.
+	Attention []EvidenceStatus `json:"attention"`
+}
+
+// EvidenceStatus is the latest evidence for one implemented control.
+type EvidenceStatus struct {
+	Framework      string     `json:"framework"`
+	ControlID      string     `json:"control_id"`
+	Owner          string     `json:"owner,omitempty"`
+	LastEvidenceAt *time.Time `json:"last_evidence_at,omitempty"`
+	Source         string     `json:"source,omitempty"`
+	AgeDays        int        `json:"age_days,omitempty"`
+}
+
+// PostureInput is what a posture is computed from. Implementations,
+// Attestations and Checks are optional; without Implementations the
+// posture has no evidence section.
+type PostureInput struct {
+	OrganizationID string
+	// Analyses are the organization's stored gap analyses, in any order.
+	Analyses []models.GapAnalysis
+	// FrameworkNames maps framework IDs to display names.
+	FrameworkNames  map[string]string
+	Implementations []models.ControlImplementation
+	Attestations    []models.Attestation
+	// Checks are the continuous monitoring results by lower-cased control
+	// ID, as from Monitor.ControlStatuses.
+	Checks map[string]ControlStatus
+	// Since starts the trend. Analyses before it still count as a
+	// framework's latest when there is none since.
+	Since time.Time
+	// MaxEvidenceAge defaults to DefaultEvidenceMaxAge.
+	MaxEvidenceAge time.Duration
+	Now            time.Time
+	// AsOf, when set, computes the posture as it stood at a past time:
+	// later analyses and attestations are ignored and evidence ages are
+	// measured from it. Implementations and Checks must be as of the same
+	// time.
+	AsOf time.Time
+}
+
+// BuildPosture computes a posture. Each framework's trend has one point per
+// day, the day's last analysis, so frequent runs do not flood a chart.
+// Analyses and attestations after in.Now are ignored.
+func BuildPosture(in PostureInput) *Posture {
+	if in.Now.IsZero() {
+		in.Now = time.Now()
+	}
+	p := &Posture{
+		OrganizationID: in.OrganizationID,
+		GeneratedAt:    in.Now.UTC(),
+		Since:          in.Since.UTC(),
+		Frameworks:     []FrameworkPosture{},
+		OpenGaps:       emptyPriorityCounts(),
+	}
+	if !in.AsOf.IsZero() {
+		asOf := in.AsOf.UTC()
+		p.AsOf = &asOf
+		in.Now = in.AsOf
+	}
+
+	byFramework := make(map[string][]models.GapAnalysis)
+	for _, ga := range in.Analyses {
+		if ga.AnalysisDate.After(in.Now) {
+			continue
+		}
+		byFramework[ga.TargetFrameworkID] = append(byFramework[ga.TargetFrameworkID], ga)
+	}
+	for fw, analyses := range byFramework {
+		sort.Slice(analyses, func(i, j int) bool { return analyses[i].AnalysisDate.Before(analyses[j].AnalysisDate) })
+		latest := analyses[len(analyses)-1]
+		fp := FrameworkPosture{
+			Framework:      fw,
+			FrameworkName:  in.FrameworkNames[fw],
+			Coverage:       latest.Summary.CoveragePercentage,
+			LatestAnalysis: latest.ID,
+			AnalyzedAt:     latest.AnalysisDate,
+			OpenGaps:       emptyPriorityCounts(),
+			TotalControls:  latest.Summary.TotalControls,
+			Trend:          []CoveragePoint{},
+		}
+		for _, gap := range latest.Gaps {
+			fp.OpenGaps[gapPriority(gap.Priority)]++
+			p.OpenGaps[gapPriority(gap.Priority)]++
+			p.TotalOpenGaps++
+		}
+		for _, ga := range analyses {
+			if ga.AnalysisDate.Before(in.Since) {
+				continue
+			}
+			point := CoveragePoint{Time: ga.AnalysisDate.UTC(), Coverage: ga.Summary.CoveragePercentage}
+			if n := len(fp.Trend); n > 0 && sameDay(fp.Trend[n-1].Time, point.Time) {
+				fp.Trend[n-1] = point
+				continue
+			}
+			fp.Trend = append(fp.Trend, point)
+		}
+		if len(fp.Trend) > 0 {
+			fp.Change = math.Round((fp.Coverage-fp.Trend[0].Coverage)*10) / 10
+		}
+		p.Frameworks = append(p.Frameworks, fp)
+	}
+	sort.Slice(p.Frameworks, func(i, j int) bool { return p.Frameworks[i].Framework < p.Frameworks[j].Framework })
+
+	if in.Implementations != nil {
+		p.Evidence = evidenceFreshness(in)
+	}
+	return p
+}
+
+// evidenceFreshness classifies the implemented and verified controls.
+func evidenceFreshness(in PostureInput) *EvidenceFreshness {
+	maxAge := in.MaxEvidenceAge
+	if maxAge <= 0 {
+		maxAge = DefaultEvidenceMaxAge
+	}
+	attested := make(map[string]time.Time)
+	for _, a := range in.Attestations {
+		if a.Status != models.AttestationAttested || a.RespondedAt == nil || a.RespondedAt.After(in.Now) {
+			continue
+		}
+		if a.RespondedAt.After(attested[a.ImplementationID]) {
+			attested[a.ImplementationID] = *a.RespondedAt
+		}
+	}
+
+	ef := &EvidenceFreshness{MaxAgeDays: int(maxAge.Hours() / 24), Attention: []EvidenceStatus{}}
+	for _, ci := range in.Implementations {
+		if ci.Status != models.ImplementationImplemented && ci.Status != models.ImplementationVerified {
+			continue
+		}
+		ef.Total++
+		es := EvidenceStatus{Framework: ci.FrameworkID, ControlID: ci.ControlID, Owner: ci.Owner}
+		if at, ok := attested[ci.ID]; ok {
+			es.LastEvidenceAt, es.Source = &at, EvidenceAttestation
+		}
+		if cs, ok := in.Checks[strings.ToLower(ci.ControlID)]; ok && cs.Status == CheckPassed &&
+			(es.LastEvidenceAt == nil || cs.VerifiedAt.After(*es.LastEvidenceAt)) {
+			verified := cs.VerifiedAt
+			es.LastEvidenceAt, es.Source = &verified, EvidenceCheck
+		}
+		switch {
+		case es.LastEvidenceAt == nil:
+			ef.Missing++
+		case in.Now.Sub(*es.LastEvidenceAt) > maxAge:
+			ef.Stale++
+			es.AgeDays = int(in.Now.Sub(*es.LastEvidenceAt).Hours() / 24)
+		default:
+			ef.Fresh++
+			continue
+		}
+		ef.Attention = append(ef.Attention, es)
+	}
+	if ef.Total > 0 {
+		ef.FreshPercentage = math.Round(float64(ef.Fresh)/float64(ef.Total)*1000) / 10
+	}
+	// Missing evidence first, then the oldest.
+	sort.SliceStable(ef.Attention, func(i, j int) bool {
+		a, b := ef.Attention[i].LastEvidenceAt, ef.Attention[j].LastEvidenceAt
+		if (a == nil) != (b == nil) {
+			return a == nil
+		}
+		if a != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		if ef.Attention[i].Framework != ef.Attention[j].Framework {
+			return ef.Attention[i].Framework < ef.Attention[j].Framework
+		}
+		return ef.Attention[i].ControlID < ef.Attention[j].ControlID
+	})
+	return ef
+}
+
+// emptyPriorityCounts returns zero counts for every gap priority, so
+// dashboards always see the same keys.
+func emptyPriorityCounts() map[string]int {
+	counts := make(map[string]int, len(priorityRank))
+	for p := range priorityRank {
+		counts[p] = 0
+	}
+	return counts
+}
+
+// gapPriority files unknown priorities under low.
+func gapPriority(priority string) string {
+	if _, ok := priorityRank[priority]; ok {
+		return priority
+	}
+	return "low"
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
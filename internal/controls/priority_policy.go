@@ -0,0 +1,340 @@
+package controls
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/rs/zerolog/log"
+)
+
+// PriorityRule sets the priority, the effort size or both of gaps in the
+// controls it matches. The first matching rule that sets a value decides
+// it.
+type PriorityRule struct {
+	// Name identifies the rule in validation errors.
+	Name     string    `json:"name,omitempty"`
+	Match    RuleMatch `json:"match"`
+	Priority string    `json:"priority,omitempty"`
+	// Effort is one of the effort model's size labels.
+	Effort string `json:"effort,omitempty"`
+}
+
+// RuleMatch holds a rule's conditions. A control matches when every set
+// condition holds; a list holds when the control has any of its values.
+type RuleMatch struct {
+	Frameworks []string `json:"frameworks,omitempty"`
+	// ControlIDs are case-insensitive glob patterns, such as "GOVERN-1.*".
+	ControlIDs       []string `json:"control_ids,omitempty"`
+	Families         []string `json:"families,omitempty"`
+	Layers           []string `json:"layers,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	MinEvidenceTypes int      `json:"min_evidence_types,omitempty"`
+	MinActivities    int      `json:"min_activities,omitempty"`
+	// MinRiskScore holds when the control's risk score is at least this.
+	MinRiskScore float64 `json:"min_risk_score,omitempty"`
+}
+
+// RiskModel scores the risk a control's gap poses to the organization as
+// the sum of the weights of its layers, family and tags, and maps the
+// score to a priority.
+type RiskModel struct {
+	LayerWeights  map[string]float64 `json:"layer_weights,omitempty"`
+	FamilyWeights map[string]float64 `json:"family_weights,omitempty"`
+	TagWeights    map[string]float64 `json:"tag_weights,omitempty"`
+	// Thresholds maps a priority to the lowest score that earns it. A score
+	// below every threshold leaves the priority to the layer, evidence and
+	// activity heuristics.
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+}
+
+// Score returns a control's risk score.
+func (r *RiskModel) Score(ctrl models.Control) float64 {
+	if r == nil {
+		return 0
+	}
+	score := 0.0
+	for _, layer := range ctrl.ApplicableLayers {
+		score += weightOf(r.LayerWeights, layer)
+	}
+	score += weightOf(r.FamilyWeights, ctrl.Family)
+	for _, tag := range ctrl.Tags {
+		score += weightOf(r.TagWeights, tag)
+	}
+	return score
+}
+
+// priority returns the most urgent priority whose threshold score reaches.
+func (r *RiskModel) priority(score float64) string {
+	best := ""
+	for p, floor := range r.Thresholds {
+		if score >= floor && (best == "" || priorityRank[p] < priorityRank[best]) {
+			best = p
+		}
+	}
+	return best
+}
+
+// weightOf looks a weight up by case-insensitive key.
+func weightOf(weights map[string]float64, key string) float64 {
+	if w, ok := weights[key]; ok || key == "" {
+		return w
+	}
+	for k, w := range weights {
+		if strings.EqualFold(k, key) {
+			return w
+		}
+	}
+	return 0
+}
+
+func (r *RiskModel) validate() error {
+	if r == nil {
+		return nil
+	}
+	if len(r.Thresholds) == 0 {
+		return fmt.Errorf("risk model needs at least one threshold")
+	}
+	for p := range r.Thresholds {
+		if _, ok := priorityRank[p]; !ok {
+			return fmt.Errorf("risk threshold has unknown priority %q", p)
+		}
+	}
+	return nil
+}
+
+// matches reports whether a control meets all of a rule's conditions.
+func (m *RuleMatch) matches(ctrl models.Control, riskScore float64) bool {
+	fold := strings.EqualFold
+	return anyOf([]string{ctrl.FrameworkID}, m.Frameworks, fold) &&
+		anyOf([]string{ctrl.ControlID}, m.ControlIDs, func(have, want string) bool {
+			ok, _ := path.Match(strings.ToLower(want), strings.ToLower(have))
+			return ok
+		}) &&
+		anyOf([]string{ctrl.Family}, m.Families, fold) &&
+		anyOf(ctrl.ApplicableLayers, m.Layers, fold) &&
+		anyOf(ctrl.Tags, m.Tags, fold) &&
+		len(ctrl.EvidenceTypes) >= m.MinEvidenceTypes &&
+		len(ctrl.Activities) >= m.MinActivities &&
+		riskScore >= m.MinRiskScore
+}
+
+func (r *PriorityRule) validate(i int, sizes []TShirtSize) error {
+	name := r.Name
+	if name == "" {
+		name = fmt.Sprintf("%d", i)
+	}
+	if r.Priority == "" && r.Effort == "" {
+		return fmt.Errorf("rule %s sets neither priority nor effort", name)
+	}
+	if _, ok := priorityRank[r.Priority]; r.Priority != "" && !ok {
+		return fmt.Errorf("rule %s has unknown priority %q", name, r.Priority)
+	}
+	if r.Effort != "" && !hasSize(sizes, r.Effort) {
+		return fmt.Errorf("rule %s has effort %q, which is not an effort size", name, r.Effort)
+	}
+	for _, pattern := range r.Match.ControlIDs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("rule %s: control ID pattern %q: %w", name, pattern, err)
+		}
+	}
+	if r.Match.MinEvidenceTypes < 0 || r.Match.MinActivities < 0 {
+		return fmt.Errorf("rule %s: minimums must not be negative", name)
+	}
+	return nil
+}
+
+func hasSize(sizes []TShirtSize, label string) bool {
+	for _, s := range sizes {
+		if s.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyInput is the input document a Rego priority policy is evaluated
+// with, once per gap.
+type PolicyInput struct {
+	FrameworkID   string   `json:"framework_id"`
+	ControlID     string   `json:"control_id"`
+	Title         string   `json:"title"`
+	Family        string   `json:"family"`
+	Layers        []string `json:"layers"`
+	Tags          []string `json:"tags"`
+	EvidenceTypes []string `json:"evidence_types"`
+	Activities    int      `json:"activities"`
+	// EffortScore and RiskScore are the model's scores for the control.
+	EffortScore float64 `json:"effort_score"`
+	RiskScore   float64 `json:"risk_score"`
+}
+
+// regoPackage is the package a Rego priority policy declares. Its priority
+// and effort rules, when defined, decide a gap's priority and effort.
+const regoPackage = "agentguard.gaps"
+
+// regoEvalTimeout bounds a single evaluation of a Rego priority policy. A
+// policy that runs out of time falls back to the declarative model.
+const regoEvalTimeout = 100 * time.Millisecond
+
+// maxRegoPolicies bounds the number of prepared Rego policies kept in
+// regoPolicies.
+const maxRegoPolicies = 64
+
+// regoCapabilities are the builtins a Rego priority policy may call: the
+// defaults without the nondeterministic ones, which include http.send,
+// net.lookup_ip_addr and time.now_ns. Policies come from API clients, so
+// they must not reach the network or depend on when they run.
+var regoCapabilities = func() *ast.Capabilities {
+	caps := ast.CapabilitiesForThisVersion()
+	builtins := caps.Builtins[:0]
+	for _, b := range caps.Builtins {
+		if !b.Nondeterministic {
+			builtins = append(builtins, b)
+		}
+	}
+	caps.Builtins = builtins
+	return caps
+}()
+
+// regoCache holds prepared Rego policies by the SHA-256 of their source,
+// evicting the least recently used once it holds maxRegoPolicies.
+type regoCache struct {
+	mu       sync.Mutex
+	order    *list.List // of *regoEntry, least recently used first
+	policies map[string]*list.Element
+}
+
+type regoEntry struct {
+	key string
+	pq  *rego.PreparedEvalQuery
+}
+
+var regoPolicies = &regoCache{order: list.New(), policies: make(map[string]*list.Element)}
+
+func (c *regoCache) get(key string) (*rego.PreparedEvalQuery, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.policies[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToBack(el)
+	return el.Value.(*regoEntry).pq, true
+}
+
+func (c *regoCache) put(key string, pq *rego.PreparedEvalQuery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.policies[key]; ok {
+		c.order.MoveToBack(el)
+		return
+	}
+	c.policies[key] = c.order.PushBack(&regoEntry{key: key, pq: pq})
+	for c.order.Len() > maxRegoPolicies {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.policies, oldest.Value.(*regoEntry).key)
+	}
+}
+
+// prepareRego compiles a Rego priority policy, or returns the cached one.
+func prepareRego(module string) (*rego.PreparedEvalQuery, error) {
+	sum := sha256.Sum256([]byte(module))
+	key := hex.EncodeToString(sum[:])
+	if pq, ok := regoPolicies.get(key); ok {
+		return pq, nil
+	}
+	parsed, err := ast.ParseModule("priority.rego", module)
+	if err != nil {
+		return nil, fmt.Errorf("parsing rego policy: %w", err)
+	}
+	if parsed == nil || parsed.Package.Path.String() != "data."+regoPackage {
+		return nil, fmt.Errorf("rego policy must declare package %s", regoPackage)
+	}
+	pq, err := rego.New(
+		rego.Query("data."+regoPackage),
+		rego.Module("priority.rego", module),
+		rego.Capabilities(regoCapabilities),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("compiling rego policy: %w", err)
+	}
+	regoPolicies.put(key, &pq)
+	return &pq, nil
+}
+
+// policyDecision is a Rego policy's priority and effort for one gap; either
+// is empty when the policy leaves it undefined.
+type policyDecision struct {
+	Priority string `json:"priority"`
+	Effort   string `json:"effort"`
+}
+
+// evalRego evaluates the model's Rego policy for a control. Values the
+// model cannot use are logged and ignored, so a faulty policy falls back
+// to the declarative model rather than failing the analysis. Evaluation
+// stops at ctx's deadline or after regoEvalTimeout, whichever is first.
+func (m *ScoringModel) evalRego(ctx context.Context, ctrl models.Control) policyDecision {
+	if m.Priority.Rego == "" {
+		return policyDecision{}
+	}
+	pq, err := prepareRego(m.Priority.Rego)
+	if err != nil {
+		log.Warn().Err(err).Msg("gap priority policy unavailable")
+		return policyDecision{}
+	}
+	input := PolicyInput{
+		FrameworkID:   ctrl.FrameworkID,
+		ControlID:     ctrl.ControlID,
+		Title:         ctrl.Title,
+		Family:        ctrl.Family,
+		Layers:        nonNil(ctrl.ApplicableLayers),
+		Tags:          nonNil(ctrl.Tags),
+		EvidenceTypes: nonNil(ctrl.EvidenceTypes),
+		Activities:    len(ctrl.Activities),
+		EffortScore:   m.EffortScore(ctrl),
+		RiskScore:     m.Priority.Risk.Score(ctrl),
+	}
+	ctx, cancel := context.WithTimeout(ctx, regoEvalTimeout)
+	defer cancel()
+	rs, err := pq.Eval(ctx, rego.EvalInput(input))
+	if err != nil || len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		if err != nil {
+			log.Warn().Err(err).Str("control_id", ctrl.ControlID).Msg("gap priority policy failed")
+		}
+		return policyDecision{}
+	}
+	var d policyDecision
+	data, _ := json.Marshal(rs[0].Expressions[0].Value)
+	if err := json.Unmarshal(data, &d); err != nil {
+		log.Warn().Err(err).Str("control_id", ctrl.ControlID).Msg("gap priority policy returned non-string values")
+		return policyDecision{}
+	}
+	if _, ok := priorityRank[d.Priority]; d.Priority != "" && !ok {
+		log.Warn().Str("control_id", ctrl.ControlID).Str("priority", d.Priority).Msg("gap priority policy returned an unknown priority")
+		d.Priority = ""
+	}
+	if d.Effort != "" && !hasSize(m.Effort.Sizes, d.Effort) {
+		log.Warn().Str("control_id", ctrl.ControlID).Str("effort", d.Effort).Msg("gap priority policy returned an unknown effort size")
+		d.Effort = ""
+	}
+	return d
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,308 @@
+package controls
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Report formats accepted by WriteReport.
+const (
+	ReportJSON = "json"
+	ReportHTML = "html"
+	ReportPDF  = "pdf"
+	ReportCSV  = "csv"
+	ReportXLSX = "xlsx"
+)
+
+// reportData is what the HTML and PDF reports are rendered from.
+type reportData struct {
+	*AnalysisOutput
+	GeneratedAt time.Time
+	Inherited   []models.ImplementedControl
+}
+
+func newReportData(output *AnalysisOutput) reportData {
+	d := reportData{AnalysisOutput: output, GeneratedAt: time.Now().UTC()}
+	for _, c := range output.Inventory {
+		if c.Source != models.ImplementationLocal {
+			d.Inherited = append(d.Inherited, c)
+		}
+	}
+	return d
+}
+
+// WriteReport writes the analysis in a report format: json, html, pdf, csv
+// (the gaps only) or xlsx (summary, gaps and inherited controls).
+func (g *GapAnalyzer) WriteReport(w io.Writer, output *AnalysisOutput, format string) error {
+	switch format {
+	case ReportJSON:
+		return g.PrintJSON(w, output)
+	case ReportHTML:
+		return g.PrintHTML(w, output)
+	case ReportPDF:
+		return g.PrintPDF(w, output)
+	case ReportCSV:
+		return WriteCSV(w, gapsTable(output))
+	case ReportXLSX:
+		return WriteXLSX(w, GapTables(output)...)
+	}
+	return fmt.Errorf("unsupported report format: %s", format)
+}
+
+// PrintHTML writes the analysis as a self-contained HTML document, with
+// styles inlined and no external resources, for sharing with auditors.
+func (g *GapAnalyzer) PrintHTML(w io.Writer, output *AnalysisOutput) error {
+	return htmlReport.Execute(w, newReportData(output))
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"list":    listOrNone,
+	"join":    strings.Join,
+	"rfc3339": func(t time.Time) string { return t.Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Gap Analysis: {{.FrameworkName}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; margin: 2rem auto; max-width: 60rem; padding: 0 1rem; line-height: 1.4; }
+h1 { font-size: 1.6rem; margin-bottom: 0.2rem; }
+h2 { font-size: 1.15rem; border-bottom: 1px solid #d0d7de; padding-bottom: 0.3rem; margin-top: 2rem; }
+h3 { font-size: 1rem; margin-bottom: 0.2rem; }
+.meta { color: #59636e; font-size: 0.9rem; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+th, td { text-align: left; padding: 0.35rem 0.6rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+th { background: #f6f8fa; }
+.stats td:first-child { width: 14rem; color: #59636e; }
+.priority { font-weight: 600; text-transform: capitalize; }
+.critical { color: #cf222e; } .high { color: #bc4c00; } .medium { color: #9a6700; } .low { color: #1a7f37; }
+.gap { border-bottom: 1px solid #d0d7de; padding-bottom: 0.6rem; page-break-inside: avoid; }
+@media print { body { margin: 0; max-width: none; } }
+</style>
+</head>
+<body>
+<h1>Gap Analysis Report</h1>
+<p class="meta">{{.FrameworkName}} ({{.Framework}}) &middot; generated {{rfc3339 .GeneratedAt}}</p>
+
+<h2>Coverage Summary</h2>
+<table class="stats">
+<tr><td>Total controls</td><td>{{.TotalControls}}</td></tr>
+<tr><td>Implemented</td><td>{{.ImplementedCount}}</td></tr>
+<tr><td>Inherited</td><td>{{.InheritedCount}}</td></tr>
+<tr><td>Via crosswalks</td><td>{{.CrosswalkCovered}}</td></tr>
+<tr><td>Partially covered</td><td>{{.PartialCount}}</td></tr>
+<tr><td>Gaps identified</td><td>{{.GapCount}}</td></tr>
+<tr><td>Coverage</td><td>{{printf "%.1f%%" .CoveragePercentage}}</td></tr>
+{{if or .AcceptedRiskCount .TransferredRiskCount}}<tr><td>Accepted risk</td><td>{{.AcceptedRiskCount}}</td></tr>
+<tr><td>Transferred risk</td><td>{{.TransferredRiskCount}}</td></tr>
+<tr><td>Risk-adjusted coverage</td><td>{{printf "%.1f%%" .RiskAdjustedCoverage}}</td></tr>
+{{end}}</table>
+{{with .Input}}
+<h2>Analysis Input</h2>
+<table class="stats">
+<tr><td>Implemented</td><td>{{list .ImplementedControls}}</td></tr>
+{{- if .SourceFramework}}
+<tr><td>Source framework</td><td>{{.SourceFramework}}</td></tr>
+{{- end}}
+<tr><td>Providers</td><td>{{list .Providers}}</td></tr>
+{{- if .Scoring}}
+<tr><td>Scoring model</td><td>custom</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Gaps by Priority</h2>
+<table class="stats">
+<tr><td class="priority critical">Critical</td><td>{{.Summary.Critical}}</td></tr>
+<tr><td class="priority high">High</td><td>{{.Summary.High}}</td></tr>
+<tr><td class="priority medium">Medium</td><td>{{.Summary.Medium}}</td></tr>
+<tr><td class="priority low">Low</td><td>{{.Summary.Low}}</td></tr>
+</table>
+{{if .Gaps}}
+<h2>Gaps</h2>
+<table>
+<tr><th>Control ID</th><th>Title</th><th>Priority</th><th>Effort</th><th>Coverage</th></tr>
+{{- range .Gaps}}
+<tr><td>{{.ControlID}}</td><td>{{.Title}}</td><td class="priority {{.Priority}}">{{.Priority}}</td><td>{{.EstimatedEffort}}</td><td>{{if .CoverageScore}}{{percent .CoverageScore}}{{else}}-{{end}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Gap Details</h2>
+{{- range .Gaps}}
+<div class="gap">
+<h3>{{.ControlID}}: {{.Title}}</h3>
+<p class="meta"><span class="priority {{.Priority}}">{{.Priority}}</span> priority &middot; {{.GapType}} &middot; {{.EstimatedEffort}} effort</p>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .CoveredBy}}
+<p>Partially covered by:</p>
+<ul>
+{{- range .CoveredBy}}
+<li>{{.FrameworkID}} {{.ControlID}} ({{.MappingType}}, {{percent .Confidence}}{{if .Derived}}, derived{{end}})</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .RemediationOptions}}
+<p>Remediation options:</p>
+<ul>
+{{- range .RemediationOptions}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</div>
+{{- end}}
+{{- end}}
+{{if .Inherited}}
+<h2>Inherited Controls</h2>
+<table>
+<tr><th>Control ID</th><th>Source</th><th>Providers</th></tr>
+{{- range .Inherited}}
+<tr><td>{{.ControlID}}</td><td>{{.Source}}</td><td>{{join .ProviderIDs ", "}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{if .FailingChecks}}
+<h2>Failing Monitoring Checks</h2>
+<table>
+<tr><th>Control ID</th><th>Checks</th><th>Last run</th></tr>
+{{- range .FailingChecks}}
+<tr><td>{{.ControlID}}</td><td>{{join .Checks ", "}}</td><td>{{rfc3339 .VerifiedAt}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{if .Crosswalks}}
+<h2>Crosswalk Mappings</h2>
+<table>
+<tr><th>Source</th><th>Target</th><th>Type</th><th>Confidence</th></tr>
+{{- range .Crosswalks}}
+<tr><td>{{.SourceControl}}</td><td>{{.TargetControls}}</td><td>{{.MappingType}}</td><td>{{.Confidence}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// PrintPDF writes the analysis as a PDF document.
+func (g *GapAnalyzer) PrintPDF(w io.Writer, output *AnalysisOutput) error {
+	d := newReportData(output)
+	doc := &pdfDocument{}
+
+	doc.title("Gap Analysis Report")
+	doc.text(fmt.Sprintf("%s (%s), generated %s", output.FrameworkName, output.Framework, d.GeneratedAt.Format(time.RFC3339)))
+
+	doc.heading("Coverage Summary")
+	doc.mono(fmt.Sprintf("%-20s %d", "Total controls", output.TotalControls))
+	doc.mono(fmt.Sprintf("%-20s %d", "Implemented", output.ImplementedCount))
+	doc.mono(fmt.Sprintf("%-20s %d", "Inherited", output.InheritedCount))
+	doc.mono(fmt.Sprintf("%-20s %d", "Via crosswalks", output.CrosswalkCovered))
+	doc.mono(fmt.Sprintf("%-20s %d", "Partially covered", output.PartialCount))
+	doc.mono(fmt.Sprintf("%-20s %d", "Gaps identified", output.GapCount))
+	doc.mono(fmt.Sprintf("%-20s %.1f%%", "Coverage", output.CoveragePercentage))
+	if output.AcceptedRiskCount+output.TransferredRiskCount > 0 {
+		doc.mono(fmt.Sprintf("%-20s %d", "Accepted risk", output.AcceptedRiskCount))
+		doc.mono(fmt.Sprintf("%-20s %d", "Transferred risk", output.TransferredRiskCount))
+		doc.mono(fmt.Sprintf("%-20s %.1f%%", "Risk-adjusted", output.RiskAdjustedCoverage))
+	}
+
+	if in := output.Input; in != nil {
+		doc.heading("Analysis Input")
+		doc.wrapped(fmt.Sprintf("%-20s ", "Implemented"), listOrNone(in.ImplementedControls))
+		if in.SourceFramework != "" {
+			doc.mono(fmt.Sprintf("%-20s %s", "Source framework", in.SourceFramework))
+		}
+		doc.wrapped(fmt.Sprintf("%-20s ", "Providers"), listOrNone(in.Providers))
+		if in.Scoring != nil {
+			doc.mono(fmt.Sprintf("%-20s custom", "Scoring model"))
+		}
+	}
+
+	doc.heading("Gaps by Priority")
+	doc.mono(fmt.Sprintf("%-20s %d", "Critical", output.Summary.Critical))
+	doc.mono(fmt.Sprintf("%-20s %d", "High", output.Summary.High))
+	doc.mono(fmt.Sprintf("%-20s %d", "Medium", output.Summary.Medium))
+	doc.mono(fmt.Sprintf("%-20s %d", "Low", output.Summary.Low))
+
+	if len(output.Gaps) > 0 {
+		doc.heading("Gaps")
+		row := "%-16s %-44s %-9s %-7s %s"
+		doc.bold(fmt.Sprintf(row, "CONTROL ID", "TITLE", "PRIORITY", "EFFORT", "COVERAGE"))
+		for _, gap := range output.Gaps {
+			coverage := "-"
+			if gap.CoverageScore > 0 {
+				coverage = fmt.Sprintf("%.0f%%", gap.CoverageScore*100)
+			}
+			doc.mono(fmt.Sprintf(row, truncate(gap.ControlID, 16), truncate(gap.Title, 44), gap.Priority, gap.EstimatedEffort, coverage))
+		}
+
+		doc.heading("Gap Details")
+		for _, gap := range output.Gaps {
+			doc.space()
+			doc.bold(gap.ControlID + ": " + gap.Title)
+			doc.mono(fmt.Sprintf("%s priority, %s, %s effort", gap.Priority, gap.GapType, gap.EstimatedEffort))
+			if gap.Description != "" {
+				doc.wrapped("", gap.Description)
+			}
+			for _, cb := range gap.CoveredBy {
+				doc.wrapped("  covered by: ", fmt.Sprintf("%s %s (%s, %.0f%%%s)", cb.FrameworkID, cb.ControlID, cb.MappingType, cb.Confidence*100, derivedNote(cb)))
+			}
+			for _, opt := range gap.RemediationOptions {
+				doc.wrapped("  - ", opt)
+			}
+		}
+	}
+
+	if len(d.Inherited) > 0 {
+		doc.heading("Inherited Controls")
+		row := "%-16s %-12s %s"
+		doc.bold(fmt.Sprintf(row, "CONTROL ID", "SOURCE", "PROVIDERS"))
+		for _, c := range d.Inherited {
+			doc.mono(fmt.Sprintf(row, truncate(c.ControlID, 16), c.Source, strings.Join(c.ProviderIDs, ", ")))
+		}
+	}
+
+	if len(output.FailingChecks) > 0 {
+		doc.heading("Failing Monitoring Checks")
+		row := "%-16s %-40s %s"
+		doc.bold(fmt.Sprintf(row, "CONTROL ID", "CHECKS", "LAST RUN"))
+		for _, s := range output.FailingChecks {
+			doc.mono(fmt.Sprintf(row, truncate(s.ControlID, 16), truncate(strings.Join(s.Checks, ", "), 40), s.VerifiedAt.Format(time.RFC3339)))
+		}
+	}
+
+	if len(output.Crosswalks) > 0 {
+		doc.heading("Crosswalk Mappings")
+		row := "%-18s %-30s %-18s %s"
+		doc.bold(fmt.Sprintf(row, "SOURCE", "TARGET", "TYPE", "CONFIDENCE"))
+		for _, xw := range output.Crosswalks {
+			doc.mono(fmt.Sprintf(row, truncate(xw.SourceControl, 18), truncate(xw.TargetControls, 30), xw.MappingType, xw.Confidence))
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
+
+// derivedNote marks coverage through a derived crosswalk in reports.
+func derivedNote(c models.CoverageContribution) string {
+	if c.Derived {
+		return ", derived"
+	}
+	return ""
+}
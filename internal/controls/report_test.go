@@ -0,0 +1,118 @@
+package controls_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func TestWriteReport(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework:     "iso-42001",
+		ImplementedControls: []string{"GOVERN-5"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out.Gaps[0].Title = `<script>alert("x")</script> (draft)`
+
+	t.Run("html", func(t *testing.T) {
+		var b strings.Builder
+		if err := analyzer.WriteReport(&b, out, controls.ReportHTML); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		html := b.String()
+		if !strings.HasPrefix(html, "<!DOCTYPE html>") || strings.Contains(html, "<script>") {
+			t.Errorf("expected an escaped HTML document, got %.200s", html)
+		}
+		for _, want := range []string{out.FrameworkName, out.Gaps[len(out.Gaps)-1].ControlID, "ISO42001-7.4"} {
+			if !strings.Contains(html, want) {
+				t.Errorf("report missing %q", want)
+			}
+		}
+		if strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+			t.Error("report should not reference external resources")
+		}
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		var b strings.Builder
+		if err := analyzer.WriteReport(&b, out, controls.ReportPDF); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pdf := b.String()
+		if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") {
+			t.Fatalf("expected a PDF document, got %.40q ... %q", pdf, pdf[len(pdf)-20:])
+		}
+		if !strings.Contains(pdf, `\(draft\)`) {
+			t.Error("expected parentheses in text to be escaped")
+		}
+		if !strings.Contains(pdf, "ISO42001-7.4") || !strings.Contains(pdf, "/Count ") {
+			t.Error("expected gaps laid out on pages")
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out := *out
+		out.Gaps = append([]controls.GapDetail(nil), out.Gaps...)
+		out.Gaps[1].Title = "=HYPERLINK(\"http://evil\")"
+
+		var b strings.Builder
+		if err := analyzer.WriteReport(&b, &out, controls.ReportCSV); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+		if err != nil {
+			t.Fatalf("parsing CSV: %v", err)
+		}
+		if len(records) != len(out.Gaps)+1 || records[0][0] != "Control ID" {
+			t.Fatalf("expected a header and %d gap rows, got %d rows", len(out.Gaps), len(records))
+		}
+		if records[1][0] != out.Gaps[0].ControlID || records[1][1] != out.Gaps[0].Title {
+			t.Errorf("first row = %v", records[1][:2])
+		}
+		if records[2][1] != `'=HYPERLINK("http://evil")` {
+			t.Errorf("expected a formula to be neutralized, got %q", records[2][1])
+		}
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := analyzer.WriteReport(&b, out, controls.ReportXLSX); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		parts := readZip(t, b.Bytes())
+		for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+			if _, ok := parts[name]; !ok {
+				t.Errorf("workbook missing %s", name)
+			}
+		}
+		if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Summary"`) || !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Gaps"`) {
+			t.Errorf("expected Summary and Gaps sheets, got %s", parts["xl/workbook.xml"])
+		}
+		if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Legend"`) {
+			t.Errorf("expected a Legend sheet, got %s", parts["xl/workbook.xml"])
+		}
+		gaps := parts["xl/worksheets/sheet2.xml"]
+		if !strings.Contains(gaps, "ISO42001-7.4") || !strings.Contains(gaps, "&lt;script&gt;") {
+			t.Error("expected escaped gap rows on the Gaps sheet")
+		}
+		if !strings.Contains(parts["xl/worksheets/sheet1.xml"], "<v>"+strconv.Itoa(out.GapCount)+"</v>") {
+			t.Error("expected the gap count as a number on the Summary sheet")
+		}
+	})
+
+	if err := analyzer.WriteReport(io.Discard, out, "docx"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,84 @@
+package controls
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// ErrInvalidTransition is returned for a review step a crosswalk's current
+// state does not allow.
+var ErrInvalidTransition = errors.New("invalid review transition")
+
+// ReviewCrosswalk moves a curated crosswalk to the given review state and
+// records who took the step. Mappings go from proposed to reviewed to
+// approved, and approval needs someone other than the reviewer. Any mapping
+// can be sent back to proposed, which clears its review.
+func ReviewCrosswalk(xw *models.Crosswalk, to models.ReviewState, by string, at time.Time) error {
+	if by == "" {
+		return errors.New("reviewer is required")
+	}
+	from := xw.ReviewState
+	if from == "" {
+		from = models.ReviewApproved
+	}
+	switch {
+	case to == models.ReviewProposed:
+		xw.ReviewedBy, xw.ReviewedAt = "", nil
+		xw.ApprovedBy, xw.ApprovedAt = "", nil
+	case from == models.ReviewProposed && to == models.ReviewReviewed:
+		xw.ReviewedBy, xw.ReviewedAt = by, &at
+	case from == models.ReviewReviewed && to == models.ReviewApproved:
+		if by == xw.ReviewedBy {
+			return fmt.Errorf("%w: %s reviewed the mapping and cannot also approve it", ErrInvalidTransition, by)
+		}
+		xw.ApprovedBy, xw.ApprovedAt = by, &at
+	default:
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	xw.ReviewState = to
+	return nil
+}
+
+// OverrideCrosswalks returns the built-in mappings with each control pair
+// that has an approved curated mapping replaced by it, followed by curated
+// pairs the built-in catalog does not map. Curated mappings still under
+// review are ignored.
+func OverrideCrosswalks(builtin, curated []models.Crosswalk) []models.Crosswalk {
+	approved := make(map[[2]string]models.Crosswalk, len(curated))
+	var order [][2]string
+	for _, xw := range curated {
+		if xw.ReviewState != "" && xw.ReviewState != models.ReviewApproved {
+			continue
+		}
+		key := crosswalkKey(xw)
+		if _, ok := approved[key]; !ok {
+			order = append(order, key)
+		}
+		approved[key] = xw
+	}
+
+	out := make([]models.Crosswalk, 0, len(builtin)+len(approved))
+	for _, xw := range builtin {
+		key := crosswalkKey(xw)
+		if override, ok := approved[key]; ok {
+			out = append(out, override)
+			delete(approved, key)
+			continue
+		}
+		out = append(out, xw)
+	}
+	for _, key := range order {
+		if xw, ok := approved[key]; ok {
+			out = append(out, xw)
+		}
+	}
+	return out
+}
+
+func crosswalkKey(xw models.Crosswalk) [2]string {
+	return [2]string{strings.ToLower(xw.SourceControlID), strings.ToLower(xw.TargetControlID)}
+}
@@ -0,0 +1,64 @@
+package controls_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestReviewCrosswalk(t *testing.T) {
+	now := time.Now()
+	xw := &models.Crosswalk{SourceControlID: "CC6.1", TargetControlID: "IAM-01", ReviewState: models.ReviewProposed}
+
+	if err := controls.ReviewCrosswalk(xw, models.ReviewApproved, "bob", now); !errors.Is(err, controls.ErrInvalidTransition) {
+		t.Fatalf("approving a proposed mapping = %v, want ErrInvalidTransition", err)
+	}
+	if err := controls.ReviewCrosswalk(xw, models.ReviewReviewed, "alice", now); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if err := controls.ReviewCrosswalk(xw, models.ReviewApproved, "alice", now); !errors.Is(err, controls.ErrInvalidTransition) {
+		t.Fatalf("reviewer approving their own review = %v, want ErrInvalidTransition", err)
+	}
+	if err := controls.ReviewCrosswalk(xw, models.ReviewApproved, "bob", now); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if xw.ReviewState != models.ReviewApproved || xw.ReviewedBy != "alice" || xw.ApprovedBy != "bob" || xw.ApprovedAt == nil {
+		t.Errorf("approved mapping = %+v", xw)
+	}
+
+	if err := controls.ReviewCrosswalk(xw, models.ReviewProposed, "carol", now); err != nil {
+		t.Fatalf("send back: %v", err)
+	}
+	if xw.ReviewState != models.ReviewProposed || xw.ReviewedBy != "" || xw.ApprovedBy != "" {
+		t.Errorf("mapping sent back still carries its review: %+v", xw)
+	}
+}
+
+func TestOverrideCrosswalks(t *testing.T) {
+	builtin := []models.Crosswalk{
+		{SourceControlID: "CC6.1", TargetControlID: "AC-2", MappingType: models.MappingPartial, Confidence: 0.7},
+		{SourceControlID: "CC7.2", TargetControlID: "SI-4", MappingType: models.MappingPartial, Confidence: 0.6},
+	}
+	curated := []models.Crosswalk{
+		{ID: "a", SourceControlID: "cc6.1", TargetControlID: "ac-2", MappingType: models.MappingExact, Confidence: 0.95, ReviewState: models.ReviewApproved},
+		{ID: "b", SourceControlID: "CC7.2", TargetControlID: "SI-4", MappingType: models.MappingRelated, ReviewState: models.ReviewReviewed},
+		{ID: "c", SourceControlID: "CC8.1", TargetControlID: "CM-3", MappingType: models.MappingExact, ReviewState: models.ReviewApproved},
+	}
+
+	got := controls.OverrideCrosswalks(builtin, curated)
+	if len(got) != 3 {
+		t.Fatalf("got %d mappings, want 3: %+v", len(got), got)
+	}
+	if got[0].ID != "a" || got[0].MappingType != models.MappingExact {
+		t.Errorf("approved mapping did not override the built-in one: %+v", got[0])
+	}
+	if got[1].ID != "" || got[1].MappingType != models.MappingPartial {
+		t.Errorf("mapping under review overrode the built-in one: %+v", got[1])
+	}
+	if got[2].ID != "c" {
+		t.Errorf("approved mapping without a built-in one missing: %+v", got[2])
+	}
+}
@@ -0,0 +1,465 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// DefaultRoadmapCapacity is the effort points scheduled per quarter when
+// RoadmapOptions leaves Capacity unset.
+const DefaultRoadmapCapacity = 8
+
+// effortPoints weights effort sizes, smallest first, so larger work counts
+// for more than its rank; sizes past the end take the last weight.
+var effortPoints = []int{1, 2, 3, 5, 8, 13, 21}
+
+// priorityRank orders gap priorities, most urgent first.
+var priorityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// foundationLayer marks the controls other controls in their family build on.
+const foundationLayer = "governance"
+
+// RoadmapOptions configures roadmap sequencing.
+type RoadmapOptions struct {
+	// Capacity is the effort points that fit in one quarter. Defaults to
+	// DefaultRoadmapCapacity.
+	Capacity int
+	// Start is a day in the first quarter. Defaults to today.
+	Start time.Time
+	// Dependencies adds prerequisites, keyed by control ID, to those
+	// inferred from the catalog.
+	Dependencies map[string][]string
+}
+
+// Roadmap is a gap analysis sequenced into quarterly remediation phases.
+type Roadmap struct {
+	Framework     string         `json:"framework"`
+	FrameworkName string         `json:"framework_name"`
+	Capacity      int            `json:"capacity"`
+	TotalEffort   int            `json:"total_effort"`
+	QuickWins     int            `json:"quick_wins"`
+	Phases        []RoadmapPhase `json:"phases"`
+}
+
+// RoadmapPhase is the work scheduled for one quarter, in order.
+type RoadmapPhase struct {
+	Quarter string        `json:"quarter"`
+	Start   time.Time     `json:"start"`
+	End     time.Time     `json:"end"`
+	Effort  int           `json:"effort"`
+	Items   []RoadmapItem `json:"items"`
+}
+
+// RoadmapItem is one gap to remediate.
+type RoadmapItem struct {
+	ControlID       string `json:"control_id"`
+	Title           string `json:"title"`
+	Priority        string `json:"priority"`
+	EstimatedEffort string `json:"estimated_effort"`
+	EffortPoints    int    `json:"effort_points"`
+	// QuickWin marks small, independent gaps of at least medium priority,
+	// scheduled ahead of other work.
+	QuickWin bool `json:"quick_win,omitempty"`
+	// DependsOn lists open gaps that must be remediated first.
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Unblocks lists the open gaps that depend on this one.
+	Unblocks []string `json:"unblocks,omitempty"`
+}
+
+// roadmapNode is a gap being sequenced.
+type roadmapNode struct {
+	item       RoadmapItem
+	deps       []*roadmapNode
+	dependents []*roadmapNode
+	// rank is the most urgent priority among the gap and everything that
+	// depends on it, so prerequisites of urgent work are pulled forward.
+	rank      int
+	scheduled bool
+}
+
+// Roadmap sequences an analysis's gaps into quarters. A gap depends on its
+// parent control and on the governance controls of its family when those
+// are gaps too, plus any dependencies in opts. Gaps are
+// scheduled once their dependencies are, quick wins first, then by the most
+// urgent priority they unblock, the number of gaps they unblock, and effort;
+// each quarter takes gaps up to its capacity, and a gap larger than the
+// capacity gets a quarter of its own. Gaps whose risk is accepted or
+// transferred are not scheduled.
+func (g *GapAnalyzer) Roadmap(output *AnalysisOutput, opts RoadmapOptions) (*Roadmap, error) {
+	if opts.Capacity <= 0 {
+		opts.Capacity = DefaultRoadmapCapacity
+	}
+	if opts.Start.IsZero() {
+		opts.Start = time.Now()
+	}
+	scoring := g.ScoringModel()
+	if output.Input != nil && output.Input.Scoring != nil {
+		scoring = output.Input.Scoring
+	}
+	sizes := scoring.ForFramework(output.Framework).Effort.Sizes
+
+	catalog := make(map[string]models.Control)
+	if list, ok := g.Controls(output.Framework); ok {
+		for _, c := range list {
+			catalog[strings.ToLower(c.ControlID)] = c
+		}
+	}
+
+	nodes := make(map[string]*roadmapNode, len(output.Gaps))
+	order := make([]*roadmapNode, 0, len(output.Gaps))
+	for _, gap := range output.Gaps {
+		if gap.RiskAccepted() {
+			continue
+		}
+		n := &roadmapNode{item: RoadmapItem{
+			ControlID:       gap.ControlID,
+			Title:           gap.Title,
+			Priority:        gap.Priority,
+			EstimatedEffort: gap.EstimatedEffort,
+			EffortPoints:    sizePoints(gap.EstimatedEffort, sizes),
+		}}
+		n.rank = rankOf(gap.Priority)
+		nodes[strings.ToLower(gap.ControlID)] = n
+		order = append(order, n)
+	}
+
+	link := func(n *roadmapNode, prerequisite string) {
+		dep, ok := nodes[strings.ToLower(prerequisite)]
+		if !ok || dep == n {
+			return
+		}
+		for _, d := range n.deps {
+			if d == dep {
+				return
+			}
+		}
+		n.deps = append(n.deps, dep)
+		dep.dependents = append(dep.dependents, n)
+	}
+
+	// Governance gaps found each family's remaining gaps.
+	foundations := make(map[string][]string)
+	for _, n := range order {
+		ctrl := catalog[strings.ToLower(n.item.ControlID)]
+		if family := controlFamily(ctrl); family != "" && hasLayer(ctrl, foundationLayer) {
+			foundations[family] = append(foundations[family], ctrl.ControlID)
+		}
+	}
+	for _, n := range order {
+		ctrl := catalog[strings.ToLower(n.item.ControlID)]
+		if ctrl.ParentControlID != nil {
+			link(n, *ctrl.ParentControlID)
+		}
+		if family := controlFamily(ctrl); family != "" && !hasLayer(ctrl, foundationLayer) {
+			for _, id := range foundations[family] {
+				link(n, id)
+			}
+		}
+		for id, prerequisites := range opts.Dependencies {
+			if strings.EqualFold(id, n.item.ControlID) {
+				for _, p := range prerequisites {
+					link(n, p)
+				}
+			}
+		}
+	}
+
+	sorted, err := topoSort(order)
+	if err != nil {
+		return nil, err
+	}
+	// Dependents come after their prerequisites, so walking backwards
+	// settles each node's rank before its prerequisites read it.
+	for i := len(sorted) - 1; i >= 0; i-- {
+		n := sorted[i]
+		for _, d := range n.dependents {
+			n.rank = min(n.rank, d.rank)
+		}
+	}
+
+	r := &Roadmap{
+		Framework:     output.Framework,
+		FrameworkName: output.FrameworkName,
+		Capacity:      opts.Capacity,
+		Phases:        []RoadmapPhase{},
+	}
+	for _, n := range order {
+		n.item.QuickWin = len(n.deps) == 0 && n.item.EffortPoints == effortPoints[0] && rankOf(n.item.Priority) <= priorityRank["medium"]
+		for _, d := range n.deps {
+			n.item.DependsOn = append(n.item.DependsOn, d.item.ControlID)
+		}
+		for _, d := range n.dependents {
+			n.item.Unblocks = append(n.item.Unblocks, d.item.ControlID)
+		}
+		sort.Strings(n.item.DependsOn)
+		sort.Strings(n.item.Unblocks)
+		r.TotalEffort += n.item.EffortPoints
+		if n.item.QuickWin {
+			r.QuickWins++
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].before(order[j]) })
+	quarter := quarterStart(opts.Start)
+	for remaining := len(order); remaining > 0; quarter = quarter.AddDate(0, 3, 0) {
+		phase := RoadmapPhase{
+			Quarter: quarterLabel(quarter),
+			Start:   quarter,
+			End:     quarter.AddDate(0, 3, -1),
+		}
+		for {
+			next := nextReady(order, opts.Capacity-phase.Effort, len(phase.Items) == 0)
+			if next == nil {
+				break
+			}
+			next.scheduled = true
+			remaining--
+			phase.Effort += next.item.EffortPoints
+			phase.Items = append(phase.Items, next.item)
+		}
+		r.Phases = append(r.Phases, phase)
+	}
+	return r, nil
+}
+
+// before orders ready gaps for scheduling.
+func (n *roadmapNode) before(o *roadmapNode) bool {
+	if n.item.QuickWin != o.item.QuickWin {
+		return n.item.QuickWin
+	}
+	if n.rank != o.rank {
+		return n.rank < o.rank
+	}
+	if len(n.dependents) != len(o.dependents) {
+		return len(n.dependents) > len(o.dependents)
+	}
+	if n.item.EffortPoints != o.item.EffortPoints {
+		return n.item.EffortPoints < o.item.EffortPoints
+	}
+	return n.item.ControlID < o.item.ControlID
+}
+
+// nextReady returns the first unscheduled gap, in scheduling order, whose
+// dependencies are scheduled and whose effort fits in room. An empty
+// quarter takes the first ready gap whatever its size.
+func nextReady(order []*roadmapNode, room int, empty bool) *roadmapNode {
+	for _, n := range order {
+		if n.scheduled || !n.ready() {
+			continue
+		}
+		if empty || n.item.EffortPoints <= room {
+			return n
+		}
+	}
+	return nil
+}
+
+func (n *roadmapNode) ready() bool {
+	for _, d := range n.deps {
+		if !d.scheduled {
+			return false
+		}
+	}
+	return true
+}
+
+// topoSort orders nodes so prerequisites precede their dependents, or
+// reports the gaps caught in a dependency cycle.
+func topoSort(nodes []*roadmapNode) ([]*roadmapNode, error) {
+	pending := make(map[*roadmapNode]int, len(nodes))
+	var queue []*roadmapNode
+	for _, n := range nodes {
+		pending[n] = len(n.deps)
+		if len(n.deps) == 0 {
+			queue = append(queue, n)
+		}
+	}
+	sorted := make([]*roadmapNode, 0, len(nodes))
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, n)
+		for _, d := range n.dependents {
+			if pending[d]--; pending[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+	if len(sorted) < len(nodes) {
+		var cycle []string
+		for _, n := range nodes {
+			if pending[n] > 0 {
+				cycle = append(cycle, n.item.ControlID)
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle among %s", strings.Join(cycle, ", "))
+	}
+	return sorted, nil
+}
+
+// sizePoints returns the effort points of an effort size label. An unknown
+// label is weighted as the middle size.
+func sizePoints(label string, sizes []TShirtSize) int {
+	rank := len(sizes) / 2
+	for i, size := range sizes {
+		if strings.EqualFold(size.Label, label) {
+			rank = i
+			break
+		}
+	}
+	return effortPoints[min(rank, len(effortPoints)-1)]
+}
+
+func rankOf(priority string) int {
+	if rank, ok := priorityRank[priority]; ok {
+		return rank
+	}
+	return len(priorityRank)
+}
+
+// controlFamily returns a control's family, or else its ID up to the last
+// '-' or '.', which groups catalog numbering such as AC-2 under AC and
+// ISO42001-6.1 under ISO42001-6.
+func controlFamily(ctrl models.Control) string {
+	if ctrl.Family != "" {
+		return ctrl.Family
+	}
+	if i := strings.LastIndexAny(ctrl.ControlID, "-."); i > 0 {
+		return strings.ToLower(ctrl.ControlID[:i])
+	}
+	return ""
+}
+
+func hasLayer(ctrl models.Control, layer string) bool {
+	for _, l := range ctrl.ApplicableLayers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+// quarterStart returns midnight UTC on the first day of t's quarter.
+func quarterStart(t time.Time) time.Time {
+	t = t.UTC()
+	month := time.Month((int(t.Month())-1)/3*3 + 1)
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func quarterLabel(t time.Time) string {
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// ParseQuarter parses a roadmap start given as a quarter (2027-Q1) or a
+// date (2027-01-15).
+func ParseQuarter(s string) (time.Time, error) {
+	if year, q, ok := strings.Cut(strings.ToUpper(s), "-Q"); ok {
+		y, err := strconv.Atoi(year)
+		n, qerr := strconv.Atoi(q)
+		if err != nil || qerr != nil || n < 1 || n > 4 {
+			return time.Time{}, fmt.Errorf("invalid quarter %q", s)
+		}
+		return time.Date(y, time.Month((n-1)*3+1), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start %q: want a quarter such as 2027-Q1 or a date", s)
+	}
+	return t, nil
+}
+
+// RoadmapTable returns a roadmap as a spreadsheet table, one row per gap in
+// schedule order.
+func RoadmapTable(r *Roadmap) Table {
+	t := Table{
+		Name: "Roadmap",
+		Header: []string{"Quarter", "Order", "Control ID", "Title", "Priority", "Effort",
+			"Effort Points", "Quick Win", "Depends On", "Unblocks"},
+	}
+	for _, phase := range r.Phases {
+		for i, item := range phase.Items {
+			quickWin := ""
+			if item.QuickWin {
+				quickWin = "yes"
+			}
+			t.Rows = append(t.Rows, []any{
+				phase.Quarter, i + 1, item.ControlID, item.Title, item.Priority, item.EstimatedEffort,
+				item.EffortPoints, quickWin, strings.Join(item.DependsOn, ", "), strings.Join(item.Unblocks, ", "),
+			})
+		}
+	}
+	return t
+}
+
+// WriteRoadmap writes a roadmap as text, json, csv or xlsx.
+func WriteRoadmap(w io.Writer, r *Roadmap, format string) error {
+	switch format {
+	case "", "text":
+		PrintRoadmap(w, r)
+		return nil
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case ReportCSV:
+		return WriteCSV(w, RoadmapTable(r))
+	case ReportXLSX:
+		return WriteXLSX(w, RoadmapTable(r))
+	}
+	return fmt.Errorf("unsupported roadmap format: %s", format)
+}
+
+// PrintRoadmap prints a roadmap one quarter at a time.
+func PrintRoadmap(w io.Writer, r *Roadmap) {
+	fmt.Fprintf(w, "\n╔══════════════════════════════════════════════════════════════════════════════╗\n")
+	fmt.Fprintf(w, "║                          REMEDIATION ROADMAP                                 ║\n")
+	fmt.Fprintf(w, "╚══════════════════════════════════════════════════════════════════════════════╝\n\n")
+
+	fmt.Fprintf(w, "Framework: %s (%s)\n", r.FrameworkName, r.Framework)
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════════════════════════\n\n")
+	fmt.Fprintf(w, "  Effort:              %d points over %d quarters\n", r.TotalEffort, len(r.Phases))
+	fmt.Fprintf(w, "  Capacity:            %d points per quarter\n", r.Capacity)
+	fmt.Fprintf(w, "  Quick Wins:          %d\n\n", r.QuickWins)
+
+	if len(r.Phases) == 0 {
+		fmt.Fprintf(w, "No gaps to remediate.\n\n")
+		return
+	}
+
+	for _, phase := range r.Phases {
+		heading := fmt.Sprintf("%s (%s to %s, %d points)", phase.Quarter,
+			phase.Start.Format(time.DateOnly), phase.End.Format(time.DateOnly), phase.Effort)
+		fmt.Fprintf(w, "%s\n%s\n", heading, strings.Repeat("─", len([]rune(heading))))
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  CONTROL ID\tTITLE\tPRIORITY\tEFFORT\tDEPENDS ON\n")
+		for _, item := range phase.Items {
+			id := item.ControlID
+			if item.QuickWin {
+				id += " *"
+			}
+			title := item.Title
+			if len(title) > 40 {
+				title = title[:37] + "..."
+			}
+			deps := "-"
+			if len(item.DependsOn) > 0 {
+				deps = strings.Join(item.DependsOn, ", ")
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", id, title, item.Priority, item.EstimatedEffort, deps)
+		}
+		tw.Flush()
+		fmt.Fprintf(w, "\n")
+	}
+	if r.QuickWins > 0 {
+		fmt.Fprintf(w, "* quick win: small, independent and at least medium priority\n\n")
+	}
+}
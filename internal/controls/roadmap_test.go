@@ -0,0 +1,126 @@
+package controls_test
+
+import (
+	"context"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func TestRoadmap(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework:     "nist-800-53",
+		ImplementedControls: []string{"AC-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := analyzer.Roadmap(out, controls.RoadmapOptions{
+		Capacity:     4,
+		Start:        time.Date(2027, 2, 10, 0, 0, 0, 0, time.UTC),
+		Dependencies: map[string][]string{"ac-3": {"IA-2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Phases) == 0 || r.Phases[0].Quarter != "2027-Q1" || !r.Phases[0].Start.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!r.Phases[0].End.Equal(time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("first phase = %+v", r.Phases)
+	}
+
+	position := make(map[string]int)
+	items := make(map[string]controls.RoadmapItem)
+	for p, phase := range r.Phases {
+		if phase.Effort > r.Capacity && len(phase.Items) > 1 {
+			t.Errorf("%s holds %d points over capacity %d", phase.Quarter, phase.Effort, r.Capacity)
+		}
+		for _, item := range phase.Items {
+			position[item.ControlID] = p*1000 + len(position)
+			items[item.ControlID] = item
+		}
+	}
+	if len(items) != out.GapCount {
+		t.Fatalf("scheduled %d gaps, want %d", len(items), out.GapCount)
+	}
+	for id, item := range items {
+		for _, dep := range item.DependsOn {
+			if position[dep] >= position[id] {
+				t.Errorf("%s scheduled before its dependency %s", id, dep)
+			}
+		}
+	}
+	// Governance controls found their family; implemented ones are no
+	// longer dependencies. Caller dependencies add to the inferred ones.
+	if got := items["AU-6"].DependsOn; !slices.Equal(got, []string{"AU-1"}) {
+		t.Errorf("AU-6 depends on %v, want [AU-1]", got)
+	}
+	if got := items["AC-3"].DependsOn; !slices.Equal(got, []string{"IA-2"}) {
+		t.Errorf("AC-3 depends on %v, want [IA-2]", got)
+	}
+	if got := items["AU-1"].Unblocks; !slices.Contains(got, "AU-6") {
+		t.Errorf("AU-1 unblocks %v, want AU-6 among them", got)
+	}
+
+	if _, err := analyzer.Roadmap(out, controls.RoadmapOptions{Dependencies: map[string][]string{"AU-1": {"AU-6"}}}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a dependency cycle error, got %v", err)
+	}
+
+	// With every gap small, independent gaps of at least medium priority
+	// are quick wins and lead the roadmap.
+	out.Input = &controls.AnalysisInput{Scoring: &controls.ScoringModel{Effort: controls.EffortModel{
+		Sizes: []controls.TShirtSize{{Label: "small", MaxScore: 100}, {Label: "large"}},
+	}}}
+	for i := range out.Gaps {
+		out.Gaps[i].EstimatedEffort = "small"
+	}
+	r, err = analyzer.Roadmap(out, controls.RoadmapOptions{Capacity: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := r.Phases[0].Items[0]
+	if r.QuickWins == 0 || !first.QuickWin || first.EffortPoints != 1 {
+		t.Errorf("quick wins = %d, first item = %+v", r.QuickWins, first)
+	}
+	for _, phase := range r.Phases {
+		for _, item := range phase.Items {
+			if item.ControlID == "AU-6" && item.QuickWin {
+				t.Error("AU-6 depends on AU-1 and is not a quick win")
+			}
+		}
+	}
+
+	var b strings.Builder
+	if err := controls.WriteRoadmap(&b, r, controls.ReportCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil || len(records) != out.GapCount+1 || records[1][0] != r.Phases[0].Quarter {
+		t.Errorf("csv = %q, %v", records, err)
+	}
+}
+
+func TestParseQuarter(t *testing.T) {
+	for in, want := range map[string]time.Time{
+		"2027-Q1":    time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2026-q4":    time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		"2027-05-20": time.Date(2027, 5, 20, 0, 0, 0, 0, time.UTC),
+	} {
+		if got, err := controls.ParseQuarter(in); err != nil || !got.Equal(want) {
+			t.Errorf("ParseQuarter(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"2027-Q5", "next year", "2027-13-01"} {
+		if _, err := controls.ParseQuarter(in); err == nil {
+			t.Errorf("ParseQuarter(%q) accepted", in)
+		}
+	}
+}
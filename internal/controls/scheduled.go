@@ -0,0 +1,212 @@
+package controls
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/rs/zerolog/log"
+)
+
+// GapDiff is what changed between two gap analyses of one framework: the
+// gaps opened by the newer analysis and those it closed.
+type GapDiff struct {
+	OrganizationID     string              `json:"organization_id"`
+	Framework          string              `json:"framework"`
+	AnalysisID         string              `json:"analysis_id"`
+	PreviousAnalysisID string              `json:"previous_analysis_id"`
+	AnalyzedAt         time.Time           `json:"analyzed_at"`
+	Coverage           float64             `json:"coverage"`
+	PreviousCoverage   float64             `json:"previous_coverage"`
+	Opened             []models.ControlGap `json:"opened"`
+	Closed             []models.ControlGap `json:"closed"`
+}
+
+// Changed reports whether any gap opened or closed.
+func (d *GapDiff) Changed() bool {
+	return len(d.Opened) > 0 || len(d.Closed) > 0
+}
+
+// DiffGapAnalyses compares two analyses of the same framework, matching
+// gaps by control ID. A gap whose risk is accepted or transferred is not
+// open, so accepting a gap's risk closes it. Opened and closed gaps are
+// sorted by control ID.
+func DiffGapAnalyses(prev, cur *models.GapAnalysis) *GapDiff {
+	d := &GapDiff{
+		OrganizationID:     cur.OrganizationID,
+		Framework:          cur.TargetFrameworkID,
+		AnalysisID:         cur.ID,
+		PreviousAnalysisID: prev.ID,
+		AnalyzedAt:         cur.AnalysisDate,
+		Coverage:           cur.Summary.CoveragePercentage,
+		PreviousCoverage:   prev.Summary.CoveragePercentage,
+		Opened:             []models.ControlGap{},
+		Closed:             []models.ControlGap{},
+	}
+	open := func(g models.ControlGap) bool {
+		return g.Disposition == "" || g.Disposition == models.DispositionRemediate
+	}
+	before := make(map[string]bool, len(prev.Gaps))
+	for _, g := range prev.Gaps {
+		before[strings.ToLower(g.ControlID)] = open(g)
+	}
+	after := make(map[string]bool, len(cur.Gaps))
+	for _, g := range cur.Gaps {
+		after[strings.ToLower(g.ControlID)] = open(g)
+		if open(g) && !before[strings.ToLower(g.ControlID)] {
+			d.Opened = append(d.Opened, g)
+		}
+	}
+	for _, g := range prev.Gaps {
+		if open(g) && !after[strings.ToLower(g.ControlID)] {
+			d.Closed = append(d.Closed, g)
+		}
+	}
+	sort.Slice(d.Opened, func(i, j int) bool { return d.Opened[i].ControlID < d.Opened[j].ControlID })
+	sort.Slice(d.Closed, func(i, j int) bool { return d.Closed[i].ControlID < d.Closed[j].ControlID })
+	return d
+}
+
+// ScheduledAnalyses re-runs gap analysis for a set of frameworks on a cron
+// schedule, so posture follows changes to tracked implementations without
+// anyone running the CLI. Each run is stored like any other analysis, and
+// gaps opened or closed since the framework's previous analysis are sent
+// to a webhook.
+type ScheduledAnalyses struct {
+	analyzer        *GapAnalyzer
+	analyses        repository.GapAnalysisRepository
+	implementations repository.ControlImplementationRepository
+	dispositions    repository.GapDispositionRepository
+	tags            repository.ControlTagRepository
+	coverage        *CoverageHistory
+	// webhook receives a JSON POST per changed framework. When nil,
+	// changes are logged.
+	webhook *webhook.Sender
+	now     func() time.Time
+}
+
+// NewScheduledAnalyses creates a scheduled analysis runner. dispositions,
+// tags, coverage and hook may be nil.
+func NewScheduledAnalyses(analyzer *GapAnalyzer, analyses repository.GapAnalysisRepository, implementations repository.ControlImplementationRepository, dispositions repository.GapDispositionRepository, tags repository.ControlTagRepository, coverage *CoverageHistory, hook *webhook.Sender) *ScheduledAnalyses {
+	return &ScheduledAnalyses{
+		analyzer:        analyzer,
+		analyses:        analyses,
+		implementations: implementations,
+		dispositions:    dispositions,
+		tags:            tags,
+		coverage:        coverage,
+		webhook:         hook,
+		now:             time.Now,
+	}
+}
+
+// Run analyzes each organization's frameworks at every time the schedule
+// matches, in UTC, until ctx is cancelled.
+func (s *ScheduledAnalyses) Run(ctx context.Context, schedule *Schedule, orgs, frameworks []string) {
+	for {
+		next := schedule.Next(s.now().UTC())
+		if next.IsZero() {
+			log.Warn().Str("schedule", schedule.String()).Msg("gap analysis schedule never matches")
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		for _, org := range orgs {
+			if _, err := s.RunOnce(ctx, org, frameworks); err != nil {
+				log.Error().Err(err).Str("org_id", org).Msg("scheduled gap analysis failed")
+			}
+		}
+	}
+}
+
+// RunOnce analyzes an organization's frameworks against its tracked
+// implementations, gap dispositions and control tags, as an on-demand
+// analysis does, stores the analyses and sends the changes since each
+// framework's previous analysis. It returns the diffs, including unchanged
+// ones; a framework's first analysis has no diff. A failure to send a diff
+// is logged and does not fail the run.
+func (s *ScheduledAnalyses) RunOnce(ctx context.Context, org string, frameworks []string) ([]GapDiff, error) {
+	stored, err := s.analyses.List(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("listing gap analyses: %w", err)
+	}
+	previous := make(map[string]*models.GapAnalysis)
+	for i := range stored {
+		ga := &stored[i]
+		if p, ok := previous[ga.TargetFrameworkID]; !ok || ga.AnalysisDate.After(p.AnalysisDate) {
+			previous[ga.TargetFrameworkID] = ga
+		}
+	}
+	impls, err := s.implementations.List(ctx, org, "")
+	if err != nil {
+		return nil, fmt.Errorf("listing control implementations: %w", err)
+	}
+
+	var diffs []GapDiff
+	for _, fw := range frameworks {
+		var tracked []models.ControlImplementation
+		for _, ci := range impls {
+			if ci.FrameworkID == fw {
+				tracked = append(tracked, ci)
+			}
+		}
+		input := &AnalysisInput{
+			TargetFramework:     fw,
+			ImplementedControls: TrackedImplementedControls(tracked),
+			Tags:                AnalysisTags(ctx, s.tags, org, fw),
+			Dispositions:        AnalysisDispositions(ctx, s.dispositions, org, fw),
+		}
+		output, err := s.analyzer.RunAnalysis(ctx, input)
+		if err != nil {
+			return diffs, fmt.Errorf("analyzing %s: %w", fw, err)
+		}
+		now := s.now()
+		s.coverage.Record(org, output.Framework, now, output.CoveragePercentage)
+		ga := NewGapAnalysis(org, input, output, now)
+		if err := s.analyses.Create(ctx, ga); err != nil {
+			return diffs, fmt.Errorf("saving %s gap analysis: %w", fw, err)
+		}
+
+		prev, ok := previous[output.Framework]
+		if !ok {
+			continue
+		}
+		diff := DiffGapAnalyses(prev, ga)
+		diffs = append(diffs, *diff)
+		if !diff.Changed() {
+			continue
+		}
+		if err := s.send(ctx, diff); err != nil {
+			log.Warn().Err(err).Str("org_id", org).Str("framework", fw).Msg("gap analysis changes not sent")
+		}
+	}
+	return diffs, nil
+}
+
+// send posts a diff to the webhook, or logs it.
+func (s *ScheduledAnalyses) send(ctx context.Context, d *GapDiff) error {
+	if s.webhook == nil {
+		log.Info().Str("org_id", d.OrganizationID).Str("framework", d.Framework).Int("opened", len(d.Opened)).
+			Int("closed", len(d.Closed)).Float64("coverage", d.Coverage).Msg("gap analysis changed")
+		return nil
+	}
+	body, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("encoding gap diff: %w", err)
+	}
+	if err := s.webhook.Send(ctx, body); err != nil {
+		return fmt.Errorf("sending gap diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package controls
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ScoringModel configures how gap priority and remediation effort are derived
+// from control attributes. Organizations plan work differently, so the weights,
+// thresholds, and T-shirt sizes are data rather than code.
+type ScoringModel struct {
+	Effort   EffortModel   `json:"effort"`
+	Priority PriorityModel `json:"priority"`
+	// Frameworks holds per-framework overrides keyed by framework ID.
+	Frameworks map[string]ScoringOverride `json:"frameworks,omitempty"`
+}
+
+// ScoringOverride adjusts the scoring model for one framework. Fields that
+// are set replace the base values, so a weight or threshold may be
+// overridden to zero; layer weights and tag priorities are merged into the
+// base ones.
+type ScoringOverride struct {
+	Effort   EffortOverride   `json:"effort"`
+	Priority PriorityOverride `json:"priority"`
+}
+
+// EffortOverride holds the effort fields a framework override may set.
+type EffortOverride struct {
+	ActivityWeight *float64           `json:"activity_weight,omitempty"`
+	EvidenceWeight *float64           `json:"evidence_weight,omitempty"`
+	LayerWeights   map[string]float64 `json:"layer_weights,omitempty"`
+	Sizes          []TShirtSize       `json:"sizes,omitempty"`
+}
+
+// PriorityOverride holds the priority fields a framework override may set.
+type PriorityOverride struct {
+	Rego                    string            `json:"rego,omitempty"`
+	TagPriorities           map[string]string `json:"tag_priorities,omitempty"`
+	Rules                   []PriorityRule    `json:"rules,omitempty"`
+	Risk                    *RiskModel        `json:"risk,omitempty"`
+	HighLayers              []string          `json:"high_layers,omitempty"`
+	HighEvidenceThreshold   *int              `json:"high_evidence_threshold,omitempty"`
+	MediumActivityThreshold *int              `json:"medium_activity_threshold,omitempty"`
+}
+
+// EffortModel scores a control's remediation effort and buckets the score into sizes.
+type EffortModel struct {
+	ActivityWeight float64 `json:"activity_weight"`
+	EvidenceWeight float64 `json:"evidence_weight"`
+	// LayerWeights adds a fixed score for each applicable layer a control touches.
+	LayerWeights map[string]float64 `json:"layer_weights,omitempty"`
+	// Sizes must be ordered by ascending MaxScore. The last size is unbounded.
+	Sizes []TShirtSize `json:"sizes,omitempty"`
+}
+
+// TShirtSize maps an effort score range to an organization-specific label.
+type TShirtSize struct {
+	Label    string  `json:"label"`
+	MaxScore float64 `json:"max_score"`
+}
+
+// PriorityModel determines gap priority from control characteristics. A
+// gap's priority comes from the first of these that decides it: the Rego
+// policy, tag priorities, rules, the risk model, and last the layer,
+// evidence and activity heuristics.
+type PriorityModel struct {
+	// Rego is a Rego module in package agentguard.gaps whose priority and
+	// effort rules, evaluated with a PolicyInput, decide ahead of the rest
+	// of the model when defined.
+	Rego string `json:"rego,omitempty"`
+	// TagPriorities sets the priority of gaps in controls an organization
+	// tagged, keyed by tag. A control with several such tags takes the most
+	// urgent.
+	TagPriorities map[string]string `json:"tag_priorities,omitempty"`
+	// Rules are checked in order; see PriorityRule.
+	Rules []PriorityRule `json:"rules,omitempty"`
+	// Risk derives priority from organizational risk weights.
+	Risk *RiskModel `json:"risk,omitempty"`
+	// HighLayers lists applicable layers that always produce a high priority gap.
+	HighLayers []string `json:"high_layers,omitempty"`
+	// HighEvidenceThreshold marks a gap high when evidence types exceed it.
+	HighEvidenceThreshold int `json:"high_evidence_threshold"`
+	// MediumActivityThreshold marks a gap medium when activities exceed it.
+	MediumActivityThreshold int `json:"medium_activity_threshold"`
+}
+
+// DefaultScoringModel returns the built-in scoring model.
+func DefaultScoringModel() *ScoringModel {
+	return &ScoringModel{
+		Effort: EffortModel{
+			ActivityWeight: 1,
+			EvidenceWeight: 1,
+			Sizes: []TShirtSize{
+				{Label: "small", MaxScore: 3},
+				{Label: "medium", MaxScore: 6},
+				{Label: "large"},
+			},
+		},
+		Priority: PriorityModel{
+			HighLayers:              []string{"governance", "risk_management"},
+			HighEvidenceThreshold:   3,
+			MediumActivityThreshold: 2,
+		},
+	}
+}
+
+// LoadScoringModel reads a scoring model from a JSON or YAML file, or a
+// Rego priority policy from a .rego file. Fields omitted from the file keep
+// their default values.
+func LoadScoringModel(path string) (*ScoringModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	model := DefaultScoringModel()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".rego":
+		model.Priority.Rego = string(data)
+	case ".yaml", ".yml":
+		// The model's field names are its JSON tags, so YAML is read as a
+		// generic document and decoded as JSON.
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing scoring model: %w", err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("parsing scoring model: %w", err)
+		}
+		fallthrough
+	default:
+		if err := json.Unmarshal(data, model); err != nil {
+			return nil, fmt.Errorf("parsing scoring model: %w", err)
+		}
+	}
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// Validate checks the model for negative weights and malformed size tables.
+func (m *ScoringModel) Validate() error {
+	if err := m.validateBase(); err != nil {
+		return err
+	}
+	for id := range m.Frameworks {
+		if err := m.ForFramework(id).validateBase(); err != nil {
+			return fmt.Errorf("framework %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (m *ScoringModel) validateBase() error {
+	if m.Effort.ActivityWeight < 0 || m.Effort.EvidenceWeight < 0 {
+		return fmt.Errorf("effort weights must not be negative")
+	}
+	for layer, w := range m.Effort.LayerWeights {
+		if w < 0 {
+			return fmt.Errorf("layer weight for %q must not be negative", layer)
+		}
+	}
+	if len(m.Effort.Sizes) == 0 {
+		return fmt.Errorf("at least one effort size is required")
+	}
+	for i, size := range m.Effort.Sizes {
+		if strings.TrimSpace(size.Label) == "" {
+			return fmt.Errorf("effort size %d has an empty label", i)
+		}
+		if i == len(m.Effort.Sizes)-1 {
+			break
+		}
+		if i > 0 && size.MaxScore <= m.Effort.Sizes[i-1].MaxScore {
+			return fmt.Errorf("effort sizes must have ascending max_score (%q)", size.Label)
+		}
+	}
+	if m.Priority.HighEvidenceThreshold < 0 || m.Priority.MediumActivityThreshold < 0 {
+		return fmt.Errorf("priority thresholds must not be negative")
+	}
+	for tag, priority := range m.Priority.TagPriorities {
+		if _, ok := priorityRank[priority]; !ok {
+			return fmt.Errorf("tag %q has unknown priority %q", tag, priority)
+		}
+	}
+	for i := range m.Priority.Rules {
+		if err := m.Priority.Rules[i].validate(i, m.Effort.Sizes); err != nil {
+			return err
+		}
+	}
+	if err := m.Priority.Risk.validate(); err != nil {
+		return err
+	}
+	if m.Priority.Rego != "" {
+		if _, err := prepareRego(m.Priority.Rego); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForFramework returns the effective model for a framework with any override applied.
+func (m *ScoringModel) ForFramework(frameworkID string) *ScoringModel {
+	effective := &ScoringModel{Effort: m.Effort, Priority: m.Priority}
+	override, ok := m.Frameworks[frameworkID]
+	if !ok {
+		return effective
+	}
+
+	if override.Effort.ActivityWeight != nil {
+		effective.Effort.ActivityWeight = *override.Effort.ActivityWeight
+	}
+	if override.Effort.EvidenceWeight != nil {
+		effective.Effort.EvidenceWeight = *override.Effort.EvidenceWeight
+	}
+	if len(override.Effort.LayerWeights) > 0 {
+		merged := make(map[string]float64, len(m.Effort.LayerWeights)+len(override.Effort.LayerWeights))
+		for k, v := range m.Effort.LayerWeights {
+			merged[k] = v
+		}
+		for k, v := range override.Effort.LayerWeights {
+			merged[k] = v
+		}
+		effective.Effort.LayerWeights = merged
+	}
+	if len(override.Effort.Sizes) > 0 {
+		effective.Effort.Sizes = override.Effort.Sizes
+	}
+	if len(override.Priority.TagPriorities) > 0 {
+		merged := make(map[string]string, len(m.Priority.TagPriorities)+len(override.Priority.TagPriorities))
+		for k, v := range m.Priority.TagPriorities {
+			merged[k] = v
+		}
+		for k, v := range override.Priority.TagPriorities {
+			merged[k] = v
+		}
+		effective.Priority.TagPriorities = merged
+	}
+	if override.Priority.Rego != "" {
+		effective.Priority.Rego = override.Priority.Rego
+	}
+	if len(override.Priority.Rules) > 0 {
+		effective.Priority.Rules = override.Priority.Rules
+	}
+	if override.Priority.Risk != nil {
+		effective.Priority.Risk = override.Priority.Risk
+	}
+	if len(override.Priority.HighLayers) > 0 {
+		effective.Priority.HighLayers = override.Priority.HighLayers
+	}
+	if override.Priority.HighEvidenceThreshold != nil {
+		effective.Priority.HighEvidenceThreshold = *override.Priority.HighEvidenceThreshold
+	}
+	if override.Priority.MediumActivityThreshold != nil {
+		effective.Priority.MediumActivityThreshold = *override.Priority.MediumActivityThreshold
+	}
+
+	return effective
+}
+
+// EffortScore returns the weighted effort score for a control.
+func (m *ScoringModel) EffortScore(ctrl models.Control) float64 {
+	score := float64(len(ctrl.Activities))*m.Effort.ActivityWeight +
+		float64(len(ctrl.EvidenceTypes))*m.Effort.EvidenceWeight
+	for _, layer := range ctrl.ApplicableLayers {
+		score += m.Effort.LayerWeights[layer]
+	}
+	return score
+}
+
+// EstimateEffort returns the T-shirt size label for a control: the Rego
+// policy's or the first matching rule's, or the size its score falls in.
+func (m *ScoringModel) EstimateEffort(ctx context.Context, ctrl models.Control) string {
+	if d := m.evalRego(ctx, ctrl); d.Effort != "" {
+		return d.Effort
+	}
+	if len(m.Priority.Rules) > 0 {
+		risk := m.Priority.Risk.Score(ctrl)
+		for _, r := range m.Priority.Rules {
+			if r.Effort != "" && r.Match.matches(ctrl, risk) {
+				return r.Effort
+			}
+		}
+	}
+	score := m.EffortScore(ctrl)
+	for i, size := range m.Effort.Sizes {
+		if i == len(m.Effort.Sizes)-1 || score <= size.MaxScore {
+			return size.Label
+		}
+	}
+	return ""
+}
+
+// DeterminePriority returns the gap priority for a control.
+func (m *ScoringModel) DeterminePriority(ctx context.Context, ctrl models.Control) string {
+	if d := m.evalRego(ctx, ctrl); d.Priority != "" {
+		return d.Priority
+	}
+	tagged := ""
+	for _, tag := range ctrl.Tags {
+		if p, ok := m.Priority.TagPriorities[tag]; ok && (tagged == "" || priorityRank[p] < priorityRank[tagged]) {
+			tagged = p
+		}
+	}
+	if tagged != "" {
+		return tagged
+	}
+	risk := m.Priority.Risk.Score(ctrl)
+	for _, r := range m.Priority.Rules {
+		if r.Priority != "" && r.Match.matches(ctrl, risk) {
+			return r.Priority
+		}
+	}
+	if m.Priority.Risk != nil {
+		if p := m.Priority.Risk.priority(risk); p != "" {
+			return p
+		}
+	}
+	for _, layer := range ctrl.ApplicableLayers {
+		for _, high := range m.Priority.HighLayers {
+			if layer == high {
+				return "high"
+			}
+		}
+	}
+	if len(ctrl.EvidenceTypes) > m.Priority.HighEvidenceThreshold {
+		return "high"
+	}
+	if len(ctrl.Activities) > m.Priority.MediumActivityThreshold {
+		return "medium"
+	}
+	return "low"
+}
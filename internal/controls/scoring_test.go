@@ -0,0 +1,255 @@
+package controls_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestScoringModel(t *testing.T) {
+	ctrl := models.Control{
+		ControlID:        "MAP-2.3",
+		Family:           "MAP",
+		Activities:       []string{"a", "b", "c"},
+		EvidenceTypes:    []string{"e1", "e2"},
+		ApplicableLayers: []string{"system"},
+	}
+
+	tests := []struct {
+		name         string
+		model        *controls.ScoringModel
+		framework    string
+		wantEffort   string
+		wantPriority string
+	}{
+		{
+			name:         "default model matches built-in heuristics",
+			model:        controls.DefaultScoringModel(),
+			wantEffort:   "medium",
+			wantPriority: "medium",
+		},
+		{
+			name: "layer weights and custom sizes",
+			model: func() *controls.ScoringModel {
+				m := controls.DefaultScoringModel()
+				m.Effort.LayerWeights = map[string]float64{"system": 10}
+				m.Effort.Sizes = []controls.TShirtSize{{Label: "S", MaxScore: 5}, {Label: "M", MaxScore: 10}, {Label: "XL"}}
+				return m
+			}(),
+			wantEffort:   "XL",
+			wantPriority: "medium",
+		},
+		{
+			name: "framework override applies only to that framework",
+			model: func() *controls.ScoringModel {
+				m := controls.DefaultScoringModel()
+				m.Frameworks = map[string]controls.ScoringOverride{
+					"nist-800-53": {Priority: controls.PriorityOverride{HighLayers: []string{"system"}}},
+				}
+				return m
+			}(),
+			framework:    "nist-800-53",
+			wantEffort:   "medium",
+			wantPriority: "high",
+		},
+		{
+			name: "framework override sets a weight and threshold to zero",
+			model: func() *controls.ScoringModel {
+				weight, threshold := 0.0, 0
+				m := controls.DefaultScoringModel()
+				m.Frameworks = map[string]controls.ScoringOverride{
+					"nist-800-53": {
+						Effort:   controls.EffortOverride{ActivityWeight: &weight},
+						Priority: controls.PriorityOverride{HighEvidenceThreshold: &threshold},
+					},
+				}
+				return m
+			}(),
+			framework:    "nist-800-53",
+			wantEffort:   "small",
+			wantPriority: "high",
+		},
+		{
+			name: "first matching rule sets priority and effort",
+			model: func() *controls.ScoringModel {
+				m := controls.DefaultScoringModel()
+				m.Priority.Rules = []controls.PriorityRule{
+					{Name: "govern", Match: controls.RuleMatch{Families: []string{"GOVERN"}}, Priority: "low"},
+					{Name: "map", Match: controls.RuleMatch{ControlIDs: []string{"map-2.*"}, MinActivities: 3}, Effort: "large"},
+					{Name: "systems", Match: controls.RuleMatch{Layers: []string{"system"}}, Priority: "critical"},
+				}
+				return m
+			}(),
+			wantEffort:   "large",
+			wantPriority: "critical",
+		},
+		{
+			name: "risk weights map to priority thresholds",
+			model: func() *controls.ScoringModel {
+				m := controls.DefaultScoringModel()
+				m.Priority.Risk = &controls.RiskModel{
+					LayerWeights:  map[string]float64{"system": 4},
+					FamilyWeights: map[string]float64{"map": 3},
+					Thresholds:    map[string]float64{"critical": 10, "high": 6},
+				}
+				return m
+			}(),
+			wantEffort:   "medium",
+			wantPriority: "high",
+		},
+		{
+			name: "rego policy decides first",
+			model: func() *controls.ScoringModel {
+				m := controls.DefaultScoringModel()
+				m.Priority.Rules = []controls.PriorityRule{{Match: controls.RuleMatch{}, Priority: "low"}}
+				m.Priority.Rego = `package agentguard.gaps
+
+priority = "critical" { input.family == "MAP"; input.activities > 2 }
+effort = "small" { startswith(input.control_id, "MAP-") }
+`
+				return m
+			}(),
+			wantEffort:   "small",
+			wantPriority: "critical",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.model.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+			m := tt.model.ForFramework(tt.framework)
+			if got := m.EstimateEffort(context.Background(), ctrl); got != tt.wantEffort {
+				t.Errorf("effort = %q, want %q", got, tt.wantEffort)
+			}
+			if got := m.DeterminePriority(context.Background(), ctrl); got != tt.wantPriority {
+				t.Errorf("priority = %q, want %q", got, tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestScoringModelValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(m *controls.ScoringModel)
+	}{
+		{name: "negative weight", mutate: func(m *controls.ScoringModel) { m.Effort.ActivityWeight = -1 }},
+		{name: "no sizes", mutate: func(m *controls.ScoringModel) { m.Effort.Sizes = nil }},
+		{name: "descending sizes", mutate: func(m *controls.ScoringModel) {
+			m.Effort.Sizes = []controls.TShirtSize{{Label: "a", MaxScore: 5}, {Label: "b", MaxScore: 2}, {Label: "c"}}
+		}},
+		{name: "empty label", mutate: func(m *controls.ScoringModel) { m.Effort.Sizes[0].Label = " " }},
+		{name: "rule without outcome", mutate: func(m *controls.ScoringModel) {
+			m.Priority.Rules = []controls.PriorityRule{{Match: controls.RuleMatch{Layers: []string{"data"}}}}
+		}},
+		{name: "rule with unknown effort", mutate: func(m *controls.ScoringModel) {
+			m.Priority.Rules = []controls.PriorityRule{{Effort: "huge"}}
+		}},
+		{name: "risk without thresholds", mutate: func(m *controls.ScoringModel) {
+			m.Priority.Risk = &controls.RiskModel{LayerWeights: map[string]float64{"data": 1}}
+		}},
+		{name: "rego in another package", mutate: func(m *controls.ScoringModel) {
+			m.Priority.Rego = "package other\n\npriority = \"high\""
+		}},
+		{name: "rego calling the network", mutate: func(m *controls.ScoringModel) {
+			m.Priority.Rego = "package agentguard.gaps\n\npriority = \"high\" { http.send({\"method\": \"get\", \"url\": \"http://169.254.169.254/\"}) }"
+		}},
+		{name: "rego resolving hosts", mutate: func(m *controls.ScoringModel) {
+			m.Priority.Rego = "package agentguard.gaps\n\npriority = \"high\" { net.lookup_ip_addr(\"internal.example\") }"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := controls.DefaultScoringModel()
+			tt.mutate(m)
+			if err := m.Validate(); err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestScoringModelRegoDeadline(t *testing.T) {
+	m := controls.DefaultScoringModel()
+	m.Priority.Rego = `package agentguard.gaps
+
+priority = "critical" { count([1 | numbers.range(1, 5000)[_]; numbers.range(1, 5000)[_]]) > 0 }
+`
+	if err := m.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	ctrl := models.Control{ControlID: "MAP-1.1", ApplicableLayers: []string{"governance"}}
+	want := controls.DefaultScoringModel().DeterminePriority(context.Background(), ctrl)
+
+	start := time.Now()
+	if got := m.DeterminePriority(context.Background(), ctrl); got != want {
+		t.Errorf("priority = %q, want the declarative model's %q once the policy runs out of time", got, want)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("policy ran for %s, want it stopped at its deadline", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := m.DeterminePriority(ctx, ctrl); got != want {
+		t.Errorf("priority with a canceled context = %q, want %q", got, want)
+	}
+}
+
+func TestLoadScoringModel(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "priority.yaml")
+	if err := os.WriteFile(yamlPath, []byte(`
+priority:
+  rules:
+    - name: customer-data
+      match: {layers: [data]}
+      priority: critical
+  risk:
+    tag_weights: {pii: 5}
+    thresholds: {high: 5}
+frameworks:
+  nist-800-53:
+    effort: {activity_weight: 0}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := controls.LoadScoringModel(yamlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Priority.Rules) != 1 || m.Priority.HighEvidenceThreshold != 3 || len(m.Effort.Sizes) != 3 {
+		t.Errorf("model = %+v, want the rule over the defaults", m.Priority)
+	}
+	if got := m.DeterminePriority(context.Background(), models.Control{Tags: []string{"pii"}}); got != "high" {
+		t.Errorf("risk priority = %q, want high", got)
+	}
+	if base, nist := m.Effort.ActivityWeight, m.ForFramework("nist-800-53").Effort.ActivityWeight; base != 1 || nist != 0 {
+		t.Errorf("activity weight = %v, nist-800-53 override = %v, want 1 and 0", base, nist)
+	}
+
+	regoPath := filepath.Join(dir, "priority.rego")
+	if err := os.WriteFile(regoPath, []byte("package agentguard.gaps\n\npriority = \"low\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if m, err = controls.LoadScoringModel(regoPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.DeterminePriority(context.Background(), models.Control{ApplicableLayers: []string{"governance"}}); got != "low" {
+		t.Errorf("rego priority = %q, want low", got)
+	}
+	if err := os.WriteFile(regoPath, []byte("package agentguard.gaps\n\npriority = {"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := controls.LoadScoringModel(regoPath); err == nil {
+		t.Error("malformed rego accepted")
+	}
+}
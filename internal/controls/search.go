@@ -0,0 +1,106 @@
+package controls
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// Weights of a query word found in a control's ID or title, and in its
+// description.
+const (
+	titleWeight       = 1.0
+	descriptionWeight = 0.4
+)
+
+// SearchControls searches the loaded frameworks' controls, implementing
+// repository.ControlSearchRepository for deployments without a database.
+// Every word of the query text must appear in the control's ID, title or
+// description; matches rank higher the more words appear in the title.
+// The analyzer holds no tags, so tag filters match nothing; SearchTagged
+// applies an organization's tags.
+func (g *GapAnalyzer) SearchControls(_ context.Context, q *repository.ControlQuery) ([]repository.ControlMatch, int, error) {
+	words := strings.Fields(strings.ToLower(q.Text))
+	var matches []repository.ControlMatch
+	for _, fw := range g.Frameworks() {
+		if len(q.FrameworkIDs) > 0 && !slices.Contains(q.FrameworkIDs, fw.ID) {
+			continue
+		}
+		controls, _ := g.Controls(fw.ID)
+		for _, c := range controls {
+			if !anyOf(c.ApplicableLayers, q.Layers, func(have, want string) bool { return have == want }) ||
+				!anyOf(c.EvidenceTypes, q.EvidenceTypes, func(have, want string) bool {
+					if q.ExactEvidence {
+						return strings.EqualFold(have, want)
+					}
+					return strings.Contains(strings.ToLower(have), strings.ToLower(want))
+				}) ||
+				!anyOf(c.Tags, q.Tags, func(have, want string) bool { return have == want }) {
+				continue
+			}
+			rank, ok := rankControl(c, words)
+			if !ok {
+				continue
+			}
+			matches = append(matches, repository.ControlMatch{Control: c, Rank: rank})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		if matches[i].FrameworkID != matches[j].FrameworkID {
+			return matches[i].FrameworkID < matches[j].FrameworkID
+		}
+		return matches[i].ControlID < matches[j].ControlID
+	})
+
+	total := len(matches)
+	matches = matches[min(q.Offset, total):]
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	return matches, total, nil
+}
+
+// rankControl scores a control against the query words, reporting false
+// when a word is missing from it.
+func rankControl(c models.Control, words []string) (float64, bool) {
+	if len(words) == 0 {
+		return 0, true
+	}
+	title := strings.ToLower(c.ControlID + " " + c.Title)
+	description := strings.ToLower(c.Description)
+	var rank float64
+	for _, w := range words {
+		switch {
+		case strings.Contains(title, w):
+			rank += titleWeight
+		case strings.Contains(description, w):
+			rank += descriptionWeight
+		default:
+			return 0, false
+		}
+	}
+	return rank / float64(len(words)), true
+}
+
+// anyOf reports whether any wanted value matches one the control has, or
+// true when nothing is wanted.
+func anyOf(have, want []string, match func(have, want string) bool) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		for _, h := range have {
+			if match(h, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package controls_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+func TestSearchControls(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	ctx := context.Background()
+
+	matches, total, err := analyzer.SearchControls(ctx, &repository.ControlQuery{
+		Text:         "encryption",
+		FrameworkIDs: []string{"csa-aicm"},
+	})
+	if err != nil {
+		t.Fatalf("SearchControls: %v", err)
+	}
+	if total == 0 || len(matches) != total {
+		t.Fatalf("got %d of %d matches, want all of at least one", len(matches), total)
+	}
+	if matches[0].ControlID != "CEK-03" {
+		t.Errorf("best match = %s, want CEK-03 (encryption in its title)", matches[0].ControlID)
+	}
+	for _, m := range matches {
+		if m.FrameworkID != "csa-aicm" {
+			t.Errorf("%s from %s, want only csa-aicm", m.ControlID, m.FrameworkID)
+		}
+		if m.Rank <= 0 {
+			t.Errorf("%s rank = %v, want positive", m.ControlID, m.Rank)
+		}
+	}
+
+	// Layer and evidence filters narrow the match; evidence matches
+	// substrings case-insensitively.
+	matches, _, err = analyzer.SearchControls(ctx, &repository.ControlQuery{
+		FrameworkIDs:  []string{"csa-aicm"},
+		Layers:        []string{"infrastructure"},
+		EvidenceTypes: []string{"tls"},
+	})
+	if err != nil {
+		t.Fatalf("SearchControls: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ControlID != "CEK-03" {
+		t.Errorf("filtered matches = %v, want CEK-03 only", controlIDs(matches))
+	}
+
+	// Exact evidence filters match whole evidence types only.
+	exact := &repository.ControlQuery{FrameworkIDs: []string{"csa-aicm"}, EvidenceTypes: []string{"tls"}, ExactEvidence: true}
+	if matches, _, _ := analyzer.SearchControls(ctx, exact); len(matches) != 0 {
+		t.Errorf("exact evidence tls = %v, want none", controlIDs(matches))
+	}
+	exact.EvidenceTypes = []string{"tls SETTINGS"}
+	if matches, _, _ := analyzer.SearchControls(ctx, exact); !slices.Equal(controlIDs(matches), []string{"csa-aicm/CEK-03"}) {
+		t.Errorf("exact evidence tls settings = %v, want CEK-03", controlIDs(matches))
+	}
+
+	// Every word must appear.
+	matches, total, err = analyzer.SearchControls(ctx, &repository.ControlQuery{Text: "encryption zzzunknown"})
+	if err != nil {
+		t.Fatalf("SearchControls: %v", err)
+	}
+	if total != 0 || len(matches) != 0 {
+		t.Errorf("got %v, want no matches", controlIDs(matches))
+	}
+
+	// Pages cover the matches in order.
+	all, total, err := analyzer.SearchControls(ctx, &repository.ControlQuery{Text: "access"})
+	if err != nil {
+		t.Fatalf("SearchControls: %v", err)
+	}
+	if total < 3 {
+		t.Fatalf("got %d matches for access, want at least 3", total)
+	}
+	page, pageTotal, err := analyzer.SearchControls(ctx, &repository.ControlQuery{Text: "access", Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchControls: %v", err)
+	}
+	if pageTotal != total || !slices.Equal(controlIDs(page), controlIDs(all[1:3])) {
+		t.Errorf("page = %v of %d, want %v of %d", controlIDs(page), pageTotal, controlIDs(all[1:3]), total)
+	}
+	if page, _, _ := analyzer.SearchControls(ctx, &repository.ControlQuery{Text: "access", Offset: total + 5}); len(page) != 0 {
+		t.Errorf("page past the end = %v, want empty", controlIDs(page))
+	}
+}
+
+func controlIDs(matches []repository.ControlMatch) []string {
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.FrameworkID + "/" + m.ControlID
+	}
+	return ids
+}
@@ -0,0 +1,199 @@
+package controls
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SeedOptions control how the embedded catalogs are seeded into a
+// repository.
+type SeedOptions struct {
+	// DryRun reports what would change without writing.
+	DryRun bool
+	// Frameworks limits seeding to these framework IDs; empty seeds all.
+	Frameworks []string
+}
+
+// SeedCounts counts the records a seed created, updated or left alone.
+type SeedCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// SeedConflict is a stored record the seed left alone because it differs
+// from the embedded one in a way the seed does not overwrite.
+type SeedConflict struct {
+	// Kind is framework or crosswalk.
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// SeedReport is the outcome of a seed.
+type SeedReport struct {
+	DryRun     bool           `json:"dry_run"`
+	Frameworks SeedCounts     `json:"frameworks"`
+	Controls   SeedCounts     `json:"controls"`
+	Crosswalks SeedCounts     `json:"crosswalks"`
+	Conflicts  []SeedConflict `json:"conflicts"`
+}
+
+// Seed upserts the analyzer's frameworks, their controls and the built-in
+// crosswalks between them into repo, so that repository-backed handlers
+// serve the embedded catalogs. Seeding is idempotent:
+//   - A framework stored at another version is a conflict, and it and its
+//     controls are left alone; use a catalog import to change versions.
+//   - Controls are matched by control ID and updated in place when their
+//     text differs. Stored controls missing from the catalog are kept.
+//   - A stored crosswalk for the same control pair with another mapping is
+//     a conflict and the curated mapping is kept.
+func (g *GapAnalyzer) Seed(ctx context.Context, repo repository.ControlRepository, opts SeedOptions) (*SeedReport, error) {
+	report := &SeedReport{DryRun: opts.DryRun, Conflicts: []SeedConflict{}}
+	seeded := make(map[string]bool)
+	for _, id := range opts.Frameworks {
+		if _, ok := g.Framework(id); !ok {
+			return nil, fmt.Errorf("unknown framework: %s", id)
+		}
+	}
+	for _, fw := range g.Frameworks() {
+		if len(opts.Frameworks) > 0 && !slices.Contains(opts.Frameworks, fw.ID) {
+			continue
+		}
+		ok, err := g.seedFramework(ctx, repo, fw, opts.DryRun, report)
+		if err != nil {
+			return nil, fmt.Errorf("seeding framework %s: %w", fw.ID, err)
+		}
+		seeded[fw.ID] = ok
+	}
+
+	direct, err := g.DirectCrosswalks()
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[[2]string]map[[2]string]models.Crosswalk)
+	for _, xw := range direct {
+		if !seeded[xw.SourceFrameworkID] || !seeded[xw.TargetFrameworkID] {
+			continue
+		}
+		pair := [2]string{xw.SourceFrameworkID, xw.TargetFrameworkID}
+		existing, ok := stored[pair]
+		if !ok {
+			list, err := repo.GetCrosswalk(ctx, pair[0], pair[1])
+			if err != nil {
+				return nil, fmt.Errorf("seeding crosswalks %s -> %s: %w", pair[0], pair[1], err)
+			}
+			existing = make(map[[2]string]models.Crosswalk, len(list))
+			for _, s := range list {
+				existing[crosswalkKey(s)] = s
+			}
+			stored[pair] = existing
+		}
+		if s, ok := existing[crosswalkKey(xw)]; ok {
+			if s.MappingType != xw.MappingType {
+				report.Conflicts = append(report.Conflicts, SeedConflict{
+					Kind:   "crosswalk",
+					ID:     fmt.Sprintf("%s:%s -> %s:%s", xw.SourceFrameworkID, xw.SourceControlID, xw.TargetFrameworkID, xw.TargetControlID),
+					Reason: fmt.Sprintf("stored mapping is %s, built-in is %s; keeping the stored one", s.MappingType, xw.MappingType),
+				})
+				continue
+			}
+			report.Crosswalks.Unchanged++
+			continue
+		}
+		report.Crosswalks.Created++
+		existing[crosswalkKey(xw)] = xw
+		if opts.DryRun {
+			continue
+		}
+		xw.ID = uuid.NewString()
+		xw.ReviewState = models.ReviewApproved
+		if err := repo.CreateCrosswalk(ctx, &xw); err != nil {
+			return nil, fmt.Errorf("seeding crosswalk %s -> %s: %w", xw.SourceControlID, xw.TargetControlID, err)
+		}
+	}
+	return report, nil
+}
+
+// seedFramework seeds one framework and its controls, reporting whether
+// they were seeded or left alone for a version conflict.
+func (g *GapAnalyzer) seedFramework(ctx context.Context, repo repository.ControlRepository, fw *models.Framework, dryRun bool, report *SeedReport) (bool, error) {
+	embedded := *fw
+	embedded.License, embedded.TextUnavailable = "", false
+	stored, err := repo.GetFramework(ctx, fw.ID)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case stored == nil:
+		report.Frameworks.Created++
+		if !dryRun {
+			if err := repo.CreateFramework(ctx, &embedded); err != nil {
+				return false, err
+			}
+		}
+	case stored.Version != embedded.Version:
+		report.Conflicts = append(report.Conflicts, SeedConflict{
+			Kind:   "framework",
+			ID:     fw.ID,
+			Reason: fmt.Sprintf("stored version is %s, embedded version is %s; import the catalog to change versions", stored.Version, embedded.Version),
+		})
+		return false, nil
+	case stored.Name != embedded.Name || stored.Publisher != embedded.Publisher ||
+		stored.Description != embedded.Description || stored.URL != embedded.URL:
+		report.Frameworks.Updated++
+		if !dryRun {
+			if err := repo.UpdateFramework(ctx, &embedded); err != nil {
+				return false, err
+			}
+		}
+	default:
+		report.Frameworks.Unchanged++
+	}
+
+	var existing map[string]models.Control
+	if stored != nil {
+		list, err := repo.ListControls(ctx, fw.ID)
+		if err != nil {
+			return false, err
+		}
+		existing = make(map[string]models.Control, len(list))
+		for _, c := range list {
+			existing[strings.ToLower(c.ControlID)] = c
+		}
+	}
+	list, _ := g.Controls(fw.ID)
+	for _, c := range list {
+		c.FrameworkID = fw.ID
+		s, ok := existing[strings.ToLower(c.ControlID)]
+		switch {
+		case !ok:
+			report.Controls.Created++
+			if dryRun {
+				continue
+			}
+			c.ID = uuid.NewString()
+			if err := repo.CreateControl(ctx, &c); err != nil {
+				return false, fmt.Errorf("control %s: %w", c.ControlID, err)
+			}
+		case len(changedFields(s, c)) > 0:
+			report.Controls.Updated++
+			if dryRun {
+				continue
+			}
+			c.ID, c.ControlID = s.ID, s.ControlID
+			if err := repo.UpdateControl(ctx, &c); err != nil {
+				return false, fmt.Errorf("control %s: %w", c.ControlID, err)
+			}
+		default:
+			report.Controls.Unchanged++
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,73 @@
+package controls_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memory.NewControlRepository()
+	opts := controls.SeedOptions{Frameworks: []string{"nist-ai-rmf", "nist-800-53"}}
+
+	dry, err := analyzer.Seed(ctx, repo, controls.SeedOptions{DryRun: true, Frameworks: opts.Frameworks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := repo.ListFrameworks(ctx); len(stored) != 0 {
+		t.Fatalf("dry run stored %d frameworks", len(stored))
+	}
+	first, err := analyzer.Seed(ctx, repo, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Frameworks.Created != 2 || first.Controls.Created == 0 || first.Crosswalks.Created == 0 {
+		t.Fatalf("first seed = %+v", first)
+	}
+	if dry.Frameworks != first.Frameworks || dry.Controls != first.Controls || dry.Crosswalks != first.Crosswalks {
+		t.Errorf("dry run = %+v, seed = %+v", dry, first)
+	}
+	again, err := analyzer.Seed(ctx, repo, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Frameworks.Unchanged != 2 || again.Controls.Unchanged != first.Controls.Created ||
+		again.Crosswalks.Unchanged != first.Crosswalks.Created || again.Controls.Created+again.Crosswalks.Created != 0 {
+		t.Errorf("second seed = %+v, want everything unchanged", again)
+	}
+
+	list, _ := repo.ListControls(ctx, "nist-ai-rmf")
+	edited := list[0]
+	edited.Title = "Edited"
+	if err := repo.UpdateControl(ctx, &edited); err != nil {
+		t.Fatal(err)
+	}
+	fw, _ := repo.GetFramework(ctx, "nist-800-53")
+	fw.Version = "4"
+	if err := repo.UpdateFramework(ctx, fw); err != nil {
+		t.Fatal(err)
+	}
+	report, err := analyzer.Seed(ctx, repo, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Controls.Updated != 1 {
+		t.Errorf("updated controls = %d, want the edited one", report.Controls.Updated)
+	}
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Kind != "framework" || report.Conflicts[0].ID != "nist-800-53" {
+		t.Errorf("conflicts = %+v, want the version conflict", report.Conflicts)
+	}
+	if report.Crosswalks.Unchanged != 0 {
+		t.Errorf("crosswalks into a conflicting framework were seeded: %+v", report.Crosswalks)
+	}
+	if _, err := analyzer.Seed(ctx, repo, controls.SeedOptions{Frameworks: []string{"unknown"}}); err == nil {
+		t.Error("unknown framework accepted")
+	}
+}
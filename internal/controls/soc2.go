@@ -0,0 +1,414 @@
+package controls
+
+import "github.com/agentguard/agentguard/internal/models"
+
+// getSOC2Controls returns the AICPA 2017 Trust Services Criteria (revised
+// points of focus, 2022) used in SOC 2 examinations: the Common Criteria
+// CC1–CC9, which apply to every report, plus the Availability and
+// Confidentiality categories. Processing Integrity and Privacy are not
+// included.
+func getSOC2Controls() []models.Control {
+	return []models.Control{
+		// CC1: Control Environment
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC1.1",
+			Title:            "Commitment to Integrity and Ethical Values",
+			Description:      "The entity demonstrates a commitment to integrity and ethical values.",
+			Objectives:       []string{"Set the tone at the top", "Establish standards of conduct", "Address deviations in a timely manner"},
+			Activities:       []string{"Publish a code of conduct", "Collect annual acknowledgements", "Investigate conduct violations"},
+			EvidenceTypes:    []string{"Code of conduct", "Signed acknowledgements", "Disciplinary records"},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC1.2",
+			Title:            "Board Independence and Oversight",
+			Description:      "The board of directors demonstrates independence from management and exercises oversight of the development and performance of internal control.",
+			Objectives:       []string{"Establish oversight responsibilities", "Maintain board independence"},
+			Activities:       []string{"Hold board or audit committee reviews of internal control", "Document oversight charters"},
+			EvidenceTypes:    []string{"Board charter", "Meeting minutes"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC1.3",
+			Title:            "Structures, Reporting Lines and Authorities",
+			Description:      "Management establishes, with board oversight, structures, reporting lines, and appropriate authorities and responsibilities in the pursuit of objectives.",
+			Objectives:       []string{"Define organizational structure", "Assign authority and responsibility"},
+			Activities:       []string{"Maintain organization charts", "Document roles and responsibilities, including AI system owners"},
+			EvidenceTypes:    []string{"Organization chart", "Role descriptions", "RACI matrix"},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC1.4",
+			Title:            "Commitment to Competence",
+			Description:      "The entity demonstrates a commitment to attract, develop, and retain competent individuals in alignment with objectives.",
+			Objectives:       []string{"Establish competence requirements", "Develop personnel"},
+			Activities:       []string{"Screen new hires", "Run security and AI risk training", "Evaluate competence periodically"},
+			EvidenceTypes:    []string{"Background checks", "Training records", "Performance reviews"},
+			ApplicableLayers: []string{"organization"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC1.5",
+			Title:            "Accountability",
+			Description:      "The entity holds individuals accountable for their internal control responsibilities in the pursuit of objectives.",
+			Objectives:       []string{"Enforce accountability for control responsibilities"},
+			Activities:       []string{"Include control duties in performance measures", "Review control owner performance"},
+			EvidenceTypes:    []string{"Performance objectives", "Control owner assignments"},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+
+		// CC2: Communication and Information
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC2.1",
+			Title:            "Quality Information",
+			Description:      "The entity obtains or generates and uses relevant, quality information to support the functioning of internal control.",
+			Objectives:       []string{"Identify information requirements", "Maintain quality information about systems and data"},
+			Activities:       []string{"Maintain system and AI model inventories", "Capture telemetry from agents and tools"},
+			EvidenceTypes:    []string{"System inventory", "Data flow diagrams", "Telemetry configuration"},
+			ApplicableLayers: []string{"data", "system"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC2.2",
+			Title:            "Internal Communication",
+			Description:      "The entity internally communicates information, including objectives and responsibilities for internal control, necessary to support the functioning of internal control.",
+			Objectives:       []string{"Communicate policies and responsibilities", "Provide channels to report concerns"},
+			Activities:       []string{"Publish security policies internally", "Operate a whistleblower or incident reporting channel"},
+			EvidenceTypes:    []string{"Policy portal", "Reporting channel records"},
+			ApplicableLayers: []string{"organization"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC2.3",
+			Title:            "External Communication",
+			Description:      "The entity communicates with external parties regarding matters affecting the functioning of internal control.",
+			Objectives:       []string{"Communicate commitments to customers", "Receive external reports"},
+			Activities:       []string{"Publish system descriptions and terms", "Operate a vulnerability disclosure channel", "Notify customers of incidents"},
+			EvidenceTypes:    []string{"Customer agreements", "System description", "Disclosure policy"},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+
+		// CC3: Risk Assessment
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC3.1",
+			Title:            "Objectives Specification",
+			Description:      "The entity specifies objectives with sufficient clarity to enable the identification and assessment of risks relating to objectives.",
+			Objectives:       []string{"Define operational, reporting and compliance objectives"},
+			Activities:       []string{"Document service commitments and system requirements", "Define risk tolerances"},
+			EvidenceTypes:    []string{"Service commitments", "Risk appetite statement"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC3.2",
+			Title:            "Risk Identification and Analysis",
+			Description:      "The entity identifies risks to the achievement of its objectives across the entity and analyzes risks as a basis for determining how the risks should be managed.",
+			Objectives:       []string{"Identify risks including AI-specific threats", "Analyze likelihood and impact"},
+			Activities:       []string{"Perform periodic risk assessments", "Threat model AI agents and their tools", "Maintain a risk register"},
+			EvidenceTypes:    []string{"Risk assessment", "Risk register", "Threat models"},
+			ApplicableLayers: []string{"governance", "system"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC3.3",
+			Title:            "Fraud Risk",
+			Description:      "The entity considers the potential for fraud in assessing risks to the achievement of objectives.",
+			Objectives:       []string{"Assess fraud risk", "Consider incentives and opportunities for misuse"},
+			Activities:       []string{"Include fraud and misuse scenarios in risk assessments", "Assess abuse of automated agents"},
+			EvidenceTypes:    []string{"Fraud risk assessment"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC3.4",
+			Title:            "Change Impact on Internal Control",
+			Description:      "The entity identifies and assesses changes that could significantly impact the system of internal control.",
+			Objectives:       []string{"Identify significant changes", "Assess the impact of changes on controls"},
+			Activities:       []string{"Review changes in business, vendors and technology, including new models", "Reassess risk after significant changes"},
+			EvidenceTypes:    []string{"Change impact assessments", "Vendor change reviews"},
+			ApplicableLayers: []string{"governance", "system"},
+		},
+
+		// CC4: Monitoring Activities
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC4.1",
+			Title:            "Ongoing and Separate Evaluations",
+			Description:      "The entity selects, develops, and performs ongoing and/or separate evaluations to ascertain whether the components of internal control are present and functioning.",
+			Objectives:       []string{"Monitor control operation continuously", "Perform independent evaluations"},
+			Activities:       []string{"Run continuous control monitoring", "Perform internal audits", "Commission penetration tests"},
+			EvidenceTypes:    []string{"Monitoring dashboards", "Internal audit reports", "Penetration test reports"},
+			ApplicableLayers: []string{"governance", "operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC4.2",
+			Title:            "Communicating Deficiencies",
+			Description:      "The entity evaluates and communicates internal control deficiencies in a timely manner to those parties responsible for taking corrective action.",
+			Objectives:       []string{"Track deficiencies to remediation"},
+			Activities:       []string{"Log control deficiencies", "Assign owners and due dates", "Report status to management"},
+			EvidenceTypes:    []string{"Deficiency log", "Remediation tracking"},
+			ApplicableLayers: []string{"governance"},
+		},
+
+		// CC5: Control Activities
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC5.1",
+			Title:            "Selection of Control Activities",
+			Description:      "The entity selects and develops control activities that contribute to the mitigation of risks to the achievement of objectives to acceptable levels.",
+			Objectives:       []string{"Map controls to risks", "Segregate incompatible duties"},
+			Activities:       []string{"Maintain a risk and control matrix", "Define segregation of duties"},
+			EvidenceTypes:    []string{"Risk and control matrix", "Segregation of duties matrix"},
+			ApplicableLayers: []string{"governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC5.2",
+			Title:            "Technology General Controls",
+			Description:      "The entity also selects and develops general control activities over technology to support the achievement of objectives.",
+			Objectives:       []string{"Establish technology infrastructure, security and change controls"},
+			Activities:       []string{"Define technology general controls", "Apply them to AI infrastructure and pipelines"},
+			EvidenceTypes:    []string{"IT general control documentation"},
+			ApplicableLayers: []string{"system", "infrastructure"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC5.3",
+			Title:            "Policies and Procedures",
+			Description:      "The entity deploys control activities through policies that establish what is expected and in procedures that put policies into action.",
+			Objectives:       []string{"Document policies", "Implement procedures"},
+			Activities:       []string{"Publish and review policies annually", "Maintain operating procedures, including agent guardrail policies"},
+			EvidenceTypes:    []string{"Policy documents", "Procedure documentation", "Policy review records"},
+			ApplicableLayers: []string{"governance"},
+		},
+
+		// CC6: Logical and Physical Access Controls
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.1",
+			Title:            "Logical Access Security",
+			Description:      "The entity implements logical access security software, infrastructure, and architectures over protected information assets to protect them from security events.",
+			Objectives:       []string{"Restrict access to information assets", "Protect credentials and keys"},
+			Activities:       []string{"Enforce role-based access", "Manage encryption keys", "Restrict agent tool permissions by policy"},
+			EvidenceTypes:    []string{"Access control configurations", "Key management records", "Agent tool policies"},
+			ApplicableLayers: []string{"system", "application"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.2",
+			Title:            "User Registration and Authorization",
+			Description:      "Prior to issuing system credentials and granting system access, the entity registers and authorizes new internal and external users, including service and agent identities.",
+			Objectives:       []string{"Authorize access before provisioning", "Remove access when no longer required"},
+			Activities:       []string{"Approve access requests", "Register agent and workload identities", "Deprovision leavers"},
+			EvidenceTypes:    []string{"Access request tickets", "Agent registry", "Deprovisioning records"},
+			ApplicableLayers: []string{"system", "operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.3",
+			Title:            "Role-Based Access and Least Privilege",
+			Description:      "The entity authorizes, modifies, or removes access to data, software, functions, and other protected information assets based on roles, responsibilities, least privilege and segregation of duties.",
+			Objectives:       []string{"Grant least privilege", "Review access periodically"},
+			Activities:       []string{"Define roles and scopes", "Perform periodic access reviews", "Scope agent tool access to task"},
+			EvidenceTypes:    []string{"Role definitions", "Access review records"},
+			ApplicableLayers: []string{"system", "operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.4",
+			Title:            "Physical Access",
+			Description:      "The entity restricts physical access to facilities and protected information assets to authorized personnel.",
+			Objectives:       []string{"Restrict physical access"},
+			Activities:       []string{"Control facility access", "Review data center provider attestations"},
+			EvidenceTypes:    []string{"Badge access logs", "Provider SOC reports"},
+			ApplicableLayers: []string{"infrastructure"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.5",
+			Title:            "Asset Disposal",
+			Description:      "The entity discontinues logical and physical protections over physical assets only after the ability to read or recover data and software from those assets has been diminished.",
+			Objectives:       []string{"Dispose of data and media securely"},
+			Activities:       []string{"Sanitize media before disposal", "Delete data on retention expiry"},
+			EvidenceTypes:    []string{"Disposal certificates", "Retention job logs"},
+			ApplicableLayers: []string{"data", "infrastructure"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.6",
+			Title:            "Boundary Protection",
+			Description:      "The entity implements logical access security measures to protect against threats from sources outside its system boundaries.",
+			Objectives:       []string{"Protect system boundaries", "Authenticate external connections"},
+			Activities:       []string{"Configure firewalls and gateways", "Require strong authentication for remote access", "Control agent egress"},
+			EvidenceTypes:    []string{"Network diagrams", "Firewall rules", "Egress policies"},
+			ApplicableLayers: []string{"infrastructure", "system"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.7",
+			Title:            "Data Transmission and Movement",
+			Description:      "The entity restricts the transmission, movement, and removal of information to authorized internal and external users and processes, and protects it during transmission.",
+			Objectives:       []string{"Encrypt data in transit", "Prevent unauthorized data movement"},
+			Activities:       []string{"Enforce TLS", "Deploy data loss prevention", "Monitor agent outputs for sensitive data"},
+			EvidenceTypes:    []string{"TLS configuration", "DLP alerts"},
+			ApplicableLayers: []string{"data", "infrastructure"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC6.8",
+			Title:            "Malicious Software Prevention",
+			Description:      "The entity implements controls to prevent or detect and act upon the introduction of unauthorized or malicious software.",
+			Objectives:       []string{"Prevent unauthorized software", "Detect malicious code"},
+			Activities:       []string{"Run endpoint protection", "Restrict software installation", "Verify model and dependency provenance"},
+			EvidenceTypes:    []string{"Endpoint protection reports", "Software allowlists", "Dependency scan results"},
+			ApplicableLayers: []string{"system", "infrastructure"},
+		},
+
+		// CC7: System Operations
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC7.1",
+			Title:            "Vulnerability and Configuration Monitoring",
+			Description:      "To meet its objectives, the entity uses detection and monitoring procedures to identify changes to configurations that introduce new vulnerabilities, and susceptibilities to newly discovered vulnerabilities.",
+			Objectives:       []string{"Maintain secure baselines", "Identify vulnerabilities"},
+			Activities:       []string{"Scan for vulnerabilities", "Monitor configuration drift", "Track model and library advisories"},
+			EvidenceTypes:    []string{"Vulnerability scan reports", "Configuration baselines"},
+			ApplicableLayers: []string{"system", "operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC7.2",
+			Title:            "Anomaly Monitoring",
+			Description:      "The entity monitors system components and their operation for anomalies indicative of malicious acts, natural disasters, and errors, and analyzes anomalies to determine whether they represent security events.",
+			Objectives:       []string{"Detect anomalous activity", "Analyze security events"},
+			Activities:       []string{"Collect and review security logs", "Alert on anomalous agent behavior", "Monitor for prompt injection and data exfiltration"},
+			EvidenceTypes:    []string{"SIEM alerts", "Log review records", "Agent security signals"},
+			ApplicableLayers: []string{"operations", "application"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC7.3",
+			Title:            "Security Event Evaluation",
+			Description:      "The entity evaluates security events to determine whether they could or have resulted in a failure to meet objectives and, if so, takes actions to prevent or address such failures.",
+			Objectives:       []string{"Triage security events", "Declare incidents"},
+			Activities:       []string{"Triage alerts against severity criteria", "Escalate incidents"},
+			EvidenceTypes:    []string{"Triage records", "Incident tickets"},
+			ApplicableLayers: []string{"operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC7.4",
+			Title:            "Incident Response",
+			Description:      "The entity responds to identified security incidents by executing a defined incident response program to understand, contain, remediate, and communicate security incidents.",
+			Objectives:       []string{"Contain and remediate incidents", "Communicate incidents"},
+			Activities:       []string{"Maintain an incident response plan", "Contain compromised agents", "Run post-incident reviews"},
+			EvidenceTypes:    []string{"Incident response plan", "Incident reports", "Post-mortems"},
+			ApplicableLayers: []string{"operations", "governance"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC7.5",
+			Title:            "Incident Recovery",
+			Description:      "The entity identifies, develops, and implements activities to recover from identified security incidents.",
+			Objectives:       []string{"Restore affected systems", "Prevent recurrence"},
+			Activities:       []string{"Restore from known-good state", "Implement lessons learned"},
+			EvidenceTypes:    []string{"Recovery records", "Lessons learned"},
+			ApplicableLayers: []string{"operations"},
+		},
+
+		// CC8: Change Management
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC8.1",
+			Title:            "Change Management",
+			Description:      "The entity authorizes, designs, develops or acquires, configures, documents, tests, approves, and implements changes to infrastructure, data, software, and procedures to meet its objectives.",
+			Objectives:       []string{"Control changes", "Test changes before deployment"},
+			Activities:       []string{"Require reviewed and approved changes", "Test model, prompt and policy changes", "Maintain rollback procedures"},
+			EvidenceTypes:    []string{"Change tickets", "Code review records", "Test results"},
+			ApplicableLayers: []string{"system", "operations"},
+		},
+
+		// CC9: Risk Mitigation
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC9.1",
+			Title:            "Business Disruption Risk Mitigation",
+			Description:      "The entity identifies, selects, and develops risk mitigation activities for risks arising from potential business disruptions.",
+			Objectives:       []string{"Mitigate disruption risk"},
+			Activities:       []string{"Maintain business continuity plans", "Procure cyber insurance"},
+			EvidenceTypes:    []string{"Business continuity plan", "Insurance policy"},
+			ApplicableLayers: []string{"governance", "operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "CC9.2",
+			Title:            "Vendor and Business Partner Risk",
+			Description:      "The entity assesses and manages risks associated with vendors and business partners.",
+			Objectives:       []string{"Assess vendor risk", "Monitor vendor performance"},
+			Activities:       []string{"Assess model providers and other vendors before onboarding", "Review vendor attestations annually"},
+			EvidenceTypes:    []string{"Vendor risk assessments", "Vendor SOC reports", "Contracts"},
+			ApplicableLayers: []string{"governance", "organization"},
+		},
+
+		// A1: Availability
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "A1.1",
+			Title:            "Capacity Management",
+			Description:      "The entity maintains, monitors, and evaluates current processing capacity and use of system components to manage capacity demand and to enable the implementation of additional capacity.",
+			Objectives:       []string{"Forecast capacity", "Prevent resource exhaustion"},
+			Activities:       []string{"Monitor utilization", "Set rate limits and token quotas", "Plan capacity"},
+			EvidenceTypes:    []string{"Capacity reports", "Rate limit configuration"},
+			ApplicableLayers: []string{"infrastructure", "operations"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "A1.2",
+			Title:            "Environmental Protections and Backup",
+			Description:      "The entity authorizes, designs, develops or acquires, implements, operates, approves, maintains, and monitors environmental protections, software, data backup processes, and recovery infrastructure to meet its objectives.",
+			Objectives:       []string{"Protect against environmental threats", "Back up data"},
+			Activities:       []string{"Run and monitor backups", "Replicate across zones"},
+			EvidenceTypes:    []string{"Backup logs", "Architecture diagrams"},
+			ApplicableLayers: []string{"infrastructure"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "A1.3",
+			Title:            "Recovery Testing",
+			Description:      "The entity tests recovery plan procedures supporting system recovery to meet its objectives.",
+			Objectives:       []string{"Verify recovery capability"},
+			Activities:       []string{"Test backup restores", "Exercise disaster recovery plans"},
+			EvidenceTypes:    []string{"Restore test results", "DR exercise reports"},
+			ApplicableLayers: []string{"operations", "infrastructure"},
+		},
+
+		// C1: Confidentiality
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "C1.1",
+			Title:            "Confidential Information Identification and Protection",
+			Description:      "The entity identifies and maintains confidential information to meet the entity's objectives related to confidentiality.",
+			Objectives:       []string{"Classify confidential information", "Protect it throughout its lifecycle"},
+			Activities:       []string{"Classify data", "Keep confidential data out of prompts and model training", "Restrict retrieval sources"},
+			EvidenceTypes:    []string{"Data classification policy", "Data inventory"},
+			ApplicableLayers: []string{"data"},
+		},
+		{
+			FrameworkID:      string(FrameworkSOC2),
+			ControlID:        "C1.2",
+			Title:            "Confidential Information Disposal",
+			Description:      "The entity disposes of confidential information to meet the entity's objectives related to confidentiality.",
+			Objectives:       []string{"Dispose of confidential information on schedule"},
+			Activities:       []string{"Enforce retention periods", "Delete trace payloads and logs on expiry", "Honor deletion requests"},
+			EvidenceTypes:    []string{"Retention schedule", "Deletion logs"},
+			ApplicableLayers: []string{"data"},
+		},
+	}
+}
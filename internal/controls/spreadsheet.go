@@ -0,0 +1,243 @@
+package controls
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Table is a sheet of a spreadsheet export. Cells are strings, ints or
+// float64s; XLSX keeps numbers numeric so they can be sorted and summed.
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+// GapTables returns an analysis as spreadsheet tables: a coverage summary,
+// one row per gap, the inherited controls when there are any, and a legend.
+func GapTables(output *AnalysisOutput) []Table {
+	summary := Table{
+		Name:   "Summary",
+		Header: []string{"Metric", "Value"},
+		Rows: [][]any{
+			{"Framework", output.Framework},
+			{"Framework name", output.FrameworkName},
+			{"Total controls", output.TotalControls},
+			{"Implemented", output.ImplementedCount},
+			{"Inherited", output.InheritedCount},
+			{"Via crosswalks", output.CrosswalkCovered},
+			{"Partially covered", output.PartialCount},
+			{"Gaps identified", output.GapCount},
+			{"Coverage percentage", output.CoveragePercentage},
+			{"Accepted risk", output.AcceptedRiskCount},
+			{"Transferred risk", output.TransferredRiskCount},
+			{"Risk-adjusted coverage percentage", output.RiskAdjustedCoverage},
+			{"Critical gaps", output.Summary.Critical},
+			{"High gaps", output.Summary.High},
+			{"Medium gaps", output.Summary.Medium},
+			{"Low gaps", output.Summary.Low},
+		},
+	}
+	if in := output.Input; in != nil {
+		summary.Rows = append(summary.Rows,
+			[]any{"Implemented controls", strings.Join(in.ImplementedControls, ", ")},
+			[]any{"Source framework", in.SourceFramework},
+			[]any{"Providers", strings.Join(in.Providers, ", ")},
+		)
+	}
+
+	tables := []Table{summary, gapsTable(output)}
+	if inherited := newReportData(output).Inherited; len(inherited) > 0 {
+		t := Table{Name: "Inherited", Header: []string{"Control ID", "Source", "Providers"}}
+		for _, c := range inherited {
+			t.Rows = append(t.Rows, []any{c.ControlID, string(c.Source), strings.Join(c.ProviderIDs, ", ")})
+		}
+		tables = append(tables, t)
+	}
+	return append(tables, gapLegend())
+}
+
+// gapLegend explains the gap columns and, for the Covered By column, the
+// crosswalk mapping types.
+func gapLegend() Table {
+	t := Table{Name: "Legend", Header: []string{"Term", "Meaning", "Coverage Weight"}}
+	t.Rows = append(t.Rows,
+		[]any{"not_implemented", "No implemented or inherited control covers this control", ""},
+		[]any{"partial", "A parent control or crosswalk mappings cover part of this control", ""},
+		[]any{"Coverage Score", fmt.Sprintf("Combined crosswalk credit from 0 to 1; %.2f or more with a direct mapping counts as covered", crosswalkFullCoverage), ""},
+		[]any{"Covered By", "Implemented controls in other frameworks that map to this control; derived marks mappings inferred through intermediate frameworks", ""},
+	)
+	t.Rows = append(t.Rows, mappingLegendRows()...)
+	return t
+}
+
+// dispositionNote describes a gap's disposition for a report cell.
+func dispositionNote(gap GapDetail) string {
+	d := gap.Disposition
+	switch {
+	case d == nil:
+		return ""
+	case d.Disposition == models.DispositionRemediate:
+		return string(d.Disposition)
+	case d.ExpiresAt == nil:
+		return fmt.Sprintf("%s (expired)", d.Disposition)
+	case gap.DispositionExpired:
+		return fmt.Sprintf("%s (expired %s)", d.Disposition, d.ExpiresAt.Format(time.DateOnly))
+	}
+	return fmt.Sprintf("%s by %s until %s", d.Disposition, d.Approver, d.ExpiresAt.Format(time.DateOnly))
+}
+
+// gapsTable returns one row per gap.
+func gapsTable(output *AnalysisOutput) Table {
+	gaps := Table{
+		Name: "Gaps",
+		Header: []string{"Control ID", "Title", "Gap Type", "Priority", "Effort",
+			"Coverage Score", "Covered By", "Remediation Options", "Description", "Disposition"},
+		Rows: make([][]any, 0, len(output.Gaps)),
+	}
+	for _, gap := range output.Gaps {
+		coveredBy := make([]string, 0, len(gap.CoveredBy))
+		for _, c := range gap.CoveredBy {
+			coveredBy = append(coveredBy, fmt.Sprintf("%s:%s (%s, %.0f%%%s)", c.FrameworkID, c.ControlID, c.MappingType, c.Confidence*100, derivedNote(c)))
+		}
+		gaps.Rows = append(gaps.Rows, []any{
+			gap.ControlID, gap.Title, gap.GapType, gap.Priority, gap.EstimatedEffort,
+			gap.CoverageScore, strings.Join(coveredBy, "; "),
+			strings.Join(gap.RemediationOptions, "; "), gap.Description, dispositionNote(gap),
+		})
+	}
+	return gaps
+}
+
+// CrosswalkTables returns a crosswalk as spreadsheet tables: the mappings,
+// the source and target controls no mapping reaches, and a legend. The
+// control lists may be nil when the frameworks' catalogs are not loaded.
+func CrosswalkTables(crosswalks []models.Crosswalk, sourceControls, targetControls []models.Control) []Table {
+	mappedSource := make(map[string]bool, len(crosswalks))
+	mappedTarget := make(map[string]bool, len(crosswalks))
+	for _, xw := range crosswalks {
+		mappedSource[strings.ToLower(xw.SourceControlID)] = true
+		mappedTarget[strings.ToLower(xw.TargetControlID)] = true
+	}
+	return []Table{
+		mappingsTable(crosswalks),
+		unmappedTable("Unmapped Source", sourceControls, mappedSource),
+		unmappedTable("Unmapped Target", targetControls, mappedTarget),
+		crosswalkLegend(),
+	}
+}
+
+// mappingsTable returns one row per crosswalk mapping.
+func mappingsTable(crosswalks []models.Crosswalk) Table {
+	t := Table{
+		Name: "Mappings",
+		Header: []string{"Source Framework", "Source Control", "Target Framework", "Target Control",
+			"Mapping Type", "Confidence", "Derived Via", "Review State", "Rationale"},
+		Rows: make([][]any, 0, len(crosswalks)),
+	}
+	for _, xw := range crosswalks {
+		t.Rows = append(t.Rows, []any{
+			xw.SourceFrameworkID, xw.SourceControlID, xw.TargetFrameworkID, xw.TargetControlID,
+			string(xw.MappingType), xw.Confidence, strings.Join(xw.Via, ", "),
+			string(xw.ReviewState), xw.Rationale,
+		})
+	}
+	return t
+}
+
+// unmappedTable lists the controls whose lower-cased IDs are not in mapped.
+func unmappedTable(name string, controls []models.Control, mapped map[string]bool) Table {
+	t := Table{Name: name, Header: []string{"Framework", "Control ID", "Title", "Family"}}
+	for _, c := range controls {
+		if !mapped[strings.ToLower(c.ControlID)] {
+			t.Rows = append(t.Rows, []any{c.FrameworkID, c.ControlID, c.Title, c.Family})
+		}
+	}
+	return t
+}
+
+// crosswalkLegend explains mapping types and the other crosswalk columns.
+func crosswalkLegend() Table {
+	t := Table{Name: "Legend", Header: []string{"Term", "Meaning", "Coverage Weight"}, Rows: mappingLegendRows()}
+	t.Rows = append(t.Rows,
+		[]any{"Derived Via", "Intermediate controls a derived mapping chains through; derived mappings multiply the confidence of each step and should be reviewed before use", ""},
+		[]any{"Review State", "Curation stage of a stored mapping: proposed, reviewed or approved; empty for built-in mappings", ""},
+		[]any{"Unmapped Source", "Source controls no mapping covers; implementing them earns no credit toward the target", ""},
+		[]any{"Unmapped Target", "Target controls no mapping reaches; they must be implemented directly", ""},
+	)
+	return t
+}
+
+// mappingLegendRows describes each mapping type with the share of a target
+// control it covers in gap analysis, and mapping confidence.
+func mappingLegendRows() [][]any {
+	rows := make([][]any, 0, 6)
+	for _, m := range []struct {
+		typ     models.MappingType
+		meaning string
+	}{
+		{models.MappingExact, "Controls are equivalent"},
+		{models.MappingSuperset, "Source includes target"},
+		{models.MappingPartial, "Some overlap exists"},
+		{models.MappingSubset, "Target includes source"},
+		{models.MappingRelated, "Controls address similar topics"},
+	} {
+		rows = append(rows, []any{string(m.typ), m.meaning, mappingCoverageWeight[m.typ]})
+	}
+	return append(rows, []any{"Confidence", "How certain a mapping is, from 0 to 1; gap analysis credits a mapping with its coverage weight times its confidence", ""})
+}
+
+// WriteCrosswalks writes crosswalk mappings as csv (the mappings only) or
+// xlsx (the sheets of CrosswalkTables).
+func WriteCrosswalks(w io.Writer, crosswalks []models.Crosswalk, sourceControls, targetControls []models.Control, format string) error {
+	switch format {
+	case ReportCSV:
+		return WriteCSV(w, mappingsTable(crosswalks))
+	case ReportXLSX:
+		return WriteXLSX(w, CrosswalkTables(crosswalks, sourceControls, targetControls)...)
+	}
+	return fmt.Errorf("unsupported crosswalk format: %s", format)
+}
+
+// WriteCSV writes a table as CSV with a header row. Text that a spreadsheet
+// would evaluate as a formula is prefixed with a quote, since control titles
+// and rationales may come from imported catalogs.
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Header); err != nil {
+		return err
+	}
+	record := make([]string, len(t.Header))
+	for _, row := range t.Rows {
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, csvCell(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCell(v any) string {
+	switch v := v.(type) {
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
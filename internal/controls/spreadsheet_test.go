@@ -0,0 +1,93 @@
+package controls_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestWriteCrosswalks(t *testing.T) {
+	crosswalks := []models.Crosswalk{
+		{SourceFrameworkID: "nist-ai-rmf", SourceControlID: "GOVERN-1", TargetFrameworkID: "iso-42001", TargetControlID: "ISO42001-5.2", MappingType: models.MappingPartial, Confidence: 0.8},
+		{SourceFrameworkID: "nist-ai-rmf", SourceControlID: "MAP-1", TargetFrameworkID: "iso-42001", TargetControlID: "ISO42001-6.1", MappingType: models.MappingRelated, Confidence: 0.56, Derived: true, Via: []string{"nist-800-53:RA-3"}},
+	}
+
+	var b strings.Builder
+	if err := controls.WriteCrosswalks(&b, crosswalks, nil, nil, controls.ReportCSV); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("parsing CSV: %v", err)
+	}
+	want := []string{"nist-ai-rmf", "MAP-1", "iso-42001", "ISO42001-6.1", "related", "0.56", "nist-800-53:RA-3", "", ""}
+	if len(records) != 3 || !slices.Equal(records[2], want) {
+		t.Errorf("records = %q, want the derived mapping as %q", records, want)
+	}
+
+	sourceControls := []models.Control{
+		{FrameworkID: "nist-ai-rmf", ControlID: "GOVERN-1", Title: "Policies"},
+		{FrameworkID: "nist-ai-rmf", ControlID: "MEASURE-2", Title: "Evaluation"},
+	}
+	targetControls := []models.Control{
+		{FrameworkID: "iso-42001", ControlID: "iso42001-5.2", Title: "AI policy"},
+		{FrameworkID: "iso-42001", ControlID: "ISO42001-8.4", Title: "Impact assessment"},
+	}
+	var x bytes.Buffer
+	if err := controls.WriteCrosswalks(&x, crosswalks, sourceControls, targetControls, controls.ReportXLSX); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parts := readZip(t, x.Bytes())
+	if workbook := parts["xl/workbook.xml"]; !strings.Contains(workbook, `name="Mappings"`) || !strings.Contains(workbook, `name="Unmapped Source"`) ||
+		!strings.Contains(workbook, `name="Unmapped Target"`) || !strings.Contains(workbook, `name="Legend"`) {
+		t.Errorf("expected mappings, unmapped and legend sheets, got %s", workbook)
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, "<v>0.56</v>") || !strings.Contains(sheet, "GOVERN-1") {
+		t.Errorf("expected mappings with numeric confidence, got %.300s", sheet)
+	}
+	// Control IDs match case-insensitively, as in gap analysis.
+	if unmapped := parts["xl/worksheets/sheet2.xml"]; !strings.Contains(unmapped, "MEASURE-2") || strings.Contains(unmapped, "GOVERN-1") {
+		t.Errorf("unmapped source = %.500s", unmapped)
+	}
+	if unmapped := parts["xl/worksheets/sheet3.xml"]; !strings.Contains(unmapped, "ISO42001-8.4") || strings.Contains(unmapped, "5.2") {
+		t.Errorf("unmapped target = %.500s", unmapped)
+	}
+	if legend := parts["xl/worksheets/sheet4.xml"]; !strings.Contains(legend, "superset") || !strings.Contains(legend, "<v>0.25</v>") {
+		t.Errorf("legend = %.500s", legend)
+	}
+
+	if err := controls.WriteCrosswalks(io.Discard, crosswalks, nil, nil, controls.ReportPDF); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+// readZip returns the files of a zip archive by name.
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+	parts := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", f.Name, err)
+		}
+		parts[f.Name] = string(b)
+	}
+	return parts
+}
@@ -0,0 +1,444 @@
+package controls
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// System Security Plan formats.
+const (
+	ReportMarkdown = "markdown"
+	ReportDOCX     = "docx"
+)
+
+// SSPInput is what a System Security Plan is assembled from.
+type SSPInput struct {
+	OrganizationID string
+	Framework      string
+	// Agent scopes the plan to one agent: the controls its traits make not
+	// applicable are listed apart. Nil covers the whole organization.
+	Agent *models.Agent
+	// Implementations are the organization's tracked implementations;
+	// those of other frameworks are ignored.
+	Implementations []models.ControlImplementation
+	// Attestations are owners' responses confirming implementations.
+	Attestations []models.Attestation
+	Dispositions []models.GapDisposition
+	// Providers lists the common control providers the system inherits from.
+	Providers []string
+}
+
+// SSP is a System Security Plan: how each control of a framework is
+// implemented, who is responsible for it and what evidences it, and the
+// gaps that remain.
+type SSP struct {
+	OrganizationID   string        `json:"organization_id"`
+	System           SSPSystem     `json:"system"`
+	Framework        string        `json:"framework"`
+	FrameworkName    string        `json:"framework_name"`
+	FrameworkVersion string        `json:"framework_version"`
+	GeneratedAt      time.Time     `json:"generated_at"`
+	Summary          SSPSummary    `json:"summary"`
+	Controls         []SSPControl  `json:"controls"`
+	ResidualGaps     []SSPGap      `json:"residual_gaps"`
+	NotApplicable    []SSPExcluded `json:"not_applicable"`
+}
+
+// SSPSystem describes the system a plan covers: an agent, or every system
+// of the organization.
+type SSPSystem struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// AgentID is set when the plan covers one agent.
+	AgentID      string   `json:"agent_id,omitempty"`
+	Owner        string   `json:"owner,omitempty"`
+	Team         string   `json:"team,omitempty"`
+	Environment  string   `json:"environment,omitempty"`
+	RiskLevel    string   `json:"risk_level,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Tools        []string `json:"tools,omitempty"`
+}
+
+// SSPSummary counts a plan's controls. Coverage is over the applicable
+// controls.
+type SSPSummary struct {
+	TotalControls      int     `json:"total_controls"`
+	Applicable         int     `json:"applicable"`
+	Implemented        int     `json:"implemented"`
+	ResidualGaps       int     `json:"residual_gaps"`
+	AcceptedRisk       int     `json:"accepted_risk"`
+	NotApplicable      int     `json:"not_applicable"`
+	CoveragePercentage float64 `json:"coverage_percentage"`
+}
+
+// SSPControl is an implemented control's entry in a plan.
+type SSPControl struct {
+	ControlID string `json:"control_id"`
+	Title     string `json:"title"`
+	Family    string `json:"family,omitempty"`
+	// Status is the tracked implementation's status, or implemented for
+	// controls inherited without one.
+	Status models.ImplementationStatus `json:"status"`
+	// Source is local, inherited or hybrid.
+	Source models.ImplementationSource `json:"source"`
+	// Narrative describes how the control is implemented, from the tracked
+	// implementation's notes. Empty when none were recorded.
+	Narrative        string        `json:"narrative"`
+	ResponsibleRoles []string      `json:"responsible_roles"`
+	Evidence         []SSPEvidence `json:"evidence"`
+	// ExpectedEvidence are the catalog's evidence types for the control.
+	ExpectedEvidence []string `json:"expected_evidence,omitempty"`
+}
+
+// SSPEvidence is a record that a control is in place: an owner's
+// attestation or a passing monitoring check.
+type SSPEvidence struct {
+	// Kind is attestation or monitoring.
+	Kind        string     `json:"kind"`
+	Description string     `json:"description"`
+	At          *time.Time `json:"at,omitempty"`
+}
+
+// SSPGap is a control a plan does not yet meet.
+type SSPGap struct {
+	ControlID       string `json:"control_id"`
+	Title           string `json:"title"`
+	GapType         string `json:"gap_type"`
+	Priority        string `json:"priority"`
+	EstimatedEffort string `json:"estimated_effort"`
+	// Status is the tracked implementation's status when work is planned.
+	Status      models.ImplementationStatus `json:"status,omitempty"`
+	Owner       string                      `json:"owner,omitempty"`
+	DueDate     *time.Time                  `json:"due_date,omitempty"`
+	Disposition *models.GapDisposition      `json:"disposition,omitempty"`
+	// DispositionExpired is set when an accepted or transferred risk's
+	// decision has expired.
+	DispositionExpired bool `json:"disposition_expired,omitempty"`
+}
+
+// SSPExcluded is a control that does not apply to the plan's agent.
+type SSPExcluded struct {
+	ControlID string   `json:"control_id"`
+	Title     string   `json:"title"`
+	Reasons   []string `json:"reasons"`
+}
+
+// SSP assembles a System Security Plan for a framework. A gap analysis of
+// the implemented and verified tracked implementations, with the given
+// providers and dispositions, decides which controls are implemented; each
+// is documented with its implementation's notes as the narrative, its
+// owner and attesting owners as responsible roles, and its attestations
+// and passing monitoring checks as evidence.
+func (g *GapAnalyzer) SSP(ctx context.Context, in *SSPInput, now time.Time) (*SSP, error) {
+	fw, ok := g.Framework(in.Framework)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework: %s", in.Framework)
+	}
+	var impls []models.ControlImplementation
+	byControl := make(map[string]models.ControlImplementation)
+	for _, ci := range in.Implementations {
+		if ci.FrameworkID == in.Framework {
+			impls = append(impls, ci)
+			byControl[strings.ToLower(ci.ControlID)] = ci
+		}
+	}
+	output, err := g.RunAnalysis(ctx, &AnalysisInput{
+		TargetFramework:     in.Framework,
+		ImplementedControls: TrackedImplementedControls(impls),
+		Providers:           in.Providers,
+		Dispositions:        in.Dispositions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]ControlApplicability)
+	if in.Agent != nil {
+		baseline, err := g.Baseline(in.Framework, AgentTraits(in.Agent), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, ca := range baseline.Controls {
+			if !ca.Applicable {
+				excluded[strings.ToLower(ca.ControlID)] = ca
+			}
+		}
+	}
+	gaps := make(map[string]GapDetail, len(output.Gaps))
+	for _, gap := range output.Gaps {
+		gaps[strings.ToLower(gap.ControlID)] = gap
+	}
+	inventory := make(map[string]models.ImplementedControl, len(output.Inventory))
+	for _, ic := range output.Inventory {
+		inventory[strings.ToLower(ic.ControlID)] = ic
+	}
+	attested := make(map[string][]models.Attestation)
+	for _, a := range in.Attestations {
+		if a.FrameworkID == in.Framework && a.Status == models.AttestationAttested {
+			key := strings.ToLower(a.ControlID)
+			attested[key] = append(attested[key], a)
+		}
+	}
+	var monitored map[string]ControlStatus
+	if m := g.Monitor(); m != nil {
+		monitored = m.ControlStatuses()
+	}
+
+	ssp := &SSP{
+		OrganizationID:   in.OrganizationID,
+		System:           sspSystem(in),
+		Framework:        fw.ID,
+		FrameworkName:    fw.Name,
+		FrameworkVersion: fw.Version,
+		GeneratedAt:      now.UTC(),
+		Controls:         []SSPControl{},
+		ResidualGaps:     []SSPGap{},
+		NotApplicable:    []SSPExcluded{},
+	}
+	list, _ := g.Controls(in.Framework)
+	for _, c := range list {
+		key := strings.ToLower(c.ControlID)
+		ci, tracked := byControl[key]
+		if ca, ok := excluded[key]; ok {
+			ssp.NotApplicable = append(ssp.NotApplicable, SSPExcluded{ControlID: c.ControlID, Title: c.Title, Reasons: ca.Reasons})
+			continue
+		}
+		if gap, ok := gaps[key]; ok {
+			sg := SSPGap{
+				ControlID:       c.ControlID,
+				Title:           c.Title,
+				GapType:         gap.GapType,
+				Priority:        gap.Priority,
+				EstimatedEffort: gap.EstimatedEffort,
+				Disposition:     gap.Disposition,
+			}
+			sg.DispositionExpired = gap.DispositionExpired
+			if tracked {
+				sg.Status, sg.Owner, sg.DueDate = ci.Status, ci.Owner, ci.DueDate
+			}
+			if gap.RiskAccepted() {
+				ssp.Summary.AcceptedRisk++
+			}
+			ssp.ResidualGaps = append(ssp.ResidualGaps, sg)
+			continue
+		}
+
+		sc := SSPControl{
+			ControlID:        c.ControlID,
+			Title:            c.Title,
+			Family:           c.Family,
+			Status:           models.ImplementationImplemented,
+			Source:           models.ImplementationLocal,
+			ResponsibleRoles: []string{},
+			Evidence:         []SSPEvidence{},
+			ExpectedEvidence: c.EvidenceTypes,
+		}
+		addRole := func(role string) {
+			if role != "" && !containsFold(sc.ResponsibleRoles, role) {
+				sc.ResponsibleRoles = append(sc.ResponsibleRoles, role)
+			}
+		}
+		if tracked {
+			sc.Status, sc.Narrative = ci.Status, ci.Notes
+			addRole(ci.Owner)
+		}
+		if ic, ok := inventory[key]; ok {
+			sc.Source = ic.Source
+			for _, id := range ic.ProviderIDs {
+				name := id
+				if p, ok := g.GetProvider(id); ok {
+					name = p.Name
+				}
+				addRole("Common control provider: " + name)
+			}
+		}
+		for _, a := range attested[key] {
+			addRole(a.Owner)
+			desc := "Attested by " + a.Owner
+			if a.Comment != "" {
+				desc += ": " + a.Comment
+			}
+			sc.Evidence = append(sc.Evidence, SSPEvidence{Kind: "attestation", Description: desc, At: a.RespondedAt})
+		}
+		if st, ok := monitored[key]; ok && st.Status == CheckPassed {
+			at := st.VerifiedAt
+			sc.Evidence = append(sc.Evidence, SSPEvidence{
+				Kind:        "monitoring",
+				Description: "Monitoring checks passed: " + strings.Join(st.Checks, ", "),
+				At:          &at,
+			})
+		}
+		ssp.Controls = append(ssp.Controls, sc)
+	}
+
+	s := &ssp.Summary
+	s.TotalControls = len(list)
+	s.NotApplicable = len(ssp.NotApplicable)
+	s.Applicable = s.TotalControls - s.NotApplicable
+	s.Implemented = len(ssp.Controls)
+	s.ResidualGaps = len(ssp.ResidualGaps)
+	if s.Applicable > 0 {
+		s.CoveragePercentage = float64(s.Implemented) / float64(s.Applicable) * 100
+	}
+	return ssp, nil
+}
+
+// sspSystem describes the agent a plan covers, or the organization.
+func sspSystem(in *SSPInput) SSPSystem {
+	a := in.Agent
+	if a == nil {
+		return SSPSystem{
+			Name:        in.OrganizationID,
+			Description: "All AI systems and agents operated by the organization.",
+		}
+	}
+	sys := SSPSystem{
+		Name:        a.Name,
+		Description: a.Description,
+		AgentID:     a.ID.String(),
+		Owner:       a.Owner,
+		Team:        a.Team,
+		Environment: a.Environment,
+		RiskLevel:   a.RiskLevel,
+	}
+	for _, c := range a.Capabilities {
+		sys.Capabilities = append(sys.Capabilities, c.Name)
+	}
+	for _, t := range a.Tools {
+		sys.Tools = append(sys.Tools, t.Name)
+	}
+	return sys
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteSSP renders a System Security Plan as Markdown or DOCX.
+func WriteSSP(w io.Writer, ssp *SSP, format string) error {
+	switch format {
+	case ReportMarkdown:
+		doc := &markdownDocument{}
+		renderSSP(doc, ssp)
+		_, err := doc.WriteTo(w)
+		return err
+	case ReportDOCX:
+		doc := &docxDocument{}
+		renderSSP(doc, ssp)
+		_, err := doc.WriteTo(w)
+		return err
+	}
+	return fmt.Errorf("unsupported SSP format: %s", format)
+}
+
+// renderSSP lays a plan out as a document.
+func renderSSP(d textDocument, ssp *SSP) {
+	sys := ssp.System
+	d.Heading(1, "System Security Plan: "+sys.Name)
+	d.Paragraph(fmt.Sprintf("Framework: %s (%s) version %s", ssp.FrameworkName, ssp.Framework, ssp.FrameworkVersion))
+	d.Paragraph("Organization: " + ssp.OrganizationID)
+	d.Paragraph("Generated: " + ssp.GeneratedAt.Format(time.DateOnly))
+
+	d.Heading(2, "1. System Description")
+	d.Paragraph(sys.Description)
+	var facts [][]string
+	for _, f := range [][2]string{
+		{"Agent ID", sys.AgentID},
+		{"System owner", sys.Owner},
+		{"Team", sys.Team},
+		{"Environment", sys.Environment},
+		{"Risk level", sys.RiskLevel},
+		{"Capabilities", strings.Join(sys.Capabilities, ", ")},
+		{"Tools", strings.Join(sys.Tools, ", ")},
+	} {
+		if f[1] != "" {
+			facts = append(facts, []string{f[0], f[1]})
+		}
+	}
+	if len(facts) > 0 {
+		d.Table([]string{"Attribute", "Value"}, facts)
+	}
+
+	s := ssp.Summary
+	d.Heading(2, "2. Control Summary")
+	d.Table([]string{"Measure", "Count"}, [][]string{
+		{"Total controls", fmt.Sprint(s.TotalControls)},
+		{"Applicable", fmt.Sprint(s.Applicable)},
+		{"Implemented", fmt.Sprint(s.Implemented)},
+		{"Residual gaps", fmt.Sprint(s.ResidualGaps)},
+		{"Accepted or transferred risk", fmt.Sprint(s.AcceptedRisk)},
+		{"Not applicable", fmt.Sprint(s.NotApplicable)},
+		{"Coverage", fmt.Sprintf("%.1f%%", s.CoveragePercentage)},
+	})
+
+	d.Heading(2, "3. Implemented Controls")
+	if len(ssp.Controls) == 0 {
+		d.Paragraph("No controls are implemented yet.")
+	}
+	for _, c := range ssp.Controls {
+		d.Heading(3, c.ControlID+" "+c.Title)
+		status := string(c.Status)
+		if c.Source != models.ImplementationLocal {
+			status += " (" + string(c.Source) + ")"
+		}
+		d.Bullet("Status: " + status)
+		roles := strings.Join(c.ResponsibleRoles, "; ")
+		if roles == "" {
+			roles = "Unassigned"
+		}
+		d.Bullet("Responsible roles: " + roles)
+		if c.Narrative != "" {
+			d.Paragraph(c.Narrative)
+		} else {
+			d.Paragraph("No implementation narrative has been recorded.")
+		}
+		for _, e := range c.Evidence {
+			text := "Evidence (" + e.Kind + "): " + e.Description
+			if e.At != nil {
+				text += ", " + e.At.Format(time.DateOnly)
+			}
+			d.Bullet(text)
+		}
+		if len(c.ExpectedEvidence) > 0 {
+			d.Bullet("Expected evidence: " + strings.Join(c.ExpectedEvidence, ", "))
+		}
+	}
+
+	d.Heading(2, "4. Residual Gaps")
+	if len(ssp.ResidualGaps) == 0 {
+		d.Paragraph("No residual gaps.")
+	} else {
+		rows := make([][]string, 0, len(ssp.ResidualGaps))
+		for _, g := range ssp.ResidualGaps {
+			status, due := string(g.Status), ""
+			if status == "" {
+				status = "not started"
+			}
+			if g.DueDate != nil {
+				due = g.DueDate.Format(time.DateOnly)
+			}
+			note := dispositionNote(GapDetail{Disposition: g.Disposition, DispositionExpired: g.DispositionExpired})
+			rows = append(rows, []string{g.ControlID, g.Title, g.Priority, status, g.Owner, due, note})
+		}
+		d.Table([]string{"Control", "Title", "Priority", "Status", "Owner", "Due", "Disposition"}, rows)
+	}
+
+	if len(ssp.NotApplicable) > 0 {
+		d.Heading(2, "5. Controls Not Applicable")
+		rows := make([][]string, 0, len(ssp.NotApplicable))
+		for _, c := range ssp.NotApplicable {
+			rows = append(rows, []string{c.ControlID, c.Title, strings.Join(c.Reasons, "; ")})
+		}
+		d.Table([]string{"Control", "Title", "Reason"}, rows)
+	}
+}
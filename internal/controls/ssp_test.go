@@ -0,0 +1,99 @@
+package controls_test
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestSSP(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	expires := now.AddDate(0, 6, 0)
+	in := &controls.SSPInput{
+		OrganizationID: "org-1",
+		Framework:      "owasp-llm-top10",
+		Agent:          &models.Agent{ID: uuid.New(), Name: "support-bot", Owner: "platform"},
+		Implementations: []models.ControlImplementation{
+			{FrameworkID: "owasp-llm-top10", ControlID: "LLM01", Status: models.ImplementationVerified,
+				Owner: "appsec", Notes: "Prompts are screened by the\ninjection classifier."},
+			{FrameworkID: "owasp-llm-top10", ControlID: "LLM02", Status: models.ImplementationInProgress, Owner: "data"},
+			{FrameworkID: "iso-42001", ControlID: "LLM03", Status: models.ImplementationVerified},
+		},
+		Attestations: []models.Attestation{
+			{FrameworkID: "owasp-llm-top10", ControlID: "LLM01", Owner: "ml-lead", Status: models.AttestationAttested, Comment: "reviewed"},
+			{FrameworkID: "owasp-llm-top10", ControlID: "LLM01", Owner: "someone", Status: models.AttestationPending},
+		},
+		Dispositions: []models.GapDisposition{
+			{FrameworkID: "owasp-llm-top10", ControlID: "LLM03", Disposition: models.DispositionAccept, Approver: "ciso", ExpiresAt: &expires},
+		},
+	}
+	ssp, err := analyzer.SSP(context.Background(), in, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ssp.System.Name != "support-bot" || ssp.System.AgentID == "" {
+		t.Errorf("system = %+v", ssp.System)
+	}
+	if len(ssp.Controls) != 1 {
+		t.Fatalf("controls = %+v, want LLM01 only", ssp.Controls)
+	}
+	c := ssp.Controls[0]
+	if c.ControlID != "LLM01" || c.Status != models.ImplementationVerified || c.Narrative == "" ||
+		!slices.Equal(c.ResponsibleRoles, []string{"appsec", "ml-lead"}) || len(c.Evidence) != 1 || c.Evidence[0].Kind != "attestation" {
+		t.Errorf("LLM01 = %+v", c)
+	}
+	var gap02, gap03 *controls.SSPGap
+	for i, g := range ssp.ResidualGaps {
+		switch g.ControlID {
+		case "LLM02":
+			gap02 = &ssp.ResidualGaps[i]
+		case "LLM03":
+			gap03 = &ssp.ResidualGaps[i]
+		case "LLM06":
+			t.Error("LLM06 does not apply to an agent without tools")
+		}
+	}
+	if gap02 == nil || gap02.Owner != "data" || gap02.Status != models.ImplementationInProgress {
+		t.Errorf("LLM02 gap = %+v", gap02)
+	}
+	if gap03 == nil || gap03.Disposition == nil || gap03.Disposition.Disposition != models.DispositionAccept {
+		t.Errorf("LLM03 gap = %+v, want accepted", gap03)
+	}
+	s := ssp.Summary
+	if s.NotApplicable == 0 || s.Implemented != 1 || s.AcceptedRisk != 1 ||
+		s.Applicable != s.TotalControls-s.NotApplicable || s.Implemented+s.ResidualGaps != s.Applicable {
+		t.Errorf("summary = %+v", s)
+	}
+
+	var md bytes.Buffer
+	if err := controls.WriteSSP(&md, ssp, controls.ReportMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# System Security Plan: support-bot", "## 3. Implemented Controls", "Prompts are screened by the injection classifier.", "## 5. Controls Not Applicable", "LLM06"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+	var docx bytes.Buffer
+	if err := controls.WriteSSP(&docx, ssp, controls.ReportDOCX); err != nil {
+		t.Fatal(err)
+	}
+	files := readZip(t, docx.Bytes())
+	if !strings.Contains(files["word/document.xml"], "Prompts are screened") || files["word/styles.xml"] == "" {
+		t.Errorf("docx has %d parts, missing the narrative or styles", len(files))
+	}
+	if err := controls.WriteSSP(&docx, ssp, "pdf"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,192 @@
+package controls
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agentguard/agentguard/internal/llm"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/vectordb"
+)
+
+// Defaults for crosswalk suggestions.
+const (
+	DefaultSuggestTopK          = 3
+	DefaultSuggestMinConfidence = 0.3
+	// embedBatchSize bounds the texts sent to the embedder at once.
+	embedBatchSize = 96
+)
+
+// CrosswalkSuggester proposes crosswalk mappings between frameworks by
+// embedding control text and pairing each source control with the most
+// similar target controls. Suggestions are for human review; they are never
+// added to the crosswalks used for gap analysis.
+type CrosswalkSuggester struct {
+	embedder llm.Embedder
+	store    vectordb.Provider
+
+	mu      sync.Mutex
+	indexed map[string]bool // framework@version already in the store
+}
+
+// NewCrosswalkSuggester creates a suggester. Target controls are indexed in
+// store, or in memory when store is nil.
+func NewCrosswalkSuggester(embedder llm.Embedder, store vectordb.Provider) *CrosswalkSuggester {
+	if store == nil {
+		store = vectordb.NewMemoryProvider()
+	}
+	return &CrosswalkSuggester{embedder: embedder, store: store, indexed: make(map[string]bool)}
+}
+
+// Embedder names the embedder behind the suggestions.
+func (s *CrosswalkSuggester) Embedder() string {
+	return s.embedder.Name()
+}
+
+// SuggestOptions tunes crosswalk suggestions.
+type SuggestOptions struct {
+	// TopK bounds the suggestions per source control.
+	TopK int `json:"top_k,omitempty"`
+	// MinConfidence drops weaker suggestions.
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+}
+
+// SuggestCrosswalks proposes source→target mappings for control pairs
+// without a direct mapping, strongest first. Confidence is the cosine
+// similarity of the two controls' embedded text.
+func (g *GapAnalyzer) SuggestCrosswalks(ctx context.Context, s *CrosswalkSuggester, source, target string, opts SuggestOptions) ([]models.Crosswalk, error) {
+	if opts.TopK <= 0 {
+		opts.TopK = DefaultSuggestTopK
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = DefaultSuggestMinConfidence
+	}
+	if _, err := g.service.GetFramework(FrameworkID(source)); err != nil {
+		return nil, err
+	}
+	targetFW, err := g.service.GetFramework(FrameworkID(target))
+	if err != nil {
+		return nil, err
+	}
+	sourceControls, _ := g.Controls(source)
+	targetControls, _ := g.Controls(target)
+
+	if err := s.index(ctx, targetFW, targetControls); err != nil {
+		return nil, err
+	}
+	direct, err := g.service.directCrosswalks(FrameworkID(source), FrameworkID(target))
+	if err != nil {
+		return nil, err
+	}
+	mapped := make(map[[2]string]bool, len(direct))
+	for _, xw := range direct {
+		mapped[[2]string{strings.ToLower(xw.SourceControlID), strings.ToLower(xw.TargetControlID)}] = true
+	}
+
+	vectors, err := s.embed(ctx, sourceControls)
+	if err != nil {
+		return nil, err
+	}
+	var out []models.Crosswalk
+	for i, c := range sourceControls {
+		// Fetch extra candidates so pairs already mapped do not crowd out
+		// new ones.
+		docs, err := s.store.Search(ctx, vectordb.SearchRequest{
+			Embedding: vectors[i],
+			TopK:      opts.TopK * 2,
+			Filter:    map[string]string{"framework": targetFW.ID, "version": targetFW.Version},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("searching %s controls: %w", target, err)
+		}
+		n := 0
+		for _, d := range docs {
+			targetID := d.Metadata["control_id"]
+			confidence := math.Round(float64(d.Score)*100) / 100
+			if n == opts.TopK || confidence < opts.MinConfidence {
+				break
+			}
+			if mapped[[2]string{strings.ToLower(c.ControlID), strings.ToLower(targetID)}] {
+				continue
+			}
+			out = append(out, models.Crosswalk{
+				SourceFrameworkID: source,
+				SourceControlID:   c.ControlID,
+				TargetFrameworkID: target,
+				TargetControlID:   targetID,
+				MappingType:       models.MappingRelated,
+				Confidence:        confidence,
+				Rationale:         fmt.Sprintf("Embedding similarity %.2f (%s); review before adopting", d.Score, s.embedder.Name()),
+			})
+			n++
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Confidence != out[j].Confidence {
+			return out[i].Confidence > out[j].Confidence
+		}
+		if out[i].SourceControlID != out[j].SourceControlID {
+			return out[i].SourceControlID < out[j].SourceControlID
+		}
+		return out[i].TargetControlID < out[j].TargetControlID
+	})
+	return out, nil
+}
+
+// index embeds a framework version's controls into the store, once.
+func (s *CrosswalkSuggester) index(ctx context.Context, fw *models.Framework, controls []models.Control) error {
+	key := fw.ID + "@" + fw.Version
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indexed[key] {
+		return nil
+	}
+	vectors, err := s.embed(ctx, controls)
+	if err != nil {
+		return err
+	}
+	docs := make([]vectordb.Document, len(controls))
+	for i, c := range controls {
+		docs[i] = vectordb.Document{
+			ID:        key + "/" + c.ControlID,
+			Content:   controlText(c),
+			Embedding: vectors[i],
+			Metadata:  map[string]string{"framework": fw.ID, "version": fw.Version, "control_id": c.ControlID},
+		}
+	}
+	if err := s.store.Upsert(ctx, docs); err != nil {
+		return fmt.Errorf("indexing %s controls: %w", fw.ID, err)
+	}
+	s.indexed[key] = true
+	return nil
+}
+
+// embed embeds controls' text in batches.
+func (s *CrosswalkSuggester) embed(ctx context.Context, controls []models.Control) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(controls))
+	for start := 0; start < len(controls); start += embedBatchSize {
+		batch := controls[start:min(start+embedBatchSize, len(controls))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = controlText(c)
+		}
+		v, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embedding controls: %w", err)
+		}
+		vectors = append(vectors, v...)
+	}
+	return vectors, nil
+}
+
+// controlText is the text of a control that is embedded: what it requires
+// rather than how it is evidenced.
+func controlText(c models.Control) string {
+	parts := append([]string{c.Title, c.Description}, c.Objectives...)
+	return strings.Join(parts, "\n")
+}
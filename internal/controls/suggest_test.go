@@ -0,0 +1,74 @@
+package controls_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/llm"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestSuggestCrosswalks(t *testing.T) {
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatalf("creating analyzer: %v", err)
+	}
+	suggester := controls.NewCrosswalkSuggester(llm.NewHashEmbedder(0), nil)
+	ctx := context.Background()
+
+	suggestions, err := analyzer.SuggestCrosswalks(ctx, suggester, "csa-aicm", "nist-800-53", controls.SuggestOptions{TopK: 2})
+	if err != nil {
+		t.Fatalf("SuggestCrosswalks: %v", err)
+	}
+	if len(suggestions) == 0 {
+		t.Fatal("no suggestions")
+	}
+	direct, err := analyzer.Crosswalks("csa-aicm", "nist-800-53", false)
+	if err != nil {
+		t.Fatalf("Crosswalks: %v", err)
+	}
+	mapped := make(map[[2]string]bool)
+	for _, xw := range direct {
+		mapped[[2]string{xw.SourceControlID, xw.TargetControlID}] = true
+	}
+	perSource := make(map[string]int)
+	for i, xw := range suggestions {
+		if mapped[[2]string{xw.SourceControlID, xw.TargetControlID}] {
+			t.Errorf("suggested %s -> %s, which is already mapped", xw.SourceControlID, xw.TargetControlID)
+		}
+		if xw.Confidence < controls.DefaultSuggestMinConfidence || xw.Confidence > 1 {
+			t.Errorf("%s -> %s confidence = %v", xw.SourceControlID, xw.TargetControlID, xw.Confidence)
+		}
+		if i > 0 && xw.Confidence > suggestions[i-1].Confidence {
+			t.Errorf("suggestions not sorted by confidence at %d", i)
+		}
+		if xw.MappingType != models.MappingRelated {
+			t.Errorf("mapping type = %s, want related", xw.MappingType)
+		}
+		perSource[xw.SourceControlID]++
+	}
+	for id, n := range perSource {
+		if n > 2 {
+			t.Errorf("%s has %d suggestions, want at most 2", id, n)
+		}
+	}
+
+	// A control suggests itself when both sides are the same catalog.
+	self, err := analyzer.SuggestCrosswalks(ctx, suggester, "csa-aicm", "csa-aicm", controls.SuggestOptions{TopK: 1, MinConfidence: 0.99})
+	if err != nil {
+		t.Fatalf("SuggestCrosswalks: %v", err)
+	}
+	if len(self) == 0 {
+		t.Fatal("no self suggestions")
+	}
+	for _, xw := range self {
+		if xw.SourceControlID != xw.TargetControlID {
+			t.Errorf("%s best matches %s, want itself", xw.SourceControlID, xw.TargetControlID)
+		}
+	}
+
+	if _, err := analyzer.SuggestCrosswalks(ctx, suggester, "csa-aicm", "no-such-framework", controls.SuggestOptions{}); err == nil {
+		t.Error("suggested against an unknown framework")
+	}
+}
@@ -0,0 +1,122 @@
+package controls
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxControlTags is the most tags one control may carry.
+const MaxControlTags = 32
+
+// validTag matches a tag: lower-case letters, digits and the separators
+// ".", "_", ":" and "-", starting with a letter or digit.
+var validTag = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]{0,63}$`)
+
+// NormalizeTags lower-cases, de-duplicates and sorts tags, rejecting
+// malformed ones.
+func NormalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !validTag.MatchString(t) {
+			return nil, fmt.Errorf("invalid tag %q: use up to 64 lower-case letters, digits, '.', '_', ':' or '-'", t)
+		}
+		out = append(out, t)
+	}
+	slices.Sort(out)
+	out = slices.Compact(out)
+	if len(out) > MaxControlTags {
+		return nil, fmt.Errorf("too many tags: %d, at most %d", len(out), MaxControlTags)
+	}
+	return out, nil
+}
+
+// TagIndex holds an organization's control tags by framework and
+// lower-cased control ID.
+type TagIndex map[[2]string][]string
+
+// IndexTags indexes tagged controls.
+func IndexTags(list []models.ControlTags) TagIndex {
+	idx := make(TagIndex, len(list))
+	for _, t := range list {
+		idx[[2]string{t.FrameworkID, strings.ToLower(t.ControlID)}] = t.Tags
+	}
+	return idx
+}
+
+// Of returns a control's tags.
+func (idx TagIndex) Of(frameworkID, controlID string) []string {
+	return idx[[2]string{frameworkID, strings.ToLower(controlID)}]
+}
+
+// Framework returns the tags of one framework's controls keyed by control
+// ID, as taken by AnalysisInput.
+func (idx TagIndex) Framework(frameworkID string) map[string][]string {
+	tags := make(map[string][]string)
+	for key, t := range idx {
+		if key[0] == frameworkID {
+			tags[key[1]] = t
+		}
+	}
+	return tags
+}
+
+// AnalysisTags returns the tags of a framework's controls for a gap
+// analysis. repo may be nil. A failure to load them is logged, and the
+// analysis goes ahead without tag priorities.
+func AnalysisTags(ctx context.Context, repo repository.ControlTagRepository, org, framework string) map[string][]string {
+	if repo == nil {
+		return nil
+	}
+	list, err := repo.ListControlTags(ctx, org, framework)
+	if err != nil {
+		log.Warn().Err(err).Str("org_id", org).Str("framework_id", framework).Msg("failed to load control tags")
+		return nil
+	}
+	return IndexTags(list).Framework(framework)
+}
+
+// Apply sets the tags of controls in place and returns those carrying any
+// of filter, or all of them when filter is empty.
+func (idx TagIndex) Apply(list []models.Control, filter []string) []models.Control {
+	out := list[:0:0]
+	for i := range list {
+		list[i].Tags = idx.Of(list[i].FrameworkID, list[i].ControlID)
+		if anyOf(list[i].Tags, filter, func(have, want string) bool { return have == want }) {
+			out = append(out, list[i])
+		}
+	}
+	return out
+}
+
+// SearchTagged answers a search with tag filters over a search that has
+// no tags, such as the analyzer's: it searches every page without the tag
+// filters, tags the matches from idx and pages those carrying q's tags.
+func SearchTagged(search func(*repository.ControlQuery) ([]repository.ControlMatch, int, error), q *repository.ControlQuery, idx TagIndex) ([]repository.ControlMatch, int, error) {
+	all := *q
+	all.Tags, all.Offset, all.Limit = nil, 0, 0
+	found, _, err := search(&all)
+	if err != nil {
+		return nil, 0, err
+	}
+	var matches []repository.ControlMatch
+	for _, m := range found {
+		m.Tags = idx.Of(m.FrameworkID, m.ControlID)
+		if anyOf(m.Tags, q.Tags, func(have, want string) bool { return have == want }) {
+			matches = append(matches, m)
+		}
+	}
+	total := len(matches)
+	matches = matches[min(q.Offset, total):]
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	return matches, total, nil
+}
@@ -0,0 +1,81 @@
+package controls_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+func TestControlTags(t *testing.T) {
+	tags, err := controls.NormalizeTags([]string{" Agent-Runtime", "data-pipeline", "agent-runtime"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tags, []string{"agent-runtime", "data-pipeline"}) {
+		t.Errorf("normalized tags = %v", tags)
+	}
+	for _, bad := range []string{"", "has space", "-leading"} {
+		if _, err := controls.NormalizeTags([]string{bad}); err == nil {
+			t.Errorf("tag %q accepted", bad)
+		}
+	}
+
+	m := controls.DefaultScoringModel()
+	m.Priority.TagPriorities = map[string]string{"agent-runtime": "critical", "data-pipeline": "medium"}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := models.Control{ApplicableLayers: []string{"governance"}, Tags: []string{"data-pipeline"}}
+	if got := m.DeterminePriority(context.Background(), ctrl); got != "medium" {
+		t.Errorf("tagged priority = %q, want the tag's over the layer's", got)
+	}
+	ctrl.Tags = append(ctrl.Tags, "agent-runtime")
+	if got := m.DeterminePriority(context.Background(), ctrl); got != "critical" {
+		t.Errorf("priority with two tags = %q, want the most urgent", got)
+	}
+	m.Priority.TagPriorities["x"] = "urgent"
+	if err := m.Validate(); err == nil {
+		t.Error("unknown tag priority accepted")
+	}
+
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoring := controls.DefaultScoringModel()
+	scoring.Priority.TagPriorities = map[string]string{"agent-runtime": "critical"}
+	out, err := analyzer.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework: "soc2",
+		Scoring:         scoring,
+		Tags:            map[string][]string{"cc6.1": {"agent-runtime"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(out.Gaps, func(g controls.GapDetail) bool { return g.ControlID == "CC6.1" })
+	if i < 0 || out.Gaps[i].Priority != "critical" || !slices.Equal(out.Gaps[i].Tags, []string{"agent-runtime"}) {
+		t.Errorf("tagged gap = %+v, want critical", out.Gaps[max(i, 0)])
+	}
+	if out.Summary.Critical != 1 {
+		t.Errorf("critical gaps = %d, want the tagged one", out.Summary.Critical)
+	}
+
+	idx := controls.IndexTags([]models.ControlTags{{FrameworkID: "soc2", ControlID: "CC6.1", Tags: []string{"agent-runtime"}}})
+	q := &repository.ControlQuery{FrameworkIDs: []string{"soc2"}, Tags: []string{"agent-runtime"}, Limit: 10}
+	matches, total, err := controls.SearchTagged(func(q *repository.ControlQuery) ([]repository.ControlMatch, int, error) {
+		return analyzer.SearchControls(context.Background(), q)
+	}, q, idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(matches) != 1 || matches[0].ControlID != "CC6.1" || matches[0].Tags[0] != "agent-runtime" {
+		t.Errorf("tagged search = %d %+v", total, matches)
+	}
+	if _, total, _ := analyzer.SearchControls(context.Background(), q); total != 0 {
+		t.Errorf("analyzer search by tag = %d matches, want none without tags", total)
+	}
+}
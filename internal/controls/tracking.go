@@ -0,0 +1,38 @@
+package controls
+
+import (
+	"sort"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// ValidImplementationStatus reports whether s is a control implementation
+// status.
+func ValidImplementationStatus(s models.ImplementationStatus) bool {
+	switch s {
+	case models.ImplementationPlanned, models.ImplementationInProgress,
+		models.ImplementationImplemented, models.ImplementationVerified:
+		return true
+	}
+	return false
+}
+
+// TrackedImplementedControls returns the control IDs of tracked
+// implementations that count as implemented in gap analysis: those
+// implemented or verified. Planned and in-progress work is not credited.
+// The IDs are sorted and never nil.
+func TrackedImplementedControls(impls []models.ControlImplementation) []string {
+	seen := make(map[string]bool, len(impls))
+	ids := []string{}
+	for _, ci := range impls {
+		if ci.Status != models.ImplementationImplemented && ci.Status != models.ImplementationVerified {
+			continue
+		}
+		if !seen[ci.ControlID] {
+			seen[ci.ControlID] = true
+			ids = append(ids, ci.ControlID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,51 @@
+package controls_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+func TestTrackedImplementedControls(t *testing.T) {
+	impls := []models.ControlImplementation{
+		{ControlID: "MAP-1", Status: models.ImplementationVerified},
+		{ControlID: "GOVERN-1", Status: models.ImplementationImplemented},
+		{ControlID: "GOVERN-2", Status: models.ImplementationInProgress},
+		{ControlID: "MEASURE-1", Status: models.ImplementationPlanned},
+		{ControlID: "GOVERN-1", Status: models.ImplementationVerified},
+	}
+	got := controls.TrackedImplementedControls(impls)
+	if want := []string{"GOVERN-1", "MAP-1"}; !slices.Equal(got, want) {
+		t.Errorf("TrackedImplementedControls = %v, want %v", got, want)
+	}
+	if got := controls.TrackedImplementedControls(nil); got == nil || len(got) != 0 {
+		t.Errorf("no implementations = %#v, want empty", got)
+	}
+
+	for status, want := range map[models.ImplementationStatus]bool{
+		models.ImplementationPlanned: true, models.ImplementationVerified: true, "done": false, "": false,
+	} {
+		if got := controls.ValidImplementationStatus(status); got != want {
+			t.Errorf("ValidImplementationStatus(%q) = %v, want %v", status, got, want)
+		}
+	}
+
+	// Tracked controls feed an analysis like an explicit list.
+	ga, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ga.RunAnalysis(context.Background(), &controls.AnalysisInput{
+		TargetFramework:     "nist-ai-rmf",
+		ImplementedControls: got,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findGap(out, "GOVERN-1") != nil || findGap(out, "GOVERN-2") == nil {
+		t.Error("only implemented and verified controls should close gaps")
+	}
+}
@@ -0,0 +1,147 @@
+package controls
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Framework definition and catalog files named <id>@<version>, e.g.
+// catalogs/nist-ai-rmf@1.0.json, add that version of the framework
+// without replacing the current one. A file that replaces the current
+// version keeps the one it replaced available under its version.
+
+// splitVersionedName splits a data file's base name into framework ID and
+// version; version is empty for unversioned files.
+func splitVersionedName(path string) (FrameworkID, string) {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	id, version, _ := strings.Cut(base, "@")
+	return FrameworkID(id), version
+}
+
+// ParseVersionRef splits a framework reference such as "nist-ai-rmf@1.0"
+// into ID and version. The version is empty when the reference names the
+// current version.
+func ParseVersionRef(ref string) (string, string) {
+	id, version, _ := strings.Cut(ref, "@")
+	return id, version
+}
+
+// archive keeps the current version of a framework, if it has controls,
+// before it is replaced.
+func (s *Service) archive(id FrameworkID) {
+	fw, ok := s.frameworks[id]
+	if !ok {
+		return
+	}
+	controls, ok := s.controls[id]
+	if !ok {
+		return
+	}
+	s.addVersion(fw, controls)
+}
+
+// addVersion records a version of a framework.
+func (s *Service) addVersion(fw *models.Framework, controls []models.Control) {
+	id := FrameworkID(fw.ID)
+	if s.versions[id] == nil {
+		s.versions[id] = make(map[string]*models.FrameworkVersion)
+	}
+	s.versions[id][fw.Version] = &models.FrameworkVersion{
+		FrameworkID:  fw.ID,
+		Version:      fw.Version,
+		Name:         fw.Name,
+		Publisher:    fw.Publisher,
+		Description:  fw.Description,
+		URL:          fw.URL,
+		ControlCount: len(controls),
+		Controls:     controls,
+	}
+}
+
+// ListVersions returns the known versions of a framework, oldest first,
+// without their controls.
+func (s *Service) ListVersions(id FrameworkID) ([]models.FrameworkVersion, error) {
+	fw, ok := s.frameworks[id]
+	if !ok {
+		return nil, fmt.Errorf("framework not found: %s", id)
+	}
+	result := make([]models.FrameworkVersion, 0, len(s.versions[id]))
+	for _, v := range s.versions[id] {
+		entry := *v
+		entry.Controls = nil
+		entry.Current = v.Version == fw.Version
+		result = append(result, entry)
+	}
+	SortVersions(result)
+	return result, nil
+}
+
+// GetVersion returns a version of a framework with its controls. An empty
+// version is the current one.
+func (s *Service) GetVersion(id FrameworkID, version string) (*models.FrameworkVersion, error) {
+	fw, ok := s.frameworks[id]
+	if !ok {
+		return nil, fmt.Errorf("framework not found: %s", id)
+	}
+	if version == "" {
+		version = fw.Version
+	}
+	v, ok := s.versions[id][version]
+	if !ok {
+		var known []string
+		versions, _ := s.ListVersions(id)
+		for _, v := range versions {
+			known = append(known, v.Version)
+		}
+		return nil, fmt.Errorf("%s has no version %q (known: %s)", id, version, strings.Join(known, ", "))
+	}
+	entry := *v
+	entry.Current = v.Version == fw.Version
+	return &entry, nil
+}
+
+// SortVersions orders framework versions oldest first.
+func SortVersions(versions []models.FrameworkVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i].Version, versions[j].Version) < 0
+	})
+}
+
+// CompareVersions orders version labels such as "1.0", "1.1" and "5.1.1",
+// comparing runs of digits numerically and everything else as text.
+func CompareVersions(a, b string) int {
+	for a != "" && b != "" {
+		ra, rest := versionRun(a)
+		rb, restB := versionRun(b)
+		na, errA := strconv.Atoi(ra)
+		nb, errB := strconv.Atoi(rb)
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+				return 1
+			}
+		case ra != rb:
+			return strings.Compare(ra, rb)
+		}
+		a, b = rest, restB
+	}
+	return strings.Compare(a, b)
+}
+
+// versionRun splits off a leading run of digits or of other characters.
+func versionRun(s string) (string, string) {
+	digit := s[0] >= '0' && s[0] <= '9'
+	i := 1
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9') == digit {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,73 @@
+package controls_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/controls"
+)
+
+func TestFrameworkVersions(t *testing.T) {
+	ga, err := controls.NewGapAnalyzer("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := ga.Versions("acme-ai-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Version != "2025.1" || versions[1].Version != "2026.1" {
+		t.Fatalf("versions = %+v", versions)
+	}
+	if versions[0].Current || !versions[1].Current || versions[0].ControlCount != 3 {
+		t.Errorf("versions = %+v", versions)
+	}
+
+	// The catalog replaced the built-in 800-53 controls; the built-in
+	// version is kept.
+	nist, err := ga.Versions("nist-800-53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nist) != 2 || nist[0].Version != "5.1" || nist[1].Version != "5.1.1" || !nist[1].Current {
+		t.Errorf("nist-800-53 versions = %+v", nist)
+	}
+
+	diff, err := ga.Diff("acme-ai-policy", "2025.1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 2 || len(diff.Removed) != 1 || diff.Removed[0].ControlID != "ACME-3" || diff.Unchanged != 1 {
+		t.Errorf("diff = %+v", diff)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].ControlID != "ACME-1" || strings.Join(diff.Changed[0].Fields, ",") != "description" {
+		t.Errorf("changed = %+v", diff.Changed)
+	}
+	diff.MarkAffected([]string{"acme-1", "ACME-2", "ACME-3"})
+	if strings.Join(diff.Affected, ",") != "acme-1,ACME-3" {
+		t.Errorf("affected = %v", diff.Affected)
+	}
+
+	if _, err := ga.Diff("acme-ai-policy", "2024.1", ""); err == nil || !strings.Contains(err.Error(), "known: 2025.1, 2026.1") {
+		t.Errorf("unknown version err = %v", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"5.1", "5.1.1", -1},
+		{"2023", "2023", 0},
+		{"2017 (rev. 2022)", "2017", 1},
+	}
+	for _, tt := range tests {
+		if got := controls.CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
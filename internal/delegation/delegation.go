@@ -0,0 +1,203 @@
+// Package delegation reconstructs the trace forests produced when agents
+// delegate work to other agents. A worker's trace links to the supervisor
+// span that invoked it; following those links up gives the delegation chain
+// behind a trace, and following them down gives everything a supervisor set
+// in motion.
+package delegation
+
+import (
+	"context"
+	"slices"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/threat"
+)
+
+// Bounds on reconstruction, so a runaway or cyclic delegation cannot make
+// a query unbounded.
+const (
+	maxDepth  = 8
+	maxTraces = 500
+)
+
+// Node is one agent's trace in a delegation forest.
+type Node struct {
+	repository.TraceSummary
+	Depth int `json:"depth"`
+	// Roles are the capabilities of the tools the agent called.
+	Roles    threat.ToolRoles `json:"roles"`
+	Children []*Node          `json:"children,omitempty"`
+}
+
+// Hop is one step of a delegation chain.
+type Hop struct {
+	TraceID string `json:"trace_id"`
+	AgentID string `json:"agent_id"`
+	// SpanID is the span in the previous hop's trace that delegated to
+	// this one; empty for the root.
+	SpanID string `json:"span_id,omitempty"`
+}
+
+// Amplification is a delegation hop where the delegate exercised
+// capabilities that no agent above it in the chain did, so the agents
+// upstream gained privileges by delegating.
+type Amplification struct {
+	TraceID       string   `json:"trace_id"`
+	AgentID       string   `json:"agent_id"`
+	ParentTraceID string   `json:"parent_trace_id"`
+	ParentAgentID string   `json:"parent_agent_id"`
+	Chain         []string `json:"chain"` // agent IDs from the root
+	Gained        []string `json:"gained"`
+	Tools         []string `json:"tools"`
+	Severity      string   `json:"severity"`
+}
+
+// Forest is the delegation forest around a trace.
+type Forest struct {
+	TraceID string `json:"trace_id"`
+	Root    *Node  `json:"root"`
+	// Chain runs from the root to the requested trace.
+	Chain  []Hop `json:"chain"`
+	Traces int   `json:"traces"`
+	// MissingParent is set when the root links to a trace that is not
+	// stored, e.g. one past retention, so the chain starts mid-way.
+	MissingParent  *models.TraceLink `json:"missing_parent,omitempty"`
+	Amplifications []Amplification   `json:"amplifications"`
+	// Truncated is set when the forest exceeded the depth or size bounds.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Reconstruct builds the delegation forest containing a trace: it walks up
+// parent links to the root, then collects every trace delegated from it.
+// It returns repository.ErrNotFound when the trace is not stored.
+func Reconstruct(ctx context.Context, repo repository.DelegationRepository, orgID, traceID string) (*Forest, error) {
+	found, err := repo.TraceSummaries(ctx, orgID, []string{traceID})
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	f := &Forest{TraceID: traceID, Amplifications: []Amplification{}}
+	up := []repository.TraceSummary{found[0]}
+	seen := map[string]bool{traceID: true}
+	for cur := found[0]; cur.Parent != nil; {
+		if len(up) > maxDepth || seen[cur.Parent.TraceID] {
+			f.Truncated = true
+			break
+		}
+		parents, err := repo.TraceSummaries(ctx, orgID, []string{cur.Parent.TraceID})
+		if err != nil {
+			return nil, err
+		}
+		if len(parents) == 0 {
+			f.MissingParent = cur.Parent
+			break
+		}
+		cur = parents[0]
+		seen[cur.TraceID] = true
+		up = append(up, cur)
+	}
+	slices.Reverse(up)
+	for _, s := range up {
+		h := Hop{TraceID: s.TraceID, AgentID: s.AgentID}
+		if s.Parent != nil && len(f.Chain) > 0 {
+			h.SpanID = s.Parent.SpanID
+		}
+		f.Chain = append(f.Chain, h)
+	}
+
+	f.Root = newNode(up[0], 0)
+	f.Traces = 1
+	seen = map[string]bool{f.Root.TraceID: true}
+	level := []*Node{f.Root}
+	for depth := 1; len(level) > 0; depth++ {
+		ids := make([]string, len(level))
+		byID := make(map[string]*Node, len(level))
+		for i, n := range level {
+			ids[i] = n.TraceID
+			byID[n.TraceID] = n
+		}
+		if depth > maxDepth {
+			f.Truncated = true
+			break
+		}
+		children, err := repo.DelegatedTraces(ctx, orgID, ids)
+		if err != nil {
+			return nil, err
+		}
+		var next []*Node
+		for _, c := range children {
+			parent := byID[c.Parent.TraceID]
+			if parent == nil || seen[c.TraceID] {
+				continue
+			}
+			if f.Traces >= maxTraces {
+				f.Truncated = true
+				break
+			}
+			seen[c.TraceID] = true
+			n := newNode(c, depth)
+			parent.Children = append(parent.Children, n)
+			next = append(next, n)
+			f.Traces++
+		}
+		level = next
+	}
+
+	f.amplifications(f.Root, nil, threat.ToolRoles{})
+	return f, nil
+}
+
+func newNode(s repository.TraceSummary, depth int) *Node {
+	return &Node{TraceSummary: s, Depth: depth, Roles: threat.RolesOf(s.Tools)}
+}
+
+// amplifications walks the forest carrying the roles exercised above each
+// node, recording nodes that exercised new sensitive, egress or action
+// roles.
+func (f *Forest) amplifications(n *Node, chain []string, above threat.ToolRoles) {
+	chain = append(slices.Clone(chain), n.AgentID)
+	if len(chain) > 1 {
+		gained := n.Roles.Without(above)
+		gained.Untrusted = false
+		if gained.Sensitive || gained.Egress || gained.Action {
+			var tools []string
+			for _, t := range n.Tools {
+				if g := threat.ClassifyTool(t).Without(above); g.Sensitive || g.Egress || g.Action {
+					tools = append(tools, t.Name)
+				}
+			}
+			f.Amplifications = append(f.Amplifications, Amplification{
+				TraceID:       n.TraceID,
+				AgentID:       n.AgentID,
+				ParentTraceID: n.Parent.TraceID,
+				ParentAgentID: chain[len(chain)-2],
+				Chain:         chain,
+				Gained:        gained.Names(),
+				Tools:         tools,
+				Severity:      severity(gained, above),
+			})
+		}
+	}
+	above = above.Union(n.Roles)
+	for _, c := range n.Children {
+		f.amplifications(c, chain, above)
+	}
+}
+
+// severity rates an amplification: gaining egress or action is high, and
+// critical when an agent upstream handled untrusted content that could
+// have steered the delegation; gaining only sensitive reads is medium.
+func severity(gained, above threat.ToolRoles) string {
+	switch {
+	case (gained.Egress || gained.Action) && above.Untrusted:
+		return "critical"
+	case gained.Egress || gained.Action:
+		return "high"
+	default:
+		return "medium"
+	}
+}
@@ -0,0 +1,124 @@
+package delegation_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/delegation"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// fakeRepo serves trace summaries from memory.
+type fakeRepo []repository.TraceSummary
+
+func (r fakeRepo) TraceSummaries(_ context.Context, _ string, ids []string) ([]repository.TraceSummary, error) {
+	var out []repository.TraceSummary
+	for _, s := range r {
+		if slices.Contains(ids, s.TraceID) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r fakeRepo) DelegatedTraces(_ context.Context, _ string, parents []string) ([]repository.TraceSummary, error) {
+	var out []repository.TraceSummary
+	for _, s := range r {
+		if s.Parent != nil && slices.Contains(parents, s.Parent.TraceID) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func summary(id, agent, parent string, tools ...models.ToolBinding) repository.TraceSummary {
+	s := repository.TraceSummary{TraceID: id, AgentID: agent, Tools: tools}
+	if parent != "" {
+		s.Parent = &models.TraceLink{TraceID: parent, SpanID: "span-" + parent}
+	}
+	return s
+}
+
+var (
+	browse    = models.ToolBinding{Name: "web_browse", Category: "web_search"}
+	db        = models.ToolBinding{Name: "customer_db", Category: "database"}
+	email     = models.ToolBinding{Name: "send_email", Category: "email"}
+	summarize = models.ToolBinding{Name: "summarize", Category: "llm"}
+)
+
+func crew() fakeRepo {
+	return fakeRepo{
+		summary("t1", "supervisor", "", browse),
+		summary("t2", "researcher", "t1", summarize),
+		summary("t3", "analyst", "t2", db),
+		summary("t4", "mailer", "t3", email, db),
+		summary("t5", "searcher", "t1", browse),
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	f, err := delegation.Reconstruct(context.Background(), crew(), "org", "t3")
+	if err != nil {
+		t.Fatalf("Reconstruct: %v", err)
+	}
+
+	var chain []string
+	for _, h := range f.Chain {
+		chain = append(chain, h.AgentID)
+	}
+	if want := []string{"supervisor", "researcher", "analyst"}; !reflect.DeepEqual(chain, want) {
+		t.Errorf("chain = %v, want %v", chain, want)
+	}
+	if f.Chain[0].SpanID != "" || f.Chain[2].SpanID != "span-t2" {
+		t.Errorf("chain spans = %+v, want the delegating span on each hop after the root", f.Chain)
+	}
+	if f.Root.TraceID != "t1" || f.Traces != 5 || len(f.Root.Children) != 2 {
+		t.Errorf("forest root %s with %d traces and %d children, want t1 with 5 and 2", f.Root.TraceID, f.Traces, len(f.Root.Children))
+	}
+
+	// The supervisor reads the web; the analyst is the first to read
+	// customer data and the mailer the first to send anything out.
+	var got []string
+	for _, a := range f.Amplifications {
+		got = append(got, a.AgentID+":"+a.Severity)
+	}
+	if want := []string{"analyst:medium", "mailer:critical"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("amplifications = %v, want %v", got, want)
+	}
+	if mailer := f.Amplifications[1]; !reflect.DeepEqual(mailer.Gained, []string{"egress"}) || !reflect.DeepEqual(mailer.Tools, []string{"send_email"}) {
+		t.Errorf("mailer gained %v via %v, want [egress] via [send_email]", mailer.Gained, mailer.Tools)
+	}
+}
+
+func TestReconstructMissingParent(t *testing.T) {
+	repo := crew()[1:]
+	f, err := delegation.Reconstruct(context.Background(), repo, "org", "t4")
+	if err != nil {
+		t.Fatalf("Reconstruct: %v", err)
+	}
+	if f.Root.TraceID != "t2" || f.MissingParent == nil || f.MissingParent.TraceID != "t1" {
+		t.Errorf("root %s, missing parent %+v; want t2 missing t1", f.Root.TraceID, f.MissingParent)
+	}
+
+	if _, err := delegation.Reconstruct(context.Background(), repo, "org", "t9"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("unknown trace: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReconstructCycle(t *testing.T) {
+	repo := fakeRepo{
+		summary("t1", "a", "t2"),
+		summary("t2", "b", "t1"),
+	}
+	f, err := delegation.Reconstruct(context.Background(), repo, "org", "t1")
+	if err != nil {
+		t.Fatalf("Reconstruct: %v", err)
+	}
+	if !f.Truncated || f.Traces != 2 {
+		t.Errorf("truncated %v with %d traces, want a truncated forest of 2", f.Truncated, f.Traces)
+	}
+}
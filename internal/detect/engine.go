@@ -0,0 +1,344 @@
+package detect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrNotFound is returned for a rule ID that is not in the loaded ruleset.
+var ErrNotFound = errors.New("detection rule not found")
+
+// historyLimit is how many earlier rulesets Status reports.
+const historyLimit = 20
+
+// RulesetInfo describes a loaded revision of the rule files.
+type RulesetInfo struct {
+	// Revision counts the distinct rulesets loaded since startup.
+	Revision int `json:"revision"`
+	// Digest is a SHA-256 over the rule files' names and contents.
+	Digest   string    `json:"digest"`
+	LoadedAt time.Time `json:"loaded_at"`
+	Files    []string  `json:"files"`
+	Rules    int       `json:"rules"`
+}
+
+// Status reports the live ruleset, the ones before it and the last failed
+// reload, if it is more recent than the live ruleset.
+type Status struct {
+	Dir     string        `json:"dir"`
+	Current RulesetInfo   `json:"current"`
+	History []RulesetInfo `json:"history"` // newest first, excluding Current
+	// LastError is why the most recent reload was rejected. The previous
+	// ruleset stays live until the files are fixed.
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// RuleState is a rule with its effective enabled state.
+type RuleState struct {
+	Rule
+	// Active is whether the rule runs: its file setting unless overridden.
+	Active bool `json:"active"`
+	// Overridden is set when Active was changed through the API.
+	Overridden bool `json:"overridden"`
+}
+
+// override is an enabled state set through the API for one version of a
+// rule. A new version in the rule files clears it.
+type override struct {
+	enabled bool
+	version int
+}
+
+// Engine holds the live detection ruleset loaded from a directory of YAML
+// rule files (*.yaml and *.yml). It is safe for concurrent use.
+type Engine struct {
+	dir string
+	now func() time.Time
+
+	mu        sync.RWMutex
+	current   RulesetInfo
+	rules     []Rule
+	byID      map[string]int
+	overrides map[string]override
+	history   []RulesetInfo
+	stamp     string
+	lastErr   error
+	lastErrAt time.Time
+}
+
+// NewEngine loads the rule files in dir. It fails when any of them is
+// invalid; later reloads keep the previous ruleset instead.
+func NewEngine(dir string) (*Engine, error) {
+	e := &Engine{dir: dir, now: time.Now, overrides: make(map[string]override)}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Dir returns the rules directory.
+func (e *Engine) Dir() string { return e.dir }
+
+// Reload reads and validates every rule file. A valid set that differs from
+// the live one becomes a new revision; an invalid one is rejected whole and
+// recorded in Status.
+func (e *Engine) Reload() error {
+	stamp, err := e.fileStamp()
+	if err == nil {
+		err = e.load(stamp)
+	}
+	if err != nil {
+		e.mu.Lock()
+		e.lastErr, e.lastErrAt = err, e.now().UTC()
+		e.mu.Unlock()
+	}
+	return err
+}
+
+// reloadIfChanged reloads when a rule file was added, removed or modified
+// since the last load.
+func (e *Engine) reloadIfChanged() error {
+	stamp, err := e.fileStamp()
+	if err != nil {
+		return err
+	}
+	e.mu.RLock()
+	same := stamp == e.stamp
+	e.mu.RUnlock()
+	if same {
+		return nil
+	}
+	return e.Reload()
+}
+
+// Run polls the rules directory every interval until ctx is cancelled,
+// reloading when the files change.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.mu.RLock()
+			revision := e.current.Revision
+			e.mu.RUnlock()
+			if err := e.reloadIfChanged(); err != nil {
+				log.Error().Err(err).Int("revision", revision).Msg("detection rules rejected, keeping the previous ruleset")
+				continue
+			}
+			e.mu.RLock()
+			cur := e.current
+			e.mu.RUnlock()
+			if cur.Revision != revision {
+				log.Info().Int("revision", cur.Revision).Int("rules", cur.Rules).Str("digest", cur.Digest[:12]).Msg("detection rules reloaded")
+			}
+		}
+	}
+}
+
+// ruleFiles lists the rule files in the directory, sorted.
+func (e *Engine) ruleFiles() ([]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(e.dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fileStamp summarizes the rule files' names, sizes and modification
+// times, to notice changes without reading them.
+func (e *Engine) fileStamp() (string, error) {
+	if _, err := os.Stat(e.dir); err != nil {
+		return "", fmt.Errorf("reading rules directory: %w", err)
+	}
+	files, err := e.ruleFiles()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", f, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+func (e *Engine) load(stamp string) error {
+	files, err := e.ruleFiles()
+	if err != nil {
+		return err
+	}
+	var (
+		rules []Rule
+		errs  []FieldError
+		names = make([]string, 0, len(files))
+		first = make(map[string]Rule)
+		h     = sha256.New()
+	)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("reading rule file: %w", err)
+		}
+		name := filepath.Base(f)
+		names = append(names, name)
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(data))
+		h.Write(data)
+
+		parsed, err := ParseRules(name, data)
+		var verr *ValidationError
+		switch {
+		case errors.As(err, &verr):
+			errs = append(errs, verr.Errors...)
+			continue
+		case err != nil:
+			return err
+		}
+		for _, r := range parsed {
+			if prev, ok := first[r.ID]; ok {
+				errs = append(errs, FieldError{File: name, Line: 1, Column: 1, Msg: fmt.Sprintf("rule %q is already defined in %s", r.ID, prev.File)})
+				continue
+			}
+			first[r.ID] = r
+			rules = append(rules, r)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stamp, e.lastErr = stamp, nil
+	if e.current.Revision > 0 && digest == e.current.Digest {
+		return nil
+	}
+	if e.current.Revision > 0 {
+		e.history = append([]RulesetInfo{e.current}, e.history...)
+		if len(e.history) > historyLimit {
+			e.history = e.history[:historyLimit]
+		}
+	}
+	e.current = RulesetInfo{
+		Revision: e.current.Revision + 1,
+		Digest:   digest,
+		LoadedAt: e.now().UTC(),
+		Files:    names,
+		Rules:    len(rules),
+	}
+	e.rules = rules
+	e.byID = make(map[string]int, len(rules))
+	for i, r := range rules {
+		e.byID[r.ID] = i
+	}
+	// Overrides only outlive a reload for the rule version they were set on.
+	for id, o := range e.overrides {
+		if i, ok := e.byID[id]; !ok || rules[i].Version != o.version {
+			delete(e.overrides, id)
+		}
+	}
+	return nil
+}
+
+// Status reports the live ruleset and its history.
+func (e *Engine) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	s := Status{Dir: e.dir, Current: e.current, History: append([]RulesetInfo{}, e.history...)}
+	if e.lastErr != nil {
+		s.LastError = e.lastErr.Error()
+		at := e.lastErrAt
+		s.LastErrorAt = &at
+	}
+	return s
+}
+
+// Rules returns the live rules in file order with their enabled states.
+func (e *Engine) Rules() []RuleState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make([]RuleState, len(e.rules))
+	for i, r := range e.rules {
+		out[i] = e.state(r)
+	}
+	return out
+}
+
+// Rule returns a live rule by ID.
+func (e *Engine) Rule(id string) (RuleState, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	i, ok := e.byID[id]
+	if !ok {
+		return RuleState{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return e.state(e.rules[i]), nil
+}
+
+// SetEnabled turns a rule on or off regardless of its rule file setting,
+// until the rule's version changes. Setting it back to the file setting
+// removes the override.
+func (e *Engine) SetEnabled(id string, enabled bool) (RuleState, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	i, ok := e.byID[id]
+	if !ok {
+		return RuleState{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	r := e.rules[i]
+	if enabled == r.Enabled {
+		delete(e.overrides, id)
+	} else {
+		e.overrides[id] = override{enabled: enabled, version: r.Version}
+	}
+	return e.state(r), nil
+}
+
+// state resolves r's enabled state. Callers hold mu.
+func (e *Engine) state(r Rule) RuleState {
+	s := RuleState{Rule: r, Active: r.Enabled}
+	if o, ok := e.overrides[r.ID]; ok {
+		s.Active, s.Overridden = o.enabled, true
+	}
+	return s
+}
+
+// active returns the rules that run and the ruleset revision they belong
+// to.
+func (e *Engine) active() ([]Rule, int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var out []Rule
+	for _, r := range e.rules {
+		if e.state(r).Active {
+			out = append(out, r)
+		}
+	}
+	return out, e.current.Revision
+}
@@ -0,0 +1,173 @@
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// maxExcerpt bounds the matched text quoted in a finding.
+const maxExcerpt = 120
+
+// Finding is a rule that matched a trace.
+type Finding struct {
+	Rule     Rule
+	Revision int
+	// SpanID and Field locate a pattern match; Field is a FieldX constant,
+	// or attributes.<key>. Both are empty for threshold rules.
+	SpanID string
+	Field  string
+	// Excerpt is the matched text, or a redacted stand-in for rules that
+	// set Redact.
+	Excerpt string
+	// Value is the trace's metric for threshold rules.
+	Value float64
+}
+
+// Evaluate runs the active rules over t. A pattern rule reports at most
+// one finding per span, at the first field it matches. Run it before span
+// payloads and attributes are stripped.
+func (e *Engine) Evaluate(t *models.AgentTrace) []Finding {
+	rules, revision := e.active()
+	if len(rules) == 0 {
+		return nil
+	}
+	var findings []Finding
+	var counts map[string]float64
+	for _, r := range rules {
+		switch r.Kind {
+		case KindPattern:
+			for i := range t.Spans {
+				if f, ok := matchSpan(r, &t.Spans[i]); ok {
+					f.Revision = revision
+					findings = append(findings, f)
+				}
+			}
+		case KindThreshold:
+			if counts == nil {
+				counts = traceCounts(t)
+			}
+			if v := counts[r.Metric]; v > r.Max {
+				findings = append(findings, Finding{Rule: r, Revision: revision, Value: v})
+			}
+		}
+	}
+	return findings
+}
+
+// matchSpan returns r's first match in s.
+func matchSpan(r Rule, s *models.Span) (Finding, bool) {
+	for _, field := range r.Fields {
+		for _, c := range spanContent(s, field) {
+			for _, re := range r.compiled {
+				if m := re.FindString(c.text); m != "" {
+					return Finding{Rule: r, SpanID: s.SpanID, Field: c.field, Excerpt: excerpt(m, r.Redact)}, true
+				}
+			}
+		}
+	}
+	return Finding{}, false
+}
+
+type content struct {
+	field string
+	text  string
+}
+
+// spanContent returns the text of one of s's fields. Attributes are
+// returned key by key, in key order.
+func spanContent(s *models.Span, field string) []content {
+	one := func(v any) []content {
+		if text := asText(v); text != "" {
+			return []content{{field: field, text: text}}
+		}
+		return nil
+	}
+	switch field {
+	case FieldPrompt:
+		if s.Data.LLM != nil {
+			return one(s.Data.LLM.Prompt)
+		}
+	case FieldToolInput:
+		if s.Data.Tool != nil {
+			return one(s.Data.Tool.Input)
+		}
+	case FieldToolOutput:
+		if s.Data.Tool != nil {
+			return one(s.Data.Tool.Output)
+		}
+	case FieldRetrievalQuery:
+		if s.Data.Retrieval != nil {
+			return one(s.Data.Retrieval.Query)
+		}
+	case FieldAttributes:
+		keys := make([]string, 0, len(s.Attributes))
+		for k := range s.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var out []content
+		for _, k := range keys {
+			if text := asText(s.Attributes[k]); text != "" {
+				out = append(out, content{field: FieldAttributes + "." + k, text: text})
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// asText returns strings as they are and other values as JSON.
+func asText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// excerpt quotes a match, truncated, or describes it without its content
+// when redact is set.
+func excerpt(m string, redact bool) string {
+	if redact {
+		n := utf8.RuneCountInString(m)
+		if n <= 8 {
+			return fmt.Sprintf("[redacted %d chars]", n)
+		}
+		return fmt.Sprintf("%s…[redacted %d chars]", string([]rune(m)[:4]), n)
+	}
+	if utf8.RuneCountInString(m) > maxExcerpt {
+		return string([]rune(m)[:maxExcerpt]) + "…"
+	}
+	return m
+}
+
+// traceCounts computes the threshold rule metrics for t.
+func traceCounts(t *models.AgentTrace) map[string]float64 {
+	c := map[string]float64{MetricSpans: float64(len(t.Spans))}
+	for _, s := range t.Spans {
+		switch {
+		case s.Data.LLM != nil:
+			c[MetricLLMCalls]++
+			c[MetricTotalTokens] += float64(s.Data.LLM.TotalTokens)
+		case s.Data.Tool != nil:
+			c[MetricToolCalls]++
+			if s.Data.Tool.ExternalCall {
+				c[MetricExternalCalls]++
+			}
+		}
+	}
+	if t.EndTime != nil {
+		c[MetricDurationMs] = float64(t.EndTime.Sub(t.StartTime).Milliseconds())
+	}
+	return c
+}
@@ -0,0 +1,383 @@
+// Package detect evaluates detection rules over ingested traces: patterns,
+// such as prompt injection phrases and secret formats, matched against span
+// content, and thresholds on per-trace counts. Rules are YAML files in a
+// rules directory and are reloaded when the files change, so detections can
+// be tuned without a rebuild. See docs/detection-rules.md for the schema.
+package detect
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is how a rule decides that a trace matches.
+type Kind string
+
+// Rule kinds.
+const (
+	// KindPattern matches regular expressions against span content.
+	KindPattern Kind = "pattern"
+	// KindThreshold fires when a per-trace count exceeds a maximum.
+	KindThreshold Kind = "threshold"
+)
+
+// Span content a pattern rule can match.
+const (
+	FieldPrompt         = "prompt"
+	FieldToolInput      = "tool_input"
+	FieldToolOutput     = "tool_output"
+	FieldRetrievalQuery = "retrieval_query"
+	FieldAttributes     = "attributes"
+)
+
+// AllFields is what a pattern rule matches when it names no fields.
+var AllFields = []string{FieldPrompt, FieldToolInput, FieldToolOutput, FieldRetrievalQuery, FieldAttributes}
+
+// Per-trace counts a threshold rule can bound.
+const (
+	MetricSpans         = "spans"
+	MetricLLMCalls      = "llm_calls"
+	MetricToolCalls     = "tool_calls"
+	MetricExternalCalls = "external_calls"
+	MetricTotalTokens   = "total_tokens"
+	MetricDurationMs    = "duration_ms"
+)
+
+var metrics = map[string]bool{
+	MetricSpans: true, MetricLLMCalls: true, MetricToolCalls: true,
+	MetricExternalCalls: true, MetricTotalTokens: true, MetricDurationMs: true,
+}
+
+// signalTypes are the signal types a rule may raise.
+var signalTypes = map[models.SignalType]bool{
+	models.SignalInjectionAttempt:    true,
+	models.SignalDataExfiltration:    true,
+	models.SignalToolAbuse:           true,
+	models.SignalPrivilegeEscalation: true,
+	models.SignalAnomalousBehavior:   true,
+	models.SignalPolicyViolation:     true,
+}
+
+var severities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// ruleIDPattern matches rule IDs, as the API accepts them.
+var ruleIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,62}[a-z0-9]$`)
+
+// Rule is a detection rule loaded from a rule file.
+type Rule struct {
+	ID          string `json:"id"`
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	Kind        Kind   `json:"kind"`
+	// Signal and Severity describe the security signal raised on a match.
+	Signal   models.SignalType `json:"signal"`
+	Severity string            `json:"severity"`
+	// Enabled is the setting in the rule file, before any API override.
+	Enabled bool `json:"enabled"`
+	// Patterns and Fields apply to pattern rules. Redact keeps the matched
+	// text out of signal evidence, for rules that find secrets.
+	Patterns []string `json:"patterns,omitempty"`
+	Fields   []string `json:"fields,omitempty"`
+	Redact   bool     `json:"redact,omitempty"`
+	// Metric and Max apply to threshold rules: the rule fires when the
+	// trace's metric is greater than Max.
+	Metric string  `json:"metric,omitempty"`
+	Max    float64 `json:"max,omitempty"`
+	// File is the rule file the rule was loaded from.
+	File string `json:"file"`
+
+	compiled []*regexp.Regexp
+}
+
+// ruleFile is the document shape of a rule file.
+type ruleFile struct {
+	Rules []struct {
+		ID          string   `yaml:"id"`
+		Version     int      `yaml:"version"`
+		Description string   `yaml:"description"`
+		Kind        string   `yaml:"kind"`
+		Signal      string   `yaml:"signal"`
+		Severity    string   `yaml:"severity"`
+		Enabled     *bool    `yaml:"enabled"`
+		Patterns    []string `yaml:"patterns"`
+		Fields      []string `yaml:"fields"`
+		Redact      bool     `yaml:"redact"`
+		Metric      string   `yaml:"metric"`
+		Max         float64  `yaml:"max"`
+	} `yaml:"rules"`
+}
+
+// FieldError is a problem at a position in a rule file.
+type FieldError struct {
+	File   string
+	Line   int
+	Column int
+	Path   string // e.g. rules[2].patterns[0]
+	Msg    string
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Path, e.Msg)
+}
+
+// ValidationError lists every problem found in a set of rule files.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid detection rules:\n  " + strings.Join(msgs, "\n  ")
+}
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindStrings
+	kindNumber
+	kindBool
+	kindObjects
+)
+
+type fieldSpec struct {
+	kind     fieldKind
+	required bool
+	items    map[string]fieldSpec
+}
+
+// ruleFileSchema is the rule file schema. Kind-specific fields are checked
+// after decoding.
+var ruleFileSchema = map[string]fieldSpec{
+	"rules": {kind: kindObjects, required: true, items: map[string]fieldSpec{
+		"id":          {kind: kindString, required: true},
+		"version":     {kind: kindNumber},
+		"description": {kind: kindString},
+		"kind":        {kind: kindString, required: true},
+		"signal":      {kind: kindString, required: true},
+		"severity":    {kind: kindString, required: true},
+		"enabled":     {kind: kindBool},
+		"patterns":    {kind: kindStrings},
+		"fields":      {kind: kindStrings},
+		"redact":      {kind: kindBool},
+		"metric":      {kind: kindString},
+		"max":         {kind: kindNumber},
+	}},
+}
+
+// ParseRules parses and validates a rule file. Every problem is reported
+// with its line and column in a *ValidationError.
+func ParseRules(path string, data []byte) ([]Rule, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, &ValidationError{Errors: []FieldError{{File: path, Line: 1, Column: 1, Msg: "empty document"}}}
+	}
+	root := doc.Content[0]
+
+	var errs []FieldError
+	validateNode(path, root, ruleFileSchema, "", &errs)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	var f ruleFile
+	if err := root.Decode(&f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Positions of each rule's fields for the semantic checks below.
+	var ruleNodes []*yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "rules" {
+			ruleNodes = root.Content[i+1].Content
+		}
+	}
+	// at reports a problem with a rule's field, or with one item of a list
+	// field when item is not negative.
+	at := func(i int, field string, item int, msg string) {
+		n, p := ruleNodes[i], fmt.Sprintf("rules[%d].%s", i, field)
+		for j := 0; j+1 < len(n.Content); j += 2 {
+			if n.Content[j].Value == field {
+				n = n.Content[j+1]
+				break
+			}
+		}
+		if item >= 0 && n.Kind == yaml.SequenceNode && item < len(n.Content) {
+			n, p = n.Content[item], fmt.Sprintf("%s[%d]", p, item)
+		}
+		errs = append(errs, FieldError{File: path, Line: n.Line, Column: n.Column, Path: p, Msg: msg})
+	}
+
+	rules := make([]Rule, 0, len(f.Rules))
+	for i, d := range f.Rules {
+		r := Rule{
+			ID:          d.ID,
+			Version:     d.Version,
+			Description: d.Description,
+			Kind:        Kind(d.Kind),
+			Signal:      models.SignalType(d.Signal),
+			Severity:    d.Severity,
+			Enabled:     d.Enabled == nil || *d.Enabled,
+			Patterns:    d.Patterns,
+			Fields:      d.Fields,
+			Redact:      d.Redact,
+			Metric:      d.Metric,
+			Max:         d.Max,
+			File:        path,
+		}
+		if !ruleIDPattern.MatchString(r.ID) {
+			at(i, "id", -1, "must be 2-64 lowercase letters, digits, dots, hyphens or underscores")
+		}
+		if r.Version == 0 {
+			r.Version = 1
+		} else if r.Version < 0 {
+			at(i, "version", -1, "must be a positive integer")
+		}
+		if !signalTypes[r.Signal] {
+			at(i, "signal", -1, "unknown signal type")
+		}
+		if !severities[r.Severity] {
+			at(i, "severity", -1, "must be low, medium, high or critical")
+		}
+
+		switch r.Kind {
+		case KindPattern:
+			if len(r.Patterns) == 0 {
+				at(i, "patterns", -1, "required for pattern rules")
+			}
+			for j, p := range r.Patterns {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					at(i, "patterns", j, err.Error())
+					continue
+				}
+				r.compiled = append(r.compiled, re)
+			}
+			if len(r.Fields) == 0 {
+				r.Fields = AllFields
+			}
+			for j, field := range r.Fields {
+				if !slices.Contains(AllFields, field) {
+					at(i, "fields", j, fmt.Sprintf("unknown field %q", field))
+				}
+			}
+			if r.Metric != "" || r.Max != 0 {
+				at(i, "metric", -1, "metric and max apply to threshold rules only")
+			}
+		case KindThreshold:
+			if !metrics[r.Metric] {
+				at(i, "metric", -1, "must be one of "+strings.Join(sortedKeys(metrics), ", "))
+			}
+			if r.Max < 0 {
+				at(i, "max", -1, "must not be negative")
+			}
+			if len(r.Patterns) > 0 || len(r.Fields) > 0 {
+				at(i, "patterns", -1, "patterns and fields apply to pattern rules only")
+			}
+		default:
+			at(i, "kind", -1, "must be pattern or threshold")
+		}
+		rules = append(rules, r)
+	}
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	return rules, nil
+}
+
+// validateNode checks a mapping against a schema: unknown keys, missing
+// required keys and value types.
+func validateNode(file string, n *yaml.Node, schema map[string]fieldSpec, path string, errs *[]FieldError) {
+	at := func(n *yaml.Node, p, msg string) {
+		*errs = append(*errs, FieldError{File: file, Line: n.Line, Column: n.Column, Path: p, Msg: msg})
+	}
+	if n.Kind != yaml.MappingNode {
+		at(n, path, "expected an object")
+		return
+	}
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		p := key.Value
+		if path != "" {
+			p = path + "." + key.Value
+		}
+		spec, ok := schema[key.Value]
+		if !ok {
+			at(key, p, "unknown field")
+			continue
+		}
+		if seen[key.Value] {
+			at(key, p, "duplicate field")
+		}
+		seen[key.Value] = true
+
+		switch spec.kind {
+		case kindString:
+			if val.Kind != yaml.ScalarNode || val.Tag == "!!null" {
+				at(val, p, "expected a string")
+			} else if spec.required && strings.TrimSpace(val.Value) == "" {
+				at(val, p, "must not be empty")
+			}
+		case kindNumber:
+			if val.Kind != yaml.ScalarNode || (val.Tag != "!!int" && val.Tag != "!!float") {
+				at(val, p, "expected a number")
+			}
+		case kindBool:
+			if val.Kind != yaml.ScalarNode || val.Tag != "!!bool" {
+				at(val, p, "expected true or false")
+			}
+		case kindStrings:
+			if val.Kind != yaml.SequenceNode {
+				at(val, p, "expected a list of strings")
+				continue
+			}
+			for j, item := range val.Content {
+				if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+					at(item, fmt.Sprintf("%s[%d]", p, j), "expected a string")
+				}
+			}
+		case kindObjects:
+			if val.Kind != yaml.SequenceNode {
+				at(val, p, "expected a list")
+				continue
+			}
+			for j, item := range val.Content {
+				validateNode(file, item, spec.items, fmt.Sprintf("%s[%d]", p, j), errs)
+			}
+		}
+	}
+	for _, k := range sortedKeys(schema) {
+		if schema[k].required && !seen[k] {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			at(n, p, "required field missing")
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,281 @@
+// Package evidence assembles compliance evidence bundles: signed, immutable
+// archives of control status, policies, decision log samples, signal
+// statistics and assessment reports for one framework and reporting period.
+package evidence
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Archive member names for the manifest and its signature.
+const (
+	ManifestName  = "manifest.json"
+	SignatureName = "manifest.sig"
+)
+
+// ErrInvalidBundle is returned when an archive's contents or signature do
+// not verify.
+var ErrInvalidBundle = errors.New("invalid evidence bundle")
+
+// FileDigest records one archived file.
+type FileDigest struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Manifest describes a bundle. It is signed, and lists the digest of every
+// other file in the archive.
+type Manifest struct {
+	Framework string       `json:"framework"`
+	OrgID     string       `json:"org_id,omitempty"`
+	Period    Period       `json:"period"`
+	CreatedAt time.Time    `json:"created_at"`
+	Generator string       `json:"generator"`
+	Files     []FileDigest `json:"files"`
+	// Notes records evidence that could not be collected, so an auditor
+	// can tell a missing source from an empty one.
+	Notes []string `json:"notes,omitempty"`
+	// PublicKey is the base64 Ed25519 key the manifest was signed with.
+	PublicKey string `json:"public_key"`
+}
+
+// Bundle collects files before they are written as a signed archive.
+type Bundle struct {
+	Framework string
+	OrgID     string
+	Period    Period
+	Generator string
+
+	files map[string][]byte
+	notes []string
+}
+
+// NewBundle creates an empty bundle.
+func NewBundle(framework, orgID string, period Period, generator string) *Bundle {
+	return &Bundle{
+		Framework: framework,
+		OrgID:     orgID,
+		Period:    period,
+		Generator: generator,
+		files:     make(map[string][]byte),
+	}
+}
+
+// Add stores a file in the bundle, replacing any file at the same path.
+func (b *Bundle) Add(path string, data []byte) {
+	b.files[path] = data
+}
+
+// AddJSON stores v as indented JSON.
+func (b *Bundle) AddJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	b.Add(path, append(data, '\n'))
+	return nil
+}
+
+// Note records a gap in the collected evidence.
+func (b *Bundle) Note(format string, args ...any) {
+	b.notes = append(b.notes, fmt.Sprintf(format, args...))
+}
+
+// Files returns the paths in the bundle in sorted order.
+func (b *Bundle) Files() []string {
+	paths := make([]string, 0, len(b.files))
+	for p := range b.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write writes the bundle as a gzipped tar archive with a manifest signed
+// by key, and returns the manifest.
+func (b *Bundle) Write(w io.Writer, key ed25519.PrivateKey) (*Manifest, error) {
+	m := &Manifest{
+		Framework: b.Framework,
+		OrgID:     b.OrgID,
+		Period:    b.Period,
+		CreatedAt: time.Now().UTC(),
+		Generator: b.Generator,
+		Notes:     b.notes,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+	paths := b.Files()
+	for _, p := range paths {
+		sum := sha256.Sum256(b.files[p])
+		m.Files = append(m.Files, FileDigest{Path: p, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(b.files[p]))})
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifest))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o444, Size: int64(len(data)), ModTime: m.CreatedAt, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := add(ManifestName, manifest); err != nil {
+		return nil, err
+	}
+	if err := add(SignatureName, []byte(sig+"\n")); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		if err := add(p, b.files[p]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Verify checks an archive's signature and every file digest. When pub is
+// nil the key embedded in the manifest is used, which proves integrity but
+// not who signed it.
+func Verify(r io.Reader, pub ed25519.PublicKey) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	defer gz.Close()
+
+	var manifest, sig []byte
+	digests := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		switch hdr.Name {
+		case ManifestName:
+			manifest = data
+		case SignatureName:
+			sig = data
+		default:
+			sum := sha256.Sum256(data)
+			digests[hdr.Name] = hex.EncodeToString(sum[:])
+		}
+	}
+	if manifest == nil || sig == nil {
+		return nil, fmt.Errorf("%w: manifest or signature missing", ErrInvalidBundle)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return nil, fmt.Errorf("%w: decoding manifest: %v", ErrInvalidBundle, err)
+	}
+	if pub == nil {
+		raw, err := base64.StdEncoding.DecodeString(m.PublicKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: manifest public key is malformed", ErrInvalidBundle)
+		}
+		pub = raw
+	}
+	rawSig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+	if err != nil || !ed25519.Verify(pub, manifest, rawSig) {
+		return nil, fmt.Errorf("%w: manifest signature does not verify", ErrInvalidBundle)
+	}
+
+	for _, f := range m.Files {
+		got, ok := digests[f.Path]
+		if !ok {
+			return &m, fmt.Errorf("%w: %s is missing", ErrInvalidBundle, f.Path)
+		}
+		if got != f.SHA256 {
+			return &m, fmt.Errorf("%w: %s digest mismatch", ErrInvalidBundle, f.Path)
+		}
+		delete(digests, f.Path)
+	}
+	if len(digests) > 0 {
+		extra := make([]string, 0, len(digests))
+		for p := range digests {
+			extra = append(extra, p)
+		}
+		sort.Strings(extra)
+		return &m, fmt.Errorf("%w: %s not listed in the manifest", ErrInvalidBundle, strings.Join(extra, ", "))
+	}
+	return &m, nil
+}
+
+// LoadSigningKey reads a PKCS #8 PEM Ed25519 private key, as written by
+// `openssl genpkey -algorithm ed25519`.
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key: %w", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return priv, nil
+}
+
+// LoadPublicKey reads a PKIX PEM Ed25519 public key.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("public key %s is not PEM encoded", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return pub, nil
+}
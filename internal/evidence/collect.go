@@ -0,0 +1,210 @@
+package evidence
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// maxPolicyFileBytes bounds a single policy file copied into a bundle.
+const maxPolicyFileBytes = 10 << 20
+
+// policyExtensions are the policy files copied from the policy directory.
+var policyExtensions = map[string]bool{".rego": true, ".yaml": true, ".yml": true, ".json": true, ".gz": true}
+
+// Sources are where evidence is collected from. Every source except the
+// analyzer is optional; missing sources are noted in the manifest.
+type Sources struct {
+	Analyzer *controls.GapAnalyzer
+	// Analysis selects the implemented controls behind the control status.
+	// Its target framework is the bundle's framework.
+	Analysis controls.AnalysisInput
+	// PolicyDir holds the policies in force, e.g. .rego files or a bundle.
+	PolicyDir string
+	// AuditLog is the hash-chained decision log to sample and verify.
+	AuditLog string
+	// DecisionSamples bounds the decisions sampled from the period.
+	DecisionSamples int
+	// Metrics provides signal statistics.
+	Metrics repository.MetricsRepository
+}
+
+// Collect assembles an evidence bundle for framework over period.
+func Collect(ctx context.Context, framework, orgID string, period Period, generator string, src Sources) (*Bundle, error) {
+	if src.Analyzer == nil {
+		return nil, errors.New("gap analyzer is required")
+	}
+	b := NewBundle(framework, orgID, period, generator)
+
+	input := src.Analysis
+	input.TargetFramework = framework
+	analysis, err := src.Analyzer.RunAnalysis(ctx, &input)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s: %w", framework, err)
+	}
+	analysis.Input = &input
+	if err := b.AddJSON("controls/status.json", analysis); err != nil {
+		return nil, err
+	}
+	var report bytes.Buffer
+	src.Analyzer.PrintReport(&report, analysis)
+	b.Add("reports/gap-analysis.txt", report.Bytes())
+
+	if err := collectPolicies(b, src.PolicyDir); err != nil {
+		return nil, err
+	}
+	if err := collectDecisions(b, src.AuditLog, orgID, period, src.DecisionSamples); err != nil {
+		return nil, err
+	}
+	if err := collectSignals(ctx, b, src.Metrics, orgID, period); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func collectPolicies(b *Bundle, dir string) error {
+	if dir == "" {
+		b.Note("no policy directory configured; policies not included")
+		return nil
+	}
+	count := 0
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !policyExtensions[filepath.Ext(p)] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxPolicyFileBytes {
+			b.Note("policy file %s exceeds %d bytes and was not included", filepath.ToSlash(rel), maxPolicyFileBytes)
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		b.Add(path.Join("policies", filepath.ToSlash(rel)), data)
+		count++
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		b.Note("policy directory %s does not exist; policies not included", dir)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("collecting policies: %w", err)
+	}
+	if count == 0 {
+		b.Note("policy directory %s contains no policy files", dir)
+	}
+	return nil
+}
+
+// collectDecisions verifies the audit log chain and samples the period's
+// decisions evenly, so the sample spans the whole period. An empty orgID
+// samples every organization.
+func collectDecisions(b *Bundle, logPath, orgID string, period Period, limit int) error {
+	if logPath == "" {
+		b.Note("no audit log configured; decision samples not included")
+		return nil
+	}
+	data, err := os.ReadFile(logPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		b.Note("audit log %s does not exist; decision samples not included", logPath)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+
+	report, err := audit.Verify(bytes.NewReader(data), nil)
+	if err != nil {
+		return err
+	}
+	if err := b.AddJSON("decisions/chain-verification.json", report); err != nil {
+		return err
+	}
+	if !report.OK() {
+		b.Note("audit log failed chain verification with %d problem(s)", len(report.Problems))
+	}
+
+	var inPeriod [][]byte
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		var e audit.Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil || e.Kind != audit.KindDecision || !period.Contains(e.Time) ||
+			orgID != "" && e.OrgID != orgID {
+			continue
+		}
+		inPeriod = append(inPeriod, append([]byte(nil), sc.Bytes()...))
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+
+	sample := inPeriod
+	if limit > 0 && len(inPeriod) > limit {
+		sample = make([][]byte, 0, limit)
+		for i := 0; i < limit; i++ {
+			sample = append(sample, inPeriod[i*len(inPeriod)/limit])
+		}
+	}
+	var out bytes.Buffer
+	for _, line := range sample {
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	b.Add("decisions/sample.jsonl", out.Bytes())
+	return b.AddJSON("decisions/summary.json", map[string]any{
+		"decisions_in_period": len(inPeriod),
+		"sampled":             len(sample),
+		"chain_head":          report.Head,
+	})
+}
+
+func collectSignals(ctx context.Context, b *Bundle, metrics repository.MetricsRepository, orgID string, period Period) error {
+	if metrics == nil {
+		b.Note("no metrics store configured; signal statistics not included")
+		return nil
+	}
+	points, err := metrics.Rollup(ctx, &repository.MetricsQuery{
+		OrgID:    orgID,
+		Metric:   repository.MetricSignals,
+		From:     period.Start,
+		To:       period.End,
+		Interval: "week",
+		GroupBy:  []string{"type", "severity"},
+	})
+	if err != nil {
+		return fmt.Errorf("querying signal statistics: %w", err)
+	}
+	totals := make(map[string]float64)
+	for _, p := range points {
+		totals[strings.Join([]string{p.Group["type"], p.Group["severity"]}, "/")] += p.Value
+	}
+	return b.AddJSON("signals/stats.json", map[string]any{
+		"by_type_severity": totals,
+		"weekly":           points,
+	})
+}
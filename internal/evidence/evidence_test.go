@@ -0,0 +1,152 @@
+package evidence_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/audit"
+	"github.com/agentguard/agentguard/internal/controls"
+	"github.com/agentguard/agentguard/internal/evidence"
+)
+
+func TestParsePeriod(t *testing.T) {
+	for in, want := range map[string][2]string{
+		"2024Q4":  {"2024-10-01", "2025-01-01"},
+		"2024-Q1": {"2024-01-01", "2024-04-01"},
+		"2024H2":  {"2024-07-01", "2025-01-01"},
+		"2024-11": {"2024-11-01", "2024-12-01"},
+		"2024":    {"2024-01-01", "2025-01-01"},
+	} {
+		p, err := evidence.ParsePeriod(in)
+		if err != nil {
+			t.Errorf("ParsePeriod(%q): %v", in, err)
+			continue
+		}
+		if got := [2]string{p.Start.Format("2006-01-02"), p.End.Format("2006-01-02")}; got != want {
+			t.Errorf("ParsePeriod(%q) = %v, want %v", in, got, want)
+		}
+	}
+	for _, in := range []string{"2024Q5", "2024-13", "Q4", ""} {
+		if _, err := evidence.ParsePeriod(in); err == nil {
+			t.Errorf("ParsePeriod(%q) accepted", in)
+		}
+	}
+}
+
+func TestCollectAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "audit.jsonl")
+	l, err := audit.Open(logPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := l.Append(audit.KindDecision, "acme", "agent-1", map[string]any{"allow": true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	analyzer, err := controls.NewGapAnalyzer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	period, _ := evidence.ParsePeriod(time.Now().UTC().Format("2006"))
+	b, err := evidence.Collect(context.Background(), "iso-42001", "acme", period, "test", evidence.Sources{
+		Analyzer:        analyzer,
+		Analysis:        controls.AnalysisInput{ImplementedControls: []string{"ISO42001-4.1"}},
+		PolicyDir:       filepath.Join(dir, "missing"),
+		AuditLog:        logPath,
+		DecisionSamples: 5,
+	})
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	want := []string{
+		"controls/status.json",
+		"decisions/chain-verification.json",
+		"decisions/sample.jsonl",
+		"decisions/summary.json",
+		"reports/gap-analysis.txt",
+	}
+	if got := b.Files(); len(got) != len(want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+	var archive bytes.Buffer
+	m, err := b.Write(&archive, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Notes) != 2 {
+		t.Errorf("notes = %q, want missing policies and metrics", m.Notes)
+	}
+	if _, err := evidence.Verify(bytes.NewReader(archive.Bytes()), pub); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := evidence.Verify(bytes.NewReader(archive.Bytes()), other); !errors.Is(err, evidence.ErrInvalidBundle) {
+		t.Errorf("Verify with wrong key: err = %v", err)
+	}
+	tampered := rewrite(t, archive.Bytes(), "decisions/sample.jsonl", []byte("{}\n"))
+	if _, err := evidence.Verify(bytes.NewReader(tampered), pub); !errors.Is(err, evidence.ErrInvalidBundle) {
+		t.Errorf("Verify of modified file: err = %v", err)
+	}
+}
+
+// rewrite returns a copy of an archive with one member's content replaced.
+func rewrite(t *testing.T, archive []byte, name string, data []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var out bytes.Buffer
+	zw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(zw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(tr)
+		if hdr.Name == name {
+			content = data
+		}
+		hdr.Size = int64(len(content))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(content)
+	}
+	tw.Close()
+	zw.Close()
+	return out.Bytes()
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	if _, err := evidence.LoadSigningKey(filepath.Join(t.TempDir(), "none.pem")); err == nil {
+		t.Error("missing key accepted")
+	}
+	path := filepath.Join(t.TempDir(), "bad.pem")
+	os.WriteFile(path, []byte("not pem"), 0o600)
+	if _, err := evidence.LoadSigningKey(path); err == nil {
+		t.Error("non-PEM key accepted")
+	}
+}
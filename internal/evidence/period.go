@@ -0,0 +1,60 @@
+package evidence
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Period is the half-open reporting interval [Start, End) an evidence bundle
+// covers.
+type Period struct {
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether t falls within the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+var (
+	quarterPattern = regexp.MustCompile(`^(\d{4})-?Q([1-4])$`)
+	halfPattern    = regexp.MustCompile(`^(\d{4})-?H([12])$`)
+	monthPattern   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+	yearPattern    = regexp.MustCompile(`^(\d{4})$`)
+)
+
+// ParsePeriod parses a reporting period in UTC: a quarter (2024Q4), half
+// year (2024H2), month (2024-11) or year (2024).
+func ParsePeriod(s string) (Period, error) {
+	start := func(year string, month int) time.Time {
+		y, _ := strconv.Atoi(year)
+		return time.Date(y, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if m := quarterPattern.FindStringSubmatch(s); m != nil {
+		q, _ := strconv.Atoi(m[2])
+		from := start(m[1], 3*(q-1)+1)
+		return Period{Label: m[1] + "Q" + m[2], Start: from, End: from.AddDate(0, 3, 0)}, nil
+	}
+	if m := halfPattern.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[2])
+		from := start(m[1], 6*(h-1)+1)
+		return Period{Label: m[1] + "H" + m[2], Start: from, End: from.AddDate(0, 6, 0)}, nil
+	}
+	if m := monthPattern.FindStringSubmatch(s); m != nil {
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return Period{}, fmt.Errorf("invalid month in period %q", s)
+		}
+		from := start(m[1], month)
+		return Period{Label: s, Start: from, End: from.AddDate(0, 1, 0)}, nil
+	}
+	if m := yearPattern.FindStringSubmatch(s); m != nil {
+		from := start(m[1], 1)
+		return Period{Label: s, Start: from, End: from.AddDate(1, 0, 0)}, nil
+	}
+	return Period{}, fmt.Errorf("invalid period %q: use a quarter (2024Q4), half (2024H2), month (2024-11) or year (2024)", s)
+}
@@ -0,0 +1,306 @@
+// Package fingerprint builds a behavioral fingerprint of each agent session
+// from the style of its prompts, the cadence of its tool calls and the user
+// driving it, and flags abrupt changes mid-session. A prompt that suddenly
+// reads nothing like the ones before it, a burst of tool calls far faster
+// than the session's rhythm, or a different user taking over a session are
+// consistent with prompt hijacking or account takeover.
+package fingerprint
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/llm"
+)
+
+// Kind identifies what changed in a session.
+type Kind string
+
+const (
+	KindPromptStyle Kind = "prompt_style"
+	KindToolCadence Kind = "tool_cadence"
+	KindUserChange  Kind = "user_change"
+)
+
+// Sensitivity sets how large a change is flagged.
+type Sensitivity struct {
+	// MinStyleSimilarity flags a prompt whose cosine similarity to the
+	// session's prompt style falls below it. Zero disables the check.
+	MinStyleSimilarity float64 `json:"min_style_similarity"`
+	// CadenceFactor flags tool calls arriving this many times faster than
+	// the session's baseline rate. Zero disables the check.
+	CadenceFactor float64 `json:"cadence_factor"`
+	// Block denies the call that changed the fingerprint instead of only
+	// flagging it.
+	Block bool `json:"block,omitempty"`
+}
+
+// Config configures a Tracker.
+type Config struct {
+	// Embedder embeds prompts for style comparison. Defaults to a local
+	// hash embedder, which compares vocabulary and needs no service.
+	Embedder llm.Embedder
+	// Baseline is how many prompts and tool call intervals establish a
+	// session's fingerprint before changes are flagged. Defaults to 3.
+	Baseline int
+	// IdleTimeout forgets sessions not seen for this long. Defaults to 30
+	// minutes.
+	IdleTimeout time.Duration
+	// MaxSessions bounds memory; the least recently seen sessions are
+	// evicted first. Defaults to 10000.
+	MaxSessions int
+	// Default applies to agents whose risk level has no entry in
+	// RiskLevels.
+	Default Sensitivity
+	// RiskLevels sets the sensitivity by agent risk level, e.g. tighter
+	// thresholds and blocking for critical agents.
+	RiskLevels map[string]Sensitivity
+}
+
+// Observation is one pre-invoke call in a session.
+type Observation struct {
+	OrgID     string
+	AgentID   string
+	SessionID string
+	UserID    string
+	RiskLevel string
+	// Prompt is the text of the latest prompt, when the caller sent it.
+	Prompt string
+	// Tool is the tool being called, when there is one.
+	Tool string
+	At   time.Time
+}
+
+// Finding reports an abrupt change in a session.
+type Finding struct {
+	Kind   Kind   `json:"kind"`
+	Reason string `json:"reason"`
+	// Score is the measured value: the style similarity or the cadence
+	// speedup. Zero for a user change.
+	Score     float64 `json:"score,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Block     bool    `json:"block"`
+}
+
+type session struct {
+	key      string
+	user     string
+	lastSeen time.Time
+
+	style   []float32 // sum of the baseline prompt embeddings
+	prompts int
+
+	lastCall     time.Time
+	intervals    int
+	meanInterval float64 // seconds, over the baseline intervals
+	recent       float64 // moving average of recent intervals, seconds
+
+	flagged map[Kind]bool
+}
+
+// Tracker keeps session fingerprints. It is safe for concurrent use.
+type Tracker struct {
+	cfg Config
+
+	mu       sync.Mutex
+	order    *list.List // of *session, least recently observed first
+	sessions map[string]*list.Element
+}
+
+// NewTracker creates a tracker.
+func NewTracker(cfg Config) *Tracker {
+	if cfg.Embedder == nil {
+		cfg.Embedder = llm.NewHashEmbedder(0)
+	}
+	if cfg.Baseline <= 0 {
+		cfg.Baseline = 3
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 30 * time.Minute
+	}
+	if cfg.MaxSessions <= 0 {
+		cfg.MaxSessions = 10000
+	}
+	return &Tracker{cfg: cfg, order: list.New(), sessions: make(map[string]*list.Element)}
+}
+
+// Sensitivity returns the sensitivity for an agent risk level.
+func (t *Tracker) Sensitivity(riskLevel string) Sensitivity {
+	if s, ok := t.cfg.RiskLevels[strings.ToLower(riskLevel)]; ok {
+		return s
+	}
+	return t.cfg.Default
+}
+
+// Observe adds a call to its session's fingerprint and returns the changes
+// it shows. Each kind of change is reported once per session. Observations
+// without a session ID are ignored.
+func (t *Tracker) Observe(ctx context.Context, obs Observation) ([]Finding, error) {
+	if obs.SessionID == "" {
+		return nil, nil
+	}
+	if obs.At.IsZero() {
+		obs.At = time.Now()
+	}
+	sens := t.Sensitivity(obs.RiskLevel)
+
+	// Embed outside the lock; the embedder may call a service.
+	var vec []float32
+	if obs.Prompt != "" && sens.MinStyleSimilarity > 0 {
+		vecs, err := t.cfg.Embedder.Embed(ctx, []string{obs.Prompt})
+		if err != nil {
+			return nil, fmt.Errorf("embedding prompt: %w", err)
+		}
+		// A prompt with no terms to compare says nothing about style.
+		if cosine(vecs[0], vecs[0]) > 0 {
+			vec = vecs[0]
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := obs.OrgID + "\x00" + obs.AgentID + "\x00" + obs.SessionID
+	var s *session
+	if el, ok := t.sessions[key]; ok {
+		s = el.Value.(*session)
+		if obs.At.Sub(s.lastSeen) > t.cfg.IdleTimeout {
+			t.remove(el)
+			s = nil
+		} else {
+			t.order.MoveToBack(el)
+		}
+	}
+	if s == nil {
+		t.evict(obs.At)
+		s = &session{key: key, user: obs.UserID, flagged: make(map[Kind]bool)}
+		t.sessions[key] = t.order.PushBack(s)
+	}
+	if obs.At.After(s.lastSeen) {
+		s.lastSeen = obs.At
+	}
+
+	var findings []Finding
+	flag := func(f Finding) {
+		if s.flagged[f.Kind] {
+			return
+		}
+		s.flagged[f.Kind] = true
+		f.Block = sens.Block
+		findings = append(findings, f)
+	}
+
+	if obs.UserID != "" {
+		if s.user == "" {
+			s.user = obs.UserID
+		} else if obs.UserID != s.user {
+			flag(Finding{Kind: KindUserChange, Reason: "session continued by a different user"})
+		}
+	}
+	if vec != nil {
+		if sim, ok := s.observePrompt(vec, t.cfg.Baseline, sens.MinStyleSimilarity); !ok {
+			flag(Finding{
+				Kind:      KindPromptStyle,
+				Reason:    fmt.Sprintf("prompt style similarity %.2f is below the session's threshold %.2f", sim, sens.MinStyleSimilarity),
+				Score:     sim,
+				Threshold: sens.MinStyleSimilarity,
+			})
+		}
+	}
+	if obs.Tool != "" && sens.CadenceFactor > 0 {
+		if speedup, ok := s.observeCall(obs.At, t.cfg.Baseline, sens.CadenceFactor); !ok {
+			flag(Finding{
+				Kind:      KindToolCadence,
+				Reason:    fmt.Sprintf("tool calls arriving %.1fx faster than the session's baseline", speedup),
+				Score:     speedup,
+				Threshold: sens.CadenceFactor,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// observePrompt compares a prompt embedding with the session's style once
+// the baseline is established, and otherwise adds it to the baseline. It
+// returns the similarity and whether the prompt fits the style.
+func (s *session) observePrompt(vec []float32, baseline int, minSimilarity float64) (float64, bool) {
+	if s.prompts >= baseline {
+		sim := cosine(vec, s.style)
+		if sim < minSimilarity {
+			return sim, false
+		}
+		return sim, true
+	}
+	if s.style == nil {
+		s.style = make([]float32, len(vec))
+	}
+	for i := range vec {
+		s.style[i] += vec[i]
+	}
+	s.prompts++
+	return 1, true
+}
+
+// observeCall folds the interval since the previous tool call into the
+// session's cadence. Once the baseline is established, it returns how many
+// times faster recent calls arrive than the baseline and whether that is
+// within factor. Recent intervals are averaged so a single quick call does
+// not trip the check; a sustained burst does.
+func (s *session) observeCall(at time.Time, baseline int, factor float64) (float64, bool) {
+	prev := s.lastCall
+	s.lastCall = at
+	if prev.IsZero() || at.Before(prev) {
+		return 0, true
+	}
+	interval := at.Sub(prev).Seconds()
+
+	if s.intervals < baseline {
+		s.intervals++
+		s.meanInterval += (interval - s.meanInterval) / float64(s.intervals)
+		s.recent = s.meanInterval
+		return 0, true
+	}
+	s.recent = (s.recent + interval) / 2
+	if s.meanInterval <= 0 {
+		return 0, true
+	}
+	speedup := s.meanInterval / math.Max(s.recent, 0.001)
+	return speedup, speedup < factor
+}
+
+// evict drops the least recently observed sessions while they are idle or
+// the tracker is full. Callers hold mu.
+func (t *Tracker) evict(now time.Time) {
+	for el := t.order.Front(); el != nil; el = t.order.Front() {
+		if now.Sub(el.Value.(*session).lastSeen) <= t.cfg.IdleTimeout && t.order.Len() < t.cfg.MaxSessions {
+			return
+		}
+		t.remove(el)
+	}
+}
+
+func (t *Tracker) remove(el *list.Element) {
+	delete(t.sessions, el.Value.(*session).key)
+	t.order.Remove(el)
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,174 @@
+// Package hashing computes the server-side content hashes stored for
+// prompts and tool payloads. Content is canonicalized so equivalent inputs
+// hash alike, then hashed with a per-organization salt as the key, so hashes
+// are comparable within an organization but cannot be reversed with
+// precomputed tables, even for short inputs.
+package hashing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"lukechampine.com/blake3"
+)
+
+// Algorithm is a keyed hash algorithm.
+type Algorithm string
+
+const (
+	// SHA256 is HMAC-SHA-256 keyed with the organization salt.
+	SHA256 Algorithm = "sha256"
+	// BLAKE3 is BLAKE3 in keyed mode with the organization salt.
+	BLAKE3 Algorithm = "blake3"
+)
+
+// Hash prefixes name the construction so salted hashes are never confused
+// with plain content digests such as payload storage keys.
+var prefixes = map[Algorithm]string{
+	SHA256: "hmac-sha256:",
+	BLAKE3: "blake3-keyed:",
+}
+
+// minSecretBytes is the minimum length of the secret salts are derived from.
+const minSecretBytes = 16
+
+// Config configures a Hasher.
+type Config struct {
+	// Algorithm defaults to SHA256.
+	Algorithm Algorithm
+	// Secret derives each organization's salt. At least 16 bytes.
+	Secret []byte
+	// OrgSalts overrides the derived salt for specific organizations, e.g.
+	// to keep hashes stable across a secret rotation.
+	OrgSalts map[string]string
+}
+
+// Hasher computes canonical salted hashes. It is safe for concurrent use.
+type Hasher struct {
+	alg     Algorithm
+	secret  []byte
+	salts   map[string][]byte
+	pattern *regexp.Regexp
+}
+
+// New creates a Hasher.
+func New(cfg Config) (*Hasher, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = SHA256
+	}
+	prefix, ok := prefixes[cfg.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash algorithm %q", cfg.Algorithm)
+	}
+	if len(cfg.Secret) < minSecretBytes {
+		return nil, fmt.Errorf("hash salt secret must be at least %d bytes", minSecretBytes)
+	}
+	h := &Hasher{
+		alg:     cfg.Algorithm,
+		secret:  cfg.Secret,
+		salts:   make(map[string][]byte, len(cfg.OrgSalts)),
+		pattern: regexp.MustCompile(`^` + regexp.QuoteMeta(prefix) + `[0-9a-f]{64}$`),
+	}
+	for org, salt := range cfg.OrgSalts {
+		if len(salt) < minSecretBytes {
+			return nil, fmt.Errorf("salt for organization %q must be at least %d bytes", org, minSecretBytes)
+		}
+		// Stretch to a 32-byte key, as BLAKE3's keyed mode requires.
+		sum := sha256.Sum256([]byte(salt))
+		h.salts[org] = sum[:]
+	}
+	return h, nil
+}
+
+// Algorithm returns the configured algorithm.
+func (h *Hasher) Algorithm() Algorithm { return h.alg }
+
+// salt returns an organization's 32-byte salt.
+func (h *Hasher) salt(orgID string) []byte {
+	if s, ok := h.salts[orgID]; ok {
+		return s
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte("agentguard hash salt\x00"))
+	mac.Write([]byte(orgID))
+	return mac.Sum(nil)
+}
+
+// Sum returns the salted hash of v's canonical form in an organization.
+func (h *Hasher) Sum(orgID string, v any) (string, error) {
+	data, err := Canonical(v)
+	if err != nil {
+		return "", err
+	}
+	key := h.salt(orgID)
+	var sum []byte
+	switch h.alg {
+	case BLAKE3:
+		b := blake3.New(32, key)
+		b.Write(data)
+		sum = b.Sum(nil)
+	default:
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		sum = mac.Sum(nil)
+	}
+	return prefixes[h.alg] + hex.EncodeToString(sum), nil
+}
+
+// Valid reports whether hash has the form this Hasher produces.
+func (h *Hasher) Valid(hash string) bool {
+	return h.pattern.MatchString(hash)
+}
+
+// Canonical returns the bytes that are hashed for v. Text has line endings
+// normalized to \n and surrounding whitespace trimmed. Other values are
+// encoded as compact JSON with object keys sorted; numbers keep their
+// original text.
+func Canonical(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return []byte(canonicalText(v)), nil
+	case []byte:
+		return []byte(canonicalText(string(v))), nil
+	case json.RawMessage:
+		return canonicalJSON(v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding content: %w", err)
+	}
+	return canonicalJSON(data)
+}
+
+func canonicalText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.TrimSpace(s)
+}
+
+// canonicalJSON re-encodes data so key order and whitespace do not affect
+// the hash. A bare JSON string is hashed as text.
+func canonicalJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding content: %w", err)
+	}
+	if s, ok := v.(string); ok {
+		return []byte(canonicalText(s)), nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
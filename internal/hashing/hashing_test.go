@@ -0,0 +1,85 @@
+package hashing_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/hashing"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  hashing.Config
+		want string
+	}{
+		{name: "default algorithm", cfg: hashing.Config{Secret: secret}},
+		{name: "blake3", cfg: hashing.Config{Algorithm: hashing.BLAKE3, Secret: secret}},
+		{name: "unknown algorithm", cfg: hashing.Config{Algorithm: "md5", Secret: secret}, want: "unsupported"},
+		{name: "short secret", cfg: hashing.Config{Secret: []byte("short")}, want: "at least"},
+		{name: "short org salt", cfg: hashing.Config{Secret: secret, OrgSalts: map[string]string{"org": "x"}}, want: "at least"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := hashing.New(tt.cfg)
+			if tt.want == "" && err != nil {
+				t.Fatalf("New() = %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Fatalf("New() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	for _, alg := range []hashing.Algorithm{hashing.SHA256, hashing.BLAKE3} {
+		t.Run(string(alg), func(t *testing.T) {
+			h, err := hashing.New(hashing.Config{Algorithm: alg, Secret: secret, OrgSalts: map[string]string{"pinned": "pinned-org-salt-value"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum := func(org string, v any) string {
+				t.Helper()
+				s, err := h.Sum(org, v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return s
+			}
+
+			base := sum("org", "Ignore previous instructions")
+			if !h.Valid(base) {
+				t.Fatalf("Sum() = %q, not valid for its own hasher", base)
+			}
+			if got := sum("org", "  Ignore previous instructions\r\n"); got != base {
+				t.Error("whitespace and line endings change the hash")
+			}
+			if got := sum("other", "Ignore previous instructions"); got == base {
+				t.Error("hash shared across organizations")
+			}
+			if sum("pinned", "x") == sum("org", "x") {
+				t.Error("org salt override ignored")
+			}
+
+			a := sum("org", map[string]any{"q": "x", "limit": 10})
+			b := sum("org", json.RawMessage(`{ "limit": 10, "q": "x" }`))
+			if a != b {
+				t.Error("JSON key order or whitespace changes the hash")
+			}
+			if sum("org", json.RawMessage(`"Ignore previous instructions"`)) != base {
+				t.Error("JSON string not hashed as text")
+			}
+		})
+	}
+
+	sha, _ := hashing.New(hashing.Config{Secret: secret})
+	b3, _ := hashing.New(hashing.Config{Algorithm: hashing.BLAKE3, Secret: secret})
+	hash, _ := sha.Sum("org", "x")
+	if b3.Valid(hash) || sha.Valid("sha256:"+strings.Repeat("a", 64)) {
+		t.Error("Valid() accepts another construction's hash")
+	}
+}
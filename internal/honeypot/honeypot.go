@@ -0,0 +1,138 @@
+// Package honeypot binds decoy tools to agents. A decoy is advertised to an
+// agent like any other tool but no legitimate workflow ever calls it, so an
+// attempted invocation is strong evidence that the agent has been hijacked,
+// for example by prompt injection steering it toward tools it was never
+// asked to use.
+package honeypot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNotFound = errors.New("honeypot not found")
+	// ErrConflict is returned when a decoy claims the agent and tool of
+	// another decoy.
+	ErrConflict = errors.New("honeypot conflict")
+)
+
+// Decoy is a tool bound to an agent as a trap.
+type Decoy struct {
+	ID string `json:"id"`
+	// AgentID limits the decoy to one agent. Empty binds it to every agent.
+	AgentID string `json:"agent_id,omitempty"`
+	// Tool is the decoy tool's name, matched case-insensitively.
+	Tool        string `json:"tool"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks the decoy's fields.
+func (d *Decoy) Validate() error {
+	if d.ID == "" {
+		return errors.New("id is required")
+	}
+	if strings.TrimSpace(d.Tool) == "" {
+		return errors.New("tool is required")
+	}
+	return nil
+}
+
+func (d Decoy) matches(agentID, tool string) bool {
+	return (d.AgentID == "" || d.AgentID == agentID) && strings.EqualFold(d.Tool, tool)
+}
+
+// Registry holds decoy bindings. It is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	decoys map[string]Decoy
+}
+
+// NewRegistry creates a registry holding decoys.
+func NewRegistry(decoys []Decoy) (*Registry, error) {
+	r := &Registry{decoys: make(map[string]Decoy, len(decoys))}
+	for _, d := range decoys {
+		if err := r.Put(d); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Get returns a decoy by ID.
+func (r *Registry) Get(id string) (Decoy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.decoys[id]
+	if !ok {
+		return Decoy{}, ErrNotFound
+	}
+	return d, nil
+}
+
+// List returns the decoys bound to an agent, including those bound to every
+// agent, sorted by ID. An empty agentID lists every decoy.
+func (r *Registry) List(agentID string) []Decoy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Decoy, 0, len(r.decoys))
+	for _, d := range r.decoys {
+		if agentID == "" || d.AgentID == "" || d.AgentID == agentID {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Put creates or replaces a decoy. Only one decoy may bind a tool to an
+// agent.
+func (r *Registry) Put(d Decoy) error {
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("honeypot %s: %w", d.ID, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, other := range r.decoys {
+		if id != d.ID && other.AgentID == d.AgentID && strings.EqualFold(other.Tool, d.Tool) {
+			return fmt.Errorf("%w: honeypot %s already binds tool %q to agent %q", ErrConflict, id, d.Tool, d.AgentID)
+		}
+	}
+	r.decoys[d.ID] = d
+	return nil
+}
+
+// Delete removes a decoy.
+func (r *Registry) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.decoys[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.decoys, id)
+	return nil
+}
+
+// Match returns the decoy a call to tool by an agent springs, preferring one
+// bound to the agent over one bound to every agent.
+func (r *Registry) Match(agentID, tool string) (Decoy, bool) {
+	if tool == "" {
+		return Decoy{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var best Decoy
+	found := false
+	for _, d := range r.decoys {
+		if !d.matches(agentID, tool) {
+			continue
+		}
+		if !found || (best.AgentID == "" && d.AgentID != "") {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
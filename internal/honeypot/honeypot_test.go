@@ -0,0 +1,76 @@
+package honeypot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/honeypot"
+)
+
+func TestMatch(t *testing.T) {
+	reg, err := honeypot.NewRegistry([]honeypot.Decoy{
+		{ID: "export-all", Tool: "export_all_customers"},
+		{ID: "billing-admin", AgentID: "billing-bot", Tool: "grant_admin"},
+		{ID: "billing-export", AgentID: "billing-bot", Tool: "Export_All_Customers"},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		agent  string
+		tool   string
+		wantID string
+	}{
+		{"decoy for every agent", "support-bot", "export_all_customers", "export-all"},
+		{"agent binding preferred", "billing-bot", "EXPORT_ALL_CUSTOMERS", "billing-export"},
+		{"agent decoy", "billing-bot", "grant_admin", "billing-admin"},
+		{"other agent's decoy", "support-bot", "grant_admin", ""},
+		{"ordinary tool", "billing-bot", "lookup_invoice", ""},
+		{"no tool", "billing-bot", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := reg.Match(tt.agent, tt.tool)
+			if ok != (tt.wantID != "") || d.ID != tt.wantID {
+				t.Errorf("Match(%q, %q) = %q, %v, want %q", tt.agent, tt.tool, d.ID, ok, tt.wantID)
+			}
+		})
+	}
+
+	if got := reg.List("support-bot"); len(got) != 1 || got[0].ID != "export-all" {
+		t.Errorf("List(support-bot) = %+v, want export-all only", got)
+	}
+	if got := reg.List(""); len(got) != 3 {
+		t.Errorf("List() = %d decoys, want 3", len(got))
+	}
+}
+
+func TestPut(t *testing.T) {
+	reg, err := honeypot.NewRegistry(nil)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	if err := reg.Put(honeypot.Decoy{ID: "a", AgentID: "bot", Tool: "wipe_db"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := reg.Put(honeypot.Decoy{ID: "b", AgentID: "bot", Tool: "WIPE_DB"}); !errors.Is(err, honeypot.ErrConflict) {
+		t.Errorf("duplicate binding: err = %v, want ErrConflict", err)
+	}
+	if err := reg.Put(honeypot.Decoy{ID: "c"}); err == nil {
+		t.Error("expected an error for a decoy without a tool")
+	}
+	if err := reg.Put(honeypot.Decoy{ID: "a", AgentID: "bot", Tool: "drop_tables"}); err != nil {
+		t.Errorf("replacing a decoy: %v", err)
+	}
+	if _, ok := reg.Match("bot", "wipe_db"); ok {
+		t.Error("replaced decoy still matches")
+	}
+	if err := reg.Delete("a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := reg.Delete("a"); !errors.Is(err, honeypot.ErrNotFound) {
+		t.Errorf("second delete: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,175 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Attribute keys set when attributes are removed or shortened.
+const (
+	// reservedPrefix marks attributes written by AgentGuard itself. Client
+	// values under it are discarded so annotations cannot be spoofed.
+	reservedPrefix     = "agentguard."
+	AttrTruncatedKeys  = "agentguard.truncated_keys"
+	AttrDroppedKeys    = "agentguard.dropped_keys"
+	truncationMarker   = "…[truncated]"
+	oversizeMarkerText = "[dropped: %d bytes exceeds %d byte limit]"
+)
+
+// DefaultAttributeDeny lists attribute key patterns dropped unless the
+// configuration overrides it: credentials and raw prompt/completion content,
+// which is tracked by hash instead.
+var DefaultAttributeDeny = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*api_key*",
+	"*apikey*",
+	"*authorization*",
+	"*cookie*",
+	"*private_key*",
+	"*access_token*",
+	"*refresh_token*",
+	"gen_ai.prompt*",
+	"gen_ai.completion*",
+	"llm.prompts*",
+	"llm.completions*",
+}
+
+// AttributePolicy filters and bounds span and event attributes.
+type AttributePolicy struct {
+	// Allow lists key patterns (path.Match syntax, case-insensitive) that
+	// may be stored. Empty allows every key not denied.
+	Allow []string
+	// Deny lists key patterns that are always dropped. Deny wins over Allow.
+	Deny []string
+	// MaxValueBytes caps one attribute's encoded size. Longer strings are
+	// truncated; other values are replaced with a marker. Zero disables.
+	MaxValueBytes int
+	// MaxSpanBytes caps the total encoded size of a span's attributes. Keys
+	// beyond the cap, in sorted order, are dropped. Zero disables.
+	MaxSpanBytes int
+}
+
+func (ap *AttributePolicy) permitted(key string) bool {
+	k := strings.ToLower(key)
+	if strings.HasPrefix(k, reservedPrefix) {
+		return false
+	}
+	for _, pattern := range ap.Deny {
+		if ok, _ := path.Match(strings.ToLower(pattern), k); ok {
+			return false
+		}
+	}
+	if len(ap.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range ap.Allow {
+		if ok, _ := path.Match(strings.ToLower(pattern), k); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAttributes applies the attribute policy to every span and span event.
+// Size caps are skipped when capSizes is false; allow/deny still applies.
+func (p *Pipeline) filterAttributes(t *models.AgentTrace, capSizes bool, report *Report) {
+	ap := &p.cfg.Attributes
+	if !capSizes {
+		uncapped := *ap
+		uncapped.MaxValueBytes, uncapped.MaxSpanBytes = 0, 0
+		ap = &uncapped
+	}
+	for i := range t.Spans {
+		s := &t.Spans[i]
+		s.Attributes = ap.apply(s.Attributes, report)
+		for j := range s.Events {
+			s.Events[j].Attributes = ap.apply(s.Events[j].Attributes, report)
+		}
+	}
+}
+
+// apply returns attrs with disallowed keys removed and sizes capped. Keys
+// that were truncated or dropped for size are listed under AttrTruncatedKeys
+// and AttrDroppedKeys so investigators know the record is incomplete.
+func (ap *AttributePolicy) apply(attrs map[string]any, report *Report) map[string]any {
+	if len(attrs) == 0 {
+		return attrs
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		if !ap.permitted(k) {
+			report.AttributesDropped++
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make(map[string]any, len(keys))
+	var truncated, dropped []string
+	total := 0
+	for _, k := range keys {
+		v, size, cut := ap.capValue(attrs[k])
+		if ap.MaxSpanBytes > 0 && total+len(k)+size > ap.MaxSpanBytes {
+			dropped = append(dropped, k)
+			continue
+		}
+		total += len(k) + size
+		if cut {
+			truncated = append(truncated, k)
+		}
+		out[k] = v
+	}
+
+	if len(truncated) > 0 {
+		out[AttrTruncatedKeys] = truncated
+		report.AttributesTruncated += len(truncated)
+	}
+	if len(dropped) > 0 {
+		out[AttrDroppedKeys] = dropped
+		report.AttributesDropped += len(dropped)
+	}
+	return out
+}
+
+// capValue bounds one value, returning it with its encoded size and whether
+// it was shortened.
+func (ap *AttributePolicy) capValue(v any) (any, int, bool) {
+	if s, ok := v.(string); ok {
+		if ap.MaxValueBytes > 0 && len(s) > ap.MaxValueBytes {
+			return truncateUTF8(s, ap.MaxValueBytes) + truncationMarker, ap.MaxValueBytes + len(truncationMarker), true
+		}
+		return s, len(s), false
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		marker := "[dropped: value is not JSON encodable]"
+		return marker, len(marker), true
+	}
+	if ap.MaxValueBytes > 0 && len(encoded) > ap.MaxValueBytes {
+		marker := fmt.Sprintf(oversizeMarkerText, len(encoded), ap.MaxValueBytes)
+		return marker, len(marker), true
+	}
+	return v, len(encoded), false
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
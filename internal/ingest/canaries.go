@@ -0,0 +1,106 @@
+package ingest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/agentguard/agentguard/internal/canary"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// detectCanaries raises a critical exfiltration signal for each canary token
+// that leaves the agent: in a tool call's input or in the attributes of a
+// tool, chain or agent span. Tokens in LLM prompts and retrieval results are
+// expected, since that is how planted documents are read. Run it before
+// attributes are filtered and payloads stripped.
+func (p *Pipeline) detectCanaries(orgID string, t *models.AgentTrace, report *Report) {
+	if p.cfg.Canaries == nil {
+		return
+	}
+	byID := make(map[string]*models.Span, len(t.Spans))
+	for i := range t.Spans {
+		byID[t.Spans[i].SpanID] = &t.Spans[i]
+	}
+
+	for i := range t.Spans {
+		s := &t.Spans[i]
+		if s.Type == models.SpanTypeLLM || s.Type == models.SpanTypeRetrieval {
+			continue
+		}
+		seen := make(map[string]bool)
+		leak := func(field string, v any) {
+			for _, c := range p.cfg.Canaries.FindIn(orgID, v) {
+				if seen[c.ID] {
+					continue
+				}
+				seen[c.ID] = true
+				t.SecuritySignals = append(t.SecuritySignals, canarySignal(orgID, t, s, byID, c, field))
+				report.CanaryLeaks++
+			}
+		}
+		if s.Data.Tool != nil {
+			leak("tool.input", s.Data.Tool.Input)
+		}
+		keys := make([]string, 0, len(s.Attributes))
+		for k := range s.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			leak("attributes."+k, s.Attributes[k])
+		}
+	}
+}
+
+// canarySignal describes a leaked canary with its retrieval path: the
+// retrievals from the planted vector store that preceded the leak, and the
+// spans from the trace root down to the leaking span.
+func canarySignal(orgID string, t *models.AgentTrace, s *models.Span, byID map[string]*models.Span, c canary.Canary, field string) models.SecuritySignal {
+	var retrievals []map[string]any
+	for _, r := range t.Spans {
+		if r.Data.Retrieval == nil || r.Data.Retrieval.VectorStore != c.VectorStore || r.StartTime.After(s.StartTime) {
+			continue
+		}
+		retrievals = append(retrievals, map[string]any{
+			"span_id": r.SpanID,
+			"query":   r.Data.Retrieval.Query,
+		})
+	}
+
+	var path []string
+	seen := make(map[string]bool)
+	for cur := s; cur != nil && !seen[cur.SpanID]; {
+		seen[cur.SpanID] = true
+		path = append([]string{cur.Name}, path...)
+		if cur.ParentSpanID == nil {
+			break
+		}
+		cur = byID[*cur.ParentSpanID]
+	}
+
+	evidence := map[string]any{
+		"canary_id":       c.ID,
+		"vector_store":    c.VectorStore,
+		"document_id":     c.DocumentID,
+		"field":           field,
+		"span_path":       path,
+		"retrieval_spans": retrievals,
+	}
+	sink := s.Name
+	if s.Data.Tool != nil {
+		evidence["tool_name"] = s.Data.Tool.ToolName
+		evidence["external_call"] = s.Data.Tool.ExternalCall
+		sink = "tool '" + s.Data.Tool.ToolName + "'"
+	}
+	return models.SecuritySignal{
+		ID:          detectorSignalID(orgID, t.TraceID, "canary", s.SpanID, c.ID),
+		TraceID:     t.TraceID,
+		SpanID:      s.SpanID,
+		Type:        models.SignalDataExfiltration,
+		Severity:    "critical",
+		Title:       "Knowledge base canary leaked",
+		Description: fmt.Sprintf("canary from document '%s' in '%s' reached %s", c.DocumentID, c.VectorStore, sink),
+		Evidence:    evidence,
+		Timestamp:   s.StartTime,
+	}
+}
@@ -0,0 +1,120 @@
+package ingest
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// deduper remembers recently ingested span and signal keys so resubmitting a
+// trace (for example, an SDK retry after a timeout) does not store it twice.
+type deduper struct {
+	window     time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu    sync.Mutex
+	order *list.List // of *dedupeEntry, oldest first
+	index map[string]*list.Element
+}
+
+type dedupeEntry struct {
+	key  string
+	seen time.Time
+}
+
+func newDeduper(window time.Duration, maxEntries int) *deduper {
+	if maxEntries <= 0 {
+		maxEntries = 100000
+	}
+	return &deduper{
+		window:     window,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		order:      list.New(),
+		index:      make(map[string]*list.Element),
+	}
+}
+
+func spanKey(orgID, traceID, spanID string) string {
+	return orgID + "/" + traceID + "/span/" + spanID
+}
+
+func signalKey(orgID, traceID, signalID string) string {
+	return orgID + "/" + traceID + "/signal/" + signalID
+}
+
+// filter removes spans and signals already recorded for orgID.
+func (d *deduper) filter(orgID string, t *models.AgentTrace, report *Report) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire()
+
+	spans := t.Spans[:0]
+	for _, s := range t.Spans {
+		if _, ok := d.index[spanKey(orgID, t.TraceID, s.SpanID)]; ok {
+			report.DuplicateSpans++
+			continue
+		}
+		spans = append(spans, s)
+	}
+	t.Spans = spans
+
+	signals := t.SecuritySignals[:0]
+	for _, sig := range t.SecuritySignals {
+		if sig.ID != "" {
+			if _, ok := d.index[signalKey(orgID, t.TraceID, sig.ID)]; ok {
+				report.DuplicateSignals++
+				continue
+			}
+		}
+		signals = append(signals, sig)
+	}
+	t.SecuritySignals = signals
+}
+
+// record marks t's spans and signals as ingested.
+func (d *deduper) record(orgID string, t *models.AgentTrace) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for _, s := range t.Spans {
+		d.add(spanKey(orgID, t.TraceID, s.SpanID), now)
+	}
+	for _, sig := range t.SecuritySignals {
+		if sig.ID != "" {
+			d.add(signalKey(orgID, t.TraceID, sig.ID), now)
+		}
+	}
+	for d.order.Len() > d.maxEntries {
+		d.remove(d.order.Front())
+	}
+}
+
+// add inserts or refreshes key. Callers must hold d.mu.
+func (d *deduper) add(key string, now time.Time) {
+	if el, ok := d.index[key]; ok {
+		el.Value.(*dedupeEntry).seen = now
+		d.order.MoveToBack(el)
+		return
+	}
+	d.index[key] = d.order.PushBack(&dedupeEntry{key: key, seen: now})
+}
+
+// expire drops entries older than the window. Callers must hold d.mu.
+func (d *deduper) expire() {
+	cutoff := d.now().Add(-d.window)
+	for el := d.order.Front(); el != nil; el = d.order.Front() {
+		if el.Value.(*dedupeEntry).seen.After(cutoff) {
+			return
+		}
+		d.remove(el)
+	}
+}
+
+func (d *deduper) remove(el *list.Element) {
+	delete(d.index, el.Value.(*dedupeEntry).key)
+	d.order.Remove(el)
+}
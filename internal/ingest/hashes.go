@@ -0,0 +1,55 @@
+package ingest
+
+import (
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// ContentHasher computes the server-side salted hashes of prompts and tool
+// payloads.
+type ContentHasher interface {
+	Sum(orgID string, v any) (string, error)
+	Valid(hash string) bool
+}
+
+// hashContent replaces client-computed prompt and tool payload hashes with
+// server-side salted hashes of the raw content. Client hashes sent without
+// content cannot be verified; unless they already have the server's form
+// they are dropped, since they would not be comparable with server hashes.
+// Raw prompts are removed; raw tool payloads are left for StorePayloads.
+func (p *Pipeline) hashContent(orgID string, t *models.AgentTrace, report *Report) {
+	if p.cfg.Hasher == nil {
+		return
+	}
+	for i := range t.Spans {
+		if llm := t.Spans[i].Data.LLM; llm != nil {
+			llm.PromptHash = p.rehash(orgID, llm.Prompt, llm.PromptHash, report)
+			llm.Prompt = nil
+		}
+		if tool := t.Spans[i].Data.Tool; tool != nil {
+			tool.InputHash = p.rehash(orgID, tool.Input, tool.InputHash, report)
+			tool.OutputHash = p.rehash(orgID, tool.Output, tool.OutputHash, report)
+		}
+	}
+}
+
+// rehash returns the server hash of content, or the client hash when there
+// is no content and the hash is in server form.
+func (p *Pipeline) rehash(orgID string, content any, clientHash string, report *Report) string {
+	if content == nil {
+		if clientHash == "" || p.cfg.Hasher.Valid(clientHash) {
+			return clientHash
+		}
+		report.HashesDropped++
+		return ""
+	}
+	hash, err := p.cfg.Hasher.Sum(orgID, content)
+	if err != nil {
+		report.HashesDropped++
+		return ""
+	}
+	report.HashesComputed++
+	if clientHash != "" && clientHash != hash {
+		report.HashesReplaced++
+	}
+	return hash
+}
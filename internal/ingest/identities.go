@@ -0,0 +1,48 @@
+package ingest
+
+import (
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/privacy"
+)
+
+// IdentityPseudonymizer replaces user and session identifiers with stable
+// per-organization pseudonyms.
+type IdentityPseudonymizer interface {
+	Pseudonymize(orgID string, kind privacy.IdentityKind, value string) string
+}
+
+// identityAttributes are the span attributes that carry the trace's user
+// and session identifiers under OpenTelemetry semantic conventions.
+var identityAttributes = map[string]privacy.IdentityKind{
+	"enduser.id": privacy.IdentityUser,
+	"user.id":    privacy.IdentityUser,
+	"session.id": privacy.IdentitySession,
+}
+
+// pseudonymizeIdentities replaces t's user and session IDs, and the span
+// attributes that repeat them, with pseudonyms before anything downstream
+// sees the raw values.
+func (p *Pipeline) pseudonymizeIdentities(orgID string, t *models.AgentTrace, report *Report) {
+	if p.cfg.Identities == nil {
+		return
+	}
+	if t.UserID != "" {
+		t.UserID = p.cfg.Identities.Pseudonymize(orgID, privacy.IdentityUser, t.UserID)
+		report.IdentitiesPseudonymized++
+	}
+	if t.SessionID != "" {
+		t.SessionID = p.cfg.Identities.Pseudonymize(orgID, privacy.IdentitySession, t.SessionID)
+		report.IdentitiesPseudonymized++
+	}
+	for i := range t.Spans {
+		attrs := t.Spans[i].Attributes
+		for key, kind := range identityAttributes {
+			v, ok := attrs[key].(string)
+			if !ok || v == "" {
+				continue
+			}
+			attrs[key] = p.cfg.Identities.Pseudonymize(orgID, kind, v)
+			report.IdentitiesPseudonymized++
+		}
+	}
+}
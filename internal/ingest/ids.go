@@ -0,0 +1,238 @@
+package ingest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"reflect"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+// W3C Trace Context ID lengths in hex characters.
+const (
+	traceIDLen = 32
+	spanIDLen  = 16
+)
+
+// validTraceID reports whether id is a W3C trace-id: 32 lowercase hex
+// characters, not all zero.
+func validTraceID(id string) bool {
+	return validHexID(id, traceIDLen)
+}
+
+// validSpanID reports whether id is a W3C parent-id: 16 lowercase hex
+// characters, not all zero.
+func validSpanID(id string) bool {
+	return validHexID(id, spanIDLen)
+}
+
+func validHexID(id string, n int) bool {
+	if len(id) != n {
+		return false
+	}
+	nonZero := false
+	for i := 0; i < n; i++ {
+		c := id[i]
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			nonZero = true
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
+
+// assignIDs gives the trace and its spans W3C IDs when the client left them
+// empty. A span without an ID cannot be a parent, and a resubmitted trace
+// without IDs is stored again since there is nothing to deduplicate on.
+func assignIDs(t *models.AgentTrace, report *Report) {
+	if t.TraceID == "" {
+		t.TraceID = newHexID(traceIDLen)
+		report.AssignedIDs++
+	}
+	for i := range t.Spans {
+		if t.Spans[i].SpanID == "" {
+			t.Spans[i].SpanID = newHexID(spanIDLen)
+			report.AssignedIDs++
+		}
+	}
+}
+
+// assignSignalIDs gives signals without one an ID and ties every signal to
+// the trace.
+func assignSignalIDs(t *models.AgentTrace, report *Report) {
+	for i := range t.SecuritySignals {
+		sig := &t.SecuritySignals[i]
+		if sig.ID == "" {
+			sig.ID = uuid.NewString()
+			report.AssignedIDs++
+		}
+		sig.TraceID = t.TraceID
+	}
+}
+
+// signalNamespace is the UUID namespace of detector signal IDs.
+var signalNamespace = uuid.MustParse("8d3f5c2e-6b1a-4f0e-9c7d-2a4e6b8f1c3d")
+
+// detectorSignalID derives the ID of a signal a detector raises from the
+// organization, the trace and what the detector found, such as its name,
+// the span and the canary or rule. Processing the same batch again, as on
+// an SDK retry, yields the same ID, so the deduper drops the signal along
+// with the batch's spans instead of storing a new finding. Fields are
+// length-prefixed so distinct field lists never share a name.
+func detectorSignalID(orgID, traceID string, fields ...string) string {
+	var name bytes.Buffer
+	for _, f := range append([]string{orgID, traceID}, fields...) {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(f)))
+		name.Write(n[:])
+		name.WriteString(f)
+	}
+	return uuid.NewSHA1(signalNamespace, name.Bytes()).String()
+}
+
+// newHexID returns a random lowercase hex ID of n characters.
+func newHexID(n int) string {
+	b := make([]byte, n/2)
+	rand.Read(b) // never returns an error as of Go 1.24
+	return hex.EncodeToString(b)
+}
+
+// validateIDs checks ID formats and rejects parent references that form a
+// cycle. Parents absent from the trace are allowed: they may belong to a
+// remote caller or arrive in a later batch.
+func validateIDs(t *models.AgentTrace) error {
+	verr := &ValidationError{}
+	if !validTraceID(t.TraceID) {
+		verr.add("trace_id %q is not a 32-character lowercase hex W3C trace ID", t.TraceID)
+	}
+
+	if p := t.Parent; p != nil {
+		switch {
+		case !validTraceID(p.TraceID):
+			verr.add("parent.trace_id %q is not a 32-character lowercase hex W3C trace ID", p.TraceID)
+		case p.TraceID == t.TraceID:
+			verr.add("parent.trace_id must name the delegating agent's trace, not this one")
+		}
+		if !validSpanID(p.SpanID) {
+			verr.add("parent.span_id %q is not a 16-character lowercase hex W3C span ID", p.SpanID)
+		}
+	}
+
+	parents := make(map[string]string, len(t.Spans))
+	for i, s := range t.Spans {
+		if !validSpanID(s.SpanID) {
+			verr.add("spans[%d].span_id %q is not a 16-character lowercase hex W3C span ID", i, s.SpanID)
+			continue
+		}
+		if s.ParentSpanID != nil {
+			if !validSpanID(*s.ParentSpanID) {
+				verr.add("spans[%d].parent_span_id %q is not a 16-character lowercase hex W3C span ID", i, *s.ParentSpanID)
+				continue
+			}
+			parents[s.SpanID] = *s.ParentSpanID
+		}
+	}
+	for i, sig := range t.SecuritySignals {
+		if sig.SpanID != "" && !validSpanID(sig.SpanID) {
+			verr.add("security_signals[%d].span_id %q is not a 16-character lowercase hex W3C span ID", i, sig.SpanID)
+		}
+		if sig.TraceID != "" && sig.TraceID != t.TraceID {
+			verr.add("security_signals[%d].trace_id %q does not match trace_id", i, sig.TraceID)
+		}
+	}
+
+	for _, cycle := range findCycles(parents) {
+		verr.add("parent_span_id references form a cycle: %v", cycle)
+	}
+	return verr.err()
+}
+
+// findCycles returns each distinct cycle in a child→parent map, as the span
+// IDs in the order they are visited.
+func findCycles(parents map[string]string) [][]string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(parents))
+	var cycles [][]string
+
+	for start := range parents {
+		if state[start] != unvisited {
+			continue
+		}
+		var path []string
+		id := start
+		for {
+			if state[id] == inProgress {
+				// id is on the current path: everything from it onward loops.
+				for i, p := range path {
+					if p == id {
+						cycles = append(cycles, append([]string(nil), path[i:]...))
+						break
+					}
+				}
+				break
+			}
+			if state[id] == done {
+				break
+			}
+			state[id] = inProgress
+			path = append(path, id)
+			parent, ok := parents[id]
+			if !ok {
+				break
+			}
+			id = parent
+		}
+		for _, p := range path {
+			state[p] = done
+		}
+	}
+	return cycles
+}
+
+// dedupeWithin drops spans and signals repeated within one submission. An
+// exact repeat is dropped; a repeated ID with different content is rejected
+// because there is no way to tell which copy is authoritative.
+func dedupeWithin(t *models.AgentTrace, report *Report) error {
+	verr := &ValidationError{}
+
+	seen := make(map[string]int, len(t.Spans))
+	spans := t.Spans[:0]
+	for _, s := range t.Spans {
+		if j, ok := seen[s.SpanID]; ok {
+			if !reflect.DeepEqual(spans[j], s) {
+				verr.add("span_id %s submitted twice with different content", s.SpanID)
+			}
+			report.DuplicateSpans++
+			continue
+		}
+		seen[s.SpanID] = len(spans)
+		spans = append(spans, s)
+	}
+	t.Spans = spans
+
+	seenSignals := make(map[string]struct{}, len(t.SecuritySignals))
+	signals := t.SecuritySignals[:0]
+	for _, sig := range t.SecuritySignals {
+		if sig.ID != "" {
+			if _, ok := seenSignals[sig.ID]; ok {
+				report.DuplicateSignals++
+				continue
+			}
+			seenSignals[sig.ID] = struct{}{}
+		}
+		signals = append(signals, sig)
+	}
+	t.SecuritySignals = signals
+
+	return verr.err()
+}
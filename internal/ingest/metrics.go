@@ -0,0 +1,48 @@
+package ingest
+
+import (
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// ModelPrice is a model's price in USD per 1,000 tokens.
+type ModelPrice struct {
+	PromptPer1K     float64
+	CompletionPer1K float64
+}
+
+// computeMetrics replaces the client's trace metrics with counts taken from
+// the spans and signals that will be persisted. Tokens of LLM spans without
+// a total are summed from their prompt and completion counts; models
+// without a price contribute no cost.
+func (p *Pipeline) computeMetrics(t *models.AgentTrace) {
+	m := models.TraceMetrics{
+		TotalSpans:      len(t.Spans),
+		SecuritySignals: len(t.SecuritySignals),
+	}
+	for _, s := range t.Spans {
+		if s.Type == models.SpanTypePolicy {
+			m.PolicyEvaluations++
+		}
+		if llm := s.Data.LLM; llm != nil {
+			m.LLMCalls++
+			total := llm.TotalTokens
+			if total == 0 {
+				total = llm.PromptTokens + llm.CompletionTokens
+			}
+			m.TotalTokens += total
+			if price, ok := p.prices[strings.ToLower(llm.Model)]; ok {
+				m.EstimatedCostUSD += float64(llm.PromptTokens)/1000*price.PromptPer1K +
+					float64(llm.CompletionTokens)/1000*price.CompletionPer1K
+			}
+		}
+		if tool := s.Data.Tool; tool != nil {
+			m.ToolInvocations++
+			if tool.PolicyDecision != nil {
+				m.PolicyEvaluations++
+			}
+		}
+	}
+	t.Metrics = m
+}
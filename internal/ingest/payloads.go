@@ -0,0 +1,80 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// PayloadStore keeps large span payloads by content hash.
+type PayloadStore interface {
+	Put(ctx context.Context, orgID string, data []byte, contentType string) (string, error)
+	TTL() time.Duration
+}
+
+// StorePayloads hashes raw tool inputs and outputs, moves those at or above
+// the payload threshold to the payload store, and strips every raw payload
+// from the trace. Quarantined agents' payloads are stored regardless of size.
+// Call it after Process and before persisting the trace. A payload that
+// cannot be stored is dropped and counted; its hash is kept.
+func (p *Pipeline) StorePayloads(ctx context.Context, orgID string, t *models.AgentTrace, report *Report) {
+	threshold := p.cfg.PayloadThreshold
+	if report.Quarantined {
+		threshold = 0
+	}
+	for i := range t.Spans {
+		tool := t.Spans[i].Data.Tool
+		if tool == nil {
+			continue
+		}
+		tool.InputHash, tool.InputRef = p.storePayload(ctx, orgID, tool.Input, tool.InputHash, threshold, report)
+		tool.OutputHash, tool.OutputRef = p.storePayload(ctx, orgID, tool.Output, tool.OutputHash, threshold, report)
+		tool.Input, tool.Output = nil, nil
+	}
+}
+
+// storePayload returns the payload's hash and, when stored, its reference.
+// A client-supplied hash is kept when the payload is not stored. Salted
+// hashes computed by the pipeline's Hasher are always kept; the reference
+// then carries the storage hash.
+func (p *Pipeline) storePayload(ctx context.Context, orgID string, payload any, hash string, threshold int, report *Report) (string, *models.PayloadRef) {
+	if payload == nil {
+		return hash, nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		report.PayloadsDropped++
+		return hash, nil
+	}
+	if hash == "" {
+		hash = storage.Hash(data)
+	}
+	if p.cfg.Payloads == nil || len(data) < threshold {
+		report.PayloadsDropped++
+		return hash, nil
+	}
+
+	stored, err := p.cfg.Payloads.Put(ctx, orgID, data, "application/json")
+	if err != nil {
+		log.Warn().Err(err).Str("org_id", orgID).Msg("failed to store span payload")
+		report.PayloadsDropped++
+		return hash, nil
+	}
+	report.PayloadsStored++
+	if p.cfg.Hasher == nil {
+		hash = stored
+	}
+	ref := &models.PayloadRef{
+		Hash:        stored,
+		Size:        int64(len(data)),
+		ContentType: "application/json",
+	}
+	if ttl := p.cfg.Payloads.TTL(); ttl > 0 {
+		ref.ExpiresAt = p.now().UTC().Add(ttl)
+	}
+	return hash, ref
+}
@@ -0,0 +1,209 @@
+// Package ingest validates and normalizes agent traces before they are
+// persisted. Stages run in a fixed order on each submitted trace and record
+// what they changed in a Report returned to the client.
+package ingest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/canary"
+	"github.com/agentguard/agentguard/internal/detect"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/severity"
+)
+
+// Config configures a Pipeline.
+type Config struct {
+	// DedupeWindow is how long ingested span IDs are remembered so repeated
+	// submissions are idempotent. Zero disables cross-request deduplication.
+	DedupeWindow time.Duration
+	// DedupeMaxEntries bounds the dedupe cache; the oldest entries are
+	// evicted first.
+	DedupeMaxEntries int
+	// MaxClockSkew is how far in the future trace timestamps may be before
+	// the whole trace is shifted back to the server receive time.
+	MaxClockSkew time.Duration
+	// MaxTraceAge rejects traces that started longer ago than this. Zero
+	// accepts any age.
+	MaxTraceAge time.Duration
+	// Attributes filters and bounds span attributes before persistence.
+	Attributes AttributePolicy
+	// Prompts correlates LLM prompt hashes across principals. Optional.
+	Prompts *prompts.Registry
+	// Canaries finds knowledge base canary tokens leaking through tool
+	// calls. Optional.
+	Canaries *canary.Registry
+	// Rules runs detection rules over trace content and counts. Optional.
+	Rules *detect.Engine
+	// Quarantine identifies agents whose traces are kept in full and run
+	// through extra detectors. Optional.
+	Quarantine QuarantineLookup
+	// Payloads stores tool inputs and outputs of at least PayloadThreshold
+	// bytes for investigators. Optional; without it payloads are only hashed.
+	Payloads         PayloadStore
+	PayloadThreshold int
+	// Identities pseudonymizes user and session IDs so raw identities are
+	// never persisted. Optional.
+	Identities IdentityPseudonymizer
+	// Hasher recomputes prompt and tool payload hashes server-side with a
+	// per-organization salt. Optional; without it client hashes are kept.
+	Hasher ContentHasher
+	// Severity re-scores the trace's signals by the criticality of the
+	// agent and the data it touched. Optional.
+	Severity *severity.Scorer
+	// Pricing maps model names (case-insensitive) to token prices for the
+	// trace's estimated cost.
+	Pricing map[string]ModelPrice
+}
+
+// Report describes how the pipeline changed a trace.
+type Report struct {
+	TraceID string `json:"trace_id"`
+	// AssignedIDs counts trace, span and signal IDs generated because the
+	// client left them empty.
+	AssignedIDs int `json:"assigned_ids,omitempty"`
+	// Accepted is the number of spans that will be persisted.
+	Accepted int `json:"accepted_spans"`
+	// DuplicateSpans were already ingested or repeated within the submission.
+	DuplicateSpans int `json:"duplicate_spans"`
+	// DuplicateSignals were already ingested or repeated within the submission.
+	DuplicateSignals int `json:"duplicate_signals"`
+	// SkewCorrectedMs is how far the trace's timestamps were shifted back
+	// because the agent host clock ran ahead. Zero when no correction applied.
+	SkewCorrectedMs int64 `json:"skew_corrected_ms,omitempty"`
+	// RecomputedDurations counts spans whose duration was derived server-side.
+	RecomputedDurations int `json:"recomputed_durations"`
+	// InvalidTimings counts spans (or the trace) whose end preceded the start.
+	InvalidTimings int `json:"invalid_timings,omitempty"`
+	// AttributesDropped counts attributes removed by policy or size caps.
+	AttributesDropped int `json:"attributes_dropped,omitempty"`
+	// AttributesTruncated counts attribute values shortened to fit size caps.
+	AttributesTruncated int `json:"attributes_truncated,omitempty"`
+	// PromptFindings counts signals raised for reused or blocked prompts.
+	PromptFindings int `json:"prompt_findings,omitempty"`
+	// CanaryLeaks counts signals raised for leaked canary tokens.
+	CanaryLeaks int `json:"canary_leaks,omitempty"`
+	// RuleFindings counts signals raised by detection rules.
+	RuleFindings int `json:"rule_findings,omitempty"`
+	// Quarantined is set when the agent is quarantined; the trace bypasses
+	// attribute size caps and is marked for full retention.
+	Quarantined bool `json:"quarantined,omitempty"`
+	// QuarantineFindings counts signals raised by quarantine-only detectors.
+	QuarantineFindings int `json:"quarantine_findings,omitempty"`
+	// PayloadsStored counts tool payloads moved to blob storage.
+	PayloadsStored int `json:"payloads_stored,omitempty"`
+	// PayloadsDropped counts tool payloads kept only as a hash.
+	PayloadsDropped int `json:"payloads_dropped,omitempty"`
+	// IdentitiesPseudonymized counts user and session identifiers replaced
+	// with pseudonyms.
+	IdentitiesPseudonymized int `json:"identities_pseudonymized,omitempty"`
+	// HashesComputed counts prompt and payload hashes computed server-side.
+	HashesComputed int `json:"hashes_computed,omitempty"`
+	// HashesReplaced counts client-sent hashes that differed from the
+	// server's and were replaced.
+	HashesReplaced int `json:"hashes_replaced,omitempty"`
+	// HashesDropped counts client-sent hashes removed because they could
+	// not be verified.
+	HashesDropped int `json:"hashes_dropped,omitempty"`
+	// SignalsRescored counts signals whose severity was adjusted for the
+	// asset they were raised on.
+	SignalsRescored int `json:"signals_rescored,omitempty"`
+}
+
+// ValidationError lists every problem found in a rejected trace.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid trace: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ValidationError) add(format string, args ...any) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+func (e *ValidationError) err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Pipeline runs ingest stages over submitted traces. It is safe for
+// concurrent use.
+type Pipeline struct {
+	cfg     Config
+	deduper *deduper
+	prices  map[string]ModelPrice
+	now     func() time.Time
+}
+
+// NewPipeline creates an ingest pipeline.
+func NewPipeline(cfg Config) *Pipeline {
+	p := &Pipeline{cfg: cfg, prices: make(map[string]ModelPrice, len(cfg.Pricing)), now: time.Now}
+	for model, price := range cfg.Pricing {
+		p.prices[strings.ToLower(model)] = price
+	}
+	if cfg.DedupeWindow > 0 {
+		p.deduper = newDeduper(cfg.DedupeWindow, cfg.DedupeMaxEntries)
+	}
+	return p
+}
+
+// Process validates t and normalizes it in place. Missing IDs are assigned,
+// and spans and signals that were already ingested for the same
+// organization are removed. The trace's metrics are computed from what
+// remains. A *ValidationError is returned when the trace must be rejected.
+func (p *Pipeline) Process(orgID string, t *models.AgentTrace) (*Report, error) {
+	report := &Report{}
+	assignIDs(t, report)
+	if err := validateIDs(t); err != nil {
+		return nil, err
+	}
+	report.TraceID = t.TraceID
+
+	if err := dedupeWithin(t, report); err != nil {
+		return nil, err
+	}
+	// Read asset context before span attributes are filtered.
+	asset := severity.TraceAsset(t)
+	p.detectCanaries(orgID, t, report)
+	p.detectRules(orgID, t, report)
+	allowTools, quarantined := p.quarantineTools(t)
+	report.Quarantined = quarantined
+	// Filter before annotating so client-sent agentguard.* keys are removed
+	// but the pipeline's own annotations survive.
+	p.filterAttributes(t, !quarantined, report)
+	p.pseudonymizeIdentities(orgID, t, report)
+	p.hashContent(orgID, t, report)
+	if err := p.normalizeTiming(t, p.now().UTC(), report); err != nil {
+		return nil, err
+	}
+	if quarantined {
+		p.detectQuarantine(orgID, t, allowTools, report)
+	}
+	if p.deduper != nil {
+		p.deduper.filter(orgID, t, report)
+	}
+	// Observe only accepted spans so a retried batch does not count its
+	// prompts again.
+	p.observePrompts(orgID, t, report)
+	report.SignalsRescored = p.cfg.Severity.RescoreTrace(t, asset)
+	assignSignalIDs(t, report)
+	p.computeMetrics(t)
+
+	report.Accepted = len(t.Spans)
+	return report, nil
+}
+
+// Commit records t's spans and signals as ingested. Call it after the trace
+// is persisted so a failed write can be retried without being deduplicated.
+func (p *Pipeline) Commit(orgID string, t *models.AgentTrace) {
+	if p.deduper != nil {
+		p.deduper.record(orgID, t)
+	}
+}
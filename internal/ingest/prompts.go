@@ -0,0 +1,59 @@
+package ingest
+
+import (
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/google/uuid"
+)
+
+// observePrompts feeds LLM prompt hashes to the registry and attaches a
+// security signal to the trace for each finding.
+func (p *Pipeline) observePrompts(orgID string, t *models.AgentTrace, report *Report) {
+	if p.cfg.Prompts == nil {
+		return
+	}
+	agentID := ""
+	if t.AgentID != uuid.Nil {
+		agentID = t.AgentID.String()
+	}
+
+	for _, s := range t.Spans {
+		if s.Data.LLM == nil || s.Data.LLM.PromptHash == "" {
+			continue
+		}
+		f := p.cfg.Prompts.Observe(prompts.Observation{
+			OrgID:   orgID,
+			Hash:    s.Data.LLM.PromptHash,
+			UserID:  t.UserID,
+			AgentID: agentID,
+			At:      s.StartTime,
+		})
+		if f == nil {
+			continue
+		}
+
+		sig := models.SecuritySignal{
+			ID:          uuid.NewString(),
+			TraceID:     t.TraceID,
+			SpanID:      s.SpanID,
+			Type:        models.SignalAnomalousBehavior,
+			Severity:    "high",
+			Title:       "Suspicious prompt reuse",
+			Description: f.Reason,
+			Evidence: map[string]any{
+				"prompt_hash":     f.Hash,
+				"count":           f.Count,
+				"distinct_users":  f.DistinctUsers,
+				"distinct_agents": f.DistinctAgents,
+			},
+			Timestamp: s.StartTime,
+		}
+		if f.Blocked {
+			sig.Type = models.SignalPolicyViolation
+			sig.Severity = "critical"
+			sig.Title = "Blocked prompt submitted"
+		}
+		t.SecuritySignals = append(t.SecuritySignals, sig)
+		report.PromptFindings++
+	}
+}
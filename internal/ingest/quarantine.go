@@ -0,0 +1,86 @@
+package ingest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+// Metadata keys set on traces from quarantined agents.
+const (
+	MetaQuarantined = "agentguard.quarantined"
+	MetaRetention   = "agentguard.retention"
+	// RetentionFull marks traces that must be kept unsampled and without
+	// attribute size caps.
+	RetentionFull = "full"
+)
+
+// QuarantineLookup reports whether an agent is quarantined and which tools
+// it may still call.
+type QuarantineLookup interface {
+	Quarantined(agentID string) (allowTools []string, quarantined bool)
+}
+
+// quarantineTools returns the allowlist for t's agent when it is quarantined.
+func (p *Pipeline) quarantineTools(t *models.AgentTrace) ([]string, bool) {
+	if p.cfg.Quarantine == nil || t.AgentID == uuid.Nil {
+		return nil, false
+	}
+	return p.cfg.Quarantine.Quarantined(t.AgentID.String())
+}
+
+// detectQuarantine marks a quarantined agent's trace for full retention and
+// runs detectors that are too noisy for agents in normal operation: any tool
+// call off the allowlist, any external call, and any retrieval.
+func (p *Pipeline) detectQuarantine(orgID string, t *models.AgentTrace, allowTools []string, report *Report) {
+	if t.Metadata == nil {
+		t.Metadata = make(map[string]any)
+	}
+	t.Metadata[MetaQuarantined] = true
+	t.Metadata[MetaRetention] = RetentionFull
+
+	allowed := append([]string(nil), allowTools...)
+	sort.Strings(allowed)
+	onAllowlist := func(name string) bool {
+		i := sort.SearchStrings(allowed, name)
+		return i < len(allowed) && allowed[i] == name
+	}
+
+	for _, s := range t.Spans {
+		switch {
+		case s.Data.Tool != nil && !onAllowlist(s.Data.Tool.ToolName):
+			p.addQuarantineSignal(orgID, t, &s, models.SignalPolicyViolation, "high",
+				"Quarantined agent called a tool off its allowlist",
+				fmt.Sprintf("tool '%s' is not permitted while the agent is quarantined", s.Data.Tool.ToolName),
+				map[string]any{"tool_name": s.Data.Tool.ToolName}, report)
+		case s.Data.Tool != nil && s.Data.Tool.ExternalCall:
+			p.addQuarantineSignal(orgID, t, &s, models.SignalDataExfiltration, "medium",
+				"Quarantined agent made an external call",
+				fmt.Sprintf("tool '%s' reached an external endpoint", s.Data.Tool.ToolName),
+				map[string]any{"tool_name": s.Data.Tool.ToolName}, report)
+		case s.Data.Retrieval != nil:
+			p.addQuarantineSignal(orgID, t, &s, models.SignalAnomalousBehavior, "medium",
+				"Quarantined agent queried a vector store",
+				fmt.Sprintf("retrieval from '%s' returned %d results", s.Data.Retrieval.VectorStore, s.Data.Retrieval.NumResults),
+				map[string]any{"vector_store": s.Data.Retrieval.VectorStore}, report)
+		}
+	}
+}
+
+func (p *Pipeline) addQuarantineSignal(orgID string, t *models.AgentTrace, s *models.Span, typ models.SignalType, severity, title, desc string, evidence map[string]any, report *Report) {
+	evidence["quarantined"] = true
+	t.SecuritySignals = append(t.SecuritySignals, models.SecuritySignal{
+		ID:          detectorSignalID(orgID, t.TraceID, "quarantine", s.SpanID, string(typ)),
+		TraceID:     t.TraceID,
+		SpanID:      s.SpanID,
+		Type:        typ,
+		Severity:    severity,
+		Title:       title,
+		Description: desc,
+		Evidence:    evidence,
+		Timestamp:   s.StartTime,
+	})
+	report.QuarantineFindings++
+}
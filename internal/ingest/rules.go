@@ -0,0 +1,55 @@
+package ingest
+
+import (
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/detect"
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// detectRules raises a signal for each detection rule finding. Run it
+// before attributes are filtered and payloads stripped so pattern rules
+// see the content the agent sent. A threshold rule's signal is identified
+// by the trace alone, so it is raised once per trace within the dedupe
+// window.
+func (p *Pipeline) detectRules(orgID string, t *models.AgentTrace, report *Report) {
+	if p.cfg.Rules == nil {
+		return
+	}
+	for _, f := range p.cfg.Rules.Evaluate(t) {
+		evidence := map[string]any{
+			"rule_id":          f.Rule.ID,
+			"rule_version":     f.Rule.Version,
+			"ruleset_revision": f.Revision,
+		}
+		sig := models.SecuritySignal{
+			ID:        detectorSignalID(orgID, t.TraceID, "rule", f.Rule.ID, f.SpanID, f.Field),
+			TraceID:   t.TraceID,
+			Type:      f.Rule.Signal,
+			Severity:  f.Rule.Severity,
+			Title:     "Detection rule " + f.Rule.ID + " matched",
+			Evidence:  evidence,
+			Timestamp: t.StartTime,
+		}
+		switch f.Rule.Kind {
+		case detect.KindPattern:
+			evidence["field"], evidence["match"] = f.Field, f.Excerpt
+			sig.SpanID = f.SpanID
+			sig.Description = fmt.Sprintf("%s matched in %s", f.Rule.ID, f.Field)
+			for _, s := range t.Spans {
+				if s.SpanID == f.SpanID {
+					sig.Timestamp = s.StartTime
+					break
+				}
+			}
+		case detect.KindThreshold:
+			evidence["metric"], evidence["value"], evidence["max"] = f.Rule.Metric, f.Value, f.Rule.Max
+			sig.Description = fmt.Sprintf("%s is %g, above the maximum of %g", f.Rule.Metric, f.Value, f.Rule.Max)
+		}
+		if f.Rule.Description != "" {
+			sig.Description = f.Rule.Description + ": " + sig.Description
+		}
+		t.SecuritySignals = append(t.SecuritySignals, sig)
+		report.RuleFindings++
+	}
+}
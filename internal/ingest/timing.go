@@ -0,0 +1,152 @@
+package ingest
+
+import (
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Metadata and attribute keys set when timing is adjusted.
+const (
+	MetaSkewCorrectedMs = "agentguard.skew_corrected_ms"
+	AttrInvalidTiming   = "agentguard.invalid_timing"
+)
+
+// normalizeTiming converts every timestamp to UTC, shifts traces whose
+// timestamps lie in the future beyond maxSkew back to the receive time, and
+// recomputes durations server-side. Client-supplied DurationMs values are
+// ignored because they come from unsynchronized agent host clocks.
+func (p *Pipeline) normalizeTiming(t *models.AgentTrace, received time.Time, report *Report) error {
+	verr := &ValidationError{}
+	if t.StartTime.IsZero() {
+		verr.add("start_time is required")
+	}
+	for i, s := range t.Spans {
+		if s.StartTime.IsZero() {
+			verr.add("spans[%d].start_time is required", i)
+		}
+	}
+	if err := verr.err(); err != nil {
+		return err
+	}
+
+	if p.cfg.MaxTraceAge > 0 && received.Sub(t.StartTime) > p.cfg.MaxTraceAge {
+		verr.add("start_time %s is older than the %s ingest limit", t.StartTime.UTC().Format(time.RFC3339), p.cfg.MaxTraceAge)
+		return verr
+	}
+
+	// Skew: if the latest timestamp in the trace is ahead of the server
+	// clock by more than the tolerance, shift everything back so that
+	// timestamp lands on the receive time. Relative timing is preserved.
+	if latest := latestTimestamp(t); latest.Sub(received) > p.cfg.MaxClockSkew {
+		offset := latest.Sub(received)
+		shiftTrace(t, -offset)
+		report.SkewCorrectedMs = offset.Milliseconds()
+		if t.Metadata == nil {
+			t.Metadata = make(map[string]any)
+		}
+		t.Metadata[MetaSkewCorrectedMs] = report.SkewCorrectedMs
+	}
+
+	toUTC(t)
+
+	var traceEnd time.Time
+	for i := range t.Spans {
+		s := &t.Spans[i]
+		s.DurationMs = 0
+		if s.EndTime == nil {
+			continue
+		}
+		if s.EndTime.Before(s.StartTime) {
+			// Negative duration: keep the start, drop the impossible end.
+			s.EndTime = nil
+			if s.Attributes == nil {
+				s.Attributes = make(map[string]any)
+			}
+			s.Attributes[AttrInvalidTiming] = "end_time before start_time"
+			report.InvalidTimings++
+			continue
+		}
+		s.DurationMs = s.EndTime.Sub(s.StartTime).Milliseconds()
+		report.RecomputedDurations++
+		if s.EndTime.After(traceEnd) {
+			traceEnd = *s.EndTime
+		}
+	}
+
+	t.DurationMs = 0
+	if t.EndTime != nil && t.EndTime.Before(t.StartTime) {
+		if t.Metadata == nil {
+			t.Metadata = make(map[string]any)
+		}
+		t.Metadata[AttrInvalidTiming] = "end_time before start_time"
+		t.EndTime = nil
+		report.InvalidTimings++
+	}
+	switch {
+	case t.EndTime != nil:
+		t.DurationMs = t.EndTime.Sub(t.StartTime).Milliseconds()
+	case !traceEnd.IsZero() && t.Status != models.TraceStatusRunning:
+		// Finished traces without an end time end with their last span.
+		t.DurationMs = traceEnd.Sub(t.StartTime).Milliseconds()
+	}
+	return nil
+}
+
+// latestTimestamp returns the latest start, end, or event time in t.
+func latestTimestamp(t *models.AgentTrace) time.Time {
+	latest := t.StartTime
+	later := func(ts time.Time) {
+		if ts.After(latest) {
+			latest = ts
+		}
+	}
+	if t.EndTime != nil {
+		later(*t.EndTime)
+	}
+	for _, s := range t.Spans {
+		later(s.StartTime)
+		if s.EndTime != nil {
+			later(*s.EndTime)
+		}
+		for _, e := range s.Events {
+			later(e.Timestamp)
+		}
+	}
+	return latest
+}
+
+// shiftTrace moves every timestamp in t by d.
+func shiftTrace(t *models.AgentTrace, d time.Duration) {
+	eachTimestamp(t, func(ts *time.Time) { *ts = ts.Add(d) })
+}
+
+// toUTC converts every timestamp in t to UTC.
+func toUTC(t *models.AgentTrace) {
+	eachTimestamp(t, func(ts *time.Time) { *ts = ts.UTC() })
+}
+
+func eachTimestamp(t *models.AgentTrace, fn func(*time.Time)) {
+	fn(&t.StartTime)
+	if t.EndTime != nil {
+		fn(t.EndTime)
+	}
+	for i := range t.Spans {
+		s := &t.Spans[i]
+		fn(&s.StartTime)
+		if s.EndTime != nil {
+			fn(s.EndTime)
+		}
+		for j := range s.Events {
+			fn(&s.Events[j].Timestamp)
+		}
+		if s.Data.Tool != nil && s.Data.Tool.PolicyDecision != nil {
+			fn(&s.Data.Tool.PolicyDecision.Timestamp)
+		}
+	}
+	for i := range t.SecuritySignals {
+		if !t.SecuritySignals[i].Timestamp.IsZero() {
+			fn(&t.SecuritySignals[i].Timestamp)
+		}
+	}
+}
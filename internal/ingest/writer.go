@@ -0,0 +1,193 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// Writer defaults.
+const (
+	DefaultBatchSize = 200
+	DefaultQueueSize = 2000
+)
+
+var (
+	// ErrBackpressure is returned when the write queue is full. Clients
+	// should retry the trace later.
+	ErrBackpressure = errors.New("trace write queue is full")
+	// ErrWriterStopped is returned once the writer has shut down.
+	ErrWriterStopped = errors.New("trace writer stopped")
+)
+
+// WriterConfig configures a Writer.
+type WriterConfig struct {
+	// BatchSize caps how many traces are written together. Defaults to
+	// DefaultBatchSize.
+	BatchSize int
+	// QueueSize is how many traces may wait to be written before new ones
+	// are refused with ErrBackpressure. Defaults to DefaultQueueSize.
+	QueueSize int
+}
+
+// Writer persists traces to one or more stores in batches. Traces that
+// arrive while a batch is being written are written together in the next
+// one, so batches grow with load and a lone trace is written at once.
+// InsertTrace returns only after its trace is stored, so a trace
+// acknowledged to the client is durable.
+//
+// Stores are written in order, each with the whole batch; put the store
+// holding spans before the one holding trace metadata. When a store fails a
+// batch, its traces are retried one at a time from that store on, so one bad
+// trace does not fail the others.
+type Writer struct {
+	stores    []repository.TraceWriter
+	batchSize int
+	queue     chan *pendingTrace
+	done      chan struct{}
+}
+
+type pendingTrace struct {
+	repository.OrgTrace
+	result chan error
+}
+
+// NewWriter creates a Writer over stores. Run must be running for traces to
+// be written.
+func NewWriter(cfg WriterConfig, stores ...repository.TraceWriter) *Writer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	return &Writer{
+		stores:    stores,
+		batchSize: cfg.BatchSize,
+		queue:     make(chan *pendingTrace, cfg.QueueSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// InsertTrace queues t and waits until it is written. It implements
+// repository.TraceWriter. When the queue is full it fails at once with
+// ErrBackpressure. If ctx ends first the trace may still be written.
+func (w *Writer) InsertTrace(ctx context.Context, orgID string, t *models.AgentTrace) error {
+	p := &pendingTrace{OrgTrace: repository.OrgTrace{OrgID: orgID, Trace: t}, result: make(chan error, 1)}
+	select {
+	case <-w.done:
+		return ErrWriterStopped
+	case w.queue <- p:
+	default:
+		return ErrBackpressure
+	}
+
+	select {
+	case err := <-p.result:
+		return err
+	case <-w.done:
+		// Traces queued before shutdown are written on the way out.
+		select {
+		case err := <-p.result:
+			return err
+		default:
+			return ErrWriterStopped
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Queued returns how many traces are waiting to be written.
+func (w *Writer) Queued() int {
+	return len(w.queue)
+}
+
+// Run writes queued traces until ctx is cancelled, then writes the traces
+// already queued and stops.
+func (w *Writer) Run(ctx context.Context) {
+	defer close(w.done)
+	batch := make([]*pendingTrace, 0, w.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx := context.WithoutCancel(ctx)
+			for {
+				select {
+				case p := <-w.queue:
+					batch = append(batch, p)
+					if len(batch) == w.batchSize {
+						w.flush(flushCtx, batch)
+						batch = batch[:0]
+					}
+				default:
+					w.flush(flushCtx, batch)
+					return
+				}
+			}
+		case p := <-w.queue:
+			batch = append(batch[:0], p)
+		fill:
+			for len(batch) < w.batchSize {
+				select {
+				case p := <-w.queue:
+					batch = append(batch, p)
+				default:
+					break fill
+				}
+			}
+			w.flush(ctx, batch)
+		}
+	}
+}
+
+// flush writes a batch and reports the result to each waiting caller.
+func (w *Writer) flush(ctx context.Context, batch []*pendingTrace) {
+	if len(batch) == 0 {
+		return
+	}
+	traces := make([]repository.OrgTrace, len(batch))
+	for i, p := range batch {
+		traces[i] = p.OrgTrace
+	}
+	failed, err := w.write(ctx, 0, traces)
+	if err != nil && len(batch) > 1 {
+		log.Warn().Err(err).Int("traces", len(batch)).Msg("trace batch write failed, retrying traces individually")
+		for i, p := range batch {
+			_, err := w.write(ctx, failed, traces[i:i+1])
+			p.result <- err
+		}
+		return
+	}
+	for _, p := range batch {
+		p.result <- err
+	}
+}
+
+// write stores traces in stores[from:], returning the index of the store
+// that failed.
+func (w *Writer) write(ctx context.Context, from int, traces []repository.OrgTrace) (int, error) {
+	for i := from; i < len(w.stores); i++ {
+		if err := insertTraces(ctx, w.stores[i], traces); err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+// insertTraces writes traces to one store, in a single call when it
+// supports batches.
+func insertTraces(ctx context.Context, store repository.TraceWriter, traces []repository.OrgTrace) error {
+	if b, ok := store.(repository.TraceBatchWriter); ok {
+		return b.InsertTraces(ctx, traces)
+	}
+	for _, t := range traces {
+		if err := store.InsertTrace(ctx, t.OrgID, t.Trace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
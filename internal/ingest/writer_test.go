@@ -0,0 +1,118 @@
+package ingest_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/ingest"
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+var _ repository.TraceWriter = (*ingest.Writer)(nil)
+
+// batchStore records the batches it is given. It fails any batch holding
+// the trace named bad, and blocks each write until release is closed.
+type batchStore struct {
+	mu      sync.Mutex
+	batches [][]string
+	bad     string
+	calls   int
+	release chan struct{}
+}
+
+func (s *batchStore) InsertTrace(ctx context.Context, orgID string, t *models.AgentTrace) error {
+	return s.InsertTraces(ctx, []repository.OrgTrace{{OrgID: orgID, Trace: t}})
+}
+
+func (s *batchStore) InsertTraces(_ context.Context, batch []repository.OrgTrace) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for _, ot := range batch {
+		if ot.Trace.TraceID == s.bad {
+			return errors.New("bad trace")
+		}
+		ids = append(ids, ot.Trace.TraceID)
+	}
+	s.batches = append(s.batches, ids)
+	return nil
+}
+
+func (s *batchStore) writing() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls > 0
+}
+
+func (s *batchStore) written() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.batches...)
+}
+
+func TestWriterBatches(t *testing.T) {
+	spans := &batchStore{bad: "bad", release: make(chan struct{})}
+	metadata := &batchStore{}
+	w := ingest.NewWriter(ingest.WriterConfig{BatchSize: 10, QueueSize: 4}, spans, metadata)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(stopped)
+	}()
+
+	// The first trace is taken off the queue and blocks in the span store;
+	// the next four fill the queue and the sixth is refused.
+	results := make(chan error, 5)
+	insert := func(id string) {
+		results <- w.InsertTrace(context.Background(), "org", &models.AgentTrace{TraceID: id})
+	}
+	go insert("first")
+	for !spans.writing() {
+		time.Sleep(time.Millisecond)
+	}
+	for _, id := range []string{"a", "b", "bad", "c"} {
+		go insert(id)
+	}
+	for w.Queued() != 4 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := w.InsertTrace(context.Background(), "org", &models.AgentTrace{TraceID: "d"}); !errors.Is(err, ingest.ErrBackpressure) {
+		t.Errorf("InsertTrace() with a full queue error = %v, want ErrBackpressure", err)
+	}
+
+	close(spans.release)
+	var failed int
+	for range 5 {
+		if err := <-results; err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("%d inserts failed, want only the bad trace", failed)
+	}
+
+	// The queued traces went as one batch, which failed on the bad trace and
+	// was retried one trace at a time; metadata never saw the bad trace.
+	for name, store := range map[string]*batchStore{"span": spans, "metadata": metadata} {
+		if got := store.written(); len(got) != 4 || len(got[0]) != 1 || len(got[1]) != 1 || got[0][0] != "first" {
+			t.Errorf("%s store batches = %v, want [first], then a, b and c alone", name, got)
+		}
+	}
+
+	cancel()
+	<-stopped
+	if err := w.InsertTrace(context.Background(), "org", &models.AgentTrace{TraceID: "late"}); !errors.Is(err, ingest.ErrWriterStopped) {
+		t.Errorf("InsertTrace() after Run returned error = %v, want ErrWriterStopped", err)
+	}
+}
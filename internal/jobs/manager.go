@@ -0,0 +1,344 @@
+// Package jobs runs expensive operations asynchronously on a bounded worker
+// pool so API requests can return immediately and clients poll for results.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Terminal reports whether the status is final.
+func (s Status) Terminal() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// ErrNotFound is returned when a job ID is unknown, has expired or belongs
+// to someone else.
+var ErrNotFound = errors.New("job not found")
+
+// ErrQueueFull is returned when the pending queue is at capacity.
+var ErrQueueFull = errors.New("job queue full")
+
+// Func is the work performed by a job. It must honor ctx cancellation.
+type Func func(ctx context.Context) (any, error)
+
+// Owner identifies who submitted a job. Jobs are only visible to the
+// organization and actor that submitted them.
+type Owner struct {
+	OrgID string
+	Actor string
+}
+
+// Job describes an asynchronous operation.
+type Job struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	OrgID       string `json:"org_id"`
+	SubmittedBy string `json:"submitted_by"`
+	// Scope is the API scope needed to read or cancel the job, that of the
+	// route that submitted it.
+	Scope      string     `json:"scope,omitempty"`
+	Status     Status     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	result any
+	fn     Func
+	cancel context.CancelFunc
+}
+
+// Config configures a Manager.
+type Config struct {
+	Workers   int           // concurrent jobs
+	QueueSize int           // pending jobs before Submit returns ErrQueueFull
+	Timeout   time.Duration // per-job execution limit
+	Retention time.Duration // how long finished jobs remain retrievable
+}
+
+// Manager queues jobs and executes them on a worker pool.
+type Manager struct {
+	cfg   Config
+	queue chan *Job
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewManager creates a manager and starts its workers.
+func NewManager(cfg Config) *Manager {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	m := &Manager{
+		cfg:   cfg,
+		queue: make(chan *Job, cfg.QueueSize),
+		jobs:  make(map[string]*Job),
+		ctx:   ctx,
+		stop:  stop,
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	m.wg.Add(1)
+	go m.reap()
+	return m
+}
+
+// Submit queues a job of the given type for owner. scope is recorded on
+// the job for the API to check when it is read or cancelled.
+func (m *Manager) Submit(owner Owner, jobType, scope string, fn Func) (*Job, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("job function is required")
+	}
+	job := &Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		OrgID:       owner.OrgID,
+		SubmittedBy: owner.Actor,
+		Scope:       scope,
+		Status:      StatusPending,
+		CreatedAt:   time.Now().UTC(),
+		fn:          fn,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, fmt.Errorf("job manager stopped")
+	}
+	select {
+	case m.queue <- job:
+	default:
+		return nil, ErrQueueFull
+	}
+	m.jobs[job.ID] = job
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// owned returns owner's job with the given ID. Callers must hold m.mu.
+func (m *Manager) owned(owner Owner, id string) (*Job, bool) {
+	job, ok := m.jobs[id]
+	if !ok || !job.ownedBy(owner) {
+		return nil, false
+	}
+	return job, true
+}
+
+// ownedBy reports whether owner submitted j.
+func (j *Job) ownedBy(owner Owner) bool {
+	return j.OrgID == owner.OrgID && j.SubmittedBy == owner.Actor
+}
+
+// Get returns a snapshot of owner's job.
+func (m *Manager) Get(owner Owner, id string) (*Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.owned(owner, id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Result returns owner's job with its result once it has succeeded.
+func (m *Manager) Result(owner Owner, id string) (any, *Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.owned(owner, id)
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	snapshot := *job
+	return job.result, &snapshot, nil
+}
+
+// List returns snapshots of owner's retained jobs, newest first, optionally
+// filtered by type.
+func (m *Manager) List(owner Owner, jobType string) []*Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]*Job, 0)
+	for _, job := range m.jobs {
+		if !job.ownedBy(owner) || jobType != "" && job.Type != jobType {
+			continue
+		}
+		snapshot := *job
+		result = append(result, &snapshot)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// Cancel stops owner's pending or running job. Cancelling a finished job is
+// a no-op.
+func (m *Manager) Cancel(owner Owner, id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.owned(owner, id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	switch job.Status {
+	case StatusPending:
+		m.finishLocked(job, StatusCancelled, nil, context.Canceled)
+	case StatusRunning:
+		job.cancel()
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// QueueLoad reports pending jobs as a fraction of queue capacity.
+func (m *Manager) QueueLoad() float64 {
+	return float64(len(m.queue)) / float64(cap(m.queue))
+}
+
+// Stop cancels running jobs and waits for workers to exit.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	m.stop()
+	m.wg.Wait()
+}
+
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case job := <-m.queue:
+			m.run(job)
+		}
+	}
+}
+
+func (m *Manager) run(job *Job) {
+	ctx, cancel := context.WithTimeout(m.ctx, m.cfg.Timeout)
+	defer cancel()
+
+	m.mu.Lock()
+	if job.Status != StatusPending {
+		// Cancelled while queued.
+		m.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	job.Status = StatusRunning
+	job.StartedAt = &now
+	job.cancel = cancel
+	m.mu.Unlock()
+
+	result, err := safeCall(ctx, job.fn)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := StatusSucceeded
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		status = StatusCancelled
+	case err != nil:
+		status = StatusFailed
+	}
+	m.finishLocked(job, status, result, err)
+
+	if status == StatusFailed {
+		log.Warn().Str("job_id", job.ID).Str("type", job.Type).Err(err).Msg("job failed")
+	}
+}
+
+// safeCall runs fn, converting a panic into an error so a single job cannot
+// take down a worker.
+func safeCall(ctx context.Context, fn Func) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// finishLocked records a terminal state. Callers must hold m.mu.
+func (m *Manager) finishLocked(job *Job, status Status, result any, err error) {
+	now := time.Now().UTC()
+	job.Status = status
+	job.FinishedAt = &now
+	job.fn = nil
+	if status == StatusSucceeded {
+		job.result = result
+	}
+	if err != nil {
+		job.Error = err.Error()
+	}
+}
+
+// reap removes finished jobs older than the retention period.
+func (m *Manager) reap() {
+	defer m.wg.Done()
+	interval := m.cfg.Retention / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := time.Now().Add(-m.cfg.Retention)
+			m.mu.Lock()
+			for id, job := range m.jobs {
+				if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+					delete(m.jobs, id)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/jobs"
+)
+
+var owner = jobs.Owner{OrgID: "acme", Actor: "api_key:ci"}
+
+func waitFor(t *testing.T, m *jobs.Manager, id string) *jobs.Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(owner, id)
+		if err != nil {
+			t.Fatalf("getting job: %v", err)
+		}
+		if job.Status.Terminal() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	m := jobs.NewManager(jobs.Config{Workers: 2})
+	defer m.Stop()
+
+	tests := []struct {
+		name       string
+		fn         jobs.Func
+		cancel     bool
+		wantStatus jobs.Status
+	}{
+		{
+			name:       "success stores result",
+			fn:         func(ctx context.Context) (any, error) { return "done", nil },
+			wantStatus: jobs.StatusSucceeded,
+		},
+		{
+			name:       "error marks failed",
+			fn:         func(ctx context.Context) (any, error) { return nil, errors.New("boom") },
+			wantStatus: jobs.StatusFailed,
+		},
+		{
+			name:       "panic marks failed",
+			fn:         func(ctx context.Context) (any, error) { panic("bad") },
+			wantStatus: jobs.StatusFailed,
+		},
+		{
+			name: "cancel stops running job",
+			fn: func(ctx context.Context) (any, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			cancel:     true,
+			wantStatus: jobs.StatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := m.Submit(owner, "test", "", tt.fn)
+			if err != nil {
+				t.Fatalf("submit: %v", err)
+			}
+			if tt.cancel {
+				for {
+					j, _ := m.Get(owner, job.ID)
+					if j.Status == jobs.StatusRunning {
+						break
+					}
+					time.Sleep(time.Millisecond)
+				}
+				if _, err := m.Cancel(owner, job.ID); err != nil {
+					t.Fatalf("cancel: %v", err)
+				}
+			}
+			got := waitFor(t, m, job.ID)
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (error %q)", got.Status, tt.wantStatus, got.Error)
+			}
+			if tt.wantStatus == jobs.StatusSucceeded {
+				result, _, _ := m.Result(owner, job.ID)
+				if result != "done" {
+					t.Errorf("result = %v, want done", result)
+				}
+			}
+		})
+	}
+
+	if _, err := m.Get(owner, "missing"); !errors.Is(err, jobs.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestManagerOwnership(t *testing.T) {
+	m := jobs.NewManager(jobs.Config{Workers: 1})
+	defer m.Stop()
+
+	job, err := m.Submit(owner, "report", "admin:privacy", func(ctx context.Context) (any, error) { return "secret", nil })
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if job.OrgID != "acme" || job.SubmittedBy != "api_key:ci" || job.Scope != "admin:privacy" {
+		t.Errorf("job = %+v, want it to record its owner and scope", job)
+	}
+	waitFor(t, m, job.ID)
+
+	for _, other := range []jobs.Owner{
+		{OrgID: "globex", Actor: "api_key:ci"},
+		{OrgID: "acme", Actor: "api_key:someone-else"},
+	} {
+		if _, err := m.Get(other, job.ID); !errors.Is(err, jobs.ErrNotFound) {
+			t.Errorf("Get by %+v: %v, want ErrNotFound", other, err)
+		}
+		if _, _, err := m.Result(other, job.ID); !errors.Is(err, jobs.ErrNotFound) {
+			t.Errorf("Result by %+v: %v, want ErrNotFound", other, err)
+		}
+		if _, err := m.Cancel(other, job.ID); !errors.Is(err, jobs.ErrNotFound) {
+			t.Errorf("Cancel by %+v: %v, want ErrNotFound", other, err)
+		}
+		if list := m.List(other, ""); len(list) != 0 {
+			t.Errorf("List by %+v = %d jobs, want none", other, len(list))
+		}
+	}
+	if list := m.List(owner, "report"); len(list) != 1 {
+		t.Errorf("List by owner = %d jobs, want 1", len(list))
+	}
+}
@@ -0,0 +1,183 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// Embedder turns texts into vectors whose cosine similarity reflects how
+// close their meanings are.
+type Embedder interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Name identifies the embedder and its model
+	Name() string
+}
+
+const (
+	OpenAIEmbeddingsURL   = "https://api.openai.com/v1/embeddings"
+	DefaultEmbeddingModel = "text-embedding-3-small"
+	DefaultHashDimensions = 1024
+)
+
+// HashEmbedder embeds texts locally by hashing their words and word pairs
+// into a fixed number of dimensions. It captures shared vocabulary rather
+// than meaning, but needs no external service.
+type HashEmbedder struct {
+	dims int
+}
+
+// NewHashEmbedder creates a local embedder with the given number of
+// dimensions, or DefaultHashDimensions when zero.
+func NewHashEmbedder(dims int) *HashEmbedder {
+	if dims <= 0 {
+		dims = DefaultHashDimensions
+	}
+	return &HashEmbedder{dims: dims}
+}
+
+// Embed hashes each text's terms into a unit vector
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, e.dims)
+		terms := embeddingTerms(text)
+		for j, t := range terms {
+			v[hashTerm(t)%uint32(e.dims)]++
+			if j > 0 {
+				v[hashTerm(terms[j-1]+" "+t)%uint32(e.dims)] += 0.5
+			}
+		}
+		out[i] = normalize(v)
+	}
+	return out, nil
+}
+
+// Name returns the embedder name
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-%d", e.dims)
+}
+
+// stopWords are too common in control text to say anything about it.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "their": true, "to": true, "with": true,
+}
+
+// embeddingTerms lowercases text, drops stop words and crudely stems the
+// rest so that "monitoring" and "monitored" share a term.
+func embeddingTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(w) < 2 || stopWords[w] {
+			continue
+		}
+		for _, suffix := range []string{"ation", "ing", "ed", "es", "s"} {
+			if len(w) > len(suffix)+3 && strings.HasSuffix(w, suffix) {
+				w = strings.TrimSuffix(w, suffix)
+				break
+			}
+		}
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+func hashTerm(t string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(t))
+	return h.Sum32()
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+// embeddingRequest is the OpenAI embeddings API request body.
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the OpenAI embeddings API response body.
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed embeds texts with the OpenAI embeddings API
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingRequest{Model: p.config.EmbeddingModel, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := OpenAIEmbeddingsURL
+	if p.config.BaseURL != "" {
+		url = strings.TrimSuffix(p.config.BaseURL, "/") + "/embeddings"
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	if p.config.Organization != "" {
+		httpReq.Header.Set("OpenAI-Organization", p.config.Organization)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var apiResp embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	out := make([][]float32, len(texts))
+	for _, d := range apiResp.Data {
+		if d.Index < 0 || d.Index >= len(out) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		out[d.Index] = d.Embedding
+	}
+	for i, v := range out {
+		if v == nil {
+			return nil, fmt.Errorf("no embedding returned for input %d", i)
+		}
+	}
+	return out, nil
+}
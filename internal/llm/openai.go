@@ -3,6 +3,8 @@ package llm
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 // OpenAIConfig holds configuration for the OpenAI provider
@@ -12,11 +14,14 @@ type OpenAIConfig struct {
 	MaxTokens    int
 	Organization string
 	BaseURL      string // For Azure OpenAI or compatible APIs
+	// EmbeddingModel is the model Embed uses
+	EmbeddingModel string
 }
 
 // OpenAIProvider implements the LLM Provider interface for OpenAI
 type OpenAIProvider struct {
 	config OpenAIConfig
+	client *http.Client
 }
 
 // NewOpenAIProvider creates a new OpenAI provider
@@ -33,8 +38,15 @@ func NewOpenAIProvider(cfg OpenAIConfig) (*OpenAIProvider, error) {
 		cfg.MaxTokens = 4096
 	}
 
+	if cfg.EmbeddingModel == "" {
+		cfg.EmbeddingModel = DefaultEmbeddingModel
+	}
+
 	return &OpenAIProvider{
 		config: cfg,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
 	}, nil
 }
 
@@ -21,6 +21,27 @@ type Framework struct {
 	URL         string    `json:"url" db:"url"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// License states the terms restricting redistribution of a catalog's
+	// text. Empty for openly licensed catalogs.
+	License string `json:"license,omitempty" db:"-"`
+	// TextUnavailable is set on a restricted catalog whose licensed copy
+	// was not provided, so its controls carry IDs and titles only.
+	TextUnavailable bool `json:"text_unavailable,omitempty" db:"-"`
+}
+
+// FrameworkVersion is a version of a framework's catalog. Superseded
+// versions are kept so assessments can be re-baselined against a newer one.
+type FrameworkVersion struct {
+	FrameworkID  string    `json:"framework_id" db:"framework_id"`
+	Version      string    `json:"version" db:"version"`
+	Name         string    `json:"name" db:"name"`
+	Publisher    string    `json:"publisher" db:"publisher"`
+	Description  string    `json:"description" db:"description"`
+	URL          string    `json:"url" db:"url"`
+	Current      bool      `json:"current" db:"-"`
+	ControlCount int       `json:"control_count" db:"-"`
+	Controls     []Control `json:"controls,omitempty" db:"controls"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
 
 // Control represents a single control within a framework.
@@ -35,6 +56,10 @@ type Control struct {
 	EvidenceTypes    []string `json:"evidence_types" db:"evidence_types"`
 	ApplicableLayers []string `json:"applicable_layers" db:"applicable_layers"`
 	ParentControlID  *string  `json:"parent_control_id,omitempty" db:"parent_control_id"`
+	Family           string   `json:"family,omitempty" db:"family"` // e.g. "Access Control"
+	// Tags are the requesting organization's labels for the control, such
+	// as "agent-runtime". They are stored apart from the catalog.
+	Tags []string `json:"tags,omitempty" db:"-"`
 }
 
 // MappingType defines the relationship between source and target controls.
@@ -61,10 +86,32 @@ type Crosswalk struct {
 	Gaps               []string    `json:"gaps" db:"gaps"`
 	Supplements        []string    `json:"supplements" db:"supplements"`
 	EvidenceMapping    []string    `json:"evidence_mapping" db:"evidence_mapping"`
+	// Derived marks a mapping inferred by chaining mappings through an
+	// intermediate framework; Via lists the intermediate control(s) as
+	// framework:control. Derived mappings are suggestions for review.
+	Derived            bool        `json:"derived,omitempty" db:"-"`
+	Via                []string    `json:"via,omitempty" db:"-"`
+	// ReviewState tracks an analyst-curated mapping through review; only
+	// approved mappings override the built-in ones. Imported mappings are
+	// approved.
+	ReviewState        ReviewState `json:"review_state,omitempty" db:"review_state"`
+	ReviewedBy         string      `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt         *time.Time  `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ApprovedBy         string      `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt         *time.Time  `json:"approved_at,omitempty" db:"approved_at"`
 	CreatedAt          time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time   `json:"updated_at" db:"updated_at"`
 }
 
+// ReviewState is the review stage of a curated crosswalk.
+type ReviewState string
+
+const (
+	ReviewProposed ReviewState = "proposed"
+	ReviewReviewed ReviewState = "reviewed"
+	ReviewApproved ReviewState = "approved"
+)
+
 // GapAnalysis represents identified gaps in control coverage.
 type GapAnalysis struct {
 	ID                string        `json:"id" db:"id"`
@@ -78,12 +125,30 @@ type GapAnalysis struct {
 
 // ControlGap represents a specific gap in control coverage.
 type ControlGap struct {
-	ControlID          string   `json:"control_id"`
-	GapType            string   `json:"gap_type"`
-	Description        string   `json:"description"`
-	RemediationOptions []string `json:"remediation_options"`
-	Priority           string   `json:"priority"`
-	EstimatedEffort    string   `json:"estimated_effort"`
+	ControlID          string                 `json:"control_id"`
+	GapType            string                 `json:"gap_type"`
+	Description        string                 `json:"description"`
+	RemediationOptions []string               `json:"remediation_options"`
+	Priority           string                 `json:"priority"`
+	EstimatedEffort    string                 `json:"estimated_effort"`
+	CoverageScore      float64                `json:"coverage_score,omitempty"`
+	CoveredBy          []CoverageContribution `json:"covered_by,omitempty"`
+	// Disposition is the organization's decision on the gap when one was
+	// in effect.
+	Disposition Disposition `json:"disposition,omitempty"`
+}
+
+// CoverageContribution records how an implemented control in another framework
+// covers a target control through a crosswalk mapping.
+type CoverageContribution struct {
+	FrameworkID string      `json:"framework_id"`
+	ControlID   string      `json:"control_id"`
+	MappingType MappingType `json:"mapping_type"`
+	Confidence  float64     `json:"confidence"`
+	Credit      float64     `json:"credit"`
+	// Derived marks credit through a mapping inferred via an intermediate
+	// framework, which can only partially cover a control.
+	Derived bool `json:"derived,omitempty"`
 }
 
 // GapSummary provides aggregate gap statistics.
@@ -92,8 +157,178 @@ type GapSummary struct {
 	FullyCovered       int            `json:"fully_covered"`
 	PartiallyCovered   int            `json:"partially_covered"`
 	NotCovered         int            `json:"not_covered"`
+	CrosswalkCovered   int            `json:"crosswalk_covered"`
+	InheritedCovered   int            `json:"inherited_covered"`
 	CoveragePercentage float64        `json:"coverage_percentage"`
-	GapsByPriority     map[string]int `json:"gaps_by_priority"`
+	// AcceptedRisk and TransferredRisk count the gaps whose risk was
+	// accepted or transferred rather than remediated.
+	AcceptedRisk    int `json:"accepted_risk,omitempty"`
+	TransferredRisk int `json:"transferred_risk,omitempty"`
+	// GapsByPriority counts the open gaps: those being remediated or not
+	// yet decided.
+	GapsByPriority map[string]int `json:"gaps_by_priority"`
+}
+
+// ControlProvider is a platform or shared service (e.g., the cloud landing zone)
+// that implements common controls inherited by the systems built on top of it.
+type ControlProvider struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Owner       string    `json:"owner" db:"owner"`
+	Controls    []string  `json:"controls" db:"controls"` // Control IDs provided as common controls
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ImplementationSource describes where a control implementation comes from.
+type ImplementationSource string
+
+const (
+	ImplementationLocal     ImplementationSource = "local"     // Implemented by the system itself
+	ImplementationInherited ImplementationSource = "inherited" // Provided entirely by a common control provider
+	ImplementationHybrid    ImplementationSource = "hybrid"    // Shared between the system and a provider
+)
+
+// VerificationMethod describes how a control implementation was confirmed.
+type VerificationMethod string
+
+const (
+	VerificationAttested  VerificationMethod = "attested"  // Declared by the system owner
+	VerificationAutomated VerificationMethod = "automated" // Confirmed by a continuous monitoring check
+)
+
+// ImplementedControl is one entry in a system's implementation inventory.
+type ImplementedControl struct {
+	ControlID    string               `json:"control_id"`
+	Source       ImplementationSource `json:"source"`
+	ProviderIDs  []string             `json:"provider_ids,omitempty"`
+	Verification VerificationMethod   `json:"verification,omitempty"`
+	VerifiedAt   *time.Time           `json:"verified_at,omitempty"`
+}
+
+// ImplementationStatus is the progress of a tracked control implementation.
+type ImplementationStatus string
+
+const (
+	ImplementationPlanned     ImplementationStatus = "planned"
+	ImplementationInProgress  ImplementationStatus = "in_progress"
+	ImplementationImplemented ImplementationStatus = "implemented"
+	ImplementationVerified    ImplementationStatus = "verified" // Implemented and confirmed by review or testing
+)
+
+// ControlImplementation tracks an organization's work on one framework
+// control. Implemented and verified controls are the organization's
+// implemented controls in gap analysis.
+type ControlImplementation struct {
+	ID             string               `json:"id" db:"id"`
+	OrganizationID string               `json:"organization_id" db:"organization_id"`
+	FrameworkID    string               `json:"framework_id" db:"framework_id"`
+	ControlID      string               `json:"control_id" db:"control_id"`
+	Status         ImplementationStatus `json:"status" db:"status"`
+	Owner          string               `json:"owner,omitempty" db:"owner"`
+	DueDate        *time.Time           `json:"due_date,omitempty" db:"due_date"`
+	Notes          string               `json:"notes,omitempty" db:"notes"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// ControlTags are the labels an organization puts on one framework
+// control, such as "agent-runtime" or "data-pipeline", to filter controls
+// and to prioritize their gaps.
+type ControlTags struct {
+	OrganizationID string    `json:"organization_id" db:"organization_id"`
+	FrameworkID    string    `json:"framework_id" db:"framework_id"`
+	ControlID      string    `json:"control_id" db:"control_id"`
+	Tags           []string  `json:"tags" db:"tags"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Disposition is how an organization decided to treat a control gap.
+type Disposition string
+
+const (
+	DispositionRemediate Disposition = "remediate" // The gap will be closed
+	DispositionAccept    Disposition = "accept"    // The risk is accepted
+	DispositionTransfer  Disposition = "transfer"  // The risk is transferred, e.g. to an insurer or vendor
+)
+
+// GapDisposition records an organization's decision on a framework
+// control's gap. Accepted and transferred risks are approved, justified
+// and expire; an expired decision leaves the gap open again.
+type GapDisposition struct {
+	ID             string      `json:"id" db:"id"`
+	OrganizationID string      `json:"organization_id" db:"organization_id"`
+	FrameworkID    string      `json:"framework_id" db:"framework_id"`
+	ControlID      string      `json:"control_id" db:"control_id"`
+	Disposition    Disposition `json:"disposition" db:"disposition"`
+	Approver       string      `json:"approver,omitempty" db:"approver"`
+	Justification  string      `json:"justification,omitempty" db:"justification"`
+	TransferredTo  string      `json:"transferred_to,omitempty" db:"transferred_to"` // Who carries a transferred risk
+	ExpiresAt      *time.Time  `json:"expires_at,omitempty" db:"expires_at"`
+	// ReviewIntervalDays is how often the decision is re-reviewed. Zero
+	// reviews it only before it expires.
+	ReviewIntervalDays int        `json:"review_interval_days" db:"review_interval_days"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewedBy         string     `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	LastRemindedAt     *time.Time `json:"last_reminded_at,omitempty" db:"last_reminded_at"`
+	CreatedBy          string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CampaignStatus is the state of an attestation campaign.
+type CampaignStatus string
+
+const (
+	CampaignOpen   CampaignStatus = "open"
+	CampaignClosed CampaignStatus = "closed"
+)
+
+// AttestationStatus is a control owner's response in an attestation
+// campaign.
+type AttestationStatus string
+
+const (
+	AttestationPending  AttestationStatus = "pending"
+	AttestationAttested AttestationStatus = "attested" // The owner confirms the control is in place
+	AttestationDeclined AttestationStatus = "declined" // The owner cannot confirm it
+)
+
+// AttestationCampaign asks control owners to confirm, by a due date, that
+// the controls recorded as implemented are still in place.
+type AttestationCampaign struct {
+	ID             string         `json:"id" db:"id"`
+	OrganizationID string         `json:"organization_id" db:"organization_id"`
+	Name           string         `json:"name" db:"name"`
+	FrameworkID    string         `json:"framework_id,omitempty" db:"framework_id"` // Empty covers every framework
+	Status         CampaignStatus `json:"status" db:"status"`
+	DueDate        time.Time      `json:"due_date" db:"due_date"`
+	// ReminderIntervalDays is how often owners with pending attestations
+	// are reminded. Zero disables reminders.
+	ReminderIntervalDays int        `json:"reminder_interval_days" db:"reminder_interval_days"`
+	CreatedBy            string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	ClosedAt             *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+}
+
+// Attestation is one control assigned to its owner in a campaign.
+type Attestation struct {
+	ID               string            `json:"id" db:"id"`
+	CampaignID       string            `json:"campaign_id" db:"campaign_id"`
+	OrganizationID   string            `json:"organization_id" db:"organization_id"`
+	ImplementationID string            `json:"implementation_id" db:"implementation_id"`
+	FrameworkID      string            `json:"framework_id" db:"framework_id"`
+	ControlID        string            `json:"control_id" db:"control_id"`
+	Owner            string            `json:"owner" db:"owner"`
+	Status           AttestationStatus `json:"status" db:"status"`
+	Comment          string            `json:"comment,omitempty" db:"comment"`
+	RespondedBy      string            `json:"responded_by,omitempty" db:"responded_by"`
+	RespondedAt      *time.Time        `json:"responded_at,omitempty" db:"responded_at"`
+	Reminders        int               `json:"reminders" db:"reminders"`
+	LastRemindedAt   *time.Time        `json:"last_reminded_at,omitempty" db:"last_reminded_at"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 // -----------------------------------------------------------------------------
@@ -147,6 +382,55 @@ type ToolBinding struct {
 	Parameters  map[string]string `json:"parameters"`
 }
 
+// SigningKey is a shared secret an agent signs its SDK hook requests with.
+// The secret is only ever returned when the key is issued.
+type SigningKey struct {
+	ID        string    `json:"id" db:"id"`
+	AgentID   string    `json:"agent_id" db:"agent_id"`
+	Secret    string    `json:"-" db:"secret"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// ExpiresAt is set when a newer key replaces this one; the key is
+	// accepted until then so agents can pick up the new key.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// AgentGroup is a crew or orchestration of agents that delegate work to
+// each other.
+type AgentGroup struct {
+	ID          string        `json:"id" db:"id"`
+	Name        string        `json:"name" db:"name"`
+	Description string        `json:"description" db:"description"`
+	Framework   string        `json:"framework" db:"framework"` // crewai, autogen, langgraph
+	Members     []GroupMember `json:"members" db:"members"`
+	Delegations []Delegation  `json:"delegations" db:"delegations"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// GroupMember is an agent's place in a group.
+type GroupMember struct {
+	AgentID string `json:"agent_id"`
+	Name    string `json:"name,omitempty"`
+	Role    string `json:"role,omitempty"` // e.g. manager, researcher
+	// TrustZone groups agents that trust each other; delegations between
+	// zones cross a trust boundary. Empty is the "default" zone.
+	TrustZone string        `json:"trust_zone,omitempty"`
+	Tools     []ToolBinding `json:"tools,omitempty"`
+	Policies  []string      `json:"policies,omitempty"` // Policy IDs bound to agent
+}
+
+// Delegation is an edge along which one agent hands work to another.
+type Delegation struct {
+	From string `json:"from"` // Agent IDs
+	To   string `json:"to"`
+	// Tools limits the delegate to these tools when acting for the caller;
+	// empty leaves all of its tools available.
+	Tools []string `json:"tools,omitempty"`
+	// Authenticated marks delegations whose caller identity the delegate
+	// verifies, e.g. with a signed token.
+	Authenticated bool `json:"authenticated,omitempty"`
+}
+
 // -----------------------------------------------------------------------------
 // Observability Models
 // -----------------------------------------------------------------------------
@@ -165,6 +449,15 @@ type AgentTrace struct {
 	SecuritySignals []SecuritySignal `json:"security_signals" db:"security_signals"`
 	Metrics        TraceMetrics    `json:"metrics" db:"metrics"`
 	Metadata       map[string]any  `json:"metadata" db:"metadata"`
+	// Parent links a worker agent's trace to the supervisor span that
+	// delegated to it, so delegations form a connected trace forest.
+	Parent *TraceLink `json:"parent,omitempty" db:"-"`
+}
+
+// TraceLink points at a span in another trace.
+type TraceLink struct {
+	TraceID string `json:"trace_id"`
+	SpanID  string `json:"span_id"`
 }
 
 // TraceStatus represents the outcome of a trace.
@@ -229,6 +522,9 @@ type LLMSpanData struct {
 	MaxTokens        int     `json:"max_tokens"`
 	PromptHash       string  `json:"prompt_hash"` // For prompt tracking without storing content
 	FinishReason     string  `json:"finish_reason"`
+	// Prompt is the raw prompt an SDK may send so the server computes
+	// PromptHash. It is never persisted.
+	Prompt any `json:"prompt,omitempty"`
 }
 
 // RetrievalSpanData contains data specific to retrieval operations.
@@ -249,6 +545,21 @@ type ToolSpanData struct {
 	ParameterCount int            `json:"parameter_count"`
 	ExternalCall   bool           `json:"external_call"`
 	PolicyDecision *PolicyDecision `json:"policy_decision,omitempty"`
+	// Input and Output are raw payloads an SDK may send. They are never
+	// persisted inline: large ones are moved to blob storage and replaced
+	// by InputRef/OutputRef, the rest are dropped after hashing.
+	Input     any         `json:"input,omitempty"`
+	Output    any         `json:"output,omitempty"`
+	InputRef  *PayloadRef `json:"input_ref,omitempty"`
+	OutputRef *PayloadRef `json:"output_ref,omitempty"`
+}
+
+// PayloadRef points to a payload in content-addressable blob storage.
+type PayloadRef struct {
+	Hash        string    `json:"hash"` // sha256:<hex>
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
 
 // PolicyDecision records a policy evaluation result.
@@ -285,6 +596,10 @@ const (
 	SignalAnomalousBehavior   SignalType = "anomalous_behavior"
 	SignalPolicyViolation     SignalType = "policy_violation"
 	SignalRateLimitExceeded   SignalType = "rate_limit_exceeded"
+	SignalFailOpen            SignalType = "fail_open" // Call allowed without a policy decision
+	SignalIdentityMismatch    SignalType = "identity_mismatch" // Caller claimed an agent other than its authenticated identity
+	SignalInvalidOutput       SignalType = "invalid_output" // Agent output failed its registered JSON Schema
+	SignalHoneypotTriggered   SignalType = "honeypot_triggered" // Agent attempted to call a decoy tool
 )
 
 // TraceMetrics contains aggregate metrics for a trace.
@@ -391,6 +706,21 @@ type Threat struct {
 	RiskLevel        string       `json:"risk_level"` // calculated from likelihood x impact
 	ATLASTechniques  []string     `json:"atlas_techniques"`
 	MitigationIDs    []string     `json:"mitigation_ids"`
+	// BaseLikelihood is the design-time likelihood, kept once observed
+	// signals have raised Likelihood above it.
+	BaseLikelihood     string              `json:"base_likelihood,omitempty"`
+	LikelihoodEvidence *LikelihoodEvidence `json:"likelihood_evidence,omitempty"`
+}
+
+// LikelihoodEvidence records the runtime signals that raised a threat's
+// likelihood above its design-time value.
+type LikelihoodEvidence struct {
+	SignalTypes  []SignalType `json:"signal_types"`
+	Signals      int64        `json:"signals"`
+	Score        float64      `json:"score"` // severity-weighted signal count
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	CalibratedAt time.Time    `json:"calibrated_at"`
 }
 
 // STRIDECategory represents STRIDE threat categories.
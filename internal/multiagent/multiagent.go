@@ -0,0 +1,262 @@
+// Package multiagent models groups of agents that delegate work to each
+// other, such as crews and orchestrations, and works out the restrictions
+// each agent inherits from the agents that call it: a delegate is bound by
+// its callers' policies as well as its own, and by every tool limit along
+// the delegation chain.
+package multiagent
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// DefaultZone is the trust zone of members that name none.
+const DefaultZone = "default"
+
+// maxChainLength bounds the agents in an enumerated delegation chain.
+const maxChainLength = 8
+
+// Zone returns a member's trust zone.
+func Zone(m models.GroupMember) string {
+	if m.TrustZone == "" {
+		return DefaultZone
+	}
+	return m.TrustZone
+}
+
+// ToolName is the name a tool is referred to by in delegation limits and
+// policies: its name, or its ID when it has none.
+func ToolName(t models.ToolBinding) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.ToolID
+}
+
+// Validate checks that a group's members are unique and its delegations
+// connect members, limiting delegates to tools they have.
+func Validate(g *models.AgentGroup) error {
+	var problems []string
+	if len(g.Members) == 0 {
+		problems = append(problems, "group has no members")
+	}
+	members := make(map[string]*models.GroupMember, len(g.Members))
+	for i := range g.Members {
+		m := &g.Members[i]
+		switch {
+		case m.AgentID == "":
+			problems = append(problems, fmt.Sprintf("members[%d]: agent_id is required", i))
+		case members[m.AgentID] != nil:
+			problems = append(problems, fmt.Sprintf("members[%d]: duplicate agent %s", i, m.AgentID))
+		default:
+			members[m.AgentID] = m
+		}
+	}
+
+	edges := make(map[[2]string]bool, len(g.Delegations))
+	for i, d := range g.Delegations {
+		from, to := members[d.From], members[d.To]
+		switch {
+		case from == nil:
+			problems = append(problems, fmt.Sprintf("delegations[%d]: unknown agent %q", i, d.From))
+			continue
+		case to == nil:
+			problems = append(problems, fmt.Sprintf("delegations[%d]: unknown agent %q", i, d.To))
+			continue
+		case d.From == d.To:
+			problems = append(problems, fmt.Sprintf("delegations[%d]: %s delegates to itself", i, d.From))
+			continue
+		case edges[[2]string{d.From, d.To}]:
+			problems = append(problems, fmt.Sprintf("delegations[%d]: duplicate delegation %s -> %s", i, d.From, d.To))
+			continue
+		}
+		edges[[2]string{d.From, d.To}] = true
+		if len(to.Tools) == 0 {
+			continue
+		}
+		for _, name := range d.Tools {
+			if !slices.ContainsFunc(to.Tools, func(t models.ToolBinding) bool { return ToolName(t) == name }) {
+				problems = append(problems, fmt.Sprintf("delegations[%d]: %s has no tool %q", i, d.To, name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid agent group: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Chain is a sequence of delegations, caller first.
+type Chain struct {
+	Agents []string `json:"agents"`
+	// Tools are the tools the last agent may use when acting for the
+	// first: its own, narrowed by every delegation's limit on the way.
+	Tools []string `json:"tools"`
+	// CrossesBoundary is set when the chain passes between trust zones.
+	CrossesBoundary bool `json:"crosses_boundary"`
+}
+
+// Scope is what binds an agent in a group.
+type Scope struct {
+	AgentID string `json:"agent_id"`
+	// Callers are the agents that can delegate to this one, directly or
+	// through others.
+	Callers []string `json:"callers"`
+	// Policies apply to the agent: its own, then those inherited from its
+	// callers.
+	Policies []string `json:"policies"`
+	// Inherited maps each inherited policy to the callers it comes from.
+	Inherited map[string][]string `json:"inherited,omitempty"`
+	// Chains are the delegation chains ending at the agent.
+	Chains []Chain `json:"chains,omitempty"`
+}
+
+// Propagate works out each member's scope. The group must be valid.
+func Propagate(g *models.AgentGroup) []Scope {
+	members := make(map[string]*models.GroupMember, len(g.Members))
+	for i := range g.Members {
+		members[g.Members[i].AgentID] = &g.Members[i]
+	}
+	out := make(map[string][]models.Delegation)
+	for _, d := range g.Delegations {
+		out[d.From] = append(out[d.From], d)
+	}
+
+	chains := make(map[string][]Chain)
+	var walk func(path []string, limit []string, limited, crosses bool)
+	walk = func(path []string, limit []string, limited, crosses bool) {
+		if len(path) >= maxChainLength {
+			return
+		}
+		last := members[path[len(path)-1]]
+		for _, d := range out[last.AgentID] {
+			if slices.Contains(path, d.To) {
+				continue
+			}
+			next := members[d.To]
+			nextLimit, nextLimited := limit, limited
+			if len(d.Tools) > 0 {
+				if limited {
+					nextLimit = intersect(limit, d.Tools)
+				} else {
+					nextLimit = slices.Clone(d.Tools)
+				}
+				nextLimited = true
+			}
+			nextCrosses := crosses || Zone(*last) != Zone(*next)
+			nextPath := append(slices.Clone(path), d.To)
+
+			var tools []string
+			for _, t := range next.Tools {
+				if name := ToolName(t); !nextLimited || slices.Contains(nextLimit, name) {
+					tools = append(tools, name)
+				}
+			}
+			if tools == nil {
+				tools = []string{}
+			}
+			chains[d.To] = append(chains[d.To], Chain{Agents: nextPath, Tools: tools, CrossesBoundary: nextCrosses})
+			walk(nextPath, nextLimit, nextLimited, nextCrosses)
+		}
+	}
+	for _, m := range g.Members {
+		walk([]string{m.AgentID}, nil, false, false)
+	}
+
+	scopes := make([]Scope, 0, len(g.Members))
+	for _, m := range g.Members {
+		s := Scope{
+			AgentID:   m.AgentID,
+			Callers:   []string{},
+			Policies:  append([]string{}, m.Policies...),
+			Inherited: make(map[string][]string),
+			Chains:    chains[m.AgentID],
+		}
+		callers := make(map[string]bool)
+		for _, c := range s.Chains {
+			for _, id := range c.Agents[:len(c.Agents)-1] {
+				callers[id] = true
+			}
+		}
+		for id := range callers {
+			s.Callers = append(s.Callers, id)
+		}
+		sort.Strings(s.Callers)
+		for _, caller := range s.Callers {
+			for _, p := range members[caller].Policies {
+				if !slices.Contains(m.Policies, p) {
+					s.Inherited[p] = append(s.Inherited[p], caller)
+				}
+			}
+		}
+		inherited := make([]string, 0, len(s.Inherited))
+		for p := range s.Inherited {
+			inherited = append(inherited, p)
+		}
+		sort.Strings(inherited)
+		s.Policies = append(s.Policies, inherited...)
+		if len(s.Inherited) == 0 {
+			s.Inherited = nil
+		}
+		scopes = append(scopes, s)
+	}
+	return scopes
+}
+
+// Cycles returns the delegation loops in a group, each starting at its
+// lowest agent ID.
+func Cycles(g *models.AgentGroup) [][]string {
+	out := make(map[string][]string)
+	for _, d := range g.Delegations {
+		out[d.From] = append(out[d.From], d.To)
+	}
+	seen := make(map[string]bool)
+	var cycles [][]string
+	var walk func(path []string)
+	walk = func(path []string) {
+		for _, next := range out[path[len(path)-1]] {
+			if i := slices.Index(path, next); i >= 0 {
+				cycle := rotate(path[i:])
+				key := strings.Join(cycle, "\x00")
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+				continue
+			}
+			if len(path) < maxChainLength {
+				walk(append(slices.Clone(path), next))
+			}
+		}
+	}
+	for _, m := range g.Members {
+		walk([]string{m.AgentID})
+	}
+	return cycles
+}
+
+// rotate returns a copy of a cycle starting at its lowest element.
+func rotate(cycle []string) []string {
+	lowest := 0
+	for i, id := range cycle {
+		if id < cycle[lowest] {
+			lowest = i
+		}
+	}
+	return append(slices.Clone(cycle[lowest:]), cycle[:lowest]...)
+}
+
+func intersect(a, b []string) []string {
+	out := []string{}
+	for _, x := range a {
+		if slices.Contains(b, x) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
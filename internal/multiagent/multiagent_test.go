@@ -0,0 +1,137 @@
+package multiagent_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/multiagent"
+)
+
+func crew() *models.AgentGroup {
+	return &models.AgentGroup{
+		ID:   "g1",
+		Name: "research crew",
+		Members: []models.GroupMember{
+			{AgentID: "planner", TrustZone: "internal", Policies: []string{"no-pii"},
+				Tools: []models.ToolBinding{{Name: "web_browse", Category: "web_search"}}},
+			{AgentID: "analyst", TrustZone: "internal", Policies: []string{"least-privilege"},
+				Tools: []models.ToolBinding{{Name: "customer_db", Category: "database"}, {Name: "send_email", Category: "email"}}},
+			{AgentID: "executor", TrustZone: "sandbox",
+				Tools: []models.ToolBinding{{Name: "python_repl", Category: "code_execution"}, {Name: "shell", Category: "code_execution"}}},
+		},
+		Delegations: []models.Delegation{
+			{From: "planner", To: "analyst"},
+			{From: "analyst", To: "executor", Tools: []string{"python_repl"}},
+			{From: "executor", To: "analyst", Authenticated: true},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := multiagent.Validate(crew()); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	g := crew()
+	g.Delegations = append(g.Delegations,
+		models.Delegation{From: "planner", To: "ghost"},
+		models.Delegation{From: "planner", To: "planner"},
+		models.Delegation{From: "planner", To: "executor", Tools: []string{"send_email"}},
+	)
+	err := multiagent.Validate(g)
+	if err == nil {
+		t.Fatal("Validate accepted an invalid group")
+	}
+	for _, want := range []string{"ghost", "planner delegates to itself", "send_email"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	scopes := multiagent.Propagate(crew())
+	var executor multiagent.Scope
+	for _, s := range scopes {
+		if s.AgentID == "executor" {
+			executor = s
+		}
+	}
+
+	if want := []string{"analyst", "planner"}; !reflect.DeepEqual(executor.Callers, want) {
+		t.Errorf("callers = %v, want %v", executor.Callers, want)
+	}
+	if want := []string{"least-privilege", "no-pii"}; !reflect.DeepEqual(executor.Policies, want) {
+		t.Errorf("policies = %v, want %v", executor.Policies, want)
+	}
+	if got := executor.Inherited["no-pii"]; !reflect.DeepEqual(got, []string{"planner"}) {
+		t.Errorf("no-pii inherited from %v, want [planner]", got)
+	}
+	if len(executor.Chains) != 2 {
+		t.Fatalf("chains = %+v, want 2", executor.Chains)
+	}
+	for _, c := range executor.Chains {
+		// The analyst's limit narrows every chain through it.
+		if !reflect.DeepEqual(c.Tools, []string{"python_repl"}) || !c.CrossesBoundary {
+			t.Errorf("chain %v: tools %v, crosses %v; want [python_repl] crossing", c.Agents, c.Tools, c.CrossesBoundary)
+		}
+	}
+}
+
+func TestCycles(t *testing.T) {
+	got := multiagent.Cycles(crew())
+	if want := [][]string{{"analyst", "executor"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles = %v, want %v", got, want)
+	}
+}
+
+func TestSpawnPolicy(t *testing.T) {
+	g := crew()
+	g.Members = append(g.Members, models.GroupMember{AgentID: "scout", Policies: []string{"rate-limited"},
+		Tools: []models.ToolBinding{{Name: "web_browse", Category: "web_search"}, {Name: "fetch_url", Category: "web_search"}}})
+	g.Members[0].Tools = append(g.Members[0].Tools, models.ToolBinding{Name: "fetch_url", Category: "web_search"})
+	g.Delegations = append(g.Delegations, models.Delegation{From: "planner", To: "scout", Tools: []string{"web_browse"}})
+	policy, err := multiagent.NewSpawnPolicy([]models.AgentGroup{*g})
+	if err != nil {
+		t.Fatalf("NewSpawnPolicy: %v", err)
+	}
+
+	// The delegation narrows scout to web_browse; it inherits the planner's
+	// policies.
+	grant, violations := policy.Check(multiagent.SpawnRequest{Parent: "planner", Child: "scout"})
+	if grant == nil {
+		t.Fatalf("planner -> scout denied: %v", violations)
+	}
+	if want := []string{"web_browse"}; !reflect.DeepEqual(grant.Tools, want) {
+		t.Errorf("granted tools = %v, want %v", grant.Tools, want)
+	}
+	if want := []string{"rate-limited", "no-pii"}; !reflect.DeepEqual(grant.Policies, want) {
+		t.Errorf("granted policies = %v, want %v", grant.Policies, want)
+	}
+
+	tests := []struct {
+		name string
+		req  multiagent.SpawnRequest
+		rule string
+	}{
+		{"unregistered child", multiagent.SpawnRequest{Parent: "planner", Child: "rogue"}, multiagent.RuleChildUnregistered},
+		{"not delegated to", multiagent.SpawnRequest{Parent: "scout", Child: "planner"}, multiagent.RuleChildNotAllowed},
+		{"unknown parent", multiagent.SpawnRequest{Parent: "intruder", Child: "scout"}, multiagent.RuleChildNotAllowed},
+		{"tool beyond delegation", multiagent.SpawnRequest{Parent: "planner", Child: "scout", Tools: []string{"fetch_url"}}, multiagent.RulePrivilegeEscalation},
+		{"unregistered tool", multiagent.SpawnRequest{Parent: "planner", Child: "scout", Tools: []string{"shell"}}, multiagent.RulePrivilegeEscalation},
+		{"tool the parent lacks", multiagent.SpawnRequest{Parent: "planner", Child: "analyst"}, multiagent.RulePrivilegeEscalation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, violations := policy.Check(tt.req)
+			if grant != nil {
+				t.Fatalf("spawn allowed with %v", grant.Tools)
+			}
+			if len(violations) == 0 || violations[0].Rule != tt.rule {
+				t.Errorf("violations = %v, want rule %s", violations, tt.rule)
+			}
+		})
+	}
+}
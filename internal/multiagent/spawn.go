@@ -0,0 +1,171 @@
+package multiagent
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// SpawnTool is the tool name under which agents ask to start a sub-agent.
+// Its parameters name the child in "agent_id" and may narrow the child's
+// tools with a "tools" list.
+const SpawnTool = "spawn_agent"
+
+// Rules a spawn can violate.
+const (
+	RuleChildUnregistered   = "child_unregistered"
+	RuleChildNotAllowed     = "child_not_allowlisted"
+	RulePrivilegeEscalation = "privilege_escalation"
+)
+
+// SpawnRequest is a parent agent's request to start a child agent.
+type SpawnRequest struct {
+	Parent string
+	Child  string
+	// Tools narrows the child to these of its tools; empty asks for all
+	// of them.
+	Tools []string
+}
+
+// SpawnViolation is a reason a spawn is refused.
+type SpawnViolation struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+}
+
+// SpawnGrant is what an allowed child may do: the tools it runs with and
+// the policies it is bound by, its own and its parent's.
+type SpawnGrant struct {
+	GroupID  string   `json:"group_id"`
+	Tools    []string `json:"tools"`
+	Policies []string `json:"policies"`
+}
+
+// SpawnPolicy decides which agents may start which sub-agents, from the
+// delegations declared in agent groups. An agent is registered when it is a
+// member of a group, and may start the agents it delegates to there, with
+// no tool its parent lacks.
+type SpawnPolicy struct {
+	groups []*models.AgentGroup
+}
+
+// NewSpawnPolicy builds a spawn policy from agent groups, which must be
+// valid.
+func NewSpawnPolicy(groups []models.AgentGroup) (*SpawnPolicy, error) {
+	p := &SpawnPolicy{}
+	for i := range groups {
+		g := &groups[i]
+		if err := Validate(g); err != nil {
+			return nil, fmt.Errorf("group %s: %w", g.ID, err)
+		}
+		p.groups = append(p.groups, g)
+	}
+	return p, nil
+}
+
+// Check decides a spawn request. It returns the child's grant from the
+// first group whose delegations allow the spawn, or the violations found
+// in the groups where the parent delegates to the child.
+func (p *SpawnPolicy) Check(req SpawnRequest) (*SpawnGrant, []SpawnViolation) {
+	registered := false
+	var violations []SpawnViolation
+	for _, g := range p.groups {
+		child := member(g, req.Child)
+		if child == nil {
+			continue
+		}
+		registered = true
+		parent := member(g, req.Parent)
+		i := slices.IndexFunc(g.Delegations, func(d models.Delegation) bool {
+			return d.From == req.Parent && d.To == req.Child
+		})
+		if parent == nil || i < 0 {
+			continue
+		}
+		grant, vs := checkSpawn(g, parent, child, g.Delegations[i], req.Tools)
+		if len(vs) == 0 {
+			return grant, nil
+		}
+		violations = append(violations, vs...)
+	}
+
+	switch {
+	case !registered:
+		return nil, []SpawnViolation{{
+			Rule:        RuleChildUnregistered,
+			Description: fmt.Sprintf("agent %s is not a registered member of any agent group", req.Child),
+		}}
+	case len(violations) == 0:
+		return nil, []SpawnViolation{{
+			Rule:        RuleChildNotAllowed,
+			Description: fmt.Sprintf("agent %s does not delegate to %s in any agent group", req.Parent, req.Child),
+		}}
+	}
+	return nil, violations
+}
+
+// checkSpawn checks a spawn along one delegation: the child may hold only
+// tools it is registered with, the delegation allows and the parent has.
+func checkSpawn(g *models.AgentGroup, parent, child *models.GroupMember, d models.Delegation, requested []string) (*SpawnGrant, []SpawnViolation) {
+	var violations []SpawnViolation
+	escalation := func(format string, args ...any) {
+		violations = append(violations, SpawnViolation{Rule: RulePrivilegeEscalation, Description: fmt.Sprintf(format, args...)})
+	}
+
+	tools := requested
+	if len(tools) == 0 {
+		tools = toolNames(child.Tools)
+	}
+	var granted []string
+	for _, name := range tools {
+		switch {
+		case !slices.ContainsFunc(child.Tools, func(t models.ToolBinding) bool { return ToolName(t) == name }):
+			escalation("%s requests tool %s it is not registered with", child.AgentID, name)
+		case len(d.Tools) > 0 && !slices.Contains(d.Tools, name):
+			if len(requested) > 0 {
+				escalation("%s limits %s to %v, not %s", parent.AgentID, child.AgentID, d.Tools, name)
+			}
+		case !slices.ContainsFunc(parent.Tools, func(t models.ToolBinding) bool { return ToolName(t) == name }):
+			escalation("%s would hold tool %s that its parent %s lacks", child.AgentID, name, parent.AgentID)
+		default:
+			granted = append(granted, name)
+		}
+	}
+	if len(violations) > 0 {
+		return nil, violations
+	}
+
+	policies := slices.Clone(child.Policies)
+	for _, pol := range parent.Policies {
+		if !slices.Contains(policies, pol) {
+			policies = append(policies, pol)
+		}
+	}
+	sort.Strings(granted)
+	if granted == nil {
+		granted = []string{}
+	}
+	if policies == nil {
+		policies = []string{}
+	}
+	return &SpawnGrant{GroupID: g.ID, Tools: granted, Policies: policies}, nil
+}
+
+func member(g *models.AgentGroup, agentID string) *models.GroupMember {
+	for i := range g.Members {
+		if g.Members[i].AgentID == agentID {
+			return &g.Members[i]
+		}
+	}
+	return nil
+}
+
+func toolNames(tools []models.ToolBinding) []string {
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = ToolName(t)
+	}
+	return names
+}
@@ -0,0 +1,270 @@
+// Package onboarding computes the checklist that takes a registered agent
+// to "governed": complete registry metadata, an owner, a threat model,
+// bound policies, an SDK that reports traces and a passing deployment
+// check. Nothing is stored; every item is derived from the registry,
+// threat models, ingested traces and the policy engine when requested.
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+// DefaultActivityWindow is how recently an agent must have sent a trace
+// for its SDK to count as reporting.
+const DefaultActivityWindow = 7 * 24 * time.Hour
+
+// Checklist item IDs, in checklist order.
+const (
+	ItemMetadata        = "metadata_complete"
+	ItemOwner           = "owner_assigned"
+	ItemThreatModel     = "threat_model"
+	ItemPolicies        = "policies_bound"
+	ItemSDKReporting    = "sdk_reporting"
+	ItemDeploymentCheck = "deployment_check"
+)
+
+// Status is the state of one checklist item.
+type Status string
+
+const (
+	StatusComplete   Status = "complete"
+	StatusIncomplete Status = "incomplete"
+	// StatusUnknown is reported when the data an item needs is not
+	// available, such as no threat model store being configured. Unknown
+	// items count as not complete.
+	StatusUnknown Status = "unknown"
+)
+
+// Item is one step toward governance.
+type Item struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status Status `json:"status"`
+	// Detail explains the status, e.g. which fields are missing.
+	Detail string `json:"detail,omitempty"`
+	// Action tells the product team what to do next. Empty when complete.
+	Action string `json:"action,omitempty"`
+}
+
+// Checklist is an agent's onboarding progress.
+type Checklist struct {
+	AgentID         string    `json:"agent_id"`
+	AgentName       string    `json:"agent_name"`
+	Items           []Item    `json:"items"`
+	Completed       int       `json:"completed"`
+	Total           int       `json:"total"`
+	PercentComplete float64   `json:"percent_complete"` // one decimal place
+	Governed        bool      `json:"governed"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
+
+// ToolAccessEvaluator evaluates tool access policy; *opa.Engine implements
+// it.
+type ToolAccessEvaluator interface {
+	Ready() bool
+	EvaluateToolAccess(ctx context.Context, agent *opa.AgentContext, tool *opa.ToolContext) (*opa.Decision, error)
+}
+
+// Checker computes onboarding checklists. Every source is optional; items
+// whose source is missing are reported as unknown.
+type Checker struct {
+	ThreatModels repository.ThreatModelRepository
+	Activity     repository.AgentActivityReader
+	Policy       ToolAccessEvaluator
+	// ActivityWindow defaults to DefaultActivityWindow.
+	ActivityWindow time.Duration
+	Now            func() time.Time
+}
+
+// Check computes a's checklist.
+func (c *Checker) Check(ctx context.Context, orgID string, a *models.Agent) (*Checklist, error) {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	cl := &Checklist{AgentID: a.ID.String(), AgentName: a.Name, GeneratedAt: now().UTC()}
+
+	threatModel, err := c.threatModelItem(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	reporting, err := c.reportingItem(ctx, orgID, a, cl.GeneratedAt)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := c.deploymentItem(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	cl.Items = []Item{metadataItem(a), ownerItem(a), threatModel, policiesItem(a), reporting, deployment}
+
+	cl.Total = len(cl.Items)
+	for _, it := range cl.Items {
+		if it.Status == StatusComplete {
+			cl.Completed++
+		}
+	}
+	cl.PercentComplete = math.Round(float64(cl.Completed)/float64(cl.Total)*1000) / 10
+	cl.Governed = cl.Completed == cl.Total
+	return cl, nil
+}
+
+func metadataItem(a *models.Agent) Item {
+	it := Item{ID: ItemMetadata, Title: "Registered metadata complete"}
+	var missing []string
+	for _, f := range []struct{ name, value string }{
+		{"name", a.Name},
+		{"description", a.Description},
+		{"framework", a.Framework},
+		{"version", a.Version},
+		{"team", a.Team},
+		{"environment", a.Environment},
+		{"risk_level", a.RiskLevel},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(a.Tools) == 0 {
+		missing = append(missing, "tools")
+	}
+	if len(missing) == 0 {
+		it.Status = StatusComplete
+		return it
+	}
+	it.Status = StatusIncomplete
+	it.Detail = "missing " + strings.Join(missing, ", ")
+	it.Action = "Update the agent registration with the missing fields"
+	return it
+}
+
+func ownerItem(a *models.Agent) Item {
+	it := Item{ID: ItemOwner, Title: "Owner assigned"}
+	if strings.TrimSpace(a.Owner) != "" {
+		it.Status, it.Detail = StatusComplete, a.Owner
+		return it
+	}
+	it.Status = StatusIncomplete
+	it.Action = "Set an accountable owner on the agent registration"
+	return it
+}
+
+func (c *Checker) threatModelItem(ctx context.Context, a *models.Agent) (Item, error) {
+	it := Item{ID: ItemThreatModel, Title: "Threat model exists"}
+	if c.ThreatModels == nil {
+		it.Status, it.Detail = StatusUnknown, "threat model store not configured"
+		return it, nil
+	}
+	tms, err := c.ThreatModels.List(ctx)
+	if err != nil {
+		return it, fmt.Errorf("listing threat models: %w", err)
+	}
+	var names []string
+	for _, tm := range tms {
+		if tm.TargetAgentID != nil && *tm.TargetAgentID == a.ID {
+			names = append(names, tm.Name)
+		}
+	}
+	if len(names) > 0 {
+		it.Status, it.Detail = StatusComplete, strings.Join(names, ", ")
+		return it, nil
+	}
+	it.Status = StatusIncomplete
+	it.Action = "Create a threat model with the agent as its target"
+	return it, nil
+}
+
+func policiesItem(a *models.Agent) Item {
+	it := Item{ID: ItemPolicies, Title: "Policies bound"}
+	if len(a.Policies) > 0 {
+		it.Status, it.Detail = StatusComplete, fmt.Sprintf("%d bound", len(a.Policies))
+		return it
+	}
+	it.Status = StatusIncomplete
+	it.Action = "Bind policies with PUT /api/v1/agents/:id/policies"
+	return it
+}
+
+func (c *Checker) reportingItem(ctx context.Context, orgID string, a *models.Agent, now time.Time) (Item, error) {
+	it := Item{ID: ItemSDKReporting, Title: "SDK reporting traces"}
+	if c.Activity == nil {
+		it.Status, it.Detail = StatusUnknown, "trace store not configured"
+		return it, nil
+	}
+	last, err := c.Activity.LastTraceAt(ctx, orgID, a.ID.String())
+	if err != nil {
+		return it, fmt.Errorf("reading agent activity: %w", err)
+	}
+	window := c.ActivityWindow
+	if window <= 0 {
+		window = DefaultActivityWindow
+	}
+	switch {
+	case last == nil:
+		it.Status, it.Detail = StatusIncomplete, "no traces received"
+		it.Action = "Install the AgentGuard SDK middleware and send a trace"
+	case now.Sub(*last) > window:
+		it.Status = StatusIncomplete
+		it.Detail = "last trace " + last.Format(time.RFC3339)
+		it.Action = "Check the SDK is still installed and can reach AgentGuard"
+	default:
+		it.Status, it.Detail = StatusComplete, "last trace "+last.Format(time.RFC3339)
+	}
+	return it, nil
+}
+
+// deploymentItem evaluates every tool the agent declares against the tool
+// access policy: an agent is ready to deploy when policy permits the tools
+// it was registered with.
+func (c *Checker) deploymentItem(ctx context.Context, a *models.Agent) (Item, error) {
+	it := Item{ID: ItemDeploymentCheck, Title: "Passed deployment check"}
+	if c.Policy == nil || !c.Policy.Ready() {
+		it.Status, it.Detail = StatusUnknown, "no policies loaded"
+		return it, nil
+	}
+	if len(a.Tools) == 0 {
+		it.Status, it.Detail = StatusIncomplete, "no tools declared"
+		it.Action = "Declare the agent's tools on its registration"
+		return it, nil
+	}
+	agent := &opa.AgentContext{
+		ID:          a.ID.String(),
+		Name:        a.Name,
+		Team:        a.Team,
+		Environment: a.Environment,
+		RiskLevel:   a.RiskLevel,
+	}
+	for _, capability := range a.Capabilities {
+		agent.Capabilities = append(agent.Capabilities, capability.Name)
+	}
+	var denied []string
+	for _, t := range a.Tools {
+		params := make(map[string]any, len(t.Parameters))
+		for k, v := range t.Parameters {
+			params[k] = v
+		}
+		d, err := c.Policy.EvaluateToolAccess(ctx, agent, &opa.ToolContext{Name: t.Name, Category: t.Category, Parameters: params})
+		if err != nil {
+			return it, fmt.Errorf("evaluating tool %s: %w", t.Name, err)
+		}
+		if !d.Allow {
+			denied = append(denied, t.Name)
+		}
+	}
+	if len(denied) == 0 {
+		it.Status, it.Detail = StatusComplete, fmt.Sprintf("%d declared tools permitted", len(a.Tools))
+		return it, nil
+	}
+	it.Status = StatusIncomplete
+	it.Detail = "denied by policy: " + strings.Join(denied, ", ")
+	it.Action = "Remove the denied tools from the registration or update the tool access policy"
+	return it, nil
+}
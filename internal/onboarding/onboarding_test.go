@@ -0,0 +1,120 @@
+package onboarding_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/onboarding"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/google/uuid"
+)
+
+// denyTools denies the named tools.
+type denyTools map[string]bool
+
+func (denyTools) Ready() bool { return true }
+
+func (d denyTools) EvaluateToolAccess(_ context.Context, _ *opa.AgentContext, tool *opa.ToolContext) (*opa.Decision, error) {
+	return &opa.Decision{Allow: !d[tool.Name]}, nil
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	agent := &models.Agent{
+		ID:          uuid.New(),
+		Name:        "support-bot",
+		Description: "Answers support tickets",
+		Framework:   "langchain",
+		Version:     "1.2.0",
+		Team:        "support",
+		Environment: "prod",
+		Tools:       []models.ToolBinding{{Name: "search_kb", Category: "retrieval"}, {Name: "shell", Category: "code_execution"}},
+	}
+	traces := memory.NewTraceStore(0)
+	threatModels := memory.NewThreatModelRepository()
+	checker := &onboarding.Checker{
+		ThreatModels: threatModels,
+		Activity:     traces,
+		Policy:       denyTools{"shell": true},
+		Now:          func() time.Time { return now },
+	}
+
+	cl, err := checker.Check(ctx, "org", agent)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := map[string]onboarding.Status{
+		onboarding.ItemMetadata:        onboarding.StatusIncomplete,
+		onboarding.ItemOwner:           onboarding.StatusIncomplete,
+		onboarding.ItemThreatModel:     onboarding.StatusIncomplete,
+		onboarding.ItemPolicies:        onboarding.StatusIncomplete,
+		onboarding.ItemSDKReporting:    onboarding.StatusIncomplete,
+		onboarding.ItemDeploymentCheck: onboarding.StatusIncomplete,
+	}
+	for _, it := range cl.Items {
+		if it.Status != want[it.ID] {
+			t.Errorf("%s = %s (%s), want %s", it.ID, it.Status, it.Detail, want[it.ID])
+		}
+		if it.Status != onboarding.StatusComplete && it.Action == "" {
+			t.Errorf("%s has no action", it.ID)
+		}
+	}
+	if cl.Items[0].Detail != "missing risk_level" {
+		t.Errorf("metadata detail = %q", cl.Items[0].Detail)
+	}
+	if cl.Items[5].Detail != "denied by policy: shell" {
+		t.Errorf("deployment detail = %q", cl.Items[5].Detail)
+	}
+	if cl.Total != 6 || cl.Completed != 0 || cl.Governed {
+		t.Errorf("checklist = %d/%d governed=%v", cl.Completed, cl.Total, cl.Governed)
+	}
+
+	// Fix everything.
+	agent.RiskLevel, agent.Owner, agent.Policies = "high", "alice@example.com", []string{"pol-1"}
+	agent.Tools = agent.Tools[:1]
+	if err := threatModels.Create(ctx, &models.ThreatModel{Name: "support-bot", TargetAgentID: &agent.ID}); err != nil {
+		t.Fatal(err)
+	}
+	trace := &models.AgentTrace{TraceID: "t1", AgentID: agent.ID, StartTime: now.Add(-time.Hour)}
+	if err := traces.InsertTrace(ctx, "org", trace); err != nil {
+		t.Fatal(err)
+	}
+	cl, err = checker.Check(ctx, "org", agent)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !cl.Governed || cl.PercentComplete != 100 {
+		t.Errorf("checklist = %+v, want governed", cl.Items)
+	}
+
+	// Traces from another organization and stale traces do not count.
+	if cl, _ = checker.Check(ctx, "other-org", agent); cl.Items[4].Status != onboarding.StatusIncomplete {
+		t.Errorf("other org sdk_reporting = %s", cl.Items[4].Status)
+	}
+	checker.Now = func() time.Time { return now.Add(30 * 24 * time.Hour) }
+	if cl, _ = checker.Check(ctx, "org", agent); cl.Items[4].Status != onboarding.StatusIncomplete || cl.PercentComplete < 83 || cl.PercentComplete > 84 {
+		t.Errorf("stale sdk_reporting = %s, percent = %.1f", cl.Items[4].Status, cl.PercentComplete)
+	}
+}
+
+func TestCheckUnknownSources(t *testing.T) {
+	cl, err := (&onboarding.Checker{}).Check(context.Background(), "org", &models.Agent{ID: uuid.New(), Owner: "bob"})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	for _, it := range cl.Items {
+		switch it.ID {
+		case onboarding.ItemThreatModel, onboarding.ItemSDKReporting, onboarding.ItemDeploymentCheck:
+			if it.Status != onboarding.StatusUnknown {
+				t.Errorf("%s = %s, want unknown without a source", it.ID, it.Status)
+			}
+		}
+	}
+	if cl.Completed != 1 {
+		t.Errorf("completed = %d, want only the owner", cl.Completed)
+	}
+}
@@ -0,0 +1,173 @@
+// Package oscal reads NIST OSCAL (Open Security Controls Assessment
+// Language) documents in their JSON form. Only the parts AgentGuard uses
+// are modelled; unknown fields are ignored.
+package oscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Catalog is an OSCAL control catalog, such as NIST SP 800-53.
+type Catalog struct {
+	UUID     string    `json:"uuid"`
+	Metadata Metadata  `json:"metadata"`
+	Params   []Param   `json:"params,omitempty"`
+	Groups   []Group   `json:"groups,omitempty"`
+	Controls []Control `json:"controls,omitempty"`
+}
+
+// Metadata describes an OSCAL document.
+type Metadata struct {
+	Title        string  `json:"title"`
+	Version      string  `json:"version"`
+	OSCALVersion string  `json:"oscal-version"`
+	LastModified string  `json:"last-modified,omitempty"`
+	Published    string  `json:"published,omitempty"`
+	Parties      []Party `json:"parties,omitempty"`
+}
+
+// Party is a person or organization named in a document.
+type Party struct {
+	UUID string `json:"uuid"`
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// Group is a set of controls, e.g. an 800-53 family.
+type Group struct {
+	ID       string    `json:"id,omitempty"`
+	Class    string    `json:"class,omitempty"`
+	Title    string    `json:"title"`
+	Params   []Param   `json:"params,omitempty"`
+	Props    []Prop    `json:"props,omitempty"`
+	Parts    []Part    `json:"parts,omitempty"`
+	Groups   []Group   `json:"groups,omitempty"`
+	Controls []Control `json:"controls,omitempty"`
+}
+
+// Control is a control; nested controls are its enhancements.
+type Control struct {
+	ID       string    `json:"id"`
+	Class    string    `json:"class,omitempty"`
+	Title    string    `json:"title"`
+	Params   []Param   `json:"params,omitempty"`
+	Props    []Prop    `json:"props,omitempty"`
+	Links    []Link    `json:"links,omitempty"`
+	Parts    []Part    `json:"parts,omitempty"`
+	Controls []Control `json:"controls,omitempty"`
+}
+
+// Param is a control parameter referenced from prose.
+type Param struct {
+	ID         string      `json:"id"`
+	Label      string      `json:"label,omitempty"`
+	Props      []Prop      `json:"props,omitempty"`
+	Guidelines []Guideline `json:"guidelines,omitempty"`
+	Select     *Selection  `json:"select,omitempty"`
+}
+
+// Guideline is prose guidance for a parameter value.
+type Guideline struct {
+	Prose string `json:"prose"`
+}
+
+// Selection constrains a parameter to one or more choices.
+type Selection struct {
+	HowMany string   `json:"how-many,omitempty"`
+	Choice  []string `json:"choice,omitempty"`
+}
+
+// Prop is a name/value annotation.
+type Prop struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Class string `json:"class,omitempty"`
+	NS    string `json:"ns,omitempty"`
+}
+
+// Link references another resource or control.
+type Link struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel,omitempty"`
+}
+
+// Part is a section of control text: the statement, guidance, assessment
+// objectives and methods, and their items.
+type Part struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Title string `json:"title,omitempty"`
+	Props []Prop `json:"props,omitempty"`
+	Prose string `json:"prose,omitempty"`
+	Parts []Part `json:"parts,omitempty"`
+}
+
+// Part names used by NIST catalogs.
+const (
+	PartStatement           = "statement"
+	PartItem                = "item"
+	PartGuidance            = "guidance"
+	PartAssessmentObjective = "assessment-objective"
+	PartAssessmentMethod    = "assessment-method"
+	PartAssessmentObjects   = "assessment-objects"
+)
+
+// ParseCatalog decodes an OSCAL catalog document.
+func ParseCatalog(r io.Reader) (*Catalog, error) {
+	var doc struct {
+		Catalog *Catalog `json:"catalog"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding oscal catalog: %w", err)
+	}
+	if doc.Catalog == nil {
+		return nil, fmt.Errorf("decoding oscal catalog: no catalog object")
+	}
+	return doc.Catalog, nil
+}
+
+// LoadCatalog reads an OSCAL catalog from a JSON file.
+func LoadCatalog(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseCatalog(f)
+}
+
+// prop returns the value of the first property named name, preferring one
+// without a class.
+func prop(props []Prop, name string) (string, bool) {
+	value, found := "", false
+	for _, p := range props {
+		if p.Name != name {
+			continue
+		}
+		if p.Class == "" {
+			return p.Value, true
+		}
+		if !found {
+			value, found = p.Value, true
+		}
+	}
+	return value, found
+}
+
+// Label returns a control's display label, e.g. "AC-2(1)".
+func (c Control) Label() string {
+	if l, ok := prop(c.Props, "label"); ok {
+		return l
+	}
+	return c.ID
+}
+
+// Withdrawn reports whether the control has been withdrawn from the
+// catalog.
+func (c Control) Withdrawn() bool {
+	s, _ := prop(c.Props, "status")
+	return s == "withdrawn"
+}
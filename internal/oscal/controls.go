@@ -0,0 +1,192 @@
+package oscal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// insertParam matches parameter references in prose, e.g.
+// "{{ insert: param, ac-01_odp.01 }}".
+var insertParam = regexp.MustCompile(`\{\{\s*insert:\s*param,\s*([^\s}]+)\s*\}\}`)
+
+// ToControls flattens a catalog into controls for frameworkID. Controls
+// take their display label as ControlID, their top-level group's title as
+// Family, and enhancements point at their parent. Withdrawn controls are
+// skipped.
+func ToControls(cat *Catalog, frameworkID string) []models.Control {
+	params := make(map[string]Param)
+	collectParams(cat.Params, params)
+	for _, g := range cat.Groups {
+		collectGroupParams(g, params)
+	}
+	for _, c := range cat.Controls {
+		collectControlParams(c, params)
+	}
+
+	var out []models.Control
+	for _, g := range cat.Groups {
+		out = appendGroup(out, g, g.Title, frameworkID, params)
+	}
+	for _, c := range cat.Controls {
+		out = appendControl(out, c, "", nil, frameworkID, params)
+	}
+	return out
+}
+
+func collectParams(ps []Param, into map[string]Param) {
+	for _, p := range ps {
+		into[p.ID] = p
+	}
+}
+
+func collectGroupParams(g Group, into map[string]Param) {
+	collectParams(g.Params, into)
+	for _, sub := range g.Groups {
+		collectGroupParams(sub, into)
+	}
+	for _, c := range g.Controls {
+		collectControlParams(c, into)
+	}
+}
+
+func collectControlParams(c Control, into map[string]Param) {
+	collectParams(c.Params, into)
+	for _, e := range c.Controls {
+		collectControlParams(e, into)
+	}
+}
+
+func appendGroup(out []models.Control, g Group, family, frameworkID string, params map[string]Param) []models.Control {
+	for _, c := range g.Controls {
+		out = appendControl(out, c, family, nil, frameworkID, params)
+	}
+	for _, sub := range g.Groups {
+		out = appendGroup(out, sub, family, frameworkID, params)
+	}
+	return out
+}
+
+func appendControl(out []models.Control, c Control, family string, parent *string, frameworkID string, params map[string]Param) []models.Control {
+	if c.Withdrawn() {
+		return out
+	}
+	label := c.Label()
+	ctrl := models.Control{
+		FrameworkID:      frameworkID,
+		ControlID:        label,
+		Title:            c.Title,
+		Family:           family,
+		Objectives:       []string{},
+		Activities:       []string{},
+		EvidenceTypes:    []string{},
+		ApplicableLayers: []string{},
+		ParentControlID:  parent,
+	}
+	for _, p := range c.Parts {
+		switch p.Name {
+		case PartStatement:
+			ctrl.Description = strings.Join(proseLines(p, params), "\n")
+		case PartAssessmentObjective:
+			ctrl.Objectives = append(ctrl.Objectives, leafProse(p, params)...)
+		case PartAssessmentMethod:
+			method, _ := prop(p.Props, "method")
+			for _, obj := range assessmentObjects(p) {
+				switch strings.ToUpper(method) {
+				case "EXAMINE":
+					ctrl.EvidenceTypes = append(ctrl.EvidenceTypes, obj)
+				case "INTERVIEW":
+					ctrl.Activities = append(ctrl.Activities, "Interview "+obj)
+				case "TEST":
+					ctrl.Activities = append(ctrl.Activities, "Test "+obj)
+				}
+			}
+		}
+	}
+	out = append(out, ctrl)
+
+	for _, e := range c.Controls {
+		out = appendControl(out, e, family, &label, frameworkID, params)
+	}
+	return out
+}
+
+// proseLines renders a part and its items as lines prefixed with their
+// labels, e.g. "a. Define and document ...".
+func proseLines(p Part, params map[string]Param) []string {
+	var lines []string
+	if p.Prose != "" {
+		text := resolve(p.Prose, params)
+		if l, ok := prop(p.Props, "label"); ok && p.Name == PartItem {
+			text = l + " " + text
+		}
+		lines = append(lines, text)
+	}
+	for _, sub := range p.Parts {
+		if sub.Name == PartItem {
+			lines = append(lines, proseLines(sub, params)...)
+		}
+	}
+	return lines
+}
+
+// leafProse returns the prose of a part's innermost items.
+func leafProse(p Part, params map[string]Param) []string {
+	if len(p.Parts) == 0 {
+		if p.Prose == "" {
+			return nil
+		}
+		return []string{resolve(p.Prose, params)}
+	}
+	var out []string
+	for _, sub := range p.Parts {
+		out = append(out, leafProse(sub, params)...)
+	}
+	return out
+}
+
+// assessmentObjects splits an assessment method's objects into entries;
+// NIST separates them with blank lines.
+func assessmentObjects(p Part) []string {
+	var out []string
+	for _, sub := range p.Parts {
+		if sub.Name != PartAssessmentObjects {
+			continue
+		}
+		for _, line := range strings.Split(sub.Prose, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				out = append(out, line)
+			}
+		}
+	}
+	return out
+}
+
+// resolve replaces parameter references with their label or choices, in
+// the "[Assignment: ...]" and "[Selection: ...]" style of the published
+// catalog.
+func resolve(prose string, params map[string]Param) string {
+	return insertParam.ReplaceAllStringFunc(prose, func(m string) string {
+		id := insertParam.FindStringSubmatch(m)[1]
+		p, ok := params[id]
+		switch {
+		case !ok:
+			return "[Assignment: " + id + "]"
+		case p.Select != nil:
+			prefix := "[Selection: "
+			if p.Select.HowMany == "one-or-more" {
+				prefix = "[Selection (one or more): "
+			}
+			choices := make([]string, len(p.Select.Choice))
+			for i, c := range p.Select.Choice {
+				choices[i] = resolve(c, params)
+			}
+			return prefix + strings.Join(choices, "; ") + "]"
+		case p.Label != "":
+			return "[Assignment: " + p.Label + "]"
+		default:
+			return "[Assignment: " + id + "]"
+		}
+	})
+}
@@ -0,0 +1,343 @@
+package oscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+// Version is the OSCAL version exported documents conform to.
+const Version = "1.1.2"
+
+// Namespace qualifies AgentGuard-specific props in exported documents.
+const Namespace = "https://agentguard.dev/ns/oscal"
+
+// ComponentDefinition is an OSCAL component definition: what a component
+// implements of one or more control sources.
+type ComponentDefinition struct {
+	UUID       string             `json:"uuid"`
+	Metadata   Metadata           `json:"metadata"`
+	Components []DefinedComponent `json:"components,omitempty"`
+}
+
+// DefinedComponent is a component and its control implementations.
+type DefinedComponent struct {
+	UUID                   string                  `json:"uuid"`
+	Type                   string                  `json:"type"`
+	Title                  string                  `json:"title"`
+	Description            string                  `json:"description"`
+	Props                  []Prop                  `json:"props,omitempty"`
+	ControlImplementations []ControlImplementation `json:"control-implementations,omitempty"`
+}
+
+// ControlImplementation is the set of requirements a component implements
+// from one control source.
+type ControlImplementation struct {
+	UUID                    string                   `json:"uuid"`
+	Source                  string                   `json:"source"`
+	Description             string                   `json:"description"`
+	Props                   []Prop                   `json:"props,omitempty"`
+	ImplementedRequirements []ImplementedRequirement `json:"implemented-requirements"`
+}
+
+// ImplementedRequirement is how a component satisfies one control.
+type ImplementedRequirement struct {
+	UUID        string `json:"uuid"`
+	ControlID   string `json:"control-id"`
+	Description string `json:"description"`
+	Props       []Prop `json:"props,omitempty"`
+	Remarks     string `json:"remarks,omitempty"`
+}
+
+// MappingCollection is an OSCAL control mapping collection: relationships
+// between the controls of two sources.
+type MappingCollection struct {
+	UUID       string     `json:"uuid"`
+	Metadata   Metadata   `json:"metadata"`
+	Provenance Provenance `json:"provenance"`
+	Mappings   []Mapping  `json:"mappings"`
+}
+
+// Provenance describes how a mapping collection was produced.
+type Provenance struct {
+	Method             string `json:"method"`
+	MatchingRationale  string `json:"matching-rationale"`
+	Status             string `json:"status"`
+	MappingDescription string `json:"mapping-description,omitempty"`
+}
+
+// Mapping relates controls in a source resource to a target resource.
+type Mapping struct {
+	UUID           string          `json:"uuid"`
+	SourceResource MappingResource `json:"source-resource"`
+	TargetResource MappingResource `json:"target-resource"`
+	Maps           []Map           `json:"maps"`
+}
+
+// MappingResource names one side of a mapping.
+type MappingResource struct {
+	Type  string `json:"type"`
+	Href  string `json:"href"`
+	Title string `json:"title,omitempty"`
+}
+
+// Map is a relationship between source and target controls.
+type Map struct {
+	UUID            string           `json:"uuid"`
+	Relationship    string           `json:"relationship"`
+	Sources         []MapItem        `json:"sources"`
+	Targets         []MapItem        `json:"targets"`
+	ConfidenceScore *ConfidenceScore `json:"confidence-score,omitempty"`
+	Props           []Prop           `json:"props,omitempty"`
+	Remarks         string           `json:"remarks,omitempty"`
+}
+
+// MapItem references a control in a mapping.
+type MapItem struct {
+	Type  string `json:"type"`
+	IDRef string `json:"id-ref"`
+}
+
+// ConfidenceScore is the confidence in a mapping, from 0 to 1.
+type ConfidenceScore struct {
+	Percentage string `json:"percentage"`
+}
+
+// relationships maps crosswalk mapping types to OSCAL mapping
+// relationships. A superset mapping means the source includes the target,
+// so the target is a subset of the source and vice versa.
+var relationships = map[models.MappingType]string{
+	models.MappingExact:    "equivalent-to",
+	models.MappingPartial:  "intersects-with",
+	models.MappingSuperset: "superset-of",
+	models.MappingSubset:   "subset-of",
+	models.MappingRelated:  "intersects-with",
+}
+
+// enhancementLabel matches an enhancement label suffix such as "(12)".
+var enhancementLabel = regexp.MustCompile(`\((\d+)\)`)
+
+// ControlID returns the OSCAL control ID for a control label, e.g. "ac-2.1"
+// for "AC-2(1)".
+func ControlID(label string) string {
+	return strings.ToLower(enhancementLabel.ReplaceAllString(label, ".$1"))
+}
+
+// SourceHref is the href exported documents use for a framework's
+// catalog; frameworks carry no OSCAL href of their own.
+func SourceHref(fw *models.Framework) string {
+	return "agentguard:frameworks/" + fw.ID
+}
+
+func metadata(title, version string) Metadata {
+	return Metadata{
+		Title:        title,
+		Version:      version,
+		OSCALVersion: Version,
+		LastModified: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// ComponentInput is a gap analysis to export as a component definition.
+type ComponentInput struct {
+	// Title names the assessed system; it defaults to "AgentGuard".
+	Title       string
+	Description string
+	Framework   *models.Framework
+	Controls    []models.Control
+	Inventory   []models.ImplementedControl
+	Gaps        []models.ControlGap
+}
+
+// NewComponentDefinition exports a gap analysis as a component definition
+// with one implemented requirement per framework control. Each carries an
+// implementation-status prop: implemented for covered controls, partial for
+// partially covered ones and planned for gaps, with the gap's priority and
+// remediation options.
+func NewComponentDefinition(in ComponentInput) *ComponentDefinition {
+	title := in.Title
+	if title == "" {
+		title = "AgentGuard"
+	}
+	gaps := make(map[string]models.ControlGap, len(in.Gaps))
+	for _, g := range in.Gaps {
+		gaps[strings.ToLower(g.ControlID)] = g
+	}
+	inventory := make(map[string]models.ImplementedControl, len(in.Inventory))
+	for _, ic := range in.Inventory {
+		inventory[strings.ToLower(ic.ControlID)] = ic
+	}
+
+	reqs := make([]ImplementedRequirement, 0, len(in.Controls))
+	for _, c := range in.Controls {
+		req := ImplementedRequirement{
+			UUID:      uuid.NewString(),
+			ControlID: ControlID(c.ControlID),
+			Props:     []Prop{{Name: "label", Value: c.ControlID, NS: Namespace}},
+		}
+		gap, isGap := gaps[strings.ToLower(c.ControlID)]
+		ic, inInventory := inventory[strings.ToLower(c.ControlID)]
+		switch {
+		case isGap && gap.GapType == "partial":
+			req.Description = "Partially implemented."
+			if gap.CoverageScore > 0 {
+				req.Description = fmt.Sprintf("Partially covered (%.0f%%) through crosswalks from implemented controls in other frameworks.", gap.CoverageScore*100)
+			}
+			req.Props = append(req.Props, Prop{Name: "implementation-status", Value: "partial"})
+		case isGap:
+			req.Description = "Not implemented."
+			req.Props = append(req.Props, Prop{Name: "implementation-status", Value: "planned"})
+		case inInventory:
+			req.Description = "Implemented."
+			req.Props = append(req.Props, Prop{Name: "implementation-status", Value: "implemented"})
+			if ic.Source != "" {
+				req.Props = append(req.Props, Prop{Name: "implementation-source", Value: string(ic.Source), NS: Namespace})
+			}
+		default:
+			req.Description = "Covered through crosswalks from implemented controls in other frameworks."
+			req.Props = append(req.Props, Prop{Name: "implementation-status", Value: "implemented"})
+		}
+		if isGap {
+			if gap.Priority != "" {
+				req.Props = append(req.Props, Prop{Name: "gap-priority", Value: gap.Priority, NS: Namespace})
+			}
+			if gap.EstimatedEffort != "" {
+				req.Props = append(req.Props, Prop{Name: "gap-effort", Value: gap.EstimatedEffort, NS: Namespace})
+			}
+			if len(gap.RemediationOptions) > 0 {
+				req.Remarks = "Remediation: " + strings.Join(gap.RemediationOptions, "; ")
+			}
+		}
+		reqs = append(reqs, req)
+	}
+
+	return &ComponentDefinition{
+		UUID:     uuid.NewString(),
+		Metadata: metadata(title+" "+in.Framework.Name+" implementation", in.Framework.Version),
+		Components: []DefinedComponent{{
+			UUID:        uuid.NewString(),
+			Type:        "software",
+			Title:       title,
+			Description: firstNonEmpty(in.Description, "Control implementation status from an AgentGuard gap analysis."),
+			ControlImplementations: []ControlImplementation{{
+				UUID:                    uuid.NewString(),
+				Source:                  SourceHref(in.Framework),
+				Description:             in.Framework.Name + " " + in.Framework.Version,
+				ImplementedRequirements: reqs,
+			}},
+		}},
+	}
+}
+
+// NewMappingCollection exports crosswalks between two frameworks as a
+// mapping collection. Derived mappings are marked with a derived prop and
+// their intermediate controls.
+func NewMappingCollection(source, target *models.Framework, crosswalks []models.Crosswalk) *MappingCollection {
+	m, derived := newMapping(source, target, crosswalks)
+	return &MappingCollection{
+		UUID:       uuid.NewString(),
+		Metadata:   metadata(source.Name+" to "+target.Name+" crosswalk", "1.0"),
+		Provenance: provenance(derived),
+		Mappings:   []Mapping{m},
+	}
+}
+
+// newMapping exports the crosswalks between two frameworks and reports
+// whether any of them is derived.
+func newMapping(source, target *models.Framework, crosswalks []models.Crosswalk) (Mapping, bool) {
+	derived := false
+	maps := make([]Map, 0, len(crosswalks))
+	for _, xw := range crosswalks {
+		rel, ok := relationships[xw.MappingType]
+		if !ok {
+			rel = "intersects-with"
+		}
+		m := Map{
+			UUID:            uuid.NewString(),
+			Relationship:    rel,
+			Sources:         []MapItem{{Type: "control", IDRef: ControlID(xw.SourceControlID)}},
+			Targets:         []MapItem{{Type: "control", IDRef: ControlID(xw.TargetControlID)}},
+			ConfidenceScore: &ConfidenceScore{Percentage: fmt.Sprintf("%.2f", xw.Confidence)},
+			Props:           []Prop{{Name: "mapping-type", Value: string(xw.MappingType), NS: Namespace}},
+			Remarks:         xw.Rationale,
+		}
+		if xw.Derived {
+			derived = true
+			m.Props = append(m.Props, Prop{Name: "derived", Value: "true", NS: Namespace})
+			for _, via := range xw.Via {
+				m.Props = append(m.Props, Prop{Name: "via", Value: via, NS: Namespace})
+			}
+		}
+		maps = append(maps, m)
+	}
+	return Mapping{
+		UUID:           uuid.NewString(),
+		SourceResource: MappingResource{Type: "catalog", Href: SourceHref(source), Title: source.Name},
+		TargetResource: MappingResource{Type: "catalog", Href: SourceHref(target), Title: target.Name},
+		Maps:           maps,
+	}, derived
+}
+
+// provenance describes exported mappings, hybrid when some are derived.
+func provenance(derived bool) Provenance {
+	method := "human"
+	if derived {
+		method = "hybrid"
+	}
+	return Provenance{Method: method, MatchingRationale: "semantic", Status: "draft"}
+}
+
+// ToFramework returns the framework a catalog describes. The publisher is
+// the first organization among the document's parties.
+func ToFramework(cat *Catalog, id string) *models.Framework {
+	fw := &models.Framework{
+		ID:      id,
+		Name:    cat.Metadata.Title,
+		Version: cat.Metadata.Version,
+	}
+	for _, p := range cat.Metadata.Parties {
+		if p.Type == "organization" {
+			fw.Publisher = p.Name
+			break
+		}
+	}
+	return fw
+}
+
+// WriteJSON writes a catalog, profile, component definition, mapping
+// collection or POA&M as an OSCAL JSON document.
+func WriteJSON(w io.Writer, doc any) error {
+	var root string
+	switch doc.(type) {
+	case *Catalog:
+		root = "catalog"
+	case *Profile:
+		root = "profile"
+	case *ComponentDefinition:
+		root = "component-definition"
+	case *MappingCollection:
+		root = "mapping-collection"
+	case *PlanOfActionAndMilestones:
+		root = "plan-of-action-and-milestones"
+	default:
+		return fmt.Errorf("unsupported oscal document %T", doc)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{root: doc})
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
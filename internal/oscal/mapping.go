@@ -0,0 +1,121 @@
+package oscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+// NewCrosswalkCollection exports crosswalks between any number of
+// framework pairs as one mapping collection with a mapping per pair, in
+// source then target order. frameworks holds every framework the
+// crosswalks reference.
+func NewCrosswalkCollection(title string, frameworks map[string]*models.Framework, crosswalks []models.Crosswalk) (*MappingCollection, error) {
+	byPair := make(map[[2]string][]models.Crosswalk)
+	for _, xw := range crosswalks {
+		pair := [2]string{xw.SourceFrameworkID, xw.TargetFrameworkID}
+		byPair[pair] = append(byPair[pair], xw)
+	}
+	pairs := make([][2]string, 0, len(byPair))
+	for pair := range byPair {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	mc := &MappingCollection{
+		UUID:     uuid.NewString(),
+		Metadata: metadata(title, "1.0"),
+		Mappings: make([]Mapping, 0, len(pairs)),
+	}
+	anyDerived := false
+	for _, pair := range pairs {
+		source, target := frameworks[pair[0]], frameworks[pair[1]]
+		if source == nil || target == nil {
+			return nil, fmt.Errorf("crosswalk %s to %s: unknown framework", pair[0], pair[1])
+		}
+		m, derived := newMapping(source, target, byPair[pair])
+		anyDerived = anyDerived || derived
+		mc.Mappings = append(mc.Mappings, m)
+	}
+	mc.Provenance = provenance(anyDerived)
+	return mc, nil
+}
+
+// ParseMappingCollection reads an OSCAL mapping collection JSON document.
+func ParseMappingCollection(r io.Reader) (*MappingCollection, error) {
+	var doc struct {
+		MappingCollection *MappingCollection `json:"mapping-collection"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding oscal mapping collection: %w", err)
+	}
+	if doc.MappingCollection == nil {
+		return nil, fmt.Errorf("decoding oscal mapping collection: no mapping-collection object")
+	}
+	return doc.MappingCollection, nil
+}
+
+// FrameworkRef returns the framework ID of a resource exported by
+// AgentGuard, whose href is SourceHref, or "" for other resources.
+func FrameworkRef(r MappingResource) string {
+	id, ok := strings.CutPrefix(r.Href, "agentguard:frameworks/")
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// MappingType returns the crosswalk mapping type of a map: the one it was
+// exported with, or else the closest to its OSCAL relationship.
+func (m Map) MappingType() models.MappingType {
+	if v, ok := prop(m.Props, "mapping-type"); ok {
+		switch t := models.MappingType(v); t {
+		case models.MappingExact, models.MappingPartial, models.MappingSuperset, models.MappingSubset, models.MappingRelated:
+			return t
+		}
+	}
+	switch m.Relationship {
+	case "equivalent-to", "equal-to":
+		return models.MappingExact
+	case "subset-of":
+		return models.MappingSubset
+	case "superset-of":
+		return models.MappingSuperset
+	case "intersects-with":
+		return models.MappingPartial
+	}
+	return models.MappingRelated
+}
+
+// Confidence returns a map's confidence from 0 to 1. Scores written as
+// percentages ("85" or "85%") are scaled down. ok is false when the map
+// has no valid score.
+func (m Map) Confidence() (confidence float64, ok bool) {
+	if m.ConfidenceScore == nil {
+		return 0, false
+	}
+	v := strings.TrimSpace(m.ConfidenceScore.Percentage)
+	percent := strings.HasSuffix(v, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+	if err != nil || f < 0 {
+		return 0, false
+	}
+	if percent || f > 1 {
+		f /= 100
+	}
+	if f > 1 {
+		return 0, false
+	}
+	return f, true
+}
@@ -0,0 +1,300 @@
+package oscal_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/oscal"
+)
+
+func TestLoadProfile(t *testing.T) {
+	cat, err := oscal.Load("testdata/profile.json")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cat.Metadata.Title != "Example Baseline" {
+		t.Errorf("title = %q, want the profile's", cat.Metadata.Title)
+	}
+
+	var got []string
+	parents := map[string]string{}
+	for _, c := range oscal.ToControls(cat, "baseline") {
+		got = append(got, c.ControlID)
+		if c.ParentControlID != nil {
+			parents[c.ControlID] = *c.ParentControlID
+		}
+	}
+	want := []string{"AC-2", "AC-2(1)", "AU-2"}
+	if len(got) != len(want) {
+		t.Fatalf("controls = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("controls = %v, want %v", got, want)
+		}
+	}
+	if parents["AC-2(1)"] != "AC-2" {
+		t.Errorf("AC-2(1) parent = %q, want AC-2", parents["AC-2(1)"])
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	data, err := os.ReadFile("testdata/profile.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cat, err := oscal.Parse(bytes.NewReader(data), "testdata")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if n := len(oscal.ToControls(cat, "baseline")); n != 3 {
+		t.Errorf("controls = %d, want 3", n)
+	}
+
+	for _, dir := range []string{"", "testdata/none", t.TempDir()} {
+		if _, err := oscal.Parse(bytes.NewReader(data), dir); err == nil {
+			t.Errorf("Parse with catalogs in %q succeeded, want the import rejected", dir)
+		}
+	}
+	escape := bytes.Replace(data, []byte(`"catalog.json"`), []byte(`"../testdata/catalog.json"`), 1)
+	if _, err := oscal.Parse(bytes.NewReader(escape), "testdata/none"); err == nil {
+		t.Error("Parse of an import outside the catalog directory succeeded")
+	}
+}
+
+func TestToFramework(t *testing.T) {
+	cat, err := oscal.LoadCatalog("testdata/catalog.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw := oscal.ToFramework(cat, "example")
+	if fw.Name != "Example Catalog" || fw.Version != "1.0" || fw.Publisher != "Example Standards Body" {
+		t.Errorf("framework = %+v", fw)
+	}
+}
+
+func TestControlID(t *testing.T) {
+	for label, want := range map[string]string{
+		"AC-2":       "ac-2",
+		"AC-2(1)":    "ac-2.1",
+		"SI-4(12)":   "si-4.12",
+		"GOVERN-1.1": "govern-1.1",
+		"AML.T0051":  "aml.t0051",
+	} {
+		if got := oscal.ControlID(label); got != want {
+			t.Errorf("ControlID(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
+
+func TestNewMappingCollection(t *testing.T) {
+	src := &models.Framework{ID: "soc2", Name: "SOC 2"}
+	tgt := &models.Framework{ID: "nist-800-53", Name: "NIST SP 800-53"}
+	mc := oscal.NewMappingCollection(src, tgt, []models.Crosswalk{
+		{SourceControlID: "CC6.1", TargetControlID: "AC-2(1)", MappingType: models.MappingSuperset, Confidence: 0.8},
+		{SourceControlID: "CC6.6", TargetControlID: "SC-7", MappingType: models.MappingExact, Confidence: 1, Derived: true, Via: []string{"iso-42001:A.6.2.6"}},
+	})
+	if mc.Provenance.Method != "hybrid" {
+		t.Errorf("method = %q, want hybrid with derived mappings", mc.Provenance.Method)
+	}
+	maps := mc.Mappings[0].Maps
+	if len(maps) != 2 {
+		t.Fatalf("maps = %d, want 2", len(maps))
+	}
+	if maps[0].Relationship != "superset-of" || maps[0].Targets[0].IDRef != "ac-2.1" {
+		t.Errorf("first map = %+v", maps[0])
+	}
+	if maps[1].Relationship != "equivalent-to" {
+		t.Errorf("exact mapping relationship = %q", maps[1].Relationship)
+	}
+
+	var buf bytes.Buffer
+	if err := oscal.WriteJSON(&buf, mc); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["mapping-collection"]; !ok {
+		t.Errorf("document root = %v, want mapping-collection", doc)
+	}
+}
+
+func TestParseMappingCollection(t *testing.T) {
+	frameworks := map[string]*models.Framework{
+		"soc2":        {ID: "soc2", Name: "SOC 2"},
+		"nist-800-53": {ID: "nist-800-53", Name: "NIST SP 800-53"},
+	}
+	mc, err := oscal.NewCrosswalkCollection("Crosswalks", frameworks, []models.Crosswalk{
+		{SourceFrameworkID: "soc2", SourceControlID: "CC6.1", TargetFrameworkID: "nist-800-53", TargetControlID: "AC-2", MappingType: models.MappingSuperset, Confidence: 0.8},
+		{SourceFrameworkID: "nist-800-53", SourceControlID: "AC-2", TargetFrameworkID: "soc2", TargetControlID: "CC6.1", MappingType: models.MappingSubset, Confidence: 0.8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := oscal.WriteJSON(&buf, mc); err != nil {
+		t.Fatal(err)
+	}
+	got, err := oscal.ParseMappingCollection(&buf)
+	if err != nil {
+		t.Fatalf("ParseMappingCollection: %v", err)
+	}
+	if len(got.Mappings) != 2 {
+		t.Fatalf("mappings = %d, want one per framework pair", len(got.Mappings))
+	}
+	m := got.Mappings[0]
+	if oscal.FrameworkRef(m.SourceResource) != "nist-800-53" || oscal.FrameworkRef(m.TargetResource) != "soc2" {
+		t.Errorf("first mapping = %s to %s, want pairs in source order", m.SourceResource.Href, m.TargetResource.Href)
+	}
+	if mt := m.Maps[0].MappingType(); mt != models.MappingSubset {
+		t.Errorf("mapping type = %q, want subset", mt)
+	}
+	if c, ok := m.Maps[0].Confidence(); !ok || c != 0.8 {
+		t.Errorf("confidence = %v, %v, want 0.8", c, ok)
+	}
+
+	if _, err := oscal.NewCrosswalkCollection("x", frameworks, []models.Crosswalk{{SourceFrameworkID: "soc2", TargetFrameworkID: "cis"}}); err == nil {
+		t.Error("crosswalk to an unknown framework exported")
+	}
+	if _, err := oscal.ParseMappingCollection(bytes.NewBufferString(`{"catalog": {}}`)); err == nil {
+		t.Error("document without a mapping collection parsed")
+	}
+}
+
+func TestMapScores(t *testing.T) {
+	for _, tc := range []struct {
+		relationship, score string
+		want                models.MappingType
+		confidence          float64
+		ok                  bool
+	}{
+		{"equivalent-to", "0.9", models.MappingExact, 0.9, true},
+		{"intersects-with", "85%", models.MappingPartial, 0.85, true},
+		{"superset-of", "70", models.MappingSuperset, 0.7, true},
+		{"related-to", "", models.MappingRelated, 0, false},
+		{"subset-of", "150", models.MappingSubset, 0, false},
+	} {
+		m := oscal.Map{Relationship: tc.relationship}
+		if tc.score != "" {
+			m.ConfidenceScore = &oscal.ConfidenceScore{Percentage: tc.score}
+		}
+		if got := m.MappingType(); got != tc.want {
+			t.Errorf("%s: mapping type = %q, want %q", tc.relationship, got, tc.want)
+		}
+		if c, ok := m.Confidence(); ok != tc.ok || c != tc.confidence {
+			t.Errorf("%q: confidence = %v, %v, want %v, %v", tc.score, c, ok, tc.confidence, tc.ok)
+		}
+	}
+}
+
+func TestNewComponentDefinition(t *testing.T) {
+	fw := &models.Framework{ID: "nist-800-53", Name: "NIST SP 800-53", Version: "5.1"}
+	cd := oscal.NewComponentDefinition(oscal.ComponentInput{
+		Framework: fw,
+		Controls: []models.Control{
+			{ControlID: "AC-1", Title: "Policy and Procedures"},
+			{ControlID: "AC-2", Title: "Account Management"},
+			{ControlID: "AC-3", Title: "Access Enforcement"},
+			{ControlID: "AU-2", Title: "Event Logging"},
+		},
+		Inventory: []models.ImplementedControl{{ControlID: "AC-2", Source: models.ImplementationLocal}},
+		Gaps: []models.ControlGap{
+			{ControlID: "AC-1", GapType: "not_implemented", Priority: "high", RemediationOptions: []string{"Develop policy"}},
+			{ControlID: "AC-3", GapType: "partial", CoverageScore: 0.5},
+		},
+	})
+
+	status := map[string]string{}
+	for _, req := range cd.Components[0].ControlImplementations[0].ImplementedRequirements {
+		for _, p := range req.Props {
+			if p.Name == "implementation-status" {
+				status[req.ControlID] = p.Value
+			}
+		}
+		if req.Description == "" {
+			t.Errorf("%s has no description", req.ControlID)
+		}
+	}
+	want := map[string]string{
+		"ac-1": "planned",
+		"ac-2": "implemented",
+		"ac-3": "partial",
+		// Not in the inventory and not a gap: covered through crosswalks.
+		"au-2": "implemented",
+	}
+	for id, w := range want {
+		if status[id] != w {
+			t.Errorf("%s status = %q, want %q", id, status[id], w)
+		}
+	}
+}
+
+func TestNewPlanOfActionAndMilestones(t *testing.T) {
+	fw := &models.Framework{ID: "nist-800-53", Name: "NIST SP 800-53", Version: "5.1"}
+	due := time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)
+	doc := oscal.NewPlanOfActionAndMilestones(oscal.POAMInput{
+		SystemID:  "org-1",
+		Framework: fw,
+		Assessed:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		Entries: []oscal.POAMEntry{{
+			ID:         "POAM-001",
+			ControlID:  "AC-2(1)",
+			Title:      "Automated Account Management",
+			Priority:   "high",
+			Start:      time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			Completion: due,
+			Milestones: []oscal.POAMMilestone{
+				{Title: "Deploy account automation", Due: due.AddDate(0, 0, -10)},
+				{Title: "Validate and close", Due: due},
+			},
+		}},
+	})
+
+	if doc.SystemID == nil || doc.SystemID.ID != "org-1" {
+		t.Errorf("system id = %+v", doc.SystemID)
+	}
+	if len(doc.POAMItems) != 1 || len(doc.Observations) != 1 || len(doc.Risks) != 1 {
+		t.Fatalf("items %d, observations %d, risks %d", len(doc.POAMItems), len(doc.Observations), len(doc.Risks))
+	}
+	item, risk := doc.POAMItems[0], doc.Risks[0]
+	if item.RelatedObservations[0].ObservationUUID != doc.Observations[0].UUID || item.RelatedRisks[0].RiskUUID != risk.UUID {
+		t.Error("poam item does not reference its observation and risk")
+	}
+	if item.Description != "Control AC-2(1) is not implemented." {
+		t.Errorf("default weakness = %q", item.Description)
+	}
+	if risk.Status != "open" || risk.Deadline != "2027-02-01T00:00:00Z" {
+		t.Errorf("risk status %q, deadline %q", risk.Status, risk.Deadline)
+	}
+	tasks := risk.Remediations[0].Tasks
+	if len(tasks) != 2 || tasks[0].Type != "milestone" || tasks[1].Timing.OnDate.Date != "2027-02-01T00:00:00Z" {
+		t.Errorf("tasks = %+v", tasks)
+	}
+	var ids []string
+	for _, p := range item.Props {
+		if p.Name == "control-id" || p.Name == "poam-id" {
+			ids = append(ids, p.Value)
+		}
+	}
+	if len(ids) != 2 || ids[0] != "ac-2.1" || ids[1] != "POAM-001" {
+		t.Errorf("item ids = %v", ids)
+	}
+
+	var buf bytes.Buffer
+	if err := oscal.WriteJSON(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := root["plan-of-action-and-milestones"]; !ok {
+		t.Errorf("document root = %v, want plan-of-action-and-milestones", root)
+	}
+}
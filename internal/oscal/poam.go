@@ -0,0 +1,213 @@
+package oscal
+
+import (
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+// PlanOfActionAndMilestones is an OSCAL POA&M: the open weaknesses of a
+// system and the plan for remediating each.
+type PlanOfActionAndMilestones struct {
+	UUID         string        `json:"uuid"`
+	Metadata     Metadata      `json:"metadata"`
+	SystemID     *SystemID     `json:"system-id,omitempty"`
+	Observations []Observation `json:"observations,omitempty"`
+	Risks        []Risk        `json:"risks,omitempty"`
+	POAMItems    []POAMItem    `json:"poam-items"`
+}
+
+// SystemID identifies the system a POA&M covers.
+type SystemID struct {
+	IdentifierType string `json:"identifier-type,omitempty"`
+	ID             string `json:"id"`
+}
+
+// Observation is an assessment finding a POA&M item is based on.
+type Observation struct {
+	UUID        string   `json:"uuid"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description"`
+	Props       []Prop   `json:"props,omitempty"`
+	Methods     []string `json:"methods"`
+	Collected   string   `json:"collected"`
+}
+
+// Risk is the risk a weakness poses and its planned remediation.
+type Risk struct {
+	UUID         string     `json:"uuid"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Statement    string     `json:"statement"`
+	Props        []Prop     `json:"props,omitempty"`
+	Status       string     `json:"status"`
+	Deadline     string     `json:"deadline,omitempty"`
+	Remediations []Response `json:"remediations,omitempty"`
+}
+
+// Response is a planned remediation of a risk.
+type Response struct {
+	UUID        string `json:"uuid"`
+	Lifecycle   string `json:"lifecycle"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Props       []Prop `json:"props,omitempty"`
+	Tasks       []Task `json:"tasks,omitempty"`
+}
+
+// Task is a step of a remediation; POA&M milestones are tasks of type
+// milestone.
+type Task struct {
+	UUID        string  `json:"uuid"`
+	Type        string  `json:"type"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Timing      *Timing `json:"timing,omitempty"`
+}
+
+// Timing is when a task is due.
+type Timing struct {
+	OnDate *OnDate `json:"on-date,omitempty"`
+}
+
+// OnDate is a single point in time.
+type OnDate struct {
+	Date string `json:"date"`
+}
+
+// POAMItem is one weakness tracked by a POA&M.
+type POAMItem struct {
+	UUID                string               `json:"uuid"`
+	Title               string               `json:"title"`
+	Description         string               `json:"description"`
+	Props               []Prop               `json:"props,omitempty"`
+	RelatedObservations []RelatedObservation `json:"related-observations,omitempty"`
+	RelatedRisks        []AssociatedRisk     `json:"related-risks,omitempty"`
+}
+
+// RelatedObservation references an observation.
+type RelatedObservation struct {
+	ObservationUUID string `json:"observation-uuid"`
+}
+
+// AssociatedRisk references a risk.
+type AssociatedRisk struct {
+	RiskUUID string `json:"risk-uuid"`
+}
+
+// POAMInput is a remediation plan to export as a POA&M.
+type POAMInput struct {
+	// Title names the assessed system; it defaults to "AgentGuard".
+	Title string
+	// SystemID identifies the system, typically the organization.
+	SystemID  string
+	Framework *models.Framework
+	// Assessed is when the gap analysis ran.
+	Assessed time.Time
+	Entries  []POAMEntry
+}
+
+// POAMEntry is one gap and its remediation schedule.
+type POAMEntry struct {
+	// ID is the item's POA&M identifier, such as POAM-001.
+	ID              string
+	ControlID       string
+	Title           string
+	Weakness        string
+	GapType         string
+	Priority        string
+	EstimatedEffort string
+	Start           time.Time
+	Completion      time.Time
+	Milestones      []POAMMilestone
+}
+
+// POAMMilestone is a dated step toward closing a gap.
+type POAMMilestone struct {
+	Title string
+	Due   time.Time
+}
+
+// NewPlanOfActionAndMilestones exports a remediation plan as a POA&M with,
+// for each gap, an observation recording the finding, an open risk whose
+// planned remediation carries the milestones, and a POA&M item linking the
+// two.
+func NewPlanOfActionAndMilestones(in POAMInput) *PlanOfActionAndMilestones {
+	title := in.Title
+	if title == "" {
+		title = "AgentGuard"
+	}
+	assessed := in.Assessed.UTC().Format(time.RFC3339)
+	source := SourceHref(in.Framework)
+
+	doc := &PlanOfActionAndMilestones{
+		UUID:      uuid.NewString(),
+		Metadata:  metadata(title+" "+in.Framework.Name+" plan of action and milestones", in.Framework.Version),
+		POAMItems: make([]POAMItem, 0, len(in.Entries)),
+	}
+	if in.SystemID != "" {
+		doc.SystemID = &SystemID{IdentifierType: Namespace, ID: in.SystemID}
+	}
+	for _, e := range in.Entries {
+		controlProps := []Prop{
+			{Name: "label", Value: e.ControlID, NS: Namespace},
+			{Name: "control-id", Value: ControlID(e.ControlID), NS: Namespace},
+			{Name: "control-source", Value: source, NS: Namespace},
+		}
+		weakness := firstNonEmpty(e.Weakness, "Control "+e.ControlID+" is not implemented.")
+
+		obs := Observation{
+			UUID:        uuid.NewString(),
+			Title:       e.ControlID + " gap",
+			Description: weakness,
+			Props:       append(append([]Prop{}, controlProps...), Prop{Name: "gap-type", Value: e.GapType, NS: Namespace}),
+			Methods:     []string{"EXAMINE"},
+			Collected:   assessed,
+		}
+
+		tasks := make([]Task, 0, len(e.Milestones))
+		for _, m := range e.Milestones {
+			tasks = append(tasks, Task{
+				UUID:   uuid.NewString(),
+				Type:   "milestone",
+				Title:  m.Title,
+				Timing: &Timing{OnDate: &OnDate{Date: m.Due.UTC().Format(time.RFC3339)}},
+			})
+		}
+		risk := Risk{
+			UUID:        uuid.NewString(),
+			Title:       e.ControlID + ": " + e.Title,
+			Description: weakness,
+			Statement:   "Until " + e.ControlID + " is implemented the system does not meet " + in.Framework.Name + ".",
+			Status:      "open",
+			Deadline:    e.Completion.UTC().Format(time.RFC3339),
+			Remediations: []Response{{
+				UUID:        uuid.NewString(),
+				Lifecycle:   "planned",
+				Title:       "Remediate " + e.ControlID,
+				Description: "Scheduled from " + e.Start.UTC().Format(time.DateOnly) + " to " + e.Completion.UTC().Format(time.DateOnly) + ".",
+				Tasks:       tasks,
+			}},
+		}
+
+		if e.Priority != "" {
+			risk.Props = append(risk.Props, Prop{Name: "gap-priority", Value: e.Priority, NS: Namespace})
+		}
+		if e.EstimatedEffort != "" {
+			risk.Props = append(risk.Props, Prop{Name: "gap-effort", Value: e.EstimatedEffort, NS: Namespace})
+		}
+
+		doc.Observations = append(doc.Observations, obs)
+		doc.Risks = append(doc.Risks, risk)
+		doc.POAMItems = append(doc.POAMItems, POAMItem{
+			UUID:                uuid.NewString(),
+			Title:               e.ControlID + ": " + e.Title,
+			Description:         weakness,
+			Props:               append(append([]Prop{}, controlProps...), Prop{Name: "poam-id", Value: e.ID, NS: Namespace}),
+			RelatedObservations: []RelatedObservation{{ObservationUUID: obs.UUID}},
+			RelatedRisks:        []AssociatedRisk{{RiskUUID: risk.UUID}},
+		})
+	}
+	return doc
+}
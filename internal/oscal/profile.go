@@ -0,0 +1,316 @@
+package oscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Profile is an OSCAL profile: a selection of controls from one or more
+// catalogs, such as the NIST SP 800-53B baselines.
+type Profile struct {
+	UUID       string      `json:"uuid"`
+	Metadata   Metadata    `json:"metadata"`
+	Imports    []Import    `json:"imports"`
+	BackMatter *BackMatter `json:"back-matter,omitempty"`
+}
+
+// Import selects controls from a catalog or another profile.
+type Import struct {
+	Href            string            `json:"href"`
+	IncludeAll      *struct{}         `json:"include-all,omitempty"`
+	IncludeControls []ControlSelector `json:"include-controls,omitempty"`
+	ExcludeControls []ControlSelector `json:"exclude-controls,omitempty"`
+}
+
+// ControlSelector selects controls by ID or ID pattern.
+type ControlSelector struct {
+	WithChildControls string     `json:"with-child-controls,omitempty"`
+	WithIDs           []string   `json:"with-ids,omitempty"`
+	Matching          []Matching `json:"matching,omitempty"`
+}
+
+// Matching selects controls whose ID matches a glob pattern.
+type Matching struct {
+	Pattern string `json:"pattern"`
+}
+
+// BackMatter holds resources referenced from a document.
+type BackMatter struct {
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// Resource is a back-matter resource; imports may reference one as
+// "#<uuid>".
+type Resource struct {
+	UUID   string  `json:"uuid"`
+	Title  string  `json:"title,omitempty"`
+	Rlinks []Rlink `json:"rlinks,omitempty"`
+}
+
+// Rlink locates a resource.
+type Rlink struct {
+	Href      string `json:"href"`
+	MediaType string `json:"media-type,omitempty"`
+}
+
+// ParseProfile decodes an OSCAL profile document.
+func ParseProfile(r io.Reader) (*Profile, error) {
+	var doc struct {
+		Profile *Profile `json:"profile"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding oscal profile: %w", err)
+	}
+	if doc.Profile == nil {
+		return nil, fmt.Errorf("decoding oscal profile: no profile object")
+	}
+	return doc.Profile, nil
+}
+
+// Load reads an OSCAL catalog or profile from a JSON file. Profiles are
+// resolved against the catalogs and profiles they import, which must be
+// local files; relative hrefs are resolved against the profile's directory.
+func Load(file string) (*Catalog, error) {
+	return load(file, map[string]bool{})
+}
+
+// Parse decodes an OSCAL catalog or profile document, such as one uploaded
+// over the API. A profile may only import catalogs and profiles inside dir.
+func Parse(r io.Reader, dir string) (*Catalog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data, "document", dir, true, map[string]bool{})
+}
+
+func load(file string, seen map[string]bool) (*Catalog, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	if seen[abs] {
+		return nil, fmt.Errorf("profile import cycle through %s", file)
+	}
+	seen[abs] = true
+	defer delete(seen, abs)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data, file, filepath.Dir(file), false, seen)
+}
+
+// decode decodes the catalog or profile named name, resolving a profile's
+// imports against dir. When confined, imports outside dir are rejected.
+func decode(data []byte, name, dir string, confined bool, seen map[string]bool) (*Catalog, error) {
+	var doc struct {
+		Catalog *Catalog `json:"catalog"`
+		Profile *Profile `json:"profile"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+	switch {
+	case doc.Catalog != nil:
+		return doc.Catalog, nil
+	case doc.Profile != nil:
+		return ResolveProfile(doc.Profile, func(href string) (*Catalog, error) {
+			local, err := localPath(dir, href)
+			if err != nil {
+				return nil, err
+			}
+			if confined && !within(dir, local) {
+				return nil, fmt.Errorf("import %s: only catalogs in the catalog directory are supported", href)
+			}
+			return load(local, seen)
+		})
+	default:
+		return nil, fmt.Errorf("%s is not an OSCAL catalog or profile", name)
+	}
+}
+
+// localPath maps an import href to a file path.
+func localPath(dir, href string) (string, error) {
+	if strings.Contains(href, "://") && !strings.HasPrefix(href, "file://") {
+		return "", fmt.Errorf("import %s: only local files are supported", href)
+	}
+	href = strings.TrimPrefix(href, "file://")
+	if filepath.IsAbs(href) {
+		return href, nil
+	}
+	return filepath.Join(dir, filepath.FromSlash(href)), nil
+}
+
+// within reports whether file is inside dir.
+func within(dir, file string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// ResolveProfile returns the catalog a profile selects. fetch loads the
+// catalog or resolved profile behind an import href. Selected controls keep
+// their groups; groups left without controls are dropped.
+func ResolveProfile(p *Profile, fetch func(href string) (*Catalog, error)) (*Catalog, error) {
+	out := &Catalog{UUID: p.UUID, Metadata: p.Metadata}
+	for _, imp := range p.Imports {
+		href := imp.Href
+		if strings.HasPrefix(href, "#") {
+			resolved, err := p.resource(strings.TrimPrefix(href, "#"))
+			if err != nil {
+				return nil, err
+			}
+			href = resolved
+		}
+		cat, err := fetch(href)
+		if err != nil {
+			return nil, fmt.Errorf("importing %s: %w", imp.Href, err)
+		}
+
+		sel := imp.selection(cat)
+		out.Params = append(out.Params, cat.Params...)
+		for _, g := range cat.Groups {
+			if g, ok := selectGroup(g, sel); ok {
+				out.Groups = append(out.Groups, g)
+			}
+		}
+		for _, c := range cat.Controls {
+			if c, ok := selectControl(c, sel); ok {
+				out.Controls = append(out.Controls, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+// resource returns the first rlink of the back-matter resource with uuid.
+func (p *Profile) resource(uuid string) (string, error) {
+	if p.BackMatter != nil {
+		for _, r := range p.BackMatter.Resources {
+			if r.UUID == uuid && len(r.Rlinks) > 0 {
+				return r.Rlinks[0].Href, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("import #%s: no back-matter resource with a link", uuid)
+}
+
+// selection returns the IDs of the controls an import selects.
+func (imp Import) selection(cat *Catalog) map[string]bool {
+	var all []Control
+	for _, g := range cat.Groups {
+		all = appendGroupControls(all, g)
+	}
+	for _, c := range cat.Controls {
+		all = appendControls(all, c)
+	}
+	byID := make(map[string]Control, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+
+	sel := make(map[string]bool)
+	if imp.IncludeAll != nil || len(imp.IncludeControls) == 0 {
+		for _, c := range all {
+			sel[c.ID] = true
+		}
+	}
+	for _, s := range imp.IncludeControls {
+		for _, id := range s.matches(all) {
+			sel[id] = true
+			if s.WithChildControls == "yes" {
+				markChildren(byID[id], sel, true)
+			}
+		}
+	}
+	for _, s := range imp.ExcludeControls {
+		for _, id := range s.matches(all) {
+			delete(sel, id)
+			if s.WithChildControls == "yes" {
+				markChildren(byID[id], sel, false)
+			}
+		}
+	}
+	return sel
+}
+
+func (s ControlSelector) matches(all []Control) []string {
+	var ids []string
+	ids = append(ids, s.WithIDs...)
+	for _, m := range s.Matching {
+		for _, c := range all {
+			if ok, _ := path.Match(m.Pattern, c.ID); ok {
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func markChildren(c Control, sel map[string]bool, include bool) {
+	for _, e := range c.Controls {
+		if include {
+			sel[e.ID] = true
+		} else {
+			delete(sel, e.ID)
+		}
+		markChildren(e, sel, include)
+	}
+}
+
+func appendGroupControls(out []Control, g Group) []Control {
+	for _, c := range g.Controls {
+		out = appendControls(out, c)
+	}
+	for _, sub := range g.Groups {
+		out = appendGroupControls(out, sub)
+	}
+	return out
+}
+
+func appendControls(out []Control, c Control) []Control {
+	out = append(out, c)
+	for _, e := range c.Controls {
+		out = appendControls(out, e)
+	}
+	return out
+}
+
+func selectGroup(g Group, sel map[string]bool) (Group, bool) {
+	out := g
+	out.Controls, out.Groups = nil, nil
+	for _, c := range g.Controls {
+		if c, ok := selectControl(c, sel); ok {
+			out.Controls = append(out.Controls, c)
+		}
+	}
+	for _, sub := range g.Groups {
+		if sub, ok := selectGroup(sub, sel); ok {
+			out.Groups = append(out.Groups, sub)
+		}
+	}
+	return out, len(out.Controls) > 0 || len(out.Groups) > 0
+}
+
+// selectControl keeps a control if it or any of its enhancements is
+// selected; an unselected parent is kept so selected enhancements stay
+// attached to it.
+func selectControl(c Control, sel map[string]bool) (Control, bool) {
+	out := c
+	out.Controls = nil
+	for _, e := range c.Controls {
+		if e, ok := selectControl(e, sel); ok {
+			out.Controls = append(out.Controls, e)
+		}
+	}
+	return out, sel[c.ID] || len(out.Controls) > 0
+}
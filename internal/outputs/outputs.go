@@ -0,0 +1,342 @@
+// Package outputs validates structured agent outputs, such as the arguments
+// of a function call produced by the model, against JSON Schemas registered
+// per agent and tool. Outputs that are malformed or carry fields the schema
+// does not allow are flagged, or blocked when the schema says so.
+package outputs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/open-policy-agent/opa/rego"
+)
+
+var (
+	ErrNotFound = errors.New("output schema not found")
+	// ErrConflict is returned when a schema claims the agent and tool of
+	// another schema.
+	ErrConflict = errors.New("output schema conflict")
+)
+
+// Mode decides what happens to an output that fails validation.
+type Mode string
+
+const (
+	// Flag records a signal and lets the output through.
+	Flag Mode = "flag"
+	// Block records a signal and tells the SDK to discard the output.
+	Block Mode = "block"
+)
+
+// Schema is a JSON Schema registered for an agent's outputs from a tool.
+type Schema struct {
+	ID string `json:"id"`
+	// AgentID limits the schema to one agent. Empty matches every agent.
+	AgentID string `json:"agent_id,omitempty"`
+	// Tool limits the schema to one tool, matched case-insensitively.
+	// Empty matches every tool.
+	Tool   string          `json:"tool,omitempty"`
+	Schema json.RawMessage `json:"schema"`
+	Mode   Mode            `json:"mode"`
+	// Strict rejects object properties the schema does not declare, so an
+	// agent cannot widen a call's scope with extra arguments. Object
+	// schemas that set additionalProperties themselves are left alone.
+	Strict      bool   `json:"strict,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks the schema's fields. It does not compile the schema; Put
+// does.
+func (s *Schema) Validate() error {
+	if s.ID == "" {
+		return errors.New("id is required")
+	}
+	if s.Mode != Flag && s.Mode != Block {
+		return fmt.Errorf("mode must be %q or %q", Flag, Block)
+	}
+	if len(s.Schema) == 0 {
+		return errors.New("schema is required")
+	}
+	return nil
+}
+
+// matches reports whether the schema applies to a tool output of an agent.
+func (s Schema) matches(agentID, tool string) bool {
+	return (s.AgentID == "" || s.AgentID == agentID) &&
+		(s.Tool == "" || strings.EqualFold(s.Tool, tool))
+}
+
+// specificity ranks schemas for the same output: agent and tool, then
+// agent, then tool, then the catch-all.
+func (s Schema) specificity() int {
+	n := 0
+	if s.AgentID != "" {
+		n += 2
+	}
+	if s.Tool != "" {
+		n++
+	}
+	return n
+}
+
+// rootField is the violation field for the output as a whole.
+const rootField = "(root)"
+
+// Violation is one way an output fails its schema.
+type Violation struct {
+	// Field is the path of the offending value, "(root)" for the output
+	// itself.
+	Field   string `json:"field"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Result is the outcome of validating an output.
+type Result struct {
+	SchemaID   string      `json:"schema_id"`
+	Mode       Mode        `json:"mode"`
+	Valid      bool        `json:"valid"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Blocked reports whether the output must be discarded.
+func (r *Result) Blocked() bool { return r != nil && !r.Valid && r.Mode == Block }
+
+// compiled is a registered schema with the document it is validated with.
+type compiled struct {
+	Schema
+	doc any
+}
+
+// Registry holds output schemas and validates outputs against them. It is
+// safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]compiled
+
+	match  rego.PreparedEvalQuery
+	verify rego.PreparedEvalQuery
+}
+
+// NewRegistry creates a registry holding schemas.
+func NewRegistry(ctx context.Context, schemas []Schema) (*Registry, error) {
+	match, err := rego.New(rego.Query("x := json.match_schema(input.output, input.schema)")).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("preparing schema validation: %w", err)
+	}
+	verify, err := rego.New(rego.Query("x := json.verify_schema(input.schema)")).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("preparing schema verification: %w", err)
+	}
+	r := &Registry{schemas: make(map[string]compiled, len(schemas)), match: match, verify: verify}
+	for _, s := range schemas {
+		if err := r.Put(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Get returns a schema by ID.
+func (r *Registry) Get(id string) (Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.schemas[id]
+	if !ok {
+		return Schema{}, ErrNotFound
+	}
+	return s.Schema, nil
+}
+
+// List returns every schema sorted by ID.
+func (r *Registry) List() []Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Schema, 0, len(r.schemas))
+	for _, s := range r.schemas {
+		out = append(out, s.Schema)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Put creates or replaces a schema after checking that it is a valid JSON
+// Schema. Only one schema may cover an agent and tool pair.
+func (r *Registry) Put(ctx context.Context, s Schema) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("output schema %s: %w", s.ID, err)
+	}
+	var doc any
+	if err := json.Unmarshal(s.Schema, &doc); err != nil {
+		return fmt.Errorf("output schema %s: %w", s.ID, err)
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return fmt.Errorf("output schema %s: schema must be a JSON object", s.ID)
+	}
+	if s.Strict {
+		closeObjects(doc)
+	}
+	if err := r.verifySchema(ctx, doc); err != nil {
+		return fmt.Errorf("output schema %s: %w", s.ID, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, other := range r.schemas {
+		if id != s.ID && other.AgentID == s.AgentID && strings.EqualFold(other.Tool, s.Tool) {
+			return fmt.Errorf("%w: schema %s already covers agent %q and tool %q", ErrConflict, id, s.AgentID, s.Tool)
+		}
+	}
+	s.Schema = append(json.RawMessage(nil), s.Schema...)
+	r.schemas[s.ID] = compiled{Schema: s, doc: doc}
+	return nil
+}
+
+// Delete removes a schema.
+func (r *Registry) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.schemas[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.schemas, id)
+	return nil
+}
+
+// Lookup returns the most specific schema covering an agent's output from
+// a tool.
+func (r *Registry) Lookup(agentID, tool string) (Schema, bool) {
+	c, ok := r.lookup(agentID, tool)
+	return c.Schema, ok
+}
+
+func (r *Registry) lookup(agentID, tool string) (compiled, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var best compiled
+	found := false
+	for _, s := range r.schemas {
+		if !s.matches(agentID, tool) {
+			continue
+		}
+		if !found || s.specificity() > best.specificity() {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// Validate checks an agent's output from a tool against the schema covering
+// it. It returns nil when no schema applies. Outputs sent as a JSON encoded
+// string, as models often return function call arguments, are decoded
+// before validation.
+func (r *Registry) Validate(ctx context.Context, agentID, tool string, output json.RawMessage) (*Result, error) {
+	s, ok := r.lookup(agentID, tool)
+	if !ok {
+		return nil, nil
+	}
+	res := &Result{SchemaID: s.ID, Mode: s.Mode}
+
+	var value any
+	if len(output) == 0 {
+		output = json.RawMessage("null")
+	}
+	if err := json.Unmarshal(output, &value); err != nil {
+		res.Violations = []Violation{{Field: rootField, Type: "malformed", Message: "output is not valid JSON"}}
+		return res, nil
+	}
+	if str, isString := value.(string); isString {
+		var decoded any
+		if err := json.Unmarshal([]byte(str), &decoded); err == nil {
+			value = decoded
+		}
+	}
+
+	// The builtin only accepts objects as documents; any JSON value can be
+	// passed encoded.
+	doc, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := r.match.Eval(ctx, rego.EvalInput(map[string]any{"output": string(doc), "schema": s.doc}))
+	if err != nil {
+		return nil, fmt.Errorf("validating output against schema %s: %w", s.ID, err)
+	}
+	pair, ok := resultPair(rs)
+	if !ok {
+		return nil, fmt.Errorf("validating output against schema %s: unexpected result", s.ID)
+	}
+	res.Valid, _ = pair[0].(bool)
+	errs, _ := pair[1].([]any)
+	for _, e := range errs {
+		m, _ := e.(map[string]any)
+		field, _ := m["field"].(string)
+		typ, _ := m["type"].(string)
+		desc, _ := m["desc"].(string)
+		if strings.EqualFold(field, rootField) {
+			field = rootField
+		}
+		res.Violations = append(res.Violations, Violation{Field: field, Type: typ, Message: desc})
+	}
+	return res, nil
+}
+
+func (r *Registry) verifySchema(ctx context.Context, doc any) error {
+	rs, err := r.verify.Eval(ctx, rego.EvalInput(map[string]any{"schema": doc}))
+	if err != nil {
+		return err
+	}
+	pair, ok := resultPair(rs)
+	if !ok {
+		return errors.New("unexpected schema verification result")
+	}
+	if valid, _ := pair[0].(bool); !valid {
+		return fmt.Errorf("invalid JSON Schema: %v", pair[1])
+	}
+	return nil
+}
+
+// resultPair extracts the [bool, details] pair the JSON Schema builtins
+// return.
+func resultPair(rs rego.ResultSet) ([]any, bool) {
+	if len(rs) == 0 {
+		return nil, false
+	}
+	pair, ok := rs[0].Bindings["x"].([]any)
+	if !ok || len(pair) != 2 {
+		return nil, false
+	}
+	return pair, true
+}
+
+// closeObjects sets additionalProperties to false on every object schema
+// that declares properties and does not set it, descending into nested
+// property, item, and anyOf/oneOf schemas. allOf branches are left open,
+// since closing each would reject the properties declared by the others.
+func closeObjects(node any) {
+	m, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+	if props, ok := m["properties"].(map[string]any); ok {
+		if _, set := m["additionalProperties"]; !set {
+			m["additionalProperties"] = false
+		}
+		for _, p := range props {
+			closeObjects(p)
+		}
+	}
+	closeObjects(m["items"])
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if list, ok := m[key].([]any); ok {
+			for _, sub := range list {
+				closeObjects(sub)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package outputs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/outputs"
+)
+
+const transferSchema = `{
+	"type": "object",
+	"required": ["account", "amount"],
+	"properties": {
+		"account": {"type": "string"},
+		"amount": {"type": "number", "maximum": 1000}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	reg, err := outputs.NewRegistry(ctx, []outputs.Schema{
+		{ID: "transfer", Tool: "transfer_funds", Schema: json.RawMessage(transferSchema), Mode: outputs.Block, Strict: true},
+		{ID: "any-object", Schema: json.RawMessage(`{"type": "object"}`), Mode: outputs.Flag},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		output    string
+		schemaID  string
+		valid     bool
+		violation string
+	}{
+		{"valid arguments", "transfer_funds", `{"account": "acct-1", "amount": 20}`, "transfer", true, ""},
+		{"json encoded string", "Transfer_Funds", `"{\"account\": \"acct-1\", \"amount\": 20}"`, "transfer", true, ""},
+		{"out of range", "transfer_funds", `{"account": "acct-1", "amount": 5000}`, "transfer", false, "amount"},
+		{"missing field", "transfer_funds", `{"amount": 20}`, "transfer", false, "(root)"},
+		{"over-scoped", "transfer_funds", `{"account": "acct-1", "amount": 20, "cc": "attacker"}`, "transfer", false, "(root)"},
+		{"malformed", "transfer_funds", `{"account": `, "transfer", false, "(root)"},
+		{"catch-all", "search", `["not", "an", "object"]`, "any-object", false, "(root)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := reg.Validate(ctx, "agent-1", tt.tool, json.RawMessage(tt.output))
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if res == nil || res.SchemaID != tt.schemaID {
+				t.Fatalf("result = %+v, want schema %s", res, tt.schemaID)
+			}
+			if res.Valid != tt.valid {
+				t.Fatalf("valid = %v, want %v (violations %+v)", res.Valid, tt.valid, res.Violations)
+			}
+			if tt.valid {
+				return
+			}
+			if len(res.Violations) == 0 || res.Violations[0].Field != tt.violation {
+				t.Errorf("violations = %+v, want field %s", res.Violations, tt.violation)
+			}
+			if got, want := res.Blocked(), res.Mode == outputs.Block; got != want {
+				t.Errorf("blocked = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLookupPrefersSpecificSchema(t *testing.T) {
+	ctx := context.Background()
+	obj := json.RawMessage(`{"type": "object"}`)
+	reg, err := outputs.NewRegistry(ctx, []outputs.Schema{
+		{ID: "all", Schema: obj, Mode: outputs.Flag},
+		{ID: "tool", Tool: "search", Schema: obj, Mode: outputs.Flag},
+		{ID: "agent", AgentID: "agent-1", Schema: obj, Mode: outputs.Flag},
+		{ID: "agent-tool", AgentID: "agent-1", Tool: "search", Schema: obj, Mode: outputs.Block},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	tests := []struct{ agent, tool, want string }{
+		{"agent-1", "search", "agent-tool"},
+		{"agent-1", "browse", "agent"},
+		{"agent-2", "search", "tool"},
+		{"agent-2", "browse", "all"},
+	}
+	for _, tt := range tests {
+		s, ok := reg.Lookup(tt.agent, tt.tool)
+		if !ok || s.ID != tt.want {
+			t.Errorf("Lookup(%s, %s) = %s, want %s", tt.agent, tt.tool, s.ID, tt.want)
+		}
+	}
+
+	if err := reg.Delete("all"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if res, err := reg.Validate(ctx, "agent-2", "browse", json.RawMessage(`{}`)); err != nil || res != nil {
+		t.Errorf("Validate without a schema = %+v, %v; want nil", res, err)
+	}
+}
+
+func TestPutRejectsInvalidSchemas(t *testing.T) {
+	ctx := context.Background()
+	reg, err := outputs.NewRegistry(ctx, []outputs.Schema{
+		{ID: "search", Tool: "search", Schema: json.RawMessage(`{"type": "object"}`), Mode: outputs.Flag},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		schema outputs.Schema
+	}{
+		{"missing mode", outputs.Schema{ID: "a", Schema: json.RawMessage(`{}`)}},
+		{"not an object", outputs.Schema{ID: "a", Schema: json.RawMessage(`[1]`), Mode: outputs.Flag}},
+		{"invalid keyword value", outputs.Schema{ID: "a", Schema: json.RawMessage(`{"type": 42}`), Mode: outputs.Flag}},
+	}
+	for _, tt := range tests {
+		if err := reg.Put(ctx, tt.schema); err == nil {
+			t.Errorf("%s: Put succeeded, want error", tt.name)
+		}
+	}
+
+	err = reg.Put(ctx, outputs.Schema{ID: "other", Tool: "SEARCH", Schema: json.RawMessage(`{}`), Mode: outputs.Flag})
+	if !errors.Is(err, outputs.ErrConflict) {
+		t.Errorf("Put duplicate coverage = %v, want ErrConflict", err)
+	}
+}
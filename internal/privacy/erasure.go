@@ -0,0 +1,279 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxReports bounds the erasure reports kept in memory.
+const maxReports = 1000
+
+// Mode selects what happens to a data subject's records.
+type Mode string
+
+const (
+	// ModeDelete removes every record tied to the subject.
+	ModeDelete Mode = "delete"
+	// ModePseudonymize keeps the records for aggregate analysis and
+	// rewrites their personal data fields.
+	ModePseudonymize Mode = "pseudonymize"
+)
+
+// Report statuses.
+const (
+	StatusCompleted = "completed"
+	StatusPartial   = "partial"
+	StatusFailed    = "failed"
+)
+
+// Request asks for a data subject's identifiers to be erased within an
+// organization.
+type Request struct {
+	OrgID       string
+	UserID      string
+	Mode        Mode
+	RequestedBy string
+}
+
+// Plan is what each store is asked to erase. Stores run in order and may
+// hand payload hashes to the stores after them.
+type Plan struct {
+	OrgID  string
+	UserID string
+	// Pseudonym replaces the user ID in pseudonymize mode.
+	Pseudonym string
+	Mode      Mode
+	Fields    []Field
+
+	key      []byte
+	payloads map[string]struct{}
+}
+
+// Action returns how a field is treated, and whether it is personal data.
+func (p *Plan) Action(name string) (FieldAction, bool) {
+	for _, f := range p.Fields {
+		if f.Name == name {
+			return f.Action, true
+		}
+	}
+	return "", false
+}
+
+// Attributes returns the span attribute keys that hold personal data.
+func (p *Plan) Attributes() []string {
+	var keys []string
+	for _, f := range p.Fields {
+		if key, ok := f.Attribute(); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Pseudonymize returns the pseudonym for any identifier, e.g. a session ID.
+func (p *Plan) Pseudonymize(value string) string {
+	return pseudonymize(p.key, value)
+}
+
+// ErasePayloads reports whether stored payloads are erased: always in
+// delete mode, and when payloads are personal data otherwise.
+func (p *Plan) ErasePayloads() bool {
+	_, ok := p.Action(FieldPayloads)
+	return p.Mode == ModeDelete || ok
+}
+
+// AddPayloads records payload hashes that only the subject's records
+// reference.
+func (p *Plan) AddPayloads(hashes ...string) {
+	for _, h := range hashes {
+		p.payloads[h] = struct{}{}
+	}
+}
+
+// Payloads returns the recorded payload hashes in sorted order.
+func (p *Plan) Payloads() []string {
+	out := make([]string, 0, len(p.payloads))
+	for h := range p.payloads {
+		out = append(out, h)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// StoreResult records what one store erased.
+type StoreResult struct {
+	Store string `json:"store"`
+	// Affected counts the records deleted or rewritten.
+	Affected int64    `json:"affected"`
+	Error    string   `json:"error,omitempty"`
+	Notes    []string `json:"notes,omitempty"`
+}
+
+// Report is the completion report for an erasure request. It names the
+// subject only by pseudonym, so it can be retained as proof of erasure.
+type Report struct {
+	ID          string        `json:"id"`
+	OrgID       string        `json:"org_id"`
+	Subject     string        `json:"subject"`
+	Mode        Mode          `json:"mode"`
+	Status      string        `json:"status"`
+	Fields      []Field       `json:"fields"`
+	Stores      []StoreResult `json:"stores"`
+	RequestedBy string        `json:"requested_by,omitempty"`
+	RequestedAt time.Time     `json:"requested_at"`
+	CompletedAt time.Time     `json:"completed_at"`
+}
+
+// Store erases a data subject's records from one backing store.
+type Store interface {
+	Name() string
+	Erase(ctx context.Context, plan *Plan) (StoreResult, error)
+}
+
+// Eraser runs erasure requests across stores and keeps their reports.
+type Eraser struct {
+	cfg    Config
+	stores []Store
+	// OnReport is called with every completed report, e.g. to append it
+	// to the audit log. Optional.
+	OnReport func(Report)
+	// Identities is the ingest pseudonymizer, when user IDs are
+	// pseudonymized at ingest. Stores are then searched by the subject's
+	// ingest pseudonym. Optional.
+	Identities *Pseudonymizer
+
+	mu      sync.Mutex
+	reports map[string]*Report
+	order   []string
+	now     func() time.Time
+}
+
+// NewEraser creates an eraser over stores, which run in the given order.
+// Stores that discover payload hashes must come before the payload store.
+func NewEraser(cfg Config, stores ...Store) (*Eraser, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Eraser{
+		cfg:     cfg,
+		stores:  stores,
+		reports: make(map[string]*Report),
+		now:     time.Now,
+	}, nil
+}
+
+// Stores returns the names of the stores erasure runs against.
+func (e *Eraser) Stores() []string {
+	names := make([]string, len(e.stores))
+	for i, s := range e.stores {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// Pseudonym returns the pseudonym a user ID is replaced with.
+func (e *Eraser) Pseudonym(userID string) string {
+	return pseudonymize(e.cfg.PseudonymKey, userID)
+}
+
+// Erase runs a request against every store. A store that fails does not
+// stop the others; the report's status is partial when some stores failed
+// and failed when all did.
+func (e *Eraser) Erase(ctx context.Context, req Request) (*Report, error) {
+	if req.OrgID == "" || req.UserID == "" {
+		return nil, fmt.Errorf("%w: organization and user ID are required", ErrInvalidRequest)
+	}
+	if req.Mode == "" {
+		req.Mode = ModeDelete
+	}
+	if req.Mode != ModeDelete && req.Mode != ModePseudonymize {
+		return nil, fmt.Errorf("%w: mode must be %s or %s", ErrInvalidRequest, ModeDelete, ModePseudonymize)
+	}
+
+	userID := req.UserID
+	if e.Identities != nil {
+		userID = e.Identities.Pseudonym(req.OrgID, IdentityUser, req.UserID)
+	}
+	plan := &Plan{
+		OrgID:     req.OrgID,
+		UserID:    userID,
+		Pseudonym: e.Pseudonym(req.UserID),
+		Mode:      req.Mode,
+		Fields:    e.cfg.fields(),
+		key:       e.cfg.PseudonymKey,
+		payloads:  make(map[string]struct{}),
+	}
+	rep := &Report{
+		ID:          uuid.NewString(),
+		OrgID:       req.OrgID,
+		Subject:     plan.Pseudonym,
+		Mode:        req.Mode,
+		Fields:      plan.Fields,
+		RequestedBy: req.RequestedBy,
+		RequestedAt: e.now().UTC(),
+	}
+
+	failed := 0
+	for _, s := range e.stores {
+		res, err := s.Erase(ctx, plan)
+		res.Store = s.Name()
+		if err != nil {
+			res.Error = err.Error()
+			failed++
+		}
+		rep.Stores = append(rep.Stores, res)
+	}
+	switch {
+	case failed == 0:
+		rep.Status = StatusCompleted
+	case failed < len(e.stores):
+		rep.Status = StatusPartial
+	default:
+		rep.Status = StatusFailed
+	}
+	rep.CompletedAt = e.now().UTC()
+
+	e.mu.Lock()
+	e.reports[rep.ID] = rep
+	e.order = append(e.order, rep.ID)
+	if len(e.order) > maxReports {
+		delete(e.reports, e.order[0])
+		e.order = e.order[1:]
+	}
+	e.mu.Unlock()
+
+	if e.OnReport != nil {
+		e.OnReport(*rep)
+	}
+	return rep, nil
+}
+
+// Report returns an organization's erasure report by ID.
+func (e *Eraser) Report(orgID, id string) (*Report, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rep, ok := e.reports[id]
+	if !ok || rep.OrgID != orgID {
+		return nil, false
+	}
+	cp := *rep
+	return &cp, true
+}
+
+// Reports returns an organization's erasure reports, newest first.
+func (e *Eraser) Reports(orgID string) []Report {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out []Report
+	for i := len(e.order) - 1; i >= 0; i-- {
+		if rep := e.reports[e.order[i]]; rep.OrgID == orgID {
+			out = append(out, *rep)
+		}
+	}
+	return out
+}
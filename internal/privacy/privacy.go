@@ -0,0 +1,124 @@
+// Package privacy handles personal data in observability stores: which
+// fields count as personal data, and data subject erasure across the stores
+// that hold them.
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// minKeyBytes is the shortest accepted pseudonymization key.
+const minKeyBytes = 16
+
+// Personal data fields. Span attributes are named "attributes.<key>".
+const (
+	FieldUserID    = "user_id"
+	FieldSessionID = "session_id"
+	// FieldPayloads covers tool inputs and outputs kept in the payload store.
+	FieldPayloads = "payloads"
+
+	attributePrefix = "attributes."
+)
+
+// FieldAction is how a personal data field is treated when a subject's
+// records are pseudonymized rather than deleted.
+type FieldAction string
+
+const (
+	// ActionPseudonymize replaces the value with a keyed hash, so records
+	// of the same subject stay linkable without identifying them.
+	ActionPseudonymize FieldAction = "pseudonymize"
+	// ActionRedact removes the value.
+	ActionRedact FieldAction = "redact"
+)
+
+// Field marks one field as personal data.
+type Field struct {
+	Name   string      `json:"name"`
+	Action FieldAction `json:"action"`
+}
+
+// Attribute reports whether the field is a span attribute, and its key.
+func (f Field) Attribute() (string, bool) {
+	if !strings.HasPrefix(f.Name, attributePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(f.Name, attributePrefix), true
+}
+
+// DefaultFields are used when no personal data fields are configured.
+var DefaultFields = []Field{
+	{Name: FieldUserID, Action: ActionPseudonymize},
+	{Name: FieldSessionID, Action: ActionPseudonymize},
+	{Name: FieldPayloads, Action: ActionRedact},
+}
+
+// Config configures personal data handling.
+type Config struct {
+	// Fields lists the fields that hold personal data. The subject's user
+	// ID is always treated as personal data; it is pseudonymized unless
+	// listed with another action.
+	Fields []Field
+	// PseudonymKey keys the HMAC behind pseudonyms. Keep it secret: anyone
+	// holding it can test whether a pseudonym belongs to a known user ID.
+	PseudonymKey []byte
+}
+
+// Validate checks the key and every field's name and action.
+func (c Config) Validate() error {
+	if len(c.PseudonymKey) < minKeyBytes {
+		return fmt.Errorf("pseudonym key must be at least %d bytes", minKeyBytes)
+	}
+	seen := make(map[string]bool)
+	for _, f := range c.Fields {
+		if seen[f.Name] {
+			return fmt.Errorf("personal data field %q is listed twice", f.Name)
+		}
+		seen[f.Name] = true
+		if f.Action != ActionPseudonymize && f.Action != ActionRedact {
+			return fmt.Errorf("personal data field %q: invalid action %q", f.Name, f.Action)
+		}
+		switch key, isAttr := f.Attribute(); {
+		case f.Name == FieldUserID, f.Name == FieldSessionID:
+		case f.Name == FieldPayloads, isAttr && key != "":
+			// Payloads and attribute values live inside other columns and
+			// cannot be rewritten in place, only removed.
+			if f.Action != ActionRedact {
+				return fmt.Errorf("personal data field %q can only be redacted", f.Name)
+			}
+		default:
+			return fmt.Errorf("unknown personal data field %q", f.Name)
+		}
+	}
+	return nil
+}
+
+// fields returns the configured fields, or the defaults, with the user ID
+// always present.
+func (c Config) fields() []Field {
+	fields := c.Fields
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	for _, f := range fields {
+		if f.Name == FieldUserID {
+			return fields
+		}
+	}
+	return append([]Field{{Name: FieldUserID, Action: ActionPseudonymize}}, fields...)
+}
+
+// ErrInvalidRequest is returned for erasure requests that cannot be run.
+var ErrInvalidRequest = errors.New("invalid erasure request")
+
+// pseudonymize returns a stable pseudonym for value under key.
+func pseudonymize(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return "anon_" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
@@ -0,0 +1,261 @@
+package privacy_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/storage"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// fakeStore records the plan it was given and hands payload hashes on.
+type fakeStore struct {
+	name     string
+	payloads []string
+	err      error
+	got      *privacy.Plan
+}
+
+func (s *fakeStore) Name() string { return s.name }
+
+func (s *fakeStore) Erase(ctx context.Context, plan *privacy.Plan) (privacy.StoreResult, error) {
+	s.got = plan
+	plan.AddPayloads(s.payloads...)
+	return privacy.StoreResult{Affected: 2}, s.err
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []privacy.Field
+		key    []byte
+		want   string
+	}{
+		{name: "defaults", key: testKey},
+		{name: "short key", key: []byte("short"), want: "at least"},
+		{name: "attribute redacted", key: testKey, fields: []privacy.Field{{Name: "attributes.email", Action: privacy.ActionRedact}}},
+		{name: "attribute pseudonymized", key: testKey, fields: []privacy.Field{{Name: "attributes.email", Action: privacy.ActionPseudonymize}}, want: "only be redacted"},
+		{name: "unknown field", key: testKey, fields: []privacy.Field{{Name: "email", Action: privacy.ActionRedact}}, want: "unknown"},
+		{name: "bad action", key: testKey, fields: []privacy.Field{{Name: "session_id", Action: "hash"}}, want: "invalid action"},
+		{name: "duplicate", key: testKey, fields: []privacy.Field{{Name: "user_id", Action: privacy.ActionRedact}, {Name: "user_id", Action: privacy.ActionRedact}}, want: "twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := privacy.Config{Fields: tt.fields, PseudonymKey: tt.key}.Validate()
+			if tt.want == "" && err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEraser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs every store and reports by pseudonym", func(t *testing.T) {
+		first := &fakeStore{name: "first", payloads: []string{"sha256:b", "sha256:a"}}
+		second := &fakeStore{name: "second"}
+		e, err := privacy.NewEraser(privacy.Config{PseudonymKey: testKey}, first, second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var audited []privacy.Report
+		e.OnReport = func(r privacy.Report) { audited = append(audited, r) }
+
+		rep, err := e.Erase(ctx, privacy.Request{OrgID: "org", UserID: "alice@example.com", Mode: privacy.ModePseudonymize})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep.Status != privacy.StatusCompleted || len(rep.Stores) != 2 || rep.Stores[0].Store != "first" {
+			t.Fatalf("report = %+v", rep)
+		}
+		if rep.Subject != e.Pseudonym("alice@example.com") || strings.Contains(rep.Subject, "alice") {
+			t.Errorf("subject = %q, want pseudonym", rep.Subject)
+		}
+		if got := second.got.Payloads(); len(got) != 2 || got[0] != "sha256:a" {
+			t.Errorf("payloads handed on = %v", got)
+		}
+		if action, ok := second.got.Action(privacy.FieldUserID); !ok || action != privacy.ActionPseudonymize {
+			t.Errorf("user_id action = %q, %v", action, ok)
+		}
+		if len(audited) != 1 || audited[0].ID != rep.ID {
+			t.Errorf("OnReport calls = %+v", audited)
+		}
+		if got, ok := e.Report("org", rep.ID); !ok || got.ID != rep.ID {
+			t.Errorf("Report() = %+v, %v", got, ok)
+		}
+		if _, ok := e.Report("other", rep.ID); ok {
+			t.Error("report visible to another organization")
+		}
+	})
+
+	t.Run("partial and failed status", func(t *testing.T) {
+		ok := &fakeStore{name: "ok"}
+		bad := &fakeStore{name: "bad", err: errors.New("unavailable")}
+		e, _ := privacy.NewEraser(privacy.Config{PseudonymKey: testKey}, ok, bad)
+		rep, err := e.Erase(ctx, privacy.Request{OrgID: "org", UserID: "u1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep.Status != privacy.StatusPartial || rep.Mode != privacy.ModeDelete || rep.Stores[1].Error != "unavailable" {
+			t.Errorf("report = %+v", rep)
+		}
+
+		e, _ = privacy.NewEraser(privacy.Config{PseudonymKey: testKey}, bad)
+		rep, _ = e.Erase(ctx, privacy.Request{OrgID: "org", UserID: "u1"})
+		if rep.Status != privacy.StatusFailed {
+			t.Errorf("status = %s, want failed", rep.Status)
+		}
+		if got := e.Reports("org"); len(got) != 1 {
+			t.Errorf("Reports() = %d, want 1", len(got))
+		}
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		e, _ := privacy.NewEraser(privacy.Config{PseudonymKey: testKey})
+		for _, req := range []privacy.Request{
+			{OrgID: "org"},
+			{OrgID: "org", UserID: "u1", Mode: "anonymize"},
+		} {
+			if _, err := e.Erase(ctx, req); !errors.Is(err, privacy.ErrInvalidRequest) {
+				t.Errorf("Erase(%+v) = %v, want ErrInvalidRequest", req, err)
+			}
+		}
+	})
+}
+
+func TestPromptStore(t *testing.T) {
+	reg := prompts.NewRegistry(prompts.Config{Window: time.Hour, DistinctUsers: 2})
+	now := time.Now()
+	reg.Observe(prompts.Observation{OrgID: "org", Hash: "h1", UserID: "alice", At: now})
+	reg.Observe(prompts.Observation{OrgID: "org", Hash: "h1", UserID: "bob", At: now})
+	reg.Observe(prompts.Observation{OrgID: "org", Hash: "h1", UserID: "alice", At: now})
+
+	e, _ := privacy.NewEraser(privacy.Config{PseudonymKey: testKey}, privacy.NewPromptStore(reg))
+	rep, err := e.Erase(context.Background(), privacy.Request{OrgID: "org", UserID: "alice", Mode: privacy.ModePseudonymize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Stores[0].Affected != 2 {
+		t.Errorf("affected = %d, want entry and offender record", rep.Stores[0].Affected)
+	}
+	entry, _ := reg.Get("org", "h1")
+	for _, u := range entry.Users {
+		if u == "alice" {
+			t.Fatalf("users = %v, still holds the subject", entry.Users)
+		}
+	}
+	for _, o := range reg.Offenders("org", 1) {
+		if o.ID == "alice" {
+			t.Fatalf("offender record still names the subject")
+		}
+	}
+}
+
+func TestPayloadStore(t *testing.T) {
+	ctx := context.Background()
+	provider, err := storage.NewLocalProvider(storage.LocalConfig{Root: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloads := storage.NewContentStore(provider, "payloads", 0)
+	hash, err := payloads.Put(ctx, "org", []byte(`{"email":"alice@example.com"}`), "application/json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, _ := payloads.Put(ctx, "org", []byte(`{"shared":true}`), "application/json")
+
+	source := &fakeStore{name: "spans", payloads: []string{hash}}
+	e, _ := privacy.NewEraser(privacy.Config{PseudonymKey: testKey}, source, privacy.NewPayloadStore(payloads))
+	rep, err := e.Erase(ctx, privacy.Request{OrgID: "org", UserID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Status != privacy.StatusCompleted || rep.Stores[1].Affected != 1 {
+		t.Fatalf("report = %+v", rep)
+	}
+	if _, err := payloads.Get(ctx, "org", hash); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("erased payload Get() = %v, want ErrNotFound", err)
+	}
+	body, err := payloads.Get(ctx, "org", kept)
+	if err != nil {
+		t.Fatalf("shared payload Get() = %v", err)
+	}
+	body.Close()
+}
+
+func TestPseudonymizer(t *testing.T) {
+	ctx := context.Background()
+	vault, err := storage.NewLocalProvider(storage.LocalConfig{Root: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := privacy.NewPseudonymizer([]byte("short"), vault, "identities"); err == nil {
+		t.Fatal("short key accepted")
+	}
+	p, err := privacy.NewPseudonymizer(testKey, vault, "identities")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := p.Pseudonymize("org", privacy.IdentityUser, "alice@example.com")
+	if !strings.HasPrefix(user, "usr_") || user != p.Pseudonym("org", privacy.IdentityUser, "alice@example.com") {
+		t.Fatalf("pseudonym = %q, want stable usr_ pseudonym", user)
+	}
+	if p.Pseudonym("other", privacy.IdentityUser, "alice@example.com") == user {
+		t.Error("pseudonym shared across organizations")
+	}
+	if p.Pseudonym("org", privacy.IdentitySession, "alice@example.com") == user {
+		t.Error("user and session pseudonyms collide")
+	}
+	if got := p.Pseudonymize("org", privacy.IdentityUser, ""); got != "" {
+		t.Errorf("empty value pseudonymized to %q", got)
+	}
+
+	if err := p.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// A fresh pseudonymizer with the same key reads the flushed vault.
+	restarted, _ := privacy.NewPseudonymizer(testKey, vault, "identities")
+	id, err := restarted.Reidentify(ctx, "org", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Kind != privacy.IdentityUser || id.Value != "alice@example.com" {
+		t.Errorf("Reidentify() = %+v", id)
+	}
+	if _, err := restarted.Reidentify(ctx, "other", user); !errors.Is(err, privacy.ErrUnknownPseudonym) {
+		t.Errorf("cross-organization Reidentify() = %v, want ErrUnknownPseudonym", err)
+	}
+	wrongKey, _ := privacy.NewPseudonymizer([]byte("fedcba9876543210fedcba9876543210"), vault, "identities")
+	if _, err := wrongKey.Reidentify(ctx, "org", user); err == nil {
+		t.Error("vault entry decrypted with the wrong key")
+	}
+
+	// Erasure searches by the ingest pseudonym and forgets the mapping.
+	source := &fakeStore{name: "spans"}
+	e, _ := privacy.NewEraser(privacy.Config{PseudonymKey: testKey}, source, privacy.NewIdentityStore(p))
+	e.Identities = p
+	rep, err := e.Erase(ctx, privacy.Request{OrgID: "org", UserID: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.got.UserID != user {
+		t.Errorf("stores searched for %q, want ingest pseudonym", source.got.UserID)
+	}
+	if rep.Stores[1].Affected != 1 {
+		t.Errorf("identity vault affected = %d, want 1", rep.Stores[1].Affected)
+	}
+	if _, err := p.Reidentify(ctx, "org", user); !errors.Is(err, privacy.ErrUnknownPseudonym) {
+		t.Errorf("Reidentify() after erasure = %v, want ErrUnknownPseudonym", err)
+	}
+}
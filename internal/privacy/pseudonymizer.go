@@ -0,0 +1,282 @@
+package privacy
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// IdentityKind is the kind of identifier a pseudonym stands for.
+type IdentityKind string
+
+const (
+	IdentityUser    IdentityKind = "user"
+	IdentitySession IdentityKind = "session"
+)
+
+// identityPrefixes tag pseudonyms with their kind, so a pseudonymized user
+// ID cannot be mistaken for a session ID.
+var identityPrefixes = map[IdentityKind]string{
+	IdentityUser:    "usr_",
+	IdentitySession: "ses_",
+}
+
+// maxPendingIdentities bounds the mappings waiting to be written to the
+// vault; beyond it new mappings are dropped and logged.
+const maxPendingIdentities = 10000
+
+// maxKnownIdentities bounds the cache of mappings already in the vault.
+// Clearing it only causes idempotent rewrites.
+const maxKnownIdentities = 100000
+
+// ErrUnknownPseudonym is returned when a pseudonym has no vault entry.
+var ErrUnknownPseudonym = errors.New("unknown pseudonym")
+
+// Identity is a re-identified pseudonym.
+type Identity struct {
+	Kind  IdentityKind `json:"kind"`
+	Value string       `json:"value"`
+}
+
+// vaultEntry is the sealed plaintext stored per pseudonym.
+type vaultEntry struct {
+	Kind      IdentityKind `json:"kind"`
+	Value     string       `json:"value"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Pseudonymizer replaces user and session IDs with keyed HMAC pseudonyms at
+// ingest. Pseudonyms are stable per organization, so analysts can
+// correlate a user's behavior without seeing who they are.
+//
+// Each mapping is sealed with AES-GCM and written to an identity vault kept
+// apart from the observability stores, so authorized investigators can
+// re-identify a pseudonym. Writes are batched off the ingest path by Run.
+// It is safe for concurrent use.
+type Pseudonymizer struct {
+	macKey []byte
+	aead   cipher.AEAD
+	vault  storage.Provider
+	prefix string
+
+	mu      sync.Mutex
+	pending map[string]vaultEntry // vault key
+	known   map[string]struct{}
+}
+
+// NewPseudonymizer creates a pseudonymizer. Separate HMAC and encryption
+// keys are derived from key, which should be kept apart from the
+// observability stores and the vault.
+func NewPseudonymizer(key []byte, vault storage.Provider, prefix string) (*Pseudonymizer, error) {
+	if len(key) < minKeyBytes {
+		return nil, fmt.Errorf("pseudonymization key must be at least %d bytes", minKeyBytes)
+	}
+	block, err := aes.NewCipher(deriveKey(key, "agentguard identity vault"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Pseudonymizer{
+		macKey:  deriveKey(key, "agentguard identity pseudonyms"),
+		aead:    aead,
+		vault:   vault,
+		prefix:  strings.TrimSuffix(prefix, "/"),
+		pending: make(map[string]vaultEntry),
+		known:   make(map[string]struct{}),
+	}, nil
+}
+
+func deriveKey(key []byte, label string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
+
+// Pseudonym returns the pseudonym for an identifier without recording it.
+func (p *Pseudonymizer) Pseudonym(orgID string, kind IdentityKind, value string) string {
+	mac := hmac.New(sha256.New, p.macKey)
+	mac.Write([]byte(orgID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return identityPrefixes[kind] + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// Pseudonymize returns the pseudonym for an identifier and queues its
+// mapping for the vault. Empty values are returned unchanged.
+func (p *Pseudonymizer) Pseudonymize(orgID string, kind IdentityKind, value string) string {
+	if value == "" {
+		return ""
+	}
+	pseudonym := p.Pseudonym(orgID, kind, value)
+	key := p.key(orgID, pseudonym)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.known[key]; ok {
+		return pseudonym
+	}
+	if _, ok := p.pending[key]; !ok {
+		if len(p.pending) >= maxPendingIdentities {
+			log.Warn().Str("org_id", orgID).Msg("identity vault backlog full, pseudonym mapping not recorded")
+			return pseudonym
+		}
+		p.pending[key] = vaultEntry{Kind: kind, Value: value, CreatedAt: time.Now().UTC()}
+	}
+	return pseudonym
+}
+
+func (p *Pseudonymizer) key(orgID, pseudonym string) string {
+	return p.prefix + "/" + url.PathEscape(orgID) + "/" + pseudonym
+}
+
+// Flush writes queued mappings to the vault. Mappings that fail to write
+// stay queued for the next flush.
+func (p *Pseudonymizer) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	batch := p.pending
+	p.pending = make(map[string]vaultEntry)
+	p.mu.Unlock()
+
+	var failed error
+	for key, entry := range batch {
+		if err := p.write(ctx, key, entry); err != nil {
+			failed = err
+			p.mu.Lock()
+			p.pending[key] = entry
+			p.mu.Unlock()
+			continue
+		}
+		p.mu.Lock()
+		if len(p.known) >= maxKnownIdentities {
+			p.known = make(map[string]struct{})
+		}
+		p.known[key] = struct{}{}
+		p.mu.Unlock()
+	}
+	if failed != nil {
+		return fmt.Errorf("writing identity vault: %w", failed)
+	}
+	return nil
+}
+
+func (p *Pseudonymizer) write(ctx context.Context, key string, entry vaultEntry) error {
+	plain, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := p.aead.Seal(nonce, nonce, plain, []byte(key))
+	return p.vault.Upload(ctx, key, bytes.NewReader(sealed), "application/octet-stream")
+}
+
+// Run flushes queued mappings every interval until ctx is cancelled, then
+// flushes once more.
+func (p *Pseudonymizer) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := p.Flush(flushCtx); err != nil {
+				log.Error().Err(err).Msg("final identity vault flush failed")
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := p.Flush(ctx); err != nil {
+				log.Error().Err(err).Msg("identity vault flush failed")
+			}
+		}
+	}
+}
+
+// Reidentify returns the identifier behind a pseudonym in an organization.
+func (p *Pseudonymizer) Reidentify(ctx context.Context, orgID, pseudonym string) (*Identity, error) {
+	key := p.key(orgID, pseudonym)
+
+	p.mu.Lock()
+	entry, ok := p.pending[key]
+	p.mu.Unlock()
+	if ok {
+		return &Identity{Kind: entry.Kind, Value: entry.Value}, nil
+	}
+
+	exists, err := p.vault.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUnknownPseudonym
+	}
+	rc, err := p.vault.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	sealed, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	n := p.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, fmt.Errorf("identity vault entry for %s is corrupt", pseudonym)
+	}
+	plain, err := p.aead.Open(nil, sealed[:n], sealed[n:], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("identity vault entry for %s does not decrypt: %w", pseudonym, err)
+	}
+	if err := json.Unmarshal(plain, &entry); err != nil {
+		return nil, err
+	}
+	return &Identity{Kind: entry.Kind, Value: entry.Value}, nil
+}
+
+// Forget deletes a pseudonym's vault entry, after which it can no longer be
+// re-identified.
+func (p *Pseudonymizer) Forget(ctx context.Context, orgID, pseudonym string) (bool, error) {
+	key := p.key(orgID, pseudonym)
+	p.mu.Lock()
+	_, pending := p.pending[key]
+	delete(p.pending, key)
+	delete(p.known, key)
+	p.mu.Unlock()
+
+	exists, err := p.vault.Exists(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		if err := p.vault.Delete(ctx, key); err != nil {
+			return false, err
+		}
+	}
+	return pending || exists, nil
+}
@@ -0,0 +1,96 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/prompts"
+)
+
+// PayloadDeleter deletes stored payloads by content hash.
+type PayloadDeleter interface {
+	Delete(ctx context.Context, orgID, hash string) error
+}
+
+// PayloadStore erases the payloads earlier stores found referenced only by
+// the subject's records. It must run after them.
+type PayloadStore struct {
+	payloads PayloadDeleter
+}
+
+// NewPayloadStore creates a store over the span payload store.
+func NewPayloadStore(payloads PayloadDeleter) *PayloadStore {
+	return &PayloadStore{payloads: payloads}
+}
+
+// Name implements Store.
+func (s *PayloadStore) Name() string { return "payloads" }
+
+// Erase implements Store.
+func (s *PayloadStore) Erase(ctx context.Context, plan *Plan) (StoreResult, error) {
+	var res StoreResult
+	if !plan.ErasePayloads() {
+		res.Notes = append(res.Notes, "payloads are not configured as personal data")
+		return res, nil
+	}
+	for _, h := range plan.Payloads() {
+		if err := s.payloads.Delete(ctx, plan.OrgID, h); err != nil {
+			return res, fmt.Errorf("deleting payload %s: %w", h, err)
+		}
+		res.Affected++
+	}
+	res.Notes = append(res.Notes, "payloads shared with other users' records are kept")
+	return res, nil
+}
+
+// PromptStore erases a subject from the in-memory prompt hash registry.
+type PromptStore struct {
+	registry *prompts.Registry
+}
+
+// NewPromptStore creates a store over the prompt hash registry.
+func NewPromptStore(registry *prompts.Registry) *PromptStore {
+	return &PromptStore{registry: registry}
+}
+
+// Name implements Store.
+func (s *PromptStore) Name() string { return "prompt_registry" }
+
+// Erase implements Store. Prompt usage counts are kept in both modes; only
+// the user ID is removed or replaced.
+func (s *PromptStore) Erase(ctx context.Context, plan *Plan) (StoreResult, error) {
+	replacement := ""
+	if action, _ := plan.Action(FieldUserID); plan.Mode == ModePseudonymize && action == ActionPseudonymize {
+		replacement = plan.Pseudonym
+	}
+	return StoreResult{Affected: int64(s.registry.ReplaceUser(plan.OrgID, plan.UserID, replacement))}, nil
+}
+
+// IdentityStore erases a subject's identity vault entry so their ingest
+// pseudonym can no longer be re-identified. Use it with
+// Eraser.Identities set.
+type IdentityStore struct {
+	identities *Pseudonymizer
+}
+
+// NewIdentityStore creates a store over the ingest identity vault.
+func NewIdentityStore(identities *Pseudonymizer) *IdentityStore {
+	return &IdentityStore{identities: identities}
+}
+
+// Name implements Store.
+func (s *IdentityStore) Name() string { return "identity_vault" }
+
+// Erase implements Store. The entry is removed in both modes.
+func (s *IdentityStore) Erase(ctx context.Context, plan *Plan) (StoreResult, error) {
+	var res StoreResult
+	found, err := s.identities.Forget(ctx, plan.OrgID, plan.UserID)
+	if err != nil {
+		return res, err
+	}
+	if found {
+		res.Affected = 1
+	}
+	res.Notes = append(res.Notes, "session mappings are kept; they do not identify the subject")
+	return res, nil
+}
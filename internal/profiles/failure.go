@@ -0,0 +1,62 @@
+package profiles
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailurePolicy decides whether a call is allowed when no policy decision
+// can be made. The most specific setting wins: the agent's risk level, then
+// its environment profile, then the route, then the global default.
+type FailurePolicy struct {
+	Default FailMode `json:"default"`
+	// Routes maps route names, such as "pre_invoke", to a fail mode.
+	Routes map[string]FailMode `json:"routes,omitempty"`
+	// RiskLevels maps agent risk levels to a fail mode. Set high and
+	// critical to closed so no environment profile can open them.
+	RiskLevels map[string]FailMode `json:"risk_levels,omitempty"`
+}
+
+// Validate checks every configured fail mode.
+func (fp *FailurePolicy) Validate() error {
+	if fp.Default != "" && !fp.Default.valid() {
+		return fmt.Errorf("default fail mode %q must be %q or %q", fp.Default, FailOpen, FailClosed)
+	}
+	for route, m := range fp.Routes {
+		if !m.valid() {
+			return fmt.Errorf("route %s: fail mode %q must be %q or %q", route, m, FailOpen, FailClosed)
+		}
+	}
+	for level, m := range fp.RiskLevels {
+		if !m.valid() {
+			return fmt.Errorf("risk level %s: fail mode %q must be %q or %q", level, m, FailOpen, FailClosed)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the fail mode for a call and which setting chose it, e.g.
+// "risk_level:high" or "profile:development". A nil policy fails closed.
+func (fp *FailurePolicy) Resolve(route string, p Profile, riskLevel string) (FailMode, string) {
+	if fp == nil {
+		if p.FailMode != "" {
+			return p.FailMode, "profile:" + p.Name
+		}
+		return FailClosed, "default"
+	}
+	if riskLevel != "" {
+		if m, ok := fp.RiskLevels[strings.ToLower(riskLevel)]; ok {
+			return m, "risk_level:" + strings.ToLower(riskLevel)
+		}
+	}
+	if p.FailMode != "" {
+		return p.FailMode, "profile:" + p.Name
+	}
+	if m, ok := fp.Routes[route]; ok {
+		return m, "route:" + route
+	}
+	if fp.Default != "" {
+		return fp.Default, "default"
+	}
+	return FailClosed, "default"
+}
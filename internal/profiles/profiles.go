@@ -0,0 +1,232 @@
+// Package profiles bundles guardrail strictness settings per deployment
+// environment. The profile applied to a policy decision is selected by the
+// agent's Environment field, so the same policies can fail open and only
+// warn in development while failing closed with human approval in production.
+//
+// The environment is reported by the SDK, so a compromised agent can claim a
+// laxer one. Deployments that do not trust agents to report it should keep
+// every profile fail-closed and enforcing.
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNotFound = errors.New("profile not found")
+	// ErrConflict is returned when a profile claims another profile's
+	// environment or the fallback profile would be deleted.
+	ErrConflict = errors.New("profile conflict")
+)
+
+// FailMode decides what happens when a policy decision cannot be made.
+type FailMode string
+
+const (
+	FailOpen   FailMode = "open"
+	FailClosed FailMode = "closed"
+)
+
+func (m FailMode) valid() bool { return m == FailOpen || m == FailClosed }
+
+// Enforcement decides whether deny decisions block the call.
+type Enforcement string
+
+const (
+	// Enforce blocks denied calls.
+	Enforce Enforcement = "enforce"
+	// Monitor allows denied calls and reports what would have been denied.
+	Monitor Enforcement = "monitor"
+)
+
+// Profile is the guardrail configuration for one environment.
+type Profile struct {
+	Name string `json:"name"`
+	// Environments are the agent Environment values that select the
+	// profile, matched case-insensitively.
+	Environments []string `json:"environments"`
+	// FailMode overrides the route and global failure defaults when set.
+	FailMode    FailMode    `json:"fail_mode,omitempty"`
+	Enforcement Enforcement `json:"enforcement"`
+	// TraceSampleRate is returned to SDKs as the fraction of traces to
+	// record, from 0 to 1.
+	TraceSampleRate float64 `json:"trace_sample_rate"`
+	// ApprovalCategories lists tool categories that need human approval
+	// before an allowed call proceeds. "*" requires approval for every tool.
+	ApprovalCategories []string `json:"approval_categories,omitempty"`
+	// RequireSandbox denies calls to code execution tools that do not
+	// declare a known sandbox profile.
+	RequireSandbox bool `json:"require_sandbox,omitempty"`
+}
+
+// Validate checks the profile's fields.
+func (p *Profile) Validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if p.FailMode != "" && !p.FailMode.valid() {
+		return fmt.Errorf("fail_mode must be %q or %q", FailOpen, FailClosed)
+	}
+	if p.Enforcement != Enforce && p.Enforcement != Monitor {
+		return fmt.Errorf("enforcement must be %q or %q", Enforce, Monitor)
+	}
+	if p.TraceSampleRate < 0 || p.TraceSampleRate > 1 {
+		return errors.New("trace_sample_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// RequiresApproval reports whether calls to a tool in category need human
+// approval under this profile.
+func (p Profile) RequiresApproval(category string) bool {
+	for _, c := range p.ApprovalCategories {
+		if c == "*" || strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// Defaults are used when no profiles are configured.
+var Defaults = []Profile{
+	{
+		Name:            "development",
+		Environments:    []string{"development", "dev", "local", "test"},
+		FailMode:        FailOpen,
+		Enforcement:     Monitor,
+		TraceSampleRate: 1,
+	},
+	{
+		Name:            "staging",
+		Environments:    []string{"staging", "stage", "qa"},
+		FailMode:        FailClosed,
+		Enforcement:     Enforce,
+		TraceSampleRate: 0.5,
+	},
+	{
+		Name:               "production",
+		Environments:       []string{"production", "prod"},
+		FailMode:           FailClosed,
+		Enforcement:        Enforce,
+		TraceSampleRate:    0.1,
+		ApprovalCategories: []string{"code_execution", "financial", "admin"},
+		RequireSandbox:     true,
+	},
+}
+
+// Registry holds profiles and resolves them by environment. It is safe for
+// concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+	fallback string
+}
+
+// NewRegistry creates a registry. fallback names the profile used for
+// agents whose environment matches no profile; it must be among profiles.
+func NewRegistry(profiles []Profile, fallback string) (*Registry, error) {
+	r := &Registry{profiles: make(map[string]Profile, len(profiles)), fallback: fallback}
+	for _, p := range profiles {
+		if err := r.Put(p); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := r.profiles[fallback]; !ok {
+		return nil, fmt.Errorf("fallback profile %q is not defined", fallback)
+	}
+	return r, nil
+}
+
+// Resolve returns the profile for an agent environment, or the fallback
+// profile when none matches.
+func (r *Registry) Resolve(environment string) Profile {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, name := range r.sortedNames() {
+		p := r.profiles[name]
+		for _, env := range p.Environments {
+			if strings.EqualFold(env, environment) {
+				return p
+			}
+		}
+	}
+	return r.profiles[r.fallback]
+}
+
+// Get returns a profile by name.
+func (r *Registry) Get(name string) (Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.profiles[name]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	return p, nil
+}
+
+// List returns every profile sorted by name.
+func (r *Registry) List() []Profile {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Profile, 0, len(r.profiles))
+	for _, name := range r.sortedNames() {
+		out = append(out, r.profiles[name])
+	}
+	return out
+}
+
+// Fallback returns the name of the profile used for unmatched environments.
+func (r *Registry) Fallback() string { return r.fallback }
+
+// Put creates or replaces a profile. An environment may select only one
+// profile.
+func (r *Registry) Put(p Profile) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("profile %s: %w", p.Name, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, other := range r.profiles {
+		if name == p.Name {
+			continue
+		}
+		for _, env := range p.Environments {
+			for _, taken := range other.Environments {
+				if strings.EqualFold(env, taken) {
+					return fmt.Errorf("%w: environment %q is already used by profile %s", ErrConflict, env, name)
+				}
+			}
+		}
+	}
+	p.Environments = append([]string(nil), p.Environments...)
+	p.ApprovalCategories = append([]string(nil), p.ApprovalCategories...)
+	r.profiles[p.Name] = p
+	return nil
+}
+
+// Delete removes a profile. The fallback profile cannot be deleted.
+func (r *Registry) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[name]; !ok {
+		return ErrNotFound
+	}
+	if name == r.fallback {
+		return fmt.Errorf("%w: %s is the fallback profile and cannot be deleted", ErrConflict, name)
+	}
+	delete(r.profiles, name)
+	return nil
+}
+
+func (r *Registry) sortedNames() []string {
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,85 @@
+package profiles_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/profiles"
+)
+
+func TestRegistry(t *testing.T) {
+	reg, err := profiles.NewRegistry(profiles.Defaults, "production")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	tests := []struct {
+		environment string
+		want        string
+	}{
+		{"dev", "development"},
+		{"Staging", "staging"},
+		{"prod", "production"},
+		{"", "production"},
+		{"unknown", "production"},
+	}
+	for _, tt := range tests {
+		if got := reg.Resolve(tt.environment).Name; got != tt.want {
+			t.Errorf("Resolve(%q) = %s, want %s", tt.environment, got, tt.want)
+		}
+	}
+
+	if !reg.Resolve("prod").RequiresApproval("Code_Execution") {
+		t.Error("production should require approval for code execution")
+	}
+
+	claim := profiles.Profile{Name: "sandbox", Environments: []string{"dev"}, FailMode: profiles.FailOpen, Enforcement: profiles.Monitor}
+	if err := reg.Put(claim); !errors.Is(err, profiles.ErrConflict) {
+		t.Errorf("Put with taken environment err = %v, want ErrConflict", err)
+	}
+	if err := reg.Delete("production"); !errors.Is(err, profiles.ErrConflict) {
+		t.Errorf("Delete fallback err = %v, want ErrConflict", err)
+	}
+	if err := reg.Put(profiles.Profile{Name: "bad", FailMode: "maybe", Enforcement: profiles.Enforce}); err == nil {
+		t.Error("Put accepted an invalid fail mode")
+	}
+	if _, err := profiles.NewRegistry(profiles.Defaults, "missing"); err == nil {
+		t.Error("NewRegistry accepted an undefined fallback")
+	}
+}
+
+func TestFailurePolicyResolve(t *testing.T) {
+	fp := &profiles.FailurePolicy{
+		Default:    profiles.FailClosed,
+		Routes:     map[string]profiles.FailMode{"pre_invoke": profiles.FailOpen},
+		RiskLevels: map[string]profiles.FailMode{"critical": profiles.FailClosed, "low": profiles.FailOpen},
+	}
+	dev := profiles.Profile{Name: "development", FailMode: profiles.FailOpen}
+	prod := profiles.Profile{Name: "production", FailMode: profiles.FailClosed}
+	inherit := profiles.Profile{Name: "staging"}
+
+	tests := []struct {
+		name       string
+		policy     *profiles.FailurePolicy
+		route      string
+		profile    profiles.Profile
+		risk       string
+		wantMode   profiles.FailMode
+		wantSource string
+	}{
+		{"risk level beats profile", fp, "pre_invoke", dev, "Critical", profiles.FailClosed, "risk_level:critical"},
+		{"low risk opens production", fp, "pre_invoke", prod, "low", profiles.FailOpen, "risk_level:low"},
+		{"profile beats route", fp, "pre_invoke", prod, "medium", profiles.FailClosed, "profile:production"},
+		{"route when profile inherits", fp, "pre_invoke", inherit, "", profiles.FailOpen, "route:pre_invoke"},
+		{"default for other routes", fp, "evaluate", inherit, "", profiles.FailClosed, "default"},
+		{"nil policy fails closed", nil, "pre_invoke", inherit, "low", profiles.FailClosed, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, source := tt.policy.Resolve(tt.route, tt.profile, tt.risk)
+			if mode != tt.wantMode || source != tt.wantSource {
+				t.Errorf("Resolve = %s (%s), want %s (%s)", mode, source, tt.wantMode, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+// Package prompts correlates LLM prompt hashes across users and agents. The
+// same prompt text submitted by many principals in a short window is a
+// hallmark of automated attack campaigns; the registry flags that reuse,
+// tracks which principals keep sending flagged prompts, and maintains a
+// blocklist that is published to OPA as policy data.
+package prompts
+
+import (
+	"container/list"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for unknown prompt hashes.
+var ErrNotFound = errors.New("prompt hash not found")
+
+// maxTrackedPrincipals bounds the distinct users/agents remembered per hash.
+const maxTrackedPrincipals = 256
+
+// Config configures a Registry.
+type Config struct {
+	// Window is how long a hash's statistics accumulate after it was last
+	// seen before they reset.
+	Window time.Duration
+	// MaxHashes bounds memory; least recently seen hashes are evicted.
+	MaxHashes int
+	// DistinctUsers flags a hash once this many users have sent it.
+	DistinctUsers int
+	// DistinctAgents flags a hash once this many agents have sent it.
+	DistinctAgents int
+	// Count flags a hash once it has been seen this many times.
+	Count int64
+}
+
+// Block records why a hash was blocked.
+type Block struct {
+	Reason    string    `json:"reason"`
+	BlockedBy string    `json:"blocked_by,omitempty"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+// Entry is the registry's view of one prompt hash within an organization.
+type Entry struct {
+	Hash       string    `json:"hash"`
+	OrgID      string    `json:"org_id"`
+	Count      int64     `json:"count"`
+	Users      []string  `json:"users"`
+	Agents     []string  `json:"agents"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Flagged    bool      `json:"flagged"`
+	FlagReason string    `json:"flag_reason,omitempty"`
+	Blocked    *Block    `json:"blocked,omitempty"`
+}
+
+// Offender is a user or agent that repeatedly sent flagged or blocked prompts.
+type Offender struct {
+	Kind     string    `json:"kind"` // user or agent
+	ID       string    `json:"id"`
+	OrgID    string    `json:"org_id"`
+	Hits     int64     `json:"hits"`
+	Hashes   []string  `json:"hashes"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Observation is one prompt use.
+type Observation struct {
+	OrgID   string
+	Hash    string
+	UserID  string
+	AgentID string
+	At      time.Time
+}
+
+// Finding reports a suspicious observation.
+type Finding struct {
+	Hash           string
+	Reason         string
+	Blocked        bool
+	Count          int64
+	DistinctUsers  int
+	DistinctAgents int
+}
+
+// Registry tracks prompt hash usage. It is safe for concurrent use.
+// State is held in memory and rebuilt from traffic after a restart; the
+// blocklist should be re-applied from configuration or the API.
+type Registry struct {
+	cfg Config
+
+	mu        sync.Mutex
+	entries   map[string]*entry // org/hash
+	order     *list.List        // of *entry, least recently observed first
+	offenders map[string]*Offender
+	blocks    map[string]Block // hash; blocks apply to every organization
+	onBlocks  []func(map[string]Block)
+}
+
+type entry struct {
+	Entry
+	key    string
+	elem   *list.Element
+	users  map[string]struct{}
+	agents map[string]struct{}
+}
+
+// NewRegistry creates a prompt hash registry.
+func NewRegistry(cfg Config) *Registry {
+	if cfg.Window <= 0 {
+		cfg.Window = 24 * time.Hour
+	}
+	if cfg.MaxHashes <= 0 {
+		cfg.MaxHashes = 100000
+	}
+	return &Registry{
+		cfg:       cfg,
+		entries:   make(map[string]*entry),
+		order:     list.New(),
+		offenders: make(map[string]*Offender),
+		blocks:    make(map[string]Block),
+	}
+}
+
+// Observe records a prompt use and returns a finding when the hash is
+// blocked, or when this use pushes it over a reuse threshold.
+func (r *Registry) Observe(o Observation) *Finding {
+	if o.Hash == "" {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := o.OrgID + "/" + o.Hash
+	e, ok := r.entries[key]
+	if !ok || o.At.Sub(e.LastSeen) > r.cfg.Window {
+		if ok {
+			r.order.Remove(e.elem)
+		} else if len(r.entries) >= r.cfg.MaxHashes {
+			r.evictOldest()
+		}
+		e = &entry{
+			Entry:  Entry{Hash: o.Hash, OrgID: o.OrgID, FirstSeen: o.At},
+			key:    key,
+			users:  make(map[string]struct{}),
+			agents: make(map[string]struct{}),
+		}
+		e.elem = r.order.PushBack(e)
+		r.entries[key] = e
+	} else {
+		r.order.MoveToBack(e.elem)
+	}
+
+	e.Count++
+	if o.At.After(e.LastSeen) {
+		e.LastSeen = o.At
+	}
+	if o.UserID != "" && len(e.users) < maxTrackedPrincipals {
+		e.users[o.UserID] = struct{}{}
+	}
+	if o.AgentID != "" && len(e.agents) < maxTrackedPrincipals {
+		e.agents[o.AgentID] = struct{}{}
+	}
+
+	var finding *Finding
+	if block, ok := r.blocks[o.Hash]; ok {
+		finding = &Finding{Hash: o.Hash, Reason: "blocked prompt hash: " + block.Reason, Blocked: true}
+	} else if !e.Flagged {
+		if reason := r.thresholdCrossed(e); reason != "" {
+			e.Flagged, e.FlagReason = true, reason
+			finding = &Finding{Hash: o.Hash, Reason: reason}
+		}
+	}
+
+	if e.Flagged || finding != nil {
+		r.recordOffense("user", o.OrgID, o.UserID, o.Hash, o.At)
+		r.recordOffense("agent", o.OrgID, o.AgentID, o.Hash, o.At)
+	}
+	if finding != nil {
+		finding.Count = e.Count
+		finding.DistinctUsers = len(e.users)
+		finding.DistinctAgents = len(e.agents)
+	}
+	return finding
+}
+
+// thresholdCrossed returns why e should be flagged, or "" if it should not.
+func (r *Registry) thresholdCrossed(e *entry) string {
+	switch {
+	case r.cfg.DistinctUsers > 0 && len(e.users) >= r.cfg.DistinctUsers:
+		return "identical prompt sent by many distinct users"
+	case r.cfg.DistinctAgents > 0 && len(e.agents) >= r.cfg.DistinctAgents:
+		return "identical prompt sent by many distinct agents"
+	case r.cfg.Count > 0 && e.Count >= r.cfg.Count:
+		return "identical prompt repeated at high volume"
+	}
+	return ""
+}
+
+func (r *Registry) recordOffense(kind, orgID, id, hash string, at time.Time) {
+	if id == "" {
+		return
+	}
+	key := kind + "/" + orgID + "/" + id
+	o, ok := r.offenders[key]
+	if !ok {
+		o = &Offender{Kind: kind, ID: id, OrgID: orgID}
+		r.offenders[key] = o
+	}
+	o.Hits++
+	if at.After(o.LastSeen) {
+		o.LastSeen = at
+	}
+	for _, h := range o.Hashes {
+		if h == hash {
+			return
+		}
+	}
+	if len(o.Hashes) < maxTrackedPrincipals {
+		o.Hashes = append(o.Hashes, hash)
+	}
+}
+
+// evictOldest removes the least recently observed entry. Callers must hold
+// r.mu.
+func (r *Registry) evictOldest() {
+	if el := r.order.Front(); el != nil {
+		delete(r.entries, el.Value.(*entry).key)
+		r.order.Remove(el)
+	}
+}
+
+func (e *entry) snapshot(blocks map[string]Block) Entry {
+	out := e.Entry
+	out.Users = sortedKeys(e.users)
+	out.Agents = sortedKeys(e.agents)
+	if b, ok := blocks[e.Hash]; ok {
+		out.Blocked = &b
+	}
+	return out
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the entry for a hash within an organization.
+func (r *Registry) Get(orgID, hash string) (Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[orgID+"/"+hash]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return e.snapshot(r.blocks), nil
+}
+
+// List returns an organization's tracked hashes, most used first. When
+// flaggedOnly is set, only flagged or blocked hashes are returned.
+func (r *Registry) List(orgID string, flaggedOnly bool) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Entry
+	for _, e := range r.entries {
+		if e.OrgID != orgID {
+			continue
+		}
+		_, blocked := r.blocks[e.Hash]
+		if flaggedOnly && !e.Flagged && !blocked {
+			continue
+		}
+		out = append(out, e.snapshot(r.blocks))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Hash < out[j].Hash
+	})
+	return out
+}
+
+// Offenders returns an organization's repeat offenders, most hits first.
+func (r *Registry) Offenders(orgID string, minHits int64) []Offender {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Offender
+	for _, o := range r.offenders {
+		if o.OrgID == orgID && o.Hits >= minHits {
+			cp := *o
+			cp.Hashes = append([]string(nil), o.Hashes...)
+			out = append(out, cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Hits != out[j].Hits {
+			return out[i].Hits > out[j].Hits
+		}
+		return out[i].Kind+out[i].ID < out[j].Kind+out[j].ID
+	})
+	return out
+}
+
+// Block adds a hash to the blocklist and publishes the updated list.
+func (r *Registry) Block(hash, reason, by string) Block {
+	r.mu.Lock()
+	b := Block{Reason: reason, BlockedBy: by, BlockedAt: time.Now().UTC()}
+	r.blocks[hash] = b
+	snapshot, listeners := r.blocklistLocked()
+	r.mu.Unlock()
+	publish(listeners, snapshot)
+	return b
+}
+
+// Unblock removes a hash from the blocklist. It returns ErrNotFound if the
+// hash was not blocked.
+func (r *Registry) Unblock(hash string) error {
+	r.mu.Lock()
+	if _, ok := r.blocks[hash]; !ok {
+		r.mu.Unlock()
+		return ErrNotFound
+	}
+	delete(r.blocks, hash)
+	snapshot, listeners := r.blocklistLocked()
+	r.mu.Unlock()
+	publish(listeners, snapshot)
+	return nil
+}
+
+// Blocklist returns the blocked hashes.
+func (r *Registry) Blocklist() map[string]Block {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot, _ := r.blocklistLocked()
+	return snapshot
+}
+
+// OnBlocklistChange registers fn to receive the full blocklist after every
+// change, for example to publish it as OPA data. fn is called immediately
+// with the current list.
+func (r *Registry) OnBlocklistChange(fn func(map[string]Block)) {
+	r.mu.Lock()
+	r.onBlocks = append(r.onBlocks, fn)
+	snapshot, _ := r.blocklistLocked()
+	r.mu.Unlock()
+	fn(snapshot)
+}
+
+func (r *Registry) blocklistLocked() (map[string]Block, []func(map[string]Block)) {
+	snapshot := make(map[string]Block, len(r.blocks))
+	for k, v := range r.blocks {
+		snapshot[k] = v
+	}
+	return snapshot, append([]func(map[string]Block){}, r.onBlocks...)
+}
+
+func publish(listeners []func(map[string]Block), snapshot map[string]Block) {
+	for _, fn := range listeners {
+		fn(snapshot)
+	}
+}
+
+// ReplaceUser replaces a user ID in an organization's entries and offender
+// records, e.g. with a pseudonym, and returns how many records changed. An
+// empty replacement removes the user; distinct-user counts already used
+// for flagging are not recomputed.
+func (r *Registry) ReplaceUser(orgID, userID, replacement string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := 0
+	for _, e := range r.entries {
+		if e.OrgID != orgID {
+			continue
+		}
+		if _, ok := e.users[userID]; ok {
+			delete(e.users, userID)
+			if replacement != "" {
+				e.users[replacement] = struct{}{}
+			}
+			changed++
+		}
+	}
+	key := "user/" + orgID + "/" + userID
+	if o, ok := r.offenders[key]; ok {
+		delete(r.offenders, key)
+		if replacement != "" {
+			o.ID = replacement
+			r.offenders["user/"+orgID+"/"+replacement] = o
+		}
+		changed++
+	}
+	return changed
+}
@@ -0,0 +1,89 @@
+package prompts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/prompts"
+)
+
+func TestRegistry(t *testing.T) {
+	now := time.Now()
+	reg := prompts.NewRegistry(prompts.Config{Window: time.Hour, DistinctUsers: 3})
+
+	var published map[string]prompts.Block
+	reg.OnBlocklistChange(func(b map[string]prompts.Block) { published = b })
+
+	observe := func(hash, user string) *prompts.Finding {
+		return reg.Observe(prompts.Observation{OrgID: "org", Hash: hash, UserID: user, AgentID: "agent-1", At: now})
+	}
+
+	t.Run("flags reuse across users once", func(t *testing.T) {
+		for _, user := range []string{"u1", "u2"} {
+			if f := observe("h1", user); f != nil {
+				t.Fatalf("unexpected finding for %s: %+v", user, f)
+			}
+		}
+		f := observe("h1", "u3")
+		if f == nil || f.Blocked || f.DistinctUsers != 3 {
+			t.Fatalf("finding = %+v, want reuse finding with 3 users", f)
+		}
+		if f := observe("h1", "u4"); f != nil {
+			t.Errorf("hash flagged twice: %+v", f)
+		}
+		if got := reg.List("org", true); len(got) != 1 || got[0].Hash != "h1" {
+			t.Errorf("flagged list = %+v", got)
+		}
+	})
+
+	t.Run("repeat offenders", func(t *testing.T) {
+		observe("h1", "u4")
+		offenders := reg.Offenders("org", 2)
+		if len(offenders) == 0 || offenders[0].Kind != "agent" || offenders[0].ID != "agent-1" {
+			t.Fatalf("offenders = %+v, want agent-1 first", offenders)
+		}
+	})
+
+	t.Run("blocked hash always produces a finding", func(t *testing.T) {
+		reg.Block("h2", "campaign", "test")
+		if _, ok := published["h2"]; !ok {
+			t.Error("blocklist change not published")
+		}
+		for i := 0; i < 2; i++ {
+			if f := observe("h2", "u1"); f == nil || !f.Blocked {
+				t.Fatalf("observation %d: finding = %+v, want blocked", i, f)
+			}
+		}
+		if err := reg.Unblock("h2"); err != nil {
+			t.Fatalf("unblock: %v", err)
+		}
+		if len(published) != 0 {
+			t.Errorf("published blocklist = %v after unblock", published)
+		}
+		if err := reg.Unblock("h2"); err != prompts.ErrNotFound {
+			t.Errorf("second unblock = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("organizations are isolated", func(t *testing.T) {
+		if _, err := reg.Get("other", "h1"); err != prompts.ErrNotFound {
+			t.Errorf("Get(other, h1) = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestRegistryEvictsLeastRecentlyObserved(t *testing.T) {
+	now := time.Now()
+	reg := prompts.NewRegistry(prompts.Config{Window: time.Hour, MaxHashes: 2})
+	for i, hash := range []string{"h1", "h2", "h1", "h3"} {
+		reg.Observe(prompts.Observation{OrgID: "org", Hash: hash, UserID: "u1", At: now.Add(time.Duration(i) * time.Second)})
+	}
+	if _, err := reg.Get("org", "h2"); err != prompts.ErrNotFound {
+		t.Errorf("Get(h2) err = %v, want the least recently observed hash evicted", err)
+	}
+	for _, hash := range []string{"h1", "h3"} {
+		if _, err := reg.Get("org", hash); err != nil {
+			t.Errorf("Get(%s): %v", hash, err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package clickhouse implements time-series storage for AgentGuard traces on
+// ClickHouse. It talks to the ClickHouse HTTP interface so no native driver is
+// required, and binds values with server-side query parameters.
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Config holds ClickHouse connection configuration.
+type Config struct {
+	Host     string
+	HTTPPort int
+	Database string
+	User     string
+	Password string
+	Secure   bool          // use HTTPS
+	Timeout  time.Duration // per-request timeout; defaults to 30s
+}
+
+// DB is a ClickHouse HTTP client bound to one database.
+type DB struct {
+	endpoint string
+	database string
+	user     string
+	password string
+	client   *http.Client
+}
+
+// New creates a ClickHouse client and verifies connectivity.
+func New(ctx context.Context, cfg Config) (*DB, error) {
+	if cfg.HTTPPort == 0 {
+		cfg.HTTPPort = 8123
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	scheme := "http"
+	if cfg.Secure {
+		scheme = "https"
+	}
+
+	db := &DB{
+		endpoint: fmt.Sprintf("%s://%s:%d/", scheme, cfg.Host, cfg.HTTPPort),
+		database: cfg.Database,
+		user:     cfg.User,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}
+
+	if err := db.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("pinging clickhouse: %w", err)
+	}
+
+	log.Info().
+		Str("host", cfg.Host).
+		Int("port", cfg.HTTPPort).
+		Str("database", cfg.Database).
+		Msg("ClickHouse connection established")
+
+	return db, nil
+}
+
+// Ping checks that the server answers queries.
+func (db *DB) Ping(ctx context.Context) error {
+	return db.exec(ctx, "SELECT 1", nil)
+}
+
+// exec runs a statement that returns no rows.
+func (db *DB) exec(ctx context.Context, sql string, params map[string]string) error {
+	body, err := db.do(ctx, sql, params, nil, nil)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// mutate runs an ALTER TABLE ... UPDATE or DELETE mutation and waits for it
+// to finish on every replica, so the change is visible when it returns.
+func (db *DB) mutate(ctx context.Context, sql string, params map[string]string) error {
+	body, err := db.do(ctx, sql, params, map[string]string{"mutations_sync": "2"}, nil)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// query runs a SELECT and decodes its FORMAT JSON rows into dest, which must
+// be a pointer to a slice.
+func (db *DB) query(ctx context.Context, sql string, params map[string]string, dest any) error {
+	body, err := db.do(ctx, sql+" FORMAT JSON", params, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var result struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(body).Decode(&result); err != nil {
+		return fmt.Errorf("decoding clickhouse response: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(result.Data, dest)
+}
+
+// insert writes rows to table using JSONEachRow.
+func (db *DB) insert(ctx context.Context, table string, rows []any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("encoding %s row: %w", table, err)
+		}
+	}
+	body, err := db.do(ctx, "INSERT INTO "+table+" FORMAT JSONEachRow", nil, nil, &buf)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// do sends a statement. Without a payload the statement is the request body;
+// with one, the statement moves to the query string and the payload is sent
+// as the body. Parameters bind to {name:Type} placeholders; settings are
+// passed as query settings.
+func (db *DB) do(ctx context.Context, sql string, params, settings map[string]string, payload io.Reader) (io.ReadCloser, error) {
+	q := url.Values{}
+	q.Set("database", db.database)
+	q.Set("output_format_json_quote_64bit_integers", "0")
+	for name, value := range settings {
+		q.Set(name, value)
+	}
+	for name, value := range params {
+		q.Set("param_"+name, value)
+	}
+
+	body := payload
+	if body == nil {
+		body = strings.NewReader(sql)
+	} else {
+		q.Set("query", sql)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, db.endpoint+"?"+q.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("building clickhouse request: %w", err)
+	}
+	if db.user != "" {
+		req.Header.Set("X-ClickHouse-User", db.user)
+		req.Header.Set("X-ClickHouse-Key", db.password)
+	}
+
+	resp, err := db.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("clickhouse returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
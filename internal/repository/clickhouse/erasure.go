@@ -0,0 +1,167 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/privacy"
+)
+
+// subjectFilter selects a data subject's spans.
+const subjectFilter = "org_id = {org:String} AND user_id = {user:String}"
+
+// ErasureStore erases data subjects from the spans and security_signals
+// tables. It implements privacy.Store.
+type ErasureStore struct {
+	db *DB
+}
+
+// NewErasureStore creates an erasure store.
+func NewErasureStore(db *DB) *ErasureStore {
+	return &ErasureStore{db: db}
+}
+
+// Name implements privacy.Store.
+func (s *ErasureStore) Name() string { return "clickhouse" }
+
+// Erase implements privacy.Store. It records the payload hashes only the
+// subject's spans reference before rewriting them, and waits for its
+// mutations to complete.
+func (s *ErasureStore) Erase(ctx context.Context, plan *privacy.Plan) (privacy.StoreResult, error) {
+	var res privacy.StoreResult
+	params := map[string]string{"org": plan.OrgID, "user": plan.UserID}
+
+	spans, err := s.count(ctx, "SELECT count() AS n FROM spans WHERE "+subjectFilter, params)
+	if err != nil {
+		return res, fmt.Errorf("counting spans: %w", err)
+	}
+	if spans == 0 {
+		res.Notes = append(res.Notes, "no spans recorded for the subject")
+		return res, nil
+	}
+
+	if plan.ErasePayloads() {
+		var rows []struct {
+			Hash string `json:"h"`
+		}
+		if err := s.db.query(ctx, `SELECT DISTINCT h FROM (
+			SELECT arrayJoin([JSONExtractString(input_ref, 'hash'), JSONExtractString(output_ref, 'hash')]) AS h
+			FROM spans WHERE `+subjectFilter+`)
+			WHERE h != '' AND h NOT IN (
+				SELECT arrayJoin([JSONExtractString(input_ref, 'hash'), JSONExtractString(output_ref, 'hash')]) FROM spans
+				WHERE org_id = {org:String} AND user_id != {user:String})`, params, &rows); err != nil {
+			return res, fmt.Errorf("querying payload hashes: %w", err)
+		}
+		for _, r := range rows {
+			plan.AddPayloads(r.Hash)
+		}
+	}
+
+	if plan.Mode == privacy.ModeDelete {
+		return s.delete(ctx, params, spans)
+	}
+	return s.pseudonymize(ctx, plan, params, spans)
+}
+
+func (s *ErasureStore) delete(ctx context.Context, params map[string]string, spans int64) (privacy.StoreResult, error) {
+	res := privacy.StoreResult{Affected: spans}
+	traces := "org_id = {org:String} AND trace_id IN (SELECT trace_id FROM spans WHERE " + subjectFilter + ")"
+	signals, err := s.count(ctx, "SELECT count() AS n FROM security_signals WHERE "+traces, params)
+	if err != nil {
+		return res, fmt.Errorf("counting security signals: %w", err)
+	}
+	// Signals first: they are found through the spans being deleted.
+	if err := s.db.mutate(ctx, "ALTER TABLE security_signals DELETE WHERE "+traces, params); err != nil {
+		return res, fmt.Errorf("deleting security signals: %w", err)
+	}
+	if err := s.db.mutate(ctx, "ALTER TABLE spans DELETE WHERE "+subjectFilter, params); err != nil {
+		return res, fmt.Errorf("deleting spans: %w", err)
+	}
+	res.Affected += signals
+	res.Notes = append(res.Notes,
+		fmt.Sprintf("deleted %d spans and %d security signals", spans, signals),
+		"metric rollups and policy violations hold no user identifiers and are kept")
+	return res, nil
+}
+
+func (s *ErasureStore) pseudonymize(ctx context.Context, plan *privacy.Plan, params map[string]string, spans int64) (privacy.StoreResult, error) {
+	res := privacy.StoreResult{Affected: spans}
+	var set []string
+
+	switch action, _ := plan.Action(privacy.FieldUserID); action {
+	case privacy.ActionRedact:
+		set = append(set, "user_id = ''")
+	default:
+		set = append(set, "user_id = {pseudonym:String}")
+		params["pseudonym"] = plan.Pseudonym
+	}
+
+	switch action, ok := plan.Action(privacy.FieldSessionID); {
+	case !ok:
+	case action == privacy.ActionRedact:
+		set = append(set, "session_id = ''")
+	default:
+		var rows []struct {
+			SessionID string `json:"session_id"`
+		}
+		if err := s.db.query(ctx, "SELECT DISTINCT session_id FROM spans WHERE "+subjectFilter+" AND session_id != ''", params, &rows); err != nil {
+			return res, fmt.Errorf("querying sessions: %w", err)
+		}
+		if len(rows) > 0 {
+			from := make([]string, len(rows))
+			to := make([]string, len(rows))
+			for i, r := range rows {
+				from[i], to[i] = r.SessionID, plan.Pseudonymize(r.SessionID)
+			}
+			set = append(set, "session_id = transform(session_id, {sessions:Array(String)}, {pseudonyms:Array(String)}, '')")
+			params["sessions"], params["pseudonyms"] = arrayParam(from), arrayParam(to)
+		}
+	}
+
+	if keys := plan.Attributes(); len(keys) > 0 {
+		// Rebuild the attributes object without the personal data keys.
+		set = append(set, `attributes = if(attributes = '', attributes, concat('{', arrayStringConcat(
+			arrayMap(kv -> concat(toJSONString(kv.1), ':', kv.2),
+				arrayFilter(kv -> NOT has({attributes:Array(String)}, kv.1), JSONExtractKeysAndValuesRaw(attributes))),
+			','), '}'))`)
+		params["attributes"] = arrayParam(keys)
+	}
+
+	if _, ok := plan.Action(privacy.FieldPayloads); ok {
+		set = append(set, "input_ref = ''", "output_ref = ''")
+		res.Notes = append(res.Notes, "payload references cleared; payload hashes are kept")
+	}
+
+	if err := s.db.mutate(ctx, "ALTER TABLE spans UPDATE "+strings.Join(set, ", ")+" WHERE "+subjectFilter, params); err != nil {
+		return res, fmt.Errorf("pseudonymizing spans: %w", err)
+	}
+	res.Notes = append(res.Notes,
+		fmt.Sprintf("pseudonymized %d spans", spans),
+		"security signals are linked by trace ID, not user ID, and are kept")
+	return res, nil
+}
+
+// count runs a SELECT count() AS n query.
+func (s *ErasureStore) count(ctx context.Context, sql string, params map[string]string) (int64, error) {
+	var rows []struct {
+		N int64 `json:"n"`
+	}
+	if err := s.db.query(ctx, sql, params, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].N, nil
+}
+
+// arrayParam formats values as an Array(String) query parameter.
+func arrayParam(values []string) string {
+	quoted := make([]string, len(values))
+	escape := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	for i, v := range values {
+		quoted[i] = "'" + escape.Replace(v) + "'"
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
@@ -0,0 +1,400 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// rollup describes how a metric is read from its rollup table.
+type rollup struct {
+	table      string
+	timeColumn string
+	value      string
+	// hourly reports whether the table has hour granularity.
+	hourly bool
+	// dimensions maps group_by names to columns.
+	dimensions map[string]string
+}
+
+var rollups = map[string]rollup{
+	repository.MetricTokens:           tokenRollup("total_tokens"),
+	repository.MetricPromptTokens:     tokenRollup("prompt_tokens"),
+	repository.MetricCompletionTokens: tokenRollup("completion_tokens"),
+	repository.MetricLLMCalls:         tokenRollup("calls"),
+	repository.MetricDenials: {
+		table:      "policy_denials_daily",
+		timeColumn: "day",
+		value:      "denials",
+		dimensions: map[string]string{"agent": "agent_id", "policy": "policy_id", "tool": "tool_name"},
+	},
+	repository.MetricRuleDenials: {
+		table:      "rule_denials_daily",
+		timeColumn: "day",
+		value:      "denials",
+		dimensions: map[string]string{"agent": "agent_id", "policy": "policy", "rule": "rule"},
+	},
+	repository.MetricSignals: {
+		table:      "signals_daily",
+		timeColumn: "day",
+		value:      "signals",
+		dimensions: map[string]string{"agent": "agent_id", "severity": "severity", "type": "type"},
+	},
+}
+
+func tokenRollup(value string) rollup {
+	return rollup{
+		table:      "llm_tokens_hourly",
+		timeColumn: "hour",
+		value:      value,
+		hourly:     true,
+		dimensions: map[string]string{"agent": "agent_id", "model": "model", "provider": "provider"},
+	}
+}
+
+// MetricsRepository reads metric rollups and writes the raw rows they are
+// maintained from.
+type MetricsRepository struct {
+	db *DB
+}
+
+// NewMetricsRepository creates a new ClickHouse metrics repository.
+func NewMetricsRepository(db *DB) *MetricsRepository {
+	return &MetricsRepository{db: db}
+}
+
+// Rollup returns a metric time series bucketed by q.Interval and split by
+// q.GroupBy. Only whitelisted dimensions are interpolated into SQL; all
+// values are bound as query parameters.
+func (r *MetricsRepository) Rollup(ctx context.Context, q *repository.MetricsQuery) ([]repository.MetricPoint, error) {
+	ru, ok := rollups[q.Metric]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown metric %q", repository.ErrInvalidQuery, q.Metric)
+	}
+
+	var bucket string
+	switch q.Interval {
+	case "hour":
+		if !ru.hourly {
+			return nil, fmt.Errorf("%w: metric %q has daily granularity", repository.ErrInvalidQuery, q.Metric)
+		}
+		bucket = "toStartOfHour(" + ru.timeColumn + ")"
+	case "", "day":
+		bucket = "toStartOfDay(" + ru.timeColumn + ")"
+	case "week":
+		bucket = "toStartOfWeek(" + ru.timeColumn + ", 1)"
+	default:
+		return nil, fmt.Errorf("%w: unknown interval %q", repository.ErrInvalidQuery, q.Interval)
+	}
+
+	selects := []string{"toUnixTimestamp(" + bucket + ") AS bucket"}
+	groups := []string{"bucket"}
+	for _, dim := range q.GroupBy {
+		col, ok := ru.dimensions[dim]
+		if !ok {
+			return nil, fmt.Errorf("%w: metric %q cannot be grouped by %q", repository.ErrInvalidQuery, q.Metric, dim)
+		}
+		selects = append(selects, col+" AS "+dim)
+		groups = append(groups, dim)
+	}
+	selects = append(selects, "toFloat64(sum("+ru.value+")) AS value")
+
+	params := map[string]string{
+		"org":  q.OrgID,
+		"from": strconv.FormatInt(q.From.Unix(), 10),
+		"to":   strconv.FormatInt(q.To.Unix(), 10),
+	}
+	// Widen the lower bound to the start of the containing rollup bucket.
+	lower := "toDate(toDateTime({from:Int64}, 'UTC'))"
+	if ru.hourly {
+		lower = "toStartOfHour(toDateTime({from:Int64}, 'UTC'))"
+	}
+	where := []string{
+		"org_id = {org:String}",
+		ru.timeColumn + " >= " + lower,
+		ru.timeColumn + " < toDateTime({to:Int64}, 'UTC')",
+	}
+	if q.AgentID != nil {
+		where = append(where, "agent_id = {agent:String}")
+		params["agent"] = *q.AgentID
+	}
+
+	query := "SELECT " + strings.Join(selects, ", ") +
+		" FROM " + ru.table +
+		" WHERE " + strings.Join(where, " AND ") +
+		" GROUP BY " + strings.Join(groups, ", ") +
+		" ORDER BY " + strings.Join(groups, ", ")
+
+	var rows []map[string]any
+	if err := r.db.query(ctx, query, params, &rows); err != nil {
+		return nil, fmt.Errorf("querying %s rollup: %w", q.Metric, err)
+	}
+
+	points := make([]repository.MetricPoint, 0, len(rows))
+	for _, row := range rows {
+		ts, _ := row["bucket"].(float64)
+		value, _ := row["value"].(float64)
+		p := repository.MetricPoint{Bucket: time.Unix(int64(ts), 0).UTC(), Value: value}
+		if len(q.GroupBy) > 0 {
+			p.Group = make(map[string]string, len(q.GroupBy))
+			for _, dim := range q.GroupBy {
+				p.Group[dim], _ = row[dim].(string)
+			}
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// spanRow is a spans table row.
+type spanRow struct {
+	OrgID            string `json:"org_id"`
+	TraceID          string `json:"trace_id"`
+	SpanID           string `json:"span_id"`
+	ParentSpanID     string `json:"parent_span_id"`
+	AgentID          string `json:"agent_id"`
+	SessionID        string `json:"session_id"`
+	UserID           string `json:"user_id"`
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	StartTime        int64  `json:"start_time"` // milliseconds, parsed by DateTime64(3)
+	DurationMs       int64  `json:"duration_ms"`
+	Status           string `json:"status"`
+	LLMModel         string `json:"llm_model"`
+	LLMProvider      string `json:"llm_provider"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+	PromptHash       string `json:"prompt_hash"`
+	ToolName         string `json:"tool_name"`
+	ToolCategory     string `json:"tool_category"`
+	ExternalCall     uint8  `json:"external_call"`
+	PolicyID         string `json:"policy_id"`
+	PolicyDecision   string `json:"policy_decision"`
+	PolicyReason     string `json:"policy_reason"`
+	Attributes       string `json:"attributes"`
+	InputHash        string `json:"input_hash"`
+	OutputHash       string `json:"output_hash"`
+	InputRef         string `json:"input_ref"`  // JSON-encoded models.PayloadRef
+	OutputRef        string `json:"output_ref"` // JSON-encoded models.PayloadRef
+	ParentTraceID    string `json:"parent_trace_id"`
+	ParentLinkSpanID string `json:"parent_link_span_id"`
+}
+
+// signalRow is a security_signals table row.
+type signalRow struct {
+	OrgID       string `json:"org_id"`
+	ID          string `json:"id"`
+	TraceID     string `json:"trace_id"`
+	SpanID      string `json:"span_id"`
+	AgentID     string `json:"agent_id"`
+	Type        string `json:"type"`
+	Severity    string `json:"severity"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Evidence    string `json:"evidence"`
+	Timestamp   int64  `json:"timestamp"`
+	Mitigated   uint8  `json:"mitigated"`
+}
+
+// InsertTrace writes a trace's spans and security signals. The rollup
+// materialized views are updated by ClickHouse as part of the insert.
+func (r *MetricsRepository) InsertTrace(ctx context.Context, orgID string, t *models.AgentTrace) error {
+	return r.InsertTraces(ctx, []repository.OrgTrace{{OrgID: orgID, Trace: t}})
+}
+
+// InsertTraces writes the spans of a batch of traces in one insert and
+// their security signals in another. It implements
+// repository.TraceBatchWriter; reads keep one row per span and signal ID,
+// so a retried batch is not duplicated.
+func (r *MetricsRepository) InsertTraces(ctx context.Context, batch []repository.OrgTrace) error {
+	var spans, signals []any
+	for _, ot := range batch {
+		rows, err := spanRows(ot.OrgID, ot.Trace)
+		if err != nil {
+			return err
+		}
+		spans = append(spans, rows...)
+
+		sigs := make([]models.SecuritySignal, len(ot.Trace.SecuritySignals))
+		for i, sig := range ot.Trace.SecuritySignals {
+			sig.TraceID = ot.Trace.TraceID
+			sigs[i] = sig
+		}
+		rows, err = signalRows(ot.OrgID, ot.Trace.AgentID.String(), sigs)
+		if err != nil {
+			return err
+		}
+		signals = append(signals, rows...)
+	}
+	if err := r.db.insert(ctx, "spans", spans); err != nil {
+		return fmt.Errorf("inserting spans: %w", err)
+	}
+	if err := r.db.insert(ctx, "security_signals", signals); err != nil {
+		return fmt.Errorf("inserting security signals: %w", err)
+	}
+	return nil
+}
+
+// spanRows converts a trace's spans to spans table rows.
+func spanRows(orgID string, t *models.AgentTrace) ([]any, error) {
+	agentID := t.AgentID.String()
+	spans := make([]any, 0, len(t.Spans))
+	for _, s := range t.Spans {
+		row := spanRow{
+			OrgID:      orgID,
+			TraceID:    t.TraceID,
+			SpanID:     s.SpanID,
+			AgentID:    agentID,
+			SessionID:  t.SessionID,
+			UserID:     t.UserID,
+			Name:       s.Name,
+			Type:       string(s.Type),
+			StartTime:  s.StartTime.UnixMilli(),
+			DurationMs: s.DurationMs,
+			Status:     s.Status,
+		}
+		if s.ParentSpanID != nil {
+			row.ParentSpanID = *s.ParentSpanID
+		}
+		if t.Parent != nil {
+			row.ParentTraceID = t.Parent.TraceID
+			row.ParentLinkSpanID = t.Parent.SpanID
+		}
+		if llm := s.Data.LLM; llm != nil {
+			row.LLMModel = llm.Model
+			row.LLMProvider = llm.Provider
+			row.PromptTokens = llm.PromptTokens
+			row.CompletionTokens = llm.CompletionTokens
+			row.TotalTokens = llm.TotalTokens
+			row.PromptHash = llm.PromptHash
+		}
+		if tool := s.Data.Tool; tool != nil {
+			row.ToolName = tool.ToolName
+			row.ToolCategory = tool.ToolCategory
+			if tool.ExternalCall {
+				row.ExternalCall = 1
+			}
+			if pd := tool.PolicyDecision; pd != nil {
+				row.PolicyID = pd.PolicyID
+				row.PolicyDecision = pd.Decision
+				row.PolicyReason = pd.Reason
+			}
+			row.InputHash = tool.InputHash
+			row.OutputHash = tool.OutputHash
+			if tool.InputRef != nil {
+				ref, err := json.Marshal(tool.InputRef)
+				if err != nil {
+					return nil, fmt.Errorf("encoding input ref for span %s: %w", s.SpanID, err)
+				}
+				row.InputRef = string(ref)
+			}
+			if tool.OutputRef != nil {
+				ref, err := json.Marshal(tool.OutputRef)
+				if err != nil {
+					return nil, fmt.Errorf("encoding output ref for span %s: %w", s.SpanID, err)
+				}
+				row.OutputRef = string(ref)
+			}
+		}
+		if len(s.Attributes) > 0 {
+			attrs, err := json.Marshal(s.Attributes)
+			if err != nil {
+				return nil, fmt.Errorf("encoding attributes for span %s: %w", s.SpanID, err)
+			}
+			row.Attributes = string(attrs)
+		}
+		spans = append(spans, row)
+	}
+	return spans, nil
+}
+
+// InsertSignals writes security signals that are not attached to an ingested
+// trace. It implements repository.SignalWriter.
+func (r *MetricsRepository) InsertSignals(ctx context.Context, orgID, agentID string, sigs []models.SecuritySignal) error {
+	signals, err := signalRows(orgID, agentID, sigs)
+	if err != nil {
+		return err
+	}
+	if err := r.db.insert(ctx, "security_signals", signals); err != nil {
+		return fmt.Errorf("inserting security signals: %w", err)
+	}
+	return nil
+}
+
+// signalRows converts security signals to security_signals table rows.
+func signalRows(orgID, agentID string, sigs []models.SecuritySignal) ([]any, error) {
+	signals := make([]any, 0, len(sigs))
+	for _, sig := range sigs {
+		row := signalRow{
+			OrgID:       orgID,
+			ID:          sig.ID,
+			TraceID:     sig.TraceID,
+			SpanID:      sig.SpanID,
+			AgentID:     agentID,
+			Type:        string(sig.Type),
+			Severity:    sig.Severity,
+			Title:       sig.Title,
+			Description: sig.Description,
+			Timestamp:   sig.Timestamp.UnixMilli(),
+		}
+		if sig.Mitigated {
+			row.Mitigated = 1
+		}
+		if len(sig.Evidence) > 0 {
+			evidence, err := json.Marshal(sig.Evidence)
+			if err != nil {
+				return nil, fmt.Errorf("encoding evidence for signal %s: %w", sig.ID, err)
+			}
+			row.Evidence = string(evidence)
+		}
+		signals = append(signals, row)
+	}
+	return signals, nil
+}
+
+// violationRow is a policy_violations table row.
+type violationRow struct {
+	OrgID     string `json:"org_id"`
+	Timestamp int64  `json:"timestamp"`
+	AgentID   string `json:"agent_id"`
+	ToolName  string `json:"tool_name"`
+	Policy    string `json:"policy"`
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Enforced  uint8  `json:"enforced"`
+}
+
+// InsertViolations writes the rules violated by denied policy decisions. It
+// implements repository.ViolationWriter.
+func (r *MetricsRepository) InsertViolations(ctx context.Context, orgID string, violations []repository.PolicyViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	rows := make([]any, 0, len(violations))
+	for _, v := range violations {
+		row := violationRow{
+			OrgID:     orgID,
+			Timestamp: v.Timestamp.UnixMilli(),
+			AgentID:   v.AgentID,
+			ToolName:  v.Tool,
+			Policy:    v.Policy,
+			Rule:      v.Rule,
+			Severity:  v.Severity,
+		}
+		if v.Enforced {
+			row.Enforced = 1
+		}
+		rows = append(rows, row)
+	}
+	if err := r.db.insert(ctx, "policy_violations", rows); err != nil {
+		return fmt.Errorf("inserting policy violations: %w", err)
+	}
+	return nil
+}
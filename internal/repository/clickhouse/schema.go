@@ -0,0 +1,249 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+// schema creates the raw event tables and the rollups maintained from them.
+// Materialized views fire on every insert into spans/security_signals, so the
+// rollups stay current without a separate aggregation job. Rollup tables use
+// SummingMergeTree, which folds rows with equal keys during merges; queries
+// still sum() because merges are eventual.
+var schema = []struct {
+	name string
+	sql  string
+}{
+	{
+		name: "spans",
+		sql: `
+			CREATE TABLE IF NOT EXISTS spans (
+				org_id            LowCardinality(String),
+				trace_id          String,
+				span_id           String,
+				parent_span_id    String,
+				agent_id          String,
+				session_id        String,
+				user_id           String,
+				name              String,
+				type              LowCardinality(String),
+				start_time        DateTime64(3, 'UTC'),
+				duration_ms       Int64,
+				status            LowCardinality(String),
+				llm_model         LowCardinality(String),
+				llm_provider      LowCardinality(String),
+				prompt_tokens     UInt64,
+				completion_tokens UInt64,
+				total_tokens      UInt64,
+				prompt_hash       String,
+				tool_name         LowCardinality(String),
+				tool_category     LowCardinality(String),
+				external_call     UInt8,
+				policy_id         String,
+				policy_decision   LowCardinality(String),
+				policy_reason     String,
+				attributes        String,
+				ingested_at       DateTime64(3, 'UTC') DEFAULT now64(3)
+			)
+			ENGINE = MergeTree
+			PARTITION BY toYYYYMM(start_time)
+			ORDER BY (org_id, agent_id, start_time, trace_id, span_id)`,
+	},
+	{
+		// Tool payload hashes and blob references, added after the spans
+		// table shipped; ADD COLUMN IF NOT EXISTS keeps this idempotent.
+		name: "spans payload columns",
+		sql: `
+			ALTER TABLE spans
+				ADD COLUMN IF NOT EXISTS input_hash  String,
+				ADD COLUMN IF NOT EXISTS output_hash String,
+				ADD COLUMN IF NOT EXISTS input_ref   String,
+				ADD COLUMN IF NOT EXISTS output_ref  String`,
+	},
+	{
+		// The supervisor span a worker agent's trace was delegated from,
+		// repeated on each of the trace's spans.
+		name: "spans delegation columns",
+		sql: `
+			ALTER TABLE spans
+				ADD COLUMN IF NOT EXISTS parent_trace_id     String,
+				ADD COLUMN IF NOT EXISTS parent_link_span_id String`,
+	},
+	{
+		name: "security_signals",
+		sql: `
+			CREATE TABLE IF NOT EXISTS security_signals (
+				org_id      LowCardinality(String),
+				id          String,
+				trace_id    String,
+				span_id     String,
+				agent_id    String,
+				type        LowCardinality(String),
+				severity    LowCardinality(String),
+				title       String,
+				description String,
+				evidence    String,
+				timestamp   DateTime64(3, 'UTC'),
+				mitigated   UInt8
+			)
+			ENGINE = MergeTree
+			PARTITION BY toYYYYMM(timestamp)
+			ORDER BY (org_id, timestamp, severity, id)`,
+	},
+	{
+		// One row per violated rule in a denied pre-invoke decision.
+		name: "policy_violations",
+		sql: `
+			CREATE TABLE IF NOT EXISTS policy_violations (
+				org_id    LowCardinality(String),
+				timestamp DateTime64(3, 'UTC'),
+				agent_id  String,
+				tool_name LowCardinality(String),
+				policy    LowCardinality(String),
+				rule      LowCardinality(String),
+				severity  LowCardinality(String),
+				enforced  UInt8
+			)
+			ENGINE = MergeTree
+			PARTITION BY toYYYYMM(timestamp)
+			ORDER BY (org_id, timestamp, policy, rule)`,
+	},
+	{
+		name: "llm_tokens_hourly",
+		sql: `
+			CREATE TABLE IF NOT EXISTS llm_tokens_hourly (
+				org_id            LowCardinality(String),
+				hour              DateTime('UTC'),
+				agent_id          String,
+				model             LowCardinality(String),
+				provider          LowCardinality(String),
+				calls             UInt64,
+				prompt_tokens     UInt64,
+				completion_tokens UInt64,
+				total_tokens      UInt64
+			)
+			ENGINE = SummingMergeTree
+			PARTITION BY toYYYYMM(hour)
+			ORDER BY (org_id, hour, agent_id, model, provider)`,
+	},
+	{
+		name: "llm_tokens_hourly_mv",
+		sql: `
+			CREATE MATERIALIZED VIEW IF NOT EXISTS llm_tokens_hourly_mv TO llm_tokens_hourly AS
+			SELECT
+				org_id,
+				toStartOfHour(start_time) AS hour,
+				agent_id,
+				llm_model AS model,
+				llm_provider AS provider,
+				count() AS calls,
+				sum(prompt_tokens) AS prompt_tokens,
+				sum(completion_tokens) AS completion_tokens,
+				sum(total_tokens) AS total_tokens
+			FROM spans
+			WHERE type = 'llm'
+			GROUP BY org_id, hour, agent_id, model, provider`,
+	},
+	{
+		name: "policy_denials_daily",
+		sql: `
+			CREATE TABLE IF NOT EXISTS policy_denials_daily (
+				org_id    LowCardinality(String),
+				day       Date,
+				agent_id  String,
+				policy_id String,
+				tool_name LowCardinality(String),
+				denials   UInt64
+			)
+			ENGINE = SummingMergeTree
+			PARTITION BY toYYYYMM(day)
+			ORDER BY (org_id, day, agent_id, policy_id, tool_name)`,
+	},
+	{
+		name: "policy_denials_daily_mv",
+		sql: `
+			CREATE MATERIALIZED VIEW IF NOT EXISTS policy_denials_daily_mv TO policy_denials_daily AS
+			SELECT
+				org_id,
+				toDate(start_time) AS day,
+				agent_id,
+				policy_id,
+				tool_name,
+				count() AS denials
+			FROM spans
+			WHERE policy_decision = 'deny'
+			GROUP BY org_id, day, agent_id, policy_id, tool_name`,
+	},
+	{
+		name: "rule_denials_daily",
+		sql: `
+			CREATE TABLE IF NOT EXISTS rule_denials_daily (
+				org_id   LowCardinality(String),
+				day      Date,
+				agent_id String,
+				policy   LowCardinality(String),
+				rule     LowCardinality(String),
+				denials  UInt64
+			)
+			ENGINE = SummingMergeTree
+			PARTITION BY toYYYYMM(day)
+			ORDER BY (org_id, day, policy, rule, agent_id)`,
+	},
+	{
+		name: "rule_denials_daily_mv",
+		sql: `
+			CREATE MATERIALIZED VIEW IF NOT EXISTS rule_denials_daily_mv TO rule_denials_daily AS
+			SELECT
+				org_id,
+				toDate(timestamp) AS day,
+				agent_id,
+				policy,
+				rule,
+				count() AS denials
+			FROM policy_violations
+			GROUP BY org_id, day, agent_id, policy, rule`,
+	},
+	{
+		name: "signals_daily",
+		sql: `
+			CREATE TABLE IF NOT EXISTS signals_daily (
+				org_id   LowCardinality(String),
+				day      Date,
+				agent_id String,
+				severity LowCardinality(String),
+				type     LowCardinality(String),
+				signals  UInt64
+			)
+			ENGINE = SummingMergeTree
+			PARTITION BY toYYYYMM(day)
+			ORDER BY (org_id, day, agent_id, severity, type)`,
+	},
+	{
+		name: "signals_daily_mv",
+		sql: `
+			CREATE MATERIALIZED VIEW IF NOT EXISTS signals_daily_mv TO signals_daily AS
+			SELECT
+				org_id,
+				toDate(timestamp) AS day,
+				agent_id,
+				severity,
+				type,
+				count() AS signals
+			FROM security_signals
+			GROUP BY org_id, day, agent_id, severity, type`,
+	},
+}
+
+// EnsureSchema creates any missing tables and materialized views. Every
+// statement is idempotent, so it is safe to run on each startup.
+func (db *DB) EnsureSchema(ctx context.Context) error {
+	for _, s := range schema {
+		if err := db.exec(ctx, s.sql, nil); err != nil {
+			return fmt.Errorf("creating %s: %w", s.name, err)
+		}
+	}
+	log.Info().Int("objects", len(schema)).Msg("ClickHouse schema ready")
+	return nil
+}
@@ -0,0 +1,124 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// signalColumns selects security_signals columns into signalRow.
+const signalColumns = `id, trace_id, span_id, agent_id, type, severity, title,
+	description, evidence, toUnixTimestamp64Milli(timestamp) AS timestamp, mitigated`
+
+// signalSortColumns maps repository.SignalSortFields to expressions.
+// Severities sort by rank, lowest first.
+var signalSortColumns = map[string]string{
+	"timestamp": "timestamp",
+	"type":      "type",
+	"severity":  "indexOf(['low', 'medium', 'high', 'critical'], severity)",
+}
+
+// ListSignals returns an organization's security signals matching filters,
+// newest first unless filters.Sort says otherwise. Signals written more than
+// once are listed once. It implements repository.SignalLister.
+func (r *MetricsRepository) ListSignals(ctx context.Context, orgID string, filters *repository.SignalFilters) ([]models.SecuritySignal, error) {
+	if filters == nil {
+		filters = &repository.SignalFilters{}
+	}
+	where, params := signalFilter(orgID, filters)
+	order := "timestamp DESC, id"
+	if col, ok := signalSortColumns[filters.Sort.Field]; ok {
+		if filters.Sort.Desc {
+			col += " DESC"
+		}
+		order = col + ", " + order
+	}
+	sql := "SELECT * FROM (SELECT " + signalColumns + " FROM security_signals WHERE " + where +
+		" ORDER BY id LIMIT 1 BY id) ORDER BY " + order
+	switch {
+	case filters.Limit > 0:
+		sql += fmt.Sprintf(" LIMIT %d OFFSET %d", filters.Limit, filters.Offset)
+	case filters.Offset > 0:
+		sql += fmt.Sprintf(" OFFSET %d ROWS", filters.Offset)
+	}
+
+	var rows []signalRow
+	if err := r.db.query(ctx, sql, params, &rows); err != nil {
+		return nil, fmt.Errorf("querying security signals: %w", err)
+	}
+	signals := make([]models.SecuritySignal, 0, len(rows))
+	for _, row := range rows {
+		sig, err := row.signal()
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, sig)
+	}
+	return signals, nil
+}
+
+// CountSignals returns how many of an organization's security signals match
+// filters. It implements repository.SignalLister.
+func (r *MetricsRepository) CountSignals(ctx context.Context, orgID string, filters *repository.SignalFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.SignalFilters{}
+	}
+	where, params := signalFilter(orgID, filters)
+	var rows []struct {
+		N int `json:"n"`
+	}
+	if err := r.db.query(ctx, "SELECT uniqExact(id) AS n FROM security_signals WHERE "+where, params, &rows); err != nil {
+		return 0, fmt.Errorf("counting security signals: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].N, nil
+}
+
+// signalFilter returns the WHERE condition selecting an organization's
+// signals matching filters, and its parameters.
+func signalFilter(orgID string, filters *repository.SignalFilters) (string, map[string]string) {
+	where := "org_id = {org:String}"
+	params := map[string]string{"org": orgID}
+	for _, f := range []struct {
+		column string
+		value  *string
+	}{
+		{"trace_id", filters.TraceID},
+		{"agent_id", filters.AgentID},
+		{"type", (*string)(filters.Type)},
+		{"severity", filters.Severity},
+	} {
+		if f.value != nil {
+			where += " AND " + f.column + " = {" + f.column + ":String}"
+			params[f.column] = *f.value
+		}
+	}
+	return where, params
+}
+
+// signal is the inverse of the row built by signalRows.
+func (row signalRow) signal() (models.SecuritySignal, error) {
+	sig := models.SecuritySignal{
+		ID:          row.ID,
+		TraceID:     row.TraceID,
+		SpanID:      row.SpanID,
+		Type:        models.SignalType(row.Type),
+		Severity:    row.Severity,
+		Title:       row.Title,
+		Description: row.Description,
+		Timestamp:   time.UnixMilli(row.Timestamp).UTC(),
+		Mitigated:   row.Mitigated == 1,
+	}
+	if row.Evidence != "" {
+		if err := json.Unmarshal([]byte(row.Evidence), &sig.Evidence); err != nil {
+			return sig, fmt.Errorf("decoding evidence for signal %s: %w", row.ID, err)
+		}
+	}
+	return sig, nil
+}
@@ -0,0 +1,102 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// ToolUsage aggregates an agent's tool spans per tool. Latency percentiles
+// are computed over span durations with quantiles(), which is approximate
+// for large inputs. It implements repository.ToolUsageRepository.
+func (r *MetricsRepository) ToolUsage(ctx context.Context, q *repository.ToolUsageQuery) ([]repository.ToolUsage, error) {
+	params := map[string]string{
+		"org":   q.OrgID,
+		"agent": q.AgentID,
+		"from":  strconv.FormatInt(q.From.UnixMilli(), 10),
+		"to":    strconv.FormatInt(q.To.UnixMilli(), 10),
+	}
+	where := `org_id = {org:String} AND agent_id = {agent:String} AND type = 'tool'
+		AND start_time >= fromUnixTimestamp64Milli({from:Int64})
+		AND start_time < fromUnixTimestamp64Milli({to:Int64})`
+	if q.Tool != nil {
+		where += " AND tool_name = {tool:String}"
+		params["tool"] = *q.Tool
+	}
+
+	query := `SELECT
+			tool_name,
+			any(tool_category) AS category,
+			count() AS calls,
+			countIf(policy_decision = 'deny') AS denials,
+			countIf(status = 'error') AS errors,
+			countIf(external_call = 1) AS external_calls,
+			quantiles(0.5, 0.95, 0.99)(duration_ms) AS latency,
+			toUnixTimestamp64Milli(min(start_time)) AS first_seen,
+			toUnixTimestamp64Milli(max(start_time)) AS last_seen
+		FROM spans
+		WHERE ` + where + `
+		GROUP BY tool_name
+		ORDER BY calls DESC, tool_name`
+
+	var rows []struct {
+		Tool          string    `json:"tool_name"`
+		Category      string    `json:"category"`
+		Calls         int64     `json:"calls"`
+		Denials       int64     `json:"denials"`
+		Errors        int64     `json:"errors"`
+		ExternalCalls int64     `json:"external_calls"`
+		Latency       []float64 `json:"latency"`
+		FirstSeen     int64     `json:"first_seen"`
+		LastSeen      int64     `json:"last_seen"`
+	}
+	if err := r.db.query(ctx, query, params, &rows); err != nil {
+		return nil, fmt.Errorf("querying tool usage: %w", err)
+	}
+
+	usage := make([]repository.ToolUsage, 0, len(rows))
+	for _, row := range rows {
+		u := repository.ToolUsage{
+			Tool:          row.Tool,
+			Category:      row.Category,
+			Calls:         row.Calls,
+			Denials:       row.Denials,
+			Errors:        row.Errors,
+			ExternalCalls: row.ExternalCalls,
+			FirstSeen:     time.UnixMilli(row.FirstSeen).UTC(),
+			LastSeen:      time.UnixMilli(row.LastSeen).UTC(),
+		}
+		if row.Calls > 0 {
+			u.DenialRate = float64(row.Denials) / float64(row.Calls)
+			u.ExternalRatio = float64(row.ExternalCalls) / float64(row.Calls)
+		}
+		if len(row.Latency) == 3 {
+			u.LatencyP50Ms, u.LatencyP95Ms, u.LatencyP99Ms = row.Latency[0], row.Latency[1], row.Latency[2]
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// LastTraceAt returns the start time of an agent's most recent span, or
+// nil when it has none. It implements repository.AgentActivityReader.
+func (r *MetricsRepository) LastTraceAt(ctx context.Context, orgID, agentID string) (*time.Time, error) {
+	var rows []struct {
+		Spans    int64 `json:"spans"`
+		LastSeen int64 `json:"last_seen"`
+	}
+	err := r.db.query(ctx, `SELECT count() AS spans, toUnixTimestamp64Milli(max(start_time)) AS last_seen
+		FROM spans WHERE org_id = {org:String} AND agent_id = {agent:String}`,
+		map[string]string{"org": orgID, "agent": agentID}, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("querying agent activity: %w", err)
+	}
+	if len(rows) == 0 || rows[0].Spans == 0 {
+		return nil, nil
+	}
+	last := time.UnixMilli(rows[0].LastSeen).UTC()
+	return &last, nil
+}
@@ -0,0 +1,215 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// spanColumns selects spans table columns into spanRow, converting times to
+// the epoch milliseconds spanRow uses on insert.
+const spanColumns = `trace_id, span_id, parent_span_id, agent_id, session_id, user_id,
+	name, type, toUnixTimestamp64Milli(start_time) AS start_time, duration_ms, status,
+	llm_model, llm_provider, prompt_tokens, completion_tokens, total_tokens, prompt_hash,
+	tool_name, tool_category, external_call, policy_id, policy_decision, policy_reason,
+	attributes, input_hash, output_hash, input_ref, output_ref,
+	parent_trace_id, parent_link_span_id`
+
+// GetTrace reassembles a trace from its stored spans and security signals.
+// Only fields persisted on ingest are restored; trace-level status and
+// metadata are not stored. It implements repository.TraceReader.
+func (r *MetricsRepository) GetTrace(ctx context.Context, orgID, traceID string) (*models.AgentTrace, error) {
+	params := map[string]string{"org": orgID, "trace": traceID}
+
+	var rows []spanRow
+	if err := r.db.query(ctx, "SELECT "+spanColumns+
+		" FROM spans WHERE org_id = {org:String} AND trace_id = {trace:String}"+
+		" ORDER BY start_time, span_id LIMIT 1 BY span_id", params, &rows); err != nil {
+		return nil, fmt.Errorf("querying spans: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	var sigRows []signalRow
+	if err := r.db.query(ctx, "SELECT "+signalColumns+
+		" FROM security_signals WHERE org_id = {org:String} AND trace_id = {trace:String}"+
+		" ORDER BY timestamp, id LIMIT 1 BY id", params, &sigRows); err != nil {
+		return nil, fmt.Errorf("querying security signals: %w", err)
+	}
+
+	first := rows[0]
+	t := &models.AgentTrace{
+		TraceID:   traceID,
+		SessionID: first.SessionID,
+		UserID:    first.UserID,
+		StartTime: time.UnixMilli(first.StartTime).UTC(),
+		Spans:     make([]models.Span, 0, len(rows)),
+	}
+	t.AgentID, _ = uuid.Parse(first.AgentID)
+	if first.ParentTraceID != "" {
+		t.Parent = &models.TraceLink{TraceID: first.ParentTraceID, SpanID: first.ParentLinkSpanID}
+	}
+
+	var end time.Time
+	for _, row := range rows {
+		s, err := row.span()
+		if err != nil {
+			return nil, err
+		}
+		if e := s.StartTime.Add(time.Duration(s.DurationMs) * time.Millisecond); e.After(end) {
+			end = e
+		}
+		if s.Data.LLM != nil {
+			t.Metrics.LLMCalls++
+			t.Metrics.TotalTokens += s.Data.LLM.TotalTokens
+		}
+		if s.Data.Tool != nil {
+			t.Metrics.ToolInvocations++
+		}
+		t.Spans = append(t.Spans, s)
+	}
+	t.EndTime = &end
+	t.DurationMs = end.Sub(t.StartTime).Milliseconds()
+	t.Metrics.TotalSpans = len(t.Spans)
+	t.Metrics.SecuritySignals = len(sigRows)
+
+	for _, row := range sigRows {
+		sig, err := row.signal()
+		if err != nil {
+			return nil, err
+		}
+		t.SecuritySignals = append(t.SecuritySignals, sig)
+	}
+	return t, nil
+}
+
+// span is the inverse of the row built by InsertTrace.
+func (row spanRow) span() (models.Span, error) {
+	start := time.UnixMilli(row.StartTime).UTC()
+	end := start.Add(time.Duration(row.DurationMs) * time.Millisecond)
+	s := models.Span{
+		SpanID:     row.SpanID,
+		Name:       row.Name,
+		Type:       models.SpanType(row.Type),
+		StartTime:  start,
+		EndTime:    &end,
+		DurationMs: row.DurationMs,
+		Status:     row.Status,
+	}
+	if row.ParentSpanID != "" {
+		parent := row.ParentSpanID
+		s.ParentSpanID = &parent
+	}
+	if row.Attributes != "" {
+		if err := json.Unmarshal([]byte(row.Attributes), &s.Attributes); err != nil {
+			return s, fmt.Errorf("decoding attributes for span %s: %w", row.SpanID, err)
+		}
+	}
+	if s.Type == models.SpanTypeLLM || row.LLMModel != "" {
+		s.Data.LLM = &models.LLMSpanData{
+			Model:            row.LLMModel,
+			Provider:         row.LLMProvider,
+			PromptTokens:     row.PromptTokens,
+			CompletionTokens: row.CompletionTokens,
+			TotalTokens:      row.TotalTokens,
+			PromptHash:       row.PromptHash,
+		}
+	}
+	if s.Type == models.SpanTypeTool || row.ToolName != "" {
+		tool := &models.ToolSpanData{
+			ToolName:     row.ToolName,
+			ToolCategory: row.ToolCategory,
+			ExternalCall: row.ExternalCall == 1,
+			InputHash:    row.InputHash,
+			OutputHash:   row.OutputHash,
+		}
+		if row.PolicyDecision != "" {
+			tool.PolicyDecision = &models.PolicyDecision{
+				PolicyID: row.PolicyID,
+				Decision: row.PolicyDecision,
+				Reason:   row.PolicyReason,
+			}
+		}
+		if row.InputRef != "" {
+			if err := json.Unmarshal([]byte(row.InputRef), &tool.InputRef); err != nil {
+				return s, fmt.Errorf("decoding input ref for span %s: %w", row.SpanID, err)
+			}
+		}
+		if row.OutputRef != "" {
+			if err := json.Unmarshal([]byte(row.OutputRef), &tool.OutputRef); err != nil {
+				return s, fmt.Errorf("decoding output ref for span %s: %w", row.SpanID, err)
+			}
+		}
+		s.Data.Tool = tool
+	}
+	return s, nil
+}
+
+// traceSummarySelect summarizes traces for delegation queries: the agent,
+// the parent link and the distinct tools called.
+const traceSummarySelect = `SELECT
+		trace_id,
+		any(agent_id) AS agent_id,
+		any(parent_trace_id) AS parent_trace_id,
+		any(parent_link_span_id) AS parent_link_span_id,
+		toUnixTimestamp64Milli(min(start_time)) AS start_time,
+		groupUniqArrayIf([tool_name, tool_category], tool_name != '') AS tools
+	FROM spans
+	WHERE org_id = {org:String} AND `
+
+// TraceSummaries implements repository.DelegationRepository.
+func (r *MetricsRepository) TraceSummaries(ctx context.Context, orgID string, ids []string) ([]repository.TraceSummary, error) {
+	return r.traceSummaries(ctx, "trace_id IN {ids:Array(String)}", orgID, ids)
+}
+
+// DelegatedTraces implements repository.DelegationRepository.
+func (r *MetricsRepository) DelegatedTraces(ctx context.Context, orgID string, parentIDs []string) ([]repository.TraceSummary, error) {
+	return r.traceSummaries(ctx, "parent_trace_id IN {ids:Array(String)}", orgID, parentIDs)
+}
+
+func (r *MetricsRepository) traceSummaries(ctx context.Context, where, orgID string, ids []string) ([]repository.TraceSummary, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		TraceID          string     `json:"trace_id"`
+		AgentID          string     `json:"agent_id"`
+		ParentTraceID    string     `json:"parent_trace_id"`
+		ParentLinkSpanID string     `json:"parent_link_span_id"`
+		StartTime        int64      `json:"start_time"`
+		Tools            [][]string `json:"tools"`
+	}
+	params := map[string]string{"org": orgID, "ids": arrayParam(ids)}
+	if err := r.db.query(ctx, traceSummarySelect+where+
+		" GROUP BY trace_id ORDER BY start_time, trace_id", params, &rows); err != nil {
+		return nil, fmt.Errorf("querying trace summaries: %w", err)
+	}
+
+	out := make([]repository.TraceSummary, 0, len(rows))
+	for _, row := range rows {
+		s := repository.TraceSummary{
+			TraceID:   row.TraceID,
+			AgentID:   row.AgentID,
+			StartTime: time.UnixMilli(row.StartTime).UTC(),
+			Tools:     make([]models.ToolBinding, 0, len(row.Tools)),
+		}
+		if row.ParentTraceID != "" {
+			s.Parent = &models.TraceLink{TraceID: row.ParentTraceID, SpanID: row.ParentLinkSpanID}
+		}
+		for _, t := range row.Tools {
+			if len(t) == 2 {
+				s.Tools = append(s.Tools, models.ToolBinding{Name: t[0], Category: t[1]})
+			}
+		}
+		sort.Slice(s.Tools, func(i, j int) bool { return s.Tools[i].Name < s.Tools[j].Name })
+		out = append(out, s)
+	}
+	return out, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by repository implementations so callers
+// can distinguish failure classes with errors.Is.
+var (
+	// ErrNotFound indicates the requested entity does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indicates a uniqueness constraint was violated.
+	ErrConflict = errors.New("already exists")
+	// ErrForeignKey indicates a referenced entity does not exist or the entity
+	// is still referenced by others.
+	ErrForeignKey = errors.New("foreign key violation")
+	// ErrInvalidQuery indicates query parameters the backend cannot serve.
+	ErrInvalidQuery = errors.New("invalid query")
+)
@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/agentguard/agentguard/internal/models"
 	"github.com/google/uuid"
@@ -28,11 +29,88 @@ type ControlRepository interface {
 	GetCrosswalk(ctx context.Context, sourceFrameworkID, targetFrameworkID string) ([]models.Crosswalk, error)
 	CreateCrosswalk(ctx context.Context, cw *models.Crosswalk) error
 	DeleteCrosswalk(ctx context.Context, id string) error
+
+	// ImportCatalog atomically creates a framework with its controls and
+	// crosswalks. Importing a new version of an existing framework
+	// supersedes the current one.
+	ImportCatalog(ctx context.Context, catalog *CatalogImport) error
+}
+
+// FrameworkVersionRepository stores the versions of a framework that a
+// newer import superseded, with their controls.
+type FrameworkVersionRepository interface {
+	// ListFrameworkVersions returns a framework's versions, the current one
+	// included, without their controls.
+	ListFrameworkVersions(ctx context.Context, frameworkID string) ([]models.FrameworkVersion, error)
+	// GetFrameworkVersion returns a version with its controls, or nil if
+	// the framework has no such version.
+	GetFrameworkVersion(ctx context.Context, frameworkID, version string) (*models.FrameworkVersion, error)
+}
+
+// CrosswalkReviewRepository reads and updates single crosswalks so that
+// analyst-curated mappings can be edited and moved through review.
+type CrosswalkReviewRepository interface {
+	// GetCrosswalkByID returns a crosswalk, or nil if there is none.
+	GetCrosswalkByID(ctx context.Context, id string) (*models.Crosswalk, error)
+	// UpdateCrosswalk replaces a crosswalk's mapping and review fields.
+	UpdateCrosswalk(ctx context.Context, cw *models.Crosswalk) error
+}
+
+// ControlTagRepository stores the tags organizations put on controls.
+type ControlTagRepository interface {
+	// ListControlTags returns an organization's tagged controls ordered by
+	// framework and control ID, of one framework unless frameworkID is
+	// empty.
+	ListControlTags(ctx context.Context, orgID, frameworkID string) ([]models.ControlTags, error)
+	// SetControlTags replaces a control's tags. Setting no tags removes
+	// them.
+	SetControlTags(ctx context.Context, t *models.ControlTags) error
+}
+
+// ControlSearchRepository searches controls across frameworks.
+type ControlSearchRepository interface {
+	// SearchControls returns a page of the controls matching q, best match
+	// first, and the number of matches in all pages.
+	SearchControls(ctx context.Context, q *ControlQuery) ([]ControlMatch, int, error)
+}
+
+// ControlQuery selects controls by free text and filters. Each filter
+// matches a control that has any of its values; an empty filter matches
+// every control.
+type ControlQuery struct {
+	Text          string
+	FrameworkIDs  []string
+	Layers        []string
+	EvidenceTypes []string // matched as case-insensitive substrings
+	// ExactEvidence matches EvidenceTypes against whole evidence types,
+	// case-insensitively, which an index serves on large catalogs.
+	ExactEvidence bool
+	// Tags matches the controls OrganizationID tagged with any of the tags.
+	Tags           []string
+	OrganizationID string
+	Offset         int
+	Limit          int
+}
+
+// ControlMatch is a control found by a search, with how well its title and
+// description match the query text.
+type ControlMatch struct {
+	models.Control
+	Rank float64 `json:"rank"`
+}
+
+// CatalogImport is a framework together with its controls and crosswalks.
+type CatalogImport struct {
+	Framework  models.Framework   `json:"framework"`
+	Controls   []models.Control   `json:"controls"`
+	Crosswalks []models.Crosswalk `json:"crosswalks,omitempty"`
 }
 
 // AgentRepository defines operations for agent registry data.
 type AgentRepository interface {
 	List(ctx context.Context, filters *AgentFilters) ([]models.Agent, error)
+	// Count returns how many agents match filters, ignoring Offset and Limit.
+	Count(ctx context.Context, filters *AgentFilters) (int, error)
 	Get(ctx context.Context, id uuid.UUID) (*models.Agent, error)
 	Create(ctx context.Context, a *models.Agent) error
 	Update(ctx context.Context, a *models.Agent) error
@@ -41,19 +119,60 @@ type AgentRepository interface {
 	BindPolicies(ctx context.Context, agentID uuid.UUID, policyIDs []string) error
 }
 
+// SigningKeyRepository stores the keys agents sign SDK hook requests with.
+// List returns an agent's keys oldest first, expired ones included; Update
+// and Delete return ErrNotFound for a missing key.
+type SigningKeyRepository interface {
+	List(ctx context.Context, agentID string) ([]models.SigningKey, error)
+	Create(ctx context.Context, k *models.SigningKey) error
+	Update(ctx context.Context, k *models.SigningKey) error
+	Delete(ctx context.Context, agentID, id string) error
+}
+
+// AgentHistory reads the agent registry as it was at a past time. Every
+// registration change, policy bindings included, is kept.
+type AgentHistory interface {
+	// GetAsOf returns an agent as registered at asOf, or nil if it was not
+	// registered then.
+	GetAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Agent, error)
+}
+
 // AgentFilters defines filtering options for agent queries.
 type AgentFilters struct {
 	Status      *models.AgentStatus
 	Environment *string
 	Team        *string
 	Framework   *string
-	Offset      int
-	Limit       int
+	// Policy matches agents bound to the policy with this ID.
+	Policy *string
+	Sort   Sort
+	Offset int
+	Limit  int
+}
+
+// Sort orders a list by one of the fields its repository accepts, named in
+// the matching SortFields variable. The zero Sort keeps the list's default
+// order, which also breaks ties.
+type Sort struct {
+	Field string
+	Desc  bool
 }
 
-// PolicyRepository defines operations for policy data.
+// Fields each list can be sorted by.
+var (
+	AgentSortFields  = []string{"name", "status", "environment", "risk_level", "created_at", "updated_at"}
+	PolicySortFields = []string{"priority", "name", "type", "created_at", "updated_at"}
+	TraceSortFields  = []string{"start_time", "duration_ms", "total_tokens", "estimated_cost_usd", "security_signals"}
+	SignalSortFields = []string{"timestamp", "type", "severity"}
+)
+
+// PolicyRepository defines operations for policy data. Get returns nil when
+// the policy does not exist; Create returns ErrConflict for a duplicate ID.
 type PolicyRepository interface {
 	List(ctx context.Context, filters *PolicyFilters) ([]models.Policy, error)
+	// Count returns how many policies match filters, ignoring Offset and
+	// Limit.
+	Count(ctx context.Context, filters *PolicyFilters) (int, error)
 	Get(ctx context.Context, id string) (*models.Policy, error)
 	Create(ctx context.Context, p *models.Policy) error
 	Update(ctx context.Context, p *models.Policy) error
@@ -65,6 +184,7 @@ type PolicyRepository interface {
 type PolicyFilters struct {
 	Type    *models.PolicyType
 	Enabled *bool
+	Sort    Sort
 	Offset  int
 	Limit   int
 }
@@ -78,6 +198,71 @@ type TraceRepository interface {
 	ListSecuritySignals(ctx context.Context, filters *SignalFilters) ([]models.SecuritySignal, error)
 }
 
+// TraceWriter persists ingested traces for an organization.
+type TraceWriter interface {
+	InsertTrace(ctx context.Context, orgID string, t *models.AgentTrace) error
+}
+
+// TraceBatchWriter persists several ingested traces in one write. A failed
+// batch may be retried, in whole or in part, so writing a trace again must
+// not duplicate it in reads.
+type TraceBatchWriter interface {
+	InsertTraces(ctx context.Context, batch []OrgTrace) error
+}
+
+// OrgTrace is a trace with the organization it was ingested for.
+type OrgTrace struct {
+	OrgID string
+	Trace *models.AgentTrace
+}
+
+// TraceLister lists the trace-level records of an organization's traces,
+// newest first unless filters sort them otherwise. Listed traces carry
+// metadata and metrics but no spans or signals. CountTraces ignores Offset
+// and Limit.
+type TraceLister interface {
+	ListTraces(ctx context.Context, orgID string, filters *TraceFilters) ([]models.AgentTrace, error)
+	CountTraces(ctx context.Context, orgID string, filters *TraceFilters) (int, error)
+}
+
+// TraceReader loads stored traces for an organization. GetTrace returns
+// ErrNotFound when the organization has no spans for the trace.
+type TraceReader interface {
+	GetTrace(ctx context.Context, orgID, traceID string) (*models.AgentTrace, error)
+}
+
+// SignalWriter persists security signals raised outside an ingested trace,
+// such as fail-open decisions made by the server.
+type SignalWriter interface {
+	InsertSignals(ctx context.Context, orgID, agentID string, signals []models.SecuritySignal) error
+}
+
+// SignalLister lists an organization's security signals, those on ingested
+// traces included, newest first unless filters sort them otherwise.
+// CountSignals ignores Offset and Limit.
+type SignalLister interface {
+	ListSignals(ctx context.Context, orgID string, filters *SignalFilters) ([]models.SecuritySignal, error)
+	CountSignals(ctx context.Context, orgID string, filters *SignalFilters) (int, error)
+}
+
+// ViolationWriter persists the policy rules behind denied decisions.
+type ViolationWriter interface {
+	InsertViolations(ctx context.Context, orgID string, violations []PolicyViolation) error
+}
+
+// PolicyViolation is one rule violated by a denied policy decision.
+// Enforced is false when the agent's profile only monitors, so the call
+// went ahead.
+type PolicyViolation struct {
+	Timestamp time.Time
+	AgentID   string
+	Tool      string
+	Policy    string
+	Rule      string
+	Severity  string
+	Enforced  bool
+}
+
 // TraceFilters defines filtering options for trace queries.
 type TraceFilters struct {
 	AgentID   *uuid.UUID
@@ -85,6 +270,7 @@ type TraceFilters struct {
 	Status    *models.TraceStatus
 	StartFrom *int64 // Unix timestamp
 	StartTo   *int64
+	Sort      Sort
 	Offset    int
 	Limit     int
 }
@@ -92,12 +278,106 @@ type TraceFilters struct {
 // SignalFilters defines filtering options for security signal queries.
 type SignalFilters struct {
 	TraceID  *string
+	AgentID  *string
 	Type     *models.SignalType
 	Severity *string
+	Sort     Sort
 	Offset   int
 	Limit    int
 }
 
+// MetricsRepository serves pre-aggregated observability metrics.
+type MetricsRepository interface {
+	Rollup(ctx context.Context, q *MetricsQuery) ([]MetricPoint, error)
+}
+
+// Metric names served by MetricsRepository.
+const (
+	MetricTokens           = "tokens"
+	MetricPromptTokens     = "prompt_tokens"
+	MetricCompletionTokens = "completion_tokens"
+	MetricLLMCalls         = "llm_calls"
+	MetricDenials          = "denials"
+	MetricRuleDenials      = "rule_denials"
+	MetricSignals          = "signals"
+)
+
+// MetricsQuery selects a metric time series.
+type MetricsQuery struct {
+	OrgID    string
+	Metric   string
+	From     time.Time
+	To       time.Time
+	Interval string   // hour, day, week
+	GroupBy  []string // metric-specific dimensions, e.g. model, severity
+	AgentID  *string
+}
+
+// MetricPoint is one bucket of a metric time series.
+type MetricPoint struct {
+	Bucket time.Time         `json:"bucket"`
+	Group  map[string]string `json:"group,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// ToolUsageRepository reports how an agent's tools are used.
+type ToolUsageRepository interface {
+	ToolUsage(ctx context.Context, q *ToolUsageQuery) ([]ToolUsage, error)
+}
+
+// AgentActivityReader reports when an agent's SDK last sent a trace.
+type AgentActivityReader interface {
+	// LastTraceAt returns the start time of the agent's most recent
+	// ingested span, or nil when none has been ingested.
+	LastTraceAt(ctx context.Context, orgID, agentID string) (*time.Time, error)
+}
+
+// ToolUsageQuery selects one agent's tool spans in a time range.
+type ToolUsageQuery struct {
+	OrgID   string
+	AgentID string
+	From    time.Time
+	To      time.Time
+	Tool    *string
+}
+
+// ToolUsage summarizes calls to one tool.
+type ToolUsage struct {
+	Tool          string    `json:"tool"`
+	Category      string    `json:"category,omitempty"`
+	Calls         int64     `json:"calls"`
+	Denials       int64     `json:"denials"`
+	DenialRate    float64   `json:"denial_rate"`
+	Errors        int64     `json:"errors"`
+	ExternalCalls int64     `json:"external_calls"`
+	ExternalRatio float64   `json:"external_ratio"`
+	LatencyP50Ms  float64   `json:"latency_p50_ms"`
+	LatencyP95Ms  float64   `json:"latency_p95_ms"`
+	LatencyP99Ms  float64   `json:"latency_p99_ms"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// DelegationRepository follows the parent links between the traces of
+// agents that delegate to each other.
+type DelegationRepository interface {
+	// TraceSummaries summarizes the stored traces among ids.
+	TraceSummaries(ctx context.Context, orgID string, ids []string) ([]TraceSummary, error)
+	// DelegatedTraces summarizes the traces linked to spans of the given
+	// parent traces.
+	DelegatedTraces(ctx context.Context, orgID string, parentIDs []string) ([]TraceSummary, error)
+}
+
+// TraceSummary is one trace's place in a delegation forest and the tools
+// its agent called.
+type TraceSummary struct {
+	TraceID   string               `json:"trace_id"`
+	AgentID   string               `json:"agent_id"`
+	Parent    *models.TraceLink    `json:"parent,omitempty"`
+	StartTime time.Time            `json:"start_time"`
+	Tools     []models.ToolBinding `json:"tools"`
+}
+
 // ThreatModelRepository defines operations for threat model data.
 type ThreatModelRepository interface {
 	List(ctx context.Context) ([]models.ThreatModel, error)
@@ -114,6 +394,62 @@ type MaturityRepository interface {
 	CreateAssessment(ctx context.Context, ma *models.MaturityAssessment) error
 }
 
+// ControlImplementationRepository tracks organizations' control
+// implementations. An organization has at most one implementation per
+// framework control; Create returns ErrConflict for another.
+type ControlImplementationRepository interface {
+	// List returns an organization's implementations ordered by framework
+	// and control ID. An empty frameworkID lists every framework.
+	List(ctx context.Context, orgID, frameworkID string) ([]models.ControlImplementation, error)
+	Get(ctx context.Context, id string) (*models.ControlImplementation, error)
+	Create(ctx context.Context, ci *models.ControlImplementation) error
+	Update(ctx context.Context, ci *models.ControlImplementation) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ControlImplementationHistory reads the implementation inventory as it
+// was at a past time. Every change to an implementation is kept, so an
+// auditor can ask what coverage was on a given date.
+type ControlImplementationHistory interface {
+	// ListAsOf returns an organization's implementations as they stood at
+	// asOf, ordered like List. An empty frameworkID lists every framework.
+	ListAsOf(ctx context.Context, orgID, frameworkID string, asOf time.Time) ([]models.ControlImplementation, error)
+}
+
+// GapDispositionRepository stores organizations' decisions on control
+// gaps. An organization has at most one disposition per framework control;
+// Create returns ErrConflict for another.
+type GapDispositionRepository interface {
+	// List returns an organization's dispositions ordered by framework and
+	// control ID. An empty frameworkID lists every framework.
+	List(ctx context.Context, orgID, frameworkID string) ([]models.GapDisposition, error)
+	// ListRiskAcceptances returns every organization's accept and transfer
+	// dispositions, for review reminders.
+	ListRiskAcceptances(ctx context.Context) ([]models.GapDisposition, error)
+	Get(ctx context.Context, id string) (*models.GapDisposition, error)
+	Create(ctx context.Context, d *models.GapDisposition) error
+	Update(ctx context.Context, d *models.GapDisposition) error
+	Delete(ctx context.Context, id string) error
+}
+
+// AttestationRepository defines operations for attestation campaigns and
+// the attestations assigned in them.
+type AttestationRepository interface {
+	// CreateCampaign stores a campaign and its attestations together.
+	CreateCampaign(ctx context.Context, c *models.AttestationCampaign, attestations []models.Attestation) error
+	// ListCampaigns returns an organization's campaigns, newest first.
+	ListCampaigns(ctx context.Context, orgID string) ([]models.AttestationCampaign, error)
+	// ListOpenCampaigns returns every organization's open campaigns.
+	ListOpenCampaigns(ctx context.Context) ([]models.AttestationCampaign, error)
+	GetCampaign(ctx context.Context, id string) (*models.AttestationCampaign, error)
+	UpdateCampaign(ctx context.Context, c *models.AttestationCampaign) error
+	// ListAttestations returns a campaign's attestations ordered by
+	// framework and control ID.
+	ListAttestations(ctx context.Context, campaignID string) ([]models.Attestation, error)
+	GetAttestation(ctx context.Context, id string) (*models.Attestation, error)
+	UpdateAttestation(ctx context.Context, a *models.Attestation) error
+}
+
 // GapAnalysisRepository defines operations for gap analysis data.
 type GapAnalysisRepository interface {
 	List(ctx context.Context, orgID string) ([]models.GapAnalysis, error)
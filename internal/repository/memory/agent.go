@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AgentRepository implements repository.AgentRepository and
+// repository.AgentHistory in memory.
+type AgentRepository struct {
+	mu      sync.RWMutex
+	agents  map[uuid.UUID]models.Agent
+	history map[uuid.UUID][]agentRevision
+}
+
+// agentRevision is an agent as registered from validFrom until its next
+// revision. A nil agent records its deletion.
+type agentRevision struct {
+	validFrom time.Time
+	agent     *models.Agent
+}
+
+// NewAgentRepository creates an empty AgentRepository.
+func NewAgentRepository() *AgentRepository {
+	return &AgentRepository{
+		agents:  make(map[uuid.UUID]models.Agent),
+		history: make(map[uuid.UUID][]agentRevision),
+	}
+}
+
+// agentSortFields compares agents by repository.AgentSortFields.
+var agentSortFields = map[string]func(a, b *models.Agent) int{
+	"name":        func(a, b *models.Agent) int { return strings.Compare(a.Name, b.Name) },
+	"status":      func(a, b *models.Agent) int { return strings.Compare(string(a.Status), string(b.Status)) },
+	"environment": func(a, b *models.Agent) int { return strings.Compare(a.Environment, b.Environment) },
+	"risk_level":  func(a, b *models.Agent) int { return strings.Compare(a.RiskLevel, b.RiskLevel) },
+	"created_at":  func(a, b *models.Agent) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated_at":  func(a, b *models.Agent) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+// List returns the agents matching filters, ordered by name unless
+// filters.Sort says otherwise.
+func (r *AgentRepository) List(_ context.Context, filters *repository.AgentFilters) ([]models.Agent, error) {
+	if filters == nil {
+		filters = &repository.AgentFilters{}
+	}
+	agents := r.matching(filters)
+	sort.Slice(agents, func(i, j int) bool {
+		if agents[i].Name != agents[j].Name {
+			return agents[i].Name < agents[j].Name
+		}
+		return agents[i].ID.String() < agents[j].ID.String()
+	})
+	sortList(agents, filters.Sort, agentSortFields)
+	return pageList(agents, filters.Offset, filters.Limit), nil
+}
+
+// Count returns how many agents match filters.
+func (r *AgentRepository) Count(_ context.Context, filters *repository.AgentFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.AgentFilters{}
+	}
+	return len(r.matching(filters)), nil
+}
+
+// matching returns the agents matching filters, in no particular order.
+func (r *AgentRepository) matching(filters *repository.AgentFilters) []models.Agent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var agents []models.Agent
+	for _, a := range r.agents {
+		if (filters.Status == nil || a.Status == *filters.Status) &&
+			(filters.Environment == nil || a.Environment == *filters.Environment) &&
+			(filters.Team == nil || a.Team == *filters.Team) &&
+			(filters.Framework == nil || a.Framework == *filters.Framework) &&
+			(filters.Policy == nil || slices.Contains(a.Policies, *filters.Policy)) {
+			agents = append(agents, a)
+		}
+	}
+	return agents
+}
+
+// Get returns an agent, or nil if there is none.
+func (r *AgentRepository) Get(_ context.Context, id uuid.UUID) (*models.Agent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.agents[id]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// GetAsOf returns an agent as registered at asOf, policy bindings included,
+// or nil if it was not registered then.
+func (r *AgentRepository) GetAsOf(_ context.Context, id uuid.UUID, asOf time.Time) (*models.Agent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var a *models.Agent
+	for _, rev := range r.history[id] {
+		if rev.validFrom.After(asOf) {
+			break
+		}
+		a = rev.agent
+	}
+	if a == nil {
+		return nil, nil
+	}
+	agent := *a
+	return &agent, nil
+}
+
+// record appends a revision to an agent's history; the caller holds the
+// write lock.
+func (r *AgentRepository) record(id uuid.UUID, at time.Time, a *models.Agent) {
+	if a != nil {
+		saved := *a
+		a = &saved
+	}
+	r.history[id] = append(r.history[id], agentRevision{validFrom: at, agent: a})
+}
+
+// Create stores an agent, assigning an ID when it has none.
+func (r *AgentRepository) Create(_ context.Context, a *models.Agent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if _, ok := r.agents[a.ID]; ok {
+		return fmt.Errorf("creating agent: %w", repository.ErrConflict)
+	}
+	a.CreatedAt = time.Now().UTC()
+	a.UpdatedAt = a.CreatedAt
+	r.agents[a.ID] = *a
+	r.record(a.ID, a.CreatedAt, a)
+	return nil
+}
+
+// Update replaces an agent, keeping its creation time.
+func (r *AgentRepository) Update(_ context.Context, a *models.Agent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.agents[a.ID]
+	if !ok {
+		return fmt.Errorf("agent %s: %w", a.ID, repository.ErrNotFound)
+	}
+	a.CreatedAt = existing.CreatedAt
+	a.UpdatedAt = time.Now().UTC()
+	r.agents[a.ID] = *a
+	r.record(a.ID, a.UpdatedAt, a)
+	return nil
+}
+
+// Delete removes an agent.
+func (r *AgentRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.agents[id]; !ok {
+		return fmt.Errorf("agent %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.agents, id)
+	r.record(id, time.Now().UTC(), nil)
+	return nil
+}
+
+// GetPolicies returns the policies bound to an agent. Policies are not
+// stored in memory, so only their IDs are set.
+func (r *AgentRepository) GetPolicies(_ context.Context, agentID uuid.UUID) ([]models.Policy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.agents[agentID]
+	if !ok {
+		return nil, fmt.Errorf("agent %s: %w", agentID, repository.ErrNotFound)
+	}
+	policies := make([]models.Policy, 0, len(a.Policies))
+	for _, id := range a.Policies {
+		policies = append(policies, models.Policy{ID: id})
+	}
+	return policies, nil
+}
+
+// BindPolicies replaces the policies bound to an agent.
+func (r *AgentRepository) BindPolicies(_ context.Context, agentID uuid.UUID, policyIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.agents[agentID]
+	if !ok {
+		return fmt.Errorf("agent %s: %w", agentID, repository.ErrNotFound)
+	}
+	a.Policies = append([]string(nil), policyIDs...)
+	a.UpdatedAt = time.Now().UTC()
+	r.agents[agentID] = a
+	r.record(agentID, a.UpdatedAt, &a)
+	return nil
+}
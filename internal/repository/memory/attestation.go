@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AttestationRepository implements repository.AttestationRepository in
+// memory.
+type AttestationRepository struct {
+	mu           sync.RWMutex
+	campaigns    map[string]models.AttestationCampaign
+	attestations map[string]models.Attestation
+}
+
+// NewAttestationRepository creates an empty AttestationRepository.
+func NewAttestationRepository() *AttestationRepository {
+	return &AttestationRepository{
+		campaigns:    make(map[string]models.AttestationCampaign),
+		attestations: make(map[string]models.Attestation),
+	}
+}
+
+// CreateCampaign stores a campaign and its attestations, assigning IDs to
+// any without one. Nothing is stored when any of them conflicts.
+func (r *AttestationRepository) CreateCampaign(_ context.Context, c *models.AttestationCampaign, attestations []models.Attestation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c.ID == "" {
+		c.ID = uuid.NewString()
+	}
+	if _, ok := r.campaigns[c.ID]; ok {
+		return fmt.Errorf("creating attestation campaign: %w", repository.ErrConflict)
+	}
+	now := time.Now().UTC()
+	controls := make(map[string]bool, len(attestations))
+	for i := range attestations {
+		a := &attestations[i]
+		if a.ID == "" {
+			a.ID = uuid.NewString()
+		}
+		key := a.FrameworkID + "\x00" + a.ControlID
+		if _, ok := r.attestations[a.ID]; ok || controls[key] {
+			return fmt.Errorf("creating attestation for %s: %w", a.ControlID, repository.ErrConflict)
+		}
+		controls[key] = true
+		a.CampaignID, a.CreatedAt, a.UpdatedAt = c.ID, now, now
+	}
+
+	c.CreatedAt = now
+	r.campaigns[c.ID] = *c
+	for _, a := range attestations {
+		r.attestations[a.ID] = a
+	}
+	return nil
+}
+
+// ListCampaigns returns an organization's campaigns, newest first.
+func (r *AttestationRepository) ListCampaigns(_ context.Context, orgID string) ([]models.AttestationCampaign, error) {
+	return r.listCampaigns(func(c models.AttestationCampaign) bool { return c.OrganizationID == orgID }, func(a, b models.AttestationCampaign) bool {
+		return a.CreatedAt.After(b.CreatedAt)
+	}), nil
+}
+
+// ListOpenCampaigns returns every organization's open campaigns, oldest
+// due date first.
+func (r *AttestationRepository) ListOpenCampaigns(_ context.Context) ([]models.AttestationCampaign, error) {
+	return r.listCampaigns(func(c models.AttestationCampaign) bool { return c.Status == models.CampaignOpen }, func(a, b models.AttestationCampaign) bool {
+		return a.DueDate.Before(b.DueDate)
+	}), nil
+}
+
+func (r *AttestationRepository) listCampaigns(keep func(models.AttestationCampaign) bool, less func(a, b models.AttestationCampaign) bool) []models.AttestationCampaign {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []models.AttestationCampaign
+	for _, c := range r.campaigns {
+		if keep(c) {
+			list = append(list, c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if less(list[i], list[j]) != less(list[j], list[i]) {
+			return less(list[i], list[j])
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// GetCampaign returns a campaign, or nil if there is none.
+func (r *AttestationRepository) GetCampaign(_ context.Context, id string) (*models.AttestationCampaign, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.campaigns[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+// UpdateCampaign replaces a campaign's name, status, due date, reminder
+// interval and closing time.
+func (r *AttestationRepository) UpdateCampaign(_ context.Context, c *models.AttestationCampaign) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.campaigns[c.ID]
+	if !ok {
+		return fmt.Errorf("attestation campaign %s: %w", c.ID, repository.ErrNotFound)
+	}
+	existing.Name, existing.Status, existing.DueDate = c.Name, c.Status, c.DueDate
+	existing.ReminderIntervalDays, existing.ClosedAt = c.ReminderIntervalDays, c.ClosedAt
+	r.campaigns[c.ID] = existing
+	return nil
+}
+
+// ListAttestations returns a campaign's attestations ordered by framework
+// and control ID.
+func (r *AttestationRepository) ListAttestations(_ context.Context, campaignID string) ([]models.Attestation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []models.Attestation
+	for _, a := range r.attestations {
+		if a.CampaignID == campaignID {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].FrameworkID != list[j].FrameworkID {
+			return list[i].FrameworkID < list[j].FrameworkID
+		}
+		return list[i].ControlID < list[j].ControlID
+	})
+	return list, nil
+}
+
+// GetAttestation returns an attestation, or nil if there is none.
+func (r *AttestationRepository) GetAttestation(_ context.Context, id string) (*models.Attestation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.attestations[id]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// UpdateAttestation replaces an attestation's owner, response and reminder
+// state.
+func (r *AttestationRepository) UpdateAttestation(_ context.Context, a *models.Attestation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.attestations[a.ID]
+	if !ok {
+		return fmt.Errorf("attestation %s: %w", a.ID, repository.ErrNotFound)
+	}
+	existing.Owner, existing.Status, existing.Comment = a.Owner, a.Status, a.Comment
+	existing.RespondedBy, existing.RespondedAt = a.RespondedBy, a.RespondedAt
+	existing.Reminders, existing.LastRemindedAt = a.Reminders, a.LastRemindedAt
+	existing.UpdatedAt = time.Now().UTC()
+	r.attestations[a.ID] = existing
+	a.UpdatedAt = existing.UpdatedAt
+	return nil
+}
@@ -0,0 +1,361 @@
+// Package memory implements the repository interfaces in process memory,
+// for development mode and tests. Nothing is persisted; data is lost when
+// the process exits.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ControlRepository implements repository.ControlRepository,
+// repository.CrosswalkReviewRepository and repository.ControlTagRepository
+// in memory.
+type ControlRepository struct {
+	mu         sync.RWMutex
+	frameworks map[string]models.Framework
+	controls   map[string]models.Control // by ID
+	crosswalks map[string]models.Crosswalk
+	tags       map[[3]string]models.ControlTags // by org, framework and lower-cased control ID
+}
+
+// NewControlRepository creates an empty ControlRepository.
+func NewControlRepository() *ControlRepository {
+	return &ControlRepository{
+		frameworks: make(map[string]models.Framework),
+		controls:   make(map[string]models.Control),
+		crosswalks: make(map[string]models.Crosswalk),
+		tags:       make(map[[3]string]models.ControlTags),
+	}
+}
+
+// ListFrameworks returns all frameworks ordered by name and version.
+func (r *ControlRepository) ListFrameworks(_ context.Context) ([]models.Framework, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	frameworks := make([]models.Framework, 0, len(r.frameworks))
+	for _, f := range r.frameworks {
+		frameworks = append(frameworks, f)
+	}
+	sort.Slice(frameworks, func(i, j int) bool {
+		if frameworks[i].Name != frameworks[j].Name {
+			return frameworks[i].Name < frameworks[j].Name
+		}
+		return frameworks[i].Version < frameworks[j].Version
+	})
+	return frameworks, nil
+}
+
+// GetFramework returns a framework, or nil if there is none.
+func (r *ControlRepository) GetFramework(_ context.Context, id string) (*models.Framework, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.frameworks[id]
+	if !ok {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// CreateFramework creates a new framework.
+func (r *ControlRepository) CreateFramework(_ context.Context, f *models.Framework) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.createFramework(f)
+}
+
+func (r *ControlRepository) createFramework(f *models.Framework) error {
+	if _, ok := r.frameworks[f.ID]; ok {
+		return fmt.Errorf("creating framework: %w", repository.ErrConflict)
+	}
+	f.CreatedAt = time.Now().UTC()
+	f.UpdatedAt = f.CreatedAt
+	r.frameworks[f.ID] = *f
+	return nil
+}
+
+// UpdateFramework updates an existing framework.
+func (r *ControlRepository) UpdateFramework(_ context.Context, f *models.Framework) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.frameworks[f.ID]
+	if !ok {
+		return fmt.Errorf("framework %s: %w", f.ID, repository.ErrNotFound)
+	}
+	f.CreatedAt = existing.CreatedAt
+	f.UpdatedAt = time.Now().UTC()
+	r.frameworks[f.ID] = *f
+	return nil
+}
+
+// DeleteFramework deletes a framework. Like the database's foreign keys,
+// it refuses while controls still belong to the framework.
+func (r *ControlRepository) DeleteFramework(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.frameworks[id]; !ok {
+		return fmt.Errorf("framework %s: %w", id, repository.ErrNotFound)
+	}
+	for _, c := range r.controls {
+		if c.FrameworkID == id {
+			return fmt.Errorf("deleting framework: %w", repository.ErrForeignKey)
+		}
+	}
+	delete(r.frameworks, id)
+	return nil
+}
+
+// ListControls returns a framework's controls ordered by control ID.
+func (r *ControlRepository) ListControls(_ context.Context, frameworkID string) ([]models.Control, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var controls []models.Control
+	for _, c := range r.controls {
+		if c.FrameworkID == frameworkID {
+			controls = append(controls, c)
+		}
+	}
+	sort.Slice(controls, func(i, j int) bool { return controls[i].ControlID < controls[j].ControlID })
+	return controls, nil
+}
+
+// GetControl returns a control by ID, or nil if there is none.
+func (r *ControlRepository) GetControl(_ context.Context, id string) (*models.Control, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.controls[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+// CreateControl creates a new control, assigning an ID when it has none.
+func (r *ControlRepository) CreateControl(_ context.Context, c *models.Control) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.createControl(c)
+}
+
+func (r *ControlRepository) createControl(c *models.Control) error {
+	if _, ok := r.frameworks[c.FrameworkID]; !ok {
+		return fmt.Errorf("creating control: %w", repository.ErrForeignKey)
+	}
+	if c.ID == "" {
+		c.ID = uuid.NewString()
+	}
+	if _, ok := r.controls[c.ID]; ok {
+		return fmt.Errorf("creating control: %w", repository.ErrConflict)
+	}
+	for _, existing := range r.controls {
+		if existing.FrameworkID == c.FrameworkID && existing.ControlID == c.ControlID {
+			return fmt.Errorf("creating control: %w", repository.ErrConflict)
+		}
+	}
+	r.controls[c.ID] = *c
+	return nil
+}
+
+// UpdateControl updates an existing control.
+func (r *ControlRepository) UpdateControl(_ context.Context, c *models.Control) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.controls[c.ID]; !ok {
+		return fmt.Errorf("control %s: %w", c.ID, repository.ErrNotFound)
+	}
+	r.controls[c.ID] = *c
+	return nil
+}
+
+// DeleteControl deletes a control by ID.
+func (r *ControlRepository) DeleteControl(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.controls[id]; !ok {
+		return fmt.Errorf("control %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.controls, id)
+	return nil
+}
+
+// ImportCatalog creates a framework with its controls and crosswalks. A
+// failure leaves the repository unchanged. Importing a different version of
+// an existing framework replaces its controls; superseded versions are not
+// kept. Re-importing the same version is a conflict.
+func (r *ControlRepository) ImportCatalog(_ context.Context, catalog *repository.CatalogImport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Work on copies so a failed import can be discarded.
+	frameworks, controls, crosswalks := r.frameworks, r.controls, r.crosswalks
+	r.frameworks = make(map[string]models.Framework, len(frameworks)+1)
+	for k, v := range frameworks {
+		r.frameworks[k] = v
+	}
+	r.controls = make(map[string]models.Control, len(controls)+len(catalog.Controls))
+	for k, v := range controls {
+		r.controls[k] = v
+	}
+	r.crosswalks = make(map[string]models.Crosswalk, len(crosswalks)+len(catalog.Crosswalks))
+	for k, v := range crosswalks {
+		r.crosswalks[k] = v
+	}
+	if err := r.importCatalog(catalog); err != nil {
+		r.frameworks, r.controls, r.crosswalks = frameworks, controls, crosswalks
+		return err
+	}
+	return nil
+}
+
+func (r *ControlRepository) importCatalog(catalog *repository.CatalogImport) error {
+	f := catalog.Framework
+	if existing, ok := r.frameworks[f.ID]; ok && existing.Version != f.Version {
+		for id, c := range r.controls {
+			if c.FrameworkID == f.ID {
+				delete(r.controls, id)
+			}
+		}
+		f.CreatedAt = existing.CreatedAt
+		f.UpdatedAt = time.Now().UTC()
+		r.frameworks[f.ID] = f
+	} else if err := r.createFramework(&f); err != nil {
+		return err
+	}
+	for i := range catalog.Controls {
+		if err := r.createControl(&catalog.Controls[i]); err != nil {
+			return fmt.Errorf("control %s: %w", catalog.Controls[i].ControlID, err)
+		}
+	}
+	for i := range catalog.Crosswalks {
+		cw := &catalog.Crosswalks[i]
+		if err := r.createCrosswalk(cw); err != nil {
+			return fmt.Errorf("crosswalk %s -> %s: %w", cw.SourceControlID, cw.TargetControlID, err)
+		}
+	}
+	return nil
+}
+
+// GetCrosswalk returns the crosswalks from one framework to another,
+// ordered by source control ID.
+func (r *ControlRepository) GetCrosswalk(_ context.Context, sourceFrameworkID, targetFrameworkID string) ([]models.Crosswalk, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var crosswalks []models.Crosswalk
+	for _, cw := range r.crosswalks {
+		if cw.SourceFrameworkID == sourceFrameworkID && cw.TargetFrameworkID == targetFrameworkID {
+			crosswalks = append(crosswalks, cw)
+		}
+	}
+	sort.Slice(crosswalks, func(i, j int) bool {
+		if crosswalks[i].SourceControlID != crosswalks[j].SourceControlID {
+			return crosswalks[i].SourceControlID < crosswalks[j].SourceControlID
+		}
+		return crosswalks[i].ID < crosswalks[j].ID
+	})
+	return crosswalks, nil
+}
+
+// GetCrosswalkByID returns a crosswalk, or nil if there is none.
+func (r *ControlRepository) GetCrosswalkByID(_ context.Context, id string) (*models.Crosswalk, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cw, ok := r.crosswalks[id]
+	if !ok {
+		return nil, nil
+	}
+	return &cw, nil
+}
+
+// CreateCrosswalk creates a crosswalk, assigning an ID when it has none.
+// Crosswalks without a review state are approved.
+func (r *ControlRepository) CreateCrosswalk(_ context.Context, cw *models.Crosswalk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.createCrosswalk(cw)
+}
+
+func (r *ControlRepository) createCrosswalk(cw *models.Crosswalk) error {
+	if cw.ID == "" {
+		cw.ID = uuid.NewString()
+	}
+	if _, ok := r.crosswalks[cw.ID]; ok {
+		return fmt.Errorf("creating crosswalk: %w", repository.ErrConflict)
+	}
+	if cw.ReviewState == "" {
+		cw.ReviewState = models.ReviewApproved
+	}
+	cw.CreatedAt = time.Now().UTC()
+	cw.UpdatedAt = cw.CreatedAt
+	r.crosswalks[cw.ID] = *cw
+	return nil
+}
+
+// UpdateCrosswalk replaces a crosswalk's mapping and review fields.
+func (r *ControlRepository) UpdateCrosswalk(_ context.Context, cw *models.Crosswalk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.crosswalks[cw.ID]
+	if !ok {
+		return fmt.Errorf("crosswalk %s: %w", cw.ID, repository.ErrNotFound)
+	}
+	cw.CreatedAt = existing.CreatedAt
+	cw.UpdatedAt = time.Now().UTC()
+	r.crosswalks[cw.ID] = *cw
+	return nil
+}
+
+// DeleteCrosswalk deletes a crosswalk by ID.
+func (r *ControlRepository) DeleteCrosswalk(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.crosswalks[id]; !ok {
+		return fmt.Errorf("crosswalk %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.crosswalks, id)
+	return nil
+}
+
+// ListControlTags returns an organization's tagged controls ordered by
+// framework and control ID, of one framework unless frameworkID is empty.
+func (r *ControlRepository) ListControlTags(_ context.Context, orgID, frameworkID string) ([]models.ControlTags, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []models.ControlTags
+	for key, t := range r.tags {
+		if key[0] == orgID && (frameworkID == "" || key[1] == frameworkID) {
+			t.Tags = append([]string(nil), t.Tags...)
+			list = append(list, t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].FrameworkID != list[j].FrameworkID {
+			return list[i].FrameworkID < list[j].FrameworkID
+		}
+		return list[i].ControlID < list[j].ControlID
+	})
+	return list, nil
+}
+
+// SetControlTags replaces a control's tags. Setting no tags removes them.
+func (r *ControlRepository) SetControlTags(_ context.Context, t *models.ControlTags) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := [3]string{t.OrganizationID, t.FrameworkID, strings.ToLower(t.ControlID)}
+	if len(t.Tags) == 0 {
+		delete(r.tags, key)
+		return nil
+	}
+	t.UpdatedAt = time.Now().UTC()
+	stored := *t
+	stored.Tags = append([]string(nil), t.Tags...)
+	r.tags[key] = stored
+	return nil
+}
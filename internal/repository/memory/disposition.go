@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// GapDispositionRepository implements repository.GapDispositionRepository
+// in memory.
+type GapDispositionRepository struct {
+	mu           sync.RWMutex
+	dispositions map[string]models.GapDisposition
+}
+
+// NewGapDispositionRepository creates an empty GapDispositionRepository.
+func NewGapDispositionRepository() *GapDispositionRepository {
+	return &GapDispositionRepository{dispositions: make(map[string]models.GapDisposition)}
+}
+
+// List returns an organization's dispositions ordered by framework and
+// control ID. An empty frameworkID lists every framework.
+func (r *GapDispositionRepository) List(_ context.Context, orgID, frameworkID string) ([]models.GapDisposition, error) {
+	return r.list(func(d *models.GapDisposition) bool {
+		return d.OrganizationID == orgID && (frameworkID == "" || d.FrameworkID == frameworkID)
+	}), nil
+}
+
+// ListRiskAcceptances returns every organization's accept and transfer
+// dispositions.
+func (r *GapDispositionRepository) ListRiskAcceptances(_ context.Context) ([]models.GapDisposition, error) {
+	return r.list(func(d *models.GapDisposition) bool {
+		return d.Disposition == models.DispositionAccept || d.Disposition == models.DispositionTransfer
+	}), nil
+}
+
+func (r *GapDispositionRepository) list(keep func(*models.GapDisposition) bool) []models.GapDisposition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []models.GapDisposition
+	for _, d := range r.dispositions {
+		if keep(&d) {
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].OrganizationID != list[j].OrganizationID {
+			return list[i].OrganizationID < list[j].OrganizationID
+		}
+		if list[i].FrameworkID != list[j].FrameworkID {
+			return list[i].FrameworkID < list[j].FrameworkID
+		}
+		return list[i].ControlID < list[j].ControlID
+	})
+	return list
+}
+
+// Get returns a disposition, or nil if there is none.
+func (r *GapDispositionRepository) Get(_ context.Context, id string) (*models.GapDisposition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.dispositions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+// Create stores a disposition, assigning an ID when it has none.
+func (r *GapDispositionRepository) Create(_ context.Context, d *models.GapDisposition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d.ID == "" {
+		d.ID = uuid.NewString()
+	}
+	if _, ok := r.dispositions[d.ID]; ok {
+		return fmt.Errorf("creating gap disposition: %w", repository.ErrConflict)
+	}
+	for _, existing := range r.dispositions {
+		if existing.OrganizationID == d.OrganizationID && existing.FrameworkID == d.FrameworkID &&
+			strings.EqualFold(existing.ControlID, d.ControlID) {
+			return fmt.Errorf("creating gap disposition: %w", repository.ErrConflict)
+		}
+	}
+	d.CreatedAt = time.Now().UTC()
+	d.UpdatedAt = d.CreatedAt
+	r.dispositions[d.ID] = *d
+	return nil
+}
+
+// Update replaces a disposition's decision, approval, expiry, review and
+// reminder fields.
+func (r *GapDispositionRepository) Update(_ context.Context, d *models.GapDisposition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.dispositions[d.ID]
+	if !ok {
+		return fmt.Errorf("gap disposition %s: %w", d.ID, repository.ErrNotFound)
+	}
+	updated := *d
+	updated.OrganizationID, updated.FrameworkID, updated.ControlID = existing.OrganizationID, existing.FrameworkID, existing.ControlID
+	updated.CreatedBy, updated.CreatedAt = existing.CreatedBy, existing.CreatedAt
+	updated.UpdatedAt = time.Now().UTC()
+	r.dispositions[d.ID] = updated
+	d.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+// Delete removes a disposition.
+func (r *GapDispositionRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.dispositions[id]; !ok {
+		return fmt.Errorf("gap disposition %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.dispositions, id)
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// GapAnalysisRepository implements repository.GapAnalysisRepository in
+// memory.
+type GapAnalysisRepository struct {
+	mu       sync.RWMutex
+	analyses map[string]models.GapAnalysis
+}
+
+// NewGapAnalysisRepository creates an empty GapAnalysisRepository.
+func NewGapAnalysisRepository() *GapAnalysisRepository {
+	return &GapAnalysisRepository{analyses: make(map[string]models.GapAnalysis)}
+}
+
+// List returns an organization's gap analyses, newest first.
+func (r *GapAnalysisRepository) List(_ context.Context, orgID string) ([]models.GapAnalysis, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var analyses []models.GapAnalysis
+	for _, ga := range r.analyses {
+		if ga.OrganizationID == orgID {
+			analyses = append(analyses, ga)
+		}
+	}
+	sort.Slice(analyses, func(i, j int) bool {
+		if !analyses[i].AnalysisDate.Equal(analyses[j].AnalysisDate) {
+			return analyses[i].AnalysisDate.After(analyses[j].AnalysisDate)
+		}
+		return analyses[i].ID < analyses[j].ID
+	})
+	return analyses, nil
+}
+
+// Get returns a gap analysis, or nil if there is none.
+func (r *GapAnalysisRepository) Get(_ context.Context, id string) (*models.GapAnalysis, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ga, ok := r.analyses[id]
+	if !ok {
+		return nil, nil
+	}
+	return &ga, nil
+}
+
+// Create stores a gap analysis, assigning an ID when it has none.
+func (r *GapAnalysisRepository) Create(_ context.Context, ga *models.GapAnalysis) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ga.ID == "" {
+		ga.ID = uuid.NewString()
+	}
+	if _, ok := r.analyses[ga.ID]; ok {
+		return fmt.Errorf("creating gap analysis: %w", repository.ErrConflict)
+	}
+	r.analyses[ga.ID] = *ga
+	return nil
+}
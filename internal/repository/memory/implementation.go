@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ControlImplementationRepository implements
+// repository.ControlImplementationRepository and
+// repository.ControlImplementationHistory in memory.
+type ControlImplementationRepository struct {
+	mu      sync.RWMutex
+	impls   map[string]models.ControlImplementation
+	history map[string][]implementationRevision
+}
+
+// implementationRevision is an implementation as it stood from validFrom
+// until its next revision. A nil impl records its deletion.
+type implementationRevision struct {
+	validFrom time.Time
+	impl      *models.ControlImplementation
+}
+
+// NewControlImplementationRepository creates an empty
+// ControlImplementationRepository.
+func NewControlImplementationRepository() *ControlImplementationRepository {
+	return &ControlImplementationRepository{
+		impls:   make(map[string]models.ControlImplementation),
+		history: make(map[string][]implementationRevision),
+	}
+}
+
+// List returns an organization's implementations ordered by framework and
+// control ID. An empty frameworkID lists every framework.
+func (r *ControlImplementationRepository) List(_ context.Context, orgID, frameworkID string) ([]models.ControlImplementation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var impls []models.ControlImplementation
+	for _, ci := range r.impls {
+		if ci.OrganizationID == orgID && (frameworkID == "" || ci.FrameworkID == frameworkID) {
+			impls = append(impls, ci)
+		}
+	}
+	sortImplementations(impls)
+	return impls, nil
+}
+
+// ListAsOf returns an organization's implementations as they stood at asOf,
+// ordered like List.
+func (r *ControlImplementationRepository) ListAsOf(_ context.Context, orgID, frameworkID string, asOf time.Time) ([]models.ControlImplementation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var impls []models.ControlImplementation
+	for _, revisions := range r.history {
+		var ci *models.ControlImplementation
+		for _, rev := range revisions {
+			if rev.validFrom.After(asOf) {
+				break
+			}
+			ci = rev.impl
+		}
+		if ci != nil && ci.OrganizationID == orgID && (frameworkID == "" || ci.FrameworkID == frameworkID) {
+			impls = append(impls, *ci)
+		}
+	}
+	sortImplementations(impls)
+	return impls, nil
+}
+
+func sortImplementations(impls []models.ControlImplementation) {
+	sort.Slice(impls, func(i, j int) bool {
+		if impls[i].FrameworkID != impls[j].FrameworkID {
+			return impls[i].FrameworkID < impls[j].FrameworkID
+		}
+		return impls[i].ControlID < impls[j].ControlID
+	})
+}
+
+// record appends a revision to an implementation's history; the caller
+// holds the write lock.
+func (r *ControlImplementationRepository) record(id string, at time.Time, ci *models.ControlImplementation) {
+	if ci != nil {
+		saved := *ci
+		ci = &saved
+	}
+	r.history[id] = append(r.history[id], implementationRevision{validFrom: at, impl: ci})
+}
+
+// Get returns a control implementation, or nil if there is none.
+func (r *ControlImplementationRepository) Get(_ context.Context, id string) (*models.ControlImplementation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ci, ok := r.impls[id]
+	if !ok {
+		return nil, nil
+	}
+	return &ci, nil
+}
+
+// Create stores a control implementation, assigning an ID when it has none.
+func (r *ControlImplementationRepository) Create(_ context.Context, ci *models.ControlImplementation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ci.ID == "" {
+		ci.ID = uuid.NewString()
+	}
+	if _, ok := r.impls[ci.ID]; ok {
+		return fmt.Errorf("creating control implementation: %w", repository.ErrConflict)
+	}
+	for _, existing := range r.impls {
+		if existing.OrganizationID == ci.OrganizationID && existing.FrameworkID == ci.FrameworkID && existing.ControlID == ci.ControlID {
+			return fmt.Errorf("creating control implementation: %w", repository.ErrConflict)
+		}
+	}
+	ci.CreatedAt = time.Now().UTC()
+	ci.UpdatedAt = ci.CreatedAt
+	r.impls[ci.ID] = *ci
+	r.record(ci.ID, ci.CreatedAt, ci)
+	return nil
+}
+
+// Update replaces a control implementation's status, owner, due date and
+// notes.
+func (r *ControlImplementationRepository) Update(_ context.Context, ci *models.ControlImplementation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.impls[ci.ID]
+	if !ok {
+		return fmt.Errorf("control implementation %s: %w", ci.ID, repository.ErrNotFound)
+	}
+	existing.Status, existing.Owner, existing.DueDate, existing.Notes = ci.Status, ci.Owner, ci.DueDate, ci.Notes
+	existing.UpdatedAt = time.Now().UTC()
+	r.impls[ci.ID] = existing
+	r.record(ci.ID, existing.UpdatedAt, &existing)
+	ci.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+// Delete removes a control implementation.
+func (r *ControlImplementationRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.impls[id]; !ok {
+		return fmt.Errorf("control implementation %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.impls, id)
+	r.record(id, time.Now().UTC(), nil)
+	return nil
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// sortList orders items, already in their list's default order, by the
+// field s names. fields compares two items by each sortable field; the
+// sort is stable, so ties keep the default order. A field without a
+// comparison leaves items as they are.
+func sortList[T any](items []T, s repository.Sort, fields map[string]func(a, b *T) int) {
+	cmp, ok := fields[s.Field]
+	if !ok {
+		return
+	}
+	slices.SortStableFunc(items, func(a, b T) int {
+		if s.Desc {
+			return cmp(&b, &a)
+		}
+		return cmp(&a, &b)
+	})
+}
+
+// pageList returns the page of items starting at offset, at most limit long
+// when limit is positive.
+func pageList[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,538 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/internal/repository/memory"
+)
+
+var (
+	_ repository.ControlRepository               = (*memory.ControlRepository)(nil)
+	_ repository.CrosswalkReviewRepository       = (*memory.ControlRepository)(nil)
+	_ repository.ControlTagRepository            = (*memory.ControlRepository)(nil)
+	_ repository.GapAnalysisRepository           = (*memory.GapAnalysisRepository)(nil)
+	_ repository.ControlImplementationRepository = (*memory.ControlImplementationRepository)(nil)
+	_ repository.ControlImplementationHistory    = (*memory.ControlImplementationRepository)(nil)
+	_ repository.AttestationRepository           = (*memory.AttestationRepository)(nil)
+	_ repository.TraceWriter                     = (*memory.TraceStore)(nil)
+	_ repository.TraceReader                     = (*memory.TraceStore)(nil)
+	_ repository.TraceLister                     = (*memory.TraceStore)(nil)
+	_ repository.SignalWriter                    = (*memory.TraceStore)(nil)
+	_ repository.SignalLister                    = (*memory.TraceStore)(nil)
+	_ repository.AgentActivityReader             = (*memory.TraceStore)(nil)
+	_ repository.AgentRepository                 = (*memory.AgentRepository)(nil)
+	_ repository.AgentHistory                    = (*memory.AgentRepository)(nil)
+	_ repository.ThreatModelRepository           = (*memory.ThreatModelRepository)(nil)
+)
+
+func TestControlRepositoryImportCatalog(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewControlRepository()
+
+	catalog := &repository.CatalogImport{
+		Framework: models.Framework{ID: "acme", Name: "ACME", Version: "1"},
+		Controls: []models.Control{
+			{FrameworkID: "acme", ControlID: "AC-2", Title: "Accounts"},
+			{FrameworkID: "acme", ControlID: "AC-1", Title: "Policy"},
+		},
+		Crosswalks: []models.Crosswalk{
+			{SourceFrameworkID: "acme", SourceControlID: "AC-1", TargetFrameworkID: "other", TargetControlID: "X-1"},
+		},
+	}
+	if err := repo.ImportCatalog(ctx, catalog); err != nil {
+		t.Fatal(err)
+	}
+	controls, _ := repo.ListControls(ctx, "acme")
+	if len(controls) != 2 || controls[0].ControlID != "AC-1" || controls[0].ID == "" {
+		t.Fatalf("controls = %+v", controls)
+	}
+	cws, _ := repo.GetCrosswalk(ctx, "acme", "other")
+	if len(cws) != 1 || cws[0].ReviewState != models.ReviewApproved {
+		t.Fatalf("crosswalks = %+v", cws)
+	}
+
+	// The same version again conflicts and leaves the catalog unchanged.
+	if err := repo.ImportCatalog(ctx, catalog); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("re-import err = %v, want ErrConflict", err)
+	}
+	// A failed import is rolled back.
+	bad := &repository.CatalogImport{
+		Framework: models.Framework{ID: "broken", Name: "Broken"},
+		Controls: []models.Control{
+			{FrameworkID: "broken", ControlID: "B-1"},
+			{FrameworkID: "broken", ControlID: "B-1"},
+		},
+	}
+	if err := repo.ImportCatalog(ctx, bad); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("duplicate control err = %v, want ErrConflict", err)
+	}
+	if f, _ := repo.GetFramework(ctx, "broken"); f != nil {
+		t.Error("failed import left its framework behind")
+	}
+
+	// A new version replaces the controls.
+	next := &repository.CatalogImport{
+		Framework: models.Framework{ID: "acme", Name: "ACME", Version: "2"},
+		Controls:  []models.Control{{FrameworkID: "acme", ControlID: "AC-9"}},
+	}
+	if err := repo.ImportCatalog(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+	controls, _ = repo.ListControls(ctx, "acme")
+	if len(controls) != 1 || controls[0].ControlID != "AC-9" {
+		t.Fatalf("controls after upgrade = %+v", controls)
+	}
+
+	if err := repo.DeleteFramework(ctx, "acme"); !errors.Is(err, repository.ErrForeignKey) {
+		t.Errorf("delete with controls err = %v, want ErrForeignKey", err)
+	}
+	if err := repo.DeleteControl(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("delete missing err = %v, want ErrNotFound", err)
+	}
+	if c, err := repo.GetControl(ctx, "missing"); c != nil || err != nil {
+		t.Errorf("get missing = %v, %v", c, err)
+	}
+}
+
+func TestControlTags(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewControlRepository()
+	for _, ct := range []*models.ControlTags{
+		{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC6.1", Tags: []string{"agent-runtime"}},
+		{OrganizationID: "org-1", FrameworkID: "nist-800-53", ControlID: "AC-2", Tags: []string{"agent-runtime", "iam"}},
+		{OrganizationID: "org-2", FrameworkID: "soc2", ControlID: "CC6.1", Tags: []string{"data-pipeline"}},
+	} {
+		if err := repo.SetControlTags(ctx, ct); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, _ := repo.ListControlTags(ctx, "org-1", "")
+	if len(all) != 2 || all[0].FrameworkID != "nist-800-53" || all[1].Tags[0] != "agent-runtime" {
+		t.Errorf("org-1 tags = %+v", all)
+	}
+	if err := repo.SetControlTags(ctx, &models.ControlTags{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "cc6.1", Tags: []string{"iam"}}); err != nil {
+		t.Fatal(err)
+	}
+	soc2, _ := repo.ListControlTags(ctx, "org-1", "soc2")
+	if len(soc2) != 1 || soc2[0].ControlID != "cc6.1" || len(soc2[0].Tags) != 1 || soc2[0].Tags[0] != "iam" {
+		t.Errorf("retagged control = %+v, want its tags replaced", soc2)
+	}
+
+	if err := repo.SetControlTags(ctx, &models.ControlTags{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC6.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if soc2, _ = repo.ListControlTags(ctx, "org-1", "soc2"); len(soc2) != 0 {
+		t.Errorf("untagged control still listed: %+v", soc2)
+	}
+	if other, _ := repo.ListControlTags(ctx, "org-2", ""); len(other) != 1 || other[0].Tags[0] != "data-pipeline" {
+		t.Errorf("org-2 tags = %+v, want them untouched", other)
+	}
+}
+
+func TestGapAnalysisRepositoryNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewGapAnalysisRepository()
+	now := time.Now()
+	for i, id := range []string{"old", "new"} {
+		ga := &models.GapAnalysis{ID: id, OrganizationID: "org-1", AnalysisDate: now.Add(time.Duration(i) * time.Hour)}
+		if err := repo.Create(ctx, ga); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = repo.Create(ctx, &models.GapAnalysis{OrganizationID: "org-2", AnalysisDate: now})
+
+	list, _ := repo.List(ctx, "org-1")
+	if len(list) != 2 || list[0].ID != "new" {
+		t.Fatalf("list = %+v", list)
+	}
+	if err := repo.Create(ctx, &models.GapAnalysis{ID: "old"}); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("duplicate err = %v, want ErrConflict", err)
+	}
+}
+
+func TestTraceStoreEvictsOldest(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewTraceStore(2)
+	for _, id := range []string{"t1", "t2", "t3"} {
+		if err := store.InsertTrace(ctx, "org-1", &models.AgentTrace{TraceID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.GetTrace(ctx, "org-1", "t1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("evicted trace err = %v, want ErrNotFound", err)
+	}
+	if tr, err := store.GetTrace(ctx, "org-1", "t3"); err != nil || tr.TraceID != "t3" {
+		t.Errorf("get t3 = %v, %v", tr, err)
+	}
+	if _, err := store.GetTrace(ctx, "org-2", "t3"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("other org err = %v, want ErrNotFound", err)
+	}
+
+	sigs := []models.SecuritySignal{{ID: "s1"}, {ID: "s2"}, {ID: "s3"}}
+	_ = store.InsertSignals(ctx, "org-1", "agent-1", sigs)
+	got := store.Signals()
+	if len(got) != 2 || got[0].Signal.ID != "s2" || got[1].AgentID != "agent-1" {
+		t.Errorf("signals = %+v", got)
+	}
+}
+
+func TestTraceStoreListTraces(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewTraceStore(0)
+	now := time.Now().UTC()
+	failed := models.TraceStatusFailed
+	for i, tr := range []models.AgentTrace{
+		{TraceID: "t1", SessionID: "s1", Status: models.TraceStatusCompleted, StartTime: now.Add(-2 * time.Hour)},
+		{TraceID: "t2", SessionID: "s1", Status: failed, StartTime: now.Add(-time.Hour),
+			Spans: []models.Span{{SpanID: "a"}}, Metrics: models.TraceMetrics{TotalSpans: 1}},
+		{TraceID: "t3", SessionID: "s2", Status: failed, StartTime: now},
+	} {
+		org := "org-1"
+		if i == 2 {
+			org = "org-2"
+		}
+		if err := store.InsertTrace(ctx, org, &tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.ListTraces(ctx, "org-1", nil)
+	if err != nil || len(got) != 2 || got[0].TraceID != "t2" || got[1].TraceID != "t1" {
+		t.Fatalf("ListTraces() = %+v, %v; want t2, t1", got, err)
+	}
+	if got[0].Spans != nil || got[0].Metrics.TotalSpans != 1 {
+		t.Errorf("listed trace = %+v, want metrics without spans", got[0])
+	}
+	got, _ = store.ListTraces(ctx, "org-1", &repository.TraceFilters{Status: &failed})
+	if len(got) != 1 || got[0].TraceID != "t2" {
+		t.Errorf("failed traces = %+v, want t2", got)
+	}
+	got, _ = store.ListTraces(ctx, "org-1", &repository.TraceFilters{Offset: 1, Limit: 5})
+	if len(got) != 1 || got[0].TraceID != "t1" {
+		t.Errorf("second page = %+v, want t1", got)
+	}
+	got, _ = store.ListTraces(ctx, "org-1", &repository.TraceFilters{Sort: repository.Sort{Field: "start_time"}})
+	if len(got) != 2 || got[0].TraceID != "t1" {
+		t.Errorf("oldest first = %+v, want t1, t2", got)
+	}
+	if n, err := store.CountTraces(ctx, "org-1", &repository.TraceFilters{Status: &failed}); err != nil || n != 1 {
+		t.Errorf("CountTraces(failed) = %d, %v; want 1", n, err)
+	}
+}
+
+func TestTraceStoreListSignals(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewTraceStore(0)
+	now := time.Now().UTC()
+	tr := &models.AgentTrace{TraceID: "t1", StartTime: now, SecuritySignals: []models.SecuritySignal{
+		{ID: "s1", TraceID: "t1", Severity: "critical", Timestamp: now.Add(-time.Hour)},
+		{ID: "s2", TraceID: "t1", Severity: "low", Timestamp: now},
+	}}
+	if err := store.InsertTrace(ctx, "org-1", tr); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.InsertSignals(ctx, "org-1", "agent", []models.SecuritySignal{{ID: "s3", Severity: "medium", Timestamp: now.Add(-2 * time.Hour)}})
+	_ = store.InsertSignals(ctx, "org-2", "agent", []models.SecuritySignal{{ID: "s4", Severity: "high", Timestamp: now}})
+
+	got, err := store.ListSignals(ctx, "org-1", nil)
+	if err != nil || len(got) != 3 || got[0].ID != "s2" || got[2].ID != "s3" {
+		t.Fatalf("ListSignals() = %+v, %v; want s2, s1, s3", got, err)
+	}
+	got, _ = store.ListSignals(ctx, "org-1", &repository.SignalFilters{Sort: repository.Sort{Field: "severity", Desc: true}, Limit: 2})
+	if len(got) != 2 || got[0].ID != "s1" || got[1].ID != "s3" {
+		t.Errorf("most severe = %+v, want s1, s3", got)
+	}
+	trace := "t1"
+	if n, err := store.CountSignals(ctx, "org-1", &repository.SignalFilters{TraceID: &trace}); err != nil || n != 2 {
+		t.Errorf("CountSignals(t1) = %d, %v; want 2", n, err)
+	}
+}
+
+func TestControlImplementationRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewControlImplementationRepository()
+	ci := &models.ControlImplementation{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC1.1", Status: models.ImplementationPlanned}
+	if err := repo.Create(ctx, ci); err != nil {
+		t.Fatal(err)
+	}
+	_ = repo.Create(ctx, &models.ControlImplementation{OrganizationID: "org-1", FrameworkID: "iso-42001", ControlID: "A.2"})
+	dup := &models.ControlImplementation{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC1.1"}
+	if err := repo.Create(ctx, dup); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("duplicate err = %v, want ErrConflict", err)
+	}
+
+	ci.Status, ci.Owner = models.ImplementationImplemented, "alice"
+	if err := repo.Update(ctx, ci); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := repo.Get(ctx, ci.ID)
+	if got == nil || got.Status != models.ImplementationImplemented || got.Owner != "alice" {
+		t.Errorf("after update = %+v", got)
+	}
+
+	all, _ := repo.List(ctx, "org-1", "")
+	soc2, _ := repo.List(ctx, "org-1", "soc2")
+	other, _ := repo.List(ctx, "org-2", "")
+	if len(all) != 2 || all[0].FrameworkID != "iso-42001" || len(soc2) != 1 || len(other) != 0 {
+		t.Errorf("list = %v / %v / %v", all, soc2, other)
+	}
+
+	if err := repo.Delete(ctx, ci.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(ctx, ci); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("update deleted err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestControlImplementationHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewControlImplementationRepository()
+	before := time.Now()
+	ci := &models.ControlImplementation{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC1.1", Status: models.ImplementationPlanned}
+	if err := repo.Create(ctx, ci); err != nil {
+		t.Fatal(err)
+	}
+	planned := time.Now()
+	ci.Status = models.ImplementationImplemented
+	if err := repo.Update(ctx, ci); err != nil {
+		t.Fatal(err)
+	}
+	implemented := time.Now()
+	if err := repo.Delete(ctx, ci.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		asOf time.Time
+		want models.ImplementationStatus
+	}{
+		{"before create", before, ""},
+		{"after create", planned, models.ImplementationPlanned},
+		{"after update", implemented, models.ImplementationImplemented},
+		{"after delete", time.Now(), ""},
+	} {
+		impls, err := repo.ListAsOf(ctx, "org-1", "soc2", tc.asOf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got models.ImplementationStatus
+		if len(impls) == 1 {
+			got = impls[0].Status
+		}
+		if len(impls) > 1 || got != tc.want {
+			t.Errorf("%s: ListAsOf = %+v, want status %q", tc.name, impls, tc.want)
+		}
+	}
+	if other, _ := repo.ListAsOf(ctx, "org-2", "", implemented); len(other) != 0 {
+		t.Errorf("ListAsOf(org-2) = %+v", other)
+	}
+}
+
+func TestAttestationRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewAttestationRepository()
+	due := time.Now().AddDate(0, 1, 0)
+	campaign := &models.AttestationCampaign{OrganizationID: "org-1", Name: "Q1", Status: models.CampaignOpen, DueDate: due}
+	atts := []models.Attestation{
+		{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC1.2", Owner: "bob", Status: models.AttestationPending},
+		{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC1.1", Owner: "alice", Status: models.AttestationPending},
+	}
+	if err := repo.CreateCampaign(ctx, campaign, atts); err != nil {
+		t.Fatal(err)
+	}
+	dup := []models.Attestation{{FrameworkID: "soc2", ControlID: "CC1.1"}, {FrameworkID: "soc2", ControlID: "CC1.1"}}
+	if err := repo.CreateCampaign(ctx, &models.AttestationCampaign{OrganizationID: "org-1"}, dup); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("duplicate control err = %v, want ErrConflict", err)
+	}
+	if list, _ := repo.ListCampaigns(ctx, "org-1"); len(list) != 1 {
+		t.Errorf("failed create left a campaign behind: %+v", list)
+	}
+
+	list, _ := repo.ListAttestations(ctx, campaign.ID)
+	if len(list) != 2 || list[0].ControlID != "CC1.1" || list[0].CampaignID != campaign.ID {
+		t.Fatalf("attestations = %+v", list)
+	}
+	a := list[0]
+	a.Status, a.Comment = models.AttestationAttested, "reviewed"
+	if err := repo.UpdateAttestation(ctx, &a); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := repo.GetAttestation(ctx, a.ID); got == nil || got.Status != models.AttestationAttested {
+		t.Errorf("after update = %+v", got)
+	}
+
+	campaign.Status = models.CampaignClosed
+	if err := repo.UpdateCampaign(ctx, campaign); err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := repo.ListOpenCampaigns(ctx); len(open) != 0 {
+		t.Errorf("open campaigns = %+v", open)
+	}
+	if err := repo.UpdateAttestation(ctx, &models.Attestation{ID: "missing"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("update missing err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGapDispositionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewGapDispositionRepository()
+	expires := time.Now().AddDate(0, 6, 0)
+	accept := &models.GapDisposition{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC6.1",
+		Disposition: models.DispositionAccept, Approver: "ciso", ExpiresAt: &expires, CreatedBy: "alice"}
+	remediate := &models.GapDisposition{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "CC1.1", Disposition: models.DispositionRemediate}
+	for _, d := range []*models.GapDisposition{accept, remediate} {
+		if err := repo.Create(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dup := &models.GapDisposition{OrganizationID: "org-1", FrameworkID: "soc2", ControlID: "cc6.1", Disposition: models.DispositionRemediate}
+	if err := repo.Create(ctx, dup); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("duplicate control err = %v, want ErrConflict", err)
+	}
+
+	if list, _ := repo.List(ctx, "org-1", "soc2"); len(list) != 2 || list[0].ControlID != "CC1.1" {
+		t.Errorf("list = %+v", list)
+	}
+	if list, _ := repo.List(ctx, "org-2", ""); len(list) != 0 {
+		t.Errorf("other organization's list = %+v", list)
+	}
+	if list, _ := repo.ListRiskAcceptances(ctx); len(list) != 1 || list[0].ID != accept.ID {
+		t.Errorf("risk acceptances = %+v", list)
+	}
+
+	update := *accept
+	update.ControlID, update.CreatedBy, update.Disposition = "CC9.9", "mallory", models.DispositionTransfer
+	if err := repo.Update(ctx, &update); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := repo.Get(ctx, accept.ID); got == nil || got.Disposition != models.DispositionTransfer ||
+		got.ControlID != "CC6.1" || got.CreatedBy != "alice" {
+		t.Errorf("after update = %+v", got)
+	}
+	if err := repo.Delete(ctx, accept.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := repo.Get(ctx, accept.ID); got != nil {
+		t.Errorf("after delete = %+v", got)
+	}
+	if err := repo.Update(ctx, &models.GapDisposition{ID: "missing"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("update missing err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestAgentRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewAgentRepository()
+	for _, a := range []models.Agent{
+		{Name: "charlie", Environment: "prod"},
+		{Name: "alpha", Environment: "prod"},
+		{Name: "bravo", Environment: "dev"},
+	} {
+		if err := repo.Create(ctx, &a); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	prod := "prod"
+	agents, err := repo.List(ctx, &repository.AgentFilters{Environment: &prod})
+	if err != nil || len(agents) != 2 || agents[0].Name != "alpha" {
+		t.Fatalf("List(prod) = %+v, %v", agents, err)
+	}
+	page, _ := repo.List(ctx, &repository.AgentFilters{Offset: 1, Limit: 1})
+	if len(page) != 1 || page[0].Name != "bravo" {
+		t.Errorf("List(offset 1, limit 1) = %+v", page)
+	}
+	if n, err := repo.Count(ctx, &repository.AgentFilters{Environment: &prod, Limit: 1}); err != nil || n != 2 {
+		t.Errorf("Count(prod) = %d, %v; want 2", n, err)
+	}
+	sorted, _ := repo.List(ctx, &repository.AgentFilters{Sort: repository.Sort{Field: "environment", Desc: true}})
+	if len(sorted) != 3 || sorted[0].Name != "alpha" || sorted[1].Name != "charlie" || sorted[2].Name != "bravo" {
+		t.Errorf("List(sort -environment) = %+v, want alpha, charlie, bravo", sorted)
+	}
+
+	id := agents[0].ID
+	if err := repo.BindPolicies(ctx, id, []string{"pol-1", "pol-2"}); err != nil {
+		t.Fatalf("BindPolicies: %v", err)
+	}
+	policies, err := repo.GetPolicies(ctx, id)
+	if err != nil || len(policies) != 2 || policies[0].ID != "pol-1" {
+		t.Errorf("GetPolicies = %+v, %v", policies, err)
+	}
+	pol := "pol-2"
+	if bound, _ := repo.List(ctx, &repository.AgentFilters{Policy: &pol}); len(bound) != 1 || bound[0].ID != id {
+		t.Errorf("List(policy pol-2) = %+v", bound)
+	}
+	bound := time.Now()
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if a, err := repo.GetAsOf(ctx, id, bound); err != nil || a == nil || len(a.Policies) != 2 {
+		t.Errorf("GetAsOf(bound) = %+v, %v", a, err)
+	}
+	if a, err := repo.GetAsOf(ctx, id, time.Now()); a != nil || err != nil {
+		t.Errorf("GetAsOf after delete = %+v, %v", a, err)
+	}
+	if a, err := repo.Get(ctx, id); a != nil || err != nil {
+		t.Errorf("Get after delete = %+v, %v", a, err)
+	}
+	if err := repo.Update(ctx, &models.Agent{ID: id}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update after delete err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPolicyRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPolicyRepository()
+	for _, p := range []models.Policy{
+		{ID: "low", Name: "low", Type: models.PolicyTypeToolAccess, Priority: 1, Enabled: true},
+		{ID: "high", Name: "high", Type: models.PolicyTypeToolAccess, Priority: 10},
+		{Name: "flow", Type: models.PolicyTypeDataFlow, Priority: 5, Enabled: true},
+	} {
+		if err := repo.Create(ctx, &p); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := repo.Create(ctx, &models.Policy{ID: "low"}); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("duplicate Create err = %v, want ErrConflict", err)
+	}
+
+	all, err := repo.List(ctx, nil)
+	if err != nil || len(all) != 3 || all[0].ID != "high" || all[2].ID != "low" {
+		t.Fatalf("List = %+v, %v", all, err)
+	}
+	if all[1].ID == "" {
+		t.Error("Create did not assign an ID")
+	}
+	enabled := true
+	if got, _ := repo.List(ctx, &repository.PolicyFilters{Enabled: &enabled, Offset: 1, Limit: 1}); len(got) != 1 || got[0].ID != "low" {
+		t.Errorf("List(enabled, offset 1, limit 1) = %+v", got)
+	}
+	if n, err := repo.Count(ctx, &repository.PolicyFilters{Enabled: &enabled}); err != nil || n != 2 {
+		t.Errorf("Count(enabled) = %d, %v; want 2", n, err)
+	}
+	if got, _ := repo.List(ctx, &repository.PolicyFilters{Sort: repository.Sort{Field: "name"}}); len(got) != 3 || got[0].Name != "flow" || got[2].Name != "low" {
+		t.Errorf("List(sort name) = %+v, want flow, high, low", got)
+	}
+	byType, err := repo.GetByType(ctx, models.PolicyTypeToolAccess)
+	if err != nil || len(byType) != 2 || byType[0].ID != "high" {
+		t.Errorf("GetByType(tool_access) = %+v, %v", byType, err)
+	}
+
+	if err := repo.Delete(ctx, "low"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if p, err := repo.Get(ctx, "low"); p != nil || err != nil {
+		t.Errorf("Get after delete = %+v, %v", p, err)
+	}
+	if err := repo.Update(ctx, &models.Policy{ID: "low"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update after delete err = %v, want ErrNotFound", err)
+	}
+}
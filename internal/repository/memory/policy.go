@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// PolicyRepository implements repository.PolicyRepository in memory.
+type PolicyRepository struct {
+	mu       sync.RWMutex
+	policies map[string]models.Policy
+}
+
+// NewPolicyRepository creates an empty PolicyRepository.
+func NewPolicyRepository() *PolicyRepository {
+	return &PolicyRepository{policies: make(map[string]models.Policy)}
+}
+
+// policySortFields compares policies by repository.PolicySortFields.
+var policySortFields = map[string]func(a, b *models.Policy) int{
+	"priority":   func(a, b *models.Policy) int { return cmp.Compare(a.Priority, b.Priority) },
+	"name":       func(a, b *models.Policy) int { return strings.Compare(a.Name, b.Name) },
+	"type":       func(a, b *models.Policy) int { return strings.Compare(string(a.Type), string(b.Type)) },
+	"created_at": func(a, b *models.Policy) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated_at": func(a, b *models.Policy) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+// List returns the policies matching filters, highest priority first unless
+// filters.Sort says otherwise.
+func (r *PolicyRepository) List(_ context.Context, filters *repository.PolicyFilters) ([]models.Policy, error) {
+	if filters == nil {
+		filters = &repository.PolicyFilters{}
+	}
+	policies := r.matching(policyFilter(filters))
+	sortList(policies, filters.Sort, policySortFields)
+	return pageList(policies, filters.Offset, filters.Limit), nil
+}
+
+// Count returns how many policies match filters.
+func (r *PolicyRepository) Count(_ context.Context, filters *repository.PolicyFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.PolicyFilters{}
+	}
+	return len(r.matching(policyFilter(filters))), nil
+}
+
+// policyFilter reports whether a policy matches filters.
+func policyFilter(filters *repository.PolicyFilters) func(*models.Policy) bool {
+	return func(p *models.Policy) bool {
+		return (filters.Type == nil || p.Type == *filters.Type) &&
+			(filters.Enabled == nil || p.Enabled == *filters.Enabled)
+	}
+}
+
+// GetByType returns every policy of a type, enabled or not, highest
+// priority first.
+func (r *PolicyRepository) GetByType(_ context.Context, policyType models.PolicyType) ([]models.Policy, error) {
+	return r.matching(func(p *models.Policy) bool { return p.Type == policyType }), nil
+}
+
+// matching returns the policies for which keep reports true, ordered by
+// descending priority, then name and ID.
+func (r *PolicyRepository) matching(keep func(*models.Policy) bool) []models.Policy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var policies []models.Policy
+	for _, p := range r.policies {
+		if keep(&p) {
+			policies = append(policies, p)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		a, b := policies[i], policies[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	return policies
+}
+
+// Get returns a policy, or nil if there is none.
+func (r *PolicyRepository) Get(_ context.Context, id string) (*models.Policy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.policies[id]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// Create stores a policy, assigning an ID when it has none.
+func (r *PolicyRepository) Create(_ context.Context, p *models.Policy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p.ID == "" {
+		p.ID = uuid.NewString()
+	}
+	if _, ok := r.policies[p.ID]; ok {
+		return fmt.Errorf("creating policy: %w", repository.ErrConflict)
+	}
+	p.CreatedAt = time.Now().UTC()
+	p.UpdatedAt = p.CreatedAt
+	r.policies[p.ID] = *p
+	return nil
+}
+
+// Update replaces a policy, keeping its creation time.
+func (r *PolicyRepository) Update(_ context.Context, p *models.Policy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.policies[p.ID]
+	if !ok {
+		return fmt.Errorf("policy %s: %w", p.ID, repository.ErrNotFound)
+	}
+	p.CreatedAt = existing.CreatedAt
+	p.UpdatedAt = time.Now().UTC()
+	r.policies[p.ID] = *p
+	return nil
+}
+
+// Delete removes a policy.
+func (r *PolicyRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.policies[id]; !ok {
+		return fmt.Errorf("policy %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.policies, id)
+	return nil
+}
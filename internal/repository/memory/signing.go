@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// SigningKeyRepository implements repository.SigningKeyRepository in
+// memory.
+type SigningKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string][]models.SigningKey // by agent, oldest first
+}
+
+// NewSigningKeyRepository creates an empty SigningKeyRepository.
+func NewSigningKeyRepository() *SigningKeyRepository {
+	return &SigningKeyRepository{keys: make(map[string][]models.SigningKey)}
+}
+
+// List returns an agent's keys, oldest first.
+func (r *SigningKeyRepository) List(_ context.Context, agentID string) ([]models.SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]models.SigningKey(nil), r.keys[agentID]...), nil
+}
+
+// Create stores a key.
+func (r *SigningKeyRepository) Create(_ context.Context, k *models.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.keys[k.AgentID] {
+		if existing.ID == k.ID {
+			return fmt.Errorf("creating signing key: %w", repository.ErrConflict)
+		}
+	}
+	r.keys[k.AgentID] = append(r.keys[k.AgentID], *k)
+	return nil
+}
+
+// Update replaces a key's expiry.
+func (r *SigningKeyRepository) Update(_ context.Context, k *models.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := r.keys[k.AgentID]
+	for i := range keys {
+		if keys[i].ID == k.ID {
+			keys[i].ExpiresAt = k.ExpiresAt
+			return nil
+		}
+	}
+	return fmt.Errorf("signing key %s: %w", k.ID, repository.ErrNotFound)
+}
+
+// Delete removes a key.
+func (r *SigningKeyRepository) Delete(_ context.Context, agentID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := r.keys[agentID]
+	for i := range keys {
+		if keys[i].ID == id {
+			r.keys[agentID] = append(keys[:i:i], keys[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("signing key %s: %w", id, repository.ErrNotFound)
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ThreatModelRepository implements repository.ThreatModelRepository in
+// memory.
+type ThreatModelRepository struct {
+	mu           sync.RWMutex
+	threatModels map[string]models.ThreatModel
+}
+
+// NewThreatModelRepository creates an empty ThreatModelRepository.
+func NewThreatModelRepository() *ThreatModelRepository {
+	return &ThreatModelRepository{threatModels: make(map[string]models.ThreatModel)}
+}
+
+// List returns every threat model, newest first.
+func (r *ThreatModelRepository) List(_ context.Context) ([]models.ThreatModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]models.ThreatModel, 0, len(r.threatModels))
+	for _, tm := range r.threatModels {
+		list = append(list, tm)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+// Get returns a threat model, or nil if there is none.
+func (r *ThreatModelRepository) Get(_ context.Context, id string) (*models.ThreatModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tm, ok := r.threatModels[id]
+	if !ok {
+		return nil, nil
+	}
+	return &tm, nil
+}
+
+// Create stores a threat model, assigning an ID when it has none.
+func (r *ThreatModelRepository) Create(_ context.Context, tm *models.ThreatModel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tm.ID == "" {
+		tm.ID = uuid.NewString()
+	}
+	if _, ok := r.threatModels[tm.ID]; ok {
+		return fmt.Errorf("creating threat model: %w", repository.ErrConflict)
+	}
+	tm.CreatedAt = time.Now().UTC()
+	tm.UpdatedAt = tm.CreatedAt
+	r.threatModels[tm.ID] = *tm
+	return nil
+}
+
+// Update replaces a threat model, keeping its creation time.
+func (r *ThreatModelRepository) Update(_ context.Context, tm *models.ThreatModel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.threatModels[tm.ID]
+	if !ok {
+		return fmt.Errorf("threat model %s: %w", tm.ID, repository.ErrNotFound)
+	}
+	tm.CreatedAt = existing.CreatedAt
+	tm.UpdatedAt = time.Now().UTC()
+	r.threatModels[tm.ID] = *tm
+	return nil
+}
+
+// Delete removes a threat model.
+func (r *ThreatModelRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.threatModels[id]; !ok {
+		return fmt.Errorf("threat model %s: %w", id, repository.ErrNotFound)
+	}
+	delete(r.threatModels, id)
+	return nil
+}
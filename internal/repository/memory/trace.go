@@ -0,0 +1,254 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// DefaultTraceLimit is the number of traces a TraceStore keeps when no
+// limit is given.
+const DefaultTraceLimit = 10000
+
+// TraceStore implements repository.TraceWriter, repository.TraceReader,
+// repository.TraceLister, repository.SignalWriter, repository.SignalLister
+// and repository.AgentActivityReader in memory. It keeps the most recently written
+// traces up to its limit and evicts the oldest beyond it; signals are
+// bounded the same way.
+type TraceStore struct {
+	mu      sync.RWMutex
+	limit   int
+	traces  map[traceKey]models.AgentTrace
+	order   []traceKey // oldest first
+	signals []StoredSignal
+}
+
+type traceKey struct {
+	orgID   string
+	traceID string
+}
+
+// StoredSignal is a security signal written outside an ingested trace.
+type StoredSignal struct {
+	OrgID   string
+	AgentID string
+	Signal  models.SecuritySignal
+}
+
+// NewTraceStore creates a TraceStore holding at most limit traces. A limit
+// of zero or less uses DefaultTraceLimit.
+func NewTraceStore(limit int) *TraceStore {
+	if limit <= 0 {
+		limit = DefaultTraceLimit
+	}
+	return &TraceStore{limit: limit, traces: make(map[traceKey]models.AgentTrace)}
+}
+
+// InsertTrace stores a trace, replacing any earlier trace with the same ID.
+func (s *TraceStore) InsertTrace(_ context.Context, orgID string, t *models.AgentTrace) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := traceKey{orgID: orgID, traceID: t.TraceID}
+	if _, ok := s.traces[key]; !ok {
+		s.order = append(s.order, key)
+	}
+	s.traces[key] = *t
+	for len(s.order) > s.limit {
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+	return nil
+}
+
+// GetTrace returns a stored trace, or ErrNotFound.
+func (s *TraceStore) GetTrace(_ context.Context, orgID, traceID string) (*models.AgentTrace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.traces[traceKey{orgID: orgID, traceID: traceID}]
+	if !ok {
+		return nil, fmt.Errorf("trace %s: %w", traceID, repository.ErrNotFound)
+	}
+	return &t, nil
+}
+
+// traceSortFields compares traces by repository.TraceSortFields.
+var traceSortFields = map[string]func(a, b *models.AgentTrace) int{
+	"start_time":  func(a, b *models.AgentTrace) int { return a.StartTime.Compare(b.StartTime) },
+	"duration_ms": func(a, b *models.AgentTrace) int { return cmp.Compare(a.DurationMs, b.DurationMs) },
+	"total_tokens": func(a, b *models.AgentTrace) int {
+		return cmp.Compare(a.Metrics.TotalTokens, b.Metrics.TotalTokens)
+	},
+	"estimated_cost_usd": func(a, b *models.AgentTrace) int {
+		return cmp.Compare(a.Metrics.EstimatedCostUSD, b.Metrics.EstimatedCostUSD)
+	},
+	"security_signals": func(a, b *models.AgentTrace) int {
+		return cmp.Compare(a.Metrics.SecuritySignals, b.Metrics.SecuritySignals)
+	},
+}
+
+// ListTraces returns an organization's traces matching filters, newest
+// first unless filters.Sort says otherwise, without their spans and signals.
+func (s *TraceStore) ListTraces(_ context.Context, orgID string, filters *repository.TraceFilters) ([]models.AgentTrace, error) {
+	if filters == nil {
+		filters = &repository.TraceFilters{}
+	}
+	out := s.matchingTraces(orgID, filters)
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartTime.Equal(out[j].StartTime) {
+			return out[i].StartTime.After(out[j].StartTime)
+		}
+		return out[i].TraceID < out[j].TraceID
+	})
+	sortList(out, filters.Sort, traceSortFields)
+	return pageList(out, filters.Offset, filters.Limit), nil
+}
+
+// CountTraces returns how many of an organization's traces match filters.
+func (s *TraceStore) CountTraces(_ context.Context, orgID string, filters *repository.TraceFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.TraceFilters{}
+	}
+	return len(s.matchingTraces(orgID, filters)), nil
+}
+
+// matchingTraces returns an organization's traces matching filters, in no
+// particular order, without their spans and signals.
+func (s *TraceStore) matchingTraces(orgID string, filters *repository.TraceFilters) []models.AgentTrace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []models.AgentTrace
+	for key, t := range s.traces {
+		switch {
+		case key.orgID != orgID,
+			filters.AgentID != nil && t.AgentID != *filters.AgentID,
+			filters.SessionID != nil && t.SessionID != *filters.SessionID,
+			filters.Status != nil && t.Status != *filters.Status,
+			filters.StartFrom != nil && t.StartTime.Before(time.Unix(*filters.StartFrom, 0)),
+			filters.StartTo != nil && !t.StartTime.Before(time.Unix(*filters.StartTo, 0)):
+			continue
+		}
+		t.Spans, t.SecuritySignals = nil, nil
+		out = append(out, t)
+	}
+	return out
+}
+
+// LastTraceAt returns the latest span start among an agent's stored
+// traces, or nil when there are none.
+func (s *TraceStore) LastTraceAt(_ context.Context, orgID, agentID string) (*time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var last *time.Time
+	for key, t := range s.traces {
+		if key.orgID != orgID || t.AgentID.String() != agentID {
+			continue
+		}
+		latest := t.StartTime
+		for _, sp := range t.Spans {
+			if sp.StartTime.After(latest) {
+				latest = sp.StartTime
+			}
+		}
+		if last == nil || latest.After(*last) {
+			last = &latest
+		}
+	}
+	return last, nil
+}
+
+// InsertSignals stores security signals raised outside a trace.
+func (s *TraceStore) InsertSignals(_ context.Context, orgID, agentID string, signals []models.SecuritySignal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sig := range signals {
+		s.signals = append(s.signals, StoredSignal{OrgID: orgID, AgentID: agentID, Signal: sig})
+	}
+	if over := len(s.signals) - s.limit; over > 0 {
+		s.signals = append([]StoredSignal(nil), s.signals[over:]...)
+	}
+	return nil
+}
+
+// Signals returns the stored signals, oldest first.
+func (s *TraceStore) Signals() []StoredSignal {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]StoredSignal(nil), s.signals...)
+}
+
+// signalSeverities ranks signal severities, lowest first, for sorting.
+var signalSeverities = []string{"low", "medium", "high", "critical"}
+
+// signalSortFields compares signals by repository.SignalSortFields.
+var signalSortFields = map[string]func(a, b *models.SecuritySignal) int{
+	"timestamp": func(a, b *models.SecuritySignal) int { return a.Timestamp.Compare(b.Timestamp) },
+	"type":      func(a, b *models.SecuritySignal) int { return strings.Compare(string(a.Type), string(b.Type)) },
+	"severity": func(a, b *models.SecuritySignal) int {
+		return cmp.Compare(slices.Index(signalSeverities, a.Severity), slices.Index(signalSeverities, b.Severity))
+	},
+}
+
+// ListSignals returns an organization's signals matching filters, those on
+// stored traces included, newest first unless filters.Sort says otherwise.
+func (s *TraceStore) ListSignals(_ context.Context, orgID string, filters *repository.SignalFilters) ([]models.SecuritySignal, error) {
+	if filters == nil {
+		filters = &repository.SignalFilters{}
+	}
+	out := s.matchingSignals(orgID, filters)
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Timestamp.Equal(out[j].Timestamp) {
+			return out[i].Timestamp.After(out[j].Timestamp)
+		}
+		return out[i].ID < out[j].ID
+	})
+	sortList(out, filters.Sort, signalSortFields)
+	return pageList(out, filters.Offset, filters.Limit), nil
+}
+
+// CountSignals returns how many of an organization's signals match filters.
+func (s *TraceStore) CountSignals(_ context.Context, orgID string, filters *repository.SignalFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.SignalFilters{}
+	}
+	return len(s.matchingSignals(orgID, filters)), nil
+}
+
+// matchingSignals returns an organization's signals matching filters, in no
+// particular order.
+func (s *TraceStore) matchingSignals(orgID string, filters *repository.SignalFilters) []models.SecuritySignal {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []models.SecuritySignal
+	keep := func(agentID string, sig *models.SecuritySignal) {
+		switch {
+		case filters.TraceID != nil && sig.TraceID != *filters.TraceID,
+			filters.AgentID != nil && agentID != *filters.AgentID,
+			filters.Type != nil && sig.Type != *filters.Type,
+			filters.Severity != nil && sig.Severity != *filters.Severity:
+			return
+		}
+		out = append(out, *sig)
+	}
+	for key, t := range s.traces {
+		if key.orgID != orgID {
+			continue
+		}
+		for i := range t.SecuritySignals {
+			keep(t.AgentID.String(), &t.SecuritySignals[i])
+		}
+	}
+	for i := range s.signals {
+		if s.signals[i].OrgID == orgID {
+			keep(s.signals[i].AgentID, &s.signals[i].Signal)
+		}
+	}
+	return out
+}
@@ -0,0 +1,279 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AgentRepository implements repository.AgentRepository and
+// repository.AgentHistory for PostgreSQL. Capabilities, tools and bound
+// policy IDs are stored as JSONB; a trigger keeps every change in
+// agent_history.
+type AgentRepository struct {
+	db *DB
+}
+
+// NewAgentRepository creates a new AgentRepository.
+func NewAgentRepository(db *DB) *AgentRepository {
+	return &AgentRepository{db: db}
+}
+
+const agentColumns = `id, name, description, framework, version, owner, team, environment,
+	capabilities, tools, policies, risk_level, status, last_active_at, created_at, updated_at`
+
+// agentSortColumns maps repository.AgentSortFields to columns.
+var agentSortColumns = map[string]string{
+	"name": "name", "status": "status", "environment": "environment",
+	"risk_level": "risk_level", "created_at": "created_at", "updated_at": "updated_at",
+}
+
+// agentFilter selects the agents matching agentFilterArgs.
+const agentFilter = `
+	WHERE ($1::text IS NULL OR status = $1)
+		AND ($2::text IS NULL OR environment = $2)
+		AND ($3::text IS NULL OR team = $3)
+		AND ($4::text IS NULL OR framework = $4)
+		AND ($5::text IS NULL OR policies ? $5)`
+
+// agentFilterArgs returns agentFilter's arguments.
+func agentFilterArgs(filters *repository.AgentFilters) []any {
+	var status *string
+	if filters.Status != nil {
+		s := string(*filters.Status)
+		status = &s
+	}
+	return []any{status, filters.Environment, filters.Team, filters.Framework, filters.Policy}
+}
+
+// List returns the agents matching filters, ordered by name unless
+// filters.Sort says otherwise.
+func (r *AgentRepository) List(ctx context.Context, filters *repository.AgentFilters) ([]models.Agent, error) {
+	if filters == nil {
+		filters = &repository.AgentFilters{}
+	}
+	query := `SELECT ` + agentColumns + `
+		FROM agents` + agentFilter + `
+		ORDER BY ` + orderBy(filters.Sort, agentSortColumns, "name, id") + `
+		LIMIT NULLIF($6, 0) OFFSET $7`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, append(agentFilterArgs(filters), filters.Limit, filters.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("querying agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []models.Agent
+	for rows.Next() {
+		a, err := scanAgent(rows)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, *a)
+	}
+	return agents, rows.Err()
+}
+
+// Count returns how many agents match filters.
+func (r *AgentRepository) Count(ctx context.Context, filters *repository.AgentFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.AgentFilters{}
+	}
+	var n int
+	if err := r.db.reader(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM agents`+agentFilter, agentFilterArgs(filters)...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting agents: %w", err)
+	}
+	return n, nil
+}
+
+// Get returns an agent, or nil if there is none.
+func (r *AgentRepository) Get(ctx context.Context, id uuid.UUID) (*models.Agent, error) {
+	query := `SELECT ` + agentColumns + ` FROM agents WHERE id = $1`
+
+	a, err := scanAgent(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting agent %s: %w", id, err)
+	}
+	return a, nil
+}
+
+// GetAsOf returns an agent as registered at asOf, policy bindings included,
+// or nil if it was not registered then.
+func (r *AgentRepository) GetAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Agent, error) {
+	query := `SELECT agent_id, name, description, framework, version, owner, team, environment,
+			capabilities, tools, policies, risk_level, status, last_active_at, created_at, updated_at
+		FROM agent_history
+		WHERE agent_id = $1 AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)`
+
+	a, err := scanAgent(r.db.reader(ctx).QueryRow(ctx, query, id, asOf))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting agent %s history: %w", id, err)
+	}
+	return a, nil
+}
+
+// Create stores an agent, assigning an ID when it has none.
+func (r *AgentRepository) Create(ctx context.Context, a *models.Agent) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	capabilities, tools, policies, err := marshalAgentJSON(a)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO agents (id, name, description, framework, version, owner, team, environment,
+		                    capabilities, tools, policies, risk_level, status, last_active_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING created_at, updated_at`
+
+	err = r.db.conn(ctx).QueryRow(ctx, query,
+		a.ID, a.Name, a.Description, a.Framework, a.Version, a.Owner, a.Team, a.Environment,
+		capabilities, tools, policies, a.RiskLevel, a.Status, a.LastActiveAt,
+	).Scan(&a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("creating agent: %w", mapError(err))
+	}
+	return nil
+}
+
+// Update replaces an agent, keeping its creation time.
+func (r *AgentRepository) Update(ctx context.Context, a *models.Agent) error {
+	capabilities, tools, policies, err := marshalAgentJSON(a)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE agents
+		SET name = $2, description = $3, framework = $4, version = $5, owner = $6, team = $7,
+		    environment = $8, capabilities = $9, tools = $10, policies = $11, risk_level = $12,
+		    status = $13, last_active_at = $14, updated_at = NOW()
+		WHERE id = $1
+		RETURNING created_at, updated_at`
+
+	err = r.db.conn(ctx).QueryRow(ctx, query,
+		a.ID, a.Name, a.Description, a.Framework, a.Version, a.Owner, a.Team, a.Environment,
+		capabilities, tools, policies, a.RiskLevel, a.Status, a.LastActiveAt,
+	).Scan(&a.CreatedAt, &a.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("agent %s: %w", a.ID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("updating agent: %w", mapError(err))
+	}
+	return nil
+}
+
+// Delete removes an agent.
+func (r *AgentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM agents WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting agent: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("agent %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetPolicies returns the policies bound to an agent. Only their IDs are
+// set; definitions are read from the PolicyRepository.
+func (r *AgentRepository) GetPolicies(ctx context.Context, agentID uuid.UUID) ([]models.Policy, error) {
+	var raw []byte
+	err := r.db.reader(ctx).QueryRow(ctx, `SELECT policies FROM agents WHERE id = $1`, agentID).Scan(&raw)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("agent %s: %w", agentID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting agent policies: %w", err)
+	}
+	var ids []string
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return nil, fmt.Errorf("decoding agent policies: %w", err)
+	}
+	policies := make([]models.Policy, 0, len(ids))
+	for _, id := range ids {
+		policies = append(policies, models.Policy{ID: id})
+	}
+	return policies, nil
+}
+
+// BindPolicies replaces the policies bound to an agent.
+func (r *AgentRepository) BindPolicies(ctx context.Context, agentID uuid.UUID, policyIDs []string) error {
+	if policyIDs == nil {
+		policyIDs = []string{}
+	}
+	policies, err := json.Marshal(policyIDs)
+	if err != nil {
+		return fmt.Errorf("encoding agent policies: %w", err)
+	}
+	result, err := r.db.conn(ctx).Exec(ctx,
+		`UPDATE agents SET policies = $2, updated_at = NOW() WHERE id = $1`, agentID, policies)
+	if err != nil {
+		return fmt.Errorf("binding agent policies: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("agent %s: %w", agentID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// marshalAgentJSON encodes an agent's JSONB columns, storing nil slices as
+// empty arrays.
+func marshalAgentJSON(a *models.Agent) (capabilities, tools, policies []byte, err error) {
+	if capabilities, err = json.Marshal(nonNil(a.Capabilities)); err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding agent capabilities: %w", err)
+	}
+	if tools, err = json.Marshal(nonNil(a.Tools)); err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding agent tools: %w", err)
+	}
+	if policies, err = json.Marshal(nonNil(a.Policies)); err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding agent policies: %w", err)
+	}
+	return capabilities, tools, policies, nil
+}
+
+// nonNil returns s, or an empty slice when s is nil.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// scanAgent scans a row of agentColumns. pgx.ErrNoRows is returned
+// unwrapped so callers can detect a missing agent.
+func scanAgent(row pgx.Row) (*models.Agent, error) {
+	var a models.Agent
+	var capabilities, tools, policies []byte
+	if err := row.Scan(
+		&a.ID, &a.Name, &a.Description, &a.Framework, &a.Version, &a.Owner, &a.Team, &a.Environment,
+		&capabilities, &tools, &policies, &a.RiskLevel, &a.Status, &a.LastActiveAt, &a.CreatedAt, &a.UpdatedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning agent: %w", err)
+	}
+	if err := json.Unmarshal(capabilities, &a.Capabilities); err != nil {
+		return nil, fmt.Errorf("decoding agent %s capabilities: %w", a.ID, err)
+	}
+	if err := json.Unmarshal(tools, &a.Tools); err != nil {
+		return nil, fmt.Errorf("decoding agent %s tools: %w", a.ID, err)
+	}
+	if err := json.Unmarshal(policies, &a.Policies); err != nil {
+		return nil, fmt.Errorf("decoding agent %s policies: %w", a.ID, err)
+	}
+	return &a, nil
+}
@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// AttestationRepository implements repository.AttestationRepository for
+// PostgreSQL.
+type AttestationRepository struct {
+	db *DB
+}
+
+// NewAttestationRepository creates a new AttestationRepository.
+func NewAttestationRepository(db *DB) *AttestationRepository {
+	return &AttestationRepository{db: db}
+}
+
+const campaignColumns = `id, organization_id, name, framework_id, status, due_date, reminder_interval_days, created_by, created_at, closed_at`
+
+const attestationColumns = `id, campaign_id, organization_id, implementation_id, framework_id, control_id, owner, status, comment,
+	responded_by, responded_at, reminders, last_reminded_at, created_at, updated_at`
+
+// CreateCampaign stores a campaign and its attestations in one transaction.
+func (r *AttestationRepository) CreateCampaign(ctx context.Context, c *models.AttestationCampaign, attestations []models.Attestation) error {
+	return r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		query := `
+			INSERT INTO attestation_campaigns (id, organization_id, name, framework_id, status, due_date, reminder_interval_days, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING created_at`
+		err := tx.QueryRow(ctx, query,
+			c.ID, c.OrganizationID, c.Name, c.FrameworkID, c.Status, c.DueDate, c.ReminderIntervalDays, c.CreatedBy,
+		).Scan(&c.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("creating attestation campaign: %w", mapError(err))
+		}
+
+		for i := range attestations {
+			a := &attestations[i]
+			query := `
+				INSERT INTO attestations (id, campaign_id, organization_id, implementation_id, framework_id, control_id, owner, status)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING created_at, updated_at`
+			err := tx.QueryRow(ctx, query,
+				a.ID, a.CampaignID, a.OrganizationID, a.ImplementationID, a.FrameworkID, a.ControlID, a.Owner, a.Status,
+			).Scan(&a.CreatedAt, &a.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("creating attestation for %s: %w", a.ControlID, mapError(err))
+			}
+		}
+		return nil
+	})
+}
+
+// ListCampaigns returns an organization's campaigns, newest first.
+func (r *AttestationRepository) ListCampaigns(ctx context.Context, orgID string) ([]models.AttestationCampaign, error) {
+	query := `SELECT ` + campaignColumns + `
+		FROM attestation_campaigns
+		WHERE organization_id = $1
+		ORDER BY created_at DESC, id`
+	return r.queryCampaigns(ctx, query, orgID)
+}
+
+// ListOpenCampaigns returns every organization's open campaigns, oldest
+// due date first.
+func (r *AttestationRepository) ListOpenCampaigns(ctx context.Context) ([]models.AttestationCampaign, error) {
+	query := `SELECT ` + campaignColumns + `
+		FROM attestation_campaigns
+		WHERE status = 'open'
+		ORDER BY due_date, id`
+	return r.queryCampaigns(ctx, query)
+}
+
+func (r *AttestationRepository) queryCampaigns(ctx context.Context, query string, args ...any) ([]models.AttestationCampaign, error) {
+	rows, err := r.db.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying attestation campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []models.AttestationCampaign
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, *c)
+	}
+	return campaigns, rows.Err()
+}
+
+// GetCampaign returns a campaign, or nil if there is none.
+func (r *AttestationRepository) GetCampaign(ctx context.Context, id string) (*models.AttestationCampaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM attestation_campaigns WHERE id = $1`
+
+	c, err := scanCampaign(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting attestation campaign %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// UpdateCampaign replaces a campaign's name, status, due date, reminder
+// interval and closing time.
+func (r *AttestationRepository) UpdateCampaign(ctx context.Context, c *models.AttestationCampaign) error {
+	query := `
+		UPDATE attestation_campaigns
+		SET name = $2, status = $3, due_date = $4, reminder_interval_days = $5, closed_at = $6
+		WHERE id = $1`
+
+	result, err := r.db.conn(ctx).Exec(ctx, query, c.ID, c.Name, c.Status, c.DueDate, c.ReminderIntervalDays, c.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("updating attestation campaign: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("attestation campaign %s: %w", c.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// ListAttestations returns a campaign's attestations ordered by framework
+// and control ID.
+func (r *AttestationRepository) ListAttestations(ctx context.Context, campaignID string) ([]models.Attestation, error) {
+	query := `SELECT ` + attestationColumns + `
+		FROM attestations
+		WHERE campaign_id = $1
+		ORDER BY framework_id, control_id`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("querying attestations: %w", err)
+	}
+	defer rows.Close()
+
+	var attestations []models.Attestation
+	for rows.Next() {
+		a, err := scanAttestation(rows)
+		if err != nil {
+			return nil, err
+		}
+		attestations = append(attestations, *a)
+	}
+	return attestations, rows.Err()
+}
+
+// GetAttestation returns an attestation, or nil if there is none.
+func (r *AttestationRepository) GetAttestation(ctx context.Context, id string) (*models.Attestation, error) {
+	query := `SELECT ` + attestationColumns + ` FROM attestations WHERE id = $1`
+
+	a, err := scanAttestation(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting attestation %s: %w", id, err)
+	}
+	return a, nil
+}
+
+// UpdateAttestation replaces an attestation's owner, response and reminder
+// state.
+func (r *AttestationRepository) UpdateAttestation(ctx context.Context, a *models.Attestation) error {
+	query := `
+		UPDATE attestations
+		SET owner = $2, status = $3, comment = $4, responded_by = $5, responded_at = $6,
+			reminders = $7, last_reminded_at = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query,
+		a.ID, a.Owner, a.Status, a.Comment, a.RespondedBy, a.RespondedAt, a.Reminders, a.LastRemindedAt,
+	).Scan(&a.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("attestation %s: %w", a.ID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("updating attestation: %w", mapError(err))
+	}
+	return nil
+}
+
+// scanCampaign scans a row of campaignColumns. pgx.ErrNoRows is returned
+// unwrapped so callers can detect a missing campaign.
+func scanCampaign(row pgx.Row) (*models.AttestationCampaign, error) {
+	var c models.AttestationCampaign
+	if err := row.Scan(
+		&c.ID, &c.OrganizationID, &c.Name, &c.FrameworkID, &c.Status, &c.DueDate,
+		&c.ReminderIntervalDays, &c.CreatedBy, &c.CreatedAt, &c.ClosedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning attestation campaign: %w", err)
+	}
+	return &c, nil
+}
+
+// scanAttestation scans a row of attestationColumns. pgx.ErrNoRows is
+// returned unwrapped so callers can detect a missing attestation.
+func scanAttestation(row pgx.Row) (*models.Attestation, error) {
+	var a models.Attestation
+	if err := row.Scan(
+		&a.ID, &a.CampaignID, &a.OrganizationID, &a.ImplementationID, &a.FrameworkID, &a.ControlID,
+		&a.Owner, &a.Status, &a.Comment, &a.RespondedBy, &a.RespondedAt, &a.Reminders, &a.LastRemindedAt,
+		&a.CreatedAt, &a.UpdatedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning attestation: %w", err)
+	}
+	return &a, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -30,7 +32,7 @@ func (r *ControlRepository) ListFrameworks(ctx context.Context) ([]models.Framew
 		FROM frameworks
 		ORDER BY name, version`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.reader(ctx).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("querying frameworks: %w", err)
 	}
@@ -59,7 +61,7 @@ func (r *ControlRepository) GetFramework(ctx context.Context, id string) (*model
 		WHERE id = $1`
 
 	var f models.Framework
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.reader(ctx).QueryRow(ctx, query, id).Scan(
 		&f.ID, &f.Name, &f.Version, &f.Publisher,
 		&f.Description, &f.URL, &f.CreatedAt, &f.UpdatedAt,
 	)
@@ -79,11 +81,11 @@ func (r *ControlRepository) CreateFramework(ctx context.Context, f *models.Frame
 		INSERT INTO frameworks (id, name, version, publisher, description, url, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	_, err := r.db.conn(ctx).Exec(ctx, query,
 		f.ID, f.Name, f.Version, f.Publisher, f.Description, f.URL,
 	)
 	if err != nil {
-		return fmt.Errorf("creating framework: %w", err)
+		return fmt.Errorf("creating framework: %w", mapError(err))
 	}
 
 	return nil
@@ -96,14 +98,14 @@ func (r *ControlRepository) UpdateFramework(ctx context.Context, f *models.Frame
 		SET name = $2, version = $3, publisher = $4, description = $5, url = $6, updated_at = NOW()
 		WHERE id = $1`
 
-	result, err := r.db.Pool.Exec(ctx, query,
+	result, err := r.db.conn(ctx).Exec(ctx, query,
 		f.ID, f.Name, f.Version, f.Publisher, f.Description, f.URL,
 	)
 	if err != nil {
-		return fmt.Errorf("updating framework: %w", err)
+		return fmt.Errorf("updating framework: %w", mapError(err))
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("framework %s not found", f.ID)
+		return fmt.Errorf("framework %s: %w", f.ID, repository.ErrNotFound)
 	}
 
 	return nil
@@ -113,12 +115,12 @@ func (r *ControlRepository) UpdateFramework(ctx context.Context, f *models.Frame
 func (r *ControlRepository) DeleteFramework(ctx context.Context, id string) error {
 	query := `DELETE FROM frameworks WHERE id = $1`
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+	result, err := r.db.conn(ctx).Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("deleting framework: %w", err)
+		return fmt.Errorf("deleting framework: %w", mapError(err))
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("framework %s not found", id)
+		return fmt.Errorf("framework %s: %w", id, repository.ErrNotFound)
 	}
 
 	return nil
@@ -132,12 +134,12 @@ func (r *ControlRepository) DeleteFramework(ctx context.Context, id string) erro
 func (r *ControlRepository) ListControls(ctx context.Context, frameworkID string) ([]models.Control, error) {
 	query := `
 		SELECT id, framework_id, control_id, title, description,
-		       objectives, activities, evidence_types, applicable_layers, parent_control_id
+		       objectives, activities, evidence_types, applicable_layers, parent_control_id, family
 		FROM controls
 		WHERE framework_id = $1
 		ORDER BY control_id`
 
-	rows, err := r.db.Pool.Query(ctx, query, frameworkID)
+	rows, err := r.db.reader(ctx).Query(ctx, query, frameworkID)
 	if err != nil {
 		return nil, fmt.Errorf("querying controls: %w", err)
 	}
@@ -150,7 +152,7 @@ func (r *ControlRepository) ListControls(ctx context.Context, frameworkID string
 
 		if err := rows.Scan(
 			&c.ID, &c.FrameworkID, &c.ControlID, &c.Title, &c.Description,
-			&objectives, &activities, &evidenceTypes, &applicableLayers, &c.ParentControlID,
+			&objectives, &activities, &evidenceTypes, &applicableLayers, &c.ParentControlID, &c.Family,
 		); err != nil {
 			return nil, fmt.Errorf("scanning control: %w", err)
 		}
@@ -179,16 +181,16 @@ func (r *ControlRepository) ListControls(ctx context.Context, frameworkID string
 func (r *ControlRepository) GetControl(ctx context.Context, id string) (*models.Control, error) {
 	query := `
 		SELECT id, framework_id, control_id, title, description,
-		       objectives, activities, evidence_types, applicable_layers, parent_control_id
+		       objectives, activities, evidence_types, applicable_layers, parent_control_id, family
 		FROM controls
 		WHERE id = $1`
 
 	var c models.Control
 	var objectives, activities, evidenceTypes, applicableLayers []byte
 
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := r.db.reader(ctx).QueryRow(ctx, query, id).Scan(
 		&c.ID, &c.FrameworkID, &c.ControlID, &c.Title, &c.Description,
-		&objectives, &activities, &evidenceTypes, &applicableLayers, &c.ParentControlID,
+		&objectives, &activities, &evidenceTypes, &applicableLayers, &c.ParentControlID, &c.Family,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -223,15 +225,15 @@ func (r *ControlRepository) CreateControl(ctx context.Context, c *models.Control
 
 	query := `
 		INSERT INTO controls (id, framework_id, control_id, title, description,
-		                      objectives, activities, evidence_types, applicable_layers, parent_control_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+		                      objectives, activities, evidence_types, applicable_layers, parent_control_id, family)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	_, err := r.db.conn(ctx).Exec(ctx, query,
 		c.ID, c.FrameworkID, c.ControlID, c.Title, c.Description,
-		objectives, activities, evidenceTypes, applicableLayers, c.ParentControlID,
+		objectives, activities, evidenceTypes, applicableLayers, c.ParentControlID, c.Family,
 	)
 	if err != nil {
-		return fmt.Errorf("creating control: %w", err)
+		return fmt.Errorf("creating control: %w", mapError(err))
 	}
 
 	return nil
@@ -248,18 +250,18 @@ func (r *ControlRepository) UpdateControl(ctx context.Context, c *models.Control
 		UPDATE controls
 		SET framework_id = $2, control_id = $3, title = $4, description = $5,
 		    objectives = $6, activities = $7, evidence_types = $8, applicable_layers = $9, parent_control_id = $10,
-		    updated_at = NOW()
+		    family = $11, updated_at = NOW()
 		WHERE id = $1`
 
-	result, err := r.db.Pool.Exec(ctx, query,
+	result, err := r.db.conn(ctx).Exec(ctx, query,
 		c.ID, c.FrameworkID, c.ControlID, c.Title, c.Description,
-		objectives, activities, evidenceTypes, applicableLayers, c.ParentControlID,
+		objectives, activities, evidenceTypes, applicableLayers, c.ParentControlID, c.Family,
 	)
 	if err != nil {
-		return fmt.Errorf("updating control: %w", err)
+		return fmt.Errorf("updating control: %w", mapError(err))
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("control %s not found", c.ID)
+		return fmt.Errorf("control %s: %w", c.ID, repository.ErrNotFound)
 	}
 
 	return nil
@@ -269,31 +271,305 @@ func (r *ControlRepository) UpdateControl(ctx context.Context, c *models.Control
 func (r *ControlRepository) DeleteControl(ctx context.Context, id string) error {
 	query := `DELETE FROM controls WHERE id = $1`
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+	result, err := r.db.conn(ctx).Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("deleting control: %w", err)
+		return fmt.Errorf("deleting control: %w", mapError(err))
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("control %s not found", id)
+		return fmt.Errorf("control %s: %w", id, repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Catalog Import
+// -----------------------------------------------------------------------------
+
+// ImportCatalog creates a framework with its controls and crosswalks in a
+// single transaction. Any failure rolls back the whole import. Importing a
+// different version of an existing framework keeps the current version in
+// framework_versions and replaces its controls; re-importing the same
+// version is a conflict.
+func (r *ControlRepository) ImportCatalog(ctx context.Context, catalog *repository.CatalogImport) error {
+	return r.db.WithTx(ctx, func(ctx context.Context, _ pgx.Tx) error {
+		existing, err := r.GetFramework(ctx, catalog.Framework.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.Version != catalog.Framework.Version {
+			if err := r.supersede(ctx, existing); err != nil {
+				return err
+			}
+			if err := r.UpdateFramework(ctx, &catalog.Framework); err != nil {
+				return err
+			}
+		} else if err := r.CreateFramework(ctx, &catalog.Framework); err != nil {
+			return err
+		}
+		for i := range catalog.Controls {
+			if err := r.CreateControl(ctx, &catalog.Controls[i]); err != nil {
+				return fmt.Errorf("control %s: %w", catalog.Controls[i].ControlID, err)
+			}
+		}
+		for i := range catalog.Crosswalks {
+			cw := &catalog.Crosswalks[i]
+			if err := r.CreateCrosswalk(ctx, cw); err != nil {
+				return fmt.Errorf("crosswalk %s -> %s: %w", cw.SourceControlID, cw.TargetControlID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// supersede copies a framework's current version into framework_versions
+// and removes its controls, ready for the new version's.
+func (r *ControlRepository) supersede(ctx context.Context, f *models.Framework) error {
+	controls, err := r.ListControls(ctx, f.ID)
+	if err != nil {
+		return err
+	}
+	if controls == nil {
+		controls = []models.Control{}
+	}
+	snapshot, err := json.Marshal(controls)
+	if err != nil {
+		return fmt.Errorf("encoding controls: %w", err)
+	}
+
+	query := `
+		INSERT INTO framework_versions (framework_id, version, name, publisher, description, url, controls)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (framework_id, version) DO UPDATE
+		SET name = EXCLUDED.name, publisher = EXCLUDED.publisher, description = EXCLUDED.description,
+		    url = EXCLUDED.url, controls = EXCLUDED.controls, created_at = NOW()`
+	if _, err := r.db.conn(ctx).Exec(ctx, query,
+		f.ID, f.Version, f.Name, f.Publisher, f.Description, f.URL, snapshot,
+	); err != nil {
+		return fmt.Errorf("archiving framework %s %s: %w", f.ID, f.Version, mapError(err))
 	}
 
+	if _, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM controls WHERE framework_id = $1`, f.ID); err != nil {
+		return fmt.Errorf("removing superseded controls: %w", mapError(err))
+	}
 	return nil
 }
 
+// -----------------------------------------------------------------------------
+// Framework Versions
+// -----------------------------------------------------------------------------
+
+// ListFrameworkVersions returns a framework's current and superseded
+// versions, without their controls, in no particular order.
+func (r *ControlRepository) ListFrameworkVersions(ctx context.Context, frameworkID string) ([]models.FrameworkVersion, error) {
+	query := `
+		SELECT f.version, f.name, f.publisher, f.description, f.url, f.updated_at, TRUE,
+		       (SELECT COUNT(*) FROM controls c WHERE c.framework_id = f.id)
+		FROM frameworks f
+		WHERE f.id = $1
+		UNION ALL
+		SELECT v.version, v.name, v.publisher, v.description, v.url, v.created_at, FALSE,
+		       jsonb_array_length(v.controls)
+		FROM framework_versions v
+		JOIN frameworks f ON f.id = v.framework_id
+		WHERE v.framework_id = $1 AND v.version <> f.version`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, frameworkID)
+	if err != nil {
+		return nil, fmt.Errorf("querying framework versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []models.FrameworkVersion
+	for rows.Next() {
+		v := models.FrameworkVersion{FrameworkID: frameworkID}
+		if err := rows.Scan(
+			&v.Version, &v.Name, &v.Publisher, &v.Description, &v.URL,
+			&v.CreatedAt, &v.Current, &v.ControlCount,
+		); err != nil {
+			return nil, fmt.Errorf("scanning framework version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// GetFrameworkVersion returns a version of a framework with its controls.
+func (r *ControlRepository) GetFrameworkVersion(ctx context.Context, frameworkID, version string) (*models.FrameworkVersion, error) {
+	current, err := r.GetFramework(ctx, frameworkID)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	if version == "" || version == current.Version {
+		controls, err := r.ListControls(ctx, frameworkID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.FrameworkVersion{
+			FrameworkID:  current.ID,
+			Version:      current.Version,
+			Name:         current.Name,
+			Publisher:    current.Publisher,
+			Description:  current.Description,
+			URL:          current.URL,
+			Current:      true,
+			ControlCount: len(controls),
+			Controls:     controls,
+			CreatedAt:    current.UpdatedAt,
+		}, nil
+	}
+
+	query := `
+		SELECT name, publisher, description, url, controls, created_at
+		FROM framework_versions
+		WHERE framework_id = $1 AND version = $2`
+
+	v := models.FrameworkVersion{FrameworkID: frameworkID, Version: version}
+	var controls []byte
+	err = r.db.reader(ctx).QueryRow(ctx, query, frameworkID, version).Scan(
+		&v.Name, &v.Publisher, &v.Description, &v.URL, &controls, &v.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting framework %s version %s: %w", frameworkID, version, err)
+	}
+	if err := json.Unmarshal(controls, &v.Controls); err != nil {
+		return nil, fmt.Errorf("decoding framework %s version %s: %w", frameworkID, version, err)
+	}
+	v.ControlCount = len(v.Controls)
+
+	return &v, nil
+}
+
+// -----------------------------------------------------------------------------
+// Control Search
+// -----------------------------------------------------------------------------
+
+// SearchControls returns the controls matching q, ranked by how well their
+// ID, title and description match its text. The total counts the matches
+// in all pages, and is zero when the offset is past the last of them.
+func (r *ControlRepository) SearchControls(ctx context.Context, q *repository.ControlQuery) ([]repository.ControlMatch, int, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	rank := "0::float8"
+	if q.Text != "" {
+		tsquery := "websearch_to_tsquery('english', " + arg(q.Text) + ")"
+		where = append(where, "search_vector @@ "+tsquery)
+		rank = "ts_rank(search_vector, " + tsquery + ")::float8"
+	}
+	if len(q.FrameworkIDs) > 0 {
+		where = append(where, "framework_id = ANY("+arg(q.FrameworkIDs)+")")
+	}
+	if len(q.Layers) > 0 {
+		where = append(where, "applicable_layers ?| "+arg(q.Layers))
+	}
+	if len(q.EvidenceTypes) > 0 && q.ExactEvidence {
+		lowered := make([]string, len(q.EvidenceTypes))
+		for i, e := range q.EvidenceTypes {
+			lowered[i] = strings.ToLower(e)
+		}
+		where = append(where, "LOWER(evidence_types::text)::jsonb ?| "+arg(lowered))
+	} else if len(q.EvidenceTypes) > 0 {
+		patterns := make([]string, len(q.EvidenceTypes))
+		for i, e := range q.EvidenceTypes {
+			patterns[i] = "%" + likeEscaper.Replace(e) + "%"
+		}
+		where = append(where, "EXISTS (SELECT 1 FROM jsonb_array_elements_text(evidence_types) e WHERE e ILIKE ANY("+arg(patterns)+"))")
+	}
+	if len(q.Tags) > 0 {
+		where = append(where, `EXISTS (SELECT 1 FROM control_tags t
+			WHERE t.organization_id = `+arg(q.OrganizationID)+` AND t.framework_id = controls.framework_id
+			  AND LOWER(t.control_id) = LOWER(controls.control_id) AND t.tags ?| `+arg(q.Tags)+`)`)
+	}
+
+	query := `
+		SELECT id, framework_id, control_id, title, description,
+		       objectives, activities, evidence_types, applicable_layers, parent_control_id, family,
+		       ` + rank + `, COUNT(*) OVER ()
+		FROM controls`
+	if len(where) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(where, "\n\t\t  AND ")
+	}
+	query += "\n\t\tORDER BY 12 DESC, framework_id, control_id"
+	if q.Limit > 0 {
+		query += " LIMIT " + arg(q.Limit)
+	}
+	if q.Offset > 0 {
+		query += " OFFSET " + arg(q.Offset)
+	}
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("searching controls: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		matches []repository.ControlMatch
+		total   int
+	)
+	for rows.Next() {
+		var m repository.ControlMatch
+		var objectives, activities, evidenceTypes, applicableLayers []byte
+
+		if err := rows.Scan(
+			&m.ID, &m.FrameworkID, &m.ControlID, &m.Title, &m.Description,
+			&objectives, &activities, &evidenceTypes, &applicableLayers, &m.ParentControlID, &m.Family,
+			&m.Rank, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scanning control: %w", err)
+		}
+
+		if err := json.Unmarshal(objectives, &m.Objectives); err != nil {
+			m.Objectives = []string{}
+		}
+		if err := json.Unmarshal(activities, &m.Activities); err != nil {
+			m.Activities = []string{}
+		}
+		if err := json.Unmarshal(evidenceTypes, &m.EvidenceTypes); err != nil {
+			m.EvidenceTypes = []string{}
+		}
+		if err := json.Unmarshal(applicableLayers, &m.ApplicableLayers); err != nil {
+			m.ApplicableLayers = []string{}
+		}
+
+		matches = append(matches, m)
+	}
+
+	return matches, total, rows.Err()
+}
+
+// likeEscaper escapes the LIKE wildcards in a literal pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // -----------------------------------------------------------------------------
 // Crosswalk Operations
 // -----------------------------------------------------------------------------
 
+// crosswalkColumns are the columns scanned by scanCrosswalk.
+const crosswalkColumns = `id, source_framework_id, source_control_id, target_framework_id, target_control_id,
+		       mapping_type, confidence, rationale, gaps, supplements, evidence_mapping,
+		       review_state, reviewed_by, reviewed_at, approved_by, approved_at, created_at, updated_at`
+
 // GetCrosswalk returns crosswalks between two frameworks.
 func (r *ControlRepository) GetCrosswalk(ctx context.Context, sourceFrameworkID, targetFrameworkID string) ([]models.Crosswalk, error) {
 	query := `
-		SELECT id, source_framework_id, source_control_id, target_framework_id, target_control_id,
-		       mapping_type, confidence, rationale, gaps, supplements, evidence_mapping, created_at, updated_at
+		SELECT ` + crosswalkColumns + `
 		FROM crosswalks
 		WHERE source_framework_id = $1 AND target_framework_id = $2
 		ORDER BY source_control_id`
 
-	rows, err := r.db.Pool.Query(ctx, query, sourceFrameworkID, targetFrameworkID)
+	rows, err := r.db.reader(ctx).Query(ctx, query, sourceFrameworkID, targetFrameworkID)
 	if err != nil {
 		return nil, fmt.Errorf("querying crosswalks: %w", err)
 	}
@@ -301,54 +577,119 @@ func (r *ControlRepository) GetCrosswalk(ctx context.Context, sourceFrameworkID,
 
 	var crosswalks []models.Crosswalk
 	for rows.Next() {
-		var cw models.Crosswalk
-		var gaps, supplements, evidenceMapping []byte
-
-		if err := rows.Scan(
-			&cw.ID, &cw.SourceFrameworkID, &cw.SourceControlID,
-			&cw.TargetFrameworkID, &cw.TargetControlID,
-			&cw.MappingType, &cw.Confidence, &cw.Rationale,
-			&gaps, &supplements, &evidenceMapping,
-			&cw.CreatedAt, &cw.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scanning crosswalk: %w", err)
+		cw, err := scanCrosswalk(rows)
+		if err != nil {
+			return nil, err
 		}
+		crosswalks = append(crosswalks, *cw)
+	}
 
-		if err := json.Unmarshal(gaps, &cw.Gaps); err != nil {
-			return nil, fmt.Errorf("unmarshaling crosswalk gaps: %w", err)
-		}
-		if err := json.Unmarshal(supplements, &cw.Supplements); err != nil {
-			return nil, fmt.Errorf("unmarshaling crosswalk supplements: %w", err)
-		}
-		if err := json.Unmarshal(evidenceMapping, &cw.EvidenceMapping); err != nil {
-			return nil, fmt.Errorf("unmarshaling crosswalk evidence_mapping: %w", err)
-		}
+	return crosswalks, rows.Err()
+}
 
-		crosswalks = append(crosswalks, cw)
+// GetCrosswalkByID returns a crosswalk, or nil if there is none.
+func (r *ControlRepository) GetCrosswalkByID(ctx context.Context, id string) (*models.Crosswalk, error) {
+	query := `SELECT ` + crosswalkColumns + ` FROM crosswalks WHERE id = $1`
+
+	cw, err := scanCrosswalk(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("getting crosswalk %s: %w", id, err)
+	}
+	return cw, nil
+}
 
-	return crosswalks, rows.Err()
+// scanCrosswalk scans a row of crosswalkColumns. pgx.ErrNoRows is returned
+// unwrapped so callers can detect a missing crosswalk.
+func scanCrosswalk(row pgx.Row) (*models.Crosswalk, error) {
+	var cw models.Crosswalk
+	var gaps, supplements, evidenceMapping []byte
+
+	if err := row.Scan(
+		&cw.ID, &cw.SourceFrameworkID, &cw.SourceControlID,
+		&cw.TargetFrameworkID, &cw.TargetControlID,
+		&cw.MappingType, &cw.Confidence, &cw.Rationale,
+		&gaps, &supplements, &evidenceMapping,
+		&cw.ReviewState, &cw.ReviewedBy, &cw.ReviewedAt, &cw.ApprovedBy, &cw.ApprovedAt,
+		&cw.CreatedAt, &cw.UpdatedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning crosswalk: %w", err)
+	}
+
+	if err := json.Unmarshal(gaps, &cw.Gaps); err != nil {
+		return nil, fmt.Errorf("unmarshaling crosswalk gaps: %w", err)
+	}
+	if err := json.Unmarshal(supplements, &cw.Supplements); err != nil {
+		return nil, fmt.Errorf("unmarshaling crosswalk supplements: %w", err)
+	}
+	if err := json.Unmarshal(evidenceMapping, &cw.EvidenceMapping); err != nil {
+		return nil, fmt.Errorf("unmarshaling crosswalk evidence_mapping: %w", err)
+	}
+	return &cw, nil
 }
 
-// CreateCrosswalk creates a new crosswalk.
+// CreateCrosswalk creates a new crosswalk. Crosswalks without a review
+// state are created approved.
 func (r *ControlRepository) CreateCrosswalk(ctx context.Context, cw *models.Crosswalk) error {
 	gaps, _ := json.Marshal(cw.Gaps)
 	supplements, _ := json.Marshal(cw.Supplements)
 	evidenceMapping, _ := json.Marshal(cw.EvidenceMapping)
+	if cw.ReviewState == "" {
+		cw.ReviewState = models.ReviewApproved
+	}
 
 	query := `
 		INSERT INTO crosswalks (id, source_framework_id, source_control_id, target_framework_id, target_control_id,
-		                        mapping_type, confidence, rationale, gaps, supplements, evidence_mapping)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+		                        mapping_type, confidence, rationale, gaps, supplements, evidence_mapping,
+		                        review_state, reviewed_by, reviewed_at, approved_by, approved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING created_at, updated_at`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	err := r.db.conn(ctx).QueryRow(ctx, query,
 		cw.ID, cw.SourceFrameworkID, cw.SourceControlID,
 		cw.TargetFrameworkID, cw.TargetControlID,
 		cw.MappingType, cw.Confidence, cw.Rationale,
 		gaps, supplements, evidenceMapping,
-	)
+		cw.ReviewState, cw.ReviewedBy, cw.ReviewedAt, cw.ApprovedBy, cw.ApprovedAt,
+	).Scan(&cw.CreatedAt, &cw.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("creating crosswalk: %w", err)
+		return fmt.Errorf("creating crosswalk: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// UpdateCrosswalk replaces a crosswalk's mapping and review fields.
+func (r *ControlRepository) UpdateCrosswalk(ctx context.Context, cw *models.Crosswalk) error {
+	gaps, _ := json.Marshal(cw.Gaps)
+	supplements, _ := json.Marshal(cw.Supplements)
+	evidenceMapping, _ := json.Marshal(cw.EvidenceMapping)
+
+	query := `
+		UPDATE crosswalks
+		SET source_framework_id = $2, source_control_id = $3, target_framework_id = $4, target_control_id = $5,
+		    mapping_type = $6, confidence = $7, rationale = $8, gaps = $9, supplements = $10, evidence_mapping = $11,
+		    review_state = $12, reviewed_by = $13, reviewed_at = $14, approved_by = $15, approved_at = $16,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query,
+		cw.ID, cw.SourceFrameworkID, cw.SourceControlID,
+		cw.TargetFrameworkID, cw.TargetControlID,
+		cw.MappingType, cw.Confidence, cw.Rationale,
+		gaps, supplements, evidenceMapping,
+		cw.ReviewState, cw.ReviewedBy, cw.ReviewedAt, cw.ApprovedBy, cw.ApprovedAt,
+	).Scan(&cw.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("crosswalk %s: %w", cw.ID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("updating crosswalk: %w", mapError(err))
 	}
 
 	return nil
@@ -358,13 +699,75 @@ func (r *ControlRepository) CreateCrosswalk(ctx context.Context, cw *models.Cros
 func (r *ControlRepository) DeleteCrosswalk(ctx context.Context, id string) error {
 	query := `DELETE FROM crosswalks WHERE id = $1`
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+	result, err := r.db.conn(ctx).Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("deleting crosswalk: %w", err)
+		return fmt.Errorf("deleting crosswalk: %w", mapError(err))
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("crosswalk %s not found", id)
+		return fmt.Errorf("crosswalk %s: %w", id, repository.ErrNotFound)
 	}
 
 	return nil
 }
+
+// -----------------------------------------------------------------------------
+// Control Tags
+// -----------------------------------------------------------------------------
+
+// ListControlTags returns an organization's tagged controls ordered by
+// framework and control ID, of one framework unless frameworkID is empty.
+func (r *ControlRepository) ListControlTags(ctx context.Context, orgID, frameworkID string) ([]models.ControlTags, error) {
+	query := `
+		SELECT organization_id, framework_id, control_id, tags, updated_at
+		FROM control_tags
+		WHERE organization_id = $1 AND ($2 = '' OR framework_id = $2)
+		ORDER BY framework_id, control_id`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, orgID, frameworkID)
+	if err != nil {
+		return nil, fmt.Errorf("querying control tags: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.ControlTags
+	for rows.Next() {
+		var t models.ControlTags
+		var tags []byte
+		if err := rows.Scan(&t.OrganizationID, &t.FrameworkID, &t.ControlID, &tags, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning control tags: %w", err)
+		}
+		if err := json.Unmarshal(tags, &t.Tags); err != nil {
+			t.Tags = []string{}
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// SetControlTags replaces a control's tags. Setting no tags removes them.
+func (r *ControlRepository) SetControlTags(ctx context.Context, t *models.ControlTags) error {
+	if len(t.Tags) == 0 {
+		_, err := r.db.conn(ctx).Exec(ctx, `
+			DELETE FROM control_tags
+			WHERE organization_id = $1 AND framework_id = $2 AND LOWER(control_id) = LOWER($3)`,
+			t.OrganizationID, t.FrameworkID, t.ControlID)
+		if err != nil {
+			return fmt.Errorf("deleting control tags: %w", mapError(err))
+		}
+		return nil
+	}
+
+	tags, _ := json.Marshal(t.Tags)
+	query := `
+		INSERT INTO control_tags (organization_id, framework_id, control_id, tags)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, framework_id, LOWER(control_id))
+		DO UPDATE SET control_id = EXCLUDED.control_id, tags = EXCLUDED.tags, updated_at = NOW()
+		RETURNING updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query, t.OrganizationID, t.FrameworkID, t.ControlID, tags).Scan(&t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("setting control tags: %w", mapError(err))
+	}
+	return nil
+}
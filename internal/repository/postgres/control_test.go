@@ -149,6 +149,13 @@ func (m *mockControlRepo) DeleteCrosswalk(_ context.Context, id string) error {
 	return nil
 }
 
+func (m *mockControlRepo) ImportCatalog(_ context.Context, catalog *repository.CatalogImport) error {
+	m.frameworks = append(m.frameworks, catalog.Framework)
+	m.controls = append(m.controls, catalog.Controls...)
+	m.crosswalks = append(m.crosswalks, catalog.Crosswalks...)
+	return nil
+}
+
 // Compile-time interface check
 var _ repository.ControlRepository = (*mockControlRepo)(nil)
 
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// GapDispositionRepository implements repository.GapDispositionRepository
+// for PostgreSQL.
+type GapDispositionRepository struct {
+	db *DB
+}
+
+// NewGapDispositionRepository creates a new GapDispositionRepository.
+func NewGapDispositionRepository(db *DB) *GapDispositionRepository {
+	return &GapDispositionRepository{db: db}
+}
+
+const dispositionColumns = `id, organization_id, framework_id, control_id, disposition, approver, justification,
+		       transferred_to, expires_at, review_interval_days, reviewed_at, reviewed_by, last_reminded_at,
+		       created_by, created_at, updated_at`
+
+// List returns an organization's dispositions ordered by framework and
+// control ID. An empty frameworkID lists every framework.
+func (r *GapDispositionRepository) List(ctx context.Context, orgID, frameworkID string) ([]models.GapDisposition, error) {
+	query := `SELECT ` + dispositionColumns + `
+		FROM gap_dispositions
+		WHERE organization_id = $1 AND ($2 = '' OR framework_id = $2)
+		ORDER BY framework_id, control_id`
+	return r.query(ctx, query, orgID, frameworkID)
+}
+
+// ListRiskAcceptances returns every organization's accept and transfer
+// dispositions.
+func (r *GapDispositionRepository) ListRiskAcceptances(ctx context.Context) ([]models.GapDisposition, error) {
+	query := `SELECT ` + dispositionColumns + `
+		FROM gap_dispositions
+		WHERE disposition IN ('accept', 'transfer')
+		ORDER BY organization_id, framework_id, control_id`
+	return r.query(ctx, query)
+}
+
+func (r *GapDispositionRepository) query(ctx context.Context, query string, args ...any) ([]models.GapDisposition, error) {
+	rows, err := r.db.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying gap dispositions: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.GapDisposition
+	for rows.Next() {
+		d, err := scanDisposition(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *d)
+	}
+	return list, rows.Err()
+}
+
+// Get returns a disposition, or nil if there is none.
+func (r *GapDispositionRepository) Get(ctx context.Context, id string) (*models.GapDisposition, error) {
+	query := `SELECT ` + dispositionColumns + ` FROM gap_dispositions WHERE id = $1`
+
+	d, err := scanDisposition(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting gap disposition %s: %w", id, err)
+	}
+	return d, nil
+}
+
+// Create stores a disposition.
+func (r *GapDispositionRepository) Create(ctx context.Context, d *models.GapDisposition) error {
+	query := `
+		INSERT INTO gap_dispositions (id, organization_id, framework_id, control_id, disposition, approver, justification,
+		                              transferred_to, expires_at, review_interval_days, reviewed_at, reviewed_by,
+		                              last_reminded_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING created_at, updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query,
+		d.ID, d.OrganizationID, d.FrameworkID, d.ControlID, d.Disposition, d.Approver, d.Justification,
+		d.TransferredTo, d.ExpiresAt, d.ReviewIntervalDays, d.ReviewedAt, d.ReviewedBy,
+		d.LastRemindedAt, d.CreatedBy,
+	).Scan(&d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("creating gap disposition: %w", mapError(err))
+	}
+	return nil
+}
+
+// Update replaces a disposition's decision, approval, expiry, review and
+// reminder fields.
+func (r *GapDispositionRepository) Update(ctx context.Context, d *models.GapDisposition) error {
+	query := `
+		UPDATE gap_dispositions
+		SET disposition = $2, approver = $3, justification = $4, transferred_to = $5, expires_at = $6,
+		    review_interval_days = $7, reviewed_at = $8, reviewed_by = $9, last_reminded_at = $10, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query,
+		d.ID, d.Disposition, d.Approver, d.Justification, d.TransferredTo, d.ExpiresAt,
+		d.ReviewIntervalDays, d.ReviewedAt, d.ReviewedBy, d.LastRemindedAt,
+	).Scan(&d.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("gap disposition %s: %w", d.ID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("updating gap disposition: %w", mapError(err))
+	}
+	return nil
+}
+
+// Delete removes a disposition.
+func (r *GapDispositionRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM gap_dispositions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting gap disposition: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("gap disposition %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// scanDisposition scans a row of dispositionColumns. pgx.ErrNoRows is
+// returned unwrapped so callers can detect a missing disposition.
+func scanDisposition(row pgx.Row) (*models.GapDisposition, error) {
+	var d models.GapDisposition
+	if err := row.Scan(
+		&d.ID, &d.OrganizationID, &d.FrameworkID, &d.ControlID, &d.Disposition, &d.Approver, &d.Justification,
+		&d.TransferredTo, &d.ExpiresAt, &d.ReviewIntervalDays, &d.ReviewedAt, &d.ReviewedBy, &d.LastRemindedAt,
+		&d.CreatedBy, &d.CreatedAt, &d.UpdatedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning gap disposition: %w", err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErasureStore erases data subjects from the agent_traces table. It
+// implements privacy.Store.
+type ErasureStore struct {
+	db *DB
+}
+
+// NewErasureStore creates an erasure store.
+func NewErasureStore(db *DB) *ErasureStore {
+	return &ErasureStore{db: db}
+}
+
+// Name implements privacy.Store.
+func (s *ErasureStore) Name() string { return "postgres" }
+
+// Erase implements privacy.Store. agent_traces has no organization column,
+// so the subject's traces are erased in every organization.
+func (s *ErasureStore) Erase(ctx context.Context, plan *privacy.Plan) (privacy.StoreResult, error) {
+	var res privacy.StoreResult
+	res.Notes = append(res.Notes, "agent_traces is not partitioned by organization; the user ID was erased across organizations")
+
+	if plan.Mode == privacy.ModeDelete {
+		// security_signals rows cascade with their trace.
+		tag, err := s.db.Pool.Exec(ctx, `DELETE FROM agent_traces WHERE user_id = $1`, plan.UserID)
+		if err != nil {
+			return res, fmt.Errorf("deleting traces: %w", err)
+		}
+		res.Affected = tag.RowsAffected()
+		return res, nil
+	}
+
+	err := pgx.BeginFunc(ctx, s.db.Pool, func(tx pgx.Tx) error {
+		switch action, ok := plan.Action(privacy.FieldSessionID); {
+		case !ok:
+		case action == privacy.ActionRedact:
+			if _, err := tx.Exec(ctx, `UPDATE agent_traces SET session_id = NULL WHERE user_id = $1`, plan.UserID); err != nil {
+				return fmt.Errorf("redacting sessions: %w", err)
+			}
+		default:
+			rows, err := tx.Query(ctx, `SELECT DISTINCT session_id FROM agent_traces WHERE user_id = $1 AND session_id IS NOT NULL`, plan.UserID)
+			if err != nil {
+				return fmt.Errorf("querying sessions: %w", err)
+			}
+			sessions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return fmt.Errorf("scanning sessions: %w", err)
+			}
+			pseudonyms := make([]string, len(sessions))
+			for i, id := range sessions {
+				pseudonyms[i] = plan.Pseudonymize(id)
+			}
+			if _, err := tx.Exec(ctx, `
+				UPDATE agent_traces t SET session_id = m.pseudonym
+				FROM unnest($2::text[], $3::text[]) AS m(session_id, pseudonym)
+				WHERE t.user_id = $1 AND t.session_id = m.session_id`,
+				plan.UserID, sessions, pseudonyms); err != nil {
+				return fmt.Errorf("pseudonymizing sessions: %w", err)
+			}
+		}
+
+		replacement := &plan.Pseudonym
+		if action, _ := plan.Action(privacy.FieldUserID); action == privacy.ActionRedact {
+			replacement = nil
+		}
+		keys := plan.Attributes()
+		if keys == nil {
+			keys = []string{}
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE agent_traces
+			SET user_id = $2, metadata = COALESCE(metadata, '{}'::jsonb) - $3::text[]
+			WHERE user_id = $1`,
+			plan.UserID, replacement, keys)
+		if err != nil {
+			return fmt.Errorf("pseudonymizing traces: %w", err)
+		}
+		res.Affected = tag.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	if _, ok := plan.Action(privacy.FieldPayloads); ok {
+		res.Notes = append(res.Notes, "span inputs embedded in agent_traces.spans are not rewritten")
+	}
+	return res, nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL SQLSTATE codes mapped to repository errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// mapError wraps err with the matching repository sentinel error while keeping
+// the original error in the chain. Unrecognized errors are returned unchanged.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", repository.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return fmt.Errorf("%w (%s): %w", repository.ErrConflict, pgErr.ConstraintName, err)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w (%s): %w", repository.ErrForeignKey, pgErr.ConstraintName, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "no rows", err: pgx.ErrNoRows, want: repository.ErrNotFound},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505", ConstraintName: "frameworks_pkey"}, want: repository.ErrConflict},
+		{name: "foreign key violation", err: fmt.Errorf("exec: %w", &pgconn.PgError{Code: "23503"}), want: repository.ErrForeignKey},
+		{name: "other error unchanged", err: &pgconn.PgError{Code: "42P01"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapError(tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("mapped error lost original: %v", got)
+			}
+			for _, sentinel := range []error{repository.ErrNotFound, repository.ErrConflict, repository.ErrForeignKey} {
+				if errors.Is(got, sentinel) != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", got, sentinel, !(sentinel == tt.want))
+				}
+			}
+		})
+	}
+}
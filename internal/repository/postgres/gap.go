@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GapAnalysisRepository implements repository.GapAnalysisRepository for
+// PostgreSQL.
+type GapAnalysisRepository struct {
+	db *DB
+}
+
+// NewGapAnalysisRepository creates a new GapAnalysisRepository.
+func NewGapAnalysisRepository(db *DB) *GapAnalysisRepository {
+	return &GapAnalysisRepository{db: db}
+}
+
+const gapAnalysisColumns = `id, organization_id, source_framework_id, target_framework_id, analysis_date, gaps, summary`
+
+// List returns an organization's gap analyses, newest first.
+func (r *GapAnalysisRepository) List(ctx context.Context, orgID string) ([]models.GapAnalysis, error) {
+	query := `SELECT ` + gapAnalysisColumns + `
+		FROM gap_analyses
+		WHERE organization_id = $1
+		ORDER BY analysis_date DESC, id`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("querying gap analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var analyses []models.GapAnalysis
+	for rows.Next() {
+		ga, err := scanGapAnalysis(rows)
+		if err != nil {
+			return nil, err
+		}
+		analyses = append(analyses, *ga)
+	}
+	return analyses, rows.Err()
+}
+
+// Get returns a gap analysis, or nil if there is none.
+func (r *GapAnalysisRepository) Get(ctx context.Context, id string) (*models.GapAnalysis, error) {
+	query := `SELECT ` + gapAnalysisColumns + ` FROM gap_analyses WHERE id = $1`
+
+	ga, err := scanGapAnalysis(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting gap analysis %s: %w", id, err)
+	}
+	return ga, nil
+}
+
+// Create stores a gap analysis.
+func (r *GapAnalysisRepository) Create(ctx context.Context, ga *models.GapAnalysis) error {
+	gaps, err := json.Marshal(ga.Gaps)
+	if err != nil {
+		return fmt.Errorf("marshaling gap analysis gaps: %w", err)
+	}
+	summary, err := json.Marshal(ga.Summary)
+	if err != nil {
+		return fmt.Errorf("marshaling gap analysis summary: %w", err)
+	}
+
+	query := `
+		INSERT INTO gap_analyses (` + gapAnalysisColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = r.db.conn(ctx).Exec(ctx, query,
+		ga.ID, ga.OrganizationID, ga.SourceFrameworkID, ga.TargetFrameworkID,
+		ga.AnalysisDate, gaps, summary,
+	)
+	if err != nil {
+		return fmt.Errorf("creating gap analysis: %w", err)
+	}
+	return nil
+}
+
+// scanGapAnalysis scans a row of gapAnalysisColumns. pgx.ErrNoRows is
+// returned unwrapped so callers can detect a missing analysis.
+func scanGapAnalysis(row pgx.Row) (*models.GapAnalysis, error) {
+	var ga models.GapAnalysis
+	var gaps, summary []byte
+
+	if err := row.Scan(
+		&ga.ID, &ga.OrganizationID, &ga.SourceFrameworkID, &ga.TargetFrameworkID,
+		&ga.AnalysisDate, &gaps, &summary,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning gap analysis: %w", err)
+	}
+
+	if err := json.Unmarshal(gaps, &ga.Gaps); err != nil {
+		return nil, fmt.Errorf("unmarshaling gap analysis gaps: %w", err)
+	}
+	if err := json.Unmarshal(summary, &ga.Summary); err != nil {
+		return nil, fmt.Errorf("unmarshaling gap analysis summary: %w", err)
+	}
+	return &ga, nil
+}
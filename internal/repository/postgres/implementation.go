@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ControlImplementationRepository implements
+// repository.ControlImplementationRepository and
+// repository.ControlImplementationHistory for PostgreSQL. A trigger keeps
+// every change in control_implementation_history.
+type ControlImplementationRepository struct {
+	db *DB
+}
+
+// NewControlImplementationRepository creates a new
+// ControlImplementationRepository.
+func NewControlImplementationRepository(db *DB) *ControlImplementationRepository {
+	return &ControlImplementationRepository{db: db}
+}
+
+const implementationColumns = `id, organization_id, framework_id, control_id, status, owner, due_date, notes, created_at, updated_at`
+
+// List returns an organization's implementations ordered by framework and
+// control ID. An empty frameworkID lists every framework.
+func (r *ControlImplementationRepository) List(ctx context.Context, orgID, frameworkID string) ([]models.ControlImplementation, error) {
+	query := `SELECT ` + implementationColumns + `
+		FROM control_implementations
+		WHERE organization_id = $1 AND ($2 = '' OR framework_id = $2)
+		ORDER BY framework_id, control_id`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, orgID, frameworkID)
+	if err != nil {
+		return nil, fmt.Errorf("querying control implementations: %w", err)
+	}
+	defer rows.Close()
+
+	var impls []models.ControlImplementation
+	for rows.Next() {
+		ci, err := scanImplementation(rows)
+		if err != nil {
+			return nil, err
+		}
+		impls = append(impls, *ci)
+	}
+	return impls, rows.Err()
+}
+
+// ListAsOf returns an organization's implementations as they stood at asOf,
+// ordered like List.
+func (r *ControlImplementationRepository) ListAsOf(ctx context.Context, orgID, frameworkID string, asOf time.Time) ([]models.ControlImplementation, error) {
+	query := `SELECT implementation_id, organization_id, framework_id, control_id, status, owner, due_date, notes, created_at, updated_at
+		FROM control_implementation_history
+		WHERE organization_id = $1 AND ($2 = '' OR framework_id = $2)
+			AND valid_from <= $3 AND (valid_to IS NULL OR valid_to > $3)
+		ORDER BY framework_id, control_id`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, orgID, frameworkID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("querying control implementation history: %w", err)
+	}
+	defer rows.Close()
+
+	var impls []models.ControlImplementation
+	for rows.Next() {
+		ci, err := scanImplementation(rows)
+		if err != nil {
+			return nil, err
+		}
+		impls = append(impls, *ci)
+	}
+	return impls, rows.Err()
+}
+
+// Get returns a control implementation, or nil if there is none.
+func (r *ControlImplementationRepository) Get(ctx context.Context, id string) (*models.ControlImplementation, error) {
+	query := `SELECT ` + implementationColumns + ` FROM control_implementations WHERE id = $1`
+
+	ci, err := scanImplementation(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting control implementation %s: %w", id, err)
+	}
+	return ci, nil
+}
+
+// Create stores a control implementation.
+func (r *ControlImplementationRepository) Create(ctx context.Context, ci *models.ControlImplementation) error {
+	query := `
+		INSERT INTO control_implementations (id, organization_id, framework_id, control_id, status, owner, due_date, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query,
+		ci.ID, ci.OrganizationID, ci.FrameworkID, ci.ControlID,
+		ci.Status, ci.Owner, ci.DueDate, ci.Notes,
+	).Scan(&ci.CreatedAt, &ci.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("creating control implementation: %w", mapError(err))
+	}
+	return nil
+}
+
+// Update replaces a control implementation's status, owner, due date and
+// notes.
+func (r *ControlImplementationRepository) Update(ctx context.Context, ci *models.ControlImplementation) error {
+	query := `
+		UPDATE control_implementations
+		SET status = $2, owner = $3, due_date = $4, notes = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.db.conn(ctx).QueryRow(ctx, query,
+		ci.ID, ci.Status, ci.Owner, ci.DueDate, ci.Notes,
+	).Scan(&ci.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("control implementation %s: %w", ci.ID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("updating control implementation: %w", mapError(err))
+	}
+	return nil
+}
+
+// Delete removes a control implementation.
+func (r *ControlImplementationRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM control_implementations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting control implementation: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("control implementation %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// scanImplementation scans a row of implementationColumns. pgx.ErrNoRows is
+// returned unwrapped so callers can detect a missing implementation.
+func scanImplementation(row pgx.Row) (*models.ControlImplementation, error) {
+	var ci models.ControlImplementation
+	if err := row.Scan(
+		&ci.ID, &ci.OrganizationID, &ci.FrameworkID, &ci.ControlID,
+		&ci.Status, &ci.Owner, &ci.DueDate, &ci.Notes, &ci.CreatedAt, &ci.UpdatedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning control implementation: %w", err)
+	}
+	return &ci, nil
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-const schemaVersion = 1
+const schemaVersion = 16
 
 var migrations = []struct {
 	version     int
@@ -92,6 +92,464 @@ var migrations = []struct {
 			ON CONFLICT (version) DO NOTHING;
 		`,
 	},
+	{
+		version:     2,
+		description: "control families for full catalogs",
+		sql: `
+			ALTER TABLE controls ADD COLUMN IF NOT EXISTS family TEXT NOT NULL DEFAULT '';
+
+			CREATE INDEX IF NOT EXISTS idx_controls_family ON controls(framework_id, family);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (2, 'control families for full catalogs')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     3,
+		description: "superseded framework versions",
+		sql: `
+			CREATE TABLE IF NOT EXISTS framework_versions (
+				framework_id TEXT NOT NULL REFERENCES frameworks(id) ON DELETE CASCADE,
+				version      TEXT NOT NULL,
+				name         TEXT NOT NULL,
+				publisher    TEXT NOT NULL DEFAULT '',
+				description  TEXT NOT NULL DEFAULT '',
+				url          TEXT NOT NULL DEFAULT '',
+				controls     JSONB NOT NULL DEFAULT '[]',
+				created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (framework_id, version)
+			);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (3, 'superseded framework versions')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     4,
+		description: "control full-text search",
+		sql: `
+			ALTER TABLE controls ADD COLUMN IF NOT EXISTS search_vector tsvector
+				GENERATED ALWAYS AS (
+					setweight(to_tsvector('english', coalesce(control_id, '')), 'A') ||
+					setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+					setweight(to_tsvector('english', coalesce(description, '')), 'B')
+				) STORED;
+
+			CREATE INDEX IF NOT EXISTS idx_controls_search ON controls USING GIN (search_vector);
+			CREATE INDEX IF NOT EXISTS idx_controls_layers ON controls USING GIN (applicable_layers);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (4, 'control full-text search')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     5,
+		description: "crosswalk review state",
+		sql: `
+			ALTER TABLE crosswalks
+				ADD COLUMN IF NOT EXISTS review_state TEXT NOT NULL DEFAULT 'approved',
+				ADD COLUMN IF NOT EXISTS reviewed_by  TEXT NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS reviewed_at  TIMESTAMPTZ,
+				ADD COLUMN IF NOT EXISTS approved_by  TEXT NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS approved_at  TIMESTAMPTZ;
+
+			CREATE INDEX IF NOT EXISTS idx_crosswalks_review_state ON crosswalks(review_state)
+				WHERE review_state <> 'approved';
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (5, 'crosswalk review state')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     6,
+		description: "gap analysis history",
+		sql: `
+			CREATE TABLE IF NOT EXISTS gap_analyses (
+				id                  TEXT PRIMARY KEY,
+				organization_id     TEXT NOT NULL,
+				source_framework_id TEXT NOT NULL DEFAULT '',
+				target_framework_id TEXT NOT NULL,
+				analysis_date       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				gaps                JSONB NOT NULL DEFAULT '[]',
+				summary             JSONB NOT NULL DEFAULT '{}'
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_gap_analyses_org_date ON gap_analyses(organization_id, analysis_date DESC);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (6, 'gap analysis history')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     7,
+		description: "control implementation tracking",
+		sql: `
+			CREATE TABLE IF NOT EXISTS control_implementations (
+				id              TEXT PRIMARY KEY,
+				organization_id TEXT NOT NULL,
+				framework_id    TEXT NOT NULL,
+				control_id      TEXT NOT NULL,
+				status          TEXT NOT NULL DEFAULT 'planned',
+				owner           TEXT NOT NULL DEFAULT '',
+				due_date        DATE,
+				notes           TEXT NOT NULL DEFAULT '',
+				created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				UNIQUE (organization_id, framework_id, control_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_control_implementations_due ON control_implementations(organization_id, due_date)
+				WHERE status IN ('planned', 'in_progress');
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (7, 'control implementation tracking')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     8,
+		description: "attestation campaigns",
+		sql: `
+			CREATE TABLE IF NOT EXISTS attestation_campaigns (
+				id                     TEXT PRIMARY KEY,
+				organization_id        TEXT NOT NULL,
+				name                   TEXT NOT NULL,
+				framework_id           TEXT NOT NULL DEFAULT '',
+				status                 TEXT NOT NULL DEFAULT 'open',
+				due_date               DATE NOT NULL,
+				reminder_interval_days INTEGER NOT NULL DEFAULT 0,
+				created_by             TEXT NOT NULL DEFAULT '',
+				created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				closed_at              TIMESTAMPTZ
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_attestation_campaigns_org ON attestation_campaigns(organization_id, created_at DESC);
+			CREATE INDEX IF NOT EXISTS idx_attestation_campaigns_open ON attestation_campaigns(due_date) WHERE status = 'open';
+
+			CREATE TABLE IF NOT EXISTS attestations (
+				id                TEXT PRIMARY KEY,
+				campaign_id       TEXT NOT NULL REFERENCES attestation_campaigns(id) ON DELETE CASCADE,
+				organization_id   TEXT NOT NULL,
+				implementation_id TEXT NOT NULL,
+				framework_id      TEXT NOT NULL,
+				control_id        TEXT NOT NULL,
+				owner             TEXT NOT NULL,
+				status            TEXT NOT NULL DEFAULT 'pending',
+				comment           TEXT NOT NULL DEFAULT '',
+				responded_by      TEXT NOT NULL DEFAULT '',
+				responded_at      TIMESTAMPTZ,
+				reminders         INTEGER NOT NULL DEFAULT 0,
+				last_reminded_at  TIMESTAMPTZ,
+				created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				UNIQUE (campaign_id, framework_id, control_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_attestations_owner ON attestations(organization_id, owner) WHERE status = 'pending';
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (8, 'attestation campaigns')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     9,
+		description: "control implementation history",
+		sql: `
+			CREATE TABLE IF NOT EXISTS control_implementation_history (
+				implementation_id TEXT NOT NULL,
+				organization_id   TEXT NOT NULL,
+				framework_id      TEXT NOT NULL,
+				control_id        TEXT NOT NULL,
+				status            TEXT NOT NULL,
+				owner             TEXT NOT NULL,
+				due_date          DATE,
+				notes             TEXT NOT NULL,
+				created_at        TIMESTAMPTZ NOT NULL,
+				updated_at        TIMESTAMPTZ NOT NULL,
+				valid_from        TIMESTAMPTZ NOT NULL,
+				valid_to          TIMESTAMPTZ
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_control_implementation_history_org
+				ON control_implementation_history(organization_id, valid_from);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_control_implementation_history_current
+				ON control_implementation_history(implementation_id) WHERE valid_to IS NULL;
+
+			-- Existing implementations are known only as of their last update.
+			INSERT INTO control_implementation_history
+				(implementation_id, organization_id, framework_id, control_id, status, owner, due_date, notes, created_at, updated_at, valid_from)
+			SELECT id, organization_id, framework_id, control_id, status, owner, due_date, notes, created_at, updated_at, updated_at
+			FROM control_implementations
+			ON CONFLICT DO NOTHING;
+
+			CREATE OR REPLACE FUNCTION record_control_implementation_history() RETURNS TRIGGER AS $$
+			DECLARE
+				changed_at TIMESTAMPTZ := CASE WHEN TG_OP = 'DELETE' THEN NOW() ELSE NEW.updated_at END;
+			BEGIN
+				IF TG_OP <> 'INSERT' THEN
+					UPDATE control_implementation_history SET valid_to = changed_at
+					WHERE implementation_id = OLD.id AND valid_to IS NULL;
+				END IF;
+				IF TG_OP = 'DELETE' THEN
+					RETURN OLD;
+				END IF;
+				INSERT INTO control_implementation_history
+					(implementation_id, organization_id, framework_id, control_id, status, owner, due_date, notes, created_at, updated_at, valid_from)
+				VALUES (NEW.id, NEW.organization_id, NEW.framework_id, NEW.control_id, NEW.status, NEW.owner, NEW.due_date, NEW.notes, NEW.created_at, NEW.updated_at, changed_at);
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql;
+
+			DROP TRIGGER IF EXISTS control_implementation_history ON control_implementations;
+			CREATE TRIGGER control_implementation_history
+				AFTER INSERT OR UPDATE OR DELETE ON control_implementations
+				FOR EACH ROW EXECUTE FUNCTION record_control_implementation_history();
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (9, 'control implementation history')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     10,
+		description: "control tags",
+		sql: `
+			CREATE TABLE IF NOT EXISTS control_tags (
+				organization_id TEXT NOT NULL,
+				framework_id    TEXT NOT NULL,
+				control_id      TEXT NOT NULL,
+				tags            JSONB NOT NULL DEFAULT '[]',
+				updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_control_tags_control
+				ON control_tags(organization_id, framework_id, LOWER(control_id));
+			CREATE INDEX IF NOT EXISTS idx_control_tags_tags ON control_tags USING GIN (tags);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (10, 'control tags')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     11,
+		description: "control evidence type index",
+		sql: `
+			CREATE INDEX IF NOT EXISTS idx_controls_evidence_types
+				ON controls USING GIN ((LOWER(evidence_types::text)::jsonb));
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (11, 'control evidence type index')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     12,
+		description: "gap dispositions",
+		sql: `
+			CREATE TABLE IF NOT EXISTS gap_dispositions (
+				id                   TEXT PRIMARY KEY,
+				organization_id      TEXT NOT NULL,
+				framework_id         TEXT NOT NULL,
+				control_id           TEXT NOT NULL,
+				disposition          TEXT NOT NULL,
+				approver             TEXT NOT NULL DEFAULT '',
+				justification        TEXT NOT NULL DEFAULT '',
+				transferred_to       TEXT NOT NULL DEFAULT '',
+				expires_at           TIMESTAMPTZ,
+				review_interval_days INTEGER NOT NULL DEFAULT 0,
+				reviewed_at          TIMESTAMPTZ,
+				reviewed_by          TEXT NOT NULL DEFAULT '',
+				last_reminded_at     TIMESTAMPTZ,
+				created_by           TEXT NOT NULL DEFAULT '',
+				created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_gap_dispositions_control
+				ON gap_dispositions(organization_id, framework_id, LOWER(control_id));
+			CREATE INDEX IF NOT EXISTS idx_gap_dispositions_risk ON gap_dispositions(expires_at)
+				WHERE disposition IN ('accept', 'transfer');
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (12, 'gap dispositions')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     13,
+		description: "agent registry",
+		sql: `
+			CREATE TABLE IF NOT EXISTS agents (
+				id             UUID PRIMARY KEY,
+				name           TEXT NOT NULL,
+				description    TEXT NOT NULL DEFAULT '',
+				framework      TEXT NOT NULL,
+				version        TEXT NOT NULL DEFAULT '',
+				owner          TEXT NOT NULL DEFAULT '',
+				team           TEXT NOT NULL DEFAULT '',
+				environment    TEXT NOT NULL,
+				capabilities   JSONB NOT NULL DEFAULT '[]',
+				tools          JSONB NOT NULL DEFAULT '[]',
+				policies       JSONB NOT NULL DEFAULT '[]',
+				risk_level     TEXT NOT NULL DEFAULT '',
+				status         TEXT NOT NULL DEFAULT 'active',
+				last_active_at TIMESTAMPTZ,
+				created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_agents_name ON agents(name, id);
+			CREATE INDEX IF NOT EXISTS idx_agents_status ON agents(status);
+			CREATE INDEX IF NOT EXISTS idx_agents_team ON agents(team);
+
+			CREATE TABLE IF NOT EXISTS agent_history (
+				agent_id       UUID NOT NULL,
+				name           TEXT NOT NULL,
+				description    TEXT NOT NULL,
+				framework      TEXT NOT NULL,
+				version        TEXT NOT NULL,
+				owner          TEXT NOT NULL,
+				team           TEXT NOT NULL,
+				environment    TEXT NOT NULL,
+				capabilities   JSONB NOT NULL,
+				tools          JSONB NOT NULL,
+				policies       JSONB NOT NULL,
+				risk_level     TEXT NOT NULL,
+				status         TEXT NOT NULL,
+				last_active_at TIMESTAMPTZ,
+				created_at     TIMESTAMPTZ NOT NULL,
+				updated_at     TIMESTAMPTZ NOT NULL,
+				valid_from     TIMESTAMPTZ NOT NULL,
+				valid_to       TIMESTAMPTZ
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_agent_history_agent ON agent_history(agent_id, valid_from);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_agent_history_current
+				ON agent_history(agent_id) WHERE valid_to IS NULL;
+
+			CREATE OR REPLACE FUNCTION record_agent_history() RETURNS TRIGGER AS $$
+			DECLARE
+				changed_at TIMESTAMPTZ := CASE WHEN TG_OP = 'DELETE' THEN NOW() ELSE NEW.updated_at END;
+			BEGIN
+				IF TG_OP <> 'INSERT' THEN
+					UPDATE agent_history SET valid_to = changed_at
+					WHERE agent_id = OLD.id AND valid_to IS NULL;
+				END IF;
+				IF TG_OP = 'DELETE' THEN
+					RETURN OLD;
+				END IF;
+				INSERT INTO agent_history
+					(agent_id, name, description, framework, version, owner, team, environment, capabilities, tools, policies,
+					 risk_level, status, last_active_at, created_at, updated_at, valid_from)
+				VALUES (NEW.id, NEW.name, NEW.description, NEW.framework, NEW.version, NEW.owner, NEW.team, NEW.environment,
+					NEW.capabilities, NEW.tools, NEW.policies, NEW.risk_level, NEW.status, NEW.last_active_at,
+					NEW.created_at, NEW.updated_at, changed_at);
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql;
+
+			DROP TRIGGER IF EXISTS agent_history ON agents;
+			CREATE TRIGGER agent_history
+				AFTER INSERT OR UPDATE OR DELETE ON agents
+				FOR EACH ROW EXECUTE FUNCTION record_agent_history();
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (13, 'agent registry')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     14,
+		description: "policies",
+		sql: `
+			CREATE TABLE IF NOT EXISTS policies (
+				id          TEXT PRIMARY KEY,
+				name        TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				type        TEXT NOT NULL,
+				version     TEXT NOT NULL DEFAULT '',
+				scope       JSONB NOT NULL DEFAULT '{}',
+				rules       JSONB NOT NULL DEFAULT '[]',
+				enabled     BOOLEAN NOT NULL DEFAULT TRUE,
+				priority    INTEGER NOT NULL DEFAULT 0,
+				metadata    JSONB NOT NULL DEFAULT '{}',
+				created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_policies_order ON policies(priority DESC, name, id);
+			CREATE INDEX IF NOT EXISTS idx_policies_type ON policies(type);
+			CREATE INDEX IF NOT EXISTS idx_agents_policies ON agents USING GIN (policies);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (14, 'policies')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     15,
+		description: "agent signing keys",
+		sql: `
+			CREATE TABLE IF NOT EXISTS agent_signing_keys (
+				id         TEXT PRIMARY KEY,
+				agent_id   TEXT NOT NULL,
+				secret     TEXT NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				expires_at TIMESTAMPTZ
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_agent_signing_keys_agent ON agent_signing_keys(agent_id, created_at);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (15, 'agent signing keys')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
+	{
+		version:     16,
+		description: "trace metadata",
+		sql: `
+			CREATE TABLE IF NOT EXISTS trace_metadata (
+				org_id             TEXT NOT NULL,
+				trace_id           TEXT NOT NULL,
+				agent_id           UUID,
+				session_id         TEXT NOT NULL DEFAULT '',
+				user_id            TEXT NOT NULL DEFAULT '',
+				start_time         TIMESTAMPTZ NOT NULL,
+				end_time           TIMESTAMPTZ,
+				duration_ms        BIGINT NOT NULL DEFAULT 0,
+				status             TEXT NOT NULL DEFAULT '',
+				total_spans        INTEGER NOT NULL DEFAULT 0,
+				llm_calls          INTEGER NOT NULL DEFAULT 0,
+				tool_invocations   INTEGER NOT NULL DEFAULT 0,
+				total_tokens       BIGINT NOT NULL DEFAULT 0,
+				estimated_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+				policy_evaluations INTEGER NOT NULL DEFAULT 0,
+				security_signals   INTEGER NOT NULL DEFAULT 0,
+				metadata           JSONB NOT NULL DEFAULT '{}',
+				parent_trace_id    TEXT NOT NULL DEFAULT '',
+				parent_span_id     TEXT NOT NULL DEFAULT '',
+				updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (org_id, trace_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_trace_metadata_start ON trace_metadata(org_id, start_time DESC);
+			CREATE INDEX IF NOT EXISTS idx_trace_metadata_agent ON trace_metadata(org_id, agent_id, start_time DESC);
+			CREATE INDEX IF NOT EXISTS idx_trace_metadata_session ON trace_metadata(org_id, session_id);
+			CREATE INDEX IF NOT EXISTS idx_trace_metadata_user ON trace_metadata(org_id, user_id);
+
+			INSERT INTO schema_migrations (version, description)
+			VALUES (16, 'trace metadata')
+			ON CONFLICT (version) DO NOTHING;
+		`,
+	},
 }
 
 // RunMigrations applies all pending database migrations in order.
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/agentguard/agentguard/internal/repository/postgres"
+
+// queryTracer implements pgx.QueryTracer, recording each query as an OTel
+// client span and logging queries slower than slowThreshold.
+type queryTracer struct {
+	tracer        trace.Tracer
+	database      string
+	slowThreshold time.Duration
+}
+
+type queryTraceKey struct{}
+
+type queryTraceData struct {
+	start time.Time
+	sql   string
+	span  trace.Span
+}
+
+func newQueryTracer(database string, slowThreshold time.Duration) *queryTracer {
+	return &queryTracer{
+		tracer:        otel.Tracer(instrumentationName),
+		database:      database,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// TraceQueryStart starts a span for the query.
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, span := t.tracer.Start(ctx, "postgres "+sqlOperation(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBNamespace(t.database),
+			semconv.DBQueryText(data.SQL),
+			semconv.DBOperationName(sqlOperation(data.SQL)),
+		),
+	)
+	return context.WithValue(ctx, queryTraceKey{}, &queryTraceData{start: time.Now(), sql: data.SQL, span: span})
+}
+
+// TraceQueryEnd finishes the query span and logs slow queries.
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qd, ok := ctx.Value(queryTraceKey{}).(*queryTraceData)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(qd.start)
+
+	if data.Err != nil {
+		qd.span.RecordError(data.Err)
+		qd.span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		qd.span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	qd.span.End()
+
+	if t.slowThreshold > 0 && elapsed >= t.slowThreshold {
+		log.Warn().
+			Dur("duration", elapsed).
+			Str("operation", sqlOperation(qd.sql)).
+			Str("query", compactSQL(qd.sql)).
+			Msg("slow query")
+	}
+}
+
+// sqlOperation returns the leading SQL keyword, e.g. SELECT or INSERT.
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// compactSQL collapses whitespace so multi-line queries log on one line.
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// registerPoolMetrics exports pgxpool statistics as OTel observable instruments.
+// role distinguishes the primary pool from replica pools.
+func registerPoolMetrics(pool *pgxpool.Pool, database, role string) error {
+	meter := otel.Meter(instrumentationName)
+	attrs := metric.WithAttributes(semconv.DBNamespace(database), attribute.String("db.client.pool.role", role))
+
+	total, err := meter.Int64ObservableGauge("db.client.connections.total",
+		metric.WithDescription("Total connections in the pool"))
+	if err != nil {
+		return err
+	}
+	idle, err := meter.Int64ObservableGauge("db.client.connections.idle",
+		metric.WithDescription("Idle connections in the pool"))
+	if err != nil {
+		return err
+	}
+	acquired, err := meter.Int64ObservableGauge("db.client.connections.acquired",
+		metric.WithDescription("Connections currently checked out of the pool"))
+	if err != nil {
+		return err
+	}
+	maxConns, err := meter.Int64ObservableGauge("db.client.connections.max",
+		metric.WithDescription("Maximum pool size"))
+	if err != nil {
+		return err
+	}
+	acquireCount, err := meter.Int64ObservableCounter("db.client.connections.acquire_count",
+		metric.WithDescription("Cumulative successful connection acquires"))
+	if err != nil {
+		return err
+	}
+	emptyAcquire, err := meter.Int64ObservableCounter("db.client.connections.empty_acquire_count",
+		metric.WithDescription("Cumulative acquires that waited because the pool was empty"))
+	if err != nil {
+		return err
+	}
+	acquireWait, err := meter.Float64ObservableCounter("db.client.connections.acquire_wait_time",
+		metric.WithDescription("Cumulative time spent waiting to acquire connections"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stat := pool.Stat()
+		o.ObserveInt64(total, int64(stat.TotalConns()), attrs)
+		o.ObserveInt64(idle, int64(stat.IdleConns()), attrs)
+		o.ObserveInt64(acquired, int64(stat.AcquiredConns()), attrs)
+		o.ObserveInt64(maxConns, int64(stat.MaxConns()), attrs)
+		o.ObserveInt64(acquireCount, stat.AcquireCount(), attrs)
+		o.ObserveInt64(emptyAcquire, stat.EmptyAcquireCount(), attrs)
+		o.ObserveFloat64(acquireWait, stat.AcquireDuration().Seconds(), attrs)
+		return nil
+	}, total, idle, acquired, maxConns, acquireCount, emptyAcquire, acquireWait)
+	return err
+}
+
+// PoolStats is a point-in-time snapshot of connection pool usage.
+type PoolStats struct {
+	TotalConns        int32   `json:"total_conns"`
+	IdleConns         int32   `json:"idle_conns"`
+	AcquiredConns     int32   `json:"acquired_conns"`
+	MaxConns          int32   `json:"max_conns"`
+	AcquireCount      int64   `json:"acquire_count"`
+	EmptyAcquireCount int64   `json:"empty_acquire_count"`
+	AcquireWaitSecs   float64 `json:"acquire_wait_seconds"`
+}
+
+// Stats returns a snapshot of connection pool usage.
+func (db *DB) Stats() PoolStats {
+	stat := db.Pool.Stat()
+	return PoolStats{
+		TotalConns:        stat.TotalConns(),
+		IdleConns:         stat.IdleConns(),
+		AcquiredConns:     stat.AcquiredConns(),
+		MaxConns:          stat.MaxConns(),
+		AcquireCount:      stat.AcquireCount(),
+		EmptyAcquireCount: stat.EmptyAcquireCount(),
+		AcquireWaitSecs:   stat.AcquireDuration().Seconds(),
+	}
+}
@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PolicyRepository implements repository.PolicyRepository for PostgreSQL.
+// Scope, rules and metadata are stored as JSONB.
+type PolicyRepository struct {
+	db *DB
+}
+
+// NewPolicyRepository creates a new PolicyRepository.
+func NewPolicyRepository(db *DB) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+const policyColumns = `id, name, description, type, version, scope, rules, enabled, priority, metadata,
+	created_at, updated_at`
+
+// policySortColumns maps repository.PolicySortFields to columns.
+var policySortColumns = map[string]string{
+	"priority": "priority", "name": "name", "type": "type",
+	"created_at": "created_at", "updated_at": "updated_at",
+}
+
+// policyFilter selects the policies matching policyFilterArgs.
+const policyFilter = `
+	WHERE ($1::text IS NULL OR type = $1)
+		AND ($2::boolean IS NULL OR enabled = $2)`
+
+// policyFilterArgs returns policyFilter's arguments.
+func policyFilterArgs(filters *repository.PolicyFilters) []any {
+	var policyType *string
+	if filters.Type != nil {
+		t := string(*filters.Type)
+		policyType = &t
+	}
+	return []any{policyType, filters.Enabled}
+}
+
+// List returns the policies matching filters, highest priority first unless
+// filters.Sort says otherwise.
+func (r *PolicyRepository) List(ctx context.Context, filters *repository.PolicyFilters) ([]models.Policy, error) {
+	if filters == nil {
+		filters = &repository.PolicyFilters{}
+	}
+	query := `SELECT ` + policyColumns + `
+		FROM policies` + policyFilter + `
+		ORDER BY ` + orderBy(filters.Sort, policySortColumns, "priority DESC, name, id") + `
+		LIMIT NULLIF($3, 0) OFFSET $4`
+
+	return r.query(ctx, query, append(policyFilterArgs(filters), filters.Limit, filters.Offset)...)
+}
+
+// Count returns how many policies match filters.
+func (r *PolicyRepository) Count(ctx context.Context, filters *repository.PolicyFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.PolicyFilters{}
+	}
+	var n int
+	if err := r.db.reader(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM policies`+policyFilter, policyFilterArgs(filters)...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting policies: %w", err)
+	}
+	return n, nil
+}
+
+// GetByType returns every policy of a type, enabled or not, highest
+// priority first.
+func (r *PolicyRepository) GetByType(ctx context.Context, policyType models.PolicyType) ([]models.Policy, error) {
+	query := `SELECT ` + policyColumns + `
+		FROM policies
+		WHERE type = $1
+		ORDER BY priority DESC, name, id`
+
+	return r.query(ctx, query, string(policyType))
+}
+
+// Get returns a policy, or nil if there is none.
+func (r *PolicyRepository) Get(ctx context.Context, id string) (*models.Policy, error) {
+	query := `SELECT ` + policyColumns + ` FROM policies WHERE id = $1`
+
+	p, err := scanPolicy(r.db.reader(ctx).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting policy %s: %w", id, err)
+	}
+	return p, nil
+}
+
+// Create stores a policy, assigning an ID when it has none.
+func (r *PolicyRepository) Create(ctx context.Context, p *models.Policy) error {
+	if p.ID == "" {
+		p.ID = uuid.NewString()
+	}
+	scope, rules, metadata, err := marshalPolicyJSON(p)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO policies (id, name, description, type, version, scope, rules, enabled, priority, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at, updated_at`
+
+	err = r.db.conn(ctx).QueryRow(ctx, query,
+		p.ID, p.Name, p.Description, p.Type, p.Version, scope, rules, p.Enabled, p.Priority, metadata,
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("creating policy: %w", mapError(err))
+	}
+	return nil
+}
+
+// Update replaces a policy, keeping its creation time.
+func (r *PolicyRepository) Update(ctx context.Context, p *models.Policy) error {
+	scope, rules, metadata, err := marshalPolicyJSON(p)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE policies
+		SET name = $2, description = $3, type = $4, version = $5, scope = $6, rules = $7,
+		    enabled = $8, priority = $9, metadata = $10, updated_at = NOW()
+		WHERE id = $1
+		RETURNING created_at, updated_at`
+
+	err = r.db.conn(ctx).QueryRow(ctx, query,
+		p.ID, p.Name, p.Description, p.Type, p.Version, scope, rules, p.Enabled, p.Priority, metadata,
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("policy %s: %w", p.ID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("updating policy: %w", mapError(err))
+	}
+	return nil
+}
+
+// Delete removes a policy. Agents bound to it are not changed; callers
+// check for bindings first.
+func (r *PolicyRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM policies WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting policy: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("policy %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// query runs a policy query and scans every row.
+func (r *PolicyRepository) query(ctx context.Context, query string, args ...any) ([]models.Policy, error) {
+	rows, err := r.db.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []models.Policy
+	for rows.Next() {
+		p, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *p)
+	}
+	return policies, rows.Err()
+}
+
+// marshalPolicyJSON encodes a policy's JSONB columns, storing nil rules as
+// an empty array and nil metadata as an empty object.
+func marshalPolicyJSON(p *models.Policy) (scope, rules, metadata []byte, err error) {
+	if scope, err = json.Marshal(p.Scope); err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding policy scope: %w", err)
+	}
+	if rules, err = json.Marshal(nonNil(p.Rules)); err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding policy rules: %w", err)
+	}
+	md := p.Metadata
+	if md == nil {
+		md = map[string]any{}
+	}
+	if metadata, err = json.Marshal(md); err != nil {
+		return nil, nil, nil, fmt.Errorf("encoding policy metadata: %w", err)
+	}
+	return scope, rules, metadata, nil
+}
+
+// scanPolicy scans a row of policyColumns. pgx.ErrNoRows is returned
+// unwrapped so callers can detect a missing policy.
+func scanPolicy(row pgx.Row) (*models.Policy, error) {
+	var p models.Policy
+	var scope, rules, metadata []byte
+	if err := row.Scan(
+		&p.ID, &p.Name, &p.Description, &p.Type, &p.Version, &scope, &rules, &p.Enabled, &p.Priority, &metadata,
+		&p.CreatedAt, &p.UpdatedAt,
+	); err == pgx.ErrNoRows {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning policy: %w", err)
+	}
+	if err := json.Unmarshal(scope, &p.Scope); err != nil {
+		return nil, fmt.Errorf("decoding policy %s scope: %w", p.ID, err)
+	}
+	if err := json.Unmarshal(rules, &p.Rules); err != nil {
+		return nil, fmt.Errorf("decoding policy %s rules: %w", p.ID, err)
+	}
+	if err := json.Unmarshal(metadata, &p.Metadata); err != nil {
+		return nil, fmt.Errorf("decoding policy %s metadata: %w", p.ID, err)
+	}
+	return &p, nil
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/agentguard/agentguard/internal/repository"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
 )
@@ -20,28 +22,85 @@ type Config struct {
 	Database string
 	SSLMode  string
 	MaxConns int32
+	// SlowQueryThreshold logs queries at or above this duration. Zero disables.
+	SlowQueryThreshold time.Duration
+	// Replicas are optional read-only hosts sharing the primary's credentials.
+	Replicas []ReplicaConfig
+	// ReplicaCheckInterval is how often replica health is probed. Defaults to 15s.
+	ReplicaCheckInterval time.Duration
 }
 
 // DB wraps the PostgreSQL connection pool.
 type DB struct {
 	Pool *pgxpool.Pool
+
+	// replicas serve read-only queries when configured; see reader.
+	replicas *replicaSet
 }
 
 // New creates a new PostgreSQL connection pool.
 // Uses struct-based config to avoid embedding credentials in the DSN string,
 // which would leak passwords in error messages and log output.
 func New(ctx context.Context, cfg Config) (*DB, error) {
+	if cfg.MaxConns == 0 {
+		cfg.MaxConns = 25
+	}
+
+	pool, err := openPool(ctx, cfg, cfg.Host, cfg.Port, "primary")
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{Pool: pool}
+	if len(cfg.Replicas) > 0 {
+		db.replicas = openReplicas(ctx, cfg)
+	}
+
+	log.Info().
+		Str("host", cfg.Host).
+		Int("port", cfg.Port).
+		Str("database", cfg.Database).
+		Int("replicas", db.replicas.size()).
+		Msg("PostgreSQL connection established")
+
+	return db, nil
+}
+
+// openPool creates and verifies a connection pool for one host.
+func openPool(ctx context.Context, cfg Config, host string, port int, role string) (*pgxpool.Pool, error) {
+	poolCfg, err := newPoolConfig(cfg, host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating connection pool: %w", err)
+	}
+
+	// Verify connection
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+
+	if err := registerPoolMetrics(pool, cfg.Database, role); err != nil {
+		log.Warn().Err(err).Str("role", role).Msg("failed to register connection pool metrics")
+	}
+
+	return pool, nil
+}
+
+// newPoolConfig builds pool settings for one host. Uses struct-based config to
+// avoid embedding credentials in the DSN string.
+func newPoolConfig(cfg Config, host string, port int) (*pgxpool.Config, error) {
 	// Build DSN without password — set password via struct field to keep it
 	// out of error-path string representations.
 	dsn := fmt.Sprintf(
 		"postgres://%s@%s:%d/%s?sslmode=%s",
-		cfg.User, cfg.Host, cfg.Port, cfg.Database, cfg.SSLMode,
+		cfg.User, host, port, cfg.Database, cfg.SSLMode,
 	)
 
-	if cfg.MaxConns == 0 {
-		cfg.MaxConns = 25
-	}
-
 	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("parsing connection config: %w", err)
@@ -57,28 +116,15 @@ func New(ctx context.Context, cfg Config) (*DB, error) {
 	poolCfg.MaxConnIdleTime = 5 * time.Minute
 	poolCfg.HealthCheckPeriod = 1 * time.Minute
 
-	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
-	if err != nil {
-		return nil, fmt.Errorf("creating connection pool: %w", err)
-	}
-
-	// Verify connection
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("pinging database: %w", err)
-	}
-
-	log.Info().
-		Str("host", cfg.Host).
-		Int("port", cfg.Port).
-		Str("database", cfg.Database).
-		Msg("PostgreSQL connection established")
+	// Trace every query as an OTel span (no-op until a tracer provider is set)
+	poolCfg.ConnConfig.Tracer = newQueryTracer(cfg.Database, cfg.SlowQueryThreshold)
 
-	return &DB{Pool: pool}, nil
+	return poolCfg, nil
 }
 
 // Close closes the connection pool.
 func (db *DB) Close() {
+	db.replicas.close()
 	if db.Pool != nil {
 		db.Pool.Close()
 		log.Info().Msg("PostgreSQL connection closed")
@@ -101,12 +147,51 @@ func (db *DB) Ping(ctx context.Context) error {
 	return db.Pool.Ping(ctx)
 }
 
-// WithTx executes a function within a transaction.
+// querier is the subset of pgx shared by the pool and transactions, so
+// repository methods run unchanged inside or outside WithTx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// txKey is the context key for the active transaction.
+type txKey struct{}
+
+// conn returns the transaction carried by ctx, or the pool when there is none.
+func (db *DB) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db.Pool
+}
+
+// reader returns a querier for read-only statements. Inside a transaction it
+// returns the transaction so reads observe uncommitted writes; otherwise it
+// picks a healthy replica, falling back to the primary when none is available.
+func (db *DB) reader(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	if pool := db.replicas.next(); pool != nil {
+		return pool
+	}
+	return db.Pool
+}
+
+// WithTx executes a function within a transaction. The transaction is also
+// carried on the context passed to fn, so repository calls made with that
+// context participate in it. Nested calls join the outer transaction.
 func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx, tx)
+	}
+
 	tx, err := db.Pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("beginning transaction: %w", err)
 	}
+	ctx = context.WithValue(ctx, txKey{}, tx)
 
 	if err := fn(ctx, tx); err != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
@@ -124,3 +209,17 @@ func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx
 
 	return nil
 }
+
+// orderBy returns an ORDER BY list for s: the column columns maps its field
+// to, then def, the list's default order, which also breaks ties. A field
+// without a column keeps the default order.
+func orderBy(s repository.Sort, columns map[string]string, def string) string {
+	col, ok := columns[s.Field]
+	if !ok {
+		return def
+	}
+	if s.Desc {
+		col += " DESC"
+	}
+	return col + ", " + def
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// ReplicaConfig identifies a read replica. Replicas share the primary's
+// credentials, database name and TLS mode.
+type ReplicaConfig struct {
+	Host string
+	Port int
+}
+
+// replica is one read-only pool and its last observed health.
+type replica struct {
+	addr    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// replicaSet round-robins reads across healthy replicas. Unreachable replicas
+// are skipped until a background probe sees them recover.
+type replicaSet struct {
+	replicas []*replica
+	counter  atomic.Uint64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// openReplicas connects to each configured replica. A replica that cannot be
+// reached at startup is kept and marked unhealthy, so it joins the rotation
+// once it comes up; a replica whose pool cannot be built is skipped.
+func openReplicas(ctx context.Context, cfg Config) *replicaSet {
+	rs := &replicaSet{stop: make(chan struct{})}
+	for _, rc := range cfg.Replicas {
+		port := rc.Port
+		if port == 0 {
+			port = cfg.Port
+		}
+		r := &replica{addr: rc.Host + ":" + strconv.Itoa(port)}
+
+		pool, err := openPool(ctx, cfg, rc.Host, port, "replica")
+		if err != nil {
+			log.Warn().Err(err).Str("replica", r.addr).Msg("read replica unavailable at startup")
+			pool, err = lazyPool(cfg, rc.Host, port)
+			if err != nil {
+				log.Error().Err(err).Str("replica", r.addr).Msg("skipping read replica")
+				continue
+			}
+			if err := registerPoolMetrics(pool, cfg.Database, "replica"); err != nil {
+				log.Warn().Err(err).Str("replica", r.addr).Msg("failed to register connection pool metrics")
+			}
+		} else {
+			r.healthy.Store(true)
+		}
+		r.pool = pool
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	interval := cfg.ReplicaCheckInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	rs.wg.Add(1)
+	go rs.probe(interval)
+	return rs
+}
+
+// lazyPool builds a pool without connecting, for replicas that are down at startup.
+func lazyPool(cfg Config, host string, port int) (*pgxpool.Pool, error) {
+	poolCfg, err := newPoolConfig(cfg, host, port)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.MinConns = 0
+	return pgxpool.NewWithConfig(context.Background(), poolCfg)
+}
+
+// probe pings every replica on each tick and updates its health.
+func (rs *replicaSet) probe(interval time.Duration) {
+	defer rs.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rs.stop:
+			return
+		case <-ticker.C:
+			for _, r := range rs.replicas {
+				ctx, cancel := context.WithTimeout(context.Background(), interval/2)
+				err := r.pool.Ping(ctx)
+				cancel()
+
+				healthy := err == nil
+				if was := r.healthy.Swap(healthy); was != healthy {
+					if healthy {
+						log.Info().Str("replica", r.addr).Msg("read replica recovered")
+					} else {
+						log.Warn().Err(err).Str("replica", r.addr).Msg("read replica unhealthy, routing reads elsewhere")
+					}
+				}
+			}
+		}
+	}
+}
+
+// next returns the next healthy replica pool in round-robin order, or nil
+// when there are no healthy replicas.
+func (rs *replicaSet) next() *pgxpool.Pool {
+	if rs == nil || len(rs.replicas) == 0 {
+		return nil
+	}
+	n := uint64(len(rs.replicas))
+	start := rs.counter.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := rs.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r.pool
+		}
+	}
+	return nil
+}
+
+// size reports the number of configured replicas.
+func (rs *replicaSet) size() int {
+	if rs == nil {
+		return 0
+	}
+	return len(rs.replicas)
+}
+
+// close stops health probing and closes every replica pool.
+func (rs *replicaSet) close() {
+	if rs == nil {
+		return
+	}
+	close(rs.stop)
+	rs.wg.Wait()
+	for _, r := range rs.replicas {
+		r.pool.Close()
+	}
+}
+
+// ReplicaStatus reports a read replica's address and current health.
+type ReplicaStatus struct {
+	Address string `json:"address"`
+	Healthy bool   `json:"healthy"`
+}
+
+// Replicas returns the health of each configured read replica.
+func (db *DB) Replicas() []ReplicaStatus {
+	if db.replicas == nil {
+		return nil
+	}
+	out := make([]ReplicaStatus, 0, len(db.replicas.replicas))
+	for _, r := range db.replicas.replicas {
+		out = append(out, ReplicaStatus{Address: r.addr, Healthy: r.healthy.Load()})
+	}
+	return out
+}
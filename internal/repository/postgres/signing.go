@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// SigningKeyRepository implements repository.SigningKeyRepository for
+// PostgreSQL. Secrets are stored as issued, since verifying an HMAC needs
+// them; restrict access to the table accordingly.
+type SigningKeyRepository struct {
+	db *DB
+}
+
+// NewSigningKeyRepository creates a new SigningKeyRepository.
+func NewSigningKeyRepository(db *DB) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// List returns an agent's keys, oldest first.
+func (r *SigningKeyRepository) List(ctx context.Context, agentID string) ([]models.SigningKey, error) {
+	query := `
+		SELECT id, agent_id, secret, created_at, expires_at
+		FROM agent_signing_keys
+		WHERE agent_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("querying signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+	for rows.Next() {
+		var k models.SigningKey
+		if err := rows.Scan(&k.ID, &k.AgentID, &k.Secret, &k.CreatedAt, &k.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("scanning signing key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// Create stores a key.
+func (r *SigningKeyRepository) Create(ctx context.Context, k *models.SigningKey) error {
+	query := `
+		INSERT INTO agent_signing_keys (id, agent_id, secret, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := r.db.conn(ctx).Exec(ctx, query, k.ID, k.AgentID, k.Secret, k.CreatedAt, k.ExpiresAt); err != nil {
+		return fmt.Errorf("creating signing key: %w", mapError(err))
+	}
+	return nil
+}
+
+// Update replaces a key's expiry.
+func (r *SigningKeyRepository) Update(ctx context.Context, k *models.SigningKey) error {
+	result, err := r.db.conn(ctx).Exec(ctx,
+		`UPDATE agent_signing_keys SET expires_at = $3 WHERE agent_id = $1 AND id = $2`, k.AgentID, k.ID, k.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("updating signing key: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("signing key %s: %w", k.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete removes a key.
+func (r *SigningKeyRepository) Delete(ctx context.Context, agentID, id string) error {
+	result, err := r.db.conn(ctx).Exec(ctx,
+		`DELETE FROM agent_signing_keys WHERE agent_id = $1 AND id = $2`, agentID, id)
+	if err != nil {
+		return fmt.Errorf("deleting signing key: %w", mapError(err))
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("signing key %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/privacy"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// TraceMetadataRepository stores trace-level records: identity, timing,
+// status, metrics and metadata. Spans and signals live in ClickHouse. It
+// implements repository.TraceWriter, repository.TraceBatchWriter,
+// repository.TraceLister and privacy.Store.
+type TraceMetadataRepository struct {
+	db *DB
+}
+
+// NewTraceMetadataRepository creates a new TraceMetadataRepository.
+func NewTraceMetadataRepository(db *DB) *TraceMetadataRepository {
+	return &TraceMetadataRepository{db: db}
+}
+
+// upsertTraceMetadata merges a submission into the trace's record. Traces
+// may arrive in several submissions, each carrying only spans not ingested
+// before, so metrics are added up and timing widened; the latest non-empty
+// status and identifiers win.
+const upsertTraceMetadata = `
+	INSERT INTO trace_metadata (org_id, trace_id, agent_id, session_id, user_id, start_time, end_time,
+		duration_ms, status, total_spans, llm_calls, tool_invocations, total_tokens, estimated_cost_usd,
+		policy_evaluations, security_signals, metadata, parent_trace_id, parent_span_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	ON CONFLICT (org_id, trace_id) DO UPDATE SET
+		agent_id = COALESCE(EXCLUDED.agent_id, trace_metadata.agent_id),
+		session_id = COALESCE(NULLIF(EXCLUDED.session_id, ''), trace_metadata.session_id),
+		user_id = COALESCE(NULLIF(EXCLUDED.user_id, ''), trace_metadata.user_id),
+		start_time = LEAST(trace_metadata.start_time, EXCLUDED.start_time),
+		end_time = GREATEST(trace_metadata.end_time, EXCLUDED.end_time),
+		duration_ms = GREATEST(trace_metadata.duration_ms, EXCLUDED.duration_ms),
+		status = COALESCE(NULLIF(EXCLUDED.status, ''), trace_metadata.status),
+		total_spans = trace_metadata.total_spans + EXCLUDED.total_spans,
+		llm_calls = trace_metadata.llm_calls + EXCLUDED.llm_calls,
+		tool_invocations = trace_metadata.tool_invocations + EXCLUDED.tool_invocations,
+		total_tokens = trace_metadata.total_tokens + EXCLUDED.total_tokens,
+		estimated_cost_usd = trace_metadata.estimated_cost_usd + EXCLUDED.estimated_cost_usd,
+		policy_evaluations = trace_metadata.policy_evaluations + EXCLUDED.policy_evaluations,
+		security_signals = trace_metadata.security_signals + EXCLUDED.security_signals,
+		metadata = trace_metadata.metadata || EXCLUDED.metadata,
+		parent_trace_id = COALESCE(NULLIF(EXCLUDED.parent_trace_id, ''), trace_metadata.parent_trace_id),
+		parent_span_id = COALESCE(NULLIF(EXCLUDED.parent_span_id, ''), trace_metadata.parent_span_id),
+		updated_at = NOW()`
+
+// InsertTrace stores a trace's record, merging it into an earlier one.
+func (r *TraceMetadataRepository) InsertTrace(ctx context.Context, orgID string, t *models.AgentTrace) error {
+	return r.InsertTraces(ctx, []repository.OrgTrace{{OrgID: orgID, Trace: t}})
+}
+
+// InsertTraces stores the records of a batch of traces in one transaction.
+func (r *TraceMetadataRepository) InsertTraces(ctx context.Context, batch []repository.OrgTrace) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	var b pgx.Batch
+	for _, ot := range batch {
+		args, err := traceMetadataArgs(ot.OrgID, ot.Trace)
+		if err != nil {
+			return err
+		}
+		b.Queue(upsertTraceMetadata, args...)
+	}
+	return r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.SendBatch(ctx, &b).Close(); err != nil {
+			return mapError(fmt.Errorf("storing trace metadata: %w", err))
+		}
+		return nil
+	})
+}
+
+// traceMetadataArgs returns upsertTraceMetadata's arguments for a trace.
+func traceMetadataArgs(orgID string, t *models.AgentTrace) ([]any, error) {
+	metadata := []byte("{}")
+	if len(t.Metadata) > 0 {
+		var err error
+		if metadata, err = json.Marshal(t.Metadata); err != nil {
+			return nil, fmt.Errorf("encoding metadata for trace %s: %w", t.TraceID, err)
+		}
+	}
+	var agentID *uuid.UUID
+	if t.AgentID != uuid.Nil {
+		agentID = &t.AgentID
+	}
+	var parentTrace, parentSpan string
+	if t.Parent != nil {
+		parentTrace, parentSpan = t.Parent.TraceID, t.Parent.SpanID
+	}
+	m := t.Metrics
+	return []any{
+		orgID, t.TraceID, agentID, t.SessionID, t.UserID, t.StartTime, t.EndTime,
+		t.DurationMs, string(t.Status), m.TotalSpans, m.LLMCalls, m.ToolInvocations, m.TotalTokens,
+		m.EstimatedCostUSD, m.PolicyEvaluations, m.SecuritySignals, metadata, parentTrace, parentSpan,
+	}, nil
+}
+
+// traceSortColumns maps repository.TraceSortFields to columns.
+var traceSortColumns = map[string]string{
+	"start_time": "start_time", "duration_ms": "duration_ms", "total_tokens": "total_tokens",
+	"estimated_cost_usd": "estimated_cost_usd", "security_signals": "security_signals",
+}
+
+// traceFilter selects an organization's trace records matching
+// traceFilterArgs.
+const traceFilter = `
+	WHERE org_id = $1
+		AND ($2::uuid IS NULL OR agent_id = $2)
+		AND ($3::text IS NULL OR session_id = $3)
+		AND ($4::text IS NULL OR status = $4)
+		AND ($5::timestamptz IS NULL OR start_time >= $5)
+		AND ($6::timestamptz IS NULL OR start_time < $6)`
+
+// traceFilterArgs returns traceFilter's arguments.
+func traceFilterArgs(orgID string, filters *repository.TraceFilters) []any {
+	var status *string
+	if filters.Status != nil {
+		s := string(*filters.Status)
+		status = &s
+	}
+	var from, to *time.Time
+	if filters.StartFrom != nil {
+		t := time.Unix(*filters.StartFrom, 0)
+		from = &t
+	}
+	if filters.StartTo != nil {
+		t := time.Unix(*filters.StartTo, 0)
+		to = &t
+	}
+	return []any{orgID, filters.AgentID, filters.SessionID, status, from, to}
+}
+
+// ListTraces returns an organization's trace records matching filters,
+// newest first unless filters.Sort says otherwise.
+func (r *TraceMetadataRepository) ListTraces(ctx context.Context, orgID string, filters *repository.TraceFilters) ([]models.AgentTrace, error) {
+	if filters == nil {
+		filters = &repository.TraceFilters{}
+	}
+	query := `
+		SELECT trace_id, agent_id, session_id, user_id, start_time, end_time, duration_ms, status,
+			total_spans, llm_calls, tool_invocations, total_tokens, estimated_cost_usd,
+			policy_evaluations, security_signals, metadata, parent_trace_id, parent_span_id
+		FROM trace_metadata` + traceFilter + `
+		ORDER BY ` + orderBy(filters.Sort, traceSortColumns, "start_time DESC, trace_id") + `
+		LIMIT NULLIF($7, 0) OFFSET $8`
+
+	rows, err := r.db.reader(ctx).Query(ctx, query, append(traceFilterArgs(orgID, filters), filters.Limit, filters.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("querying traces: %w", err)
+	}
+	defer rows.Close()
+
+	var traces []models.AgentTrace
+	for rows.Next() {
+		var (
+			t                       models.AgentTrace
+			agentID                 *uuid.UUID
+			status                  string
+			metadata                []byte
+			parentTrace, parentSpan string
+		)
+		m := &t.Metrics
+		if err := rows.Scan(&t.TraceID, &agentID, &t.SessionID, &t.UserID, &t.StartTime, &t.EndTime,
+			&t.DurationMs, &status, &m.TotalSpans, &m.LLMCalls, &m.ToolInvocations, &m.TotalTokens,
+			&m.EstimatedCostUSD, &m.PolicyEvaluations, &m.SecuritySignals, &metadata,
+			&parentTrace, &parentSpan); err != nil {
+			return nil, fmt.Errorf("scanning trace: %w", err)
+		}
+		if agentID != nil {
+			t.AgentID = *agentID
+		}
+		t.Status = models.TraceStatus(status)
+		if err := json.Unmarshal(metadata, &t.Metadata); err != nil {
+			return nil, fmt.Errorf("decoding metadata for trace %s: %w", t.TraceID, err)
+		}
+		if parentTrace != "" {
+			t.Parent = &models.TraceLink{TraceID: parentTrace, SpanID: parentSpan}
+		}
+		traces = append(traces, t)
+	}
+	return traces, rows.Err()
+}
+
+// CountTraces returns how many of an organization's trace records match
+// filters.
+func (r *TraceMetadataRepository) CountTraces(ctx context.Context, orgID string, filters *repository.TraceFilters) (int, error) {
+	if filters == nil {
+		filters = &repository.TraceFilters{}
+	}
+	var n int
+	if err := r.db.reader(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM trace_metadata`+traceFilter, traceFilterArgs(orgID, filters)...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting traces: %w", err)
+	}
+	return n, nil
+}
+
+// Name implements privacy.Store.
+func (r *TraceMetadataRepository) Name() string { return "postgres_trace_metadata" }
+
+// Erase implements privacy.Store for the subject's trace records in the
+// plan's organization.
+func (r *TraceMetadataRepository) Erase(ctx context.Context, plan *privacy.Plan) (privacy.StoreResult, error) {
+	var res privacy.StoreResult
+	if plan.Mode == privacy.ModeDelete {
+		tag, err := r.db.conn(ctx).Exec(ctx, `DELETE FROM trace_metadata WHERE org_id = $1 AND user_id = $2`, plan.OrgID, plan.UserID)
+		if err != nil {
+			return res, fmt.Errorf("deleting trace metadata: %w", err)
+		}
+		res.Affected = tag.RowsAffected()
+		return res, nil
+	}
+
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		switch action, ok := plan.Action(privacy.FieldSessionID); {
+		case !ok:
+		case action == privacy.ActionRedact:
+			if _, err := tx.Exec(ctx, `UPDATE trace_metadata SET session_id = '' WHERE org_id = $1 AND user_id = $2`, plan.OrgID, plan.UserID); err != nil {
+				return fmt.Errorf("redacting sessions: %w", err)
+			}
+		default:
+			rows, err := tx.Query(ctx, `SELECT DISTINCT session_id FROM trace_metadata WHERE org_id = $1 AND user_id = $2 AND session_id <> ''`, plan.OrgID, plan.UserID)
+			if err != nil {
+				return fmt.Errorf("querying sessions: %w", err)
+			}
+			sessions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return fmt.Errorf("scanning sessions: %w", err)
+			}
+			pseudonyms := make([]string, len(sessions))
+			for i, id := range sessions {
+				pseudonyms[i] = plan.Pseudonymize(id)
+			}
+			if _, err := tx.Exec(ctx, `
+				UPDATE trace_metadata t SET session_id = m.pseudonym
+				FROM unnest($3::text[], $4::text[]) AS m(session_id, pseudonym)
+				WHERE t.org_id = $1 AND t.user_id = $2 AND t.session_id = m.session_id`,
+				plan.OrgID, plan.UserID, sessions, pseudonyms); err != nil {
+				return fmt.Errorf("pseudonymizing sessions: %w", err)
+			}
+		}
+
+		replacement := plan.Pseudonym
+		if action, _ := plan.Action(privacy.FieldUserID); action == privacy.ActionRedact {
+			replacement = ""
+		}
+		keys := plan.Attributes()
+		if keys == nil {
+			keys = []string{}
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE trace_metadata
+			SET user_id = $3, metadata = metadata - $4::text[], updated_at = NOW()
+			WHERE org_id = $1 AND user_id = $2`,
+			plan.OrgID, plan.UserID, replacement, keys)
+		if err != nil {
+			return fmt.Errorf("pseudonymizing trace metadata: %w", err)
+		}
+		res.Affected = tag.RowsAffected()
+		return nil
+	})
+	return res, err
+}
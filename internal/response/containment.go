@@ -0,0 +1,209 @@
+package response
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// AgentState is the containment applied to one agent.
+type AgentState struct {
+	AgentID      string `json:"agent_id"`
+	Suspended    bool   `json:"suspended"`
+	ToolsRevoked bool   `json:"tools_revoked"`
+	RateLimited  bool   `json:"rate_limited"` // rate limit forced to zero
+	// Quarantined agents may only call QuarantineTools; their traces are kept
+	// in full and run through additional detectors.
+	Quarantined     bool      `json:"quarantined"`
+	QuarantineTools []string  `json:"quarantine_tools,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (s *AgentState) contained() bool {
+	return s.Suspended || s.ToolsRevoked || s.RateLimited || s.Quarantined
+}
+
+// Containment holds per-agent restrictions consulted before tool calls are
+// evaluated. It is safe for concurrent use.
+type Containment struct {
+	mu     sync.RWMutex
+	agents map[string]*AgentState
+}
+
+// NewContainment creates an empty containment store.
+func NewContainment() *Containment {
+	return &Containment{agents: make(map[string]*AgentState)}
+}
+
+// set applies fn to the agent's state and reports whether the state changed.
+func (c *Containment) set(agentID, reason string, fn func(*AgentState) bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.agents[agentID]
+	if !ok {
+		s = &AgentState{AgentID: agentID}
+	}
+	if !fn(s) {
+		return false
+	}
+	s.UpdatedAt = time.Now().UTC()
+	if reason != "" {
+		s.Reason = reason
+	}
+	if s.contained() {
+		c.agents[agentID] = s
+	} else {
+		delete(c.agents, agentID)
+	}
+	return true
+}
+
+// Suspend blocks every tool call from the agent.
+func (c *Containment) Suspend(agentID, reason string) bool {
+	return c.set(agentID, reason, func(s *AgentState) bool {
+		changed := !s.Suspended
+		s.Suspended = true
+		return changed
+	})
+}
+
+// Resume lifts a suspension.
+func (c *Containment) Resume(agentID string) bool {
+	return c.set(agentID, "", func(s *AgentState) bool {
+		changed := s.Suspended
+		s.Suspended = false
+		return changed
+	})
+}
+
+// RevokeTools removes the agent's tool bindings until restored.
+func (c *Containment) RevokeTools(agentID, reason string) bool {
+	return c.set(agentID, reason, func(s *AgentState) bool {
+		changed := !s.ToolsRevoked
+		s.ToolsRevoked = true
+		return changed
+	})
+}
+
+// RestoreTools reinstates the agent's tool bindings.
+func (c *Containment) RestoreTools(agentID string) bool {
+	return c.set(agentID, "", func(s *AgentState) bool {
+		changed := s.ToolsRevoked
+		s.ToolsRevoked = false
+		return changed
+	})
+}
+
+// ZeroRateLimit forces the agent's rate limit to zero.
+func (c *Containment) ZeroRateLimit(agentID, reason string) bool {
+	return c.set(agentID, reason, func(s *AgentState) bool {
+		changed := !s.RateLimited
+		s.RateLimited = true
+		return changed
+	})
+}
+
+// RestoreRateLimit returns the agent to its configured rate limit.
+func (c *Containment) RestoreRateLimit(agentID string) bool {
+	return c.set(agentID, "", func(s *AgentState) bool {
+		changed := s.RateLimited
+		s.RateLimited = false
+		return changed
+	})
+}
+
+// Quarantine restricts the agent to allowTools. Calling it on a quarantined
+// agent replaces the allowlist.
+func (c *Containment) Quarantine(agentID, reason string, allowTools []string) bool {
+	tools := append([]string(nil), allowTools...)
+	sort.Strings(tools)
+	return c.set(agentID, reason, func(s *AgentState) bool {
+		changed := !s.Quarantined || !equalStrings(s.QuarantineTools, tools)
+		s.Quarantined = true
+		s.QuarantineTools = tools
+		return changed
+	})
+}
+
+// Release lifts a quarantine.
+func (c *Containment) Release(agentID string) bool {
+	return c.set(agentID, "", func(s *AgentState) bool {
+		changed := s.Quarantined
+		s.Quarantined = false
+		s.QuarantineTools = nil
+		return changed
+	})
+}
+
+// Quarantined reports whether the agent is quarantined and which tools it may
+// still call. It satisfies ingest.QuarantineLookup.
+func (c *Containment) Quarantined(agentID string) ([]string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.agents[agentID]
+	if !ok || !s.Quarantined {
+		return nil, false
+	}
+	return append([]string(nil), s.QuarantineTools...), true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Check reports whether the agent may invoke tool, and why not. An empty
+// tool checks agent-wide restrictions only.
+func (c *Containment) Check(agentID, tool string) (bool, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.agents[agentID]
+	if !ok {
+		return true, ""
+	}
+	switch {
+	case s.Suspended:
+		return false, "agent is suspended: " + s.Reason
+	case s.ToolsRevoked:
+		return false, "agent tool bindings are revoked: " + s.Reason
+	case s.RateLimited:
+		return false, "agent rate limit is set to zero: " + s.Reason
+	case s.Quarantined && tool != "":
+		i := sort.SearchStrings(s.QuarantineTools, tool)
+		if i == len(s.QuarantineTools) || s.QuarantineTools[i] != tool {
+			return false, "agent is quarantined and tool '" + tool + "' is not on its allowlist: " + s.Reason
+		}
+	}
+	return true, ""
+}
+
+// Get returns the agent's containment state.
+func (c *Containment) Get(agentID string) (AgentState, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.agents[agentID]
+	if !ok {
+		return AgentState{AgentID: agentID}, false
+	}
+	return *s, true
+}
+
+// List returns every contained agent.
+func (c *Containment) List() []AgentState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]AgentState, 0, len(c.agents))
+	for _, s := range c.agents {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AgentID < out[j].AgentID })
+	return out
+}
@@ -0,0 +1,473 @@
+// Package response runs automated containment when security signals fire.
+// Rules match signals by type and severity and trigger actions such as
+// suspending the agent or revoking its tools. Every action is recorded in an
+// audit log, can run in dry-run mode, and can be rolled back manually.
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// ActionType identifies a response action.
+type ActionType string
+
+const (
+	ActionSuspendAgent  ActionType = "suspend_agent"
+	ActionRevokeTools   ActionType = "revoke_tools"
+	ActionZeroRateLimit ActionType = "zero_rate_limit"
+	ActionBlockPrompt   ActionType = "block_prompt"
+	ActionNotify        ActionType = "notify"
+	ActionQuarantine    ActionType = "quarantine"
+	// ActionSuggestQuarantine records a quarantine recommendation for an
+	// operator to accept instead of applying it.
+	ActionSuggestQuarantine ActionType = "suggest_quarantine"
+)
+
+// ActionStatus is the outcome of an action.
+type ActionStatus string
+
+const (
+	StatusApplied    ActionStatus = "applied"
+	StatusDryRun     ActionStatus = "dry_run"
+	StatusNoop       ActionStatus = "noop" // target was already in the requested state
+	StatusFailed     ActionStatus = "failed"
+	StatusRolledBack ActionStatus = "rolled_back"
+	StatusSuggested  ActionStatus = "suggested"
+)
+
+// Errors returned by Rollback.
+var (
+	ErrNotFound       = errors.New("action not found")
+	ErrNotReversible  = errors.New("action cannot be rolled back")
+	ErrAlreadyUndone  = errors.New("action already rolled back")
+	ErrNothingApplied = errors.New("action made no change to roll back")
+	ErrNotSuggestion  = errors.New("action is not a pending suggestion")
+)
+
+// severityRank orders signal severities.
+var severityRank = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// Rule maps matching signals to actions.
+type Rule struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	SignalTypes []models.SignalType `json:"signal_types,omitempty"` // empty matches any type
+	MinSeverity string              `json:"min_severity"`
+	Actions     []ActionType        `json:"actions"`
+}
+
+func (r *Rule) matches(sig *models.SecuritySignal) bool {
+	if severityRank[sig.Severity] < severityRank[r.MinSeverity] {
+		return false
+	}
+	if len(r.SignalTypes) == 0 {
+		return true
+	}
+	for _, t := range r.SignalTypes {
+		if t == sig.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures an Engine.
+type Config struct {
+	// DryRun records what would happen without changing anything.
+	DryRun bool
+	// Cooldown suppresses repeat firings of a rule for the same agent.
+	Cooldown time.Duration
+	// NotifyWebhook receives a JSON POST for notify actions. When nil,
+	// notifications are logged.
+	NotifyWebhook *webhook.Sender
+	// MaxRecords bounds the in-memory audit log.
+	MaxRecords int
+	// QuarantineTools are the tools a quarantined agent may still call.
+	QuarantineTools []string
+	Rules           []Rule
+	// OnRecord, when set, is called with every new or changed audit record,
+	// e.g. to append it to a durable audit log. It must not call back into
+	// the engine.
+	OnRecord func(ActionRecord)
+}
+
+// ActionRecord is one audited action.
+type ActionRecord struct {
+	ID           string       `json:"id"`
+	RuleID       string       `json:"rule_id"`
+	Action       ActionType   `json:"action"`
+	Status       ActionStatus `json:"status"`
+	OrgID        string       `json:"org_id"`
+	AgentID      string       `json:"agent_id,omitempty"`
+	Target       string       `json:"target,omitempty"` // e.g. the blocked prompt hash
+	SignalID     string       `json:"signal_id"`
+	SignalType   string       `json:"signal_type"`
+	Severity     string       `json:"severity"`
+	Error        string       `json:"error,omitempty"`
+	RequestedBy  string       `json:"requested_by,omitempty"` // operator for manual actions
+	CreatedAt    time.Time    `json:"created_at"`
+	RolledBackAt *time.Time   `json:"rolled_back_at,omitempty"`
+	RolledBackBy string       `json:"rolled_back_by,omitempty"`
+}
+
+// Engine evaluates signals against rules and executes actions.
+type Engine struct {
+	cfg         Config
+	containment *Containment
+	prompts     *prompts.Registry
+
+	mu       sync.Mutex
+	records  []*ActionRecord
+	byID     map[string]*ActionRecord
+	lastFire map[string]time.Time // rule/agent
+}
+
+// NewEngine creates a response engine. prompts may be nil, in which case
+// block_prompt actions fail.
+func NewEngine(cfg Config, containment *Containment, registry *prompts.Registry) *Engine {
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 10000
+	}
+	return &Engine{
+		cfg:         cfg,
+		containment: containment,
+		prompts:     registry,
+		byID:        make(map[string]*ActionRecord),
+		lastFire:    make(map[string]time.Time),
+	}
+}
+
+// Rules returns the configured rules.
+func (e *Engine) Rules() []Rule { return e.cfg.Rules }
+
+// DryRun reports whether the engine only records actions.
+func (e *Engine) DryRun() bool { return e.cfg.DryRun }
+
+// Containment returns the containment store actions apply to.
+func (e *Engine) Containment() *Containment { return e.containment }
+
+// HandleTrace runs rules for every signal on a persisted trace.
+func (e *Engine) HandleTrace(ctx context.Context, orgID string, t *models.AgentTrace) []ActionRecord {
+	agentID := ""
+	if t.AgentID != uuid.Nil {
+		agentID = t.AgentID.String()
+	}
+	var out []ActionRecord
+	for i := range t.SecuritySignals {
+		out = append(out, e.HandleSignal(ctx, orgID, agentID, &t.SecuritySignals[i])...)
+	}
+	return out
+}
+
+// HandleSignal runs every matching rule for one signal and returns the
+// resulting audit records.
+func (e *Engine) HandleSignal(ctx context.Context, orgID, agentID string, sig *models.SecuritySignal) []ActionRecord {
+	var out []ActionRecord
+	for i := range e.cfg.Rules {
+		rule := &e.cfg.Rules[i]
+		if !rule.matches(sig) || !e.fire(rule.ID, orgID, agentID) {
+			continue
+		}
+		for _, action := range rule.Actions {
+			rec := &ActionRecord{
+				ID:         uuid.NewString(),
+				RuleID:     rule.ID,
+				Action:     action,
+				OrgID:      orgID,
+				AgentID:    agentID,
+				SignalID:   sig.ID,
+				SignalType: string(sig.Type),
+				Severity:   sig.Severity,
+				CreatedAt:  time.Now().UTC(),
+			}
+			if hash, ok := sig.Evidence["prompt_hash"].(string); ok && action == ActionBlockPrompt {
+				rec.Target = hash
+			}
+			e.execute(ctx, rule, rec, sig)
+			e.audit(rec)
+			out = append(out, *rec)
+		}
+	}
+	return out
+}
+
+// fire enforces the per-rule, per-agent cooldown.
+func (e *Engine) fire(ruleID, orgID, agentID string) bool {
+	key := ruleID + "/" + orgID + "/" + agentID
+	now := time.Now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if last, ok := e.lastFire[key]; ok && now.Sub(last) < e.cfg.Cooldown {
+		return false
+	}
+	e.lastFire[key] = now
+	return true
+}
+
+func (e *Engine) execute(ctx context.Context, rule *Rule, rec *ActionRecord, sig *models.SecuritySignal) {
+	reason := fmt.Sprintf("response rule %s: %s", rule.ID, sig.Title)
+
+	needsAgent := rec.Action != ActionBlockPrompt && rec.Action != ActionNotify
+	switch {
+	case needsAgent && rec.AgentID == "":
+		rec.Status, rec.Error = StatusFailed, "signal has no agent to act on"
+		return
+	case rec.Action == ActionBlockPrompt && rec.Target == "":
+		rec.Status, rec.Error = StatusFailed, "signal has no prompt hash to block"
+		return
+	case rec.Action == ActionBlockPrompt && e.prompts == nil:
+		rec.Status, rec.Error = StatusFailed, "prompt registry not configured"
+		return
+	}
+
+	if rec.Action == ActionSuggestQuarantine {
+		// Suggestions never change state, so dry-run does not apply.
+		if _, already := e.containment.Quarantined(rec.AgentID); already {
+			rec.Status = StatusNoop
+			return
+		}
+		rec.Status = StatusSuggested
+		log.Warn().Str("rule", rule.ID).Str("agent_id", rec.AgentID).Msg("quarantine suggested")
+		return
+	}
+
+	if e.cfg.DryRun {
+		rec.Status = StatusDryRun
+		log.Info().Str("rule", rule.ID).Str("action", string(rec.Action)).Str("agent_id", rec.AgentID).
+			Msg("response action (dry run)")
+		return
+	}
+
+	changed := true
+	switch rec.Action {
+	case ActionSuspendAgent:
+		changed = e.containment.Suspend(rec.AgentID, reason)
+	case ActionRevokeTools:
+		changed = e.containment.RevokeTools(rec.AgentID, reason)
+	case ActionZeroRateLimit:
+		changed = e.containment.ZeroRateLimit(rec.AgentID, reason)
+	case ActionQuarantine:
+		changed = e.containment.Quarantine(rec.AgentID, reason, e.cfg.QuarantineTools)
+	case ActionBlockPrompt:
+		if _, blocked := e.prompts.Blocklist()[rec.Target]; blocked {
+			changed = false
+		} else {
+			e.prompts.Block(rec.Target, reason, "response:"+rule.ID)
+		}
+	case ActionNotify:
+		if err := e.notify(ctx, rule, rec, sig); err != nil {
+			rec.Status, rec.Error = StatusFailed, err.Error()
+			return
+		}
+	default:
+		rec.Status, rec.Error = StatusFailed, "unknown action"
+		return
+	}
+
+	rec.Status = StatusApplied
+	if !changed {
+		rec.Status = StatusNoop
+	}
+	log.Warn().Str("rule", rule.ID).Str("action", string(rec.Action)).Str("agent_id", rec.AgentID).
+		Str("status", string(rec.Status)).Msg("response action executed")
+}
+
+// notify posts the signal and rule to the configured webhook, or logs it.
+func (e *Engine) notify(ctx context.Context, rule *Rule, rec *ActionRecord, sig *models.SecuritySignal) error {
+	if e.cfg.NotifyWebhook == nil {
+		log.Warn().Str("rule", rule.ID).Str("agent_id", rec.AgentID).Str("signal", sig.Title).
+			Str("severity", sig.Severity).Msg("response notification")
+		return nil
+	}
+	body, err := json.Marshal(map[string]any{
+		"rule":     rule,
+		"org_id":   rec.OrgID,
+		"agent_id": rec.AgentID,
+		"signal":   sig,
+		"dry_run":  e.cfg.DryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding notification: %w", err)
+	}
+	if err := e.cfg.NotifyWebhook.Send(ctx, body); err != nil {
+		return fmt.Errorf("sending notification: %w", err)
+	}
+	return nil
+}
+
+func (e *Engine) audit(rec *ActionRecord) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.record(rec)
+	e.records = append(e.records, rec)
+	e.byID[rec.ID] = rec
+	if over := len(e.records) - e.cfg.MaxRecords; over > 0 {
+		for _, old := range e.records[:over] {
+			delete(e.byID, old.ID)
+		}
+		e.records = append([]*ActionRecord(nil), e.records[over:]...)
+	}
+}
+
+// record passes a record to the OnRecord hook. Callers hold e.mu.
+func (e *Engine) record(rec *ActionRecord) {
+	if e.cfg.OnRecord != nil {
+		e.cfg.OnRecord(*rec)
+	}
+}
+
+// Actions returns audit records, newest first, optionally for one agent.
+func (e *Engine) Actions(agentID string) []ActionRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]ActionRecord, 0, len(e.records))
+	for i := len(e.records) - 1; i >= 0; i-- {
+		if agentID == "" || e.records[i].AgentID == agentID {
+			out = append(out, *e.records[i])
+		}
+	}
+	return out
+}
+
+// Rollback reverses an applied action.
+func (e *Engine) Rollback(id, by string) (ActionRecord, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rec, ok := e.byID[id]
+	if !ok {
+		return ActionRecord{}, ErrNotFound
+	}
+	switch rec.Status {
+	case StatusRolledBack:
+		return *rec, ErrAlreadyUndone
+	case StatusApplied:
+	default:
+		return *rec, ErrNothingApplied
+	}
+
+	switch rec.Action {
+	case ActionSuspendAgent:
+		e.containment.Resume(rec.AgentID)
+	case ActionRevokeTools:
+		e.containment.RestoreTools(rec.AgentID)
+	case ActionZeroRateLimit:
+		e.containment.RestoreRateLimit(rec.AgentID)
+	case ActionQuarantine, ActionSuggestQuarantine:
+		e.containment.Release(rec.AgentID)
+	case ActionBlockPrompt:
+		if err := e.prompts.Unblock(rec.Target); err != nil && !errors.Is(err, prompts.ErrNotFound) {
+			return *rec, err
+		}
+	default:
+		return *rec, ErrNotReversible
+	}
+
+	now := time.Now().UTC()
+	rec.Status = StatusRolledBack
+	rec.RolledBackAt = &now
+	rec.RolledBackBy = by
+	e.record(rec)
+	log.Info().Str("action_id", id).Str("action", string(rec.Action)).Str("agent_id", rec.AgentID).
+		Msg("response action rolled back")
+	return *rec, nil
+}
+
+// Suggestions returns pending quarantine suggestions, newest first.
+func (e *Engine) Suggestions() []ActionRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out []ActionRecord
+	for i := len(e.records) - 1; i >= 0; i-- {
+		if e.records[i].Status == StatusSuggested {
+			out = append(out, *e.records[i])
+		}
+	}
+	return out
+}
+
+// AcceptSuggestion quarantines the agent named by a pending suggestion. The
+// record becomes an applied action that Rollback can release.
+func (e *Engine) AcceptSuggestion(id, by string, allowTools []string) (ActionRecord, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rec, ok := e.byID[id]
+	if !ok {
+		return ActionRecord{}, ErrNotFound
+	}
+	if rec.Status != StatusSuggested {
+		return *rec, ErrNotSuggestion
+	}
+	if allowTools == nil {
+		allowTools = e.cfg.QuarantineTools
+	}
+	reason := fmt.Sprintf("quarantine suggested by rule %s accepted by %s", rec.RuleID, by)
+	rec.RequestedBy = by
+	if e.containment.Quarantine(rec.AgentID, reason, allowTools) {
+		rec.Status = StatusApplied
+	} else {
+		rec.Status = StatusNoop
+	}
+	e.record(rec)
+	log.Warn().Str("action_id", id).Str("agent_id", rec.AgentID).Msg("quarantine suggestion accepted")
+	return *rec, nil
+}
+
+// Quarantine quarantines an agent at an operator's request and audits it.
+// A nil allowTools uses the configured default allowlist.
+func (e *Engine) Quarantine(orgID, agentID, reason, by string, allowTools []string) ActionRecord {
+	if allowTools == nil {
+		allowTools = e.cfg.QuarantineTools
+	}
+	if reason == "" {
+		reason = "quarantined by " + by
+	}
+	rec := &ActionRecord{
+		ID:          uuid.NewString(),
+		RuleID:      "manual",
+		Action:      ActionQuarantine,
+		Status:      StatusApplied,
+		OrgID:       orgID,
+		AgentID:     agentID,
+		CreatedAt:   time.Now().UTC(),
+		RequestedBy: by,
+	}
+	if !e.containment.Quarantine(agentID, reason, allowTools) {
+		rec.Status = StatusNoop
+	}
+	e.audit(rec)
+	log.Warn().Str("agent_id", agentID).Str("by", by).Strs("allow_tools", allowTools).Msg("agent quarantined")
+	return *rec
+}
+
+// Release lifts an agent's quarantine and marks the actions that applied it
+// as rolled back. It reports whether the agent was quarantined.
+func (e *Engine) Release(agentID, by string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.containment.Release(agentID) {
+		return false
+	}
+	now := time.Now().UTC()
+	for _, rec := range e.records {
+		if rec.AgentID == agentID && rec.Status == StatusApplied &&
+			(rec.Action == ActionQuarantine || rec.Action == ActionSuggestQuarantine) {
+			rec.Status = StatusRolledBack
+			rec.RolledBackAt = &now
+			rec.RolledBackBy = by
+			e.record(rec)
+		}
+	}
+	log.Info().Str("agent_id", agentID).Str("by", by).Msg("agent released from quarantine")
+	return true
+}
@@ -0,0 +1,132 @@
+package response_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/prompts"
+	"github.com/agentguard/agentguard/internal/response"
+)
+
+func TestEngine(t *testing.T) {
+	rules := []response.Rule{{
+		ID:          "contain",
+		MinSeverity: "critical",
+		Actions:     []response.ActionType{response.ActionSuspendAgent, response.ActionBlockPrompt, response.ActionNotify},
+	}}
+	critical := &models.SecuritySignal{
+		ID:       "sig-1",
+		Type:     models.SignalPolicyViolation,
+		Severity: "critical",
+		Title:    "Blocked prompt submitted",
+		Evidence: map[string]any{"prompt_hash": "abc12345"},
+	}
+	ctx := context.Background()
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		e := response.NewEngine(response.Config{DryRun: true, Rules: rules}, response.NewContainment(), prompts.NewRegistry(prompts.Config{}))
+		recs := e.HandleSignal(ctx, "org", "agent-1", critical)
+		if len(recs) != 3 {
+			t.Fatalf("got %d records, want 3", len(recs))
+		}
+		for _, r := range recs {
+			if r.Status != response.StatusDryRun {
+				t.Errorf("%s status = %s, want dry_run", r.Action, r.Status)
+			}
+		}
+		if ok, _ := e.Containment().Check("agent-1", ""); !ok {
+			t.Error("dry run suspended the agent")
+		}
+	})
+
+	t.Run("apply and roll back", func(t *testing.T) {
+		reg := prompts.NewRegistry(prompts.Config{})
+		e := response.NewEngine(response.Config{Rules: rules}, response.NewContainment(), reg)
+
+		low := *critical
+		low.Severity = "high"
+		if recs := e.HandleSignal(ctx, "org", "agent-1", &low); len(recs) != 0 {
+			t.Fatalf("high signal matched critical rule: %+v", recs)
+		}
+
+		recs := e.HandleSignal(ctx, "org", "agent-1", critical)
+		if ok, _ := e.Containment().Check("agent-1", ""); ok {
+			t.Fatal("agent not suspended")
+		}
+		if _, ok := reg.Blocklist()["abc12345"]; !ok {
+			t.Fatal("prompt not blocked")
+		}
+
+		for _, r := range recs {
+			_, err := e.Rollback(r.ID, "tester")
+			if r.Action == response.ActionNotify {
+				if !errors.Is(err, response.ErrNotReversible) {
+					t.Errorf("notify rollback err = %v, want ErrNotReversible", err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("rollback %s: %v", r.Action, err)
+			}
+			if _, err := e.Rollback(r.ID, "tester"); !errors.Is(err, response.ErrAlreadyUndone) {
+				t.Errorf("second rollback err = %v, want ErrAlreadyUndone", err)
+			}
+		}
+		if ok, _ := e.Containment().Check("agent-1", ""); !ok {
+			t.Error("agent still suspended after rollback")
+		}
+		if len(reg.Blocklist()) != 0 {
+			t.Error("prompt still blocked after rollback")
+		}
+	})
+
+	t.Run("missing agent fails agent actions", func(t *testing.T) {
+		e := response.NewEngine(response.Config{Rules: rules}, response.NewContainment(), nil)
+		recs := e.HandleSignal(ctx, "org", "", critical)
+		if recs[0].Status != response.StatusFailed || recs[1].Status != response.StatusFailed {
+			t.Errorf("statuses = %s, %s, want failed", recs[0].Status, recs[1].Status)
+		}
+	})
+
+	t.Run("quarantine suggestion accepted and released", func(t *testing.T) {
+		suggest := []response.Rule{{
+			ID:          "suggest",
+			MinSeverity: "high",
+			Actions:     []response.ActionType{response.ActionSuggestQuarantine},
+		}}
+		// Dry run does not hold back suggestions.
+		e := response.NewEngine(response.Config{DryRun: true, Rules: suggest, QuarantineTools: []string{"search"}}, response.NewContainment(), nil)
+		recs := e.HandleSignal(ctx, "org", "agent-1", critical)
+		if len(recs) != 1 || recs[0].Status != response.StatusSuggested {
+			t.Fatalf("records = %+v, want one suggestion", recs)
+		}
+		if ok, _ := e.Containment().Check("agent-1", "shell"); !ok {
+			t.Fatal("suggestion quarantined the agent")
+		}
+
+		if _, err := e.AcceptSuggestion(recs[0].ID, "tester", nil); err != nil {
+			t.Fatalf("accept: %v", err)
+		}
+		if _, err := e.AcceptSuggestion(recs[0].ID, "tester", nil); !errors.Is(err, response.ErrNotSuggestion) {
+			t.Errorf("second accept err = %v, want ErrNotSuggestion", err)
+		}
+		if ok, _ := e.Containment().Check("agent-1", "shell"); ok {
+			t.Error("quarantined agent may call a tool off the allowlist")
+		}
+		if ok, _ := e.Containment().Check("agent-1", "search"); !ok {
+			t.Error("quarantined agent denied an allowlisted tool")
+		}
+
+		if !e.Release("agent-1", "tester") {
+			t.Fatal("release reported agent not quarantined")
+		}
+		if ok, _ := e.Containment().Check("agent-1", "shell"); !ok {
+			t.Error("agent still quarantined after release")
+		}
+		if got := e.Actions("agent-1")[0].Status; got != response.StatusRolledBack {
+			t.Errorf("accepted suggestion status after release = %s, want rolled_back", got)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+// Package severity re-scores security signals by the criticality of the
+// asset they were raised on. A medium signal from a production agent with a
+// high risk level that touched regulated data matters more than the same
+// signal from a development sandbox, so rules combining the agent's
+// environment, risk level and the data classifications involved raise or
+// lower severities before signals are stored, exported or routed to the
+// response engine.
+package severity
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/google/uuid"
+)
+
+// levels are the signal severities, lowest first.
+var levels = []string{"low", "medium", "high", "critical"}
+
+// rank orders signal severities; unknown severities rank zero.
+var rank = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// Valid reports whether s is a known severity.
+func Valid(s string) bool { return rank[s] > 0 }
+
+// Evidence keys recorded on re-scored signals.
+const (
+	// EvidenceBaseSeverity is the severity the signal was raised with.
+	EvidenceBaseSeverity = "base_severity"
+	// EvidenceRules lists the IDs of the rules that matched.
+	EvidenceRules = "severity_rules"
+	// EvidenceAsset is the asset context the signal was scored against.
+	EvidenceAsset = "asset"
+)
+
+// Attribute and metadata keys carrying asset context on traces and signal
+// evidence.
+const (
+	KeyEnvironment         = "environment"
+	KeyRiskLevel           = "risk_level"
+	KeyDataClassification  = "data_classification"
+	KeyDataClassifications = "data_classifications"
+	// SpanKeyDataClassification is the span attribute naming the
+	// classification of the data a span read or wrote.
+	SpanKeyDataClassification = "data.classification"
+)
+
+// Asset is what is known about the agent a signal was raised on.
+type Asset struct {
+	Environment         string   `json:"environment,omitempty"`
+	RiskLevel           string   `json:"risk_level,omitempty"`
+	DataClassifications []string `json:"data_classifications,omitempty"`
+}
+
+// merge fills a's empty fields from o and adds o's classifications.
+func (a Asset) merge(o Asset) Asset {
+	if a.Environment == "" {
+		a.Environment = o.Environment
+	}
+	if a.RiskLevel == "" {
+		a.RiskLevel = o.RiskLevel
+	}
+	a.DataClassifications = normalize(append(append([]string{}, a.DataClassifications...), o.DataClassifications...))
+	return a
+}
+
+// Rule adjusts the severity of signals on matching assets. Each non-empty
+// list must contain the asset's value; DataClassifications matches when the
+// asset touched any of them. A rule with no lists matches every asset.
+type Rule struct {
+	ID                  string   `json:"id"`
+	Environments        []string `json:"environments,omitempty"`
+	RiskLevels          []string `json:"risk_levels,omitempty"`
+	DataClassifications []string `json:"data_classifications,omitempty"`
+	// Adjust moves the severity up (positive) or down (negative) by this
+	// many levels.
+	Adjust int `json:"adjust"`
+	// Floor and Ceiling bound the adjusted severity. Optional.
+	Floor   string `json:"floor,omitempty"`
+	Ceiling string `json:"ceiling,omitempty"`
+}
+
+func (r *Rule) matches(a Asset) bool {
+	if len(r.Environments) > 0 && !contains(r.Environments, a.Environment) {
+		return false
+	}
+	if len(r.RiskLevels) > 0 && !contains(r.RiskLevels, a.RiskLevel) {
+		return false
+	}
+	if len(r.DataClassifications) > 0 {
+		for _, c := range a.DataClassifications {
+			if contains(r.DataClassifications, c) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Config configures a Scorer.
+type Config struct {
+	Rules []Rule
+	// Assets is the asset inventory by agent ID. Its environment and risk
+	// level take precedence over what agents report about themselves.
+	Assets map[string]Asset
+}
+
+// Result is the outcome of scoring one severity.
+type Result struct {
+	Base     string   `json:"base_severity"`
+	Severity string   `json:"severity"`
+	Rules    []string `json:"rules"`
+	Asset    Asset    `json:"asset"`
+}
+
+// Scorer applies severity rules. A nil Scorer leaves severities unchanged.
+type Scorer struct {
+	rules  []Rule
+	assets map[string]Asset
+}
+
+// New creates a Scorer. Rule and asset values are compared case-insensitively.
+func New(cfg Config) *Scorer {
+	s := &Scorer{assets: make(map[string]Asset, len(cfg.Assets))}
+	for _, r := range cfg.Rules {
+		r.Environments = normalize(r.Environments)
+		r.RiskLevels = normalize(r.RiskLevels)
+		r.DataClassifications = normalize(r.DataClassifications)
+		s.rules = append(s.rules, r)
+	}
+	for id, a := range cfg.Assets {
+		s.assets[strings.ToLower(id)] = clean(a)
+	}
+	return s
+}
+
+// Rules returns the configured rules in evaluation order.
+func (s *Scorer) Rules() []Rule {
+	if s == nil {
+		return nil
+	}
+	return append([]Rule(nil), s.rules...)
+}
+
+// Resolve combines the inventory entry for agentID with the context the
+// agent reported.
+func (s *Scorer) Resolve(agentID string, reported Asset) Asset {
+	reported = clean(reported)
+	if s == nil {
+		return reported
+	}
+	return s.assets[strings.ToLower(agentID)].merge(reported)
+}
+
+// Score adjusts severity for asset a. Adjustments from every matching rule
+// add up and the result stays within low and critical, then within the
+// highest matching floor and the lowest matching ceiling. Unknown
+// severities are returned unchanged.
+func (s *Scorer) Score(severity string, a Asset) Result {
+	res := Result{Base: severity, Severity: severity, Asset: a}
+	if s == nil || !Valid(severity) {
+		return res
+	}
+	level := rank[severity]
+	floor, ceiling := rank["low"], rank["critical"]
+	for i := range s.rules {
+		r := &s.rules[i]
+		if !r.matches(a) {
+			continue
+		}
+		res.Rules = append(res.Rules, r.ID)
+		level += r.Adjust
+		if f := rank[r.Floor]; f > floor {
+			floor = f
+		}
+		if c := rank[r.Ceiling]; c > 0 && c < ceiling {
+			ceiling = c
+		}
+	}
+	level = max(level, rank["low"])
+	level = min(level, rank["critical"])
+	level = max(level, floor)
+	level = min(level, ceiling)
+	res.Severity = levels[level-1]
+	return res
+}
+
+// Rescore adjusts sig's severity for the asset it was raised on: the
+// inventory entry for agentID, then reported, then context found in the
+// signal's evidence. A signal that was already re-scored is scored again
+// from its base severity, so rescoring is idempotent. It reports whether
+// the severity changed.
+func (s *Scorer) Rescore(agentID string, reported Asset, sig *models.SecuritySignal) bool {
+	if s == nil || len(s.rules) == 0 {
+		return false
+	}
+	base := sig.Severity
+	if b, ok := sig.Evidence[EvidenceBaseSeverity].(string); ok && Valid(b) {
+		base = b
+	}
+	a := s.Resolve(agentID, reported.merge(fromMap(sig.Evidence)))
+	res := s.Score(base, a)
+	if len(res.Rules) == 0 {
+		return false
+	}
+	if sig.Evidence == nil {
+		sig.Evidence = make(map[string]any)
+	}
+	sig.Evidence[EvidenceBaseSeverity] = res.Base
+	sig.Evidence[EvidenceRules] = res.Rules
+	sig.Evidence[EvidenceAsset] = res.Asset
+	changed := sig.Severity != res.Severity
+	sig.Severity = res.Severity
+	return changed
+}
+
+// RescoreTrace re-scores every signal on t against the trace's asset
+// context and returns how many changed severity.
+func (s *Scorer) RescoreTrace(t *models.AgentTrace, reported Asset) int {
+	if s == nil || len(t.SecuritySignals) == 0 {
+		return 0
+	}
+	agentID := ""
+	if t.AgentID != uuid.Nil {
+		agentID = t.AgentID.String()
+	}
+	n := 0
+	for i := range t.SecuritySignals {
+		if s.Rescore(agentID, reported, &t.SecuritySignals[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+// TraceAsset reads asset context from a trace: the environment, risk
+// level and data classifications in its metadata, and the
+// data.classification attribute of its spans. Call it before span
+// attributes are filtered.
+func TraceAsset(t *models.AgentTrace) Asset {
+	a := fromMap(t.Metadata)
+	for _, sp := range t.Spans {
+		a.DataClassifications = append(a.DataClassifications, strs(sp.Attributes[SpanKeyDataClassification])...)
+	}
+	return clean(a)
+}
+
+// fromMap reads asset context from trace metadata or signal evidence.
+func fromMap(m map[string]any) Asset {
+	var a Asset
+	if v, ok := m[KeyEnvironment].(string); ok {
+		a.Environment = v
+	}
+	if v, ok := m[KeyRiskLevel].(string); ok {
+		a.RiskLevel = v
+	}
+	a.DataClassifications = append(strs(m[KeyDataClassification]), strs(m[KeyDataClassifications])...)
+	return a
+}
+
+// strs reads a string or a list of strings.
+func strs(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		var out []string
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func clean(a Asset) Asset {
+	a.Environment = strings.ToLower(strings.TrimSpace(a.Environment))
+	a.RiskLevel = strings.ToLower(strings.TrimSpace(a.RiskLevel))
+	a.DataClassifications = normalize(a.DataClassifications)
+	return a
+}
+
+// normalize lowercases, trims, sorts and deduplicates values.
+func normalize(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package severity_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/severity"
+)
+
+var rules = []severity.Rule{
+	{ID: "prod-high-risk", Environments: []string{"prod", "production"}, RiskLevels: []string{"high", "critical"}, Adjust: 1},
+	{ID: "regulated-data", DataClassifications: []string{"pii", "phi"}, Adjust: 1},
+	{ID: "dev-sandbox", Environments: []string{"dev"}, Adjust: -1},
+	{ID: "payments-floor", DataClassifications: []string{"pci"}, Floor: "high"},
+	{ID: "staging-cap", Environments: []string{"staging"}, Ceiling: "medium"},
+}
+
+func TestScore(t *testing.T) {
+	s := severity.New(severity.Config{Rules: rules})
+	tests := []struct {
+		name      string
+		severity  string
+		asset     severity.Asset
+		want      string
+		wantRules []string
+	}{
+		{"no match", "medium", severity.Asset{Environment: "prod", RiskLevel: "low"}, "medium", nil},
+		{"prod high risk", "medium", severity.Asset{Environment: "production", RiskLevel: "high"}, "high", []string{"prod-high-risk"}},
+		{"adjustments add up", "medium", severity.Asset{Environment: "prod", RiskLevel: "critical", DataClassifications: []string{"pii"}}, "critical", []string{"prod-high-risk", "regulated-data"}},
+		{"capped at critical", "high", severity.Asset{Environment: "prod", RiskLevel: "high", DataClassifications: []string{"phi"}}, "critical", []string{"prod-high-risk", "regulated-data"}},
+		{"dev lowered", "medium", severity.Asset{Environment: "dev"}, "low", []string{"dev-sandbox"}},
+		{"not below low", "low", severity.Asset{Environment: "dev"}, "low", []string{"dev-sandbox"}},
+		{"floor", "low", severity.Asset{DataClassifications: []string{"pci"}}, "high", []string{"payments-floor"}},
+		{"ceiling", "critical", severity.Asset{Environment: "staging"}, "medium", []string{"staging-cap"}},
+		{"unknown severity", "info", severity.Asset{Environment: "dev"}, "info", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Score(tt.severity, tt.asset)
+			if got.Severity != tt.want || !reflect.DeepEqual(got.Rules, tt.wantRules) {
+				t.Errorf("Score(%s) = %s %v, want %s %v", tt.severity, got.Severity, got.Rules, tt.want, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestRescore(t *testing.T) {
+	s := severity.New(severity.Config{
+		Rules: rules,
+		// The inventory overrides the environment the agent reports.
+		Assets: map[string]severity.Asset{"agent-1": {Environment: "Prod", RiskLevel: "high"}},
+	})
+	sig := models.SecuritySignal{
+		Type:     models.SignalFailOpen,
+		Severity: "medium",
+		Evidence: map[string]any{"environment": "dev", "data_classification": "PII"},
+	}
+	if !s.Rescore("agent-1", severity.Asset{}, &sig) {
+		t.Fatal("Rescore reported no change")
+	}
+	if sig.Severity != "critical" || sig.Evidence[severity.EvidenceBaseSeverity] != "medium" {
+		t.Fatalf("signal = %+v, want medium raised to critical", sig)
+	}
+	want := severity.Asset{Environment: "prod", RiskLevel: "high", DataClassifications: []string{"pii"}}
+	if got := sig.Evidence[severity.EvidenceAsset]; !reflect.DeepEqual(got, want) {
+		t.Errorf("asset = %+v, want %+v", got, want)
+	}
+
+	// Rescoring again starts from the base severity.
+	if s.Rescore("agent-1", severity.Asset{}, &sig) || sig.Severity != "critical" {
+		t.Errorf("second Rescore changed the signal: %+v", sig)
+	}
+
+	// Agents missing from the inventory are scored on what they report.
+	other := models.SecuritySignal{Severity: "high"}
+	s.Rescore("agent-2", severity.Asset{Environment: "dev"}, &other)
+	if other.Severity != "medium" {
+		t.Errorf("severity = %s, want dev signal lowered to medium", other.Severity)
+	}
+
+	var nilScorer *severity.Scorer
+	if nilScorer.Rescore("agent-1", severity.Asset{}, &other) || other.Severity != "medium" {
+		t.Error("nil Scorer changed the signal")
+	}
+}
+
+func TestTraceAsset(t *testing.T) {
+	trace := &models.AgentTrace{
+		Metadata: map[string]any{"environment": " PROD ", "risk_level": "High", "data_classifications": []any{"confidential"}},
+		Spans: []models.Span{
+			{Attributes: map[string]any{"data.classification": "pii"}},
+			{Attributes: map[string]any{"data.classification": []any{"PII", "phi"}}},
+			{},
+		},
+	}
+	want := severity.Asset{Environment: "prod", RiskLevel: "high", DataClassifications: []string{"confidential", "phi", "pii"}}
+	if got := severity.TraceAsset(trace); !reflect.DeepEqual(got, want) {
+		t.Errorf("TraceAsset = %+v, want %+v", got, want)
+	}
+}
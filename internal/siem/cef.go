@@ -0,0 +1,145 @@
+package siem
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event fields that can be mapped to CEF extension keys.
+const (
+	FieldTime        = "time"
+	FieldSignalID    = "signal_id"
+	FieldType        = "type"
+	FieldDescription = "description"
+	FieldOrgID       = "org_id"
+	FieldAgentID     = "agent_id"
+	FieldTraceID     = "trace_id"
+	FieldSpanID      = "span_id"
+	FieldTool        = "tool"
+	FieldPolicies    = "policies"
+	FieldReasons     = "reasons"
+	FieldAction      = "action"
+	FieldEnvironment = "environment"
+)
+
+// DefaultFields maps event fields to CEF extension keys. Fields on custom
+// string keys (cs1..cs6) are labeled with the field name.
+var DefaultFields = map[string]string{
+	FieldTime:        "rt",
+	FieldSignalID:    "externalId",
+	FieldType:        "cat",
+	FieldDescription: "msg",
+	FieldOrgID:       "cs1",
+	FieldAgentID:     "suser",
+	FieldTraceID:     "cs2",
+	FieldSpanID:      "cs3",
+	FieldTool:        "cs4",
+	FieldPolicies:    "cs5",
+	FieldReasons:     "reason",
+	FieldAction:      "act",
+	FieldEnvironment: "cs6",
+}
+
+// fieldOrder fixes the order of extension fields so events diff cleanly.
+var fieldOrder = []string{
+	FieldTime, FieldSignalID, FieldType, FieldAction, FieldOrgID, FieldAgentID,
+	FieldTool, FieldPolicies, FieldReasons, FieldTraceID, FieldSpanID,
+	FieldEnvironment, FieldDescription,
+}
+
+// maxFieldLen truncates extension values; many CEF receivers cap msg and
+// custom strings at 1023 characters.
+const maxFieldLen = 1023
+
+// event is a signal or denial ready to be rendered as CEF.
+type event struct {
+	signatureID string
+	name        string
+	severity    string
+	time        time.Time
+	fields      map[string]string
+}
+
+// cefSeverity maps AgentGuard severities to the CEF 0-10 scale.
+func cefSeverity(severity string) int {
+	switch strings.ToLower(severity) {
+	case "low":
+		return 3
+	case "high":
+		return 8
+	case "critical":
+		return 10
+	}
+	return 5
+}
+
+// cef renders an event as a CEF:0 record. Extension keys come from fields,
+// a mapping of event fields to CEF keys; fields mapped to "" are dropped.
+func (e event) cef(version string, fields map[string]string) string {
+	var b strings.Builder
+	b.WriteString("CEF:0|AgentGuard|AgentGuard|")
+	b.WriteString(cefHeader(version))
+	b.WriteByte('|')
+	b.WriteString(cefHeader(e.signatureID))
+	b.WriteByte('|')
+	b.WriteString(cefHeader(e.name))
+	b.WriteByte('|')
+	b.WriteString(strconv.Itoa(cefSeverity(e.severity)))
+	b.WriteByte('|')
+
+	values := make(map[string]string, len(e.fields)+1)
+	for k, v := range e.fields {
+		values[k] = v
+	}
+	if !e.time.IsZero() {
+		values[FieldTime] = strconv.FormatInt(e.time.UnixMilli(), 10)
+	}
+	first := true
+	add := func(key, value string) {
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(cefValue(value))
+	}
+	for _, field := range fieldOrder {
+		value := values[field]
+		key := fields[field]
+		if value == "" || key == "" {
+			continue
+		}
+		add(key, value)
+		if isCustomString(key) {
+			add(key+"Label", field)
+		}
+	}
+	return b.String()
+}
+
+// isCustomString reports whether key is a CEF custom string (cs1..cs6),
+// which receivers display by its label.
+func isCustomString(key string) bool {
+	return len(key) == 3 && strings.HasPrefix(key, "cs") && key[2] >= '1' && key[2] <= '6'
+}
+
+var headerEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+
+func cefHeader(s string) string {
+	return headerEscaper.Replace(s)
+}
+
+var valueEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+
+func cefValue(s string) string {
+	if len(s) > maxFieldLen {
+		s = s[:maxFieldLen]
+		for !utf8.ValidString(s) {
+			s = s[:len(s)-1]
+		}
+	}
+	return valueEscaper.Replace(s)
+}
@@ -0,0 +1,225 @@
+package siem_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/siem"
+)
+
+func testSignal() models.SecuritySignal {
+	return models.SecuritySignal{
+		ID:          "sig-1",
+		TraceID:     "trace-1",
+		Type:        models.SignalToolAbuse,
+		Severity:    "critical",
+		Title:       "honeypot | invoked",
+		Description: "called drop_tables with a=b\nthen exited",
+		Timestamp:   time.UnixMilli(1700000000123),
+	}
+}
+
+// readLines accepts one TCP connection and returns the first n
+// newline-delimited messages.
+func readLines(t *testing.T, ln net.Listener, n int) []string {
+	t.Helper()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	var lines []string
+	for len(lines) < n {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading syslog: %v (got %q)", err, lines)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	return lines
+}
+
+func TestSyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	s, err := siem.NewSyslog(siem.SyslogConfig{Addr: ln.Addr().String(), Hostname: "gw1", Version: "1.2.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.ExportSignals(context.Background(), "org-1", "agent-1", []models.SecuritySignal{testSignal()}); err != nil {
+		t.Fatal(err)
+	}
+	s.Deny("org-1", siem.Denial{
+		AgentID:  "agent-1",
+		Tool:     "shell",
+		Reasons:  []string{"tool not allowed", "prod"},
+		Policies: []string{"tool_allowlist"},
+		Severity: "high",
+		Time:     time.UnixMilli(1700000000456),
+	})
+
+	lines := readLines(t, ln, 2)
+	// local4 (20) * 8 + crit (2)
+	want := "<162>1 "
+	if !strings.HasPrefix(lines[0], want) || !strings.Contains(lines[0], " gw1 agentguard - - - CEF:0|AgentGuard|AgentGuard|1.2.3|tool_abuse|honeypot \\| invoked|10|") {
+		t.Errorf("signal = %q", lines[0])
+	}
+	for _, field := range []string{
+		"rt=1700000000123", "externalId=sig-1", "cat=tool_abuse",
+		"cs1=org-1 cs1Label=org_id", "suser=agent-1", "cs2=trace-1 cs2Label=trace_id",
+		`msg=called drop_tables with a\=b\nthen exited`,
+	} {
+		if !strings.Contains(lines[0], field) {
+			t.Errorf("signal missing %q: %s", field, lines[0])
+		}
+	}
+
+	// local4 (20) * 8 + err (3)
+	if !strings.HasPrefix(lines[1], "<163>1 ") || !strings.Contains(lines[1], "|policy_denial|Policy denied tool shell|8|") {
+		t.Errorf("denial = %q", lines[1])
+	}
+	for _, field := range []string{"act=deny", "cs4=shell cs4Label=tool", "cs5=tool_allowlist", "reason=tool not allowed; prod"} {
+		if !strings.Contains(lines[1], field) {
+			t.Errorf("denial missing %q: %s", field, lines[1])
+		}
+	}
+}
+
+func TestSyslogFieldMappingAndRFC3164(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	s, err := siem.NewSyslog(siem.SyslogConfig{
+		Addr:     ln.Addr().String(),
+		Facility: "auth",
+		Format:   siem.FormatRFC3164,
+		Hostname: "gw1",
+		Fields:   map[string]string{siem.FieldAgentID: "duid", siem.FieldDescription: ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	sig := testSignal()
+	sig.Severity = "low"
+	if err := s.ExportSignals(context.Background(), "org-1", "agent-1", []models.SecuritySignal{sig}); err != nil {
+		t.Fatal(err)
+	}
+
+	line := readLines(t, ln, 1)[0]
+	// auth (4) * 8 + notice (5)
+	if !strings.HasPrefix(line, "<37>") || !strings.Contains(line, " gw1 agentguard: CEF:0|") {
+		t.Errorf("header = %q", line)
+	}
+	if !strings.Contains(line, "duid=agent-1") || strings.Contains(line, "suser=") || strings.Contains(line, "msg=") {
+		t.Errorf("field mapping not applied: %s", line)
+	}
+
+	if _, err := siem.NewSyslog(siem.SyslogConfig{Addr: "x:1", Fields: map[string]string{"nope": "cs1"}}); err == nil {
+		t.Error("unknown field accepted")
+	}
+	if _, err := siem.NewSyslog(siem.SyslogConfig{Addr: "x:1", Facility: "local9"}); err == nil {
+		t.Error("unknown facility accepted")
+	}
+}
+
+func TestSyslogTLSOctetCounting(t *testing.T) {
+	// Borrow the test server's certificate for a TLS syslog listener.
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: srv.TLS.Certificates})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	s, err := siem.NewSyslog(siem.SyslogConfig{
+		Network: siem.NetworkTLS,
+		Addr:    ln.Addr().String(),
+		TLS:     &tls.Config{RootCAs: roots, ServerName: "example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExportSignals(context.Background(), "org-1", "", []models.SecuritySignal{testSignal()}); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	length, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatalf("reading frame length: %v", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		t.Fatalf("frame length %q: %v", length, err)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(msg), "<162>1 ") || !strings.HasSuffix(string(msg), `msg=called drop_tables with a\=b\nthen exited`) {
+		t.Errorf("message = %q", msg)
+	}
+	// Close flushes and stops accepting events.
+	s.Close()
+	if err := s.ExportSignals(context.Background(), "org-1", "", []models.SecuritySignal{testSignal()}); err == nil {
+		t.Error("closed exporter accepted a signal")
+	}
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("udp unavailable:", err)
+	}
+	defer conn.Close()
+
+	s, err := siem.NewSyslog(siem.SyslogConfig{Network: siem.NetworkUDP, Addr: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Deny("org-1", siem.Denial{AgentID: "agent-1"})
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// local4 (20) * 8 + warning (4); one message per datagram, unframed.
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<164>1 ") || !strings.Contains(msg, "|Policy denied request|5|") || strings.HasSuffix(msg, "\n") {
+		t.Errorf("datagram = %q", msg)
+	}
+}
@@ -0,0 +1,358 @@
+// Package siem forwards security signals and policy denials to security
+// information and event management systems as CEF events over syslog, for
+// SOCs that ingest syslog rather than the AgentGuard API.
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Syslog transports.
+const (
+	NetworkTCP = "tcp"
+	NetworkTLS = "tls"
+	NetworkUDP = "udp"
+)
+
+// Syslog message formats.
+const (
+	FormatRFC5424 = "rfc5424"
+	FormatRFC3164 = "rfc3164"
+)
+
+// facilities are the syslog facility codes by name.
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// ErrQueueFull is returned when events arrive faster than the syslog
+// receiver accepts them; the events are dropped.
+var ErrQueueFull = errors.New("syslog: queue full, event dropped")
+
+var errClosed = errors.New("syslog: exporter is closed")
+
+// SyslogConfig configures a syslog exporter.
+type SyslogConfig struct {
+	// Network is tcp (default), tls or udp.
+	Network string
+	// Addr is the receiver's host:port. Required.
+	Addr string
+	// TLS configures the tls network. Defaults to verifying the receiver
+	// against the system roots.
+	TLS *tls.Config
+	// Facility is a facility name such as local4 (default), auth or user.
+	Facility string
+	// Format is the syslog header, rfc5424 (default) or rfc3164.
+	Format string
+	// Hostname is reported in the header. Defaults to the host name.
+	Hostname string
+	// Version is reported as the CEF device version.
+	Version string
+	// Fields override DefaultFields, mapping event fields to CEF extension
+	// keys; a field mapped to "" is left out.
+	Fields map[string]string
+	// QueueSize bounds events waiting to be sent. Defaults to 1024.
+	QueueSize int
+	// DialTimeout bounds connecting to the receiver. Defaults to 5 seconds.
+	DialTimeout time.Duration
+}
+
+// Denial describes a denied pre-invoke request.
+type Denial struct {
+	AgentID     string
+	Tool        string
+	Environment string
+	Reasons     []string
+	Policies    []string
+	// Severity is the most severe violated rule's severity; empty is
+	// reported as medium.
+	Severity string
+	Time     time.Time
+}
+
+// Syslog sends CEF events to a syslog receiver. Events are queued and
+// written by a background goroutine so a slow or unreachable receiver never
+// holds up a request; the connection is redialed after a failed write. It
+// implements apm.SignalExporter.
+type Syslog struct {
+	cfg      SyslogConfig
+	facility int
+	fields   map[string]string
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan queued
+	done   chan struct{}
+
+	conn net.Conn // owned by the writer goroutine
+}
+
+type queued struct {
+	msg      string
+	severity int
+}
+
+// NewSyslog creates a syslog exporter and starts its writer. Call Close to
+// flush queued events.
+func NewSyslog(cfg SyslogConfig) (*Syslog, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("syslog: address is required")
+	}
+	if cfg.Network == "" {
+		cfg.Network = NetworkTCP
+	}
+	switch cfg.Network {
+	case NetworkTCP, NetworkTLS, NetworkUDP:
+	default:
+		return nil, fmt.Errorf("syslog: unknown network %q", cfg.Network)
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatRFC5424
+	}
+	if cfg.Format != FormatRFC5424 && cfg.Format != FormatRFC3164 {
+		return nil, fmt.Errorf("syslog: unknown format %q", cfg.Format)
+	}
+	if cfg.Facility == "" {
+		cfg.Facility = "local4"
+	}
+	facility, ok := facilities[strings.ToLower(cfg.Facility)]
+	if !ok {
+		return nil, fmt.Errorf("syslog: unknown facility %q", cfg.Facility)
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+		if cfg.Hostname == "" {
+			cfg.Hostname = "-"
+		}
+	}
+	if cfg.Version == "" {
+		cfg.Version = "dev"
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+
+	fields := make(map[string]string, len(DefaultFields))
+	for k, v := range DefaultFields {
+		fields[k] = v
+	}
+	for k, v := range cfg.Fields {
+		if _, ok := DefaultFields[k]; !ok {
+			return nil, fmt.Errorf("syslog: unknown field %q", k)
+		}
+		fields[k] = v
+	}
+
+	s := &Syslog{
+		cfg:      cfg,
+		facility: facility,
+		fields:   fields,
+		queue:    make(chan queued, cfg.QueueSize),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Name identifies the exporter in logs.
+func (s *Syslog) Name() string { return "syslog" }
+
+// ExportSignals queues each signal as a CEF event whose signature ID is the
+// signal type.
+func (s *Syslog) ExportSignals(_ context.Context, orgID, agentID string, signals []models.SecuritySignal) error {
+	var errs []error
+	for _, sig := range signals {
+		name := sig.Title
+		if name == "" {
+			name = string(sig.Type)
+		}
+		e := event{
+			signatureID: string(sig.Type),
+			name:        name,
+			severity:    sig.Severity,
+			time:        sig.Timestamp,
+			fields: map[string]string{
+				FieldSignalID:    sig.ID,
+				FieldType:        string(sig.Type),
+				FieldDescription: sig.Description,
+				FieldOrgID:       orgID,
+				FieldAgentID:     agentID,
+				FieldTraceID:     sig.TraceID,
+				FieldSpanID:      sig.SpanID,
+			},
+		}
+		errs = append(errs, s.send(e))
+	}
+	return errors.Join(errs...)
+}
+
+// Deny queues a policy denial as a CEF event with signature ID
+// policy_denial. A full queue drops the event with a warning.
+func (s *Syslog) Deny(orgID string, d Denial) {
+	name := "Policy denied request"
+	if d.Tool != "" {
+		name = "Policy denied tool " + d.Tool
+	}
+	severity := d.Severity
+	if severity == "" {
+		severity = "medium"
+	}
+	if d.Time.IsZero() {
+		d.Time = time.Now()
+	}
+	e := event{
+		signatureID: "policy_denial",
+		name:        name,
+		severity:    severity,
+		time:        d.Time,
+		fields: map[string]string{
+			FieldType:        "policy_denial",
+			FieldAction:      "deny",
+			FieldOrgID:       orgID,
+			FieldAgentID:     d.AgentID,
+			FieldTool:        d.Tool,
+			FieldPolicies:    strings.Join(d.Policies, ","),
+			FieldReasons:     strings.Join(d.Reasons, "; "),
+			FieldEnvironment: d.Environment,
+		},
+	}
+	if err := s.send(e); err != nil {
+		log.Warn().Err(err).Str("agent_id", d.AgentID).Msg("policy denial not forwarded to syslog")
+	}
+}
+
+// send formats an event and queues it without blocking.
+func (s *Syslog) send(e event) error {
+	q := queued{msg: e.cef(s.cfg.Version, s.fields), severity: syslogSeverity(e.severity)}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errClosed
+	}
+	select {
+	case s.queue <- q:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting events, sends those already queued, and closes the
+// connection.
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *Syslog) run() {
+	defer close(s.done)
+	defer func() {
+		if s.conn != nil {
+			s.conn.Close()
+		}
+	}()
+	for q := range s.queue {
+		frame := s.frame(q, time.Now())
+		// A broken stream connection is only noticed on write; redial
+		// once so a receiver restart costs no more than one event.
+		var err error
+		for attempt := 0; attempt < 2; attempt++ {
+			if err = s.write(frame); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			log.Error().Err(err).Str("addr", s.cfg.Addr).Msg("failed to send event to syslog")
+		}
+	}
+}
+
+func (s *Syslog) write(frame []byte) error {
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(s.cfg.DialTimeout))
+	if _, err := s.conn.Write(frame); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *Syslog) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.cfg.DialTimeout}
+	if s.cfg.Network == NetworkTLS {
+		cfg := s.cfg.TLS
+		if cfg == nil {
+			cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		return tls.DialWithDialer(dialer, "tcp", s.cfg.Addr, cfg)
+	}
+	return dialer.Dial(s.cfg.Network, s.cfg.Addr)
+}
+
+// frame renders a queued event as a syslog message. Stream transports
+// delimit messages with a newline over tcp and, per RFC 5425, an octet
+// count over tls.
+func (s *Syslog) frame(q queued, now time.Time) []byte {
+	pri := s.facility*8 + q.severity
+	var msg string
+	if s.cfg.Format == FormatRFC3164 {
+		msg = fmt.Sprintf("<%d>%s %s agentguard: %s", pri, now.Format(time.Stamp), s.cfg.Hostname, q.msg)
+	} else {
+		msg = fmt.Sprintf("<%d>1 %s %s agentguard - - - %s", pri, now.UTC().Format(time.RFC3339Nano), s.cfg.Hostname, q.msg)
+	}
+	switch s.cfg.Network {
+	case NetworkTLS:
+		return []byte(strconv.Itoa(len(msg)) + " " + msg)
+	case NetworkTCP:
+		return []byte(msg + "\n")
+	}
+	return []byte(msg)
+}
+
+// syslogSeverity maps AgentGuard severities to syslog severities.
+func syslogSeverity(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 2
+	case "high":
+		return 3
+	case "medium":
+		return 4
+	case "low":
+		return 5
+	}
+	return 6
+}
@@ -0,0 +1,226 @@
+// Package signing issues per-agent keys for signing SDK hook requests and
+// verifies those signatures. Requests are signed like AgentGuard's own
+// webhooks (see pkg/client): an HMAC-SHA-256 over a timestamp, a nonce and
+// the body. Timestamps may be off by the configured tolerance in either
+// direction, and a nonce is accepted once.
+package signing
+
+import (
+	"container/list"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+	"github.com/agentguard/agentguard/pkg/client"
+	"github.com/google/uuid"
+)
+
+// DefaultGrace is how long a key stays valid after a newer key replaces it.
+const DefaultGrace = 24 * time.Hour
+
+// ErrNoKey is returned when an agent has no active signing key.
+var ErrNoKey = errors.New("agent has no active signing key")
+
+// Options configures Keys.
+type Options struct {
+	// Tolerance is how far a request's timestamp may be from the server
+	// clock. Defaults to client.DefaultWebhookTolerance.
+	Tolerance time.Duration
+	// Grace is how long replaced keys stay valid after a rotation.
+	// Defaults to DefaultGrace.
+	Grace time.Duration
+}
+
+// Keys issues, rotates and verifies agents' signing keys. It is safe for
+// concurrent use.
+type Keys struct {
+	repo      repository.SigningKeyRepository
+	tolerance time.Duration
+	grace     time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	order *list.List // of *seenNonce, soonest forgotten first
+	seen  map[string]*list.Element
+}
+
+// seenNonce is an agent's nonce, remembered until it can no longer be replayed
+// within tolerance.
+type seenNonce struct {
+	key    string
+	forget time.Time
+}
+
+// New creates Keys backed by repo.
+func New(repo repository.SigningKeyRepository, opts Options) *Keys {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = client.DefaultWebhookTolerance
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultGrace
+	}
+	return &Keys{
+		repo:      repo,
+		tolerance: opts.Tolerance,
+		grace:     opts.Grace,
+		now:       time.Now,
+		order:     list.New(),
+		seen:      make(map[string]*list.Element),
+	}
+}
+
+// Active reports whether k is accepted at t.
+func Active(k *models.SigningKey, t time.Time) bool {
+	return k.ExpiresAt == nil || t.Before(*k.ExpiresAt)
+}
+
+// List returns an agent's keys, oldest first, without their secrets.
+func (k *Keys) List(ctx context.Context, agentID string) ([]models.SigningKey, error) {
+	keys, err := k.repo.List(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].Secret = ""
+	}
+	return keys, nil
+}
+
+// HasActive reports whether an agent has a key it must sign with.
+func (k *Keys) HasActive(ctx context.Context, agentID string) (bool, error) {
+	keys, err := k.active(ctx, agentID)
+	return len(keys) > 0, err
+}
+
+// Rotate issues a new key for an agent, with its secret set. Keys it
+// replaces stay valid for the grace period, or until their earlier expiry.
+func (k *Keys) Rotate(ctx context.Context, agentID string) (*models.SigningKey, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+	now := k.now().UTC()
+	key := &models.SigningKey{
+		ID:        uuid.NewString(),
+		AgentID:   agentID,
+		Secret:    hex.EncodeToString(b[:]),
+		CreatedAt: now,
+	}
+
+	old, err := k.active(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	expires := now.Add(k.grace)
+	for i := range old {
+		if old[i].ExpiresAt != nil && old[i].ExpiresAt.Before(expires) {
+			continue
+		}
+		old[i].ExpiresAt = &expires
+		if err := k.repo.Update(ctx, &old[i]); err != nil {
+			return nil, fmt.Errorf("expiring signing key %s: %w", old[i].ID, err)
+		}
+	}
+	if err := k.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Revoke deletes an agent's key immediately.
+func (k *Keys) Revoke(ctx context.Context, agentID, keyID string) error {
+	return k.repo.Delete(ctx, agentID, keyID)
+}
+
+// Verify checks a request an agent signed against its raw body and
+// returns the key that signed it. Errors other than repository failures
+// wrap ErrNoKey or one of pkg/client's verification errors. A request that
+// fails verification does not use up its nonce.
+func (k *Keys) Verify(ctx context.Context, agentID string, header http.Header, body []byte) (*models.SigningKey, error) {
+	sig, ts, nonce := header.Get(client.SignatureHeader), header.Get(client.TimestampHeader), header.Get(client.NonceHeader)
+	if sig == "" || ts == "" || nonce == "" {
+		return nil, client.ErrMissingSignature
+	}
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp %q", client.ErrMissingSignature, ts)
+	}
+	keys, err := k.active(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKey
+	}
+	var signer *models.SigningKey
+	for i := range keys {
+		want := client.SignWebhook([]byte(keys[i].Secret), timestamp, nonce, body)
+		for _, got := range strings.Split(sig, ",") {
+			if hmac.Equal([]byte(strings.TrimSpace(got)), []byte(want)) {
+				signer = &keys[i]
+			}
+		}
+	}
+	if signer == nil {
+		return nil, client.ErrInvalidSignature
+	}
+	sent := time.Unix(timestamp, 0)
+	now := k.now()
+	if d := now.Sub(sent); d > k.tolerance || d < -k.tolerance {
+		return nil, client.ErrStaleTimestamp
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for el := k.order.Front(); el != nil && now.After(el.Value.(*seenNonce).forget); el = k.order.Front() {
+		delete(k.seen, el.Value.(*seenNonce).key)
+		k.order.Remove(el)
+	}
+	seen := agentID + "\x00" + nonce
+	if _, ok := k.seen[seen]; ok {
+		return nil, client.ErrReplayedNonce
+	}
+	k.remember(seen, sent.Add(k.tolerance))
+	signer.Secret = ""
+	return signer, nil
+}
+
+// remember records a nonce until forget, keeping k.order sorted by when
+// nonces can be forgotten. Timestamps arrive nearly in order, so the new
+// nonce usually goes at the back. Callers must hold k.mu.
+func (k *Keys) remember(key string, forget time.Time) {
+	n := &seenNonce{key: key, forget: forget}
+	for el := k.order.Back(); el != nil; el = el.Prev() {
+		if !el.Value.(*seenNonce).forget.After(forget) {
+			k.seen[key] = k.order.InsertAfter(n, el)
+			return
+		}
+	}
+	k.seen[key] = k.order.PushFront(n)
+}
+
+// active returns an agent's keys accepted now.
+func (k *Keys) active(ctx context.Context, agentID string) ([]models.SigningKey, error) {
+	keys, err := k.repo.List(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("listing signing keys: %w", err)
+	}
+	now := k.now()
+	active := keys[:0]
+	for i := range keys {
+		if Active(&keys[i], now) {
+			active = append(active, keys[i])
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,85 @@
+package signing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/repository/memory"
+	"github.com/agentguard/agentguard/internal/signing"
+	"github.com/agentguard/agentguard/pkg/client"
+)
+
+func signed(t *testing.T, agentID, secret string, body []byte) *http.Request {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/sdk/pre-invoke", strings.NewReader(string(body)))
+	if err := client.SignRequest(r, agentID, secret, body); err != nil {
+		t.Fatalf("SignRequest() error = %v", err)
+	}
+	return r
+}
+
+func TestKeys(t *testing.T) {
+	ctx := context.Background()
+	keys := signing.New(memory.NewSigningKeyRepository(), signing.Options{Grace: time.Hour})
+	body := []byte(`{"agent":{"id":"agent-1"}}`)
+
+	if _, err := keys.Verify(ctx, "agent-1", signed(t, "agent-1", "nope", body).Header, body); !errors.Is(err, signing.ErrNoKey) {
+		t.Errorf("Verify() without keys error = %v, want ErrNoKey", err)
+	}
+
+	first, err := keys.Rotate(ctx, "agent-1")
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if len(first.Secret) != 64 {
+		t.Fatalf("Rotate() secret = %q, want 32 hex bytes", first.Secret)
+	}
+	r := signed(t, "agent-1", first.Secret, body)
+	key, err := keys.Verify(ctx, "agent-1", r.Header, body)
+	if err != nil || key.ID != first.ID || key.Secret != "" {
+		t.Fatalf("Verify() = %+v, %v", key, err)
+	}
+	if _, err := keys.Verify(ctx, "agent-1", r.Header, body); !errors.Is(err, client.ErrReplayedNonce) {
+		t.Errorf("replayed Verify() error = %v, want ErrReplayedNonce", err)
+	}
+	if _, err := keys.Verify(ctx, "agent-2", signed(t, "agent-2", first.Secret, body).Header, body); !errors.Is(err, signing.ErrNoKey) {
+		t.Errorf("Verify() as another agent error = %v, want ErrNoKey", err)
+	}
+	tampered := signed(t, "agent-1", first.Secret, body)
+	if _, err := keys.Verify(ctx, "agent-1", tampered.Header, []byte(`{"agent":{"id":"agent-9"}}`)); !errors.Is(err, client.ErrInvalidSignature) {
+		t.Errorf("Verify() of a changed body error = %v, want ErrInvalidSignature", err)
+	}
+
+	// After a rotation both keys are accepted until the old one expires.
+	second, err := keys.Rotate(ctx, "agent-1")
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	for _, secret := range []string{first.Secret, second.Secret} {
+		if _, err := keys.Verify(ctx, "agent-1", signed(t, "agent-1", secret, body).Header, body); err != nil {
+			t.Errorf("Verify() during grace error = %v", err)
+		}
+	}
+	list, err := keys.List(ctx, "agent-1")
+	if err != nil || len(list) != 2 || list[0].ExpiresAt == nil || list[1].ExpiresAt != nil || list[0].Secret != "" {
+		t.Fatalf("List() = %+v, %v", list, err)
+	}
+	if !signing.Active(&list[0], time.Now()) || signing.Active(&list[0], time.Now().Add(2*time.Hour)) {
+		t.Errorf("old key expires at %v, want in an hour", list[0].ExpiresAt)
+	}
+
+	if err := keys.Revoke(ctx, "agent-1", second.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := keys.Verify(ctx, "agent-1", signed(t, "agent-1", second.Secret, body).Header, body); !errors.Is(err, client.ErrInvalidSignature) {
+		t.Errorf("Verify() with a revoked key error = %v, want ErrInvalidSignature", err)
+	}
+	if ok, err := keys.HasActive(ctx, "agent-1"); !ok || err != nil {
+		t.Errorf("HasActive() = %v, %v; the first key is still in its grace period", ok, err)
+	}
+}
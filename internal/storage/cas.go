@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidHash is returned for malformed content hashes.
+var ErrInvalidHash = errors.New("invalid content hash")
+
+var contentHashPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// ContentStore keeps payloads in a Provider under keys derived from their
+// SHA-256 digest, so identical payloads are stored once per organization.
+// Objects expire ttl after their last upload and are removed by Sweep.
+type ContentStore struct {
+	provider Provider
+	prefix   string
+	ttl      time.Duration
+}
+
+// NewContentStore creates a content-addressable store rooted at prefix.
+func NewContentStore(provider Provider, prefix string, ttl time.Duration) *ContentStore {
+	return &ContentStore{provider: provider, prefix: strings.TrimSuffix(prefix, "/"), ttl: ttl}
+}
+
+// TTL returns how long stored content is retained.
+func (s *ContentStore) TTL() time.Duration { return s.ttl }
+
+// Hash returns the content hash of data in the form sha256:<hex>.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// key namespaces content by organization so one tenant cannot probe for
+// another's payloads by hash.
+func (s *ContentStore) key(orgID, hash string) (string, error) {
+	if orgID == "" || strings.Contains(orgID, "..") {
+		return "", fmt.Errorf("invalid organization %q", orgID)
+	}
+	return s.prefix + "/" + url.PathEscape(orgID) + "/" + strings.TrimPrefix(hash, "sha256:"), nil
+}
+
+// Put stores data and returns its hash. Uploading content that already
+// exists refreshes its expiry.
+func (s *ContentStore) Put(ctx context.Context, orgID string, data []byte, contentType string) (string, error) {
+	hash := Hash(data)
+	key, err := s.key(orgID, hash)
+	if err != nil {
+		return "", err
+	}
+	if err := s.provider.Upload(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return "", fmt.Errorf("storing payload %s: %w", hash, err)
+	}
+	return hash, nil
+}
+
+// Get opens the content with the given hash in an organization.
+func (s *ContentStore) Get(ctx context.Context, orgID, hash string) (io.ReadCloser, error) {
+	if !contentHashPattern.MatchString(hash) {
+		return nil, ErrInvalidHash
+	}
+	key, err := s.key(orgID, hash)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.provider.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return s.provider.Download(ctx, key)
+}
+
+// Delete removes the content with the given hash in an organization.
+// Deleting content that does not exist is not an error.
+func (s *ContentStore) Delete(ctx context.Context, orgID, hash string) error {
+	if !contentHashPattern.MatchString(hash) {
+		return ErrInvalidHash
+	}
+	key, err := s.key(orgID, hash)
+	if err != nil {
+		return err
+	}
+	if err := s.provider.Delete(ctx, key); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("deleting payload %s: %w", hash, err)
+	}
+	return nil
+}
+
+// Sweep deletes content older than the store's TTL and returns how many
+// objects were removed.
+func (s *ContentStore) Sweep(ctx context.Context) (int, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+	objects, err := s.provider.List(ctx, s.prefix+"/")
+	if err != nil {
+		return 0, fmt.Errorf("listing payloads: %w", err)
+	}
+	cutoff := time.Now().Add(-s.ttl)
+	deleted := 0
+	for _, obj := range objects {
+		modified, err := time.Parse(time.RFC3339, obj.LastModified)
+		if err != nil || modified.After(cutoff) {
+			continue
+		}
+		if err := s.provider.Delete(ctx, obj.Key); err != nil {
+			return deleted, fmt.Errorf("deleting payload %s: %w", obj.Key, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Run sweeps expired content every interval until ctx is cancelled. A
+// non-positive interval disables sweeping.
+func (s *ContentStore) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.Sweep(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("payload expiry sweep failed")
+			} else if n > 0 {
+				log.Info().Int("deleted", n).Msg("expired payloads removed")
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// LocalConfig holds configuration for filesystem storage
+type LocalConfig struct {
+	Root string // Directory holding objects; created if missing
+}
+
+// LocalProvider implements storage on the local filesystem. It is intended
+// for development and single-node deployments.
+type LocalProvider struct {
+	root string
+}
+
+// NewLocalProvider creates a new filesystem provider
+func NewLocalProvider(cfg LocalConfig) (*LocalProvider, error) {
+	if cfg.Root == "" {
+		return nil, fmt.Errorf("local storage root is required")
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("resolving storage root: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("creating storage root: %w", err)
+	}
+	return &LocalProvider{root: root}, nil
+}
+
+// path maps a key to a file under root, rejecting keys that escape it.
+func (p *LocalProvider) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(p.root, filepath.FromSlash(clean)), nil
+}
+
+func (p *LocalProvider) Upload(ctx context.Context, key string, content io.Reader, contentType string) error {
+	path, err := p.path(key)
+	if err != nil {
+		return err
+	}
+	if err := checkRetention(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating object directory: %w", err)
+	}
+	// Write to a temp file and rename so readers never see partial objects.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("creating object: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing object: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("storing object: %w", err)
+	}
+	return nil
+}
+
+func (p *LocalProvider) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (p *LocalProvider) Delete(ctx context.Context, key string) error {
+	path, err := p.path(key)
+	if err != nil {
+		return err
+	}
+	if err := checkRetention(path); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (p *LocalProvider) List(ctx context.Context, prefix string) ([]Object, error) {
+	var out []Object
+	err := filepath.WalkDir(p.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") || strings.HasPrefix(d.Name(), retentionPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(p.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, Object{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime().UTC().Format(time.RFC3339),
+		})
+		return nil
+	})
+	return out, err
+}
+
+func (p *LocalProvider) Exists(ctx context.Context, key string) (bool, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+// retentionPrefix names the sidecar file holding an object's retention lock.
+const retentionPrefix = ".retention-"
+
+func retentionPath(path string) string {
+	return filepath.Join(filepath.Dir(path), retentionPrefix+filepath.Base(path)+".json")
+}
+
+func readRetention(path string) (*Retention, error) {
+	data, err := os.ReadFile(retentionPath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Retention
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("reading retention lock: %w", err)
+	}
+	return &r, nil
+}
+
+func checkRetention(path string) error {
+	r, err := readRetention(path)
+	if err != nil {
+		return err
+	}
+	if r != nil && time.Now().Before(r.Until) {
+		return fmt.Errorf("%w until %s (%s)", ErrRetentionLocked, r.Until.Format(time.RFC3339), r.Mode)
+	}
+	return nil
+}
+
+// LockRetention implements RetentionLocker. The lock is kept in a sidecar
+// file and the object is made read-only; this guards against mistakes, not
+// against anyone with write access to the directory.
+func (p *LocalProvider) LockRetention(ctx context.Context, key string, r Retention) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	path, err := p.path(key)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	current, err := readRetention(path)
+	if err != nil {
+		return err
+	}
+	if current != nil && time.Now().Before(current.Until) {
+		if r.Until.Before(current.Until) {
+			return fmt.Errorf("%w: retention cannot be shortened", ErrRetentionLocked)
+		}
+		if current.Mode == RetentionCompliance {
+			r.Mode = RetentionCompliance
+		}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(retentionPath(path), data, 0o400); err != nil {
+		// An existing read-only sidecar is replaced rather than rewritten.
+		if err := os.Remove(retentionPath(path)); err != nil {
+			return fmt.Errorf("writing retention lock: %w", err)
+		}
+		if err := os.WriteFile(retentionPath(path), data, 0o400); err != nil {
+			return fmt.Errorf("writing retention lock: %w", err)
+		}
+	}
+	return os.Chmod(path, 0o400)
+}
+
+// Retention implements RetentionLocker. It returns nil for unlocked objects.
+func (p *LocalProvider) Retention(ctx context.Context, key string) (*Retention, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return readRetention(path)
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRetentionLocked is returned when overwriting or deleting an object
+// under an unexpired retention lock.
+var ErrRetentionLocked = errors.New("object is under retention lock")
+
+// RetentionMode selects how strictly a retention lock is enforced, following
+// S3 Object Lock semantics.
+type RetentionMode string
+
+const (
+	// RetentionGovernance locks can be lifted by privileged users of the
+	// backing store.
+	RetentionGovernance RetentionMode = "governance"
+	// RetentionCompliance locks cannot be shortened or removed by anyone.
+	RetentionCompliance RetentionMode = "compliance"
+)
+
+// Retention is a write-once lock on an object until a point in time.
+type Retention struct {
+	Mode  RetentionMode `json:"mode"`
+	Until time.Time     `json:"until"`
+}
+
+// Validate checks the mode and that the lock ends in the future.
+func (r Retention) Validate() error {
+	switch r.Mode {
+	case RetentionGovernance, RetentionCompliance:
+	default:
+		return fmt.Errorf("invalid retention mode %q", r.Mode)
+	}
+	if !r.Until.After(time.Now()) {
+		return fmt.Errorf("retention must end in the future")
+	}
+	return nil
+}
+
+// RetentionLocker is implemented by providers that can place write-once
+// retention locks on objects. Locks may be extended but never shortened.
+type RetentionLocker interface {
+	LockRetention(ctx context.Context, key string, r Retention) error
+	Retention(ctx context.Context, key string) (*Retention, error)
+}
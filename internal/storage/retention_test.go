@@ -0,0 +1,55 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/storage"
+)
+
+func TestLocalRetentionLock(t *testing.T) {
+	ctx := context.Background()
+	p, err := storage.NewLocalProvider(storage.LocalConfig{Root: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := storage.Retention{Mode: storage.RetentionCompliance, Until: time.Now().Add(time.Hour)}
+	if err := p.LockRetention(ctx, "evidence/a.tar.gz", lock); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("locking a missing object: err = %v", err)
+	}
+	if err := p.Upload(ctx, "evidence/a.tar.gz", strings.NewReader("bundle"), "application/gzip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.LockRetention(ctx, "evidence/a.tar.gz", lock); err != nil {
+		t.Fatalf("LockRetention: %v", err)
+	}
+
+	if err := p.Upload(ctx, "evidence/a.tar.gz", strings.NewReader("forged"), "application/gzip"); !errors.Is(err, storage.ErrRetentionLocked) {
+		t.Errorf("overwrite: err = %v, want ErrRetentionLocked", err)
+	}
+	if err := p.Delete(ctx, "evidence/a.tar.gz"); !errors.Is(err, storage.ErrRetentionLocked) {
+		t.Errorf("delete: err = %v, want ErrRetentionLocked", err)
+	}
+	shorter := storage.Retention{Mode: storage.RetentionGovernance, Until: time.Now().Add(time.Minute)}
+	if err := p.LockRetention(ctx, "evidence/a.tar.gz", shorter); !errors.Is(err, storage.ErrRetentionLocked) {
+		t.Errorf("shortening: err = %v, want ErrRetentionLocked", err)
+	}
+	longer := storage.Retention{Mode: storage.RetentionGovernance, Until: time.Now().Add(2 * time.Hour)}
+	if err := p.LockRetention(ctx, "evidence/a.tar.gz", longer); err != nil {
+		t.Errorf("extending: %v", err)
+	}
+	if r, _ := p.Retention(ctx, "evidence/a.tar.gz"); r == nil || r.Mode != storage.RetentionCompliance {
+		t.Errorf("retention = %+v, want compliance mode kept", r)
+	}
+
+	objects, err := p.List(ctx, "evidence/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 {
+		t.Errorf("List = %+v, want only the object", objects)
+	}
+}
@@ -30,6 +30,9 @@ type Config struct {
 	Environment    string
 	OTLPEndpoint   string
 	MetricsPort    int
+	// MetricReaders are added to the Prometheus exporter, e.g. periodic
+	// readers pushing to a hosted monitoring platform.
+	MetricReaders []sdkmetric.Reader
 }
 
 // Provider manages OpenTelemetry providers
@@ -52,18 +55,9 @@ type Provider struct {
 func NewProvider(cfg Config) (*Provider, error) {
 	ctx := context.Background()
 
-	// Create resource with service info
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(cfg.ServiceName),
-			semconv.ServiceVersion(cfg.ServiceVersion),
-			attribute.String("environment", cfg.Environment),
-		),
-	)
+	res, err := newResource(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		return nil, err
 	}
 
 	// Setup trace exporter — use TLS by default, plaintext only when OTEL_INSECURE=true
@@ -99,10 +93,11 @@ func NewProvider(cfg Config) (*Provider, error) {
 		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
 	}
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(promExporter),
-		sdkmetric.WithResource(res),
-	)
+	meterOpts := []sdkmetric.Option{sdkmetric.WithReader(promExporter), sdkmetric.WithResource(res)}
+	for _, r := range cfg.MetricReaders {
+		meterOpts = append(meterOpts, sdkmetric.WithReader(r))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(meterOpts...)
 	otel.SetMeterProvider(meterProvider)
 
 	p := &Provider{
@@ -121,6 +116,40 @@ func NewProvider(cfg Config) (*Provider, error) {
 	return p, nil
 }
 
+// NewMeterProvider installs a global meter provider feeding only
+// cfg.MetricReaders, for pushing metrics when tracing and the Prometheus
+// endpoint are not configured.
+func NewMeterProvider(cfg Config) (*sdkmetric.MeterProvider, error) {
+	res, err := newResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	for _, r := range cfg.MetricReaders {
+		opts = append(opts, sdkmetric.WithReader(r))
+	}
+	mp := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(mp)
+	return mp, nil
+}
+
+// newResource describes the service to telemetry backends.
+func newResource(cfg Config) (*resource.Resource, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			attribute.String("environment", cfg.Environment),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	return res, nil
+}
+
 func (p *Provider) initMetrics() error {
 	var err error
 
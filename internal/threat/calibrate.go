@@ -0,0 +1,240 @@
+package threat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/repository"
+)
+
+// signalThreats ties each signal type to the ATLAS techniques it is
+// evidence of, and the STRIDE category used for threats that name no
+// technique. Signal types not listed, such as policy violations, say
+// nothing about a specific threat and never raise likelihood.
+var signalThreats = map[models.SignalType]struct {
+	techniques []string
+	category   models.STRIDECategory
+}{
+	models.SignalInjectionAttempt: {
+		techniques: []string{"AML.T0051", "AML.T0054", "AML.T0061", "AML.T0070"},
+		category:   models.STRIDETampering,
+	},
+	models.SignalToolAbuse: {
+		techniques: []string{"AML.T0053", "AML.T0050"},
+		category:   models.STRIDEElevationOfPrivilege,
+	},
+	models.SignalPrivilegeEscalation: {
+		techniques: []string{"AML.T0012", "AML.T0053", "AML.T0054"},
+		category:   models.STRIDEElevationOfPrivilege,
+	},
+	models.SignalDataExfiltration: {
+		techniques: []string{"AML.T0024", "AML.T0025", "AML.T0056", "AML.T0057"},
+		category:   models.STRIDEInformationDisclosure,
+	},
+	models.SignalIdentityMismatch: {
+		techniques: []string{"AML.T0012"},
+		category:   models.STRIDESpoofing,
+	},
+	models.SignalInvalidOutput: {
+		techniques: []string{"AML.T0053"},
+		category:   models.STRIDETampering,
+	},
+	models.SignalHoneypotTriggered: {
+		techniques: []string{"AML.T0051", "AML.T0053"},
+		category:   models.STRIDEElevationOfPrivilege,
+	},
+	models.SignalRateLimitExceeded: {
+		techniques: []string{"AML.T0029", "AML.T0034", "AML.T0046"},
+		category:   models.STRIDEDenialOfService,
+	},
+}
+
+// severityWeights weigh signals by severity when scoring evidence.
+var severityWeights = map[string]float64{
+	"low":      0.5,
+	"medium":   1,
+	"high":     2,
+	"critical": 4,
+}
+
+// Observation is a count of signals of one type and severity.
+type Observation struct {
+	Type     models.SignalType
+	Severity string
+	Count    int64
+}
+
+// Calibration configures how observed signals raise likelihood.
+type Calibration struct {
+	// Window is how far back signals are counted. Defaults to 30 days.
+	Window time.Duration
+	// Raise is the severity-weighted signal score that raises a threat's
+	// likelihood one level above its design-time value. Defaults to 1.
+	Raise float64
+	// Surge is the score that raises it two levels. Defaults to 20.
+	Surge float64
+}
+
+func (c Calibration) withDefaults() Calibration {
+	if c.Window <= 0 {
+		c.Window = 30 * 24 * time.Hour
+	}
+	if c.Raise <= 0 {
+		c.Raise = 1
+	}
+	if c.Surge <= 0 {
+		c.Surge = 20
+	}
+	return c
+}
+
+// Adjustment is a threat whose likelihood calibration changed.
+type Adjustment struct {
+	ThreatID string  `json:"threat_id"`
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Score    float64 `json:"score"`
+}
+
+// Calibrate counts the model's target agent's signals over the calibration
+// window and applies them with Apply. Models without a target agent count
+// every signal in the organization.
+func Calibrate(ctx context.Context, metrics repository.MetricsRepository, orgID string, tm *models.ThreatModel, cal Calibration, now time.Time) ([]Adjustment, error) {
+	cal = cal.withDefaults()
+	q := &repository.MetricsQuery{
+		OrgID:    orgID,
+		Metric:   repository.MetricSignals,
+		From:     now.Add(-cal.Window),
+		To:       now,
+		Interval: "week",
+		GroupBy:  []string{"type", "severity"},
+	}
+	if tm.TargetAgentID != nil {
+		agent := tm.TargetAgentID.String()
+		q.AgentID = &agent
+	}
+	points, err := metrics.Rollup(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("counting signals: %w", err)
+	}
+
+	type key struct{ typ, severity string }
+	counts := make(map[key]int64)
+	for _, p := range points {
+		counts[key{p.Group["type"], p.Group["severity"]}] += int64(p.Value)
+	}
+	obs := make([]Observation, 0, len(counts))
+	for k, n := range counts {
+		obs = append(obs, Observation{Type: models.SignalType(k.typ), Severity: k.severity, Count: n})
+	}
+	return Apply(tm, obs, cal, now), nil
+}
+
+// Apply raises the likelihood of each threat the observations are evidence
+// of, records the evidence, and rescores the model. Likelihood is always
+// recomputed from the design-time value, so repeated calibration does not
+// compound and a threat returns to its design-time likelihood once its
+// signals age out of the window.
+func Apply(tm *models.ThreatModel, obs []Observation, cal Calibration, now time.Time) []Adjustment {
+	cal = cal.withDefaults()
+	var adjustments []Adjustment
+	for i := range tm.Threats {
+		t := &tm.Threats[i]
+		base := t.BaseLikelihood
+		if base == "" {
+			base = t.Likelihood
+		}
+
+		var score float64
+		var signals int64
+		types := make(map[models.SignalType]bool)
+		for _, o := range obs {
+			if o.Count <= 0 || !evidenceOf(*t, o.Type) {
+				continue
+			}
+			weight, ok := severityWeights[strings.ToLower(o.Severity)]
+			if !ok {
+				weight = 1
+			}
+			score += weight * float64(o.Count)
+			signals += o.Count
+			types[o.Type] = true
+		}
+
+		raise := 0
+		switch {
+		case score >= cal.Surge:
+			raise = 2
+		case score >= cal.Raise:
+			raise = 1
+		}
+		likelihood := raiseLikelihood(base, raise)
+
+		if likelihood != t.Likelihood {
+			adjustments = append(adjustments, Adjustment{ThreatID: t.ID, From: t.Likelihood, To: likelihood, Score: score})
+		}
+		t.Likelihood = likelihood
+		if raise == 0 {
+			t.BaseLikelihood = ""
+			t.LikelihoodEvidence = nil
+			continue
+		}
+		t.BaseLikelihood = base
+		ev := &models.LikelihoodEvidence{
+			Signals:      signals,
+			Score:        score,
+			From:         now.Add(-cal.Window),
+			To:           now,
+			CalibratedAt: now,
+		}
+		for typ := range types {
+			ev.SignalTypes = append(ev.SignalTypes, typ)
+		}
+		sort.Slice(ev.SignalTypes, func(a, b int) bool { return ev.SignalTypes[a] < ev.SignalTypes[b] })
+		t.LikelihoodEvidence = ev
+	}
+	Score(tm)
+	return adjustments
+}
+
+// evidenceOf reports whether signals of a type are evidence of a threat:
+// they share an ATLAS technique (or a sub-technique of one), or the threat
+// names no technique and has the signal's STRIDE category.
+func evidenceOf(t models.Threat, typ models.SignalType) bool {
+	m, ok := signalThreats[typ]
+	if !ok {
+		return false
+	}
+	if len(t.ATLASTechniques) == 0 {
+		return t.Category == m.category
+	}
+	for _, id := range t.ATLASTechniques {
+		for _, tech := range m.techniques {
+			if id == tech || strings.HasPrefix(id, tech+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// raiseLikelihood returns the likelihood n levels above base, capped at the
+// highest level. An unset or unknown base is treated as low.
+func raiseLikelihood(base string, n int) string {
+	if n == 0 {
+		return base
+	}
+	i := level(Likelihoods, base) - 1
+	if i < 0 {
+		i = 0
+	}
+	i += n
+	if i >= len(Likelihoods) {
+		i = len(Likelihoods) - 1
+	}
+	return Likelihoods[i]
+}
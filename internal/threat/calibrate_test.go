@@ -0,0 +1,102 @@
+package threat_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/threat"
+)
+
+func testModel() *models.ThreatModel {
+	return &models.ThreatModel{
+		Threats: []models.Threat{
+			{ID: "T1", Category: models.STRIDETampering, Likelihood: "medium", Impact: "high", ATLASTechniques: []string{"AML.T0051.001"}},
+			{ID: "T2", Category: models.STRIDEElevationOfPrivilege, Likelihood: "low", Impact: "critical", MitigationIDs: []string{"M1"}},
+			{ID: "T3", Category: models.STRIDEInformationDisclosure, Likelihood: "low", Impact: "medium", ATLASTechniques: []string{"AML.T0057"}},
+		},
+		Mitigations: []models.Mitigation{{ID: "M1", Status: "implemented"}},
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	tm := testModel()
+	obs := []threat.Observation{
+		{Type: models.SignalInjectionAttempt, Severity: "high", Count: 3},
+		// No ATLAS techniques on T2, so it matches on STRIDE category.
+		{Type: models.SignalToolAbuse, Severity: "critical", Count: 6},
+		// Policy violations are not evidence of a specific threat.
+		{Type: models.SignalPolicyViolation, Severity: "critical", Count: 100},
+	}
+
+	adj := threat.Apply(tm, obs, threat.Calibration{}, now)
+	if len(adj) != 2 {
+		t.Fatalf("adjustments = %+v, want 2", adj)
+	}
+
+	t1, t2, t3 := tm.Threats[0], tm.Threats[1], tm.Threats[2]
+	if t1.Likelihood != "high" || t1.BaseLikelihood != "medium" {
+		t.Errorf("T1 likelihood = %s (base %s), want high (base medium)", t1.Likelihood, t1.BaseLikelihood)
+	}
+	if t1.RiskLevel != "high" {
+		t.Errorf("T1 risk = %s, want high", t1.RiskLevel)
+	}
+	if ev := t1.LikelihoodEvidence; ev == nil || ev.Signals != 3 || ev.Score != 6 || len(ev.SignalTypes) != 1 {
+		t.Errorf("T1 evidence = %+v", ev)
+	}
+	// A score of 24 passes the surge threshold: two levels.
+	if t2.Likelihood != "high" || t2.RiskLevel != "critical" {
+		t.Errorf("T2 = %s/%s, want high/critical", t2.Likelihood, t2.RiskLevel)
+	}
+	if t3.Likelihood != "low" || t3.LikelihoodEvidence != nil || t3.BaseLikelihood != "" {
+		t.Errorf("T3 changed without signals: %+v", t3)
+	}
+	if tm.RiskSummary.TotalThreats != 3 || tm.RiskSummary.ThreatsByRisk["critical"] != 1 {
+		t.Errorf("summary = %+v", tm.RiskSummary)
+	}
+
+	// Recalibrating with the same signals does not compound, and once
+	// they age out the threats return to their design-time likelihood.
+	if adj := threat.Apply(tm, obs, threat.Calibration{}, now); len(adj) != 0 {
+		t.Errorf("recalibration adjusted %+v", adj)
+	}
+	threat.Apply(tm, nil, threat.Calibration{}, now.Add(60*24*time.Hour))
+	if tm.Threats[0].Likelihood != "medium" || tm.Threats[0].LikelihoodEvidence != nil {
+		t.Errorf("T1 after signals aged out = %+v", tm.Threats[0])
+	}
+	if tm.Threats[1].Likelihood != "low" {
+		t.Errorf("T2 after signals aged out = %s, want low", tm.Threats[1].Likelihood)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tm := testModel()
+	threat.Score(tm)
+	s := tm.RiskSummary
+	// T2 is mitigated; T1 (2×3) and T3 (1×2) remain out of 3×16.
+	if got := s.MitigationCoverage; got < 33.3 || got > 33.4 {
+		t.Errorf("mitigation coverage = %v, want 33.3", got)
+	}
+	if got := s.ResidualRiskScore; got < 16.6 || got > 16.7 {
+		t.Errorf("residual risk = %v, want 16.7", got)
+	}
+	if s.ThreatsByCategory["tampering"] != 1 {
+		t.Errorf("by category = %v", s.ThreatsByCategory)
+	}
+}
+
+func TestRiskLevel(t *testing.T) {
+	for _, tt := range []struct{ l, i, want string }{
+		{"very_high", "critical", "critical"},
+		{"high", "critical", "critical"},
+		{"high", "high", "high"},
+		{"medium", "medium", "medium"},
+		{"low", "medium", "low"},
+		{"", "critical", "low"},
+	} {
+		if got := threat.RiskLevel(tt.l, tt.i); got != tt.want {
+			t.Errorf("RiskLevel(%q, %q) = %s, want %s", tt.l, tt.i, got, tt.want)
+		}
+	}
+}
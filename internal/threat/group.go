@@ -0,0 +1,296 @@
+package threat
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/multiagent"
+)
+
+// Mitigations proposed by multi-agent threat models.
+var groupMitigations = map[string]models.Mitigation{
+	"M-restrict-delegation": {
+		ID:             "M-restrict-delegation",
+		Title:          "Limit delegated tools",
+		Description:    "List the tools each delegate may use on its caller's behalf, so a caller cannot reach capabilities it was not granted.",
+		ControlType:    "preventive",
+		Implementation: "Set tools on the delegation, or bind the caller's tool policies to the delegate.",
+		MappedControls: []string{"AC-6", "AC-4"},
+		Status:         "proposed",
+	},
+	"M-authenticate-delegation": {
+		ID:             "M-authenticate-delegation",
+		Title:          "Authenticate delegating agents",
+		Description:    "Have the delegate verify the caller's identity, e.g. with a signed workload token, before acting across a trust boundary.",
+		ControlType:    "preventive",
+		Implementation: "Pass the delegation chain to the pre-invoke hook with workload identity bound to each caller.",
+		MappedControls: []string{"IA-9", "IA-3"},
+		Status:         "proposed",
+	},
+	"M-validate-handoff": {
+		ID:             "M-validate-handoff",
+		Title:          "Treat delegated tasks as untrusted input",
+		Description:    "Validate delegated tasks against an expected schema and screen them for injected instructions before the delegate acts.",
+		ControlType:    "preventive",
+		Implementation: "Structured task handoff with prompt-injection screening at the boundary.",
+		MappedControls: []string{"SI-10", "AC-4"},
+		Status:         "proposed",
+	},
+	"M-bound-delegation": {
+		ID:             "M-bound-delegation",
+		Title:          "Bound delegation depth and loops",
+		Description:    "Cap delegation depth and detect agents delegating back to their callers.",
+		ControlType:    "preventive",
+		Implementation: "Maximum delegation depth and per-chain call budgets in the orchestrator.",
+		MappedControls: []string{"SC-5"},
+		Status:         "proposed",
+	},
+}
+
+// BoundaryID is the ID of a trust zone's boundary in group threat models.
+func BoundaryID(zone string) string { return "tb-" + zone }
+
+// AnalyzeGroup builds a threat model for a group of delegating agents: a
+// trust boundary per trust zone, and threats for delegations that grant a
+// caller capabilities it lacks, that carry instructions or unverified
+// identities across a boundary, and that loop. The group must be valid.
+func AnalyzeGroup(g *models.AgentGroup) *models.ThreatModel {
+	members := make(map[string]models.GroupMember, len(g.Members))
+	zones := make(map[string][]string)
+	for _, m := range g.Members {
+		members[m.AgentID] = m
+		zones[multiagent.Zone(m)] = append(zones[multiagent.Zone(m)], m.AgentID)
+	}
+
+	tm := &models.ThreatModel{
+		ID:          "tm-group-" + g.ID,
+		Name:        g.Name + " delegation threat model",
+		Description: fmt.Sprintf("Threats from delegation between the %d agents of %s", len(g.Members), g.Name),
+		Scope:       "multi-agent",
+		Threats:     []models.Threat{},
+		Mitigations: []models.Mitigation{},
+	}
+	names := make([]string, 0, len(zones))
+	for z := range zones {
+		names = append(names, z)
+	}
+	sort.Strings(names)
+	for _, z := range names {
+		tm.TrustBoundaries = append(tm.TrustBoundaries, models.TrustBoundary{
+			ID:          BoundaryID(z),
+			Name:        z,
+			Description: fmt.Sprintf("Agents in the %s trust zone", z),
+			Components:  zones[z],
+		})
+	}
+
+	used := make(map[string]bool)
+	add := func(t models.Threat) {
+		t.ID = fmt.Sprintf("T%d", len(tm.Threats)+1)
+		for _, id := range t.MitigationIDs {
+			used[id] = true
+		}
+		tm.Threats = append(tm.Threats, t)
+	}
+
+	for _, d := range g.Delegations {
+		from, to := members[d.From], members[d.To]
+		fromZone, toZone := multiagent.Zone(from), multiagent.Zone(to)
+		callerRoles := toolRoles(from.Tools, nil)
+		delegateRoles := toolRoles(to.Tools, d.Tools)
+		// A caller steered by content it reads can steer its delegates.
+		likelihood := "medium"
+		techniques := []string{}
+		if callerRoles.Untrusted {
+			likelihood = "high"
+			techniques = append(techniques, "AML.T0051.001")
+		}
+		entry := fmt.Sprintf("delegation %s -> %s", d.From, d.To)
+
+		gained := delegateRoles.Without(callerRoles)
+		gained.Untrusted = false
+		if gained.Sensitive || gained.Egress || gained.Action {
+			add(models.Threat{
+				Title:              fmt.Sprintf("%s gains %s capabilities through %s", d.From, roleNames(gained), d.To),
+				Description:        fmt.Sprintf("%s can have %s act for it with tools it does not hold itself (a confused deputy).", d.From, d.To),
+				Category:           models.STRIDEElevationOfPrivilege,
+				AffectedComponents: []string{d.From, d.To},
+				TrustBoundary:      BoundaryID(toZone),
+				EntryPoint:         entry,
+				Likelihood:         likelihood,
+				Impact:             roleImpact(gained),
+				ATLASTechniques:    append(slices.Clone(techniques), "AML.T0053"),
+				MitigationIDs:      []string{"M-restrict-delegation"},
+			})
+		}
+
+		if fromZone == toZone {
+			continue
+		}
+		add(models.Threat{
+			Title:              fmt.Sprintf("Delegated instructions cross from %s to %s", fromZone, toZone),
+			Description:        fmt.Sprintf("Tasks %s hands to %s cross a trust boundary and may carry injected instructions.", d.From, d.To),
+			Category:           models.STRIDETampering,
+			AffectedComponents: []string{d.From, d.To},
+			TrustBoundary:      BoundaryID(toZone),
+			EntryPoint:         entry,
+			Likelihood:         likelihood,
+			Impact:             roleImpact(delegateRoles),
+			ATLASTechniques:    append(slices.Clone(techniques), "AML.T0051"),
+			MitigationIDs:      []string{"M-validate-handoff"},
+		})
+		if !d.Authenticated {
+			add(models.Threat{
+				Title:              fmt.Sprintf("%s does not verify that %s is its caller", d.To, d.From),
+				Description:        fmt.Sprintf("Anything able to reach %s across the %s boundary can pose as %s.", d.To, toZone, d.From),
+				Category:           models.STRIDESpoofing,
+				AffectedComponents: []string{d.To},
+				TrustBoundary:      BoundaryID(toZone),
+				EntryPoint:         entry,
+				Likelihood:         "medium",
+				Impact:             roleImpact(delegateRoles),
+				ATLASTechniques:    []string{"AML.T0012"},
+				MitigationIDs:      []string{"M-authenticate-delegation"},
+			})
+		}
+	}
+
+	for _, cycle := range multiagent.Cycles(g) {
+		add(models.Threat{
+			Title:              "Delegation loop: " + strings.Join(append(slices.Clone(cycle), cycle[0]), " -> "),
+			Description:        "Agents that delegate back to their callers can loop, exhausting budgets and quotas.",
+			Category:           models.STRIDEDenialOfService,
+			AffectedComponents: cycle,
+			TrustBoundary:      BoundaryID(multiagent.Zone(members[cycle[0]])),
+			EntryPoint:         "delegation " + cycle[0],
+			Likelihood:         "medium",
+			Impact:             "medium",
+			ATLASTechniques:    []string{"AML.T0029", "AML.T0034"},
+			MitigationIDs:      []string{"M-bound-delegation"},
+		})
+	}
+
+	ids := make([]string, 0, len(used))
+	for id := range used {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		m := groupMitigations[id]
+		m.MappedControls = slices.Clone(m.MappedControls)
+		tm.Mitigations = append(tm.Mitigations, m)
+	}
+
+	Score(tm)
+	return tm
+}
+
+// toolRoles combines the roles of tools, limited to the named ones when
+// limit is non-empty.
+func toolRoles(tools []models.ToolBinding, limit []string) ToolRoles {
+	var roles ToolRoles
+	for _, t := range tools {
+		if len(limit) == 0 || slices.Contains(limit, multiagent.ToolName(t)) {
+			roles = roles.Union(ClassifyTool(t))
+		}
+	}
+	return roles
+}
+
+// roleImpact rates what a set of capabilities can do if misused: critical
+// when sensitive data can leave or be acted on, high for any egress or
+// action, medium for sensitive reads.
+func roleImpact(r ToolRoles) string {
+	switch {
+	case r.Sensitive && r.sink():
+		return "critical"
+	case r.sink():
+		return "high"
+	case r.Sensitive:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func roleNames(r ToolRoles) string {
+	var names []string
+	if r.Sensitive {
+		names = append(names, "sensitive data")
+	}
+	if r.Egress {
+		names = append(names, "egress")
+	}
+	if r.Action {
+		names = append(names, "action")
+	}
+	return strings.Join(names, ", ")
+}
+
+// GroupDiagram renders a group as a Mermaid flowchart: a subgraph per
+// trust zone and an arrow per delegation, labelled with its tool limit.
+// Delegations across trust boundaries are drawn thick and red, and marked
+// when the caller is not authenticated.
+func GroupDiagram(g *models.AgentGroup) string {
+	node := make(map[string]string, len(g.Members))
+	zones := make(map[string][]models.GroupMember)
+	for i, m := range g.Members {
+		node[m.AgentID] = fmt.Sprintf("a%d", i)
+		zones[multiagent.Zone(m)] = append(zones[multiagent.Zone(m)], m)
+	}
+	names := make([]string, 0, len(zones))
+	for z := range zones {
+		names = append(names, z)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, z := range names {
+		fmt.Fprintf(&b, "  subgraph z%d[\"%s\"]\n", i, mermaidText(z))
+		for _, m := range zones[z] {
+			label := m.Name
+			if label == "" {
+				label = m.AgentID
+			}
+			if m.Role != "" {
+				label += "<br/>" + m.Role
+			}
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", node[m.AgentID], mermaidText(label))
+		}
+		b.WriteString("  end\n")
+	}
+
+	zoneOf := make(map[string]string, len(g.Members))
+	for _, m := range g.Members {
+		zoneOf[m.AgentID] = multiagent.Zone(m)
+	}
+	var crossing []string
+	for i, d := range g.Delegations {
+		label := "all tools"
+		if len(d.Tools) > 0 {
+			label = strings.Join(d.Tools, ", ")
+		}
+		arrow := "-->"
+		if zoneOf[d.From] != zoneOf[d.To] {
+			arrow = "==>"
+			crossing = append(crossing, fmt.Sprint(i))
+			if !d.Authenticated {
+				label += " (unauthenticated)"
+			}
+		}
+		fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", node[d.From], arrow, mermaidText(label), node[d.To])
+	}
+	if len(crossing) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#d62728,stroke-width:3px\n", strings.Join(crossing, ","))
+	}
+	return b.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
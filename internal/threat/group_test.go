@@ -0,0 +1,76 @@
+package threat_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/threat"
+)
+
+func crew() *models.AgentGroup {
+	return &models.AgentGroup{
+		ID:   "g1",
+		Name: "research crew",
+		Members: []models.GroupMember{
+			{AgentID: "planner", TrustZone: "internal",
+				Tools: []models.ToolBinding{{Name: "web_browse", Category: "web_search"}}},
+			{AgentID: "analyst", TrustZone: "internal",
+				Tools: []models.ToolBinding{{Name: "customer_db", Category: "database"}, {Name: "send_email", Category: "email"}}},
+			{AgentID: "executor", TrustZone: "sandbox",
+				Tools: []models.ToolBinding{{Name: "python_repl", Category: "code_execution"}}},
+		},
+		Delegations: []models.Delegation{
+			{From: "planner", To: "analyst"},
+			{From: "analyst", To: "executor", Tools: []string{"python_repl"}},
+			{From: "executor", To: "analyst", Authenticated: true},
+		},
+	}
+}
+
+func TestAnalyzeGroup(t *testing.T) {
+	tm := threat.AnalyzeGroup(crew())
+
+	if len(tm.TrustBoundaries) != 2 || tm.TrustBoundaries[0].ID != "tb-internal" || tm.TrustBoundaries[1].ID != "tb-sandbox" {
+		t.Errorf("trust boundaries = %+v, want tb-internal and tb-sandbox", tm.TrustBoundaries)
+	}
+
+	var got []string
+	for _, th := range tm.Threats {
+		got = append(got, string(th.Category)+":"+strings.Join(th.AffectedComponents, ">")+":"+th.RiskLevel)
+	}
+	want := []string{
+		// The planner reads the web and can steer the analyst's data access.
+		"elevation_of_privilege:planner>analyst:critical",
+		"elevation_of_privilege:analyst>executor:high",
+		"tampering:analyst>executor:high",
+		"spoofing:executor:high",
+		"elevation_of_privilege:executor>analyst:high",
+		"tampering:executor>analyst:high",
+		"denial_of_service:analyst>executor:medium",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("threats:\n got %v\nwant %v", got, want)
+	}
+	if tm.Threats[0].Likelihood != "high" {
+		t.Errorf("likelihood = %s, want high for a caller with untrusted input", tm.Threats[0].Likelihood)
+	}
+	if len(tm.Mitigations) != 4 {
+		t.Errorf("mitigations = %d, want 4", len(tm.Mitigations))
+	}
+}
+
+func TestGroupDiagram(t *testing.T) {
+	d := threat.GroupDiagram(crew())
+	for _, want := range []string{
+		`subgraph z0["internal"]`,
+		`a0 -->|"all tools"| a1`,
+		`a1 ==>|"python_repl (unauthenticated)"| a2`,
+		`a2 ==>|"all tools"| a1`,
+		"linkStyle 1,2 ",
+	} {
+		if !strings.Contains(d, want) {
+			t.Errorf("diagram missing %q:\n%s", want, d)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package threat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// ToolRoles are the capabilities of a tool that matter to attack paths.
+type ToolRoles struct {
+	// Untrusted tools return content an attacker can influence, such as
+	// web pages or inbound email, and so can carry prompt injections.
+	Untrusted bool `json:"untrusted,omitempty"`
+	// Sensitive tools read data that must not leave the organization.
+	Sensitive bool `json:"sensitive,omitempty"`
+	// Egress tools send data outside the agent, e.g. email or webhooks.
+	Egress bool `json:"egress,omitempty"`
+	// Action tools have side effects: code execution, writes, payments.
+	Action bool `json:"action,omitempty"`
+}
+
+func (r ToolRoles) sink() bool { return r.Egress || r.Action }
+
+// Union returns the roles in either r or o.
+func (r ToolRoles) Union(o ToolRoles) ToolRoles {
+	return ToolRoles{
+		Untrusted: r.Untrusted || o.Untrusted,
+		Sensitive: r.Sensitive || o.Sensitive,
+		Egress:    r.Egress || o.Egress,
+		Action:    r.Action || o.Action,
+	}
+}
+
+// Without returns the roles in r that are not in o.
+func (r ToolRoles) Without(o ToolRoles) ToolRoles {
+	return ToolRoles{
+		Untrusted: r.Untrusted && !o.Untrusted,
+		Sensitive: r.Sensitive && !o.Sensitive,
+		Egress:    r.Egress && !o.Egress,
+		Action:    r.Action && !o.Action,
+	}
+}
+
+// Names lists the roles set in r.
+func (r ToolRoles) Names() []string {
+	names := []string{}
+	for _, role := range []struct {
+		set  bool
+		name string
+	}{{r.Untrusted, "untrusted"}, {r.Sensitive, "sensitive"}, {r.Egress, "egress"}, {r.Action, "action"}} {
+		if role.set {
+			names = append(names, role.name)
+		}
+	}
+	return names
+}
+
+// RolesOf combines the roles of tools.
+func RolesOf(tools []models.ToolBinding) ToolRoles {
+	var roles ToolRoles
+	for _, t := range tools {
+		roles = roles.Union(ClassifyTool(t))
+	}
+	return roles
+}
+
+// roleKeywords classify a tool by words in its name, category and
+// permissions.
+var roleKeywords = []struct {
+	words []string
+	roles ToolRoles
+}{
+	{[]string{"web", "browse", "browser", "search", "fetch", "scrape", "crawl", "url", "rss", "inbox", "read_email", "upload", "external_api"}, ToolRoles{Untrusted: true}},
+	{[]string{"database", "db", "sql", "query", "file", "filesystem", "file_access", "crm", "secret", "vault", "customer", "hr", "records", "knowledge_base"}, ToolRoles{Sensitive: true}},
+	{[]string{"send", "slack", "message", "webhook", "post", "publish", "share", "notify", "sms", "http_post"}, ToolRoles{Egress: true}},
+	{[]string{"exec", "execute", "shell", "code", "python", "bash", "payment", "transfer", "financial_transaction", "delete", "write", "deploy", "purchase"}, ToolRoles{Action: true}},
+}
+
+// ClassifyTool infers a tool's roles from its name, category and
+// permissions. A tool may have several roles: an email tool both reads
+// untrusted mail and sends it.
+func ClassifyTool(t models.ToolBinding) ToolRoles {
+	var words []string
+	for _, s := range append([]string{t.Name, t.ToolID, t.Category}, t.Permissions...) {
+		s = strings.ToLower(s)
+		words = append(words, s)
+		words = append(words, strings.FieldsFunc(s, func(r rune) bool {
+			return r == '_' || r == '-' || r == '.' || r == ' ' || r == ':' || r == '/'
+		})...)
+	}
+	var roles ToolRoles
+	for _, k := range roleKeywords {
+		for _, kw := range k.words {
+			if containsWord(words, kw) {
+				roles.Untrusted = roles.Untrusted || k.roles.Untrusted
+				roles.Sensitive = roles.Sensitive || k.roles.Sensitive
+				roles.Egress = roles.Egress || k.roles.Egress
+				roles.Action = roles.Action || k.roles.Action
+				break
+			}
+		}
+	}
+	// Mail tools read inbound mail unless they can only send.
+	if containsWord(words, "email") || containsWord(words, "mail") {
+		if !containsWord(words, "send") || containsWord(words, "read") {
+			roles.Untrusted = true
+		}
+	}
+	return roles
+}
+
+func containsWord(words []string, w string) bool {
+	for _, x := range words {
+		if x == w {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolNode is a tool in an agent's capability graph.
+type ToolNode struct {
+	Name  string    `json:"name"`
+	Roles ToolRoles `json:"roles"`
+}
+
+// Transition is an observed call of one tool followed by another in the
+// same trace.
+type Transition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PathOptions configures attack path analysis.
+type PathOptions struct {
+	// Overrides replaces the inferred roles of tools by name.
+	Overrides map[string]ToolRoles
+	// Transitions restricts the graph to observed tool sequences. When
+	// empty, any tool's output can reach any other tool through the model's
+	// context, and only sensitive tools are considered as intermediate
+	// steps.
+	Transitions []Transition
+	// MaxSteps bounds the tools in a path. Defaults to 4.
+	MaxSteps int
+}
+
+// AttackPath is a chain of tool calls from an untrusted input source to a
+// sink.
+type AttackPath struct {
+	Tools []string `json:"tools"`
+	// Kind is exfiltration when sensitive data can reach an egress tool,
+	// otherwise injected_action.
+	Kind      string   `json:"kind"`
+	Risk      string   `json:"risk"`
+	Rationale string   `json:"rationale"`
+	Steps     []string `json:"steps"`
+}
+
+// Chokepoint is a tool whose guarding breaks a set of attack paths.
+type Chokepoint struct {
+	Tool       string `json:"tool"`
+	Role       string `json:"role"`
+	Paths      int    `json:"paths"`
+	Suggestion string `json:"suggestion"`
+}
+
+// PathAnalysis is the attack path analysis of one agent.
+type PathAnalysis struct {
+	AgentID     string       `json:"agent_id,omitempty"`
+	Tools       []ToolNode   `json:"tools"`
+	Paths       []AttackPath `json:"paths"`
+	Chokepoints []Chokepoint `json:"chokepoints"`
+	// Sandboxes recommends a sandbox profile for each code execution tool.
+	Sandboxes []SandboxRecommendation `json:"sandboxes"`
+}
+
+// AnalyzePaths builds a tool capability graph for an agent's tools and
+// enumerates the paths from untrusted input sources to egress or action
+// tools, riskiest first, with the tools to guard to break them all.
+func AnalyzePaths(agentID string, tools []models.ToolBinding, opts PathOptions) *PathAnalysis {
+	if opts.MaxSteps <= 0 {
+		opts.MaxSteps = 4
+	}
+	out := &PathAnalysis{AgentID: agentID, Tools: []ToolNode{}, Paths: []AttackPath{}, Chokepoints: []Chokepoint{}, Sandboxes: []SandboxRecommendation{}}
+	roles := make(map[string]ToolRoles)
+	var names []string
+	for _, t := range tools {
+		name := t.Name
+		if name == "" {
+			name = t.ToolID
+		}
+		if _, dup := roles[name]; dup || name == "" {
+			continue
+		}
+		r, ok := opts.Overrides[name]
+		if !ok {
+			r = ClassifyTool(t)
+		}
+		roles[name] = r
+		names = append(names, name)
+		out.Tools = append(out.Tools, ToolNode{Name: name, Roles: r})
+	}
+	sort.Strings(names)
+
+	observed := len(opts.Transitions) > 0
+	next := make(map[string][]string)
+	if observed {
+		seen := make(map[Transition]bool)
+		for _, tr := range opts.Transitions {
+			if _, ok := roles[tr.From]; !ok || seen[tr] || tr.From == tr.To {
+				continue
+			}
+			if _, ok := roles[tr.To]; !ok {
+				continue
+			}
+			seen[tr] = true
+			next[tr.From] = append(next[tr.From], tr.To)
+		}
+		for k := range next {
+			sort.Strings(next[k])
+		}
+	} else {
+		for _, from := range names {
+			for _, to := range names {
+				if from != to {
+					next[from] = append(next[from], to)
+				}
+			}
+		}
+	}
+
+	var walk func(path []string, sensitive bool)
+	walk = func(path []string, sensitive bool) {
+		last := path[len(path)-1]
+		if len(path) > 1 && roles[last].sink() {
+			out.Paths = append(out.Paths, newAttackPath(path, roles))
+			if !observed {
+				return
+			}
+		}
+		if len(path) >= opts.MaxSteps {
+			return
+		}
+		for _, n := range next[last] {
+			if contains(path, n) {
+				continue
+			}
+			r := roles[n]
+			// Without observed sequences every tool follows every other,
+			// so only steps that add sensitive data or end the path are
+			// worth reporting.
+			if !observed && !r.sink() && !(r.Sensitive && !sensitive) {
+				continue
+			}
+			walk(append(append([]string(nil), path...), n), sensitive || r.Sensitive)
+		}
+	}
+	for _, name := range names {
+		if roles[name].Untrusted {
+			walk([]string{name}, roles[name].Sensitive)
+		}
+	}
+
+	sort.SliceStable(out.Paths, func(i, j int) bool {
+		ri, rj := riskRank(out.Paths[i].Risk), riskRank(out.Paths[j].Risk)
+		if ri != rj {
+			return ri > rj
+		}
+		return len(out.Paths[i].Tools) < len(out.Paths[j].Tools)
+	})
+	out.Chokepoints = chokepoints(out.Paths, roles)
+	out.Sandboxes = RecommendSandboxes(tools)
+	return out
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func riskRank(r string) int {
+	return level([]string{"low", "medium", "high", "critical"}, r)
+}
+
+func newAttackPath(path []string, roles map[string]ToolRoles) AttackPath {
+	src, sink := path[0], path[len(path)-1]
+	sensitive := ""
+	for _, t := range path[:len(path)-1] {
+		if roles[t].Sensitive {
+			sensitive = t
+			break
+		}
+	}
+	p := AttackPath{Tools: path, Kind: "injected_action"}
+	steps := []string{fmt.Sprintf("%s returns attacker-controlled content into the agent's context", src)}
+	for _, t := range path[1 : len(path)-1] {
+		if t == sensitive {
+			steps = append(steps, fmt.Sprintf("injected instructions direct %s to read sensitive data", t))
+		} else {
+			steps = append(steps, fmt.Sprintf("%s passes the content on", t))
+		}
+	}
+	switch {
+	case sensitive != "" && roles[sink].Egress:
+		p.Kind = "exfiltration"
+		p.Risk = "critical"
+		p.Rationale = fmt.Sprintf("content from %s can direct data read by %s out through %s", src, sensitive, sink)
+		steps = append(steps, fmt.Sprintf("%s sends the data outside the organization", sink))
+	case roles[sink].Action:
+		p.Risk = "high"
+		p.Rationale = fmt.Sprintf("content from %s can trigger side effects through %s", src, sink)
+		steps = append(steps, fmt.Sprintf("%s performs an action chosen by the attacker", sink))
+	default:
+		p.Risk = "medium"
+		p.Rationale = fmt.Sprintf("content from %s can direct what %s sends", src, sink)
+		steps = append(steps, fmt.Sprintf("%s sends attacker-chosen content", sink))
+	}
+	p.Steps = steps
+	return p
+}
+
+// chokepoints greedily picks the tools whose guarding breaks the most
+// remaining paths until every path is covered. Candidates are sinks and
+// sensitive tools, where a pre-invoke policy can require approval without
+// cutting the agent off from its inputs; sinks win ties.
+func chokepoints(paths []AttackPath, roles map[string]ToolRoles) []Chokepoint {
+	out := []Chokepoint{}
+	covered := make([]bool, len(paths))
+	for {
+		counts := make(map[string]int)
+		for i, p := range paths {
+			if covered[i] {
+				continue
+			}
+			for _, t := range p.Tools[1:] {
+				if roles[t].sink() || roles[t].Sensitive {
+					counts[t]++
+				}
+			}
+		}
+		if len(counts) == 0 {
+			return out
+		}
+		best, bestScore := "", -1
+		for t, n := range counts {
+			score := n * 2
+			if roles[t].sink() {
+				score++
+			}
+			if score > bestScore || (score == bestScore && t < best) {
+				best, bestScore = t, score
+			}
+		}
+		for i, p := range paths {
+			if !covered[i] && contains(p.Tools[1:], best) {
+				covered[i] = true
+			}
+		}
+		out = append(out, newChokepoint(best, roles[best], counts[best]))
+	}
+}
+
+func newChokepoint(tool string, r ToolRoles, paths int) Chokepoint {
+	c := Chokepoint{Tool: tool, Paths: paths}
+	switch {
+	case r.Egress:
+		c.Role = "sink"
+		c.Suggestion = fmt.Sprintf("require approval for %s in sessions that have read untrusted content, or restrict its destinations to an allowlist", tool)
+	case r.Action:
+		c.Role = "sink"
+		c.Suggestion = fmt.Sprintf("require approval for %s in sessions that have read untrusted content", tool)
+	default:
+		c.Role = "sensitive_data"
+		c.Suggestion = fmt.Sprintf("deny %s in sessions that have read untrusted content, or scope it to the data the task needs", tool)
+	}
+	return c
+}
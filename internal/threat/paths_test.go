@@ -0,0 +1,100 @@
+package threat_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/threat"
+)
+
+func TestClassifyTool(t *testing.T) {
+	for _, tt := range []struct {
+		tool models.ToolBinding
+		want threat.ToolRoles
+	}{
+		{models.ToolBinding{Name: "web_browse", Category: "web_search"}, threat.ToolRoles{Untrusted: true}},
+		{models.ToolBinding{Name: "customer_db", Category: "database", Permissions: []string{"read"}}, threat.ToolRoles{Sensitive: true}},
+		{models.ToolBinding{Name: "send_email", Category: "email"}, threat.ToolRoles{Egress: true}},
+		{models.ToolBinding{Name: "read_email", Category: "email"}, threat.ToolRoles{Untrusted: true}},
+		{models.ToolBinding{Name: "python_repl", Category: "code_execution"}, threat.ToolRoles{Action: true}},
+		{models.ToolBinding{Name: "summarize", Category: "llm"}, threat.ToolRoles{}},
+	} {
+		if got := threat.ClassifyTool(tt.tool); got != tt.want {
+			t.Errorf("ClassifyTool(%s) = %+v, want %+v", tt.tool.Name, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzePaths(t *testing.T) {
+	tools := []models.ToolBinding{
+		{Name: "web_browse", Category: "web_search"},
+		{Name: "summarize", Category: "llm"},
+		{Name: "customer_db", Category: "database"},
+		{Name: "send_email", Category: "email"},
+	}
+
+	a := threat.AnalyzePaths("agent-1", tools, threat.PathOptions{})
+	var got []string
+	for _, p := range a.Paths {
+		got = append(got, strings.Join(p.Tools, ">")+":"+p.Risk)
+	}
+	want := []string{
+		"web_browse>customer_db>send_email:critical",
+		"web_browse>send_email:medium",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("paths = %v, want %v", got, want)
+	}
+	if len(a.Chokepoints) != 1 || a.Chokepoints[0].Tool != "send_email" || a.Chokepoints[0].Paths != 2 {
+		t.Errorf("chokepoints = %+v, want send_email covering both paths", a.Chokepoints)
+	}
+
+	// Observed sequences follow the actual chain, including steps that
+	// only transform content.
+	a = threat.AnalyzePaths("agent-1", tools, threat.PathOptions{Transitions: []threat.Transition{
+		{From: "web_browse", To: "summarize"},
+		{From: "summarize", To: "send_email"},
+	}})
+	if len(a.Paths) != 1 || strings.Join(a.Paths[0].Tools, ">") != "web_browse>summarize>send_email" {
+		t.Fatalf("observed paths = %+v", a.Paths)
+	}
+	if a.Paths[0].Kind != "injected_action" || len(a.Paths[0].Steps) != 3 {
+		t.Errorf("observed path = %+v", a.Paths[0])
+	}
+
+	// Overrides mark a tool as trusted.
+	a = threat.AnalyzePaths("agent-1", tools, threat.PathOptions{Overrides: map[string]threat.ToolRoles{"web_browse": {}}})
+	if len(a.Paths) != 0 {
+		t.Errorf("paths with trusted browsing = %+v", a.Paths)
+	}
+}
+
+func TestRecommendSandboxes(t *testing.T) {
+	// An agent that reads untrusted content keeps exec tools offline.
+	recs := threat.RecommendSandboxes([]models.ToolBinding{
+		{Name: "web_browse", Category: "web_search"},
+		{Name: "python_repl", Category: "code_execution", Permissions: []string{"network"}},
+	})
+	if len(recs) != 1 || recs[0].Tool != "python_repl" || recs[0].Profile.Name != threat.SandboxIsolated {
+		t.Fatalf("recommendations = %+v, want python_repl isolated", recs)
+	}
+	if recs[0].Profile.Network.Mode != "none" {
+		t.Errorf("network mode = %q, want none", recs[0].Profile.Network.Mode)
+	}
+
+	recs = threat.RecommendSandboxes([]models.ToolBinding{
+		{Name: "run_shell", Permissions: []string{"file:write", "net:pypi.org"}},
+		{Name: "summarize", Category: "llm"},
+	})
+	if len(recs) != 1 || recs[0].Profile.Name != threat.SandboxWorkspaceEgress {
+		t.Fatalf("recommendations = %+v, want run_shell workspace-egress", recs)
+	}
+	if got := recs[0].Profile.Network.Allow; len(got) != 1 || got[0] != "pypi.org" {
+		t.Errorf("egress allowlist = %v, want [pypi.org]", got)
+	}
+
+	if recs := threat.RecommendSandboxes([]models.ToolBinding{{Name: "send_email", Category: "email"}}); len(recs) != 0 {
+		t.Errorf("recommendations for a tool that runs no code = %+v", recs)
+	}
+}
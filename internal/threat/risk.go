@@ -0,0 +1,95 @@
+// Package threat scores threat models and keeps their likelihoods in step
+// with the security signals observed at runtime.
+package threat
+
+import "github.com/agentguard/agentguard/internal/models"
+
+// Likelihoods are the threat likelihood levels, lowest first.
+var Likelihoods = []string{"low", "medium", "high", "very_high"}
+
+// Impacts are the threat impact levels, lowest first.
+var Impacts = []string{"low", "medium", "high", "critical"}
+
+func level(levels []string, v string) int {
+	for i, l := range levels {
+		if l == v {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// riskScore is likelihood × impact on 1–4 scales, or 0 when either is
+// unset or unknown.
+func riskScore(t models.Threat) int {
+	return level(Likelihoods, t.Likelihood) * level(Impacts, t.Impact)
+}
+
+// RiskLevel returns the risk level for a likelihood and impact: critical
+// from a score of 12 (e.g. high × critical), high from 6, medium from 3,
+// otherwise low. Unknown values rate as low.
+func RiskLevel(likelihood, impact string) string {
+	switch score := level(Likelihoods, likelihood) * level(Impacts, impact); {
+	case score >= 12:
+		return "critical"
+	case score >= 6:
+		return "high"
+	case score >= 3:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// Score sets each threat's risk level from its likelihood and impact and
+// recomputes the model's risk summary.
+func Score(tm *models.ThreatModel) {
+	for i := range tm.Threats {
+		t := &tm.Threats[i]
+		t.RiskLevel = RiskLevel(t.Likelihood, t.Impact)
+	}
+	tm.RiskSummary = Summarize(tm)
+}
+
+// Summarize computes a model's risk summary. A threat is mitigated when any
+// of its mitigations is implemented or verified. MitigationCoverage is the
+// share of mitigated threats; ResidualRiskScore is the risk of unmitigated
+// threats as a percentage of the maximum risk of all threats.
+func Summarize(tm *models.ThreatModel) models.RiskSummary {
+	mitigated := make(map[string]bool, len(tm.Mitigations))
+	for _, m := range tm.Mitigations {
+		if m.Status == "implemented" || m.Status == "verified" {
+			mitigated[m.ID] = true
+		}
+	}
+
+	summary := models.RiskSummary{
+		TotalThreats:      len(tm.Threats),
+		ThreatsByCategory: make(map[string]int),
+		ThreatsByRisk:     make(map[string]int),
+	}
+	if len(tm.Threats) == 0 {
+		return summary
+	}
+	covered, residual := 0, 0
+	for _, t := range tm.Threats {
+		summary.ThreatsByCategory[string(t.Category)]++
+		summary.ThreatsByRisk[RiskLevel(t.Likelihood, t.Impact)]++
+		isMitigated := false
+		for _, id := range t.MitigationIDs {
+			if mitigated[id] {
+				isMitigated = true
+				break
+			}
+		}
+		if isMitigated {
+			covered++
+		} else {
+			residual += riskScore(t)
+		}
+	}
+	maxScore := len(Likelihoods) * len(Impacts) * len(tm.Threats)
+	summary.MitigationCoverage = float64(covered) / float64(len(tm.Threats)) * 100
+	summary.ResidualRiskScore = float64(residual) / float64(maxScore) * 100
+	return summary
+}
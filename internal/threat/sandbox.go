@@ -0,0 +1,192 @@
+package threat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+// SandboxProfile constrains what code run by an execution tool can reach.
+type SandboxProfile struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Filesystem  SandboxFilesystem `json:"filesystem"`
+	Network     SandboxNetwork    `json:"network"`
+	Syscalls    SandboxSyscalls   `json:"syscalls"`
+	Limits      SandboxLimits     `json:"limits"`
+}
+
+// SandboxFilesystem restricts file access.
+type SandboxFilesystem struct {
+	ReadOnlyRoot bool `json:"read_only_root"`
+	// Writable lists the only writable paths, each a size-limited tmpfs
+	// or scratch volume discarded after the run.
+	Writable []string `json:"writable"`
+	// Hidden lists paths that must not be mounted, such as credentials.
+	Hidden []string `json:"hidden"`
+}
+
+// SandboxNetwork restricts network access.
+type SandboxNetwork struct {
+	// Mode is none or allowlist.
+	Mode string `json:"mode"`
+	// Allow lists the hosts reachable in allowlist mode.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// SandboxSyscalls restricts the system calls and privileges of sandboxed
+// processes.
+type SandboxSyscalls struct {
+	// Seccomp is default-deny: only an allowlist of syscalls is permitted.
+	Seccomp         string   `json:"seccomp"`
+	Deny            []string `json:"deny"`
+	NoNewPrivileges bool     `json:"no_new_privileges"`
+	NonRoot         bool     `json:"non_root"`
+	DropCaps        []string `json:"drop_capabilities"`
+}
+
+// SandboxLimits bounds the resources of one run.
+type SandboxLimits struct {
+	TimeoutSec   int `json:"timeout_sec"`
+	MemoryMB     int `json:"memory_mb"`
+	MaxProcesses int `json:"max_processes"`
+}
+
+// Sandbox profile names.
+const (
+	SandboxIsolated        = "isolated"
+	SandboxWorkspace       = "workspace"
+	SandboxEgressAllowlist = "egress-allowlist"
+	SandboxWorkspaceEgress = "workspace-egress"
+)
+
+// SandboxProfileNames lists the sandbox profiles an execution tool may
+// declare.
+func SandboxProfileNames() []string {
+	return []string{SandboxIsolated, SandboxWorkspace, SandboxEgressAllowlist, SandboxWorkspaceEgress}
+}
+
+// SandboxProfiles returns the built-in sandbox profiles.
+func SandboxProfiles() []SandboxProfile {
+	var out []SandboxProfile
+	for _, name := range SandboxProfileNames() {
+		out = append(out, sandboxProfile(name, nil))
+	}
+	return out
+}
+
+// sandboxProfile builds a built-in profile; allow fills an egress
+// allowlist.
+func sandboxProfile(name string, allow []string) SandboxProfile {
+	p := SandboxProfile{
+		Name:        name,
+		Description: "No network; read-only root with a scratch /tmp",
+		Filesystem: SandboxFilesystem{
+			ReadOnlyRoot: true,
+			Writable:     []string{"/tmp"},
+			Hidden:       []string{"/var/run/secrets", "/var/run/docker.sock", "~/.aws", "~/.ssh", "~/.config/gcloud", "~/.kube"},
+		},
+		Network: SandboxNetwork{Mode: "none"},
+		Syscalls: SandboxSyscalls{
+			Seccomp:         "default-deny",
+			Deny:            []string{"ptrace", "mount", "umount2", "unshare", "setns", "bpf", "keyctl", "perf_event_open", "init_module", "kexec_load"},
+			NoNewPrivileges: true,
+			NonRoot:         true,
+			DropCaps:        []string{"ALL"},
+		},
+		Limits: SandboxLimits{TimeoutSec: 60, MemoryMB: 512, MaxProcesses: 64},
+	}
+	if name == SandboxWorkspace || name == SandboxWorkspaceEgress {
+		p.Filesystem.Writable = append(p.Filesystem.Writable, "/workspace")
+		p.Description = "No network; read-only root with a per-run /workspace volume"
+	}
+	if name == SandboxEgressAllowlist || name == SandboxWorkspaceEgress {
+		p.Network = SandboxNetwork{Mode: "allowlist", Allow: allow}
+		if p.Network.Allow == nil {
+			p.Network.Allow = []string{}
+		}
+		p.Description = strings.Replace(p.Description, "No network", "Egress to allowlisted hosts only", 1)
+	}
+	return p
+}
+
+// SandboxRecommendation is the sandbox profile recommended for one code
+// execution tool.
+type SandboxRecommendation struct {
+	Tool      string         `json:"tool"`
+	Profile   SandboxProfile `json:"profile"`
+	Rationale []string       `json:"rationale"`
+}
+
+// ExecutesCode reports whether a tool runs code, by its category, name or
+// ID. It classifies tools as the pre-invoke sandbox check does.
+func ExecutesCode(t models.ToolBinding) bool {
+	for _, name := range []string{t.Name, t.ToolID} {
+		if (&opa.ToolContext{Name: name, Category: t.Category}).ExecutesCode() {
+			return true
+		}
+	}
+	return false
+}
+
+// RecommendSandboxes recommends a sandbox profile for each of an agent's
+// code execution tools. Code is assumed attacker-influenced when the agent
+// also reads untrusted content, so such tools are kept off the network even
+// when they ask for it.
+func RecommendSandboxes(tools []models.ToolBinding) []SandboxRecommendation {
+	roles := RolesOf(tools)
+	out := []SandboxRecommendation{}
+	for _, t := range tools {
+		if !ExecutesCode(t) {
+			continue
+		}
+		name := t.Name
+		if name == "" {
+			name = t.ToolID
+		}
+		files, hosts, network := execNeeds(t)
+		rationale := []string{fmt.Sprintf("%s runs code the model writes; contain it with a default-deny seccomp profile, no privileges and resource limits", name)}
+
+		profile := SandboxIsolated
+		if files {
+			profile = SandboxWorkspace
+			rationale = append(rationale, "it writes files, so give it a per-run /workspace volume rather than host paths")
+		}
+		switch {
+		case network && roles.Untrusted:
+			rationale = append(rationale, "it asks for network access, but the agent reads untrusted content, so injected code could exfiltrate or fetch payloads; keep it offline and route requests through a policy-checked egress tool")
+		case network:
+			if files {
+				profile = SandboxWorkspaceEgress
+			} else {
+				profile = SandboxEgressAllowlist
+			}
+			rationale = append(rationale, "it needs network access; allow only the hosts it must reach")
+		}
+		if roles.Sensitive {
+			rationale = append(rationale, "the agent reads sensitive data; pass only the data a run needs into the sandbox instead of mounting data stores or credentials")
+		}
+		out = append(out, SandboxRecommendation{Tool: name, Profile: sandboxProfile(profile, hosts), Rationale: rationale})
+	}
+	return out
+}
+
+// execNeeds infers from a tool's permissions whether it writes files and
+// reaches the network, and which hosts it names as net:<host>.
+func execNeeds(t models.ToolBinding) (files bool, hosts []string, network bool) {
+	for _, p := range t.Permissions {
+		p = strings.ToLower(p)
+		switch {
+		case strings.HasPrefix(p, "net:"):
+			network = true
+			hosts = append(hosts, strings.TrimPrefix(p, "net:"))
+		case p == "network" || p == "internet" || p == "http" || p == "net":
+			network = true
+		case strings.Contains(p, "write") || strings.Contains(p, "file"):
+			files = true
+		}
+	}
+	return files, hosts, network
+}
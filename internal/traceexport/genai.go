@@ -0,0 +1,113 @@
+package traceexport
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// OpenTelemetry GenAI semantic convention operation names.
+const (
+	genAIOperationChat        = "chat"
+	genAIOperationExecuteTool = "execute_tool"
+)
+
+// genAISystems maps provider names SDKs commonly report to the well-known
+// gen_ai.system values. Other providers are reported lowercased.
+var genAISystems = map[string]string{
+	"azure":        "az.ai.openai",
+	"azure_openai": "az.ai.openai",
+	"azure-openai": "az.ai.openai",
+	"azureopenai":  "az.ai.openai",
+	"bedrock":      "aws.bedrock",
+	"aws_bedrock":  "aws.bedrock",
+	"aws-bedrock":  "aws.bedrock",
+	"vertex":       "gcp.vertex_ai",
+	"vertexai":     "gcp.vertex_ai",
+	"vertex_ai":    "gcp.vertex_ai",
+	"google":       "gcp.gemini",
+	"gemini":       "gcp.gemini",
+	"mistral":      "mistral_ai",
+	"mistralai":    "mistral_ai",
+	"watsonx":      "ibm.watsonx.ai",
+}
+
+// genAIAttributes maps a span's model or tool call data to the OpenTelemetry
+// GenAI semantic convention (gen_ai.*), which APM tools such as Datadog and
+// Grafana render natively. Unset fields are omitted rather than reported as
+// zero; a temperature of zero cannot be told from an unset one and is
+// omitted too.
+func genAIAttributes(s models.Span) []attribute {
+	var attrs []attribute
+	if llm := s.Data.LLM; llm != nil {
+		attrs = append(attrs, attribute{"gen_ai.operation.name", genAIOperationChat})
+		if system := genAISystem(llm.Provider); system != "" {
+			// gen_ai.provider.name supersedes gen_ai.system in newer
+			// versions of the convention; report both until tools catch up.
+			attrs = append(attrs,
+				attribute{"gen_ai.system", system},
+				attribute{"gen_ai.provider.name", system})
+		}
+		if llm.Model != "" {
+			attrs = append(attrs, attribute{"gen_ai.request.model", llm.Model})
+		}
+		if llm.Temperature != 0 {
+			attrs = append(attrs, attribute{"gen_ai.request.temperature", llm.Temperature})
+		}
+		if llm.MaxTokens > 0 {
+			attrs = append(attrs, attribute{"gen_ai.request.max_tokens", int64(llm.MaxTokens)})
+		}
+		if llm.PromptTokens > 0 || llm.CompletionTokens > 0 {
+			attrs = append(attrs,
+				attribute{"gen_ai.usage.input_tokens", int64(llm.PromptTokens)},
+				attribute{"gen_ai.usage.output_tokens", int64(llm.CompletionTokens)})
+		}
+		if llm.FinishReason != "" {
+			attrs = append(attrs, attribute{"gen_ai.response.finish_reasons", []string{llm.FinishReason}})
+		}
+	}
+	if tool := s.Data.Tool; tool != nil {
+		// Tools that call out to external services run as extensions;
+		// the rest are functions the agent executes itself.
+		toolType := "function"
+		if tool.ExternalCall {
+			toolType = "extension"
+		}
+		attrs = append(attrs,
+			attribute{"gen_ai.operation.name", genAIOperationExecuteTool},
+			attribute{"gen_ai.tool.name", tool.ToolName},
+			attribute{"gen_ai.tool.type", toolType})
+	}
+	return attrs
+}
+
+func genAISystem(provider string) string {
+	p := strings.ToLower(strings.TrimSpace(provider))
+	if system, ok := genAISystems[p]; ok {
+		return system
+	}
+	return p
+}
+
+// otlpSpanAttributes returns a span's attributes with the GenAI semantic
+// convention attributes added. Like AgentGuard's own fields, they take
+// precedence over free-form attributes with the same key.
+func otlpSpanAttributes(s models.Span) []OTLPKeyValue {
+	attrs := spanAttributes(s)
+	genai := genAIAttributes(s)
+	if len(genai) == 0 {
+		return otlpKeyValues(attrs)
+	}
+	mapped := make(map[string]bool, len(genai))
+	for _, a := range genai {
+		mapped[a.key] = true
+	}
+	for _, a := range attrs {
+		if !mapped[a.key] {
+			genai = append(genai, a)
+		}
+	}
+	sort.Slice(genai, func(i, j int) bool { return genai[i].key < genai[j].key })
+	return otlpKeyValues(genai)
+}
@@ -0,0 +1,140 @@
+package traceexport
+
+import (
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// jaegerProcessID is the single process every exported span belongs to.
+const jaegerProcessID = "p1"
+
+// JaegerTraces is the Jaeger query service JSON format, which the Jaeger UI
+// can open directly via "Upload JSON".
+type JaegerTraces struct {
+	Data []JaegerTrace `json:"data"`
+}
+
+// JaegerTrace is one trace with its spans and processes.
+type JaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []JaegerSpan             `json:"spans"`
+	Processes map[string]JaegerProcess `json:"processes"`
+}
+
+// JaegerSpan is a single span. Times are in microseconds.
+type JaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	OperationName string            `json:"operationName"`
+	References    []JaegerReference `json:"references"`
+	StartTime     int64             `json:"startTime"`
+	Duration      int64             `json:"duration"`
+	Tags          []JaegerKeyValue  `json:"tags"`
+	Logs          []JaegerLog       `json:"logs"`
+	ProcessID     string            `json:"processID"`
+}
+
+// JaegerReference links a span to its parent, or a delegated trace's root
+// span to the supervisor span that delegated it.
+type JaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+// JaegerLog is a timestamped span log.
+type JaegerLog struct {
+	Timestamp int64            `json:"timestamp"`
+	Fields    []JaegerKeyValue `json:"fields"`
+}
+
+// JaegerProcess describes the service that emitted spans.
+type JaegerProcess struct {
+	ServiceName string           `json:"serviceName"`
+	Tags        []JaegerKeyValue `json:"tags"`
+}
+
+// JaegerKeyValue is a typed tag.
+type JaegerKeyValue struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"` // string, bool, int64, float64
+	Value any    `json:"value"`
+}
+
+// Jaeger converts t to Jaeger JSON. Span events and security signals become
+// span logs; failed spans are tagged error=true as Jaeger expects.
+func Jaeger(t *models.AgentTrace) *JaegerTraces {
+	processTags := []attribute{{"agentguard.agent.id", t.AgentID.String()}}
+	if t.SessionID != "" {
+		processTags = append(processTags, attribute{"session.id", t.SessionID})
+	}
+	if t.UserID != "" {
+		processTags = append(processTags, attribute{"user.id", t.UserID})
+	}
+
+	signals := signalsBySpan(t)
+	spans := make([]JaegerSpan, 0, len(t.Spans))
+	for _, s := range t.Spans {
+		span := JaegerSpan{
+			TraceID:       t.TraceID,
+			SpanID:        s.SpanID,
+			OperationName: s.Name,
+			References:    []JaegerReference{},
+			StartTime:     s.StartTime.UnixMicro(),
+			Duration:      spanEnd(s).Sub(s.StartTime).Microseconds(),
+			Tags:          jaegerKeyValues(spanAttributes(s)),
+			Logs:          []JaegerLog{},
+			ProcessID:     jaegerProcessID,
+		}
+		if s.ParentSpanID != nil {
+			span.References = append(span.References, JaegerReference{RefType: "CHILD_OF", TraceID: t.TraceID, SpanID: *s.ParentSpanID})
+		} else if t.Parent != nil {
+			span.References = append(span.References, JaegerReference{RefType: "FOLLOWS_FROM", TraceID: t.Parent.TraceID, SpanID: t.Parent.SpanID})
+		}
+		if s.Status == "error" {
+			// Jaeger keys failures on a boolean error tag; an "error"
+			// attribute carrying the message moves to error.message.
+			for i, kv := range span.Tags {
+				if kv.Key == "error" {
+					span.Tags[i].Key = "error.message"
+				}
+			}
+			span.Tags = append(span.Tags, JaegerKeyValue{Key: "error", Type: "bool", Value: true})
+		}
+		for _, e := range s.Events {
+			fields := append([]JaegerKeyValue{{Key: "event", Type: "string", Value: e.Name}}, jaegerKeyValues(sortedAttributes(e.Attributes))...)
+			span.Logs = append(span.Logs, JaegerLog{Timestamp: e.Timestamp.UnixMicro(), Fields: fields})
+		}
+		for _, sig := range signals[s.SpanID] {
+			fields := append([]JaegerKeyValue{{Key: "event", Type: "string", Value: "agentguard.security_signal"}}, jaegerKeyValues(signalAttributes(sig))...)
+			span.Logs = append(span.Logs, JaegerLog{Timestamp: sig.Timestamp.UnixMicro(), Fields: fields})
+		}
+		spans = append(spans, span)
+	}
+
+	return &JaegerTraces{Data: []JaegerTrace{{
+		TraceID: t.TraceID,
+		Spans:   spans,
+		Processes: map[string]JaegerProcess{
+			jaegerProcessID: {ServiceName: ServiceName, Tags: jaegerKeyValues(processTags)},
+		},
+	}}}
+}
+
+func jaegerKeyValues(attrs []attribute) []JaegerKeyValue {
+	out := make([]JaegerKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kv := JaegerKeyValue{Key: a.key, Value: scalar(a.value)}
+		switch kv.Value.(type) {
+		case bool:
+			kv.Type = "bool"
+		case int64:
+			kv.Type = "int64"
+		case float64:
+			kv.Type = "float64"
+		default:
+			kv.Type = "string"
+		}
+		out = append(out, kv)
+	}
+	return out
+}
@@ -0,0 +1,231 @@
+package traceexport
+
+import (
+	"strconv"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// OTLP span kinds and status codes.
+const (
+	otlpKindInternal = 1
+	otlpKindServer   = 2
+	otlpKindClient   = 3
+
+	otlpStatusUnset = 0
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+// OTLPTraces is an OTLP/JSON ExportTraceServiceRequest, accepted by the OTLP
+// HTTP endpoint of Tempo, Jaeger, and the OpenTelemetry Collector.
+type OTLPTraces struct {
+	ResourceSpans []OTLPResourceSpans `json:"resourceSpans"`
+}
+
+// OTLPResourceSpans groups spans by the resource that produced them.
+type OTLPResourceSpans struct {
+	Resource   OTLPResource     `json:"resource"`
+	ScopeSpans []OTLPScopeSpans `json:"scopeSpans"`
+}
+
+// OTLPResource describes the entity that produced spans.
+type OTLPResource struct {
+	Attributes []OTLPKeyValue `json:"attributes"`
+}
+
+// OTLPScopeSpans groups spans by instrumentation scope.
+type OTLPScopeSpans struct {
+	Scope OTLPScope  `json:"scope"`
+	Spans []OTLPSpan `json:"spans"`
+}
+
+// OTLPScope names the instrumentation that produced spans.
+type OTLPScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// OTLPSpan is a single span. Timestamps are nanoseconds since the epoch,
+// encoded as strings per the OTLP/JSON mapping of 64-bit integers.
+type OTLPSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []OTLPKeyValue `json:"attributes,omitempty"`
+	Events            []OTLPEvent    `json:"events,omitempty"`
+	Links             []OTLPLink     `json:"links,omitempty"`
+	Status            OTLPStatus     `json:"status"`
+}
+
+// OTLPLink points at a span in another trace.
+type OTLPLink struct {
+	TraceID    string         `json:"traceId"`
+	SpanID     string         `json:"spanId"`
+	Attributes []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+// OTLPEvent is a timestamped annotation on a span.
+type OTLPEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+// OTLPStatus is a span's outcome.
+type OTLPStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLPKeyValue is an attribute.
+type OTLPKeyValue struct {
+	Key   string       `json:"key"`
+	Value OTLPAnyValue `json:"value"`
+}
+
+// OTLPAnyValue holds exactly one typed value.
+type OTLPAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *OTLPArrayValue `json:"arrayValue,omitempty"`
+}
+
+// OTLPArrayValue is a list of values.
+type OTLPArrayValue struct {
+	Values []OTLPAnyValue `json:"values"`
+}
+
+// OTLP converts t to OTLP/JSON. Span events and security signals both become
+// span events; signals are named "agentguard.security_signal".
+func OTLP(t *models.AgentTrace) *OTLPTraces {
+	resource := []OTLPKeyValue{
+		otlpKeyValue(attribute{"service.name", ServiceName}),
+		otlpKeyValue(attribute{"agentguard.agent.id", t.AgentID.String()}),
+	}
+	if t.SessionID != "" {
+		resource = append(resource, otlpKeyValue(attribute{"session.id", t.SessionID}))
+	}
+	if t.UserID != "" {
+		resource = append(resource, otlpKeyValue(attribute{"user.id", t.UserID}))
+	}
+
+	signals := signalsBySpan(t)
+	spans := make([]OTLPSpan, 0, len(t.Spans))
+	for _, s := range t.Spans {
+		span := OTLPSpan{
+			TraceID:           t.TraceID,
+			SpanID:            s.SpanID,
+			Name:              s.Name,
+			Kind:              otlpKind(s),
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(spanEnd(s).UnixNano(), 10),
+			Attributes:        otlpSpanAttributes(s),
+			Status:            otlpStatus(s),
+		}
+		if s.ParentSpanID != nil {
+			span.ParentSpanID = *s.ParentSpanID
+		} else if t.Parent != nil {
+			// Root spans of a delegated trace link to the supervisor span.
+			span.Links = []OTLPLink{{
+				TraceID:    t.Parent.TraceID,
+				SpanID:     t.Parent.SpanID,
+				Attributes: otlpKeyValues([]attribute{{"agentguard.link.type", "delegation"}}),
+			}}
+		}
+		for _, e := range s.Events {
+			span.Events = append(span.Events, OTLPEvent{
+				TimeUnixNano: strconv.FormatInt(e.Timestamp.UnixNano(), 10),
+				Name:         e.Name,
+				Attributes:   otlpKeyValues(sortedAttributes(e.Attributes)),
+			})
+		}
+		for _, sig := range signals[s.SpanID] {
+			span.Events = append(span.Events, OTLPEvent{
+				TimeUnixNano: strconv.FormatInt(sig.Timestamp.UnixNano(), 10),
+				Name:         "agentguard.security_signal",
+				Attributes:   otlpKeyValues(signalAttributes(sig)),
+			})
+		}
+		spans = append(spans, span)
+	}
+
+	return &OTLPTraces{ResourceSpans: []OTLPResourceSpans{{
+		Resource:   OTLPResource{Attributes: resource},
+		ScopeSpans: []OTLPScopeSpans{{Scope: OTLPScope{Name: "agentguard.export"}, Spans: spans}},
+	}}}
+}
+
+// otlpKind maps span types to span kinds: model, tool and retrieval calls
+// are outbound (client), agent spans serve a request, the rest are internal.
+func otlpKind(s models.Span) int {
+	switch s.Type {
+	case models.SpanTypeLLM, models.SpanTypeTool, models.SpanTypeRetrieval:
+		return otlpKindClient
+	case models.SpanTypeAgent:
+		return otlpKindServer
+	default:
+		return otlpKindInternal
+	}
+}
+
+func otlpStatus(s models.Span) OTLPStatus {
+	switch s.Status {
+	case "error":
+		st := OTLPStatus{Code: otlpStatusError}
+		if msg, ok := s.Attributes["error"].(string); ok {
+			st.Message = msg
+		}
+		return st
+	case "ok", "success", "completed":
+		return OTLPStatus{Code: otlpStatusOK}
+	default:
+		return OTLPStatus{Code: otlpStatusUnset}
+	}
+}
+
+func otlpKeyValues(attrs []attribute) []OTLPKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]OTLPKeyValue, len(attrs))
+	for i, a := range attrs {
+		out[i] = otlpKeyValue(a)
+	}
+	return out
+}
+
+func otlpKeyValue(a attribute) OTLPKeyValue {
+	return OTLPKeyValue{Key: a.key, Value: otlpValue(a.value)}
+}
+
+// otlpValue encodes a scalar, or a string list such as
+// gen_ai.response.finish_reasons, which OTLP carries as an array.
+func otlpValue(value any) OTLPAnyValue {
+	var av OTLPAnyValue
+	if list, ok := value.([]string); ok {
+		av.ArrayValue = &OTLPArrayValue{Values: make([]OTLPAnyValue, len(list))}
+		for i, s := range list {
+			av.ArrayValue.Values[i] = otlpValue(s)
+		}
+		return av
+	}
+	switch v := scalar(value).(type) {
+	case bool:
+		av.BoolValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		av.IntValue = &s
+	case float64:
+		av.DoubleValue = &v
+	case string:
+		av.StringValue = &v
+	}
+	return av
+}
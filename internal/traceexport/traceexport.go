@@ -0,0 +1,160 @@
+// Package traceexport converts stored agent traces to standard tracing
+// formats so they can be imported into tools such as Jaeger, Tempo, and
+// Grafana.
+package traceexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+)
+
+// Supported export formats.
+const (
+	FormatOTLP   = "otlp"
+	FormatJaeger = "jaeger"
+)
+
+// ServiceName is the service reported for exported traces.
+const ServiceName = "agentguard"
+
+// Export encodes t in the named format.
+func Export(format string, t *models.AgentTrace) (any, error) {
+	switch format {
+	case FormatOTLP:
+		return OTLP(t), nil
+	case FormatJaeger:
+		return Jaeger(t), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// attribute is one span attribute in a format-neutral form.
+type attribute struct {
+	key   string
+	value any // string, bool, int64 or float64
+}
+
+// spanAttributes flattens a span's typed data and its free-form attributes
+// into sorted key/value pairs. AgentGuard fields use the agentguard.
+// namespace; free-form attributes keep their keys.
+func spanAttributes(s models.Span) []attribute {
+	attrs := map[string]any{"agentguard.span.type": string(s.Type)}
+	if llm := s.Data.LLM; llm != nil {
+		attrs["agentguard.llm.model"] = llm.Model
+		attrs["agentguard.llm.provider"] = llm.Provider
+		attrs["agentguard.llm.prompt_tokens"] = int64(llm.PromptTokens)
+		attrs["agentguard.llm.completion_tokens"] = int64(llm.CompletionTokens)
+		attrs["agentguard.llm.total_tokens"] = int64(llm.TotalTokens)
+		if llm.PromptHash != "" {
+			attrs["agentguard.llm.prompt_hash"] = llm.PromptHash
+		}
+	}
+	if r := s.Data.Retrieval; r != nil {
+		attrs["agentguard.retrieval.vector_store"] = r.VectorStore
+		attrs["agentguard.retrieval.num_results"] = int64(r.NumResults)
+	}
+	if tool := s.Data.Tool; tool != nil {
+		attrs["agentguard.tool.name"] = tool.ToolName
+		attrs["agentguard.tool.category"] = tool.ToolCategory
+		attrs["agentguard.tool.external_call"] = tool.ExternalCall
+		if tool.InputHash != "" {
+			attrs["agentguard.tool.input_hash"] = tool.InputHash
+		}
+		if tool.OutputHash != "" {
+			attrs["agentguard.tool.output_hash"] = tool.OutputHash
+		}
+		if pd := tool.PolicyDecision; pd != nil {
+			attrs["agentguard.policy.id"] = pd.PolicyID
+			attrs["agentguard.policy.decision"] = pd.Decision
+			if pd.Reason != "" {
+				attrs["agentguard.policy.reason"] = pd.Reason
+			}
+		}
+	}
+	for k, v := range s.Attributes {
+		if _, ok := attrs[k]; !ok {
+			attrs[k] = v
+		}
+	}
+	return sortedAttributes(attrs)
+}
+
+// sortedAttributes normalizes values to the scalar types every format
+// supports; anything else is JSON-encoded.
+func sortedAttributes(m map[string]any) []attribute {
+	out := make([]attribute, 0, len(m))
+	for k, v := range m {
+		out = append(out, attribute{key: k, value: scalar(v)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].key < out[j].key })
+	return out
+}
+
+func scalar(v any) any {
+	switch v := v.(type) {
+	case string, bool, int64, float64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case nil:
+		return ""
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// spanEnd returns when a span ended, from EndTime or its duration.
+func spanEnd(s models.Span) time.Time {
+	if s.EndTime != nil {
+		return *s.EndTime
+	}
+	return s.StartTime.Add(time.Duration(s.DurationMs) * time.Millisecond)
+}
+
+// signalsBySpan groups a trace's security signals by span ID. Signals with no
+// span are attached to the root span by the exporters.
+func signalsBySpan(t *models.AgentTrace) map[string][]models.SecuritySignal {
+	out := make(map[string][]models.SecuritySignal)
+	root := ""
+	for _, s := range t.Spans {
+		if s.ParentSpanID == nil {
+			root = s.SpanID
+			break
+		}
+	}
+	for _, sig := range t.SecuritySignals {
+		id := sig.SpanID
+		if id == "" {
+			id = root
+		}
+		out[id] = append(out[id], sig)
+	}
+	return out
+}
+
+// signalAttributes describes a security signal as an event.
+func signalAttributes(sig models.SecuritySignal) []attribute {
+	attrs := map[string]any{
+		"agentguard.signal.id":       sig.ID,
+		"agentguard.signal.type":     string(sig.Type),
+		"agentguard.signal.severity": sig.Severity,
+		"agentguard.signal.title":    sig.Title,
+	}
+	if sig.Description != "" {
+		attrs["agentguard.signal.description"] = sig.Description
+	}
+	return sortedAttributes(attrs)
+}
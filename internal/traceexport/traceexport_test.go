@@ -0,0 +1,181 @@
+package traceexport_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/models"
+	"github.com/agentguard/agentguard/internal/traceexport"
+	"github.com/google/uuid"
+)
+
+func testTrace() *models.AgentTrace {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	parent := "00f067aa0ba902b7"
+	return &models.AgentTrace{
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		AgentID:   uuid.New(),
+		SessionID: "s1",
+		StartTime: start,
+		Spans: []models.Span{
+			{SpanID: parent, Name: "agent", Type: models.SpanTypeAgent, StartTime: start, DurationMs: 100, Status: "ok"},
+			{
+				SpanID: "00f067aa0ba902b8", ParentSpanID: &parent, Name: "shell", Type: models.SpanTypeTool,
+				StartTime: start.Add(10 * time.Millisecond), DurationMs: 20, Status: "error",
+				Attributes: map[string]any{"error": "denied", "retries": 2},
+				Data: models.SpanData{Tool: &models.ToolSpanData{
+					ToolName:       "shell",
+					PolicyDecision: &models.PolicyDecision{PolicyID: "p1", Decision: "deny"},
+				}},
+			},
+		},
+		SecuritySignals: []models.SecuritySignal{
+			{ID: "sig-1", Type: models.SignalToolAbuse, Severity: "high", Title: "shell", Timestamp: start},
+		},
+	}
+}
+
+func TestOTLP(t *testing.T) {
+	out := traceexport.OTLP(testTrace())
+	spans := out.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	root, tool := spans[0], spans[1]
+	if root.StartTimeUnixNano != "1735732800000000000" || root.EndTimeUnixNano != "1735732800100000000" {
+		t.Errorf("root times = %s..%s", root.StartTimeUnixNano, root.EndTimeUnixNano)
+	}
+	if tool.ParentSpanID != root.SpanID || tool.Kind != 3 || tool.Status.Code != 2 || tool.Status.Message != "denied" {
+		t.Errorf("tool span = %+v", tool)
+	}
+	attrs := map[string]traceexport.OTLPAnyValue{}
+	for _, kv := range tool.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["agentguard.policy.decision"]; v.StringValue == nil || *v.StringValue != "deny" {
+		t.Errorf("policy decision attribute = %+v", v)
+	}
+	if v := attrs["retries"]; v.IntValue == nil || *v.IntValue != "2" {
+		t.Errorf("retries attribute = %+v, want intValue 2", v)
+	}
+	// The span-less signal attaches to the root span.
+	if len(root.Events) != 1 || root.Events[0].Name != "agentguard.security_signal" {
+		t.Errorf("root events = %+v, want the security signal", root.Events)
+	}
+}
+
+func TestOTLPGenAIAttributes(t *testing.T) {
+	tr := testTrace()
+	parent := tr.Spans[0].SpanID
+	tr.Spans = append(tr.Spans, models.Span{
+		SpanID: "00f067aa0ba902b9", ParentSpanID: &parent, Name: "llm", Type: models.SpanTypeLLM,
+		StartTime: tr.StartTime, DurationMs: 50, Status: "ok",
+		Attributes: map[string]any{"gen_ai.request.model": "stale"},
+		Data: models.SpanData{LLM: &models.LLMSpanData{
+			Model: "gpt-4o", Provider: "Azure", PromptTokens: 120, CompletionTokens: 30,
+			Temperature: 0.2, MaxTokens: 512, FinishReason: "stop",
+		}},
+	})
+	spans := traceexport.OTLP(tr).ResourceSpans[0].ScopeSpans[0].Spans
+	attrsOf := func(s traceexport.OTLPSpan) map[string]traceexport.OTLPAnyValue {
+		out := map[string]traceexport.OTLPAnyValue{}
+		for i, kv := range s.Attributes {
+			if i > 0 && s.Attributes[i-1].Key >= kv.Key {
+				t.Errorf("attributes not sorted and unique at %q", kv.Key)
+			}
+			out[kv.Key] = kv.Value
+		}
+		return out
+	}
+	str := func(v traceexport.OTLPAnyValue) string {
+		if v.StringValue == nil {
+			return ""
+		}
+		return *v.StringValue
+	}
+
+	llm := attrsOf(spans[2])
+	for key, want := range map[string]string{
+		"gen_ai.operation.name": "chat",
+		"gen_ai.system":         "az.ai.openai",
+		"gen_ai.provider.name":  "az.ai.openai",
+		"gen_ai.request.model":  "gpt-4o",
+		"agentguard.llm.model":  "gpt-4o",
+	} {
+		if got := str(llm[key]); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	for key, want := range map[string]string{"gen_ai.usage.input_tokens": "120", "gen_ai.usage.output_tokens": "30", "gen_ai.request.max_tokens": "512"} {
+		if v := llm[key]; v.IntValue == nil || *v.IntValue != want {
+			t.Errorf("%s = %+v, want intValue %s", key, v, want)
+		}
+	}
+	if v := llm["gen_ai.request.temperature"]; v.DoubleValue == nil || *v.DoubleValue != 0.2 {
+		t.Errorf("temperature = %+v", v)
+	}
+	if v := llm["gen_ai.response.finish_reasons"]; v.ArrayValue == nil || len(v.ArrayValue.Values) != 1 || str(v.ArrayValue.Values[0]) != "stop" {
+		t.Errorf("finish reasons = %+v, want [stop]", v)
+	}
+
+	tool := attrsOf(spans[1])
+	if str(tool["gen_ai.operation.name"]) != "execute_tool" || str(tool["gen_ai.tool.name"]) != "shell" || str(tool["gen_ai.tool.type"]) != "function" {
+		t.Errorf("tool attributes = %+v", tool)
+	}
+	if _, ok := attrsOf(spans[0])["gen_ai.operation.name"]; ok {
+		t.Error("agent span should carry no GenAI operation")
+	}
+
+	// Jaeger tags are unchanged.
+	for _, kv := range traceexport.Jaeger(tr).Data[0].Spans[2].Tags {
+		if kv.Key == "gen_ai.system" {
+			t.Error("unexpected GenAI attribute in Jaeger export")
+		}
+	}
+}
+
+func TestDelegatedTraceLinks(t *testing.T) {
+	tr := testTrace()
+	tr.Parent = &models.TraceLink{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331"}
+
+	spans := traceexport.OTLP(tr).ResourceSpans[0].ScopeSpans[0].Spans
+	if links := spans[0].Links; len(links) != 1 || links[0].TraceID != tr.Parent.TraceID || links[0].SpanID != tr.Parent.SpanID {
+		t.Errorf("root span links = %+v, want the supervisor span", links)
+	}
+	if len(spans[1].Links) != 0 {
+		t.Errorf("child span links = %+v, want none", spans[1].Links)
+	}
+
+	root := traceexport.Jaeger(tr).Data[0].Spans[0]
+	if len(root.References) != 1 || root.References[0].RefType != "FOLLOWS_FROM" || root.References[0].TraceID != tr.Parent.TraceID {
+		t.Errorf("root references = %+v, want FOLLOWS_FROM the supervisor span", root.References)
+	}
+}
+
+func TestJaeger(t *testing.T) {
+	out := traceexport.Jaeger(testTrace())
+	trace := out.Data[0]
+	if trace.Processes["p1"].ServiceName != traceexport.ServiceName {
+		t.Errorf("processes = %+v", trace.Processes)
+	}
+	tool := trace.Spans[1]
+	if tool.StartTime != 1735732800010000 || tool.Duration != 20000 {
+		t.Errorf("tool timing = %d+%d, want microseconds", tool.StartTime, tool.Duration)
+	}
+	if len(tool.References) != 1 || tool.References[0].RefType != "CHILD_OF" || tool.References[0].SpanID != "00f067aa0ba902b7" {
+		t.Errorf("references = %+v", tool.References)
+	}
+	var errTag bool
+	for _, kv := range tool.Tags {
+		if kv.Key == "error" && kv.Value == true {
+			errTag = true
+		}
+	}
+	if !errTag {
+		t.Error("failed span not tagged error=true")
+	}
+
+	if _, err := traceexport.Export("zipkin", testTrace()); err == nil {
+		t.Error("unsupported format accepted")
+	}
+}
@@ -0,0 +1,125 @@
+package urlcat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxFeedBytes bounds a downloaded category feed.
+const maxFeedBytes = 16 << 20
+
+// Updater merges the built-in categories with category feeds and publishes
+// the result whenever it changes. Later feeds override earlier ones and the
+// defaults. It is safe for concurrent use.
+type Updater struct {
+	sources []string
+	publish func(domains map[string]string) error
+	client  *http.Client
+
+	mu      sync.RWMutex
+	domains map[string]string
+}
+
+// NewUpdater creates an updater for feeds at sources, each an http(s) URL
+// or a file path. publish receives every new set of categories.
+func NewUpdater(sources []string, publish func(domains map[string]string) error) *Updater {
+	return &Updater{
+		sources: sources,
+		publish: publish,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Domains returns the categories last published, keyed by domain.
+func (u *Updater) Domains() map[string]string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return maps.Clone(u.domains)
+}
+
+// Refresh reloads every feed and publishes the merged categories if they
+// changed. When a feed cannot be loaded the previously published categories
+// are kept; on the first refresh the categories that did load are published
+// so that the defaults apply.
+func (u *Updater) Refresh(ctx context.Context) error {
+	domains := maps.Clone(Defaults)
+	var errs []error
+	for _, src := range u.sources {
+		feed, err := u.load(ctx, src)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("loading url categories from %s: %w", src, err))
+			continue
+		}
+		maps.Copy(domains, feed)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(errs) > 0 && u.domains != nil {
+		return errors.Join(errs...)
+	}
+	if u.domains != nil && maps.Equal(u.domains, domains) {
+		return nil
+	}
+	if u.publish != nil {
+		if err := u.publish(maps.Clone(domains)); err != nil {
+			return fmt.Errorf("publishing url categories: %w", err)
+		}
+	}
+	u.domains = domains
+	return errors.Join(errs...)
+}
+
+// Run refreshes the categories every interval until ctx is cancelled.
+func (u *Updater) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := u.Refresh(ctx); err != nil {
+				log.Error().Err(err).Msg("url category refresh failed")
+			}
+		}
+	}
+}
+
+func (u *Updater) load(ctx context.Context, src string) (map[string]string, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Parse(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return Parse(io.LimitReader(resp.Body, maxFeedBytes))
+}
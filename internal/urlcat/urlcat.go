@@ -0,0 +1,130 @@
+// Package urlcat categorizes URLs by domain so that policies can keep
+// web-browsing tools away from sites an injected agent could use to
+// exfiltrate data. Categories are published to the policy engine as data
+// and refreshed from configured feeds by an Updater.
+package urlcat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// URL categories.
+const (
+	News        = "news"
+	Webmail     = "webmail"
+	PasteSites  = "paste_sites"
+	FileSharing = "file_sharing"
+)
+
+// HighExfiltrationRisk lists the categories where an agent can post data
+// for an attacker to collect. They are blocked unless an agent's policy
+// says otherwise.
+var HighExfiltrationRisk = []string{Webmail, PasteSites, FileSharing}
+
+// Defaults maps well-known domains to their categories. Subdomains inherit
+// the category of the closest listed parent.
+var Defaults = map[string]string{
+	// News
+	"apnews.com":      News,
+	"bbc.co.uk":       News,
+	"bbc.com":         News,
+	"bloomberg.com":   News,
+	"cnn.com":         News,
+	"nytimes.com":     News,
+	"reuters.com":     News,
+	"theguardian.com": News,
+	"wsj.com":         News,
+
+	// Webmail
+	"mail.google.com":    Webmail,
+	"mail.yahoo.com":     Webmail,
+	"mail.proton.me":     Webmail,
+	"outlook.live.com":   Webmail,
+	"outlook.office.com": Webmail,
+	"protonmail.com":     Webmail,
+	"mail.zoho.com":      Webmail,
+	"gmx.com":            Webmail,
+	"tutanota.com":       Webmail,
+
+	// Paste sites
+	"controlc.com":    PasteSites,
+	"dpaste.org":      PasteSites,
+	"ghostbin.com":    PasteSites,
+	"gist.github.com": PasteSites,
+	"hastebin.com":    PasteSites,
+	"paste.ee":        PasteSites,
+	"pastebin.com":    PasteSites,
+	"privatebin.net":  PasteSites,
+	"rentry.co":       PasteSites,
+
+	// File sharing
+	"anonfiles.com":  FileSharing,
+	"dropbox.com":    FileSharing,
+	"file.io":        FileSharing,
+	"gofile.io":      FileSharing,
+	"mediafire.com":  FileSharing,
+	"mega.nz":        FileSharing,
+	"transfer.sh":    FileSharing,
+	"wetransfer.com": FileSharing,
+	"sendspace.com":  FileSharing,
+}
+
+// Host returns the lowercased host of a URL, without port or credentials.
+// URLs without a scheme are read as http URLs.
+func Host(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
+
+// Categorize returns the category of a URL's host in domains, or "" when
+// neither the host nor any parent domain is listed.
+func Categorize(domains map[string]string, rawURL string) string {
+	host := Host(rawURL)
+	for host != "" {
+		if c, ok := domains[host]; ok {
+			return c
+		}
+		_, parent, ok := strings.Cut(host, ".")
+		if !ok {
+			break
+		}
+		host = parent
+	}
+	return ""
+}
+
+// Parse reads a category feed: a JSON object mapping domains to
+// categories, either at the top level or under "domains". Domains are
+// lowercased and categories must be non-empty.
+func Parse(r io.Reader) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding category feed: %w", err)
+	}
+	if nested, ok := raw["domains"]; ok {
+		raw = nil
+		if err := json.Unmarshal(nested, &raw); err != nil {
+			return nil, fmt.Errorf("decoding category feed domains: %w", err)
+		}
+	}
+	out := make(map[string]string, len(raw))
+	for domain, v := range raw {
+		var category string
+		if err := json.Unmarshal(v, &category); err != nil || category == "" {
+			return nil, fmt.Errorf("domain %q: category must be a non-empty string", domain)
+		}
+		out[strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")] = strings.ToLower(category)
+	}
+	return out, nil
+}
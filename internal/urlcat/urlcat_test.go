@@ -0,0 +1,61 @@
+package urlcat_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/urlcat"
+)
+
+func TestCategorize(t *testing.T) {
+	for _, tt := range []struct{ url, want string }{
+		{"https://pastebin.com/raw/abc", urlcat.PasteSites},
+		{"https://user:pw@Mail.Google.com:443/mail", urlcat.Webmail},
+		{"www.dropbox.com/s/abc", urlcat.FileSharing},
+		{"https://google.com/search", ""},
+		{"not a url", ""},
+	} {
+		if got := urlcat.Categorize(urlcat.Defaults, tt.url); got != tt.want {
+			t.Errorf("Categorize(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestUpdater(t *testing.T) {
+	feed := filepath.Join(t.TempDir(), "feed.json")
+	if err := os.WriteFile(feed, []byte(`{"domains": {"Paste.Example.com": "paste_sites", "reuters.com": "partner"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var published []map[string]string
+	u := urlcat.NewUpdater([]string{feed}, func(d map[string]string) error {
+		published = append(published, d)
+		return nil
+	})
+	if err := u.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	d := u.Domains()
+	if d["paste.example.com"] != urlcat.PasteSites || d["reuters.com"] != "partner" || d["pastebin.com"] != urlcat.PasteSites {
+		t.Errorf("domains do not merge the feed over the defaults: %v", d)
+	}
+
+	// Unchanged categories are not republished.
+	if err := u.Refresh(context.Background()); err != nil || len(published) != 1 {
+		t.Fatalf("refresh = %v, published %d times, want once", err, len(published))
+	}
+
+	// A broken feed keeps the published categories.
+	if err := os.WriteFile(feed, []byte(`{"x.com": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Refresh(context.Background()); err == nil || !strings.Contains(err.Error(), "x.com") {
+		t.Errorf("refresh with a broken feed = %v, want an error naming x.com", err)
+	}
+	if len(published) != 1 || u.Domains()["paste.example.com"] == "" {
+		t.Error("a broken feed replaced the published categories")
+	}
+}
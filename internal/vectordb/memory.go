@@ -0,0 +1,105 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// MemoryProvider keeps documents in process and searches them by cosine
+// similarity to a pre-computed embedding. It suits small corpora such as
+// control catalogs.
+type MemoryProvider struct {
+	mu   sync.RWMutex
+	docs map[string]Document
+}
+
+// NewMemoryProvider creates an empty in-memory provider
+func NewMemoryProvider() *MemoryProvider {
+	return &MemoryProvider{docs: make(map[string]Document)}
+}
+
+// Upsert stores documents, which must carry their embeddings
+func (p *MemoryProvider) Upsert(ctx context.Context, docs []Document) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, d := range docs {
+		if len(d.Embedding) == 0 {
+			return fmt.Errorf("document %s has no embedding", d.ID)
+		}
+		p.docs[d.ID] = d
+	}
+	return nil
+}
+
+// Search returns the TopK documents matching every filter, most similar to
+// the request embedding first
+func (p *MemoryProvider) Search(ctx context.Context, req SearchRequest) ([]Document, error) {
+	if len(req.Embedding) == 0 {
+		return nil, fmt.Errorf("memory provider requires a pre-computed embedding")
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var out []Document
+	for _, d := range p.docs {
+		if !matchesFilter(d, req.Filter) {
+			continue
+		}
+		d.Score = cosine(req.Embedding, d.Embedding)
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].ID < out[j].ID
+	})
+	if req.TopK > 0 && len(out) > req.TopK {
+		out = out[:req.TopK]
+	}
+	return out, nil
+}
+
+// Delete removes documents by ID
+func (p *MemoryProvider) Delete(ctx context.Context, ids []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, id := range ids {
+		delete(p.docs, id)
+	}
+	return nil
+}
+
+func (p *MemoryProvider) Name() string {
+	return "memory"
+}
+
+func matchesFilter(d Document, filter map[string]string) bool {
+	for k, v := range filter {
+		if d.Metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// cosine returns the cosine similarity of two vectors, or 0 when their
+// lengths differ or either is zero.
+func cosine(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / math.Sqrt(na*nb))
+}
@@ -0,0 +1,84 @@
+// Package webhook delivers JSON payloads to configured destinations,
+// signed so receivers can verify them with pkg/client.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/agentguard/agentguard/pkg/client"
+)
+
+// MinSecretBytes is the shortest secret a destination may be configured
+// with.
+const MinSecretBytes = 16
+
+// Sender POSTs JSON to one destination. Every request carries a timestamp
+// and a random nonce; with a secret it also carries an HMAC-SHA-256
+// signature over both and the body.
+type Sender struct {
+	url    string
+	secret []byte
+	client *http.Client
+	now    func() time.Time
+}
+
+// New creates a Sender for url. secret may be empty, in which case
+// webhooks are sent unsigned; otherwise it must be at least MinSecretBytes
+// long.
+func New(url, secret string) (*Sender, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if secret != "" && len(secret) < MinSecretBytes {
+		return nil, fmt.Errorf("webhook secret must be at least %d bytes", MinSecretBytes)
+	}
+	return &Sender{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+// Signed reports whether the Sender signs its webhooks.
+func (s *Sender) Signed() bool {
+	return len(s.secret) > 0
+}
+
+// Send POSTs body, which must be JSON, and fails unless the destination
+// answers with a 2xx status.
+func (s *Sender) Send(ctx context.Context, body []byte) error {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Errorf("generating webhook nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(b[:])
+	timestamp := s.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(client.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(client.NonceHeader, nonce)
+	if s.Signed() {
+		req.Header.Set(client.SignatureHeader, client.SignWebhook(s.secret, timestamp, nonce, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agentguard/agentguard/internal/webhook"
+	"github.com/agentguard/agentguard/pkg/client"
+)
+
+func TestSender(t *testing.T) {
+	if _, err := webhook.New("http://example.com", "short"); err == nil {
+		t.Error("New() accepted a short secret")
+	}
+
+	var headers []http.Header
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	unsigned, err := webhook.New(srv.URL, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := unsigned.Send(ctx, []byte(`{}`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	h := headers[0]
+	if h.Get(client.SignatureHeader) != "" || h.Get(client.TimestampHeader) == "" || len(h.Get(client.NonceHeader)) != 32 {
+		t.Errorf("unsigned headers = %v", h)
+	}
+
+	signed, err := webhook.New(srv.URL, "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := signed.Send(ctx, []byte(`{}`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if headers[1].Get(client.SignatureHeader) == "" || headers[1].Get(client.NonceHeader) == h.Get(client.NonceHeader) {
+		t.Errorf("signed headers = %v", headers[1])
+	}
+
+	status = http.StatusBadGateway
+	if err := signed.Send(ctx, []byte(`{}`)); err == nil {
+		t.Error("Send() succeeded on a 502")
+	}
+}
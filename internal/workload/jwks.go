@@ -0,0 +1,242 @@
+package workload
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// keySetTTL is how long fetched keys are trusted before a refresh.
+	keySetTTL = time.Hour
+	// keySetMinRefresh bounds refreshes triggered by unknown key IDs.
+	keySetMinRefresh = time.Minute
+	// maxKeySetBytes bounds discovery and key set responses.
+	maxKeySetBytes = 1 << 20
+)
+
+// jwk is a JSON Web Key; only the public parameters are read.
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type publicKey struct {
+	id      string
+	keyType string
+	key     crypto.PublicKey
+}
+
+// keySet caches an issuer's signing keys.
+type keySet struct {
+	issuer Issuer
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    []publicKey
+	fetched time.Time
+}
+
+func newKeySet(is Issuer, client *http.Client) *keySet {
+	return &keySet{issuer: is, client: client}
+}
+
+// key returns the key that verifies tokens signed with kid and alg,
+// refreshing the set when it is stale or the key is unknown.
+func (s *keySet) key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	keyType := algorithms[alg].keyType
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.fetched) < keySetTTL {
+		if k := s.find(kid, keyType); k != nil {
+			return k, nil
+		}
+		if time.Since(s.fetched) < keySetMinRefresh {
+			return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+		}
+	}
+	keys, err := s.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading key set for %s: %w", s.issuer.URL, err)
+	}
+	s.keys, s.fetched = keys, time.Now()
+	if k := s.find(kid, keyType); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+}
+
+// find matches by key ID, or accepts the only key of the right type when
+// the token names none.
+func (s *keySet) find(kid, keyType string) crypto.PublicKey {
+	var only crypto.PublicKey
+	n := 0
+	for _, k := range s.keys {
+		if k.keyType != keyType {
+			continue
+		}
+		if kid != "" && k.id == kid {
+			return k.key
+		}
+		only = k.key
+		n++
+	}
+	if kid == "" && n == 1 {
+		return only
+	}
+	return nil
+}
+
+func (s *keySet) load(ctx context.Context) ([]publicKey, error) {
+	var data []byte
+	var err error
+	switch {
+	case s.issuer.JWKSFile != "":
+		data, err = os.ReadFile(s.issuer.JWKSFile)
+	case s.issuer.JWKSURL != "":
+		data, err = s.get(ctx, s.issuer.JWKSURL)
+	default:
+		var uri string
+		uri, err = s.discover(ctx)
+		if err == nil {
+			data, err = s.get(ctx, uri)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseKeySet(data)
+}
+
+// discover reads jwks_uri from the issuer's OpenID configuration.
+func (s *keySet) discover(ctx context.Context) (string, error) {
+	data, err := s.get(ctx, strings.TrimSuffix(s.issuer.URL, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return "", err
+	}
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("decoding openid configuration: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return "", errors.New("openid configuration has no jwks_uri")
+	}
+	return doc.JWKSURI, nil
+}
+
+func (s *keySet) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxKeySetBytes))
+}
+
+func parseKeySet(data []byte) ([]publicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decoding key set: %w", err)
+	}
+	keys := make([]publicKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.KeyID, err)
+		}
+		if pub == nil {
+			continue
+		}
+		keys = append(keys, publicKey{id: k.KeyID, keyType: k.KeyType, key: pub})
+	}
+	return keys, nil
+}
+
+// publicKey decodes RSA and EC keys; other key types are skipped.
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,158 @@
+package workload
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// header is the JOSE header of a signed token.
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// claims are the registered claims plus the Kubernetes service account
+// claims projected tokens carry.
+type claims struct {
+	Issuer     string          `json:"iss"`
+	Subject    string          `json:"sub"`
+	Audience   audience        `json:"aud"`
+	Expiry     int64           `json:"exp"`
+	NotBefore  int64           `json:"nbf"`
+	Kubernetes *kubernetesInfo `json:"kubernetes.io"`
+}
+
+type kubernetesInfo struct {
+	Namespace      string  `json:"namespace"`
+	ServiceAccount k8sName `json:"serviceaccount"`
+	Pod            k8sName `json:"pod"`
+}
+
+type k8sName struct {
+	Name string `json:"name"`
+}
+
+// audience accepts the aud claim as a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// token is a parsed but not yet verified JWT.
+type token struct {
+	header    header
+	claims    claims
+	signed    string
+	signature []byte
+}
+
+func parseToken(raw string) (*token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+	t := &token{signed: parts[0] + "." + parts[1]}
+	if err := decodeSegment(parts[0], &t.header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if err := decodeSegment(parts[1], &t.claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+	t.signature = sig
+	if _, ok := algorithms[t.header.Algorithm]; !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, t.header.Algorithm)
+	}
+	return t, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// algorithm is a supported asymmetric JWS algorithm. Symmetric algorithms
+// and "none" are deliberately absent.
+type algorithm struct {
+	hash crypto.Hash
+	// keyType is the JWK kty the algorithm verifies with.
+	keyType string
+}
+
+var algorithms = map[string]algorithm{
+	"RS256": {crypto.SHA256, "RSA"},
+	"RS384": {crypto.SHA384, "RSA"},
+	"RS512": {crypto.SHA512, "RSA"},
+	"ES256": {crypto.SHA256, "EC"},
+	"ES384": {crypto.SHA384, "EC"},
+}
+
+func (t *token) verify(key crypto.PublicKey) error {
+	alg := algorithms[t.header.Algorithm]
+	digest := hashOf(alg.hash, t.signed)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg.keyType != "RSA" {
+			break
+		}
+		if err := rsa.VerifyPKCS1v15(k, alg.hash, digest, t.signature); err != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if alg.keyType != "EC" {
+			break
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(t.signature) != 2*size {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: key does not match algorithm %s", ErrInvalidToken, t.header.Algorithm)
+}
+
+func hashOf(h crypto.Hash, s string) []byte {
+	switch h {
+	case crypto.SHA384:
+		sum := sha512.Sum384([]byte(s))
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512([]byte(s))
+		return sum[:]
+	default:
+		sum := sha256.Sum256([]byte(s))
+		return sum[:]
+	}
+}
@@ -0,0 +1,200 @@
+package workload
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidSVID is returned for client certificates that are not valid
+// X.509-SVIDs for the configured trust domain.
+var ErrInvalidSVID = errors.New("invalid SPIFFE SVID")
+
+// SPIFFEConfig configures an SVIDVerifier.
+type SPIFFEConfig struct {
+	// TrustDomain is the accepted trust domain, e.g. "prod.example.org".
+	TrustDomain string
+	// Bindings map SPIFFE IDs to agents. Subject patterns match the full
+	// ID, e.g. "spiffe://prod.example.org/ns/agents/sa/*". When AgentID is
+	// empty, the last path segment of the ID is used.
+	Bindings []Binding
+}
+
+// SVIDVerifier maps X.509-SVID client certificates to agents. Chain
+// verification against the trust bundle is left to the TLS handshake.
+type SVIDVerifier struct {
+	trustDomain string
+	bindings    []Binding
+}
+
+// NewSVIDVerifier creates an SVID verifier for one trust domain.
+func NewSVIDVerifier(cfg SPIFFEConfig) (*SVIDVerifier, error) {
+	td := strings.ToLower(strings.TrimPrefix(cfg.TrustDomain, "spiffe://"))
+	if td == "" {
+		return nil, errors.New("spiffe trust domain is required")
+	}
+	for _, b := range cfg.Bindings {
+		if _, err := path.Match(b.Subject, ""); err != nil || b.Subject == "" {
+			return nil, fmt.Errorf("spiffe binding %q is not a valid pattern", b.Subject)
+		}
+	}
+	return &SVIDVerifier{trustDomain: td, bindings: cfg.Bindings}, nil
+}
+
+// Identify returns the agent identity of a verified leaf certificate.
+func (v *SVIDVerifier) Identify(cert *x509.Certificate) (*Identity, error) {
+	if cert.IsCA {
+		return nil, fmt.Errorf("%w: leaf is a CA certificate", ErrInvalidSVID)
+	}
+	if len(cert.URIs) != 1 {
+		return nil, fmt.Errorf("%w: want exactly one URI SAN, got %d", ErrInvalidSVID, len(cert.URIs))
+	}
+	id, td, err := ParseSPIFFEID(cert.URIs[0])
+	if err != nil {
+		return nil, err
+	}
+	if td != v.trustDomain {
+		return nil, fmt.Errorf("%w: trust domain %q is not trusted", ErrInvalidSVID, td)
+	}
+
+	ident := &Identity{
+		Issuer:    "spiffe://" + v.trustDomain,
+		Subject:   id,
+		ExpiresAt: cert.NotAfter.UTC(),
+	}
+	for _, b := range v.bindings {
+		if ok, _ := path.Match(b.Subject, id); !ok {
+			continue
+		}
+		ident.AgentID = b.AgentID
+		if ident.AgentID == "" {
+			ident.AgentID = path.Base(id)
+		}
+		ident.OrgID = b.OrgID
+		return ident, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnbound, id)
+}
+
+// ParseSPIFFEID validates u as a workload SPIFFE ID and returns it in
+// canonical form along with its trust domain.
+func ParseSPIFFEID(u *url.URL) (id, trustDomain string, err error) {
+	switch {
+	case u.Scheme != "spiffe":
+		return "", "", fmt.Errorf("%w: scheme %q is not spiffe", ErrInvalidSVID, u.Scheme)
+	case u.Host == "" || u.Port() != "" || u.User != nil:
+		return "", "", fmt.Errorf("%w: invalid trust domain in %q", ErrInvalidSVID, u)
+	case u.RawQuery != "" || u.Fragment != "":
+		return "", "", fmt.Errorf("%w: query or fragment in %q", ErrInvalidSVID, u)
+	case u.Path == "" || u.Path == "/" || strings.HasSuffix(u.Path, "/"):
+		return "", "", fmt.Errorf("%w: %q has no workload path", ErrInvalidSVID, u)
+	}
+	for _, seg := range strings.Split(u.Path[1:], "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", "", fmt.Errorf("%w: invalid path segment in %q", ErrInvalidSVID, u)
+		}
+	}
+	trustDomain = strings.ToLower(u.Host)
+	return "spiffe://" + trustDomain + u.Path, trustDomain, nil
+}
+
+// SVIDSource serves the server's own SVID and trust bundle from files kept
+// current by the SPIRE agent (or spiffe-helper), reloading them when they
+// change on disk.
+type SVIDSource struct {
+	certFile, keyFile, bundleFile string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	bundle   *x509.CertPool
+	modified time.Time
+}
+
+// NewSVIDSource loads the server SVID and trust bundle.
+func NewSVIDSource(certFile, keyFile, bundleFile string) (*SVIDSource, error) {
+	s := &SVIDSource{certFile: certFile, keyFile: keyFile, bundleFile: bundleFile}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// TLSConfig returns a server configuration that requests client SVIDs and
+// verifies any presented certificate against the trust bundle. Clients
+// without a certificate still connect and authenticate by other means.
+func (s *SVIDSource) TLSConfig() *tls.Config {
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		// A failed reload keeps serving the previous SVID: rotation may be
+		// caught between writing the certificate and the key, and the
+		// next handshake retries.
+		s.reloadIfChanged()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{*s.cert},
+			ClientCAs:    s.bundle,
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+		}, nil
+	}
+	return base
+}
+
+func (s *SVIDSource) reloadIfChanged() {
+	latest, err := s.latestModTime()
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	stale := latest.After(s.modified)
+	s.mu.Unlock()
+	if stale {
+		_ = s.reload()
+	}
+}
+
+func (s *SVIDSource) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{s.certFile, s.keyFile, s.bundleFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (s *SVIDSource) reload() error {
+	modified, err := s.latestModTime()
+	if err != nil {
+		return fmt.Errorf("reading svid files: %w", err)
+	}
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading server svid: %w", err)
+	}
+	pem, err := os.ReadFile(s.bundleFile)
+	if err != nil {
+		return fmt.Errorf("reading trust bundle: %w", err)
+	}
+	bundle := x509.NewCertPool()
+	if !bundle.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("trust bundle %s contains no certificates", s.bundleFile)
+	}
+
+	s.mu.Lock()
+	s.cert, s.bundle, s.modified = &cert, bundle, modified
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,82 @@
+package workload_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/workload"
+)
+
+func TestSVIDVerifier(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svid := func(ids ...string) *x509.Certificate {
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			NotBefore:    time.Now().Add(-time.Minute),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		for _, id := range ids {
+			u, err := url.Parse(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tmpl.URIs = append(tmpl.URIs, u)
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+
+	v, err := workload.NewSVIDVerifier(workload.SPIFFEConfig{
+		TrustDomain: "Prod.Example.org",
+		Bindings: []workload.Binding{
+			{Subject: "spiffe://prod.example.org/ns/billing/sa/*", AgentID: "billing-agent", OrgID: "acme"},
+			{Subject: "spiffe://prod.example.org/ns/agents/sa/*"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := v.Identify(svid("spiffe://PROD.example.org/ns/agents/sa/support-bot"))
+	if err != nil {
+		t.Fatalf("Identify: %v", err)
+	}
+	if id.AgentID != "support-bot" || id.Subject != "spiffe://prod.example.org/ns/agents/sa/support-bot" {
+		t.Errorf("identity = %+v, want support-bot with canonical id", id)
+	}
+	if id, err := v.Identify(svid("spiffe://prod.example.org/ns/billing/sa/worker")); err != nil || id.AgentID != "billing-agent" || id.OrgID != "acme" {
+		t.Errorf("explicit binding = %+v, %v; want billing-agent in acme", id, err)
+	}
+
+	for name, tc := range map[string]struct {
+		cert *x509.Certificate
+		want error
+	}{
+		"foreign trust domain": {svid("spiffe://dev.example.org/ns/agents/sa/x"), workload.ErrInvalidSVID},
+		"two ids":              {svid("spiffe://prod.example.org/a", "spiffe://prod.example.org/b"), workload.ErrInvalidSVID},
+		"no path":              {svid("spiffe://prod.example.org"), workload.ErrInvalidSVID},
+		"not spiffe":           {svid("https://prod.example.org/ns/agents/sa/x"), workload.ErrInvalidSVID},
+		"unbound":              {svid("spiffe://prod.example.org/ns/other/sa/x"), workload.ErrUnbound},
+	} {
+		if _, err := v.Identify(tc.cert); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", name, err, tc.want)
+		}
+	}
+}
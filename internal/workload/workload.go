@@ -0,0 +1,218 @@
+// Package workload authenticates agents by their platform workload identity.
+// Agents running in Kubernetes (or any platform with an OIDC issuer) present
+// a short-lived signed token, such as a projected service account token,
+// instead of a distributed API key. The verifier checks the token against the
+// issuer's published signing keys and maps the authenticated subject to a
+// registered agent through configured bindings. In zero-trust deployments
+// agents may instead present a SPIFFE X.509-SVID over mTLS, whose spiffe://
+// ID is mapped the same way.
+package workload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that fail parsing, signature
+	// or claim validation.
+	ErrInvalidToken = errors.New("invalid workload token")
+	// ErrUnbound is returned for valid tokens whose subject matches no
+	// binding.
+	ErrUnbound = errors.New("workload identity is not bound to an agent")
+)
+
+// Issuer is a trusted token issuer.
+type Issuer struct {
+	// URL must equal the token's iss claim.
+	URL string
+	// JWKSURL is the issuer's key set. When empty and JWKSFile is unset, it
+	// is discovered from URL's /.well-known/openid-configuration.
+	JWKSURL string
+	// JWKSFile reads the key set from disk, for clusters whose key endpoint
+	// is not reachable anonymously.
+	JWKSFile string
+	// Audiences lists accepted aud values; at least one is required.
+	Audiences []string
+}
+
+// Binding maps token subjects to an agent.
+type Binding struct {
+	// Issuer restricts the binding to one issuer URL; empty matches any.
+	Issuer string
+	// Subject is a path.Match pattern against the sub claim, e.g.
+	// "system:serviceaccount:agents:*".
+	Subject string
+	// AgentID is the bound agent. When empty, the Kubernetes service account
+	// name is used, falling back to the subject.
+	AgentID string
+	// OrgID, when set, pins the identity's organization.
+	OrgID string
+}
+
+// Config configures a Verifier.
+type Config struct {
+	Issuers  []Issuer
+	Bindings []Binding
+	// Leeway tolerates clock skew on exp and nbf. Defaults to one minute.
+	Leeway time.Duration
+	// Client fetches discovery documents and key sets. Defaults to a client
+	// with a ten second timeout.
+	Client *http.Client
+}
+
+// Identity is an authenticated workload mapped to an agent.
+type Identity struct {
+	Issuer         string    `json:"issuer"`
+	Subject        string    `json:"subject"`
+	Namespace      string    `json:"namespace,omitempty"`
+	ServiceAccount string    `json:"service_account,omitempty"`
+	Pod            string    `json:"pod,omitempty"`
+	AgentID        string    `json:"agent_id"`
+	OrgID          string    `json:"org_id,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// Verifier validates workload tokens. It is safe for concurrent use.
+type Verifier struct {
+	issuers  map[string]*issuer
+	bindings []Binding
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+type issuer struct {
+	Issuer
+	keys *keySet
+}
+
+// NewVerifier creates a verifier for the configured issuers. Key sets are
+// fetched lazily on first use.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if len(cfg.Issuers) == 0 {
+		return nil, errors.New("workload identity requires at least one issuer")
+	}
+	if cfg.Leeway <= 0 {
+		cfg.Leeway = time.Minute
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	v := &Verifier{
+		issuers:  make(map[string]*issuer, len(cfg.Issuers)),
+		bindings: cfg.Bindings,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+	for _, is := range cfg.Issuers {
+		if is.URL == "" {
+			return nil, errors.New("workload issuer url is required")
+		}
+		if len(is.Audiences) == 0 {
+			return nil, fmt.Errorf("workload issuer %q: at least one audience is required", is.URL)
+		}
+		if _, dup := v.issuers[is.URL]; dup {
+			return nil, fmt.Errorf("workload issuer %q is configured twice", is.URL)
+		}
+		v.issuers[is.URL] = &issuer{Issuer: is, keys: newKeySet(is, cfg.Client)}
+	}
+	for _, b := range cfg.Bindings {
+		if _, err := path.Match(b.Subject, ""); err != nil || b.Subject == "" {
+			return nil, fmt.Errorf("workload binding subject %q is not a valid pattern", b.Subject)
+		}
+		if b.Issuer != "" && v.issuers[b.Issuer] == nil {
+			return nil, fmt.Errorf("workload binding for %q references unknown issuer %q", b.Subject, b.Issuer)
+		}
+	}
+	return v, nil
+}
+
+// Verify validates token and returns the agent identity it is bound to.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	tok, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	is, ok := v.issuers[tok.claims.Issuer]
+	if !ok {
+		return nil, fmt.Errorf("%w: untrusted issuer %q", ErrInvalidToken, tok.claims.Issuer)
+	}
+	key, err := is.keys.key(ctx, tok.header.KeyID, tok.header.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := tok.verify(key); err != nil {
+		return nil, err
+	}
+	if err := v.validateClaims(is, &tok.claims); err != nil {
+		return nil, err
+	}
+	return v.bind(&tok.claims)
+}
+
+// IsJWT reports whether token has the shape of a signed JWT, so callers can
+// tell workload tokens apart from opaque API keys.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func (v *Verifier) validateClaims(is *issuer, c *claims) error {
+	now := v.now()
+	if c.Expiry == 0 {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(time.Unix(c.Expiry, 0).Add(v.leeway)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+	for _, want := range is.Audiences {
+		for _, got := range c.Audience {
+			if want == got {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: audience not accepted", ErrInvalidToken)
+}
+
+func (v *Verifier) bind(c *claims) (*Identity, error) {
+	id := &Identity{
+		Issuer:    c.Issuer,
+		Subject:   c.Subject,
+		ExpiresAt: time.Unix(c.Expiry, 0).UTC(),
+	}
+	if k := c.Kubernetes; k != nil {
+		id.Namespace = k.Namespace
+		id.ServiceAccount = k.ServiceAccount.Name
+		id.Pod = k.Pod.Name
+	}
+	for _, b := range v.bindings {
+		if b.Issuer != "" && b.Issuer != c.Issuer {
+			continue
+		}
+		if ok, _ := path.Match(b.Subject, c.Subject); !ok {
+			continue
+		}
+		id.AgentID = b.AgentID
+		if id.AgentID == "" {
+			id.AgentID = id.ServiceAccount
+		}
+		if id.AgentID == "" {
+			id.AgentID = c.Subject
+		}
+		id.OrgID = b.OrgID
+		return id, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnbound, c.Subject)
+}
@@ -0,0 +1,112 @@
+package workload_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/internal/workload"
+)
+
+func TestVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"jwks_uri": srv.URL + "/keys"})
+		case "/keys":
+			json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+				"kty": "RSA", "kid": "k1", "use": "sig",
+				"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sign := func(claims map[string]any) string {
+		h, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+		c, _ := json.Marshal(claims)
+		signed := b64(h) + "." + b64(c)
+		sum := sha256.Sum256([]byte(signed))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed + "." + b64(sig)
+	}
+	k8s := func(sub string, exp time.Time, aud string) map[string]any {
+		return map[string]any{
+			"iss": srv.URL,
+			"sub": sub,
+			"aud": []string{aud},
+			"exp": exp.Unix(),
+			"kubernetes.io": map[string]any{
+				"namespace":      "agents",
+				"serviceaccount": map[string]string{"name": "support-bot"},
+			},
+		}
+	}
+
+	v, err := workload.NewVerifier(workload.Config{
+		Issuers: []workload.Issuer{{URL: srv.URL, Audiences: []string{"agentguard"}}},
+		Bindings: []workload.Binding{
+			{Subject: "system:serviceaccount:agents:billing", AgentID: "billing-agent", OrgID: "acme"},
+			{Subject: "system:serviceaccount:agents:*"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	hour := time.Now().Add(time.Hour)
+
+	id, err := v.Verify(ctx, sign(k8s("system:serviceaccount:agents:support-bot", hour, "agentguard")))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if id.AgentID != "support-bot" || id.Namespace != "agents" || id.OrgID != "" {
+		t.Errorf("identity = %+v, want service account agent without org", id)
+	}
+
+	id, err = v.Verify(ctx, sign(k8s("system:serviceaccount:agents:billing", hour, "agentguard")))
+	if err != nil || id.AgentID != "billing-agent" || id.OrgID != "acme" {
+		t.Errorf("explicit binding = %+v, %v; want billing-agent in acme", id, err)
+	}
+
+	for name, tc := range map[string]struct {
+		token string
+		want  error
+	}{
+		"expired":        {sign(k8s("system:serviceaccount:agents:x", time.Now().Add(-time.Hour), "agentguard")), workload.ErrInvalidToken},
+		"wrong audience": {sign(k8s("system:serviceaccount:agents:x", hour, "other")), workload.ErrInvalidToken},
+		"unbound":        {sign(k8s("system:serviceaccount:other:x", hour, "agentguard")), workload.ErrUnbound},
+		"tampered":       {sign(k8s("system:serviceaccount:agents:x", hour, "agentguard")) + "A", workload.ErrInvalidToken},
+		"unsigned":       {b64([]byte(`{"alg":"none"}`)) + "." + b64([]byte(`{}`)) + ".", workload.ErrInvalidToken},
+	} {
+		if _, err := v.Verify(ctx, tc.token); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", name, err, tc.want)
+		}
+	}
+
+	if !workload.IsJWT("a.b.c") || workload.IsJWT("opaque-api-key") {
+		t.Error("IsJWT misclassified a token")
+	}
+}
@@ -0,0 +1,7 @@
+-- AgentGuard Control Families
+-- Migration: 002_control_families
+-- Description: Record each control's family so full OSCAL catalogs can be browsed by family
+
+ALTER TABLE controls ADD COLUMN IF NOT EXISTS family VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_controls_family ON controls(framework_id, family);
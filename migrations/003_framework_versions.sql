@@ -0,0 +1,15 @@
+-- AgentGuard Framework Versions
+-- Migration: 003_framework_versions
+-- Description: Keep superseded versions of a framework's catalog so assessments can be re-baselined after a standard update
+
+CREATE TABLE IF NOT EXISTS framework_versions (
+    framework_id VARCHAR(64) NOT NULL REFERENCES frameworks(id) ON DELETE CASCADE,
+    version VARCHAR(32) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    publisher VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    controls JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (framework_id, version)
+);
@@ -0,0 +1,13 @@
+-- AgentGuard Control Search
+-- Migration: 004_control_search
+-- Description: Full-text search over control titles and descriptions, and an index for filtering by layer
+
+ALTER TABLE controls ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(control_id, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_controls_search ON controls USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_controls_layers ON controls USING GIN (applicable_layers);
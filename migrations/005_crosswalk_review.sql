@@ -0,0 +1,13 @@
+-- AgentGuard Crosswalk Review
+-- Migration: 005_crosswalk_review
+-- Description: Review state for analyst-curated crosswalks; existing mappings are approved
+
+ALTER TABLE crosswalks
+    ADD COLUMN IF NOT EXISTS review_state TEXT NOT NULL DEFAULT 'approved',
+    ADD COLUMN IF NOT EXISTS reviewed_by  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS reviewed_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS approved_by  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS approved_at  TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_crosswalks_review_state ON crosswalks(review_state)
+    WHERE review_state <> 'approved';
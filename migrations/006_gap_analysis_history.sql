@@ -0,0 +1,10 @@
+-- AgentGuard Gap Analysis History
+-- Migration: 006_gap_analysis_history
+-- Description: Persist every gap analysis run; analyses may target loaded
+-- frameworks that are not in the frameworks table and may have no source
+
+ALTER TABLE gap_analyses DROP CONSTRAINT IF EXISTS gap_analyses_source_framework_id_fkey;
+ALTER TABLE gap_analyses DROP CONSTRAINT IF EXISTS gap_analyses_target_framework_id_fkey;
+ALTER TABLE gap_analyses ALTER COLUMN source_framework_id SET DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_gap_analyses_org_date ON gap_analyses(organization_id, analysis_date DESC);
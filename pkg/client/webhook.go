@@ -0,0 +1,187 @@
+// Package client holds helpers for programs that integrate with an
+// AgentGuard server.
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Signature headers. Every webhook AgentGuard sends carries a timestamp
+// and a random nonce; webhooks to destinations with a secret also carry a
+// signature over both and the body. Signed SDK hook requests carry the same
+// headers plus the signing agent.
+const (
+	SignatureHeader = "X-AgentGuard-Signature"
+	TimestampHeader = "X-AgentGuard-Timestamp"
+	NonceHeader     = "X-AgentGuard-Nonce"
+	AgentHeader     = "X-AgentGuard-Agent"
+)
+
+// DefaultWebhookTolerance is how far a webhook's timestamp may be from the
+// receiver's clock, in either direction.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// maxWebhookBody bounds the body VerifyRequest reads.
+const maxWebhookBody = 10 << 20
+
+// Verification errors, for webhooks and signed SDK hook requests alike.
+var (
+	ErrMissingSignature = errors.New("signature, timestamp or nonce missing")
+	ErrInvalidSignature = errors.New("signature does not match")
+	ErrStaleTimestamp   = errors.New("timestamp outside tolerance")
+	ErrReplayedNonce    = errors.New("nonce already seen")
+)
+
+// SignWebhook returns the signature header value for a webhook: "v1="
+// followed by the hex HMAC-SHA-256, keyed by secret, of the timestamp in
+// Unix seconds, the nonce and the body joined by dots.
+func SignWebhook(secret []byte, timestamp int64, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write([]byte(nonce))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest signs an SDK hook request as agentID with one of the agent's
+// signing keys. body must be the exact bytes sent as the request body.
+// During a key rotation, signing with the new secret is enough: the server
+// accepts every active key.
+func SignRequest(r *http.Request, agentID, secret string, body []byte) error {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Errorf("generating request nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(b[:])
+	timestamp := time.Now().Unix()
+	r.Header.Set(AgentHeader, agentID)
+	r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(NonceHeader, nonce)
+	r.Header.Set(SignatureHeader, SignWebhook([]byte(secret), timestamp, nonce, body))
+	return nil
+}
+
+// WebhookVerifier checks that webhooks were signed with a destination's
+// secret, are recent, and have not been delivered before. It remembers
+// nonces for as long as their timestamps are within tolerance, so a
+// captured webhook cannot be replayed. It is safe for concurrent use.
+type WebhookVerifier struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	order *list.List // of *seenNonce, soonest forgotten first
+	seen  map[string]*list.Element
+}
+
+// seenNonce is a nonce, remembered until it can no longer be replayed
+// within tolerance.
+type seenNonce struct {
+	nonce  string
+	forget time.Time
+}
+
+// NewWebhookVerifier creates a verifier for the secret configured for this
+// destination. tolerance <= 0 defaults to DefaultWebhookTolerance.
+func NewWebhookVerifier(secret string, tolerance time.Duration) *WebhookVerifier {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	return &WebhookVerifier{
+		secret:    []byte(secret),
+		tolerance: tolerance,
+		now:       time.Now,
+		order:     list.New(),
+		seen:      make(map[string]*list.Element),
+	}
+}
+
+// Verify checks a webhook's headers against its raw body. A webhook that
+// fails verification does not use up its nonce.
+func (v *WebhookVerifier) Verify(header http.Header, body []byte) error {
+	sig, ts, nonce := header.Get(SignatureHeader), header.Get(TimestampHeader), header.Get(NonceHeader)
+	if sig == "" || ts == "" || nonce == "" {
+		return ErrMissingSignature
+	}
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrMissingSignature, ts)
+	}
+	want := SignWebhook(v.secret, timestamp, nonce, body)
+	if !signatureMatches(sig, want) {
+		return ErrInvalidSignature
+	}
+	sent := time.Unix(timestamp, 0)
+	now := v.now()
+	if d := now.Sub(sent); d > v.tolerance || d < -v.tolerance {
+		return ErrStaleTimestamp
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for el := v.order.Front(); el != nil && now.After(el.Value.(*seenNonce).forget); el = v.order.Front() {
+		delete(v.seen, el.Value.(*seenNonce).nonce)
+		v.order.Remove(el)
+	}
+	if _, ok := v.seen[nonce]; ok {
+		return ErrReplayedNonce
+	}
+	v.remember(nonce, sent.Add(v.tolerance))
+	return nil
+}
+
+// remember records nonce until forget, keeping v.order sorted by when
+// nonces can be forgotten. Timestamps arrive nearly in order, so the new
+// nonce usually goes at the back. Callers must hold v.mu.
+func (v *WebhookVerifier) remember(nonce string, forget time.Time) {
+	n := &seenNonce{nonce: nonce, forget: forget}
+	for el := v.order.Back(); el != nil; el = el.Prev() {
+		if !el.Value.(*seenNonce).forget.After(forget) {
+			v.seen[nonce] = v.order.InsertAfter(n, el)
+			return
+		}
+	}
+	v.seen[nonce] = v.order.PushFront(n)
+}
+
+// VerifyRequest reads and verifies a webhook request, returning its body.
+// The request body is replaced so handlers can read it again.
+func (v *WebhookVerifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		return nil, fmt.Errorf("reading webhook body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := v.Verify(r.Header, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// signatureMatches reports whether any comma-separated signature in header
+// equals want, comparing in constant time.
+func signatureMatches(header, want string) bool {
+	match := false
+	for _, sig := range strings.Split(header, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(sig)), []byte(want)) {
+			match = true
+		}
+	}
+	return match
+}
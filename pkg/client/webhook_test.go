@@ -0,0 +1,97 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/pkg/client"
+)
+
+func signedHeader(secret string, sent time.Time, nonce string, body []byte) http.Header {
+	h := http.Header{}
+	h.Set(client.TimestampHeader, strconv.FormatInt(sent.Unix(), 10))
+	h.Set(client.NonceHeader, nonce)
+	h.Set(client.SignatureHeader, client.SignWebhook([]byte(secret), sent.Unix(), nonce, body))
+	return h
+}
+
+func TestWebhookVerifier(t *testing.T) {
+	const secret = "0123456789abcdef"
+	body := []byte(`{"org_id":"acme"}`)
+	now := time.Now()
+	v := client.NewWebhookVerifier(secret, time.Minute)
+
+	if err := v.Verify(signedHeader(secret, now, "n1", body), body); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := v.Verify(signedHeader(secret, now, "n1", body), body); !errors.Is(err, client.ErrReplayedNonce) {
+		t.Errorf("replayed Verify() error = %v, want ErrReplayedNonce", err)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		want   error
+	}{
+		{"tampered body", signedHeader(secret, now, "n2", body), []byte(`{"org_id":"evil"}`), client.ErrInvalidSignature},
+		{"wrong secret", signedHeader("fedcba9876543210", now, "n3", body), body, client.ErrInvalidSignature},
+		{"stale", signedHeader(secret, now.Add(-2*time.Minute), "n4", body), body, client.ErrStaleTimestamp},
+		{"future", signedHeader(secret, now.Add(2*time.Minute), "n5", body), body, client.ErrStaleTimestamp},
+		{"unsigned", http.Header{}, body, client.ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Verify(tt.header, tt.body); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// A rotated secret: the sender lists signatures under both secrets.
+	h := signedHeader(secret, now, "n6", body)
+	h.Set(client.SignatureHeader, client.SignWebhook([]byte("old-secret-0123456"), now.Unix(), "n6", body)+", "+h.Get(client.SignatureHeader))
+	if err := v.Verify(h, body); err != nil {
+		t.Errorf("Verify() with two signatures error = %v", err)
+	}
+
+	// Nonces are remembered whatever order their timestamps arrive in.
+	sent := map[string]time.Time{"o1": now.Add(30 * time.Second), "o2": now.Add(-30 * time.Second), "o3": now}
+	for _, nonce := range []string{"o1", "o2", "o3"} {
+		if err := v.Verify(signedHeader(secret, sent[nonce], nonce, body), body); err != nil {
+			t.Fatalf("Verify(%s) error = %v", nonce, err)
+		}
+	}
+	for _, nonce := range []string{"o1", "o2", "o3"} {
+		if err := v.Verify(signedHeader(secret, sent[nonce], nonce, body), body); !errors.Is(err, client.ErrReplayedNonce) {
+			t.Errorf("replayed Verify(%s) error = %v, want ErrReplayedNonce", nonce, err)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+	r.Header = signedHeader(secret, now, "n7", body)
+	got, err := v.VerifyRequest(r)
+	if err != nil || !bytes.Equal(got, body) {
+		t.Errorf("VerifyRequest() = %s, %v", got, err)
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	const secret = "0123456789abcdef"
+	body := []byte(`{"agent_id":"agent-1"}`)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/sdk/post-invoke", bytes.NewReader(body))
+	if err := client.SignRequest(r, "agent-1", secret, body); err != nil {
+		t.Fatalf("SignRequest() error = %v", err)
+	}
+	if r.Header.Get(client.AgentHeader) != "agent-1" {
+		t.Errorf("%s = %q", client.AgentHeader, r.Header.Get(client.AgentHeader))
+	}
+	if err := client.NewWebhookVerifier(secret, 0).Verify(r.Header, body); err != nil {
+		t.Errorf("Verify() of a signed request error = %v", err)
+	}
+}
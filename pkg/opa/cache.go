@@ -0,0 +1,113 @@
+package opa
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DecisionCache remembers recent decisions by input so a caller that cannot
+// wait for evaluation can fall back to the last answer for the same call.
+// It is an LRU bounded by size, with entries expiring after ttl.
+type DecisionCache struct {
+	ttl   time.Duration
+	max   int
+	now   func() time.Time
+	mu    sync.Mutex
+	ll    *list.List
+	byKey map[string]*list.Element
+}
+
+type cachedDecision struct {
+	key      string
+	decision Decision
+	at       time.Time
+}
+
+// NewDecisionCache creates a cache. max <= 0 defaults to 10000 entries.
+func NewDecisionCache(ttl time.Duration, max int) *DecisionCache {
+	if max <= 0 {
+		max = 10000
+	}
+	return &DecisionCache{ttl: ttl, max: max, now: time.Now, ll: list.New(), byKey: make(map[string]*list.Element)}
+}
+
+// DecisionKey derives a cache key from an evaluation input, ignoring the
+// per-request timestamp and client IP. Inputs that cannot be encoded yield an
+// empty key, which is never cached.
+func DecisionKey(input *EvaluationInput) string {
+	keyed := *input
+	if input.Request != nil {
+		req := *input.Request
+		req.Timestamp, req.IP = time.Time{}, ""
+		keyed.Request = &req
+	}
+	b, err := json.Marshal(&keyed)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Put stores a decision. It is a no-op on a nil cache.
+func (c *DecisionCache) Put(key string, d *Decision) {
+	if c == nil || key == "" || d == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.byKey[key]; ok {
+		c.ll.Remove(el)
+	}
+	c.byKey[key] = c.ll.PushFront(&cachedDecision{key: key, decision: d.clone(), at: c.now()})
+	for c.ll.Len() > c.max {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.byKey, oldest.Value.(*cachedDecision).key)
+	}
+}
+
+// Get returns a copy of an unexpired decision and its age. The copy's
+// Metadata is never nil.
+func (c *DecisionCache) Get(key string) (*Decision, time.Duration, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.byKey[key]
+	if !ok {
+		return nil, 0, false
+	}
+	entry := el.Value.(*cachedDecision)
+	age := c.now().Sub(entry.at)
+	if c.ttl > 0 && age > c.ttl {
+		c.ll.Remove(el)
+		delete(c.byKey, key)
+		return nil, 0, false
+	}
+	c.ll.MoveToFront(el)
+	d := entry.decision.clone()
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]any)
+	}
+	return &d, age, true
+}
+
+// clone copies d so cached entries are not changed through returned values.
+func (d *Decision) clone() Decision {
+	out := *d
+	out.Reasons = append([]string(nil), d.Reasons...)
+	out.Violations = append([]Violation(nil), d.Violations...)
+	if d.Metadata != nil {
+		out.Metadata = make(map[string]any, len(d.Metadata))
+		for k, v := range d.Metadata {
+			out.Metadata[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package opa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+func TestDecisionCache(t *testing.T) {
+	now := time.Now()
+	c := opa.NewDecisionCache(50*time.Millisecond, 2)
+
+	input := &opa.EvaluationInput{
+		Agent:   opa.AgentContext{ID: "agent-1"},
+		Tool:    &opa.ToolContext{Name: "search"},
+		Request: &opa.RequestContext{SessionID: "s1", Timestamp: now, IP: "10.0.0.1"},
+	}
+	key := opa.DecisionKey(input)
+
+	later := *input
+	req := *input.Request
+	req.Timestamp, req.IP = now.Add(time.Second), "10.0.0.2"
+	later.Request = &req
+	if opa.DecisionKey(&later) != key {
+		t.Fatal("key changed with request timestamp and IP")
+	}
+
+	c.Put(key, &opa.Decision{Allow: true, Metadata: map[string]any{"a": 1}})
+	got, _, ok := c.Get(key)
+	if !ok || !got.Allow {
+		t.Fatalf("Get = %+v, %v; want cached allow", got, ok)
+	}
+	got.Metadata["a"] = 2
+	if again, _, _ := c.Get(key); again.Metadata["a"] != 1 {
+		t.Error("mutating a returned decision changed the cache")
+	}
+
+	c.Put("k2", &opa.Decision{})
+	c.Put("k3", &opa.Decision{})
+	if _, _, ok := c.Get(key); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, _, ok := c.Get("k3"); ok {
+		t.Error("expired entry returned")
+	}
+
+	var nilCache *opa.DecisionCache
+	nilCache.Put(key, &opa.Decision{})
+	if _, _, ok := nilCache.Get(key); ok {
+		t.Error("nil cache returned a decision")
+	}
+}
@@ -0,0 +1,41 @@
+package opa
+
+// DelegationContext describes the agents that delegated the call being
+// evaluated, so their restrictions carry over to the delegate.
+type DelegationContext struct {
+	GroupID string `json:"group_id,omitempty"`
+	// Callers lists the delegating agents, from the agent that started
+	// the chain to the one that delegated directly.
+	Callers []AgentContext `json:"callers"`
+}
+
+// AsCaller returns a copy of the input evaluated as if caller made the call
+// itself, without delegation context.
+func (in *EvaluationInput) AsCaller(caller AgentContext) *EvaluationInput {
+	out := *in
+	out.Agent = caller
+	out.Delegation = nil
+	return &out
+}
+
+// MergeDelegated folds a caller's decision into the delegate's: the call is
+// denied if the caller could not make it, with the caller's reasons and
+// violations attributed to it.
+func (d *Decision) MergeDelegated(caller string, cd *Decision) {
+	if cd.Allow {
+		return
+	}
+	d.Allow = false
+	for _, r := range cd.Reasons {
+		d.Reasons = append(d.Reasons, "delegated by "+caller+": "+r)
+	}
+	if len(cd.Reasons) == 0 {
+		d.Reasons = append(d.Reasons, "delegated by "+caller+": denied")
+	}
+	d.Violations = append(d.Violations, cd.Violations...)
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]any)
+	}
+	denied, _ := d.Metadata["denied_callers"].([]string)
+	d.Metadata["denied_callers"] = append(denied, caller)
+}
@@ -0,0 +1,47 @@
+package opa_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+func TestAsCaller(t *testing.T) {
+	in := &opa.EvaluationInput{
+		Agent: opa.AgentContext{ID: "executor"},
+		Tool:  &opa.ToolContext{Name: "python_repl"},
+		Delegation: &opa.DelegationContext{
+			Callers: []opa.AgentContext{{ID: "planner"}},
+		},
+	}
+	out := in.AsCaller(in.Delegation.Callers[0])
+	if out.Agent.ID != "planner" || out.Tool.Name != "python_repl" || out.Delegation != nil {
+		t.Errorf("AsCaller = %+v, want planner calling python_repl without delegation", out)
+	}
+	if in.Agent.ID != "executor" || in.Delegation == nil {
+		t.Error("AsCaller modified its input")
+	}
+}
+
+func TestMergeDelegated(t *testing.T) {
+	d := &opa.Decision{Allow: true}
+	d.MergeDelegated("analyst", &opa.Decision{Allow: true})
+	if !d.Allow {
+		t.Fatal("an allowing caller denied the call")
+	}
+
+	d.MergeDelegated("planner", &opa.Decision{
+		Reasons:    []string{"tool not permitted"},
+		Violations: []opa.Violation{{Policy: "tools", Rule: "allowlist"}},
+	})
+	if d.Allow {
+		t.Fatal("a denying caller did not deny the call")
+	}
+	if want := []string{"delegated by planner: tool not permitted"}; !reflect.DeepEqual(d.Reasons, want) {
+		t.Errorf("reasons = %v, want %v", d.Reasons, want)
+	}
+	if len(d.Violations) != 1 || !reflect.DeepEqual(d.Metadata["denied_callers"], []string{"planner"}) {
+		t.Errorf("violations = %v, metadata = %v", d.Violations, d.Metadata)
+	}
+}
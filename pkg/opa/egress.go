@@ -0,0 +1,80 @@
+package opa
+
+// URLCategoriesPath is the data path holding URL categories: an object
+// with "domains", mapping domains to categories, and "default_blocked",
+// the categories blocked for agents without their own list.
+const URLCategoriesPath = "url_categories"
+
+// URLCategoryData builds the data published at URLCategoriesPath.
+func URLCategoryData(domains map[string]string, defaultBlocked []string) map[string]any {
+	d := make(map[string]any, len(domains))
+	for domain, category := range domains {
+		d[domain] = category
+	}
+	blocked := make([]any, len(defaultBlocked))
+	for i, c := range defaultBlocked {
+		blocked[i] = c
+	}
+	return map[string]any{"domains": d, "default_blocked": blocked}
+}
+
+// BaseEgressPolicy is the default Rego policy for web-browsing egress. It
+// denies tool calls whose url parameter falls in a blocked URL category.
+// Agents listed in data.policies.blocked_url_categories get their own list
+// of blocked categories, which may be empty; others get the defaults.
+const BaseEgressPolicy = `
+package agentguard.egress
+
+import future.keywords.in
+
+default allow = false
+
+allow {
+    not url_blocked
+}
+
+# Host of the URL the tool reaches, without scheme, credentials or port
+host := lower(trim_suffix(parts[1], ".")) {
+    parts := regex.find_all_string_submatch_n(
+        "^(?:[a-zA-Z][a-zA-Z0-9+.-]*://)?(?:[^@/?#]*@)?([^:/?#]+)",
+        input.tool.parameters.url,
+        1
+    )[0]
+}
+
+# Category of the closest listed domain: the host itself, then its parents
+url_category := data.url_categories.domains[listed[0]] {
+    labels := split(host, ".")
+    suffixes := [s | some i, _ in labels; s := concat(".", array.slice(labels, i, count(labels)))]
+    listed := [s | some s in suffixes; data.url_categories.domains[s]]
+}
+
+# Categories blocked for this agent
+blocked_categories = categories {
+    categories := data.policies.blocked_url_categories[input.agent.id]
+} else = data.url_categories.default_blocked {
+    true
+}
+
+url_blocked {
+    url_category in blocked_categories
+}
+
+denial_reasons[reason] {
+    url_blocked
+    reason := sprintf(
+        "URL host '%s' is in blocked category '%s' for agent '%s'",
+        [host, url_category, input.agent.id]
+    )
+}
+
+violations[v] {
+    url_blocked
+    v := {
+        "policy": "egress",
+        "rule": "url_category_blocked",
+        "description": sprintf("Tool '%s' reached %s site '%s'", [input.tool.name, url_category, host]),
+        "severity": "high",
+    }
+}
+`
@@ -0,0 +1,58 @@
+package opa_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agentguard/agentguard/pkg/opa"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
+)
+
+func TestBaseEgressPolicy(t *testing.T) {
+	data := map[string]any{
+		opa.URLCategoriesPath: opa.URLCategoryData(map[string]string{
+			"pastebin.com":    "paste_sites",
+			"reuters.com":     "news",
+			"mail.google.com": "webmail",
+		}, []string{"paste_sites", "webmail"}),
+		"policies": map[string]any{
+			"blocked_url_categories": map[string]any{"researcher": []any{"news"}},
+		},
+	}
+	query, err := rego.New(
+		rego.Query("data.agentguard.egress"),
+		rego.Module("egress.rego", opa.BaseEgressPolicy),
+		rego.Store(inmem.NewFromObject(data)),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		agent, url string
+		allow      bool
+	}{
+		{"assistant", "https://pastebin.com/raw/abc", false},
+		{"assistant", "https://user@MAIL.google.com:443/mail/u/0", false},
+		{"assistant", "https://www.reuters.com/world", true},
+		{"assistant", "https://google.com/search?q=x", true},
+		{"assistant", "", true},
+		// Agents with their own list get only its categories blocked.
+		{"researcher", "https://pastebin.com/raw/abc", true},
+		{"researcher", "reuters.com/world", false},
+	} {
+		input := map[string]any{
+			"agent": map[string]any{"id": tt.agent},
+			"tool":  map[string]any{"name": "browse", "parameters": map[string]any{"url": tt.url}},
+		}
+		rs, err := query.Eval(context.Background(), rego.EvalInput(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := rs[0].Expressions[0].Value.(map[string]any)
+		if allow := result["allow"] == true; allow != tt.allow {
+			t.Errorf("%s %q: allow = %v, want %v (%v)", tt.agent, tt.url, allow, tt.allow, result["denial_reasons"])
+		}
+	}
+}
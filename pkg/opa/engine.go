@@ -36,6 +36,10 @@ type Decision struct {
 	Violations []Violation    `json:"violations,omitempty"`
 	Metadata   map[string]any `json:"metadata,omitempty"`
 	EvalTimeUs int64          `json:"eval_time_us"`
+	// Degraded is set when the decision did not come from a completed
+	// evaluation, e.g. a cached or default answer served after the latency
+	// budget ran out.
+	Degraded bool `json:"degraded,omitempty"`
 }
 
 // Violation represents a policy violation.
@@ -48,11 +52,12 @@ type Violation struct {
 
 // EvaluationInput is the input to policy evaluation.
 type EvaluationInput struct {
-	Agent       AgentContext      `json:"agent"`
-	Tool        *ToolContext      `json:"tool,omitempty"`
-	Data        *DataContext      `json:"data,omitempty"`
-	Request     *RequestContext   `json:"request,omitempty"`
-	Environment map[string]string `json:"environment,omitempty"`
+	Agent       AgentContext       `json:"agent"`
+	Tool        *ToolContext       `json:"tool,omitempty"`
+	Data        *DataContext       `json:"data,omitempty"`
+	Request     *RequestContext    `json:"request,omitempty"`
+	Environment map[string]string  `json:"environment,omitempty"`
+	Delegation  *DelegationContext `json:"delegation,omitempty"`
 }
 
 // AgentContext provides agent information for policy evaluation.
@@ -62,14 +67,16 @@ type AgentContext struct {
 	Team         string   `json:"team"`
 	Environment  string   `json:"environment"`
 	Capabilities []string `json:"capabilities"`
+	RiskLevel    string   `json:"risk_level,omitempty"` // low, medium, high, critical
 }
 
 // ToolContext provides tool invocation information.
 type ToolContext struct {
-	Name       string         `json:"name"`
-	Category   string         `json:"category"`
-	Parameters map[string]any `json:"parameters"`
-	External   bool           `json:"external"`
+	Name       string          `json:"name"`
+	Category   string          `json:"category"`
+	Parameters map[string]any  `json:"parameters"`
+	External   bool            `json:"external"`
+	Sandbox    *SandboxContext `json:"sandbox,omitempty"`
 }
 
 // DataContext provides data flow information.
@@ -86,8 +93,18 @@ type RequestContext struct {
 	SessionID string    `json:"session_id"`
 	Timestamp time.Time `json:"timestamp"`
 	IP        string    `json:"ip,omitempty"`
+	// PromptHash identifies the prompt that led to this request, checked
+	// against data.blocked_prompt_hashes.
+	PromptHash string `json:"prompt_hash,omitempty"`
+	// Prompt is the raw prompt, sent instead of PromptHash when the server
+	// computes salted hashes. It is replaced by its hash before evaluation.
+	Prompt any `json:"prompt,omitempty"`
 }
 
+// BlockedPromptHashesPath is the data path holding blocked prompt hashes,
+// an object keyed by hash.
+const BlockedPromptHashesPath = "blocked_prompt_hashes"
+
 // NewEngine creates a new policy engine.
 func NewEngine() (*Engine, error) {
 	store := inmem.New()
@@ -103,13 +120,7 @@ func (e *Engine) LoadPolicies(ctx context.Context, paths []string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	r := rego.New(
-		rego.Query("data.agentguard"),
-		rego.Store(e.store),
-		rego.Load(paths, nil),
-	)
-
-	pq, err := r.PrepareForEval(ctx)
+	pq, err := e.prepare(ctx, rego.Load(paths, nil))
 	if err != nil {
 		return fmt.Errorf("failed to prepare policy: %w", err)
 	}
@@ -124,13 +135,7 @@ func (e *Engine) LoadPolicyBundle(ctx context.Context, bundlePath string) error
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	r := rego.New(
-		rego.Query("data.agentguard"),
-		rego.Store(e.store),
-		rego.LoadBundle(bundlePath),
-	)
-
-	pq, err := r.PrepareForEval(ctx)
+	pq, err := e.prepare(ctx, rego.LoadBundle(bundlePath))
 	if err != nil {
 		return fmt.Errorf("failed to load bundle: %w", err)
 	}
@@ -140,6 +145,31 @@ func (e *Engine) LoadPolicyBundle(ctx context.Context, bundlePath string) error
 	return nil
 }
 
+// prepare compiles the default query over the engine's store with load,
+// which loads policies from files. Loading writes the policies to the
+// store, so it runs in a write transaction.
+func (e *Engine) prepare(ctx context.Context, load func(*rego.Rego)) (rego.PreparedEvalQuery, error) {
+	txn, err := e.store.NewTransaction(ctx, storage.WriteParams)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("starting storage transaction: %w", err)
+	}
+	pq, err := rego.New(
+		rego.Query("data.agentguard"),
+		rego.Store(e.store),
+		rego.Transaction(txn),
+		load,
+	).PrepareForEval(ctx)
+	if err != nil {
+		e.store.Abort(ctx, txn)
+		return rego.PreparedEvalQuery{}, err
+	}
+	if err := e.store.Commit(ctx, txn); err != nil {
+		e.store.Abort(ctx, txn)
+		return rego.PreparedEvalQuery{}, fmt.Errorf("committing storage transaction: %w", err)
+	}
+	return pq, nil
+}
+
 // UpdateData updates the policy data store using the OPA storage transaction API.
 func (e *Engine) UpdateData(ctx context.Context, path string, data any) error {
 	e.mu.Lock()
@@ -285,6 +315,7 @@ allow {
     not tool_blocked
     parameters_valid
     not rate_limit_exceeded
+    not prompt_blocked
 }
 
 # Tool is allowed if explicitly listed for this agent
@@ -302,6 +333,11 @@ tool_blocked {
     input.tool.name in data.policies.blocked_tools[input.agent.id]
 }
 
+# Deny if the originating prompt has been blocked (see prompt hash registry)
+prompt_blocked {
+    data.blocked_prompt_hashes[input.request.prompt_hash]
+}
+
 # Parameters are valid if no forbidden patterns found
 parameters_valid {
     not contains_forbidden_pattern
@@ -334,6 +370,11 @@ denial_reasons[reason] {
     reason := sprintf("Invalid parameters for tool '%s'", [input.tool.name])
 }
 
+denial_reasons[reason] {
+    prompt_blocked
+    reason := sprintf("Prompt hash '%s' is blocked", [input.request.prompt_hash])
+}
+
 denial_reasons[reason] {
     rate_limit_exceeded
     reason := sprintf("Rate limit exceeded for tool '%s'", [input.tool.name])
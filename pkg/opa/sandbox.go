@@ -0,0 +1,64 @@
+package opa
+
+import (
+	"slices"
+	"strings"
+)
+
+// SandboxContext is the sandbox a code execution tool declares it runs in.
+type SandboxContext struct {
+	// Profile names the sandbox profile, e.g. "isolated".
+	Profile string `json:"profile"`
+}
+
+// execCategories are tool categories that run code.
+var execCategories = []string{"code_execution", "code_exec", "code_interpreter"}
+
+// execWords mark a tool that runs code by its name.
+var execWords = []string{"exec", "execute", "shell", "bash", "python", "repl", "interpreter", "run_code", "notebook"}
+
+// ExecutesCode reports whether the tool runs code, by its category or name.
+func (t *ToolContext) ExecutesCode() bool {
+	if t == nil {
+		return false
+	}
+	if slices.Contains(execCategories, strings.ToLower(t.Category)) {
+		return true
+	}
+	name := strings.ToLower(t.Name)
+	if slices.Contains(execWords, name) {
+		return true
+	}
+	for _, w := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if slices.Contains(execWords, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireSandbox denies a call to a code execution tool that declares no
+// sandbox profile, or one not among profiles. Calls to other tools are left
+// as decided.
+func (d *Decision) RequireSandbox(tool *ToolContext, profiles []string) {
+	if !tool.ExecutesCode() {
+		return
+	}
+	var reason string
+	switch {
+	case tool.Sandbox == nil || tool.Sandbox.Profile == "":
+		reason = "code execution tool '" + tool.Name + "' declares no sandbox profile"
+	case !slices.Contains(profiles, tool.Sandbox.Profile):
+		reason = "code execution tool '" + tool.Name + "' declares unknown sandbox profile '" + tool.Sandbox.Profile + "'"
+	default:
+		return
+	}
+	d.Allow = false
+	d.Reasons = append(d.Reasons, reason)
+	d.Violations = append(d.Violations, Violation{
+		Policy:      "sandbox",
+		Rule:        "sandbox_required",
+		Description: reason,
+		Severity:    "high",
+	})
+}
@@ -0,0 +1,31 @@
+package opa_test
+
+import (
+	"testing"
+
+	"github.com/agentguard/agentguard/pkg/opa"
+)
+
+func TestRequireSandbox(t *testing.T) {
+	profiles := []string{"isolated", "workspace"}
+	for _, tt := range []struct {
+		name  string
+		tool  *opa.ToolContext
+		allow bool
+	}{
+		{"no sandbox", &opa.ToolContext{Name: "python_repl"}, false},
+		{"unknown profile", &opa.ToolContext{Name: "exec", Sandbox: &opa.SandboxContext{Profile: "host"}}, false},
+		{"known profile", &opa.ToolContext{Name: "run_shell", Sandbox: &opa.SandboxContext{Profile: "isolated"}}, true},
+		{"exec category", &opa.ToolContext{Name: "analyze", Category: "code_execution"}, false},
+		{"not exec", &opa.ToolContext{Name: "send_email", Category: "email"}, true},
+	} {
+		d := &opa.Decision{Allow: true}
+		d.RequireSandbox(tt.tool, profiles)
+		if d.Allow != tt.allow {
+			t.Errorf("%s: allow = %v, want %v", tt.name, d.Allow, tt.allow)
+		}
+		if !tt.allow && (len(d.Violations) != 1 || d.Violations[0].Rule != "sandbox_required") {
+			t.Errorf("%s: violations = %+v, want sandbox_required", tt.name, d.Violations)
+		}
+	}
+}
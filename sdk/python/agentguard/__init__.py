@@ -14,7 +14,9 @@ Usage with LangChain:
 
 import asyncio
 import hashlib
+import hmac
 import json
+import secrets
 import time
 import uuid
 from abc import ABC, abstractmethod
@@ -60,6 +62,9 @@ class PolicyDecision:
     violations: List[Dict[str, Any]] = field(default_factory=list)
     eval_time_us: int = 0
     metadata: Dict[str, Any] = field(default_factory=dict)
+    # True when the answer is a cached or default decision served because
+    # evaluation exceeded the latency budget, or a local fallback.
+    degraded: bool = False
 
 
 @dataclass
@@ -108,6 +113,14 @@ class Trace:
     spans: List[Span] = field(default_factory=list)
     security_signals: List[SecuritySignal] = field(default_factory=list)
     metadata: Dict[str, Any] = field(default_factory=dict)
+    # Supervisor span that delegated this invocation, as
+    # {"trace_id": ..., "span_id": ...}; links worker traces into a forest.
+    parent: Optional[Dict[str, str]] = None
+
+    def delegation_link(self, span: Optional[Span] = None) -> Dict[str, str]:
+        """Link for a worker agent invoked from span (default: the last span)."""
+        span = span or self.spans[-1]
+        return {"trace_id": self.trace_id, "span_id": span.span_id}
 
 
 class AgentGuardClient:
@@ -118,10 +131,28 @@ class AgentGuardClient:
         api_key: str,
         base_url: str = "http://localhost:8080",
         timeout: float = 30.0,
+        decision_budget_ms: int = 100,
+        network_allowance_ms: int = 250,
+        fallback_allow: bool = False,
+        signing_secret: Optional[str] = None,
     ):
+        """
+        decision_budget_ms is sent to the server as the pre-invoke deadline.
+        If no response arrives within the budget plus network_allowance_ms,
+        pre_invoke returns a local degraded decision that allows the call
+        only when fallback_allow is set (fail-open).
+
+        signing_secret is the agent's request signing key, issued by
+        POST /api/v1/agents/{id}/signing-keys. When set, pre_invoke and
+        post_invoke requests are HMAC-signed as the agent they name.
+        """
         self.api_key = api_key
         self.base_url = base_url.rstrip("/")
         self.timeout = timeout
+        self.decision_budget_ms = decision_budget_ms
+        self.network_allowance_ms = network_allowance_ms
+        self.fallback_allow = fallback_allow
+        self.signing_secret = signing_secret
         self._client: Optional[httpx.AsyncClient] = None
     
     async def _get_client(self) -> httpx.AsyncClient:
@@ -142,6 +173,28 @@ class AgentGuardClient:
             await self._client.aclose()
             self._client = None
     
+    def _sdk_request(self, agent_id: str, payload: Dict[str, Any]) -> Dict[str, Any]:
+        """Encode an SDK hook body, signing it when a secret is set.
+
+        The signature is "v1=" and the hex HMAC-SHA-256 of the timestamp,
+        nonce and exact body bytes joined by dots, as in pkg/client.
+        """
+        body = json.dumps(payload, separators=(",", ":")).encode()
+        headers: Dict[str, str] = {}
+        if self.signing_secret:
+            timestamp = str(int(time.time()))
+            nonce = secrets.token_hex(16)
+            mac = hmac.new(self.signing_secret.encode(), digestmod=hashlib.sha256)
+            mac.update(f"{timestamp}.{nonce}.".encode())
+            mac.update(body)
+            headers = {
+                "X-AgentGuard-Agent": agent_id,
+                "X-AgentGuard-Timestamp": timestamp,
+                "X-AgentGuard-Nonce": nonce,
+                "X-AgentGuard-Signature": "v1=" + mac.hexdigest(),
+            }
+        return {"content": body, "headers": headers}
+    
     async def evaluate_policy(
         self,
         agent_id: str,
@@ -191,6 +244,8 @@ class AgentGuardClient:
             "security_signals": [self._signal_to_dict(s) for s in trace.security_signals],
             "metadata": trace.metadata,
         }
+        if trace.parent:
+            payload["parent"] = trace.parent
         
         response = await client.post("/api/v1/observe/traces", json=payload)
         response.raise_for_status()
@@ -215,14 +270,42 @@ class AgentGuardClient:
             "timestamp": datetime.utcnow().isoformat(),
         }
         
-        response = await client.post("/api/v1/sdk/pre-invoke", json=payload)
-        response.raise_for_status()
-        
+        deadline = (self.decision_budget_ms + self.network_allowance_ms) / 1000
+        request = self._sdk_request(agent_id, payload)
+        request["headers"]["X-AgentGuard-Budget-Ms"] = str(self.decision_budget_ms)
+        try:
+            response = await client.post(
+                "/api/v1/sdk/pre-invoke",
+                timeout=deadline,
+                **request,
+            )
+        except (httpx.TimeoutException, httpx.TransportError) as exc:
+            return self._local_fallback(f"pre-invoke unavailable: {exc.__class__.__name__}")
+
+        # 403 carries a deny decision body; other errors are unexpected.
+        if response.status_code != 403:
+            response.raise_for_status()
+
         data = response.json()
+        allow = data.get("allow", False)
         return PolicyDecision(
-            allow=data.get("allow", True),
-            decision=DecisionType(data.get("decision", "allow")),
+            allow=allow,
+            decision=DecisionType.ALLOW if allow else DecisionType.DENY,
             reasons=data.get("reasons", []),
+            violations=data.get("violations", []),
+            eval_time_us=data.get("eval_time_us", 0),
+            metadata=data.get("metadata", {}),
+            degraded=data.get("degraded", False),
+        )
+
+    def _local_fallback(self, reason: str) -> PolicyDecision:
+        """Decision used when the server cannot answer within the budget."""
+        return PolicyDecision(
+            allow=self.fallback_allow,
+            decision=DecisionType.ALLOW if self.fallback_allow else DecisionType.DENY,
+            reasons=[reason],
+            metadata={"degraded_source": "local_fallback"},
+            degraded=True,
         )
     
     async def post_invoke(
@@ -232,8 +315,15 @@ class AgentGuardClient:
         result: Any,
         duration_ms: int,
         session_id: str,
-    ) -> None:
-        """Post-invocation hook for observability."""
+        output: Any = None,
+    ) -> Dict[str, Any]:
+        """Post-invocation hook for observability.
+
+        When output is given, such as the function call arguments produced
+        by the model, it is validated against the JSON Schema registered for
+        the agent and tool. The returned body's "allow" is False when the
+        output failed a blocking schema and must be discarded.
+        """
         client = await self._get_client()
         
         payload = {
@@ -244,9 +334,16 @@ class AgentGuardClient:
             "session_id": session_id,
             "timestamp": datetime.utcnow().isoformat(),
         }
+        if output is not None:
+            payload["output"] = output
         
-        response = await client.post("/api/v1/sdk/post-invoke", json=payload)
-        response.raise_for_status()
+        response = await client.post(
+            "/api/v1/sdk/post-invoke", **self._sdk_request(agent_id, payload)
+        )
+        # 403 carries the failed output validation; other errors are unexpected.
+        if response.status_code != 403:
+            response.raise_for_status()
+        return response.json()
     
     def _span_to_dict(self, span: Span) -> Dict[str, Any]:
         return {
@@ -291,6 +388,7 @@ class AgentGuard:
         base_url: str = "http://localhost:8080",
         enabled: bool = True,
         fail_open: bool = False,
+        signing_secret: Optional[str] = None,
     ):
         """
         Initialize AgentGuard.
@@ -301,11 +399,13 @@ class AgentGuard:
             base_url: AgentGuard API base URL
             enabled: Whether to enable policy enforcement
             fail_open: If True, allow actions when AgentGuard is unavailable
+            signing_secret: Request signing key for this agent, if it signs
+                its pre/post-invoke requests
         """
         self.agent_id = agent_id
         self.enabled = enabled
         self.fail_open = fail_open
-        self.client = AgentGuardClient(api_key, base_url)
+        self.client = AgentGuardClient(api_key, base_url, signing_secret=signing_secret)
         self._current_trace: Optional[Trace] = None
         self._current_span_stack: List[Span] = []
     
@@ -319,14 +419,20 @@ class AgentGuard:
         session_id: str,
         user_id: Optional[str] = None,
         metadata: Optional[Dict[str, Any]] = None,
+        parent: Optional[Dict[str, str]] = None,
     ):
-        """Context manager for tracing an agent execution."""
+        """Context manager for tracing an agent execution.
+
+        Pass parent=supervisor_trace.delegation_link() when this agent was
+        invoked by another, so both traces join one delegation forest.
+        """
         trace = Trace(
             trace_id=str(uuid.uuid4()),
             agent_id=self.agent_id,
             session_id=session_id,
             user_id=user_id,
             metadata=metadata or {},
+            parent=parent,
         )
         self._current_trace = trace
         